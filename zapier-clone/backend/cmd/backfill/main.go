@@ -0,0 +1,182 @@
+// backend/cmd/backfill/main.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/audit"
+	"github.com/shivani-1505/zapier-clone/backend/internal/backfill"
+	"github.com/shivani-1505/zapier-clone/backend/internal/config"
+	"github.com/shivani-1505/zapier-clone/backend/internal/db"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mappingstore"
+	"github.com/shivani-1505/zapier-clone/backend/internal/riskmatrix"
+	"github.com/shivani-1505/zapier-clone/backend/internal/tenant"
+)
+
+func main() {
+	dataDir := flag.String("data", "./data", "directory holding the mapping files")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "server config file; environment variables override it")
+	tables := flag.String("tables", strings.Join(backfill.Tables, ","), "comma separated ServiceNow tables to backfill")
+	query := flag.String("query", "", "encoded query that narrows the records, e.g. active=true")
+	pageSize := flag.Int("page-size", 100, "records fetched per ServiceNow request")
+	limit := flag.Int("limit", 0, "stop after creating this many issues per table (0 for no limit)")
+	batch := flag.Int("batch", jira.MaxBulkIssues, "issues created per Jira bulk request (1 to create them one at a time)")
+	dryRun := flag.Bool("dry-run", false, "report what would be created without writing anything")
+	format := flag.String("format", "text", "report format: text or json")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: backfill [flags]\n\nCreates Jira issues for existing ServiceNow records that are not in the\nmapping store yet, and records the links. Nothing is posted to Slack.\nExits 1 when any record failed.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Progress goes to stderr so a JSON report on stdout stays parseable
+	logging.Setup(os.Stderr)
+
+	// Read the same settings as the server; the default tenant's credentials are required
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fatal("error loading configuration", "error", err)
+	}
+	if err := cfg.Export(); err != nil {
+		fatal("error applying configuration", "error", err)
+	}
+	t := tenant.DefaultFromEnv()
+	if err := t.Validate(); err != nil {
+		fatal("invalid configuration", "error", err)
+	}
+
+	// Honour MAPPING_STORE so the backfill writes the same backend as the server
+	risks, err := mappingstore.Open(mappingstore.ConfigFromEnv(*dataDir, getEnv("TENANT_ID", "default")))
+	if err != nil {
+		fatal("error loading risk mapping", "error", err)
+	}
+	if closer, ok := risks.(io.Closer); ok {
+		defer closer.Close()
+	}
+	incidents, err := jira.NewIncidentJiraMapping(*dataDir)
+	if err != nil {
+		fatal("error loading incident mapping", "error", err)
+	}
+	fieldMappingConfig, err := mapping.LoadConfig(*dataDir)
+	if err != nil {
+		fatal("invalid field mapping config", "error", err)
+	}
+
+	riskMatrix, err := riskmatrix.NewStore(*dataDir)
+	if err != nil {
+		fatal("error loading risk matrix", "error", err)
+	}
+
+	serviceNowClient := servicenow.NewClient(t.ServiceNow.URL, t.ServiceNow.Username, t.ServiceNow.Password)
+	jiraClient := jira.NewClient(t.Jira.URL, t.Jira.Email, t.Jira.APIToken, t.Jira.ProjectKey).WithAPIVersion(t.Jira.APIVersion)
+
+	// Record the issues created in the same audit log as the server's calls
+	if databaseURL := getEnv("DATABASE_URL", ""); databaseURL != "" && !*dryRun {
+		conn, err := db.Open(databaseURL)
+		if err != nil {
+			fatal("database error", "error", err)
+		}
+		defer conn.Close()
+		auditLog := audit.NewLog(conn, getEnv("TENANT_ID", "default"))
+		audit.Wrap(serviceNowClient.HTTPClient, auditLog, "servicenow")
+		audit.Wrap(jiraClient.HTTPClient, auditLog, "jira")
+	}
+
+	// Risk matrix scores go in the site's Risk Matrix Score field when it exists
+	if _, err := jiraClient.DiscoverRiskScoreField(false); err != nil {
+		slog.Warn("could not look up the Jira field", "field", jira.RiskScoreFieldName, "error", err)
+	}
+
+	backfiller := backfill.NewBackfiller(
+		serviceNowClient,
+		jiraClient,
+		mapping.NewEngine(fieldMappingConfig),
+		risks,
+		incidents,
+	)
+	backfiller.Matrix = riskMatrix
+	backfiller.PageSize = *pageSize
+	backfiller.Query = *query
+	backfiller.Limit = *limit
+	backfiller.BatchSize = *batch
+	backfiller.DryRun = *dryRun
+
+	// Ctrl-C stops between pages; links stored so far are kept
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = audit.WithActor(ctx, "backfill")
+
+	report, runErr := backfiller.Run(ctx, strings.Split(*tables, ","))
+	if report == nil {
+		fatal("error starting backfill", "error", runErr)
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fatal("error writing report", "error", err)
+		}
+	case "text":
+		printReport(report)
+	default:
+		fatal("unknown format", "format", *format)
+	}
+
+	if runErr != nil {
+		fatal("backfill stopped early", "error", runErr)
+	}
+	if report.Failed() > 0 {
+		os.Exit(1)
+	}
+}
+
+// printReport writes a human-readable summary per table
+func printReport(report *backfill.Report) {
+	title := "Backfill"
+	if report.DryRun {
+		title = "Backfill (dry run)"
+	}
+	fmt.Printf("%s finished in %s\n", title, report.FinishedAt.Sub(report.StartedAt).Round(time.Second))
+
+	for _, summary := range report.Tables {
+		fmt.Printf("\n%s:\n", summary.Table)
+		fmt.Printf("  %-9s %d\n", "scanned", summary.Scanned)
+		fmt.Printf("  %-9s %d\n", "existing", summary.Existing)
+		fmt.Printf("  %-9s %d\n", "relinked", summary.Relinked)
+		fmt.Printf("  %-9s %d\n", "created", summary.Created)
+		fmt.Printf("  %-9s %d\n", "failed", summary.Failed)
+		for _, failure := range summary.Failures {
+			fmt.Printf("      - %s %s: %s\n", failure.SysID, failure.Number, failure.Error)
+		}
+	}
+}
+
+// Helper function to get environment variables with default fallback
+// fatal logs an error the backfill cannot go on after and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
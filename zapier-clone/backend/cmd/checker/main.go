@@ -0,0 +1,142 @@
+// backend/cmd/checker/main.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/config"
+	"github.com/shivani-1505/zapier-clone/backend/internal/consistency"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mappingstore"
+	"github.com/shivani-1505/zapier-clone/backend/internal/tenant"
+)
+
+func main() {
+	dataDir := flag.String("data", "./data", "directory holding the mapping files")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "server config file; environment variables override it")
+	format := flag.String("format", "text", "report format: text or json")
+	planFile := flag.String("plan", "", "write a repair plan for the reconciliation job to this file")
+	staleDays := flag.Int("stale-days", 180, "report pairs closed on both sides for this many days as stale")
+	verbose := flag.Bool("v", false, "list ok pairs in the text report")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: checker [flags]\n\nCompares the Jira/ServiceNow mapping store against both systems.\nExits 1 when any pair is not ok.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Read the same settings as the server; the default tenant's credentials are required
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+	if err := cfg.Export(); err != nil {
+		log.Fatalf("Error applying configuration: %v", err)
+	}
+	t := tenant.DefaultFromEnv()
+	if err := t.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Honour MAPPING_STORE so the checker reads the same backend as the server
+	risks, err := mappingstore.Open(mappingstore.ConfigFromEnv(*dataDir, getEnv("TENANT_ID", "default")))
+	if err != nil {
+		log.Fatalf("Error loading risk mapping: %v", err)
+	}
+	incidents, err := jira.NewIncidentJiraMapping(*dataDir)
+	if err != nil {
+		log.Fatalf("Error loading incident mapping: %v", err)
+	}
+
+	jiraClient := jira.NewClient(t.Jira.URL, t.Jira.Email, t.Jira.APIToken, t.Jira.ProjectKey).WithAPIVersion(t.Jira.APIVersion)
+	// Compare against the site's real ServiceNow ID field; the checker never creates it
+	if _, err := jiraClient.DiscoverServiceNowIDField(false); err != nil {
+		log.Printf("Warning: could not discover the Jira ServiceNow ID field, using %s: %v", jira.DefaultServiceNowIDField, err)
+	}
+
+	checker := consistency.NewChecker(
+		servicenow.NewClient(t.ServiceNow.URL, t.ServiceNow.Username, t.ServiceNow.Password),
+		jiraClient,
+	)
+	checker.StaleAfter = time.Duration(*staleDays) * 24 * time.Hour
+
+	pairs, indexProblems, err := consistency.Pairs(risks, incidents)
+	if err != nil {
+		log.Fatalf("Error reading mappings: %v", err)
+	}
+	report := checker.Check(pairs, indexProblems)
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Error writing report: %v", err)
+		}
+	case "text":
+		printReport(report, *verbose)
+	default:
+		log.Fatalf("Unknown format %q", *format)
+	}
+
+	if *planFile != "" {
+		plan := consistency.Plan(report)
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			log.Fatalf("Error encoding repair plan: %v", err)
+		}
+		if err := os.WriteFile(*planFile, data, 0644); err != nil {
+			log.Fatalf("Error writing repair plan: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d repair action(s) to %s\n", len(plan.Actions), *planFile)
+	}
+
+	if report.Counts[consistency.StatusOK] != len(pairs)+len(indexProblems) {
+		os.Exit(1)
+	}
+}
+
+// printReport writes a human-readable report grouped by category
+func printReport(report *consistency.Report, verbose bool) {
+	statuses := []string{
+		consistency.StatusOK,
+		consistency.StatusOrphaned,
+		consistency.StatusMismatched,
+		consistency.StatusStale,
+		consistency.StatusError,
+	}
+
+	fmt.Printf("Consistency check at %s\n\n", report.CheckedAt.Format(time.RFC3339))
+	for _, status := range statuses {
+		fmt.Printf("  %-11s %d\n", status, report.Counts[status])
+	}
+
+	for _, status := range statuses {
+		results := report.Results[status]
+		if len(results) == 0 || (status == consistency.StatusOK && !verbose) {
+			continue
+		}
+
+		fmt.Printf("\n%s:\n", strings.ToUpper(status))
+		for _, result := range results {
+			fmt.Printf("  [%s] %s/%s <-> %s\n", result.Mapping, result.Table, result.SysID, result.JiraKey)
+			for _, issue := range result.Issues {
+				fmt.Printf("      - %s\n", issue)
+			}
+		}
+	}
+}
+
+// Helper function to get environment variables with default fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,116 @@
+// backend/cmd/e2e/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/e2e"
+)
+
+func main() {
+	dir := flag.String("dir", "./fixtures/e2e", "directory holding the YAML scenarios")
+	backendURL := flag.String("backend-url", getEnv("BACKEND_URL", "http://localhost:8080"), "URL of the running backend")
+	serviceNowURL := flag.String("servicenow-url", getEnv("SERVICENOW_URL", "http://localhost:3000"), "mock ServiceNow URL")
+	username := flag.String("username", getEnv("SERVICENOW_USERNAME", "admin"), "ServiceNow username")
+	password := flag.String("password", getEnv("SERVICENOW_PASSWORD", "password"), "ServiceNow password")
+	jiraURL := flag.String("jira-url", getEnv("JIRA_URL", "http://localhost:3001"), "mock Jira URL")
+	slackURL := flag.String("slack-url", getEnv("SLACK_URL", "http://localhost:3002"), "mock Slack URL")
+	webhookKey := flag.String("webhook-key", getEnv("WEBHOOK_API_KEY", ""), "API key issued for the servicenow webhook source, sent with ServiceNow webhooks")
+	webhookToken := flag.String("webhook-token", getEnv("SERVICENOW_WEBHOOK_TOKEN", ""), "the backend's SERVICENOW_WEBHOOK_TOKEN, sent with ServiceNow webhooks")
+	startMocks := flag.Bool("start-mocks", false, "build and start the three mock servers for the run instead of using running ones")
+	mocksDir := flag.String("mocks-dir", "..", "directory holding the mock servers, for -start-mocks")
+	timeout := flag.Duration("timeout", e2e.DefaultTimeout, "how long expect steps wait, unless the scenario says otherwise")
+	verbose := flag.Bool("v", false, "print the steps of every scenario, not just failing ones")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: e2e [flags] [file ...]\n\nRuns end-to-end scenarios through the backend and the mock ServiceNow,\nJira and Slack servers. The backend must be running and pointed at the\nmocks. Exits 1 when any scenario fails.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var scenarios []*e2e.Scenario
+	if flag.NArg() > 0 {
+		for _, path := range flag.Args() {
+			scenario, err := e2e.LoadScenario(path)
+			if err != nil {
+				log.Fatalf("Error loading scenario: %v", err)
+			}
+			scenarios = append(scenarios, scenario)
+		}
+	} else {
+		var err error
+		scenarios, err = e2e.LoadScenarios(*dir)
+		if err != nil {
+			log.Fatalf("Error loading scenarios: %v", err)
+		}
+	}
+
+	stopMocks := func() {}
+	if *startMocks {
+		var err error
+		stopMocks, err = e2e.StartMocks(*mocksDir, *backendURL)
+		if err != nil {
+			log.Fatalf("Error starting mock servers: %v", err)
+		}
+	}
+
+	// Stop on Ctrl-C, still printing the report and stopping the mocks
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	runner := e2e.NewRunner(e2e.Config{
+		BackendURL:         *backendURL,
+		ServiceNowURL:      *serviceNowURL,
+		ServiceNowUsername: *username,
+		ServiceNowPassword: *password,
+		JiraURL:            *jiraURL,
+		SlackURL:           *slackURL,
+		WebhookKey:         *webhookKey,
+		WebhookToken:       *webhookToken,
+		Timeout:            *timeout,
+	})
+
+	failed := 0
+	for _, scenario := range scenarios {
+		result := runner.Run(ctx, scenario)
+		status := "PASS"
+		if !result.Passed() {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("%s  %s (%s) %s\n", status, scenario.Name, scenario.File, result.Duration.Round(time.Millisecond))
+
+		if *verbose || !result.Passed() {
+			for _, step := range result.Steps {
+				if step.Err != nil {
+					fmt.Printf("      FAIL %s: %v\n", step.Step.Title(), step.Err)
+				} else {
+					fmt.Printf("      ok   %s (%s)\n", step.Step.Title(), step.Duration.Round(time.Millisecond))
+				}
+			}
+			if skipped := len(scenario.Steps) - len(result.Steps); skipped > 0 {
+				fmt.Printf("      %d step(s) not run\n", skipped)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d scenario(s), %d failed\n", len(scenarios), failed)
+	stopMocks()
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// Helper function to get environment variables with default fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,63 @@
+// backend/cmd/fixtures/main.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/workflow"
+)
+
+func main() {
+	dir := flag.String("dir", "./fixtures/workflows", "directory holding the YAML workflow fixtures")
+	verbose := flag.Bool("v", false, "print the calls made by every fixture, not just failing ones")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: fixtures [flags] [file ...]\n\nRuns declarative workflow fixtures against mocked connectors.\nExits 1 when any fixture fails.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var fixtures []*workflow.Fixture
+	if flag.NArg() > 0 {
+		for _, path := range flag.Args() {
+			fixture, err := workflow.LoadFixture(path)
+			if err != nil {
+				log.Fatalf("Error loading fixture: %v", err)
+			}
+			fixtures = append(fixtures, fixture)
+		}
+	} else {
+		var err error
+		fixtures, err = workflow.LoadFixtures(*dir)
+		if err != nil {
+			log.Fatalf("Error loading fixtures: %v", err)
+		}
+	}
+
+	failed := 0
+	for _, fixture := range fixtures {
+		result := workflow.RunFixture(fixture)
+		status := "PASS"
+		if !result.Passed() {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("%s  %s (%s)\n", status, fixture.Name, fixture.File)
+
+		for _, failure := range result.Failures {
+			fmt.Printf("      - %s\n", failure)
+		}
+		if *verbose || !result.Passed() {
+			for _, call := range result.Calls {
+				fmt.Printf("      > %s.%s %v\n", call.Service, call.Action, call.Fields)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d fixture(s), %d failed\n", len(fixtures), failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,79 @@
+// backend/cmd/relay/main.go
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/relay"
+)
+
+// The relay agent runs inside a restricted network next to ServiceNow. Point
+// ServiceNow's outbound REST messages at this agent instead of the backend;
+// the agent only ever makes outbound connections to RELAY_UPSTREAM_URL.
+func main() {
+	upstreamURL := getEnv("RELAY_UPSTREAM_URL", "")
+	secret := getEnv("RELAY_SHARED_SECRET", "")
+	if upstreamURL == "" || secret == "" {
+		log.Fatal("RELAY_UPSTREAM_URL and RELAY_SHARED_SECRET are required")
+	}
+
+	hostname, _ := os.Hostname()
+	queue, err := relay.NewQueue(getEnv("RELAY_QUEUE_DIR", "./data/relay-queue"), 100000)
+	if err != nil {
+		log.Fatalf("Error opening relay queue: %v", err)
+	}
+
+	agent := relay.NewAgent(getEnv("RELAY_AGENT_NAME", hostname), upstreamURL, secret, queue)
+	agent.Start()
+	defer agent.Stop()
+
+	if queued := queue.Len(); queued > 0 {
+		log.Printf("Resuming with %d queued message(s)", queued)
+	}
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/webhooks/{source}", agent.HandleWebhook).Methods("POST")
+	r.HandleFunc("/health", agent.HandleHealth).Methods("GET")
+
+	srv := &http.Server{
+		Addr:         getEnv("RELAY_LISTEN_ADDR", ":8090"),
+		Handler:      r,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	go func() {
+		log.Printf("Relay agent listening on %s, forwarding to %s", srv.Addr, upstreamURL)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server error: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	<-c
+
+	log.Println("Shutting down relay agent...")
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Relay agent forced to shutdown: %v", err)
+	}
+}
+
+// Helper function to get environment variables with default fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
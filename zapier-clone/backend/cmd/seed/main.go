@@ -0,0 +1,326 @@
+// backend/cmd/seed/main.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/api/middleware"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// severityWeight is a single entry of the severity distribution
+type severityWeight struct {
+	Name   string
+	Weight int
+}
+
+// generator produces realistic GRC records
+type generator struct {
+	rng        *rand.Rand
+	severities []severityWeight
+	spread     time.Duration
+	now        time.Time
+}
+
+func main() {
+	// Parse command line flags
+	serviceNowURL := flag.String("servicenow-url", getEnv("SERVICENOW_URL", "http://localhost:3000"), "ServiceNow instance or mock server URL")
+	username := flag.String("username", getEnv("SERVICENOW_USERNAME", "admin"), "ServiceNow username")
+	password := flag.String("password", getEnv("SERVICENOW_PASSWORD", "password"), "ServiceNow password")
+	risks := flag.Int("risks", 25, "number of risks to generate")
+	incidents := flag.Int("incidents", 10, "number of security incidents to generate")
+	tasks := flag.Int("tasks", 15, "number of compliance tasks to generate")
+	findings := flag.Int("findings", 10, "number of audit findings to generate")
+	severity := flag.String("severity", "critical=10,high=25,medium=40,low=25", "severity distribution as name=weight pairs")
+	spread := flag.Duration("spread", 30*24*time.Hour, "time window over which creation dates are spread")
+	webhookURL := flag.String("webhook-url", "", "if set, also send each record to this backend webhook URL to drive the sync pipeline")
+	webhookKey := flag.String("webhook-key", getEnv("WEBHOOK_API_KEY", ""), "API key issued for the servicenow webhook source, sent in X-Webhook-Key")
+	webhookToken := flag.String("webhook-token", getEnv("SERVICENOW_WEBHOOK_TOKEN", ""), "the backend's SERVICENOW_WEBHOOK_TOKEN, sent in X-ServiceNow-Token")
+	delay := flag.Duration("delay", 0, "pause between records, useful for load tests")
+	seed := flag.Int64("seed", 0, "random seed (0 uses the current time)")
+	flag.Parse()
+
+	severities, err := parseSeverityDistribution(*severity)
+	if err != nil {
+		log.Fatalf("Invalid severity distribution: %v", err)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	gen := &generator{
+		rng:        rand.New(rand.NewSource(*seed)),
+		severities: severities,
+		spread:     *spread,
+		now:        time.Now(),
+	}
+
+	client := servicenow.NewClient(*serviceNowURL, *username, *password)
+
+	plan := []struct {
+		table string
+		count int
+		build func(i int) map[string]interface{}
+	}{
+		{"sn_risk_risk", *risks, gen.risk},
+		{"sn_si_incident", *incidents, gen.incident},
+		{"sn_compliance_task", *tasks, gen.complianceTask},
+		{"sn_audit_finding", *findings, gen.auditFinding},
+	}
+
+	log.Printf("Seeding %s (seed %d)", *serviceNowURL, *seed)
+
+	var created, failed int
+	for _, step := range plan {
+		inserted := 0
+		for i := 1; i <= step.count; i++ {
+			record := step.build(i)
+
+			result, err := client.CreateRecord(step.table, record)
+			if err != nil {
+				log.Printf("Error creating %s record %s: %v", step.table, record["number"], err)
+				failed++
+				continue
+			}
+			inserted++
+
+			if *webhookURL != "" {
+				if err := sendWebhook(*webhookURL, *webhookKey, *webhookToken, step.table, result); err != nil {
+					log.Printf("Error sending webhook for %s: %v", record["number"], err)
+				}
+			}
+
+			if *delay > 0 {
+				time.Sleep(*delay)
+			}
+		}
+		created += inserted
+		log.Printf("Created %d of %d %s records", inserted, step.count, step.table)
+	}
+
+	log.Printf("Seeding complete: %d created, %d failed", created, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseSeverityDistribution parses "critical=10,high=25,..." into weights
+func parseSeverityDistribution(spec string) ([]severityWeight, error) {
+	var weights []severityWeight
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("expected name=weight, got %q", part)
+		}
+
+		weight, err := strconv.Atoi(kv[1])
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight for %s: %q", kv[0], kv[1])
+		}
+
+		weights = append(weights, severityWeight{Name: strings.ToLower(kv[0]), Weight: weight})
+	}
+
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("no severities given")
+	}
+
+	return weights, nil
+}
+
+// pickSeverity chooses a severity according to the configured distribution
+func (g *generator) pickSeverity() string {
+	total := 0
+	for _, s := range g.severities {
+		total += s.Weight
+	}
+	if total == 0 {
+		return g.severities[0].Name
+	}
+
+	n := g.rng.Intn(total)
+	for _, s := range g.severities {
+		if n < s.Weight {
+			return s.Name
+		}
+		n -= s.Weight
+	}
+	return g.severities[len(g.severities)-1].Name
+}
+
+// createdAt returns a random timestamp within the configured spread
+func (g *generator) createdAt() time.Time {
+	if g.spread <= 0 {
+		return g.now
+	}
+	return g.now.Add(-time.Duration(g.rng.Int63n(int64(g.spread))))
+}
+
+// pick returns a random element of options
+func (g *generator) pick(options []string) string {
+	return options[g.rng.Intn(len(options))]
+}
+
+// riskScoreFor returns a score that maps back to the given severity via servicenow.RiskSeverity
+func (g *generator) riskScoreFor(severity string) float64 {
+	switch severity {
+	case "critical":
+		return 80 + g.rng.Float64()*20
+	case "high":
+		return 60 + g.rng.Float64()*20
+	case "medium":
+		return 40 + g.rng.Float64()*20
+	default:
+		return g.rng.Float64() * 40
+	}
+}
+
+func (g *generator) risk(i int) map[string]interface{} {
+	severity := g.pickSeverity()
+	created := g.createdAt()
+	category := g.pick([]string{"Security", "Financial", "Operational", "Compliance", "Strategic"})
+
+	return map[string]interface{}{
+		"sys_id":            fmt.Sprintf("seedrisk%04d", i),
+		"number":            fmt.Sprintf("RISK%05d", 10000+i),
+		"short_description": fmt.Sprintf("%s risk: %s", category, g.pick([]string{"Unpatched servers", "Weak vendor controls", "Expired certificates", "Missing segregation of duties", "Unencrypted backups"})),
+		"description":       "Generated by the seed tool for demo and load testing purposes.",
+		"category":          category,
+		"subcategory":       "Generated",
+		"state":             g.pick([]string{"Draft", "In Progress", "Completed"}),
+		"impact":            strings.Title(severity),
+		"likelihood":        g.pick([]string{"Low", "Medium", "High"}),
+		"risk_score":        g.riskScoreFor(severity),
+		"assigned_to":       "",
+		"sys_created_on":    created.Format(time.RFC3339),
+		"sys_updated_on":    created.Format(time.RFC3339),
+		"due_date":          created.Add(30 * 24 * time.Hour).Format(time.RFC3339),
+		"mitigation_plan":   "",
+	}
+}
+
+func (g *generator) incident(i int) map[string]interface{} {
+	severity := g.pickSeverity()
+	created := g.createdAt()
+
+	return map[string]interface{}{
+		"sys_id":            fmt.Sprintf("seedinc%04d", i),
+		"number":            fmt.Sprintf("INC%05d", 10000+i),
+		"short_description": g.pick([]string{"Phishing campaign detected", "Malware on endpoint", "Unauthorized access attempt", "Data exfiltration alert", "DDoS against public site"}),
+		"description":       "Generated by the seed tool for demo and load testing purposes.",
+		"category":          "Security",
+		"subcategory":       g.pick([]string{"Network", "Endpoint", "Identity", "Application"}),
+		"state":             "new",
+		"priority":          strings.Title(severity),
+		"severity":          strings.Title(severity),
+		"impact":            strings.Title(severity),
+		"assigned_to":       "",
+		"assignment_group":  "Security Operations",
+		"sys_created_on":    created.Format(time.RFC3339),
+		"sys_updated_on":    created.Format(time.RFC3339),
+	}
+}
+
+func (g *generator) complianceTask(i int) map[string]interface{} {
+	created := g.createdAt()
+	framework := g.pick([]string{"SOX", "ISO 27001", "SOC 2", "GDPR", "PCI DSS"})
+
+	return map[string]interface{}{
+		"sys_id":               fmt.Sprintf("seedtask%04d", i),
+		"number":               fmt.Sprintf("COMP%05d", 10000+i),
+		"short_description":    fmt.Sprintf("%s control assessment", framework),
+		"description":          "Generated by the seed tool for demo and load testing purposes.",
+		"compliance_framework": framework,
+		"regulation":           g.pick([]string{"Section 404", "Annex A.12", "CC6.1", "Article 32", "Requirement 8"}),
+		"state":                g.pick([]string{"open", "in_progress", "closed"}),
+		"assigned_to":          "",
+		"sys_created_on":       created.Format(time.RFC3339),
+		"sys_updated_on":       created.Format(time.RFC3339),
+		"due_date":             created.Add(time.Duration(14+g.rng.Intn(60)) * 24 * time.Hour).Format(time.RFC3339),
+	}
+}
+
+func (g *generator) auditFinding(i int) map[string]interface{} {
+	severity := g.pickSeverity()
+	created := g.createdAt()
+
+	return map[string]interface{}{
+		"sys_id":            fmt.Sprintf("seedfind%04d", i),
+		"number":            fmt.Sprintf("FIND%05d", 10000+i),
+		"short_description": g.pick([]string{"Access reviews not performed", "Change tickets missing approval", "Backup restore not tested", "Terminated users still active"}),
+		"description":       "Generated by the seed tool for demo and load testing purposes.",
+		"audit_name":        g.pick([]string{"Annual IT Audit", "SOX Walkthrough", "ISO Surveillance Audit"}),
+		"severity":          strings.Title(severity),
+		"state":             "open",
+		"assigned_to":       "",
+		"sys_created_on":    created.Format(time.RFC3339),
+		"sys_updated_on":    created.Format(time.RFC3339),
+		"due_date":          created.Add(45 * 24 * time.Hour).Format(time.RFC3339),
+		"resolution":        "",
+	}
+}
+
+// sendWebhook posts a record to the backend as if ServiceNow had inserted it,
+// authenticated with the webhook API key and ServiceNow token when given
+func sendWebhook(url, key, token, table string, record map[string]interface{}) error {
+	id, _ := record["sys_id"].(string)
+	payload := servicenow.WebhookPayload{
+		ID:         id,
+		TableName:  table,
+		ActionType: "inserted",
+		Data:       record,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("error creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(middleware.HeaderWebhookKey, key)
+	}
+	if token != "" {
+		req.Header.Set(middleware.HeaderServiceNowToken, token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Helper function to get environment variables with default fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,84 @@
+// backend/internal/api/handlers/access_review.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/reporting"
+)
+
+// AccessReviewHandler serves access review reports
+type AccessReviewHandler struct {
+	AccessReviewer *reporting.AccessReviewer
+}
+
+// NewAccessReviewHandler creates a new access review handler
+func NewAccessReviewHandler(reviewer *reporting.AccessReviewer) *AccessReviewHandler {
+	return &AccessReviewHandler{
+		AccessReviewer: reviewer,
+	}
+}
+
+// HandleAccessReview returns the current access review as JSON, CSV or PDF
+func (h *AccessReviewHandler) HandleAccessReview(w http.ResponseWriter, r *http.Request) {
+	if h.AccessReviewer == nil {
+		http.Error(w, "Access review requires a database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	review, err := h.AccessReviewer.Generate()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error generating access review", "error", err)
+		http.Error(w, "Error generating access review", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("access-review-%s", review.Period)
+
+	switch r.URL.Query().Get("format") {
+	case "csv":
+		data, err := review.CSV()
+		if err != nil {
+			logging.FromContext(r.Context()).Error("error rendering access review CSV", "error", err)
+			http.Error(w, "Error rendering access review", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		w.Write(data)
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".pdf"))
+		w.Write(review.PDF())
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"review": review,
+			"stale":  review.StaleCount(),
+		})
+	default:
+		http.Error(w, "Unsupported format; use json, csv or pdf", http.StatusBadRequest)
+	}
+}
+
+// HandleSendAccessReview posts the access review to the compliance Slack channel
+func (h *AccessReviewHandler) HandleSendAccessReview(w http.ResponseWriter, r *http.Request) {
+	if h.AccessReviewer == nil {
+		http.Error(w, "Access review requires a database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	reviewer := *h.AccessReviewer
+	reviewer.SlackClient = h.AccessReviewer.SlackClient.WithContext(r.Context())
+	if err := reviewer.SendAccessReview(); err != nil {
+		logging.FromContext(r.Context()).Error("error sending access review", "error", err)
+		http.Error(w, "Error sending access review", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}`))
+}
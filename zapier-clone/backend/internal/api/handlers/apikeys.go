@@ -0,0 +1,155 @@
+// backend/internal/api/handlers/apikeys.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/apikeys"
+)
+
+// APIKeyHandler manages the API keys webhook senders authenticate with
+type APIKeyHandler struct {
+	Store *apikeys.Store
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(store *apikeys.Store) *APIKeyHandler {
+	return &APIKeyHandler{Store: store}
+}
+
+// createdAPIKey is the response to a create: the only time the key is shown
+type createdAPIKey struct {
+	*apikeys.Key
+	Secret string `json:"key"`
+}
+
+// HandleListAPIKeys returns every key, revoked ones included
+func (h *APIKeyHandler) HandleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if !h.configured(w) {
+		return
+	}
+
+	keys, err := h.Store.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, keys)
+}
+
+// HandleGetAPIKey returns one key without its secret
+func (h *APIKeyHandler) HandleGetAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.keyID(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.Store.Get(id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, key)
+}
+
+// HandleCreateAPIKey issues a key for a webhook source to the signed-in user:
+// {"name": "...", "source": "pagerduty"}. The key is only returned here.
+func (h *APIKeyHandler) HandleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if !h.configured(w) {
+		return
+	}
+	userID, ok := userIDFromRequest(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Sign in to create API keys")
+		return
+	}
+
+	var request apikeys.Key
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	key, secret, err := h.Store.Create(userID, request)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, createdAPIKey{Key: key, Secret: secret})
+}
+
+// HandleUpdateAPIKey renames a key: {"name": "..."}
+func (h *APIKeyHandler) HandleUpdateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.keyID(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	key, err := h.Store.Rename(id, request.Name)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, key)
+}
+
+// HandleRevokeAPIKey revokes a key; it stays listed for access reviews
+func (h *APIKeyHandler) HandleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.keyID(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.Store.Revoke(id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, key)
+}
+
+// configured writes 503 when there is no database to keep keys in
+func (h *APIKeyHandler) configured(w http.ResponseWriter) bool {
+	if h.Store == nil {
+		writeError(w, http.StatusServiceUnavailable, "API keys need DATABASE_URL")
+		return false
+	}
+	return true
+}
+
+// keyID reads the {id} path variable
+func (h *APIKeyHandler) keyID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if !h.configured(w) {
+		return 0, false
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid API key ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps API key store errors to HTTP statuses
+func (h *APIKeyHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apikeys.ErrNotFound):
+		writeError(w, http.StatusNotFound, "API key not found")
+	case errors.Is(err, apikeys.ErrInvalid), errors.Is(err, apikeys.ErrSource):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
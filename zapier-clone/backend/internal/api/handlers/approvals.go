@@ -0,0 +1,213 @@
+// backend/internal/api/handlers/approvals.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workflow"
+)
+
+// approvalWorkflows are the built-in flows whose Jira step an approval completes
+var approvalWorkflows = map[string]string{
+	"sn_risk_risk":   "grc-risk-notify",
+	"sn_si_incident": "grc-incident-response",
+}
+
+// ApprovalHandler lists Jira issues held for approval and decides them, from
+// the API or the Approve and Reject buttons in Slack
+type ApprovalHandler struct {
+	Approvals       *servicenow.ApprovalGate
+	SlackClient     *slack.Client
+	RiskHandler     *servicenow.RiskHandler
+	IncidentHandler *servicenow.IncidentHandler
+	// AuditHandler creates and links the issues of findings held as possible duplicates
+	AuditHandler *servicenow.AuditHandler
+	// Executions records each decision in the workflow execution history
+	Executions *workflow.Store
+}
+
+// NewApprovalHandler creates a new approval handler
+func NewApprovalHandler(approvals *servicenow.ApprovalGate, slackClient *slack.Client, riskHandler *servicenow.RiskHandler, incidentHandler *servicenow.IncidentHandler, executions *workflow.Store) *ApprovalHandler {
+	return &ApprovalHandler{
+		Approvals:       approvals,
+		SlackClient:     slackClient,
+		RiskHandler:     riskHandler,
+		IncidentHandler: incidentHandler,
+		Executions:      executions,
+	}
+}
+
+// HandleListApprovals returns approvals, newest first, optionally filtered by ?status=
+func (h *ApprovalHandler) HandleListApprovals(w http.ResponseWriter, r *http.Request) {
+	if h.Approvals == nil {
+		http.Error(w, "Jira approvals are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", servicenow.ApprovalPending, servicenow.ApprovalApproved, servicenow.ApprovalRejected, servicenow.ApprovalLinked:
+	default:
+		http.Error(w, fmt.Sprintf("Invalid status %q: use %s, %s, %s or %s", status,
+			servicenow.ApprovalPending, servicenow.ApprovalApproved, servicenow.ApprovalRejected, servicenow.ApprovalLinked), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Approvals.List(status))
+}
+
+// HandleGetApproval returns the approval of one record
+func (h *ApprovalHandler) HandleGetApproval(w http.ResponseWriter, r *http.Request) {
+	if h.Approvals == nil {
+		http.Error(w, "Jira approvals are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	approval, ok := h.Approvals.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "Approval not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(approval)
+}
+
+// HandleDecideApproval approves or rejects a pending approval:
+// {"approve": true|false, "by": "..."}. A possible duplicate can instead be
+// linked to the existing issue with {"link": true}.
+func (h *ApprovalHandler) HandleDecideApproval(w http.ResponseWriter, r *http.Request) {
+	if h.Approvals == nil {
+		http.Error(w, "Jira approvals are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var request struct {
+		Approve *bool  `json:"approve"`
+		Link    bool   `json:"link"`
+		By      string `json:"by"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || (request.Approve == nil && !request.Link) {
+		http.Error(w, `Request body must set "approve" to true or false, or "link" to true`, http.StatusBadRequest)
+		return
+	}
+	if request.By == "" {
+		request.By = "api"
+	}
+
+	var approval *servicenow.Approval
+	var err error
+	if request.Link {
+		approval, err = h.Link(r.Context(), mux.Vars(r)["id"], request.By)
+	} else {
+		approval, err = h.Decide(r.Context(), mux.Vars(r)["id"], *request.Approve, request.By)
+	}
+	switch {
+	case errors.Is(err, servicenow.ErrApprovalNotFound):
+		http.Error(w, "Approval not found", http.StatusNotFound)
+		return
+	case errors.Is(err, servicenow.ErrApprovalDecided):
+		http.Error(w, "Approval has already been decided", http.StatusConflict)
+		return
+	case errors.Is(err, servicenow.ErrNotDuplicate):
+		http.Error(w, "Approval is not for a possible duplicate", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	json.NewEncoder(w).Encode(approval)
+}
+
+// Decide approves or rejects a record's Jira issue, creating it on approval,
+// and records the decision as a run of the record's built-in flow
+func (h *ApprovalHandler) Decide(ctx context.Context, id string, approve bool, by string) (*servicenow.Approval, error) {
+	approval, err := h.Approvals.Decide(h.SlackClient.WithContext(ctx), id, approve, by, func(approval *servicenow.Approval) (string, error) {
+		switch approval.Table {
+		case "sn_risk_risk":
+			return h.RiskHandler.WithContext(ctx).CreateApprovedIssue(approval)
+		case "sn_si_incident":
+			return h.IncidentHandler.WithContext(ctx).CreateApprovedEpic(approval)
+		case "sn_audit_finding":
+			return h.AuditHandler.WithContext(ctx).CreateApprovedIssue(approval)
+		}
+		return "", fmt.Errorf("no Jira issue is created for %s records", approval.Table)
+	})
+	if errors.Is(err, servicenow.ErrApprovalNotFound) || errors.Is(err, servicenow.ErrApprovalDecided) {
+		return approval, err
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating approved Jira issue", "sys_id", id, "error", err)
+	}
+
+	h.record(approval, err)
+	return approval, err
+}
+
+// Link links a record held as a possible duplicate to the existing issue and
+// records it as a run of the record's built-in flow
+func (h *ApprovalHandler) Link(ctx context.Context, id, by string) (*servicenow.Approval, error) {
+	approval, err := h.Approvals.Link(h.SlackClient.WithContext(ctx), id, by, func(approval *servicenow.Approval) error {
+		switch approval.Table {
+		case "sn_risk_risk":
+			return h.RiskHandler.WithContext(ctx).LinkDuplicate(approval.ID, approval.Number, approval.Duplicate)
+		case "sn_si_incident":
+			return h.IncidentHandler.WithContext(ctx).LinkDuplicate(approval.ID, approval.Number, approval.Duplicate)
+		case "sn_audit_finding":
+			return h.AuditHandler.WithContext(ctx).LinkDuplicate(approval.ID, approval.Number, approval.Duplicate)
+		}
+		return fmt.Errorf("no Jira issue is linked for %s records", approval.Table)
+	})
+	if errors.Is(err, servicenow.ErrApprovalNotFound) || errors.Is(err, servicenow.ErrApprovalDecided) || errors.Is(err, servicenow.ErrNotDuplicate) {
+		return approval, err
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error linking possible duplicate to Jira issue", "sys_id", id, "error", err)
+	}
+
+	h.record(approval, err)
+	return approval, err
+}
+
+// record adds the decision to the execution history as the Jira step of the
+// record's built-in flow, with who decided and when as its input
+func (h *ApprovalHandler) record(approval *servicenow.Approval, err error) {
+	// Audit findings have no built-in flow to record in
+	flow, ok := approvalWorkflows[approval.Table]
+	if !ok {
+		return
+	}
+	var trigger map[string]interface{}
+	if jsonErr := json.Unmarshal(approval.Record, &trigger); jsonErr != nil {
+		trigger = map[string]interface{}{"sys_id": approval.ID}
+	}
+	run := h.Executions.StartRun(flow, trigger)
+
+	input := map[string]interface{}{
+		"table":      approval.Table,
+		"sys_id":     approval.ID,
+		"approval":   approval.Status,
+		"decided_by": approval.DecidedBy,
+		"decided_at": approval.DecidedAt,
+	}
+	output := map[string]interface{}{"issue_key": approval.JiraKey}
+	switch approval.Status {
+	case servicenow.ApprovalRejected:
+		output = map[string]interface{}{"skipped": "rejected"}
+	case servicenow.ApprovalLinked:
+		output = map[string]interface{}{"issue_key": approval.JiraKey, "linked_duplicate": true}
+	}
+	run.Step("jira", "create_issue", input, output, err)
+	run.Finish(nil)
+}
@@ -0,0 +1,90 @@
+// backend/internal/api/handlers/audit_log.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/audit"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// AuditLogHandler serves the log of outbound calls that modified other systems
+type AuditLogHandler struct {
+	Log *audit.Log
+}
+
+// NewAuditLogHandler creates a new audit log handler
+func NewAuditLogHandler(auditLog *audit.Log) *AuditLogHandler {
+	return &AuditLogHandler{Log: auditLog}
+}
+
+// HandleListAuditLog returns audit entries, newest first, filtered by
+// ?system=, ?actor=, ?method=, ?since=, ?until=, ?failed=true and paged with
+// ?before_id= and ?limit=
+func (h *AuditLogHandler) HandleListAuditLog(w http.ResponseWriter, r *http.Request) {
+	if h.Log == nil {
+		writeError(w, http.StatusServiceUnavailable, "The audit log needs a database")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := audit.Filter{
+		TargetSystem: query.Get("system"),
+		Actor:        query.Get("actor"),
+		Method:       query.Get("method"),
+		FailedOnly:   query.Get("failed") == "true",
+	}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, name+" must be an RFC 3339 time")
+				return
+			}
+			*target = parsed
+		}
+	}
+	if value := query.Get("before_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			writeError(w, http.StatusBadRequest, "before_id must be a positive entry id")
+			return
+		}
+		filter.BeforeID = id
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.Log.List(filter)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error listing audit log", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error listing audit log")
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// HandleVerifyAuditLog recomputes the hash chain and reports the first entry
+// that was altered, if any
+func (h *AuditLogHandler) HandleVerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	if h.Log == nil {
+		writeError(w, http.StatusServiceUnavailable, "The audit log needs a database")
+		return
+	}
+
+	result, err := h.Log.Verify()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error verifying audit log", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error verifying audit log")
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
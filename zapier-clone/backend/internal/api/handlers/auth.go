@@ -0,0 +1,249 @@
+// backend/internal/api/handlers/auth.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/auth"
+	"github.com/shivani-1505/zapier-clone/backend/internal/tenant"
+)
+
+// AuthHandler signs frontend users in and out and manages their profile
+type AuthHandler struct {
+	Service *auth.Service
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(service *auth.Service) *AuthHandler {
+	return &AuthHandler{
+		Service: service,
+	}
+}
+
+// HandleRegister lets an administrator create an account; the JWT middleware
+// only lets administrators through
+func (h *AuthHandler) HandleRegister(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var req struct {
+		Username string `json:"username"`
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		FullName string `json:"full_name"`
+		Admin    bool   `json:"admin"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Accounts join the tenant of the administrator's token
+	user, err := h.Service.CreateUser(r.Header.Get(tenant.HeaderID), req.Username, req.Email, req.Password, req.FullName, req.Admin)
+	if err != nil {
+		h.writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, user)
+}
+
+// HandleLogin exchanges an email and password for a token pair
+func (h *AuthHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	pair, err := h.Service.Login(req.Email, req.Password)
+	if err != nil {
+		h.writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, pair)
+}
+
+// HandleRefresh rotates a refresh token into a new token pair
+func (h *AuthHandler) HandleRefresh(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	pair, err := h.Service.Refresh(req.RefreshToken)
+	if err != nil {
+		h.writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, pair)
+}
+
+// HandleLogout revokes the caller's access token and the session of the
+// refresh token in the body, if any
+func (h *AuthHandler) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	claims, ok := auth.ClaimsFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	// The body is optional
+	json.NewDecoder(r.Body).Decode(&req)
+
+	if err := h.Service.Logout(claims, req.RefreshToken); err != nil {
+		h.writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// HandleGetProfile returns the signed-in user
+func (h *AuthHandler) HandleGetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	user, _, err := h.Service.Store.GetUser(userID)
+	if err != nil {
+		h.writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+// HandleUpdateProfile changes the signed-in user's username and full name
+func (h *AuthHandler) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	user, _, err := h.Service.Store.GetUser(userID)
+	if err != nil {
+		h.writeAuthError(w, err)
+		return
+	}
+
+	var req struct {
+		Username *string `json:"username"`
+		FullName *string `json:"full_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Username != nil {
+		user.Username = *req.Username
+	}
+	if req.FullName != nil {
+		user.FullName = *req.FullName
+	}
+	if user.Username == "" {
+		writeError(w, http.StatusBadRequest, "username cannot be empty")
+		return
+	}
+
+	if err := h.Service.Store.UpdateProfile(userID, user.Username, user.FullName); err != nil {
+		h.writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+// HandleChangePassword replaces the signed-in user's password; their refresh
+// tokens are revoked so other sessions must sign in again
+func (h *AuthHandler) HandleChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.Service.ChangePassword(userID, req.CurrentPassword, req.NewPassword); err != nil {
+		h.writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// enabled answers 503 when sign-in is not configured
+func (h *AuthHandler) enabled(w http.ResponseWriter) bool {
+	if h.Service == nil {
+		writeError(w, http.StatusServiceUnavailable, "Sign-in requires a database connection and JWT_SIGNING_KEYS")
+		return false
+	}
+	return true
+}
+
+// caller returns the authenticated user's ID
+func (h *AuthHandler) caller(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if !h.enabled(w) {
+		return 0, false
+	}
+
+	userID, ok := userIDFromRequest(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeAuthError maps auth errors to statuses, hiding unexpected ones
+func (h *AuthHandler) writeAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials),
+		errors.Is(err, auth.ErrInvalidToken),
+		errors.Is(err, auth.ErrTokenExpired),
+		errors.Is(err, auth.ErrTokenRevoked),
+		errors.Is(err, auth.ErrTokenReused):
+		writeError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, auth.ErrUserExists):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, auth.ErrInvalidUser), errors.Is(err, auth.ErrWeakPassword):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, auth.ErrUserNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	default:
+		slog.Error("auth error", "error", err)
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// writeError writes {"error": message}, the shape the frontend reads
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
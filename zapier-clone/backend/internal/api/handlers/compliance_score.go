@@ -0,0 +1,87 @@
+// backend/internal/api/handlers/compliance_score.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/scoring"
+)
+
+// defaultTenant is used when a request does not name a tenant
+const defaultTenant = "default"
+
+// ComplianceScoreHandler serves compliance scores and their weights
+type ComplianceScoreHandler struct {
+	Engine *scoring.Engine
+}
+
+// NewComplianceScoreHandler creates a new compliance score handler
+func NewComplianceScoreHandler(engine *scoring.Engine) *ComplianceScoreHandler {
+	return &ComplianceScoreHandler{
+		Engine: engine,
+	}
+}
+
+// HandleGetScore computes the current score with its breakdown and period comparisons
+func (h *ComplianceScoreHandler) HandleGetScore(w http.ResponseWriter, r *http.Request) {
+	engine := *h.Engine
+	engine.ServiceNowClient = h.Engine.ServiceNowClient.WithContext(r.Context())
+	report, err := engine.Score(tenantFromRequest(r))
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error computing compliance score", "error", err)
+		http.Error(w, "Error computing compliance score", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleGetHistory returns stored score snapshots
+func (h *ComplianceScoreHandler) HandleGetHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Engine.Store.GetHistory(tenantFromRequest(r)))
+}
+
+// HandleGetWeights returns the weights in effect for the tenant
+func (h *ComplianceScoreHandler) HandleGetWeights(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Engine.Store.GetWeights(tenantFromRequest(r)))
+}
+
+// HandleSetWeights updates the tenant's factor weights
+func (h *ComplianceScoreHandler) HandleSetWeights(w http.ResponseWriter, r *http.Request) {
+	var weights scoring.Weights
+	if err := json.NewDecoder(r.Body).Decode(&weights); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := weights.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tenant := tenantFromRequest(r)
+	if err := h.Engine.Store.SetWeights(tenant, weights); err != nil {
+		logging.FromContext(r.Context()).Error("error saving compliance score weights", "error", err)
+		http.Error(w, "Error saving weights", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Engine.Store.GetWeights(tenant))
+}
+
+// tenantFromRequest reads the tenant from the X-Tenant-ID header or tenant query parameter
+func tenantFromRequest(r *http.Request) string {
+	if tenant := r.Header.Get("X-Tenant-ID"); tenant != "" {
+		return tenant
+	}
+	if tenant := r.URL.Query().Get("tenant"); tenant != "" {
+		return tenant
+	}
+	return defaultTenant
+}
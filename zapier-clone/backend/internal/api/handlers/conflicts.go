@@ -0,0 +1,120 @@
+// backend/internal/api/handlers/conflicts.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/consistency"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// ConflictHandler lists conflicting Jira and ServiceNow edits and resolves the
+// ones flagged for review
+type ConflictHandler struct {
+	Conflicts *consistency.ConflictDetector
+	// Replayers sync a held update by the side it came from
+	Replayers map[string]Replayer
+}
+
+// NewConflictHandler creates a new conflict handler
+func NewConflictHandler(conflicts *consistency.ConflictDetector, replayers map[string]Replayer) *ConflictHandler {
+	return &ConflictHandler{
+		Conflicts: conflicts,
+		Replayers: replayers,
+	}
+}
+
+// HandleListConflicts returns conflicts, newest first, optionally filtered by ?status=
+func (h *ConflictHandler) HandleListConflicts(w http.ResponseWriter, r *http.Request) {
+	if h.Conflicts == nil {
+		http.Error(w, "Conflict detection is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != consistency.ConflictOpen && status != consistency.ConflictResolved {
+		http.Error(w, fmt.Sprintf("Invalid status %q: use %s or %s", status, consistency.ConflictOpen, consistency.ConflictResolved), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Conflicts.List(status))
+}
+
+// HandleGetConflict returns one conflict with any held update
+func (h *ConflictHandler) HandleGetConflict(w http.ResponseWriter, r *http.Request) {
+	if h.Conflicts == nil {
+		http.Error(w, "Conflict detection is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	conflict, ok := h.Conflicts.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "Conflict not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conflict)
+}
+
+// HandleResolveConflict settles a conflict awaiting review. The body names the
+// side whose edit stands: {"keep": "jira"|"servicenow", "by": "..."}.
+func (h *ConflictHandler) HandleResolveConflict(w http.ResponseWriter, r *http.Request) {
+	if h.Conflicts == nil {
+		http.Error(w, "Conflict detection is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var request struct {
+		Keep string `json:"keep"`
+		By   string `json:"by"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if request.Keep != consistency.SideJira && request.Keep != consistency.SideServiceNow {
+		http.Error(w, fmt.Sprintf("keep must be %s or %s", consistency.SideJira, consistency.SideServiceNow), http.StatusBadRequest)
+		return
+	}
+	if request.By == "" {
+		request.By = "api"
+	}
+
+	conflict, err := h.Resolve(r.Context(), mux.Vars(r)["id"], request.Keep, request.By)
+	switch {
+	case errors.Is(err, consistency.ErrConflictNotFound):
+		http.Error(w, "Conflict not found", http.StatusNotFound)
+		return
+	case errors.Is(err, consistency.ErrConflictResolved):
+		http.Error(w, "Conflict has already been resolved", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	json.NewEncoder(w).Encode(conflict)
+}
+
+// Resolve settles a conflict for keep and syncs its held update when that side won
+func (h *ConflictHandler) Resolve(ctx context.Context, id, keep, by string) (*consistency.Conflict, error) {
+	conflict, err := h.Conflicts.Resolve(ctx, id, keep, by, func(ctx context.Context, side string, held json.RawMessage) error {
+		replay, ok := h.Replayers[side]
+		if !ok {
+			return fmt.Errorf("no replayer for %s updates", side)
+		}
+		return replay(ctx, held)
+	})
+	if err != nil && conflict != nil && !errors.Is(err, consistency.ErrConflictResolved) {
+		logging.FromContext(ctx).Error("error resolving conflict", "conflict", id, "keep", keep, "error", err)
+	}
+	return conflict, err
+}
@@ -0,0 +1,325 @@
+// backend/internal/api/handlers/connections.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/connections"
+)
+
+// ConnectionHandler serves the per-user connection API and OAuth flows
+type ConnectionHandler struct {
+	Manager *connections.Manager
+}
+
+// NewConnectionHandler creates a new connection handler
+func NewConnectionHandler(manager *connections.Manager) *ConnectionHandler {
+	return &ConnectionHandler{Manager: manager}
+}
+
+// createConnectionRequest is the body of POST /api/v1/connections
+type createConnectionRequest struct {
+	Name        string            `json:"name"`
+	Service     string            `json:"service"`
+	AuthType    string            `json:"auth_type"`
+	Environment string            `json:"environment"`
+	AuthData    map[string]string `json:"auth_data"`
+	// Metadata holds settings for the environment, such as a sandbox project_key
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// HandleListConnections lists the caller's connections, optionally filtered by
+// ?service= and ?environment=
+func (h *ConnectionHandler) HandleListConnections(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	environment := r.URL.Query().Get("environment")
+	if environment != "" && !connections.ValidEnvironment(environment) {
+		h.writeError(w, connections.ErrInvalidEnvironment)
+		return
+	}
+	list, err := h.Manager.Store.List(userID, r.URL.Query().Get("service"), environment)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if list == nil {
+		list = []connections.Connection{}
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// HandleListProviders lists the services that can be connected with OAuth
+func (h *ConnectionHandler) HandleListProviders(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.caller(w, r); !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"providers": h.Manager.ProviderNames()})
+}
+
+// HandleCreateConnection stores an API key connection, or a pending OAuth
+// connection that the OAuth callback completes
+func (h *ConnectionHandler) HandleCreateConnection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	var req createConnectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" || req.Service == "" {
+		http.Error(w, "name and service are required", http.StatusBadRequest)
+		return
+	}
+
+	conn := &connections.Connection{
+		UserID:      userID,
+		Name:        req.Name,
+		Service:     req.Service,
+		AuthType:    req.AuthType,
+		Environment: req.Environment,
+		Metadata:    req.Metadata,
+	}
+
+	switch req.AuthType {
+	case connections.AuthOAuth:
+		if _, ok := h.Manager.Providers[req.Service]; !ok {
+			http.Error(w, fmt.Sprintf("OAuth is not configured for %s", req.Service), http.StatusBadRequest)
+			return
+		}
+		conn.Status = connections.StatusPending
+		conn.Credentials = &connections.Credentials{}
+	case connections.AuthAPIKey:
+		if len(req.AuthData) == 0 {
+			http.Error(w, "auth_data is required for api_key connections", http.StatusBadRequest)
+			return
+		}
+		conn.Status = connections.StatusActive
+		conn.Credentials = &connections.Credentials{Fields: req.AuthData}
+	default:
+		http.Error(w, "auth_type must be oauth or api_key", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Manager.Store.Create(conn); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, conn)
+}
+
+// HandleGetConnection returns one connection (never its credentials)
+func (h *ConnectionHandler) HandleGetConnection(w http.ResponseWriter, r *http.Request) {
+	conn, ok := h.owned(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, conn)
+}
+
+// HandleUpdateConnection renames a connection, moves it to another environment,
+// updates its metadata or replaces API key credentials
+func (h *ConnectionHandler) HandleUpdateConnection(w http.ResponseWriter, r *http.Request) {
+	conn, ok := h.owned(w, r)
+	if !ok {
+		return
+	}
+
+	var req createConnectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Name != "" {
+		conn.Name = req.Name
+	}
+	if req.Environment != "" {
+		conn.Environment = req.Environment
+	}
+	if len(req.Metadata) > 0 {
+		conn.Metadata = connections.MergeMetadata(conn.Metadata, req.Metadata)
+	}
+	if len(req.AuthData) > 0 {
+		if conn.AuthType != connections.AuthAPIKey {
+			http.Error(w, "OAuth credentials can only be replaced by reconnecting", http.StatusBadRequest)
+			return
+		}
+		conn.Credentials = &connections.Credentials{Fields: req.AuthData}
+		conn.Status = connections.StatusActive
+	}
+
+	if err := h.Manager.Store.Update(conn); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, conn)
+}
+
+// HandleRevokeConnection revokes a connection's token but keeps the record for access reviews
+func (h *ConnectionHandler) HandleRevokeConnection(w http.ResponseWriter, r *http.Request) {
+	conn, ok := h.owned(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Manager.Revoke(r.Context(), conn.ID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": connections.StatusRevoked})
+}
+
+// HandleDeleteConnection revokes a connection and deletes it
+func (h *ConnectionHandler) HandleDeleteConnection(w http.ResponseWriter, r *http.Request) {
+	conn, ok := h.owned(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Manager.Revoke(r.Context(), conn.ID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if err := h.Manager.Store.Delete(conn.ID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// HandleTestConnection checks that a connection's credentials still work
+func (h *ConnectionHandler) HandleTestConnection(w http.ResponseWriter, r *http.Request) {
+	conn, ok := h.owned(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Manager.Test(r.Context(), conn.ID); err != nil {
+		if errors.Is(err, connections.ErrNotFound) {
+			h.writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": false, "error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// HandleOAuthURL starts an OAuth flow; ?connection_id= ties it to a pending connection
+func (h *ConnectionHandler) HandleOAuthURL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	connectionID, _ := strconv.Atoi(r.URL.Query().Get("connection_id"))
+	authURL, state, err := h.Manager.StartOAuth(userID, mux.Vars(r)["service"], connectionID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"url": authURL, "state": state})
+}
+
+// HandleOAuthCallback completes an OAuth flow with the code the provider returned
+func (h *ConnectionHandler) HandleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Code  string `json:"code"`
+		State string `json:"state"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" || req.State == "" {
+		http.Error(w, "code and state are required", http.StatusBadRequest)
+		return
+	}
+
+	conn, err := h.Manager.CompleteOAuth(r.Context(), userID, mux.Vars(r)["service"], req.Code, req.State)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, conn)
+}
+
+// caller returns the authenticated user, writing an error if there is none
+func (h *ConnectionHandler) caller(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if h.Manager == nil {
+		http.Error(w, "Connections require a database connection and CONNECTIONS_ENCRYPTION_KEY", http.StatusServiceUnavailable)
+		return 0, false
+	}
+
+	userID, ok := userIDFromRequest(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, false
+	}
+	return userID, true
+}
+
+// owned loads the connection named in the path if it belongs to the caller
+func (h *ConnectionHandler) owned(w http.ResponseWriter, r *http.Request) (*connections.Connection, bool) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid connection ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	conn, err := h.Manager.Store.Get(id)
+	if err != nil {
+		h.writeError(w, err)
+		return nil, false
+	}
+	// Other users' connections are reported as missing rather than forbidden
+	if conn.UserID != userID {
+		h.writeError(w, connections.ErrNotFound)
+		return nil, false
+	}
+
+	return conn, true
+}
+
+// writeError maps connection errors to HTTP statuses
+func (h *ConnectionHandler) writeError(w http.ResponseWriter, err error) {
+	var tokenErr *connections.TokenError
+	switch {
+	case errors.Is(err, connections.ErrNotFound):
+		http.Error(w, "Connection not found", http.StatusNotFound)
+	case errors.Is(err, connections.ErrUnknownProvider):
+		http.Error(w, "OAuth is not configured for this service", http.StatusNotFound)
+	case errors.Is(err, connections.ErrInvalidState), errors.Is(err, connections.ErrInvalidEnvironment):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.As(err, &tokenErr):
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	case errors.Is(err, connections.ErrNotConnected):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		slog.Error("connection error", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
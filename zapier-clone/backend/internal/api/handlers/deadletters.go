@@ -0,0 +1,108 @@
+// backend/internal/api/handlers/deadletters.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/monitoring"
+)
+
+// Replayer re-runs the sync for a dead-lettered payload; ctx carries the
+// replay request's deadline and correlation ID
+type Replayer func(ctx context.Context, payload json.RawMessage) error
+
+// DeadLetterHandler lists failed inbound webhooks and re-drives them
+type DeadLetterHandler struct {
+	DeadLetters *monitoring.DeadLetterStore
+	// Replayers re-run dead letters by source
+	Replayers map[string]Replayer
+}
+
+// NewDeadLetterHandler creates a new dead letter handler
+func NewDeadLetterHandler(store *monitoring.DeadLetterStore, replayers map[string]Replayer) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		DeadLetters: store,
+		Replayers:   replayers,
+	}
+}
+
+// HandleListDeadLetters returns dead letters, newest first, optionally filtered by ?status=
+func (h *DeadLetterHandler) HandleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if h.DeadLetters == nil {
+		http.Error(w, "Dead letter storage is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != monitoring.DeadLetterPending && status != monitoring.DeadLetterReplayed {
+		http.Error(w, fmt.Sprintf("Invalid status %q: use %s or %s", status, monitoring.DeadLetterPending, monitoring.DeadLetterReplayed), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.DeadLetters.List(status))
+}
+
+// HandleGetDeadLetter returns one dead letter with its full payload
+func (h *DeadLetterHandler) HandleGetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if h.DeadLetters == nil {
+		http.Error(w, "Dead letter storage is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	letter, ok := h.DeadLetters.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "Dead letter not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(letter)
+}
+
+// HandleReplayDeadLetter re-runs a dead letter's sync synchronously and reports
+// the outcome; a failed replay leaves it pending with the new error
+func (h *DeadLetterHandler) HandleReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if h.DeadLetters == nil {
+		http.Error(w, "Dead letter storage is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	letter, ok := h.DeadLetters.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "Dead letter not found", http.StatusNotFound)
+		return
+	}
+	if letter.Status == monitoring.DeadLetterReplayed {
+		http.Error(w, "Dead letter has already been replayed", http.StatusConflict)
+		return
+	}
+
+	replay, ok := h.Replayers[letter.Source]
+	if !ok {
+		http.Error(w, fmt.Sprintf("No replayer for source %s", letter.Source), http.StatusUnprocessableEntity)
+		return
+	}
+
+	logger := logging.FromContext(r.Context()).With("dead_letter", letter.ID)
+	replayErr := replay(r.Context(), letter.Payload)
+	if replayErr != nil {
+		logger.Error("dead letter replay failed", "error", replayErr)
+	}
+
+	updated, err := h.DeadLetters.RecordReplay(letter.ID, replayErr)
+	if err != nil {
+		logger.Error("error recording dead letter replay", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if replayErr != nil {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	json.NewEncoder(w).Encode(updated)
+}
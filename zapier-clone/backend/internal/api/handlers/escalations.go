@@ -0,0 +1,132 @@
+// backend/internal/api/handlers/escalations.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// EscalationHandler manages the policies that escalate unacknowledged
+// incidents and lists the escalations under way
+type EscalationHandler struct {
+	Escalator *servicenow.Escalator
+}
+
+// NewEscalationHandler creates a new escalation handler
+func NewEscalationHandler(escalator *servicenow.Escalator) *EscalationHandler {
+	return &EscalationHandler{
+		Escalator: escalator,
+	}
+}
+
+// HandleListPolicies returns every policy in creation order
+func (h *EscalationHandler) HandleListPolicies(w http.ResponseWriter, r *http.Request) {
+	if h.Escalator == nil {
+		writeError(w, http.StatusServiceUnavailable, "Escalations are not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.Escalator.Policies())
+}
+
+// HandleGetPolicy returns one policy
+func (h *EscalationHandler) HandleGetPolicy(w http.ResponseWriter, r *http.Request) {
+	if h.Escalator == nil {
+		writeError(w, http.StatusServiceUnavailable, "Escalations are not configured")
+		return
+	}
+
+	policy, ok := h.Escalator.Policy(mux.Vars(r)["id"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "Escalation policy not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// HandleCreatePolicy stores a new policy; it applies to incidents announced from then on
+func (h *EscalationHandler) HandleCreatePolicy(w http.ResponseWriter, r *http.Request) {
+	if h.Escalator == nil {
+		writeError(w, http.StatusServiceUnavailable, "Escalations are not configured")
+		return
+	}
+
+	var policy servicenow.EscalationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	created, err := h.Escalator.CreatePolicy(policy)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// HandleUpdatePolicy replaces a policy's settings
+func (h *EscalationHandler) HandleUpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	if h.Escalator == nil {
+		writeError(w, http.StatusServiceUnavailable, "Escalations are not configured")
+		return
+	}
+
+	var policy servicenow.EscalationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	updated, err := h.Escalator.UpdatePolicy(mux.Vars(r)["id"], policy)
+	if errors.Is(err, servicenow.ErrEscalationPolicyNotFound) {
+		writeError(w, http.StatusNotFound, "Escalation policy not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// HandleDeletePolicy removes a policy
+func (h *EscalationHandler) HandleDeletePolicy(w http.ResponseWriter, r *http.Request) {
+	if h.Escalator == nil {
+		writeError(w, http.StatusServiceUnavailable, "Escalations are not configured")
+		return
+	}
+
+	err := h.Escalator.DeletePolicy(mux.Vars(r)["id"])
+	if errors.Is(err, servicenow.ErrEscalationPolicyNotFound) {
+		writeError(w, http.StatusNotFound, "Escalation policy not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleListEscalations returns escalations, newest first, optionally filtered by ?status=
+func (h *EscalationHandler) HandleListEscalations(w http.ResponseWriter, r *http.Request) {
+	if h.Escalator == nil {
+		writeError(w, http.StatusServiceUnavailable, "Escalations are not configured")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", servicenow.EscalationWaiting, servicenow.EscalationAcknowledged, servicenow.EscalationExhausted, servicenow.EscalationClosed:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid status %q: use %s, %s, %s or %s", status,
+			servicenow.EscalationWaiting, servicenow.EscalationAcknowledged, servicenow.EscalationExhausted, servicenow.EscalationClosed))
+		return
+	}
+	writeJSON(w, http.StatusOK, h.Escalator.List(status))
+}
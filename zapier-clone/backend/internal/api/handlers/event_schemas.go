@@ -0,0 +1,134 @@
+// backend/internal/api/handlers/event_schemas.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/events"
+)
+
+// EventSchemaHandler exposes the event schema registry
+type EventSchemaHandler struct {
+	Registry *events.Registry
+}
+
+// NewEventSchemaHandler creates a new event schema handler
+func NewEventSchemaHandler(registry *events.Registry) *EventSchemaHandler {
+	return &EventSchemaHandler{
+		Registry: registry,
+	}
+}
+
+// HandleListSchemas lists every registered schema version
+func (h *EventSchemaHandler) HandleListSchemas(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.Registry.Schemas())
+}
+
+// HandleRegisterSchema adds a new schema version and reports its compatibility with the previous one
+func (h *EventSchemaHandler) HandleRegisterSchema(w http.ResponseWriter, r *http.Request) {
+	var schema events.Schema
+	if err := json.NewDecoder(r.Body).Decode(&schema); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Registry.Register(&schema); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	var problems []string
+	if schema.Version > 1 {
+		problems, _ = h.Registry.CheckCompatibility(schema.Type, schema.Version-1, schema.Version)
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"schema":     schema,
+		"compatible": len(problems) == 0,
+		"problems":   problems,
+	})
+}
+
+// HandleCheckCompatibility reports whether moving an event type between two versions would break consumers
+func (h *EventSchemaHandler) HandleCheckCompatibility(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type string `json:"type"`
+		From int    `json:"from"`
+		To   int    `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Type == "" {
+		http.Error(w, "type, from and to are required", http.StatusBadRequest)
+		return
+	}
+
+	problems, err := h.Registry.CheckCompatibility(req.Type, req.From, req.To)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"compatible": len(problems) == 0,
+		"problems":   problems,
+	})
+}
+
+// HandleValidateEvent validates an event envelope without publishing it
+func (h *EventSchemaHandler) HandleValidateEvent(w http.ResponseWriter, r *http.Request) {
+	var event events.Envelope
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.Registry.Validate(&event)
+	var validationErr *events.ValidationError
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"valid": true})
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusOK, map[string]interface{}{"valid": false, "problems": validationErr.Problems})
+	default:
+		h.writeError(w, err)
+	}
+}
+
+// HandleListPublishers lists the event versions each connector publishes
+func (h *EventSchemaHandler) HandleListPublishers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.Registry.Publishers())
+}
+
+// HandleDeclarePublisher upgrades (or declares) the version a connector publishes
+func (h *EventSchemaHandler) HandleDeclarePublisher(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type    string `json:"type"`
+		Version int    `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Type == "" {
+		http.Error(w, "type and version are required", http.StatusBadRequest)
+		return
+	}
+
+	connector := mux.Vars(r)["connector"]
+	if err := h.Registry.DeclarePublisher(connector, req.Type, req.Version); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.Registry.Publishers()[connector])
+}
+
+// writeError maps registry errors to HTTP statuses
+func (h *EventSchemaHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, events.ErrUnknownSchema):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, events.ErrIncompatible):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,73 @@
+// backend/internal/api/handlers/evidence.go
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/evidence"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// EvidenceHandler accepts evidence files for control tests and compliance tasks
+type EvidenceHandler struct {
+	Uploader *servicenow.EvidenceUploader
+}
+
+// NewEvidenceHandler creates a new evidence upload handler
+func NewEvidenceHandler(uploader *servicenow.EvidenceUploader) *EvidenceHandler {
+	return &EvidenceHandler{
+		Uploader: uploader,
+	}
+}
+
+// HandleUpload attaches the multipart "file" field to a record in ServiceNow,
+// its Jira issue and evidence storage
+func (h *EvidenceHandler) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	if h.Uploader == nil {
+		writeError(w, http.StatusServiceUnavailable, "Evidence uploads are not configured")
+		return
+	}
+	vars := mux.Vars(r)
+	if _, ok := servicenow.EvidenceTables[vars["table"]]; !ok {
+		writeError(w, http.StatusBadRequest, "Evidence can only be attached to sn_policy_control_test and sn_compliance_task records")
+		return
+	}
+
+	// Leave room for the multipart framing around the file itself
+	r.Body = http.MaxBytesReader(w, r.Body, h.Uploader.Policy.MaxBytes+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, evidence.ErrTooLarge.Error())
+			return
+		}
+		writeError(w, http.StatusBadRequest, "Expected a multipart form with a \"file\" field")
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Error reading the uploaded file")
+		return
+	}
+
+	upload, err := h.Uploader.WithContext(r.Context()).Upload(vars["table"], vars["sysId"], r.FormValue("uploaded_by"), header.Filename, content, "")
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusCreated, upload)
+	case errors.Is(err, evidence.ErrTooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, evidence.ErrFileType):
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, evidence.ErrEmpty):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, servicenow.ErrRecordNotFound):
+		writeError(w, http.StatusNotFound, "Record not found in ServiceNow")
+	default:
+		writeError(w, http.StatusBadGateway, err.Error())
+	}
+}
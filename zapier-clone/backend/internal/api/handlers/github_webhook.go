@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/github"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/jobs"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+	"github.com/shivani-1505/zapier-clone/backend/internal/monitoring"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workflow"
+)
+
+// GitHubWebhookHandler handles issues and issue_comment webhooks from the
+// repository audit findings and compliance tasks are filed in
+type GitHubWebhookHandler struct {
+	Issues         *servicenow.GitHubIssues
+	VolumeDetector *monitoring.VolumeDetector
+	FailureAlerter *monitoring.FailureAlerter
+	// Jobs runs webhooks in the background; without it each gets its own goroutine
+	Jobs *jobs.Queue
+	// Workflows runs the stored workflows an issue or comment event starts
+	Workflows *workflow.Dispatcher
+}
+
+// NewGitHubWebhookHandler creates a new GitHub webhook handler
+func NewGitHubWebhookHandler(issues *servicenow.GitHubIssues) *GitHubWebhookHandler {
+	return &GitHubWebhookHandler{
+		Issues: issues,
+	}
+}
+
+// HandleWebhook processes incoming webhooks from GitHub
+func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	eventType := r.Header.Get("X-GitHub-Event")
+	if eventType == "ping" {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "pong"})
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading payload", http.StatusBadRequest)
+		return
+	}
+	var event github.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	// Processing outlives the request but keeps its correlation ID
+	ctx := logging.Detach(r.Context())
+	logging.FromContext(ctx).Info("received GitHub webhook", "event", eventType, "action", event.Action, "delivery", r.Header.Get("X-GitHub-Delivery"))
+
+	// Count the event for volume anomaly detection
+	h.VolumeDetector.Record("github")
+	metrics.WebhooksReceived.Inc("github")
+
+	// Process the webhook asynchronously
+	if err := h.submit(ctx, eventType, &event, body, time.Now()); err != nil {
+		rejectJob(w, err)
+		return
+	}
+
+	// Respond immediately to GitHub
+	writeJSON(w, http.StatusOK, map[string]string{"status": "received"})
+}
+
+// gitHubJob is a queued GitHub webhook; the event is kept as GitHub sent it
+type gitHubJob struct {
+	EventType string          `json:"event_type"`
+	Event     json.RawMessage `json:"event"`
+	Received  time.Time       `json:"received"`
+}
+
+// RegisterJobs processes the handler's webhooks on q
+func (h *GitHubWebhookHandler) RegisterJobs(q *jobs.Queue) {
+	h.Jobs = q
+	q.Handle(JobGitHubWebhook, func(ctx context.Context, data json.RawMessage) error {
+		var job gitHubJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		var event github.WebhookEvent
+		if err := json.Unmarshal(job.Event, &event); err != nil {
+			return err
+		}
+		h.processWebhook(ctx, job.EventType, &event, job.Event, job.Received)
+		return nil
+	})
+}
+
+// submit queues a webhook for processWebhook
+func (h *GitHubWebhookHandler) submit(ctx context.Context, eventType string, event *github.WebhookEvent, body []byte, received time.Time) error {
+	if h.Jobs == nil {
+		go h.processWebhook(ctx, eventType, event, body, received)
+		return nil
+	}
+	return h.Jobs.Submit(ctx, JobGitHubWebhook, gitHubJob{EventType: eventType, Event: body, Received: received})
+}
+
+// processWebhook applies the event to the linked ServiceNow record and runs
+// the stored workflows it starts; raw is the event as GitHub sent it
+func (h *GitHubWebhookHandler) processWebhook(ctx context.Context, eventType string, event *github.WebhookEvent, raw json.RawMessage, received time.Time) {
+	logger := logging.FromContext(ctx).With("event", eventType, "action", event.Action)
+
+	var syncErr error
+	defer func() {
+		metrics.WebhookProcessing.ObserveSince(received, "github", metrics.Result(syncErr))
+		h.dispatch(ctx, eventType, event, raw)
+	}()
+
+	linked, err := h.Issues.WithContext(ctx).HandleIssueEvent(eventType, event)
+	if err != nil {
+		logger.Error("error syncing GitHub issue event", "error", err)
+		syncErr = err
+		h.reportFailure(eventType, event, err)
+		return
+	}
+	if !linked {
+		logger.Debug("GitHub issue is not linked to a ServiceNow record")
+	}
+}
+
+// dispatch runs the stored workflows the event starts, such as
+// issues.opened, with the event as GitHub sent it as their trigger data
+func (h *GitHubWebhookHandler) dispatch(ctx context.Context, eventType string, event *github.WebhookEvent, raw json.RawMessage) {
+	if h.Workflows == nil || event.Action == "" {
+		return
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		logging.FromContext(ctx).Error("error reading GitHub event for workflows", "event", eventType, "error", err)
+		return
+	}
+	h.Workflows.Dispatch(ctx, "github", eventType+"."+event.Action, data)
+}
+
+// reportFailure alerts the ops channel about an event that could not be synced
+func (h *GitHubWebhookHandler) reportFailure(eventType string, event *github.WebhookEvent, err error) {
+	issue := ""
+	if event.Issue != nil {
+		issue = "#" + strconv.Itoa(event.Issue.Number)
+	}
+	metrics.SyncFailures.Inc("github")
+	h.FailureAlerter.Report("github", eventType+"."+event.Action, issue, event, err)
+}
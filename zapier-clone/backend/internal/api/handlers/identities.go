@@ -0,0 +1,184 @@
+// backend/internal/api/handlers/identities.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/identity"
+)
+
+// IdentityHandler manages the mappings between ServiceNow users and their Jira
+// and Slack accounts
+type IdentityHandler struct {
+	Store    *identity.Store
+	Resolver *identity.Resolver
+}
+
+// NewIdentityHandler creates a new identity handler
+func NewIdentityHandler(store *identity.Store, resolver *identity.Resolver) *IdentityHandler {
+	return &IdentityHandler{
+		Store:    store,
+		Resolver: resolver,
+	}
+}
+
+// HandleListIdentities returns every identity mapping
+func (h *IdentityHandler) HandleListIdentities(w http.ResponseWriter, r *http.Request) {
+	if !h.configured(w) {
+		return
+	}
+
+	identities, err := h.Store.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, identities)
+}
+
+// HandleGetIdentity returns one identity mapping
+func (h *IdentityHandler) HandleGetIdentity(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.identityID(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := h.Store.Get(id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, found)
+}
+
+// HandleCreateIdentity stores a manual mapping; automatic matching never changes it
+func (h *IdentityHandler) HandleCreateIdentity(w http.ResponseWriter, r *http.Request) {
+	if !h.configured(w) {
+		return
+	}
+
+	var request identity.Identity
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if request.Source == "" {
+		request.Source = identity.SourceManual
+	}
+
+	created, err := h.Store.Create(request)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.Resolver.Forget()
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// HandleUpdateIdentity replaces a mapping. It becomes manual unless the body
+// sets "source": "auto", which lets email matching fill in missing accounts.
+func (h *IdentityHandler) HandleUpdateIdentity(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.identityID(w, r)
+	if !ok {
+		return
+	}
+
+	var request identity.Identity
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if request.Source == "" {
+		request.Source = identity.SourceManual
+	}
+
+	updated, err := h.Store.Update(id, request)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.Resolver.Forget()
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// HandleDeleteIdentity removes a mapping; the person is matched by email again when next seen
+func (h *IdentityHandler) HandleDeleteIdentity(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.identityID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.Delete(id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.Resolver.Forget()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleMatchIdentity matches a ServiceNow user by email now and returns the
+// stored mapping: {"servicenow_user": "<sys_id, user_name or email>"}
+func (h *IdentityHandler) HandleMatchIdentity(w http.ResponseWriter, r *http.Request) {
+	if !h.configured(w) {
+		return
+	}
+
+	var request struct {
+		ServiceNowUser string `json:"servicenow_user"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.ServiceNowUser == "" {
+		writeError(w, http.StatusBadRequest, "servicenow_user is required")
+		return
+	}
+
+	matched, err := h.Resolver.Match(r.Context(), request.ServiceNowUser)
+	if errors.Is(err, identity.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "No ServiceNow user with an email address matches "+request.ServiceNowUser)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, matched)
+}
+
+// configured writes 503 when there is no database to keep mappings in
+func (h *IdentityHandler) configured(w http.ResponseWriter) bool {
+	if h.Store == nil {
+		writeError(w, http.StatusServiceUnavailable, "Identity mapping needs DATABASE_URL")
+		return false
+	}
+	return true
+}
+
+// identityID reads the {id} path variable
+func (h *IdentityHandler) identityID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if !h.configured(w) {
+		return 0, false
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid identity ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps identity store errors to HTTP statuses
+func (h *IdentityHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, identity.ErrNotFound):
+		writeError(w, http.StatusNotFound, "Identity not found")
+	case errors.Is(err, identity.ErrExists):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, identity.ErrInvalid), errors.Is(err, identity.ErrSource):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,81 @@
+// backend/internal/api/handlers/integrations.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+)
+
+// IntegrationHandler lists the registered integrations and runs their actions
+// and connection checks
+type IntegrationHandler struct {
+	Registry *common.Registry
+}
+
+// NewIntegrationHandler creates a new integration handler
+func NewIntegrationHandler(registry *common.Registry) *IntegrationHandler {
+	return &IntegrationHandler{
+		Registry: registry,
+	}
+}
+
+// HandleListIntegrations returns every integration with its triggers and actions
+func (h *IntegrationHandler) HandleListIntegrations(w http.ResponseWriter, r *http.Request) {
+	descriptors := []common.Descriptor{}
+	for _, integration := range h.Registry.List() {
+		descriptors = append(descriptors, common.Describe(integration))
+	}
+	writeJSON(w, http.StatusOK, descriptors)
+}
+
+// HandleValidateIntegration checks an integration's credentials
+func (h *IntegrationHandler) HandleValidateIntegration(w http.ResponseWriter, r *http.Request) {
+	err := h.Registry.Validate(r.Context(), mux.Vars(r)["name"])
+	if h.writeRequestError(w, err) {
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// HandleExecuteAction runs one action with the fields in the request body, so
+// a workflow step can be tried before it is saved
+func (h *IntegrationHandler) HandleExecuteAction(w http.ResponseWriter, r *http.Request) {
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	vars := mux.Vars(r)
+	output, err := h.Registry.Execute(r.Context(), vars["name"], vars["action"], fields)
+	if h.writeRequestError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, output)
+}
+
+// writeRequestError answers requests for unknown integrations, actions or
+// capabilities and actions missing a field, and reports whether it did
+func (h *IntegrationHandler) writeRequestError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, common.ErrUnknownIntegration), errors.Is(err, common.ErrUnknownAction):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, common.ErrNotSupported), errors.Is(err, common.ErrMissingField):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		return false
+	}
+	return true
+}
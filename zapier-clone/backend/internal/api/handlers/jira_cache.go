@@ -0,0 +1,40 @@
+// backend/internal/api/handlers/jira_cache.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+)
+
+// JiraCacheHandler shows and clears the Jira metadata the client caches
+type JiraCacheHandler struct {
+	JiraClient *jira.Client
+}
+
+// NewJiraCacheHandler creates a new Jira metadata cache handler
+func NewJiraCacheHandler(jiraClient *jira.Client) *JiraCacheHandler {
+	return &JiraCacheHandler{
+		JiraClient: jiraClient,
+	}
+}
+
+// HandleGetCache counts the cached transitions, fields and projects
+func (h *JiraCacheHandler) HandleGetCache(w http.ResponseWriter, r *http.Request) {
+	if h.JiraClient == nil {
+		writeError(w, http.StatusServiceUnavailable, "Jira is not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.JiraClient.MetadataCacheStats())
+}
+
+// HandleInvalidateCache drops the cached metadata so the next calls fetch it
+// again, such as after a workflow was changed in Jira
+func (h *JiraCacheHandler) HandleInvalidateCache(w http.ResponseWriter, r *http.Request) {
+	if h.JiraClient == nil {
+		writeError(w, http.StatusServiceUnavailable, "Jira is not configured")
+		return
+	}
+	h.JiraClient.InvalidateMetadata()
+	writeJSON(w, http.StatusOK, h.JiraClient.MetadataCacheStats())
+}
@@ -0,0 +1,37 @@
+// backend/internal/api/handlers/jira_projects.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// JiraProjectHandler shows which Jira projects and components were
+// provisioned for ServiceNow tables
+type JiraProjectHandler struct {
+	Projects *servicenow.ProjectProvisioner
+}
+
+// NewJiraProjectHandler creates a new provisioned project handler
+func NewJiraProjectHandler(projects *servicenow.ProjectProvisioner) *JiraProjectHandler {
+	return &JiraProjectHandler{
+		Projects: projects,
+	}
+}
+
+// HandleListProvisioned returns the provisioned project or component of each table
+func (h *JiraProjectHandler) HandleListProvisioned(w http.ResponseWriter, r *http.Request) {
+	provisioned := h.Projects.List()
+	if provisioned == nil {
+		provisioned = []servicenow.ProvisionedProject{}
+	}
+	mode := ""
+	if h.Projects != nil {
+		mode = h.Projects.Mode
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"mode":        mode,
+		"provisioned": provisioned,
+	})
+}
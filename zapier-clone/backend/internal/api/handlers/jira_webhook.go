@@ -1,13 +1,24 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/consistency"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/jobs"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/loopguard"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+	"github.com/shivani-1505/zapier-clone/backend/internal/monitoring"
+	"github.com/shivani-1505/zapier-clone/backend/internal/syncevents"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workflow"
 )
 
 // JiraWebhookHandler handles incoming webhooks from Jira
@@ -16,6 +27,24 @@ type JiraWebhookHandler struct {
 	SlackClient      *slack.Client
 	JiraClient       *jira.Client
 	AuditHandler     *servicenow.AuditHandler
+	DeletionHandler  *servicenow.JiraDeletionHandler
+	MoveHandler      *servicenow.JiraMoveHandler
+	CommentSync      *servicenow.CommentSync
+	VolumeDetector   *monitoring.VolumeDetector
+	LoopGuard        *loopguard.Guard
+	FailureAlerter   *monitoring.FailureAlerter
+	// Conflicts holds back updates that conflict with a recent ServiceNow edit
+	Conflicts *consistency.ConflictDetector
+	// Events streams the outcome of each webhook to subscribers such as gRPC clients
+	Events *syncevents.Hub
+	// Jobs runs webhooks in the background; without it each gets its own goroutine
+	Jobs *jobs.Queue
+	// RegulatoryChanges records finished regulatory change subtasks in ServiceNow
+	RegulatoryChanges *servicenow.RegulatoryChangeHandler
+	// Remediation records finished audit finding milestones
+	Remediation *servicenow.RemediationTracker
+	// Workflows runs the stored workflows an issue or comment event starts
+	Workflows *workflow.Dispatcher
 }
 
 // NewJiraWebhookHandler creates a new Jira webhook handler
@@ -34,41 +63,293 @@ func NewJiraWebhookHandler(
 
 // HandleWebhook processes incoming webhooks from Jira
 func (h *JiraWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
-	// Parse the incoming webhook payload
+	// Parse the incoming webhook payload, keeping the body for the queued job
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading payload", http.StatusBadRequest)
+		return
+	}
 	var event jira.WebhookEvent
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+	if err := json.Unmarshal(body, &event); err != nil {
 		http.Error(w, "Invalid payload", http.StatusBadRequest)
 		return
 	}
 
-	// Log the received webhook
-	log.Printf("Received Jira webhook: %s", event.WebhookEvent)
+	// Processing outlives the request but keeps its correlation ID
+	ctx := logging.Detach(r.Context())
+	logging.FromContext(ctx).Info("received Jira webhook", "event", event.WebhookEvent)
+
+	// Count the event for volume anomaly detection
+	h.VolumeDetector.Record("jira")
+	metrics.WebhooksReceived.Inc("jira")
 
 	// Process the webhook asynchronously
-	go h.processWebhook(&event)
+	if err := h.submit(ctx, &event, body, time.Now()); err != nil {
+		rejectJob(w, err)
+		return
+	}
 
 	// Respond immediately to Jira
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"status":"received"}`))
 }
 
-// processWebhook processes the webhook payload asynchronously
-func (h *JiraWebhookHandler) processWebhook(event *jira.WebhookEvent) {
+// jiraJob is a queued Jira webhook; the event is kept as Jira sent it
+type jiraJob struct {
+	Event    json.RawMessage `json:"event"`
+	Received time.Time       `json:"received"`
+}
+
+// RegisterJobs processes the handler's webhooks on q
+func (h *JiraWebhookHandler) RegisterJobs(q *jobs.Queue) {
+	h.Jobs = q
+	q.Handle(JobJiraWebhook, func(ctx context.Context, data json.RawMessage) error {
+		var job jiraJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		var event jira.WebhookEvent
+		if err := json.Unmarshal(job.Event, &event); err != nil {
+			return err
+		}
+		h.processWebhook(ctx, &event, job.Event, job.Received)
+		return nil
+	})
+}
+
+// submit queues a webhook for processWebhook
+func (h *JiraWebhookHandler) submit(ctx context.Context, event *jira.WebhookEvent, body []byte, received time.Time) error {
+	if h.Jobs == nil {
+		go h.processWebhook(ctx, event, body, received)
+		return nil
+	}
+	return h.Jobs.Submit(ctx, JobJiraWebhook, jiraJob{Event: body, Received: received})
+}
+
+// processWebhook processes the webhook payload asynchronously; raw is the event as Jira sent it
+func (h *JiraWebhookHandler) processWebhook(ctx context.Context, event *jira.WebhookEvent, raw json.RawMessage, received time.Time) {
+	h = h.withContext(ctx)
+	logger := logging.FromContext(ctx).With("event", event.WebhookEvent)
+
+	var syncErr error
+	result := syncevents.ResultSuccess
+	defer func() {
+		metrics.WebhookProcessing.ObserveSince(received, "jira", metrics.Result(syncErr))
+		if syncErr != nil {
+			result = syncevents.ResultFailure
+		}
+		h.Events.Publish(jiraEvent(event, result, syncErr))
+		// Events held back from the sync would loop through workflows too
+		if result != syncevents.ResultSkipped {
+			h.dispatch(ctx, event, raw)
+		}
+	}()
+
 	// Handle different types of events
 	switch event.WebhookEvent {
 	case "jira:issue_updated":
+		// Transitions cached for the issue's old status no longer apply
+		if status, ok := changedStatus(event); ok {
+			h.JiraClient.IssueStatusChanged(event.Issue.Key, status)
+		}
+		// Re-point the linked record first so the rest of the update finds it under the new key
+		if h.MoveHandler != nil {
+			if moved, err := h.MoveHandler.HandleIssueMoved(event); err != nil {
+				logger.Error("error relinking moved Jira issue", "error", err)
+				syncErr = err
+				h.reportFailure(event, err)
+			} else if moved {
+				logger.Info("issue moved", "issue", event.Issue.Key)
+			}
+		}
+		if !h.LoopGuard.Allow(syncEntity(event), loopguard.OriginJira, changelogFields(event)) {
+			logger.Info("skipping update: sync loop guard is active", "issue", event.Issue.Key)
+			result = syncevents.ResultSkipped
+			return
+		}
+		if !h.Conflicts.JiraUpdate(ctx, event, raw) {
+			logger.Info("skipping update: it conflicts with a recent ServiceNow edit", "issue", event.Issue.Key)
+			result = syncevents.ResultSkipped
+			return
+		}
+		// Regulatory change subtasks have no ServiceNow record of their own
+		if h.RegulatoryChanges != nil {
+			handled, err := h.RegulatoryChanges.HandleJiraUpdate(event)
+			if err != nil {
+				logger.Error("error tracking regulatory change subtask", "issue", event.Issue.Key, "error", err)
+				syncErr = err
+				h.reportFailure(event, err)
+			}
+			if handled {
+				return
+			}
+		}
+		// Nor do remediation milestones, which are sub-tasks of a finding's issue
+		if h.Remediation != nil {
+			handled, err := h.Remediation.HandleJiraUpdate(event)
+			if err != nil {
+				logger.Error("error tracking remediation milestone", "issue", event.Issue.Key, "error", err)
+				syncErr = err
+				h.reportFailure(event, err)
+			}
+			if handled {
+				return
+			}
+		}
 		if err := h.AuditHandler.HandleJiraUpdate(event); err != nil {
-			log.Printf("Error processing Jira issue update: %v", err)
+			logger.Error("error processing Jira issue update", "error", err)
+			syncErr = err
+			h.reportFailure(event, err)
 		}
 	case "jira:issue_created":
-		log.Printf("Issue created: %s", event.Issue.Key)
+		logger.Info("issue created", "issue", event.Issue.Key)
 	case "jira:issue_deleted":
-		log.Printf("Issue deleted: %s", event.Issue.Key)
+		logger.Info("issue deleted", "issue", event.Issue.Key)
+		if h.DeletionHandler != nil {
+			if err := h.DeletionHandler.HandleIssueDeleted(event); err != nil {
+				logger.Error("error applying Jira deletion policy", "error", err)
+				syncErr = err
+				h.reportFailure(event, err)
+			}
+		}
 	case "comment_created", "comment_updated", "comment_deleted":
 		if err := h.AuditHandler.HandleJiraUpdate(event); err != nil {
-			log.Printf("Error processing Jira comment event: %v", err)
+			logger.Error("error processing Jira comment event", "error", err)
+			syncErr = err
+			h.reportFailure(event, err)
+		}
+		if h.CommentSync != nil {
+			if err := h.CommentSync.HandleJiraComment(event); err != nil {
+				logger.Error("error syncing Jira comment", "error", err)
+				syncErr = err
+				h.reportFailure(event, err)
+			}
 		}
 	default:
-		log.Printf("Unhandled Jira event type: %s", event.WebhookEvent)
+		logger.Warn("unhandled Jira event type")
+	}
+}
+
+// jiraTriggers maps Jira webhook events to the jira integration's triggers
+var jiraTriggers = map[string]string{
+	"jira:issue_created": "issue.created",
+	"jira:issue_updated": "issue.updated",
+	"jira:issue_deleted": "issue.deleted",
+	"comment_created":    "comment.created",
+}
+
+// dispatch runs the stored workflows the event starts, with the event as Jira
+// sent it as their trigger data
+func (h *JiraWebhookHandler) dispatch(ctx context.Context, event *jira.WebhookEvent, raw json.RawMessage) {
+	trigger, ok := jiraTriggers[event.WebhookEvent]
+	if !ok || h.Workflows == nil {
+		return
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		logging.FromContext(ctx).Error("error reading Jira event for workflows", "event", event.WebhookEvent, "error", err)
+		return
+	}
+	h.Workflows.Dispatch(ctx, "jira", trigger, data)
+}
+
+// Replay syncs a Jira issue update that was held back, such as one kept when a
+// conflict was resolved. It bypasses the loop guard and the conflict detector.
+func (h *JiraWebhookHandler) Replay(ctx context.Context, data json.RawMessage) error {
+	var event jira.WebhookEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("invalid Jira event: %w", err)
+	}
+	return h.withContext(ctx).AuditHandler.HandleJiraUpdate(&event)
+}
+
+// withContext returns a copy of the handler whose clients and flows run under ctx
+func (h *JiraWebhookHandler) withContext(ctx context.Context) *JiraWebhookHandler {
+	scoped := *h
+	scoped.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	scoped.JiraClient = h.JiraClient.WithContext(ctx)
+	scoped.SlackClient = h.SlackClient.WithContext(ctx)
+	scoped.AuditHandler = h.AuditHandler.WithContext(ctx)
+	if h.DeletionHandler != nil {
+		scoped.DeletionHandler = h.DeletionHandler.WithContext(ctx)
+	}
+	if h.MoveHandler != nil {
+		scoped.MoveHandler = h.MoveHandler.WithContext(ctx)
+	}
+	if h.CommentSync != nil {
+		scoped.CommentSync = h.CommentSync.WithContext(ctx)
+	}
+	if h.RegulatoryChanges != nil {
+		scoped.RegulatoryChanges = h.RegulatoryChanges.WithContext(ctx)
+	}
+	if h.Remediation != nil {
+		scoped.Remediation = h.Remediation.WithContext(ctx)
+	}
+	return &scoped
+}
+
+// reportFailure alerts the ops channel about an event that could not be synced
+func (h *JiraWebhookHandler) reportFailure(event *jira.WebhookEvent, err error) {
+	issueKey := ""
+	if event.Issue != nil {
+		issueKey = event.Issue.Key
+	}
+	metrics.SyncFailures.Inc("jira")
+	h.FailureAlerter.Report("jira", event.WebhookEvent, issueKey, event, err)
+}
+
+// jiraEvent describes a processed webhook for event subscribers
+func jiraEvent(event *jira.WebhookEvent, result string, err error) syncevents.Event {
+	synced := syncevents.Event{
+		Source: syncevents.SourceJira,
+		Action: event.WebhookEvent,
+		Result: result,
+	}
+	if event.Issue != nil {
+		synced.JiraKey = event.Issue.Key
+		synced.SysID = event.Issue.ServiceNowID()
+	}
+	if err != nil {
+		synced.Error = err.Error()
+	}
+	return synced
+}
+
+// syncEntity identifies the record an event refers to, preferring the linked ServiceNow ID
+// so that updates from both systems land in the same loop guard chain
+func syncEntity(event *jira.WebhookEvent) string {
+	if event.Issue == nil {
+		return ""
+	}
+	if servicenowID := event.Issue.ServiceNowID(); servicenowID != "" {
+		return servicenowID
+	}
+	return event.Issue.Key
+}
+
+// changedStatus returns the status a Jira update moved the issue to
+func changedStatus(event *jira.WebhookEvent) (string, bool) {
+	if event.Changelog == nil || event.Issue == nil {
+		return "", false
+	}
+	for _, item := range event.Changelog.Items {
+		if item.Field == "status" {
+			return item.ToString, true
+		}
+	}
+	return "", false
+}
+
+// changelogFields lists the fields changed by a Jira update
+func changelogFields(event *jira.WebhookEvent) []string {
+	var fields []string
+	if event.Changelog != nil {
+		for _, item := range event.Changelog.Items {
+			fields = append(fields, item.Field)
+		}
+	}
+	if event.Comment != nil {
+		fields = append(fields, "comment")
 	}
+	return fields
 }
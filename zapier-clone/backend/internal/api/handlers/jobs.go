@@ -0,0 +1,22 @@
+// backend/internal/api/handlers/jobs.go
+package handlers
+
+import (
+	"net/http"
+)
+
+// Background job kinds. Webhooks and interactions are acknowledged right away
+// and processed on the job pool, which drains them at shutdown.
+const (
+	JobServiceNowWebhook = "servicenow.webhook"
+	JobJiraWebhook       = "jira.webhook"
+	JobGitHubWebhook     = "github.webhook"
+	JobInteraction       = "slack.interaction"
+)
+
+// rejectJob answers a webhook whose job could not be queued. 503 makes
+// ServiceNow, Jira and GitHub deliver it again, by then to a running server.
+func rejectJob(w http.ResponseWriter, err error) {
+	w.Header().Set("Retry-After", "30")
+	http.Error(w, "Service unavailable: "+err.Error(), http.StatusServiceUnavailable)
+}
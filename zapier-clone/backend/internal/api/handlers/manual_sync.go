@@ -0,0 +1,311 @@
+// backend/internal/api/handlers/manual_sync.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// Sync operations reported for each target system
+const (
+	SyncCreated   = "created"
+	SyncUpdated   = "updated"
+	SyncUnchanged = "unchanged"
+	SyncSkipped   = "skipped"
+	SyncFailed    = "failed"
+)
+
+// tableChannels maps ServiceNow tables to the Slack channel key they post to
+var tableChannels = map[string]string{
+	"sn_risk_risk":           "risk-management",
+	"sn_compliance_task":     "compliance",
+	"sn_si_incident":         "incident",
+	"sn_policy_control_test": "control-testing",
+	"sn_audit_finding":       "audit",
+	"sn_vendor_risk":         "vendor-risk",
+	"sn_regulatory_change":   "regulatory",
+}
+
+// FieldChange is a single field that differed between ServiceNow and the target
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// SyncAction describes what a manual sync did in one target system
+type SyncAction struct {
+	Target    string        `json:"target"`
+	Operation string        `json:"operation"`
+	Reference string        `json:"reference,omitempty"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// SyncResult is the response of a manual sync
+type SyncResult struct {
+	Table    string       `json:"table"`
+	SysID    string       `json:"sys_id"`
+	Number   string       `json:"number,omitempty"`
+	Actions  []SyncAction `json:"actions"`
+	Duration string       `json:"duration"`
+}
+
+// HandleManualSync forces a re-sync of one ServiceNow record to Jira and Slack
+func (h *ServiceNowWebhookHandler) HandleManualSync(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	table := vars["table"]
+	sysID := vars["sysId"]
+
+	if _, ok := tableChannels[table]; !ok && !h.CustomTables.Handles(table) {
+		http.Error(w, fmt.Sprintf("Unsupported table: %s", table), http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+
+	// Bind this request's deadline and correlation ID to every call the sync makes
+	scoped := h.withContext(r.Context())
+
+	record, err := scoped.ServiceNowClient.GetRecord(table, sysID)
+	if errors.Is(err, servicenow.ErrRecordNotFound) {
+		http.Error(w, "Record not found in ServiceNow", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		scoped.log().Error("error fetching record for manual sync", "table", table, "sys_id", sysID, "error", err)
+		http.Error(w, "Error fetching record from ServiceNow", http.StatusBadGateway)
+		return
+	}
+
+	result := scoped.syncRecord(table, sysID, record)
+	result.Duration = time.Since(start).String()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// syncRecord updates the linked Jira issue, or runs the new-record flow when there is none
+func (h *ServiceNowWebhookHandler) syncRecord(table, sysID string, record map[string]interface{}) *SyncResult {
+	result := &SyncResult{
+		Table:  table,
+		SysID:  sysID,
+		Number: stringField(record, "number"),
+	}
+	// Wait for a webhook already syncing this record
+	defer h.Locks.Lock(table + ":" + sysID)()
+
+	jiraKey := h.linkedJiraKey(table, sysID, record)
+	if jiraKey == "" {
+		// Nothing synced yet - treat the record as new so mapping and routing run in full
+		ts, err := h.dispatchNewRecord(table, record)
+		if err != nil {
+			result.Actions = append(result.Actions, SyncAction{Target: "slack", Operation: SyncFailed, Error: err.Error()})
+			return result
+		}
+		result.Actions = append(result.Actions, SyncAction{Target: "slack", Operation: SyncCreated, Reference: ts})
+
+		// Handlers that create Jira issues write the key back to ServiceNow, so re-read the record
+		if refreshed, err := h.ServiceNowClient.GetRecord(table, sysID); err == nil {
+			record = refreshed
+		}
+		if key := h.linkedJiraKey(table, sysID, record); key != "" {
+			result.Actions = append(result.Actions, SyncAction{Target: "jira", Operation: SyncCreated, Reference: key})
+		} else {
+			result.Actions = append(result.Actions, SyncAction{Target: "jira", Operation: SyncSkipped})
+		}
+		return result
+	}
+
+	jiraAction := h.syncJiraIssue(jiraKey, record)
+	result.Actions = append(result.Actions, jiraAction)
+	result.Actions = append(result.Actions, h.postSyncNotice(table, record, jiraAction))
+
+	return result
+}
+
+// syncJiraIssue brings the summary and description of a linked issue up to date
+func (h *ServiceNowWebhookHandler) syncJiraIssue(jiraKey string, record map[string]interface{}) SyncAction {
+	action := SyncAction{Target: "jira", Reference: jiraKey}
+
+	issue, err := h.JiraClient.GetIssue(jiraKey)
+	if err != nil {
+		action.Operation = SyncFailed
+		action.Error = err.Error()
+		return action
+	}
+
+	fields, _ := issue["fields"].(map[string]interface{})
+
+	update := &jira.TicketUpdate{}
+
+	summary := fmt.Sprintf("[%s] %s", stringField(record, "number"), stringField(record, "short_description"))
+	if current := stringField(fields, "summary"); current != summary {
+		action.Changes = append(action.Changes, FieldChange{Field: "summary", Old: current, New: summary})
+		update.Summary = summary
+	}
+
+	if description := stringField(record, "description"); description != "" {
+		// v3 issues return the description as an ADF document; compare the text only
+		if current := jira.TextValue(fields["description"]); !jira.SameText(current, description) {
+			action.Changes = append(action.Changes, FieldChange{Field: "description", Old: current, New: description})
+			update.Description = description
+		}
+	}
+
+	if len(action.Changes) == 0 {
+		action.Operation = SyncUnchanged
+		return action
+	}
+
+	update.Comment = "🔄 Manually re-synced from ServiceNow."
+	if err := h.JiraClient.UpdateIssue(jiraKey, update); err != nil {
+		action.Operation = SyncFailed
+		action.Error = err.Error()
+		return action
+	}
+
+	action.Operation = SyncUpdated
+	return action
+}
+
+// postSyncNotice posts the current record state to the table's Slack channel
+func (h *ServiceNowWebhookHandler) postSyncNotice(table string, record map[string]interface{}, jiraAction SyncAction) SyncAction {
+	channel, ok := slack.ChannelMapping[tableChannels[table]]
+	if !ok {
+		// Registered tables post where grc_item notifications default to
+		channel = slack.ChannelMapping[notification.ItemTypes["grc_item"]]
+	}
+
+	text := fmt.Sprintf("🔄 *%s* was manually re-synced: %s\n• State: %s\n• Jira: %s (%s)",
+		stringField(record, "number"),
+		stringField(record, "short_description"),
+		stringField(record, "state"),
+		jiraAction.Reference,
+		jiraAction.Operation)
+	for _, change := range jiraAction.Changes {
+		text += fmt.Sprintf("\n• Updated %s", change.Field)
+	}
+
+	ts, err := h.SlackClient.PostMessage(channel, slack.Message{Text: text})
+	if err != nil {
+		return SyncAction{Target: "slack", Operation: SyncFailed, Error: err.Error()}
+	}
+
+	return SyncAction{Target: "slack", Operation: SyncCreated, Reference: ts}
+}
+
+// linkedJiraKey finds the Jira issue already linked to a ServiceNow record
+func (h *ServiceNowWebhookHandler) linkedJiraKey(table, sysID string, record map[string]interface{}) string {
+	if table == "sn_risk_risk" && h.RiskHandler != nil && h.RiskHandler.RiskJiraMapping != nil {
+		if key, ok := h.RiskHandler.RiskJiraMapping.GetJiraKeyFromRiskID(sysID); ok {
+			return key
+		}
+	}
+	if h.CustomTables.Handles(table) {
+		if key, ok := h.CustomTables.Tables.IssueKey(table, sysID); ok {
+			return key
+		}
+	}
+	if table == "sn_regulatory_change" && h.RegulatoryChangeHandler != nil {
+		if tasks, ok := h.RegulatoryChangeHandler.Tasks.Get(sysID); ok {
+			return tasks.Epic
+		}
+	}
+
+	return stringField(record, "jira_ticket")
+}
+
+// dispatchNewRecord runs the new-record handler for a table and returns the Slack message timestamp
+func (h *ServiceNowWebhookHandler) dispatchNewRecord(table string, record map[string]interface{}) (string, error) {
+	switch table {
+	case "sn_risk_risk":
+		var risk servicenow.Risk
+		if err := decodeRecord(record, &risk); err != nil {
+			return "", err
+		}
+		return h.RiskHandler.HandleNewRisk(risk)
+	case "sn_compliance_task":
+		var task servicenow.ComplianceTask
+		if err := decodeRecord(record, &task); err != nil {
+			return "", err
+		}
+		return h.ComplianceHandler.HandleNewComplianceTask(task)
+	case "sn_si_incident":
+		var incident servicenow.Incident
+		if err := decodeRecord(record, &incident); err != nil {
+			return "", err
+		}
+		return h.IncidentHandler.HandleNewIncident(incident)
+	case "sn_policy_control_test":
+		var test servicenow.ControlTest
+		if err := decodeRecord(record, &test); err != nil {
+			return "", err
+		}
+		return h.ControlTestHandler.HandleNewControlTest(test)
+	case "sn_audit_finding":
+		var finding servicenow.AuditFinding
+		if err := decodeRecord(record, &finding); err != nil {
+			return "", err
+		}
+		return h.AuditHandler.HandleNewAuditFinding(finding)
+	case "sn_vendor_risk":
+		var risk servicenow.VendorRisk
+		if err := decodeRecord(record, &risk); err != nil {
+			return "", err
+		}
+		return h.VendorRiskHandler.HandleNewVendorRisk(risk)
+	case "sn_regulatory_change":
+		var change servicenow.RegulatoryChange
+		if err := decodeRecord(record, &change); err != nil {
+			return "", err
+		}
+		return h.RegulatoryChangeHandler.HandleNewRegulatoryChange(change)
+	default:
+		if h.CustomTables.Handles(table) {
+			return h.CustomTables.HandleNewRecord(servicenow.WebhookPayload{
+				ID:         stringField(record, "sys_id"),
+				TableName:  table,
+				ActionType: "inserted",
+				Data:       record,
+			})
+		}
+		return "", fmt.Errorf("unsupported table: %s", table)
+	}
+}
+
+// decodeRecord converts a generic ServiceNow record into a typed model
+func decodeRecord(record map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error marshaling record: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error unmarshaling record: %w", err)
+	}
+	return nil
+}
+
+// stringField reads a string value from a generic record, tolerating missing keys
+func stringField(record map[string]interface{}, field string) string {
+	if record == nil {
+		return ""
+	}
+	switch v := record[field].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
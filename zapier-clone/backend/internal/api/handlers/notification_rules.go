@@ -0,0 +1,157 @@
+// backend/internal/api/handlers/notification_rules.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// NotificationRuleHandler manages the rules that route Slack notifications to channels
+type NotificationRuleHandler struct {
+	Router *notification.Router
+}
+
+// NewNotificationRuleHandler creates a new notification rule handler
+func NewNotificationRuleHandler(router *notification.Router) *NotificationRuleHandler {
+	return &NotificationRuleHandler{
+		Router: router,
+	}
+}
+
+// HandleListRules returns every rule in creation order
+func (h *NotificationRuleHandler) HandleListRules(w http.ResponseWriter, r *http.Request) {
+	if h.Router == nil {
+		writeError(w, http.StatusServiceUnavailable, "Notification rules are not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.Router.List())
+}
+
+// HandleGetRule returns one rule
+func (h *NotificationRuleHandler) HandleGetRule(w http.ResponseWriter, r *http.Request) {
+	if h.Router == nil {
+		writeError(w, http.StatusServiceUnavailable, "Notification rules are not configured")
+		return
+	}
+
+	rule, ok := h.Router.Get(mux.Vars(r)["id"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "Notification rule not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// HandleCreateRule stores a new rule; it applies to the next notification
+func (h *NotificationRuleHandler) HandleCreateRule(w http.ResponseWriter, r *http.Request) {
+	if h.Router == nil {
+		writeError(w, http.StatusServiceUnavailable, "Notification rules are not configured")
+		return
+	}
+
+	var rule notification.Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	created, err := h.Router.Create(rule)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// HandleUpdateRule replaces a rule's settings
+func (h *NotificationRuleHandler) HandleUpdateRule(w http.ResponseWriter, r *http.Request) {
+	if h.Router == nil {
+		writeError(w, http.StatusServiceUnavailable, "Notification rules are not configured")
+		return
+	}
+
+	var rule notification.Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	updated, err := h.Router.Update(mux.Vars(r)["id"], rule)
+	if errors.Is(err, notification.ErrRuleNotFound) {
+		writeError(w, http.StatusNotFound, "Notification rule not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// HandleDeleteRule removes a rule
+func (h *NotificationRuleHandler) HandleDeleteRule(w http.ResponseWriter, r *http.Request) {
+	if h.Router == nil {
+		writeError(w, http.StatusServiceUnavailable, "Notification rules are not configured")
+		return
+	}
+
+	err := h.Router.Delete(mux.Vars(r)["id"])
+	if errors.Is(err, notification.ErrRuleNotFound) {
+		writeError(w, http.StatusNotFound, "Notification rule not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandlePreviewRoute returns the channels an item would be announced in, the
+// language of each and those that would hold it back now, e.g.
+// ?item_type=risk&severity=High&category=Security
+func (h *NotificationRuleHandler) HandlePreviewRoute(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	item := notification.Item{
+		Type:     query.Get("item_type"),
+		Severity: query.Get("severity"),
+		Category: query.Get("category"),
+	}
+	if _, ok := notification.ItemTypes[item.Type]; !ok {
+		writeError(w, http.StatusBadRequest, "Unknown item_type")
+		return
+	}
+
+	channels := h.Router.Channels(item)
+	locales := make(map[string]string, len(channels))
+	held := make(map[string]string)
+	for _, channel := range channels {
+		locales[channel] = h.Router.Locale(channel)
+		if reason := h.Router.Throttle(channel, item); reason != "" {
+			held[channel] = reason
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"item_type": item.Type,
+		"severity":  item.Severity,
+		"category":  item.Category,
+		"channels":  channels,
+		"locales":   locales,
+		"held":      held,
+	})
+}
+
+// HandleListChannels returns the quiet hours, rate cap and recent volume of
+// every channel a rule throttles
+func (h *NotificationRuleHandler) HandleListChannels(w http.ResponseWriter, r *http.Request) {
+	if h.Router == nil {
+		writeError(w, http.StatusServiceUnavailable, "Notification rules are not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.Router.ChannelStatuses())
+}
@@ -0,0 +1,63 @@
+// backend/internal/api/handlers/pollers.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/polling"
+)
+
+// PollerHandler exposes polling source health and manual runs on the admin API
+type PollerHandler struct {
+	Poller *polling.Poller
+}
+
+// NewPollerHandler creates a new poller handler
+func NewPollerHandler(poller *polling.Poller) *PollerHandler {
+	return &PollerHandler{
+		Poller: poller,
+	}
+}
+
+// HandleListPollers returns the health of every polling source
+func (h *PollerHandler) HandleListPollers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Poller.Health())
+}
+
+// HandleRunPoller polls a source now and returns its health afterwards
+func (h *PollerHandler) HandleRunPoller(w http.ResponseWriter, r *http.Request) {
+	health, err := h.Poller.RunNow(r.Context(), mux.Vars(r)["source"])
+	switch {
+	case errors.Is(err, polling.ErrUnknownSource):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, polling.ErrRunning):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	json.NewEncoder(w).Encode(health)
+}
+
+// HandleResetWatermark makes a source start over on its next poll
+func (h *PollerHandler) HandleResetWatermark(w http.ResponseWriter, r *http.Request) {
+	if err := h.Poller.Reset(mux.Vars(r)["source"]); err != nil {
+		if errors.Is(err, polling.ErrUnknownSource) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}`))
+}
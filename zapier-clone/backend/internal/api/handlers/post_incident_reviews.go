@@ -0,0 +1,82 @@
+// backend/internal/api/handlers/post_incident_reviews.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// PostIncidentReviewHandler lists the reviews drafted for resolved incidents
+// and drafts them on request
+type PostIncidentReviewHandler struct {
+	Reviews *servicenow.PostIncidentReviews
+}
+
+// NewPostIncidentReviewHandler creates a new post-incident review handler
+func NewPostIncidentReviewHandler(reviews *servicenow.PostIncidentReviews) *PostIncidentReviewHandler {
+	return &PostIncidentReviewHandler{
+		Reviews: reviews,
+	}
+}
+
+// HandleListReviews returns reviews, newest first, without their documents
+func (h *PostIncidentReviewHandler) HandleListReviews(w http.ResponseWriter, r *http.Request) {
+	if h.Reviews == nil {
+		writeError(w, http.StatusServiceUnavailable, "Post-incident reviews are not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.Reviews.List())
+}
+
+// HandleGetReview returns the review of one incident as JSON, or its
+// document alone with ?format=markdown
+func (h *PostIncidentReviewHandler) HandleGetReview(w http.ResponseWriter, r *http.Request) {
+	if h.Reviews == nil {
+		writeError(w, http.StatusServiceUnavailable, "Post-incident reviews are not configured")
+		return
+	}
+
+	review, ok := h.Reviews.Get(mux.Vars(r)["sysId"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "The incident has no post-incident review")
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "markdown":
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "pir-"+review.Number+".md"))
+		w.Write([]byte(review.Document))
+	case "", "json":
+		writeJSON(w, http.StatusOK, review)
+	default:
+		writeError(w, http.StatusBadRequest, "Unsupported format; use json or markdown")
+	}
+}
+
+// HandleGenerateReview drafts the review of an incident whatever its
+// priority, or returns the one it has
+func (h *PostIncidentReviewHandler) HandleGenerateReview(w http.ResponseWriter, r *http.Request) {
+	if h.Reviews == nil {
+		writeError(w, http.StatusServiceUnavailable, "Post-incident reviews are not configured")
+		return
+	}
+
+	sysID := mux.Vars(r)["sysId"]
+	review, err := h.Reviews.Generate(r.Context(), servicenow.Incident{ID: sysID}, true)
+	if errors.Is(err, servicenow.ErrRecordNotFound) {
+		writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error drafting post-incident review", "sys_id", sysID, "error", err)
+		writeError(w, http.StatusBadGateway, "Error drafting post-incident review: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, review)
+}
@@ -0,0 +1,183 @@
+// backend/internal/api/handlers/proxy.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// jiraProxyFields are the Jira issue fields the frontend may see
+var jiraProxyFields = []string{
+	"summary", "status", "priority", "issuetype", "assignee", "reporter",
+	"resolution", "labels", "duedate", "created", "updated",
+}
+
+// serviceNowProxyFields are the record fields the frontend may see, per table
+var serviceNowProxyFields = map[string][]string{
+	"sn_risk_risk":           {"sys_id", "number", "short_description", "category", "state", "risk_score", "impact", "likelihood", "assigned_to", "due_date", "sys_created_on", "sys_updated_on"},
+	"sn_compliance_task":     {"sys_id", "number", "short_description", "compliance_framework", "regulation", "state", "assigned_to", "due_date", "sys_created_on", "sys_updated_on"},
+	"sn_si_incident":         {"sys_id", "number", "short_description", "category", "state", "priority", "severity", "assigned_to", "assignment_group", "sys_created_on", "sys_updated_on"},
+	"sn_policy_control_test": {"sys_id", "number", "short_description", "control_name", "framework", "state", "test_status", "assigned_to", "due_date", "sys_updated_on"},
+	"sn_audit_finding":       {"sys_id", "number", "short_description", "severity", "state", "assigned_to", "due_date", "jira_ticket", "sys_updated_on"},
+	"sn_vendor_risk":         {"sys_id", "number", "short_description", "vendor_name", "risk_level", "state", "assigned_to", "due_date", "sys_updated_on"},
+	"sn_regulatory_change":   {"sys_id", "number", "short_description", "regulation", "effective_date", "state", "assigned_to", "sys_updated_on"},
+}
+
+// proxyCacheEntry is a cached proxy response
+type proxyCacheEntry struct {
+	body      map[string]interface{}
+	expiresAt time.Time
+}
+
+// ProxyHandler serves whitelisted, cached read-through views of Jira and ServiceNow records
+type ProxyHandler struct {
+	ServiceNowClient *servicenow.Client
+	JiraClient       *jira.Client
+	CacheTTL         time.Duration
+	MaxCacheEntries  int
+
+	mutex sync.Mutex
+	cache map[string]proxyCacheEntry
+}
+
+// NewProxyHandler creates a new proxy handler
+func NewProxyHandler(serviceNowClient *servicenow.Client, jiraClient *jira.Client) *ProxyHandler {
+	return &ProxyHandler{
+		ServiceNowClient: serviceNowClient,
+		JiraClient:       jiraClient,
+		CacheTTL:         30 * time.Second,
+		MaxCacheEntries:  1000,
+		cache:            make(map[string]proxyCacheEntry),
+	}
+}
+
+// HandleJiraIssue returns the whitelisted fields of a Jira issue
+func (h *ProxyHandler) HandleJiraIssue(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	h.serve(w, "jira:"+key, func() (map[string]interface{}, int, error) {
+		issue, err := h.JiraClient.WithContext(r.Context()).GetIssue(key)
+		if err != nil {
+			return nil, http.StatusBadGateway, err
+		}
+
+		fields, _ := issue["fields"].(map[string]interface{})
+		result := map[string]interface{}{
+			"key": issue["key"],
+		}
+		for _, field := range jiraProxyFields {
+			if value, ok := fields[field]; ok {
+				result[field] = flattenJiraValue(value)
+			}
+		}
+		return result, http.StatusOK, nil
+	})
+}
+
+// HandleServiceNowRecord returns the whitelisted fields of a ServiceNow record
+func (h *ProxyHandler) HandleServiceNowRecord(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	table := vars["table"]
+	id := vars["id"]
+
+	allowed, ok := serviceNowProxyFields[table]
+	if !ok {
+		http.Error(w, "Table not available through the proxy", http.StatusForbidden)
+		return
+	}
+
+	h.serve(w, "servicenow:"+table+":"+id, func() (map[string]interface{}, int, error) {
+		record, err := h.ServiceNowClient.WithContext(r.Context()).GetRecord(table, id)
+		if errors.Is(err, servicenow.ErrRecordNotFound) {
+			return nil, http.StatusNotFound, err
+		}
+		if err != nil {
+			return nil, http.StatusBadGateway, err
+		}
+
+		result := make(map[string]interface{}, len(allowed))
+		for _, field := range allowed {
+			if value, ok := record[field]; ok {
+				result[field] = value
+			}
+		}
+		return result, http.StatusOK, nil
+	})
+}
+
+// serve writes a cached response or fetches a fresh one
+func (h *ProxyHandler) serve(w http.ResponseWriter, cacheKey string, fetch func() (map[string]interface{}, int, error)) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if body, ok := h.cached(cacheKey); ok {
+		w.Header().Set("X-Cache", "HIT")
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	body, status, err := fetch()
+	if err != nil {
+		slog.Error("proxy error", "key", cacheKey, "error", err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	h.store(cacheKey, body)
+
+	w.Header().Set("X-Cache", "MISS")
+	json.NewEncoder(w).Encode(body)
+}
+
+// cached returns an unexpired cache entry
+func (h *ProxyHandler) cached(key string) (map[string]interface{}, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	entry, ok := h.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// store caches a response, evicting expired entries when the cache is full
+func (h *ProxyHandler) store(key string, body map[string]interface{}) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := time.Now()
+	if len(h.cache) >= h.MaxCacheEntries {
+		for k, entry := range h.cache {
+			if now.After(entry.expiresAt) {
+				delete(h.cache, k)
+			}
+		}
+	}
+	if len(h.cache) >= h.MaxCacheEntries {
+		return
+	}
+
+	h.cache[key] = proxyCacheEntry{body: body, expiresAt: now.Add(h.CacheTTL)}
+}
+
+// flattenJiraValue reduces Jira objects (status, user, priority...) to their display name
+func flattenJiraValue(value interface{}) interface{} {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	for _, key := range []string{"displayName", "name", "value"} {
+		if v, ok := obj[key]; ok {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// backend/internal/api/handlers/reconcile.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/consistency"
+)
+
+// ReconcileHandler exposes Jira/ServiceNow drift reports on the admin API
+type ReconcileHandler struct {
+	Reconciler *consistency.Reconciler
+}
+
+// NewReconcileHandler creates a new reconcile handler
+func NewReconcileHandler(reconciler *consistency.Reconciler) *ReconcileHandler {
+	return &ReconcileHandler{
+		Reconciler: reconciler,
+	}
+}
+
+// HandleGetReport returns the report of the last reconciliation
+func (h *ReconcileHandler) HandleGetReport(w http.ResponseWriter, r *http.Request) {
+	report := h.Reconciler.Last()
+	if report == nil {
+		http.Error(w, "No reconciliation has run yet", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleRun reconciles every mapped pair now and returns the report
+func (h *ReconcileHandler) HandleRun(w http.ResponseWriter, r *http.Request) {
+	report, err := h.Reconciler.Run(r.Context())
+	if errors.Is(err, consistency.ErrReconcileRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,123 @@
+// backend/internal/api/handlers/relay.go
+package handlers
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/relay"
+)
+
+// relayDedupeWindow is how long delivered relay message IDs are remembered. The agent
+// retries until acknowledged, so a lost response can deliver the same message twice.
+const relayDedupeWindow = 24 * time.Hour
+
+// RelayHandler accepts webhooks forwarded by relay agents on restricted networks
+// and replays them into the regular webhook handlers
+type RelayHandler struct {
+	Secret  string
+	Targets map[string]http.HandlerFunc
+
+	mu         sync.Mutex
+	seen       map[string]time.Time
+	lastPruned time.Time
+}
+
+// NewRelayHandler creates a relay handler; the shared secret comes from RELAY_SHARED_SECRET
+func NewRelayHandler(targets map[string]http.HandlerFunc) *RelayHandler {
+	return &RelayHandler{
+		Secret:  os.Getenv("RELAY_SHARED_SECRET"),
+		Targets: targets,
+		seen:    make(map[string]time.Time),
+	}
+}
+
+// HandleIngest verifies a forwarded message and hands it to the target for its source
+func (h *RelayHandler) HandleIngest(w http.ResponseWriter, r *http.Request) {
+	if h.Secret == "" {
+		http.Error(w, "Relay ingestion is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "Error reading payload", http.StatusBadRequest)
+		return
+	}
+
+	agent := r.Header.Get(relay.HeaderAgent)
+	messageID := r.Header.Get(relay.HeaderMessageID)
+	err = relay.Verify(h.Secret, r.Header.Get(relay.HeaderTimestamp), messageID, r.Header.Get(relay.HeaderSignature), body, time.Now())
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("rejected relay message from agent", "message_id", messageID, "agent", agent, "error", err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	source := r.Header.Get(relay.HeaderSource)
+	target, ok := h.Targets[source]
+	if !ok {
+		http.Error(w, "Unknown relay source", http.StatusBadRequest)
+		return
+	}
+
+	dedupeKey := agent + "/" + messageID
+	if h.delivered(dedupeKey) {
+		logging.FromContext(r.Context()).Warn("ignoring duplicate relay message from agent", "message_id", messageID, "agent", agent)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"duplicate"}`))
+		return
+	}
+
+	logging.FromContext(r.Context()).Info("received webhook via relay agent", "source", source, "agent", agent, "message_id", messageID)
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	target(recorder, r)
+
+	if recorder.status < 300 {
+		h.markDelivered(dedupeKey)
+	}
+}
+
+// delivered reports whether a message was already accepted
+func (h *RelayHandler) delivered(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := h.seen[key]
+	return ok
+}
+
+// markDelivered remembers a message, pruning ones older than the dedupe window hourly
+func (h *RelayHandler) markDelivered(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(h.lastPruned) > time.Hour {
+		for k, at := range h.seen {
+			if now.Sub(at) > relayDedupeWindow {
+				delete(h.seen, k)
+			}
+		}
+		h.lastPruned = now
+	}
+	h.seen[key] = now
+}
+
+// statusRecorder captures the status code written by a wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status before writing it
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,104 @@
+// backend/internal/api/handlers/remediation.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// RemediationHandler manages the remediation milestones of audit findings
+type RemediationHandler struct {
+	Tracker *servicenow.RemediationTracker
+}
+
+// NewRemediationHandler creates a new remediation handler
+func NewRemediationHandler(tracker *servicenow.RemediationTracker) *RemediationHandler {
+	return &RemediationHandler{
+		Tracker: tracker,
+	}
+}
+
+// HandleListPlans returns the remediation plan of every finding that has one
+func (h *RemediationHandler) HandleListPlans(w http.ResponseWriter, r *http.Request) {
+	if h.Tracker == nil {
+		writeError(w, http.StatusServiceUnavailable, "Remediation tracking is not configured")
+		return
+	}
+	plans := h.Tracker.Plans.List()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"plans": plans, "count": len(plans)})
+}
+
+// HandleGetPlan returns a finding's remediation plan with its percent complete
+func (h *RemediationHandler) HandleGetPlan(w http.ResponseWriter, r *http.Request) {
+	if h.Tracker == nil {
+		writeError(w, http.StatusServiceUnavailable, "Remediation tracking is not configured")
+		return
+	}
+	plan, ok := h.Tracker.Plans.Get(mux.Vars(r)["sysId"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "The finding has no remediation milestones")
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// HandleAddMilestone adds a milestone to a finding and creates its Jira sub-task
+func (h *RemediationHandler) HandleAddMilestone(w http.ResponseWriter, r *http.Request) {
+	if h.Tracker == nil {
+		writeError(w, http.StatusServiceUnavailable, "Remediation tracking is not configured")
+		return
+	}
+	var milestone servicenow.Milestone
+	if err := json.NewDecoder(r.Body).Decode(&milestone); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	plan, added, err := h.Tracker.WithContext(r.Context()).AddMilestone(mux.Vars(r)["sysId"], milestone)
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"milestone": added, "plan": plan})
+	case errors.Is(err, servicenow.ErrInvalidMilestone):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, servicenow.ErrRecordNotFound):
+		writeError(w, http.StatusNotFound, "Audit finding not found in ServiceNow")
+	default:
+		writeError(w, http.StatusBadGateway, err.Error())
+	}
+}
+
+// HandleUpdateMilestone completes or reopens a milestone with {"done": true|false}
+func (h *RemediationHandler) HandleUpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	if h.Tracker == nil {
+		writeError(w, http.StatusServiceUnavailable, "Remediation tracking is not configured")
+		return
+	}
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Milestone not found")
+		return
+	}
+	var body struct {
+		Done *bool `json:"done"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Done == nil {
+		writeError(w, http.StatusBadRequest, `Expected {"done": true} or {"done": false}`)
+		return
+	}
+
+	plan, err := h.Tracker.WithContext(r.Context()).SetMilestoneDone(vars["sysId"], id, *body.Done)
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, plan)
+	case errors.Is(err, servicenow.ErrMilestoneNotFound):
+		writeError(w, http.StatusNotFound, "Milestone not found")
+	default:
+		writeError(w, http.StatusBadGateway, err.Error())
+	}
+}
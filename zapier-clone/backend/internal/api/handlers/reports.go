@@ -0,0 +1,248 @@
+// backend/internal/api/handlers/reports.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/reporting"
+)
+
+// ReportHandler serves the weekly compliance summaries and their CSV and PDF,
+// manages the user-defined reports and lists when reports run
+type ReportHandler struct {
+	Weekly      *reporting.WeeklyReporter
+	Definitions *reporting.ReportDefinitions
+	Scheduler   *reporting.ReportScheduler
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(weekly *reporting.WeeklyReporter, definitions *reporting.ReportDefinitions, scheduler *reporting.ReportScheduler) *ReportHandler {
+	return &ReportHandler{
+		Weekly:      weekly,
+		Definitions: definitions,
+		Scheduler:   scheduler,
+	}
+}
+
+// HandleListReports returns the stored reports, newest first
+func (h *ReportHandler) HandleListReports(w http.ResponseWriter, r *http.Request) {
+	if h.Weekly == nil {
+		writeError(w, http.StatusServiceUnavailable, "Reports are not configured")
+		return
+	}
+
+	reports, err := h.Weekly.List()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error listing reports", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error listing reports")
+		return
+	}
+	writeJSON(w, http.StatusOK, reports)
+}
+
+// HandleGetReport returns a stored report as JSON, CSV or PDF
+func (h *ReportHandler) HandleGetReport(w http.ResponseWriter, r *http.Request) {
+	if h.Weekly == nil {
+		writeError(w, http.StatusServiceUnavailable, "Reports are not configured")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	summary, err := h.Weekly.Load(id)
+	if errors.Is(err, reporting.ErrReportNotFound) {
+		writeError(w, http.StatusNotFound, "Report not found")
+		return
+	}
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error loading report", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "Error loading report")
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "csv":
+		data, err := summary.CSV()
+		if err != nil {
+			logging.FromContext(r.Context()).Error("error rendering report CSV", "error", err)
+			writeError(w, http.StatusInternalServerError, "Error rendering report")
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", summary.ID+".csv"))
+		w.Write(data)
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", summary.ID+".pdf"))
+		w.Write(summary.PDF())
+	case "", "json":
+		writeJSON(w, http.StatusOK, summary)
+	default:
+		writeError(w, http.StatusBadRequest, "Unsupported format; use json, csv or pdf")
+	}
+}
+
+// HandleSendWeeklyReport generates this week's summary, stores it and posts it to Slack
+func (h *ReportHandler) HandleSendWeeklyReport(w http.ResponseWriter, r *http.Request) {
+	if h.Weekly == nil {
+		writeError(w, http.StatusServiceUnavailable, "Reports are not configured")
+		return
+	}
+
+	summary, err := h.Weekly.WithContext(r.Context()).Send()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error sending weekly report", "error", err)
+		writeError(w, http.StatusBadGateway, "Error sending weekly report")
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// HandleListDefinitions returns every report definition in creation order
+func (h *ReportHandler) HandleListDefinitions(w http.ResponseWriter, r *http.Request) {
+	if h.Definitions == nil {
+		writeError(w, http.StatusServiceUnavailable, "Report definitions are not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.Definitions.List())
+}
+
+// HandleGetDefinition returns one report definition
+func (h *ReportHandler) HandleGetDefinition(w http.ResponseWriter, r *http.Request) {
+	if h.Definitions == nil {
+		writeError(w, http.StatusServiceUnavailable, "Report definitions are not configured")
+		return
+	}
+
+	definition, ok := h.Definitions.Get(mux.Vars(r)["id"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "Report definition not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, definition)
+}
+
+// HandleCreateDefinition stores a new report definition; the scheduler runs it from then on
+func (h *ReportHandler) HandleCreateDefinition(w http.ResponseWriter, r *http.Request) {
+	if h.Definitions == nil {
+		writeError(w, http.StatusServiceUnavailable, "Report definitions are not configured")
+		return
+	}
+
+	var definition reporting.ReportDefinition
+	if err := json.NewDecoder(r.Body).Decode(&definition); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	created, err := h.Definitions.Create(definition)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// HandleUpdateDefinition replaces a report definition
+func (h *ReportHandler) HandleUpdateDefinition(w http.ResponseWriter, r *http.Request) {
+	if h.Definitions == nil {
+		writeError(w, http.StatusServiceUnavailable, "Report definitions are not configured")
+		return
+	}
+
+	var definition reporting.ReportDefinition
+	if err := json.NewDecoder(r.Body).Decode(&definition); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	updated, err := h.Definitions.Update(mux.Vars(r)["id"], definition)
+	if errors.Is(err, reporting.ErrDefinitionNotFound) {
+		writeError(w, http.StatusNotFound, "Report definition not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// HandleDeleteDefinition removes a report definition
+func (h *ReportHandler) HandleDeleteDefinition(w http.ResponseWriter, r *http.Request) {
+	if h.Definitions == nil {
+		writeError(w, http.StatusServiceUnavailable, "Report definitions are not configured")
+		return
+	}
+
+	err := h.Definitions.Delete(mux.Vars(r)["id"])
+	if errors.Is(err, reporting.ErrDefinitionNotFound) {
+		writeError(w, http.StatusNotFound, "Report definition not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleRunDefinition runs a report definition now and posts it to its
+// channel, or with ?dry_run=true only returns the result
+func (h *ReportHandler) HandleRunDefinition(w http.ResponseWriter, r *http.Request) {
+	if h.Definitions == nil {
+		writeError(w, http.StatusServiceUnavailable, "Report definitions are not configured")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	var (
+		result *reporting.DefinitionResult
+		err    error
+	)
+	if r.URL.Query().Get("dry_run") == "true" {
+		definition, ok := h.Definitions.Get(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "Report definition not found")
+			return
+		}
+		result, err = h.Definitions.Evaluate(r.Context(), definition)
+	} else {
+		result, err = h.Definitions.Run(r.Context(), id)
+	}
+	if errors.Is(err, reporting.ErrDefinitionNotFound) {
+		writeError(w, http.StatusNotFound, "Report definition not found")
+		return
+	}
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error running report", "id", id, "error", err)
+		writeError(w, http.StatusBadGateway, "Error running report")
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// HandleListSchedules returns each report's schedule and its next run times;
+// ?count= sets how many (default 5, at most 50)
+func (h *ReportHandler) HandleListSchedules(w http.ResponseWriter, r *http.Request) {
+	if h.Scheduler == nil {
+		writeError(w, http.StatusServiceUnavailable, "The report scheduler is not configured")
+		return
+	}
+
+	count := 5
+	if value := r.URL.Query().Get("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 50 {
+			writeError(w, http.StatusBadRequest, "count must be between 1 and 50")
+			return
+		}
+		count = parsed
+	}
+	writeJSON(w, http.StatusOK, h.Scheduler.Schedules(count))
+}
@@ -0,0 +1,100 @@
+// backend/internal/api/handlers/risk_matrix.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/riskmatrix"
+)
+
+// RiskMatrixHandler serves and edits the likelihood × impact matrix risks
+// are rated with
+type RiskMatrixHandler struct {
+	Matrix *riskmatrix.Store
+}
+
+// NewRiskMatrixHandler creates a new risk matrix handler
+func NewRiskMatrixHandler(matrix *riskmatrix.Store) *RiskMatrixHandler {
+	return &RiskMatrixHandler{
+		Matrix: matrix,
+	}
+}
+
+// riskMatrixResponse is the matrix in effect and whether it is the default
+type riskMatrixResponse struct {
+	*riskmatrix.Matrix
+	Default bool `json:"default"`
+}
+
+// HandleGetMatrix returns the matrix in effect
+func (h *RiskMatrixHandler) HandleGetMatrix(w http.ResponseWriter, r *http.Request) {
+	if h.Matrix == nil {
+		writeError(w, http.StatusServiceUnavailable, "The risk matrix is not available")
+		return
+	}
+	writeJSON(w, http.StatusOK, riskMatrixResponse{Matrix: h.Matrix.Get(), Default: !h.Matrix.Customized()})
+}
+
+// HandleSetMatrix replaces the matrix. Risks are rated with it from their
+// next update on.
+func (h *RiskMatrixHandler) HandleSetMatrix(w http.ResponseWriter, r *http.Request) {
+	if h.Matrix == nil {
+		writeError(w, http.StatusServiceUnavailable, "The risk matrix is not available")
+		return
+	}
+	var matrix riskmatrix.Matrix
+	if err := json.NewDecoder(r.Body).Decode(&matrix); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := matrix.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.Matrix.Set(&matrix); err != nil {
+		logging.FromContext(r.Context()).Error("error saving risk matrix", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error saving risk matrix")
+		return
+	}
+	writeJSON(w, http.StatusOK, riskMatrixResponse{Matrix: h.Matrix.Get()})
+}
+
+// HandleResetMatrix goes back to the default matrix
+func (h *RiskMatrixHandler) HandleResetMatrix(w http.ResponseWriter, r *http.Request) {
+	if h.Matrix == nil {
+		writeError(w, http.StatusServiceUnavailable, "The risk matrix is not available")
+		return
+	}
+	if err := h.Matrix.Reset(); err != nil {
+		logging.FromContext(r.Context()).Error("error resetting risk matrix", "error", err)
+		writeError(w, http.StatusInternalServerError, "Error resetting risk matrix")
+		return
+	}
+	writeJSON(w, http.StatusOK, riskMatrixResponse{Matrix: h.Matrix.Get(), Default: true})
+}
+
+// HandleAssess rates a likelihood and impact, given as ServiceNow values
+func (h *RiskMatrixHandler) HandleAssess(w http.ResponseWriter, r *http.Request) {
+	if h.Matrix == nil {
+		writeError(w, http.StatusServiceUnavailable, "The risk matrix is not available")
+		return
+	}
+	var request struct {
+		Likelihood string `json:"likelihood"`
+		Impact     string `json:"impact"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	assessment, ok := h.Matrix.Get().Assess(request.Likelihood, request.Impact)
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Likelihood and impact must each match a level of the matrix")
+		return
+	}
+	writeJSON(w, http.StatusOK, assessment)
+}
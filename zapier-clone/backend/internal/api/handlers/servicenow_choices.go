@@ -0,0 +1,62 @@
+// backend/internal/api/handlers/servicenow_choices.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// ServiceNowChoiceHandler exposes the cached ServiceNow choice lists on the admin API
+type ServiceNowChoiceHandler struct {
+	Choices *servicenow.ChoiceCache
+}
+
+// NewServiceNowChoiceHandler creates a new choice list handler
+func NewServiceNowChoiceHandler(choices *servicenow.ChoiceCache) *ServiceNowChoiceHandler {
+	return &ServiceNowChoiceHandler{
+		Choices: choices,
+	}
+}
+
+// HandleGetChoices returns the cached choice lists of a table
+func (h *ServiceNowChoiceHandler) HandleGetChoices(w http.ResponseWriter, r *http.Request) {
+	if h.Choices == nil {
+		http.Error(w, "Choice list sync is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	table := mux.Vars(r)["table"]
+	fields, syncedAt, ok := h.Choices.TableChoices(table)
+	if !ok {
+		http.Error(w, "No choice lists cached for table", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"table":     table,
+		"fields":    fields,
+		"synced_at": syncedAt,
+	})
+}
+
+// HandleSyncChoices refreshes the choice lists of a table from ServiceNow
+func (h *ServiceNowChoiceHandler) HandleSyncChoices(w http.ResponseWriter, r *http.Request) {
+	if h.Choices == nil {
+		http.Error(w, "Choice list sync is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	table := mux.Vars(r)["table"]
+	if err := h.Choices.Sync(table); err != nil {
+		logging.FromContext(r.Context()).Error("error syncing choice list", "table", table, "error", err)
+		http.Error(w, "Error syncing choice list", http.StatusBadGateway)
+		return
+	}
+
+	h.HandleGetChoices(w, r)
+}
@@ -2,13 +2,30 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/consistency"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/jobs"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/loopguard"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mappingstore"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+	"github.com/shivani-1505/zapier-clone/backend/internal/monitoring"
+	"github.com/shivani-1505/zapier-clone/backend/internal/polling"
+	"github.com/shivani-1505/zapier-clone/backend/internal/sla"
+	"github.com/shivani-1505/zapier-clone/backend/internal/syncevents"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workflow"
 )
 
 // ServiceNowWebhookHandler handles incoming webhooks from ServiceNow
@@ -24,6 +41,34 @@ type ServiceNowWebhookHandler struct {
 	VendorRiskHandler       *servicenow.VendorRiskHandler
 	RegulatoryChangeHandler *servicenow.RegulatoryChangeHandler
 	ReportingHandler        *servicenow.ReportingHandler
+	CommentSync             *servicenow.CommentSync
+	// Threads posts updates to records announced in Slack as thread replies
+	Threads        *servicenow.ThreadTracker
+	VolumeDetector *monitoring.VolumeDetector
+	LoopGuard      *loopguard.Guard
+	FailureAlerter *monitoring.FailureAlerter
+	// DeadLetters keeps failed webhooks for replay
+	DeadLetters *monitoring.DeadLetterStore
+	// Settler coalesces a new record's insert and follow-up updates into one insert
+	Settler *servicenow.Settler
+	// Executions records runs of the built-in flows in the workflow execution history
+	Executions *workflow.Store
+	// Workflows runs the stored workflows a record's creation or change starts
+	Workflows *workflow.Dispatcher
+	// Jobs runs webhooks in the background; without it each gets its own goroutine
+	Jobs *jobs.Queue
+	// Locks lets one sync at a time run for a record, so concurrent webhooks
+	// cannot both find it unlinked and create two Jira issues
+	Locks *mappingstore.RecordLocks
+	// Conflicts holds back updates that conflict with a recent Jira edit
+	Conflicts *consistency.ConflictDetector
+	// Events streams the outcome of each webhook to subscribers such as gRPC clients
+	Events *syncevents.Hub
+	// CustomTables syncs the tables registered through /api/v1/tables
+	CustomTables *servicenow.CustomTableHandler
+
+	// ctx carries the correlation ID of the event a withContext copy is handling
+	ctx context.Context
 }
 
 // NewServiceNowWebhookHandler creates a new ServiceNow webhook handler
@@ -31,23 +76,13 @@ func NewServiceNowWebhookHandler(
 	serviceNowClient *servicenow.Client,
 	slackClient *slack.Client,
 	jiraClient *jira.Client,
+	riskHandler *servicenow.RiskHandler,
 ) *ServiceNowWebhookHandler {
-	// Initialize risk-jira mapping
-	riskJiraMapping, err := jira.NewRiskJiraMapping("./data")
-	if err != nil {
-		log.Printf("Warning: Failed to initialize risk-jira mapping: %v", err)
-		// Create an empty mapping as fallback
-		riskJiraMapping = &jira.RiskJiraMapping{
-			RiskIDToJiraKey: make(map[string]string),
-			JiraKeyToRiskID: make(map[string]string),
-		}
-	}
-
 	return &ServiceNowWebhookHandler{
 		ServiceNowClient:        serviceNowClient,
 		SlackClient:             slackClient,
 		JiraClient:              jiraClient,
-		RiskHandler:             servicenow.NewRiskHandler(serviceNowClient, slackClient, jiraClient, riskJiraMapping),
+		RiskHandler:             riskHandler,
 		ComplianceHandler:       servicenow.NewComplianceTaskHandler(serviceNowClient, slackClient),
 		IncidentHandler:         servicenow.NewIncidentHandler(serviceNowClient, slackClient, jiraClient),
 		ControlTestHandler:      servicenow.NewPolicyControlHandler(serviceNowClient, slackClient),
@@ -55,6 +90,8 @@ func NewServiceNowWebhookHandler(
 		VendorRiskHandler:       servicenow.NewVendorRiskHandler(serviceNowClient, slackClient),
 		RegulatoryChangeHandler: servicenow.NewRegulatoryChangeHandler(serviceNowClient, slackClient),
 		ReportingHandler:        servicenow.NewReportingHandler(serviceNowClient, slackClient),
+		Settler:                 servicenow.NewSettlerFromEnv(),
+		Locks:                   mappingstore.NewRecordLocks(),
 	}
 }
 
@@ -67,83 +104,290 @@ func (h *ServiceNowWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Process the webhook based on the table name and action type
-	go h.processWebhook(payload)
+	// Count the event for volume anomaly detection
+	h.VolumeDetector.Record(payload.TableName)
+	metrics.WebhooksReceived.Inc("servicenow")
+	received := time.Now()
+
+	// Processing outlives the request but keeps its correlation ID
+	ctx := logging.Detach(r.Context())
+
+	// Hold new records until their burst of updates settles; everything else
+	// is queued right away
+	release := func(payload servicenow.WebhookPayload) {
+		if err := h.submit(ctx, payload, received); err != nil {
+			h.withContext(ctx).reportFailure(payload, err)
+		}
+	}
+	if !h.Settler.Hold(payload, release) {
+		if err := h.submit(ctx, payload, received); err != nil {
+			rejectJob(w, err)
+			return
+		}
+	}
 
 	// Respond immediately to ServiceNow
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"status":"received"}`))
 }
 
-// processWebhook processes the webhook payload asynchronously
-func (h *ServiceNowWebhookHandler) processWebhook(payload servicenow.WebhookPayload) {
+// serviceNowJob is a queued ServiceNow webhook
+type serviceNowJob struct {
+	Payload  servicenow.WebhookPayload `json:"payload"`
+	Received time.Time                 `json:"received"`
+}
+
+// RegisterJobs processes the handler's webhooks on q. Inserts still held in
+// the settle window are queued when the pool starts draining.
+func (h *ServiceNowWebhookHandler) RegisterJobs(q *jobs.Queue) {
+	h.Jobs = q
+	q.Handle(JobServiceNowWebhook, func(ctx context.Context, data json.RawMessage) error {
+		var job serviceNowJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		h.processWebhook(ctx, job.Payload, job.Received)
+		return nil
+	})
+	q.OnDrain(h.Settler.Flush)
+}
+
+// submit queues a webhook for processWebhook
+func (h *ServiceNowWebhookHandler) submit(ctx context.Context, payload servicenow.WebhookPayload, received time.Time) error {
+	if h.Jobs == nil {
+		go h.processWebhook(ctx, payload, received)
+		return nil
+	}
+	return h.Jobs.Submit(ctx, JobServiceNowWebhook, serviceNowJob{Payload: payload, Received: received})
+}
+
+// processWebhook processes the webhook payload asynchronously. received is when
+// the event arrived, so the processing time includes any settle window.
+func (h *ServiceNowWebhookHandler) processWebhook(ctx context.Context, payload servicenow.WebhookPayload, received time.Time) {
+	h = h.withContext(ctx)
+
+	// Break Jira↔ServiceNow update cycles before doing any work
+	if payload.ActionType == "updated" && !h.LoopGuard.Allow(payload.ID, loopguard.OriginServiceNow, changedFields(payload.Data)) {
+		h.log().Info("skipping update: sync loop guard is active", "table", payload.TableName, "sys_id", payload.ID)
+		h.Events.Publish(serviceNowEvent(payload, syncevents.ResultSkipped, nil))
+		return
+	}
+	if !h.Conflicts.ServiceNowUpdate(ctx, payload) {
+		h.log().Info("skipping update: it conflicts with a recent Jira edit", "table", payload.TableName, "sys_id", payload.ID)
+		h.Events.Publish(serviceNowEvent(payload, syncevents.ResultSkipped, nil))
+		return
+	}
+
+	err := h.syncWebhook(payload)
+	metrics.WebhookProcessing.ObserveSince(received, "servicenow", metrics.Result(err))
+	h.Workflows.Dispatch(ctx, "servicenow", serviceNowTrigger(payload), workflowTrigger(payload))
+	if err != nil {
+		h.reportFailure(payload, err)
+		h.Events.Publish(serviceNowEvent(payload, syncevents.ResultFailure, err))
+		return
+	}
+	h.Events.Publish(serviceNowEvent(payload, syncevents.ResultSuccess, nil))
+}
+
+// serviceNowTrigger names the workflow trigger of a webhook, such as
+// sn_risk_risk.created; deletions start no workflow
+func serviceNowTrigger(payload servicenow.WebhookPayload) string {
+	switch payload.ActionType {
+	case "inserted":
+		return payload.TableName + ".created"
+	case "updated":
+		return payload.TableName + ".updated"
+	}
+	return ""
+}
+
+// workflowTrigger is the record as workflows see it: its fields with its
+// sys_id and table
+func workflowTrigger(payload servicenow.WebhookPayload) map[string]interface{} {
+	data := make(map[string]interface{}, len(payload.Data)+2)
+	for name, value := range payload.Data {
+		data[name] = value
+	}
+	data["sys_id"] = payload.ID
+	data["table"] = payload.TableName
+	return data
+}
+
+// serviceNowEvent describes a processed webhook for event subscribers
+func serviceNowEvent(payload servicenow.WebhookPayload, result string, err error) syncevents.Event {
+	event := syncevents.Event{
+		Source:  syncevents.SourceServiceNow,
+		Action:  payload.ActionType,
+		Table:   payload.TableName,
+		SysID:   payload.ID,
+		Number:  stringField(payload.Data, "number"),
+		JiraKey: stringField(payload.Data, "jira_ticket"),
+		Result:  result,
+	}
+	if severity := stringField(payload.Data, "severity"); severity != "" {
+		event.Severity = sla.Severity(severity)
+	} else if score, parseErr := strconv.ParseFloat(stringField(payload.Data, "risk_score"), 64); parseErr == nil {
+		event.Severity = strings.ToLower(servicenow.RiskSeverity(score))
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// withContext returns a copy of the handler whose clients, flows and logs run under ctx
+func (h *ServiceNowWebhookHandler) withContext(ctx context.Context) *ServiceNowWebhookHandler {
+	scoped := *h
+	scoped.ctx = ctx
+	scoped.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	scoped.JiraClient = h.JiraClient.WithContext(ctx)
+	scoped.SlackClient = h.SlackClient.WithContext(ctx)
+	scoped.RiskHandler = h.RiskHandler.WithContext(ctx)
+	scoped.ComplianceHandler = h.ComplianceHandler.WithContext(ctx)
+	scoped.IncidentHandler = h.IncidentHandler.WithContext(ctx)
+	scoped.ControlTestHandler = h.ControlTestHandler.WithContext(ctx)
+	scoped.AuditHandler = h.AuditHandler.WithContext(ctx)
+	scoped.VendorRiskHandler = h.VendorRiskHandler.WithContext(ctx)
+	scoped.RegulatoryChangeHandler = h.RegulatoryChangeHandler.WithContext(ctx)
+	scoped.ReportingHandler = h.ReportingHandler.WithContext(ctx)
+	if h.CommentSync != nil {
+		scoped.CommentSync = h.CommentSync.WithContext(ctx)
+	}
+	scoped.Threads = h.Threads.WithContext(ctx)
+	if h.CustomTables != nil {
+		scoped.CustomTables = h.CustomTables.WithContext(ctx)
+	}
+	return &scoped
+}
+
+// log returns the logger for the event being handled
+func (h *ServiceNowWebhookHandler) log() *slog.Logger {
+	if h.ctx == nil {
+		return slog.Default()
+	}
+	return logging.FromContext(h.ctx)
+}
+
+// Replay re-runs the sync for a dead-lettered webhook payload. It bypasses the
+// settle window and the loop guard: an operator asked for this exact event.
+func (h *ServiceNowWebhookHandler) Replay(ctx context.Context, data json.RawMessage) error {
+	var payload servicenow.WebhookPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("invalid dead letter payload: %w", err)
+	}
+	return h.withContext(ctx).syncWebhook(payload)
+}
+
+// syncWebhook runs the sync for one webhook and returns the first error, so
+// failed events can be dead-lettered and replayed
+func (h *ServiceNowWebhookHandler) syncWebhook(payload servicenow.WebhookPayload) error {
+	// One sync per record at a time; see Locks
+	defer h.Locks.Lock(payload.TableName + ":" + payload.ID)()
+
+	// Propagate new work notes and comments to the linked Jira issue
+	var commentErr error
+	if payload.ActionType == "updated" && h.CommentSync != nil {
+		if commentErr = h.CommentSync.HandleRecordUpdate(payload); commentErr != nil {
+			h.log().Error("error syncing ServiceNow comments", "sys_id", payload.ID, "error", commentErr)
+		}
+	}
+
+	var err error
 	switch payload.TableName {
 	case "sn_risk_risk":
-		h.processRiskWebhook(payload)
+		err = h.processRiskWebhook(payload)
 	case "sn_compliance_task":
-		h.processComplianceTaskWebhook(payload)
+		err = h.processComplianceTaskWebhook(payload)
 	case "sn_si_incident":
-		h.processIncidentWebhook(payload)
+		err = h.processIncidentWebhook(payload)
 	case "sn_policy_control_test":
-		h.processControlTestWebhook(payload)
+		err = h.processControlTestWebhook(payload)
 	case "sn_audit_finding":
-		h.processAuditFindingWebhook(payload)
+		err = h.processAuditFindingWebhook(payload)
 	case "sn_vendor_risk":
-		h.processVendorRiskWebhook(payload)
+		err = h.processVendorRiskWebhook(payload)
 	case "sn_regulatory_change":
-		h.processRegulatoryChangeWebhook(payload)
+		err = h.processRegulatoryChangeWebhook(payload)
 	default:
-		log.Printf("Unsupported table: %s", payload.TableName)
+		if !h.CustomTables.Handles(payload.TableName) {
+			h.log().Warn("unsupported table", "table", payload.TableName)
+			break
+		}
+		err = h.processCustomTableWebhook(payload)
+	}
+
+	if commentErr != nil {
+		return commentErr
 	}
+	return err
 }
 
 // processRiskWebhook processes risk-related webhooks
-func (h *ServiceNowWebhookHandler) processRiskWebhook(payload servicenow.WebhookPayload) {
+func (h *ServiceNowWebhookHandler) processRiskWebhook(payload servicenow.WebhookPayload) error {
 	// Convert the payload data to a Risk object
 	riskData, err := json.Marshal(payload.Data)
 	if err != nil {
-		log.Printf("Error marshaling risk data: %v", err)
-		return
+		h.log().Error("error marshaling risk data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	var risk servicenow.Risk
 	if err := json.Unmarshal(riskData, &risk); err != nil {
-		log.Printf("Error unmarshaling risk data: %v", err)
-		return
+		h.log().Error("error unmarshaling risk data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	// Process the risk based on the action type
 	switch payload.ActionType {
 	case "inserted":
 		// New risk created
-		_, err := h.RiskHandler.HandleNewRisk(risk)
+		run := h.Executions.StartRun("grc-risk-notify", payload.Data)
+		ts, err := h.RiskHandler.HandleNewRisk(risk)
+		jiraKey, linked := h.RiskHandler.RiskJiraMapping.GetJiraKeyFromRiskID(risk.ID)
+		recordBuiltInRun(run, payload, ts, err, jiraKey, linked, h.RiskHandler.Approvals.Pending(risk.ID))
 		if err != nil {
-			log.Printf("Error handling new risk: %v", err)
+			h.log().Error("error handling new risk", "sys_id", payload.ID, "error", err)
+			return err
 		}
 	case "updated":
-		// Risk updated
-		// In a real implementation, you'd look up the thread info from a database
-		// For simplicity, we're just logging it
-		log.Printf("Risk updated: %s", risk.ID)
+		// Risk updated; rate it again and reply in the thread it was announced in
+		if err := h.RiskHandler.RerateRisk(risk); err != nil {
+			h.log().Error("error rating updated risk", "sys_id", risk.ID, "error", err)
+		}
+		return h.replyInThread(payload, "risk updated")
 	case "deleted":
 		// Risk deleted
-		log.Printf("Risk deleted: %s", risk.ID)
+		h.log().Info("risk deleted", "sys_id", risk.ID)
 	}
+	return nil
+}
+
+// replyInThread posts an update to the Slack thread of the record it changes.
+// Records announced before threads were tracked are only logged, rather than
+// announced again.
+func (h *ServiceNowWebhookHandler) replyInThread(payload servicenow.WebhookPayload, event string) error {
+	threaded, err := h.Threads.HandleRecordUpdate(payload)
+	if err != nil {
+		h.log().Error("error posting update to Slack thread", "table", payload.TableName, "sys_id", payload.ID, "error", err)
+		return err
+	}
+	h.log().Info(event, "sys_id", payload.ID, "threaded", threaded)
+	return nil
 }
 
 // processComplianceTaskWebhook processes compliance task webhooks
-func (h *ServiceNowWebhookHandler) processComplianceTaskWebhook(payload servicenow.WebhookPayload) {
+func (h *ServiceNowWebhookHandler) processComplianceTaskWebhook(payload servicenow.WebhookPayload) error {
 	// Convert the payload data to a ComplianceTask object
 	taskData, err := json.Marshal(payload.Data)
 	if err != nil {
-		log.Printf("Error marshaling compliance task data: %v", err)
-		return
+		h.log().Error("error marshaling compliance task data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	var task servicenow.ComplianceTask
 	if err := json.Unmarshal(taskData, &task); err != nil {
-		log.Printf("Error unmarshaling compliance task data: %v", err)
-		return
+		h.log().Error("error unmarshaling compliance task data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	// Process the compliance task based on the action type
@@ -152,64 +396,80 @@ func (h *ServiceNowWebhookHandler) processComplianceTaskWebhook(payload servicen
 		// New compliance task created
 		_, err := h.ComplianceHandler.HandleNewComplianceTask(task)
 		if err != nil {
-			log.Printf("Error handling new compliance task: %v", err)
+			h.log().Error("error handling new compliance task", "sys_id", payload.ID, "error", err)
+			return err
 		}
 	case "updated":
 		// Compliance task updated
 		// In a real implementation, you'd look up the thread info from a database
-		log.Printf("Compliance task updated: %s", task.ID)
+		h.log().Info("compliance task updated", "sys_id", task.ID)
+		if err := h.ComplianceHandler.GitHub.SyncComplianceTask(task); err != nil {
+			h.log().Error("error updating GitHub issue for compliance task", "sys_id", payload.ID, "error", err)
+			return err
+		}
 	case "deleted":
 		// Compliance task deleted
-		log.Printf("Compliance task deleted: %s", task.ID)
+		h.log().Info("compliance task deleted", "sys_id", task.ID)
 	}
+	return nil
 }
 
 // processIncidentWebhook processes security incident webhooks
-func (h *ServiceNowWebhookHandler) processIncidentWebhook(payload servicenow.WebhookPayload) {
+func (h *ServiceNowWebhookHandler) processIncidentWebhook(payload servicenow.WebhookPayload) error {
 	// Convert the payload data to an Incident object
 	incidentData, err := json.Marshal(payload.Data)
 	if err != nil {
-		log.Printf("Error marshaling incident data: %v", err)
-		return
+		h.log().Error("error marshaling incident data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	var incident servicenow.Incident
 	if err := json.Unmarshal(incidentData, &incident); err != nil {
-		log.Printf("Error unmarshaling incident data: %v", err)
-		return
+		h.log().Error("error unmarshaling incident data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	// Process the incident based on the action type
 	switch payload.ActionType {
 	case "inserted":
 		// New incident created
-		_, err := h.IncidentHandler.HandleNewIncident(incident)
+		run := h.Executions.StartRun("grc-incident-response", payload.Data)
+		ts, err := h.IncidentHandler.HandleNewIncident(incident)
+		jiraKey, linked := h.IncidentHandler.IncidentJiraMapping.GetJiraKeyFromIncidentID(incident.ID)
+		recordBuiltInRun(run, payload, ts, err, jiraKey, linked, h.IncidentHandler.Approvals.Pending(incident.ID))
 		if err != nil {
-			log.Printf("Error handling new incident: %v", err)
+			h.log().Error("error handling new incident", "sys_id", payload.ID, "error", err)
+			return err
 		}
 	case "updated":
-		// Incident updated
-		// In a real implementation, you'd look up the thread info from a database
-		log.Printf("Incident updated: %s", incident.ID)
+		// Incident updated; a major one gets its war room opened, refreshed or
+		// archived and a resolved one its review drafted, before the reply in
+		// the thread it was announced in
+		if err := h.IncidentHandler.SyncWarRoom(incident); err != nil {
+			h.log().Error("error syncing incident war room", "sys_id", payload.ID, "error", err)
+		}
+		h.IncidentHandler.ReviewIfResolved(incident)
+		return h.replyInThread(payload, "incident updated")
 	case "deleted":
 		// Incident deleted
-		log.Printf("Incident deleted: %s", incident.ID)
+		h.log().Info("incident deleted", "sys_id", incident.ID)
 	}
+	return nil
 }
 
 // processControlTestWebhook processes control test webhooks
-func (h *ServiceNowWebhookHandler) processControlTestWebhook(payload servicenow.WebhookPayload) {
+func (h *ServiceNowWebhookHandler) processControlTestWebhook(payload servicenow.WebhookPayload) error {
 	// Convert the payload data to a ControlTest object
 	testData, err := json.Marshal(payload.Data)
 	if err != nil {
-		log.Printf("Error marshaling control test data: %v", err)
-		return
+		h.log().Error("error marshaling control test data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	var test servicenow.ControlTest
 	if err := json.Unmarshal(testData, &test); err != nil {
-		log.Printf("Error unmarshaling control test data: %v", err)
-		return
+		h.log().Error("error unmarshaling control test data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	// Process the control test based on the action type
@@ -218,30 +478,32 @@ func (h *ServiceNowWebhookHandler) processControlTestWebhook(payload servicenow.
 		// New control test created
 		_, err := h.ControlTestHandler.HandleNewControlTest(test)
 		if err != nil {
-			log.Printf("Error handling new control test: %v", err)
+			h.log().Error("error handling new control test", "sys_id", payload.ID, "error", err)
+			return err
 		}
 	case "updated":
 		// Control test updated
-		log.Printf("Control test updated: %s", test.ID)
+		h.log().Info("control test updated", "sys_id", test.ID)
 	case "deleted":
 		// Control test deleted
-		log.Printf("Control test deleted: %s", test.ID)
+		h.log().Info("control test deleted", "sys_id", test.ID)
 	}
+	return nil
 }
 
 // processAuditFindingWebhook processes audit finding webhooks
-func (h *ServiceNowWebhookHandler) processAuditFindingWebhook(payload servicenow.WebhookPayload) {
+func (h *ServiceNowWebhookHandler) processAuditFindingWebhook(payload servicenow.WebhookPayload) error {
 	// Convert the payload data to an AuditFinding object
 	findingData, err := json.Marshal(payload.Data)
 	if err != nil {
-		log.Printf("Error marshaling audit finding data: %v", err)
-		return
+		h.log().Error("error marshaling audit finding data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	var finding servicenow.AuditFinding
 	if err := json.Unmarshal(findingData, &finding); err != nil {
-		log.Printf("Error unmarshaling audit finding data: %v", err)
-		return
+		h.log().Error("error unmarshaling audit finding data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	// Process the audit finding based on the action type
@@ -250,30 +512,36 @@ func (h *ServiceNowWebhookHandler) processAuditFindingWebhook(payload servicenow
 		// New audit finding created
 		_, err := h.AuditHandler.HandleNewAuditFinding(finding)
 		if err != nil {
-			log.Printf("Error handling new audit finding: %v", err)
+			h.log().Error("error handling new audit finding", "sys_id", payload.ID, "error", err)
+			return err
 		}
 	case "updated":
 		// Audit finding updated
-		log.Printf("Audit finding updated: %s", finding.ID)
+		h.log().Info("audit finding updated", "sys_id", finding.ID)
+		if err := h.AuditHandler.GitHub.SyncAuditFinding(finding); err != nil {
+			h.log().Error("error updating GitHub issue for audit finding", "sys_id", payload.ID, "error", err)
+			return err
+		}
 	case "deleted":
 		// Audit finding deleted
-		log.Printf("Audit finding deleted: %s", finding.ID)
+		h.log().Info("audit finding deleted", "sys_id", finding.ID)
 	}
+	return nil
 }
 
 // processVendorRiskWebhook processes vendor risk webhooks
-func (h *ServiceNowWebhookHandler) processVendorRiskWebhook(payload servicenow.WebhookPayload) {
+func (h *ServiceNowWebhookHandler) processVendorRiskWebhook(payload servicenow.WebhookPayload) error {
 	// Convert the payload data to a VendorRisk object
 	riskData, err := json.Marshal(payload.Data)
 	if err != nil {
-		log.Printf("Error marshaling vendor risk data: %v", err)
-		return
+		h.log().Error("error marshaling vendor risk data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	var risk servicenow.VendorRisk
 	if err := json.Unmarshal(riskData, &risk); err != nil {
-		log.Printf("Error unmarshaling vendor risk data: %v", err)
-		return
+		h.log().Error("error unmarshaling vendor risk data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	// Process the vendor risk based on the action type
@@ -282,30 +550,32 @@ func (h *ServiceNowWebhookHandler) processVendorRiskWebhook(payload servicenow.W
 		// New vendor risk created
 		_, err := h.VendorRiskHandler.HandleNewVendorRisk(risk)
 		if err != nil {
-			log.Printf("Error handling new vendor risk: %v", err)
+			h.log().Error("error handling new vendor risk", "sys_id", payload.ID, "error", err)
+			return err
 		}
 	case "updated":
 		// Vendor risk updated
-		log.Printf("Vendor risk updated: %s", risk.ID)
+		h.log().Info("vendor risk updated", "sys_id", risk.ID)
 	case "deleted":
 		// Vendor risk deleted
-		log.Printf("Vendor risk deleted: %s", risk.ID)
+		h.log().Info("vendor risk deleted", "sys_id", risk.ID)
 	}
+	return nil
 }
 
 // processRegulatoryChangeWebhook processes regulatory change webhooks
-func (h *ServiceNowWebhookHandler) processRegulatoryChangeWebhook(payload servicenow.WebhookPayload) {
+func (h *ServiceNowWebhookHandler) processRegulatoryChangeWebhook(payload servicenow.WebhookPayload) error {
 	// Convert the payload data to a RegulatoryChange object
 	changeData, err := json.Marshal(payload.Data)
 	if err != nil {
-		log.Printf("Error marshaling regulatory change data: %v", err)
-		return
+		h.log().Error("error marshaling regulatory change data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	var change servicenow.RegulatoryChange
 	if err := json.Unmarshal(changeData, &change); err != nil {
-		log.Printf("Error unmarshaling regulatory change data: %v", err)
-		return
+		h.log().Error("error unmarshaling regulatory change data", "sys_id", payload.ID, "error", err)
+		return err
 	}
 
 	// Process the regulatory change based on the action type
@@ -314,13 +584,93 @@ func (h *ServiceNowWebhookHandler) processRegulatoryChangeWebhook(payload servic
 		// New regulatory change created
 		_, err := h.RegulatoryChangeHandler.HandleNewRegulatoryChange(change)
 		if err != nil {
-			log.Printf("Error handling new regulatory change: %v", err)
+			h.log().Error("error handling new regulatory change", "sys_id", payload.ID, "error", err)
+			return err
 		}
 	case "updated":
 		// Regulatory change updated
-		log.Printf("Regulatory change updated: %s", change.ID)
+		h.log().Info("regulatory change updated", "sys_id", change.ID)
 	case "deleted":
 		// Regulatory change deleted
-		log.Printf("Regulatory change deleted: %s", change.ID)
+		h.log().Info("regulatory change deleted", "sys_id", change.ID)
+	}
+	return nil
+}
+
+// processCustomTableWebhook processes webhooks of registered tables
+func (h *ServiceNowWebhookHandler) processCustomTableWebhook(payload servicenow.WebhookPayload) error {
+	switch payload.ActionType {
+	case "inserted":
+		// New record: create its Jira issue and announce it
+		if _, err := h.CustomTables.HandleNewRecord(payload); err != nil {
+			h.log().Error("error handling new record", "table", payload.TableName, "sys_id", payload.ID, "error", err)
+			return err
+		}
+	case "updated":
+		return h.replyInThread(payload, payload.TableName+" updated")
+	case "deleted":
+		h.log().Info("record deleted", "table", payload.TableName, "sys_id", payload.ID)
+	}
+	return nil
+}
+
+// reportFailure alerts the ops channel about a webhook that could not be synced
+// and dead-letters it for replay
+func (h *ServiceNowWebhookHandler) reportFailure(payload servicenow.WebhookPayload, err error) {
+	eventType := payload.TableName + " " + payload.ActionType
+	metrics.SyncFailures.Inc("servicenow")
+	h.FailureAlerter.Report("servicenow", eventType, payload.ID, payload, err)
+	if _, dlErr := h.DeadLetters.Add("servicenow", eventType, payload.ID, payload, err); dlErr != nil {
+		h.log().Error("error dead-lettering ServiceNow webhook", "sys_id", payload.ID, "error", dlErr)
+	}
+}
+
+// recordBuiltInRun records a built-in flow's Slack post and Jira issue as
+// execution steps. The built-in handlers log Jira errors rather than return
+// them, so a missing link is recorded as the Jira step's failure. An issue
+// awaiting approval has no Jira step yet; the decision records it.
+func recordBuiltInRun(run *workflow.Run, payload servicenow.WebhookPayload, messageTS string, err error, jiraKey string, linked, awaitingApproval bool) {
+	input := map[string]interface{}{"table": payload.TableName, "sys_id": payload.ID}
+
+	if err != nil {
+		run.Step("slack", "post_message", input, nil, err)
+		run.Finish(err)
+		return
+	}
+	if awaitingApproval {
+		run.Step("slack", "post_message", input, map[string]interface{}{"message_ts": messageTS, "jira_approval": servicenow.ApprovalPending}, nil)
+		run.Finish(nil)
+		return
+	}
+	run.Step("slack", "post_message", input, map[string]interface{}{"message_ts": messageTS}, nil)
+
+	if linked {
+		run.Step("jira", "create_issue", input, map[string]interface{}{"issue_key": jiraKey}, nil)
+	} else {
+		run.Step("jira", "create_issue", input, nil, errors.New("no Jira issue was linked to the record"))
+	}
+	run.Finish(nil)
+}
+
+// changedFields lists the record fields carried by a webhook, ignoring system bookkeeping
+func changedFields(data map[string]interface{}) []string {
+	var fields []string
+	for field := range data {
+		switch field {
+		case "sys_updated_on", "sys_updated_by", "sys_mod_count":
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// PollHandler syncs records polled from a table as if they had arrived by
+// webhook. Failures are alerted and dead-lettered like webhook failures, so the
+// poller moves on rather than re-reading them.
+func (h *ServiceNowWebhookHandler) PollHandler(table string) polling.Handler {
+	return func(ctx context.Context, item polling.Item) error {
+		h.processWebhook(ctx, servicenow.PolledPayload(table, item), time.Now())
+		return nil
 	}
 }
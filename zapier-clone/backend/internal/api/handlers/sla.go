@@ -0,0 +1,34 @@
+// backend/internal/api/handlers/sla.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/sla"
+)
+
+// SLAHandler serves the incidents that breached their SLA
+type SLAHandler struct {
+	Tracker *sla.Tracker
+}
+
+// NewSLAHandler creates a new SLA handler
+func NewSLAHandler(tracker *sla.Tracker) *SLAHandler {
+	return &SLAHandler{Tracker: tracker}
+}
+
+// HandleListBreaches returns breached incidents, most overdue first. With
+// ?status=open, incidents resolved since are left out.
+func (h *SLAHandler) HandleListBreaches(w http.ResponseWriter, r *http.Request) {
+	if h.Tracker == nil {
+		writeError(w, http.StatusServiceUnavailable, "SLA tracking is not configured")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != "open" {
+		writeError(w, http.StatusBadRequest, `status must be "open" or omitted`)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.Tracker.Breaches(status == "open"))
+}
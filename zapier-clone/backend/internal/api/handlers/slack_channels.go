@@ -0,0 +1,68 @@
+// backend/internal/api/handlers/slack_channels.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// SlackChannelHandler reports on the Slack channels the integration posts to
+type SlackChannelHandler struct {
+	SlackClient *slack.Client
+}
+
+// NewSlackChannelHandler creates a new Slack channel handler
+func NewSlackChannelHandler(slackClient *slack.Client) *SlackChannelHandler {
+	return &SlackChannelHandler{
+		SlackClient: slackClient,
+	}
+}
+
+// HandleChannelReport lists every configured channel and whether the bot can reach it
+func (h *SlackChannelHandler) HandleChannelReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.SlackClient.WithContext(r.Context()).ChannelReport()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error building Slack channel report", "error", err)
+		http.Error(w, "Error checking Slack channels", http.StatusBadGateway)
+		return
+	}
+
+	unreachable := 0
+	for _, status := range report {
+		if !status.Reachable {
+			unreachable++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"channels":    report,
+		"unreachable": unreachable,
+	})
+}
+
+// HandleUserGroupReport lists every mapped usergroup handle and whether it resolves to a Slack usergroup
+func (h *SlackChannelHandler) HandleUserGroupReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.SlackClient.WithContext(r.Context()).UserGroupReport()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error building Slack usergroup report", "error", err)
+		http.Error(w, "Error checking Slack usergroups", http.StatusBadGateway)
+		return
+	}
+
+	unresolved := 0
+	for _, status := range report {
+		if !status.Resolved {
+			unresolved++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"usergroups": report,
+		"unresolved": unresolved,
+	})
+}
@@ -3,12 +3,14 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
 )
 
 // SlackCommandHandler handles incoming slash commands from Slack
@@ -24,6 +26,7 @@ type SlackCommandHandler struct {
 	VendorRiskHandler       *servicenow.VendorRiskHandler
 	RegulatoryChangeHandler *servicenow.RegulatoryChangeHandler
 	ReportingHandler        *servicenow.ReportingHandler
+	Router                  *slack.CommandRouter
 }
 
 // NewSlackCommandHandler creates a new Slack command handler
@@ -33,7 +36,7 @@ func NewSlackCommandHandler(
 	jiraClient *jira.Client,
 	riskHandler *servicenow.RiskHandler,
 ) *SlackCommandHandler {
-	return &SlackCommandHandler{
+	h := &SlackCommandHandler{
 		ServiceNowClient:        serviceNowClient,
 		SlackClient:             slackClient,
 		JiraClient:              jiraClient,
@@ -45,6 +48,57 @@ func NewSlackCommandHandler(
 		VendorRiskHandler:       servicenow.NewVendorRiskHandler(serviceNowClient, slackClient),
 		RegulatoryChangeHandler: servicenow.NewRegulatoryChangeHandler(serviceNowClient, slackClient),
 		ReportingHandler:        servicenow.NewReportingHandler(serviceNowClient, slackClient),
+		Router:                  slack.NewCommandRouter(),
+	}
+	h.registerCommands()
+	return h
+}
+
+// commandRegistrar is implemented by every handler that owns slash commands
+type commandRegistrar interface {
+	RegisterCommands(router *slack.CommandRouter) error
+}
+
+// registerCommands builds the command router from the GRC handlers
+func (h *SlackCommandHandler) registerCommands() {
+	registrars := []commandRegistrar{
+		h.ComplianceHandler,
+		h.IncidentHandler,
+		h.ControlTestHandler,
+		h.AuditHandler,
+		h.VendorRiskHandler,
+		h.RegulatoryChangeHandler,
+		h.ReportingHandler,
+	}
+	for _, registrar := range registrars {
+		if err := registrar.RegisterCommands(h.Router); err != nil {
+			slog.Error("error registering Slack commands", "error", err)
+		}
+	}
+
+	commands := []slack.SlashCommand{
+		{
+			Name:        "/assign-owner",
+			Usage:       "RECORD_ID USER",
+			Description: "Assign an owner to a GRC record",
+			Args:        2,
+			Handler: func(command *slack.Command, args []string) (string, error) {
+				// In a real implementation, you'd route to the handler that owns the record
+				return "Owner assignment functionality is under development. Please use the buttons in the message.", nil
+			},
+		},
+		{
+			Name:        "/grc-help",
+			Description: "List the available GRC commands",
+			Handler: func(command *slack.Command, args []string) (string, error) {
+				return h.Router.Help(), nil
+			},
+		},
+	}
+	for _, command := range commands {
+		if err := h.Router.Register(command); err != nil {
+			slog.Error("error registering Slack commands", "error", err)
+		}
 	}
 }
 
@@ -56,6 +110,8 @@ func (h *SlackCommandHandler) HandleCommand(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	metrics.WebhooksReceived.Inc("slack")
+
 	// Create a Command object from the form data
 	command := &slack.Command{
 		Token:       r.FormValue("token"),
@@ -71,58 +127,17 @@ func (h *SlackCommandHandler) HandleCommand(w http.ResponseWriter, r *http.Reque
 		TriggerID:   r.FormValue("trigger_id"),
 	}
 
-	// Process the command
-	response, err := h.processCommand(command)
-	if err != nil {
-		log.Printf("Error processing command: %v", err)
-		http.Error(w, "Error processing command", http.StatusInternalServerError)
-		return
-	}
+	response := h.Router.Dispatch(command)
 
-	// Create the response
-	responseJSON, err := json.Marshal(map[string]string{"text": response})
-	if err != nil {
-		http.Error(w, "Error creating response", http.StatusInternalServerError)
+	// An empty 200 tells Slack there is nothing to show the user
+	if response.Text == "" {
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 
 	// Send the response
 	w.Header().Set("Content-Type", "application/json")
-	w.Write(responseJSON)
-}
-
-// processCommand processes the Slack command
-func (h *SlackCommandHandler) processCommand(command *slack.Command) (string, error) {
-	// Process the command based on the command type
-	switch command.Command {
-	case "/upload-evidence":
-		return h.ComplianceHandler.ProcessComplianceTaskCommand(command)
-
-	case "/incident-update", "/resolve-incident":
-		return h.IncidentHandler.ProcessIncidentCommand(command)
-
-	case "/submit-test":
-		return h.ControlTestHandler.ProcessControlCommand(command)
-
-	case "/resolve-finding":
-		return h.AuditHandler.ProcessAuditCommand(command)
-
-	case "/update-vendor":
-		return h.VendorRiskHandler.ProcessVendorCommand(command)
-
-	case "/assess-impact", "/plan-implementation":
-		return h.RegulatoryChangeHandler.ProcessRegulatoryCommand(command)
-
-	case "/grc-status":
-		return h.ReportingHandler.ProcessReportingCommand(command)
-
-	case "/assign-owner":
-		// This general command would handle assignment for any GRC object
-		// In a real implementation, you'd parse the command and route to the appropriate handler
-		return "Owner assignment functionality is under development. Please use the buttons in the message.", nil
-
-	default:
-		log.Printf("Unknown command: %s", command.Command)
-		return "Unknown command. Available commands: /upload-evidence, /incident-update, /resolve-incident, /submit-test, /resolve-finding, /update-vendor, /assess-impact, /plan-implementation, /grc-status, /assign-owner", nil
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logging.FromContext(r.Context()).Error("error writing command response", "error", err)
 	}
 }
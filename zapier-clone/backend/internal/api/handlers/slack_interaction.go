@@ -2,14 +2,21 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/consistency"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/jobs"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
 )
 
 // SlackInteractionHandler handles incoming interactions from Slack
@@ -25,6 +32,15 @@ type SlackInteractionHandler struct {
 	VendorRiskHandler       *servicenow.VendorRiskHandler
 	RegulatoryChangeHandler *servicenow.RegulatoryChangeHandler
 	ReportingHandler        *servicenow.ReportingHandler
+	// Conflicts resolves conflicting edits from their review message
+	Conflicts *ConflictHandler
+	// Approvals decides Jira issues held for approval from their request message
+	Approvals *ApprovalHandler
+	// Jobs runs interactions in the background; without it each gets its own goroutine
+	Jobs *jobs.Queue
+
+	// ctx carries the correlation ID of the interaction a withContext copy is handling
+	ctx context.Context
 }
 
 // NewSlackInteractionHandler creates a new Slack interaction handler
@@ -32,28 +48,22 @@ func NewSlackInteractionHandler(
 	serviceNowClient *servicenow.Client,
 	slackClient *slack.Client,
 	jiraClient *jira.Client,
+	riskHandler *servicenow.RiskHandler,
 	incidentHandler *servicenow.IncidentHandler,
 ) *SlackInteractionHandler {
-	// Initialize risk-jira mapping
-	riskJiraMapping, err := jira.NewRiskJiraMapping("./data")
-	if err != nil {
-		log.Printf("Warning: Failed to initialize risk-jira mapping: %v", err)
-		// Create an empty mapping as fallback
-		riskJiraMapping = &jira.RiskJiraMapping{
-			RiskIDToJiraKey: make(map[string]string),
-			JiraKeyToRiskID: make(map[string]string),
-		}
-	}
+	// Questionnaire answers tick the checklist of the vendor risk's Jira issue
+	vendorRiskHandler := servicenow.NewVendorRiskHandler(serviceNowClient, slackClient)
+	vendorRiskHandler.JiraClient = jiraClient
 
 	return &SlackInteractionHandler{
 		ServiceNowClient:        serviceNowClient,
 		SlackClient:             slackClient,
-		RiskHandler:             servicenow.NewRiskHandler(serviceNowClient, slackClient, jiraClient, riskJiraMapping),
+		RiskHandler:             riskHandler,
 		ComplianceHandler:       servicenow.NewComplianceTaskHandler(serviceNowClient, slackClient),
 		IncidentHandler:         incidentHandler,
 		ControlTestHandler:      servicenow.NewPolicyControlHandler(serviceNowClient, slackClient),
 		AuditHandler:            servicenow.NewAuditHandler(serviceNowClient, slackClient, jiraClient),
-		VendorRiskHandler:       servicenow.NewVendorRiskHandler(serviceNowClient, slackClient),
+		VendorRiskHandler:       vendorRiskHandler,
 		RegulatoryChangeHandler: servicenow.NewRegulatoryChangeHandler(serviceNowClient, slackClient),
 		ReportingHandler:        servicenow.NewReportingHandler(serviceNowClient, slackClient),
 	}
@@ -80,305 +90,407 @@ func (h *SlackInteractionHandler) HandleInteraction(w http.ResponseWriter, r *ht
 		http.Error(w, "Invalid payload format", http.StatusBadRequest)
 		return
 	}
+	payload.Normalize()
+	metrics.WebhooksReceived.Inc("slack")
 
-	// Process the interaction asynchronously
-	go h.processInteraction(payload)
+	// Process the interaction asynchronously, keeping the request's correlation ID
+	if err := h.submit(logging.Detach(r.Context()), payload); err != nil {
+		rejectJob(w, err)
+		return
+	}
 
-	// Respond immediately to Slack
+	// An empty 200 closes a submitted modal; Slack ignores the body for block actions
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"text":"Processing your request..."}`))
+	if payload.Type != "view_submission" {
+		w.Write([]byte(`{"text":"Processing your request..."}`))
+	}
+}
+
+// RegisterJobs processes the handler's interactions, from Slack and Teams, on q
+func (h *SlackInteractionHandler) RegisterJobs(q *jobs.Queue) {
+	h.Jobs = q
+	q.Handle(JobInteraction, func(ctx context.Context, data json.RawMessage) error {
+		var payload slack.InteractionPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return err
+		}
+		h.processInteraction(ctx, payload)
+		return nil
+	})
+}
+
+// submit queues an interaction for processInteraction
+func (h *SlackInteractionHandler) submit(ctx context.Context, payload slack.InteractionPayload) error {
+	if h.Jobs == nil {
+		go h.processInteraction(ctx, payload)
+		return nil
+	}
+	return h.Jobs.Submit(ctx, JobInteraction, payload)
 }
 
 // processInteraction processes the Slack interaction payload asynchronously
-func (h *SlackInteractionHandler) processInteraction(payload slack.InteractionPayload) {
-	// Skip if no actions
-	if len(payload.Actions) == 0 {
+func (h *SlackInteractionHandler) processInteraction(ctx context.Context, payload slack.InteractionPayload) {
+	h = h.withContext(ctx)
+
+	if payload.Type == "view_submission" {
+		h.processViewSubmission(payload)
+		return
+	}
+
+	for _, action := range payload.Actions {
+		h.processAction(payload, action)
+	}
+}
+
+// withContext returns a copy of the handler whose clients, flows and logs run under ctx
+func (h *SlackInteractionHandler) withContext(ctx context.Context) *SlackInteractionHandler {
+	scoped := *h
+	scoped.ctx = ctx
+	scoped.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	scoped.SlackClient = h.SlackClient.WithContext(ctx)
+	scoped.RiskHandler = h.RiskHandler.WithContext(ctx)
+	scoped.ComplianceHandler = h.ComplianceHandler.WithContext(ctx)
+	scoped.IncidentHandler = h.IncidentHandler.WithContext(ctx)
+	scoped.ControlTestHandler = h.ControlTestHandler.WithContext(ctx)
+	scoped.AuditHandler = h.AuditHandler.WithContext(ctx)
+	scoped.VendorRiskHandler = h.VendorRiskHandler.WithContext(ctx)
+	scoped.RegulatoryChangeHandler = h.RegulatoryChangeHandler.WithContext(ctx)
+	scoped.ReportingHandler = h.ReportingHandler.WithContext(ctx)
+	return &scoped
+}
+
+// log returns the logger for the interaction being handled
+func (h *SlackInteractionHandler) log() *slog.Logger {
+	if h.ctx == nil {
+		return slog.Default()
+	}
+	return logging.FromContext(h.ctx)
+}
+
+// processAction dispatches one button press to the ServiceNow handler that owns it
+func (h *SlackInteractionHandler) processAction(payload slack.InteractionPayload, action slack.InteractionAction) {
+	actionID := action.ActionID
+
+	// Link buttons (view_risk, view_incident, ...) only open a URL
+	if strings.HasPrefix(actionID, "view_") {
 		return
 	}
 
-	// Get the first action
-	action := payload.Actions[0]
-	actionID := action["action_id"]
-	actionValue := action["value"]
+	// Button values are "<verb>_<kind>_<sys_id>"
+	parts := strings.SplitN(action.Value, "_", 3)
+	if len(parts) < 3 {
+		h.log().Warn("invalid action value", "action", actionID, "value", action.Value)
+		return
+	}
+	recordID := parts[2]
 
-	// Process based on the action ID
+	var err error
 	switch actionID {
 	// Risk Management interactions
-	case "discuss_risk", "assign_risk":
-		// Extract the risk ID from the value
-		parts := strings.Split(actionValue, "_")
-		if len(parts) < 3 {
-			log.Printf("Invalid risk action value: %s", actionValue)
-			return
-		}
-		riskID := parts[2]
-
-		if actionID == "discuss_risk" {
-			log.Printf("Risk discussion initiated for: %s", riskID)
-		} else if actionID == "assign_risk" {
-			err := h.RiskHandler.HandleRiskAssignment(riskID, payload.ChannelID, payload.MessageTS, payload.UserID)
-			if err != nil {
-				log.Printf("Error assigning risk: %v", err)
-			}
-		}
+	case "discuss_risk":
+		h.log().Info("risk discussion initiated", "sys_id", recordID)
+	case "assign_risk":
+		err = h.RiskHandler.HandleRiskAssignment(recordID, payload.ChannelID, payload.MessageTS, payload.UserID)
 
 	// Compliance Task interactions
-	case "upload_evidence", "assign_task":
-		// Extract the task ID from the value
-		parts := strings.Split(actionValue, "_")
-		if len(parts) < 3 {
-			log.Printf("Invalid task action value: %s", actionValue)
-			return
-		}
-		taskID := parts[2]
-
-		if actionID == "upload_evidence" {
-			log.Printf("Evidence upload initiated for task: %s", taskID)
-		} else if actionID == "assign_task" {
-			err := h.ComplianceHandler.HandleComplianceTaskAssignment(taskID, payload.ChannelID, payload.MessageTS, payload.UserID)
-			if err != nil {
-				log.Printf("Error assigning compliance task: %v", err)
+	case "upload_evidence":
+		_, err = h.SlackClient.PostReply(payload.ChannelID, payload.MessageTS, slack.Message{
+			Text: fmt.Sprintf("<@%s> upload the evidence file to Slack, then attach it with `/upload-evidence %s FILE_URL` using the file's link.", payload.UserID, recordID),
+		})
+	case "assign_task":
+		err = h.ComplianceHandler.HandleComplianceTaskAssignment(recordID, payload.ChannelID, payload.MessageTS, payload.UserID)
+
+	// Incident Response interactions
+	case "acknowledge_incident":
+		err = h.IncidentHandler.HandleIncidentAcknowledgment(recordID, payload.ChannelID, payload.MessageTS, payload.UserID)
+	case "update_incident":
+		err = h.openTextModal(payload, "incident_update_modal", recordID, "Update Incident", "Submit",
+			"update_text", "Update details", "Enter your update about this incident...")
+	case "resolve_incident":
+		err = h.openTextModal(payload, "incident_resolve_modal", recordID, "Resolve Incident", "Resolve",
+			"resolution_notes", "Resolution details", "Describe how the incident was resolved...")
+
+	// Control Testing interactions
+	case "submit_test_results":
+		// In a real implementation, you'd open a modal for test result input
+		h.log().Info("test result submission initiated", "sys_id", recordID)
+
+	// Audit Management interactions
+	case "assign_finding":
+		err = h.AuditHandler.HandleAuditFindingAssignment(recordID, payload.ChannelID, payload.MessageTS, payload.UserID)
+	case "resolve_finding":
+		err = h.openTextModal(payload, "finding_resolve_modal", recordID, "Resolve Finding", "Resolve",
+			"resolution_notes", "Resolution details", "Describe how the finding was remediated...")
+
+	// Vendor Risk Management interactions
+	case "request_compliance_report":
+		err = h.openQuestionnaireModal(payload, recordID)
+	case "update_vendor_status":
+		// In a real implementation, you'd open a modal for status update input
+		h.log().Info("vendor status update initiated", "sys_id", recordID)
+
+	// Regulatory Change Management interactions
+	case "add_impact_assessment":
+		// In a real implementation, you'd open a modal for impact assessment input
+		h.log().Info("impact assessment initiated", "sys_id", recordID)
+	case "create_implementation_plan":
+		// In a real implementation, you'd open a modal for implementation plan input
+		h.log().Info("implementation plan creation initiated", "sys_id", recordID)
+
+	// Sync conflict reviews; the record ID is the conflict ID
+	case "keep_servicenow", "keep_jira":
+		err = h.resolveConflict(payload, parts[1], recordID)
+
+	// Jira approval requests; the record ID is the risk or incident sys_id
+	case "approve_jira", "reject_jira":
+		err = h.decideApproval(payload, parts[0] == "approve", recordID)
+	case "link_jira":
+		err = h.linkDuplicate(payload, recordID)
+
+	default:
+		h.log().Warn("unhandled Slack action", "action", actionID)
+		return
+	}
+
+	if err != nil {
+		h.log().Error("error handling Slack action", "action", actionID, "sys_id", recordID, "error", err)
+	}
+}
+
+// resolveConflict settles a conflict from its review message and replies in its thread
+func (h *SlackInteractionHandler) resolveConflict(payload slack.InteractionPayload, keep, conflictID string) error {
+	if h.Conflicts == nil || h.Conflicts.Conflicts == nil {
+		return fmt.Errorf("conflict detection is not configured")
+	}
+
+	by := payload.UserName
+	if by == "" {
+		by = payload.UserID
+	}
+	ctx := h.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	conflict, err := h.Conflicts.Resolve(ctx, conflictID, keep, "slack:"+by)
+	text := fmt.Sprintf("✅ <@%s> kept the %s edit", payload.UserID, keep)
+	switch {
+	case errors.Is(err, consistency.ErrConflictResolved):
+		text = fmt.Sprintf("This conflict was already resolved for %s by %s", conflict.Winner, conflict.ResolvedBy)
+	case err != nil && conflict != nil:
+		text = fmt.Sprintf("⚠️ <@%s> kept the %s edit, but syncing it failed: %v", payload.UserID, keep, err)
+	case err != nil:
+		return err
+	}
+
+	_, replyErr := h.SlackClient.PostReply(payload.ChannelID, payload.MessageTS, slack.Message{Text: text})
+	return replyErr
+}
+
+// decideApproval approves or rejects a held Jira issue. The request message
+// shows the outcome; only a repeat decision is answered in the thread.
+func (h *SlackInteractionHandler) decideApproval(payload slack.InteractionPayload, approve bool, sysID string) error {
+	if h.Approvals == nil || h.Approvals.Approvals == nil {
+		return fmt.Errorf("Jira approvals are not configured")
+	}
+
+	by := payload.UserName
+	if by == "" {
+		by = payload.UserID
+	}
+	ctx := h.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	approval, err := h.Approvals.Decide(ctx, sysID, approve, "slack:"+by)
+	if errors.Is(err, servicenow.ErrApprovalDecided) {
+		text := fmt.Sprintf("This Jira issue was already %s by %s", approval.Status, approval.DecidedBy)
+		_, replyErr := h.SlackClient.PostReply(payload.ChannelID, approval.ThreadTS, slack.Message{Text: text})
+		return replyErr
+	}
+	return err
+}
+
+// linkDuplicate links a record held as a possible duplicate to the existing
+// issue. Like a decision, only a repeat is answered in the thread.
+func (h *SlackInteractionHandler) linkDuplicate(payload slack.InteractionPayload, sysID string) error {
+	if h.Approvals == nil || h.Approvals.Approvals == nil {
+		return fmt.Errorf("Jira approvals are not configured")
+	}
+
+	by := payload.UserName
+	if by == "" {
+		by = payload.UserID
+	}
+	ctx := h.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	approval, err := h.Approvals.Link(ctx, sysID, "slack:"+by)
+	if errors.Is(err, servicenow.ErrApprovalDecided) {
+		text := fmt.Sprintf("This Jira issue was already %s by %s", approval.Status, approval.DecidedBy)
+		_, replyErr := h.SlackClient.PostReply(payload.ChannelID, approval.ThreadTS, slack.Message{Text: text})
+		return replyErr
+	}
+	return err
+}
+
+// processViewSubmission handles a submitted modal opened by processAction
+func (h *SlackInteractionHandler) processViewSubmission(payload slack.InteractionPayload) {
+	// Metadata carries the record and the thread the button was pressed in
+	metaParts := strings.SplitN(payload.View.PrivateMetadata, ":", 3)
+	if len(metaParts) < 3 {
+		h.log().Warn("invalid modal metadata", "callback_id", payload.View.CallbackID, "metadata", payload.View.PrivateMetadata)
+		return
+	}
+	recordID, channelID, threadTS := metaParts[0], metaParts[1], metaParts[2]
+	values := payload.View.State.Values
+
+	var err error
+	switch payload.View.CallbackID {
+	case "incident_update_modal":
+		updateText := values["update_text"]["update_text_input"].Value
+		err = h.IncidentHandler.HandleIncidentUpdate(recordID, channelID, threadTS, payload.UserID, updateText)
+	case "incident_resolve_modal":
+		resolutionNotes := values["resolution_notes"]["resolution_notes_input"].Value
+		err = h.IncidentHandler.HandleIncidentResolution(recordID, channelID, threadTS, payload.UserID, resolutionNotes)
+	case "finding_resolve_modal":
+		resolution := values["resolution_notes"]["resolution_notes_input"].Value
+		err = h.AuditHandler.HandleAuditFindingResolution(recordID, channelID, threadTS, payload.UserID, resolution)
+	case "vendor_questionnaire_modal":
+		answers := make([]servicenow.QuestionnaireAnswer, 0, len(h.VendorRiskHandler.Questionnaire))
+		for _, question := range h.VendorRiskHandler.Questionnaire {
+			value := values[question.ID][question.ID+"_input"]
+			answer := value.Value
+			if value.SelectedOption != nil {
+				answer = value.SelectedOption.Value
 			}
+			answers = append(answers, servicenow.QuestionnaireAnswer{QuestionID: question.ID, Question: question.Text, Answer: answer})
 		}
+		err = h.VendorRiskHandler.HandleQuestionnaireResponse(recordID, channelID, threadTS, payload.UserID, answers)
+	default:
+		h.log().Warn("unhandled modal callback", "callback_id", payload.View.CallbackID)
+		return
+	}
 
-	// Replace the existing incident handling code (around lines 143-156) with this enhanced version:
+	if err != nil {
+		h.log().Error("error handling Slack modal", "callback_id", payload.View.CallbackID, "sys_id", recordID, "error", err)
+	}
+}
 
-	// Incident Response interactions
-	case "acknowledge_incident", "update_incident", "resolve_incident":
-		// Extract the incident ID from the value
-		parts := strings.Split(actionValue, "_")
-		if len(parts) < 3 {
-			log.Printf("Invalid incident action value: %s", actionValue)
-			return
-		}
-		incidentID := parts[2]
+// openTextModal opens a modal with one multiline input. The input's block ID is
+// blockID and its action ID is blockID + "_input".
+func (h *SlackInteractionHandler) openTextModal(payload slack.InteractionPayload, callbackID, recordID, title, submit, blockID, label, placeholder string) error {
+	// Presses from Teams cards carry no trigger ID and cannot open a modal
+	if payload.TriggerID == "" {
+		_, err := h.SlackClient.PostReply(payload.ChannelID, payload.MessageTS, slack.Message{
+			Text: fmt.Sprintf("%s is not available here; please make the change in ServiceNow.", title),
+		})
+		return err
+	}
 
-		if actionID == "acknowledge_incident" {
-			err := h.IncidentHandler.HandleIncidentAcknowledgment(incidentID, payload.ChannelID, payload.MessageTS, payload.UserID)
-			if err != nil {
-				log.Printf("Error acknowledging incident: %v", err)
-			}
-		} else if actionID == "update_incident" {
-			// Open a modal for detailed update
-			modalRequest := slack.ModalRequest{
-				TriggerID: payload.TriggerID,
-				View: slack.Modal{
-					Type: "modal",
-					Title: slack.TextObject{
-						Type: "plain_text",
-						Text: "Update Incident",
-					},
-					CallbackID:      "incident_update_modal",
-					PrivateMetadata: incidentID + ":" + payload.ChannelID + ":" + payload.MessageTS,
-					Submit: slack.TextObject{
-						Type: "plain_text",
-						Text: "Submit",
-					},
-					Close: slack.TextObject{
-						Type: "plain_text",
-						Text: "Cancel",
-					},
-					Blocks: []slack.Block{
-						{
-							Type:    "input",
-							BlockID: "update_text",
-							Element: map[string]interface{}{
-								"type":      "plain_text_input",
-								"action_id": "update_text_input",
-								"multiline": true,
-								"placeholder": map[string]interface{}{
-									"type": "plain_text",
-									"text": "Enter your update about this incident...",
-								},
-							},
-							Label: slack.TextObject{
-								Type: "plain_text",
-								Text: "Update details",
-							},
-							Optional: false,
+	modalRequest := slack.ModalRequest{
+		TriggerID: payload.TriggerID,
+		View: slack.Modal{
+			Type: "modal",
+			Title: slack.TextObject{
+				Type: "plain_text",
+				Text: title,
+			},
+			CallbackID:      callbackID,
+			PrivateMetadata: recordID + ":" + payload.ChannelID + ":" + payload.MessageTS,
+			Submit: slack.TextObject{
+				Type: "plain_text",
+				Text: submit,
+			},
+			Close: slack.TextObject{
+				Type: "plain_text",
+				Text: "Cancel",
+			},
+			Blocks: []slack.Block{
+				{
+					Type:    "input",
+					BlockID: blockID,
+					Element: map[string]interface{}{
+						"type":      "plain_text_input",
+						"action_id": blockID + "_input",
+						"multiline": true,
+						"placeholder": map[string]interface{}{
+							"type": "plain_text",
+							"text": placeholder,
 						},
 					},
-				},
-			}
-
-			err := h.SlackClient.OpenModal(modalRequest)
-			if err != nil {
-				log.Printf("Error opening incident update modal: %v", err)
-			}
-		} else if actionID == "resolve_incident" {
-			// Open a modal for resolution details
-			modalRequest := slack.ModalRequest{
-				TriggerID: payload.TriggerID,
-				View: slack.Modal{
-					Type: "modal",
-					Title: slack.TextObject{
-						Type: "plain_text",
-						Text: "Resolve Incident",
-					},
-					CallbackID:      "incident_resolve_modal",
-					PrivateMetadata: incidentID + ":" + payload.ChannelID + ":" + payload.MessageTS,
-					Submit: slack.TextObject{
-						Type: "plain_text",
-						Text: "Resolve",
-					},
-					Close: slack.TextObject{
+					Label: slack.TextObject{
 						Type: "plain_text",
-						Text: "Cancel",
-					},
-					Blocks: []slack.Block{
-						{
-							Type:    "input",
-							BlockID: "resolution_notes",
-							Element: map[string]interface{}{
-								"type":      "plain_text_input",
-								"action_id": "resolution_notes_input",
-								"multiline": true,
-								"placeholder": map[string]string{
-									"type": "plain_text",
-									"text": "Describe how the incident was resolved...",
-								},
-							},
-							Label: slack.TextObject{
-								Type: "plain_text",
-								Text: "Resolution details",
-							},
-							Optional: false,
-						},
+						Text: label,
 					},
+					Optional: false,
 				},
-			}
-
-			err := h.SlackClient.OpenModal(modalRequest)
-			if err != nil {
-				log.Printf("Error opening incident resolution modal: %v", err)
-			}
-		}
+			},
+		},
+	}
 
-	// Control Testing interactions
-	case "submit_test_results":
-		// Extract the test ID from the value
-		parts := strings.Split(actionValue, "_")
-		if len(parts) < 3 {
-			log.Printf("Invalid test results value: %s", actionValue)
-			return
-		}
-		testID := parts[2]
+	if err := h.SlackClient.OpenModal(modalRequest); err != nil {
+		return fmt.Errorf("error opening %s: %w", callbackID, err)
+	}
+	return nil
+}
 
-		// In a real implementation, you'd open a modal for test result input
-		log.Printf("Test result submission initiated for: %s", testID)
+// openQuestionnaireModal opens the vendor risk questionnaire: a select for
+// each question with options and a text input for the others. Presses from
+// Teams cards cannot open a modal and request the report in the thread instead.
+func (h *SlackInteractionHandler) openQuestionnaireModal(payload slack.InteractionPayload, riskID string) error {
+	if payload.TriggerID == "" {
+		return h.VendorRiskHandler.HandleComplianceReportRequest(riskID, payload.ChannelID, payload.MessageTS, payload.UserID)
+	}
 
-	// Audit Management interactions
-	case "assign_finding", "resolve_finding":
-		// Extract the finding ID from the value
-		parts := strings.Split(actionValue, "_")
-		if len(parts) < 3 {
-			log.Printf("Invalid finding action value: %s", actionValue)
-			return
+	blocks := make([]slack.Block, 0, len(h.VendorRiskHandler.Questionnaire))
+	for _, question := range h.VendorRiskHandler.Questionnaire {
+		element := map[string]interface{}{
+			"type":      "plain_text_input",
+			"action_id": question.ID + "_input",
+			"multiline": true,
 		}
-		findingID := parts[2]
-
-		if actionID == "assign_finding" {
-			err := h.AuditHandler.HandleAuditFindingAssignment(findingID, payload.ChannelID, payload.MessageTS, payload.UserID)
-			if err != nil {
-				log.Printf("Error assigning audit finding: %v", err)
+		if len(question.Options) > 0 {
+			options := make([]map[string]interface{}, 0, len(question.Options))
+			for _, option := range question.Options {
+				options = append(options, map[string]interface{}{
+					"text":  map[string]interface{}{"type": "plain_text", "text": option},
+					"value": option,
+				})
 			}
-		} else if actionID == "resolve_finding" {
-			// In a real implementation, you'd open a modal for resolution notes
-			log.Printf("Audit finding resolution initiated for: %s", findingID)
-		}
-
-	// Vendor Risk Management interactions
-	case "request_compliance_report", "update_vendor_status":
-		// Extract the vendor risk ID from the value
-		parts := strings.Split(actionValue, "_")
-		if len(parts) < 3 {
-			log.Printf("Invalid vendor action value: %s", actionValue)
-			return
-		}
-		riskID := parts[2]
-
-		if actionID == "request_compliance_report" {
-			err := h.VendorRiskHandler.HandleComplianceReportRequest(riskID, payload.ChannelID, payload.MessageTS, payload.UserID)
-			if err != nil {
-				log.Printf("Error requesting compliance report: %v", err)
+			element = map[string]interface{}{
+				"type":      "static_select",
+				"action_id": question.ID + "_input",
+				"options":   options,
 			}
-		} else if actionID == "update_vendor_status" {
-			// In a real implementation, you'd open a modal for status update input
-			log.Printf("Vendor status update initiated for: %s", riskID)
-		}
-
-	// Regulatory Change Management interactions
-	case "add_impact_assessment", "create_implementation_plan":
-		// Extract the change ID from the value
-		parts := strings.Split(actionValue, "_")
-		if len(parts) < 3 {
-			log.Printf("Invalid regulatory change action value: %s", actionValue)
-			return
-		}
-		changeID := parts[2]
-
-		if actionID == "add_impact_assessment" {
-			// In a real implementation, you'd open a modal for impact assessment input
-			log.Printf("Impact assessment initiated for: %s", changeID)
-		} else if actionID == "create_implementation_plan" {
-			// In a real implementation, you'd open a modal for implementation plan input
-			log.Printf("Implementation plan creation initiated for: %s", changeID)
 		}
+		blocks = append(blocks, slack.Block{
+			Type:     "input",
+			BlockID:  question.ID,
+			Element:  element,
+			Label:    slack.TextObject{Type: "plain_text", Text: question.Text},
+			Optional: question.Optional,
+		})
+	}
 
-		// Handle modal submissions if this is a view submission
-		if payload.Type == "view_submission" {
-			switch payload.View.CallbackID {
-			case "incident_update_modal":
-				// Handle incident update modal submission
-				values := payload.View.State.Values
-				updateText := values["update_text"]["update_text_input"].Value
-
-				// Parse metadata to get incident ID and thread info
-				metaParts := strings.Split(payload.View.PrivateMetadata, ":")
-				if len(metaParts) >= 3 {
-					incidentID := metaParts[0]
-					channelID := metaParts[1]
-					threadTS := metaParts[2]
-
-					err := h.IncidentHandler.HandleIncidentUpdate(
-						incidentID,
-						channelID,
-						threadTS,
-						payload.User.ID,
-						updateText,
-					)
-					if err != nil {
-						log.Printf("Error handling incident update: %v", err)
-					}
-				}
-
-			case "incident_resolve_modal":
-				// Handle incident resolution modal submission
-				values := payload.View.State.Values
-				resolutionNotes := values["resolution_notes"]["resolution_notes_input"].Value
-
-				// Parse metadata to get incident ID and thread info
-				metaParts := strings.Split(payload.View.PrivateMetadata, ":")
-				if len(metaParts) >= 3 {
-					incidentID := metaParts[0]
-					channelID := metaParts[1]
-					threadTS := metaParts[2]
-
-					err := h.IncidentHandler.HandleIncidentResolution(
-						incidentID,
-						channelID,
-						threadTS,
-						payload.User.ID,
-						resolutionNotes,
-					)
-					if err != nil {
-						log.Printf("Error handling incident resolution: %v", err)
-					}
-				}
-			}
-		}
+	modalRequest := slack.ModalRequest{
+		TriggerID: payload.TriggerID,
+		View: slack.Modal{
+			Type:            "modal",
+			Title:           slack.TextObject{Type: "plain_text", Text: "Vendor Questionnaire"},
+			CallbackID:      "vendor_questionnaire_modal",
+			PrivateMetadata: riskID + ":" + payload.ChannelID + ":" + payload.MessageTS,
+			Submit:          slack.TextObject{Type: "plain_text", Text: "Submit"},
+			Close:           slack.TextObject{Type: "plain_text", Text: "Cancel"},
+			Blocks:          blocks,
+		},
+	}
 
-	default:
-		log.Printf("Unhandled action ID: %s", actionID)
+	if err := h.SlackClient.OpenModal(modalRequest); err != nil {
+		return fmt.Errorf("error opening vendor_questionnaire_modal: %w", err)
 	}
+	return nil
 }
@@ -0,0 +1,156 @@
+// backend/internal/api/handlers/stream.go
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shivani-1505/zapier-clone/backend/internal/eventbus"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// Stream connection timing: a ping goes out every streamPingInterval, and a
+// client that has not answered within streamPongWait is disconnected
+const (
+	streamWriteWait    = 10 * time.Second
+	streamPongWait     = 60 * time.Second
+	streamPingInterval = 30 * time.Second
+	streamBuffer       = 256
+)
+
+// StreamHandler pushes a tenant's integration activity to dashboards over
+// WebSocket
+type StreamHandler struct {
+	Events   *eventbus.Bus
+	Upgrader websocket.Upgrader
+}
+
+// NewStreamHandler creates a stream handler for a tenant's event bus.
+// Browsers on other origins, such as the frontend dev server, are accepted
+// when listed in STREAM_ALLOWED_ORIGINS (comma-separated).
+func NewStreamHandler(events *eventbus.Bus) *StreamHandler {
+	allowed := splitList(os.Getenv("STREAM_ALLOWED_ORIGINS"))
+	return &StreamHandler{
+		Events: events,
+		Upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+			CheckOrigin: func(r *http.Request) bool {
+				return sameOrigin(r) || containsOrigin(allowed, r.Header.Get("Origin"))
+			},
+		},
+	}
+}
+
+// streamEvent is one frame of the stream
+type streamEvent struct {
+	eventbus.Message
+	// Dropped is how many events were missed since the previous frame because
+	// the client fell behind
+	Dropped uint64 `json:"dropped,omitempty"`
+}
+
+// HandleStream upgrades to a WebSocket and sends every event of the tenant
+// matching ?types= (patterns such as ticket.* or *.failed) and ?sources=,
+// each comma-separated, until either side closes
+func (h *StreamHandler) HandleStream(w http.ResponseWriter, r *http.Request) {
+	if h.Events == nil {
+		writeError(w, http.StatusServiceUnavailable, "The activity stream is not available")
+		return
+	}
+	query := r.URL.Query()
+	filter := eventbus.Filter{
+		Types:   splitList(query.Get("types")),
+		Sources: splitList(query.Get("sources")),
+	}
+
+	conn, err := h.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already answered
+		return
+	}
+	defer conn.Close()
+
+	events, dropped, cancel := h.Events.Subscribe(filter, streamBuffer)
+	defer cancel()
+
+	// Read to handle pongs and notice the client closing; clients send nothing else
+	closed := make(chan struct{})
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+
+	var reported uint64
+	for {
+		select {
+		case <-closed:
+			return
+		case message, ok := <-events:
+			conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if !ok {
+				// The bus closed because the server is shutting down
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+				return
+			}
+			missed := dropped()
+			if err := conn.WriteJSON(streamEvent{Message: message, Dropped: missed - reported}); err != nil {
+				logging.FromContext(r.Context()).Error("error writing to activity stream", "error", err)
+				return
+			}
+			reported = missed
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sameOrigin accepts requests without an Origin header (non-browser clients)
+// and browsers on the server's own host
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+func containsOrigin(allowed []string, origin string) bool {
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimRight(a, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
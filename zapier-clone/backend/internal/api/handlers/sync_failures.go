@@ -0,0 +1,40 @@
+// backend/internal/api/handlers/sync_failures.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/monitoring"
+)
+
+// SyncFailureHandler exposes the full events behind sync failure alerts
+type SyncFailureHandler struct {
+	FailureAlerter *monitoring.FailureAlerter
+}
+
+// NewSyncFailureHandler creates a new sync failure handler
+func NewSyncFailureHandler(alerter *monitoring.FailureAlerter) *SyncFailureHandler {
+	return &SyncFailureHandler{
+		FailureAlerter: alerter,
+	}
+}
+
+// HandleListFailures returns recent sync failures, newest first
+func (h *SyncFailureHandler) HandleListFailures(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.FailureAlerter.List())
+}
+
+// HandleGetFailure returns one failure with its unredacted payload
+func (h *SyncFailureHandler) HandleGetFailure(w http.ResponseWriter, r *http.Request) {
+	failure, ok := h.FailureAlerter.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "Sync failure not found; only recent failures are kept", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(failure)
+}
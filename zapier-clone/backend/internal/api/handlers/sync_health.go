@@ -0,0 +1,175 @@
+// backend/internal/api/handlers/sync_health.go
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/github"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/jobs"
+	"github.com/shivani-1505/zapier-clone/backend/internal/monitoring"
+	"github.com/shivani-1505/zapier-clone/backend/internal/tenant"
+)
+
+// SyncHealthHandler summarizes sync health for an ops dashboard
+type SyncHealthHandler struct {
+	Health    *monitoring.SyncHealth
+	Risks     jira.MappingStore
+	Incidents *jira.IncidentJiraMapping
+	// GitHubIssues is nil when GitHub is not configured
+	GitHubIssues *github.IssueMapping
+	Jobs         *jobs.Queue
+	Settler      *servicenow.Settler
+	DeadLetters  *monitoring.DeadLetterStore
+}
+
+// NewSyncHealthHandler creates a new sync health handler
+func NewSyncHealthHandler(health *monitoring.SyncHealth, risks jira.MappingStore, incidents *jira.IncidentJiraMapping, gitHubIssues *github.IssueMapping, jobQueue *jobs.Queue, settler *servicenow.Settler, deadLetters *monitoring.DeadLetterStore) *SyncHealthHandler {
+	return &SyncHealthHandler{
+		Health:       health,
+		Risks:        risks,
+		Incidents:    incidents,
+		GitHubIssues: gitHubIssues,
+		Jobs:         jobQueue,
+		Settler:      settler,
+		DeadLetters:  deadLetters,
+	}
+}
+
+// MappingCounts is how many records are linked in each mapping
+type MappingCounts struct {
+	Risks        int  `json:"risks"`
+	Incidents    int  `json:"incidents"`
+	GitHubIssues *int `json:"github_issues,omitempty"`
+	Total        int  `json:"total"`
+}
+
+// ErrorRate is the share of syncs that failed in the last hour
+type ErrorRate struct {
+	Window    string  `json:"window"`
+	Successes int     `json:"successes"`
+	Failures  int     `json:"failures"`
+	Rate      float64 `json:"rate"`
+}
+
+// IntegrationsHealth is the per-integration state with the overall error rate
+type IntegrationsHealth struct {
+	Integrations []monitoring.IntegrationHealth `json:"integrations"`
+	ErrorRate    ErrorRate                      `json:"error_rate"`
+}
+
+// QueueDepth is the work waiting to be synced
+type QueueDepth struct {
+	// Jobs covers the pool shared by all tenants
+	Jobs jobs.Stats `json:"jobs"`
+	// Settling are new ServiceNow records held in the settle window
+	Settling    int              `json:"settling"`
+	DeadLetters DeadLetterCounts `json:"dead_letters"`
+}
+
+// DeadLetterCounts counts dead letters by status
+type DeadLetterCounts struct {
+	Pending  int `json:"pending"`
+	Replayed int `json:"replayed"`
+}
+
+// SyncHealthSummary is everything the dashboard shows
+type SyncHealthSummary struct {
+	Tenant      string        `json:"tenant"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Mappings    MappingCounts `json:"mappings"`
+	IntegrationsHealth
+	Queues QueueDepth `json:"queues"`
+}
+
+// HandleGetSyncHealth returns the whole summary
+func (h *SyncHealthHandler) HandleGetSyncHealth(w http.ResponseWriter, r *http.Request) {
+	mappings, err := h.mappingCounts()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, SyncHealthSummary{
+		Tenant:             r.Header.Get(tenant.HeaderID),
+		GeneratedAt:        time.Now().UTC(),
+		Mappings:           mappings,
+		IntegrationsHealth: h.integrations(),
+		Queues:             h.queueDepth(),
+	})
+}
+
+// HandleGetMappingCounts returns how many records are linked
+func (h *SyncHealthHandler) HandleGetMappingCounts(w http.ResponseWriter, r *http.Request) {
+	mappings, err := h.mappingCounts()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, mappings)
+}
+
+// HandleGetIntegrationsHealth returns the last success and failure of each
+// integration and the error rates over the last hour
+func (h *SyncHealthHandler) HandleGetIntegrationsHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.integrations())
+}
+
+// HandleGetQueueDepth returns queued jobs, held records and dead letters
+func (h *SyncHealthHandler) HandleGetQueueDepth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.queueDepth())
+}
+
+func (h *SyncHealthHandler) mappingCounts() (MappingCounts, error) {
+	var counts MappingCounts
+	if h.Risks != nil {
+		risks, _, err := h.Risks.Snapshot()
+		if err != nil {
+			return counts, err
+		}
+		counts.Risks = len(risks)
+	}
+	if h.Incidents != nil {
+		incidents, _ := h.Incidents.Snapshot()
+		counts.Incidents = len(incidents)
+	}
+	counts.Total = counts.Risks + counts.Incidents
+	if h.GitHubIssues != nil {
+		issues := h.GitHubIssues.Count()
+		counts.GitHubIssues = &issues
+		counts.Total += issues
+	}
+	return counts, nil
+}
+
+func (h *SyncHealthHandler) integrations() IntegrationsHealth {
+	health := IntegrationsHealth{
+		Integrations: []monitoring.IntegrationHealth{},
+		ErrorRate:    ErrorRate{Window: "1h"},
+	}
+	if h.Health == nil {
+		return health
+	}
+	health.Integrations = h.Health.Integrations()
+	health.ErrorRate.Successes, health.ErrorRate.Failures, health.ErrorRate.Rate = h.Health.ErrorRate()
+	return health
+}
+
+func (h *SyncHealthHandler) queueDepth() QueueDepth {
+	depth := QueueDepth{Settling: h.Settler.Pending()}
+	if h.Jobs != nil {
+		depth.Jobs = h.Jobs.Stats()
+	}
+	if h.DeadLetters != nil {
+		for _, letter := range h.DeadLetters.List("") {
+			switch letter.Status {
+			case monitoring.DeadLetterPending:
+				depth.DeadLetters.Pending++
+			case monitoring.DeadLetterReplayed:
+				depth.DeadLetters.Replayed++
+			}
+		}
+	}
+	return depth
+}
@@ -0,0 +1,37 @@
+// backend/internal/api/handlers/sync_loops.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/loopguard"
+)
+
+// SyncLoopHandler exposes the sync loop guard on the admin API
+type SyncLoopHandler struct {
+	LoopGuard *loopguard.Guard
+}
+
+// NewSyncLoopHandler creates a new sync loop handler
+func NewSyncLoopHandler(guard *loopguard.Guard) *SyncLoopHandler {
+	return &SyncLoopHandler{
+		LoopGuard: guard,
+	}
+}
+
+// HandleListLoops returns loop guard counters and recently detected loops
+func (h *SyncLoopHandler) HandleListLoops(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.LoopGuard.Stats())
+}
+
+// HandleResetLoop lifts the block on an entity so it syncs again
+func (h *SyncLoopHandler) HandleResetLoop(w http.ResponseWriter, r *http.Request) {
+	entity := mux.Vars(r)["entity"]
+	h.LoopGuard.Reset(entity)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}`))
+}
@@ -0,0 +1,116 @@
+// backend/internal/api/handlers/tables.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// TableHandler manages the ServiceNow tables synced besides the built-in ones
+type TableHandler struct {
+	Tables *servicenow.TableRegistry
+}
+
+// NewTableHandler creates a new table registry handler
+func NewTableHandler(tables *servicenow.TableRegistry) *TableHandler {
+	return &TableHandler{
+		Tables: tables,
+	}
+}
+
+// HandleListTables returns the built-in tables and the registered ones
+func (h *TableHandler) HandleListTables(w http.ResponseWriter, r *http.Request) {
+	registered := h.Tables.List()
+	if registered == nil {
+		registered = []servicenow.CustomTable{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"built_in":   servicenow.GRCTables,
+		"registered": registered,
+	})
+}
+
+// HandleGetTable returns one registered table
+func (h *TableHandler) HandleGetTable(w http.ResponseWriter, r *http.Request) {
+	table, ok := h.Tables.Get(mux.Vars(r)["name"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "Table not registered")
+		return
+	}
+	writeJSON(w, http.StatusOK, table)
+}
+
+// HandleRegisterTable registers a new table; its next webhook is synced
+func (h *TableHandler) HandleRegisterTable(w http.ResponseWriter, r *http.Request) {
+	if h.Tables == nil {
+		writeError(w, http.StatusServiceUnavailable, "The table registry is not configured")
+		return
+	}
+
+	var table servicenow.CustomTable
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if _, exists := h.Tables.Get(table.Name); exists {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Table %s is already registered; use PUT to change it", table.Name))
+		return
+	}
+
+	registered, _, err := h.Tables.Register(table)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, registered)
+}
+
+// HandleUpdateTable registers a table or replaces its settings
+func (h *TableHandler) HandleUpdateTable(w http.ResponseWriter, r *http.Request) {
+	if h.Tables == nil {
+		writeError(w, http.StatusServiceUnavailable, "The table registry is not configured")
+		return
+	}
+
+	var table servicenow.CustomTable
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	table.Name = mux.Vars(r)["name"]
+
+	registered, created, err := h.Tables.Register(table)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, registered)
+}
+
+// HandleDeleteTable unregisters a table; its webhooks are ignored again
+func (h *TableHandler) HandleDeleteTable(w http.ResponseWriter, r *http.Request) {
+	if h.Tables == nil {
+		writeError(w, http.StatusServiceUnavailable, "The table registry is not configured")
+		return
+	}
+
+	err := h.Tables.Unregister(mux.Vars(r)["name"])
+	if errors.Is(err, servicenow.ErrTableNotFound) {
+		writeError(w, http.StatusNotFound, "Table not registered")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/teams"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// TeamsBotHandler receives activities from the Teams bot. Adaptive Card button
+// presses are handled exactly like the Slack buttons they were converted from.
+type TeamsBotHandler struct {
+	Interactions *SlackInteractionHandler
+}
+
+// NewTeamsBotHandler creates a Teams bot handler that dispatches to interactions
+func NewTeamsBotHandler(interactions *SlackInteractionHandler) *TeamsBotHandler {
+	return &TeamsBotHandler{
+		Interactions: interactions,
+	}
+}
+
+// HandleActivity processes an activity sent by the Bot Connector
+func (h *TeamsBotHandler) HandleActivity(w http.ResponseWriter, r *http.Request) {
+	var activity teams.IncomingActivity
+	if err := json.NewDecoder(r.Body).Decode(&activity); err != nil {
+		http.Error(w, "Invalid activity", http.StatusBadRequest)
+		return
+	}
+	metrics.WebhooksReceived.Inc("teams")
+
+	// Only card submits are acted on; other activities (installs, mentions) are acknowledged
+	submit, ok := activity.Submit()
+	if !ok {
+		logging.FromContext(r.Context()).Debug("ignoring Teams activity", "type", activity.Type)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	channel, rootID := activity.Thread()
+	payload := slack.InteractionPayload{
+		Type:      "block_actions",
+		ChannelID: slack.PlatformChannel(notification.PlatformTeams, channel),
+		MessageTS: rootID,
+		UserID:    activity.From.Name,
+		UserName:  activity.From.Name,
+		Actions:   []slack.InteractionAction{{ActionID: submit.ActionID, Type: "button", Value: submit.Value}},
+	}
+
+	// Process the press asynchronously, keeping the request's correlation ID
+	if err := h.Interactions.submit(logging.Detach(r.Context()), payload); err != nil {
+		rejectJob(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,55 @@
+// backend/internal/api/handlers/war_rooms.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// WarRoomHandler lists the Slack channels opened for major incidents
+type WarRoomHandler struct {
+	WarRooms *servicenow.WarRooms
+}
+
+// NewWarRoomHandler creates a new war room handler
+func NewWarRoomHandler(warRooms *servicenow.WarRooms) *WarRoomHandler {
+	return &WarRoomHandler{
+		WarRooms: warRooms,
+	}
+}
+
+// HandleListWarRooms returns war rooms, newest first, optionally filtered by ?status=
+func (h *WarRoomHandler) HandleListWarRooms(w http.ResponseWriter, r *http.Request) {
+	if h.WarRooms == nil {
+		writeError(w, http.StatusServiceUnavailable, "War rooms are not configured")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", servicenow.WarRoomOpen, servicenow.WarRoomArchived:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid status %q: use %s or %s", status,
+			servicenow.WarRoomOpen, servicenow.WarRoomArchived))
+		return
+	}
+	writeJSON(w, http.StatusOK, h.WarRooms.List(status))
+}
+
+// HandleGetWarRoom returns the war room of one incident
+func (h *WarRoomHandler) HandleGetWarRoom(w http.ResponseWriter, r *http.Request) {
+	if h.WarRooms == nil {
+		writeError(w, http.StatusServiceUnavailable, "War rooms are not configured")
+		return
+	}
+
+	room, ok := h.WarRooms.Get(mux.Vars(r)["sysId"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "The incident has no war room")
+		return
+	}
+	writeJSON(w, http.StatusOK, room)
+}
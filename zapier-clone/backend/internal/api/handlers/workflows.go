@@ -0,0 +1,479 @@
+// backend/internal/api/handlers/workflows.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/connections"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workflow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workspace"
+)
+
+// WorkflowHandler serves CRUD endpoints for stored workflow definitions
+type WorkflowHandler struct {
+	Store      *workflow.Store
+	Workspaces *workspace.Store
+	// Connections is nil without CONNECTIONS_ENCRYPTION_KEY; sandbox workflows
+	// then cannot be activated
+	Connections *connections.Store
+}
+
+// NewWorkflowHandler creates a new workflow handler
+func NewWorkflowHandler(store *workflow.Store, workspaces *workspace.Store) *WorkflowHandler {
+	return &WorkflowHandler{
+		Store:      store,
+		Workspaces: workspaces,
+	}
+}
+
+// HandleListWorkflows lists workflows, optionally filtered by workspace_id,
+// status and environment
+func (h *WorkflowHandler) HandleListWorkflows(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	opts := workflow.ListOptions{
+		Status:      r.URL.Query().Get("status"),
+		Environment: r.URL.Query().Get("environment"),
+	}
+	if workspaceID, err := strconv.Atoi(r.URL.Query().Get("workspace_id")); err == nil {
+		if _, err := h.Workspaces.MemberRole(workspaceID, userID); err != nil {
+			h.writeError(w, err)
+			return
+		}
+		opts.WorkspaceID = workspaceID
+	} else {
+		opts.UserID = userID
+	}
+
+	workflows, err := h.Store.List(opts)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, workflows)
+}
+
+// HandleGetWorkflow returns one workflow
+func (h *WorkflowHandler) HandleGetWorkflow(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorize(w, r, workspace.ActionView)
+	if !ok {
+		return
+	}
+
+	wf, err := h.Store.Get(id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, wf)
+}
+
+// HandleCreateWorkflow stores a new workflow owned by the caller
+func (h *WorkflowHandler) HandleCreateWorkflow(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	var wf workflow.Workflow
+	if err := json.NewDecoder(r.Body).Decode(&wf); err != nil || wf.Name == "" || wf.TriggerService == "" || wf.TriggerID == "" {
+		http.Error(w, "name, trigger_service and trigger_id are required", http.StatusBadRequest)
+		return
+	}
+	if err := wf.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	wf.ID = 0
+	wf.Key = ""
+	wf.UserID = userID
+	if !h.checkConnections(w, &wf) {
+		return
+	}
+
+	if wf.WorkspaceID != nil {
+		role, err := h.Workspaces.MemberRole(*wf.WorkspaceID, userID)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		if !workspace.RoleAllows(role, workspace.ActionEdit) {
+			h.writeError(w, workspace.ErrForbidden)
+			return
+		}
+		if err := h.Workspaces.CheckWorkflowQuota(*wf.WorkspaceID); err != nil {
+			h.writeError(w, err)
+			return
+		}
+	}
+
+	if err := h.Store.Create(&wf); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, wf)
+}
+
+// HandleUpdateWorkflow replaces a workflow's definition
+func (h *WorkflowHandler) HandleUpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorize(w, r, workspace.ActionEdit)
+	if !ok {
+		return
+	}
+
+	existing, err := h.Store.Get(id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	var wf workflow.Workflow
+	if err := json.NewDecoder(r.Body).Decode(&wf); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := wf.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Ownership and workspace only change through the workspace API
+	wf.ID = id
+	wf.Key = existing.Key
+	wf.UserID = existing.UserID
+	wf.WorkspaceID = existing.WorkspaceID
+	if wf.Status == "" {
+		wf.Status = existing.Status
+	}
+	if !h.checkConnections(w, &wf) {
+		return
+	}
+
+	if err := h.Store.Update(&wf); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, wf)
+}
+
+// HandleDeleteWorkflow deletes a workflow
+func (h *WorkflowHandler) HandleDeleteWorkflow(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorize(w, r, workspace.ActionManage)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.Delete(id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// HandleActivateWorkflow marks a workflow active
+func (h *WorkflowHandler) HandleActivateWorkflow(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, workflow.StatusActive)
+}
+
+// HandleDeactivateWorkflow marks a workflow inactive
+func (h *WorkflowHandler) HandleDeactivateWorkflow(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, workflow.StatusInactive)
+}
+
+func (h *WorkflowHandler) setStatus(w http.ResponseWriter, r *http.Request, status string) {
+	id, ok := h.authorize(w, r, workspace.ActionEdit)
+	if !ok {
+		return
+	}
+
+	if status == workflow.StatusActive {
+		wf, err := h.Store.Get(id)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		wf.Status = status
+		if !h.checkConnections(w, wf) {
+			return
+		}
+	}
+
+	if err := h.Store.SetStatus(id, status); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": status})
+}
+
+// HandleSetEnvironment switches the environment a workflow targets, e.g. to
+// production once it has been tried against a sandbox
+func (h *WorkflowHandler) HandleSetEnvironment(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorize(w, r, workspace.ActionEdit)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Environment string `json:"environment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Environment == "" {
+		http.Error(w, "environment is required", http.StatusBadRequest)
+		return
+	}
+
+	wf, err := h.Store.Get(id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	wf.Environment = req.Environment
+	if err := wf.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.checkConnections(w, wf) {
+		return
+	}
+
+	if err := h.Store.SetEnvironment(id, wf.Environment); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"environment": wf.Environment})
+}
+
+// checkConnections keeps an active sandbox workflow from falling back to
+// production: its owner needs an active sandbox connection for every service
+// its actions call. Production workflows use the server's own integrations
+// when the owner has no connection. It writes a 409 and returns false when a
+// connection is missing.
+func (h *WorkflowHandler) checkConnections(w http.ResponseWriter, wf *workflow.Workflow) bool {
+	if wf.Status != workflow.StatusActive || wf.Environment != workflow.EnvironmentSandbox {
+		return true
+	}
+	if h.Connections == nil {
+		http.Error(w, "Sandbox workflows require connections (CONNECTIONS_ENCRYPTION_KEY)", http.StatusConflict)
+		return false
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, action := range wf.Actions {
+		service := action.ActionService
+		if service == workflow.ConditionService || seen[service] {
+			continue
+		}
+		seen[service] = true
+		_, err := h.Connections.FindActive(wf.UserID, service, connections.EnvironmentSandbox)
+		if errors.Is(err, connections.ErrNotFound) {
+			missing = append(missing, service)
+			continue
+		}
+		if err != nil {
+			h.writeError(w, err)
+			return false
+		}
+	}
+	if len(missing) > 0 {
+		http.Error(w, "No active sandbox connection for "+strings.Join(missing, ", "), http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// HandleListExecutions lists a workflow's execution history, newest first,
+// optionally filtered by status
+func (h *WorkflowHandler) HandleListExecutions(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorize(w, r, workspace.ActionView)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	executions, err := h.Store.ListExecutions(id, r.URL.Query().Get("status"), limit)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, executions)
+}
+
+// HandleGetExecutionByID returns an execution with every step's status, input,
+// output, duration and error. ?unredacted=true needs manage access to the workflow.
+func (h *WorkflowHandler) HandleGetExecutionByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid execution ID", http.StatusBadRequest)
+		return
+	}
+
+	execution, err := h.Store.GetExecution(id, false)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	action := workspace.ActionView
+	unredacted := r.URL.Query().Get("unredacted") == "true"
+	if unredacted {
+		action = workspace.ActionManage
+	}
+	allowed, err := h.Workspaces.Can(userID, execution.WorkflowID, action)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if !allowed {
+		h.writeError(w, workspace.ErrForbidden)
+		return
+	}
+
+	if unredacted && execution.Redacted {
+		execution, err = h.Store.GetExecution(id, true)
+		if errors.Is(err, workflow.ErrUnredactedNotAllowed) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		logging.FromContext(r.Context()).Info("user viewed unredacted execution", "user_id", userID, "execution_id", id)
+	}
+
+	writeJSON(w, http.StatusOK, execution)
+}
+
+// HandleGetExecution returns one of the workspace's executions with its steps.
+// Sensitive fields are redacted; workspace admins can pass ?unredacted=true
+// to see the originals where the redaction policy kept them.
+func (h *WorkflowHandler) HandleGetExecution(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	workspaceID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid workspace ID", http.StatusBadRequest)
+		return
+	}
+	executionID, err := strconv.Atoi(vars["executionId"])
+	if err != nil {
+		http.Error(w, "Invalid execution ID", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.Workspaces.MemberRole(workspaceID, userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	unredacted := r.URL.Query().Get("unredacted") == "true"
+	if unredacted && !workspace.RoleAllows(role, workspace.ActionManage) {
+		http.Error(w, "Only workspace admins can view unredacted data", http.StatusForbidden)
+		return
+	}
+
+	execution, err := h.Store.GetExecution(executionID, unredacted)
+	if errors.Is(err, workflow.ErrUnredactedNotAllowed) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if execution.WorkspaceID == nil || *execution.WorkspaceID != workspaceID {
+		http.Error(w, "Execution not found", http.StatusNotFound)
+		return
+	}
+
+	if unredacted {
+		logging.FromContext(r.Context()).Info("user viewed unredacted execution", "user_id", userID, "execution_id", executionID, "workspace_id", workspaceID)
+	}
+	writeJSON(w, http.StatusOK, execution)
+}
+
+// caller returns the authenticated user, writing an error if there is none
+func (h *WorkflowHandler) caller(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if h.Store == nil || h.Workspaces == nil {
+		http.Error(w, "Workflows require a database connection", http.StatusServiceUnavailable)
+		return 0, false
+	}
+
+	userID, ok := userIDFromRequest(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, false
+	}
+	return userID, true
+}
+
+// authorize checks the caller's access to the workflow named in the path
+func (h *WorkflowHandler) authorize(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid workflow ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	allowed, err := h.Workspaces.Can(userID, id, action)
+	if err != nil {
+		h.writeError(w, err)
+		return 0, false
+	}
+	if !allowed {
+		h.writeError(w, workspace.ErrForbidden)
+		return 0, false
+	}
+
+	return id, true
+}
+
+// writeError maps workflow and workspace errors to HTTP statuses
+func (h *WorkflowHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, workflow.ErrNotFound), errors.Is(err, workspace.ErrNotFound):
+		http.Error(w, "Workflow not found", http.StatusNotFound)
+	case errors.Is(err, workflow.ErrExecutionNotFound):
+		http.Error(w, "Execution not found", http.StatusNotFound)
+	case errors.Is(err, workspace.ErrNotMember), errors.Is(err, workspace.ErrForbidden):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, workspace.ErrQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		slog.Error("workflow error", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,351 @@
+// backend/internal/api/handlers/workspaces.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workspace"
+)
+
+// WorkspaceHandler manages team workspaces, members and workflow sharing
+type WorkspaceHandler struct {
+	Store *workspace.Store
+}
+
+// NewWorkspaceHandler creates a new workspace handler
+func NewWorkspaceHandler(store *workspace.Store) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		Store: store,
+	}
+}
+
+// HandleListWorkspaces lists the caller's workspaces
+func (h *WorkspaceHandler) HandleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	workspaces, err := h.Store.ListForUser(userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, workspaces)
+}
+
+// HandleCreateWorkspace creates a workspace owned by the caller
+func (h *WorkspaceHandler) HandleCreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" || req.Slug == "" {
+		http.Error(w, "name and slug are required", http.StatusBadRequest)
+		return
+	}
+
+	ws, err := h.Store.CreateWorkspace(req.Name, req.Slug, userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, ws)
+}
+
+// HandleListMembers lists a workspace's members
+func (h *WorkspaceHandler) HandleListMembers(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.authorize(w, r, workspace.ActionView)
+	if !ok {
+		return
+	}
+
+	members, err := h.Store.Members(workspaceID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, members)
+}
+
+// HandleSetMember adds a member or changes their role
+func (h *WorkspaceHandler) HandleSetMember(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.authorize(w, r, workspace.ActionManage)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.SetMember(workspaceID, memberID, req.Role); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// HandleRemoveMember removes a member from a workspace
+func (h *WorkspaceHandler) HandleRemoveMember(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.authorize(w, r, workspace.ActionManage)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.RemoveMember(workspaceID, memberID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// HandleGetUsage returns the workspace's quota usage
+func (h *WorkspaceHandler) HandleGetUsage(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.authorize(w, r, workspace.ActionView)
+	if !ok {
+		return
+	}
+
+	usage, err := h.Store.Usage(workspaceID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, usage)
+}
+
+// HandleSetQuota sets the workspace's limits
+func (h *WorkspaceHandler) HandleSetQuota(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.authorize(w, r, workspace.ActionManage)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		MaxWorkflows         *int `json:"max_workflows"`
+		MaxMonthlyExecutions *int `json:"max_monthly_executions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.SetQuota(workspaceID, req.MaxWorkflows, req.MaxMonthlyExecutions); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.HandleGetUsage(w, r)
+}
+
+// HandleListExecutions lists the workspace's executions, optionally filtered by status
+func (h *WorkspaceHandler) HandleListExecutions(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := h.authorize(w, r, workspace.ActionView)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	executions, err := h.Store.Executions(workspaceID, r.URL.Query().Get("status"), limit)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, executions)
+}
+
+// HandleListShares lists the workspaces a workflow is shared with
+func (h *WorkspaceHandler) HandleListShares(w http.ResponseWriter, r *http.Request) {
+	workflowID, ok := h.authorizeWorkflow(w, r, workspace.ActionView)
+	if !ok {
+		return
+	}
+
+	shares, err := h.Store.Shares(workflowID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, shares)
+}
+
+// HandleShareWorkflow shares a workflow with another workspace
+func (h *WorkspaceHandler) HandleShareWorkflow(w http.ResponseWriter, r *http.Request) {
+	workflowID, ok := h.authorizeWorkflow(w, r, workspace.ActionManage)
+	if !ok {
+		return
+	}
+	userID, _ := userIDFromRequest(r)
+
+	var req struct {
+		WorkspaceID int    `json:"workspace_id"`
+		Permission  string `json:"permission"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.WorkspaceID == 0 {
+		http.Error(w, "workspace_id and permission are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.ShareWorkflow(workflowID, req.WorkspaceID, req.Permission, userID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// HandleUnshareWorkflow revokes a workspace's access to a workflow
+func (h *WorkspaceHandler) HandleUnshareWorkflow(w http.ResponseWriter, r *http.Request) {
+	workflowID, ok := h.authorizeWorkflow(w, r, workspace.ActionManage)
+	if !ok {
+		return
+	}
+
+	targetID, err := strconv.Atoi(mux.Vars(r)["workspaceId"])
+	if err != nil {
+		http.Error(w, "Invalid workspace ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Store.UnshareWorkflow(workflowID, targetID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// caller returns the authenticated user, writing an error if there is none
+func (h *WorkspaceHandler) caller(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if h.Store == nil {
+		http.Error(w, "Workspaces require a database connection", http.StatusServiceUnavailable)
+		return 0, false
+	}
+
+	userID, ok := userIDFromRequest(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, false
+	}
+	return userID, true
+}
+
+// authorize checks the caller's role in the workspace named in the path
+func (h *WorkspaceHandler) authorize(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return 0, false
+	}
+
+	workspaceID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid workspace ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	role, err := h.Store.MemberRole(workspaceID, userID)
+	if err != nil {
+		h.writeError(w, err)
+		return 0, false
+	}
+	if !workspace.RoleAllows(role, action) {
+		h.writeError(w, workspace.ErrForbidden)
+		return 0, false
+	}
+
+	return workspaceID, true
+}
+
+// authorizeWorkflow checks the caller's access to the workflow named in the path
+func (h *WorkspaceHandler) authorizeWorkflow(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	userID, ok := h.caller(w, r)
+	if !ok {
+		return 0, false
+	}
+
+	workflowID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid workflow ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	allowed, err := h.Store.Can(userID, workflowID, action)
+	if err != nil {
+		h.writeError(w, err)
+		return 0, false
+	}
+	if !allowed {
+		h.writeError(w, workspace.ErrForbidden)
+		return 0, false
+	}
+
+	return workflowID, true
+}
+
+// writeError maps workspace errors to HTTP statuses
+func (h *WorkspaceHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, workspace.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, workspace.ErrNotMember), errors.Is(err, workspace.ErrForbidden):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, workspace.ErrLastOwner), errors.Is(err, workspace.ErrInvalidRole):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, workspace.ErrQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		slog.Error("workspace error", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+// userIDFromRequest reads the authenticated user ID set by the auth layer
+func userIDFromRequest(r *http.Request) (int, bool) {
+	userID, err := strconv.Atoi(r.Header.Get("X-User-ID"))
+	if err != nil || userID <= 0 {
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,151 @@
+// backend/internal/api/middleware/jwt.go
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/auth"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// HeaderUserID carries the authenticated user to the handlers
+const HeaderUserID = "X-User-ID"
+
+// JWTMiddleware authenticates frontend requests with access tokens issued by
+// the auth service and passes the user on in X-User-ID
+type JWTMiddleware struct {
+	Service *auth.Service
+	// Protected are the path prefixes that need a token
+	Protected []string
+	// Public are paths under a protected prefix that don't, such as login
+	Public []string
+	// Admin are the protected paths only administrators may use. An entry
+	// may start with a method, as in "PUT /path", to cover only that method.
+	Admin []string
+}
+
+// NewJWTMiddleware protects the frontend's /api/v1 and /api/admin APIs. The
+// admin APIs, account creation and webhook API keys need an administrator's
+// token.
+func NewJWTMiddleware(service *auth.Service) *JWTMiddleware {
+	return &JWTMiddleware{
+		Service:   service,
+		Protected: []string{"/api/v1/", "/api/admin/"},
+		Public: []string{
+			"/api/v1/auth/login",
+			"/api/v1/auth/refresh",
+		},
+		Admin: []string{
+			"/api/admin/",
+			"/api/v1/auth/register",
+			"/api/v1/apikeys",
+			"/api/v1/apikeys/",
+			"PUT /api/v1/compliance/score/weights",
+			"PUT /api/v1/compliance/risk-matrix",
+			"DELETE /api/v1/compliance/risk-matrix",
+		},
+	}
+}
+
+// Middleware verifies the bearer token on protected paths
+func (m *JWTMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only a verified token may name the user
+		r.Header.Del(HeaderUserID)
+
+		if !m.protects(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Without an auth service nothing can be verified, so nothing passes
+		if m.Service == nil {
+			http.Error(w, "Sign-in is not configured", http.StatusServiceUnavailable)
+			return
+		}
+
+		token := auth.BearerToken(r)
+		if token == "" {
+			writeAuthError(w, "missing bearer token")
+			return
+		}
+
+		claims, err := m.Service.Authenticate(token)
+		if err != nil {
+			if !errors.Is(err, auth.ErrInvalidToken) && !errors.Is(err, auth.ErrTokenExpired) && !errors.Is(err, auth.ErrTokenRevoked) {
+				logging.FromContext(r.Context()).Error("error authenticating request", "path", r.URL.Path, "error", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			writeAuthError(w, err.Error())
+			return
+		}
+
+		if !claims.Admin && m.needsAdmin(r) {
+			writeForbidden(w, auth.ErrForbidden.Error())
+			return
+		}
+
+		r.Header.Set(HeaderUserID, strconv.Itoa(claims.Subject))
+		next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims)))
+	})
+}
+
+// protects reports whether a path needs a token
+func (m *JWTMiddleware) protects(path string) bool {
+	if matchesPrefix(m.Public, path) {
+		return false
+	}
+	for _, prefix := range m.Protected {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// needsAdmin reports whether a request is for an administrators' path
+func (m *JWTMiddleware) needsAdmin(r *http.Request) bool {
+	for _, entry := range m.Admin {
+		path := entry
+		if method, rest, ok := strings.Cut(entry, " "); ok {
+			if method != r.Method {
+				continue
+			}
+			path = rest
+		}
+		if matchesPrefix([]string{path}, r.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPrefix reports whether a path is one of the entries, or under one
+// ending in a slash
+func matchesPrefix(entries []string, path string) bool {
+	for _, prefix := range entries {
+		if path == prefix || (strings.HasSuffix(prefix, "/") && strings.HasPrefix(path, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAuthError answers 401 in the JSON shape the frontend reads
+func writeAuthError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write([]byte(`{"error":"` + message + `"}`))
+}
+
+// writeForbidden answers 403 to an authenticated caller who lacks access
+func writeForbidden(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	w.Write([]byte(`{"error":"` + message + `"}`))
+}
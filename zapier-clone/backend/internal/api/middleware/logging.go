@@ -2,9 +2,13 @@
 package middleware
 
 import (
-	"log"
+	"bufio"
+	"errors"
+	"net"
 	"net/http"
 	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
 )
 
 // LoggingMiddleware logs request information
@@ -15,18 +19,42 @@ func NewLoggingMiddleware() *LoggingMiddleware {
 	return &LoggingMiddleware{}
 }
 
-// Middleware logs information about the request
+// Middleware logs one record per request with its status and duration. It runs
+// after the timeout middleware so the record carries the request ID.
 func (m *LoggingMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
 
-		// Log the request details
-		log.Printf("Request started: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
-
-		// Call the next handler
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(recorder, r)
 
-		// Log the request completion
-		log.Printf("Request completed: %s %s in %v", r.Method, r.URL.Path, time.Since(start))
+		logging.FromContext(r.Context()).Info("request completed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"remote", remoteHost(r),
+		)
 	})
 }
+
+// statusRecorder remembers the status code a handler wrote
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Hijack hands the connection to a WebSocket upgrade; the record then shows 101
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
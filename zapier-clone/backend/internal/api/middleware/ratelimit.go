@@ -0,0 +1,107 @@
+// backend/internal/api/middleware/ratelimit.go
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket for one client
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimitMiddleware limits requests per client with a token bucket
+type RateLimitMiddleware struct {
+	// Rate is the number of requests allowed per second once the burst is spent
+	Rate float64
+	// Burst is the number of requests a client can make at once
+	Burst int
+
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewRateLimitMiddleware creates a new rate limiting middleware
+func NewRateLimitMiddleware(rate float64, burst int) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		Rate:    rate,
+		Burst:   burst,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Middleware rejects requests with 429 when the client has no tokens left
+func (m *RateLimitMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.allow(clientKey(r)) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(1/m.Rate)+1))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow takes a token from the client's bucket
+func (m *RateLimitMiddleware) allow(key string) bool {
+	now := time.Now()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		// Drop buckets of idle clients before tracking a new one
+		if len(m.buckets) > 10000 {
+			m.prune(now)
+		}
+
+		b = &bucket{tokens: float64(m.Burst), lastSeen: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.lastSeen).Seconds() * m.Rate
+	if b.tokens > float64(m.Burst) {
+		b.tokens = float64(m.Burst)
+	}
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// prune removes buckets that would have refilled completely
+func (m *RateLimitMiddleware) prune(now time.Time) {
+	full := time.Duration(float64(m.Burst) / m.Rate * float64(time.Second))
+	for key, b := range m.buckets {
+		if now.Sub(b.lastSeen) > full {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// clientKey identifies the caller by credentials, falling back to the remote address
+func clientKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return auth
+	}
+	return remoteHost(r)
+}
+
+// remoteHost returns the IP address of the connection a request came in on
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
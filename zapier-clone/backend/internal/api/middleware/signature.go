@@ -0,0 +1,307 @@
+// backend/internal/api/middleware/signature.go
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// DefaultMaxClockSkew is how far a signed timestamp may be from our clock
+const DefaultMaxClockSkew = 5 * time.Minute
+
+// maxSignedBody caps how much of a webhook is read for verification
+const maxSignedBody = 1 << 20
+
+// Headers ServiceNow business rules send with the shared webhook token
+const (
+	HeaderServiceNowToken     = "X-ServiceNow-Token"
+	HeaderServiceNowTimestamp = "X-ServiceNow-Timestamp"
+	HeaderServiceNowSignature = "X-ServiceNow-Signature"
+)
+
+// HeaderJiraSignature carries the HMAC of a Jira webhook created with a secret
+const HeaderJiraSignature = "X-Hub-Signature"
+
+// HeaderGitHubSignature carries the HMAC-SHA256 of a GitHub webhook
+const HeaderGitHubSignature = "X-Hub-Signature-256"
+
+var (
+	// ErrMissingSignature is returned when a request carries no signature at all
+	ErrMissingSignature = errors.New("missing signature")
+	// ErrBadSignature is returned when a signature or token does not match
+	ErrBadSignature = errors.New("signature mismatch")
+	// ErrClockSkew is returned when a signed timestamp is outside the skew window
+	ErrClockSkew = errors.New("timestamp outside the allowed clock skew")
+)
+
+// Verifier checks one source's request signature against the raw body
+type Verifier interface {
+	Verify(r *http.Request, body []byte, now time.Time) error
+}
+
+// SignatureMiddleware rejects inbound webhooks that are not signed by their source
+type SignatureMiddleware struct {
+	// Source names the sender in logs
+	Source string
+	// Verifier is nil when no secret is configured; requests are then rejected
+	Verifier Verifier
+	// AllowUnsigned lets requests through unverified when Verifier is nil, for
+	// local development against the mock servers
+	AllowUnsigned bool
+
+	now func() time.Time
+}
+
+// NewSignatureMiddleware creates the middleware for a source
+func NewSignatureMiddleware(source string, verifier Verifier) *SignatureMiddleware {
+	return &SignatureMiddleware{
+		Source:        source,
+		Verifier:      verifier,
+		AllowUnsigned: allowUnsignedFromEnv(),
+		now:           time.Now,
+	}
+}
+
+// NewSlackSignatureMiddleware verifies Slack's v0 signature with SLACK_SIGNING_SECRET
+func NewSlackSignatureMiddleware() *SignatureMiddleware {
+	var verifier Verifier
+	if secret := secretFromEnv("SLACK_SIGNING_SECRET", "Slack"); secret != "" {
+		verifier = &SlackVerifier{SigningSecret: secret, MaxClockSkew: MaxClockSkewFromEnv()}
+	}
+	return NewSignatureMiddleware("Slack", verifier)
+}
+
+// NewJiraSignatureMiddleware verifies Jira's webhook HMAC with JIRA_WEBHOOK_SECRET
+func NewJiraSignatureMiddleware() *SignatureMiddleware {
+	var verifier Verifier
+	if secret := secretFromEnv("JIRA_WEBHOOK_SECRET", "Jira"); secret != "" {
+		verifier = &JiraVerifier{Secret: secret, MaxClockSkew: MaxClockSkewFromEnv()}
+	}
+	return NewSignatureMiddleware("Jira", verifier)
+}
+
+// NewGitHubSignatureMiddleware verifies GitHub's webhook HMAC with GITHUB_WEBHOOK_SECRET
+func NewGitHubSignatureMiddleware() *SignatureMiddleware {
+	var verifier Verifier
+	if secret := secretFromEnv("GITHUB_WEBHOOK_SECRET", "GitHub"); secret != "" {
+		verifier = &GitHubVerifier{Secret: secret}
+	}
+	return NewSignatureMiddleware("GitHub", verifier)
+}
+
+// NewServiceNowSignatureMiddleware verifies the ServiceNow shared token in SERVICENOW_WEBHOOK_TOKEN
+func NewServiceNowSignatureMiddleware() *SignatureMiddleware {
+	var verifier Verifier
+	if token := secretFromEnv("SERVICENOW_WEBHOOK_TOKEN", "ServiceNow"); token != "" {
+		verifier = &ServiceNowVerifier{Token: token, MaxClockSkew: MaxClockSkewFromEnv()}
+	}
+	return NewSignatureMiddleware("ServiceNow", verifier)
+}
+
+// MaxClockSkewFromEnv reads WEBHOOK_MAX_CLOCK_SKEW (e.g. "2m"), defaulting to five minutes
+func MaxClockSkewFromEnv() time.Duration {
+	value := os.Getenv("WEBHOOK_MAX_CLOCK_SKEW")
+	if value == "" {
+		return DefaultMaxClockSkew
+	}
+	skew, err := time.ParseDuration(value)
+	if err != nil || skew <= 0 {
+		slog.Warn("ignoring invalid WEBHOOK_MAX_CLOCK_SKEW", "value", value)
+		return DefaultMaxClockSkew
+	}
+	return skew
+}
+
+// allowUnsignedFromEnv reads ALLOW_UNSIGNED_WEBHOOKS, which lets sources
+// without a secret through unverified; only the mock servers need it
+func allowUnsignedFromEnv() bool {
+	return strings.EqualFold(os.Getenv("ALLOW_UNSIGNED_WEBHOOKS"), "true")
+}
+
+// secretFromEnv reads a webhook secret, reporting when it is not set
+func secretFromEnv(key, source string) string {
+	secret := os.Getenv(key)
+	if secret == "" {
+		if allowUnsignedFromEnv() {
+			slog.Warn("webhook secret is not set; ALLOW_UNSIGNED_WEBHOOKS lets requests through unverified", "variable", key, "source", source)
+		} else {
+			slog.Error("webhook secret is not set; requests will be rejected", "variable", key, "source", source)
+		}
+	}
+	return secret
+}
+
+// Middleware verifies the signature and restores the body for the next handler
+func (m *SignatureMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.Verifier == nil {
+			if m.AllowUnsigned {
+				next.ServeHTTP(w, r)
+				return
+			}
+			logging.FromContext(r.Context()).Error("rejected request: no webhook secret is configured", "source", m.Source, "path", r.URL.Path, "remote", remoteHost(r))
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBody))
+		if err != nil {
+			http.Error(w, "Error reading request", http.StatusBadRequest)
+			return
+		}
+
+		if err := m.Verifier.Verify(r, body, m.now()); err != nil {
+			logging.FromContext(r.Context()).Warn("rejected request", "source", m.Source, "path", r.URL.Path, "remote", remoteHost(r), "error", err)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SlackVerifier checks Slack's v0 request signature
+type SlackVerifier struct {
+	SigningSecret string
+	MaxClockSkew  time.Duration
+}
+
+// Verify checks the signature and timestamp headers
+func (v *SlackVerifier) Verify(r *http.Request, body []byte, now time.Time) error {
+	timestamp := r.Header.Get(slack.HeaderRequestTimestamp)
+	signature := r.Header.Get(slack.HeaderSignature)
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+	if err := checkSkew(timestamp, time.Second, now, v.MaxClockSkew); err != nil {
+		return err
+	}
+	if err := slack.VerifySignatureWithin(v.SigningSecret, timestamp, signature, body, now, v.MaxClockSkew); err != nil {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// JiraVerifier checks the "sha256=<hex>" HMAC Jira sends for webhooks created
+// with a secret. Jira signs no header timestamp, so the skew window is applied
+// to the payload's own millisecond "timestamp" field when present.
+type JiraVerifier struct {
+	Secret       string
+	MaxClockSkew time.Duration
+}
+
+// Verify checks the body HMAC and the payload timestamp
+func (v *JiraVerifier) Verify(r *http.Request, body []byte, now time.Time) error {
+	signature := r.Header.Get(HeaderJiraSignature)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	if !validHMAC(v.Secret, body, strings.TrimPrefix(signature, "sha256=")) {
+		return ErrBadSignature
+	}
+
+	var payload struct {
+		Timestamp json.Number `json:"timestamp"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Timestamp != "" {
+		if err := checkSkew(payload.Timestamp.String(), time.Millisecond, now, v.MaxClockSkew); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GitHubVerifier checks the "sha256=<hex>" HMAC GitHub sends in
+// X-Hub-Signature-256. GitHub signs no timestamp, so there is no skew window;
+// X-GitHub-Delivery IDs identify redeliveries instead.
+type GitHubVerifier struct {
+	Secret string
+}
+
+// Verify checks the body HMAC
+func (v *GitHubVerifier) Verify(r *http.Request, body []byte, now time.Time) error {
+	signature := r.Header.Get(HeaderGitHubSignature)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	if !validHMAC(v.Secret, body, strings.TrimPrefix(signature, "sha256=")) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// ServiceNowVerifier checks the shared token a ServiceNow business rule sends.
+// Rules that can compute an HMAC send X-ServiceNow-Signature, the hex
+// HMAC-SHA256 of "timestamp.body" keyed by the token, with X-ServiceNow-Timestamp
+// in Unix seconds; that form is checked against the skew window. Simpler rules
+// send the token itself in X-ServiceNow-Token.
+type ServiceNowVerifier struct {
+	Token        string
+	MaxClockSkew time.Duration
+}
+
+// Verify checks the signature when present, otherwise the plain token
+func (v *ServiceNowVerifier) Verify(r *http.Request, body []byte, now time.Time) error {
+	if signature := r.Header.Get(HeaderServiceNowSignature); signature != "" {
+		timestamp := r.Header.Get(HeaderServiceNowTimestamp)
+		if err := checkSkew(timestamp, time.Second, now, v.MaxClockSkew); err != nil {
+			return err
+		}
+		signed := append([]byte(timestamp+"."), body...)
+		if !validHMAC(v.Token, signed, signature) {
+			return ErrBadSignature
+		}
+		return nil
+	}
+
+	token := r.Header.Get(HeaderServiceNowToken)
+	if token == "" {
+		return ErrMissingSignature
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(v.Token)) != 1 {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// checkSkew parses an integer timestamp in the given unit and rejects it when
+// it is further than maxSkew from now in either direction
+func checkSkew(timestamp string, unit time.Duration, now time.Time, maxSkew time.Duration) error {
+	value, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrClockSkew
+	}
+
+	skew := now.Sub(time.Unix(0, value*int64(unit)))
+	if skew > maxSkew || skew < -maxSkew {
+		return ErrClockSkew
+	}
+	return nil
+}
+
+// validHMAC compares a hex HMAC-SHA256 of body in constant time
+func validHMAC(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
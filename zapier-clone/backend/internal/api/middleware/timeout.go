@@ -0,0 +1,179 @@
+// backend/internal/api/middleware/timeout.go
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// HeaderRequestID carries the correlation ID of a request
+const HeaderRequestID = logging.HeaderRequestID
+
+// CorrelationID returns the ID assigned to a request by the timeout middleware
+func CorrelationID(ctx context.Context) string {
+	return logging.RequestID(ctx)
+}
+
+// TimeoutMiddleware bounds how long a handler may run. The request context
+// carries the deadline so client calls made with it are cancelled, and the
+// caller gets a 504 with the correlation ID when it is exceeded.
+type TimeoutMiddleware struct {
+	// Default applies to routes without an override
+	Default time.Duration
+	// Routes overrides the timeout by route path template, e.g. "/api/v1/sync/{table}/{sysId}".
+	// A zero timeout leaves a long-lived route, such as a WebSocket, unbounded and unbuffered.
+	Routes map[string]time.Duration
+}
+
+// NewTimeoutMiddleware creates a timeout middleware; REQUEST_TIMEOUT sets the default
+func NewTimeoutMiddleware() *TimeoutMiddleware {
+	timeout := 10 * time.Second
+	if value := os.Getenv("REQUEST_TIMEOUT"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			timeout = parsed
+		} else {
+			slog.Warn("ignoring invalid REQUEST_TIMEOUT", "value", value)
+		}
+	}
+
+	return &TimeoutMiddleware{
+		Default: timeout,
+		Routes:  make(map[string]time.Duration),
+	}
+}
+
+// Set overrides the timeout for a route path template
+func (m *TimeoutMiddleware) Set(pathTemplate string, timeout time.Duration) *TimeoutMiddleware {
+	m.Routes[pathTemplate] = timeout
+	return m
+}
+
+// Max returns the longest timeout of any route, for sizing server write timeouts
+func (m *TimeoutMiddleware) Max() time.Duration {
+	longest := m.Default
+	for _, timeout := range m.Routes {
+		if timeout > longest {
+			longest = timeout
+		}
+	}
+	return longest
+}
+
+// timeoutFor looks up the override for the matched route
+func (m *TimeoutMiddleware) timeoutFor(r *http.Request) time.Duration {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			if timeout, ok := m.Routes[template]; ok {
+				return timeout
+			}
+		}
+	}
+	return m.Default
+}
+
+// Middleware runs the handler under a deadline and answers 504 if it is not
+// done in time. The handler's output is buffered so a late write cannot reach
+// a response that has already timed out.
+func (m *TimeoutMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if id == "" {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set(HeaderRequestID, id)
+
+		timeout := m.timeoutFor(r)
+		if timeout == 0 {
+			next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+			return
+		}
+		ctx, cancel := context.WithTimeout(logging.WithRequestID(r.Context(), id), timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		tw := &timeoutWriter{header: make(http.Header), code: http.StatusOK}
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			next.ServeHTTP(tw, r)
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			tw.mutex.Lock()
+			defer tw.mutex.Unlock()
+			for key, values := range tw.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(tw.code)
+			w.Write(tw.body.Bytes())
+		case <-ctx.Done():
+			tw.mutex.Lock()
+			defer tw.mutex.Unlock()
+			tw.timedOut = true
+
+			if ctx.Err() == context.DeadlineExceeded {
+				logging.FromContext(ctx).Warn("request timed out", "method", r.Method, "path", r.URL.Path, "timeout", timeout.String())
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusGatewayTimeout)
+				json.NewEncoder(w).Encode(map[string]string{
+					"error":          "request timed out",
+					"correlation_id": id,
+				})
+			}
+			// A cancelled context means the client went away; nobody is listening
+		}
+	})
+}
+
+// timeoutWriter buffers a handler's response until it finishes
+type timeoutWriter struct {
+	mutex    sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	code     int
+	wrote    bool
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	if tw.timedOut || tw.wrote {
+		return
+	}
+	tw.wrote = true
+	tw.code = code
+}
+
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wrote = true
+	return tw.body.Write(data)
+}
@@ -0,0 +1,42 @@
+// backend/internal/api/middleware/webhook_events.go
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/eventbus"
+)
+
+// WebhookEventMiddleware publishes a webhook.received event for every webhook
+// to /api/webhooks/{source} that was accepted
+type WebhookEventMiddleware struct {
+	// Events is the tenant's event bus; nil publishes nothing
+	Events *eventbus.Bus
+}
+
+// NewWebhookEventMiddleware creates the middleware for a tenant's bus
+func NewWebhookEventMiddleware(events *eventbus.Bus) *WebhookEventMiddleware {
+	return &WebhookEventMiddleware{Events: events}
+}
+
+// Middleware publishes once the handler has answered with a 2xx
+func (m *WebhookEventMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		if recorder.status < 200 || recorder.status >= 300 {
+			return
+		}
+
+		source := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/webhooks/"), "/")
+		m.Events.Publish(eventbus.Message{
+			Type:   eventbus.TypeWebhookReceived,
+			Source: source,
+			Data: map[string]interface{}{
+				"status":     recorder.status,
+				"request_id": CorrelationID(r.Context()),
+			},
+		})
+	})
+}
@@ -4,53 +4,631 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/shivani-1505/zapier-clone/backend/internal/api/handlers"
+	"github.com/shivani-1505/zapier-clone/backend/internal/api/middleware"
+	"github.com/shivani-1505/zapier-clone/backend/internal/api/webhooks"
+	"github.com/shivani-1505/zapier-clone/backend/internal/apikeys"
+	"github.com/shivani-1505/zapier-clone/backend/internal/audit"
+	"github.com/shivani-1505/zapier-clone/backend/internal/auth"
+	"github.com/shivani-1505/zapier-clone/backend/internal/connections"
+	"github.com/shivani-1505/zapier-clone/backend/internal/consistency"
+	"github.com/shivani-1505/zapier-clone/backend/internal/eventbus"
+	"github.com/shivani-1505/zapier-clone/backend/internal/events"
+	"github.com/shivani-1505/zapier-clone/backend/internal/identity"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/github"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/teams"
+	"github.com/shivani-1505/zapier-clone/backend/internal/jobs"
+	"github.com/shivani-1505/zapier-clone/backend/internal/loopguard"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+	"github.com/shivani-1505/zapier-clone/backend/internal/monitoring"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+	"github.com/shivani-1505/zapier-clone/backend/internal/polling"
+	"github.com/shivani-1505/zapier-clone/backend/internal/reporting"
+	"github.com/shivani-1505/zapier-clone/backend/internal/scoring"
+	"github.com/shivani-1505/zapier-clone/backend/internal/sla"
+	"github.com/shivani-1505/zapier-clone/backend/internal/syncevents"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workflow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workspace"
 )
 
+// RequestTimeouts returns the per-route timeouts: REQUEST_TIMEOUT by default,
+// longer for synchronous syncs and reports, Slack's 3 second ack window, and
+// none for the activity stream
+func RequestTimeouts() *middleware.TimeoutMiddleware {
+	return middleware.NewTimeoutMiddleware().
+		Set("/api/v1/sync/{table}/{sysId}", 30*time.Second).
+		Set("/api/v1/evidence/{table}/{sysId}", 2*time.Minute).
+		Set("/api/admin/servicenow/choices/{table}/sync", 30*time.Second).
+		Set("/api/v1/deadletters/{id}/replay", 30*time.Second).
+		Set("/api/admin/sync/conflicts/{id}/resolve", 30*time.Second).
+		Set("/api/admin/approvals/{id}/decide", 30*time.Second).
+		Set("/api/admin/pollers/{source}/run", 60*time.Second).
+		Set("/api/admin/reconcile/run", 5*time.Minute).
+		Set("/api/v1/compliance/score", 30*time.Second).
+		Set("/api/admin/reports/access-review", 30*time.Second).
+		Set("/api/admin/reports/access-review/send", 60*time.Second).
+		Set("/api/v1/reports/weekly", 60*time.Second).
+		Set("/api/v1/reports/definitions/{id}/run", 60*time.Second).
+		Set("/api/slack/interactions", 3*time.Second).
+		Set("/api/slack/interaction", 3*time.Second).
+		Set("/api/slack/commands", 3*time.Second).
+		Set("/api/v1/stream", 0)
+}
+
+// Dependencies are the clients, handlers and stores of one tenant that the
+// routes are served with. Optional ones may be nil: GitHubIssues and Teams
+// when they are not configured, and the stores that need a database when it
+// is not set.
+type Dependencies struct {
+	ServiceNow        *servicenow.Client
+	Slack             *slack.Client
+	Jira              *jira.Client
+	Teams             *teams.Client
+	Risks             *servicenow.RiskHandler
+	Incidents         *servicenow.IncidentHandler
+	GitHubIssues      *servicenow.GitHubIssues
+	CustomTables      *servicenow.CustomTableHandler
+	RegulatoryChanges *servicenow.RegulatoryChangeHandler
+	Evidence          *servicenow.EvidenceUploader
+	Remediation       *servicenow.RemediationTracker
+	DeletionPolicies  servicenow.DeletionPolicies
+	// Integrations gets the built-in integrations; ones registered before
+	// SetupRoutes are served the same way
+	Integrations *common.Registry
+
+	VolumeDetector *monitoring.VolumeDetector
+	FailureAlerter *monitoring.FailureAlerter
+	DeadLetters    *monitoring.DeadLetterStore
+	SyncHealth     *monitoring.SyncHealth
+	LoopGuard      *loopguard.Guard
+	Poller         *polling.Poller
+	Reconciler     *consistency.Reconciler
+	Conflicts      *consistency.ConflictDetector
+	Jobs           *jobs.Queue
+	SyncEvents     *syncevents.Hub
+	EventBus       *eventbus.Bus
+	EventSchemas   *events.Registry
+
+	Auth          *auth.Service
+	APIKeys       *apikeys.Store
+	Identities    *identity.Resolver
+	Workspaces    *workspace.Store
+	Workflows     *workflow.Store
+	Connections   *connections.Manager
+	Notifications *notification.Router
+	SLA           *sla.Tracker
+	Scoring       *scoring.Engine
+	AuditLog      *audit.Log
+
+	AccessReviewer    *reporting.AccessReviewer
+	WeeklyReporter    *reporting.WeeklyReporter
+	ReportDefinitions *reporting.ReportDefinitions
+	ReportScheduler   *reporting.ReportScheduler
+}
+
 // SetupRoutes configures all the API routes for the application
-func SetupRoutes(r *mux.Router, serviceNowClient *servicenow.Client, slackClient *slack.Client, jiraClient *jira.Client, riskHandler *servicenow.RiskHandler, incidentHandler *servicenow.IncidentHandler) {
+func SetupRoutes(r *mux.Router, deps Dependencies) {
+	// Bound every request and give it a correlation ID
+	r.Use(RequestTimeouts().Middleware)
+	r.Use(middleware.NewLoggingMiddleware().Middleware)
+	// Frontend APIs need an access token, and the admin APIs an administrator's
+	r.Use(middleware.NewJWTMiddleware(deps.Auth).Middleware)
+
 	// Create handlers
 	serviceNowWebhookHandler := handlers.NewServiceNowWebhookHandler(
-		serviceNowClient,
-		slackClient,
-		jiraClient,
+		deps.ServiceNow,
+		deps.Slack,
+		deps.Jira,
+		deps.Risks,
 	)
+	// Built-in flows show up in the workflow execution history
+	serviceNowWebhookHandler.Executions = deps.Workflows
+	// Share the incident handler so webhooks use the same mappings as Slack
+	serviceNowWebhookHandler.IncidentHandler = deps.Incidents
+	serviceNowWebhookHandler.Threads = deps.Risks.Threads
+	// New regulatory changes get a Jira epic with impact assessment subtasks
+	if deps.RegulatoryChanges != nil {
+		serviceNowWebhookHandler.RegulatoryChangeHandler = deps.RegulatoryChanges
+	}
+	// New records are announced in the channels the notification rules pick
+	serviceNowWebhookHandler.ComplianceHandler.Routes = deps.Notifications
+	serviceNowWebhookHandler.ControlTestHandler.Routes = deps.Notifications
+	serviceNowWebhookHandler.AuditHandler.Routes = deps.Notifications
+	// Finding announcements open the thread remediation progress is posted to
+	serviceNowWebhookHandler.AuditHandler.Threads = deps.Risks.Threads
+	serviceNowWebhookHandler.VendorRiskHandler.Routes = deps.Notifications
+	serviceNowWebhookHandler.RegulatoryChangeHandler.Routes = deps.Notifications
+	// Audit findings and compliance tasks are filed as GitHub issues when a repository is configured
+	serviceNowWebhookHandler.AuditHandler.GitHub = deps.GitHubIssues
+	serviceNowWebhookHandler.AuditHandler.Projects = deps.Risks.Projects
+	// Findings that may duplicate an existing issue are linked or held for a decision like risks
+	serviceNowWebhookHandler.AuditHandler.Duplicates = deps.Risks.Duplicates
+	serviceNowWebhookHandler.AuditHandler.Approvals = deps.Risks.Approvals
+	serviceNowWebhookHandler.ComplianceHandler.GitHub = deps.GitHubIssues
+	// Tables registered through /api/v1/tables are synced with their own field maps
+	serviceNowWebhookHandler.CustomTables = deps.CustomTables
 	slackInteractionHandler := handlers.NewSlackInteractionHandler(
-		serviceNowClient,
-		slackClient,
-		jiraClient,
-		incidentHandler,
+		deps.ServiceNow,
+		deps.Slack,
+		deps.Jira,
+		deps.Risks,
+		deps.Incidents,
 	)
 	slackCommandHandler := handlers.NewSlackCommandHandler(
-		serviceNowClient,
-		slackClient,
-		jiraClient,
-		riskHandler,
+		deps.ServiceNow,
+		deps.Slack,
+		deps.Jira,
+		deps.Risks,
 	)
 	jiraWebhookHandler := handlers.NewJiraWebhookHandler(
-		serviceNowClient,
-		slackClient,
-		jiraClient,
+		deps.ServiceNow,
+		deps.Slack,
+		deps.Jira,
+	)
+	jiraWebhookHandler.RegulatoryChanges = deps.RegulatoryChanges
+	// /upload-evidence attaches files to control tests and compliance tasks
+	slackCommandHandler.ComplianceHandler.Evidence = deps.Evidence
+	// Remediation milestones are added from Slack and completed from Jira sub-tasks
+	jiraWebhookHandler.Remediation = deps.Remediation
+	if deps.Remediation != nil {
+		if err := deps.Remediation.RegisterCommands(slackCommandHandler.Router); err != nil {
+			slog.Warn("error registering remediation commands", "error", err)
+		}
+	}
+	gitHubWebhookHandler := handlers.NewGitHubWebhookHandler(deps.GitHubIssues)
+	slackChannelHandler := handlers.NewSlackChannelHandler(deps.Slack)
+	syncLoopHandler := handlers.NewSyncLoopHandler(deps.LoopGuard)
+	syncFailureHandler := handlers.NewSyncFailureHandler(deps.FailureAlerter)
+	pollerHandler := handlers.NewPollerHandler(deps.Poller)
+	reconcileHandler := handlers.NewReconcileHandler(deps.Reconciler)
+	notificationRuleHandler := handlers.NewNotificationRuleHandler(deps.Notifications)
+	integrationHandler := handlers.NewIntegrationHandler(deps.Integrations)
+	deadLetterHandler := handlers.NewDeadLetterHandler(deps.DeadLetters, map[string]handlers.Replayer{
+		"servicenow": serviceNowWebhookHandler.Replay,
+	})
+	conflictHandler := handlers.NewConflictHandler(deps.Conflicts, map[string]handlers.Replayer{
+		consistency.SideServiceNow: serviceNowWebhookHandler.Replay,
+		consistency.SideJira:       jiraWebhookHandler.Replay,
+	})
+	approvalHandler := handlers.NewApprovalHandler(deps.Risks.Approvals, deps.Slack, deps.Risks, deps.Incidents, deps.Workflows)
+	approvalHandler.AuditHandler = serviceNowWebhookHandler.AuditHandler
+	accessReviewHandler := handlers.NewAccessReviewHandler(deps.AccessReviewer)
+	reportHandler := handlers.NewReportHandler(deps.WeeklyReporter, deps.ReportDefinitions, deps.ReportScheduler)
+	auditLogHandler := handlers.NewAuditLogHandler(deps.AuditLog)
+	apiKeyHandler := handlers.NewAPIKeyHandler(deps.APIKeys)
+	streamHandler := handlers.NewStreamHandler(deps.EventBus)
+	var gitHubIssueMapping *github.IssueMapping
+	if deps.GitHubIssues != nil {
+		gitHubIssueMapping = deps.GitHubIssues.Mapping
+	}
+	syncHealthHandler := handlers.NewSyncHealthHandler(deps.SyncHealth, deps.Risks.RiskJiraMapping, deps.Incidents.IncidentJiraMapping, gitHubIssueMapping, deps.Jobs, serviceNowWebhookHandler.Settler, deps.DeadLetters)
+	jiraProjectHandler := handlers.NewJiraProjectHandler(deps.Risks.Projects)
+	jiraCacheHandler := handlers.NewJiraCacheHandler(deps.Jira)
+	complianceScoreHandler := handlers.NewComplianceScoreHandler(deps.Scoring)
+	riskMatrixHandler := handlers.NewRiskMatrixHandler(deps.Risks.Matrix)
+	var tableRegistry *servicenow.TableRegistry
+	if deps.CustomTables != nil {
+		tableRegistry = deps.CustomTables.Tables
+	}
+	tableHandler := handlers.NewTableHandler(tableRegistry)
+	evidenceHandler := handlers.NewEvidenceHandler(deps.Evidence)
+	remediationHandler := handlers.NewRemediationHandler(deps.Remediation)
+	serviceNowChoiceHandler := handlers.NewServiceNowChoiceHandler(deps.ServiceNow.Choices)
+	proxyHandler := handlers.NewProxyHandler(deps.ServiceNow, deps.Jira)
+	workspaceHandler := handlers.NewWorkspaceHandler(deps.Workspaces)
+	workflowHandler := handlers.NewWorkflowHandler(deps.Workflows, deps.Workspaces)
+	connectionHandler := handlers.NewConnectionHandler(deps.Connections)
+	if deps.Connections != nil {
+		workflowHandler.Connections = deps.Connections.Store
+	}
+	authHandler := handlers.NewAuthHandler(deps.Auth)
+	var identityStore *identity.Store
+	if deps.Identities != nil {
+		identityStore = deps.Identities.Store
+	}
+	identityHandler := handlers.NewIdentityHandler(identityStore, deps.Identities)
+	slaHandler := handlers.NewSLAHandler(deps.SLA)
+	escalationHandler := handlers.NewEscalationHandler(deps.Incidents.Escalations)
+	warRoomHandler := handlers.NewWarRoomHandler(deps.Incidents.WarRooms)
+	reviewHandler := handlers.NewPostIncidentReviewHandler(deps.Incidents.Reviews)
+	webhookIngestor := webhooks.NewDefaultIngestor(deps.Slack)
+	webhookIngestor.Schemas = deps.EventSchemas
+	eventSchemaHandler := handlers.NewEventSchemaHandler(deps.EventSchemas)
+	relayHandler := handlers.NewRelayHandler(map[string]http.HandlerFunc{
+		"servicenow": serviceNowWebhookHandler.HandleWebhook,
+		"jira":       jiraWebhookHandler.HandleWebhook,
+	})
+
+	// Process webhooks and interactions on the job pool so shutdown can drain them
+	if deps.Jobs != nil {
+		serviceNowWebhookHandler.RegisterJobs(deps.Jobs)
+		jiraWebhookHandler.RegisterJobs(deps.Jobs)
+		gitHubWebhookHandler.RegisterJobs(deps.Jobs)
+		slackInteractionHandler.RegisterJobs(deps.Jobs)
+		webhookIngestor.RegisterJobs(deps.Jobs)
+	}
+
+	// Feed inbound webhook volume into the anomaly detector
+	serviceNowWebhookHandler.VolumeDetector = deps.VolumeDetector
+	jiraWebhookHandler.VolumeDetector = deps.VolumeDetector
+	gitHubWebhookHandler.VolumeDetector = deps.VolumeDetector
+	webhookIngestor.VolumeDetector = deps.VolumeDetector
+
+	// Alert the ops channel when a webhook cannot be synced
+	serviceNowWebhookHandler.FailureAlerter = deps.FailureAlerter
+	jiraWebhookHandler.FailureAlerter = deps.FailureAlerter
+	gitHubWebhookHandler.FailureAlerter = deps.FailureAlerter
+
+	// Keep failed ServiceNow webhooks so operators can replay them
+	serviceNowWebhookHandler.DeadLetters = deps.DeadLetters
+
+	// Guard both webhook paths against Jira↔ServiceNow update loops
+	serviceNowWebhookHandler.LoopGuard = deps.LoopGuard
+	jiraWebhookHandler.LoopGuard = deps.LoopGuard
+
+	// Settle edits of the same field made on both sides at once
+	serviceNowWebhookHandler.Conflicts = deps.Conflicts
+	jiraWebhookHandler.Conflicts = deps.Conflicts
+	slackInteractionHandler.Conflicts = conflictHandler
+
+	// Publish the outcome of every synced webhook to event subscribers
+	serviceNowWebhookHandler.Events = deps.SyncEvents
+	jiraWebhookHandler.Events = deps.SyncEvents
+
+	// Create held Jira issues once approved in Slack
+	slackInteractionHandler.Approvals = approvalHandler
+
+	// Decide what happens to ServiceNow records whose Jira issue is deleted
+	jiraWebhookHandler.DeletionHandler = servicenow.NewJiraDeletionHandler(
+		deps.ServiceNow,
+		deps.Slack,
+		deps.Jira,
+		deps.Risks.RiskJiraMapping,
+		deps.DeletionPolicies,
+	)
+	jiraWebhookHandler.DeletionHandler.IncidentJiraMapping = deps.Incidents.IncidentJiraMapping
+
+	// Keep links working when a Jira issue is moved to another project and gets a new key
+	jiraWebhookHandler.MoveHandler = servicenow.NewJiraMoveHandler(
+		deps.ServiceNow,
+		deps.Risks.RiskJiraMapping,
+		deps.Incidents.IncidentJiraMapping,
 	)
 
-	// ServiceNow webhook endpoints
-	r.HandleFunc("/api/webhooks/servicenow", serviceNowWebhookHandler.HandleWebhook).Methods("POST")
+	// Mirror comments between Jira issues and their ServiceNow records
+	commentSync := servicenow.NewCommentSync(
+		deps.ServiceNow,
+		deps.Jira,
+		deps.Risks.RiskJiraMapping,
+		deps.Incidents.IncidentJiraMapping,
+	)
+	serviceNowWebhookHandler.CommentSync = commentSync
+	jiraWebhookHandler.CommentSync = commentSync
+
+	// Translate assignees between ServiceNow, Jira and Slack when identities are kept in the database
+	if deps.Identities != nil {
+		deps.Risks.Identities = deps.Identities
+		deps.Incidents.Identities = deps.Identities
+		serviceNowWebhookHandler.AuditHandler.Identities = deps.Identities
+		slackInteractionHandler.AuditHandler.Identities = deps.Identities
+		slackInteractionHandler.ComplianceHandler.Identities = deps.Identities
+		if deps.CustomTables != nil {
+			deps.CustomTables.Identities = deps.Identities
+		}
+		if deps.RegulatoryChanges != nil {
+			deps.RegulatoryChanges.Identities = deps.Identities
+		}
+		if deps.Remediation != nil {
+			deps.Remediation.Identities = deps.Identities
+		}
+
+		// @-mention mapped assignees, and DM new risk owners when SLACK_DM_ASSIGNEES=true
+		assignees := servicenow.NewAssigneeNotifier(deps.Identities)
+		assignees.ConfigureFromEnv()
+		deps.Risks.Assignees = assignees
+		if deps.Risks.Threads != nil {
+			deps.Risks.Threads.Assignees = assignees
+		}
+	}
+
+	// Poll ServiceNow tables when SERVICENOW_POLL_INTERVAL is set, for instances
+	// whose webhooks cannot reach us; polled records go through the webhook path
+	if schedule, ok := servicenow.PollScheduleFromEnv(); ok {
+		for _, table := range servicenow.GRCTables {
+			deps.Poller.Register("servicenow:"+table, servicenow.NewTablePollSource(deps.ServiceNow, table), serviceNowWebhookHandler.PollHandler(table), schedule)
+		}
+		// Tables registered later are polled from the next restart
+		for _, table := range tableRegistry.List() {
+			deps.Poller.Register("servicenow:"+table.Name, servicenow.NewTablePollSource(deps.ServiceNow, table.Name), serviceNowWebhookHandler.PollHandler(table.Name), schedule)
+		}
+	}
+
+	// Slack channel diagnostics
+	r.HandleFunc("/api/admin/slack/channels", slackChannelHandler.HandleChannelReport).Methods("GET")
+	r.HandleFunc("/api/admin/slack/usergroups", slackChannelHandler.HandleUserGroupReport).Methods("GET")
+
+	// Sync health for the ops dashboard
+	r.HandleFunc("/api/admin/sync/health", syncHealthHandler.HandleGetSyncHealth).Methods("GET")
+	r.HandleFunc("/api/admin/sync/health/mappings", syncHealthHandler.HandleGetMappingCounts).Methods("GET")
+	r.HandleFunc("/api/admin/sync/health/integrations", syncHealthHandler.HandleGetIntegrationsHealth).Methods("GET")
+	r.HandleFunc("/api/admin/sync/health/queues", syncHealthHandler.HandleGetQueueDepth).Methods("GET")
+	r.HandleFunc("/api/admin/jira/projects", jiraProjectHandler.HandleListProvisioned).Methods("GET")
+	r.HandleFunc("/api/admin/jira/cache", jiraCacheHandler.HandleGetCache).Methods("GET")
+	r.HandleFunc("/api/admin/jira/cache", jiraCacheHandler.HandleInvalidateCache).Methods("DELETE")
+
+	// Sync loop guard
+	r.HandleFunc("/api/admin/sync/loops", syncLoopHandler.HandleListLoops).Methods("GET")
+	r.HandleFunc("/api/admin/sync/loops/{entity}", syncLoopHandler.HandleResetLoop).Methods("DELETE")
+
+	// Conflicting Jira and ServiceNow edits
+	r.HandleFunc("/api/admin/sync/conflicts", conflictHandler.HandleListConflicts).Methods("GET")
+	r.HandleFunc("/api/admin/sync/conflicts/{id}", conflictHandler.HandleGetConflict).Methods("GET")
+	r.HandleFunc("/api/admin/sync/conflicts/{id}/resolve", conflictHandler.HandleResolveConflict).Methods("POST")
+
+	// Jira issues held for approval
+	r.HandleFunc("/api/admin/approvals", approvalHandler.HandleListApprovals).Methods("GET")
+	r.HandleFunc("/api/admin/approvals/{id}", approvalHandler.HandleGetApproval).Methods("GET")
+	r.HandleFunc("/api/admin/approvals/{id}/decide", approvalHandler.HandleDecideApproval).Methods("POST")
+
+	// Full events behind sync failure alerts
+	r.HandleFunc("/api/admin/events/failures", syncFailureHandler.HandleListFailures).Methods("GET")
+	r.HandleFunc("/api/admin/events/failures/{id}", syncFailureHandler.HandleGetFailure).Methods("GET")
+
+	// Polling sources
+	r.HandleFunc("/api/admin/pollers", pollerHandler.HandleListPollers).Methods("GET")
+	r.HandleFunc("/api/admin/pollers/{source}/run", pollerHandler.HandleRunPoller).Methods("POST")
+	r.HandleFunc("/api/admin/pollers/{source}/watermark", pollerHandler.HandleResetWatermark).Methods("DELETE")
+
+	// Jira/ServiceNow drift reconciliation
+	r.HandleFunc("/api/admin/reconcile", reconcileHandler.HandleGetReport).Methods("GET")
+	r.HandleFunc("/api/admin/reconcile/run", reconcileHandler.HandleRun).Methods("POST")
+
+	// Slack notification routing rules
+	r.HandleFunc("/api/v1/notification-rules", notificationRuleHandler.HandleListRules).Methods("GET")
+	r.HandleFunc("/api/v1/notification-rules", notificationRuleHandler.HandleCreateRule).Methods("POST")
+	r.HandleFunc("/api/v1/notification-rules/preview", notificationRuleHandler.HandlePreviewRoute).Methods("GET")
+	r.HandleFunc("/api/v1/notification-rules/channels", notificationRuleHandler.HandleListChannels).Methods("GET")
+	r.HandleFunc("/api/v1/notification-rules/{id}", notificationRuleHandler.HandleGetRule).Methods("GET")
+	r.HandleFunc("/api/v1/notification-rules/{id}", notificationRuleHandler.HandleUpdateRule).Methods("PUT")
+	r.HandleFunc("/api/v1/notification-rules/{id}", notificationRuleHandler.HandleDeleteRule).Methods("DELETE")
+
+	// ServiceNow tables synced besides the built-in ones
+	r.HandleFunc("/api/v1/tables", tableHandler.HandleListTables).Methods("GET")
+	r.HandleFunc("/api/v1/tables", tableHandler.HandleRegisterTable).Methods("POST")
+	r.HandleFunc("/api/v1/tables/{name}", tableHandler.HandleGetTable).Methods("GET")
+	r.HandleFunc("/api/v1/tables/{name}", tableHandler.HandleUpdateTable).Methods("PUT")
+	r.HandleFunc("/api/v1/tables/{name}", tableHandler.HandleDeleteTable).Methods("DELETE")
+
+	// People's ServiceNow, Jira and Slack accounts
+	r.HandleFunc("/api/v1/identities", identityHandler.HandleListIdentities).Methods("GET")
+	r.HandleFunc("/api/v1/identities", identityHandler.HandleCreateIdentity).Methods("POST")
+	r.HandleFunc("/api/v1/identities/match", identityHandler.HandleMatchIdentity).Methods("POST")
+	r.HandleFunc("/api/v1/identities/{id}", identityHandler.HandleGetIdentity).Methods("GET")
+	r.HandleFunc("/api/v1/identities/{id}", identityHandler.HandleUpdateIdentity).Methods("PUT")
+	r.HandleFunc("/api/v1/identities/{id}", identityHandler.HandleDeleteIdentity).Methods("DELETE")
+
+	// API keys for webhook senders; administrators only
+	r.HandleFunc("/api/v1/apikeys", apiKeyHandler.HandleListAPIKeys).Methods("GET")
+	r.HandleFunc("/api/v1/apikeys", apiKeyHandler.HandleCreateAPIKey).Methods("POST")
+	r.HandleFunc("/api/v1/apikeys/{id}", apiKeyHandler.HandleGetAPIKey).Methods("GET")
+	r.HandleFunc("/api/v1/apikeys/{id}", apiKeyHandler.HandleUpdateAPIKey).Methods("PUT")
+	r.HandleFunc("/api/v1/apikeys/{id}", apiKeyHandler.HandleRevokeAPIKey).Methods("DELETE")
+
+	// Incidents past their SLA
+	r.HandleFunc("/api/v1/sla/breaches", slaHandler.HandleListBreaches).Methods("GET")
+
+	// Escalation of unacknowledged incidents
+	r.HandleFunc("/api/v1/escalations", escalationHandler.HandleListEscalations).Methods("GET")
+	r.HandleFunc("/api/v1/escalation-policies", escalationHandler.HandleListPolicies).Methods("GET")
+	r.HandleFunc("/api/v1/escalation-policies", escalationHandler.HandleCreatePolicy).Methods("POST")
+	r.HandleFunc("/api/v1/escalation-policies/{id}", escalationHandler.HandleGetPolicy).Methods("GET")
+	r.HandleFunc("/api/v1/escalation-policies/{id}", escalationHandler.HandleUpdatePolicy).Methods("PUT")
+	r.HandleFunc("/api/v1/escalation-policies/{id}", escalationHandler.HandleDeletePolicy).Methods("DELETE")
+	r.HandleFunc("/api/v1/war-rooms", warRoomHandler.HandleListWarRooms).Methods("GET")
+	r.HandleFunc("/api/v1/war-rooms/{sysId}", warRoomHandler.HandleGetWarRoom).Methods("GET")
+	r.HandleFunc("/api/v1/post-incident-reviews", reviewHandler.HandleListReviews).Methods("GET")
+	r.HandleFunc("/api/v1/post-incident-reviews/{sysId}", reviewHandler.HandleGetReview).Methods("GET")
+	r.HandleFunc("/api/v1/post-incident-reviews/{sysId}", reviewHandler.HandleGenerateReview).Methods("POST")
+
+	// ServiceNow choice lists
+	r.HandleFunc("/api/admin/servicenow/choices/{table}", serviceNowChoiceHandler.HandleGetChoices).Methods("GET")
+	r.HandleFunc("/api/admin/servicenow/choices/{table}/sync", serviceNowChoiceHandler.HandleSyncChoices).Methods("POST")
+
+	// Read-through proxy for the frontend - signed in like the rest of /api/v1, and rate limited
+	proxy := r.PathPrefix("/api/v1/proxy").Subrouter()
+	proxy.Use(middleware.NewRateLimitMiddleware(5, 20).Middleware)
+	proxy.HandleFunc("/jira/issue/{key}", proxyHandler.HandleJiraIssue).Methods("GET")
+	proxy.HandleFunc("/servicenow/{table}/{id}", proxyHandler.HandleServiceNowRecord).Methods("GET")
 
-	// Slack interaction endpoints
-	r.HandleFunc("/api/slack/interactions", slackInteractionHandler.HandleInteraction).Methods("POST")
+	// Frontend sign-in with rotating refresh tokens
+	r.HandleFunc("/api/v1/auth/register", authHandler.HandleRegister).Methods("POST")
+	r.HandleFunc("/api/v1/auth/login", authHandler.HandleLogin).Methods("POST")
+	r.HandleFunc("/api/v1/auth/refresh", authHandler.HandleRefresh).Methods("POST")
+	r.HandleFunc("/api/v1/auth/logout", authHandler.HandleLogout).Methods("POST")
+	r.HandleFunc("/api/v1/user/profile", authHandler.HandleGetProfile).Methods("GET")
+	r.HandleFunc("/api/v1/user/profile", authHandler.HandleUpdateProfile).Methods("PUT")
+	r.HandleFunc("/api/v1/user/password", authHandler.HandleChangePassword).Methods("PUT")
 
-	// Slack command endpoints
-	r.HandleFunc("/api/slack/commands", slackCommandHandler.HandleCommand).Methods("POST")
-	r.HandleFunc("/api/slack/interaction", slackInteractionHandler.HandleInteraction).Methods("POST")
+	// Stored workflow definitions
+	r.HandleFunc("/api/v1/workflows", workflowHandler.HandleListWorkflows).Methods("GET")
+	r.HandleFunc("/api/v1/workflows", workflowHandler.HandleCreateWorkflow).Methods("POST")
+	r.HandleFunc("/api/v1/workflows/{id}", workflowHandler.HandleGetWorkflow).Methods("GET")
+	r.HandleFunc("/api/v1/workflows/{id}", workflowHandler.HandleUpdateWorkflow).Methods("PUT")
+	r.HandleFunc("/api/v1/workflows/{id}", workflowHandler.HandleDeleteWorkflow).Methods("DELETE")
+	r.HandleFunc("/api/v1/workflows/{id}/activate", workflowHandler.HandleActivateWorkflow).Methods("POST")
+	r.HandleFunc("/api/v1/workflows/{id}/deactivate", workflowHandler.HandleDeactivateWorkflow).Methods("POST")
+	r.HandleFunc("/api/v1/workflows/{id}/environment", workflowHandler.HandleSetEnvironment).Methods("PUT")
 
-	// Jira webhook endpoints
-	r.HandleFunc("/api/webhooks/jira", jiraWebhookHandler.HandleWebhook).Methods("POST")
+	// Team workspaces and workflow sharing
+	r.HandleFunc("/api/v1/workspaces", workspaceHandler.HandleListWorkspaces).Methods("GET")
+	r.HandleFunc("/api/v1/workspaces", workspaceHandler.HandleCreateWorkspace).Methods("POST")
+	r.HandleFunc("/api/v1/workspaces/{id}/members", workspaceHandler.HandleListMembers).Methods("GET")
+	r.HandleFunc("/api/v1/workspaces/{id}/members/{userId}", workspaceHandler.HandleSetMember).Methods("PUT")
+	r.HandleFunc("/api/v1/workspaces/{id}/members/{userId}", workspaceHandler.HandleRemoveMember).Methods("DELETE")
+	r.HandleFunc("/api/v1/workspaces/{id}/usage", workspaceHandler.HandleGetUsage).Methods("GET")
+	r.HandleFunc("/api/v1/workspaces/{id}/quota", workspaceHandler.HandleSetQuota).Methods("PUT")
+	r.HandleFunc("/api/v1/workspaces/{id}/executions", workspaceHandler.HandleListExecutions).Methods("GET")
+	r.HandleFunc("/api/v1/workspaces/{id}/executions/{executionId}", workflowHandler.HandleGetExecution).Methods("GET")
+	r.HandleFunc("/api/v1/workflows/{id}/executions", workflowHandler.HandleListExecutions).Methods("GET")
+	r.HandleFunc("/api/v1/executions/{id}", workflowHandler.HandleGetExecutionByID).Methods("GET")
+	r.HandleFunc("/api/v1/workflows/{id}/shares", workspaceHandler.HandleListShares).Methods("GET")
+	r.HandleFunc("/api/v1/workflows/{id}/shares", workspaceHandler.HandleShareWorkflow).Methods("POST")
+	r.HandleFunc("/api/v1/workflows/{id}/shares/{workspaceId}", workspaceHandler.HandleUnshareWorkflow).Methods("DELETE")
+
+	// Per-user service connections (API keys and OAuth)
+	r.HandleFunc("/api/v1/connections", connectionHandler.HandleListConnections).Methods("GET")
+	r.HandleFunc("/api/v1/connections", connectionHandler.HandleCreateConnection).Methods("POST")
+	r.HandleFunc("/api/v1/connections/providers", connectionHandler.HandleListProviders).Methods("GET")
+	r.HandleFunc("/api/v1/connections/oauth/{service}/url", connectionHandler.HandleOAuthURL).Methods("GET")
+	r.HandleFunc("/api/v1/connections/{id:[0-9]+}", connectionHandler.HandleGetConnection).Methods("GET")
+	r.HandleFunc("/api/v1/connections/{id:[0-9]+}", connectionHandler.HandleUpdateConnection).Methods("PUT")
+	r.HandleFunc("/api/v1/connections/{id:[0-9]+}", connectionHandler.HandleDeleteConnection).Methods("DELETE")
+	r.HandleFunc("/api/v1/connections/{id:[0-9]+}/revoke", connectionHandler.HandleRevokeConnection).Methods("POST")
+	r.HandleFunc("/api/v1/connections/{id:[0-9]+}/test", connectionHandler.HandleTestConnection).Methods("POST")
+	r.HandleFunc("/api/v1/auth/oauth/callback/{service}", connectionHandler.HandleOAuthCallback).Methods("POST")
+
+	// Failed ServiceNow webhooks and their replay
+	r.HandleFunc("/api/v1/deadletters", deadLetterHandler.HandleListDeadLetters).Methods("GET")
+	r.HandleFunc("/api/v1/deadletters/{id}", deadLetterHandler.HandleGetDeadLetter).Methods("GET")
+	r.HandleFunc("/api/v1/deadletters/{id}/replay", deadLetterHandler.HandleReplayDeadLetter).Methods("POST")
+
+	// Manual per-record sync
+	r.HandleFunc("/api/v1/sync/{table}/{sysId}", serviceNowWebhookHandler.HandleManualSync).Methods("POST")
+
+	// Control test and compliance task evidence
+	r.HandleFunc("/api/v1/evidence/{table}/{sysId}", evidenceHandler.HandleUpload).Methods("POST")
+
+	// Audit finding remediation milestones
+	r.HandleFunc("/api/v1/remediation", remediationHandler.HandleListPlans).Methods("GET")
+	r.HandleFunc("/api/v1/findings/{sysId}/remediation", remediationHandler.HandleGetPlan).Methods("GET")
+	r.HandleFunc("/api/v1/findings/{sysId}/milestones", remediationHandler.HandleAddMilestone).Methods("POST")
+	r.HandleFunc("/api/v1/findings/{sysId}/milestones/{id}", remediationHandler.HandleUpdateMilestone).Methods("PATCH")
+
+	// Compliance score
+	r.HandleFunc("/api/v1/compliance/score", complianceScoreHandler.HandleGetScore).Methods("GET")
+	r.HandleFunc("/api/v1/compliance/score/history", complianceScoreHandler.HandleGetHistory).Methods("GET")
+	r.HandleFunc("/api/v1/compliance/score/weights", complianceScoreHandler.HandleGetWeights).Methods("GET")
+	r.HandleFunc("/api/v1/compliance/score/weights", complianceScoreHandler.HandleSetWeights).Methods("PUT")
+	r.HandleFunc("/api/v1/compliance/risk-matrix", riskMatrixHandler.HandleGetMatrix).Methods("GET")
+	r.HandleFunc("/api/v1/compliance/risk-matrix", riskMatrixHandler.HandleSetMatrix).Methods("PUT")
+	r.HandleFunc("/api/v1/compliance/risk-matrix", riskMatrixHandler.HandleResetMatrix).Methods("DELETE")
+	r.HandleFunc("/api/v1/compliance/risk-matrix/assess", riskMatrixHandler.HandleAssess).Methods("POST")
+
+	// Access review reports
+	r.HandleFunc("/api/admin/reports/access-review", accessReviewHandler.HandleAccessReview).Methods("GET")
+	r.HandleFunc("/api/admin/reports/access-review/send", accessReviewHandler.HandleSendAccessReview).Methods("POST")
+
+	// Weekly compliance summaries
+	r.HandleFunc("/api/v1/reports", reportHandler.HandleListReports).Methods("GET")
+	r.HandleFunc("/api/v1/reports/weekly", reportHandler.HandleSendWeeklyReport).Methods("POST")
+
+	// Report schedules and user-defined reports, registered before
+	// /api/v1/reports/{id} so "schedule" and "definitions" aren't taken for report IDs
+	r.HandleFunc("/api/v1/reports/schedule", reportHandler.HandleListSchedules).Methods("GET")
+	r.HandleFunc("/api/v1/reports/definitions", reportHandler.HandleListDefinitions).Methods("GET")
+	r.HandleFunc("/api/v1/reports/definitions", reportHandler.HandleCreateDefinition).Methods("POST")
+	r.HandleFunc("/api/v1/reports/definitions/{id}", reportHandler.HandleGetDefinition).Methods("GET")
+	r.HandleFunc("/api/v1/reports/definitions/{id}", reportHandler.HandleUpdateDefinition).Methods("PUT")
+	r.HandleFunc("/api/v1/reports/definitions/{id}", reportHandler.HandleDeleteDefinition).Methods("DELETE")
+	r.HandleFunc("/api/v1/reports/definitions/{id}/run", reportHandler.HandleRunDefinition).Methods("POST")
+	r.HandleFunc("/api/v1/reports/{id}", reportHandler.HandleGetReport).Methods("GET")
+
+	// Audit log of outbound modifying calls
+	r.HandleFunc("/api/v1/audit", auditLogHandler.HandleListAuditLog).Methods("GET")
+	r.HandleFunc("/api/v1/audit/verify", auditLogHandler.HandleVerifyAuditLog).Methods("GET")
+
+	// Live integration activity over WebSocket
+	r.HandleFunc("/api/v1/stream", streamHandler.HandleStream).Methods("GET")
+
+	// Built-in integrations. ServiceNow webhooks are verified with the shared
+	// webhook token, Jira and GitHub webhooks with their webhook secrets,
+	// Slack callbacks with the Slack signing secret and Teams bot requests
+	// with the Bot Connector's signed token; GitHub and Teams are only added
+	// when configured. Integrations registered before SetupRoutes are served
+	// the same way.
+	serviceNowSignature := middleware.NewServiceNowSignatureMiddleware()
+	jiraSignature := middleware.NewJiraSignatureMiddleware()
+	slackSignature := middleware.NewSlackSignatureMiddleware()
+	builtins := []common.Integration{
+		servicenow.NewIntegration(deps.ServiceNow, serviceNowSignature.Middleware(http.HandlerFunc(serviceNowWebhookHandler.HandleWebhook))),
+		jira.NewIntegration(deps.Jira, jiraSignature.Middleware(http.HandlerFunc(jiraWebhookHandler.HandleWebhook))),
+		slack.NewIntegration(deps.Slack,
+			slackSignature.Middleware(http.HandlerFunc(slackInteractionHandler.HandleInteraction)),
+			slackSignature.Middleware(http.HandlerFunc(slackCommandHandler.HandleCommand)),
+		),
+	}
+	if deps.GitHubIssues != nil {
+		gitHubSignature := middleware.NewGitHubSignatureMiddleware()
+		builtins = append(builtins, github.NewIntegration(deps.GitHubIssues.GitHubClient, gitHubSignature.Middleware(http.HandlerFunc(gitHubWebhookHandler.HandleWebhook))))
+	}
+	if deps.Teams != nil {
+		teamsBotHandler := handlers.NewTeamsBotHandler(slackInteractionHandler)
+		teamsSignature := middleware.NewSignatureMiddleware("Teams", teams.NewVerifier(deps.Teams.AppID))
+		builtins = append(builtins, teams.NewIntegration(deps.Teams, teamsSignature.Middleware(http.HandlerFunc(teamsBotHandler.HandleActivity))))
+	}
+	for _, integration := range builtins {
+		if err := deps.Integrations.Register(integration); err != nil {
+			slog.Warn("error registering integration", "integration", integration.Name(), "error", err)
+		}
+	}
+
+	// Stored workflows run on the integrations' webhooks, with the
+	// integrations' actions as their steps
+	if deps.Workflows != nil {
+		dispatcher := workflow.NewDispatcher(deps.Workflows, deps.Integrations)
+		serviceNowWebhookHandler.Workflows = dispatcher
+		jiraWebhookHandler.Workflows = dispatcher
+		gitHubWebhookHandler.Workflows = dispatcher
+	}
+
+	// Webhook senders authenticate with the API keys issued for their source,
+	// checked before each source's own signature
+	webhookKeys := middleware.NewWebhookKeyMiddleware(deps.APIKeys)
+	// Accepted webhooks show up in the activity stream
+	webhookEvents := middleware.NewWebhookEventMiddleware(deps.EventBus)
+
+	// Integration webhook endpoints; each handler verifies its own requests
+	for _, trigger := range deps.Integrations.Triggers() {
+		r.Handle("/api/webhooks/"+trigger.Name(), webhookKeys.Middleware(webhookEvents.Middleware(trigger.WebhookHandler()))).Methods("POST")
+	}
+
+	// Endpoints integrations serve themselves, such as Slack's callbacks
+	for _, route := range deps.Integrations.Routes() {
+		r.Handle(route.Path, route.Handler).Methods(route.Method)
+	}
+
+	// Registered integrations, their actions and connection checks
+	r.HandleFunc("/api/v1/integrations", integrationHandler.HandleListIntegrations).Methods("GET")
+	r.HandleFunc("/api/v1/integrations/{name}/validate", integrationHandler.HandleValidateIntegration).Methods("POST")
+	r.HandleFunc("/api/v1/integrations/{name}/actions/{action}", integrationHandler.HandleExecuteAction).Methods("POST")
+
+	// Generic webhook ingestion for every other source; registered after the
+	// integration routes so those keep their own handlers
+	r.Handle("/api/webhooks/{source}", webhookKeys.Middleware(webhookEvents.Middleware(http.HandlerFunc(webhookIngestor.HandleWebhook)))).Methods("POST")
+	r.HandleFunc("/api/admin/webhooks/routes", webhookIngestor.HandleListRoutes).Methods("GET")
+
+	// Event schema registry
+	r.HandleFunc("/api/admin/events/schemas", eventSchemaHandler.HandleListSchemas).Methods("GET")
+	r.HandleFunc("/api/admin/events/schemas", eventSchemaHandler.HandleRegisterSchema).Methods("POST")
+	r.HandleFunc("/api/admin/events/compatibility", eventSchemaHandler.HandleCheckCompatibility).Methods("POST")
+	r.HandleFunc("/api/admin/events/validate", eventSchemaHandler.HandleValidateEvent).Methods("POST")
+	r.HandleFunc("/api/admin/events/publishers", eventSchemaHandler.HandleListPublishers).Methods("GET")
+	r.HandleFunc("/api/admin/events/publishers/{connector}", eventSchemaHandler.HandleDeclarePublisher).Methods("PUT")
+
+	// Webhooks forwarded by relay agents on restricted networks
+	r.HandleFunc("/api/relay/ingest", relayHandler.HandleIngest).Methods("POST")
+
+	// Prometheus scrape endpoint; the metrics cover the whole process, not one tenant
+	r.Handle("/metrics", metrics.HandlerFromEnv()).Methods("GET")
 
 	// Health check endpoint
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -68,7 +646,7 @@ func SetupRoutes(r *mux.Router, serviceNowClient *servicenow.Client, slackClient
 		}
 
 		// Handle the new incident
-		messageTS, err := incidentHandler.HandleNewIncident(incident)
+		messageTS, err := deps.Incidents.HandleNewIncident(incident)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Error handling incident: %v", err), http.StatusInternalServerError)
 			return
@@ -94,7 +672,7 @@ func SetupRoutes(r *mux.Router, serviceNowClient *servicenow.Client, slackClient
 			return
 		}
 
-		err := incidentHandler.HandleIncidentUpdate(
+		err := deps.Incidents.HandleIncidentUpdate(
 			incidentID,
 			update.ChannelID,
 			update.ThreadTS,
@@ -130,29 +708,628 @@ func SetupRoutes(r *mux.Router, serviceNowClient *servicenow.Client, slackClient
             <body>
                 <h1>GRC Integration API Documentation</h1>
                 <p>This page documents the available API endpoints for the GRC Integration service.</p>
+                <p>Every /api request runs against one tenant, chosen by the X-API-Key header, then the X-Tenant-ID header or ?tenant= query (for webhooks), then the Slack team of a Slack request, then the default tenant.</p>
+                <p>Webhook senders authenticate with an API key issued for their source, sent in X-Webhook-Key or as a bearer token. Requests without an active key for their source get a 401.</p>
+                <p>Requests time out after REQUEST_TIMEOUT (default 10s; longer for manual syncs and reports, 3s for Slack) with a 504 carrying a correlation_id. Send X-Request-ID to choose the ID; it is echoed on every response.</p>
                 
                 <h2>ServiceNow Webhooks</h2>
                 <div class="endpoint">
                     <span class="method">POST</span> /api/webhooks/servicenow
-                    <p>Endpoint for receiving webhooks from ServiceNow GRC.</p>
+                    <p>Endpoint for receiving webhooks from ServiceNow GRC. Requests must carry SERVICENOW_WEBHOOK_TOKEN or a signature made with it.</p>
                 </div>
                 
                 <h2>Jira Webhooks</h2>
                 <div class="endpoint">
                     <span class="method">POST</span> /api/webhooks/jira
-                    <p>Endpoint for receiving webhooks from Jira.</p>
+                    <p>Endpoint for receiving webhooks from Jira. Requests must carry a valid X-Hub-Signature made with JIRA_WEBHOOK_SECRET.</p>
+                </div>
+                
+                <h2>GitHub Webhooks</h2>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/webhooks/github
+                    <p>When GITHUB_REPO is set, receives issues and issue_comment webhooks for the repository findings and compliance tasks are filed in: closing or reopening an issue updates the record's state and comments become work notes. Requests must carry a valid X-Hub-Signature-256 made with GITHUB_WEBHOOK_SECRET. Without GITHUB_REPO, GitHub deliveries go to the generic ingestion below.</p>
+                </div>
+                
+                <h2>Microsoft Teams Bot</h2>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/teams/messages
+                    <p>When TEAMS_APP_ID is set, receives Bot Framework activities for the Teams bot. Requests must carry a Bot Connector token issued for the app ID. Adaptive Card button presses are handled like the Slack buttons they mirror; other activities are acknowledged and ignored.</p>
+                </div>
+                
+                <h2>Other Webhooks</h2>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/webhooks/{source}
+                    <p>Generic ingestion for registered sources (github, pagerduty, generic). Payloads are validated, normalized and routed by the routing table.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/webhooks/routes
+                    <p>Lists the registered webhook sources and routing rules.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/relay/ingest
+                    <p>Receives ServiceNow and Jira webhooks queued and forwarded by a relay agent (cmd/relay), signed with RELAY_SHARED_SECRET.</p>
+                </div>
+                
+                <h2>Event Schemas</h2>
+                <div class="endpoint">
+                    <span class="method">GET | POST</span> /api/admin/events/schemas
+                    <p>Lists registered normalized event schemas (e.g. incident.updated v2) or registers a new version.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/admin/events/compatibility
+                    <p>Checks whether moving an event type from one version to another would break consumers.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/admin/events/validate
+                    <p>Validates an event envelope against its schema.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/events/publishers, <span class="method">PUT</span> /api/admin/events/publishers/{connector}
+                    <p>Lists or upgrades the event versions each connector publishes; incompatible upgrades are refused.</p>
                 </div>
                 
                 <h2>Slack Interactions</h2>
                 <div class="endpoint">
                     <span class="method">POST</span> /api/slack/interactions
-                    <p>Endpoint for handling Slack interactive components.</p>
+                    <p>Endpoint for handling Slack interactive components (buttons and modal submissions). Requests must carry a valid Slack signature (SLACK_SIGNING_SECRET).</p>
                 </div>
                 
                 <h2>Slack Commands</h2>
                 <div class="endpoint">
                     <span class="method">POST</span> /api/slack/commands
-                    <p>Endpoint for handling Slack slash commands.</p>
+                    <p>Endpoint for handling Slack slash commands. Requests must carry a valid Slack signature.</p>
+                </div>
+                
+                <h2>Slack Channel Diagnostics</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/slack/channels
+                    <p>Lists configured Slack channels the bot cannot reach.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/slack/usergroups
+                    <p>Resolves the usergroup handles mentioned in notifications and lists any that do not exist.</p>
+                </div>
+                
+                <h2>Sync Health</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/sync/health
+                    <p>Summarizes mapping counts, each integration's last successful and failed sync, the error rate over the last hour, queue depth and dead letters.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/sync/health/mappings
+                    <p>Counts the linked risks, incidents and GitHub issues.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/sync/health/integrations
+                    <p>Returns each integration's last success, last failure and error, with successes and failures over the last hour.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/sync/health/queues
+                    <p>Returns queued and running jobs, ServiceNow records held in the settle window and dead letters by status.</p>
+                </div>
+
+                <h2>Jira Project Provisioning</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/jira/projects
+                    <p>Lists the Jira project or component provisioned for each ServiceNow table, and the provisioning mode.</p>
+                </div>
+
+                <h2>Jira Metadata Cache</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/jira/cache
+                    <p>Counts the transitions, fields and projects cached for the Jira site, and how long they are kept.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/admin/jira/cache
+                    <p>Drops the cached metadata, including project components, so it is fetched again. Use it after changing a workflow, field or project in Jira.</p>
+                </div>
+
+                <h2>Sync Loop Guard</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/sync/loops
+                    <p>Shows loop guard counters and recently broken update loops.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/admin/sync/loops/{entity}
+                    <p>Lifts the loop guard block on an entity.</p>
+                </div>
+
+                <h2>Sync Conflicts</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/sync/conflicts
+                    <p>Lists status, priority and assignee edits made on both sides of a pair within the conflict window, newest first. Filter with ?status=open or ?status=resolved.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/sync/conflicts/{id}
+                    <p>Returns one conflict with both edits and any update held for review.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/admin/sync/conflicts/{id}/resolve
+                    <p>Resolves a conflict flagged for review: {"keep": "jira" or "servicenow"}. The other edit is reverted and the held update is synced if its side was kept.</p>
+                </div>
+
+                <h2>Jira Approvals</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/approvals
+                    <p>Lists risks, incidents and possible duplicates whose Jira issue waits for approval in Slack, newest first. Filter with ?status=pending, approved, rejected or linked.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/approvals/{sys_id}
+                    <p>Returns a record's approval with who decided it, when, and the Jira issue created.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/admin/approvals/{sys_id}/decide
+                    <p>Approves or rejects a pending approval: {"approve": true or false, "by": "..."}. Approval creates the Jira issue; both are recorded in the execution history. A possible duplicate can instead be linked to the existing issue with {"link": true}.</p>
+                </div>
+                
+                <h2>Sync Failures</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/events/failures
+                    <p>Lists recent webhook events that failed to sync, newest first.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/events/failures/{id}
+                    <p>Returns one failed event with its full payload. Ops alerts in Slack link here and show only a redacted preview.</p>
+                </div>
+
+                <h2>Dead Letters</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/deadletters
+                    <p>Lists ServiceNow webhooks that failed to sync, newest first. Filter with ?status=pending or ?status=replayed.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/deadletters/{id}
+                    <p>Returns one dead letter with its full payload.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/deadletters/{id}/replay
+                    <p>Re-runs the sync for a pending dead letter and reports whether it succeeded.</p>
+                </div>
+
+                <h2>Polling Sources</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/pollers
+                    <p>Shows each polling source's schedule, watermark, last run and health.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/admin/pollers/{source}/run
+                    <p>Polls a source now, outside its schedule.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/admin/pollers/{source}/watermark
+                    <p>Forgets a source's watermark so its next poll starts over.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/reconcile
+                    <p>Shows the last Jira/ServiceNow drift report.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/admin/reconcile/run
+                    <p>Compares status, priority and assignee across linked pairs now, healing drift where an authoritative side is configured.</p>
+                </div>
+
+                <h2>Notification Routing</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/notification-rules
+                    <p>Lists the rules that route new records to Slack channels by item type, severity and category.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/notification-rules
+                    <p>Adds a rule. It applies to the next notification, without a restart.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/notification-rules/preview
+                    <p>Shows the channels an item would be announced in, the language of each, and the channels whose quiet hours or rate cap would hold it for the digest now (?item_type=&amp;severity=&amp;category=).</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/notification-rules/channels
+                    <p>Lists the channels with quiet hours or a rate cap: whether they are quiet now, their announcements in the last hour and the items held for their digest.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/notification-rules/{id}
+                    <p>Shows one rule.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/notification-rules/{id}
+                    <p>Replaces a rule.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/v1/notification-rules/{id}
+                    <p>Removes a rule.</p>
+                </div>
+
+                <h2>ServiceNow Tables</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/tables
+                    <p>Lists the built-in tables and those registered for syncing, sn_grc_item included.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/tables
+                    <p>Registers a table with an optional field map, Jira project and issue type. Its next webhook creates a Jira issue.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/tables/{name}
+                    <p>Shows one registered table.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/tables/{name}
+                    <p>Registers a table or replaces its settings.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/v1/tables/{name}
+                    <p>Unregisters a table; its webhooks are ignored again.</p>
+                </div>
+
+                <h2>Identities</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/identities
+                    <p>Lists the mappings from ServiceNow users to Jira accounts and Slack users.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/identities
+                    <p>Adds a manual mapping: servicenow_user_id (a sys_user sys_id), jira_account_id and slack_user_id. Email matching never changes manual mappings.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/identities/match
+                    <p>Matches a ServiceNow user's accounts by email now: {"servicenow_user": sys_id, user_name or email}.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/identities/{id}
+                    <p>Shows one mapping.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/identities/{id}
+                    <p>Replaces a mapping. It becomes manual unless the body sets "source": "auto".</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/v1/identities/{id}
+                    <p>Removes a mapping; the person is matched by email again when next seen.</p>
+                </div>
+
+                <h2>API Keys</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/apikeys
+                    <p>Lists webhook API keys with their prefix, source, last use and revocation time. Needs DATABASE_URL. Every API key endpoint needs an administrator.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/apikeys
+                    <p>Issues a key for a webhook source: {"name": "...", "source": "pagerduty"}. The key is only returned in this response.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/apikeys/{id}
+                    <p>Returns one key without its secret.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/apikeys/{id}
+                    <p>Renames a key.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/v1/apikeys/{id}
+                    <p>Revokes a key. It stays listed for access reviews.</p>
+                </div>
+
+                <h2>SLAs</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/sla/breaches[?status=open]
+                    <p>Lists linked incidents that ran past their SLA, most overdue first, with the target, elapsed and overdue times. ?status=open leaves out incidents resolved since.</p>
+                </div>
+
+                <h2>Escalations</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/escalation-policies
+                    <p>Lists the policies that escalate unacknowledged incidents.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/escalation-policies
+                    <p>Adds a policy: channel, severities (critical by default), after_minutes and chain, the Slack user IDs notified in turn.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/escalation-policies/{id}
+                    <p>Shows one policy.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/escalation-policies/{id}
+                    <p>Replaces a policy; escalations under way follow its new chain and timing.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/v1/escalation-policies/{id}
+                    <p>Removes a policy; incidents it was escalating stop escalating.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/escalations[?status=waiting|acknowledged|exhausted|closed]
+                    <p>Lists incident escalations, newest first, with who was notified and when.</p>
+                </div>
+
+                <h2>War Rooms</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/war-rooms[?status=open|archived]
+                    <p>Lists the Slack channels opened for major incidents, newest first, with their members and pinned summary.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/war-rooms/{sysId}
+                    <p>Shows the war room of one incident.</p>
+                </div>
+
+                <h2>Post-Incident Reviews</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/post-incident-reviews
+                    <p>Lists the reviews drafted for resolved incidents, newest first, with their Jira tickets.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/post-incident-reviews/{sysId}[?format=json|markdown]
+                    <p>Shows an incident's review with its timeline, or downloads the draft as markdown.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/post-incident-reviews/{sysId}
+                    <p>Drafts an incident's review now, whatever its priority; an incident that has one gets it back.</p>
+                </div>
+
+                <h2>Integrations</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/integrations
+                    <p>Lists the registered integrations with their triggers and actions.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/integrations/{name}/validate
+                    <p>Checks that an integration's credentials work.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/integrations/{name}/actions/{action}
+                    <p>Runs one action with the fields in the JSON body and returns its output.</p>
+                </div>
+
+                <h2>ServiceNow Choice Lists</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/servicenow/choices/{table}
+                    <p>Shows the cached choice lists used to validate outbound values.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/admin/servicenow/choices/{table}/sync
+                    <p>Re-syncs a table's choice lists from sys_choice.</p>
+                </div>
+                
+                <h2>Frontend Proxy</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/proxy/jira/issue/{key}
+                    <p>Live, whitelisted view of a Jira issue. Requires a bearer token; cached and rate limited.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/proxy/servicenow/{table}/{id}
+                    <p>Live, whitelisted view of a ServiceNow GRC record. Requires a bearer token; cached and rate limited.</p>
+                </div>
+                
+                <h2>Sign-in</h2>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/auth/login
+                    <p>Signs in, returning a short-lived access token and a refresh token.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/auth/register
+                    <p>Creates an account. Administrators only; nobody can sign themselves up.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/auth/refresh
+                    <p>Exchanges a refresh token for a new pair. Each refresh token works once; reusing one ends the session.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/auth/logout
+                    <p>Revokes the access token and the session of the refresh token in the body.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET | PUT</span> /api/v1/user/profile, <span class="method">PUT</span> /api/v1/user/password
+                    <p>Reads or updates the signed-in user. Changing the password signs out other sessions.</p>
+                </div>
+                
+                <h2>Workflows</h2>
+                <div class="endpoint">
+                    <span class="method">GET | POST</span> /api/v1/workflows
+                    <p>Lists the caller's workflows (or a workspace's, with ?workspace_id=; ?status= and ?environment= filter) or creates a workflow with its trigger, actions and data mappings.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET | PUT | DELETE</span> /api/v1/workflows/{id}
+                    <p>Reads, replaces or deletes a stored workflow.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/workflows/{id}/activate, /api/v1/workflows/{id}/deactivate
+                    <p>Turns a workflow on or off. A sandbox workflow is only activated when its owner has an active sandbox connection for every service its actions call.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/workflows/{id}/environment
+                    <p>Switches the workflow between its production and sandbox connections with {"environment": "sandbox"}.</p>
+                </div>
+                
+                <h2>Connections</h2>
+                <div class="endpoint">
+                    <span class="method">GET | POST</span> /api/v1/connections
+                    <p>Lists the caller's connections (?service= and ?environment= to filter) or creates one for the production or sandbox environment. API key connections store auth_data encrypted; OAuth connections start as pending.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/connections/oauth/{service}/url
+                    <p>Returns the consent URL and signed state for Jira or Slack. Pass ?connection_id= to complete a specific pending connection.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/auth/oauth/callback/{service}
+                    <p>Exchanges the code and state from the provider redirect for tokens. Tokens are refreshed automatically before they expire.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET | PUT | DELETE</span> /api/v1/connections/{id}
+                    <p>Reads, renames or deletes a connection. Credentials are never returned.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/connections/{id}/revoke, /api/v1/connections/{id}/test
+                    <p>Revokes the token (the record stays for access reviews) or checks that the credentials still work.</p>
+                </div>
+                
+                <h2>Workspaces</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/workspaces
+                    <p>Lists the caller's workspaces and their role in each. The caller is identified by the X-User-ID header.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/workspaces
+                    <p>Creates a workspace owned by the caller.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET | PUT | DELETE</span> /api/v1/workspaces/{id}/members[/{userId}]
+                    <p>Lists members, or sets/removes a member's role (owner, admin, editor, viewer).</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/workspaces/{id}/usage, <span class="method">PUT</span> /api/v1/workspaces/{id}/quota
+                    <p>Shows or sets workflow and monthly execution quotas.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/workspaces/{id}/executions
+                    <p>Lists the workspace's executions, optionally filtered by status.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/workspaces/{id}/executions/{executionId}[?unredacted=true]
+                    <p>Returns an execution with its step inputs and outputs. Sensitive fields read "[redacted]" and are listed in redacted_fields; workspace admins can request the originals where the redaction policy keeps them.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/workflows/{id}/executions?status=failed&limit=50
+                    <p>Lists a workflow's execution history with status, duration and step counts, newest first.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/executions/{id}[?unredacted=true]
+                    <p>Returns an execution with the trigger data and every step's status, input, output, duration and error.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET | POST | DELETE</span> /api/v1/workflows/{id}/shares[/{workspaceId}]
+                    <p>Lists, grants (view, run, edit) or revokes access to a workflow for another workspace.</p>
+                </div>
+                
+                <h2>Manual Sync</h2>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/sync/{table}/{sysId}
+                    <p>Re-syncs one ServiceNow record to Jira and Slack and returns what changed.</p>
+                </div>
+                
+                <h2>Evidence</h2>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/evidence/{table}/{sysId}
+                    <p>Attaches the multipart "file" field to a control test or compliance task, its Jira issue and evidence storage. Rejects disallowed types (415) and oversized files (413).</p>
+                </div>
+                
+                <h2>Remediation</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/remediation
+                    <p>Lists the remediation plan of every audit finding with milestones, with its percent complete.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/findings/{sysId}/remediation
+                    <p>Returns one finding's milestones and percent complete.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/findings/{sysId}/milestones
+                    <p>Adds a milestone ({"title", "description", "due_date", "owner"}) and mirrors it as a Jira sub-task of the finding's issue.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PATCH</span> /api/v1/findings/{sysId}/milestones/{id}
+                    <p>Completes or reopens a milestone with {"done": true|false} and transitions its Jira sub-task.</p>
+                </div>
+                
+                <h2>Compliance Score</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/compliance/score
+                    <p>Computes the severity-weighted compliance score with a per-factor breakdown and comparisons against previous periods.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/compliance/score/history
+                    <p>Lists stored daily score snapshots.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/compliance/score/weights
+                    <p>Shows the factor weights in effect.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/compliance/score/weights
+                    <p>Sets factor weights, e.g. {"open_criticals": 5, "overdue_tasks": 2, "failed_tests": 3}. Administrators only; scoped by the X-Tenant-ID header.</p>
+                </div>
+                
+                <h2>Risk Matrix</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/compliance/risk-matrix
+                    <p>Shows the likelihood × impact matrix risks are rated with, and whether it is the default 5x5 matrix.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/compliance/risk-matrix
+                    <p>Replaces the matrix: likelihoods and impacts from lowest to highest, a row of scores per likelihood, and bands of {"min", "rating", "priority"}. Administrators only.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/v1/compliance/risk-matrix
+                    <p>Goes back to the default matrix. Administrators only.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/compliance/risk-matrix/assess
+                    <p>Rates {"likelihood", "impact"} given as ServiceNow values and returns the score, rating and Jira priority.</p>
+                </div>
+                
+                <h2>Access Review</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/admin/reports/access-review?format=json|csv|pdf
+                    <p>Lists connections and API keys with owners, scopes and last use. Administrators only.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/admin/reports/access-review/send
+                    <p>Posts the access review to the compliance Slack channel.</p>
+                </div>
+
+                <h2>Compliance Reports</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/reports
+                    <p>Lists the stored weekly compliance summaries, newest first.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/reports/{id}?format=json|csv|pdf
+                    <p>Downloads one summary: open risks by category, overdue items and incident SLA results.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/reports/weekly
+                    <p>Generates this week's summary now, stores it and posts it to the reports Slack channel.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/reports/schedule[?count=5]
+                    <p>Lists every scheduled report with its cron expression, timezone, last run and next run times.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/reports/definitions
+                    <p>Lists the user-defined reports.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/reports/definitions
+                    <p>Defines a report, e.g. {"name": "Open vendor risks by owner", "sources": ["vendor_risks"], "filter": "state != closed", "group_by": "owner", "schedule": "0 9 * * 1", "timezone": "America/New_York", "channel": "vendor-risk"}.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/reports/definitions/{id}
+                    <p>Shows one report definition and when it last ran.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">PUT</span> /api/v1/reports/definitions/{id}
+                    <p>Replaces a report definition.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">DELETE</span> /api/v1/reports/definitions/{id}
+                    <p>Deletes a report definition.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">POST</span> /api/v1/reports/definitions/{id}/run[?dry_run=true]
+                    <p>Runs a report now and posts it to its channel; with dry_run only returns the counts.</p>
+                </div>
+
+                <h2>Audit Log</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/audit[?system=&amp;actor=&amp;method=&amp;since=&amp;until=&amp;failed=true&amp;before_id=&amp;limit=100]
+                    <p>Lists the POST, PUT, PATCH and DELETE calls made to other systems, newest first. Needs DATABASE_URL.</p>
+                </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/audit/verify
+                    <p>Recomputes the hash chain and reports the first altered entry and the head hash.</p>
+                </div>
+
+                <h2>Activity Stream</h2>
+                <div class="endpoint">
+                    <span class="method">GET</span> /api/v1/stream[?types=ticket.*,*.failed&amp;sources=jira,slack&amp;access_token=]
+                    <p>WebSocket. Pushes the tenant's webhooks, Jira writes, Slack posts and failures as they happen. Browsers pass the access token as access_token.</p>
                 </div>
                 
                 <h2>Health Check</h2>
@@ -160,6 +1337,10 @@ func SetupRoutes(r *mux.Router, serviceNowClient *servicenow.Client, slackClient
                     <span class="method">GET</span> /health
                     <p>Endpoint for monitoring service health.</p>
                 </div>
+                <div class="endpoint">
+                    <span class="method">GET</span> /metrics
+                    <p>Prometheus metrics for webhooks, syncs and outbound calls.</p>
+                </div>
             </body>
             </html>
         `))
@@ -0,0 +1,83 @@
+// backend/internal/api/webhooks/defaults.go
+package webhooks
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// DefaultRules route PagerDuty incidents to the incident channel and log everything else
+var DefaultRules = []Rule{
+	{Source: "pagerduty", Event: "incident.*", Handler: "slack", Channel: "incident"},
+	{Source: "*", Handler: "log"},
+}
+
+// NewDefaultIngestor registers the built-in sources and handlers. Secrets come from
+// GITHUB_WEBHOOK_SECRET, PAGERDUTY_WEBHOOK_SECRET and GENERIC_WEBHOOK_TOKEN, and the
+// routing table from the JSON file in WEBHOOK_ROUTES_FILE when set.
+func NewDefaultIngestor(slackClient *slack.Client) *Ingestor {
+	ingestor := NewIngestor()
+
+	ingestor.RegisterSource("github", &GitHubSource{Secret: os.Getenv("GITHUB_WEBHOOK_SECRET")})
+	ingestor.RegisterSource("pagerduty", &PagerDutySource{Secret: os.Getenv("PAGERDUTY_WEBHOOK_SECRET")})
+	ingestor.RegisterSource("generic", &GenericSource{
+		Token:        os.Getenv("GENERIC_WEBHOOK_TOKEN"),
+		TypeField:    "type",
+		VersionField: "version",
+		IDField:      "id",
+		SummaryField: "summary",
+	})
+
+	ingestor.RegisterHandler("log", LogHandler)
+	ingestor.RegisterHandler("slack", NewSlackHandler(slackClient))
+
+	if filename := os.Getenv("WEBHOOK_ROUTES_FILE"); filename != "" {
+		if err := ingestor.LoadRulesFile(filename); err != nil {
+			slog.Warn("error loading WEBHOOK_ROUTES_FILE; using the default webhook routes", "error", err)
+		} else {
+			return ingestor
+		}
+	}
+
+	if err := ingestor.SetRules(DefaultRules); err != nil {
+		slog.Error("error installing the default webhook routes", "error", err)
+	}
+
+	return ingestor
+}
+
+// LogHandler writes the event to the server log
+func LogHandler(event *Event, rule Rule) error {
+	slog.Info("webhook event", "source", event.Source, "type", event.Type, "id", event.ID, "summary", event.Summary)
+	return nil
+}
+
+// NewSlackHandler posts a one-line summary of the event to the rule's channel. Channel
+// may be a ChannelMapping key or a channel name; it defaults to the ops channel.
+func NewSlackHandler(slackClient *slack.Client) Handler {
+	return func(event *Event, rule Rule) error {
+		channel := rule.Channel
+		if mapped, ok := slack.ChannelMapping[channel]; ok {
+			channel = mapped
+		}
+		if channel == "" {
+			channel = slack.ChannelMapping["ops"]
+		}
+
+		text := fmt.Sprintf("*[%s]* `%s`", event.Source, event.Type)
+		if event.Summary != "" {
+			text += " " + event.Summary
+		}
+		if event.URL != "" {
+			text += fmt.Sprintf(" (<%s|view>)", event.URL)
+		}
+
+		if _, err := slackClient.PostMessage(channel, slack.Message{Text: text}); err != nil {
+			return fmt.Errorf("error posting webhook event to Slack: %w", err)
+		}
+		return nil
+	}
+}
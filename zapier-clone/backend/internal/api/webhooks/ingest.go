@@ -0,0 +1,318 @@
+// backend/internal/api/webhooks/ingest.go
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shivani-1505/zapier-clone/backend/internal/events"
+	"github.com/shivani-1505/zapier-clone/backend/internal/jobs"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+	"github.com/shivani-1505/zapier-clone/backend/internal/monitoring"
+)
+
+// JobEvent is the job kind for routed webhook events
+const JobEvent = "webhook.event"
+
+// maxBodyBytes caps the size of an inbound webhook payload
+const maxBodyBytes = 1 << 20
+
+// ErrInvalidSignature is returned by a Source when a payload fails verification
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Event is a webhook payload normalized into a source-independent shape
+type Event struct {
+	Source     string                 `json:"source"`
+	Type       string                 `json:"type"`
+	Version    int                    `json:"version,omitempty"`
+	ID         string                 `json:"id,omitempty"`
+	Summary    string                 `json:"summary,omitempty"`
+	URL        string                 `json:"url,omitempty"`
+	Payload    map[string]interface{} `json:"payload"`
+	ReceivedAt time.Time              `json:"received_at"`
+}
+
+// Source validates and normalizes the webhooks of one external system
+type Source interface {
+	// Validate checks the request's authenticity (signature, token) against the raw body
+	Validate(r *http.Request, body []byte) error
+	// Normalize converts the raw body into an Event
+	Normalize(r *http.Request, body []byte) (*Event, error)
+}
+
+// Handler processes a routed event
+type Handler func(event *Event, rule Rule) error
+
+// Rule routes events from a source to a named handler. Event is matched with
+// path.Match, so "issue*" or "*" work; an empty Event matches everything.
+type Rule struct {
+	Source  string `json:"source"`
+	Event   string `json:"event"`
+	Handler string `json:"handler"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// Matches reports whether the rule applies to an event
+func (r Rule) Matches(event *Event) bool {
+	if r.Source != event.Source && r.Source != "*" {
+		return false
+	}
+	if r.Event == "" {
+		return true
+	}
+	ok, err := path.Match(r.Event, event.Type)
+	return err == nil && ok
+}
+
+// Ingestor serves /api/webhooks/{source} and dispatches events through the routing table
+type Ingestor struct {
+	VolumeDetector *monitoring.VolumeDetector
+	// Schemas validates events that declare a schema version
+	Schemas *events.Registry
+	// Jobs runs matched handlers in the background; without it each event gets its own goroutine
+	Jobs *jobs.Queue
+
+	mu       sync.RWMutex
+	sources  map[string]Source
+	handlers map[string]Handler
+	rules    []Rule
+}
+
+// NewIngestor creates an ingestor with no sources, handlers or rules
+func NewIngestor() *Ingestor {
+	return &Ingestor{
+		sources:  make(map[string]Source),
+		handlers: make(map[string]Handler),
+	}
+}
+
+// RegisterSource makes a source available at /api/webhooks/{name}
+func (i *Ingestor) RegisterSource(name string, source Source) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.sources[name] = source
+}
+
+// RegisterHandler makes a handler available to routing rules under name
+func (i *Ingestor) RegisterHandler(name string, handler Handler) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.handlers[name] = handler
+}
+
+// SetRules replaces the routing table, rejecting rules that name unknown handlers
+func (i *Ingestor) SetRules(rules []Rule) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, rule := range rules {
+		if _, ok := i.handlers[rule.Handler]; !ok {
+			return fmt.Errorf("routing rule for %s/%s names unknown handler %q", rule.Source, rule.Event, rule.Handler)
+		}
+	}
+
+	i.rules = append([]Rule(nil), rules...)
+	return nil
+}
+
+// Rules returns a copy of the routing table
+func (i *Ingestor) Rules() []Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]Rule(nil), i.rules...)
+}
+
+// Sources lists the registered source names
+func (i *Ingestor) Sources() []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	names := make([]string, 0, len(i.sources))
+	for name := range i.sources {
+		names = append(names, name)
+	}
+	return names
+}
+
+// LoadRulesFile reads a JSON array of rules from a file and installs it
+func (i *Ingestor) LoadRulesFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("error reading routing rules: %w", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("error parsing routing rules: %w", err)
+	}
+
+	return i.SetRules(rules)
+}
+
+// HandleWebhook validates, normalizes and routes a webhook from the source named in the path
+func (i *Ingestor) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["source"]
+
+	i.mu.RLock()
+	source, ok := i.sources[name]
+	i.mu.RUnlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown webhook source %q", name), http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "Error reading payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := source.Validate(r, body); err != nil {
+		logging.FromContext(r.Context()).Warn("rejected webhook", "source", name, "error", err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := source.Normalize(r, body)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("error normalizing webhook", "source", name, "error", err)
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	event.Source = name
+	event.ReceivedAt = time.Now()
+
+	if err := i.validate(event); err != nil {
+		logging.FromContext(r.Context()).Warn("rejected webhook", "source", name, "type", event.Type, "error", err)
+		status := http.StatusBadRequest
+		var validationErr *events.ValidationError
+		if errors.As(err, &validationErr) {
+			status = http.StatusUnprocessableEntity
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	logging.FromContext(r.Context()).Info("received webhook", "source", name, "type", event.Type)
+
+	// Count the event for volume anomaly detection
+	i.VolumeDetector.Record(name)
+	metrics.WebhooksReceived.Inc(name)
+
+	matched := i.matchingRules(event)
+	if err := i.submit(logging.Detach(r.Context()), event, matched); err != nil {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "Service unavailable: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "received",
+		"type":   event.Type,
+		"routes": len(matched),
+	})
+}
+
+// validate checks versioned events against the schema registry. Events that don't
+// declare a version are passed through unchecked.
+func (i *Ingestor) validate(event *Event) error {
+	if i.Schemas == nil || event.Version == 0 {
+		return nil
+	}
+
+	data := event.Payload
+	if nested, ok := event.Payload["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	return i.Schemas.Validate(&events.Envelope{
+		Type:    event.Type,
+		Version: event.Version,
+		Source:  event.Source,
+		ID:      event.ID,
+		Data:    data,
+	})
+}
+
+// matchingRules returns the rules that apply to an event, in table order
+func (i *Ingestor) matchingRules(event *Event) []Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var matched []Rule
+	for _, rule := range i.rules {
+		if rule.Matches(event) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// eventJob is a queued event with the rules it matched when it arrived
+type eventJob struct {
+	Event *Event `json:"event"`
+	Rules []Rule `json:"rules"`
+}
+
+// RegisterJobs dispatches the ingestor's events on q
+func (i *Ingestor) RegisterJobs(q *jobs.Queue) {
+	i.Jobs = q
+	q.Handle(JobEvent, func(ctx context.Context, data json.RawMessage) error {
+		var job eventJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		i.dispatch(ctx, job.Event, job.Rules)
+		return nil
+	})
+}
+
+// submit queues an event for dispatch
+func (i *Ingestor) submit(ctx context.Context, event *Event, rules []Rule) error {
+	if i.Jobs == nil {
+		go i.dispatch(logging.Detach(ctx), event, rules)
+		return nil
+	}
+	return i.Jobs.Submit(ctx, JobEvent, eventJob{Event: event, Rules: rules})
+}
+
+// dispatch runs every matched handler; one failing handler does not stop the others
+func (i *Ingestor) dispatch(ctx context.Context, event *Event, rules []Rule) {
+	if len(rules) == 0 {
+		logging.FromContext(ctx).Warn("no routing rule for webhook", "source", event.Source, "type", event.Type)
+		return
+	}
+
+	for _, rule := range rules {
+		i.mu.RLock()
+		handler := i.handlers[rule.Handler]
+		i.mu.RUnlock()
+
+		if err := handler(event, rule); err != nil {
+			logging.FromContext(ctx).Error("error handling webhook", "source", event.Source, "type", event.Type, "handler", rule.Handler, "error", err)
+		}
+	}
+}
+
+// HandleListRoutes reports the registered sources and the routing table
+func (i *Ingestor) HandleListRoutes(w http.ResponseWriter, r *http.Request) {
+	sources := i.Sources()
+	sort.Strings(sources)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sources": sources,
+		"rules":   i.Rules(),
+	})
+}
@@ -0,0 +1,184 @@
+// backend/internal/api/webhooks/sources.go
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GitHubSource verifies X-Hub-Signature-256 and takes the event type from X-GitHub-Event
+type GitHubSource struct {
+	Secret string
+}
+
+// Validate checks the HMAC-SHA256 signature when a secret is configured
+func (s *GitHubSource) Validate(r *http.Request, body []byte) error {
+	if s.Secret == "" {
+		return nil
+	}
+
+	signature := strings.TrimPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256=")
+	if !validHMAC(s.Secret, body, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Normalize builds an event such as "pull_request.opened" from a GitHub delivery
+func (s *GitHubSource) Normalize(r *http.Request, body []byte) (*Event, error) {
+	payload, err := decodePayload(body)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		Type:    r.Header.Get("X-GitHub-Event"),
+		ID:      r.Header.Get("X-GitHub-Delivery"),
+		Payload: payload,
+	}
+	if action := lookupString(payload, "action"); action != "" {
+		event.Type += "." + action
+	}
+
+	for _, key := range []string{"pull_request", "issue", "release"} {
+		if title := lookupString(payload, key, "title"); title != "" {
+			event.Summary = title
+			event.URL = lookupString(payload, key, "html_url")
+			break
+		}
+	}
+	if event.Summary == "" {
+		event.Summary = lookupString(payload, "repository", "full_name")
+		event.URL = lookupString(payload, "repository", "html_url")
+	}
+
+	return event, nil
+}
+
+// PagerDutySource verifies X-PagerDuty-Signature and normalizes V3 webhook payloads
+type PagerDutySource struct {
+	Secret string
+}
+
+// Validate checks that one of the v1= signatures matches when a secret is configured
+func (s *PagerDutySource) Validate(r *http.Request, body []byte) error {
+	if s.Secret == "" {
+		return nil
+	}
+
+	// PagerDuty sends one signature per active secret during rotation
+	for _, signature := range strings.Split(r.Header.Get("X-PagerDuty-Signature"), ",") {
+		if validHMAC(s.Secret, body, strings.TrimPrefix(strings.TrimSpace(signature), "v1=")) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Normalize builds an event such as "incident.triggered" from a PagerDuty delivery
+func (s *PagerDutySource) Normalize(r *http.Request, body []byte) (*Event, error) {
+	payload, err := decodePayload(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Type:    lookupString(payload, "event", "event_type"),
+		ID:      lookupString(payload, "event", "id"),
+		Summary: lookupString(payload, "event", "data", "title"),
+		URL:     lookupString(payload, "event", "data", "html_url"),
+		Payload: payload,
+	}, nil
+}
+
+// GenericSource accepts arbitrary JSON, optionally guarded by a shared token in X-Webhook-Token.
+// The field names say where to find the event type, schema version, ID and summary in the payload.
+type GenericSource struct {
+	Token        string
+	TypeField    string
+	VersionField string
+	IDField      string
+	SummaryField string
+}
+
+// Validate compares the shared token when one is configured
+func (s *GenericSource) Validate(r *http.Request, body []byte) error {
+	if s.Token == "" {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Webhook-Token")), []byte(s.Token)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Normalize reads the configured fields, defaulting the type to "event"
+func (s *GenericSource) Normalize(r *http.Request, body []byte) (*Event, error) {
+	payload, err := decodePayload(body)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		Type:    lookupString(payload, strings.Split(s.TypeField, ".")...),
+		ID:      lookupString(payload, strings.Split(s.IDField, ".")...),
+		Summary: lookupString(payload, strings.Split(s.SummaryField, ".")...),
+		Payload: payload,
+	}
+	if event.Type == "" {
+		event.Type = "event"
+	}
+	if version, err := strconv.Atoi(lookupString(payload, strings.Split(s.VersionField, ".")...)); err == nil {
+		event.Version = version
+	}
+
+	return event, nil
+}
+
+// validHMAC compares a hex HMAC-SHA256 signature of body in constant time
+func validHMAC(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// decodePayload parses a JSON object body
+func decodePayload(body []byte) (map[string]interface{}, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("error decoding payload: %w", err)
+	}
+	return payload, nil
+}
+
+// lookupString follows a path of keys through nested objects and returns the string found there
+func lookupString(payload map[string]interface{}, keys ...string) string {
+	var current interface{} = payload
+	for _, key := range keys {
+		object, ok := current.(map[string]interface{})
+		if !ok || key == "" {
+			return ""
+		}
+		current = object[key]
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprintf("%v", v)
+	}
+	return ""
+}
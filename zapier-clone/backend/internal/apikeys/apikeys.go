@@ -0,0 +1,233 @@
+// backend/internal/apikeys/apikeys.go
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// keyPrefix starts every key so leaked keys are easy to recognise
+const keyPrefix = "whk_"
+
+// prefixLength is how much of a key is kept in clear to tell keys apart
+const prefixLength = len(keyPrefix) + 8
+
+// keyColumns are selected in the order scanKey reads them
+const keyColumns = `id, user_id, name, key_prefix, COALESCE(source, ''), created_at, last_used_at, revoked_at`
+
+var (
+	ErrNotFound = errors.New("API key not found")
+	ErrInvalid  = errors.New("name and source are required")
+	ErrSource   = errors.New("source must be lowercase letters, digits, dashes or underscores")
+	// ErrMissing and ErrRejected are returned by Authenticate
+	ErrMissing  = errors.New("an API key is required for this webhook source")
+	ErrRejected = errors.New("invalid, revoked or foreign API key")
+)
+
+// sourcePattern matches the {source} of /api/webhooks/{source}
+var sourcePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Key is an API key a webhook sender authenticates with. The secret itself is
+// only returned once, when the key is created.
+type Key struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+	// Prefix is the start of the key, enough to recognise it
+	Prefix string `json:"prefix"`
+	// Source is the webhook source the key is accepted for, e.g. "pagerduty"
+	Source     string     `json:"source"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the key has not been revoked
+func (k *Key) Active() bool {
+	return k.RevokedAt == nil
+}
+
+// Validate checks the fields a caller sets
+func (k *Key) Validate() error {
+	if strings.TrimSpace(k.Name) == "" || k.Source == "" {
+		return ErrInvalid
+	}
+	if !sourcePattern.MatchString(k.Source) {
+		return ErrSource
+	}
+	return nil
+}
+
+// Hash returns the hex SHA-256 stored for a key; keys are random, so a fast
+// hash is enough
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// generate returns a new random key
+func generate() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating API key: %w", err)
+	}
+	return keyPrefix + hex.EncodeToString(b), nil
+}
+
+// Store manages one tenant's API keys in PostgreSQL
+type Store struct {
+	DB     *sql.DB
+	Tenant string
+	// UsageInterval limits how often last_used_at is written for a busy key
+	UsageInterval time.Duration
+}
+
+// NewStore creates an API key store for a tenant
+func NewStore(db *sql.DB, tenant string) *Store {
+	return &Store{DB: db, Tenant: tenant, UsageInterval: time.Minute}
+}
+
+// List returns every key, revoked ones included, newest first
+func (s *Store) List() ([]Key, error) {
+	rows, err := s.DB.Query(`SELECT `+keyColumns+` FROM api_keys WHERE tenant_id = $1 AND source IS NOT NULL ORDER BY id DESC`, s.Tenant)
+	if err != nil {
+		return nil, fmt.Errorf("error listing API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []Key{}
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+// Get fetches a key by ID
+func (s *Store) Get(id int) (*Key, error) {
+	row := s.DB.QueryRow(`SELECT `+keyColumns+` FROM api_keys WHERE tenant_id = $1 AND id = $2 AND source IS NOT NULL`, s.Tenant, id)
+	key, err := scanKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return key, err
+}
+
+// Create stores a new key for a user and returns it with the secret, which
+// is not kept
+func (s *Store) Create(userID int, key Key) (*Key, string, error) {
+	if err := key.Validate(); err != nil {
+		return nil, "", err
+	}
+	secret, err := generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key.UserID = userID
+	key.Prefix = secret[:prefixLength]
+	err = s.DB.QueryRow(
+		`INSERT INTO api_keys (tenant_id, user_id, name, key_prefix, key_hash, source)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		s.Tenant, userID, strings.TrimSpace(key.Name), key.Prefix, Hash(secret), key.Source,
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating API key: %w", err)
+	}
+	key.Name = strings.TrimSpace(key.Name)
+	key.LastUsedAt, key.RevokedAt = nil, nil
+	return &key, secret, nil
+}
+
+// Rename changes a key's name; the secret and source stay the same
+func (s *Store) Rename(id int, name string) (*Key, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, ErrInvalid
+	}
+	row := s.DB.QueryRow(
+		`UPDATE api_keys SET name = $3 WHERE tenant_id = $1 AND id = $2 AND source IS NOT NULL
+		 RETURNING `+keyColumns, s.Tenant, id, strings.TrimSpace(name))
+	key, err := scanKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return key, err
+}
+
+// Revoke stops a key from authenticating. The key stays listed, with its
+// revocation time, for access reviews.
+func (s *Store) Revoke(id int) (*Key, error) {
+	row := s.DB.QueryRow(
+		`UPDATE api_keys SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		 WHERE tenant_id = $1 AND id = $2 AND source IS NOT NULL
+		 RETURNING `+keyColumns, s.Tenant, id)
+	key, err := scanKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return key, err
+}
+
+// Authenticate returns the active key matching secret for a source and
+// records that it was used
+func (s *Store) Authenticate(source, secret string) (*Key, error) {
+	if secret == "" {
+		return nil, ErrMissing
+	}
+	row := s.DB.QueryRow(
+		`SELECT `+keyColumns+` FROM api_keys
+		 WHERE tenant_id = $1 AND key_hash = $2 AND source = $3 AND revoked_at IS NULL`,
+		s.Tenant, Hash(secret), source)
+	key, err := scanKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRejected
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= s.UsageInterval {
+		if _, err := s.DB.Exec(`UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, key.ID, now); err != nil {
+			return nil, fmt.Errorf("error recording API key use: %w", err)
+		}
+		key.LastUsedAt = &now
+	}
+	return key, nil
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanKey reads a row selected with keyColumns
+func scanKey(row rowScanner) (*Key, error) {
+	key := &Key{}
+	var lastUsed, revoked sql.NullTime
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Source, &key.CreatedAt, &lastUsed, &revoked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning API key: %w", err)
+	}
+	if lastUsed.Valid {
+		key.LastUsedAt = &lastUsed.Time
+	}
+	if revoked.Valid {
+		key.RevokedAt = &revoked.Time
+	}
+	return key, nil
+}
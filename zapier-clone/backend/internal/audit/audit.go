@@ -0,0 +1,295 @@
+// backend/internal/audit/audit.go
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/auth"
+)
+
+// GenesisHash is the previous hash of a tenant's first entry
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// ActorSystem is the actor of calls no signed-in user started, such as
+// webhooks, polls and scheduled jobs
+const ActorSystem = "system"
+
+// entryColumns are selected in the order scanEntry reads them
+const entryColumns = `id, created_at, actor, COALESCE(request_id, ''), target_system, method, target,
+	payload_digest, status_code, COALESCE(error, ''), prev_hash, hash`
+
+// Entry is one outbound call that modified another system
+type Entry struct {
+	ID           int64     `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Actor        string    `json:"actor"`
+	RequestID    string    `json:"request_id,omitempty"`
+	TargetSystem string    `json:"target_system"`
+	Method       string    `json:"method"`
+	// Target is the host and path called, e.g. "dev.service-now.com/api/now/table/sn_risk_risk"
+	Target string `json:"target"`
+	// PayloadDigest is the hex SHA-256 of the request body
+	PayloadDigest string `json:"payload_digest"`
+	// StatusCode is 0 when the call failed before a response
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+	PrevHash   string `json:"prev_hash"`
+	Hash       string `json:"hash"`
+}
+
+// Succeeded reports whether the call got a 2xx response
+func (e *Entry) Succeeded() bool {
+	return e.StatusCode >= 200 && e.StatusCode < 300
+}
+
+// computeHash hashes the entry's fields together with the previous entry's hash
+func (e *Entry) computeHash(tenant string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		e.PrevHash,
+		tenant,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.Actor,
+		e.RequestID,
+		e.TargetSystem,
+		e.Method,
+		e.Target,
+		e.PayloadDigest,
+		fmt.Sprint(e.StatusCode),
+		e.Error,
+	}, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Filter narrows a listing; zero values match everything
+type Filter struct {
+	TargetSystem string
+	Actor        string
+	Method       string
+	Since        time.Time
+	Until        time.Time
+	// BeforeID pages backwards from an entry
+	BeforeID int64
+	// FailedOnly keeps calls without a 2xx response
+	FailedOnly bool
+	Limit      int
+}
+
+// Verification is the result of checking a tenant's hash chain
+type Verification struct {
+	Valid   bool `json:"valid"`
+	Entries int  `json:"entries"`
+	// BrokenAt is the first entry whose hash or link doesn't match
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// HeadHash is the latest entry's hash; keeping a copy elsewhere also
+	// reveals entries removed from the end
+	HeadHash string `json:"head_hash"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a context whose outbound calls are recorded as made by actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns who a call is made for: an actor set with
+// WithActor, the signed-in user, or ActorSystem
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		if claims.Email != "" {
+			return "user:" + claims.Email
+		}
+		return fmt.Sprintf("user:%d", claims.Subject)
+	}
+	return ActorSystem
+}
+
+// Log is one tenant's append-only audit log in PostgreSQL
+type Log struct {
+	DB     *sql.DB
+	Tenant string
+
+	now func() time.Time
+}
+
+// NewLog creates an audit log for a tenant
+func NewLog(db *sql.DB, tenant string) *Log {
+	return &Log{DB: db, Tenant: tenant, now: time.Now}
+}
+
+// Append chains an entry onto the log. A transaction-scoped advisory lock on
+// the tenant keeps concurrent appends, from this or another server, in order.
+func (l *Log) Append(entry Entry) (*Entry, error) {
+	tx, err := l.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting audit transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('audit_log:' || $1))`, l.Tenant); err != nil {
+		return nil, fmt.Errorf("error locking audit log: %w", err)
+	}
+
+	entry.PrevHash = GenesisHash
+	err = tx.QueryRow(`SELECT hash FROM audit_log WHERE tenant_id = $1 ORDER BY id DESC LIMIT 1`, l.Tenant).Scan(&entry.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("error reading audit chain: %w", err)
+	}
+
+	// PostgreSQL keeps microseconds, so hash what will be read back
+	entry.CreatedAt = l.now().UTC().Truncate(time.Microsecond)
+	entry.Hash = entry.computeHash(l.Tenant)
+
+	err = tx.QueryRow(
+		`INSERT INTO audit_log (tenant_id, created_at, actor, request_id, target_system, method, target,
+		 payload_digest, status_code, error, prev_hash, hash)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		 RETURNING id`,
+		l.Tenant, entry.CreatedAt, entry.Actor, nullString(entry.RequestID), entry.TargetSystem, entry.Method, entry.Target,
+		entry.PayloadDigest, entry.StatusCode, nullString(entry.Error), entry.PrevHash, entry.Hash,
+	).Scan(&entry.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error writing audit entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing audit entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// List returns entries matching the filter, newest first
+func (l *Log) List(filter Filter) ([]Entry, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{l.Tenant}
+	where := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.TargetSystem != "" {
+		where("target_system = $%d", filter.TargetSystem)
+	}
+	if filter.Actor != "" {
+		where("actor = $%d", filter.Actor)
+	}
+	if filter.Method != "" {
+		where("method = $%d", strings.ToUpper(filter.Method))
+	}
+	if !filter.Since.IsZero() {
+		where("created_at >= $%d", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		where("created_at < $%d", filter.Until.UTC())
+	}
+	if filter.BeforeID > 0 {
+		where("id < $%d", filter.BeforeID)
+	}
+	if filter.FailedOnly {
+		conditions = append(conditions, "(status_code < 200 OR status_code >= 300)")
+	}
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	rows, err := l.DB.Query(
+		`SELECT `+entryColumns+` FROM audit_log WHERE `+strings.Join(conditions, " AND ")+
+			fmt.Sprintf(` ORDER BY id DESC LIMIT %d`, limit), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// Verify walks the tenant's chain from the first entry, recomputing every
+// hash and checking each entry links to the one before it
+func (l *Log) Verify() (*Verification, error) {
+	rows, err := l.DB.Query(`SELECT `+entryColumns+` FROM audit_log WHERE tenant_id = $1 ORDER BY id`, l.Tenant)
+	if err != nil {
+		return nil, fmt.Errorf("error reading audit log: %w", err)
+	}
+	defer rows.Close()
+
+	chain := newChainVerifier(l.Tenant)
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		chain.add(entry)
+	}
+	return chain.result(), rows.Err()
+}
+
+// chainVerifier checks a tenant's entries one at a time, oldest first
+type chainVerifier struct {
+	tenant string
+	prev   string
+	status Verification
+}
+
+func newChainVerifier(tenant string) *chainVerifier {
+	return &chainVerifier{tenant: tenant, prev: GenesisHash, status: Verification{Valid: true}}
+}
+
+// add checks the next entry; entries after the first broken one are only
+// counted, but still move the head
+func (c *chainVerifier) add(entry *Entry) {
+	c.status.Entries++
+	if c.status.Valid {
+		switch {
+		case entry.PrevHash != c.prev:
+			c.status.Valid, c.status.BrokenAt, c.status.Reason = false, entry.ID, "prev_hash does not match the previous entry's hash"
+		case entry.computeHash(c.tenant) != entry.Hash:
+			c.status.Valid, c.status.BrokenAt, c.status.Reason = false, entry.ID, "hash does not match the entry's contents"
+		}
+	}
+	c.prev = entry.Hash
+}
+
+// result returns the verification of the entries added so far
+func (c *chainVerifier) result() *Verification {
+	status := c.status
+	status.HeadHash = c.prev
+	return &status
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEntry(row scanner) (*Entry, error) {
+	var entry Entry
+	err := row.Scan(&entry.ID, &entry.CreatedAt, &entry.Actor, &entry.RequestID, &entry.TargetSystem, &entry.Method,
+		&entry.Target, &entry.PayloadDigest, &entry.StatusCode, &entry.Error, &entry.PrevHash, &entry.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning audit entry: %w", err)
+	}
+	entry.CreatedAt = entry.CreatedAt.UTC()
+	return &entry, nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
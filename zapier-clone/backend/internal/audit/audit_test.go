@@ -0,0 +1,127 @@
+// backend/internal/audit/audit_test.go
+package audit
+
+import (
+	"testing"
+	"time"
+)
+
+// chain returns n linked entries for tenant, as Append writes them
+func chain(tenant string, n int) []*Entry {
+	var entries []*Entry
+	prev := GenesisHash
+	created := time.Date(2024, 5, 14, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= n; i++ {
+		entry := &Entry{
+			ID:            int64(i),
+			CreatedAt:     created.Add(time.Duration(i) * time.Second),
+			Actor:         ActorSystem,
+			TargetSystem:  "jira",
+			Method:        "POST",
+			Target:        "jira.example.com/rest/api/2/issue",
+			PayloadDigest: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			StatusCode:    201,
+			PrevHash:      prev,
+		}
+		entry.Hash = entry.computeHash(tenant)
+		prev = entry.Hash
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestChainVerifier(t *testing.T) {
+	tests := []struct {
+		name string
+		// tamper changes the chain of five entries before it is verified
+		tamper       func(entries []*Entry) []*Entry
+		tenant       string
+		wantValid    bool
+		wantBrokenAt int64
+		wantEntries  int
+	}{
+		{
+			name:        "intact chain",
+			tamper:      func(entries []*Entry) []*Entry { return entries },
+			wantValid:   true,
+			wantEntries: 5,
+		},
+		{
+			name:        "empty chain",
+			tamper:      func(entries []*Entry) []*Entry { return nil },
+			wantValid:   true,
+			wantEntries: 0,
+		},
+		{
+			name: "edited entry",
+			tamper: func(entries []*Entry) []*Entry {
+				entries[2].StatusCode = 500
+				return entries
+			},
+			wantBrokenAt: 3,
+			wantEntries:  5,
+		},
+		{
+			name: "edited entry with its hash recomputed",
+			tamper: func(entries []*Entry) []*Entry {
+				entries[2].Actor = "user:mallory@example.com"
+				entries[2].Hash = entries[2].computeHash("acme")
+				return entries
+			},
+			wantBrokenAt: 4,
+			wantEntries:  5,
+		},
+		{
+			name: "removed entry",
+			tamper: func(entries []*Entry) []*Entry {
+				return append(entries[:1], entries[2:]...)
+			},
+			wantBrokenAt: 3,
+			wantEntries:  4,
+		},
+		{
+			name: "reordered entries",
+			tamper: func(entries []*Entry) []*Entry {
+				entries[1], entries[2] = entries[2], entries[1]
+				return entries
+			},
+			wantBrokenAt: 3,
+			wantEntries:  5,
+		},
+		{
+			name:         "chain of another tenant",
+			tamper:       func(entries []*Entry) []*Entry { return entries },
+			tenant:       "globex",
+			wantBrokenAt: 1,
+			wantEntries:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := tt.tenant
+			if tenant == "" {
+				tenant = "acme"
+			}
+			entries := tt.tamper(chain("acme", 5))
+
+			verifier := newChainVerifier(tenant)
+			for _, entry := range entries {
+				verifier.add(entry)
+			}
+			got := verifier.result()
+
+			if got.Valid != tt.wantValid || got.BrokenAt != tt.wantBrokenAt || got.Entries != tt.wantEntries {
+				t.Errorf("result = valid %v, broken at %d, %d entries (%s); want valid %v, broken at %d, %d entries",
+					got.Valid, got.BrokenAt, got.Entries, got.Reason, tt.wantValid, tt.wantBrokenAt, tt.wantEntries)
+			}
+			wantHead := GenesisHash
+			if len(entries) > 0 {
+				wantHead = entries[len(entries)-1].Hash
+			}
+			if got.HeadHash != wantHead {
+				t.Errorf("HeadHash = %s, want %s", got.HeadHash, wantHead)
+			}
+		})
+	}
+}
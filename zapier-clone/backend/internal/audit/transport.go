@@ -0,0 +1,109 @@
+// backend/internal/audit/transport.go
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// modifyingMethods are the HTTP methods recorded; reads are not audited
+var modifyingMethods = map[string]bool{
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// Transport records every modifying request sent through it in an audit log.
+// It wraps outside the shared transport, so a request retried after a 429 is
+// one entry with the final result.
+type Transport struct {
+	Base http.RoundTripper
+	Log  *Log
+	// System names the target in entries, e.g. "servicenow"
+	System string
+}
+
+// Wrap routes a client's requests through an audit Transport for system
+func Wrap(client *http.Client, log *Log, system string) {
+	if client == nil || log == nil {
+		return
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &Transport{Base: base, Log: log, System: system}
+}
+
+// RoundTrip sends the request and, for modifying methods, appends its outcome.
+// A failed append is logged rather than failing a call that has already been made.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !modifyingMethods[req.Method] {
+		return t.Base.RoundTrip(req)
+	}
+
+	digest, req, err := payloadDigest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+
+	entry := Entry{
+		Actor:         ActorFromContext(req.Context()),
+		RequestID:     logging.RequestID(req.Context()),
+		TargetSystem:  t.System,
+		Method:        req.Method,
+		Target:        req.URL.Host + req.URL.EscapedPath(),
+		PayloadDigest: digest,
+	}
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if _, appendErr := t.Log.Append(entry); appendErr != nil {
+		logging.FromContext(req.Context()).Error("error recording outbound call in the audit log",
+			"system", t.System, "method", req.Method, "target", entry.Target, "error", appendErr)
+	}
+
+	return resp, err
+}
+
+// payloadDigest hashes the request body, leaving the request able to send it
+func payloadDigest(req *http.Request) (string, *http.Request, error) {
+	var body []byte
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+	case req.GetBody != nil:
+		copied, err := req.GetBody()
+		if err != nil {
+			return "", req, err
+		}
+		body, err = io.ReadAll(copied)
+		copied.Close()
+		if err != nil {
+			return "", req, err
+		}
+	default:
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", req, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), req, nil
+}
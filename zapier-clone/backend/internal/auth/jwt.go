@@ -0,0 +1,161 @@
+// backend/internal/auth/jwt.go
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims are the fields of an access token
+type Claims struct {
+	Subject   int    `json:"sub"`
+	Email     string `json:"email,omitempty"`
+	Tenant    string `json:"tid,omitempty"`
+	Admin     bool   `json:"adm,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	ID        string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Expires returns the token's expiry time
+func (c *Claims) Expires() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// header is the JOSE header of an HS256 token
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// Signer issues and verifies HS256 JWTs. Tokens are signed with the active key
+// and verified with any configured key, so keys can be rotated without
+// logging everyone out.
+type Signer struct {
+	Issuer string
+
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+// NewSigner creates a signer from "kid:secret" entries separated by commas; the
+// first entry signs new tokens. Secrets must be at least 32 bytes.
+func NewSigner(spec, issuer string) (*Signer, error) {
+	signer := &Signer{Issuer: issuer, keys: make(map[string][]byte)}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid signing key entry: expected kid:secret")
+		}
+		if len(parts[1]) < 32 {
+			return nil, fmt.Errorf("signing key %s is shorter than 32 bytes", parts[0])
+		}
+		if _, dup := signer.keys[parts[0]]; dup {
+			return nil, fmt.Errorf("duplicate signing key %s", parts[0])
+		}
+		signer.keys[parts[0]] = []byte(parts[1])
+		if signer.activeKeyID == "" {
+			signer.activeKeyID = parts[0]
+		}
+	}
+
+	if signer.activeKeyID == "" {
+		return nil, fmt.Errorf("no signing keys configured")
+	}
+	return signer, nil
+}
+
+// Sign issues a token for the claims, filling in the ID and issuer
+func (s *Signer) Sign(claims Claims) (string, error) {
+	if claims.ID == "" {
+		id, err := randomToken(16)
+		if err != nil {
+			return "", err
+		}
+		claims.ID = id
+	}
+	claims.Issuer = s.Issuer
+
+	head, err := json.Marshal(header{Algorithm: "HS256", Type: "JWT", KeyID: s.activeKeyID})
+	if err != nil {
+		return "", fmt.Errorf("error encoding token header: %w", err)
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("error encoding token claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(head) + "." + base64.RawURLEncoding.EncodeToString(body)
+	return unsigned + "." + sign(s.keys[s.activeKeyID], unsigned), nil
+}
+
+// Verify checks a token's signature, issuer and expiry and returns its claims
+func (s *Signer) Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var head header
+	if err := decodeSegment(parts[0], &head); err != nil || head.Algorithm != "HS256" {
+		return nil, ErrInvalidToken
+	}
+	key, ok := s.keys[head.KeyID]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(sign(key, parts[0]+"."+parts[1]))) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Issuer != s.Issuer || claims.Subject <= 0 || claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	return &claims, nil
+}
+
+// sign returns the base64url HMAC-SHA256 of a token's header and payload
+func sign(key []byte, unsigned string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// decodeSegment decodes one base64url JSON part of a token
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// randomToken returns n random bytes, hex encoded
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
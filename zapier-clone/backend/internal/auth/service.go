@@ -0,0 +1,303 @@
+// backend/internal/auth/service.go
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Default token lifetimes
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// minPasswordLength is the shortest password accepted at registration
+const minPasswordLength = 8
+
+// Service signs users in and issues, rotates and revokes their tokens
+type Service struct {
+	Store      *Store
+	Signer     *Signer
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+
+	now func() time.Time
+}
+
+// NewService creates an auth service
+func NewService(store *Store, signer *Signer) *Service {
+	return &Service{
+		Store:      store,
+		Signer:     signer,
+		AccessTTL:  DefaultAccessTTL,
+		RefreshTTL: DefaultRefreshTTL,
+		now:        time.Now,
+	}
+}
+
+// NewServiceFromEnv reads JWT_SIGNING_KEYS ("kid:secret,..."), JWT_ISSUER,
+// JWT_ACCESS_TTL and JWT_REFRESH_TTL. The keys are required: without them
+// nothing could be authenticated.
+func NewServiceFromEnv(store *Store) (*Service, error) {
+	keys := os.Getenv("JWT_SIGNING_KEYS")
+	if keys == "" {
+		return nil, errors.New("JWT_SIGNING_KEYS is not set")
+	}
+
+	signer, err := NewSigner(keys, getEnv("JWT_ISSUER", "zapier-clone"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_SIGNING_KEYS: %w", err)
+	}
+
+	service := NewService(store, signer)
+	if service.AccessTTL, err = durationFromEnv("JWT_ACCESS_TTL", DefaultAccessTTL); err != nil {
+		return nil, err
+	}
+	if service.RefreshTTL, err = durationFromEnv("JWT_REFRESH_TTL", DefaultRefreshTTL); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// CreateUser creates an account in a tenant. Accounts are created by
+// administrators; nobody can sign themselves up.
+func (s *Service) CreateUser(tenantID, username, email, password, fullName string, admin bool) (*User, error) {
+	username = strings.TrimSpace(username)
+	email = strings.TrimSpace(email)
+	if username == "" || email == "" || !strings.Contains(email, "@") {
+		return nil, ErrInvalidUser
+	}
+	if len(password) < minPasswordLength {
+		return nil, ErrWeakPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing password: %w", err)
+	}
+
+	return s.Store.CreateUser(tenantID, username, email, string(hash), fullName, admin)
+}
+
+// EnsureAdmin makes sure the account with an email is an administrator,
+// creating it in the tenant with the password when it does not exist. It
+// bootstraps the first administrator, who then creates everyone else.
+func (s *Service) EnsureAdmin(tenantID, username, email, password string) error {
+	user, _, err := s.Store.GetUserByEmail(strings.TrimSpace(email))
+	if errors.Is(err, ErrUserNotFound) {
+		_, err = s.CreateUser(tenantID, username, email, password, "", true)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if user.Admin {
+		return nil
+	}
+	return s.Store.SetAdmin(user.ID, true)
+}
+
+// EnsureAdminFromEnv runs EnsureAdmin for AUTH_ADMIN_EMAIL and
+// AUTH_ADMIN_PASSWORD when both are set. AUTH_ADMIN_USERNAME defaults to the
+// part of the email before the @, and AUTH_ADMIN_TENANT to defaultTenant.
+func (s *Service) EnsureAdminFromEnv(defaultTenant string) error {
+	email := os.Getenv("AUTH_ADMIN_EMAIL")
+	password := os.Getenv("AUTH_ADMIN_PASSWORD")
+	if email == "" || password == "" {
+		return nil
+	}
+	username, _, _ := strings.Cut(email, "@")
+	return s.EnsureAdmin(getEnv("AUTH_ADMIN_TENANT", defaultTenant), getEnv("AUTH_ADMIN_USERNAME", username), email, password)
+}
+
+// Login checks a user's password and starts a new session
+func (s *Service) Login(email, password string) (*TokenPair, error) {
+	user, hash, err := s.Store.GetUserByEmail(strings.TrimSpace(email))
+	if errors.Is(err, ErrUserNotFound) {
+		// Spend the same time as a real check so emails can't be probed by timing
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return s.issue(user)
+}
+
+// Refresh exchanges a refresh token for a new pair. The old refresh token is
+// revoked; presenting it again revokes the whole session, since only a stolen
+// copy would still be using it.
+func (s *Service) Refresh(token string) (*TokenPair, error) {
+	stored, err := s.Store.getRefreshToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		slog.Warn("revoked refresh token reused; ending the session", "user_id", stored.UserID)
+		if err := s.Store.RevokeFamily(stored.Family); err != nil {
+			slog.Error("error revoking session after token reuse", "error", err)
+		}
+		return nil, ErrTokenReused
+	}
+	if !s.now().Before(stored.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	user, _, err := s.Store.GetUser(stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Store.RotateRefreshToken(stored, refresh, s.now().Add(s.RefreshTTL)); err != nil {
+		if errors.Is(err, ErrTokenReused) {
+			s.Store.RevokeFamily(stored.Family)
+		}
+		return nil, err
+	}
+
+	return s.pair(user, refresh)
+}
+
+// Logout revokes the access token and, when given, the session's refresh tokens
+func (s *Service) Logout(claims *Claims, refreshToken string) error {
+	if err := s.Store.RevokeAccessToken(claims); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.Store.getRefreshToken(refreshToken)
+	if errors.Is(err, ErrInvalidToken) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if stored.UserID != claims.Subject {
+		return ErrInvalidToken
+	}
+	return s.Store.RevokeFamily(stored.Family)
+}
+
+// Authenticate verifies an access token and checks it has not been revoked
+func (s *Service) Authenticate(token string) (*Claims, error) {
+	claims, err := s.Signer.Verify(token, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.Store.IsAccessTokenRevoked(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// ChangePassword replaces a user's password and signs out their other sessions
+func (s *Service) ChangePassword(userID int, current, next string) error {
+	_, hash, err := s.Store.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(current)) != nil {
+		return ErrInvalidCredentials
+	}
+	if len(next) < minPasswordLength {
+		return ErrWeakPassword
+	}
+
+	newHash, err := bcrypt.GenerateFromPassword([]byte(next), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+	if err := s.Store.UpdatePassword(userID, string(newHash)); err != nil {
+		return err
+	}
+	return s.Store.RevokeUserTokens(userID)
+}
+
+// issue starts a session: a new refresh token family and an access token
+func (s *Service) issue(user *User) (*TokenPair, error) {
+	family, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Store.AddRefreshToken(user.ID, refresh, family, s.now().Add(s.RefreshTTL)); err != nil {
+		return nil, err
+	}
+
+	return s.pair(user, refresh)
+}
+
+// pair signs an access token to go with a refresh token
+func (s *Service) pair(user *User, refresh string) (*TokenPair, error) {
+	now := s.now()
+	access, err := s.Signer.Sign(Claims{
+		Subject:   user.ID,
+		Email:     user.Email,
+		Tenant:    user.TenantID,
+		Admin:     user.Admin,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.AccessTTL.Seconds()),
+		User:         user,
+	}, nil
+}
+
+// dummyHash is compared against when a login names an unknown email
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+
+// durationFromEnv reads a positive Go duration from the environment
+func durationFromEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return d, nil
+}
+
+// getEnv returns an environment variable or the fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,242 @@
+// backend/internal/auth/store.go
+package auth
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Store manages users, refresh tokens and revoked access tokens in PostgreSQL
+type Store struct {
+	DB *sql.DB
+}
+
+// NewStore creates an auth store
+func NewStore(db *sql.DB) *Store {
+	return &Store{DB: db}
+}
+
+// CreateUser inserts a user with an already hashed password
+func (s *Store) CreateUser(tenantID, username, email, passwordHash, fullName string, admin bool) (*User, error) {
+	user := &User{Username: username, Email: email, FullName: fullName, TenantID: tenantID, Admin: admin}
+	err := s.DB.QueryRow(
+		`INSERT INTO users (tenant_id, username, email, password_hash, full_name, is_admin) VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		tenantID, username, email, passwordHash, nullString(fullName), admin,
+	).Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrUserExists
+		}
+		return nil, fmt.Errorf("error creating user: %w", err)
+	}
+	return user, nil
+}
+
+// GetUser fetches a user by ID
+func (s *Store) GetUser(id int) (*User, string, error) {
+	return s.scanUser(s.DB.QueryRow(
+		`SELECT id, username, email, COALESCE(full_name, ''), tenant_id, is_admin, password_hash, created_at FROM users WHERE id = $1`, id))
+}
+
+// GetUserByEmail fetches a user by email address, case-insensitively
+func (s *Store) GetUserByEmail(email string) (*User, string, error) {
+	return s.scanUser(s.DB.QueryRow(
+		`SELECT id, username, email, COALESCE(full_name, ''), tenant_id, is_admin, password_hash, created_at FROM users WHERE LOWER(email) = LOWER($1)`, email))
+}
+
+// scanUser reads a user row and its password hash
+func (s *Store) scanUser(row *sql.Row) (*User, string, error) {
+	user := &User{}
+	var passwordHash string
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.FullName, &user.TenantID, &user.Admin, &passwordHash, &user.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrUserNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting user: %w", err)
+	}
+	return user, passwordHash, nil
+}
+
+// SetAdmin grants or withdraws a user's administrator access
+func (s *Store) SetAdmin(id int, admin bool) error {
+	result, err := s.DB.Exec(`UPDATE users SET is_admin = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id, admin)
+	if err != nil {
+		return fmt.Errorf("error updating user: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdateProfile changes a user's name and full name
+func (s *Store) UpdateProfile(id int, username, fullName string) error {
+	_, err := s.DB.Exec(
+		`UPDATE users SET username = $1, full_name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3`,
+		username, nullString(fullName), id,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrUserExists
+		}
+		return fmt.Errorf("error updating profile: %w", err)
+	}
+	return nil
+}
+
+// UpdatePassword replaces a user's password hash
+func (s *Store) UpdatePassword(id int, passwordHash string) error {
+	if _, err := s.DB.Exec(
+		`UPDATE users SET password_hash = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		passwordHash, id,
+	); err != nil {
+		return fmt.Errorf("error updating password: %w", err)
+	}
+	return nil
+}
+
+// refreshToken is a stored refresh token
+type refreshToken struct {
+	ID        int
+	UserID    int
+	Family    string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// AddRefreshToken stores the hash of a refresh token
+func (s *Store) AddRefreshToken(userID int, token, family string, expiresAt time.Time) (int, error) {
+	var id int
+	err := s.DB.QueryRow(
+		`INSERT INTO auth_tokens (user_id, token, family, expires_at) VALUES ($1, $2, $3, $4) RETURNING id`,
+		userID, hashToken(token), family, expiresAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error storing refresh token: %w", err)
+	}
+	return id, nil
+}
+
+// getRefreshToken looks a refresh token up by its value
+func (s *Store) getRefreshToken(token string) (*refreshToken, error) {
+	stored := &refreshToken{}
+	err := s.DB.QueryRow(
+		`SELECT id, user_id, COALESCE(family, ''), expires_at, revoked_at FROM auth_tokens WHERE token = $1`,
+		hashToken(token),
+	).Scan(&stored.ID, &stored.UserID, &stored.Family, &stored.ExpiresAt, &stored.RevokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting refresh token: %w", err)
+	}
+	return stored, nil
+}
+
+// RotateRefreshToken revokes a refresh token and stores its replacement in one
+// transaction. It fails with ErrTokenReused if the old token was already revoked,
+// which happens when a rotated token is presented again.
+func (s *Store) RotateRefreshToken(old *refreshToken, token string, expiresAt time.Time) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	if err := tx.QueryRow(
+		`INSERT INTO auth_tokens (user_id, token, family, expires_at) VALUES ($1, $2, $3, $4) RETURNING id`,
+		old.UserID, hashToken(token), old.Family, expiresAt,
+	).Scan(&id); err != nil {
+		return fmt.Errorf("error storing refresh token: %w", err)
+	}
+
+	// Only one of two concurrent refreshes with the same token can win
+	result, err := tx.Exec(
+		`UPDATE auth_tokens SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $1 WHERE id = $2 AND revoked_at IS NULL`,
+		id, old.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error revoking refresh token: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrTokenReused
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeFamily revokes every refresh token issued from one login
+func (s *Store) RevokeFamily(family string) error {
+	if _, err := s.DB.Exec(
+		`UPDATE auth_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family = $1 AND revoked_at IS NULL`, family,
+	); err != nil {
+		return fmt.Errorf("error revoking session: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserTokens revokes every refresh token a user holds
+func (s *Store) RevokeUserTokens(userID int) error {
+	if _, err := s.DB.Exec(
+		`UPDATE auth_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`, userID,
+	); err != nil {
+		return fmt.Errorf("error revoking sessions: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccessToken blacklists an access token until it expires, pruning
+// entries that have expired on their own
+func (s *Store) RevokeAccessToken(claims *Claims) error {
+	if _, err := s.DB.Exec(
+		`INSERT INTO revoked_access_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3) ON CONFLICT (jti) DO NOTHING`,
+		claims.ID, claims.Subject, claims.Expires(),
+	); err != nil {
+		return fmt.Errorf("error revoking access token: %w", err)
+	}
+
+	if _, err := s.DB.Exec(`DELETE FROM revoked_access_tokens WHERE expires_at < CURRENT_TIMESTAMP`); err != nil {
+		return fmt.Errorf("error pruning revoked access tokens: %w", err)
+	}
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether an access token was blacklisted
+func (s *Store) IsAccessTokenRevoked(jti string) (bool, error) {
+	var exists bool
+	if err := s.DB.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE jti = $1)`, jti,
+	).Scan(&exists); err != nil {
+		return false, fmt.Errorf("error checking access token: %w", err)
+	}
+	return exists, nil
+}
+
+// hashToken is how refresh tokens are stored, so a database leak exposes no usable tokens
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// isUniqueViolation reports whether an insert or update hit a unique constraint
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,327 @@
+// backend/internal/backfill/backfill.go
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+	"github.com/shivani-1505/zapier-clone/backend/internal/riskmatrix"
+)
+
+// Tables are the ServiceNow tables with a Jira mapping to backfill
+var Tables = []string{"sn_risk_risk", "sn_si_incident"}
+
+// TableSummary counts what the backfill did with one table's records
+type TableSummary struct {
+	Table   string `json:"table"`
+	Scanned int    `json:"scanned"`
+	// Existing records were already in the mapping store
+	Existing int `json:"existing"`
+	// Relinked records named their Jira issue in jira_ticket and were only added to the mapping store
+	Relinked int       `json:"relinked"`
+	Created  int       `json:"created"`
+	Failed   int       `json:"failed"`
+	Failures []Failure `json:"failures,omitempty"`
+}
+
+// Failure is a record that could not be backfilled
+type Failure struct {
+	SysID  string `json:"sys_id"`
+	Number string `json:"number,omitempty"`
+	Error  string `json:"error"`
+}
+
+// Report summarizes a backfill run
+type Report struct {
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	DryRun     bool            `json:"dry_run"`
+	Tables     []*TableSummary `json:"tables"`
+}
+
+// Failed returns the number of records that could not be backfilled
+func (r *Report) Failed() int {
+	failed := 0
+	for _, summary := range r.Tables {
+		failed += summary.Failed
+	}
+	return failed
+}
+
+// Backfiller creates Jira issues for ServiceNow records that were created
+// before the integration was deployed. Issues are built with the same field
+// mapping as new records, but nothing is posted to Slack: the records are
+// history, not news. Records already in the mapping store are skipped, so a
+// run can be repeated after a failure.
+type Backfiller struct {
+	ServiceNowClient *servicenow.Client
+	JiraClient       *jira.Client
+	FieldMapping     *mapping.Engine
+	Risks            jira.MappingStore
+	Incidents        *jira.IncidentJiraMapping
+	// Matrix rates risks by likelihood and impact as the server does; nil
+	// leaves their priority to the field mapping
+	Matrix *riskmatrix.Store
+	// PageSize is the number of records fetched per ServiceNow request
+	PageSize int
+	// Query is an encoded query that narrows the records, e.g. "active=true"
+	Query string
+	// Limit caps the issues created per table; 0 means no limit
+	Limit int
+	// BatchSize is the number of issues created per Jira bulk request, up to
+	// jira.MaxBulkIssues; 1 creates them one at a time
+	BatchSize int
+	// DryRun counts what would be created without writing anything
+	DryRun bool
+}
+
+// NewBackfiller creates a backfiller that pages 100 records at a time and
+// creates their issues in bulk
+func NewBackfiller(serviceNowClient *servicenow.Client, jiraClient *jira.Client, fieldMapping *mapping.Engine, risks jira.MappingStore, incidents *jira.IncidentJiraMapping) *Backfiller {
+	return &Backfiller{
+		ServiceNowClient: serviceNowClient,
+		JiraClient:       jiraClient,
+		FieldMapping:     fieldMapping,
+		Risks:            risks,
+		Incidents:        incidents,
+		PageSize:         100,
+		BatchSize:        jira.MaxBulkIssues,
+	}
+}
+
+// pendingIssue is a record whose Jira issue is created with the rest of its page
+type pendingIssue struct {
+	SysID  string
+	Number string
+	Ticket *jira.Ticket
+}
+
+// Run backfills the given tables in order. It stops early only when ctx is
+// cancelled; failures of single records are collected in the report.
+func (b *Backfiller) Run(ctx context.Context, tables []string) (*Report, error) {
+	for _, table := range tables {
+		if !supported(table) {
+			return nil, fmt.Errorf("table %s has no Jira mapping to backfill (supported: %s)", table, strings.Join(Tables, ", "))
+		}
+	}
+
+	if b.PageSize <= 0 {
+		b.PageSize = 100
+	}
+
+	ctx = logging.NewContext(ctx)
+	report := &Report{StartedAt: time.Now(), DryRun: b.DryRun}
+	for _, table := range tables {
+		summary, err := b.runTable(ctx, table)
+		report.Tables = append(report.Tables, summary)
+		if err != nil {
+			report.FinishedAt = time.Now()
+			return report, err
+		}
+	}
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// runTable walks one table, oldest records first. New issues are created
+// PageSize at a time, so no more than a page of records is held in memory.
+func (b *Backfiller) runTable(ctx context.Context, table string) (*TableSummary, error) {
+	summary := &TableSummary{Table: table}
+	serviceNowClient := b.ServiceNowClient.WithContext(ctx)
+	jiraClient := b.JiraClient.WithContext(ctx)
+	logger := logging.FromContext(ctx).With("table", table)
+
+	query := servicenow.NewQuery().Encoded(b.Query).OrderBy("sys_created_on").OrderBy("sys_id")
+	records := serviceNowClient.Records(table, servicenow.ListOptions{Query: query.String(), PageSize: b.PageSize})
+
+	var pending []pendingIssue
+	flush := func() {
+		b.createIssues(jiraClient, table, pending, summary)
+		pending = nil
+		logger.Info("backfill progress", "scanned", summary.Scanned, "created", summary.Created)
+	}
+
+	for records.Next() {
+		if b.Limit > 0 && summary.Created+len(pending) >= b.Limit {
+			flush()
+			logger.Info("backfill limit reached", "limit", b.Limit)
+			return summary, nil
+		}
+		summary.Scanned++
+		if issue, ok := b.backfillRecord(table, records.Record(), summary); ok {
+			pending = append(pending, issue)
+		}
+		if len(pending) >= b.PageSize {
+			flush()
+		}
+	}
+	flush()
+
+	if err := records.Err(); err != nil {
+		if ctx.Err() != nil {
+			return summary, ctx.Err()
+		}
+		return summary, err
+	}
+	return summary, nil
+}
+
+// backfillRecord links one record to its Jira issue. A record that needs a new
+// issue is returned for createIssues.
+func (b *Backfiller) backfillRecord(table string, record map[string]interface{}, summary *TableSummary) (pendingIssue, bool) {
+	sysID := stringField(record, "sys_id")
+	number := stringField(record, "number")
+
+	if sysID == "" {
+		summary.fail(sysID, number, fmt.Errorf("record has no sys_id"))
+		return pendingIssue{}, false
+	}
+	if _, linked := b.lookup(table, sysID); linked {
+		summary.Existing++
+		return pendingIssue{}, false
+	}
+
+	// Records synced by an earlier deployment name their issue; trust it rather than duplicate it
+	if key := stringField(record, "jira_ticket"); key != "" {
+		if !b.DryRun {
+			if err := b.link(table, sysID, key); err != nil {
+				summary.fail(sysID, number, err)
+				return pendingIssue{}, false
+			}
+		}
+		summary.Relinked++
+		return pendingIssue{}, false
+	}
+
+	if b.DryRun {
+		summary.Created++
+		return pendingIssue{}, false
+	}
+
+	ticket, err := b.ticket(table, record)
+	if err != nil {
+		summary.fail(sysID, number, fmt.Errorf("error creating Jira issue: %w", err))
+		return pendingIssue{}, false
+	}
+	return pendingIssue{SysID: sysID, Number: number, Ticket: ticket}, true
+}
+
+// createIssues creates a page's new issues, in bulk unless BatchSize is 1, and
+// links each one that was created
+func (b *Backfiller) createIssues(jiraClient *jira.Client, table string, pending []pendingIssue, summary *TableSummary) {
+	if len(pending) == 0 {
+		return
+	}
+
+	var results []jira.BulkResult
+	if b.BatchSize == 1 {
+		for _, issue := range pending {
+			ticket, err := jiraClient.CreateIssue(issue.Ticket)
+			results = append(results, jira.BulkResult{Ticket: ticket, Err: err})
+		}
+	} else {
+		tickets := make([]*jira.Ticket, len(pending))
+		for i, issue := range pending {
+			tickets[i] = issue.Ticket
+		}
+		results = jiraClient.CreateIssues(tickets, b.BatchSize)
+	}
+
+	for i, result := range results {
+		issue := pending[i]
+		if result.Err != nil {
+			summary.fail(issue.SysID, issue.Number, result.Err)
+			continue
+		}
+		if err := b.link(table, issue.SysID, result.Ticket.Key); err != nil {
+			summary.fail(issue.SysID, issue.Number, fmt.Errorf("created %s but could not store the mapping: %w", result.Ticket.Key, err))
+			continue
+		}
+		summary.Created++
+	}
+}
+
+// fail records a record that could not be backfilled
+func (s *TableSummary) fail(sysID, number string, err error) {
+	s.Failed++
+	s.Failures = append(s.Failures, Failure{SysID: sysID, Number: number, Error: err.Error()})
+}
+
+// ticket builds the Jira issue for a record the way the new-record flows do
+func (b *Backfiller) ticket(table string, record map[string]interface{}) (*jira.Ticket, error) {
+	ticket, err := b.FieldMapping.Ticket(table, servicenow.MappingRecord(table, record))
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Project == "" {
+		ticket.Project = b.JiraClient.ProjectKey
+	}
+	if table == "sn_si_incident" && ticket.Epic != nil && ticket.Epic.Color == "" {
+		ticket.Epic.Color = "red"
+	}
+	if table == "sn_risk_risk" && b.Matrix != nil {
+		likelihood, _ := record["likelihood"].(string)
+		impact, _ := record["impact"].(string)
+		if assessment, ok := b.Matrix.Get().Assess(likelihood, impact); ok {
+			if assessment.Priority != "" {
+				ticket.Priority = assessment.Priority
+			}
+			if field := b.JiraClient.RiskScoreField(); field != "" {
+				if ticket.Fields == nil {
+					ticket.Fields = make(map[string]interface{})
+				}
+				ticket.Fields[field] = assessment.Score
+			}
+		}
+	}
+	return ticket, nil
+}
+
+// lookup returns the Jira issue a record is mapped to
+func (b *Backfiller) lookup(table, sysID string) (string, bool) {
+	switch table {
+	case "sn_risk_risk":
+		return b.Risks.GetJiraKeyFromRiskID(sysID)
+	case "sn_si_incident":
+		return b.Incidents.GetJiraKeyFromIncidentID(sysID)
+	}
+	return "", false
+}
+
+// link stores a record's Jira issue in the table's mapping store
+func (b *Backfiller) link(table, sysID, jiraKey string) error {
+	switch table {
+	case "sn_risk_risk":
+		return b.Risks.AddMapping(sysID, jiraKey)
+	case "sn_si_incident":
+		return b.Incidents.AddMapping(sysID, jiraKey)
+	}
+	return fmt.Errorf("unsupported table: %s", table)
+}
+
+func supported(table string) bool {
+	for _, t := range Tables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// stringField reads a string value from a record, tolerating missing keys
+func stringField(record map[string]interface{}, field string) string {
+	switch v := record[field].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
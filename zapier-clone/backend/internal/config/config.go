@@ -0,0 +1,476 @@
+// backend/internal/config/config.go
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces secrets in the printed config
+const RedactedValue = "[redacted]"
+
+// projectKey matches Jira project keys
+var projectKey = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,9}$`)
+
+// projectKeyPrefix matches the start of provisioned project keys
+var projectKeyPrefix = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,5}$`)
+
+// secretName marks free-form env entries whose values are redacted
+var secretName = regexp.MustCompile(`(?i)(TOKEN|SECRET|PASSWORD|KEY)`)
+
+// Config is the server's settings file. Every setting also has an environment
+// variable, which overrides the file; Export hands the merged settings to the
+// packages that read the environment.
+type Config struct {
+	Server     ServerConfig     `yaml:"server"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Tenants    TenantsConfig    `yaml:"tenants"`
+	ServiceNow ServiceNowConfig `yaml:"servicenow"`
+	Jira       JiraConfig       `yaml:"jira"`
+	Slack      SlackConfig      `yaml:"slack"`
+	GitHub     GitHubConfig     `yaml:"github"`
+	Teams      TeamsConfig      `yaml:"teams"`
+	Secrets    SecretsConfig    `yaml:"secrets"`
+	// Env sets any other variable the server reads, e.g. SLACK_DIGEST_INTERVAL
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// path is the file the config was read from; empty for environment-only setups
+	path string
+	// secrets holds the values of settings given as secret references
+	secrets *Secrets
+}
+
+// ServerConfig is the HTTP listener and logging
+type ServerConfig struct {
+	Addr      string `yaml:"addr,omitempty" env:"SERVER_ADDR"`
+	LogLevel  string `yaml:"log_level,omitempty" env:"LOG_LEVEL"`
+	LogFormat string `yaml:"log_format,omitempty" env:"LOG_FORMAT"`
+}
+
+// DatabaseConfig is the PostgreSQL database
+type DatabaseConfig struct {
+	URL                      string `yaml:"url,omitempty" env:"DATABASE_URL" secret:"true"`
+	ConnectionsEncryptionKey string `yaml:"connections_encryption_key,omitempty" env:"CONNECTIONS_ENCRYPTION_KEY" secret:"true"`
+}
+
+// TenantsConfig selects multi-tenant mode
+type TenantsConfig struct {
+	File string `yaml:"file,omitempty" env:"TENANTS_FILE"`
+	// Required turns off the default tenant built from this config
+	Required    bool   `yaml:"required,omitempty" env:"TENANT_REQUIRED"`
+	DefaultName string `yaml:"default_name,omitempty" env:"DEFAULT_TENANT_NAME"`
+}
+
+// ServiceNowConfig is the default tenant's ServiceNow instance
+type ServiceNowConfig struct {
+	URL          string `yaml:"url,omitempty" env:"SERVICENOW_URL"`
+	Username     string `yaml:"username,omitempty" env:"SERVICENOW_USERNAME"`
+	Password     string `yaml:"password,omitempty" env:"SERVICENOW_PASSWORD" secret:"true"`
+	WebhookToken string `yaml:"webhook_token,omitempty" env:"SERVICENOW_WEBHOOK_TOKEN" secret:"true"`
+	// Tables sends each table's tickets to a named Jira project instead of project_key
+	Tables map[string]string `yaml:"tables,omitempty" env:"SERVICENOW_TABLES"`
+}
+
+// JiraConfig is the default tenant's Jira site
+type JiraConfig struct {
+	URL        string `yaml:"url,omitempty" env:"JIRA_URL"`
+	Email      string `yaml:"email,omitempty" env:"JIRA_EMAIL"`
+	APIToken   string `yaml:"api_token,omitempty" env:"JIRA_API_TOKEN" secret:"true"`
+	ProjectKey string `yaml:"project_key,omitempty" env:"JIRA_PROJECT_KEY"`
+	APIVersion string `yaml:"api_version,omitempty" env:"JIRA_API_VERSION"`
+	// Projects names Jira project keys so servicenow.tables can refer to them
+	Projects      map[string]string `yaml:"projects,omitempty" env:"JIRA_PROJECTS"`
+	WebhookSecret string            `yaml:"webhook_secret,omitempty" env:"JIRA_WEBHOOK_SECRET" secret:"true"`
+	// AutoProvision gives tables without a project their own "project" or
+	// "component" on their first ticket
+	AutoProvision      string `yaml:"auto_provision,omitempty" env:"JIRA_AUTO_PROVISION"`
+	ProvisionKeyPrefix string `yaml:"provision_key_prefix,omitempty" env:"JIRA_PROVISION_KEY_PREFIX"`
+	ProvisionLead      string `yaml:"provision_lead,omitempty" env:"JIRA_PROVISION_LEAD"`
+}
+
+// SlackConfig is the default tenant's Slack workspace
+type SlackConfig struct {
+	Token         string `yaml:"token,omitempty" env:"SLACK_API_TOKEN" secret:"true"`
+	SigningSecret string `yaml:"signing_secret,omitempty" env:"SLACK_SIGNING_SECRET" secret:"true"`
+	TeamID        string `yaml:"team_id,omitempty" env:"SLACK_TEAM_ID"`
+}
+
+// GitHubConfig is the default tenant's issue repository
+type GitHubConfig struct {
+	Token         string `yaml:"token,omitempty" env:"GITHUB_TOKEN" secret:"true"`
+	Repo          string `yaml:"repo,omitempty" env:"GITHUB_REPO"`
+	APIURL        string `yaml:"api_url,omitempty" env:"GITHUB_API_URL"`
+	WebhookSecret string `yaml:"webhook_secret,omitempty" env:"GITHUB_WEBHOOK_SECRET" secret:"true"`
+}
+
+// TeamsConfig is the default tenant's Microsoft Teams bot
+type TeamsConfig struct {
+	AppID       string            `yaml:"app_id,omitempty" env:"TEAMS_APP_ID"`
+	AppPassword string            `yaml:"app_password,omitempty" env:"TEAMS_APP_PASSWORD" secret:"true"`
+	TenantID    string            `yaml:"tenant_id,omitempty" env:"TEAMS_TENANT_ID"`
+	ServiceURL  string            `yaml:"service_url,omitempty" env:"TEAMS_SERVICE_URL"`
+	Channels    map[string]string `yaml:"channels,omitempty" env:"TEAMS_CHANNELS"`
+	Default     bool              `yaml:"default,omitempty" env:"TEAMS_DEFAULT"`
+}
+
+// SecretsConfig is where secret references are resolved. Settings written as
+// "vault:<path>#<key>" or "awssm:<secret id>#<key>" are read from there.
+type SecretsConfig struct {
+	VaultAddr  string `yaml:"vault_addr,omitempty" env:"VAULT_ADDR"`
+	VaultToken string `yaml:"vault_token,omitempty" env:"VAULT_TOKEN" secret:"true"`
+	// VaultTokenFile is read on every request, e.g. the sink of a Vault agent
+	VaultTokenFile string `yaml:"vault_token_file,omitempty" env:"VAULT_TOKEN_FILE"`
+	VaultNamespace string `yaml:"vault_namespace,omitempty" env:"VAULT_NAMESPACE"`
+	// AWSRegion selects the Secrets Manager endpoint; keys come from AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+	AWSRegion string `yaml:"aws_region,omitempty" env:"AWS_REGION"`
+	// RefreshInterval is how often references are read again to pick up
+	// rotated secrets; "0" reads them only at startup. Defaults to 5m.
+	RefreshInterval string `yaml:"refresh_interval,omitempty" env:"SECRETS_REFRESH_INTERVAL"`
+}
+
+// setting is one env-backed field of the config
+type setting struct {
+	// Key is the setting's place in the file, e.g. "jira.api_token"
+	Key    string
+	Env    string
+	Secret bool
+	value  reflect.Value
+}
+
+// Load reads a YAML (or JSON) config file and applies environment overrides.
+// An empty path reads the environment alone. ${VAR} references in the file are
+// expanded, so secrets can stay out of it.
+func Load(path string) (*Config, error) {
+	cfg := &Config{path: path}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		// Misspelled keys would otherwise be silently ignored
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+
+	for _, s := range cfg.settings() {
+		expandEnv(s.value)
+		raw, ok := os.LookupEnv(s.Env)
+		if !ok {
+			continue
+		}
+		if err := set(s.value, raw); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", s.Env, err)
+		}
+	}
+	for name, value := range cfg.Env {
+		if raw, ok := os.LookupEnv(name); ok {
+			cfg.Env[name] = raw
+		} else {
+			cfg.Env[name] = os.ExpandEnv(value)
+		}
+	}
+
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadFromEnv loads the file named by CONFIG_FILE, or the environment alone
+// when it is unset
+func LoadFromEnv() (*Config, error) {
+	return Load(os.Getenv("CONFIG_FILE"))
+}
+
+// Path returns the file the config was read from
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Validate checks that the default tenant's credentials are present and that
+// URLs, project keys and table routes are well formed. Every problem is
+// reported, not just the first.
+func (c *Config) Validate() error {
+	var problems []string
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	byKey := make(map[string]setting)
+	for _, s := range c.settings() {
+		byKey[s.Key] = s
+	}
+	require := func(keys ...string) {
+		for _, key := range keys {
+			if s := byKey[key]; s.value.IsZero() {
+				problem("%s (%s) is required", key, s.Env)
+			}
+		}
+	}
+	checkURL := func(key, value string) {
+		if value == "" {
+			return
+		}
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problem("%s (%s) must be an http or https URL, got %q", key, byKey[key].Env, value)
+		}
+	}
+
+	// Without the default tenant every tenant comes from tenants.file, which
+	// is checked when the tenant registry loads it
+	if !c.Tenants.Required {
+		require("servicenow.url", "servicenow.username", "servicenow.password",
+			"jira.url", "jira.email", "jira.api_token", "jira.project_key")
+	} else if c.Tenants.File == "" {
+		problem("tenants.required (TENANT_REQUIRED) needs tenants.file (TENANTS_FILE)")
+	}
+	if c.Tenants.File != "" {
+		if _, err := os.Stat(c.Tenants.File); err != nil {
+			problem("tenants.file (TENANTS_FILE): %v", err)
+		}
+	}
+
+	checkURL("servicenow.url", c.ServiceNow.URL)
+	checkURL("jira.url", c.Jira.URL)
+	checkURL("github.api_url", c.GitHub.APIURL)
+	checkURL("teams.service_url", c.Teams.ServiceURL)
+	checkURL("secrets.vault_addr", c.Secrets.VaultAddr)
+	if interval := c.Secrets.RefreshInterval; interval != "" {
+		if d, err := time.ParseDuration(interval); err != nil || d < 0 {
+			problem("secrets.refresh_interval (SECRETS_REFRESH_INTERVAL) must be a duration such as 5m, got %q", interval)
+		}
+	}
+
+	if key := c.Jira.ProjectKey; key != "" && !projectKey.MatchString(key) {
+		problem("jira.project_key (JIRA_PROJECT_KEY) %q is not a Jira project key", key)
+	}
+	if v := c.Jira.APIVersion; v != "" && v != "2" && v != "3" {
+		problem("jira.api_version (JIRA_API_VERSION) must be 2 or 3, got %q", v)
+	}
+	for _, name := range sortedKeys(c.Jira.Projects) {
+		if key := c.Jira.Projects[name]; !projectKey.MatchString(key) {
+			problem("jira.projects (JIRA_PROJECTS): %s: %q is not a Jira project key", name, key)
+		}
+	}
+	if mode := c.Jira.AutoProvision; mode != "" && mode != "project" && mode != "component" {
+		problem("jira.auto_provision (JIRA_AUTO_PROVISION) must be project or component, got %q", mode)
+	}
+	if prefix := c.Jira.ProvisionKeyPrefix; prefix != "" && !projectKeyPrefix.MatchString(prefix) {
+		problem("jira.provision_key_prefix (JIRA_PROVISION_KEY_PREFIX) must be up to 6 capital letters or digits, starting with a letter, got %q", prefix)
+	}
+	for _, table := range sortedKeys(c.ServiceNow.Tables) {
+		if name := c.ServiceNow.Tables[table]; c.Jira.Projects[name] == "" {
+			problem("servicenow.tables (SERVICENOW_TABLES): %s: %q is not in jira.projects", table, name)
+		}
+	}
+
+	if repo := c.GitHub.Repo; repo != "" {
+		owner, name, ok := strings.Cut(repo, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			problem("github.repo (GITHUB_REPO) must be owner/name, got %q", repo)
+		}
+	}
+	if c.Teams.AppID != "" && c.Teams.AppPassword == "" {
+		problem("teams.app_password (TEAMS_APP_PASSWORD) is required with teams.app_id")
+	}
+
+	switch strings.ToLower(c.Server.LogLevel) {
+	case "", "debug", "info", "warn", "error":
+	default:
+		problem("server.log_level (LOG_LEVEL) must be debug, info, warn or error, got %q", c.Server.LogLevel)
+	}
+	switch strings.ToLower(c.Server.LogFormat) {
+	case "", "json", "text":
+	default:
+		problem("server.log_format (LOG_FORMAT) must be json or text, got %q", c.Server.LogFormat)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// Export sets the environment variable of every setting that came from the
+// file, so packages that read the environment see it. Variables already set
+// are left alone, unless they held a secret reference, which is replaced by
+// the secret.
+func (c *Config) Export() error {
+	for _, s := range c.settings() {
+		if _, ok := os.LookupEnv(s.Env); (ok && !c.secrets.has(s.Env)) || s.value.IsZero() {
+			continue
+		}
+		if err := os.Setenv(s.Env, format(s.value)); err != nil {
+			return fmt.Errorf("error setting %s: %w", s.Env, err)
+		}
+	}
+	for name, value := range c.Env {
+		if _, ok := os.LookupEnv(name); ok && !c.secrets.has(name) {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return fmt.Errorf("error setting %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Redacted returns a copy of the config with secrets replaced by RedactedValue.
+// Settings read from a secret reference show the reference instead.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	for _, s := range redacted.settings() {
+		if ref, ok := c.secrets.reference(s.Env); ok {
+			s.value.SetString(ref)
+		} else if s.Secret && !s.value.IsZero() {
+			s.value.SetString(RedactedValue)
+		}
+	}
+	if c.Env != nil {
+		redacted.Env = make(map[string]string, len(c.Env))
+		for name, value := range c.Env {
+			if ref, ok := c.secrets.reference(name); ok {
+				value = ref
+			} else if secretName.MatchString(name) && value != "" {
+				value = RedactedValue
+			}
+			redacted.Env[name] = value
+		}
+	}
+	return &redacted
+}
+
+// Print writes the effective config as YAML with secrets redacted
+func (c *Config) Print(w io.Writer) error {
+	source := "environment only"
+	if c.path != "" {
+		source = c.path + " with environment overrides"
+	}
+	fmt.Fprintf(w, "# Effective configuration (%s)\n", source)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return fmt.Errorf("error printing config: %w", err)
+	}
+	return encoder.Close()
+}
+
+// settings lists the env-backed fields of every section
+func (c *Config) settings() []setting {
+	var list []setting
+	root := reflect.ValueOf(c).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Type().Field(i)
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+		sectionName, _, _ := strings.Cut(section.Tag.Get("yaml"), ",")
+		for j := 0; j < section.Type.NumField(); j++ {
+			field := section.Type.Field(j)
+			env := field.Tag.Get("env")
+			if env == "" {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			list = append(list, setting{
+				Key:    sectionName + "." + name,
+				Env:    env,
+				Secret: field.Tag.Get("secret") == "true",
+				value:  root.Field(i).Field(j),
+			})
+		}
+	}
+	return list
+}
+
+// set parses an environment value into a field; maps are "key=value,key=value"
+func set(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		if raw == "" {
+			field.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		field.SetBool(b)
+	case reflect.Map:
+		field.Set(reflect.ValueOf(parsePairs(raw)))
+	}
+	return nil
+}
+
+// format prints a field the way set parses it
+func format(field reflect.Value) string {
+	switch field.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool())
+	case reflect.Map:
+		pairs := field.Interface().(map[string]string)
+		list := make([]string, 0, len(pairs))
+		for _, key := range sortedKeys(pairs) {
+			list = append(list, key+"="+pairs[key])
+		}
+		return strings.Join(list, ",")
+	default:
+		return field.String()
+	}
+}
+
+// expandEnv resolves ${VAR} references in a string or map field
+func expandEnv(field reflect.Value) {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(os.ExpandEnv(field.String()))
+	case reflect.Map:
+		if pairs, ok := field.Interface().(map[string]string); ok {
+			for key, value := range pairs {
+				pairs[key] = os.ExpandEnv(value)
+			}
+		}
+	}
+}
+
+// parsePairs reads "key=value,key=value"
+func parsePairs(value string) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, v, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(key) != "" {
+			pairs[strings.TrimSpace(key)] = strings.TrimSpace(v)
+		}
+	}
+	return pairs
+}
+
+// sortedKeys returns a map's keys in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
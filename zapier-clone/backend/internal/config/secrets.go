@@ -0,0 +1,278 @@
+// backend/internal/config/secrets.go
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secret reference schemes
+const (
+	SchemeVault = "vault"
+	SchemeAWS   = "awssm"
+)
+
+// DefaultRefreshInterval is how often secret references are read again
+const DefaultRefreshInterval = 5 * time.Minute
+
+// ErrSecretNotFound is returned when a reference names a missing secret or key
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Reference is a parsed secret reference such as "vault:secret/data/jira#token"
+type Reference struct {
+	Scheme string
+	// Path is the Vault path or the Secrets Manager secret ID
+	Path string
+	// Key picks a field of the secret; for Secrets Manager an empty key takes the whole string
+	Key string
+}
+
+// ParseReference reads a secret reference; ok is false for ordinary values
+func ParseReference(value string) (Reference, bool) {
+	scheme, rest, ok := strings.Cut(value, ":")
+	if !ok || (scheme != SchemeVault && scheme != SchemeAWS) || rest == "" {
+		return Reference{}, false
+	}
+	path, key, _ := strings.Cut(rest, "#")
+	return Reference{Scheme: scheme, Path: path, Key: key}, true
+}
+
+// String formats the reference the way it is written in config
+func (r Reference) String() string {
+	if r.Key == "" {
+		return r.Scheme + ":" + r.Path
+	}
+	return r.Scheme + ":" + r.Path + "#" + r.Key
+}
+
+// SecretStore reads one secret from a secrets manager
+type SecretStore interface {
+	Read(ctx context.Context, ref Reference) (string, error)
+}
+
+// Secrets holds the settings that were given as secret references and keeps
+// them current. When a secret is rotated its environment variable is updated
+// and the callbacks registered with OnRotate run.
+type Secrets struct {
+	stores map[string]SecretStore
+	// refs maps environment variable names to the reference they were read from
+	refs map[string]Reference
+
+	mu        sync.Mutex
+	values    map[string]string
+	callbacks map[string][]func(string)
+	stop      chan struct{}
+}
+
+// has reports whether an environment variable's setting came from a reference
+func (s *Secrets) has(env string) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.refs[env]
+	return ok
+}
+
+// reference returns the reference an environment variable's setting came from
+func (s *Secrets) reference(env string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	ref, ok := s.refs[env]
+	return ref.String(), ok
+}
+
+// Names lists the environment variables set from secret references
+func (s *Secrets) Names() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.refs))
+	for name := range s.refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OnRotate registers fn to receive the new value whenever the secret behind
+// an environment variable changes. Variables not set from a reference never rotate.
+func (s *Secrets) OnRotate(env string, fn func(value string)) {
+	if !s.has(env) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks[env] = append(s.callbacks[env], fn)
+}
+
+// Start reads the references again every interval until Stop is called
+func (s *Secrets) Start(interval time.Duration) {
+	if s == nil || len(s.refs) == 0 || interval <= 0 {
+		return
+	}
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	stop := s.stop
+	s.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.Refresh(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop ends the refresh loop
+func (s *Secrets) Stop() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// Refresh reads every reference once. A secret that cannot be read keeps its
+// current value, so an unreachable secrets manager does not break running clients.
+func (s *Secrets) Refresh(ctx context.Context) {
+	for _, env := range s.Names() {
+		ref := s.refs[env]
+		value, err := s.read(ctx, ref)
+		if err != nil {
+			slog.Warn("error refreshing secret", "env", env, "reference", ref.String(), "error", err)
+			continue
+		}
+
+		s.mu.Lock()
+		changed := s.values[env] != value
+		s.values[env] = value
+		callbacks := append([]func(string){}, s.callbacks[env]...)
+		s.mu.Unlock()
+		if !changed {
+			continue
+		}
+
+		os.Setenv(env, value)
+		for _, fn := range callbacks {
+			fn(value)
+		}
+		slog.Info("secret rotated", "env", env, "reference", ref.String())
+	}
+}
+
+// read fetches one reference from its store
+func (s *Secrets) read(ctx context.Context, ref Reference) (string, error) {
+	store, ok := s.stores[ref.Scheme]
+	if !ok || store == nil {
+		switch ref.Scheme {
+		case SchemeVault:
+			return "", fmt.Errorf("%s needs secrets.vault_addr (VAULT_ADDR)", ref)
+		default:
+			return "", fmt.Errorf("%s needs secrets.aws_region (AWS_REGION)", ref)
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return store.Read(ctx, ref)
+}
+
+// Resolved returns the settings read from secret references; nil when there are none
+func (c *Config) Resolved() *Secrets {
+	return c.secrets
+}
+
+// RefreshInterval returns how often secret references are read again
+func (c *Config) RefreshInterval() time.Duration {
+	if c.Secrets.RefreshInterval == "" {
+		return DefaultRefreshInterval
+	}
+	d, err := time.ParseDuration(c.Secrets.RefreshInterval)
+	if err != nil {
+		return DefaultRefreshInterval
+	}
+	return d
+}
+
+// resolveSecrets replaces settings written as secret references with the
+// secrets they name. The secrets section itself cannot use references.
+func (c *Config) resolveSecrets() error {
+	secrets := &Secrets{
+		stores:    c.secretStores(),
+		refs:      make(map[string]Reference),
+		values:    make(map[string]string),
+		callbacks: make(map[string][]func(string)),
+	}
+
+	var problems []string
+	resolve := func(env, key, value string) (string, bool) {
+		ref, ok := ParseReference(value)
+		if !ok {
+			return value, false
+		}
+		secret, err := secrets.read(context.Background(), ref)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s (%s): %v", key, env, err))
+			return value, false
+		}
+		secrets.refs[env] = ref
+		secrets.values[env] = secret
+		return secret, true
+	}
+
+	for _, s := range c.settings() {
+		if strings.HasPrefix(s.Key, "secrets.") || s.value.Kind() != reflect.String {
+			continue
+		}
+		if secret, ok := resolve(s.Env, s.Key, s.value.String()); ok {
+			s.value.SetString(secret)
+		}
+	}
+	for _, name := range sortedKeys(c.Env) {
+		if secret, ok := resolve(name, "env."+name, c.Env[name]); ok {
+			c.Env[name] = secret
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("error resolving secrets:\n  %s", strings.Join(problems, "\n  "))
+	}
+	if len(secrets.refs) > 0 {
+		c.secrets = secrets
+	}
+	return nil
+}
+
+// secretStores builds a store for each secrets manager that is configured
+func (c *Config) secretStores() map[string]SecretStore {
+	stores := make(map[string]SecretStore)
+	if c.Secrets.VaultAddr != "" {
+		stores[SchemeVault] = NewVaultStore(c.Secrets.VaultAddr, c.Secrets.VaultToken, c.Secrets.VaultTokenFile, c.Secrets.VaultNamespace)
+	}
+	if c.Secrets.AWSRegion != "" {
+		stores[SchemeAWS] = NewAWSStoreFromEnv(c.Secrets.AWSRegion)
+	}
+	return stores
+}
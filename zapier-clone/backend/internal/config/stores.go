@@ -0,0 +1,253 @@
+// backend/internal/config/stores.go
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VaultStore reads secrets from HashiCorp Vault's KV engine, version 1 or 2
+type VaultStore struct {
+	Addr       string
+	Token      string
+	TokenFile  string
+	Namespace  string
+	HTTPClient *http.Client
+}
+
+// NewVaultStore creates a Vault store. A token file, when set, is read for
+// every request so renewed tokens are picked up.
+func NewVaultStore(addr, token, tokenFile, namespace string) *VaultStore {
+	return &VaultStore{
+		Addr:       strings.TrimRight(addr, "/"),
+		Token:      token,
+		TokenFile:  tokenFile,
+		Namespace:  namespace,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Read implements SecretStore. "vault:secret/data/jira#token" reads the token
+// field of /v1/secret/data/jira.
+func (v *VaultStore) Read(ctx context.Context, ref Reference) (string, error) {
+	if ref.Key == "" {
+		return "", fmt.Errorf("%s: Vault references need a #key", ref)
+	}
+	token := v.Token
+	if v.TokenFile != "" {
+		data, err := os.ReadFile(v.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("error reading Vault token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.Addr+"/v1/"+strings.TrimLeft(ref.Path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	resp, err := v.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error contacting Vault: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, ref)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("Vault returned %d for %s: %s", resp.StatusCode, ref.Path, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("error parsing Vault response: %w", err)
+	}
+	// KV version 2 nests the fields under data.data, next to data.metadata
+	fields := secret.Data
+	if inner, ok := fields["data"].(map[string]interface{}); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = inner
+		}
+	}
+	return secretField(fields, ref)
+}
+
+// awsService is the Secrets Manager signing name
+const awsService = "secretsmanager"
+
+// AWSStore reads secrets from AWS Secrets Manager
+type AWSStore struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Endpoint overrides the regional endpoint, e.g. for a VPC endpoint
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// NewAWSStoreFromEnv creates a Secrets Manager store with the standard AWS
+// credential variables
+func NewAWSStoreFromEnv(region string) *AWSStore {
+	endpoint := os.Getenv("AWS_SECRETS_MANAGER_ENDPOINT")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+	return &AWSStore{
+		Region:          region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Endpoint:        strings.TrimRight(endpoint, "/"),
+		HTTPClient:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Read implements SecretStore. "awssm:prod/jira#token" reads the token field
+// of a JSON secret; without a #key the whole secret string is used.
+func (a *AWSStore) Read(ctx context.Context, ref Reference) (string, error) {
+	if a.AccessKeyID == "" || a.SecretAccessKey == "" {
+		return "", fmt.Errorf("%s: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required", ref)
+	}
+	payload, err := json.Marshal(map[string]string{"SecretId": ref.Path})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.Endpoint+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("error creating Secrets Manager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	a.sign(req, payload, time.Now().UTC())
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error contacting Secrets Manager: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &apiErr)
+		if strings.HasSuffix(apiErr.Type, "ResourceNotFoundException") {
+			return "", fmt.Errorf("%w: %s", ErrSecretNotFound, ref)
+		}
+		return "", fmt.Errorf("Secrets Manager returned %d for %s: %s %s", resp.StatusCode, ref.Path, apiErr.Type, apiErr.Message)
+	}
+
+	var secret struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("error parsing Secrets Manager response: %w", err)
+	}
+	if ref.Key == "" {
+		return secret.SecretString, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("%s: secret is not a JSON object, so it has no key %q", ref.Path, ref.Key)
+	}
+	return secretField(fields, ref)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header
+func (a *AWSStore) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if a.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, req.URL.RawQuery, canonicalHeaders.String(), signedHeaders, hashHex(payload),
+	}, "\n")
+
+	scope := date + "/" + a.Region + "/" + awsService + "/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+a.SecretAccessKey), date)
+	key = hmacSHA256(key, a.Region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		a.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// secretField picks a reference's key from a secret's fields
+func secretField(fields map[string]interface{}, ref Reference) (string, error) {
+	value, ok := fields[ref.Key]
+	if !ok {
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return "", fmt.Errorf("%w: %s has no key %q (keys: %s)", ErrSecretNotFound, ref.Path, ref.Key, strings.Join(keys, ", "))
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// hashHex returns the hex SHA-256 of data
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 signs data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,95 @@
+// backend/internal/connections/cipher.go
+package connections
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// cipherPrefix versions the ciphertext format so keys can be rotated later
+const cipherPrefix = "v1:"
+
+// Cipher encrypts credentials with AES-256-GCM
+type Cipher struct {
+	aead cipher.AEAD
+	// stateKey signs OAuth state tokens; it is derived from the encryption key
+	stateKey []byte
+}
+
+// NewCipher creates a cipher from a 32-byte key given as hex or base64
+func NewCipher(encodedKey string) (*Cipher, error) {
+	key, err := decodeKey(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GCM: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("oauth-state"))
+
+	return &Cipher{aead: aead, stateKey: mac.Sum(nil)}, nil
+}
+
+// Encrypt seals plaintext; a fresh nonce is prepended to the ciphertext
+func (c *Cipher) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+	return cipherPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt
+func (c *Cipher) Decrypt(value string) ([]byte, error) {
+	if !strings.HasPrefix(value, cipherPrefix) {
+		return nil, errors.New("unsupported ciphertext format")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, cipherPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding ciphertext: %w", err)
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting credentials: %w", err)
+	}
+	return plaintext, nil
+}
+
+// decodeKey accepts a 32-byte key as 64 hex characters or base64
+func decodeKey(encoded string) ([]byte, error) {
+	if encoded == "" {
+		return nil, errors.New("encryption key is empty")
+	}
+	if key, err := hex.DecodeString(encoded); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(encoded); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, errors.New("encryption key must be 32 bytes, hex or base64 encoded")
+}
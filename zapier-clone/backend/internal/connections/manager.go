@@ -0,0 +1,333 @@
+// backend/internal/connections/manager.go
+package connections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// Manager runs OAuth flows and keeps stored tokens fresh
+type Manager struct {
+	Store     *Store
+	Providers map[string]*Provider
+	// RefreshMargin refreshes tokens this long before they expire
+	RefreshMargin time.Duration
+	// RefreshInterval is how often the background refresher runs
+	RefreshInterval time.Duration
+	// StateTTL limits how long a user has to complete consent
+	StateTTL time.Duration
+
+	// refreshMutex serializes refreshes so rotating refresh tokens are used once
+	refreshMutex sync.Mutex
+	stop         chan struct{}
+}
+
+// NewManager creates a connection manager
+func NewManager(store *Store, providers map[string]*Provider) *Manager {
+	return &Manager{
+		Store:           store,
+		Providers:       providers,
+		RefreshMargin:   5 * time.Minute,
+		RefreshInterval: 5 * time.Minute,
+		StateTTL:        10 * time.Minute,
+		stop:            make(chan struct{}),
+	}
+}
+
+// ProviderNames lists the services with OAuth configured
+func (m *Manager) ProviderNames() []string {
+	names := make([]string, 0, len(m.Providers))
+	for name := range m.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartOAuth returns the consent URL and state for a user. connectionID, if not
+// zero, is the pending connection the callback should complete.
+func (m *Manager) StartOAuth(userID int, service string, connectionID int) (string, string, error) {
+	provider, ok := m.Providers[service]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, err := m.Store.Cipher.signState(oauthState{
+		UserID:       userID,
+		Service:      service,
+		ConnectionID: connectionID,
+		Expires:      time.Now().Add(m.StateTTL).Unix(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return provider.AuthCodeURL(state), state, nil
+}
+
+// CompleteOAuth exchanges the authorization code and stores the tokens on the
+// pending connection (or a new one)
+func (m *Manager) CompleteOAuth(ctx context.Context, userID int, service, code, stateToken string) (*Connection, error) {
+	provider, ok := m.Providers[service]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	state, err := m.Store.Cipher.verifyState(stateToken, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if state.UserID != userID || state.Service != service {
+		return nil, ErrInvalidState
+	}
+
+	creds, raw, err := provider.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{"scopes": creds.Scope}
+	if provider.Describe != nil {
+		described, err := provider.Describe(ctx, provider, creds, raw)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s account details: %w", service, err)
+		}
+		metadata = described
+	}
+
+	conn, err := m.pendingConnection(userID, service, state.ConnectionID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if conn == nil {
+		conn = &Connection{
+			UserID:   userID,
+			Name:     defaultName(service, metadata),
+			Service:  service,
+			AuthType: AuthOAuth,
+		}
+	}
+	conn.Status = StatusActive
+	conn.Credentials = creds
+	conn.ExpiresAt = creds.ExpiresAt
+	conn.Metadata = MergeMetadata(conn.Metadata, metadata)
+
+	if conn.ID == 0 {
+		err = m.Store.Create(conn)
+	} else {
+		err = m.Store.Update(conn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("OAuth connection completed", "connection", conn.ID, "service", service, "user_id", userID)
+	return conn, nil
+}
+
+// pendingConnection finds the connection an OAuth callback completes
+func (m *Manager) pendingConnection(userID int, service string, connectionID int) (*Connection, error) {
+	if connectionID != 0 {
+		conn, err := m.Store.Get(connectionID)
+		if err != nil {
+			return nil, err
+		}
+		if conn.UserID != userID || conn.Service != service || conn.AuthType != AuthOAuth {
+			return nil, ErrInvalidState
+		}
+		return conn, nil
+	}
+	return m.Store.FindPending(userID, service)
+}
+
+// AccessToken returns a usable token for a connection, refreshing it first if
+// it is about to expire
+func (m *Manager) AccessToken(ctx context.Context, id int) (string, error) {
+	conn, err := m.Store.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if conn.Status != StatusActive || conn.Credentials.AccessToken == "" {
+		return "", ErrNotConnected
+	}
+
+	if conn.Credentials.Expired(m.RefreshMargin) {
+		if conn, err = m.refresh(ctx, id, m.RefreshMargin); err != nil {
+			return "", err
+		}
+	}
+
+	if err := m.Store.TouchLastUsed(id); err != nil {
+		logging.FromContext(ctx).Warn("error recording connection use", "connection", id, "error", err)
+	}
+	return conn.Credentials.AccessToken, nil
+}
+
+// refresh renews a connection's token if it expires within margin. The connection is
+// re-read under the lock because another caller may have refreshed (and rotated the
+// refresh token) already.
+func (m *Manager) refresh(ctx context.Context, id int, margin time.Duration) (*Connection, error) {
+	m.refreshMutex.Lock()
+	defer m.refreshMutex.Unlock()
+
+	conn, err := m.Store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !conn.Credentials.Expired(margin) {
+		return conn, nil
+	}
+
+	provider, ok := m.Providers[conn.Service]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	if conn.Credentials.RefreshToken == "" {
+		m.markExpired(conn, "no refresh token")
+		return nil, ErrNotConnected
+	}
+
+	creds, err := provider.Refresh(ctx, conn.Credentials.RefreshToken)
+	if err != nil {
+		var tokenErr *TokenError
+		if errors.As(err, &tokenErr) && tokenErr.Permanent() {
+			m.markExpired(conn, tokenErr.Code)
+			return nil, fmt.Errorf("%w: %v", ErrNotConnected, err)
+		}
+		return nil, fmt.Errorf("error refreshing connection %d: %w", conn.ID, err)
+	}
+
+	if creds.Scope == "" {
+		creds.Scope = conn.Credentials.Scope
+	}
+	conn.Credentials = creds
+	if err := m.Store.Update(conn); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// markExpired flags a connection whose refresh can never succeed
+func (m *Manager) markExpired(conn *Connection, reason string) {
+	slog.Warn("connection can no longer be refreshed; the user must reconnect", "connection", conn.ID, "service", conn.Service, "reason", reason)
+	if err := m.Store.SetStatus(conn.ID, StatusExpired); err != nil {
+		slog.Error("error marking connection expired", "connection", conn.ID, "error", err)
+	}
+}
+
+// Start runs the background refresher
+func (m *Manager) Start() {
+	go func() {
+		ticker := time.NewTicker(m.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			m.RefreshDue()
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends the background refresher
+func (m *Manager) Stop() {
+	close(m.stop)
+}
+
+// RefreshDue refreshes every token that would expire before the next run
+func (m *Manager) RefreshDue() {
+	due, err := m.Store.DueForRefresh(time.Now().Add(m.RefreshInterval + m.RefreshMargin))
+	if err != nil {
+		slog.Error("error listing connections to refresh", "error", err)
+		return
+	}
+
+	for _, conn := range due {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		// Refresh ahead of the usual margin so tokens never lapse between runs
+		if _, err := m.refresh(ctx, conn.ID, m.RefreshInterval+m.RefreshMargin); err != nil {
+			logging.FromContext(ctx).Error("error refreshing connection", "connection", conn.ID, "service", conn.Service, "error", err)
+		}
+		cancel()
+	}
+}
+
+// Test checks that a connection's credentials still work
+func (m *Manager) Test(ctx context.Context, id int) error {
+	conn, err := m.Store.Get(id)
+	if err != nil {
+		return err
+	}
+	if conn.AuthType != AuthOAuth {
+		// API key credentials are checked by the integration clients when used
+		return nil
+	}
+
+	provider, ok := m.Providers[conn.Service]
+	if !ok {
+		return ErrUnknownProvider
+	}
+	if _, err := m.AccessToken(ctx, id); err != nil {
+		return err
+	}
+	if conn, err = m.Store.Get(id); err != nil {
+		return err
+	}
+	if provider.Verify == nil {
+		return nil
+	}
+	return provider.Verify(ctx, provider, conn.Credentials)
+}
+
+// Revoke invalidates a connection's token at the provider (when supported) and
+// wipes the stored credentials
+func (m *Manager) Revoke(ctx context.Context, id int) error {
+	conn, err := m.Store.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if provider, ok := m.Providers[conn.Service]; ok && provider.Revoke != nil && conn.Credentials.AccessToken != "" {
+		if err := provider.Revoke(ctx, provider, conn.Credentials); err != nil {
+			// The local credentials are wiped regardless; the token expires on its own
+			logging.FromContext(ctx).Warn("could not revoke token", "connection", id, "service", conn.Service, "error", err)
+		}
+	}
+
+	return m.Store.Revoke(id)
+}
+
+// defaultName names a new connection after the account it was granted for
+func defaultName(service string, metadata map[string]interface{}) string {
+	name := strings.ToUpper(service[:1]) + service[1:]
+	for _, key := range []string{"site_name", "team_name"} {
+		if account, ok := metadata[key].(string); ok && account != "" {
+			return fmt.Sprintf("%s (%s)", name, account)
+		}
+	}
+	return name
+}
+
+// MergeMetadata overlays fresh account details on existing metadata
+func MergeMetadata(existing, fresh map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(existing)+len(fresh))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range fresh {
+		merged[key] = value
+	}
+	return merged
+}
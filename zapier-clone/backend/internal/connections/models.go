@@ -0,0 +1,84 @@
+// backend/internal/connections/models.go
+package connections
+
+import (
+	"errors"
+	"time"
+)
+
+// Connection statuses
+const (
+	StatusPending = "pending" // OAuth flow started but not completed
+	StatusActive  = "active"
+	StatusExpired = "expired" // refresh failed; the user has to reconnect
+	StatusRevoked = "revoked"
+)
+
+// Environments a connection can point at. Workflows target one of them, so a
+// change can be tried against a sandbox site or project before production.
+const (
+	EnvironmentProduction = "production"
+	EnvironmentSandbox    = "sandbox"
+)
+
+// ValidEnvironment reports whether env names a known environment
+func ValidEnvironment(env string) bool {
+	return env == EnvironmentProduction || env == EnvironmentSandbox
+}
+
+// Authentication types
+const (
+	AuthOAuth  = "oauth"
+	AuthAPIKey = "api_key"
+)
+
+var (
+	// ErrNotFound is returned when a connection does not exist
+	ErrNotFound = errors.New("connection not found")
+	// ErrUnknownProvider is returned for services without an OAuth provider
+	ErrUnknownProvider = errors.New("unknown OAuth provider")
+	// ErrInvalidState is returned when an OAuth state token is forged or expired
+	ErrInvalidState = errors.New("invalid OAuth state")
+	// ErrNotConnected is returned when a connection has no usable credentials
+	ErrNotConnected = errors.New("connection is not active")
+	// ErrInvalidEnvironment is returned for environments other than production and sandbox
+	ErrInvalidEnvironment = errors.New("environment must be production or sandbox")
+)
+
+// Connection is a user's stored credential for one external service. The
+// credentials themselves are never serialized; see Credentials.
+type Connection struct {
+	ID       int    `json:"id"`
+	UserID   int    `json:"user_id"`
+	Name     string `json:"name"`
+	Service  string `json:"service"`
+	Status   string `json:"status"`
+	AuthType string `json:"auth_type"`
+	// Environment is production (the default) or sandbox
+	Environment string                 `json:"environment"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time             `json:"revoked_at,omitempty"`
+	LastUsedAt  *time.Time             `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+
+	Credentials *Credentials `json:"-"`
+}
+
+// Credentials are stored encrypted in connections.auth_data
+type Credentials struct {
+	AccessToken  string     `json:"access_token,omitempty"`
+	RefreshToken string     `json:"refresh_token,omitempty"`
+	TokenType    string     `json:"token_type,omitempty"`
+	Scope        string     `json:"scope,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+
+	// Fields holds API key style credentials (e.g. email and api_token)
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Expired reports whether the access token expires within the given margin
+func (c *Credentials) Expired(margin time.Duration) bool {
+	return c.ExpiresAt != nil && time.Until(*c.ExpiresAt) < margin
+}
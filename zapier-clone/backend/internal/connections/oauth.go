@@ -0,0 +1,213 @@
+// backend/internal/connections/oauth.go
+package connections
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Provider runs the OAuth 2.0 authorization code flow for one service
+type Provider struct {
+	Name         string
+	AuthURL      string
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// ScopeSeparator joins Scopes in the authorize URL (space unless the provider differs)
+	ScopeSeparator string
+	// AuthParams are extra query parameters for the authorize URL
+	AuthParams map[string]string
+	// TokenJSON sends token requests as JSON instead of a form
+	TokenJSON bool
+
+	// Describe returns account details (site, workspace) kept as connection metadata
+	Describe func(ctx context.Context, p *Provider, creds *Credentials, raw map[string]interface{}) (map[string]interface{}, error)
+	// Verify checks that a token still works
+	Verify func(ctx context.Context, p *Provider, creds *Credentials) error
+	// Revoke invalidates a token at the provider, if the provider supports it
+	Revoke func(ctx context.Context, p *Provider, creds *Credentials) error
+
+	HTTPClient *http.Client
+}
+
+// TokenError is an error response from a token endpoint
+type TokenError struct {
+	Code        string
+	Description string
+	StatusCode  int
+}
+
+func (e *TokenError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("token request failed: %s (%s)", e.Code, e.Description)
+	}
+	return fmt.Sprintf("token request failed: %s", e.Code)
+}
+
+// Permanent reports whether retrying cannot succeed and the user must reconnect
+func (e *TokenError) Permanent() bool {
+	switch e.Code {
+	case "invalid_grant", "invalid_refresh_token", "token_revoked", "invalid_client":
+		return true
+	}
+	return false
+}
+
+// AuthCodeURL builds the URL the user is sent to for consent
+func (p *Provider) AuthCodeURL(state string) string {
+	separator := p.ScopeSeparator
+	if separator == "" {
+		separator = " "
+	}
+
+	query := url.Values{}
+	query.Set("client_id", p.ClientID)
+	query.Set("redirect_uri", p.RedirectURL)
+	query.Set("response_type", "code")
+	query.Set("state", state)
+	if len(p.Scopes) > 0 {
+		query.Set("scope", strings.Join(p.Scopes, separator))
+	}
+	for key, value := range p.AuthParams {
+		query.Set(key, value)
+	}
+
+	return p.AuthURL + "?" + query.Encode()
+}
+
+// Exchange trades an authorization code for tokens
+func (p *Provider) Exchange(ctx context.Context, code string) (*Credentials, map[string]interface{}, error) {
+	return p.tokenRequest(ctx, map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": p.RedirectURL,
+	})
+}
+
+// Refresh obtains a new access token; providers that rotate refresh tokens return a new one
+func (p *Provider) Refresh(ctx context.Context, refreshToken string) (*Credentials, error) {
+	creds, _, err := p.tokenRequest(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if creds.RefreshToken == "" {
+		creds.RefreshToken = refreshToken
+	}
+	return creds, nil
+}
+
+// tokenRequest calls the token endpoint and parses the standard token response.
+// Slack reports errors as {"ok": false, "error": ...} with a 200 status.
+func (p *Provider) tokenRequest(ctx context.Context, params map[string]string) (*Credentials, map[string]interface{}, error) {
+	params["client_id"] = p.ClientID
+	params["client_secret"] = p.ClientSecret
+
+	var body io.Reader
+	contentType := "application/x-www-form-urlencoded"
+	if p.TokenJSON {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error encoding token request: %w", err)
+		}
+		body = bytes.NewReader(data)
+		contentType = "application/json"
+	} else {
+		form := url.Values{}
+		for key, value := range params {
+			form.Set(key, value)
+		}
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.TokenURL, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error calling %s token endpoint: %w", p.Name, err)
+	}
+	defer resp.Body.Close()
+
+	var raw map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("error decoding %s token response (status %d): %w", p.Name, resp.StatusCode, err)
+	}
+
+	if code := stringValue(raw, "error"); code != "" || resp.StatusCode != http.StatusOK || raw["ok"] == false {
+		if code == "" {
+			code = fmt.Sprintf("http_%d", resp.StatusCode)
+		}
+		return nil, nil, &TokenError{Code: code, Description: stringValue(raw, "error_description"), StatusCode: resp.StatusCode}
+	}
+
+	creds := &Credentials{
+		AccessToken:  stringValue(raw, "access_token"),
+		RefreshToken: stringValue(raw, "refresh_token"),
+		TokenType:    stringValue(raw, "token_type"),
+		Scope:        stringValue(raw, "scope"),
+	}
+	if creds.AccessToken == "" {
+		return nil, nil, fmt.Errorf("%s token response has no access token", p.Name)
+	}
+	if expiresIn, ok := raw["expires_in"].(float64); ok && expiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(expiresIn) * time.Second)
+		creds.ExpiresAt = &expiresAt
+	}
+
+	return creds, raw, nil
+}
+
+// getJSON performs an authenticated GET and decodes the JSON response into v
+func (p *Provider) getJSON(ctx context.Context, endpoint string, creds *Credentials, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+creds.AccessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", p.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", p.Name, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s response: %w", p.Name, err)
+	}
+	return nil
+}
+
+func (p *Provider) client() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// stringValue reads a string field from a decoded JSON object
+func stringValue(m map[string]interface{}, key string) string {
+	if s, ok := m[key].(string); ok {
+		return s
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+// backend/internal/connections/providers.go
+package connections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Atlassian OAuth 2.0 (3LO) endpoints
+const (
+	jiraAuthURL      = "https://auth.atlassian.com/authorize"
+	jiraTokenURL     = "https://auth.atlassian.com/oauth/token"
+	jiraResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+)
+
+// Slack OAuth v2 endpoints
+const (
+	slackAuthURL   = "https://slack.com/oauth/v2/authorize"
+	slackTokenURL  = "https://slack.com/api/oauth.v2.access"
+	slackTestURL   = "https://slack.com/api/auth.test"
+	slackRevokeURL = "https://slack.com/api/auth.revoke"
+)
+
+// DefaultJiraScopes cover reading and writing issues; offline_access returns a refresh token
+var DefaultJiraScopes = []string{"read:jira-work", "write:jira-work", "read:jira-user", "offline_access"}
+
+// DefaultSlackScopes are the bot scopes the GRC notifications use
+var DefaultSlackScopes = []string{"chat:write", "channels:read", "groups:read", "usergroups:read", "commands"}
+
+// NewJiraProvider configures Atlassian OAuth for Jira Cloud
+func NewJiraProvider(clientID, clientSecret, redirectURL string, scopes []string) *Provider {
+	return &Provider{
+		Name:         "jira",
+		AuthURL:      jiraAuthURL,
+		TokenURL:     jiraTokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+		AuthParams: map[string]string{
+			"audience": "api.atlassian.com",
+			"prompt":   "consent",
+		},
+		TokenJSON:  true,
+		Describe:   describeJira,
+		Verify:     verifyJira,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewSlackProvider configures Slack OAuth v2 for a bot token
+func NewSlackProvider(clientID, clientSecret, redirectURL string, scopes []string) *Provider {
+	return &Provider{
+		Name:           "slack",
+		AuthURL:        slackAuthURL,
+		TokenURL:       slackTokenURL,
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		RedirectURL:    redirectURL,
+		Scopes:         scopes,
+		ScopeSeparator: ",",
+		Describe:       describeSlack,
+		Verify:         verifySlack,
+		Revoke:         revokeSlack,
+		HTTPClient:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// LoadProvidersFromEnv configures the providers whose client IDs are set.
+// Redirect URLs are OAUTH_REDIRECT_BASE_URL/<service>, matching the frontend's
+// /oauth/callback/:service route.
+func LoadProvidersFromEnv() map[string]*Provider {
+	base := strings.TrimSuffix(getEnv("OAUTH_REDIRECT_BASE_URL", "http://localhost:3000/oauth/callback"), "/")
+	providers := make(map[string]*Provider)
+
+	if clientID := os.Getenv("JIRA_OAUTH_CLIENT_ID"); clientID != "" {
+		providers["jira"] = NewJiraProvider(clientID, os.Getenv("JIRA_OAUTH_CLIENT_SECRET"), base+"/jira",
+			scopesFromEnv("JIRA_OAUTH_SCOPES", DefaultJiraScopes))
+	}
+	if clientID := os.Getenv("SLACK_OAUTH_CLIENT_ID"); clientID != "" {
+		providers["slack"] = NewSlackProvider(clientID, os.Getenv("SLACK_OAUTH_CLIENT_SECRET"), base+"/slack",
+			scopesFromEnv("SLACK_OAUTH_SCOPES", DefaultSlackScopes))
+	}
+
+	return providers
+}
+
+// jiraResource is a site the token can access
+type jiraResource struct {
+	ID   string `json:"id"`
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// describeJira records the Jira site the token was granted for; API calls go
+// through https://api.atlassian.com/ex/jira/<cloud_id>
+func describeJira(ctx context.Context, p *Provider, creds *Credentials, raw map[string]interface{}) (map[string]interface{}, error) {
+	var resources []jiraResource
+	if err := p.getJSON(ctx, jiraResourcesURL, creds, &resources); err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, errors.New("token does not grant access to any Jira site")
+	}
+
+	site := resources[0]
+	return map[string]interface{}{
+		"cloud_id":  site.ID,
+		"site_url":  site.URL,
+		"site_name": site.Name,
+		"scopes":    creds.Scope,
+	}, nil
+}
+
+// verifyJira checks the token by listing its accessible sites
+func verifyJira(ctx context.Context, p *Provider, creds *Credentials) error {
+	var resources []jiraResource
+	return p.getJSON(ctx, jiraResourcesURL, creds, &resources)
+}
+
+// describeSlack records the workspace from the token response
+func describeSlack(ctx context.Context, p *Provider, creds *Credentials, raw map[string]interface{}) (map[string]interface{}, error) {
+	metadata := map[string]interface{}{
+		"scopes":      creds.Scope,
+		"bot_user_id": stringValue(raw, "bot_user_id"),
+		"app_id":      stringValue(raw, "app_id"),
+	}
+	if team, ok := raw["team"].(map[string]interface{}); ok {
+		metadata["team_id"] = stringValue(team, "id")
+		metadata["team_name"] = stringValue(team, "name")
+	}
+	return metadata, nil
+}
+
+// slackResponse is the common envelope of Slack Web API responses
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// verifySlack calls auth.test with the token
+func verifySlack(ctx context.Context, p *Provider, creds *Credentials) error {
+	var result slackResponse
+	if err := p.getJSON(ctx, slackTestURL, creds, &result); err != nil {
+		return err
+	}
+	if !result.OK {
+		return fmt.Errorf("slack auth.test failed: %s", result.Error)
+	}
+	return nil
+}
+
+// revokeSlack calls auth.revoke so the token stops working immediately
+func revokeSlack(ctx context.Context, p *Provider, creds *Credentials) error {
+	var result slackResponse
+	if err := p.getJSON(ctx, slackRevokeURL, creds, &result); err != nil {
+		return err
+	}
+	if !result.OK && result.Error != "invalid_auth" && result.Error != "token_revoked" {
+		return fmt.Errorf("slack auth.revoke failed: %s", result.Error)
+	}
+	return nil
+}
+
+// scopesFromEnv reads a comma- or space-separated scope list
+func scopesFromEnv(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// getEnv returns an environment variable or the fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,305 @@
+// backend/internal/connections/store.go
+package connections
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Store persists connections in PostgreSQL with credentials encrypted at rest
+type Store struct {
+	DB     *sql.DB
+	Cipher *Cipher
+}
+
+// NewStore creates a connection store
+func NewStore(db *sql.DB, cipher *Cipher) *Store {
+	return &Store{DB: db, Cipher: cipher}
+}
+
+const connectionColumns = `id, user_id, name, service, status, auth_type, environment, auth_data, COALESCE(metadata, ''),
+	expires_at, revoked_at, last_used_at, created_at, updated_at`
+
+// Create inserts a connection; an empty environment means production
+func (s *Store) Create(c *Connection) error {
+	if c.Environment == "" {
+		c.Environment = EnvironmentProduction
+	}
+	if !ValidEnvironment(c.Environment) {
+		return ErrInvalidEnvironment
+	}
+	authData, err := s.sealCredentials(c.Credentials)
+	if err != nil {
+		return err
+	}
+	metadata, err := marshalMetadata(c.Metadata)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.QueryRow(
+		`INSERT INTO connections (user_id, name, service, status, auth_type, environment, auth_data, metadata, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING id, created_at, updated_at`,
+		c.UserID, c.Name, c.Service, c.Status, c.AuthType, c.Environment, authData, metadata, c.ExpiresAt,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating connection: %w", err)
+	}
+	return nil
+}
+
+// Get fetches a connection with its decrypted credentials
+func (s *Store) Get(id int) (*Connection, error) {
+	row := s.DB.QueryRow(`SELECT `+connectionColumns+` FROM connections WHERE id = $1`, id)
+	c, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return c, err
+}
+
+// List returns a user's connections, optionally for one service and environment
+func (s *Store) List(userID int, service, environment string) ([]Connection, error) {
+	rows, err := s.DB.Query(
+		`SELECT `+connectionColumns+` FROM connections
+		 WHERE user_id = $1 AND ($2 = '' OR service = $2) AND ($3 = '' OR environment = $3)
+		 ORDER BY service, environment, name`, userID, service, environment)
+	if err != nil {
+		return nil, fmt.Errorf("error listing connections: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Connection
+	for rows.Next() {
+		c, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *c)
+	}
+	return result, rows.Err()
+}
+
+// FindActive returns the user's most recently updated active connection to a
+// service in an environment
+func (s *Store) FindActive(userID int, service, environment string) (*Connection, error) {
+	row := s.DB.QueryRow(
+		`SELECT `+connectionColumns+` FROM connections
+		 WHERE user_id = $1 AND service = $2 AND environment = $3 AND status = $4
+		 ORDER BY updated_at DESC LIMIT 1`, userID, service, environment, StatusActive)
+	c, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return c, err
+}
+
+// FindPending returns the user's most recent pending OAuth connection for a service
+func (s *Store) FindPending(userID int, service string) (*Connection, error) {
+	row := s.DB.QueryRow(
+		`SELECT `+connectionColumns+` FROM connections
+		 WHERE user_id = $1 AND service = $2 AND auth_type = $3 AND status = $4
+		 ORDER BY created_at DESC LIMIT 1`, userID, service, AuthOAuth, StatusPending)
+	c, err := s.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return c, err
+}
+
+// DueForRefresh returns active OAuth connections whose tokens expire before the given time
+func (s *Store) DueForRefresh(before time.Time) ([]Connection, error) {
+	rows, err := s.DB.Query(
+		`SELECT `+connectionColumns+` FROM connections
+		 WHERE auth_type = $1 AND status = $2 AND expires_at IS NOT NULL AND expires_at < $3
+		 ORDER BY expires_at`, AuthOAuth, StatusActive, before)
+	if err != nil {
+		return nil, fmt.Errorf("error listing expiring connections: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Connection
+	for rows.Next() {
+		c, err := s.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *c)
+	}
+	return result, rows.Err()
+}
+
+// Update saves a connection's name, status, environment, metadata and credentials
+func (s *Store) Update(c *Connection) error {
+	if !ValidEnvironment(c.Environment) {
+		return ErrInvalidEnvironment
+	}
+	authData, err := s.sealCredentials(c.Credentials)
+	if err != nil {
+		return err
+	}
+	metadata, err := marshalMetadata(c.Metadata)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt *time.Time
+	if c.Credentials != nil {
+		expiresAt = c.Credentials.ExpiresAt
+	}
+	c.ExpiresAt = expiresAt
+
+	result, err := s.DB.Exec(
+		`UPDATE connections
+		 SET name = $1, status = $2, environment = $3, auth_data = $4, metadata = $5, expires_at = $6, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $7`,
+		c.Name, c.Status, c.Environment, authData, metadata, expiresAt, c.ID)
+	if err != nil {
+		return fmt.Errorf("error updating connection: %w", err)
+	}
+	return expectRow(result)
+}
+
+// SetStatus changes a connection's status without touching its credentials
+func (s *Store) SetStatus(id int, status string) error {
+	result, err := s.DB.Exec(
+		`UPDATE connections SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("error updating connection status: %w", err)
+	}
+	return expectRow(result)
+}
+
+// Revoke wipes a connection's credentials and marks it revoked; the row is kept
+// so access reviews still show who had access
+func (s *Store) Revoke(id int) error {
+	authData, err := s.sealCredentials(nil)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.DB.Exec(
+		`UPDATE connections
+		 SET status = $1, auth_data = $2, expires_at = NULL, revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $3`, StatusRevoked, authData, id)
+	if err != nil {
+		return fmt.Errorf("error revoking connection: %w", err)
+	}
+	return expectRow(result)
+}
+
+// Delete removes a connection
+func (s *Store) Delete(id int) error {
+	result, err := s.DB.Exec(`DELETE FROM connections WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting connection: %w", err)
+	}
+	return expectRow(result)
+}
+
+// TouchLastUsed records that a connection's credentials were used
+func (s *Store) TouchLastUsed(id int) error {
+	if _, err := s.DB.Exec(`UPDATE connections SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("error updating connection last use: %w", err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan reads one connection row and decrypts its credentials
+func (s *Store) scan(row scanner) (*Connection, error) {
+	c := &Connection{}
+	var authData, metadata string
+	var expiresAt, revokedAt, lastUsedAt sql.NullTime
+
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Service, &c.Status, &c.AuthType, &c.Environment, &authData, &metadata,
+		&expiresAt, &revokedAt, &lastUsedAt, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error scanning connection: %w", err)
+	}
+
+	if expiresAt.Valid {
+		c.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		c.RevokedAt = &revokedAt.Time
+	}
+	if lastUsedAt.Valid {
+		c.LastUsedAt = &lastUsedAt.Time
+	}
+
+	if metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &c.Metadata); err != nil {
+			return nil, fmt.Errorf("error decoding metadata for connection %d: %w", c.ID, err)
+		}
+	}
+
+	c.Credentials, err = s.openCredentials(authData)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials for connection %d: %w", c.ID, err)
+	}
+	return c, nil
+}
+
+// sealCredentials encrypts credentials for the auth_data column
+func (s *Store) sealCredentials(creds *Credentials) (string, error) {
+	if creds == nil {
+		creds = &Credentials{}
+	}
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return "", fmt.Errorf("error encoding credentials: %w", err)
+	}
+	return s.Cipher.Encrypt(data)
+}
+
+// openCredentials decrypts the auth_data column
+func (s *Store) openCredentials(authData string) (*Credentials, error) {
+	if authData == "" {
+		return &Credentials{}, nil
+	}
+	data, err := s.Cipher.Decrypt(authData)
+	if err != nil {
+		return nil, err
+	}
+	creds := &Credentials{}
+	if err := json.Unmarshal(data, creds); err != nil {
+		return nil, fmt.Errorf("error decoding credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// marshalMetadata encodes metadata for the JSON text column
+func marshalMetadata(metadata map[string]interface{}) (*string, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding metadata: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// expectRow maps an update that touched nothing to ErrNotFound
+func expectRow(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+// backend/internal/consistency/checker.go
+package consistency
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// Result categories
+const (
+	StatusOK         = "ok"
+	StatusOrphaned   = "orphaned"
+	StatusMismatched = "mismatched"
+	StatusStale      = "stale"
+	StatusError      = "error"
+)
+
+// Mapping names, matching the files in the data directory
+const (
+	MappingRisk     = "risk"
+	MappingIncident = "incident"
+)
+
+// Pair is one mapping entry linking a ServiceNow record to a Jira issue
+type Pair struct {
+	Mapping string `json:"mapping"`
+	Table   string `json:"servicenow_table"`
+	SysID   string `json:"sys_id"`
+	JiraKey string `json:"jira_key"`
+}
+
+// Result is the outcome of checking one pair
+type Result struct {
+	Pair
+	Status        string   `json:"status"`
+	Issues        []string `json:"issues,omitempty"`
+	RecordMissing bool     `json:"record_missing,omitempty"`
+	IssueMissing  bool     `json:"issue_missing,omitempty"`
+}
+
+// Report groups results by status
+type Report struct {
+	CheckedAt time.Time           `json:"checked_at"`
+	Counts    map[string]int      `json:"counts"`
+	Results   map[string][]Result `json:"results"`
+}
+
+// Checker compares the mapping store against ServiceNow and Jira
+type Checker struct {
+	ServiceNowClient *servicenow.Client
+	JiraClient       *jira.Client
+	// StaleAfter is how long both sides must have been closed before a pair is reported stale
+	StaleAfter time.Duration
+
+	now func() time.Time
+}
+
+// NewChecker creates a checker with a 180 day stale threshold
+func NewChecker(serviceNowClient *servicenow.Client, jiraClient *jira.Client) *Checker {
+	return &Checker{
+		ServiceNowClient: serviceNowClient,
+		JiraClient:       jiraClient,
+		StaleAfter:       180 * 24 * time.Hour,
+		now:              time.Now,
+	}
+}
+
+// Pairs flattens the risk and incident mappings, including entries that only exist in
+// one direction of a mapping's two indexes
+func Pairs(risks jira.MappingStore, incidents *jira.IncidentJiraMapping) ([]Pair, []Result, error) {
+	var pairs []Pair
+	var broken []Result
+
+	collect := func(mapping, table string, forward, reverse map[string]string) {
+		for sysID, key := range forward {
+			pairs = append(pairs, Pair{Mapping: mapping, Table: table, SysID: sysID, JiraKey: key})
+			if reverse[key] != sysID {
+				broken = append(broken, Result{
+					Pair:   Pair{Mapping: mapping, Table: table, SysID: sysID, JiraKey: key},
+					Status: StatusMismatched,
+					Issues: []string{fmt.Sprintf("reverse index maps %s to %q", key, reverse[key])},
+				})
+			}
+		}
+		for key, sysID := range reverse {
+			if _, ok := forward[sysID]; !ok {
+				broken = append(broken, Result{
+					Pair:   Pair{Mapping: mapping, Table: table, SysID: sysID, JiraKey: key},
+					Status: StatusOrphaned,
+					Issues: []string{"entry exists only in the reverse index"},
+				})
+			}
+		}
+	}
+
+	if risks != nil {
+		forward, reverse, err := risks.Snapshot()
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading risk mappings: %w", err)
+		}
+		collect(MappingRisk, "sn_risk_risk", forward, reverse)
+	}
+	if incidents != nil {
+		forward, reverse := incidents.Snapshot()
+		collect(MappingIncident, "sn_si_incident", forward, reverse)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Mapping != pairs[j].Mapping {
+			return pairs[i].Mapping < pairs[j].Mapping
+		}
+		return pairs[i].SysID < pairs[j].SysID
+	})
+
+	return pairs, broken, nil
+}
+
+// Check verifies every pair against both systems. Index problems found while
+// flattening the mappings are included as-is.
+func (c *Checker) Check(pairs []Pair, indexProblems []Result) *Report {
+	report := &Report{
+		CheckedAt: c.now(),
+		Counts:    make(map[string]int),
+		Results:   make(map[string][]Result),
+	}
+
+	add := func(result Result) {
+		report.Counts[result.Status]++
+		report.Results[result.Status] = append(report.Results[result.Status], result)
+	}
+
+	for _, result := range indexProblems {
+		add(result)
+	}
+	for _, pair := range pairs {
+		add(c.CheckPair(pair))
+	}
+
+	return report
+}
+
+// CheckPair verifies that both sides of a pair exist and point at each other
+func (c *Checker) CheckPair(pair Pair) Result {
+	result := Result{Pair: pair, Status: StatusOK}
+
+	record, recordErr := c.ServiceNowClient.GetRecord(pair.Table, pair.SysID)
+	issue, issueErr := c.JiraClient.GetIssue(pair.JiraKey)
+
+	recordMissing := errors.Is(recordErr, servicenow.ErrRecordNotFound)
+	issueMissing := jira.IsNotFound(issueErr)
+
+	if recordErr != nil && !recordMissing {
+		result.Status = StatusError
+		result.Issues = append(result.Issues, fmt.Sprintf("ServiceNow lookup failed: %v", recordErr))
+	}
+	if issueErr != nil && !issueMissing {
+		result.Status = StatusError
+		result.Issues = append(result.Issues, fmt.Sprintf("Jira lookup failed: %v", issueErr))
+	}
+	if result.Status == StatusError {
+		return result
+	}
+
+	if recordMissing || issueMissing {
+		result.Status = StatusOrphaned
+		result.RecordMissing = recordMissing
+		result.IssueMissing = issueMissing
+		if recordMissing {
+			result.Issues = append(result.Issues, fmt.Sprintf("ServiceNow record %s/%s no longer exists", pair.Table, pair.SysID))
+		}
+		if issueMissing {
+			result.Issues = append(result.Issues, fmt.Sprintf("Jira issue %s no longer exists", pair.JiraKey))
+		}
+		return result
+	}
+
+	fields, _ := issue["fields"].(map[string]interface{})
+
+	// Cross-references are optional on both sides, but must agree when present
+	if linked, _ := record["jira_ticket"].(string); linked != "" && linked != pair.JiraKey {
+		result.Issues = append(result.Issues, fmt.Sprintf("ServiceNow record links to %s", linked))
+	}
+	if linked, _ := fields[c.JiraClient.ServiceNowIDField()].(string); linked != "" && linked != pair.SysID {
+		result.Issues = append(result.Issues, fmt.Sprintf("Jira issue links to ServiceNow record %s", linked))
+	}
+	if len(result.Issues) > 0 {
+		result.Status = StatusMismatched
+		return result
+	}
+
+	if c.isStale(record, fields) {
+		result.Status = StatusStale
+		result.Issues = append(result.Issues, fmt.Sprintf("both sides closed for more than %d days", int(c.StaleAfter.Hours()/24)))
+	}
+
+	return result
+}
+
+// isStale reports whether both sides are closed and neither has changed within StaleAfter
+func (c *Checker) isStale(record, fields map[string]interface{}) bool {
+	state, _ := record["state"].(string)
+	if !closedStates[strings.ToLower(state)] {
+		return false
+	}
+
+	status, _ := fields["status"].(map[string]interface{})
+	category, _ := status["statusCategory"].(map[string]interface{})
+	if key, _ := category["key"].(string); key != "done" {
+		return false
+	}
+
+	cutoff := c.now().Add(-c.StaleAfter)
+
+	updatedOn, _ := record["sys_updated_on"].(string)
+	recordUpdated, err := time.Parse("2006-01-02 15:04:05", updatedOn)
+	if err != nil || recordUpdated.After(cutoff) {
+		return false
+	}
+
+	updated, _ := fields["updated"].(string)
+	issueUpdated, err := time.Parse("2006-01-02T15:04:05.000-0700", updated)
+	if err != nil || issueUpdated.After(cutoff) {
+		return false
+	}
+
+	return true
+}
+
+// closedStates are the ServiceNow state values (labels and common numeric codes) treated as closed
+var closedStates = map[string]bool{
+	"closed":    true,
+	"resolved":  true,
+	"cancelled": true,
+	"retired":   true,
+	"3":         true,
+	"4":         true,
+	"7":         true,
+}
@@ -0,0 +1,683 @@
+// backend/internal/consistency/conflicts.go
+package consistency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// Conflict policies: which edit stands when both sides change the same field
+const (
+	PolicyJiraWins       = "jira-wins"
+	PolicyServiceNowWins = "servicenow-wins"
+	PolicyNewestWins     = "newest-wins"
+	PolicyFlagForReview  = "flag-for-review"
+)
+
+// Conflict states
+const (
+	ConflictOpen     = "open"
+	ConflictResolved = "resolved"
+)
+
+// ErrConflictNotFound is returned for an unknown conflict ID
+var ErrConflictNotFound = errors.New("conflict not found")
+
+// ErrConflictResolved is returned when a conflict that was already settled is resolved again
+var ErrConflictResolved = errors.New("conflict is already resolved")
+
+// serviceNowFields are the record fields behind each compared field
+var serviceNowFields = map[string]string{
+	FieldStatus:   "state",
+	FieldPriority: "priority",
+	FieldAssignee: "assigned_to",
+}
+
+// jiraFields are the changelog fields behind each compared field
+var jiraFields = map[string]string{
+	"status":   FieldStatus,
+	"priority": FieldPriority,
+	"assignee": FieldAssignee,
+}
+
+// Edit is a person's change to one field on one side of a linked pair
+type Edit struct {
+	Side  string `json:"side"`
+	Field string `json:"field"`
+	Value string `json:"value"`
+	// Previous is the value before the edit; a losing edit is reverted to it
+	Previous string `json:"previous"`
+	// PreviousID is the raw value behind Previous, such as a sys_id or a Jira user
+	PreviousID string    `json:"previous_id,omitempty"`
+	By         string    `json:"by,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// Conflict is an edit of the same field on each side of a pair, made too close
+// together for either to have seen the other
+type Conflict struct {
+	ID string `json:"id"`
+	Pair
+	Field      string `json:"field"`
+	ServiceNow Edit   `json:"servicenow"`
+	Jira       Edit   `json:"jira"`
+	Policy     string `json:"policy"`
+	Status     string `json:"status"`
+	// Winner is the side whose edit stands; empty while the conflict awaits review
+	Winner     string     `json:"winner,omitempty"`
+	DetectedAt time.Time  `json:"detected_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+	// Error is why the losing edit could not be reverted
+	Error string `json:"error,omitempty"`
+	// Held is the update that was not synced while the conflict awaits review
+	HeldSide string          `json:"held_side,omitempty"`
+	Held     json.RawMessage `json:"held,omitempty"`
+}
+
+// edit returns the conflict's edit from one side
+func (c *Conflict) edit(side string) Edit {
+	if side == SideJira {
+		return c.Jira
+	}
+	return c.ServiceNow
+}
+
+// snapshot is the last seen state of a ServiceNow record's compared fields;
+// webhooks carry the whole record, so edits are found by comparing with it
+type snapshot struct {
+	Values  map[string]interface{}
+	Version int
+	Seen    time.Time
+}
+
+// ConflictDetector watches edits from both webhooks for changes to the same
+// field of a linked pair within Window, and settles them with a policy
+type ConflictDetector struct {
+	ServiceNowClient *servicenow.Client
+	JiraClient       *jira.Client
+	SlackClient      *slack.Client
+	Risks            jira.MappingStore
+	Incidents        *jira.IncidentJiraMapping
+	// Window is how close together two edits must be to conflict
+	Window time.Duration
+	// Policy applies to fields without an entry in Policies
+	Policy   string
+	Policies map[string]string
+	// Channel receives conflicts flagged for review
+	Channel string
+	// Routes gives the language of Channel; nil writes English
+	Routes *notification.Router
+	// MaxStored bounds how many conflicts are kept; resolved ones are dropped first
+	MaxStored int
+	// MaxTracked bounds how many ServiceNow records are remembered for comparison
+	MaxTracked int
+
+	mutex     sync.Mutex
+	edits     map[string]map[string]Edit
+	snapshots map[string]*snapshot
+	conflicts []*Conflict
+	sequence  int64
+	filePath  string
+	now       func() time.Time
+}
+
+// NewConflictDetector creates a newest-wins detector that keeps its conflicts
+// in storagePath and posts reviews to the ops channel
+func NewConflictDetector(storagePath string, serviceNowClient *servicenow.Client, jiraClient *jira.Client, slackClient *slack.Client, risks jira.MappingStore, incidents *jira.IncidentJiraMapping) (*ConflictDetector, error) {
+	d := &ConflictDetector{
+		ServiceNowClient: serviceNowClient,
+		JiraClient:       jiraClient,
+		SlackClient:      slackClient,
+		Risks:            risks,
+		Incidents:        incidents,
+		Window:           2 * time.Minute,
+		Policy:           PolicyNewestWins,
+		Policies:         map[string]string{},
+		Channel:          slack.ChannelMapping["ops"],
+		MaxStored:        500,
+		MaxTracked:       10000,
+		edits:            make(map[string]map[string]Edit),
+		snapshots:        make(map[string]*snapshot),
+		filePath:         filepath.Join(storagePath, "conflicts.json"),
+		now:              time.Now,
+	}
+
+	if _, err := os.Stat(d.filePath); err == nil {
+		file, err := os.ReadFile(d.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading conflict file: %w", err)
+		}
+		if err := json.Unmarshal(file, &d.conflicts); err != nil {
+			return nil, fmt.Errorf("error unmarshaling conflicts: %w", err)
+		}
+		// Keep IDs unique across restarts
+		d.sequence = int64(len(d.conflicts))
+	}
+
+	return d, nil
+}
+
+// ConfigureFromEnv reads CONFLICT_WINDOW (e.g. "5m") and CONFLICT_POLICY, a
+// default policy optionally followed by per-field ones:
+// "newest-wins,assignee=flag-for-review". Invalid entries are logged and ignored.
+func (d *ConflictDetector) ConfigureFromEnv() {
+	if value := os.Getenv("CONFLICT_WINDOW"); value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			slog.Warn("ignoring invalid CONFLICT_WINDOW", "value", value)
+		} else {
+			d.Window = window
+		}
+	}
+
+	for _, entry := range strings.Split(os.Getenv("CONFLICT_POLICY"), ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		field, policy, perField := strings.Cut(entry, "=")
+		if !perField {
+			field, policy = "", entry
+		}
+		field, policy = strings.TrimSpace(field), strings.TrimSpace(policy)
+		if !validPolicy(policy) || (perField && serviceNowFields[field] == "") {
+			slog.Warn("ignoring invalid conflict policy", "entry", entry)
+			continue
+		}
+		if perField {
+			d.Policies[field] = policy
+		} else {
+			d.Policy = policy
+		}
+	}
+}
+
+// validPolicy reports whether policy names a conflict policy
+func validPolicy(policy string) bool {
+	switch policy {
+	case PolicyJiraWins, PolicyServiceNowWins, PolicyNewestWins, PolicyFlagForReview:
+		return true
+	}
+	return false
+}
+
+// policy returns the policy for a field
+func (d *ConflictDetector) policy(field string) string {
+	if policy := d.Policies[field]; policy != "" {
+		return policy
+	}
+	return d.Policy
+}
+
+// ServiceNowUpdate records the edits in a ServiceNow webhook and reports
+// whether the update should be synced to Jira. Changes written by the
+// integration's own user, and updates older than one already seen, are not edits.
+func (d *ConflictDetector) ServiceNowUpdate(ctx context.Context, payload servicenow.WebhookPayload) bool {
+	if d == nil || payload.ActionType == "deleted" {
+		return true
+	}
+
+	version, _ := strconv.Atoi(stringValue(payload.Data["sys_mod_count"]))
+	at := d.now()
+	if updated, err := time.Parse("2006-01-02 15:04:05", stringValue(payload.Data["sys_updated_on"])); err == nil {
+		at = updated
+	}
+	by := stringValue(payload.Data["sys_updated_by"])
+	ownChange := by != "" && d.ServiceNowClient != nil && strings.EqualFold(by, d.ServiceNowClient.Username)
+
+	d.mutex.Lock()
+	previous := d.snapshots[payload.ID]
+	if previous != nil && version > 0 && version < previous.Version {
+		d.mutex.Unlock()
+		return true
+	}
+	current := &snapshot{Values: map[string]interface{}{}, Version: version, Seen: d.now()}
+	for _, field := range serviceNowFields {
+		if value, ok := payload.Data[field]; ok {
+			current.Values[field] = value
+		}
+	}
+	d.snapshots[payload.ID] = current
+	d.pruneSnapshots()
+	d.mutex.Unlock()
+
+	if previous == nil || ownChange || payload.ActionType != "updated" {
+		return true
+	}
+
+	var edits []Edit
+	for field, recordField := range serviceNowFields {
+		value, ok := current.Values[recordField]
+		if !ok {
+			continue
+		}
+		before := previous.Values[recordField]
+		if referenceID(value) == referenceID(before) {
+			continue
+		}
+		edits = append(edits, Edit{
+			Side:       SideServiceNow,
+			Field:      field,
+			Value:      referenceValue(value),
+			Previous:   referenceValue(before),
+			PreviousID: referenceID(before),
+			By:         by,
+			At:         at,
+		})
+	}
+	if len(edits) == 0 {
+		return true
+	}
+
+	jiraKey := servicenow.LinkedIssue(payload, d.Risks, d.Incidents)
+	if jiraKey == "" {
+		return true
+	}
+	held, _ := json.Marshal(payload)
+	return d.observe(ctx, Pair{Table: payload.TableName, SysID: payload.ID, JiraKey: jiraKey}, edits, held)
+}
+
+// JiraUpdate records the edits in a Jira issue_updated event and reports
+// whether the update should be synced to ServiceNow. raw is the event as Jira
+// sent it, kept if the update is held for review.
+func (d *ConflictDetector) JiraUpdate(ctx context.Context, event *jira.WebhookEvent, raw json.RawMessage) bool {
+	if d == nil || event.Issue == nil || event.Changelog == nil {
+		return true
+	}
+	if event.User != nil && d.JiraClient != nil && d.JiraClient.Email != "" &&
+		(strings.EqualFold(event.User.EmailAddress, d.JiraClient.Email) || strings.EqualFold(event.User.Name, d.JiraClient.Email)) {
+		return true
+	}
+
+	at := d.now()
+	if event.Timestamp > 0 {
+		at = time.UnixMilli(event.Timestamp)
+	}
+	by := ""
+	if event.User != nil {
+		by = event.User.DisplayName
+	}
+
+	var edits []Edit
+	for _, item := range event.Changelog.Items {
+		field, ok := jiraFields[strings.ToLower(item.Field)]
+		if !ok {
+			continue
+		}
+		edits = append(edits, Edit{
+			Side:       SideJira,
+			Field:      field,
+			Value:      item.ToString,
+			Previous:   item.FromString,
+			PreviousID: item.From,
+			By:         by,
+			At:         at,
+		})
+	}
+	if len(edits) == 0 {
+		return true
+	}
+
+	table, sysID := servicenow.LinkedRecord(event.Issue, d.Risks, d.Incidents)
+	if sysID == "" {
+		return true
+	}
+	return d.observe(ctx, Pair{Table: table, SysID: sysID, JiraKey: event.Issue.Key}, edits, raw)
+}
+
+// observe records edits to a pair and settles any that conflict with a recent
+// edit from the other side. It reports whether the update carrying the edits
+// should be synced: not when one of them lost or is held for review.
+func (d *ConflictDetector) observe(ctx context.Context, pair Pair, edits []Edit, held json.RawMessage) bool {
+	d.mutex.Lock()
+	recent := d.edits[pair.SysID]
+	if recent == nil {
+		recent = make(map[string]Edit)
+		d.edits[pair.SysID] = recent
+	}
+
+	syncUpdate := true
+	var found, lost []*Conflict
+	for _, edit := range edits {
+		opposite := otherSide(edit.Side)
+		other, seen := recent[edit.Field+"/"+opposite]
+		gap := edit.At.Sub(other.At)
+		// Both sides setting the same value agree, however close together
+		if !seen || gap > d.Window || -gap > d.Window || strings.EqualFold(edit.Value, other.Value) {
+			recent[edit.Field+"/"+edit.Side] = edit
+			continue
+		}
+		// Settled edits do not count towards the next conflict
+		delete(recent, edit.Field+"/"+opposite)
+
+		conflict := &Conflict{
+			Pair:       pair,
+			Field:      edit.Field,
+			Policy:     d.policy(edit.Field),
+			Status:     ConflictResolved,
+			DetectedAt: d.now(),
+		}
+		d.sequence++
+		conflict.ID = fmt.Sprintf("%s-%d", conflict.DetectedAt.Format("20060102150405"), d.sequence)
+		if edit.Side == SideJira {
+			conflict.Jira, conflict.ServiceNow = edit, other
+		} else {
+			conflict.ServiceNow, conflict.Jira = edit, other
+		}
+
+		switch conflict.Policy {
+		case PolicyFlagForReview:
+			conflict.Status = ConflictOpen
+			conflict.HeldSide = edit.Side
+			conflict.Held = held
+			syncUpdate = false
+		default:
+			conflict.Winner = winner(conflict.Policy, conflict)
+			resolvedAt := conflict.DetectedAt
+			conflict.ResolvedAt = &resolvedAt
+			conflict.ResolvedBy = "policy:" + conflict.Policy
+			if conflict.Winner != edit.Side {
+				lost = append(lost, conflict)
+				syncUpdate = false
+			}
+		}
+		found = append(found, conflict)
+		d.conflicts = append(d.conflicts, conflict)
+	}
+	d.trim()
+	d.mutex.Unlock()
+
+	if len(found) == 0 {
+		return true
+	}
+	for _, conflict := range found {
+		logging.FromContext(ctx).Warn("conflicting edits", "field", conflict.Field, "table", conflict.Table, "sys_id", conflict.SysID, "issue", conflict.JiraKey, "policy", conflict.Policy)
+		if conflict.Status == ConflictOpen {
+			d.postReview(ctx, conflict)
+		}
+	}
+	// The edits that arrived now lost: put their side back as it was
+	for _, conflict := range lost {
+		loser := otherSide(conflict.Winner)
+		if err := d.revert(ctx, conflict.Pair, conflict.edit(loser)); err != nil {
+			logging.FromContext(ctx).Error("error reverting edit", "side", loser, "field", conflict.Field, "sys_id", conflict.SysID, "error", err)
+			d.setError(conflict, err)
+		}
+	}
+	d.save()
+	return syncUpdate
+}
+
+// winner returns the side a policy gives a conflict to
+func winner(policy string, conflict *Conflict) string {
+	switch policy {
+	case PolicyJiraWins:
+		return SideJira
+	case PolicyServiceNowWins:
+		return SideServiceNow
+	}
+	if conflict.Jira.At.After(conflict.ServiceNow.At) {
+		return SideJira
+	}
+	return SideServiceNow
+}
+
+// otherSide returns the opposite side
+func otherSide(side string) string {
+	if side == SideJira {
+		return SideServiceNow
+	}
+	return SideJira
+}
+
+// revert puts an edited field back to its previous value on the edit's side.
+// The write is made by the integration's user, so it is not seen as an edit.
+func (d *ConflictDetector) revert(ctx context.Context, pair Pair, edit Edit) error {
+	if edit.Side == SideServiceNow {
+		return d.ServiceNowClient.WithContext(ctx).UpdateRecord(pair.Table, pair.SysID, map[string]interface{}{
+			serviceNowFields[edit.Field]: edit.PreviousID,
+		})
+	}
+
+	jiraClient := d.JiraClient.WithContext(ctx)
+	switch edit.Field {
+	case FieldStatus:
+		return jiraClient.UpdateIssue(pair.JiraKey, &jira.TicketUpdate{Status: edit.Previous})
+	case FieldPriority:
+		return jiraClient.UpdateIssue(pair.JiraKey, &jira.TicketUpdate{Priority: edit.Previous})
+	case FieldAssignee:
+		if edit.PreviousID == "" {
+			return jiraClient.UpdateIssue(pair.JiraKey, &jira.TicketUpdate{Fields: map[string]interface{}{"assignee": nil}})
+		}
+		return jiraClient.UpdateIssue(pair.JiraKey, &jira.TicketUpdate{Assignee: edit.PreviousID})
+	}
+	return fmt.Errorf("%s cannot be reverted", edit.Field)
+}
+
+// List returns stored conflicts, newest first, optionally only those with status
+func (d *ConflictDetector) List(status string) []*Conflict {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	list := []*Conflict{}
+	for i := len(d.conflicts) - 1; i >= 0; i-- {
+		if status == "" || d.conflicts[i].Status == status {
+			list = append(list, d.conflicts[i])
+		}
+	}
+	return list
+}
+
+// Get returns a stored conflict by ID
+func (d *ConflictDetector) Get(id string) (*Conflict, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, conflict := range d.conflicts {
+		if conflict.ID == id {
+			return conflict, true
+		}
+	}
+	return nil, false
+}
+
+// Resolve settles a conflict that awaits review in favour of keep, a side.
+// The other side's edit is reverted. When the held update's side wins, replay
+// syncs the held update, which was skipped when the conflict was found.
+func (d *ConflictDetector) Resolve(ctx context.Context, id, keep, by string, replay func(ctx context.Context, side string, held json.RawMessage) error) (*Conflict, error) {
+	if keep != SideJira && keep != SideServiceNow {
+		return nil, fmt.Errorf("keep must be %s or %s", SideJira, SideServiceNow)
+	}
+
+	d.mutex.Lock()
+	var conflict *Conflict
+	for _, c := range d.conflicts {
+		if c.ID == id {
+			conflict = c
+		}
+	}
+	if conflict == nil {
+		d.mutex.Unlock()
+		return nil, ErrConflictNotFound
+	}
+	if conflict.Status != ConflictOpen {
+		d.mutex.Unlock()
+		return conflict, ErrConflictResolved
+	}
+	// Claim the conflict so a second click cannot resolve it the other way
+	conflict.Status = ConflictResolved
+	d.mutex.Unlock()
+
+	loser := otherSide(keep)
+	err := d.revert(ctx, conflict.Pair, conflict.edit(loser))
+	if err == nil && keep == conflict.HeldSide && len(conflict.Held) > 0 {
+		err = replay(ctx, conflict.HeldSide, conflict.Held)
+	}
+
+	d.mutex.Lock()
+	now := d.now()
+	conflict.Winner = keep
+	conflict.ResolvedAt = &now
+	conflict.ResolvedBy = by
+	conflict.Held = nil
+	if err != nil {
+		conflict.Error = err.Error()
+	}
+	d.mutex.Unlock()
+	d.save()
+
+	logging.FromContext(ctx).Info("conflict resolved", "conflict", conflict.ID, "keep", keep, "by", by)
+	return conflict, err
+}
+
+// postReview asks the ops channel which edit of a conflict should stand
+func (d *ConflictDetector) postReview(ctx context.Context, conflict *Conflict) {
+	if d.SlackClient == nil {
+		return
+	}
+
+	locale := d.Routes.Locale(d.Channel)
+	text := i18n.T(locale, "conflict.header", conflict.Field, conflict.Table, conflict.SysID, conflict.JiraKey)
+	details := i18n.T(locale, "conflict.details",
+		conflict.ServiceNow.Previous, conflict.ServiceNow.Value, editor(locale, conflict.ServiceNow),
+		conflict.Jira.Previous, conflict.Jira.Value, editor(locale, conflict.Jira))
+
+	button := func(label, side, style string) map[string]interface{} {
+		element := map[string]interface{}{
+			"type": "button",
+			"text": map[string]interface{}{
+				"type":  "plain_text",
+				"text":  label,
+				"emoji": true,
+			},
+			"value":     fmt.Sprintf("keep_%s_%s", side, conflict.ID),
+			"action_id": "keep_" + side,
+		}
+		if style != "" {
+			element["style"] = style
+		}
+		return element
+	}
+
+	message := slack.Message{
+		Text: text,
+		Blocks: []slack.Block{
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", text, false),
+			},
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", details, false),
+			},
+			{
+				Type: "actions",
+				Elements: []interface{}{
+					button(i18n.T(locale, "conflict.button.keep_servicenow"), SideServiceNow, "primary"),
+					button(i18n.T(locale, "conflict.button.keep_jira"), SideJira, ""),
+				},
+			},
+			{
+				Type: "context",
+				Elements: []interface{}{
+					map[string]interface{}{
+						"type": "mrkdwn",
+						"text": i18n.T(locale, "conflict.held", conflict.HeldSide, conflict.ID),
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := d.SlackClient.WithContext(ctx).PostMessage(d.Channel, message); err != nil {
+		logging.FromContext(ctx).Warn("error posting conflict to Slack", "conflict", conflict.ID, "error", err)
+	}
+}
+
+// editor describes in a locale who made an edit and when
+func editor(locale string, edit Edit) string {
+	at := edit.At.UTC().Format("15:04:05")
+	if edit.By == "" {
+		return i18n.T(locale, "conflict.edited_at", at)
+	}
+	return i18n.T(locale, "conflict.edited_by", edit.By, at)
+}
+
+// setError records why a conflict's losing edit could not be reverted
+func (d *ConflictDetector) setError(conflict *Conflict, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	conflict.Error = err.Error()
+}
+
+// trim drops the oldest resolved conflicts beyond MaxStored; the caller holds the lock
+func (d *ConflictDetector) trim() {
+	excess := len(d.conflicts) - d.MaxStored
+	if excess <= 0 {
+		return
+	}
+	kept := d.conflicts[:0]
+	for _, conflict := range d.conflicts {
+		if excess > 0 && conflict.Status == ConflictResolved {
+			excess--
+			continue
+		}
+		kept = append(kept, conflict)
+	}
+	d.conflicts = kept
+}
+
+// pruneSnapshots forgets the least recently seen records beyond MaxTracked;
+// the caller holds the lock
+func (d *ConflictDetector) pruneSnapshots() {
+	for len(d.snapshots) > d.MaxTracked {
+		oldest := ""
+		for sysID, snap := range d.snapshots {
+			if oldest == "" || snap.Seen.Before(d.snapshots[oldest].Seen) {
+				oldest = sysID
+			}
+		}
+		delete(d.snapshots, oldest)
+		delete(d.edits, oldest)
+	}
+}
+
+// save writes the conflicts to disk
+func (d *ConflictDetector) save() {
+	d.mutex.Lock()
+	data, err := json.MarshalIndent(d.conflicts, "", "  ")
+	d.mutex.Unlock()
+	if err != nil {
+		slog.Error("error marshaling conflicts", "error", err)
+		return
+	}
+	if err := os.WriteFile(d.filePath, data, 0644); err != nil {
+		slog.Error("error saving conflicts", "error", err)
+	}
+}
+
+// referenceID reads the stored value of a field returned as a string or as
+// {"display_value": ..., "value": ...}
+func referenceID(value interface{}) string {
+	if ref, ok := value.(map[string]interface{}); ok {
+		return stringValue(ref["value"])
+	}
+	return stringValue(value)
+}
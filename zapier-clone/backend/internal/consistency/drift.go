@@ -0,0 +1,494 @@
+// backend/internal/consistency/drift.go
+package consistency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+	"github.com/shivani-1505/zapier-clone/backend/internal/riskmatrix"
+)
+
+// Fields compared between a ServiceNow record and its Jira issue
+const (
+	FieldStatus   = "status"
+	FieldPriority = "priority"
+	FieldAssignee = "assignee"
+)
+
+// Sides that can be authoritative for a field
+const (
+	SideServiceNow = "servicenow"
+	SideJira       = "jira"
+)
+
+// Status categories, named after Jira's statusCategory keys
+const (
+	categoryNew        = "new"
+	categoryInProgress = "indeterminate"
+	categoryDone       = "done"
+)
+
+// ErrReconcileRunning is returned when a run is requested while one is in progress
+var ErrReconcileRunning = errors.New("a reconciliation is already running")
+
+// maxSlackDrifts is how many drifts the Slack summary lists
+const maxSlackDrifts = 15
+
+// Drift is one field whose value differs between the two sides of a pair
+type Drift struct {
+	Pair
+	Field      string `json:"field"`
+	ServiceNow string `json:"servicenow"`
+	Jira       string `json:"jira"`
+	// Healed is set when the authoritative side's value was pushed to the other
+	Healed    bool   `json:"healed,omitempty"`
+	HealError string `json:"heal_error,omitempty"`
+}
+
+// DriftReport is the outcome of one reconciliation run
+type DriftReport struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Pairs     int       `json:"pairs"`
+	Drifts    []Drift   `json:"drifts"`
+	// Errors are pairs that could not be compared; missing records and issues
+	// are left to the consistency checker
+	Errors []Result `json:"errors,omitempty"`
+}
+
+// Authority names the side whose value wins for each field. Fields without an
+// entry are reported but never changed.
+type Authority map[string]string
+
+// ParseAuthority reads "status=servicenow,priority=servicenow". Status can be
+// healed in either direction; priority only from ServiceNow, because the field
+// mapping derives it from record fields; assignee is only reported. Anything
+// else is logged and ignored.
+func ParseAuthority(spec string) Authority {
+	authority := Authority{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		field, side, ok := strings.Cut(entry, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		side = strings.ToLower(strings.TrimSpace(side))
+		valid := ok && ((field == FieldStatus && (side == SideServiceNow || side == SideJira)) ||
+			(field == FieldPriority && side == SideServiceNow))
+		if !valid {
+			slog.Warn("ignoring invalid reconcile authority", "entry", entry)
+			continue
+		}
+		authority[field] = side
+	}
+	return authority
+}
+
+// Reconciler periodically compares status, priority and assignee across mapped
+// pairs, reports drift to Slack and optionally heals it
+type Reconciler struct {
+	ServiceNowClient *servicenow.Client
+	JiraClient       *jira.Client
+	SlackClient      *slack.Client
+	// FieldMapping gives the Jira priority a record should have
+	FieldMapping *mapping.Engine
+	Risks        jira.MappingStore
+	Incidents    *jira.IncidentJiraMapping
+	// Matrix gives risks rated by likelihood and impact their priority instead
+	Matrix *riskmatrix.Store
+	// Channel receives the drift summary; nothing is posted when there is no drift
+	Channel   string
+	Authority Authority
+	Interval  time.Duration
+
+	mutex    sync.Mutex
+	last     *DriftReport
+	active   bool
+	running  bool
+	stopChan chan struct{}
+	now      func() time.Time
+}
+
+// NewReconciler creates a report-only reconciler that posts to the ops channel
+func NewReconciler(serviceNowClient *servicenow.Client, jiraClient *jira.Client, slackClient *slack.Client, fieldMapping *mapping.Engine, risks jira.MappingStore, incidents *jira.IncidentJiraMapping) *Reconciler {
+	return &Reconciler{
+		ServiceNowClient: serviceNowClient,
+		JiraClient:       jiraClient,
+		SlackClient:      slackClient,
+		FieldMapping:     fieldMapping,
+		Risks:            risks,
+		Incidents:        incidents,
+		Channel:          slack.ChannelMapping["ops"],
+		Authority:        Authority{},
+		stopChan:         make(chan struct{}),
+		now:              time.Now,
+	}
+}
+
+// ConfigureFromEnv reads RECONCILE_INTERVAL (e.g. "6h") and RECONCILE_AUTHORITY.
+// It returns false when the interval is unset or "0", leaving the job off.
+func (r *Reconciler) ConfigureFromEnv() bool {
+	r.Authority = ParseAuthority(os.Getenv("RECONCILE_AUTHORITY"))
+
+	value := os.Getenv("RECONCILE_INTERVAL")
+	if value == "" || value == "0" {
+		return false
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		slog.Warn("ignoring invalid RECONCILE_INTERVAL", "value", value)
+		return false
+	}
+	r.Interval = interval
+	return true
+}
+
+// Start runs a reconciliation every Interval until Stop
+func (r *Reconciler) Start() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.running || r.Interval <= 0 {
+		return
+	}
+	r.running = true
+
+	go func() {
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stopChan:
+				return
+			case <-ticker.C:
+				if _, err := r.Run(context.Background()); err != nil {
+					slog.Error("error running reconciliation", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduled runs
+func (r *Reconciler) Stop() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if !r.running {
+		return
+	}
+	r.running = false
+	close(r.stopChan)
+}
+
+// Last returns the report of the most recent run, or nil before the first
+func (r *Reconciler) Last() *DriftReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.last
+}
+
+// Run compares every mapped pair, heals drift on fields with an authoritative
+// side, and posts a summary to the channel when anything drifted
+func (r *Reconciler) Run(ctx context.Context) (*DriftReport, error) {
+	r.mutex.Lock()
+	if r.active {
+		r.mutex.Unlock()
+		return nil, ErrReconcileRunning
+	}
+	r.active = true
+	r.mutex.Unlock()
+	defer func() {
+		r.mutex.Lock()
+		r.active = false
+		r.mutex.Unlock()
+	}()
+
+	ctx = logging.NewContext(ctx)
+	logger := logging.FromContext(ctx)
+	serviceNowClient := r.ServiceNowClient.WithContext(ctx)
+	jiraClient := r.JiraClient.WithContext(ctx)
+
+	pairs, _, err := Pairs(r.Risks, r.Incidents)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{CheckedAt: r.now(), Pairs: len(pairs), Drifts: []Drift{}}
+	// Pairs are sorted by mapping, so each table's records are read in batches
+	for start := 0; start < len(pairs); {
+		end := start + 1
+		for end < len(pairs) && end-start < recordBatchSize && pairs[end].Table == pairs[start].Table {
+			end++
+		}
+		batch := pairs[start:end]
+		start = end
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		records, err := readRecords(serviceNowClient, batch)
+
+		for _, pair := range batch {
+			record, ok := records[pair.SysID]
+			if !ok {
+				lookupErr := err
+				if lookupErr == nil {
+					lookupErr = servicenow.ErrRecordNotFound
+				}
+				report.Errors = append(report.Errors, Result{Pair: pair, Status: StatusError, Issues: []string{fmt.Sprintf("ServiceNow lookup failed: %v", lookupErr)}})
+				continue
+			}
+			issue, err := jiraClient.GetIssue(pair.JiraKey)
+			if err != nil {
+				report.Errors = append(report.Errors, Result{Pair: pair, Status: StatusError, Issues: []string{fmt.Sprintf("Jira lookup failed: %v", err)}})
+				continue
+			}
+			fields, _ := issue["fields"].(map[string]interface{})
+
+			for _, drift := range r.compare(pair, record, fields) {
+				if side := r.Authority[drift.Field]; side != "" {
+					if err := r.heal(serviceNowClient, jiraClient, side, &drift); err != nil {
+						drift.HealError = err.Error()
+					} else {
+						drift.Healed = true
+					}
+				}
+				report.Drifts = append(report.Drifts, drift)
+			}
+		}
+	}
+
+	logger.Info("reconciliation finished", "pairs", report.Pairs, "drifts", len(report.Drifts), "errors", len(report.Errors))
+
+	r.mutex.Lock()
+	r.last = report
+	r.mutex.Unlock()
+
+	if len(report.Drifts) > 0 {
+		if _, err := r.SlackClient.WithContext(ctx).PostMessage(r.Channel, r.summary(report)); err != nil {
+			logger.Error("error posting drift report to Slack", "error", err)
+		}
+	}
+	return report, nil
+}
+
+// recordBatchSize is the number of records read per ServiceNow request
+const recordBatchSize = 100
+
+// readRecords reads the records of pairs from one table with a single query,
+// keyed by sys_id. Records that no longer exist are absent from the result.
+func readRecords(client *servicenow.Client, pairs []Pair) (map[string]map[string]interface{}, error) {
+	sysIDs := make([]string, len(pairs))
+	for i, pair := range pairs {
+		sysIDs[i] = pair.SysID
+	}
+	query := servicenow.NewQuery().In("sys_id", sysIDs...)
+
+	records := make(map[string]map[string]interface{}, len(pairs))
+	iter := client.Records(pairs[0].Table, servicenow.ListOptions{Query: query.String(), PageSize: len(pairs)})
+	for iter.Next() {
+		record := iter.Record()
+		records[stringValue(record["sys_id"])] = record
+	}
+	return records, iter.Err()
+}
+
+// compare returns the fields that differ between a record and its issue
+func (r *Reconciler) compare(pair Pair, record, fields map[string]interface{}) []Drift {
+	var drifts []Drift
+
+	state := stringValue(record["state"])
+	status, _ := fields["status"].(map[string]interface{})
+	category, _ := status["statusCategory"].(map[string]interface{})
+	if issueCategory := stringValue(category["key"]); state != "" && issueCategory != "" && stateCategory(state) != issueCategory {
+		drifts = append(drifts, Drift{Pair: pair, Field: FieldStatus, ServiceNow: state, Jira: stringValue(status["name"])})
+	}
+
+	if expected := r.expectedPriority(pair.Table, record); expected != "" {
+		priority, _ := fields["priority"].(map[string]interface{})
+		if actual := stringValue(priority["name"]); !strings.EqualFold(expected, actual) {
+			drifts = append(drifts, Drift{Pair: pair, Field: FieldPriority, ServiceNow: expected, Jira: actual})
+		}
+	}
+
+	// Assignees can only be matched by name or email; bare sys_ids are skipped
+	serviceNowAssignee := referenceValue(record["assigned_to"])
+	assignee, _ := fields["assignee"].(map[string]interface{})
+	email, name := stringValue(assignee["emailAddress"]), stringValue(assignee["displayName"])
+	if !isSysID(serviceNowAssignee) && (serviceNowAssignee != "" || name != "" || email != "") &&
+		!strings.EqualFold(serviceNowAssignee, email) && !strings.EqualFold(serviceNowAssignee, name) {
+		jiraAssignee := name
+		if email != "" {
+			jiraAssignee = email
+		}
+		drifts = append(drifts, Drift{Pair: pair, Field: FieldAssignee, ServiceNow: serviceNowAssignee, Jira: jiraAssignee})
+	}
+
+	return drifts
+}
+
+// expectedPriority is the Jira priority the field mapping gives the record,
+// or the risk matrix when it rates the risk
+func (r *Reconciler) expectedPriority(table string, record map[string]interface{}) string {
+	if table == "sn_risk_risk" && r.Matrix != nil {
+		assessment, ok := r.Matrix.Get().Assess(stringValue(record["likelihood"]), stringValue(record["impact"]))
+		if ok && assessment.Priority != "" {
+			return assessment.Priority
+		}
+	}
+	if r.FieldMapping == nil {
+		return ""
+	}
+	mapped, err := r.FieldMapping.Map(table, servicenow.MappingRecord(table, record))
+	if err != nil {
+		return ""
+	}
+	return stringValue(mapped["priority"])
+}
+
+// heal pushes the authoritative side's value to the other side
+func (r *Reconciler) heal(serviceNowClient *servicenow.Client, jiraClient *jira.Client, side string, drift *Drift) error {
+	switch drift.Field {
+	case FieldStatus:
+		if side == SideServiceNow {
+			return jiraClient.UpdateIssue(drift.JiraKey, &jira.TicketUpdate{Status: jiraStatuses[stateCategory(drift.ServiceNow)]})
+		}
+		state, ok := serviceNowStates[drift.Table][jiraCategory(drift.Jira)]
+		if !ok {
+			return fmt.Errorf("no %s state for Jira status %q", drift.Table, drift.Jira)
+		}
+		return serviceNowClient.UpdateRecord(drift.Table, drift.SysID, map[string]interface{}{"state": state})
+	case FieldPriority:
+		if side == SideServiceNow {
+			return jiraClient.UpdateIssue(drift.JiraKey, &jira.TicketUpdate{Priority: drift.ServiceNow})
+		}
+		return fmt.Errorf("priority is derived from ServiceNow fields and cannot be pushed from Jira")
+	}
+	return fmt.Errorf("%s cannot be healed automatically", drift.Field)
+}
+
+// summary formats a report for Slack, listing the first drifts
+func (r *Reconciler) summary(report *DriftReport) slack.Message {
+	healed := 0
+	for _, drift := range report.Drifts {
+		if drift.Healed {
+			healed++
+		}
+	}
+
+	text := fmt.Sprintf("🔍 *Jira/ServiceNow drift:* %d field(s) differ across %d linked pair(s), %d healed", len(report.Drifts), report.Pairs, healed)
+	for i, drift := range report.Drifts {
+		if i == maxSlackDrifts {
+			text += fmt.Sprintf("\n…and %d more", len(report.Drifts)-i)
+			break
+		}
+		outcome := ""
+		switch {
+		case drift.Healed:
+			outcome = fmt.Sprintf(" (healed from %s)", r.Authority[drift.Field])
+		case drift.HealError != "":
+			outcome = fmt.Sprintf(" (heal failed: %s)", drift.HealError)
+		}
+		text += fmt.Sprintf("\n• %s ↔ %s %s: ServiceNow `%s`, Jira `%s`%s", drift.SysID, drift.JiraKey, drift.Field, drift.ServiceNow, drift.Jira, outcome)
+	}
+	return slack.Message{Text: text}
+}
+
+// jiraStatuses are the default workflow's statuses for each category
+var jiraStatuses = map[string]string{
+	categoryNew:        "To Do",
+	categoryInProgress: "In Progress",
+	categoryDone:       "Done",
+}
+
+// serviceNowStates are the states written to each table for a Jira status category
+var serviceNowStates = map[string]map[string]string{
+	"sn_risk_risk": {
+		categoryNew:        "Draft",
+		categoryInProgress: "In Progress",
+		categoryDone:       "Completed",
+	},
+	"sn_si_incident": {
+		categoryNew:        "open",
+		categoryInProgress: "in_progress",
+		categoryDone:       "resolved",
+	},
+}
+
+// newStates are the ServiceNow state values (labels and common numeric codes) treated as not started
+var newStates = map[string]bool{
+	"draft": true,
+	"new":   true,
+	"open":  true,
+	"1":     true,
+	"-5":    true,
+}
+
+// stateCategory puts a ServiceNow state in a Jira status category
+func stateCategory(state string) string {
+	state = strings.ToLower(state)
+	switch {
+	case closedStates[state], state == "completed":
+		return categoryDone
+	case newStates[state]:
+		return categoryNew
+	}
+	return categoryInProgress
+}
+
+// jiraCategory guesses the category of a Jira status name
+func jiraCategory(status string) string {
+	for category, name := range jiraStatuses {
+		if strings.EqualFold(name, status) {
+			return category
+		}
+	}
+	return stateCategory(status)
+}
+
+// referenceValue reads a reference field returned as a string or as
+// {"display_value": ..., "value": ...}
+func referenceValue(value interface{}) string {
+	if ref, ok := value.(map[string]interface{}); ok {
+		if display := stringValue(ref["display_value"]); display != "" {
+			return display
+		}
+		return stringValue(ref["value"])
+	}
+	return stringValue(value)
+}
+
+// isSysID reports whether a value is a 32-character ServiceNow sys_id
+func isSysID(value string) bool {
+	if len(value) != 32 {
+		return false
+	}
+	for _, c := range value {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
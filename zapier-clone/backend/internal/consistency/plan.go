@@ -0,0 +1,69 @@
+// backend/internal/consistency/plan.go
+package consistency
+
+import (
+	"strings"
+	"time"
+)
+
+// Repair actions
+const (
+	// RepairRemoveMapping drops a mapping whose ServiceNow record no longer exists
+	RepairRemoveMapping = "remove_mapping"
+	// RepairRecreateIssue creates a new Jira issue for a record whose issue was deleted
+	RepairRecreateIssue = "recreate_issue"
+	// RepairRelink rewrites the mapping and cross-reference fields so both sides agree
+	RepairRelink = "relink"
+	// RepairArchiveMapping moves a long-closed pair out of the active mapping
+	RepairArchiveMapping = "archive_mapping"
+	// RepairReview needs a human decision
+	RepairReview = "review"
+)
+
+// RepairAction is one step of a repair plan
+type RepairAction struct {
+	Action string `json:"action"`
+	Pair
+	Reason string `json:"reason"`
+}
+
+// RepairPlan lists the actions that would bring the mapping store back in line with
+// both systems. It is written as JSON for the reconciliation job to apply.
+type RepairPlan struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Actions     []RepairAction `json:"actions"`
+}
+
+// Plan derives a repair plan from a report; ok pairs and lookup errors produce no actions
+func Plan(report *Report) *RepairPlan {
+	plan := &RepairPlan{GeneratedAt: report.CheckedAt}
+
+	for _, status := range []string{StatusOrphaned, StatusMismatched, StatusStale} {
+		for _, result := range report.Results[status] {
+			plan.Actions = append(plan.Actions, RepairAction{
+				Action: repairFor(result),
+				Pair:   result.Pair,
+				Reason: strings.Join(result.Issues, "; "),
+			})
+		}
+	}
+
+	return plan
+}
+
+// repairFor picks the action for a problem result
+func repairFor(result Result) string {
+	switch result.Status {
+	case StatusStale:
+		return RepairArchiveMapping
+	case StatusMismatched:
+		return RepairRelink
+	case StatusOrphaned:
+		if result.IssueMissing && !result.RecordMissing {
+			return RepairRecreateIssue
+		}
+		// Record gone, or a dangling reverse-index entry
+		return RepairRemoveMapping
+	}
+	return RepairReview
+}
@@ -0,0 +1,30 @@
+// backend/internal/db/db.go
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	// Register the PostgreSQL driver
+	_ "github.com/lib/pq"
+)
+
+// Open connects to the PostgreSQL database described by the given URL
+func Open(databaseURL string) (*sql.DB, error) {
+	conn, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+
+	conn.SetMaxOpenConns(10)
+	conn.SetMaxIdleConns(5)
+	conn.SetConnMaxLifetime(30 * time.Minute)
+
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,14 @@
+-- Revert the initial schema
+DROP FUNCTION IF EXISTS last_insert_id();
+DROP TABLE IF EXISTS action_types;
+DROP TABLE IF EXISTS trigger_types;
+DROP TABLE IF EXISTS webhook_triggers;
+DROP TABLE IF EXISTS scheduled_triggers;
+DROP TABLE IF EXISTS workflow_action_executions;
+DROP TABLE IF EXISTS workflow_executions;
+DROP TABLE IF EXISTS workflow_data_mappings;
+DROP TABLE IF EXISTS workflow_actions;
+DROP TABLE IF EXISTS workflows;
+DROP TABLE IF EXISTS connections;
+DROP TABLE IF EXISTS auth_tokens;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,8 @@
+-- Revert team workspaces
+DROP INDEX IF EXISTS idx_workflow_executions_workspace_id;
+DROP INDEX IF EXISTS idx_workflows_workspace_id;
+ALTER TABLE workflow_executions DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE workflows DROP COLUMN IF EXISTS workspace_id;
+DROP TABLE IF EXISTS workflow_shares;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,66 @@
+-- Create workspaces table for team-owned workflows
+CREATE TABLE IF NOT EXISTS workspaces (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    max_workflows INTEGER, -- NULL means unlimited
+    max_monthly_executions INTEGER, -- NULL means unlimited
+    created_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Create workspace_members table
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    role TEXT NOT NULL, -- owner, admin, editor, viewer
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, user_id),
+    FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create workflow_shares table for explicit sharing between workspaces
+CREATE TABLE IF NOT EXISTS workflow_shares (
+    id SERIAL PRIMARY KEY,
+    workflow_id INTEGER NOT NULL,
+    workspace_id INTEGER NOT NULL, -- Workspace the workflow is shared with
+    permission TEXT NOT NULL, -- view, run, edit
+    created_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workflow_id, workspace_id),
+    FOREIGN KEY (workflow_id) REFERENCES workflows(id) ON DELETE CASCADE,
+    FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Workflows and executions carry their workspace
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS workspace_id INTEGER REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS workspace_id INTEGER REFERENCES workspaces(id) ON DELETE CASCADE;
+
+-- Give every existing user a personal workspace and move their workflows into it
+INSERT INTO workspaces (name, slug, created_by)
+SELECT COALESCE(u.full_name, u.username) || ' (personal)', 'personal-' || u.id, u.id
+FROM users u
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT w.id, w.created_by, 'owner'
+FROM workspaces w
+WHERE w.slug = 'personal-' || w.created_by
+ON CONFLICT DO NOTHING;
+
+UPDATE workflows
+SET workspace_id = (SELECT w.id FROM workspaces w WHERE w.slug = 'personal-' || workflows.user_id)
+WHERE workspace_id IS NULL;
+
+UPDATE workflow_executions
+SET workspace_id = (SELECT wf.workspace_id FROM workflows wf WHERE wf.id = workflow_executions.workflow_id)
+WHERE workspace_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user_id ON workspace_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_workflow_shares_workspace_id ON workflow_shares(workspace_id);
+CREATE INDEX IF NOT EXISTS idx_workflows_workspace_id ON workflows(workspace_id);
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_workspace_id ON workflow_executions(workspace_id);
@@ -0,0 +1,6 @@
+-- Revert workflow keys and the system account
+DROP INDEX IF EXISTS idx_workflows_trigger;
+DELETE FROM workflows WHERE key IS NOT NULL;
+DELETE FROM workspaces WHERE slug = 'system';
+DELETE FROM users WHERE username = 'system';
+ALTER TABLE workflows DROP COLUMN IF EXISTS key;
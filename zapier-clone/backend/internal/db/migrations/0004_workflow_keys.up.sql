@@ -0,0 +1,19 @@
+-- Stable keys identify built-in workflows so seeding is idempotent
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS key TEXT UNIQUE;
+
+-- System account and workspace that own the built-in workflows
+INSERT INTO users (username, email, password_hash, full_name)
+VALUES ('system', 'system@localhost', '!', 'System')
+ON CONFLICT (username) DO NOTHING;
+
+INSERT INTO workspaces (name, slug, created_by)
+SELECT 'System', 'system', id FROM users WHERE username = 'system'
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT w.id, u.id, 'owner'
+FROM workspaces w, users u
+WHERE w.slug = 'system' AND u.username = 'system'
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_workflows_trigger ON workflows(trigger_service, trigger_id);
@@ -0,0 +1,6 @@
+-- Revert connection token columns
+DROP INDEX IF EXISTS idx_connections_expires_at;
+DROP INDEX IF EXISTS idx_connections_user_service;
+
+ALTER TABLE connections DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE connections DROP COLUMN IF EXISTS expires_at;
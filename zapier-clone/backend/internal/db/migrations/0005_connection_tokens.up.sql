@@ -0,0 +1,7 @@
+-- OAuth connections: token expiry drives background refresh, revoked_at keeps
+-- revoked connections visible to access reviews
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_connections_user_service ON connections(user_id, service);
+CREATE INDEX IF NOT EXISTS idx_connections_expires_at ON connections(expires_at) WHERE expires_at IS NOT NULL;
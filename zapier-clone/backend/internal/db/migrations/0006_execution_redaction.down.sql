@@ -0,0 +1,7 @@
+-- Revert execution redaction columns
+ALTER TABLE workflow_action_executions DROP COLUMN IF EXISTS raw_output_data;
+ALTER TABLE workflow_action_executions DROP COLUMN IF EXISTS raw_input_data;
+ALTER TABLE workflow_action_executions DROP COLUMN IF EXISTS redacted_fields;
+
+ALTER TABLE workflow_executions DROP COLUMN IF EXISTS raw_trigger_data;
+ALTER TABLE workflow_executions DROP COLUMN IF EXISTS redacted_fields;
@@ -0,0 +1,8 @@
+-- Execution data is redacted before it is stored. redacted_fields lists the
+-- redacted paths; raw_* hold encrypted originals where policy allows viewing them
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS redacted_fields TEXT;
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS raw_trigger_data TEXT;
+
+ALTER TABLE workflow_action_executions ADD COLUMN IF NOT EXISTS redacted_fields TEXT;
+ALTER TABLE workflow_action_executions ADD COLUMN IF NOT EXISTS raw_input_data TEXT;
+ALTER TABLE workflow_action_executions ADD COLUMN IF NOT EXISTS raw_output_data TEXT;
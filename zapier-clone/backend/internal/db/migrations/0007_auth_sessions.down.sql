@@ -0,0 +1,9 @@
+-- Revert auth session tables and columns
+DROP TABLE IF EXISTS revoked_access_tokens;
+
+DROP INDEX IF EXISTS idx_auth_tokens_user_id;
+DROP INDEX IF EXISTS idx_auth_tokens_family;
+
+ALTER TABLE auth_tokens DROP COLUMN IF EXISTS replaced_by;
+ALTER TABLE auth_tokens DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE auth_tokens DROP COLUMN IF EXISTS family;
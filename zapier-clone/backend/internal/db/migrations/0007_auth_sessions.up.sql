@@ -0,0 +1,18 @@
+-- Refresh tokens rotate on every use. token holds a SHA-256 hash; tokens issued
+-- from one login share a family so reuse of a rotated token revokes the session
+ALTER TABLE auth_tokens ADD COLUMN IF NOT EXISTS family TEXT;
+ALTER TABLE auth_tokens ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP;
+ALTER TABLE auth_tokens ADD COLUMN IF NOT EXISTS replaced_by INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_auth_tokens_family ON auth_tokens(family);
+CREATE INDEX IF NOT EXISTS idx_auth_tokens_user_id ON auth_tokens(user_id);
+
+-- Access tokens revoked by logout before they expire
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
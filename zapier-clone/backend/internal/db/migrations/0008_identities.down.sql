@@ -0,0 +1,2 @@
+-- Revert identity mappings
+DROP TABLE IF EXISTS identities;
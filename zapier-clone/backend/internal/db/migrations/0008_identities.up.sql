@@ -0,0 +1,21 @@
+-- One person across ServiceNow, Jira and Slack, per tenant. source is manual for
+-- mappings entered through the API and auto for ones matched by email
+CREATE TABLE IF NOT EXISTS identities (
+    id SERIAL PRIMARY KEY,
+    tenant_id TEXT NOT NULL,
+    servicenow_user_id TEXT NOT NULL, -- sys_user sys_id
+    servicenow_username TEXT,         -- sys_user user_name
+    email TEXT,
+    display_name TEXT,
+    jira_account_id TEXT,
+    slack_user_id TEXT,
+    source TEXT NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(tenant_id, servicenow_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_identities_username ON identities(tenant_id, LOWER(servicenow_username));
+CREATE INDEX IF NOT EXISTS idx_identities_email ON identities(tenant_id, LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_identities_slack_user_id ON identities(tenant_id, slack_user_id);
+CREATE INDEX IF NOT EXISTS idx_identities_jira_account_id ON identities(tenant_id, jira_account_id);
@@ -0,0 +1,33 @@
+-- Append-only record of every modifying call made to ServiceNow, Jira, Slack,
+-- GitHub and Teams. Each row's hash covers the row and the previous row's hash
+-- (per tenant), so an edited, deleted or reordered row breaks the chain
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    actor TEXT NOT NULL,              -- user:<email>, or system for webhooks and jobs
+    request_id TEXT,
+    target_system TEXT NOT NULL,      -- servicenow, jira, slack, github or teams
+    method TEXT NOT NULL,
+    target TEXT NOT NULL,             -- host and path of the call
+    payload_digest TEXT NOT NULL,     -- sha256 of the request body
+    status_code INTEGER NOT NULL,     -- 0 when no response was received
+    error TEXT,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_tenant ON audit_log(tenant_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_system ON audit_log(tenant_id, target_system, created_at);
+
+-- Rows can only be added
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
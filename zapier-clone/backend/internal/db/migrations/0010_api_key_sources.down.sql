@@ -0,0 +1,5 @@
+-- Revert webhook API key columns
+DROP INDEX IF EXISTS idx_api_keys_tenant_source;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS source;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,6 @@
+-- API keys authenticate webhook senders: each key belongs to a tenant and
+-- is accepted only on its source's /api/webhooks/{source} endpoint
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS source TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant_source ON api_keys(tenant_id, source) WHERE revoked_at IS NULL;
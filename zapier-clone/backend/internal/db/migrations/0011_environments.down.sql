@@ -0,0 +1,5 @@
+-- Revert connection and workflow environments
+DROP INDEX IF EXISTS idx_connections_user_service_environment;
+
+ALTER TABLE workflows DROP COLUMN IF EXISTS environment;
+ALTER TABLE connections DROP COLUMN IF EXISTS environment;
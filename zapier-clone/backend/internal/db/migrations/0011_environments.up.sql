@@ -0,0 +1,6 @@
+-- Connections point at a production or sandbox site, and each workflow
+-- targets one environment, so changes can be tried in a sandbox first
+ALTER TABLE connections ADD COLUMN IF NOT EXISTS environment TEXT NOT NULL DEFAULT 'production';
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS environment TEXT NOT NULL DEFAULT 'production';
+
+CREATE INDEX IF NOT EXISTS idx_connections_user_service_environment ON connections(user_id, service, environment);
@@ -0,0 +1,2 @@
+-- Revert user administrators
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
@@ -0,0 +1,2 @@
+-- Administrators create accounts and use the /api/admin APIs
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
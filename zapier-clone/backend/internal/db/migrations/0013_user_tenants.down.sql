@@ -0,0 +1,4 @@
+-- Revert user tenants
+DROP INDEX IF EXISTS idx_users_tenant_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,5 @@
+-- Each account belongs to a tenant; its access tokens carry the tenant, so
+-- requests are routed by the token rather than by a header the client sets
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON users(tenant_id);
@@ -0,0 +1,10 @@
+// backend/internal/db/migrations/migrations.go
+package migrations
+
+import "embed"
+
+// FS holds the SQL migration files compiled into the binary.
+// Files are named NNNN_description.up.sql / NNNN_description.down.sql.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,104 @@
+// backend/internal/e2e/mocks.go
+package e2e
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Mock is one of the mock servers in the repository
+type Mock struct {
+	// Dir is the mock's directory, relative to the repository root
+	Dir       string
+	HealthURL string
+}
+
+// Mocks are the mock servers the scenarios drive, on their fixed ports
+var Mocks = []Mock{
+	{Dir: "grc-mock-servicenow", HealthURL: "http://localhost:3000/health"},
+	{Dir: "mock-jira", HealthURL: "http://localhost:3001/health"},
+	{Dir: "mock-slack-server", HealthURL: "http://localhost:3002/health"},
+}
+
+// StartMocks builds and starts the mock servers under root and waits until
+// they are healthy. Jira webhooks are pointed at the backend. Each mock logs
+// to <dir>.log in a temporary directory; stop kills the mocks and removes
+// their binaries, keeping the logs for a look at failures.
+func StartMocks(root, backendURL string) (stop func(), err error) {
+	tmp, err := os.MkdirTemp("", "e2e-mocks-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+
+	var running []*exec.Cmd
+	stop = func() {
+		for _, cmd := range running {
+			cmd.Process.Kill()
+			cmd.Wait()
+		}
+		for _, mock := range Mocks {
+			os.Remove(filepath.Join(tmp, mock.Dir))
+		}
+	}
+
+	for _, mock := range Mocks {
+		binary := filepath.Join(tmp, mock.Dir)
+		build := exec.Command("go", "build", "-o", binary, ".")
+		build.Dir = filepath.Join(root, mock.Dir)
+		if output, err := build.CombinedOutput(); err != nil {
+			stop()
+			return nil, fmt.Errorf("error building %s: %w\n%s", mock.Dir, err, output)
+		}
+
+		logFile, err := os.Create(binary + ".log")
+		if err != nil {
+			stop()
+			return nil, fmt.Errorf("error creating log for %s: %w", mock.Dir, err)
+		}
+		cmd := exec.Command(binary)
+		cmd.Dir = build.Dir
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+		cmd.Env = append(os.Environ(), "WEBHOOK_TARGET_URL="+backendURL+"/api/webhooks/jira")
+		if err := cmd.Start(); err != nil {
+			logFile.Close()
+			stop()
+			return nil, fmt.Errorf("error starting %s: %w", mock.Dir, err)
+		}
+		running = append(running, cmd)
+		slog.Info("started", "dir", mock.Dir, "log_file", logFile.Name())
+	}
+
+	for _, mock := range Mocks {
+		if err := waitHealthy(mock.HealthURL, 30*time.Second); err != nil {
+			stop()
+			return nil, fmt.Errorf("%s did not come up: %w", mock.Dir, err)
+		}
+	}
+	return stop, nil
+}
+
+// waitHealthy polls a health URL until it answers 200
+func waitHealthy(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: time.Second}
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			err = fmt.Errorf("status %d", resp.StatusCode)
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
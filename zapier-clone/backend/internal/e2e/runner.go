@@ -0,0 +1,553 @@
+// backend/internal/e2e/runner.go
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/api/middleware"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// Default waits
+const (
+	DefaultTimeout      = 30 * time.Second
+	DefaultPollInterval = 500 * time.Millisecond
+)
+
+// Config says where the backend and the mocks are
+type Config struct {
+	BackendURL         string
+	ServiceNowURL      string
+	ServiceNowUsername string
+	ServiceNowPassword string
+	JiraURL            string
+	SlackURL           string
+	// WebhookKey is an API key issued for the servicenow webhook source, and
+	// WebhookToken the backend's SERVICENOW_WEBHOOK_TOKEN; both are sent with
+	// servicenow.webhook steps when set
+	WebhookKey   string
+	WebhookToken string
+	// Timeout is how long expect steps wait when the scenario does not say
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// StepResult is the outcome of one step
+type StepResult struct {
+	Step     Step
+	Err      error
+	Duration time.Duration
+}
+
+// Result is the outcome of a scenario. Steps after a failing one are not run
+// and have no result.
+type Result struct {
+	Scenario *Scenario
+	Steps    []StepResult
+	Duration time.Duration
+}
+
+// Passed reports whether every step of the scenario ran and passed
+func (r *Result) Passed() bool {
+	if len(r.Steps) != len(r.Scenario.Steps) {
+		return false
+	}
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Runner runs scenarios against the backend and the mocks
+type Runner struct {
+	config     Config
+	serviceNow *servicenow.Client
+	http       *http.Client
+}
+
+// NewRunner returns a runner for the given servers
+func NewRunner(config Config) *Runner {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	return &Runner{
+		config:     config,
+		serviceNow: servicenow.NewClient(config.ServiceNowURL, config.ServiceNowUsername, config.ServiceNowPassword),
+		http:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// run is the state of one scenario run
+type run struct {
+	*Runner
+	ctx      context.Context
+	scenario *Scenario
+	vars     map[string]string
+}
+
+// Run runs a scenario, stopping at the first failing step or when ctx is done
+func (r *Runner) Run(ctx context.Context, scenario *Scenario) *Result {
+	started := time.Now()
+	state := &run{
+		Runner:   r,
+		ctx:      ctx,
+		scenario: scenario,
+		vars:     map[string]string{"run": fmt.Sprintf("%d", started.UnixNano()%1000000000)},
+	}
+
+	result := &Result{Scenario: scenario}
+	for _, step := range scenario.Steps {
+		stepStarted := time.Now()
+		err := ctx.Err()
+		if err == nil {
+			err = state.step(step)
+		}
+		result.Steps = append(result.Steps, StepResult{Step: step, Err: err, Duration: time.Since(stepStarted)})
+		if err != nil {
+			break
+		}
+	}
+	result.Duration = time.Since(started)
+	return result
+}
+
+// step expands a step's variables and runs it
+func (r *run) step(step Step) error {
+	var err error
+	expand := func(s string) string {
+		return os.Expand(s, func(name string) string {
+			value, ok := r.vars[name]
+			if !ok && err == nil {
+				err = fmt.Errorf("unknown variable %q", name)
+			}
+			return value
+		})
+	}
+	step.Table = expand(step.Table)
+	step.ID = expand(step.ID)
+	step.JQL = expand(step.JQL)
+	step.Issue = expand(step.Issue)
+	step.Status = expand(step.Status)
+	step.Resolution = expand(step.Resolution)
+	step.Channel = expand(step.Channel)
+	step.Thread = expand(step.Thread)
+	step.Contains = expand(step.Contains)
+	step.Fields = expandFields(step.Fields, expand)
+	if err != nil {
+		return err
+	}
+
+	switch step.Action {
+	case ActionServiceNowCreate:
+		return r.serviceNowCreate(step)
+	case ActionServiceNowUpdate:
+		return r.serviceNow.WithContext(r.ctx).UpdateRecord(step.Table, step.ID, step.Fields)
+	case ActionServiceNowWebhook:
+		return r.serviceNowWebhook(step)
+	case ActionServiceNowExpect:
+		return r.serviceNowExpect(step)
+	case ActionJiraExpect:
+		return r.jiraExpect(step)
+	case ActionJiraTransition:
+		return r.jiraTransition(step)
+	case ActionSlackExpect:
+		return r.slackExpect(step)
+	case ActionWait:
+		select {
+		case <-time.After(step.Timeout):
+			return nil
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		}
+	}
+	return fmt.Errorf("unknown action %q", step.Action)
+}
+
+// expandFields expands the variables in every string of fields
+func expandFields(fields map[string]interface{}, expand func(string) string) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	expanded := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		switch v := value.(type) {
+		case string:
+			expanded[key] = expand(v)
+		case map[string]interface{}:
+			expanded[key] = expandFields(v, expand)
+		default:
+			expanded[key] = v
+		}
+	}
+	return expanded
+}
+
+// save stores the values a step asked for; value looks a name up in the step's result
+func (r *run) save(step Step, value func(name string) (string, bool)) error {
+	for variable, name := range step.Save {
+		v, ok := value(name)
+		if !ok {
+			return fmt.Errorf("cannot save %s: the result has no %q", variable, name)
+		}
+		r.vars[variable] = v
+	}
+	return nil
+}
+
+// eventually polls check until it passes or the step's timeout runs out. The
+// error of a timed-out step is the last check's.
+func (r *run) eventually(step Step, check func() error) error {
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = r.scenario.Timeout
+	}
+	if timeout <= 0 {
+		timeout = r.config.Timeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("after %s: %w", timeout, err)
+		}
+		select {
+		case <-time.After(r.config.PollInterval):
+		case <-r.ctx.Done():
+			return fmt.Errorf("%w (last check: %v)", r.ctx.Err(), err)
+		}
+	}
+}
+
+func (r *run) serviceNowCreate(step Step) error {
+	record, err := r.serviceNow.WithContext(r.ctx).CreateRecord(step.Table, step.Fields)
+	if err != nil {
+		return fmt.Errorf("error creating %s record: %w", step.Table, err)
+	}
+	return r.save(step, func(name string) (string, bool) { return fieldString(record, name) })
+}
+
+// serviceNowWebhook posts a record to the backend the way a ServiceNow business rule does
+func (r *run) serviceNowWebhook(step Step) error {
+	record, err := r.serviceNow.WithContext(r.ctx).GetRecord(step.Table, step.ID)
+	if err != nil {
+		return fmt.Errorf("error reading %s/%s: %w", step.Table, step.ID, err)
+	}
+	event := step.Event
+	if event == "" {
+		event = "inserted"
+	}
+	payload := servicenow.WebhookPayload{ID: step.ID, TableName: step.Table, ActionType: event, Data: record}
+
+	header := http.Header{}
+	if r.config.WebhookKey != "" {
+		header.Set(middleware.HeaderWebhookKey, r.config.WebhookKey)
+	}
+	if r.config.WebhookToken != "" {
+		header.Set(middleware.HeaderServiceNowToken, r.config.WebhookToken)
+	}
+	return r.send("POST", strings.TrimRight(r.config.BackendURL, "/")+"/api/webhooks/servicenow", header, payload, nil)
+}
+
+func (r *run) serviceNowExpect(step Step) error {
+	var record map[string]interface{}
+	err := r.eventually(step, func() error {
+		var err error
+		record, err = r.serviceNow.WithContext(r.ctx).GetRecord(step.Table, step.ID)
+		if err != nil {
+			return fmt.Errorf("error reading %s/%s: %w", step.Table, step.ID, err)
+		}
+		return matchFields(record, step.Fields)
+	})
+	if err != nil {
+		return err
+	}
+	return r.save(step, func(name string) (string, bool) { return fieldString(record, name) })
+}
+
+func (r *run) jiraExpect(step Step) error {
+	var issue map[string]interface{}
+	err := r.eventually(step, func() error {
+		var response struct {
+			Issues []map[string]interface{} `json:"issues"`
+		}
+		query := url.Values{"jql": {step.JQL}, "maxResults": {"1"}}
+		if err := r.doJSON("GET", r.jiraURL("search?"+query.Encode()), nil, &response); err != nil {
+			return err
+		}
+		if len(response.Issues) == 0 {
+			return fmt.Errorf("no issue matches %s", step.JQL)
+		}
+		issue = response.Issues[0]
+		return matchFields(issue, step.Fields)
+	})
+	if err != nil {
+		return err
+	}
+	return r.save(step, func(name string) (string, bool) { return fieldString(issue, name) })
+}
+
+// jiraTransition follows the issue's transitions until it reaches the status.
+// When no transition leads there directly it takes one to a status it has not
+// been in yet, such as In Progress on the way from To Do to Done. Each hop
+// waits for the mock to deliver its webhook, so the backend sees them in order.
+func (r *run) jiraTransition(step Step) error {
+	visited := map[string]bool{}
+	for hops := 0; hops < 5; hops++ {
+		var response struct {
+			Transitions []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+				To   struct {
+					Name string `json:"name"`
+				} `json:"to"`
+			} `json:"transitions"`
+		}
+		if err := r.doJSON("GET", r.jiraURL("issue/"+step.Issue+"/transitions"), nil, &response); err != nil {
+			return err
+		}
+
+		id, to := "", ""
+		for _, transition := range response.Transitions {
+			if strings.EqualFold(transition.To.Name, step.Status) {
+				id, to = transition.ID, transition.To.Name
+				break
+			}
+			if id == "" && !visited[strings.ToLower(transition.To.Name)] {
+				id, to = transition.ID, transition.To.Name
+			}
+		}
+		if id == "" {
+			return fmt.Errorf("no transition of %s leads to %s", step.Issue, step.Status)
+		}
+
+		delivered := r.transitionWebhooks(step.Issue)
+		body := map[string]interface{}{"transition": map[string]string{"id": id}}
+		done := strings.EqualFold(to, step.Status)
+		if done && step.Resolution != "" {
+			body["fields"] = map[string]interface{}{"resolution": map[string]string{"name": step.Resolution}}
+		}
+		if err := r.doJSON("POST", r.jiraURL("issue/"+step.Issue+"/transitions"), body, nil); err != nil {
+			return fmt.Errorf("error moving %s to %s: %w", step.Issue, to, err)
+		}
+		r.waitTransitionWebhook(step.Issue, delivered)
+		if done {
+			return nil
+		}
+		visited[strings.ToLower(to)] = true
+	}
+	return fmt.Errorf("%s did not reach %s in 5 transitions", step.Issue, step.Status)
+}
+
+// transitionWebhooks counts the transition webhooks the mock Jira has sent for
+// an issue, or returns -1 when its webhook log cannot be read
+func (r *run) transitionWebhooks(issue string) int {
+	var logs struct {
+		Total int `json:"total"`
+	}
+	query := url.Values{"source": {"transition"}, "issue_key": {issue}}
+	if err := r.doJSON("GET", strings.TrimRight(r.config.JiraURL, "/")+"/api/webhook_logs?"+query.Encode(), nil, &logs); err != nil {
+		return -1
+	}
+	return logs.Total
+}
+
+// waitTransitionWebhook waits up to five seconds for the mock Jira to log one
+// more transition webhook than before. Without a webhook log it waits one poll
+// interval instead.
+func (r *run) waitTransitionWebhook(issue string, before int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if before < 0 || r.transitionWebhooks(issue) > before || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if before < 0 {
+		time.Sleep(r.config.PollInterval)
+	}
+}
+
+// slackMessage is a message as the mock Slack's workspace view returns it
+type slackMessage struct {
+	ChannelID string          `json:"channel_id"`
+	Text      string          `json:"text"`
+	Blocks    json.RawMessage `json:"blocks"`
+	Timestamp string          `json:"timestamp"`
+	Replies   []slackMessage  `json:"replies"`
+}
+
+// contains reports whether the message's text or blocks contain s
+func (m slackMessage) contains(s string) bool {
+	return strings.Contains(m.Text, s) || strings.Contains(string(m.Blocks), s)
+}
+
+// slackExpect waits for a message in a channel, or for a reply in a thread,
+// containing the step's text
+func (r *run) slackExpect(step Step) error {
+	var found slackMessage
+	err := r.eventually(step, func() error {
+		var workspace struct {
+			Channels []struct {
+				ID       string         `json:"id"`
+				Name     string         `json:"name"`
+				Messages []slackMessage `json:"messages"`
+			} `json:"channels"`
+		}
+		if err := r.doJSON("GET", strings.TrimRight(r.config.SlackURL, "/")+"/api/mock/workspace", nil, &workspace); err != nil {
+			return err
+		}
+
+		channel := strings.TrimPrefix(step.Channel, "#")
+		for _, c := range workspace.Channels {
+			if channel != "" && c.ID != channel && c.Name != channel {
+				continue
+			}
+			for _, message := range c.Messages {
+				if step.Thread == "" {
+					if message.contains(step.Contains) {
+						found = message
+						return nil
+					}
+					continue
+				}
+				if message.Timestamp != step.Thread {
+					continue
+				}
+				for _, reply := range message.Replies {
+					if reply.contains(step.Contains) {
+						found = reply
+						return nil
+					}
+				}
+				return fmt.Errorf("no reply in thread %s contains %q", step.Thread, step.Contains)
+			}
+		}
+		if step.Thread != "" {
+			return fmt.Errorf("no thread %s", step.Thread)
+		}
+		return fmt.Errorf("no message in %s contains %q", step.Channel, step.Contains)
+	})
+	if err != nil {
+		return err
+	}
+	return r.save(step, func(name string) (string, bool) {
+		switch name {
+		case "ts":
+			return found.Timestamp, true
+		case "channel":
+			return found.ChannelID, true
+		case "text":
+			return found.Text, true
+		}
+		return "", false
+	})
+}
+
+func (r *run) jiraURL(endpoint string) string {
+	return strings.TrimRight(r.config.JiraURL, "/") + "/rest/api/2/" + endpoint
+}
+
+// doJSON sends a request and decodes a JSON response into out, if not nil
+func (r *run) doJSON(method, target string, body, out interface{}) error {
+	return r.send(method, target, nil, body, out)
+}
+
+// send is doJSON with extra request headers
+func (r *run) send(method, target string, header http.Header, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error marshaling request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %d: %s", method, target, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("error decoding response from %s: %w", target, err)
+	}
+	return nil
+}
+
+// matchFields checks that a record or issue has the wanted field values
+func matchFields(record map[string]interface{}, want map[string]interface{}) error {
+	for field, wanted := range want {
+		got, ok := fieldString(record, field)
+		if !ok {
+			return fmt.Errorf("%s is not set, want %q", field, fmt.Sprint(wanted))
+		}
+		if !strings.EqualFold(got, fmt.Sprint(wanted)) {
+			return fmt.Errorf("%s is %q, want %q", field, got, fmt.Sprint(wanted))
+		}
+	}
+	return nil
+}
+
+// fieldString reads a field of a ServiceNow record or Jira issue as a string.
+// Jira keeps most fields under "fields", and references such as status are
+// objects with a name; ServiceNow reference fields have a value.
+func fieldString(record map[string]interface{}, field string) (string, bool) {
+	value, ok := record[field]
+	if !ok {
+		if fields, isMap := record["fields"].(map[string]interface{}); isMap {
+			value, ok = fields[field]
+		}
+	}
+	if !ok || value == nil {
+		return "", false
+	}
+	if object, isMap := value.(map[string]interface{}); isMap {
+		for _, key := range []string{"name", "value", "key"} {
+			if v, has := object[key]; has {
+				return fmt.Sprint(v), true
+			}
+		}
+	}
+	return fmt.Sprint(value), true
+}
@@ -0,0 +1,192 @@
+// backend/internal/e2e/scenario.go
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step actions
+const (
+	ActionServiceNowCreate  = "servicenow.create"
+	ActionServiceNowUpdate  = "servicenow.update"
+	ActionServiceNowWebhook = "servicenow.webhook"
+	ActionServiceNowExpect  = "servicenow.expect"
+	ActionJiraExpect        = "jira.expect"
+	ActionJiraTransition    = "jira.transition"
+	ActionSlackExpect       = "slack.expect"
+	ActionWait              = "wait"
+)
+
+// Scenario is a scripted run through the running backend and the mock
+// ServiceNow, Jira and Slack servers. Steps run in order and the first
+// failing step ends the scenario.
+//
+//	name: risk is announced and gets a Jira issue
+//	steps:
+//	  - action: servicenow.create
+//	    table: sn_risk_risk
+//	    fields:
+//	      number: RISK-E2E-${run}
+//	      short_description: E2E risk ${run}
+//	    save: {risk_id: sys_id}
+//	  - action: servicenow.webhook
+//	    table: sn_risk_risk
+//	    id: ${risk_id}
+//	  - action: jira.expect
+//	    jql: summary ~ "RISK-E2E-${run}"
+//	    save: {issue: key}
+//
+// Strings may refer to ${run}, unique to each run, and to values saved by
+// earlier steps.
+type Scenario struct {
+	Name string `yaml:"name"`
+	// Timeout is how long expect steps wait, unless they set their own
+	Timeout time.Duration `yaml:"timeout"`
+	Steps   []Step        `yaml:"steps"`
+
+	// File is the path the scenario was loaded from
+	File string `yaml:"-"`
+}
+
+// Step is one action of a scenario. Which fields apply depends on the action:
+//
+//   - servicenow.create: Table and Fields; saves fields of the created record.
+//   - servicenow.update: Table, ID and Fields.
+//   - servicenow.webhook: Table, ID and Event (inserted by default); posts the
+//     record to the backend as ServiceNow would.
+//   - servicenow.expect: Table, ID and the Fields the record must have.
+//   - jira.expect: JQL the issue must match, and Fields it must have; saves
+//     key or issue fields.
+//   - jira.transition: Issue, Status to reach and an optional Resolution.
+//   - slack.expect: Channel (ID or name), Contains, and Thread to look at the
+//     replies to a message; saves ts or channel.
+//   - wait: sleeps for Timeout.
+type Step struct {
+	Name       string                 `yaml:"name"`
+	Action     string                 `yaml:"action"`
+	Table      string                 `yaml:"table"`
+	ID         string                 `yaml:"id"`
+	Event      string                 `yaml:"event"`
+	Fields     map[string]interface{} `yaml:"fields"`
+	JQL        string                 `yaml:"jql"`
+	Issue      string                 `yaml:"issue"`
+	Status     string                 `yaml:"status"`
+	Resolution string                 `yaml:"resolution"`
+	Channel    string                 `yaml:"channel"`
+	Thread     string                 `yaml:"thread"`
+	Contains   string                 `yaml:"contains"`
+	// Save stores values of the step's result as variables, keyed by variable name
+	Save    map[string]string `yaml:"save"`
+	Timeout time.Duration     `yaml:"timeout"`
+}
+
+// Title names the step in reports
+func (s Step) Title() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Action
+}
+
+// LoadScenarios reads every .yaml/.yml scenario under dir, sorted by path
+func LoadScenarios(dir string) ([]*Scenario, error) {
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading scenarios from %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	var scenarios []*Scenario
+	for _, path := range paths {
+		scenario, err := LoadScenario(path)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
+
+// LoadScenario reads a single scenario file and checks its steps
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading scenario %s: %w", path, err)
+	}
+
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("error parsing scenario %s: %w", path, err)
+	}
+	scenario.File = path
+	if scenario.Name == "" {
+		scenario.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+	return &scenario, nil
+}
+
+// Validate checks that every step names a known action and has what it needs
+func (s *Scenario) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("no steps")
+	}
+	for i, step := range s.Steps {
+		var missing string
+		switch step.Action {
+		case ActionServiceNowCreate:
+			if step.Table == "" {
+				missing = "table"
+			}
+		case ActionServiceNowUpdate, ActionServiceNowWebhook, ActionServiceNowExpect:
+			if step.Table == "" {
+				missing = "table"
+			} else if step.ID == "" {
+				missing = "id"
+			}
+		case ActionJiraExpect:
+			if step.JQL == "" {
+				missing = "jql"
+			}
+		case ActionJiraTransition:
+			if step.Issue == "" {
+				missing = "issue"
+			} else if step.Status == "" {
+				missing = "status"
+			}
+		case ActionSlackExpect:
+			if step.Channel == "" && step.Thread == "" {
+				missing = "channel or thread"
+			}
+		case ActionWait:
+			if step.Timeout <= 0 {
+				missing = "timeout"
+			}
+		default:
+			return fmt.Errorf("step %d: unknown action %q", i+1, step.Action)
+		}
+		if missing != "" {
+			return fmt.Errorf("step %d (%s): missing %s", i+1, step.Action, missing)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+// backend/internal/eventbus/eventbus.go
+package eventbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// Sources of bus messages
+const (
+	SourceServiceNow = "servicenow"
+	SourceJira       = "jira"
+	SourceSlack      = "slack"
+)
+
+// Message types besides those of synced records, which are named after the
+// record and action, e.g. risk.created
+const (
+	TypeWebhookReceived    = "webhook.received"
+	TypeTicketCreated      = "ticket.created"
+	TypeTicketUpdated      = "ticket.updated"
+	TypeTicketTransitioned = "ticket.transitioned"
+	TypeTicketCommented    = "ticket.commented"
+	TypeNotificationSent   = "notification.sent"
+	TypeNotificationFailed = "notification.failed"
+	TypeSyncFailed         = "sync.failed"
+	TypeSyncSkipped        = "sync.skipped"
+)
+
+// DefaultQueue is how many messages may wait for the external publisher
+// before further ones are dropped
+const DefaultQueue = 1024
+
+// publishTimeout bounds one call to the external publisher
+const publishTimeout = 10 * time.Second
+
+// Message is one normalized integration event
+type Message struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	Tenant string    `json:"tenant"`
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	// Subject is what the event is about: a sys_id, a Jira key or a channel
+	Subject string      `json:"subject,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Filter selects messages; an empty list matches every value. Types may be
+// patterns such as ticket.* or *.failed.
+type Filter struct {
+	Types   []string
+	Sources []string
+}
+
+// Matches reports whether a message passes the filter
+func (f Filter) Matches(message Message) bool {
+	if len(f.Sources) > 0 && !contains(f.Sources, message.Source) {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, pattern := range f.Types {
+		if matched, _ := path.Match(pattern, message.Type); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Publisher delivers messages to an external broker
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+	Close() error
+}
+
+// NewPublisherFromEnv returns the broker selected by EVENT_BUS: "nats",
+// "kafka", or nil for "inprocess" (the default), where only in-process
+// subscribers see events
+func NewPublisherFromEnv() (Publisher, error) {
+	switch kind := strings.ToLower(os.Getenv("EVENT_BUS")); kind {
+	case "", "inprocess":
+		return nil, nil
+	case "nats":
+		return NewNATSPublisher(envOr("EVENT_BUS_NATS_URL", DefaultNATSURL), envOr("EVENT_BUS_SUBJECT_PREFIX", DefaultSubjectPrefix))
+	case "kafka":
+		brokers := strings.Split(os.Getenv("EVENT_BUS_KAFKA_BROKERS"), ",")
+		return NewKafkaPublisher(brokers, envOr("EVENT_BUS_KAFKA_TOPIC", DefaultKafkaTopic))
+	default:
+		return nil, fmt.Errorf("unknown EVENT_BUS %q: use inprocess, nats or kafka", kind)
+	}
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Bus carries one tenant's events to in-process subscribers and, when one is
+// configured, an external publisher. Publishing never blocks: the publisher is
+// fed from a queue, and messages that do not fit are dropped and counted.
+type Bus struct {
+	Tenant string
+
+	publisher Publisher
+	queue     chan Message
+	dropped   uint64
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu          sync.RWMutex
+	subscribers map[int]*subscription
+	nextID      int
+	closed      bool
+	now         func() time.Time
+}
+
+// subscription is one in-process consumer's filtered channel
+type subscription struct {
+	filter  Filter
+	events  chan Message
+	dropped uint64
+}
+
+// NewBus creates a tenant's bus; publisher may be nil
+func NewBus(tenant string, publisher Publisher) *Bus {
+	b := &Bus{
+		Tenant:      tenant,
+		publisher:   publisher,
+		done:        make(chan struct{}),
+		subscribers: make(map[int]*subscription),
+		now:         time.Now,
+	}
+	if publisher != nil {
+		b.queue = make(chan Message, DefaultQueue)
+		go b.run()
+	} else {
+		close(b.done)
+	}
+	return b
+}
+
+// Publish stamps a message and hands it on. A nil bus discards messages, as
+// does a closed one.
+func (b *Bus) Publish(message Message) {
+	if b == nil {
+		return
+	}
+	if message.ID == "" {
+		message.ID = newID()
+	}
+	if message.Time.IsZero() {
+		message.Time = b.now().UTC()
+	}
+	message.Tenant = b.Tenant
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.subscribers {
+		if !sub.filter.Matches(message) {
+			continue
+		}
+		select {
+		case sub.events <- message:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+		}
+	}
+	if b.queue == nil {
+		return
+	}
+	select {
+	case b.queue <- message:
+	default:
+		atomic.AddUint64(&b.dropped, 1)
+		metrics.EventBusMessages.Inc("dropped")
+	}
+}
+
+// Subscribe returns a channel of the messages matching filter that are
+// published from now on, and a function that ends the subscription and
+// closes the channel. A subscriber that falls buffer messages behind misses
+// messages; dropped reports how many. Closing the bus ends every subscription.
+func (b *Bus) Subscribe(filter Filter, buffer int) (events <-chan Message, dropped func() uint64, cancel func()) {
+	if buffer <= 0 {
+		buffer = DefaultQueue
+	}
+	sub := &subscription{filter: filter, events: make(chan Message, buffer)}
+	dropped = func() uint64 { return atomic.LoadUint64(&sub.dropped) }
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(sub.events)
+		return sub.events, dropped, func() {}
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[id]; ok {
+			delete(b.subscribers, id)
+			close(sub.events)
+		}
+	}
+	return sub.events, dropped, cancel
+}
+
+// Dropped returns how many messages never reached the external publisher
+// because its queue was full
+func (b *Bus) Dropped() uint64 {
+	return atomic.LoadUint64(&b.dropped)
+}
+
+// Close ends every subscription, stops accepting messages for the publisher
+// and waits up to timeout for the queued ones to go out. The publisher itself
+// is shared and closed by its owner.
+func (b *Bus) Close(timeout time.Duration) {
+	if b == nil {
+		return
+	}
+	b.closeOnce.Do(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.closed = true
+		for id, sub := range b.subscribers {
+			delete(b.subscribers, id)
+			close(sub.events)
+		}
+		if b.queue != nil {
+			close(b.queue)
+			b.queue = nil
+		}
+	})
+	select {
+	case <-b.done:
+	case <-time.After(timeout):
+		slog.Warn("event bus closed with messages still queued", "tenant", b.Tenant)
+	}
+}
+
+// run hands queued messages to the publisher until the queue is closed
+func (b *Bus) run() {
+	defer close(b.done)
+	for message := range b.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		err := b.publisher.Publish(ctx, message)
+		cancel()
+		if err != nil {
+			logging.FromContext(ctx).Error("error publishing event to the event bus", "type", message.Type, "message", message.ID, "error", err)
+			metrics.EventBusMessages.Inc("failed")
+			continue
+		}
+		metrics.EventBusMessages.Inc("published")
+	}
+}
+
+// newID returns a random message ID
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
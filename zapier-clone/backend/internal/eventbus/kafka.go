@@ -0,0 +1,75 @@
+// backend/internal/eventbus/kafka.go
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// DefaultKafkaTopic is the topic events are written to
+const DefaultKafkaTopic = "zapierclone.integration-events"
+
+// KafkaPublisher writes every message to one topic, keyed by tenant and
+// subject so the events for a record stay in order on one partition. The
+// type and tenant are also sent as headers for consumers that filter
+// without decoding.
+type KafkaPublisher struct {
+	Topic  string
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher creates a publisher for a list of host:port brokers
+func NewKafkaPublisher(brokers []string, topic string) (*KafkaPublisher, error) {
+	var addrs []string
+	for _, broker := range brokers {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			addrs = append(addrs, broker)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, errors.New("EVENT_BUS_KAFKA_BROKERS is required for the kafka event bus")
+	}
+	return &KafkaPublisher{
+		Topic: topic,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(addrs...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireOne,
+			// The bus already queues; send each message as it arrives
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}, nil
+}
+
+// Publish writes a message and waits for the leader to acknowledge it
+func (p *KafkaPublisher) Publish(ctx context.Context, message Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(message.Tenant + "/" + message.Subject),
+		Value: payload,
+		Time:  message.Time,
+		Headers: []kafka.Header{
+			{Key: "type", Value: []byte(message.Type)},
+			{Key: "tenant", Value: []byte(message.Tenant)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error writing to Kafka: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the writer
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,212 @@
+// backend/internal/eventbus/nats.go
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATS defaults
+const (
+	DefaultNATSURL       = "nats://localhost:4222"
+	DefaultSubjectPrefix = "zapierclone"
+)
+
+// NATSPublisher publishes each message on <prefix>.<tenant>.<type>, e.g.
+// zapierclone.default.risk.created, over the NATS client protocol. It
+// connects on first use and reconnects after a failed write.
+type NATSPublisher struct {
+	URL    string
+	Prefix string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	writer *bufio.Writer
+}
+
+// NewNATSPublisher creates a publisher for a nats://[user:pass@]host:port URL
+func NewNATSPublisher(rawURL, prefix string) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "nats" || u.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q: expected nats://host:port", rawURL)
+	}
+	return &NATSPublisher{URL: rawURL, Prefix: strings.Trim(prefix, ".")}, nil
+}
+
+// Subject returns the subject a message is published on
+func (p *NATSPublisher) Subject(message Message) string {
+	return p.Prefix + "." + subjectToken(message.Tenant) + "." + message.Type
+}
+
+// Publish sends a message, reconnecting once if the connection was lost
+func (p *NATSPublisher) Publish(ctx context.Context, message Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		if p.conn == nil {
+			if err := p.connect(ctx); err != nil {
+				return err
+			}
+		}
+		err := p.publish(ctx, p.Subject(message), payload)
+		if err == nil {
+			return nil
+		}
+		p.disconnect()
+		if attempt > 0 || ctx.Err() != nil {
+			return fmt.Errorf("error publishing to NATS: %w", err)
+		}
+	}
+}
+
+// Close closes the connection
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.disconnect()
+	return nil
+}
+
+// connect dials the server and completes the handshake. Callers hold mu.
+func (p *NATSPublisher) connect(ctx context.Context) error {
+	u, _ := url.Parse(p.URL)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	reader := bufio.NewReader(conn)
+
+	// The server speaks first with INFO
+	line, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("error reading NATS server info: %v", firstError(err, line))
+	}
+
+	options := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "zapier-clone",
+		"lang":     "go",
+	}
+	if u.User != nil {
+		options["user"] = u.User.Username()
+		options["pass"], _ = u.User.Password()
+	}
+	connect, _ := json.Marshal(options)
+	writer := bufio.NewWriter(conn)
+	fmt.Fprintf(writer, "CONNECT %s\r\nPING\r\n", connect)
+	if err := writer.Flush(); err != nil {
+		conn.Close()
+		return fmt.Errorf("error connecting to NATS: %w", err)
+	}
+
+	// A PONG confirms the CONNECT was accepted; -ERR means it was not
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return fmt.Errorf("error connecting to NATS: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "PONG" {
+			break
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			conn.Close()
+			return fmt.Errorf("NATS rejected the connection: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+	conn.SetDeadline(time.Time{})
+
+	p.conn, p.writer = conn, writer
+	go p.readLoop(conn, reader)
+	return nil
+}
+
+// publish writes one PUB. Callers hold mu.
+func (p *NATSPublisher) publish(ctx context.Context, subject string, payload []byte) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		p.conn.SetWriteDeadline(deadline)
+		defer p.conn.SetWriteDeadline(time.Time{})
+	}
+	fmt.Fprintf(p.writer, "PUB %s %d\r\n", subject, len(payload))
+	p.writer.Write(payload)
+	p.writer.WriteString("\r\n")
+	return p.writer.Flush()
+}
+
+// readLoop answers the server's keepalive PINGs and drops the connection when
+// the server closes it or reports an error
+func (p *NATSPublisher) readLoop(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if line == "PING" {
+			p.mu.Lock()
+			if p.conn == conn {
+				p.writer.WriteString("PONG\r\n")
+				p.writer.Flush()
+			}
+			p.mu.Unlock()
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			break
+		}
+	}
+
+	p.mu.Lock()
+	if p.conn == conn {
+		p.disconnect()
+	}
+	p.mu.Unlock()
+}
+
+// disconnect closes the current connection. Callers hold mu.
+func (p *NATSPublisher) disconnect() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn, p.writer = nil, nil
+	}
+}
+
+// subjectToken makes a value safe as a single subject token
+func subjectToken(value string) string {
+	if value == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '*' || r == '>' || r <= ' ' {
+			return '_'
+		}
+		return r
+	}, value)
+}
+
+// firstError describes a failed handshake read
+func firstError(err error, line string) error {
+	if err != nil {
+		return err
+	}
+	return errors.New(strings.TrimSpace(line))
+}
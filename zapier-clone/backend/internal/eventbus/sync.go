@@ -0,0 +1,86 @@
+// backend/internal/eventbus/sync.go
+package eventbus
+
+import (
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/syncevents"
+)
+
+// recordKinds names the records of the GRC tables in event types; other
+// tables use their name without the sn_ prefix
+var recordKinds = map[string]string{
+	"sn_risk_risk":           "risk",
+	"sn_si_incident":         "incident",
+	"sn_compliance_task":     "compliance_task",
+	"sn_policy_control_test": "control_test",
+	"sn_audit_finding":       "audit_finding",
+	"sn_vendor_risk":         "vendor_risk",
+	"sn_regulatory_change":   "regulatory_change",
+}
+
+// serviceNowActions maps webhook actions to event type verbs
+var serviceNowActions = map[string]string{
+	"inserted": "created",
+	"updated":  "updated",
+	"deleted":  "deleted",
+}
+
+// Forward publishes every event of a sync event hub on the bus until stop is
+// called
+func (b *Bus) Forward(hub *syncevents.Hub) (stop func()) {
+	if b == nil || hub == nil {
+		return func() {}
+	}
+	events, _, cancel := hub.Subscribe(syncevents.Filter{}, syncevents.DefaultBuffer)
+	go func() {
+		for event := range events {
+			b.Publish(FromSyncEvent(event))
+		}
+	}()
+	return cancel
+}
+
+// FromSyncEvent converts a synced inbound change. Successful syncs are typed
+// by record and action (risk.created, issue.updated); failed and skipped ones
+// are sync.failed and sync.skipped.
+func FromSyncEvent(event syncevents.Event) Message {
+	message := Message{
+		Time:    event.Time,
+		Source:  event.Source,
+		Subject: event.SysID,
+		Data:    event,
+	}
+	if event.Source == syncevents.SourceJira {
+		message.Subject = event.JiraKey
+	}
+
+	switch event.Result {
+	case syncevents.ResultFailure:
+		message.Type = TypeSyncFailed
+	case syncevents.ResultSkipped:
+		message.Type = TypeSyncSkipped
+	default:
+		message.Type = syncType(event)
+	}
+	return message
+}
+
+// syncType names a successful sync, e.g. risk.created or comment.created
+func syncType(event syncevents.Event) string {
+	if event.Source == syncevents.SourceJira {
+		// jira:issue_updated -> issue.updated, comment_created -> comment.created
+		action := strings.TrimPrefix(event.Action, "jira:")
+		return strings.Replace(action, "_", ".", 1)
+	}
+
+	kind, ok := recordKinds[event.Table]
+	if !ok {
+		kind = strings.TrimPrefix(event.Table, "sn_")
+	}
+	action, ok := serviceNowActions[strings.ToLower(event.Action)]
+	if !ok {
+		action = strings.ToLower(event.Action)
+	}
+	return kind + "." + action
+}
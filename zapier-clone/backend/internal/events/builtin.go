@@ -0,0 +1,138 @@
+// backend/internal/events/builtin.go
+package events
+
+import (
+	"log/slog"
+)
+
+// Fields shared by every GRC record event
+var recordFields = map[string]Field{
+	"sys_id":            {Type: FieldString, Required: true},
+	"number":            {Type: FieldString, Required: true},
+	"short_description": {Type: FieldString, Required: true},
+	"description":       {Type: FieldString},
+	"state":             {Type: FieldString},
+	"assigned_to":       {Type: FieldString},
+	"jira_key":          {Type: FieldString},
+}
+
+// BuiltinSchemas are the normalized events produced by the ServiceNow and Jira connectors
+var BuiltinSchemas = []*Schema{
+	{
+		Type:        "risk.created",
+		Version:     1,
+		Description: "A GRC risk was created in ServiceNow",
+		Fields: withFields(recordFields, map[string]Field{
+			"risk_score": {Type: FieldNumber, Required: true},
+			"category":   {Type: FieldString},
+			"due_date":   {Type: FieldTime},
+		}),
+	},
+	{
+		Type:        "risk.updated",
+		Version:     1,
+		Description: "A GRC risk changed in ServiceNow",
+		Fields: withFields(recordFields, map[string]Field{
+			"risk_score":     {Type: FieldNumber},
+			"changed_fields": {Type: FieldArray, Required: true},
+		}),
+	},
+	{
+		Type:        "incident.created",
+		Version:     1,
+		Description: "A security incident was opened in ServiceNow",
+		Fields: withFields(recordFields, map[string]Field{
+			"severity": {Type: FieldString, Required: true, Enum: []string{"critical", "high", "medium", "low"}},
+			"category": {Type: FieldString},
+			"impact":   {Type: FieldString},
+		}),
+	},
+	{
+		Type:        "incident.updated",
+		Version:     1,
+		Description: "A security incident changed in ServiceNow",
+		Fields: withFields(recordFields, map[string]Field{
+			"severity":       {Type: FieldString, Enum: []string{"critical", "high", "medium", "low"}},
+			"changed_fields": {Type: FieldArray, Required: true},
+		}),
+	},
+	{
+		Type:        "incident.updated",
+		Version:     2,
+		Description: "A security incident changed in ServiceNow, with previous values and the acting user",
+		Fields: withFields(recordFields, map[string]Field{
+			"severity":       {Type: FieldString, Enum: []string{"critical", "high", "medium", "low"}},
+			"changed_fields": {Type: FieldArray, Required: true},
+			"previous":       {Type: FieldObject},
+			"updated_by":     {Type: FieldString},
+		}),
+	},
+	{
+		Type:        "issue.updated",
+		Version:     1,
+		Description: "A Jira issue linked to ServiceNow changed",
+		Fields: map[string]Field{
+			"key":            {Type: FieldString, Required: true},
+			"status":         {Type: FieldString, Required: true},
+			"servicenow_id":  {Type: FieldString},
+			"changed_fields": {Type: FieldArray},
+		},
+	},
+	{
+		Type:        "comment.created",
+		Version:     1,
+		Description: "A comment or work note was added on either side of a linked pair",
+		Fields: map[string]Field{
+			"system":    {Type: FieldString, Required: true, Enum: []string{"jira", "servicenow"}},
+			"record_id": {Type: FieldString, Required: true},
+			"author":    {Type: FieldString},
+			"body":      {Type: FieldString, Required: true},
+		},
+	},
+}
+
+// BuiltinPublishers are the event versions each built-in connector publishes
+var BuiltinPublishers = map[string]map[string]int{
+	"servicenow": {
+		"risk.created":     1,
+		"risk.updated":     1,
+		"incident.created": 1,
+		"incident.updated": 1,
+		"comment.created":  1,
+	},
+	"jira": {
+		"issue.updated":   1,
+		"comment.created": 1,
+	},
+}
+
+// NewDefaultRegistry creates a registry holding the built-in schemas and publishers
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, schema := range BuiltinSchemas {
+		if err := registry.Register(schema); err != nil {
+			slog.Error("error registering event schema", "schema", schema.Key(), "error", err)
+		}
+	}
+
+	for connector, types := range BuiltinPublishers {
+		for eventType, version := range types {
+			if err := registry.DeclarePublisher(connector, eventType, version); err != nil {
+				slog.Error("error declaring event publisher", "connector", connector, "schema", SchemaKey(eventType, version), "error", err)
+			}
+		}
+	}
+
+	return registry
+}
+
+// withFields merges field sets into a new map
+func withFields(sets ...map[string]Field) map[string]Field {
+	fields := make(map[string]Field)
+	for _, set := range sets {
+		for name, field := range set {
+			fields[name] = field
+		}
+	}
+	return fields
+}
@@ -0,0 +1,185 @@
+// backend/internal/events/registry.go
+package events
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry errors
+var (
+	ErrUnknownSchema = errors.New("unknown event schema")
+	ErrIncompatible  = errors.New("incompatible event schema")
+)
+
+// Registry holds the versioned schemas of normalized events and the version each
+// connector publishes
+type Registry struct {
+	mu         sync.RWMutex
+	schemas    map[string]map[int]*Schema
+	publishers map[string]map[string]int
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas:    make(map[string]map[int]*Schema),
+		publishers: make(map[string]map[string]int),
+	}
+}
+
+// Register adds a schema version. Breaking versions may be registered; connectors
+// are only prevented from upgrading to them (see DeclarePublisher).
+func (r *Registry) Register(schema *Schema) error {
+	if schema.Type == "" || schema.Version < 1 {
+		return fmt.Errorf("schema needs a type and a version of at least 1")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := r.schemas[schema.Type]
+	if versions == nil {
+		versions = make(map[int]*Schema)
+		r.schemas[schema.Type] = versions
+	}
+	if _, exists := versions[schema.Version]; exists {
+		return fmt.Errorf("schema %s is already registered", schema.Key())
+	}
+
+	versions[schema.Version] = schema
+	return nil
+}
+
+// Get returns a schema version
+func (r *Registry) Get(eventType string, version int) (*Schema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schema := r.schemas[eventType][version]
+	if schema == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, SchemaKey(eventType, version))
+	}
+	return schema, nil
+}
+
+// Latest returns the newest version of an event type
+func (r *Registry) Latest(eventType string) (*Schema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *Schema
+	for _, schema := range r.schemas[eventType] {
+		if latest == nil || schema.Version > latest.Version {
+			latest = schema
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, eventType)
+	}
+	return latest, nil
+}
+
+// Schemas lists every registered schema ordered by type and version
+func (r *Registry) Schemas() []*Schema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var schemas []*Schema
+	for _, versions := range r.schemas {
+		for _, schema := range versions {
+			schemas = append(schemas, schema)
+		}
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		if schemas[i].Type != schemas[j].Type {
+			return schemas[i].Type < schemas[j].Type
+		}
+		return schemas[i].Version < schemas[j].Version
+	})
+	return schemas
+}
+
+// Validate checks an event against the schema for its type and version
+func (r *Registry) Validate(event *Envelope) error {
+	schema, err := r.Get(event.Type, event.Version)
+	if err != nil {
+		return err
+	}
+	return schema.Validate(event.Data)
+}
+
+// CheckCompatibility reports the problems with replacing an event type's version
+// from with version to, checking every step in between
+func (r *Registry) CheckCompatibility(eventType string, from, to int) ([]string, error) {
+	if to < from {
+		return nil, fmt.Errorf("cannot downgrade %s from v%d to v%d", eventType, from, to)
+	}
+
+	var problems []string
+	for version := from + 1; version <= to; version++ {
+		older, err := r.Get(eventType, version-1)
+		if err != nil {
+			return nil, err
+		}
+		newer, err := r.Get(eventType, version)
+		if err != nil {
+			return nil, err
+		}
+		for _, problem := range newer.CompatibleWith(older) {
+			problems = append(problems, fmt.Sprintf("v%d: %s", version, problem))
+		}
+	}
+
+	return problems, nil
+}
+
+// DeclarePublisher records that a connector publishes an event type at a version.
+// Upgrading a connector to a newer version is refused if it would break consumers
+// of the version it published before.
+func (r *Registry) DeclarePublisher(connector, eventType string, version int) error {
+	if _, err := r.Get(eventType, version); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	current, declared := r.publishers[connector][eventType]
+	r.mu.RUnlock()
+
+	if declared && version != current {
+		problems, err := r.CheckCompatibility(eventType, current, version)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%w: %s upgrading %s from v%d to v%d: %s", ErrIncompatible, connector, eventType, current, version, strings.Join(problems, "; "))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.publishers[connector] == nil {
+		r.publishers[connector] = make(map[string]int)
+	}
+	r.publishers[connector][eventType] = version
+	return nil
+}
+
+// Publishers returns the declared versions per connector and event type
+func (r *Registry) Publishers() map[string]map[string]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	publishers := make(map[string]map[string]int, len(r.publishers))
+	for connector, types := range r.publishers {
+		publishers[connector] = make(map[string]int, len(types))
+		for eventType, version := range types {
+			publishers[connector][eventType] = version
+		}
+	}
+	return publishers
+}
@@ -0,0 +1,186 @@
+// backend/internal/events/schema.go
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Field types understood by the validator
+const (
+	FieldString = "string"
+	FieldNumber = "number"
+	FieldBool   = "bool"
+	FieldTime   = "time"
+	FieldObject = "object"
+	FieldArray  = "array"
+)
+
+// Field describes one field of an event's data
+type Field struct {
+	Type     string   `json:"type"`
+	Required bool     `json:"required,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+}
+
+// Schema is one version of a normalized event type such as risk.created v1
+type Schema struct {
+	Type        string           `json:"type"`
+	Version     int              `json:"version"`
+	Description string           `json:"description,omitempty"`
+	Fields      map[string]Field `json:"fields"`
+}
+
+// Key identifies a schema, e.g. "incident.updated@v2"
+func (s *Schema) Key() string {
+	return SchemaKey(s.Type, s.Version)
+}
+
+// SchemaKey formats a type and version as a schema key
+func SchemaKey(eventType string, version int) string {
+	return fmt.Sprintf("%s@v%d", eventType, version)
+}
+
+// Envelope is a normalized internal event
+type Envelope struct {
+	Type       string                 `json:"type"`
+	Version    int                    `json:"version"`
+	Source     string                 `json:"source"`
+	ID         string                 `json:"id,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// ValidationError lists every problem found in an event's data
+type ValidationError struct {
+	Schema   string
+	Problems []string
+}
+
+// Error implements the error interface for ValidationError
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("event does not match %s: %s", e.Schema, strings.Join(e.Problems, "; "))
+}
+
+// Validate checks data against the schema. Unknown fields are allowed so producers
+// can add optional fields without a version bump.
+func (s *Schema) Validate(data map[string]interface{}) error {
+	var problems []string
+
+	names := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := s.Fields[name]
+		value, present := data[name]
+		if !present || value == nil {
+			if field.Required {
+				problems = append(problems, fmt.Sprintf("%s is required", name))
+			}
+			continue
+		}
+
+		if !matchesType(field.Type, value) {
+			problems = append(problems, fmt.Sprintf("%s must be a %s", name, field.Type))
+			continue
+		}
+
+		if len(field.Enum) > 0 && !contains(field.Enum, fmt.Sprintf("%v", value)) {
+			problems = append(problems, fmt.Sprintf("%s must be one of %s", name, strings.Join(field.Enum, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Schema: s.Key(), Problems: problems}
+	}
+	return nil
+}
+
+// CompatibleWith reports why s cannot replace older for existing consumers. A newer
+// version may add optional fields and widen enums, but may not add required fields,
+// drop or loosen required fields, change a field's type or narrow an enum.
+func (s *Schema) CompatibleWith(older *Schema) []string {
+	var problems []string
+
+	for name, old := range older.Fields {
+		field, ok := s.Fields[name]
+		switch {
+		case !ok && old.Required:
+			problems = append(problems, fmt.Sprintf("required field %s was removed", name))
+		case !ok:
+			// Dropping an optional field is safe
+		case field.Type != old.Type:
+			problems = append(problems, fmt.Sprintf("field %s changed type from %s to %s", name, old.Type, field.Type))
+		case old.Required && !field.Required:
+			problems = append(problems, fmt.Sprintf("field %s is no longer required", name))
+		default:
+			if len(field.Enum) > 0 {
+				for _, value := range old.Enum {
+					if !contains(field.Enum, value) {
+						problems = append(problems, fmt.Sprintf("field %s no longer accepts %q", name, value))
+					}
+				}
+				if len(old.Enum) == 0 {
+					problems = append(problems, fmt.Sprintf("field %s gained an enum restriction", name))
+				}
+			}
+		}
+	}
+
+	for name, field := range s.Fields {
+		if _, ok := older.Fields[name]; !ok && field.Required {
+			problems = append(problems, fmt.Sprintf("new field %s is required", name))
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// matchesType checks a decoded JSON value against a field type
+func matchesType(fieldType string, value interface{}) bool {
+	switch fieldType {
+	case FieldString:
+		_, ok := value.(string)
+		return ok
+	case FieldNumber:
+		switch value.(type) {
+		case float64, float32, int, int64:
+			return true
+		}
+		return false
+	case FieldBool:
+		_, ok := value.(bool)
+		return ok
+	case FieldTime:
+		switch v := value.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, err := time.Parse(time.RFC3339, v)
+			return err == nil
+		}
+		return false
+	case FieldObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case FieldArray:
+		_, ok := value.([]interface{})
+		return ok
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
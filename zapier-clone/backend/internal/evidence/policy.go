@@ -0,0 +1,148 @@
+// backend/internal/evidence/policy.go
+package evidence
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxBytes is the largest evidence file accepted unless EVIDENCE_MAX_BYTES says otherwise
+const DefaultMaxBytes = 25 << 20
+
+var (
+	// ErrEmpty is returned for a file without content
+	ErrEmpty = errors.New("evidence file is empty")
+	// ErrTooLarge is returned for a file over the policy's size limit
+	ErrTooLarge = errors.New("evidence file is too large")
+	// ErrFileType is returned for a file whose type is not allowed or whose
+	// content does not match its extension
+	ErrFileType = errors.New("evidence file type is not allowed")
+)
+
+// fileType is how a file with a given extension is stored and what its
+// content must look like
+type fileType struct {
+	contentType string
+	// sniffed is the type http.DetectContentType reports for genuine files
+	sniffed string
+}
+
+// fileTypes are the evidence formats the server knows how to check, by extension
+var fileTypes = map[string]fileType{
+	"pdf":  {"application/pdf", "application/pdf"},
+	"png":  {"image/png", "image/png"},
+	"jpg":  {"image/jpeg", "image/jpeg"},
+	"jpeg": {"image/jpeg", "image/jpeg"},
+	"gif":  {"image/gif", "image/gif"},
+	"csv":  {"text/csv", "text/plain"},
+	"txt":  {"text/plain", "text/plain"},
+	"log":  {"text/plain", "text/plain"},
+	"json": {"application/json", "text/plain"},
+	"docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip"},
+	"xlsx": {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/zip"},
+	"zip":  {"application/zip", "application/zip"},
+}
+
+// DefaultFileTypes are the extensions accepted unless EVIDENCE_FILE_TYPES says otherwise
+var DefaultFileTypes = []string{"pdf", "png", "jpg", "jpeg", "gif", "csv", "txt", "log", "json", "docx", "xlsx", "zip"}
+
+// Policy decides which evidence files are accepted
+type Policy struct {
+	MaxBytes int64
+	// FileTypes are the accepted extensions, without the dot
+	FileTypes []string
+}
+
+// DefaultPolicy accepts the default file types up to DefaultMaxBytes
+func DefaultPolicy() Policy {
+	return Policy{MaxBytes: DefaultMaxBytes, FileTypes: DefaultFileTypes}
+}
+
+// PolicyFromEnv reads EVIDENCE_MAX_BYTES and EVIDENCE_FILE_TYPES over the
+// default policy. Extensions the server cannot check are ignored.
+func PolicyFromEnv() Policy {
+	policy := DefaultPolicy()
+	if value := os.Getenv("EVIDENCE_MAX_BYTES"); value != "" {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil && n > 0 {
+			policy.MaxBytes = n
+		} else {
+			slog.Warn("ignoring invalid EVIDENCE_MAX_BYTES", "value", value)
+		}
+	}
+	if value := os.Getenv("EVIDENCE_FILE_TYPES"); value != "" {
+		policy.FileTypes = nil
+		for _, ext := range strings.Split(value, ",") {
+			ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+			if ext == "" {
+				continue
+			}
+			if _, ok := fileTypes[ext]; !ok {
+				slog.Warn("ignoring unsupported evidence file type in EVIDENCE_FILE_TYPES", "type", ext)
+				continue
+			}
+			policy.FileTypes = append(policy.FileTypes, ext)
+		}
+	}
+	return policy
+}
+
+// Allows reports whether files with ext are accepted
+func (p Policy) Allows(ext string) bool {
+	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+	for _, allowed := range p.FileTypes {
+		if allowed == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// Check validates a file's name, size and content, and returns the content
+// type to store it with. Errors wrap ErrEmpty, ErrTooLarge or ErrFileType.
+func (p Policy) Check(fileName string, content []byte) (string, error) {
+	if len(content) == 0 {
+		return "", ErrEmpty
+	}
+	if p.MaxBytes > 0 && int64(len(content)) > p.MaxBytes {
+		return "", fmt.Errorf("%w: %d bytes, the limit is %d", ErrTooLarge, len(content), p.MaxBytes)
+	}
+
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(fileName), "."))
+	if ext == "" || !p.Allows(ext) {
+		return "", fmt.Errorf("%w: %q (allowed: %s)", ErrFileType, fileName, strings.Join(p.FileTypes, ", "))
+	}
+	expected := fileTypes[ext]
+	sniffed, _, _ := strings.Cut(http.DetectContentType(content), ";")
+	if sniffed != expected.sniffed {
+		return "", fmt.Errorf("%w: %q does not contain %s data", ErrFileType, fileName, ext)
+	}
+	return expected.contentType, nil
+}
+
+// unsafeName matches the characters object keys and attachment names leave out
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SafeName reduces a file name to its base name in letters, digits, dots,
+// dashes and underscores
+func SafeName(fileName string) string {
+	name := filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	name = strings.Trim(unsafeName.ReplaceAllString(name, "-"), "-.")
+	if name == "" {
+		return "evidence"
+	}
+	return name
+}
+
+// ObjectKey names where a record's evidence file is stored, e.g.
+// "sn_policy_control_test/<sys_id>/20240501T120000Z-report.pdf"
+func ObjectKey(table, sysID, fileName string, at time.Time) string {
+	return fmt.Sprintf("%s/%s/%s-%s", table, sysID, at.UTC().Format("20060102T150405Z"), SafeName(fileName))
+}
@@ -0,0 +1,180 @@
+// backend/internal/evidence/s3.go
+package evidence
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Store keeps evidence files and returns a link to each
+type Store interface {
+	Put(ctx context.Context, key, contentType string, content []byte) (string, error)
+}
+
+// S3Store keeps evidence in a bucket of S3 or an S3-compatible service such as
+// MinIO, using path-style requests signed with Signature Version 4
+type S3Store struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// PublicURL is where stored files are linked from; it defaults to the bucket's URL
+	PublicURL  string
+	HTTPClient *http.Client
+}
+
+// NewS3StoreFromEnv configures a store from EVIDENCE_S3_BUCKET and its
+// companions. It returns nil when no bucket is set. Credentials fall back to
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+func NewS3StoreFromEnv() *S3Store {
+	bucket := os.Getenv("EVIDENCE_S3_BUCKET")
+	if bucket == "" {
+		return nil
+	}
+	region := os.Getenv("EVIDENCE_S3_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := os.Getenv("EVIDENCE_S3_ENDPOINT")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+
+	return &S3Store{
+		Endpoint:        strings.TrimRight(endpoint, "/"),
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     envOr("EVIDENCE_S3_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: envOr("EVIDENCE_S3_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    envOr("EVIDENCE_S3_SESSION_TOKEN", "AWS_SESSION_TOKEN"),
+		PublicURL:       strings.TrimRight(os.Getenv("EVIDENCE_S3_PUBLIC_URL"), "/"),
+		HTTPClient:      &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// envOr reads the first of the variables that is set
+func envOr(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Put implements Store, uploading content as the object key
+func (s *S3Store) Put(ctx context.Context, key, contentType string, content []byte) (string, error) {
+	if s.AccessKeyID == "" || s.SecretAccessKey == "" {
+		return "", fmt.Errorf("evidence storage: EVIDENCE_S3_ACCESS_KEY_ID and EVIDENCE_S3_SECRET_ACCESS_KEY are required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(s.Endpoint+"/"+s.Bucket, key), bytes.NewReader(content))
+	if err != nil {
+		return "", fmt.Errorf("error creating evidence upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, content, time.Now().UTC())
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error uploading evidence to %s: %w", s.Bucket, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("error uploading evidence to %s: status %d: %s", s.Bucket, resp.StatusCode, body)
+	}
+
+	return s.URL(key), nil
+}
+
+// URL is the link to a stored object
+func (s *S3Store) URL(key string) string {
+	base := s.PublicURL
+	if base == "" {
+		base = s.Endpoint + "/" + s.Bucket
+	}
+	return s.objectURL(base, key)
+}
+
+// objectURL appends key to base, escaping each path segment
+func (s *S3Store) objectURL(base, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return base + "/" + strings.Join(segments, "/")
+}
+
+// sign adds an AWS Signature Version 4 Authorization header for S3
+func (s *S3Store) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := hashHex(payload)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, req.URL.RawQuery, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// hashHex returns the hex SHA-256 of data
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 signs data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
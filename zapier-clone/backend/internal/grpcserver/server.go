@@ -0,0 +1,367 @@
+// backend/internal/grpcserver/server.go
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/syncevents"
+	"github.com/shivani-1505/zapier-clone/backend/internal/tenant"
+	syncv1 "github.com/shivani-1505/zapier-clone/backend/proto/sync/v1"
+)
+
+// MetadataTenant names the tenant a call expects; a call naming another
+// tenant than its credential is refused
+const MetadataTenant = "x-tenant-id"
+
+// Mapping kinds
+const (
+	KindRisk     = "risk"
+	KindIncident = "incident"
+)
+
+// Tenant is what the gRPC services read for one tenant
+type Tenant struct {
+	Events    *syncevents.Hub
+	Risks     jira.MappingStore
+	Incidents *jira.IncidentJiraMapping
+}
+
+// Server serves the sync event stream and mapping lookups over gRPC, next to
+// the HTTP server
+type Server struct {
+	Addr string
+	// Token is the bearer token that gives callers the default tenant, sent
+	// in "authorization" metadata
+	Token string
+	// Tenants looks up the tenant of a tenant API key sent as the bearer token
+	Tenants *tenant.Registry
+	// AllowUnauthenticated gives calls without a token the default tenant, for
+	// local development
+	AllowUnauthenticated bool
+
+	mu      sync.RWMutex
+	tenants map[string]*Tenant
+	grpc    *grpc.Server
+}
+
+// NewServerFromEnv returns a server listening on GRPC_ADDR, or nil when it is
+// not set. Callers authenticate with GRPC_AUTH_TOKEN or a tenant's API key;
+// without GRPC_AUTH_TOKEN the server refuses to start unless
+// GRPC_ALLOW_UNAUTHENTICATED=true.
+func NewServerFromEnv(tenants *tenant.Registry) (*Server, error) {
+	addr := os.Getenv("GRPC_ADDR")
+	if addr == "" {
+		return nil, nil
+	}
+	token := os.Getenv("GRPC_AUTH_TOKEN")
+	allow := strings.EqualFold(os.Getenv("GRPC_ALLOW_UNAUTHENTICATED"), "true")
+	if token == "" {
+		if !allow {
+			return nil, errors.New("GRPC_AUTH_TOKEN is required with GRPC_ADDR; set GRPC_ALLOW_UNAUTHENTICATED=true to serve the default tenant without it")
+		}
+		slog.Warn("GRPC_ALLOW_UNAUTHENTICATED lets gRPC calls without a token read the default tenant")
+	}
+	s := NewServer(addr, token, tenants)
+	s.AllowUnauthenticated = allow
+	return s, nil
+}
+
+// NewServer creates a gRPC server for addr
+func NewServer(addr, token string, tenants *tenant.Registry) *Server {
+	s := &Server{
+		Addr:    addr,
+		Token:   token,
+		Tenants: tenants,
+		tenants: make(map[string]*Tenant),
+	}
+	s.grpc = grpc.NewServer(
+		grpc.UnaryInterceptor(s.authorizeUnary),
+		grpc.StreamInterceptor(s.authorizeStream),
+	)
+	syncv1.RegisterSyncEventsServer(s.grpc, &eventService{server: s})
+	syncv1.RegisterMappingsServer(s.grpc, &mappingService{server: s})
+	return s
+}
+
+// AddTenant makes a tenant's events and mappings available; a nil server ignores it
+func (s *Server) AddTenant(id string, t *Tenant) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenants[id] = t
+}
+
+// Start listens on Addr and serves in the background
+func (s *Server) Start() error {
+	if s == nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	slog.Info("starting gRPC server", "addr", s.Addr)
+	go func() {
+		if err := s.grpc.Serve(listener); err != nil {
+			slog.Error("gRPC server error", "error", err)
+		}
+	}()
+	return nil
+}
+
+// Stop ends open streams and waits up to timeout for unary calls to finish
+func (s *Server) Stop(timeout time.Duration) {
+	if s == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		s.grpc.Stop()
+	}
+}
+
+// tenantKey carries the tenant ID authorize resolved for a call
+type tenantKey struct{}
+
+// tenant returns the tenant a call was authorized for
+func (s *Server) tenant(ctx context.Context) (*Tenant, error) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "call is not authorized")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.tenants[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown tenant %q", id)
+	}
+	return t, nil
+}
+
+// authorize checks the bearer token in a call's metadata and returns a
+// context carrying the tenant the token belongs to
+func (s *Server) authorize(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id, err := s.credentialTenant(md.Get("authorization"))
+	if err != nil {
+		return nil, err
+	}
+	if values := md.Get(MetadataTenant); len(values) > 0 && values[0] != "" && values[0] != id {
+		return nil, status.Error(codes.PermissionDenied, "credentials do not belong to the requested tenant")
+	}
+	return context.WithValue(ctx, tenantKey{}, id), nil
+}
+
+// credentialTenant returns the tenant of the first bearer token that is
+// GRPC_AUTH_TOKEN or a tenant API key
+func (s *Server) credentialTenant(values []string) (string, error) {
+	for _, value := range values {
+		token := strings.TrimPrefix(value, "Bearer ")
+		if token == "" {
+			continue
+		}
+		if s.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1 {
+			return tenant.DefaultID, nil
+		}
+		if s.Tenants != nil {
+			if t, ok := s.Tenants.ByAPIKey(token); ok {
+				return t.ID, nil
+			}
+		}
+	}
+	if len(values) == 0 && s.AllowUnauthenticated {
+		return tenant.DefaultID, nil
+	}
+	return "", status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+func (s *Server) authorizeUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authorizedStream hands handlers the context authorize resolved
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (a *authorizedStream) Context() context.Context {
+	return a.ctx
+}
+
+// eventService implements syncv1.SyncEventsServer
+type eventService struct {
+	syncv1.UnimplementedSyncEventsServer
+	server *Server
+}
+
+// Subscribe streams the tenant's events until the client goes away or the server stops
+func (e *eventService) Subscribe(req *syncv1.SubscribeRequest, stream syncv1.SyncEvents_SubscribeServer) error {
+	t, err := e.server.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	if t.Events == nil {
+		return status.Error(codes.Unavailable, "sync events are not available")
+	}
+
+	events, dropped, cancel := t.Events.Subscribe(syncevents.Filter{
+		Tables:     req.GetTables(),
+		Severities: req.GetSeverities(),
+		Sources:    req.GetSources(),
+	}, syncevents.DefaultBuffer)
+	defer cancel()
+
+	// Report misses on the next event sent rather than as a separate message
+	var reported uint64
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			missed := dropped()
+			message := toProto(event)
+			message.Dropped = missed - reported
+			reported = missed
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toProto converts an event to its wire form
+func toProto(event syncevents.Event) *syncv1.SyncEvent {
+	return &syncv1.SyncEvent{
+		Sequence: event.Sequence,
+		Time:     timestamppb.New(event.Time),
+		Tenant:   event.Tenant,
+		Source:   event.Source,
+		Action:   event.Action,
+		Table:    event.Table,
+		SysId:    event.SysID,
+		Number:   event.Number,
+		Severity: event.Severity,
+		JiraKey:  event.JiraKey,
+		Result:   event.Result,
+		Error:    event.Error,
+	}
+}
+
+// mappingService implements syncv1.MappingsServer
+type mappingService struct {
+	syncv1.UnimplementedMappingsServer
+	server *Server
+}
+
+// LookupMapping finds a link by ServiceNow sys_id or Jira key, risks first
+func (m *mappingService) LookupMapping(ctx context.Context, req *syncv1.LookupMappingRequest) (*syncv1.Mapping, error) {
+	t, err := m.server.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := req.GetKey().(type) {
+	case *syncv1.LookupMappingRequest_SysId:
+		if t.Risks != nil {
+			if jiraKey, ok := t.Risks.GetJiraKeyFromRiskID(key.SysId); ok {
+				return &syncv1.Mapping{Kind: KindRisk, SysId: key.SysId, JiraKey: jiraKey}, nil
+			}
+		}
+		if t.Incidents != nil {
+			if jiraKey, ok := t.Incidents.GetJiraKeyFromIncidentID(key.SysId); ok {
+				return &syncv1.Mapping{Kind: KindIncident, SysId: key.SysId, JiraKey: jiraKey}, nil
+			}
+		}
+		return nil, status.Errorf(codes.NotFound, "no Jira issue is linked to %s", key.SysId)
+	case *syncv1.LookupMappingRequest_JiraKey:
+		if t.Risks != nil {
+			if sysID, ok := t.Risks.GetRiskIDFromJiraKey(key.JiraKey); ok {
+				return &syncv1.Mapping{Kind: KindRisk, SysId: sysID, JiraKey: key.JiraKey}, nil
+			}
+		}
+		if t.Incidents != nil {
+			if sysID, ok := t.Incidents.GetIncidentIDFromJiraKey(key.JiraKey); ok {
+				return &syncv1.Mapping{Kind: KindIncident, SysId: sysID, JiraKey: key.JiraKey}, nil
+			}
+		}
+		return nil, status.Errorf(codes.NotFound, "no ServiceNow record is linked to %s", key.JiraKey)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "sys_id or jira_key is required")
+	}
+}
+
+// ListMappings returns every link of a kind, or of both, ordered by kind and sys_id
+func (m *mappingService) ListMappings(ctx context.Context, req *syncv1.ListMappingsRequest) (*syncv1.ListMappingsResponse, error) {
+	t, err := m.server.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kind := req.GetKind()
+	if kind != "" && kind != KindRisk && kind != KindIncident {
+		return nil, status.Errorf(codes.InvalidArgument, "kind must be %s or %s", KindRisk, KindIncident)
+	}
+
+	response := &syncv1.ListMappingsResponse{}
+	if (kind == "" || kind == KindRisk) && t.Risks != nil {
+		risks, _, err := t.Risks.Snapshot()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error reading risk mappings: %v", err)
+		}
+		response.Mappings = append(response.Mappings, sortedMappings(KindRisk, risks)...)
+	}
+	if (kind == "" || kind == KindIncident) && t.Incidents != nil {
+		incidents, _ := t.Incidents.Snapshot()
+		response.Mappings = append(response.Mappings, sortedMappings(KindIncident, incidents)...)
+	}
+	return response, nil
+}
+
+// sortedMappings converts a sys_id to Jira key index, ordered by sys_id
+func sortedMappings(kind string, links map[string]string) []*syncv1.Mapping {
+	mappings := make([]*syncv1.Mapping, 0, len(links))
+	for sysID, jiraKey := range links {
+		mappings = append(mappings, &syncv1.Mapping{Kind: kind, SysId: sysID, JiraKey: jiraKey})
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].SysId < mappings[j].SysId })
+	return mappings
+}
@@ -0,0 +1,191 @@
+// backend/internal/i18n/i18n.go
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is used when no locale is configured and fills in messages a
+// catalog lacks
+const DefaultLocale = "en"
+
+//go:embed locales/*.yaml
+var builtin embed.FS
+
+// Catalog maps message keys to fmt formats. Formats may use explicit argument
+// indexes such as %[2]s when a language orders the arguments differently.
+type Catalog map[string]string
+
+var (
+	mutex    sync.RWMutex
+	catalogs = make(map[string]Catalog)
+)
+
+func init() {
+	entries, err := builtin.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("error reading built-in message catalogs: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := builtin.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("error reading message catalog %s: %v", entry.Name(), err))
+		}
+		if err := add(strings.TrimSuffix(entry.Name(), ".yaml"), data); err != nil {
+			panic(err.Error())
+		}
+	}
+}
+
+// LoadDir adds the <locale>.yaml catalogs of a directory, so languages can be
+// added or wording changed without a rebuild. Their keys replace the built-in
+// ones; keys they leave out keep the built-in text.
+func LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return fmt.Errorf("error listing message catalogs: %w", err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading message catalog %s: %w", path, err)
+		}
+		if err := add(strings.TrimSuffix(filepath.Base(path), ".yaml"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigureFromEnv loads NOTIFICATION_CATALOG_DIR when it is set
+func ConfigureFromEnv() error {
+	if dir := os.Getenv("NOTIFICATION_CATALOG_DIR"); dir != "" {
+		return LoadDir(dir)
+	}
+	return nil
+}
+
+// add merges a catalog into the one of its locale
+func add(locale string, data []byte) error {
+	var catalog Catalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("error parsing message catalog %s: %w", locale, err)
+	}
+
+	locale = strings.ToLower(locale)
+	mutex.Lock()
+	defer mutex.Unlock()
+	if catalogs[locale] == nil {
+		catalogs[locale] = make(Catalog)
+	}
+	for key, format := range catalog {
+		catalogs[locale][key] = format
+	}
+	return nil
+}
+
+// Supported returns the locales with a catalog, sorted
+func Supported() []string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Normalize returns the catalog locale for a tag such as "es", "es-MX" or
+// "pt_BR", preferring the full tag over its language. It returns "" when
+// neither has a catalog.
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if locale == "" {
+		return ""
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if _, ok := catalogs[locale]; ok {
+		return locale
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	if _, ok := catalogs[language]; ok {
+		return language
+	}
+	return ""
+}
+
+// T formats the message key in a locale. Unknown locales and keys missing
+// from the locale's catalog fall back to English, and a key no catalog has is
+// returned as is so the gap shows in Slack rather than an empty message.
+func T(locale, key string, args ...interface{}) string {
+	format, ok := lookup(locale, key)
+	if !ok {
+		slog.Warn("message key missing from catalogs", "key", key, "locale", locale)
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Value translates a value taken from a record, such as a severity or state,
+// using the key "<kind>.<value>" in lower case. Values without a translation
+// are returned unchanged.
+func Value(locale, kind, value string) string {
+	if value == "" {
+		return value
+	}
+	key := kind + "." + strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), " ", "_"))
+	if format, ok := lookup(locale, key); ok {
+		return format
+	}
+	return value
+}
+
+// Date formats a date with the locale's "format.date" layout
+func Date(locale string, t time.Time) string {
+	return t.Format(T(locale, "format.date"))
+}
+
+// DateTime formats a time with the locale's "format.datetime" layout
+func DateTime(locale string, t time.Time) string {
+	return t.Format(T(locale, "format.datetime"))
+}
+
+// Plural picks "<key>.one" for a count of one and "<key>.other" otherwise,
+// and formats it with the count as its first argument
+func Plural(locale, key string, n int, args ...interface{}) string {
+	form := key + ".other"
+	if n == 1 {
+		form = key + ".one"
+	}
+	return T(locale, form, append([]interface{}{n}, args...)...)
+}
+
+// lookup finds a key in the locale's catalog, then in the default one
+func lookup(locale, key string) (string, bool) {
+	locale = Normalize(locale)
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if format, ok := catalogs[locale][key]; ok {
+		return format, true
+	}
+	format, ok := catalogs[DefaultLocale][key]
+	return format, ok
+}
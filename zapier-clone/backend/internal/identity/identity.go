@@ -0,0 +1,60 @@
+// backend/internal/identity/identity.go
+package identity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Sources of an identity mapping
+const (
+	// SourceManual mappings were entered through the API; auto-matching never changes them
+	SourceManual = "manual"
+	// SourceAuto mappings were matched by email and are filled in as more accounts are found
+	SourceAuto = "auto"
+)
+
+// Errors returned by the identity store
+var (
+	ErrNotFound = errors.New("identity not found")
+	ErrExists   = errors.New("an identity already maps this ServiceNow user")
+	ErrInvalid  = errors.New("servicenow_user_id is required")
+	ErrSource   = errors.New("source must be manual or auto")
+)
+
+// Identity is one person's accounts in ServiceNow, Jira and Slack
+type Identity struct {
+	ID int `json:"id"`
+	// ServiceNowUserID is the sys_id of the person's sys_user record
+	ServiceNowUserID   string    `json:"servicenow_user_id"`
+	ServiceNowUsername string    `json:"servicenow_username,omitempty"`
+	Email              string    `json:"email,omitempty"`
+	DisplayName        string    `json:"display_name,omitempty"`
+	JiraAccountID      string    `json:"jira_account_id,omitempty"`
+	SlackUserID        string    `json:"slack_user_id,omitempty"`
+	Source             string    `json:"source"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Validate checks an identity before it is stored and fills in its source
+func (i *Identity) Validate() error {
+	i.ServiceNowUserID = strings.TrimSpace(i.ServiceNowUserID)
+	i.Email = strings.TrimSpace(i.Email)
+	if i.ServiceNowUserID == "" {
+		return ErrInvalid
+	}
+	if i.Source == "" {
+		i.Source = SourceManual
+	}
+	if i.Source != SourceManual && i.Source != SourceAuto {
+		return ErrSource
+	}
+	return nil
+}
+
+// complete reports whether every account of the identity is known
+func (i *Identity) complete() bool {
+	return i.JiraAccountID != "" && i.SlackUserID != ""
+}
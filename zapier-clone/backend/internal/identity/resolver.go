@@ -0,0 +1,286 @@
+// backend/internal/identity/resolver.go
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// Resolver translates people between ServiceNow, Jira and Slack. Stored
+// mappings come first; a person without one is matched by the email on their
+// sys_user record, and the match is stored with SourceAuto.
+type Resolver struct {
+	Store            *Store
+	ServiceNowClient *servicenow.Client
+	JiraClient       *jira.Client
+	SlackClient      *slack.Client
+	// RetryAfter is how long a person who could not be matched is left alone
+	// before trying again, so every webhook does not repeat the lookups
+	RetryAfter time.Duration
+
+	mutex  sync.Mutex
+	misses map[string]time.Time
+	now    func() time.Time
+}
+
+// NewResolver creates a resolver that retries failed matches after an hour
+func NewResolver(store *Store, serviceNowClient *servicenow.Client, jiraClient *jira.Client, slackClient *slack.Client) *Resolver {
+	return &Resolver{
+		Store:            store,
+		ServiceNowClient: serviceNowClient,
+		JiraClient:       jiraClient,
+		SlackClient:      slackClient,
+		RetryAfter:       time.Hour,
+		misses:           make(map[string]time.Time),
+		now:              time.Now,
+	}
+}
+
+// JiraAccountID returns the Jira account of a ServiceNow user, given as a
+// sys_id or user_name, or "" when none is known
+func (r *Resolver) JiraAccountID(ctx context.Context, serviceNowUser string) string {
+	if identity := r.lookup(ctx, serviceNowUser); identity != nil {
+		return identity.JiraAccountID
+	}
+	return ""
+}
+
+// SlackUserID returns the Slack user of a ServiceNow user, or "" when none is known
+func (r *Resolver) SlackUserID(ctx context.Context, serviceNowUser string) string {
+	if identity := r.lookup(ctx, serviceNowUser); identity != nil {
+		return identity.SlackUserID
+	}
+	return ""
+}
+
+// ServiceNowUserID returns the sys_id of a Slack user's sys_user record, or ""
+// when none is known
+func (r *Resolver) ServiceNowUserID(ctx context.Context, slackUserID string) string {
+	if slackUserID == "" {
+		return ""
+	}
+	identity, err := r.Store.FindBySlackUser(slackUserID)
+	if errors.Is(err, ErrNotFound) && r.retry("slack:"+slackUserID) {
+		identity, err = r.matchSlackUser(ctx, slackUserID)
+		if errors.Is(err, ErrNotFound) {
+			r.miss("slack:" + slackUserID)
+		}
+	}
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			logging.FromContext(ctx).Error("error resolving Slack user", "slack_user", slackUserID, "error", err)
+		}
+		return ""
+	}
+	return identity.ServiceNowUserID
+}
+
+// lookup returns the identity of a ServiceNow user, matching them when they
+// have no stored mapping or an automatic one with accounts missing. Errors are
+// logged; callers fall back to the raw value.
+func (r *Resolver) lookup(ctx context.Context, serviceNowUser string) *Identity {
+	serviceNowUser = strings.TrimSpace(serviceNowUser)
+	if serviceNowUser == "" {
+		return nil
+	}
+
+	identity, err := r.Store.FindByServiceNowUser(serviceNowUser)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		logging.FromContext(ctx).Error("error reading identity", "user", serviceNowUser, "error", err)
+		return nil
+	}
+	if identity != nil && (identity.Source == SourceManual || identity.complete()) {
+		return identity
+	}
+	if !r.retry("servicenow:" + serviceNowUser) {
+		return identity
+	}
+
+	matched, err := r.Match(ctx, serviceNowUser)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			logging.FromContext(ctx).Error("error matching identity", "user", serviceNowUser, "error", err)
+		}
+		r.miss("servicenow:" + serviceNowUser)
+		return identity
+	}
+	if !matched.complete() {
+		r.miss("servicenow:" + serviceNowUser)
+	}
+	return matched
+}
+
+// Match looks up a ServiceNow user's Jira and Slack accounts by the email on
+// their sys_user record and stores what was found. A manual mapping is
+// returned unchanged. It returns ErrNotFound when the user or their email is unknown.
+func (r *Resolver) Match(ctx context.Context, serviceNowUser string) (*Identity, error) {
+	user, err := r.serviceNowUser(ctx, serviceNowUser)
+	if err != nil {
+		return nil, err
+	}
+	return r.matchRecord(ctx, user)
+}
+
+// matchSlackUser finds the sys_user with a Slack user's email and matches them
+func (r *Resolver) matchSlackUser(ctx context.Context, slackUserID string) (*Identity, error) {
+	if r.SlackClient == nil {
+		return nil, ErrNotFound
+	}
+	member, err := r.SlackClient.WithContext(ctx).GetUserInfo(slackUserID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Slack user %s: %w", slackUserID, err)
+	}
+	if member == nil || member.Profile.Email == "" {
+		return nil, ErrNotFound
+	}
+
+	user, err := r.serviceNowUser(ctx, member.Profile.Email)
+	if err != nil {
+		return nil, err
+	}
+	return r.matchRecord(ctx, user)
+}
+
+// serviceNowUser reads the sys_user record with a sys_id, user_name or email
+func (r *Resolver) serviceNowUser(ctx context.Context, user string) (map[string]interface{}, error) {
+	client := r.ServiceNowClient.WithContext(ctx)
+	if isSysID(user) {
+		record, err := client.GetRecord("sys_user", user)
+		if errors.Is(err, servicenow.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading ServiceNow user %s: %w", user, err)
+		}
+		return record, nil
+	}
+
+	records, err := client.QueryRecords("sys_user", fmt.Sprintf("user_name=%s^ORemail=%s", user, user), 1)
+	if err != nil {
+		return nil, fmt.Errorf("error finding ServiceNow user %s: %w", user, err)
+	}
+	if len(records) == 0 {
+		return nil, ErrNotFound
+	}
+	return records[0], nil
+}
+
+// matchRecord stores the identity of a sys_user record, filling in the Jira
+// and Slack accounts that have the record's email
+func (r *Resolver) matchRecord(ctx context.Context, user map[string]interface{}) (*Identity, error) {
+	sysID := field(user, "sys_id")
+	email := field(user, "email")
+	if sysID == "" || email == "" {
+		return nil, ErrNotFound
+	}
+
+	identity, err := r.Store.FindByServiceNowUser(sysID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if identity != nil && identity.Source == SourceManual {
+		return identity, nil
+	}
+	if identity == nil {
+		identity = &Identity{ServiceNowUserID: sysID, Source: SourceAuto}
+	}
+	identity.ServiceNowUsername = field(user, "user_name")
+	identity.Email = email
+	identity.DisplayName = field(user, "name")
+
+	if identity.JiraAccountID == "" && r.JiraClient != nil {
+		account, err := r.JiraClient.WithContext(ctx).FindUserByEmail(email)
+		if err != nil {
+			return nil, err
+		}
+		if account != nil {
+			identity.JiraAccountID = account.ID
+		}
+	}
+	if identity.SlackUserID == "" && r.SlackClient != nil {
+		member, err := r.SlackClient.WithContext(ctx).LookupUserByEmail(email)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil && !member.Deleted {
+			identity.SlackUserID = member.ID
+		}
+	}
+
+	var saved *Identity
+	if identity.ID == 0 {
+		saved, err = r.Store.Create(*identity)
+	} else {
+		saved, err = r.Store.Update(identity.ID, *identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("matched identity by email", "servicenow_user", sysID,
+		"jira_account", saved.JiraAccountID != "", "slack_user", saved.SlackUserID != "")
+	return saved, nil
+}
+
+// retry reports whether a person who could not be matched may be tried again
+func (r *Resolver) retry(key string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	missed, ok := r.misses[key]
+	if ok && r.now().Sub(missed) < r.RetryAfter {
+		return false
+	}
+	delete(r.misses, key)
+	return true
+}
+
+// miss records a person who could not be fully matched
+func (r *Resolver) miss(key string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.misses[key] = r.now()
+}
+
+// Forget clears failed matches, such as after mappings were edited
+func (r *Resolver) Forget() {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.misses = make(map[string]time.Time)
+}
+
+// field reads a sys_user field returned as a string or as
+// {"display_value": ..., "value": ...}
+func field(record map[string]interface{}, name string) string {
+	switch value := record[name].(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		s, _ := value["value"].(string)
+		return s
+	}
+	return ""
+}
+
+// isSysID reports whether a value is a 32-character ServiceNow sys_id
+func isSysID(value string) bool {
+	if len(value) != 32 {
+		return false
+	}
+	for _, c := range value {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
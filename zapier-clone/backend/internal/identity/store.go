@@ -0,0 +1,172 @@
+// backend/internal/identity/store.go
+package identity
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// identityColumns are selected in the order scanIdentity reads them
+const identityColumns = `id, servicenow_user_id, COALESCE(servicenow_username, ''), COALESCE(email, ''),
+	COALESCE(display_name, ''), COALESCE(jira_account_id, ''), COALESCE(slack_user_id, ''), source, created_at, updated_at`
+
+// Store manages one tenant's identity mappings in PostgreSQL
+type Store struct {
+	DB     *sql.DB
+	Tenant string
+}
+
+// NewStore creates an identity store for a tenant
+func NewStore(db *sql.DB, tenant string) *Store {
+	return &Store{DB: db, Tenant: tenant}
+}
+
+// List returns every identity, ordered by display name and email
+func (s *Store) List() ([]Identity, error) {
+	rows, err := s.DB.Query(
+		`SELECT `+identityColumns+` FROM identities WHERE tenant_id = $1
+		 ORDER BY LOWER(COALESCE(display_name, email, servicenow_user_id))`, s.Tenant)
+	if err != nil {
+		return nil, fmt.Errorf("error listing identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := []Identity{}
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, *identity)
+	}
+	return identities, rows.Err()
+}
+
+// Get fetches an identity by ID
+func (s *Store) Get(id int) (*Identity, error) {
+	return s.find(`id = $2`, id)
+}
+
+// FindByServiceNowUser fetches the identity of a sys_user by sys_id or user_name
+func (s *Store) FindByServiceNowUser(user string) (*Identity, error) {
+	return s.find(`(servicenow_user_id = $2 OR LOWER(servicenow_username) = LOWER($2))`, user)
+}
+
+// FindBySlackUser fetches the identity with a Slack user ID
+func (s *Store) FindBySlackUser(slackUserID string) (*Identity, error) {
+	return s.find(`slack_user_id = $2`, slackUserID)
+}
+
+// FindByJiraAccount fetches the identity with a Jira account ID
+func (s *Store) FindByJiraAccount(accountID string) (*Identity, error) {
+	return s.find(`jira_account_id = $2`, accountID)
+}
+
+// FindByEmail fetches the identity with an email address, case-insensitively
+func (s *Store) FindByEmail(email string) (*Identity, error) {
+	return s.find(`LOWER(email) = LOWER($2)`, email)
+}
+
+// find fetches the first identity matching a condition on $2
+func (s *Store) find(condition string, arg interface{}) (*Identity, error) {
+	row := s.DB.QueryRow(`SELECT `+identityColumns+` FROM identities WHERE tenant_id = $1 AND `+condition+` ORDER BY id LIMIT 1`, s.Tenant, arg)
+	identity, err := scanIdentity(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return identity, err
+}
+
+// Create stores a new identity
+func (s *Store) Create(identity Identity) (*Identity, error) {
+	if err := identity.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.DB.QueryRow(
+		`INSERT INTO identities (tenant_id, servicenow_user_id, servicenow_username, email, display_name, jira_account_id, slack_user_id, source)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING id, created_at, updated_at`,
+		s.Tenant, identity.ServiceNowUserID, nullString(identity.ServiceNowUsername), nullString(identity.Email),
+		nullString(identity.DisplayName), nullString(identity.JiraAccountID), nullString(identity.SlackUserID), identity.Source,
+	).Scan(&identity.ID, &identity.CreatedAt, &identity.UpdatedAt)
+	if isUniqueViolation(err) {
+		return nil, ErrExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating identity: %w", err)
+	}
+	return &identity, nil
+}
+
+// Update replaces an identity's fields
+func (s *Store) Update(id int, identity Identity) (*Identity, error) {
+	if err := identity.Validate(); err != nil {
+		return nil, err
+	}
+
+	identity.ID = id
+	err := s.DB.QueryRow(
+		`UPDATE identities SET servicenow_user_id = $3, servicenow_username = $4, email = $5, display_name = $6,
+		 jira_account_id = $7, slack_user_id = $8, source = $9, updated_at = CURRENT_TIMESTAMP
+		 WHERE tenant_id = $1 AND id = $2
+		 RETURNING created_at, updated_at`,
+		s.Tenant, id, identity.ServiceNowUserID, nullString(identity.ServiceNowUsername), nullString(identity.Email),
+		nullString(identity.DisplayName), nullString(identity.JiraAccountID), nullString(identity.SlackUserID), identity.Source,
+	).Scan(&identity.CreatedAt, &identity.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if isUniqueViolation(err) {
+		return nil, ErrExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating identity: %w", err)
+	}
+	return &identity, nil
+}
+
+// Delete removes an identity
+func (s *Store) Delete(id int) error {
+	result, err := s.DB.Exec(`DELETE FROM identities WHERE tenant_id = $1 AND id = $2`, s.Tenant, id)
+	if err != nil {
+		return fmt.Errorf("error deleting identity: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanIdentity reads a row selected with identityColumns
+func scanIdentity(row rowScanner) (*Identity, error) {
+	identity := &Identity{}
+	err := row.Scan(&identity.ID, &identity.ServiceNowUserID, &identity.ServiceNowUsername, &identity.Email,
+		&identity.DisplayName, &identity.JiraAccountID, &identity.SlackUserID, &identity.Source, &identity.CreatedAt, &identity.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning identity: %w", err)
+	}
+	return identity, nil
+}
+
+// isUniqueViolation reports whether an insert or update hit a unique constraint
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
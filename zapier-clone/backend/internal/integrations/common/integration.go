@@ -0,0 +1,233 @@
+// backend/internal/integrations/common/integration.go
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Integration errors
+var (
+	ErrUnknownIntegration = errors.New("unknown integration")
+	ErrUnknownAction      = errors.New("unknown action")
+	// ErrNotSupported is returned when an integration lacks a capability
+	ErrNotSupported = errors.New("not supported by this integration")
+	// ErrMissingField is returned when an action is called without a required field
+	ErrMissingField = errors.New("missing required field")
+)
+
+// Integration is an external service the backend connects to. Capabilities are
+// opt-in: an integration also implements Trigger when it receives webhooks,
+// Action when workflows can call it, ConnectionValidator when its
+// credentials can be checked and RouteProvider when it serves endpoints of
+// its own.
+type Integration interface {
+	// Name is the integration's service key, e.g. "jira"; webhooks arrive at /api/webhooks/<name>
+	Name() string
+}
+
+// TriggerSpec describes an event that can start a workflow
+type TriggerSpec struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// ActionSpec describes something a workflow can ask an integration to do
+type ActionSpec struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	// Fields are required; actions accept further optional fields
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Trigger is an integration that starts workflows from its webhooks
+type Trigger interface {
+	Integration
+	Triggers() []TriggerSpec
+	// WebhookHandler serves the integration's webhooks, including verifying them
+	WebhookHandler() http.Handler
+}
+
+// Action is an integration that workflows can call
+type Action interface {
+	Integration
+	Actions() []ActionSpec
+	// Execute runs an action with mapped fields and returns its output, which
+	// later workflow steps can map from
+	Execute(ctx context.Context, action string, fields map[string]interface{}) (map[string]interface{}, error)
+}
+
+// ConnectionValidator is an integration whose credentials can be checked
+type ConnectionValidator interface {
+	Integration
+	ValidateConnection(ctx context.Context) error
+}
+
+// Route is an endpoint an integration serves besides its webhook
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.Handler
+}
+
+// RouteProvider is an integration that serves its own endpoints, such as a
+// chat app's interaction callbacks. The routes are mounted as they are, so
+// their handlers verify their own requests.
+type RouteProvider interface {
+	Integration
+	Routes() []Route
+}
+
+// Descriptor summarizes an integration's capabilities for API listings
+type Descriptor struct {
+	Name                string        `json:"name"`
+	Triggers            []TriggerSpec `json:"triggers,omitempty"`
+	Actions             []ActionSpec  `json:"actions,omitempty"`
+	ReceivesWebhooks    bool          `json:"receives_webhooks"`
+	ValidatesConnection bool          `json:"validates_connection"`
+}
+
+// Describe lists an integration's capabilities
+func Describe(integration Integration) Descriptor {
+	descriptor := Descriptor{Name: integration.Name()}
+	if trigger, ok := integration.(Trigger); ok {
+		descriptor.Triggers = trigger.Triggers()
+		descriptor.ReceivesWebhooks = trigger.WebhookHandler() != nil
+	}
+	if action, ok := integration.(Action); ok {
+		descriptor.Actions = action.Actions()
+	}
+	_, descriptor.ValidatesConnection = integration.(ConnectionValidator)
+	return descriptor
+}
+
+// Registry holds the integrations of one tenant. Routes and workflow steps are
+// looked up here, so a new integration only has to be registered.
+type Registry struct {
+	mutex        sync.RWMutex
+	integrations map[string]Integration
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		integrations: make(map[string]Integration),
+	}
+}
+
+// Register adds an integration; names must be unique
+func (r *Registry) Register(integration Integration) error {
+	name := integration.Name()
+	if name == "" {
+		return fmt.Errorf("integration needs a name")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.integrations[name]; exists {
+		return fmt.Errorf("integration %s is already registered", name)
+	}
+	r.integrations[name] = integration
+	return nil
+}
+
+// Get returns a registered integration
+func (r *Registry) Get(name string) (Integration, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	integration, ok := r.integrations[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownIntegration, name)
+	}
+	return integration, nil
+}
+
+// List returns the registered integrations ordered by name
+func (r *Registry) List() []Integration {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	integrations := make([]Integration, 0, len(r.integrations))
+	for _, integration := range r.integrations {
+		integrations = append(integrations, integration)
+	}
+	sort.Slice(integrations, func(i, j int) bool { return integrations[i].Name() < integrations[j].Name() })
+	return integrations
+}
+
+// Triggers returns the integrations that receive webhooks, ordered by name
+func (r *Registry) Triggers() []Trigger {
+	var triggers []Trigger
+	for _, integration := range r.List() {
+		if trigger, ok := integration.(Trigger); ok && trigger.WebhookHandler() != nil {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers
+}
+
+// Routes returns the endpoints of the integrations that serve their own,
+// ordered by integration name
+func (r *Registry) Routes() []Route {
+	var routes []Route
+	for _, integration := range r.List() {
+		if provider, ok := integration.(RouteProvider); ok {
+			routes = append(routes, provider.Routes()...)
+		}
+	}
+	return routes
+}
+
+// Execute runs an action of a registered integration
+func (r *Registry) Execute(ctx context.Context, name, action string, fields map[string]interface{}) (map[string]interface{}, error) {
+	integration, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	actor, ok := integration.(Action)
+	if !ok {
+		return nil, fmt.Errorf("%s actions: %w", name, ErrNotSupported)
+	}
+	return actor.Execute(ctx, action, fields)
+}
+
+// Validate checks a registered integration's connection
+func (r *Registry) Validate(ctx context.Context, name string) error {
+	integration, err := r.Get(name)
+	if err != nil {
+		return err
+	}
+	validator, ok := integration.(ConnectionValidator)
+	if !ok {
+		return fmt.Errorf("%s connection checks: %w", name, ErrNotSupported)
+	}
+	return validator.ValidateConnection(ctx)
+}
+
+// RequireFields returns ErrMissingField naming the first required field that is missing or empty
+func RequireFields(fields map[string]interface{}, names ...string) error {
+	for _, name := range names {
+		if value, ok := fields[name]; !ok || value == nil || value == "" {
+			return fmt.Errorf("%w: %s", ErrMissingField, name)
+		}
+	}
+	return nil
+}
+
+// StringField returns a field as a string, or "" when it is missing
+func StringField(fields map[string]interface{}, name string) string {
+	value, ok := fields[name]
+	if !ok || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
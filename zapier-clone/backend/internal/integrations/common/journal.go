@@ -0,0 +1,136 @@
+// backend/internal/integrations/common/journal.go
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// JournaledFile keeps the JSON snapshot of an in-memory store safe from a
+// crash mid-save. A change is appended to a journal and synced before it is
+// applied; the snapshot is then replaced through a temporary file and a rename,
+// and the journal is cleared. After a crash the file holds either the old
+// snapshot, with the change still in the journal, or the new one.
+//
+// JournaledFile does no locking: callers hold their store's write lock across
+// Append, applying the change and Save.
+type JournaledFile struct {
+	Path string
+}
+
+// NewJournaledFile creates a journaled file; the journal is Path + ".journal"
+func NewJournaledFile(path string) *JournaledFile {
+	return &JournaledFile{Path: path}
+}
+
+// journalPath is where changes not yet in the snapshot are kept
+func (f *JournaledFile) journalPath() string {
+	return f.Path + ".journal"
+}
+
+// Load reads the snapshot into v and returns the journaled changes the
+// snapshot may not include, oldest first. The caller applies them, which must
+// be safe to repeat, and then calls Save. Missing files are not an error, and a
+// change whose journal line was cut short by a crash is dropped, since it was
+// never applied.
+func (f *JournaledFile) Load(v interface{}) ([]json.RawMessage, error) {
+	data, err := os.ReadFile(f.Path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", f.Path, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("error reading %s: %w", f.Path, err)
+	}
+
+	journal, err := os.ReadFile(f.journalPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", f.journalPath(), err)
+	}
+
+	var changes []json.RawMessage
+	scanner := bufio.NewScanner(bytes.NewReader(journal))
+	scanner.Buffer(make([]byte, 64*1024), len(journal)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || !json.Valid(line) {
+			continue
+		}
+		changes = append(changes, json.RawMessage(append([]byte(nil), line...)))
+	}
+	return changes, nil
+}
+
+// Append writes a change to the journal and syncs it to disk
+func (f *JournaledFile) Append(change interface{}) error {
+	line, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("error encoding journal entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	journal, err := os.OpenFile(f.journalPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening journal: %w", err)
+	}
+	defer journal.Close()
+	if _, err := journal.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing journal: %w", err)
+	}
+	return journal.Sync()
+}
+
+// Save replaces the snapshot with v and clears the journal
+func (f *JournaledFile) Save(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %w", filepath.Base(f.Path), err)
+	}
+	dir := filepath.Dir(f.Path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(f.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error syncing %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), f.Path); err != nil {
+		return fmt.Errorf("error replacing %s: %w", f.Path, err)
+	}
+	// Make the rename itself durable before the journal goes
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+
+	if err := os.Remove(f.journalPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error clearing journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// backend/internal/integrations/common/secret.go
+package common
+
+import "sync"
+
+// Secret is a credential that can be replaced while requests are in flight,
+// for example when a secrets manager rotates it. Client copies made with
+// WithContext share the pointer and so see the new value. A nil Secret is empty.
+type Secret struct {
+	mu    sync.RWMutex
+	value string
+}
+
+// NewSecret returns a secret holding value
+func NewSecret(value string) *Secret {
+	return &Secret{value: value}
+}
+
+// Get returns the current value
+func (s *Secret) Get() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
+
+// Set replaces the value for requests made from now on
+func (s *Secret) Set(value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.value = value
+	s.mu.Unlock()
+}
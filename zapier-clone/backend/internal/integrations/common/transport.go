@@ -0,0 +1,335 @@
+// backend/internal/integrations/common/transport.go
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// Defaults for the shared outbound transport
+const (
+	DefaultRate             = 10.0
+	DefaultBurst            = 20
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 30 * time.Second
+	DefaultMaxRetryWait     = 30 * time.Second
+	DefaultMaxRetries       = 2
+)
+
+// ErrCircuitOpen is returned without contacting a host whose circuit is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// hostState is the rate limit bucket and circuit breaker for one host
+type hostState struct {
+	mutex sync.Mutex
+
+	tokens   float64
+	lastFill time.Time
+	// blockedUntil is set from Retry-After; nothing is sent to the host before it
+	blockedUntil time.Time
+
+	state    string
+	failures int
+	openedAt time.Time
+	// probing is true while the single half-open request is in flight
+	probing bool
+}
+
+// Transport wraps an http.RoundTripper with a per-host token bucket, Retry-After
+// handling and a circuit breaker. Requests wait for a token rather than fail,
+// bounded by their context. After FailureThreshold consecutive failures
+// (transport errors or 5xx responses) the host's circuit opens and requests
+// fail fast with ErrCircuitOpen for Cooldown; then one request is let through
+// to probe the host, and its outcome closes or reopens the circuit.
+type Transport struct {
+	Base http.RoundTripper
+	// Rate is the number of requests per second allowed to each host
+	Rate float64
+	// Burst is the number of requests a host can receive at once
+	Burst            int
+	FailureThreshold int
+	Cooldown         time.Duration
+	// MaxRetries is how often a 429 is retried after its Retry-After delay
+	MaxRetries int
+	// MaxRetryWait caps how long a Retry-After delay is honoured before the
+	// response is returned to the caller instead
+	MaxRetryWait time.Duration
+
+	mutex sync.Mutex
+	hosts map[string]*hostState
+	now   func() time.Time
+}
+
+// NewTransport creates a transport with the default limits
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		Base:             base,
+		Rate:             DefaultRate,
+		Burst:            DefaultBurst,
+		FailureThreshold: DefaultFailureThreshold,
+		Cooldown:         DefaultCooldown,
+		MaxRetries:       DefaultMaxRetries,
+		MaxRetryWait:     DefaultMaxRetryWait,
+		hosts:            make(map[string]*hostState),
+		now:              time.Now,
+	}
+}
+
+// NewTransportFromEnv reads OUTBOUND_RATE_LIMIT (requests per second per host),
+// OUTBOUND_RATE_BURST, OUTBOUND_BREAKER_THRESHOLD and OUTBOUND_BREAKER_COOLDOWN
+func NewTransportFromEnv() *Transport {
+	t := NewTransport(nil)
+	if value := os.Getenv("OUTBOUND_RATE_LIMIT"); value != "" {
+		if rate, err := strconv.ParseFloat(value, 64); err == nil && rate > 0 {
+			t.Rate = rate
+		} else {
+			slog.Warn("ignoring invalid OUTBOUND_RATE_LIMIT", "value", value)
+		}
+	}
+	t.Burst = intFromEnv("OUTBOUND_RATE_BURST", t.Burst)
+	t.FailureThreshold = intFromEnv("OUTBOUND_BREAKER_THRESHOLD", t.FailureThreshold)
+	if value := os.Getenv("OUTBOUND_BREAKER_COOLDOWN"); value != "" {
+		if cooldown, err := time.ParseDuration(value); err == nil && cooldown > 0 {
+			t.Cooldown = cooldown
+		} else {
+			slog.Warn("ignoring invalid OUTBOUND_BREAKER_COOLDOWN", "value", value)
+		}
+	}
+	return t
+}
+
+var (
+	sharedOnce      sync.Once
+	sharedTransport *Transport
+)
+
+// SharedTransport returns the transport every Jira and ServiceNow client uses,
+// so clients for the same host share its limits
+func SharedTransport() *Transport {
+	sharedOnce.Do(func() {
+		sharedTransport = NewTransportFromEnv()
+	})
+	return sharedTransport
+}
+
+// NewHTTPClient returns a client with the given timeout that sends through the
+// shared transport
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: SharedTransport(),
+	}
+}
+
+// RoundTrip sends the request once the host allows it
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := t.host(req.URL.Host)
+
+	for attempt := 0; ; attempt++ {
+		if err := t.acquire(req.Context(), req.URL.Host, host); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.Base.RoundTrip(req)
+		t.record(req.URL.Host, host, resp, err)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		// A 429 was not processed, so it is safe to send again if the body can be replayed
+		wait := retryAfter(resp, t.now())
+		if attempt >= t.MaxRetries || wait > t.MaxRetryWait || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		resp.Body.Close()
+		metrics.Retries.Inc(metrics.Integration(req.Context(), req.URL.Host))
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("error rewinding request body: %w", err)
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// State returns the breaker state of a host, "closed" for hosts not yet seen
+func (t *Transport) State(hostname string) string {
+	t.mutex.Lock()
+	host, ok := t.hosts[hostname]
+	t.mutex.Unlock()
+	if !ok {
+		return StateClosed
+	}
+
+	host.mutex.Lock()
+	defer host.mutex.Unlock()
+	return host.state
+}
+
+// host returns the state for a host, creating it on first use
+func (t *Transport) host(hostname string) *hostState {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	host, ok := t.hosts[hostname]
+	if !ok {
+		host = &hostState{tokens: float64(t.Burst), lastFill: t.now(), state: StateClosed}
+		t.hosts[hostname] = host
+	}
+	return host
+}
+
+// acquire checks the circuit and waits for Retry-After and a rate limit token
+func (t *Transport) acquire(ctx context.Context, hostname string, host *hostState) error {
+	for {
+		wait, err := t.reserve(hostname, host)
+		if err != nil || wait == 0 {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available, or returns how long to wait for one
+func (t *Transport) reserve(hostname string, host *hostState) (time.Duration, error) {
+	now := t.now()
+
+	host.mutex.Lock()
+	defer host.mutex.Unlock()
+
+	switch host.state {
+	case StateOpen:
+		if now.Sub(host.openedAt) < t.Cooldown {
+			return 0, fmt.Errorf("%s: %w", hostname, ErrCircuitOpen)
+		}
+		host.state = StateHalfOpen
+		host.probing = false
+		slog.Info("circuit half-open; sending a probe request", "host", hostname)
+		fallthrough
+	case StateHalfOpen:
+		if host.probing {
+			return 0, fmt.Errorf("%s: %w", hostname, ErrCircuitOpen)
+		}
+	}
+
+	if now.Before(host.blockedUntil) {
+		return host.blockedUntil.Sub(now), nil
+	}
+
+	host.tokens += now.Sub(host.lastFill).Seconds() * t.Rate
+	if host.tokens > float64(t.Burst) {
+		host.tokens = float64(t.Burst)
+	}
+	host.lastFill = now
+	if host.tokens < 1 {
+		return time.Duration((1 - host.tokens) / t.Rate * float64(time.Second)), nil
+	}
+	host.tokens--
+
+	if host.state == StateHalfOpen {
+		host.probing = true
+	}
+	return 0, nil
+}
+
+// record updates the breaker and Retry-After block from a response
+func (t *Transport) record(hostname string, host *hostState, resp *http.Response, err error) {
+	now := t.now()
+
+	host.mutex.Lock()
+	defer host.mutex.Unlock()
+
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait := retryAfter(resp, now); wait > 0 {
+			host.blockedUntil = now.Add(wait)
+		}
+	}
+
+	// Cancelled requests say nothing about the host
+	if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		host.probing = false
+		return
+	}
+
+	failed := err != nil || resp.StatusCode >= 500
+	if !failed {
+		if host.state != StateClosed {
+			slog.Info("circuit closed", "host", hostname)
+		}
+		host.state = StateClosed
+		host.failures = 0
+		host.probing = false
+		return
+	}
+
+	host.failures++
+	if host.state == StateHalfOpen || host.failures >= t.FailureThreshold {
+		if host.state != StateOpen {
+			slog.Warn("circuit opened", "host", hostname, "consecutive_failures", host.failures)
+		}
+		host.state = StateOpen
+		host.openedAt = now
+		host.probing = false
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// intFromEnv reads a positive integer from the environment
+func intFromEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		slog.Warn("ignoring invalid setting", "variable", key, "value", value)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,167 @@
+// backend/internal/integrations/github/client.go
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// DefaultBaseURL is the public GitHub REST API; GitHub Enterprise Server
+// instances serve it under https://<host>/api/v3
+const DefaultBaseURL = "https://api.github.com"
+
+// Client provides methods to interact with the issues of one GitHub repository
+type Client struct {
+	BaseURL string
+	Token   string
+	// Repo is the repository issues are filed in, as "owner/name"
+	Repo       string
+	HTTPClient *http.Client
+
+	// ctx bounds API calls made through a WithContext copy
+	ctx context.Context
+}
+
+// NewClient creates a new GitHub client; an empty base URL uses DefaultBaseURL
+func NewClient(baseURL, token, repo string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		Repo:       repo,
+		HTTPClient: common.NewHTTPClient(30 * time.Second),
+	}
+}
+
+// WithContext returns a copy of the client whose API calls are cancelled with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if c == nil {
+		return nil
+	}
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// Context returns the context API calls run under
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// makeRequest sends a request to an endpoint such as "repos/owner/name/issues"
+// and decodes a JSON response into out when it is not nil
+func (c *Client) makeRequest(method, endpoint string, body, out interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error marshaling request body: %w", err)
+		}
+		bodyReader = bytes.NewBuffer(jsonData)
+	}
+
+	url := fmt.Sprintf("%s/%s", c.BaseURL, endpoint)
+	req, err := http.NewRequestWithContext(metrics.WithIntegration(c.Context(), metrics.IntegrationGitHub), method, url, bodyReader)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	logging.Propagate(req)
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	metrics.RequestDuration.ObserveSince(start, metrics.IntegrationGitHub, metrics.Outcome(resp, err))
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &ErrorResponse{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(respBody, apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = string(respBody)
+		}
+		return apiErr
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("error decoding response: %w", err)
+		}
+	}
+	return nil
+}
+
+// issuesEndpoint is the issues collection of the configured repository
+func (c *Client) issuesEndpoint() string {
+	return fmt.Sprintf("repos/%s/issues", c.Repo)
+}
+
+// CreateIssue opens a new issue in the repository
+func (c *Client) CreateIssue(request *IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.makeRequest("POST", c.issuesEndpoint(), request, &issue); err != nil {
+		return nil, fmt.Errorf("error creating GitHub issue: %w", err)
+	}
+	return &issue, nil
+}
+
+// UpdateIssue changes the fields set in the request; labels replace the issue's labels
+func (c *Client) UpdateIssue(number int, request *IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.makeRequest("PATCH", fmt.Sprintf("%s/%d", c.issuesEndpoint(), number), request, &issue); err != nil {
+		return nil, fmt.Errorf("error updating GitHub issue #%d: %w", number, err)
+	}
+	return &issue, nil
+}
+
+// GetIssue retrieves an issue by number
+func (c *Client) GetIssue(number int) (*Issue, error) {
+	var issue Issue
+	if err := c.makeRequest("GET", fmt.Sprintf("%s/%d", c.issuesEndpoint(), number), nil, &issue); err != nil {
+		return nil, fmt.Errorf("error getting GitHub issue #%d: %w", number, err)
+	}
+	return &issue, nil
+}
+
+// AddComment comments on an issue
+func (c *Client) AddComment(number int, body string) error {
+	request := map[string]string{"body": body}
+	if err := c.makeRequest("POST", fmt.Sprintf("%s/%d/comments", c.issuesEndpoint(), number), request, nil); err != nil {
+		return fmt.Errorf("error commenting on GitHub issue #%d: %w", number, err)
+	}
+	return nil
+}
+
+// GetRepository checks that the token can see the configured repository
+func (c *Client) GetRepository() (*Repository, error) {
+	var repo Repository
+	if err := c.makeRequest("GET", "repos/"+c.Repo, nil, &repo); err != nil {
+		return nil, fmt.Errorf("error getting GitHub repository %s: %w", c.Repo, err)
+	}
+	return &repo, nil
+}
@@ -0,0 +1,153 @@
+// backend/internal/integrations/github/integration.go
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+)
+
+// Integration exposes GitHub issues to workflows through the common integration interfaces
+type Integration struct {
+	Client *Client
+	// Webhook receives issues and issue_comment webhooks; it is set where the webhook handler is built
+	Webhook http.Handler
+}
+
+// NewIntegration creates the GitHub integration for a client
+func NewIntegration(client *Client, webhook http.Handler) *Integration {
+	return &Integration{
+		Client:  client,
+		Webhook: webhook,
+	}
+}
+
+// Name implements common.Integration
+func (i *Integration) Name() string {
+	return "github"
+}
+
+// Triggers implements common.Trigger
+func (i *Integration) Triggers() []common.TriggerSpec {
+	return []common.TriggerSpec{
+		{ID: "issues.opened", Description: "A GitHub issue was opened"},
+		{ID: "issues.closed", Description: "A GitHub issue was closed"},
+		{ID: "issues.reopened", Description: "A closed GitHub issue was reopened"},
+		{ID: "issues.labeled", Description: "A label was added to a GitHub issue"},
+		{ID: "issue_comment.created", Description: "A comment was added to a GitHub issue"},
+	}
+}
+
+// WebhookHandler implements common.Trigger
+func (i *Integration) WebhookHandler() http.Handler {
+	return i.Webhook
+}
+
+// Actions implements common.Action
+func (i *Integration) Actions() []common.ActionSpec {
+	return []common.ActionSpec{
+		{ID: "create_issue", Description: "Open an issue; severity adds its severity label and labels is a comma-separated list", Fields: []string{"title"}},
+		{ID: "update_issue", Description: "Change an issue's title, body, state (open or closed) or labels", Fields: []string{"number"}},
+		{ID: "add_comment", Description: "Comment on an issue", Fields: []string{"number", "body"}},
+	}
+}
+
+// Execute implements common.Action
+func (i *Integration) Execute(ctx context.Context, action string, fields map[string]interface{}) (map[string]interface{}, error) {
+	client := i.Client.WithContext(ctx)
+
+	switch action {
+	case "create_issue":
+		if err := common.RequireFields(fields, "title"); err != nil {
+			return nil, err
+		}
+		request := &IssueRequest{
+			Title:  common.StringField(fields, "title"),
+			Body:   common.StringField(fields, "body"),
+			Labels: WithSeverityLabel(splitLabels(common.StringField(fields, "labels")), common.StringField(fields, "severity")),
+		}
+		issue, err := client.CreateIssue(request)
+		if err != nil {
+			return nil, err
+		}
+		return issueOutput(issue), nil
+
+	case "update_issue":
+		if err := common.RequireFields(fields, "number"); err != nil {
+			return nil, err
+		}
+		number, err := issueNumber(fields)
+		if err != nil {
+			return nil, err
+		}
+		request := &IssueRequest{
+			Title: common.StringField(fields, "title"),
+			Body:  common.StringField(fields, "body"),
+			State: common.StringField(fields, "state"),
+		}
+		if labels := common.StringField(fields, "labels"); labels != "" {
+			request.Labels = splitLabels(labels)
+		}
+		issue, err := client.UpdateIssue(number, request)
+		if err != nil {
+			return nil, err
+		}
+		return issueOutput(issue), nil
+
+	case "add_comment":
+		if err := common.RequireFields(fields, "number", "body"); err != nil {
+			return nil, err
+		}
+		number, err := issueNumber(fields)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.AddComment(number, common.StringField(fields, "body")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"number": number}, nil
+	}
+
+	return nil, fmt.Errorf("%w: github.%s", common.ErrUnknownAction, action)
+}
+
+// ValidateConnection implements common.ConnectionValidator by reading the configured repository
+func (i *Integration) ValidateConnection(ctx context.Context) error {
+	if _, err := i.Client.WithContext(ctx).GetRepository(); err != nil {
+		return fmt.Errorf("error checking GitHub token: %w", err)
+	}
+	return nil
+}
+
+// issueOutput is what later workflow steps can map from an issue
+func issueOutput(issue *Issue) map[string]interface{} {
+	return map[string]interface{}{
+		"number": issue.Number,
+		"state":  issue.State,
+		"url":    issue.HTMLURL,
+	}
+}
+
+// issueNumber reads the number field, which arrives as a JSON number or a string
+func issueNumber(fields map[string]interface{}) (int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(common.StringField(fields, "number"), "#"))
+	if err != nil {
+		return 0, fmt.Errorf("%w: number must be an issue number", common.ErrMissingField)
+	}
+	return number, nil
+}
+
+// splitLabels parses a comma-separated label list
+func splitLabels(labels string) []string {
+	var names []string
+	for _, label := range strings.Split(labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			names = append(names, label)
+		}
+	}
+	return names
+}
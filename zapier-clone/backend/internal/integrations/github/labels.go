@@ -0,0 +1,40 @@
+// backend/internal/integrations/github/labels.go
+package github
+
+import "strings"
+
+// severityLabelPrefix starts every label the integration derives from a severity
+const severityLabelPrefix = "severity:"
+
+// SeverityLabels maps ServiceNow severities, by name or number, to GitHub labels
+var SeverityLabels = map[string]string{
+	"critical": "severity:critical",
+	"1":        "severity:critical",
+	"high":     "severity:high",
+	"2":        "severity:high",
+	"medium":   "severity:medium",
+	"moderate": "severity:medium",
+	"3":        "severity:medium",
+	"low":      "severity:low",
+	"4":        "severity:low",
+}
+
+// SeverityLabel returns the label for a severity, or "" when it has none
+func SeverityLabel(severity string) string {
+	return SeverityLabels[strings.ToLower(strings.TrimSpace(severity))]
+}
+
+// WithSeverityLabel replaces any severity label in labels with the one for
+// severity, keeping the labels people added on GitHub
+func WithSeverityLabel(labels []string, severity string) []string {
+	updated := make([]string, 0, len(labels)+1)
+	for _, label := range labels {
+		if !strings.HasPrefix(label, severityLabelPrefix) {
+			updated = append(updated, label)
+		}
+	}
+	if label := SeverityLabel(severity); label != "" {
+		updated = append(updated, label)
+	}
+	return updated
+}
@@ -0,0 +1,163 @@
+// backend/internal/integrations/github/mapping.go
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+)
+
+// IssueMapping stores the links between ServiceNow records and GitHub issues
+// in a JSON file. Records are keyed "table:sys_id" since findings and
+// compliance tasks share the repository. Changes are journaled before the file
+// is rewritten, so a crash mid-save cannot corrupt it.
+type IssueMapping struct {
+	RecordToIssue map[string]int `json:"recordToIssue"`
+	IssueToRecord map[int]string `json:"issueToRecord"`
+	mutex         sync.RWMutex
+	file          *common.JournaledFile
+}
+
+// issueChange is one link change as written to the journal
+type issueChange struct {
+	Op     string `json:"op"` // add or remove
+	Record string `json:"record"`
+	Issue  int    `json:"issue,omitempty"`
+}
+
+// NewIssueMapping loads the mapping kept in storagePath, if any, replaying
+// any change a crash left in the journal
+func NewIssueMapping(storagePath string) (*IssueMapping, error) {
+	mapping := &IssueMapping{
+		RecordToIssue: make(map[string]int),
+		IssueToRecord: make(map[int]string),
+		file:          common.NewJournaledFile(filepath.Join(storagePath, "github_issue_mapping.json")),
+	}
+
+	changes, err := mapping.file.Load(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("error loading GitHub issue mapping: %w", err)
+	}
+	for _, raw := range changes {
+		var change issueChange
+		if err := json.Unmarshal(raw, &change); err != nil {
+			continue
+		}
+		switch change.Op {
+		case "add":
+			mapping.link(change.Record, change.Issue)
+		case "remove":
+			mapping.unlink(change.Record)
+		}
+	}
+	if len(changes) > 0 {
+		if err := mapping.save(); err != nil {
+			return nil, err
+		}
+	}
+
+	return mapping, nil
+}
+
+// recordKey identifies a ServiceNow record in the mapping
+func recordKey(table, sysID string) string {
+	return table + ":" + sysID
+}
+
+// AddMapping links a record to an issue, replacing any previous link of either
+func (m *IssueMapping) AddMapping(table, sysID string, number int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := recordKey(table, sysID)
+	if err := m.journal(issueChange{Op: "add", Record: key, Issue: number}); err != nil {
+		return err
+	}
+	m.link(key, number)
+	return m.save()
+}
+
+// GetIssue returns the issue number linked to a record
+func (m *IssueMapping) GetIssue(table, sysID string) (int, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	number, ok := m.RecordToIssue[recordKey(table, sysID)]
+	return number, ok
+}
+
+// GetRecord returns the table and sys_id of the record linked to an issue
+func (m *IssueMapping) GetRecord(number int) (string, string, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	key, ok := m.IssueToRecord[number]
+	if !ok {
+		return "", "", false
+	}
+	table, sysID, _ := strings.Cut(key, ":")
+	return table, sysID, true
+}
+
+// Count returns how many records are linked to an issue
+func (m *IssueMapping) Count() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.RecordToIssue)
+}
+
+// RemoveMapping drops a record's link, if any
+func (m *IssueMapping) RemoveMapping(table, sysID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := recordKey(table, sysID)
+	if _, ok := m.RecordToIssue[key]; !ok {
+		return nil
+	}
+	if err := m.journal(issueChange{Op: "remove", Record: key}); err != nil {
+		return err
+	}
+	m.unlink(key)
+	return m.save()
+}
+
+// link updates both indexes; the caller holds the write lock
+func (m *IssueMapping) link(key string, number int) {
+	if oldNumber, ok := m.RecordToIssue[key]; ok && oldNumber != number {
+		delete(m.IssueToRecord, oldNumber)
+	}
+	if oldKey, ok := m.IssueToRecord[number]; ok && oldKey != key {
+		delete(m.RecordToIssue, oldKey)
+	}
+	m.RecordToIssue[key] = number
+	m.IssueToRecord[number] = key
+}
+
+// unlink drops a record from both indexes; the caller holds the write lock
+func (m *IssueMapping) unlink(key string) {
+	if number, ok := m.RecordToIssue[key]; ok {
+		delete(m.RecordToIssue, key)
+		delete(m.IssueToRecord, number)
+	}
+}
+
+// journal records a change before it is applied
+func (m *IssueMapping) journal(change issueChange) error {
+	if m.file == nil {
+		return nil // No persistence
+	}
+	return m.file.Append(change)
+}
+
+// save persists the mapping to disk
+func (m *IssueMapping) save() error {
+	if m.file == nil {
+		return nil // No persistence
+	}
+	return m.file.Save(m)
+}
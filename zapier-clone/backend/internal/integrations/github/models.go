@@ -0,0 +1,99 @@
+// backend/internal/integrations/github/models.go
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Issue states
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+)
+
+// Issue is a GitHub issue as returned by the REST API and in webhooks
+type Issue struct {
+	Number  int     `json:"number"`
+	Title   string  `json:"title"`
+	Body    string  `json:"body"`
+	State   string  `json:"state"`
+	HTMLURL string  `json:"html_url"`
+	Labels  []Label `json:"labels"`
+	// UpdatedAt is the RFC 3339 time of the issue's last change
+	UpdatedAt string `json:"updated_at,omitempty"`
+	// StateReason is "completed" or "not_planned" for closed issues
+	StateReason string `json:"state_reason,omitempty"`
+}
+
+// LabelNames returns the names of the issue's labels
+func (i *Issue) LabelNames() []string {
+	names := make([]string, 0, len(i.Labels))
+	for _, label := range i.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+// Label is a GitHub issue label
+type Label struct {
+	Name string `json:"name"`
+}
+
+// IssueRequest creates or updates an issue. Empty fields are left unchanged
+// on update; a non-nil Labels replaces the issue's labels.
+type IssueRequest struct {
+	Title  string   `json:"title,omitempty"`
+	Body   string   `json:"body,omitempty"`
+	State  string   `json:"state,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Comment is an issue comment in a webhook
+type Comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	User User   `json:"user"`
+}
+
+// User is the account behind a webhook or comment
+type User struct {
+	Login string `json:"login"`
+	// Type is "User" or "Bot"
+	Type string `json:"type"`
+}
+
+// Repository is the repository a webhook or API response refers to
+type Repository struct {
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url"`
+}
+
+// WebhookEvent is the payload of an "issues" or "issue_comment" webhook; the
+// event name itself arrives in the X-GitHub-Event header
+type WebhookEvent struct {
+	Action     string     `json:"action"`
+	Issue      *Issue     `json:"issue,omitempty"`
+	Comment    *Comment   `json:"comment,omitempty"`
+	Label      *Label     `json:"label,omitempty"`
+	Repository Repository `json:"repository"`
+	Sender     User       `json:"sender"`
+}
+
+// ErrorResponse represents an error response from the GitHub API
+type ErrorResponse struct {
+	Message    string `json:"message"`
+	StatusCode int    `json:"-"`
+}
+
+// Error implements the error interface for ErrorResponse
+func (e *ErrorResponse) Error() string {
+	return fmt.Sprintf("GitHub API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a GitHub 404 response
+func IsNotFound(err error) bool {
+	var apiErr *ErrorResponse
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
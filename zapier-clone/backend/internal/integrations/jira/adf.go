@@ -0,0 +1,294 @@
+// backend/internal/integrations/jira/adf.go
+package jira
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ADFNode is a node of an Atlassian Document Format document, the rich text
+// format Jira Cloud's v3 API uses for descriptions and comments
+type ADFNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []*ADFNode             `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []ADFMark              `json:"marks,omitempty"`
+}
+
+// ADFMark formats a text node (strong, code, link)
+type ADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Block syntax understood by ADFFromText
+var (
+	adfFence     = regexp.MustCompile("^(?:```\\s*([\\w+-]*)|\\{code(?::([\\w+-]+))?\\})\\s*$")
+	adfHeading   = regexp.MustCompile(`^(?:(#{1,6})|h([1-6])\.)\s+(.*)$`)
+	adfRule      = regexp.MustCompile(`^-{3,}$`)
+	adfBullet    = regexp.MustCompile(`^[-*•]\s+(.*)$`)
+	adfNumbered  = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	adfTrailing  = regexp.MustCompile(`[.,;:!?]+$`)
+	adfInlineAll = regexp.MustCompile(strings.Join([]string{
+		`\[([^\]|]+)\]\((https?://[^)\s]+)\)`, // [text](url)
+		`\[([^\]|]+)\|(https?://[^\]\s]+)\]`,  // [text|url], Jira wiki
+		"`([^`]+)`",                           // `code`
+		`\{\{([^}]+)\}\}`,                     // {{code}}, Jira wiki
+		`\*\*([^*]+)\*\*`,                     // **bold**
+		`\*([^*\s][^*]*)\*`,                   // *bold*, Jira wiki
+		`(https?://[^\s<>()\[\]]+)`,           // bare URL
+	}, "|"))
+)
+
+// ADFFromText converts markdown-ish text, as found in ServiceNow descriptions
+// and in the field mapping templates, to an ADF document. It understands
+// paragraphs, headings, bullet and numbered lists, code blocks (``` or
+// {code}), horizontal rules, links, bold and inline code; anything else is
+// kept as plain text.
+func ADFFromText(text string) *ADFNode {
+	doc := &ADFNode{Type: "doc", Version: 1, Content: []*ADFNode{}}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			doc.Content = append(doc.Content, &ADFNode{Type: "paragraph", Content: adfLines(paragraph)})
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if match := adfFence.FindStringSubmatch(trimmed); match != nil {
+			flush()
+			closing := "```"
+			if strings.HasPrefix(trimmed, "{code") {
+				closing = "{code}"
+			}
+			var code []string
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != closing; i++ {
+				code = append(code, lines[i])
+			}
+			block := &ADFNode{Type: "codeBlock"}
+			if language := match[1] + match[2]; language != "" {
+				block.Attrs = map[string]interface{}{"language": language}
+			}
+			if body := strings.Join(code, "\n"); body != "" {
+				block.Content = []*ADFNode{{Type: "text", Text: body}}
+			}
+			doc.Content = append(doc.Content, block)
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+		case adfRule.MatchString(trimmed):
+			flush()
+			doc.Content = append(doc.Content, &ADFNode{Type: "rule"})
+		case adfHeading.MatchString(trimmed):
+			flush()
+			match := adfHeading.FindStringSubmatch(trimmed)
+			level := len(match[1])
+			if match[2] != "" {
+				level, _ = strconv.Atoi(match[2])
+			}
+			doc.Content = append(doc.Content, &ADFNode{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": level},
+				Content: adfInline(match[3]),
+			})
+		case adfBullet.MatchString(trimmed), adfNumbered.MatchString(trimmed):
+			flush()
+			pattern, listType := adfBullet, "bulletList"
+			if !adfBullet.MatchString(trimmed) {
+				pattern, listType = adfNumbered, "orderedList"
+			}
+			list := &ADFNode{Type: listType}
+			for ; i < len(lines); i++ {
+				match := pattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+				if match == nil {
+					break
+				}
+				list.Content = append(list.Content, &ADFNode{
+					Type:    "listItem",
+					Content: []*ADFNode{{Type: "paragraph", Content: adfInline(match[1])}},
+				})
+			}
+			i--
+			doc.Content = append(doc.Content, list)
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+
+	return doc
+}
+
+// adfLines joins a paragraph's lines with hard breaks
+func adfLines(lines []string) []*ADFNode {
+	var content []*ADFNode
+	for i, line := range lines {
+		if i > 0 {
+			content = append(content, &ADFNode{Type: "hardBreak"})
+		}
+		content = append(content, adfInline(line)...)
+	}
+	return content
+}
+
+// adfInline turns one line into text nodes with link, code and strong marks
+func adfInline(line string) []*ADFNode {
+	var nodes []*ADFNode
+	text := func(s string, marks ...ADFMark) {
+		if s != "" {
+			nodes = append(nodes, &ADFNode{Type: "text", Text: s, Marks: marks})
+		}
+	}
+	link := func(href string) ADFMark {
+		return ADFMark{Type: "link", Attrs: map[string]interface{}{"href": href}}
+	}
+
+	last := 0
+	for _, m := range adfInlineAll.FindAllStringSubmatchIndex(line, -1) {
+		text(line[last:m[0]])
+		last = m[1]
+		group := func(n int) string {
+			if m[2*n] < 0 {
+				return ""
+			}
+			return line[m[2*n]:m[2*n+1]]
+		}
+
+		switch {
+		case group(1) != "":
+			text(group(1), link(group(2)))
+		case group(3) != "":
+			text(group(3), link(group(4)))
+		case group(5) != "":
+			text(group(5), ADFMark{Type: "code"})
+		case group(6) != "":
+			text(group(6), ADFMark{Type: "code"})
+		case group(7) != "":
+			text(group(7), ADFMark{Type: "strong"})
+		case group(8) != "":
+			text(group(8), ADFMark{Type: "strong"})
+		default:
+			// Sentence punctuation after a bare URL is not part of it
+			url := group(9)
+			trailing := adfTrailing.FindString(url)
+			url = strings.TrimSuffix(url, trailing)
+			text(url, link(url))
+			text(trailing)
+		}
+	}
+	text(line[last:])
+
+	return nodes
+}
+
+// PlainText flattens a document to text: blocks are separated by blank lines,
+// list items start with "- " and formatting is dropped
+func (n *ADFNode) PlainText() string {
+	var b strings.Builder
+	n.writeText(&b)
+	return strings.TrimSpace(b.String())
+}
+
+func (n *ADFNode) writeText(b *strings.Builder) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n")
+		return
+	case "rule":
+		b.WriteString("----\n\n")
+		return
+	case "mention", "emoji":
+		if text, ok := n.Attrs["text"].(string); ok {
+			b.WriteString(text)
+		}
+		return
+	case "listItem":
+		b.WriteString("- ")
+	}
+
+	for _, child := range n.Content {
+		child.writeText(b)
+	}
+
+	switch n.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote":
+		b.WriteString("\n\n")
+	case "listItem":
+		// The item's paragraph already ended the line
+		trimmed := strings.TrimRight(b.String(), "\n")
+		b.Reset()
+		b.WriteString(trimmed + "\n")
+	case "bulletList", "orderedList":
+		b.WriteString("\n")
+	}
+}
+
+// Text is a description or comment body. Jira sends it as a string from the
+// v2 API and webhooks, and as an ADF document from the v3 API; documents are
+// flattened to plain text.
+type Text string
+
+// UnmarshalJSON accepts a string, null or an ADF document
+func (t *Text) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		var doc ADFNode
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		*t = Text(doc.PlainText())
+		return nil
+	}
+
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*t = ""
+	} else {
+		*t = Text(*s)
+	}
+	return nil
+}
+
+// TextValue reads a description or comment body from a decoded issue, in
+// either API version's format
+func TextValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		var text Text
+		if err := json.Unmarshal(data, &text); err != nil {
+			return ""
+		}
+		return string(text)
+	}
+	return ""
+}
+
+// SameText reports whether two descriptions read the same once formatting is
+// ignored, so a v3 issue's document can be compared with the text it came from
+func SameText(a, b string) bool {
+	return ADFFromText(a).PlainText() == ADFFromText(b).PlainText()
+}
@@ -0,0 +1,159 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/eventbus"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// MaxBulkIssues is the most issues Jira creates in one bulk request
+const MaxBulkIssues = 50
+
+// bulkAttempts is how often a chunk Jira pushed back on is sent again
+const bulkAttempts = 3
+
+// BulkResult is the outcome of one ticket passed to CreateIssues
+type BulkResult struct {
+	// Ticket is the created issue; nil when Err is set
+	Ticket *Ticket
+	Err    error
+}
+
+// CreateIssues creates tickets through the bulk API (POST issue/bulk),
+// chunkSize at a time, up to MaxBulkIssues. The results are in the order of
+// tickets. An issue Jira rejects fails on its own; the rest of its chunk is
+// still created.
+//
+// When Jira pushes back with 429 or 503 the chunk size is halved and the chunk
+// is sent again. The shared transport holds that request until the Retry-After
+// delay has passed. If Jira keeps pushing back, or the host's circuit is open,
+// or the client's context ends, the remaining tickets fail without being sent.
+func (c *Client) CreateIssues(tickets []*Ticket, chunkSize int) []BulkResult {
+	if chunkSize <= 0 || chunkSize > MaxBulkIssues {
+		chunkSize = MaxBulkIssues
+	}
+	results := make([]BulkResult, len(tickets))
+	logger := c.Logger()
+
+	attempts := 0
+	for start := 0; start < len(tickets); {
+		end := start + chunkSize
+		if end > len(tickets) {
+			end = len(tickets)
+		}
+
+		err := c.createChunk(tickets[start:end], results[start:end])
+		if err == nil {
+			start = end
+			attempts = 0
+			continue
+		}
+
+		if pushedBack(err) && attempts < bulkAttempts {
+			attempts++
+			if chunkSize > 1 {
+				chunkSize /= 2
+			}
+			logger.Warn("Jira pushed back on bulk create; retrying with smaller chunks", "chunk_size", chunkSize, "attempt", attempts, "error", err)
+			continue
+		}
+
+		err = fmt.Errorf("error creating Jira issues: %w", err)
+		if pushedBack(err) || errors.Is(err, common.ErrCircuitOpen) || c.Context().Err() != nil {
+			// Sending the rest now would only add to the load
+			for i := start; i < len(tickets); i++ {
+				results[i].Err = err
+			}
+			logger.Error("stopped bulk create", "created", created(results[:start]), "remaining", len(tickets)-start, "error", err)
+			return results
+		}
+		for i := start; i < end; i++ {
+			results[i].Err = err
+		}
+		start = end
+		attempts = 0
+	}
+	return results
+}
+
+// bulkResponse is Jira's answer to a bulk create. Issues lists the created
+// issues in request order; Errors names the elements that failed.
+type bulkResponse struct {
+	Issues []createdIssue `json:"issues"`
+	Errors []struct {
+		Status              int           `json:"status"`
+		FailedElementNumber int           `json:"failedElementNumber"`
+		ElementErrors       ErrorResponse `json:"elementErrors"`
+	} `json:"errors"`
+}
+
+// createChunk sends one bulk request and fills results for its tickets. It
+// returns an error only when the request as a whole failed.
+func (c *Client) createChunk(tickets []*Ticket, results []BulkResult) error {
+	updates := make([]map[string]interface{}, len(tickets))
+	for i, ticket := range tickets {
+		updates[i] = map[string]interface{}{"fields": c.issueFields(ticket)}
+	}
+
+	status, body, err := c.send("POST", "issue/bulk", map[string]interface{}{"issueUpdates": updates})
+	if err != nil {
+		return err
+	}
+
+	// Jira answers 201 when at least one issue was created and 400 when none
+	// were; both list the failures per element
+	var resp bulkResponse
+	parsed := json.Unmarshal(body, &resp) == nil && len(resp.Issues)+len(resp.Errors) > 0
+	if !parsed || (status != http.StatusCreated && status != http.StatusOK && status != http.StatusBadRequest) {
+		if status < 200 || status >= 300 {
+			return apiError(status, body)
+		}
+		return fmt.Errorf("error parsing Jira bulk response")
+	}
+
+	failed := make(map[int]error, len(resp.Errors))
+	for _, e := range resp.Errors {
+		elementErr := e.ElementErrors
+		elementErr.StatusCode = e.Status
+		failed[e.FailedElementNumber] = &elementErr
+	}
+
+	next := 0
+	for i, ticket := range tickets {
+		if err, ok := failed[i]; ok {
+			results[i].Err = fmt.Errorf("error creating Jira issue: %w", err)
+			continue
+		}
+		if next >= len(resp.Issues) {
+			results[i].Err = fmt.Errorf("Jira bulk response has no issue for element %d", i)
+			continue
+		}
+		results[i].Ticket = resp.Issues[next].ticket(ticket)
+		c.publishTicket(eventbus.TypeTicketCreated, resp.Issues[next].Key)
+		next++
+	}
+	metrics.JiraIssues.Add(float64(next), "created")
+	return nil
+}
+
+// pushedBack reports whether Jira asked for fewer requests
+func pushedBack(err error) bool {
+	var apiErr *ErrorResponse
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusServiceUnavailable)
+}
+
+// created counts the tickets that were created
+func created(results []BulkResult) int {
+	n := 0
+	for _, result := range results {
+		if result.Ticket != nil {
+			n++
+		}
+	}
+	return n
+}
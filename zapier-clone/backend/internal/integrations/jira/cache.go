@@ -0,0 +1,246 @@
+// backend/internal/integrations/jira/cache.go
+package jira
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// DefaultMetadataTTL is how long transitions, fields and projects are reused
+// before they are fetched again
+const DefaultMetadataTTL = 10 * time.Minute
+
+// Kinds of cached metadata, used in keys and metrics
+const (
+	cacheTransitions = "transitions"
+	cacheFields      = "fields"
+	cacheProjects    = "projects"
+)
+
+// sweepMetadataAt is how many entries the cache holds before stale ones are dropped
+const sweepMetadataAt = 10000
+
+// metadataEntry is a cached value and when it goes stale
+type metadataEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// metadata caches Jira metadata that rarely changes, keyed by site first so
+// tenants sharing a site share it, like the discovered custom fields. Besides
+// transitions, fields and projects it remembers the status each issue was
+// last moved to, so an issue's transitions can be taken from another issue of
+// its project in that status.
+var metadata = struct {
+	sync.Mutex
+	entries map[string]metadataEntry
+}{entries: make(map[string]metadataEntry)}
+
+// MetadataCacheStats counts a site's cached entries by kind
+type MetadataCacheStats struct {
+	Site    string         `json:"site"`
+	TTL     string         `json:"ttl"`
+	Entries map[string]int `json:"entries"`
+}
+
+// metadataTTLFromEnv reads JIRA_METADATA_CACHE_TTL, a duration such as "5m";
+// "0" turns the cache off
+func metadataTTLFromEnv() time.Duration {
+	value := os.Getenv("JIRA_METADATA_CACHE_TTL")
+	if value == "" {
+		return DefaultMetadataTTL
+	}
+	if value == "0" {
+		return 0
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return DefaultMetadataTTL
+	}
+	return ttl
+}
+
+// metadataKey is the cache key of a value of kind on the client's site
+func (c *Client) metadataKey(kind string, parts ...string) string {
+	return siteURL(c.BaseURL) + " " + kind + " " + strings.ToUpper(strings.Join(parts, " "))
+}
+
+// lookup returns a value that has not gone stale, counting the hit or miss
+func (c *Client) lookup(kind string, parts ...string) (interface{}, bool) {
+	value, ok := c.cached(kind, parts...)
+	c.countLookup(kind, ok)
+	return value, ok
+}
+
+// countLookup counts a cache hit or miss while the cache is on
+func (c *Client) countLookup(kind string, hit bool) {
+	if c.MetadataTTL <= 0 {
+		return
+	}
+	if hit {
+		metrics.JiraMetadataCache.Inc(kind, "hit")
+	} else {
+		metrics.JiraMetadataCache.Inc(kind, "miss")
+	}
+}
+
+// cached returns a value that has not gone stale
+func (c *Client) cached(kind string, parts ...string) (interface{}, bool) {
+	if c.MetadataTTL <= 0 {
+		return nil, false
+	}
+	key := c.metadataKey(kind, parts...)
+	metadata.Lock()
+	entry, ok := metadata.entries[key]
+	if ok && time.Now().After(entry.expires) {
+		delete(metadata.entries, key)
+		ok = false
+	}
+	metadata.Unlock()
+	return entry.value, ok
+}
+
+// cache keeps a value for the client's TTL
+func (c *Client) cache(value interface{}, kind string, parts ...string) {
+	if c.MetadataTTL <= 0 {
+		return
+	}
+	now := time.Now()
+	metadata.Lock()
+	defer metadata.Unlock()
+	if len(metadata.entries) >= sweepMetadataAt {
+		for key, entry := range metadata.entries {
+			if now.After(entry.expires) {
+				delete(metadata.entries, key)
+			}
+		}
+	}
+	metadata.entries[c.metadataKey(kind, parts...)] = metadataEntry{value: value, expires: now.Add(c.MetadataTTL)}
+}
+
+// uncache drops a cached value
+func (c *Client) uncache(kind string, parts ...string) {
+	metadata.Lock()
+	defer metadata.Unlock()
+	delete(metadata.entries, c.metadataKey(kind, parts...))
+}
+
+// issueProject is the project key an issue key starts with
+func issueProject(issueKey string) string {
+	project, _, _ := strings.Cut(issueKey, "-")
+	return project
+}
+
+// issueStatus returns the status an issue was last moved to, when it is known
+func (c *Client) issueStatus(issueKey string) (string, bool) {
+	metadata.Lock()
+	defer metadata.Unlock()
+	entry, ok := metadata.entries[c.metadataKey(cacheTransitions, "moved", issueKey)]
+	if !ok || time.Now().After(entry.expires) {
+		return "", false
+	}
+	return entry.value.(string), true
+}
+
+// cachedTransitions returns the transitions available to an issue: its own
+// when they were listed since it last moved, or those of its project's issues
+// in the status it was moved to
+func (c *Client) cachedTransitions(issueKey string) ([]Transition, bool) {
+	transitions, ok := c.cached(cacheTransitions, "issue", issueKey)
+	if !ok {
+		if status, known := c.issueStatus(issueKey); known {
+			transitions, ok = c.cached(cacheTransitions, "status", issueProject(issueKey), status)
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	return transitions.([]Transition), true
+}
+
+// cacheTransitions keeps the transitions listed for an issue, and for its
+// project's status when the issue's status is known
+func (c *Client) cacheTransitions(issueKey string, transitions []Transition) {
+	c.cache(transitions, cacheTransitions, "issue", issueKey)
+	if status, ok := c.issueStatus(issueKey); ok {
+		c.cache(transitions, cacheTransitions, "status", issueProject(issueKey), status)
+	}
+}
+
+// issueMoved forgets an issue's transitions after it changed status. status
+// is where it went, or "" when that is not known.
+func (c *Client) issueMoved(issueKey, status string) {
+	c.uncache(cacheTransitions, "issue", issueKey)
+	if status == "" {
+		c.uncache(cacheTransitions, "moved", issueKey)
+		return
+	}
+	c.cache(status, cacheTransitions, "moved", issueKey)
+}
+
+// IssueStatusChanged forgets an issue's cached transitions after it was
+// moved to status outside the client, such as in the Jira UI
+func (c *Client) IssueStatusChanged(issueKey, status string) {
+	c.issueMoved(issueKey, status)
+}
+
+// transitionFailed drops the transitions that offered a transition Jira
+// refused, in case the workflow changed or the issue's type has another one
+func (c *Client) transitionFailed(issueKey string) {
+	if status, ok := c.issueStatus(issueKey); ok {
+		c.uncache(cacheTransitions, "status", issueProject(issueKey), status)
+	}
+	c.issueMoved(issueKey, "")
+}
+
+// InvalidateMetadata drops everything cached for the client's site:
+// transitions, fields, projects and components. Call it after changing a
+// workflow, field or project in Jira.
+func (c *Client) InvalidateMetadata() {
+	site := siteURL(c.BaseURL)
+	metadata.Lock()
+	for key := range metadata.entries {
+		if strings.HasPrefix(key, site+" ") {
+			delete(metadata.entries, key)
+		}
+	}
+	metadata.Unlock()
+
+	projectComponents.Lock()
+	for key := range projectComponents.names {
+		if strings.HasPrefix(key, site+" ") {
+			delete(projectComponents.names, key)
+		}
+	}
+	projectComponents.Unlock()
+}
+
+// MetadataCacheStats counts the transitions, fields and projects cached for
+// the client's site that have not gone stale
+func (c *Client) MetadataCacheStats() MetadataCacheStats {
+	site := siteURL(c.BaseURL)
+	stats := MetadataCacheStats{Site: site, TTL: c.MetadataTTL.String(), Entries: map[string]int{
+		cacheTransitions: 0,
+		cacheFields:      0,
+		cacheProjects:    0,
+	}}
+	now := time.Now()
+	metadata.Lock()
+	defer metadata.Unlock()
+	for key, entry := range metadata.entries {
+		rest, ok := strings.CutPrefix(key, site+" ")
+		if !ok || now.After(entry.expires) {
+			continue
+		}
+		kind, rest, _ := strings.Cut(rest, " ")
+		if strings.HasPrefix(rest, "MOVED ") {
+			continue
+		}
+		stats.Entries[kind]++
+	}
+	return stats
+}
@@ -0,0 +1,148 @@
+// backend/internal/integrations/jira/checklist.go
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ChecklistFieldName is the name of the multi-line text field that holds an
+// issue's checklist
+const ChecklistFieldName = "Checklist"
+
+// Custom field type and searcher for multi-line text fields
+const (
+	textAreaFieldType     = "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+	textAreaFieldSearcher = "com.atlassian.jira.plugin.system.customfieldtypes:textsearcher"
+)
+
+// checklistFields caches the discovered Checklist field per Jira site
+var checklistFields = struct {
+	sync.RWMutex
+	ids map[string]string
+}{ids: make(map[string]string)}
+
+// ChecklistItem is one line of a checklist
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// CreateTextAreaField creates a multi-line text custom field
+func (c *Client) CreateTextAreaField(name, description string) (*Field, error) {
+	return c.createField(name, description, textAreaFieldType, textAreaFieldSearcher)
+}
+
+// DiscoverChecklistField finds the site's "Checklist" field, creating it when
+// create is set and it is missing. It returns "" when there is none.
+func (c *Client) DiscoverChecklistField(create bool) (string, error) {
+	site := siteURL(c.BaseURL)
+	checklistFields.RLock()
+	id, ok := checklistFields.ids[site]
+	checklistFields.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	id, err := c.findCustomField(ChecklistFieldName)
+	if err != nil {
+		return "", err
+	}
+	if id == "" && !create {
+		return "", nil
+	}
+	if id == "" {
+		field, err := c.CreateTextAreaField(ChecklistFieldName, "Checklist kept in sync by the GRC integration, one \"- [x] item\" per line")
+		if err != nil {
+			return "", err
+		}
+		id = field.ID
+	}
+
+	checklistFields.Lock()
+	checklistFields.ids[site] = id
+	checklistFields.Unlock()
+	return id, nil
+}
+
+// ChecklistField returns the ID of the site's Checklist field, or "" before
+// discovery or when the site has none
+func (c *Client) ChecklistField() string {
+	checklistFields.RLock()
+	defer checklistFields.RUnlock()
+	return checklistFields.ids[siteURL(c.BaseURL)]
+}
+
+// UpdateChecklist ticks or adds items on an issue's checklist, matching
+// existing lines by text; other lines are kept. Without a Checklist field the
+// items are posted as a comment instead.
+func (c *Client) UpdateChecklist(issueKey string, items []ChecklistItem) error {
+	field := c.ChecklistField()
+	if field == "" {
+		return c.AddComment(issueKey, FormatChecklist(items))
+	}
+
+	issue, err := c.GetIssue(issueKey)
+	if err != nil {
+		return err
+	}
+	fields, _ := issue["fields"].(map[string]interface{})
+	checklist := MergeChecklist(ParseChecklist(TextValue(fields[field])), items)
+
+	update := &TicketUpdate{Fields: map[string]interface{}{field: c.richText(FormatChecklist(checklist))}}
+	if err := c.UpdateIssue(issueKey, update); err != nil {
+		return fmt.Errorf("error updating checklist of %s: %w", issueKey, err)
+	}
+	return nil
+}
+
+// ParseChecklist reads "- [ ] item" and "- [x] item" lines; other lines are
+// ignored
+func ParseChecklist(text string) []ChecklistItem {
+	var items []ChecklistItem
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "-*"))
+		switch {
+		case strings.HasPrefix(line, "[ ]"):
+			items = append(items, ChecklistItem{Text: strings.TrimSpace(line[3:])})
+		case strings.HasPrefix(strings.ToLower(line), "[x]"):
+			items = append(items, ChecklistItem{Text: strings.TrimSpace(line[3:]), Done: true})
+		}
+	}
+	return items
+}
+
+// FormatChecklist writes items as "- [x] item" lines
+func FormatChecklist(items []ChecklistItem) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		mark := " "
+		if item.Done {
+			mark = "x"
+		}
+		lines = append(lines, fmt.Sprintf("- [%s] %s", mark, item.Text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MergeChecklist applies updates to a checklist: items with the same text
+// (ignoring case) take the update's state, and new items are appended
+func MergeChecklist(checklist, updates []ChecklistItem) []ChecklistItem {
+	merged := append([]ChecklistItem(nil), checklist...)
+	for _, update := range updates {
+		found := false
+		for i := range merged {
+			if strings.EqualFold(merged[i].Text, update.Text) {
+				merged[i].Done = update.Done
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, update)
+		}
+	}
+	return merged
+}
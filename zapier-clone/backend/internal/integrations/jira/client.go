@@ -2,21 +2,55 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"os"
 	"strings"
 	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/eventbus"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// Jira REST API versions. Version 3 (Jira Cloud) takes descriptions and
+// comments as ADF documents; version 2 takes plain text.
+const (
+	APIVersion2 = "2"
+	APIVersion3 = "3"
 )
 
 // Client provides methods to interact with the Jira API
 type Client struct {
-	BaseURL    string
-	Email      string
-	APIToken   string
+	BaseURL string
+	Email   string
+	// APIToken is replaced in place when the secret is rotated
+	APIToken   *common.Secret
 	HTTPClient *http.Client
 	ProjectKey string
+	// APIVersion selects the REST API the client talks to, APIVersion2 or APIVersion3
+	APIVersion string
+	// Events receives a ticket.* event for every issue write; nil publishes nothing
+	Events *eventbus.Bus
+	// CreateComponents creates the components a new issue names that its
+	// project lacks; otherwise they are left off the issue
+	CreateComponents bool
+	// StatusSynonyms lists other names of statuses issues are moved to, so a
+	// workflow's "Closed" is reached when "Done" is asked for
+	StatusSynonyms map[string][]string
+	// MaxTransitionHops limits the transitions made to reach one status
+	MaxTransitionHops int
+	// MetadataTTL is how long transitions, fields and projects are cached; 0 turns the cache off
+	MetadataTTL time.Duration
+
+	// ctx bounds API calls made through a WithContext copy
+	ctx context.Context
 }
 
 // NewClient creates a new Jira client
@@ -24,69 +58,231 @@ func NewClient(baseURL, email, apiToken, projectKey string) *Client {
 	return &Client{
 		BaseURL:    baseURL,
 		Email:      email,
-		APIToken:   apiToken,
-		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		APIToken:   common.NewSecret(apiToken),
+		HTTPClient: common.NewHTTPClient(30 * time.Second),
 		ProjectKey: projectKey,
+		APIVersion: APIVersion2,
+		// Set JIRA_CREATE_COMPONENTS=false when the account may not administer projects
+		CreateComponents:  os.Getenv("JIRA_CREATE_COMPONENTS") != "false",
+		StatusSynonyms:    statusSynonymsFromEnv(),
+		MaxTransitionHops: maxTransitionHopsFromEnv(),
+		MetadataTTL:       metadataTTLFromEnv(),
+	}
+}
+
+// WithContext returns a copy of the client whose API calls are cancelled with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// WithAPIVersion returns a copy of the client that talks to the given REST API
+// version; an empty version keeps the current one
+func (c *Client) WithAPIVersion(version string) *Client {
+	copied := *c
+	if version != "" {
+		copied.APIVersion = version
 	}
+	return &copied
+}
+
+// Context returns the context API calls run under
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// Logger returns the logger for work done with this client, tagged with the
+// correlation ID of its context
+func (c *Client) Logger() *slog.Logger {
+	return logging.FromContext(c.Context())
 }
 
 // To handle HTTP requests
 func (c *Client) makeRequest(method, endpoint string, body interface{}) ([]byte, error) {
+	status, respBody, err := c.send(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if status < 200 || status >= 300 {
+		return nil, apiError(status, respBody)
+	}
+
+	if operation := issueOperation(method, endpoint); operation != "" {
+		metrics.JiraIssues.Inc(operation)
+		c.publishWrite(operation, endpoint, respBody)
+	}
+	return respBody, nil
+}
+
+// send makes a request and returns the status and body of whatever Jira answered
+func (c *Client) send(method, endpoint string, body interface{}) (int, []byte, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
-			return nil, fmt.Errorf("error marshaling request body: %w", err)
+			return 0, nil, fmt.Errorf("error marshaling request body: %w", err)
 		}
 		bodyReader = bytes.NewBuffer(jsonData)
 	}
 
-	url := fmt.Sprintf("%s/%s", c.BaseURL, endpoint)
-	req, err := http.NewRequest(method, url, bodyReader)
+	url := c.apiURL(endpoint)
+	req, err := http.NewRequestWithContext(metrics.WithIntegration(c.Context(), metrics.IntegrationJira), method, url, bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return 0, nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.Email, c.APIToken)
+	req.SetBasicAuth(c.Email, c.APIToken.Get())
+	logging.Propagate(req)
 
+	start := time.Now()
 	resp, err := c.HTTPClient.Do(req)
+	metrics.RequestDuration.ObserveSince(start, metrics.IntegrationJira, metrics.Outcome(resp, err))
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return 0, nil, fmt.Errorf("error making request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return 0, nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+// apiError decodes a Jira error response
+func apiError(status int, body []byte) error {
+	var errorResp ErrorResponse
+	if err := json.Unmarshal(body, &errorResp); err != nil {
+		return &ErrorResponse{ErrorMessages: []string{string(body)}, StatusCode: status}
 	}
+	errorResp.StatusCode = status
+	return &errorResp
+}
+
+// issueOperation names the issue write a request makes, for metrics
+func issueOperation(method, endpoint string) string {
+	switch {
+	case method == "POST" && endpoint == "issue":
+		return "created"
+	case method == "PUT" && strings.HasPrefix(endpoint, "issue/"):
+		return "updated"
+	case method == "POST" && strings.HasSuffix(endpoint, "/transitions"):
+		return "transitioned"
+	case method == "POST" && strings.HasSuffix(endpoint, "/comment"):
+		return "commented"
+	}
+	return ""
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var errorResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errorResp); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+// ticketTypes are the bus event types of issueOperation's operations
+var ticketTypes = map[string]string{
+	"created":      eventbus.TypeTicketCreated,
+	"updated":      eventbus.TypeTicketUpdated,
+	"transitioned": eventbus.TypeTicketTransitioned,
+	"commented":    eventbus.TypeTicketCommented,
+}
+
+// publishWrite puts an issue write on the event bus. The key of a created
+// issue is read from Jira's response; other writes name it in the endpoint.
+func (c *Client) publishWrite(operation, endpoint string, respBody []byte) {
+	if c.Events == nil {
+		return
+	}
+	key := strings.SplitN(strings.TrimPrefix(endpoint, "issue/"), "/", 2)[0]
+	if operation == "created" {
+		var created struct {
+			Key string `json:"key"`
 		}
-		errorResp.StatusCode = resp.StatusCode
-		return nil, &errorResp
+		json.Unmarshal(respBody, &created)
+		key = created.Key
 	}
+	c.publishTicket(ticketTypes[operation], key)
+}
 
-	return respBody, nil
+// publishTicket puts one ticket event on the event bus; without a bus it does nothing
+func (c *Client) publishTicket(eventType, key string) {
+	c.Events.Publish(eventbus.Message{
+		Type:    eventType,
+		Source:  eventbus.SourceJira,
+		Subject: key,
+		Data: map[string]string{
+			"key":     key,
+			"project": strings.SplitN(key, "-", 2)[0],
+		},
+	})
+}
+
+// apiURL resolves an endpoint such as "issue/KEY" against the configured API
+// version. A base URL that already names a REST API path is used with its
+// version swapped, so older configurations pointing at .../rest/api/2 work.
+func (c *Client) apiURL(endpoint string) string {
+	version := c.APIVersion
+	if version == "" {
+		version = APIVersion2
+	}
+
+	base := strings.TrimRight(c.BaseURL, "/")
+	if i := strings.Index(base, "/rest/api/"); i >= 0 {
+		base = base[:i]
+	}
+	return fmt.Sprintf("%s/rest/api/%s/%s", base, version, endpoint)
+}
+
+// richText encodes a description or comment body for the configured API version
+func (c *Client) richText(text string) interface{} {
+	if c.APIVersion == APIVersion3 {
+		return ADFFromText(text)
+	}
+	return text
 }
 
 // CreateIssue creates a new issue in Jira
 func (c *Client) CreateIssue(ticket *Ticket) (*Ticket, error) {
-	// Convert ticket to API format
+	requestBody := map[string]interface{}{
+		"fields": c.issueFields(ticket),
+	}
+	// Make the API request
+	resp, err := c.makeRequest("POST", "issue", requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Jira issue: %w", err)
+	}
+
+	// Parse the response
+	var result createdIssue
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("error parsing Jira response: %w", err)
+	}
+
+	return result.ticket(ticket), nil
+}
+
+// issueFields converts a ticket to the fields of a create request
+func (c *Client) issueFields(ticket *Ticket) map[string]interface{} {
 	fields := map[string]interface{}{
 		"project":     map[string]string{"key": ticket.Project},
 		"issuetype":   map[string]string{"name": ticket.IssueType},
 		"summary":     ticket.Summary,
-		"description": ticket.Description,
+		"description": c.richText(ticket.Description),
 	}
 
 	if ticket.Priority != "" {
 		fields["priority"] = map[string]string{"name": ticket.Priority}
 	}
 
+	if ticket.AssigneeID != "" {
+		fields["assignee"] = map[string]string{"accountId": ticket.AssigneeID}
+	}
+
+	if ticket.Parent != "" {
+		fields["parent"] = map[string]string{"key": ticket.Parent}
+	}
+
 	if !ticket.DueDate.IsZero() {
 		fields["duedate"] = ticket.DueDate.Format("2006-01-02")
 	}
@@ -106,58 +302,45 @@ func (c *Client) CreateIssue(ticket *Ticket) (*Ticket, error) {
 		// fields["customfield_10010"] = ticket.Epic.Color
 	}
 
-	// Add components if any are specified
-	if len(ticket.Components) > 0 {
-		components := make([]map[string]string, len(ticket.Components))
-		for i, component := range ticket.Components {
+	// Add components if any are specified, creating those the project lacks
+	if names := c.issueComponents(ticket.Project, ticket.Components); len(names) > 0 {
+		components := make([]map[string]string, len(names))
+		for i, component := range names {
 			components[i] = map[string]string{"name": component}
 		}
 		fields["components"] = components
 	}
 
 	// Add any custom fields
-	if len(ticket.Fields) > 0 {
-		for key, value := range ticket.Fields {
-			fields[key] = value
-		}
+	for key, value := range ticket.Fields {
+		fields[key] = value
 	}
 
-	requestBody := map[string]interface{}{
-		"fields": fields,
-	}
-	// Make the API request
-	resp, err := c.makeRequest("POST", "issue", requestBody)
-	if err != nil {
-		return nil, fmt.Errorf("error creating Jira issue: %w", err)
-	}
-
-	// Parse the response
-	var result struct {
-		ID   string `json:"id"`
-		Key  string `json:"key"`
-		Self string `json:"self"`
-	}
+	return fields
+}
 
-	if err := json.Unmarshal(resp, &result); err != nil {
-		return nil, fmt.Errorf("error parsing Jira response: %w", err)
-	}
+// createdIssue is Jira's answer to a create request
+type createdIssue struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Self string `json:"self"`
+}
 
-	// Return the created ticket
-	createdTicket := &Ticket{
-		ID:          result.ID,
-		Key:         result.Key,
-		Self:        result.Self,
-		Project:     ticket.Project,
-		IssueType:   ticket.IssueType,
-		Summary:     ticket.Summary,
-		Description: ticket.Description,
-		Priority:    ticket.Priority,
-		DueDate:     ticket.DueDate,
-		Labels:      ticket.Labels,
-		Fields:      ticket.Fields,
+// ticket returns the created ticket: the one requested plus its ID and key
+func (i createdIssue) ticket(requested *Ticket) *Ticket {
+	return &Ticket{
+		ID:          i.ID,
+		Key:         i.Key,
+		Self:        i.Self,
+		Project:     requested.Project,
+		IssueType:   requested.IssueType,
+		Summary:     requested.Summary,
+		Description: requested.Description,
+		Priority:    requested.Priority,
+		DueDate:     requested.DueDate,
+		Labels:      requested.Labels,
+		Fields:      requested.Fields,
 	}
-
-	return createdTicket, nil
 }
 
 // CreateSubtask creates a subtask for an existing issue
@@ -174,7 +357,7 @@ func (c *Client) CreateSubtask(parentKey, summary, description string) (string,
 				"name": "Sub-task",
 			},
 			"summary":     summary,
-			"description": description,
+			"description": c.richText(description),
 		},
 	}
 
@@ -196,48 +379,23 @@ func (c *Client) CreateSubtask(parentKey, summary, description string) (string,
 	return issueKey, nil
 }
 
-// UpdateIssue updates an existing Jira issue with new values
+// UpdateIssue updates an existing Jira issue with new values. When no
+// transition leads to the new status, the other values are still updated and
+// the error wraps ErrNoTransition.
 func (c *Client) UpdateIssue(issueKey string, update *TicketUpdate) error {
 	// Build the update request based on the update object
 	updateRequest := UpdateTicketRequest{
 		Fields: make(map[string]interface{}),
 	}
+	var statusErr error
 
 	// Handle status change through transitions
 	if update.Status != "" {
-		// First get available transitions
-		transitions, err := c.getTransitions(issueKey)
-		if err != nil {
-			return fmt.Errorf("error getting available transitions: %w", err)
-		}
-
-		// Find the transition ID for the target status
-		var transitionID string
-		for _, t := range transitions {
-			if strings.EqualFold(t.To.Name, update.Status) {
-				transitionID = t.ID
-				break
-			}
-		}
-
-		if transitionID == "" {
-			return fmt.Errorf("no transition found for status %s", update.Status)
-		}
-
-		// Make the transition request
-		transitionReq := TransitionRequest{}
-		transitionReq.Transition.ID = transitionID
-
-		if update.Resolution != "" {
-			if transitionReq.Fields == nil {
-				transitionReq.Fields = make(map[string]interface{})
-			}
-			transitionReq.Fields["resolution"] = map[string]string{"name": update.Resolution}
-		}
-
-		_, err = c.makeRequest("POST", fmt.Sprintf("issue/%s/transitions", issueKey), transitionReq)
-		if err != nil {
-			return fmt.Errorf("error transitioning issue: %w", err)
+		// A workflow without a way to the status still gets the other changes
+		if _, err := c.TransitionTo(issueKey, update.Status, update.Resolution); errors.Is(err, ErrNoTransition) {
+			statusErr = err
+		} else if err != nil {
+			return err
 		}
 	}
 
@@ -247,7 +405,7 @@ func (c *Client) UpdateIssue(issueKey string, update *TicketUpdate) error {
 	}
 
 	if update.Description != "" {
-		updateRequest.Fields["description"] = update.Description
+		updateRequest.Fields["description"] = c.richText(update.Description)
 	}
 
 	if update.Priority != "" {
@@ -258,7 +416,9 @@ func (c *Client) UpdateIssue(issueKey string, update *TicketUpdate) error {
 		updateRequest.Fields["duedate"] = update.DueDate
 	}
 
-	if update.Assignee != "" {
+	if update.AssigneeID != "" {
+		updateRequest.Fields["assignee"] = map[string]string{"accountId": update.AssigneeID}
+	} else if update.Assignee != "" {
 		updateRequest.Fields["assignee"] = map[string]string{"name": update.Assignee}
 	}
 
@@ -280,7 +440,7 @@ func (c *Client) UpdateIssue(issueKey string, update *TicketUpdate) error {
 	// Add comment if provided
 	if update.Comment != "" {
 		commentReq := CommentRequest{
-			Body: update.Comment,
+			Body: c.richText(update.Comment),
 		}
 
 		_, err := c.makeRequest("POST", fmt.Sprintf("issue/%s/comment", issueKey), commentReq)
@@ -289,11 +449,12 @@ func (c *Client) UpdateIssue(issueKey string, update *TicketUpdate) error {
 		}
 	}
 
-	return nil
+	return statusErr
 }
 
-// Helper method to get available transitions for an issue
-func (c *Client) getTransitions(issueKey string) ([]Transition, error) {
+// Helper method to list the available transitions of an issue from Jira. They
+// are cached until the issue moves, and shared by the project's issues in the same status.
+func (c *Client) fetchTransitions(issueKey string) ([]Transition, error) {
 	resp, err := c.makeRequest("GET", fmt.Sprintf("issue/%s/transitions", issueKey), nil)
 	if err != nil {
 		return nil, err
@@ -307,13 +468,14 @@ func (c *Client) getTransitions(issueKey string) ([]Transition, error) {
 		return nil, err
 	}
 
+	c.cacheTransitions(issueKey, result.Transitions)
 	return result.Transitions, nil
 }
 
 // AddComment adds a comment to an issue
 func (c *Client) AddComment(issueKey, comment string) error {
 	data := map[string]interface{}{
-		"body": comment,
+		"body": c.richText(comment),
 	}
 
 	_, err := c.makeRequest("POST", fmt.Sprintf("issue/%s/comment", issueKey), data)
@@ -324,6 +486,51 @@ func (c *Client) AddComment(issueKey, comment string) error {
 	return nil
 }
 
+// GetComments lists an issue's comments, oldest first, with their bodies as
+// plain text
+func (c *Client) GetComments(issueKey string) ([]Comment, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("issue/%s/comment", issueKey), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting Jira comments: %w", err)
+	}
+
+	var result struct {
+		Comments []struct {
+			ID      string      `json:"id"`
+			Body    Text        `json:"body"`
+			Author  interface{} `json:"author"`
+			Created string      `json:"created"`
+			Updated string      `json:"updated"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshaling comments: %w", err)
+	}
+
+	comments := make([]Comment, 0, len(result.Comments))
+	for _, raw := range result.Comments {
+		comment := Comment{ID: raw.ID, Body: string(raw.Body), Created: jiraTime(raw.Created), Updated: jiraTime(raw.Updated)}
+		switch author := raw.Author.(type) {
+		case string:
+			comment.Author = author
+		case map[string]interface{}:
+			comment.Author, _ = author["displayName"].(string)
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// jiraTime reads a timestamp as Jira formats it, or as RFC 3339
+func jiraTime(value string) time.Time {
+	for _, layout := range []string{"2006-01-02T15:04:05.000-0700", time.RFC3339} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
 // GetIssue gets issue details by key
 func (c *Client) GetIssue(issueKey string) (map[string]interface{}, error) {
 	resp, err := c.makeRequest("GET", fmt.Sprintf("issue/%s", issueKey), nil)
@@ -352,5 +559,15 @@ func (c *Client) TransitionIssue(issueKey string, transitionID string) error {
 		return fmt.Errorf("error transitioning Jira issue: %w", err)
 	}
 
+	// Where the transition leads is only known when it was listed
+	status := ""
+	if transitions, ok := c.cachedTransitions(issueKey); ok {
+		for _, t := range transitions {
+			if t.ID == transitionID {
+				status = t.To.Name
+			}
+		}
+	}
+	c.issueMoved(issueKey, status)
 	return nil
 }
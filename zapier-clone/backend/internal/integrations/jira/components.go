@@ -0,0 +1,104 @@
+// backend/internal/integrations/jira/components.go
+package jira
+
+import (
+	"strings"
+	"sync"
+)
+
+// projectComponents caches the component names of each project, keyed by
+// site and project key, and by lowercased name within a project. Jira rejects
+// an issue that names a component its project lacks.
+var projectComponents = struct {
+	sync.Mutex
+	names map[string]map[string]string
+}{names: make(map[string]map[string]string)}
+
+func componentsKey(site, project string) string {
+	return site + " " + strings.ToUpper(project)
+}
+
+// rememberComponent adds a component to the cache of a project that has
+// already been listed
+func (c *Client) rememberComponent(project, name string) {
+	projectComponents.Lock()
+	defer projectComponents.Unlock()
+	if names, ok := projectComponents.names[componentsKey(siteURL(c.BaseURL), project)]; ok {
+		names[strings.ToLower(name)] = name
+	}
+}
+
+// knownComponents returns a project's components by lowercased name, listing
+// them the first time or when refresh is set
+func (c *Client) knownComponents(project string, refresh bool) (map[string]string, error) {
+	key := componentsKey(siteURL(c.BaseURL), project)
+	projectComponents.Lock()
+	names, ok := projectComponents.names[key]
+	projectComponents.Unlock()
+	if ok && !refresh {
+		return names, nil
+	}
+
+	components, err := c.GetComponents(project)
+	if err != nil {
+		return nil, err
+	}
+	names = make(map[string]string, len(components))
+	for _, component := range components {
+		names[strings.ToLower(component.Name)] = component.Name
+	}
+	projectComponents.Lock()
+	projectComponents.names[key] = names
+	projectComponents.Unlock()
+	return names, nil
+}
+
+// issueComponents returns the components a new issue in project is created
+// with: each of names as the project spells it, after creating the missing
+// ones when CreateComponents is set. Components that are missing and could not
+// be created are left off, with a log line, so the issue is still created.
+func (c *Client) issueComponents(project string, names []string) []string {
+	if len(names) == 0 || project == "" {
+		return names
+	}
+	known, err := c.knownComponents(project, false)
+	if err != nil {
+		c.Logger().Warn("error listing components of Jira project; sending them unchecked", "project", project, "error", err)
+		return names
+	}
+
+	components := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		lower := strings.ToLower(name)
+		if name == "" || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+
+		if existing, ok := known[lower]; ok {
+			components = append(components, existing)
+			continue
+		}
+		if !c.CreateComponents {
+			c.Logger().Warn("leaving component off a new issue: the Jira project has no such component", "component", name, "project", project)
+			continue
+		}
+		if _, err := c.CreateComponent(project, name, "Created for ServiceNow records in this category"); err != nil {
+			// Another issue may have created it in the meantime
+			refreshed, refreshErr := c.knownComponents(project, true)
+			if refreshErr == nil {
+				if existing, ok := refreshed[lower]; ok {
+					known = refreshed
+					components = append(components, existing)
+					continue
+				}
+			}
+			c.Logger().Warn("leaving component off a new issue", "component", name, "project", project, "error", err)
+			continue
+		}
+		components = append(components, name)
+	}
+	return components
+}
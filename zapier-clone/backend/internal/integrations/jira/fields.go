@@ -0,0 +1,230 @@
+// backend/internal/integrations/jira/fields.go
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ServiceNowIDFieldName is the name of the custom field that holds the sys_id of
+// the ServiceNow record an issue was created from
+const ServiceNowIDFieldName = "ServiceNow ID"
+
+// DefaultServiceNowIDField is used for a site whose ServiceNow ID field has not
+// been discovered, such as when discovery failed at startup
+const DefaultServiceNowIDField = "customfield_servicenow_id"
+
+// Field is a system or custom field, as listed by /rest/api/2/field
+type Field struct {
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Custom bool         `json:"custom"`
+	Schema *FieldSchema `json:"schema,omitempty"`
+}
+
+// FieldSchema describes the values a field holds
+type FieldSchema struct {
+	Type   string `json:"type"`
+	Custom string `json:"custom,omitempty"`
+}
+
+// RiskScoreFieldName is the name of the number field that holds a risk's
+// likelihood × impact score
+const RiskScoreFieldName = "Risk Matrix Score"
+
+// Custom field types and searchers for single-line text and number fields
+const (
+	textFieldType       = "com.atlassian.jira.plugin.system.customfieldtypes:textfield"
+	textFieldSearcher   = "com.atlassian.jira.plugin.system.customfieldtypes:textsearcher"
+	numberFieldType     = "com.atlassian.jira.plugin.system.customfieldtypes:float"
+	numberFieldSearcher = "com.atlassian.jira.plugin.system.customfieldtypes:exactnumber"
+)
+
+// serviceNowIDFields caches the discovered ServiceNow ID field per Jira site.
+// Custom field IDs differ between sites, and tenants sharing a site share one.
+var serviceNowIDFields = struct {
+	sync.RWMutex
+	ids map[string]string
+}{ids: make(map[string]string)}
+
+// riskScoreFields caches the discovered Risk Matrix Score field per Jira site
+var riskScoreFields = struct {
+	sync.RWMutex
+	ids map[string]string
+}{ids: make(map[string]string)}
+
+// siteURL is the Jira site a URL belongs to: its scheme and host, without any
+// REST API path. Both the configured base URL and an issue's self link map to it.
+func siteURL(rawURL string) string {
+	site := strings.ToLower(strings.TrimRight(rawURL, "/"))
+	if i := strings.Index(site, "/rest/api/"); i >= 0 {
+		site = site[:i]
+	}
+	return site
+}
+
+// serviceNowIDField returns the ServiceNow ID field of a site
+func serviceNowIDField(site string) string {
+	serviceNowIDFields.RLock()
+	defer serviceNowIDFields.RUnlock()
+	if id, ok := serviceNowIDFields.ids[site]; ok {
+		return id
+	}
+	return DefaultServiceNowIDField
+}
+
+// GetFields lists the site's system and custom fields. The list is cached
+// until a field is created through the client.
+func (c *Client) GetFields() ([]Field, error) {
+	if fields, ok := c.lookup(cacheFields); ok {
+		return fields.([]Field), nil
+	}
+
+	resp, err := c.makeRequest("GET", "field", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error listing Jira fields: %w", err)
+	}
+
+	var fields []Field
+	if err := json.Unmarshal(resp, &fields); err != nil {
+		return nil, fmt.Errorf("error parsing Jira fields: %w", err)
+	}
+	c.cache(fields, cacheFields)
+	return fields, nil
+}
+
+// CreateTextField creates a single-line text custom field. Jira Cloud only
+// lets it be set on issues once it is on the project's screens.
+func (c *Client) CreateTextField(name, description string) (*Field, error) {
+	return c.createField(name, description, textFieldType, textFieldSearcher)
+}
+
+// CreateNumberField creates a number custom field
+func (c *Client) CreateNumberField(name, description string) (*Field, error) {
+	return c.createField(name, description, numberFieldType, numberFieldSearcher)
+}
+
+func (c *Client) createField(name, description, fieldType, searcher string) (*Field, error) {
+	requestBody := map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"type":        fieldType,
+		"searcherKey": searcher,
+	}
+	resp, err := c.makeRequest("POST", "field", requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Jira field %q: %w", name, err)
+	}
+	c.uncache(cacheFields)
+
+	var field Field
+	if err := json.Unmarshal(resp, &field); err != nil {
+		return nil, fmt.Errorf("error parsing Jira field: %w", err)
+	}
+	return &field, nil
+}
+
+// DiscoverServiceNowIDField finds the site's "ServiceNow ID" custom field and
+// caches its ID for the site. A site without one gets the field when create is
+// set; otherwise DefaultServiceNowIDField stays in use. It returns the cached
+// ID when the site was already discovered.
+func (c *Client) DiscoverServiceNowIDField(create bool) (string, error) {
+	site := siteURL(c.BaseURL)
+	serviceNowIDFields.RLock()
+	id, ok := serviceNowIDFields.ids[site]
+	serviceNowIDFields.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	id, err := c.findCustomField(ServiceNowIDFieldName)
+	if err != nil {
+		return "", err
+	}
+
+	if id == "" && !create {
+		return DefaultServiceNowIDField, nil
+	}
+	if id == "" {
+		field, err := c.CreateTextField(ServiceNowIDFieldName, "sys_id of the ServiceNow record this issue is synced with")
+		if err != nil {
+			return "", err
+		}
+		id = field.ID
+	}
+
+	serviceNowIDFields.Lock()
+	serviceNowIDFields.ids[site] = id
+	serviceNowIDFields.Unlock()
+	return id, nil
+}
+
+// findCustomField returns the ID of the custom field with a name, or ""
+func (c *Client) findCustomField(name string) (string, error) {
+	fields, err := c.GetFields()
+	if err != nil {
+		return "", err
+	}
+	for _, field := range fields {
+		if field.Custom && strings.EqualFold(field.Name, name) {
+			return field.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// DiscoverRiskScoreField finds the site's "Risk Matrix Score" field, creating
+// it when create is set, and caches its ID for the site. Without the field,
+// "" is returned and scores are not written to issues.
+func (c *Client) DiscoverRiskScoreField(create bool) (string, error) {
+	site := siteURL(c.BaseURL)
+	riskScoreFields.RLock()
+	id, ok := riskScoreFields.ids[site]
+	riskScoreFields.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	id, err := c.findCustomField(RiskScoreFieldName)
+	if err != nil {
+		return "", err
+	}
+	if id == "" && !create {
+		return "", nil
+	}
+	if id == "" {
+		field, err := c.CreateNumberField(RiskScoreFieldName, "Likelihood × impact score of the ServiceNow risk, from the risk matrix")
+		if err != nil {
+			return "", err
+		}
+		id = field.ID
+	}
+
+	riskScoreFields.Lock()
+	riskScoreFields.ids[site] = id
+	riskScoreFields.Unlock()
+	return id, nil
+}
+
+// RiskScoreField returns the ID of the site's Risk Matrix Score field, or ""
+// if it has not been discovered
+func (c *Client) RiskScoreField() string {
+	riskScoreFields.RLock()
+	defer riskScoreFields.RUnlock()
+	return riskScoreFields.ids[siteURL(c.BaseURL)]
+}
+
+// ServiceNowIDField returns the ID of the site's ServiceNow ID field, or
+// DefaultServiceNowIDField if it has not been discovered
+func (c *Client) ServiceNowIDField() string {
+	return serviceNowIDField(siteURL(c.BaseURL))
+}
+
+// ServiceNowID returns the sys_id held in the issue's ServiceNow ID field, looked
+// up for the site the issue's self link points at
+func (i *WebhookIssue) ServiceNowID() string {
+	id, _ := i.Fields.CustomFields[serviceNowIDField(siteURL(i.Self))].(string)
+	return id
+}
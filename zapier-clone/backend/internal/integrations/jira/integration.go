@@ -0,0 +1,126 @@
+// backend/internal/integrations/jira/integration.go
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+)
+
+// Integration exposes Jira to workflows through the common integration interfaces
+type Integration struct {
+	Client *Client
+	// Webhook receives jira:* webhooks; it is set where the webhook handler is built
+	Webhook http.Handler
+}
+
+// NewIntegration creates the Jira integration for a client
+func NewIntegration(client *Client, webhook http.Handler) *Integration {
+	return &Integration{
+		Client:  client,
+		Webhook: webhook,
+	}
+}
+
+// Name implements common.Integration
+func (i *Integration) Name() string {
+	return "jira"
+}
+
+// Triggers implements common.Trigger
+func (i *Integration) Triggers() []common.TriggerSpec {
+	return []common.TriggerSpec{
+		{ID: "issue.created", Description: "A Jira issue was created"},
+		{ID: "issue.updated", Description: "A Jira issue changed or moved to another project"},
+		{ID: "issue.deleted", Description: "A Jira issue was deleted"},
+		{ID: "comment.created", Description: "A comment was added to a Jira issue"},
+	}
+}
+
+// WebhookHandler implements common.Trigger
+func (i *Integration) WebhookHandler() http.Handler {
+	return i.Webhook
+}
+
+// Actions implements common.Action
+func (i *Integration) Actions() []common.ActionSpec {
+	return []common.ActionSpec{
+		{ID: "create_issue", Description: "Create an issue; project defaults to the configured project and issue_type to Task", Fields: []string{"summary"}},
+		{ID: "update_issue", Description: "Change an issue's status, assignee, priority, summary or description, optionally with a comment", Fields: []string{"issue_key"}},
+		{ID: "add_comment", Description: "Comment on an issue", Fields: []string{"issue_key", "body"}},
+	}
+}
+
+// Execute implements common.Action
+func (i *Integration) Execute(ctx context.Context, action string, fields map[string]interface{}) (map[string]interface{}, error) {
+	client := i.Client.WithContext(ctx)
+
+	switch action {
+	case "create_issue":
+		if err := common.RequireFields(fields, "summary"); err != nil {
+			return nil, err
+		}
+		ticket := &Ticket{
+			Project:     common.StringField(fields, "project"),
+			IssueType:   common.StringField(fields, "issue_type"),
+			Summary:     common.StringField(fields, "summary"),
+			Description: common.StringField(fields, "description"),
+			Priority:    common.StringField(fields, "priority"),
+		}
+		if ticket.Project == "" {
+			ticket.Project = client.ProjectKey
+		}
+		if ticket.IssueType == "" {
+			ticket.IssueType = "Task"
+		}
+		created, err := client.CreateIssue(ticket)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"key": created.Key,
+			"id":  created.ID,
+			"url": fmt.Sprintf("%s/browse/%s", client.BaseURL, created.Key),
+		}, nil
+
+	case "update_issue":
+		if err := common.RequireFields(fields, "issue_key"); err != nil {
+			return nil, err
+		}
+		key := common.StringField(fields, "issue_key")
+		update := &TicketUpdate{
+			Status:      common.StringField(fields, "status"),
+			Assignee:    common.StringField(fields, "assignee"),
+			Priority:    common.StringField(fields, "priority"),
+			Summary:     common.StringField(fields, "summary"),
+			Description: common.StringField(fields, "description"),
+			Comment:     common.StringField(fields, "comment"),
+		}
+		if err := client.UpdateIssue(key, update); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"key": key}, nil
+
+	case "add_comment":
+		if err := common.RequireFields(fields, "issue_key", "body"); err != nil {
+			return nil, err
+		}
+		key := common.StringField(fields, "issue_key")
+		if err := client.AddComment(key, common.StringField(fields, "body")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"key": key}, nil
+	}
+
+	return nil, fmt.Errorf("%w: jira.%s", common.ErrUnknownAction, action)
+}
+
+// ValidateConnection implements common.ConnectionValidator by reading the API user
+func (i *Integration) ValidateConnection(ctx context.Context) error {
+	if _, err := i.Client.WithContext(ctx).makeRequest("GET", "myself", nil); err != nil {
+		return fmt.Errorf("error checking Jira credentials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// backend/internal/integrations/jira/mapping_store.go
+package jira
+
+import "encoding/json"
+
+// MappingStore persists the links between ServiceNow risks and Jira issues.
+// RiskJiraMapping is the file implementation; internal/mappingstore adds
+// SQLite and Redis backends that are safe for several replicas.
+type MappingStore interface {
+	// AddMapping links a risk to a Jira issue, replacing any previous link of either
+	AddMapping(riskID, jiraKey string) error
+	// GetJiraKeyFromRiskID returns the issue linked to a risk
+	GetJiraKeyFromRiskID(riskID string) (string, bool)
+	// GetRiskIDFromJiraKey returns the risk linked to an issue
+	GetRiskIDFromJiraKey(jiraKey string) (string, bool)
+	// RemoveMapping drops a risk's link, if any
+	RemoveMapping(riskID string) error
+	// Snapshot returns both indexes so consistency checks can compare them
+	Snapshot() (riskToJira, jiraToRisk map[string]string, err error)
+}
+
+// Journaled mapping change operations
+const (
+	changeAdd    = "add"
+	changeRemove = "remove"
+)
+
+// mappingChange is one link change as written to a mapping file's journal
+type mappingChange struct {
+	Op  string `json:"op"`
+	ID  string `json:"id"`
+	Key string `json:"key,omitempty"`
+}
+
+// replayChanges applies journaled changes left by a crash. Adding or removing
+// a link twice has the same effect as doing it once.
+func replayChanges(changes []json.RawMessage, link func(id, key string), unlink func(id string)) {
+	for _, raw := range changes {
+		var change mappingChange
+		if err := json.Unmarshal(raw, &change); err != nil {
+			continue
+		}
+		switch change.Op {
+		case changeAdd:
+			link(change.ID, change.Key)
+		case changeRemove:
+			unlink(change.ID)
+		}
+	}
+}
+
+// copyIndex copies one direction of a mapping
+func copyIndex(index map[string]string) map[string]string {
+	copied := make(map[string]string, len(index))
+	for from, to := range index {
+		copied[from] = to
+	}
+	return copied
+}
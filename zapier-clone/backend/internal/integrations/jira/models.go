@@ -2,34 +2,47 @@ package jira
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
 )
 
 // Ticket represents a Jira issue
 type Ticket struct {
-	ID          string                 `json:"id,omitempty"`
-	Key         string                 `json:"key,omitempty"`
-	Self        string                 `json:"self,omitempty"`
-	Project     string                 `json:"project"`
-	IssueType   string                 `json:"issuetype"`
-	Summary     string                 `json:"summary"`
-	Description string                 `json:"description"`
-	Priority    string                 `json:"priority,omitempty"`
-	Status      string                 `json:"status,omitempty"`
-	Assignee    string                 `json:"assignee,omitempty"`
-	Parent      string                 `json:"parent,omitempty"`
-	Reporter    string                 `json:"reporter,omitempty"`
-	Created     time.Time              `json:"created,omitempty"`
-	Updated     time.Time              `json:"updated,omitempty"`
-	DueDate     time.Time              `json:"duedate,omitempty"`
-	Labels      []string               `json:"labels,omitempty"`
-	Epic        *EpicDetails           `json:"epic,omitempty"`
-	Components  []string               `json:"components,omitempty"`
-	Fields      map[string]interface{} `json:"fields,omitempty"`
+	ID          string `json:"id,omitempty"`
+	Key         string `json:"key,omitempty"`
+	Self        string `json:"self,omitempty"`
+	Project     string `json:"project"`
+	IssueType   string `json:"issuetype"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	Priority    string `json:"priority,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Assignee    string `json:"assignee,omitempty"`
+	// AssigneeID is the assignee's account ID; it takes precedence over Assignee
+	AssigneeID string                 `json:"assignee_id,omitempty"`
+	Parent     string                 `json:"parent,omitempty"`
+	Reporter   string                 `json:"reporter,omitempty"`
+	Created    time.Time              `json:"created,omitempty"`
+	Updated    time.Time              `json:"updated,omitempty"`
+	DueDate    time.Time              `json:"duedate,omitempty"`
+	Labels     []string               `json:"labels,omitempty"`
+	Epic       *EpicDetails           `json:"epic,omitempty"`
+	Components []string               `json:"components,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+}
+
+// ProjectOf returns the project key of an issue key such as "SEC-12"
+func ProjectOf(issueKey string) string {
+	project, _, _ := strings.Cut(issueKey, "-")
+	return project
 }
 
 // EpicDetails contains Epic-specific fields
@@ -39,10 +52,12 @@ type EpicDetails struct {
 }
 
 type TicketUpdate struct {
-	Status      string                 `json:"status,omitempty"`
-	Resolution  string                 `json:"resolution,omitempty"`
-	Comment     string                 `json:"comment,omitempty"`
-	Assignee    string                 `json:"assignee,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Resolution string `json:"resolution,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	Assignee   string `json:"assignee,omitempty"`
+	// AssigneeID is the assignee's account ID; it takes precedence over Assignee
+	AssigneeID  string                 `json:"assignee_id,omitempty"`
 	Priority    string                 `json:"priority,omitempty"`
 	DueDate     string                 `json:"dueDate,omitempty"`
 	Summary     string                 `json:"summary,omitempty"`
@@ -258,7 +273,8 @@ type TransitionRequest struct {
 
 // CommentRequest represents the request to add a comment to a Jira issue
 type CommentRequest struct {
-	Body       string `json:"body"`
+	// Body is a string for the v2 API and an *ADFNode for v3
+	Body       interface{} `json:"body"`
 	Visibility struct {
 		Type  string `json:"type,omitempty"`
 		Value string `json:"value,omitempty"`
@@ -286,7 +302,7 @@ type WebhookIssue struct {
 // WebhookIssueFields represents the issue fields in a Jira webhook event
 type WebhookIssueFields struct {
 	Summary     string             `json:"summary"`
-	Description string             `json:"description"`
+	Description Text               `json:"description"`
 	Status      *WebhookStatus     `json:"status"`
 	Resolution  *WebhookResolution `json:"resolution"`
 	Assignee    *WebhookUser       `json:"assignee"`
@@ -340,6 +356,20 @@ type WebhookStatus struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Self        string `json:"self"`
+	// Category is the status category, when the event includes it
+	Category *StatusCategory `json:"statusCategory,omitempty"`
+}
+
+// Done reports whether the status is in the done category, or is named
+// "Done" when the event has no category
+func (s *WebhookStatus) Done() bool {
+	if s == nil {
+		return false
+	}
+	if s.Category != nil && s.Category.Key != "" {
+		return s.Category.Key == "done"
+	}
+	return strings.EqualFold(s.Name, "Done")
 }
 
 // WebhookResolution represents a resolution in a Jira webhook event
@@ -363,7 +393,7 @@ type WebhookUser struct {
 // WebhookComment represents a comment in a Jira webhook event
 type WebhookComment struct {
 	ID      string       `json:"id"`
-	Body    string       `json:"body"`
+	Body    Text         `json:"body"`
 	Author  *WebhookUser `json:"author"`
 	Created string       `json:"created"`
 	Updated string       `json:"updated"`
@@ -407,6 +437,12 @@ type ErrorResponse struct {
 	StatusCode    int               `json:"-"`
 }
 
+// IsNotFound reports whether err is a Jira 404 response
+func IsNotFound(err error) bool {
+	var apiErr *ErrorResponse
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
 // Error implements the error interface for ErrorResponse
 func (e *ErrorResponse) Error() string {
 	var msg string
@@ -429,25 +465,28 @@ func NewEmptyRiskJiraMapping() *RiskJiraMapping {
 	return &RiskJiraMapping{
 		RiskIDToJiraKey: make(map[string]string),
 		JiraKeyToRiskID: make(map[string]string),
-		filePath:        "", // No persistence
+		// No file: nothing is persisted
 	}
 }
 
+// IncidentJiraMapping stores the links between ServiceNow incidents and Jira
+// epics. Like RiskJiraMapping it is safe for concurrent use and journals every
+// change before rewriting its file. A mapping built as a literal is not persisted.
 type IncidentJiraMapping struct {
 	IncidentIDToJiraKey map[string]string `json:"incident_id_to_jira_key"`
 	JiraKeyToIncidentID map[string]string `json:"jira_key_to_incident_id"`
-	filePath            string
+	mutex               sync.RWMutex
+	file                *common.JournaledFile
 }
 
-// NewIncidentJiraMapping creates a new mapping store and loads existing mappings
+// NewIncidentJiraMapping creates a new mapping store and loads existing mappings,
+// replaying any change a crash left in the journal
 func NewIncidentJiraMapping(dataDir string) (*IncidentJiraMapping, error) {
-	filePath := filepath.Join(dataDir, "incident_jira_mapping.json")
-
 	// Create the mapping
 	mapping := &IncidentJiraMapping{
 		IncidentIDToJiraKey: make(map[string]string),
 		JiraKeyToIncidentID: make(map[string]string),
-		filePath:            filePath,
+		file:                common.NewJournaledFile(filepath.Join(dataDir, "incident_jira_mapping.json")),
 	}
 
 	// Create data directory if it doesn't exist
@@ -455,19 +494,16 @@ func NewIncidentJiraMapping(dataDir string) (*IncidentJiraMapping, error) {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	// Try to load existing mappings
-	file, err := os.Open(filePath)
-	if os.IsNotExist(err) {
-		// File doesn't exist yet, that's ok
-		return mapping, nil
-	} else if err != nil {
-		return nil, fmt.Errorf("error opening mapping file: %w", err)
+	// Load existing mappings; a missing file just means none yet
+	changes, err := mapping.file.Load(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("error loading incident mapping: %w", err)
 	}
-	defer file.Close()
-
-	// Decode the JSON data
-	if err := json.NewDecoder(file).Decode(mapping); err != nil {
-		return nil, fmt.Errorf("error decoding mapping data: %w", err)
+	if len(changes) > 0 {
+		replayChanges(changes, mapping.link, mapping.unlink)
+		if err := mapping.save(); err != nil {
+			return nil, err
+		}
 	}
 
 	return mapping, nil
@@ -475,38 +511,97 @@ func NewIncidentJiraMapping(dataDir string) (*IncidentJiraMapping, error) {
 
 // SaveMapping saves the current mappings to disk
 func (m *IncidentJiraMapping) SaveMapping() error {
-	if m.filePath == "" {
-		return nil // No persistence
-	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.save()
+}
 
-	file, err := os.Create(m.filePath)
-	if err != nil {
-		return fmt.Errorf("error creating mapping file: %w", err)
-	}
-	defer file.Close()
+// AddMapping adds a new mapping between ServiceNow incident ID and Jira key,
+// replacing any previous link of either
+func (m *IncidentJiraMapping) AddMapping(incidentID, jiraKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
-	if err := json.NewEncoder(file).Encode(m); err != nil {
-		return fmt.Errorf("error encoding mapping data: %w", err)
+	if err := m.journal(mappingChange{Op: changeAdd, ID: incidentID, Key: jiraKey}); err != nil {
+		return err
 	}
-
-	return nil
+	m.link(incidentID, jiraKey)
+	return m.save()
 }
 
-// AddMapping adds a new mapping between ServiceNow incident ID and Jira key
-func (m *IncidentJiraMapping) AddMapping(incidentID, jiraKey string) error {
-	m.IncidentIDToJiraKey[incidentID] = jiraKey
-	m.JiraKeyToIncidentID[jiraKey] = incidentID
-	return m.SaveMapping()
+// RemoveMapping drops an incident's link, if any
+func (m *IncidentJiraMapping) RemoveMapping(incidentID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.IncidentIDToJiraKey[incidentID]; !ok {
+		return nil
+	}
+	if err := m.journal(mappingChange{Op: changeRemove, ID: incidentID}); err != nil {
+		return err
+	}
+	m.unlink(incidentID)
+	return m.save()
 }
 
 // GetJiraKeyFromIncidentID gets the Jira key for a given ServiceNow incident ID
 func (m *IncidentJiraMapping) GetJiraKeyFromIncidentID(incidentID string) (string, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
 	jiraKey, exists := m.IncidentIDToJiraKey[incidentID]
 	return jiraKey, exists
 }
 
 // GetIncidentIDFromJiraKey gets the ServiceNow incident ID for a given Jira key
 func (m *IncidentJiraMapping) GetIncidentIDFromJiraKey(jiraKey string) (string, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
 	incidentID, exists := m.JiraKeyToIncidentID[jiraKey]
 	return incidentID, exists
 }
+
+// Snapshot returns copies of both indexes
+func (m *IncidentJiraMapping) Snapshot() (map[string]string, map[string]string) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return copyIndex(m.IncidentIDToJiraKey), copyIndex(m.JiraKeyToIncidentID)
+}
+
+// link updates both indexes; the caller holds the write lock
+func (m *IncidentJiraMapping) link(incidentID, jiraKey string) {
+	if oldKey, ok := m.IncidentIDToJiraKey[incidentID]; ok && oldKey != jiraKey {
+		delete(m.JiraKeyToIncidentID, oldKey)
+	}
+	if oldIncident, ok := m.JiraKeyToIncidentID[jiraKey]; ok && oldIncident != incidentID {
+		delete(m.IncidentIDToJiraKey, oldIncident)
+	}
+	m.IncidentIDToJiraKey[incidentID] = jiraKey
+	m.JiraKeyToIncidentID[jiraKey] = incidentID
+}
+
+// unlink drops an incident from both indexes; the caller holds the write lock
+func (m *IncidentJiraMapping) unlink(incidentID string) {
+	if jiraKey, ok := m.IncidentIDToJiraKey[incidentID]; ok {
+		delete(m.IncidentIDToJiraKey, incidentID)
+		delete(m.JiraKeyToIncidentID, jiraKey)
+	}
+}
+
+// journal records a change before it is applied
+func (m *IncidentJiraMapping) journal(change mappingChange) error {
+	if m.file == nil {
+		return nil // No persistence
+	}
+	return m.file.Append(change)
+}
+
+// save persists the mapping to disk
+func (m *IncidentJiraMapping) save() error {
+	if m.file == nil {
+		return nil // No persistence
+	}
+	return m.file.Save(m)
+}
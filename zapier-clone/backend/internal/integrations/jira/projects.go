@@ -0,0 +1,102 @@
+// backend/internal/integrations/jira/projects.go
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetProject returns a project by key, or nil when there is none. Projects
+// found are cached; missing ones are looked up again next time.
+func (c *Client) GetProject(key string) (*Project, error) {
+	if project, ok := c.lookup(cacheProjects, key); ok {
+		copied := project.(Project)
+		return &copied, nil
+	}
+
+	resp, err := c.makeRequest("GET", "project/"+url.PathEscape(key), nil)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting Jira project %s: %w", key, err)
+	}
+
+	var project Project
+	if err := json.Unmarshal(resp, &project); err != nil {
+		return nil, fmt.Errorf("error parsing Jira project: %w", err)
+	}
+	c.cache(project, cacheProjects, key)
+	return &project, nil
+}
+
+// CreateProject creates a business project. An empty leadAccountID makes the
+// account the client signs in with the project lead.
+func (c *Client) CreateProject(key, name, description, leadAccountID string) (*Project, error) {
+	if leadAccountID == "" {
+		resp, err := c.makeRequest("GET", "myself", nil)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up the Jira project lead: %w", err)
+		}
+		var myself User
+		if err := json.Unmarshal(resp, &myself); err != nil {
+			return nil, fmt.Errorf("error parsing Jira user: %w", err)
+		}
+		leadAccountID = myself.ID
+	}
+
+	requestBody := map[string]interface{}{
+		"key":            key,
+		"name":           name,
+		"description":    description,
+		"projectTypeKey": "business",
+		"leadAccountId":  leadAccountID,
+		"assigneeType":   "UNASSIGNED",
+	}
+	resp, err := c.makeRequest("POST", "project", requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Jira project %s: %w", key, err)
+	}
+
+	project := Project{Key: key, Name: name}
+	if err := json.Unmarshal(resp, &project); err != nil {
+		return nil, fmt.Errorf("error parsing Jira project: %w", err)
+	}
+	c.uncache(cacheProjects, key)
+	return &project, nil
+}
+
+// GetComponents lists a project's components
+func (c *Client) GetComponents(projectKey string) ([]Component, error) {
+	resp, err := c.makeRequest("GET", "project/"+url.PathEscape(projectKey)+"/components", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error listing components of Jira project %s: %w", projectKey, err)
+	}
+
+	var components []Component
+	if err := json.Unmarshal(resp, &components); err != nil {
+		return nil, fmt.Errorf("error parsing Jira components: %w", err)
+	}
+	return components, nil
+}
+
+// CreateComponent adds a component to a project
+func (c *Client) CreateComponent(projectKey, name, description string) (*Component, error) {
+	requestBody := map[string]interface{}{
+		"project":     projectKey,
+		"name":        name,
+		"description": description,
+	}
+	resp, err := c.makeRequest("POST", "component", requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Jira component %q in %s: %w", name, projectKey, err)
+	}
+
+	component := Component{Name: name}
+	if err := json.Unmarshal(resp, &component); err != nil {
+		return nil, fmt.Errorf("error parsing Jira component: %w", err)
+	}
+	c.rememberComponent(projectKey, component.Name)
+	return &component, nil
+}
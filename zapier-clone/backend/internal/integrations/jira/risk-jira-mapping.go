@@ -1,54 +1,59 @@
 package jira
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
 )
 
-// RiskJiraMapping stores the mapping between ServiceNow risks and Jira issues
+// RiskJiraMapping stores the mapping between ServiceNow risks and Jira issues in a
+// JSON file. It is safe for concurrent use, and every change is journaled before
+// the file is rewritten so a crash mid-save cannot corrupt it. It is only safe
+// for a single process; use a SQLite or Redis store when several replicas write
+// mappings.
 type RiskJiraMapping struct {
 	RiskIDToJiraKey map[string]string `json:"riskIdToJiraKey"`
 	JiraKeyToRiskID map[string]string `json:"jiraKeyToRiskID"`
 	mutex           sync.RWMutex
-	filePath        string
+	// file is nil for a mapping that is not persisted
+	file *common.JournaledFile
 }
 
-// NewRiskJiraMapping creates a new mapping store
+// NewRiskJiraMapping creates a new mapping store, replaying any change a crash
+// left in the journal
 func NewRiskJiraMapping(storagePath string) (*RiskJiraMapping, error) {
-	filePath := filepath.Join(storagePath, "risk_jira_mapping.json")
-
 	mapping := &RiskJiraMapping{
 		RiskIDToJiraKey: make(map[string]string),
 		JiraKeyToRiskID: make(map[string]string),
-		filePath:        filePath,
+		file:            common.NewJournaledFile(filepath.Join(storagePath, "risk_jira_mapping.json")),
 	}
 
-	// Try to load existing mapping
-	if _, err := os.Stat(filePath); err == nil {
-		file, err := os.ReadFile(filePath)
-		if err != nil {
-			return nil, fmt.Errorf("error reading mapping file: %w", err)
-		}
-
-		if err := json.Unmarshal(file, mapping); err != nil {
-			return nil, fmt.Errorf("error unmarshaling mapping: %w", err)
+	changes, err := mapping.file.Load(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("error loading risk mapping: %w", err)
+	}
+	if len(changes) > 0 {
+		replayChanges(changes, mapping.link, mapping.unlink)
+		if err := mapping.save(); err != nil {
+			return nil, err
 		}
 	}
 
 	return mapping, nil
 }
 
-// AddMapping adds a mapping between a risk ID and a Jira issue key
+// AddMapping adds a mapping between a risk ID and a Jira issue key, replacing
+// any previous link of either
 func (m *RiskJiraMapping) AddMapping(riskID, jiraKey string) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	m.RiskIDToJiraKey[riskID] = jiraKey
-	m.JiraKeyToRiskID[jiraKey] = riskID
-
+	if err := m.journal(mappingChange{Op: changeAdd, ID: riskID, Key: jiraKey}); err != nil {
+		return err
+	}
+	m.link(riskID, jiraKey)
 	return m.save()
 }
 
@@ -70,22 +75,61 @@ func (m *RiskJiraMapping) GetRiskIDFromJiraKey(jiraKey string) (string, bool) {
 	return riskID, exists
 }
 
-// save persists the mapping to disk
-func (m *RiskJiraMapping) save() error {
-	data, err := json.MarshalIndent(m, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error marshaling mapping: %w", err)
+// RemoveMapping drops a risk's link, if any
+func (m *RiskJiraMapping) RemoveMapping(riskID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.RiskIDToJiraKey[riskID]; !ok {
+		return nil
 	}
+	if err := m.journal(mappingChange{Op: changeRemove, ID: riskID}); err != nil {
+		return err
+	}
+	m.unlink(riskID)
+	return m.save()
+}
 
-	// Ensure directory exists
-	dir := filepath.Dir(m.filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("error creating directory: %w", err)
+// Snapshot returns copies of both indexes
+func (m *RiskJiraMapping) Snapshot() (map[string]string, map[string]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return copyIndex(m.RiskIDToJiraKey), copyIndex(m.JiraKeyToRiskID), nil
+}
+
+// link updates both indexes; the caller holds the write lock
+func (m *RiskJiraMapping) link(riskID, jiraKey string) {
+	if oldKey, ok := m.RiskIDToJiraKey[riskID]; ok && oldKey != jiraKey {
+		delete(m.JiraKeyToRiskID, oldKey)
+	}
+	if oldRisk, ok := m.JiraKeyToRiskID[jiraKey]; ok && oldRisk != riskID {
+		delete(m.RiskIDToJiraKey, oldRisk)
 	}
+	m.RiskIDToJiraKey[riskID] = jiraKey
+	m.JiraKeyToRiskID[jiraKey] = riskID
+}
+
+// unlink drops a risk from both indexes; the caller holds the write lock
+func (m *RiskJiraMapping) unlink(riskID string) {
+	if jiraKey, ok := m.RiskIDToJiraKey[riskID]; ok {
+		delete(m.RiskIDToJiraKey, riskID)
+		delete(m.JiraKeyToRiskID, jiraKey)
+	}
+}
 
-	if err := os.WriteFile(m.filePath, data, 0644); err != nil {
-		return fmt.Errorf("error writing mapping file: %w", err)
+// journal records a change before it is applied
+func (m *RiskJiraMapping) journal(change mappingChange) error {
+	if m.file == nil {
+		return nil // No persistence
 	}
+	return m.file.Append(change)
+}
 
-	return nil
+// save persists the mapping to disk
+func (m *RiskJiraMapping) save() error {
+	if m.file == nil {
+		return nil // No persistence
+	}
+	return m.file.Save(m)
 }
@@ -0,0 +1,58 @@
+// backend/internal/integrations/jira/search.go
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// FoundIssue is an issue returned by Search
+type FoundIssue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	Status  string `json:"status,omitempty"`
+}
+
+// Search returns up to maxResults issues matching jql with their summary and status
+func (c *Client) Search(jql string, maxResults int) ([]FoundIssue, error) {
+	query := url.Values{}
+	query.Set("jql", jql)
+	query.Set("maxResults", fmt.Sprint(maxResults))
+	query.Set("fields", "summary,status")
+	resp, err := c.makeRequest("GET", "search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error searching Jira issues: %w", err)
+	}
+
+	// Jira nests summary and status under fields; flat values are read as well
+	var result struct {
+		Issues []struct {
+			Key     string      `json:"key"`
+			Summary string      `json:"summary"`
+			Status  interface{} `json:"status"`
+			Fields  struct {
+				Summary string `json:"summary"`
+				Status  struct {
+					Name string `json:"name"`
+				} `json:"status"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("error parsing Jira search results: %w", err)
+	}
+
+	issues := make([]FoundIssue, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		found := FoundIssue{Key: issue.Key, Summary: issue.Fields.Summary, Status: issue.Fields.Status.Name}
+		if found.Summary == "" {
+			found.Summary = issue.Summary
+		}
+		if status, ok := issue.Status.(string); ok && found.Status == "" {
+			found.Status = status
+		}
+		issues = append(issues, found)
+	}
+	return issues, nil
+}
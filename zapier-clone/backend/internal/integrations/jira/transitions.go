@@ -0,0 +1,462 @@
+// backend/internal/integrations/jira/transitions.go
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrNoTransition means no transition available to the issue leads to the
+// requested status, so its status was left alone
+var ErrNoTransition = errors.New("no transition leads to the status")
+
+// DefaultMaxTransitionHops is how many transitions TransitionTo makes at most
+// on the way to a status
+const DefaultMaxTransitionHops = 5
+
+// maxTransitionHopsFromEnv reads JIRA_MAX_TRANSITION_HOPS
+func maxTransitionHopsFromEnv() int {
+	value := os.Getenv("JIRA_MAX_TRANSITION_HOPS")
+	if value == "" {
+		return DefaultMaxTransitionHops
+	}
+	hops, err := strconv.Atoi(value)
+	if err != nil || hops < 1 {
+		slog.Warn("ignoring JIRA_MAX_TRANSITION_HOPS; use a whole number of at least 1", "value", value)
+		return DefaultMaxTransitionHops
+	}
+	return hops
+}
+
+// DefaultStatusSynonyms are other names workflows give the statuses the
+// integration moves issues to
+var DefaultStatusSynonyms = map[string][]string{
+	"To Do":       {"Open", "Backlog", "New", "Reopened"},
+	"In Progress": {"In Development", "Doing", "Started"},
+	"Done":        {"Closed", "Resolved", "Complete", "Completed"},
+}
+
+// statusSynonymsFromEnv returns DefaultStatusSynonyms with JIRA_STATUS_SYNONYMS
+// applied. It lists statuses with their other names, for example
+// "Done=Closed|Shipped,In Progress=Working". A status listed there replaces
+// its default synonyms.
+func statusSynonymsFromEnv() map[string][]string {
+	synonyms := make(map[string][]string, len(DefaultStatusSynonyms))
+	for status, names := range DefaultStatusSynonyms {
+		synonyms[status] = names
+	}
+
+	for _, entry := range strings.Split(os.Getenv("JIRA_STATUS_SYNONYMS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		status, list, ok := strings.Cut(entry, "=")
+		status = strings.TrimSpace(status)
+		if !ok || status == "" {
+			slog.Warn("ignoring Jira status synonyms; expected STATUS=NAME|NAME", "entry", entry)
+			continue
+		}
+		var names []string
+		for _, name := range strings.Split(list, "|") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		synonyms[status] = names
+	}
+	return synonyms
+}
+
+// StatusNames returns status followed by the other names it goes by. A status
+// listed as a synonym brings in the status it belongs to and that one's other
+// synonyms, so "Closed" also matches "Done" and "Resolved".
+func (c *Client) StatusNames(status string) []string {
+	names := []string{status}
+	seen := map[string]bool{strings.ToLower(status): true}
+	add := func(name string) {
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	for canonical, synonyms := range c.StatusSynonyms {
+		group := append([]string{canonical}, synonyms...)
+		for _, name := range group {
+			if strings.EqualFold(name, status) {
+				for _, other := range group {
+					add(other)
+				}
+				break
+			}
+		}
+	}
+	return names
+}
+
+// matchTransition picks the transition that leads to status: one whose target
+// has the status's name, then one of its synonyms, then a transition named
+// like the status, as in workflows with a "Close" or "Done" transition
+func (c *Client) matchTransition(transitions []Transition, status string) (Transition, bool) {
+	names := c.StatusNames(status)
+	for _, name := range names {
+		for _, t := range transitions {
+			if strings.EqualFold(t.To.Name, name) {
+				return t, true
+			}
+		}
+	}
+	for _, name := range names {
+		for _, t := range transitions {
+			if strings.EqualFold(t.Name, name) {
+				return t, true
+			}
+		}
+	}
+	return Transition{}, false
+}
+
+// TransitionTo moves an issue to status, setting resolution on the last
+// transition when it is not empty, and returns the status the issue ended up
+// in. An issue already in status (or one of its synonyms) is left as it is.
+//
+// When no transition leads there directly, as from To Do to Done in a To Do →
+// In Progress → Done workflow, the path is planned from the issue's workflow
+// before the issue is changed: the shortest one of at most MaxTransitionHops
+// transitions that passes through no done status. When there is none, the
+// issue is left alone and the error wraps ErrNoTransition.
+func (c *Client) TransitionTo(issueKey, status, resolution string) (string, error) {
+	moved, ok, err := c.transitionDirectly(issueKey, status, resolution, true)
+	if err != nil || ok {
+		return moved, err
+	}
+	return c.transitionAlongPath(issueKey, status, resolution)
+}
+
+// transitionDirectly makes the transition available to the issue that leads
+// to status, reporting false when there is none. With retry set, cached
+// transitions that offer none, or one Jira refuses, are listed again once.
+func (c *Client) transitionDirectly(issueKey, status, resolution string, retry bool) (string, bool, error) {
+	transitions, cached, err := c.availableTransitions(issueKey)
+	if err != nil {
+		return "", false, err
+	}
+	next, ok := c.matchTransition(transitions, status)
+	if !ok {
+		if cached && retry {
+			// The cached transitions may be stale; look again with fresh ones
+			c.transitionFailed(issueKey)
+			return c.transitionDirectly(issueKey, status, resolution, false)
+		}
+		return "", false, nil
+	}
+	if err := c.applyTransition(issueKey, next, resolution); err != nil {
+		if cached && retry {
+			c.transitionFailed(issueKey)
+			return c.transitionDirectly(issueKey, status, resolution, false)
+		}
+		return "", false, err
+	}
+	return next.To.Name, true, nil
+}
+
+// transitionAlongPath moves an issue that no transition takes to status
+// directly along the path planned from its workflow
+func (c *Client) transitionAlongPath(issueKey, status, resolution string) (string, error) {
+	issue, err := c.workflowIssue(issueKey)
+	if err != nil {
+		return "", fmt.Errorf("error getting the status of %s: %w", issueKey, err)
+	}
+	start := issue.Fields.Status
+	if c.isStatus(start.Name, status) {
+		return start.Name, nil
+	}
+
+	graph, err := c.workflowGraph(issue)
+	if err != nil {
+		return "", fmt.Errorf("%w %s from the %s status of %s, as its workflow could not be read: %w", ErrNoTransition, status, start.Name, issueKey, err)
+	}
+	path := c.planPath(graph, start.ID, status)
+	if path == nil {
+		return "", fmt.Errorf("%w %s from the %s status of %s", ErrNoTransition, status, start.Name, issueKey)
+	}
+
+	for i, t := range path {
+		hopResolution := ""
+		if i == len(path)-1 {
+			hopResolution = resolution
+		}
+		if err := c.applyTransition(issueKey, t, hopResolution); err != nil {
+			// The workflow may have changed since it was cached
+			c.uncache(cacheTransitions, "workflow", issue.Fields.Project.Key, issue.Fields.IssueType.ID)
+			return "", fmt.Errorf("error moving %s to %s after %d of %d transitions: %w", issueKey, status, i, len(path), err)
+		}
+	}
+	return path[len(path)-1].To.Name, nil
+}
+
+// planPath returns the shortest path of at most MaxTransitionHops transitions
+// from the status with ID from to status, or nil when there is none. Done
+// statuses are never passed through, as entering one resolves the issue and
+// its record.
+func (c *Client) planPath(graph *workflowGraph, from, status string) []Transition {
+	type step struct {
+		status string
+		path   []Transition
+	}
+	seen := map[string]bool{from: true}
+	queue := []step{{status: from}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if len(current.path) >= c.maxTransitionHops() {
+			continue
+		}
+		outgoing := graph.from(current.status)
+		if t, ok := c.matchTransition(outgoing, status); ok {
+			return append(current.path, t)
+		}
+		for _, t := range outgoing {
+			if seen[t.To.ID] || c.isDone(t.To) {
+				continue
+			}
+			seen[t.To.ID] = true
+			path := append(append([]Transition(nil), current.path...), t)
+			queue = append(queue, step{status: t.To.ID, path: path})
+		}
+	}
+	return nil
+}
+
+// isDone reports whether a status is in the done category, or named like Done
+// when its category is not given
+func (c *Client) isDone(status Status) bool {
+	if status.Category.Key != "" {
+		return status.Category.Key == "done"
+	}
+	return c.isStatus(status.Name, "Done")
+}
+
+// workflowGraph is a workflow's transitions by the status they leave, as
+// Jira's workflow metadata lists them
+type workflowGraph struct {
+	transitions map[string][]Transition
+	// global transitions leave every status
+	global []Transition
+}
+
+// from returns the transitions that leave the status with ID status
+func (g *workflowGraph) from(status string) []Transition {
+	return append(append([]Transition(nil), g.transitions[status]...), g.global...)
+}
+
+// workflowIssueFields is what picks an issue's workflow and where the issue is in it
+type workflowIssueFields struct {
+	Fields struct {
+		Project struct {
+			ID  string `json:"id"`
+			Key string `json:"key"`
+		} `json:"project"`
+		IssueType struct {
+			ID string `json:"id"`
+		} `json:"issuetype"`
+		Status Status `json:"status"`
+	} `json:"fields"`
+}
+
+// workflowIssue reads an issue's project, type and status
+func (c *Client) workflowIssue(issueKey string) (*workflowIssueFields, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("issue/%s?fields=project,issuetype,status", issueKey), nil)
+	if err != nil {
+		return nil, err
+	}
+	var issue workflowIssueFields
+	if err := json.Unmarshal(resp, &issue); err != nil {
+		return nil, fmt.Errorf("error unmarshaling issue: %w", err)
+	}
+	if issue.Fields.Status.ID == "" {
+		return nil, fmt.Errorf("issue %s has no status", issueKey)
+	}
+	return &issue, nil
+}
+
+// workflowGraph returns the transitions of the workflow an issue's project
+// uses for its type. It is read from the project's workflow scheme, the
+// workflow's transitions and the categories of the type's statuses, and
+// cached like the transitions of issues.
+func (c *Client) workflowGraph(issue *workflowIssueFields) (*workflowGraph, error) {
+	project, issueType := issue.Fields.Project, issue.Fields.IssueType.ID
+	if graph, ok := c.lookup(cacheTransitions, "workflow", project.Key, issueType); ok {
+		return graph.(*workflowGraph), nil
+	}
+
+	workflow, err := c.workflowName(project.ID, issueType)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := c.projectStatuses(project.Key, issueType)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("workflowName", workflow)
+	query.Set("expand", "transitions")
+	resp, err := c.makeRequest("GET", "workflow/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting workflow %s: %w", workflow, err)
+	}
+	var result struct {
+		Values []struct {
+			Transitions []struct {
+				ID   string   `json:"id"`
+				Name string   `json:"name"`
+				From []string `json:"from"`
+				To   string   `json:"to"`
+				Type string   `json:"type"`
+			} `json:"transitions"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshaling workflow %s: %w", workflow, err)
+	}
+	if len(result.Values) == 0 {
+		return nil, fmt.Errorf("workflow %s not found", workflow)
+	}
+
+	graph := &workflowGraph{transitions: make(map[string][]Transition)}
+	for _, raw := range result.Values[0].Transitions {
+		to, ok := statuses[raw.To]
+		if !ok || raw.Type == "initial" {
+			continue
+		}
+		t := Transition{ID: raw.ID, Name: raw.Name, To: to}
+		if raw.Type == "global" || len(raw.From) == 0 {
+			graph.global = append(graph.global, t)
+			continue
+		}
+		for _, from := range raw.From {
+			graph.transitions[from] = append(graph.transitions[from], t)
+		}
+	}
+	c.cache(graph, cacheTransitions, "workflow", project.Key, issueType)
+	return graph, nil
+}
+
+// workflowName returns the workflow a project's scheme assigns to an issue type
+func (c *Client) workflowName(projectID, issueType string) (string, error) {
+	resp, err := c.makeRequest("GET", "workflowscheme/project?projectId="+url.QueryEscape(projectID), nil)
+	if err != nil {
+		return "", fmt.Errorf("error getting workflow scheme: %w", err)
+	}
+	var result struct {
+		Values []struct {
+			WorkflowScheme struct {
+				DefaultWorkflow   string            `json:"defaultWorkflow"`
+				IssueTypeMappings map[string]string `json:"issueTypeMappings"`
+			} `json:"workflowScheme"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return "", fmt.Errorf("error unmarshaling workflow scheme: %w", err)
+	}
+	if len(result.Values) == 0 {
+		return "", fmt.Errorf("project %s has no workflow scheme", projectID)
+	}
+	scheme := result.Values[0].WorkflowScheme
+	if workflow := scheme.IssueTypeMappings[issueType]; workflow != "" {
+		return workflow, nil
+	}
+	if scheme.DefaultWorkflow == "" {
+		return "", fmt.Errorf("project %s has no workflow for issue type %s", projectID, issueType)
+	}
+	return scheme.DefaultWorkflow, nil
+}
+
+// projectStatuses returns the statuses an issue type of a project can be in,
+// with their categories, by ID
+func (c *Client) projectStatuses(projectKey, issueType string) (map[string]Status, error) {
+	resp, err := c.makeRequest("GET", "project/"+url.PathEscape(projectKey)+"/statuses", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting statuses of project %s: %w", projectKey, err)
+	}
+	var types []struct {
+		ID       string   `json:"id"`
+		Statuses []Status `json:"statuses"`
+	}
+	if err := json.Unmarshal(resp, &types); err != nil {
+		return nil, fmt.Errorf("error unmarshaling statuses: %w", err)
+	}
+	statuses := make(map[string]Status)
+	for _, t := range types {
+		if t.ID != issueType {
+			continue
+		}
+		for _, status := range t.Statuses {
+			statuses[status.ID] = status
+		}
+	}
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("project %s has no statuses for issue type %s", projectKey, issueType)
+	}
+	return statuses, nil
+}
+
+// availableTransitions returns the transitions available to an issue now,
+// reporting whether they came from the cache
+func (c *Client) availableTransitions(issueKey string) ([]Transition, bool, error) {
+	transitions, cached := c.cachedTransitions(issueKey)
+	c.countLookup(cacheTransitions, cached)
+	if cached {
+		return transitions, true, nil
+	}
+	transitions, err := c.fetchTransitions(issueKey)
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting available transitions: %w", err)
+	}
+	return transitions, false, nil
+}
+
+// applyTransition makes a transition, setting resolution when it is not empty
+func (c *Client) applyTransition(issueKey string, transition Transition, resolution string) error {
+	transitionReq := TransitionRequest{}
+	transitionReq.Transition.ID = transition.ID
+
+	if resolution != "" {
+		if transitionReq.Fields == nil {
+			transitionReq.Fields = make(map[string]interface{})
+		}
+		transitionReq.Fields["resolution"] = map[string]string{"name": resolution}
+	}
+
+	_, err := c.makeRequest("POST", fmt.Sprintf("issue/%s/transitions", issueKey), transitionReq)
+	if err != nil {
+		return fmt.Errorf("error transitioning issue to %s: %w", transition.To.Name, err)
+	}
+	c.issueMoved(issueKey, transition.To.Name)
+	return nil
+}
+
+// isStatus reports whether name is status or one of its synonyms
+func (c *Client) isStatus(name, status string) bool {
+	for _, other := range c.StatusNames(status) {
+		if strings.EqualFold(name, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxTransitionHops is MaxTransitionHops, or DefaultMaxTransitionHops when unset
+func (c *Client) maxTransitionHops() int {
+	if c.MaxTransitionHops > 0 {
+		return c.MaxTransitionHops
+	}
+	return DefaultMaxTransitionHops
+}
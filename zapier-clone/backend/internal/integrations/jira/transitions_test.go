@@ -0,0 +1,149 @@
+// backend/internal/integrations/jira/transitions_test.go
+package jira
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Statuses of a To Do → In Progress → In Review → Done workflow
+var (
+	statusToDo       = Status{ID: "1", Name: "To Do", Category: StatusCategory{Key: "new"}}
+	statusInProgress = Status{ID: "2", Name: "In Progress", Category: StatusCategory{Key: "indeterminate"}}
+	statusInReview   = Status{ID: "3", Name: "In Review", Category: StatusCategory{Key: "indeterminate"}}
+	statusDone       = Status{ID: "4", Name: "Done", Category: StatusCategory{Key: "done"}}
+	statusReopened   = Status{ID: "5", Name: "Reopened", Category: StatusCategory{Key: "new"}}
+	statusBlocked    = Status{ID: "6", Name: "Blocked", Category: StatusCategory{Key: "indeterminate"}}
+)
+
+func TestPlanPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		graph   *workflowGraph
+		from    string
+		status  string
+		maxHops int
+		// want lists the IDs of the planned transitions; nil means no path
+		want []string
+	}{
+		{
+			name: "direct transition",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "11", Name: "Start", To: statusInProgress}, {ID: "41", Name: "Finish", To: statusDone}},
+			}},
+			from:   "1",
+			status: "Done",
+			want:   []string{"41"},
+		},
+		{
+			name: "multi-hop path",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "11", Name: "Start", To: statusInProgress}},
+				"2": {{ID: "21", Name: "Review", To: statusInReview}, {ID: "22", Name: "Stop", To: statusToDo}},
+				"3": {{ID: "31", Name: "Approve", To: statusDone}},
+			}},
+			from:   "1",
+			status: "Done",
+			want:   []string{"11", "21", "31"},
+		},
+		{
+			name: "shortest of two paths",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "11", Name: "Start", To: statusInProgress}, {ID: "12", Name: "Block", To: statusBlocked}},
+				"2": {{ID: "21", Name: "Review", To: statusInReview}},
+				"3": {{ID: "31", Name: "Approve", To: statusDone}},
+				"6": {{ID: "61", Name: "Approve", To: statusDone}},
+			}},
+			from:   "1",
+			status: "Done",
+			want:   []string{"12", "61"},
+		},
+		{
+			name: "unreachable status",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "11", Name: "Start", To: statusInProgress}},
+				"2": {{ID: "21", Name: "Review", To: statusInReview}},
+			}},
+			from:   "1",
+			status: "Done",
+			want:   nil,
+		},
+		{
+			name: "cycle without the status",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "11", Name: "Start", To: statusInProgress}},
+				"2": {{ID: "21", Name: "Review", To: statusInReview}, {ID: "22", Name: "Stop", To: statusToDo}},
+				"3": {{ID: "32", Name: "Rework", To: statusInProgress}},
+			}},
+			from:   "1",
+			status: "Done",
+			want:   nil,
+		},
+		{
+			name: "cycle on the way to the status",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "11", Name: "Start", To: statusInProgress}},
+				"2": {{ID: "22", Name: "Stop", To: statusToDo}, {ID: "21", Name: "Review", To: statusInReview}},
+				"3": {{ID: "32", Name: "Rework", To: statusInProgress}, {ID: "31", Name: "Approve", To: statusDone}},
+			}},
+			from:   "1",
+			status: "Done",
+			want:   []string{"11", "21", "31"},
+		},
+		{
+			name: "done status is not passed through",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "41", Name: "Finish", To: statusDone}},
+				"4": {{ID: "51", Name: "Reopen", To: statusReopened}},
+			}},
+			from:   "1",
+			status: "Reopened",
+			want:   nil,
+		},
+		{
+			name: "global transition",
+			graph: &workflowGraph{
+				transitions: map[string][]Transition{"1": {{ID: "11", Name: "Start", To: statusInProgress}}},
+				global:      []Transition{{ID: "91", Name: "Block", To: statusBlocked}},
+			},
+			from:   "2",
+			status: "Blocked",
+			want:   []string{"91"},
+		},
+		{
+			name: "synonym of the status",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "41", Name: "Finish", To: statusDone}},
+			}},
+			from:   "1",
+			status: "Closed",
+			want:   []string{"41"},
+		},
+		{
+			name: "path longer than the hop limit",
+			graph: &workflowGraph{transitions: map[string][]Transition{
+				"1": {{ID: "11", Name: "Start", To: statusInProgress}},
+				"2": {{ID: "21", Name: "Review", To: statusInReview}},
+				"3": {{ID: "31", Name: "Approve", To: statusDone}},
+			}},
+			from:    "1",
+			status:  "Done",
+			maxHops: 2,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{StatusSynonyms: DefaultStatusSynonyms, MaxTransitionHops: tt.maxHops}
+
+			var got []string
+			for _, transition := range c.planPath(tt.graph, tt.from, tt.status) {
+				got = append(got, transition.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planPath = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// backend/internal/integrations/jira/users.go
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FindUserByEmail returns the active user with an email address, or nil when
+// there is none. Sites that hide email addresses still match on the query.
+func (c *Client) FindUserByEmail(email string) (*User, error) {
+	query := url.Values{}
+	query.Set("query", email)
+	resp, err := c.makeRequest("GET", "user/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error searching Jira users: %w", err)
+	}
+
+	var users []User
+	if err := json.Unmarshal(resp, &users); err != nil {
+		return nil, fmt.Errorf("error parsing Jira users: %w", err)
+	}
+
+	for i := range users {
+		if users[i].ID != "" && strings.EqualFold(users[i].EmailAddress, email) {
+			return &users[i], nil
+		}
+	}
+	// A site that hides email addresses still finds the one user by it
+	if len(users) == 1 && users[0].ID != "" && users[0].EmailAddress == "" {
+		return &users[0], nil
+	}
+	return nil, nil
+}
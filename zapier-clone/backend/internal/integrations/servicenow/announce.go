@@ -0,0 +1,52 @@
+// backend/internal/integrations/servicenow/announce.go
+package servicenow
+
+import (
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// announce posts a new record's message to every channel the notification
+// rules route it to. The first channel that accepts it is the record's home:
+// replies and its thread go there. Channels whose digest holds the item get
+// it in the next summary instead; when every channel held it, ts is empty and
+// there is no message to reply to. It fails only when no channel accepted the
+// message. build renders the message in each channel's locale.
+func announce(client *slack.Client, routes *notification.Router, item notification.Item, build func(locale string) slack.Message) (channel, ts string, err error) {
+	held := ""
+	messages := make(map[string]slack.Message)
+	for _, target := range routes.Channels(item) {
+		if routes.Hold(target, item) {
+			if held == "" {
+				held = target
+			}
+			continue
+		}
+
+		locale := routes.Locale(target)
+		message, ok := messages[locale]
+		if !ok {
+			message = build(locale)
+			messages[locale] = message
+		}
+		posted, postErr := client.PostMessage(target, message)
+		if postErr != nil {
+			client.Logger().Error("error announcing in Slack channel", "channel", target, "item_type", item.Type, "error", postErr)
+			if err == nil {
+				err = postErr
+			}
+			continue
+		}
+		if channel == "" {
+			channel, ts = target, posted
+		}
+	}
+
+	if channel != "" {
+		return channel, ts, nil
+	}
+	if held != "" && err == nil {
+		return held, "", nil
+	}
+	return "", "", err
+}
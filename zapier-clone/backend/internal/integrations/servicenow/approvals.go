@@ -0,0 +1,374 @@
+// backend/internal/integrations/servicenow/approvals.go
+package servicenow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// Approval states
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	// ApprovalLinked is a possible duplicate linked to the existing issue
+	ApprovalLinked = "linked"
+)
+
+// ErrApprovalNotFound is returned for a record with no approval request
+var ErrApprovalNotFound = errors.New("approval not found")
+
+// ErrApprovalDecided is returned when an approval that was already decided is decided again
+var ErrApprovalDecided = errors.New("approval has already been decided")
+
+// ErrNotDuplicate is returned when linking an approval that was not held as a possible duplicate
+var ErrNotDuplicate = errors.New("approval is not for a possible duplicate")
+
+// Approval is a Jira issue held back until someone approves it in Slack
+type Approval struct {
+	// ID is the sys_id of the record the issue is for
+	ID       string `json:"id"`
+	Table    string `json:"table"`
+	Number   string `json:"number"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	// Duplicate is the existing issue the record may duplicate, when that is why it is held
+	Duplicate *DuplicateMatch `json:"duplicate,omitempty"`
+	// Record is the risk or incident the issue is created from on approval
+	Record json.RawMessage `json:"record"`
+	Status string          `json:"status"`
+	// Channel and TS are the approval request; ThreadTS is the announcement it replies to
+	Channel     string     `json:"channel"`
+	TS          string     `json:"ts"`
+	ThreadTS    string     `json:"thread_ts"`
+	RequestedAt time.Time  `json:"requested_at"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	JiraKey     string     `json:"jira_key,omitempty"`
+	// Error is why the issue could not be created after approval
+	Error string `json:"error,omitempty"`
+}
+
+// ApprovalGate holds back Jira issue creation for configured tables and
+// severities until someone presses Approve on a message in the record's
+// Slack thread
+type ApprovalGate struct {
+	// Rules maps a table to the severities that need approval, lowercased;
+	// an empty list means every severity
+	Rules map[string][]string
+	// Routes gives the language of each request's channel; nil writes English
+	Routes *notification.Router
+
+	mutex     sync.Mutex
+	approvals map[string]*Approval
+	filePath  string
+	now       func() time.Time
+}
+
+// NewApprovalGate creates a gate with no rules that keeps its approvals in storagePath
+func NewApprovalGate(storagePath string) (*ApprovalGate, error) {
+	g := &ApprovalGate{
+		Rules:     map[string][]string{},
+		approvals: make(map[string]*Approval),
+		filePath:  filepath.Join(storagePath, "approvals.json"),
+		now:       time.Now,
+	}
+
+	if _, err := os.Stat(g.filePath); err == nil {
+		file, err := os.ReadFile(g.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading approval file: %w", err)
+		}
+		if err := json.Unmarshal(file, &g.approvals); err != nil {
+			return nil, fmt.Errorf("error unmarshaling approvals: %w", err)
+		}
+	}
+
+	return g, nil
+}
+
+// ConfigureFromEnv reads JIRA_APPROVAL_REQUIRED, a comma-separated list of
+// tables, each optionally limited to severities:
+// "sn_risk_risk=high|critical,sn_si_incident". Unknown tables are logged and ignored.
+func (g *ApprovalGate) ConfigureFromEnv() {
+	for _, entry := range strings.Split(os.Getenv("JIRA_APPROVAL_REQUIRED"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		table, severities, _ := strings.Cut(entry, "=")
+		table = strings.TrimSpace(table)
+		if table != "sn_risk_risk" && table != "sn_si_incident" {
+			slog.Warn("ignoring Jira approval rule for unsupported table", "table", table)
+			continue
+		}
+
+		var levels []string
+		for _, severity := range strings.Split(severities, "|") {
+			if severity = strings.ToLower(strings.TrimSpace(severity)); severity != "" {
+				levels = append(levels, severity)
+			}
+		}
+		g.Rules[table] = levels
+	}
+}
+
+// Required reports whether a record's Jira issue needs approval first
+func (g *ApprovalGate) Required(table, severity string) bool {
+	if g == nil {
+		return false
+	}
+	levels, ok := g.Rules[table]
+	if !ok {
+		return false
+	}
+	if len(levels) == 0 {
+		return true
+	}
+	for _, level := range levels {
+		if strings.EqualFold(level, severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pending reports whether a record's Jira issue is awaiting approval
+func (g *ApprovalGate) Pending(id string) bool {
+	if g == nil {
+		return false
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	approval, ok := g.approvals[id]
+	return ok && approval.Status == ApprovalPending
+}
+
+// Request posts Approve and Reject buttons in the thread of a record's
+// announcement and stores the approval. record is what the issue is created
+// from once approved.
+func (g *ApprovalGate) Request(slackClient *slack.Client, approval Approval, record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error marshaling record for approval: %w", err)
+	}
+	approval.Record = data
+	approval.Status = ApprovalPending
+	approval.RequestedAt = g.now()
+
+	ts, err := slackClient.PostReply(approval.Channel, approval.ThreadTS, g.message(approval))
+	if err != nil {
+		return fmt.Errorf("error posting Jira approval request for %s: %w", approval.Number, err)
+	}
+	approval.TS = ts
+
+	g.mutex.Lock()
+	g.approvals[approval.ID] = &approval
+	g.mutex.Unlock()
+	g.save()
+	return nil
+}
+
+// List returns approvals, newest first, optionally only those with a status
+func (g *ApprovalGate) List(status string) []*Approval {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	list := []*Approval{}
+	for _, approval := range g.approvals {
+		if status == "" || approval.Status == status {
+			list = append(list, approval)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].RequestedAt.After(list[j].RequestedAt) })
+	return list
+}
+
+// Get returns the approval of a record by sys_id
+func (g *ApprovalGate) Get(id string) (*Approval, bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	approval, ok := g.approvals[id]
+	return approval, ok
+}
+
+// Decide approves or rejects a pending approval for by. On approval, create
+// makes the Jira issue and returns its key. The request message is updated to
+// show the outcome in place of its buttons.
+func (g *ApprovalGate) Decide(slackClient *slack.Client, id string, approve bool, by string, create func(*Approval) (string, error)) (*Approval, error) {
+	g.mutex.Lock()
+	approval, ok := g.approvals[id]
+	if !ok {
+		g.mutex.Unlock()
+		return nil, ErrApprovalNotFound
+	}
+	if approval.Status != ApprovalPending {
+		g.mutex.Unlock()
+		return approval, ErrApprovalDecided
+	}
+	// Claim the approval so a second click cannot decide it again
+	approval.Status = ApprovalRejected
+	if approve {
+		approval.Status = ApprovalApproved
+	}
+	now := g.now()
+	approval.DecidedBy = by
+	approval.DecidedAt = &now
+	g.mutex.Unlock()
+
+	var err error
+	if approve {
+		var key string
+		key, err = create(approval)
+		g.mutex.Lock()
+		approval.JiraKey = key
+		if err != nil {
+			approval.Error = err.Error()
+		}
+		g.mutex.Unlock()
+	}
+	g.save()
+
+	if slackClient != nil && approval.TS != "" {
+		if updateErr := slackClient.UpdateMessage(approval.Channel, approval.TS, g.message(*approval)); updateErr != nil {
+			slackClient.Logger().Warn("error updating Jira approval request", "sys_id", id, "error", updateErr)
+		}
+	}
+	return approval, err
+}
+
+// Link settles a possible duplicate by linking the record to the existing
+// issue with link. The request message is updated like a decision.
+func (g *ApprovalGate) Link(slackClient *slack.Client, id, by string, link func(*Approval) error) (*Approval, error) {
+	g.mutex.Lock()
+	approval, ok := g.approvals[id]
+	if !ok {
+		g.mutex.Unlock()
+		return nil, ErrApprovalNotFound
+	}
+	if approval.Duplicate == nil {
+		g.mutex.Unlock()
+		return approval, ErrNotDuplicate
+	}
+	if approval.Status != ApprovalPending {
+		g.mutex.Unlock()
+		return approval, ErrApprovalDecided
+	}
+	now := g.now()
+	approval.Status = ApprovalLinked
+	approval.DecidedBy = by
+	approval.DecidedAt = &now
+	approval.JiraKey = approval.Duplicate.Key
+	g.mutex.Unlock()
+
+	err := link(approval)
+	if err != nil {
+		g.mutex.Lock()
+		approval.Error = err.Error()
+		g.mutex.Unlock()
+	}
+	g.save()
+
+	if slackClient != nil && approval.TS != "" {
+		if updateErr := slackClient.UpdateMessage(approval.Channel, approval.TS, g.message(*approval)); updateErr != nil {
+			slackClient.Logger().Warn("error updating Jira approval request", "sys_id", id, "error", updateErr)
+		}
+	}
+	return approval, err
+}
+
+// message is the request with buttons while pending, and its outcome after,
+// in the language of the request's channel
+func (g *ApprovalGate) message(approval Approval) slack.Message {
+	locale := g.Routes.Locale(approval.Channel)
+	text := i18n.T(locale, "approval.request", approval.Number, i18n.Value(locale, "severity", approval.Severity))
+	if approval.Duplicate != nil {
+		text = i18n.T(locale, "approval.duplicate",
+			approval.Number, approval.Duplicate.Key, approval.Duplicate.Summary, approval.Duplicate.Reason(locale), approval.Duplicate.Key)
+	}
+	outcome := ""
+	switch {
+	case approval.Status == ApprovalLinked && approval.Error != "":
+		outcome = i18n.T(locale, "approval.link_failed", approval.DecidedBy, approval.JiraKey, approval.Error)
+	case approval.Status == ApprovalLinked:
+		outcome = i18n.T(locale, "approval.linked", approval.JiraKey, approval.DecidedBy)
+	case approval.Status == ApprovalRejected:
+		outcome = i18n.T(locale, "approval.rejected", approval.DecidedBy)
+	case approval.Status == ApprovalApproved && approval.Error != "":
+		outcome = i18n.T(locale, "approval.create_failed", approval.DecidedBy, approval.Error)
+	case approval.Status == ApprovalApproved:
+		outcome = i18n.T(locale, "approval.approved", approval.DecidedBy, approval.JiraKey)
+	}
+
+	blocks := []slack.Block{{
+		Type: "section",
+		Text: slack.NewTextObject("mrkdwn", text, false),
+	}}
+	if outcome != "" {
+		blocks = append(blocks, slack.Block{
+			Type:     "context",
+			Elements: []interface{}{map[string]interface{}{"type": "mrkdwn", "text": outcome}},
+		})
+		return slack.Message{Text: text, Blocks: blocks}
+	}
+
+	button := func(label, verb, style string) map[string]interface{} {
+		button := map[string]interface{}{
+			"type": "button",
+			"text": map[string]interface{}{
+				"type":  "plain_text",
+				"text":  label,
+				"emoji": true,
+			},
+			"value":     fmt.Sprintf("%s_jira_%s", verb, approval.ID),
+			"action_id": verb + "_jira",
+		}
+		// Slack refuses an empty style; without one the button is plain
+		if style != "" {
+			button["style"] = style
+		}
+		return button
+	}
+	elements := []interface{}{
+		button(i18n.T(locale, "approval.button.approve"), "approve", "primary"),
+		button(i18n.T(locale, "approval.button.reject"), "reject", "danger"),
+	}
+	if approval.Duplicate != nil {
+		elements = []interface{}{
+			button(i18n.T(locale, "approval.button.link", approval.Duplicate.Key), "link", "primary"),
+			button(i18n.T(locale, "approval.button.create"), "approve", ""),
+			button(i18n.T(locale, "approval.button.reject"), "reject", "danger"),
+		}
+	}
+	blocks = append(blocks, slack.Block{
+		Type:     "actions",
+		Elements: elements,
+	})
+	return slack.Message{Text: text, Blocks: blocks}
+}
+
+// save writes approvals to disk; failures are logged
+func (g *ApprovalGate) save() {
+	g.mutex.Lock()
+	data, err := json.MarshalIndent(g.approvals, "", "  ")
+	g.mutex.Unlock()
+	if err != nil {
+		slog.Error("error marshaling approvals", "error", err)
+		return
+	}
+	if err := os.WriteFile(g.filePath, data, 0644); err != nil {
+		slog.Error("error saving approvals", "error", err)
+	}
+}
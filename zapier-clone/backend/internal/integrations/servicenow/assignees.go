@@ -0,0 +1,85 @@
+// backend/internal/integrations/servicenow/assignees.go
+package servicenow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// AssigneeNotifier @-mentions the people risks and incidents are assigned to by
+// their mapped Slack user and, when DirectMessage is on, sends them the risk's
+// message with its action buttons as a DM
+type AssigneeNotifier struct {
+	Identities Identities
+	// DirectMessage DMs each new risk assignee who has a Slack user
+	DirectMessage bool
+}
+
+// NewAssigneeNotifier creates a notifier that mentions mapped assignees
+func NewAssigneeNotifier(identities Identities) *AssigneeNotifier {
+	return &AssigneeNotifier{Identities: identities}
+}
+
+// ConfigureFromEnv turns on assignment DMs when SLACK_DM_ASSIGNEES is "true"
+func (n *AssigneeNotifier) ConfigureFromEnv() {
+	n.DirectMessage = strings.EqualFold(os.Getenv("SLACK_DM_ASSIGNEES"), "true")
+}
+
+// Mention returns "<@U…>" for an assignee with a Slack user, or the display
+// name in bold. user is the sys_id or user_name used for the lookup.
+func (n *AssigneeNotifier) Mention(ctx context.Context, user, display string) string {
+	if display == "" {
+		display = user
+	}
+	if slackUserID := n.slackUser(ctx, user); slackUserID != "" {
+		return fmt.Sprintf("<@%s>", slackUserID)
+	}
+	return fmt.Sprintf("*%s*", display)
+}
+
+// NotifyRisk sends the person a risk was assigned to its message as a DM. It
+// does nothing unless DirectMessage is on and the person has a Slack user;
+// failures are only logged, since the channel message was already posted.
+func (n *AssigneeNotifier) NotifyRisk(slackClient *slack.Client, number, user string, message slack.Message) {
+	if n == nil || !n.DirectMessage {
+		return
+	}
+	slackUserID := n.slackUser(slackClient.Context(), user)
+	if slackUserID == "" {
+		return
+	}
+
+	dm := slack.Message{
+		Text: fmt.Sprintf("You have been assigned risk %s", number),
+		Blocks: append([]slack.Block{{
+			Type: "section",
+			Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("👋 You have been assigned risk *%s*", number), false),
+		}}, message.Blocks...),
+	}
+	if _, err := slackClient.SendDirectMessage(slackUserID, dm); err != nil {
+		slackClient.Logger().Error("error sending assignment DM", "number", number, "slack_user", slackUserID, "error", err)
+	}
+}
+
+// slackUser returns the Slack user of a ServiceNow user, or "" when unknown
+func (n *AssigneeNotifier) slackUser(ctx context.Context, user string) string {
+	if n == nil || n.Identities == nil || user == "" {
+		return ""
+	}
+	return n.Identities.SlackUserID(ctx, user)
+}
+
+// assigneeID reads the sys_id of an assigned_to field, which is either a plain
+// value or a reference with a value
+func assigneeID(value interface{}) string {
+	if reference, ok := value.(map[string]interface{}); ok {
+		if id := referenceValue(reference["value"]); id != "" {
+			return id
+		}
+	}
+	return referenceValue(value)
+}
@@ -0,0 +1,66 @@
+// backend/internal/integrations/servicenow/attachments.go
+package servicenow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// Attachment is a file attached to a ServiceNow record
+type Attachment struct {
+	SysID       string `json:"sys_id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   string `json:"size_bytes,omitempty"`
+	TableName   string `json:"table_name"`
+	TableSysID  string `json:"table_sys_id"`
+}
+
+// UploadAttachment attaches a file to a record through the Attachment API,
+// which takes the file as the raw request body
+func (c *Client) UploadAttachment(table, sysID, fileName, contentType string, content []byte) (*Attachment, error) {
+	query := url.Values{}
+	query.Set("table_name", table)
+	query.Set("table_sys_id", sysID)
+	query.Set("file_name", fileName)
+	endpoint := fmt.Sprintf("%s/api/now/attachment/file?%s", c.BaseURL, query.Encode())
+
+	ctx := metrics.WithIntegration(c.Context(), metrics.IntegrationServiceNow)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.SetBasicAuth(c.Username, c.Password.Get())
+	logging.Propagate(req)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	metrics.RequestDuration.ObserveSince(start, metrics.IntegrationServiceNow, metrics.Outcome(resp, err))
+	if err != nil {
+		return nil, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("error attaching %s to %s/%s: status %d: %s", fileName, table, sysID, resp.StatusCode, body)
+	}
+
+	var response struct {
+		Result Attachment `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error decoding attachment response: %w", err)
+	}
+	return &response.Result, nil
+}
@@ -2,12 +2,16 @@
 package servicenow
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
 )
 
 // AuditFinding represents an audit finding in ServiceNow GRC
@@ -31,6 +35,21 @@ type AuditHandler struct {
 	ServiceNowClient *Client
 	SlackClient      *slack.Client
 	JiraClient       *jira.Client
+	JournalWriter    *JournalWriter
+	// Routes picks the channels new audit findings are announced in
+	Routes *notification.Router
+	// GitHub files findings as GitHub issues when a repository is configured
+	GitHub *GitHubIssues
+	// Identities maps assignees to their Jira and ServiceNow accounts when configured
+	Identities Identities
+	// Projects picks the Jira project of findings; without it they go to AUDIT
+	Projects *ProjectProvisioner
+	// Threads keeps the announcement of each finding, where remediation progress is posted
+	Threads *ThreadTracker
+	// Duplicates looks for an existing issue before one is created for a finding
+	Duplicates *DuplicateDetector
+	// Approvals holds the issue of a possible duplicate until someone decides in Slack
+	Approvals *ApprovalGate
 }
 
 // NewAuditHandler creates a new audit handler
@@ -39,106 +58,153 @@ func NewAuditHandler(serviceNowClient *Client, slackClient *slack.Client, jiraCl
 		ServiceNowClient: serviceNowClient,
 		SlackClient:      slackClient,
 		JiraClient:       jiraClient,
+		JournalWriter:    NewJournalWriter(serviceNowClient),
 	}
 }
 
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *AuditHandler) WithContext(ctx context.Context) *AuditHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	copied.JiraClient = h.JiraClient.WithContext(ctx)
+	copied.GitHub = h.GitHub.WithContext(ctx)
+	copied.Threads = h.Threads.WithContext(ctx)
+	return &copied
+}
+
 // HandleNewAuditFinding processes a new audit finding and notifies Slack
 func (h *AuditHandler) HandleNewAuditFinding(finding AuditFinding) (string, error) {
-	// Create a Slack message for the audit finding
-	message := slack.Message{
-		Blocks: []slack.Block{
-			{
-				Type: "header",
-				Text: slack.NewTextObject("plain_text", fmt.Sprintf("📌 New Finding: %s", finding.ShortDesc), true),
-			},
-			{
-				Type: "section",
-				Fields: []*slack.TextObject{
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Finding ID:*\n%s", finding.Number), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Audit:*\n%s", finding.Audit), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Severity:*\n%s", finding.Severity), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Due Date:*\n%s", finding.DueDate.Format("Jan 2, 2006")), false),
+	// Create the Slack message for the audit finding in a channel's language
+	build := func(locale string) slack.Message {
+		return slack.Message{
+			Blocks: []slack.Block{
+				{
+					Type: "header",
+					Text: slack.NewTextObject("plain_text", i18n.T(locale, "audit_finding.header", finding.ShortDesc), true),
 				},
-			},
-			{
-				Type: "section",
-				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Description:*\n%s", finding.Description), false),
-			},
-			{
-				Type: "actions",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Assign Owner",
-							"emoji": true,
-						},
-						"value":     fmt.Sprintf("assign_finding_%s", finding.ID),
-						"action_id": "assign_finding",
+				{
+					Type: "section",
+					Fields: []*slack.TextObject{
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "audit_finding.field.number", finding.Number), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "audit_finding.field.audit", finding.Audit), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.severity", i18n.Value(locale, "severity", finding.Severity)), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.due_date", i18n.Date(locale, finding.DueDate)), false),
 					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Resolve Finding",
-							"emoji": true,
+				},
+				{
+					Type: "section",
+					Text: slack.NewTextObject("mrkdwn", i18n.T(locale, "field.description", finding.Description), false),
+				},
+				{
+					Type: "actions",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.assign_owner"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("assign_finding_%s", finding.ID),
+							"action_id": "assign_finding",
 						},
-						"value":     fmt.Sprintf("resolve_finding_%s", finding.ID),
-						"action_id": "resolve_finding",
-					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "View in ServiceNow",
-							"emoji": true,
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "audit_finding.button.resolve"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("resolve_finding_%s", finding.ID),
+							"action_id": "resolve_finding",
+						},
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.view_servicenow"),
+								"emoji": true,
+							},
+							"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_audit_finding.do?sys_id=%s", h.ServiceNowClient.BaseURL, finding.ID),
+							"action_id": "view_finding",
 						},
-						"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_audit_finding.do?sys_id=%s", h.ServiceNowClient.BaseURL, finding.ID),
-						"action_id": "view_finding",
 					},
 				},
-			},
-			{
-				Type: "context",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "mrkdwn",
-						"text": "🔎 Assign an owner for resolution.",
+				{
+					Type: "context",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "mrkdwn",
+							"text": i18n.T(locale, "audit_finding.context"),
+						},
 					},
 				},
 			},
-		},
+		}
 	}
 
-	// Post the message to the audit-team channel
-	ts, err := h.SlackClient.PostMessage(slack.ChannelMapping["audit"], message)
+	// Post the message to the channels routed for the audit finding, audit-team by default
+	item := notification.Item{
+		Type:     "audit_finding",
+		Severity: finding.Severity,
+		Number:   finding.Number,
+		Title:    finding.ShortDesc,
+		URL:      fmt.Sprintf("%s/nav_to.do?uri=sn_audit_finding.do?sys_id=%s", h.ServiceNowClient.BaseURL, finding.ID),
+	}
+	channel, ts, err := announce(h.SlackClient, h.Routes, item, build)
 	if err != nil {
 		return "", fmt.Errorf("error posting audit finding message to Slack: %w", err)
 	}
+	locale := h.Routes.Locale(channel)
+	message := build(locale)
+	h.Threads.Start("sn_audit_finding", finding.ID, finding.Number, finding.State, finding.AssignedTo, channel, ts, message)
 
 	//-------------- JIRA FUNCTION CALLS ---------------------------
 
-	// Create a Jira ticket for the audit finding
-	jiraTicket, err := h.createJiraTicketForFinding(finding)
-	if err != nil {
-		// We don't want to fail the whole process if Jira creation fails
-		// Just log the error and continue
-		fmt.Printf("Error creating Jira ticket for finding %s: %s\n", finding.ID, err)
-	} else {
-		// Update the Slack message with the Jira ticket information
-		err = h.updateSlackWithJiraInfo(slack.ChannelMapping["audit"], ts, jiraTicket)
-		if err != nil {
-			fmt.Printf("Error updating Slack message with Jira info: %s\n", err)
+	// A finding that looks like an existing issue is linked to it, or held
+	// for its thread to decide, depending on the duplicate rule
+	duplicate := h.duplicateOf(finding)
+	switch {
+	case duplicate != nil && duplicate.Action == DuplicateLink:
+		if err := h.LinkDuplicate(finding.ID, finding.Number, duplicate); err != nil {
+			h.ServiceNowClient.Logger().Error("error linking finding to duplicate Jira issue", "sys_id", finding.ID, "error", err)
+		} else if ts != "" {
+			reply := slack.Message{
+				Text: i18n.T(locale, "audit_finding.duplicate_linked", h.JiraClient.BaseURL, duplicate.Key, duplicate.Key, duplicate.Reason(locale)),
+			}
+			if _, err := h.SlackClient.PostReply(channel, ts, reply); err != nil {
+				h.ServiceNowClient.Logger().Warn("error posting duplicate link to Slack", "sys_id", finding.ID, "error", err)
+			}
 		}
-
-		// Update ServiceNow with the Jira ticket ID
-		err = h.updateServiceNowWithJiraInfo(finding.ID, jiraTicket.Key)
-		if err != nil {
-			fmt.Printf("Error updating ServiceNow with Jira info: %s\n", err)
+	case duplicate != nil && ts != "" && h.Approvals != nil:
+		approval := Approval{
+			ID:        finding.ID,
+			Table:     "sn_audit_finding",
+			Number:    finding.Number,
+			Severity:  finding.Severity,
+			Title:     finding.ShortDesc,
+			Duplicate: duplicate,
+			Channel:   channel,
+			ThreadTS:  ts,
+		}
+		if err := h.Approvals.Request(h.SlackClient, approval, finding); err != nil {
+			h.ServiceNowClient.Logger().Error("error asking about duplicate Jira ticket", "sys_id", finding.ID, "error", err)
+		}
+	default:
+		// Create a Jira ticket for the audit finding
+		if _, err := h.linkJiraTicket(finding, channel, ts); err != nil {
+			// We don't want to fail the whole process if Jira creation fails
+			// Just log the error and continue
+			h.ServiceNowClient.Logger().Error("error creating Jira ticket for finding", "sys_id", finding.ID, "error", err)
 		}
 	}
 
+	// File the finding in the GitHub repository as well, when one is configured
+	if _, err := h.GitHub.FileAuditFinding(finding); err != nil {
+		h.ServiceNowClient.Logger().Error("error filing GitHub issue for finding", "sys_id", finding.ID, "error", err)
+	}
+
 	return ts, nil
 }
 
@@ -162,7 +228,7 @@ func (h *AuditHandler) HandleAuditFindingAssignment(findingID, channelID, thread
 
 	// Update ServiceNow with the assignment
 	body := map[string]string{
-		"assigned_to": assigneeID,
+		"assigned_to": serviceNowAssignee(h.ServiceNowClient.Context(), h.Identities, assigneeID),
 		"state":       "assigned",
 	}
 
@@ -201,42 +267,114 @@ func (h *AuditHandler) HandleAuditFindingResolution(findingID, channelID, thread
 	return nil
 }
 
-// ProcessAuditCommand handles slash commands for audit findings
-func (h *AuditHandler) ProcessAuditCommand(command *slack.Command) (string, error) {
-	// Handle different audit commands
-	switch {
-	case command.Command == "/resolve-finding":
-		// Format: /resolve-finding FINDING_ID Resolution notes
-		parts := strings.SplitN(command.Text, " ", 2)
-		if len(parts) < 2 {
-			return "Invalid command format. Usage: /resolve-finding FINDING_ID Resolution notes", nil
-		}
+// RegisterCommands adds the audit finding slash commands to the router
+func (h *AuditHandler) RegisterCommands(router *slack.CommandRouter) error {
+	return router.Register(slack.SlashCommand{
+		Name:        "/resolve-finding",
+		Usage:       "FINDING_ID RESOLUTION_NOTES",
+		Description: "Resolve an audit finding",
+		Args:        2,
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			findingID, resolution := args[0], args[1]
+			if err := h.HandleAuditFindingResolution(findingID, command.ChannelID, "", command.UserID, resolution); err != nil {
+				return "", fmt.Errorf("error resolving audit finding: %w", err)
+			}
 
-		findingID := parts[0]
-		resolution := parts[1]
+			// Update the Jira ticket if available
+			if err := h.updateJiraFromSlackResolution(findingID, resolution); err != nil {
+				// Log error but don't fail the whole operation
+				h.ServiceNowClient.Logger().Error("error updating Jira from Slack resolution", "sys_id", findingID, "error", err)
+			}
 
-		err := h.HandleAuditFindingResolution(findingID, command.ChannelID, "", command.UserID, resolution)
-		if err != nil {
-			return fmt.Sprintf("Error resolving audit finding: %s", err), nil
-		}
+			return "Audit finding resolved successfully!", nil
+		},
+	})
+}
 
-		// Update the Jira ticket if available
-		err = h.updateJiraFromSlackResolution(findingID, resolution)
-		if err != nil {
-			// Log error but don't fail the whole operation
-			fmt.Printf("Error updating Jira from Slack resolution: %s\n", err)
-		}
+//--------------------------- JIRA FUNCTIONS -----------------------------------------------------------------
 
-		return "Audit finding resolved successfully!", nil
+// CreateApprovedIssue creates and links the Jira ticket of a finding held as
+// a possible duplicate once someone chose a new one, returning its key
+func (h *AuditHandler) CreateApprovedIssue(approval *Approval) (string, error) {
+	var finding AuditFinding
+	if err := json.Unmarshal(approval.Record, &finding); err != nil {
+		return "", fmt.Errorf("error reading approved finding %s: %w", approval.Number, err)
+	}
 
-	default:
-		return "Unknown command", nil
+	jiraTicket, err := h.linkJiraTicket(finding, approval.Channel, approval.ThreadTS)
+	if err != nil {
+		return "", fmt.Errorf("error creating Jira ticket for finding %s: %w", approval.Number, err)
 	}
+	return jiraTicket.Key, nil
 }
 
-//--------------------------- JIRA FUNCTIONS -----------------------------------------------------------------
+// LinkDuplicate links a finding to the existing ticket it duplicates instead
+// of creating one, and notes it on the ticket
+func (h *AuditHandler) LinkDuplicate(findingID, number string, duplicate *DuplicateMatch) error {
+	if err := h.updateServiceNowWithJiraInfo(findingID, duplicate.Key); err != nil {
+		return err
+	}
+	if err := noteDuplicate(h.JiraClient, "sn_audit_finding", number, duplicate); err != nil {
+		h.ServiceNowClient.Logger().Warn("error noting duplicate on Jira issue", "issue", duplicate.Key, "sys_id", findingID, "error", err)
+	}
+	return nil
+}
+
+// duplicateOf returns the ticket a new finding may duplicate, or nil. A check
+// that fails is logged and the ticket is created as usual.
+func (h *AuditHandler) duplicateOf(finding AuditFinding) *DuplicateMatch {
+	if _, ok := h.Duplicates.Rule("sn_audit_finding"); !ok {
+		return nil
+	}
+	duplicate, err := h.Duplicates.Find(h.JiraClient, "sn_audit_finding", finding.Number, h.findingTicket(finding))
+	if err != nil {
+		h.ServiceNowClient.Logger().Warn("error checking for duplicate Jira issues", "sys_id", finding.ID, "error", err)
+		return nil
+	}
+	return duplicate
+}
+
+// linkJiraTicket creates a finding's Jira ticket, replies with it in the
+// finding's thread and records it on the finding. Failures after the ticket
+// exists are only logged.
+func (h *AuditHandler) linkJiraTicket(finding AuditFinding, channel, ts string) (*jira.Ticket, error) {
+	jiraTicket, err := h.createJiraTicketForFinding(finding)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the Slack message with the Jira ticket information
+	err = h.updateSlackWithJiraInfo(channel, ts, jiraTicket)
+	if err != nil {
+		h.ServiceNowClient.Logger().Warn("error updating Slack message with Jira info", "sys_id", finding.ID, "issue", jiraTicket.Key, "error", err)
+	}
+
+	// Update ServiceNow with the Jira ticket ID
+	err = h.updateServiceNowWithJiraInfo(finding.ID, jiraTicket.Key)
+	if err != nil {
+		h.ServiceNowClient.Logger().Error("error updating ServiceNow with Jira info", "sys_id", finding.ID, "issue", jiraTicket.Key, "error", err)
+	}
+	return jiraTicket, nil
+}
 
 func (h *AuditHandler) createJiraTicketForFinding(finding AuditFinding) (*jira.Ticket, error) {
+	ticket := h.findingTicket(finding)
+
+	// Log the attempt to create a Jira ticket
+	h.ServiceNowClient.Logger().Info("creating Jira ticket for finding", "number", finding.Number, "title", finding.ShortDesc)
+
+	// Create the ticket in Jira
+	createdTicket, err := h.JiraClient.CreateIssue(ticket)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Jira ticket: %w", err)
+	}
+
+	h.ServiceNowClient.Logger().Info("created Jira ticket for finding", "issue", createdTicket.Key, "number", finding.Number)
+	return createdTicket, nil
+}
+
+// findingTicket builds the Jira ticket of a finding
+func (h *AuditHandler) findingTicket(finding AuditFinding) *jira.Ticket {
 	// Create labels for better organization and filtering in Jira
 	labels := []string{"audit-finding", strings.ToLower(finding.Severity)}
 	if finding.Audit != "" {
@@ -269,30 +407,20 @@ _This ticket was automatically created from a ServiceNow audit finding. Updates
 
 	// Create a new Jira ticket
 	ticket := &jira.Ticket{
-		Project:     "AUDIT", // Configure your Jira project key
 		IssueType:   "Audit Finding",
 		Summary:     fmt.Sprintf("[%s] %s", finding.Number, finding.ShortDesc),
 		Description: description,
 		Priority:    mapSeverityToPriority(finding.Severity),
 		DueDate:     finding.DueDate,
 		Labels:      labels,
+		AssigneeID:  jiraAccount(h.ServiceNowClient.Context(), h.Identities, finding.AssignedTo),
 		Fields: map[string]interface{}{
-			"customfield_servicenow_id": finding.ID,    // Custom field to store ServiceNow ID
-			"customfield_audit_name":    finding.Audit, // Additional custom field to make searching easier
+			h.JiraClient.ServiceNowIDField(): finding.ID,    // Custom field to store ServiceNow ID
+			"customfield_audit_name":         finding.Audit, // Additional custom field to make searching easier
 		},
 	}
-
-	// Log the attempt to create a Jira ticket
-	fmt.Printf("Creating Jira ticket for finding %s (%s)\n", finding.Number, finding.ShortDesc)
-
-	// Create the ticket in Jira
-	createdTicket, err := h.JiraClient.CreateIssue(ticket)
-	if err != nil {
-		return nil, fmt.Errorf("error creating Jira ticket: %w", err)
-	}
-
-	fmt.Printf("Successfully created Jira ticket %s for finding %s\n", createdTicket.Key, finding.Number)
-	return createdTicket, nil
+	h.Projects.Route("sn_audit_finding", ticket, "AUDIT")
+	return ticket
 }
 
 // updateJiraFromSlackResolution updates the Jira ticket when a finding is resolved from Slack
@@ -341,6 +469,11 @@ func mapSeverityToPriority(severity string) string {
 
 // updateSlackWithJiraInfo updates the Slack message with Jira ticket information
 func (h *AuditHandler) updateSlackWithJiraInfo(channel, threadTS string, jiraTicket *jira.Ticket) error {
+	// A finding held for the digest has no message to reply to
+	if threadTS == "" {
+		return nil
+	}
+
 	// Create a reply with Jira information
 	message := slack.Message{
 		Text: fmt.Sprintf("📎 Jira ticket created: <%s/browse/%s|%s>",
@@ -374,8 +507,8 @@ func (h *AuditHandler) updateServiceNowWithJiraInfo(findingID, jiraKey string) e
 // HandleJiraUpdate processes updates from Jira and syncs them to ServiceNow
 func (h *AuditHandler) HandleJiraUpdate(jiraEvent *jira.WebhookEvent) error {
 	// Get the ServiceNow ID from the custom field
-	servicenowID, ok := jiraEvent.Issue.Fields.CustomFields["customfield_servicenow_id"].(string)
-	if !ok || servicenowID == "" {
+	servicenowID := jiraEvent.Issue.ServiceNowID()
+	if servicenowID == "" {
 		return fmt.Errorf("no ServiceNow ID found in Jira ticket")
 	}
 
@@ -389,7 +522,7 @@ func (h *AuditHandler) HandleJiraUpdate(jiraEvent *jira.WebhookEvent) error {
 	// Get the comment if this was a comment event
 	comment := ""
 	if jiraEvent.Comment != nil {
-		comment = jiraEvent.Comment.Body
+		comment = string(jiraEvent.Comment.Body)
 	}
 
 	// Map Jira status to ServiceNow state
@@ -422,16 +555,13 @@ func (h *AuditHandler) HandleJiraUpdate(jiraEvent *jira.WebhookEvent) error {
 		body["resolution"] = servicenowResolution
 	}
 
-	// Also add any comments to ServiceNow audit log
-	if comment != "" {
-		body["work_notes"] = fmt.Sprintf("Update from Jira: %s", comment)
-	}
-
 	_, err := h.ServiceNowClient.makeRequest("PATCH", fmt.Sprintf("api/now/table/sn_audit_finding/%s", servicenowID), body)
 	if err != nil {
 		return fmt.Errorf("error updating ServiceNow from Jira update: %w", err)
 	}
 
+	// Comments themselves are copied to work notes by CommentSync
+
 	// If the status changed, post an update to Slack as well
 	if servicenowState != "" {
 		// You would need to retrieve the original Slack thread details from a database
@@ -452,7 +582,7 @@ func (h *AuditHandler) HandleJiraUpdate(jiraEvent *jira.WebhookEvent) error {
 
 			_, err := h.SlackClient.PostReply(channelID, threadTS, message)
 			if err != nil {
-				fmt.Printf("Error posting Jira update to Slack: %s\n", err)
+				h.ServiceNowClient.Logger().Warn("error posting Jira update to Slack", "error", err)
 			}
 		}
 	}
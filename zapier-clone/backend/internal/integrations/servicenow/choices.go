@@ -0,0 +1,307 @@
+// backend/internal/integrations/servicenow/choices.go
+package servicenow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Choice is one entry of a ServiceNow choice list (sys_choice)
+type Choice struct {
+	Element  string `json:"element"`
+	Value    string `json:"value"`
+	Label    string `json:"label"`
+	Sequence int    `json:"sequence"`
+}
+
+// InvalidChoiceError is returned when a value is not in the field's choice list
+type InvalidChoiceError struct {
+	Table       string
+	Field       string
+	Value       string
+	Suggestions []string
+}
+
+func (e *InvalidChoiceError) Error() string {
+	msg := fmt.Sprintf("invalid value %q for %s.%s", e.Value, e.Table, e.Field)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (did you mean %s?)", quoteJoin(e.Suggestions))
+	}
+	return msg
+}
+
+// tableChoices is the cached choice list of one table, keyed by field
+type tableChoices struct {
+	Fields   map[string][]Choice
+	SyncedAt time.Time
+}
+
+// ChoiceCache keeps a local copy of ServiceNow choice lists so outbound values
+// can be validated before they are written
+type ChoiceCache struct {
+	Client *Client
+	// Tables are synced on Start and every RefreshInterval afterwards
+	Tables          []string
+	RefreshInterval time.Duration
+
+	mutex    sync.RWMutex
+	tables   map[string]*tableChoices
+	stopChan chan struct{}
+	running  bool
+}
+
+// NewChoiceCache creates a choice cache for the given tables
+func NewChoiceCache(client *Client, tables ...string) *ChoiceCache {
+	return &ChoiceCache{
+		Client:          client,
+		Tables:          tables,
+		RefreshInterval: 6 * time.Hour,
+		tables:          make(map[string]*tableChoices),
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// Start syncs all tables and keeps them refreshed in the background
+func (c *ChoiceCache) Start() {
+	if c.running {
+		return
+	}
+	c.running = true
+
+	go func() {
+		c.SyncAll()
+
+		ticker := time.NewTicker(c.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.stopChan:
+				return
+			case <-ticker.C:
+				c.SyncAll()
+			}
+		}
+	}()
+}
+
+// Stop stops the background refresh
+func (c *ChoiceCache) Stop() {
+	if !c.running {
+		return
+	}
+	c.running = false
+	close(c.stopChan)
+}
+
+// SyncAll syncs every configured table, logging failures
+func (c *ChoiceCache) SyncAll() {
+	for _, table := range c.Tables {
+		if err := c.Sync(table); err != nil {
+			c.Client.Logger().Warn("could not sync choice list", "table", table, "error", err)
+		}
+	}
+}
+
+// Sync fetches the active choice list of a table from sys_choice
+func (c *ChoiceCache) Sync(table string) error {
+	query := url.Values{}
+	query.Set("sysparm_query", fmt.Sprintf("name=%s^inactive=false^ORDERBYsequence", table))
+	query.Set("sysparm_fields", "element,value,label,sequence")
+
+	resp, err := c.Client.makeRequest("GET", "api/now/table/sys_choice?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result []struct {
+			Element  string `json:"element"`
+			Value    string `json:"value"`
+			Label    string `json:"label"`
+			Sequence string `json:"sequence"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+
+	choices := &tableChoices{
+		Fields:   make(map[string][]Choice),
+		SyncedAt: time.Now(),
+	}
+	for _, r := range response.Result {
+		sequence, _ := strconv.Atoi(r.Sequence)
+		choices.Fields[r.Element] = append(choices.Fields[r.Element], Choice{
+			Element:  r.Element,
+			Value:    r.Value,
+			Label:    r.Label,
+			Sequence: sequence,
+		})
+	}
+	for _, list := range choices.Fields {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Sequence < list[j].Sequence })
+	}
+
+	c.mutex.Lock()
+	c.tables[table] = choices
+	c.mutex.Unlock()
+
+	return nil
+}
+
+// Choices returns the cached choice list for a field
+func (c *ChoiceCache) Choices(table, field string) []Choice {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	choices, ok := c.tables[table]
+	if !ok {
+		return nil
+	}
+	return choices.Fields[field]
+}
+
+// TableChoices returns all cached choice lists of a table and when they were synced
+func (c *ChoiceCache) TableChoices(table string) (map[string][]Choice, time.Time, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	choices, ok := c.tables[table]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	return choices.Fields, choices.SyncedAt, true
+}
+
+// Validate checks a value against the field's choice list. Fields without a
+// cached choice list are not validated.
+func (c *ChoiceCache) Validate(table, field, value string) error {
+	if c == nil {
+		return nil
+	}
+
+	choices := c.Choices(table, field)
+	if len(choices) == 0 {
+		return nil
+	}
+
+	for _, choice := range choices {
+		if choice.Value == value {
+			return nil
+		}
+	}
+
+	return &InvalidChoiceError{
+		Table:       table,
+		Field:       field,
+		Value:       value,
+		Suggestions: suggestChoices(value, choices),
+	}
+}
+
+// ValidateRecord checks every string field of an outbound record
+func (c *ChoiceCache) ValidateRecord(table string, data map[string]interface{}) error {
+	if c == nil {
+		return nil
+	}
+
+	for field, v := range data {
+		value, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if err := c.Validate(table, field, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// suggestChoices returns up to three choice values closest to the given value
+func suggestChoices(value string, choices []Choice) []string {
+	type candidate struct {
+		value    string
+		distance int
+	}
+
+	needle := strings.ToLower(strings.TrimSpace(value))
+	var candidates []candidate
+	for _, choice := range choices {
+		distance := levenshtein(needle, strings.ToLower(choice.Value))
+		if d := levenshtein(needle, strings.ToLower(choice.Label)); d < distance {
+			distance = d
+		}
+
+		// Allow roughly one typo per three characters
+		limit := len(needle) / 3
+		if limit < 2 {
+			limit = 2
+		}
+		if distance <= limit {
+			candidates = append(candidates, candidate{value: choice.Value, distance: distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		suggestions = append(suggestions, candidates[i].value)
+	}
+	return suggestions
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
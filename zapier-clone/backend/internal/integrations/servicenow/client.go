@@ -3,67 +3,127 @@ package servicenow
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
 )
 
+// ErrRecordNotFound is returned when a ServiceNow record does not exist
+var ErrRecordNotFound = errors.New("record not found")
+
 // Client represents a ServiceNow GRC API client
 type Client struct {
-	BaseURL    string
-	Username   string
-	Password   string
+	BaseURL  string
+	Username string
+	// Password is replaced in place when the secret is rotated
+	Password   *common.Secret
 	HTTPClient *http.Client
+	// Choices validates outbound choice field values when set
+	Choices *ChoiceCache
+	// DataDir holds the record mapping files for this instance
+	DataDir string
+
+	// ctx bounds API calls made through a WithContext copy
+	ctx context.Context
 }
 
 // NewClient creates a new ServiceNow GRC client
 func NewClient(baseURL, username, password string) *Client {
 	return &Client{
-		BaseURL:  baseURL,
-		Username: username,
-		Password: password,
-		HTTPClient: &http.Client{
-			Timeout: time.Second * 30,
-		},
+		BaseURL:    baseURL,
+		Username:   username,
+		Password:   common.NewSecret(password),
+		HTTPClient: common.NewHTTPClient(30 * time.Second),
+		DataDir:    "./data",
 	}
 }
 
+// WithContext returns a copy of the client whose API calls are cancelled with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// Context returns the context API calls run under
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// Logger returns the logger for work done with this client, tagged with the
+// correlation ID of its context
+func (c *Client) Logger() *slog.Logger {
+	return logging.FromContext(c.Context())
+}
+
 // makeRequest performs an HTTP request to the ServiceNow API
 func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.Response, error) {
 	url := fmt.Sprintf("%s/%s", c.BaseURL, endpoint)
 
 	var req *http.Request
 	var err error
+	ctx := metrics.WithIntegration(c.Context(), metrics.IntegrationServiceNow)
 
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %w", err)
 		}
-		req, err = http.NewRequest(method, url, bytes.NewBuffer(jsonBody))
+		req, err = http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(jsonBody))
 		if err != nil {
 			return nil, fmt.Errorf("error creating request: %w", err)
 		}
 	} else {
-		req, err = http.NewRequest(method, url, nil)
+		req, err = http.NewRequestWithContext(ctx, method, url, nil)
 		if err != nil {
 			return nil, fmt.Errorf("error creating request: %w", err)
 		}
 	}
 
-	req.SetBasicAuth(c.Username, c.Password)
+	req.SetBasicAuth(c.Username, c.Password.Get())
+	logging.Propagate(req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
+	start := time.Now()
 	resp, err := c.HTTPClient.Do(req)
+	metrics.RequestDuration.ObserveSince(start, metrics.IntegrationServiceNow, metrics.Outcome(resp, err))
 	if err != nil {
 		return nil, fmt.Errorf("error executing request: %w", err)
 	}
+	recordWrite(method, endpoint, resp)
 
 	return resp, nil
 }
 
+// recordWrite counts a successful Table API write for metrics
+func recordWrite(method, endpoint string, resp *http.Response) {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || !strings.HasPrefix(endpoint, "api/now/table/") {
+		return
+	}
+	table := strings.TrimPrefix(endpoint, "api/now/table/")
+	table = strings.SplitN(strings.SplitN(table, "?", 2)[0], "/", 2)[0]
+
+	switch method {
+	case "POST":
+		metrics.ServiceNowUpdates.Inc(table, "created")
+	case "PATCH", "PUT":
+		metrics.ServiceNowUpdates.Inc(table, "updated")
+	}
+}
+
 // GetRisks fetches risks from ServiceNow GRC
 func (c *Client) GetRisks() ([]Risk, error) {
 	resp, err := c.makeRequest("GET", "api/now/table/sn_risk_risk", nil)
@@ -130,8 +190,34 @@ func (c *Client) GetIncidents() ([]Incident, error) {
 	return response.Result, nil
 }
 
+// GetControlTests fetches control tests from ServiceNow GRC
+func (c *Client) GetControlTests() ([]ControlTest, error) {
+	resp, err := c.makeRequest("GET", "api/now/table/sn_policy_control_test", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result []ControlTest `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return response.Result, nil
+}
+
 // UpdateRiskStatus updates the status of a risk in ServiceNow GRC
 func (c *Client) UpdateRiskStatus(riskID, status string) error {
+	if err := c.Choices.Validate("sn_risk_risk", "state", status); err != nil {
+		return err
+	}
+
 	body := map[string]string{
 		"state": status,
 	}
@@ -151,6 +237,10 @@ func (c *Client) UpdateRiskStatus(riskID, status string) error {
 
 // UpdateComplianceTaskStatus updates the status of a compliance task
 func (c *Client) UpdateComplianceTaskStatus(taskID, status string) error {
+	if err := c.Choices.Validate("sn_compliance_task", "state", status); err != nil {
+		return err
+	}
+
 	body := map[string]string{
 		"state": status,
 	}
@@ -170,6 +260,10 @@ func (c *Client) UpdateComplianceTaskStatus(taskID, status string) error {
 
 // UpdateIncidentStatus updates the status of a security incident
 func (c *Client) UpdateIncidentStatus(incidentID, status string) error {
+	if err := c.Choices.Validate("sn_si_incident", "state", status); err != nil {
+		return err
+	}
+
 	body := map[string]string{
 		"state": status,
 	}
@@ -187,10 +281,88 @@ func (c *Client) UpdateIncidentStatus(incidentID, status string) error {
 	return nil
 }
 
-// AttachEvidenceToComplianceTask attaches evidence to a compliance task
-func (c *Client) AttachEvidenceToComplianceTask(taskID, fileName, fileContent string) error {
-	// In a real implementation, this would handle file uploads with multipart/form-data
-	// For simplicity, we're just simulating success
+// CreateRecord inserts a new record into the given ServiceNow table
+func (c *Client) CreateRecord(table string, data map[string]interface{}) (map[string]interface{}, error) {
+	if err := c.Choices.ValidateRecord(table, data); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.makeRequest("POST", fmt.Sprintf("api/now/table/%s", table), data)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result map[string]interface{} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return response.Result, nil
+}
+
+// GetRecord fetches a single record from the given ServiceNow table
+func (c *Client) GetRecord(table, sysID string) (map[string]interface{}, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("api/now/table/%s/%s", table, sysID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrRecordNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result map[string]interface{} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return response.Result, nil
+}
+
+// QueryRecords fetches up to limit records of a table matching an encoded query
+func (c *Client) QueryRecords(table, query string, limit int) ([]map[string]interface{}, error) {
+	return c.QueryRecordsPage(table, query, limit, 0)
+}
+
+// QueryRecordsPage fetches up to limit records starting at offset. The query
+// should include an ORDERBY so pages do not overlap. Records iterates over
+// every page instead.
+func (c *Client) QueryRecordsPage(table, query string, limit, offset int) ([]map[string]interface{}, error) {
+	return c.ListRecords(table, ListOptions{Query: query, PageSize: limit, Offset: offset})
+}
+
+// UpdateRecord patches fields on a record in the given ServiceNow table
+func (c *Client) UpdateRecord(table, sysID string, data map[string]interface{}) error {
+	if err := c.Choices.ValidateRecord(table, data); err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest("PATCH", fmt.Sprintf("api/now/table/%s/%s", table, sysID), data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrRecordNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,190 @@
+// backend/internal/integrations/servicenow/comment_sync.go
+package servicenow
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+)
+
+// Markers appended to propagated comments. A comment carrying either marker was
+// written by the integration, so it is never propagated again.
+const (
+	commentMarkerFromJira       = "[synced-from-jira:"
+	commentMarkerFromServiceNow = "[synced-from-servicenow:"
+)
+
+// CommentSync propagates comments between Jira issues and the ServiceNow records they
+// are linked to: Jira comments become work notes, and work notes and additional
+// comments become Jira comments.
+type CommentSync struct {
+	ServiceNowClient    *Client
+	JiraClient          *jira.Client
+	JournalWriter       *JournalWriter
+	RiskJiraMapping     jira.MappingStore
+	IncidentJiraMapping *jira.IncidentJiraMapping
+}
+
+// NewCommentSync creates a comment sync using the risk and incident mappings to find linked records
+func NewCommentSync(serviceNowClient *Client, jiraClient *jira.Client, riskMapping jira.MappingStore, incidentMapping *jira.IncidentJiraMapping) *CommentSync {
+	return &CommentSync{
+		ServiceNowClient:    serviceNowClient,
+		JiraClient:          jiraClient,
+		JournalWriter:       NewJournalWriter(serviceNowClient),
+		RiskJiraMapping:     riskMapping,
+		IncidentJiraMapping: incidentMapping,
+	}
+}
+
+// WithContext returns a copy of the comment sync whose API calls run under ctx
+func (s *CommentSync) WithContext(ctx context.Context) *CommentSync {
+	copied := *s
+	copied.ServiceNowClient = s.ServiceNowClient.WithContext(ctx)
+	copied.JiraClient = s.JiraClient.WithContext(ctx)
+	return &copied
+}
+
+// IsSyncedComment reports whether text was written by the integration
+func IsSyncedComment(text string) bool {
+	return strings.Contains(text, commentMarkerFromJira) ||
+		strings.Contains(text, commentMarkerFromServiceNow) ||
+		strings.Contains(text, journalMarkerPrefix)
+}
+
+// HandleJiraComment writes a Jira comment to the linked ServiceNow record as a work note
+func (s *CommentSync) HandleJiraComment(event *jira.WebhookEvent) error {
+	if event.Issue == nil || event.Comment == nil {
+		return nil
+	}
+
+	comment := event.Comment
+	if event.WebhookEvent == "comment_deleted" {
+		s.ServiceNowClient.Logger().Info("Jira comment deleted; ServiceNow work notes are append-only, leaving it", "comment", comment.ID, "issue", event.Issue.Key)
+		return nil
+	}
+	if IsSyncedComment(string(comment.Body)) {
+		return nil
+	}
+
+	table, sysID := LinkedRecord(event.Issue, s.RiskJiraMapping, s.IncidentJiraMapping)
+	if sysID == "" {
+		return nil
+	}
+
+	author := "Unknown"
+	if comment.Author != nil && comment.Author.DisplayName != "" {
+		author = comment.Author.DisplayName
+	}
+
+	heading := fmt.Sprintf("Jira comment by %s on %s:", author, event.Issue.Key)
+	if event.WebhookEvent == "comment_updated" {
+		heading = fmt.Sprintf("Jira comment by %s on %s (edited):", author, event.Issue.Key)
+	}
+	text := fmt.Sprintf("%s\n%s\n\n%s%s]", heading, strings.TrimSpace(string(comment.Body)), commentMarkerFromJira, comment.ID)
+
+	// Keyed on the edit time so each edit is written once, however often the webhook is retried
+	key := JournalKey("jira-comment", event.Issue.Key, comment.ID, comment.Updated)
+	if err := s.JournalWriter.Write(table, sysID, JournalWorkNotes, key, text); err != nil {
+		return fmt.Errorf("error syncing Jira comment %s to %s/%s: %w", comment.ID, table, sysID, err)
+	}
+
+	s.ServiceNowClient.Logger().Info("synced Jira comment", "comment", comment.ID, "issue", event.Issue.Key, "table", table, "sys_id", sysID)
+	return nil
+}
+
+// HandleRecordUpdate posts new work notes and additional comments from a ServiceNow
+// update webhook to the linked Jira issue
+func (s *CommentSync) HandleRecordUpdate(payload WebhookPayload) error {
+	jiraKey := s.linkedJiraKey(payload)
+	if jiraKey == "" {
+		return nil
+	}
+
+	author, _ := payload.Data["sys_updated_by"].(string)
+	if author == "" {
+		author = "ServiceNow"
+	}
+	number, _ := payload.Data["number"].(string)
+	if number == "" {
+		number = payload.ID
+	}
+
+	for _, field := range []string{JournalWorkNotes, JournalComments} {
+		text, _ := payload.Data[field].(string)
+		text = strings.TrimSpace(text)
+		if text == "" || IsSyncedComment(text) {
+			continue
+		}
+
+		label := "work note"
+		if field == JournalComments {
+			label = "comment"
+		}
+
+		sum := sha1.Sum([]byte(payload.ID + "\x00" + field + "\x00" + text))
+		marker := hex.EncodeToString(sum[:])[:12]
+		body := fmt.Sprintf("ServiceNow %s by %s on %s:\n%s\n\n%s%s]", label, author, number, text, commentMarkerFromServiceNow, marker)
+
+		if err := s.JiraClient.AddComment(jiraKey, body); err != nil {
+			return fmt.Errorf("error syncing ServiceNow %s on %s to %s: %w", label, payload.ID, jiraKey, err)
+		}
+		s.ServiceNowClient.Logger().Info("synced ServiceNow journal entry", "field", label, "table", payload.TableName, "sys_id", payload.ID, "issue", jiraKey)
+	}
+
+	return nil
+}
+
+// linkedJiraKey finds the Jira issue linked to the record in a webhook payload
+func (s *CommentSync) linkedJiraKey(payload WebhookPayload) string {
+	return LinkedIssue(payload, s.RiskJiraMapping, s.IncidentJiraMapping)
+}
+
+// LinkedIssue finds the Jira issue linked to the record in a webhook payload,
+// checking the stored mappings before the record's jira_ticket field
+func LinkedIssue(payload WebhookPayload, risks jira.MappingStore, incidents *jira.IncidentJiraMapping) string {
+	switch payload.TableName {
+	case "sn_risk_risk":
+		if risks != nil {
+			if key, ok := risks.GetJiraKeyFromRiskID(payload.ID); ok {
+				return key
+			}
+		}
+	case "sn_si_incident":
+		if incidents != nil {
+			if key, ok := incidents.GetJiraKeyFromIncidentID(payload.ID); ok {
+				return key
+			}
+		}
+	}
+
+	key, _ := payload.Data["jira_ticket"].(string)
+	return key
+}
+
+// LinkedRecord finds the ServiceNow record a Jira issue was synced from, checking the
+// stored mappings before the ServiceNow link fields on the issue
+func LinkedRecord(issue *jira.WebhookIssue, risks jira.MappingStore, incidents *jira.IncidentJiraMapping) (string, string) {
+	if risks != nil {
+		if riskID, ok := risks.GetRiskIDFromJiraKey(issue.Key); ok {
+			return "sn_risk_risk", riskID
+		}
+	}
+	if incidents != nil {
+		if incidentID, ok := incidents.GetIncidentIDFromJiraKey(issue.Key); ok {
+			return "sn_si_incident", incidentID
+		}
+	}
+
+	sysID := issue.ServiceNowID()
+	table, _ := issue.Fields.CustomFields["customfield_servicenow_table"].(string)
+	if table == "" {
+		// Issues carrying only the sys_id are created from audit findings
+		table = "sn_audit_finding"
+	}
+
+	return table, sysID
+}
@@ -2,16 +2,26 @@
 package servicenow
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
 )
 
 // ComplianceTaskHandler handles compliance task notifications and interactions
 type ComplianceTaskHandler struct {
 	ServiceNowClient *Client
 	SlackClient      *slack.Client
+	// Routes picks the channels new compliance tasks are announced in
+	Routes *notification.Router
+	// GitHub files tasks as GitHub issues when a repository is configured
+	GitHub *GitHubIssues
+	// Identities maps Slack users to their ServiceNow accounts when configured
+	Identities Identities
+	// Evidence handles /upload-evidence; without it the command is unavailable
+	Evidence *EvidenceUploader
 }
 
 // NewComplianceTaskHandler creates a new compliance task handler
@@ -22,81 +32,105 @@ func NewComplianceTaskHandler(serviceNowClient *Client, slackClient *slack.Clien
 	}
 }
 
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *ComplianceTaskHandler) WithContext(ctx context.Context) *ComplianceTaskHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	copied.GitHub = h.GitHub.WithContext(ctx)
+	copied.Evidence = h.Evidence.WithContext(ctx)
+	return &copied
+}
+
 // HandleNewComplianceTask processes a new compliance task and notifies Slack
 func (h *ComplianceTaskHandler) HandleNewComplianceTask(task ComplianceTask) (string, error) {
-	// Create a Slack message for the compliance task
-	message := slack.Message{
-		Blocks: []slack.Block{
-			{
-				Type: "header",
-				Text: slack.NewTextObject("plain_text", fmt.Sprintf("📝 Compliance Task: %s", task.ShortDesc), true),
-			},
-			{
-				Type: "section",
-				Fields: []*slack.TextObject{
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Task ID:*\n%s", task.Number), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Framework:*\n%s", task.Framework), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Regulation:*\n%s", task.Regulation), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Due Date:*\n%s", task.DueDate.Format("Jan 2, 2006")), false),
+	// Create the Slack message for the compliance task in a channel's language
+	build := func(locale string) slack.Message {
+		return slack.Message{
+			Blocks: []slack.Block{
+				{
+					Type: "header",
+					Text: slack.NewTextObject("plain_text", i18n.T(locale, "compliance_task.header", task.ShortDesc), true),
 				},
-			},
-			{
-				Type: "section",
-				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Description:*\n%s", task.Description), false),
-			},
-			{
-				Type: "actions",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Upload Evidence",
-							"emoji": true,
-						},
-						"value":     fmt.Sprintf("upload_evidence_%s", task.ID),
-						"action_id": "upload_evidence",
+				{
+					Type: "section",
+					Fields: []*slack.TextObject{
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "compliance_task.field.number", task.Number), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "compliance_task.field.framework", task.Framework), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "compliance_task.field.regulation", task.Regulation), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.due_date", i18n.Date(locale, task.DueDate)), false),
 					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Assign Owner",
-							"emoji": true,
+				},
+				{
+					Type: "section",
+					Text: slack.NewTextObject("mrkdwn", i18n.T(locale, "field.description", task.Description), false),
+				},
+				{
+					Type: "actions",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "compliance_task.button.evidence"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("upload_evidence_%s", task.ID),
+							"action_id": "upload_evidence",
 						},
-						"value":     fmt.Sprintf("assign_task_%s", task.ID),
-						"action_id": "assign_task",
-					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "View in ServiceNow",
-							"emoji": true,
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.assign_owner"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("assign_task_%s", task.ID),
+							"action_id": "assign_task",
+						},
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.view_servicenow"),
+								"emoji": true,
+							},
+							"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_compliance_task.do?sys_id=%s", h.ServiceNowClient.BaseURL, task.ID),
+							"action_id": "view_task",
 						},
-						"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_compliance_task.do?sys_id=%s", h.ServiceNowClient.BaseURL, task.ID),
-						"action_id": "view_task",
 					},
 				},
-			},
-			{
-				Type: "context",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "mrkdwn",
-						"text": fmt.Sprintf("📅 This compliance task is due by *%s*.", task.DueDate.Format("Jan 2, 2006")),
+				{
+					Type: "context",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "mrkdwn",
+							"text": i18n.T(locale, "compliance_task.context", i18n.Date(locale, task.DueDate)),
+						},
 					},
 				},
 			},
-		},
+		}
 	}
 
-	// Post the message to the compliance-team channel
-	ts, err := h.SlackClient.PostMessage(slack.ChannelMapping["compliance"], message)
+	// Post the message to the channels routed for the compliance task, compliance-team by default
+	item := notification.Item{
+		Type:     "compliance_task",
+		Category: task.Framework,
+		Number:   task.Number,
+		Title:    task.ShortDesc,
+		URL:      fmt.Sprintf("%s/nav_to.do?uri=sn_compliance_task.do?sys_id=%s", h.ServiceNowClient.BaseURL, task.ID),
+	}
+	_, ts, err := announce(h.SlackClient, h.Routes, item, build)
 	if err != nil {
 		return "", fmt.Errorf("error posting compliance task message to Slack: %w", err)
 	}
 
+	// File the task in the GitHub repository as well, when one is configured
+	if _, err := h.GitHub.FileComplianceTask(task); err != nil {
+		h.ServiceNowClient.Logger().Error("error filing GitHub issue for compliance task", "sys_id", task.ID, "error", err)
+	}
+
 	return ts, nil
 }
 
@@ -141,7 +175,7 @@ func (h *ComplianceTaskHandler) HandleComplianceTaskAssignment(taskID, channelID
 
 	// Update ServiceNow with the assignment
 	body := map[string]string{
-		"assigned_to": assigneeID,
+		"assigned_to": serviceNowAssignee(h.ServiceNowClient.Context(), h.Identities, assigneeID),
 	}
 
 	_, err = h.ServiceNowClient.makeRequest("PATCH", fmt.Sprintf("api/now/table/sn_compliance_task/%s", taskID), body)
@@ -152,64 +186,18 @@ func (h *ComplianceTaskHandler) HandleComplianceTaskAssignment(taskID, channelID
 	return nil
 }
 
-// HandleEvidenceUpload processes evidence uploads for compliance tasks
-func (h *ComplianceTaskHandler) HandleEvidenceUpload(taskID, channelID, threadTS, userID, fileName, fileContent string) error {
-	// Upload the file to Slack
-	_, err := h.SlackClient.UploadFile(channelID, fileName, fileContent)
-	if err != nil {
-		return fmt.Errorf("error uploading evidence to Slack: %w", err)
-	}
-
-	// Post a notification about the evidence upload
-	message := slack.Message{
-		Text: fmt.Sprintf("<@%s> uploaded evidence: `%s`", userID, fileName),
-	}
-
-	// Post the reply to the thread
-	_, err = h.SlackClient.PostReply(channelID, threadTS, message)
-	if err != nil {
-		return fmt.Errorf("error posting evidence upload notification to Slack thread: %w", err)
-	}
-
-	// Attach the evidence to the compliance task in ServiceNow
-	err = h.ServiceNowClient.AttachEvidenceToComplianceTask(taskID, fileName, fileContent)
-	if err != nil {
-		return fmt.Errorf("error attaching evidence to compliance task in ServiceNow: %w", err)
-	}
-
-	return nil
-}
-
-// ProcessComplianceTaskCommand handles slash commands for compliance tasks
-func (h *ComplianceTaskHandler) ProcessComplianceTaskCommand(command *slack.Command) (string, error) {
-	// Handle different compliance task commands
-	switch {
-	case command.Command == "/upload-evidence":
-		// Format: /upload-evidence TASK_ID EVIDENCE_URL
-		// This is a simplified version - in reality, you'd handle file uploads differently
-		parts := splitCommand(command.Text)
-		if len(parts) < 2 {
-			return "Invalid command format. Usage: /upload-evidence TASK_ID EVIDENCE_URL", nil
-		}
-
-		taskID := parts[0]
-		evidenceURL := parts[1]
-
-		// Simulate evidence upload
-		err := h.HandleEvidenceUpload(taskID, command.ChannelID, "", command.UserID, "evidence.pdf", evidenceURL)
-		if err != nil {
-			return fmt.Sprintf("Error uploading evidence: %s", err), nil
-		}
-
-		return "Evidence uploaded successfully!", nil
-
-	default:
-		return "Unknown command", nil
-	}
-}
-
-// Helper function to split command text
-func splitCommand(text string) []string {
-	// Simple space-based splitting - in a real implementation, you might want to handle quoted strings, etc.
-	return strings.Fields(text)
+// RegisterCommands adds the compliance task slash commands to the router
+func (h *ComplianceTaskHandler) RegisterCommands(router *slack.CommandRouter) error {
+	return router.Register(slack.SlashCommand{
+		Name:        "/upload-evidence",
+		Usage:       "RECORD_ID FILE_URL",
+		Description: "Attach evidence to a control test or compliance task",
+		Args:        2,
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			if h.Evidence == nil {
+				return "Evidence uploads are not configured.", nil
+			}
+			return h.Evidence.HandleUploadCommand(command, args[0], args[1])
+		},
+	})
 }
@@ -0,0 +1,187 @@
+// backend/internal/integrations/servicenow/custom_tables.go
+package servicenow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// CustomTableHandler syncs records of the tables in the registry: each new
+// record gets a Jira issue built with the table's field map and is announced
+// in Slack as a grc_item
+type CustomTableHandler struct {
+	ServiceNowClient *Client
+	SlackClient      *slack.Client
+	JiraClient       *jira.Client
+	Tables           *TableRegistry
+	// FieldMapping supplies the map of tables registered without one
+	FieldMapping *mapping.Engine
+	// Threads keeps the announcement of each record so updates reply in its thread
+	Threads *ThreadTracker
+	// Routes picks the channels new records are announced in
+	Routes *notification.Router
+	// Identities maps assignees to their Jira accounts when configured
+	Identities Identities
+	// Projects picks the Jira project when neither the table nor its map does
+	Projects *ProjectProvisioner
+}
+
+// NewCustomTableHandler creates a handler for the tables in registry
+func NewCustomTableHandler(serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, registry *TableRegistry) *CustomTableHandler {
+	return &CustomTableHandler{
+		ServiceNowClient: serviceNowClient,
+		SlackClient:      slackClient,
+		JiraClient:       jiraClient,
+		Tables:           registry,
+		FieldMapping:     mapping.NewEngine(mapping.Default()),
+	}
+}
+
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *CustomTableHandler) WithContext(ctx context.Context) *CustomTableHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	copied.JiraClient = h.JiraClient.WithContext(ctx)
+	copied.Threads = h.Threads.WithContext(ctx)
+	return &copied
+}
+
+// Handles reports whether table is registered
+func (h *CustomTableHandler) Handles(table string) bool {
+	if h == nil {
+		return false
+	}
+	_, ok := h.Tables.Get(table)
+	return ok
+}
+
+// HandleNewRecord creates the Jira issue of a new record and announces it,
+// returning the announcement's timestamp. A record that already has an
+// issue, e.g. a replayed webhook, is skipped.
+func (h *CustomTableHandler) HandleNewRecord(payload WebhookPayload) (string, error) {
+	table, ok := h.Tables.Get(payload.TableName)
+	if !ok {
+		return "", ErrTableNotFound
+	}
+	if _, linked := h.Tables.IssueKey(table.Name, payload.ID); linked {
+		return "", nil
+	}
+
+	ticket, err := h.ticket(table, payload.Data)
+	if err != nil {
+		return "", err
+	}
+	assignedTo := referenceValue(payload.Data["assigned_to"])
+	ticket.AssigneeID = jiraAccount(h.ServiceNowClient.Context(), h.Identities, assignedTo)
+
+	issue, err := h.JiraClient.CreateIssue(ticket)
+	if err != nil {
+		return "", fmt.Errorf("error creating Jira issue for %s %s: %w", table.Name, payload.ID, err)
+	}
+	if err := h.Tables.Link(table.Name, payload.ID, issue.Key); err != nil {
+		return "", err
+	}
+
+	return h.announce(table, payload, issue, assignedTo), nil
+}
+
+// ticket maps a record with the table's own map, the field mapping config's
+// map for the table, or the generic table's map, in that order
+func (h *CustomTableHandler) ticket(table CustomTable, record map[string]interface{}) (*jira.Ticket, error) {
+	tm := table.Mapping
+	if tm == nil {
+		tm = h.FieldMapping.Config.Tables[table.Name]
+	}
+	if tm == nil {
+		tm = h.FieldMapping.Config.Tables[GenericTable]
+	}
+	if tm == nil {
+		return nil, fmt.Errorf("no field mapping for table %s", table.Name)
+	}
+
+	cfg := &mapping.Config{Tables: map[string]*mapping.TableMap{table.Name: tm}}
+	if table.Project != "" {
+		cfg.SetDefault(table.Name, "project", table.Project)
+	}
+	if table.IssueType != "" {
+		cfg.SetDefault(table.Name, "issuetype", table.IssueType)
+	}
+	ticket, err := mapping.NewEngine(cfg).Ticket(table.Name, record)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.IssueType == "" {
+		ticket.IssueType = "Task"
+	}
+	h.Projects.Route(table.Name, ticket, h.JiraClient.ProjectKey)
+	return ticket, nil
+}
+
+// announce posts the new record and its issue to the channels routed for
+// grc_item and returns the message timestamp. Failures are logged, as the
+// issue already exists.
+func (h *CustomTableHandler) announce(table CustomTable, payload WebhookPayload, issue *jira.Ticket, assignedTo string) string {
+	label := table.Label
+	if label == "" {
+		label = tableTitle(table.Name)
+	}
+	number := referenceValue(payload.Data["number"])
+	title := referenceValue(payload.Data["short_description"])
+	if title == "" {
+		title = number
+	}
+	url := fmt.Sprintf("%s/nav_to.do?uri=%s.do?sys_id=%s", h.ServiceNowClient.BaseURL, table.Name, payload.ID)
+
+	build := func(locale string) slack.Message {
+		return slack.Message{
+			Blocks: []slack.Block{
+				{
+					Type: "header",
+					Text: slack.NewTextObject("plain_text", i18n.T(locale, "grc_item.header", label, title), true),
+				},
+				{
+					Type: "section",
+					Fields: []*slack.TextObject{
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "grc_item.field.number", number), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "grc_item.field.table", table.Name), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "grc_item.field.issue", issue.Key), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "grc_item.field.project", issue.Project), false),
+					},
+				},
+				{
+					Type: "context",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "mrkdwn",
+							"text": fmt.Sprintf("<%s|%s>", url, i18n.T(locale, "button.view_servicenow")),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	item := notification.Item{
+		Type:     "grc_item",
+		Severity: referenceValue(payload.Data["severity"]),
+		Category: referenceValue(payload.Data["category"]),
+		Number:   number,
+		Title:    title,
+		URL:      url,
+	}
+	channel, ts, err := announce(h.SlackClient, h.Routes, item, build)
+	if err != nil {
+		h.SlackClient.Logger().Error("error announcing record", "table", table.Name, "sys_id", payload.ID, "error", err)
+		return ""
+	}
+	message := build(h.Routes.Locale(channel))
+	h.Threads.Start(table.Name, payload.ID, number, referenceValue(payload.Data["state"]), assignedTo, channel, ts, message)
+	return ts
+}
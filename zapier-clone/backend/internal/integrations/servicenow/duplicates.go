@@ -0,0 +1,240 @@
+// backend/internal/integrations/servicenow/duplicates.go
+package servicenow
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+)
+
+// Duplicate check actions
+const (
+	// DuplicateLink links the record to the existing issue instead of creating one
+	DuplicateLink = "link"
+	// DuplicateFlag holds the issue back and asks in the record's Slack thread
+	DuplicateFlag = "flag"
+)
+
+// DefaultDuplicateThreshold is how similar two summaries must be, from 0 to 1,
+// for the issue to count as a possible duplicate
+const DefaultDuplicateThreshold = 0.85
+
+// duplicateTables are the tables whose new records can be checked for duplicates
+var duplicateTables = map[string]bool{
+	"sn_risk_risk":     true,
+	"sn_si_incident":   true,
+	"sn_audit_finding": true,
+}
+
+// DuplicateRule is what a table does with a new record that looks like an
+// existing Jira issue
+type DuplicateRule struct {
+	Action    string  `json:"action"`
+	Threshold float64 `json:"threshold"`
+}
+
+// DuplicateMatch is an existing Jira issue a new record may duplicate
+type DuplicateMatch struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	// Similarity is how alike the summaries are, from 0 to 1
+	Similarity float64 `json:"similarity"`
+	// SameNumber is set when the issue mentions the record's ServiceNow number
+	SameNumber bool `json:"same_number,omitempty"`
+	// Action is the table's rule for the match, link or flag
+	Action string `json:"action"`
+}
+
+// Reason says in a locale why the issue was matched
+func (m DuplicateMatch) Reason(locale string) string {
+	if m.SameNumber {
+		return i18n.T(locale, "duplicate.reason.same_number")
+	}
+	return i18n.T(locale, "duplicate.reason.similar", int(m.Similarity*100+0.5))
+}
+
+// DuplicateDetector searches Jira for an issue a new record may duplicate
+// before one is created for it: first one that mentions the record's number,
+// then a recent unresolved issue with a similar summary
+type DuplicateDetector struct {
+	Rules map[string]DuplicateRule
+	// Window limits the summary comparison to issues created within it
+	Window time.Duration
+	// Candidates is how many of the most recent issues are compared
+	Candidates int
+}
+
+// NewDuplicateDetector creates a detector with no rules, comparing the last
+// 100 issues of the past 90 days
+func NewDuplicateDetector() *DuplicateDetector {
+	return &DuplicateDetector{
+		Rules:      map[string]DuplicateRule{},
+		Window:     90 * 24 * time.Hour,
+		Candidates: 100,
+	}
+}
+
+// ConfigureFromEnv reads JIRA_DUPLICATE_CHECK, a comma-separated list of
+// tables with their action and optionally their own threshold:
+// "sn_risk_risk=flag,sn_audit_finding=link:0.9". JIRA_DUPLICATE_THRESHOLD
+// sets the threshold of the others and JIRA_DUPLICATE_WINDOW_DAYS how far
+// back summaries are compared. Unknown tables and actions are logged and ignored.
+func (d *DuplicateDetector) ConfigureFromEnv() {
+	threshold := DefaultDuplicateThreshold
+	if value := os.Getenv("JIRA_DUPLICATE_THRESHOLD"); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed > 0 && parsed <= 1 {
+			threshold = parsed
+		} else {
+			slog.Warn("ignoring JIRA_DUPLICATE_THRESHOLD; use a number between 0 and 1", "value", value)
+		}
+	}
+	if value := os.Getenv("JIRA_DUPLICATE_WINDOW_DAYS"); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days > 0 {
+			d.Window = time.Duration(days) * 24 * time.Hour
+		} else {
+			slog.Warn("ignoring JIRA_DUPLICATE_WINDOW_DAYS", "value", value)
+		}
+	}
+
+	for _, entry := range strings.Split(os.Getenv("JIRA_DUPLICATE_CHECK"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		table, setting, _ := strings.Cut(entry, "=")
+		table = strings.TrimSpace(table)
+		if !duplicateTables[table] {
+			slog.Warn("ignoring Jira duplicate check for unsupported table", "table", table)
+			continue
+		}
+
+		action, level, hasLevel := strings.Cut(strings.TrimSpace(setting), ":")
+		rule := DuplicateRule{Action: strings.ToLower(strings.TrimSpace(action)), Threshold: threshold}
+		if rule.Action == "" {
+			rule.Action = DuplicateFlag
+		}
+		if rule.Action != DuplicateLink && rule.Action != DuplicateFlag {
+			slog.Warn("ignoring Jira duplicate check with an unknown action", "table", table, "action", action)
+			continue
+		}
+		if hasLevel {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(level), 64)
+			if err != nil || parsed <= 0 || parsed > 1 {
+				slog.Warn("ignoring Jira duplicate check with an invalid threshold", "table", table, "threshold", level)
+				continue
+			}
+			rule.Threshold = parsed
+		}
+		d.Rules[table] = rule
+	}
+}
+
+// Rule returns a table's rule, reporting false when its records are not checked
+func (d *DuplicateDetector) Rule(table string) (DuplicateRule, bool) {
+	if d == nil {
+		return DuplicateRule{}, false
+	}
+	rule, ok := d.Rules[table]
+	return rule, ok
+}
+
+// Find returns the issue in the ticket's project that a new record with
+// number may duplicate, or nil when there is none or the table is not checked
+func (d *DuplicateDetector) Find(client *jira.Client, table, number string, ticket *jira.Ticket) (*DuplicateMatch, error) {
+	rule, ok := d.Rule(table)
+	if !ok {
+		return nil, nil
+	}
+	scope := ""
+	if ticket.Project != "" {
+		scope = fmt.Sprintf("project = %q AND ", ticket.Project)
+	}
+
+	if number != "" {
+		issues, err := client.Search(fmt.Sprintf("%stext ~ %q ORDER BY created DESC", scope, number), 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) > 0 {
+			return &DuplicateMatch{Key: issues[0].Key, Summary: issues[0].Summary, Similarity: 1, SameNumber: true, Action: rule.Action}, nil
+		}
+	}
+
+	days := int(d.Window.Hours() / 24)
+	issues, err := client.Search(fmt.Sprintf("%sresolution IS EMPTY AND created >= -%dd ORDER BY created DESC", scope, days), d.Candidates)
+	if err != nil {
+		return nil, err
+	}
+	var best *DuplicateMatch
+	for _, issue := range issues {
+		similarity := Similarity(ticket.Summary, issue.Summary)
+		if similarity >= rule.Threshold && (best == nil || similarity > best.Similarity) {
+			best = &DuplicateMatch{Key: issue.Key, Summary: issue.Summary, Similarity: similarity, Action: rule.Action}
+		}
+	}
+	return best, nil
+}
+
+// summaryNumber is the "[RISK0001] " that generated summaries start with
+var summaryNumber = regexp.MustCompile(`^\s*\[[^\]]*\]\s*`)
+
+// Similarity compares two summaries from 0 to 1 by the letter pairs they
+// share (the Sørensen–Dice coefficient), ignoring case, punctuation and a
+// leading "[NUMBER]"
+func Similarity(a, b string) float64 {
+	a, b = normalizeSummary(a), normalizeSummary(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	pairs := func(s string) map[string]int {
+		runes := []rune(s)
+		counts := make(map[string]int)
+		for i := 0; i+1 < len(runes); i++ {
+			counts[string(runes[i:i+2])]++
+		}
+		return counts
+	}
+	first, second := pairs(a), pairs(b)
+	total, shared := 0, 0
+	for pair, count := range first {
+		total += count
+		if other := second[pair]; other < count {
+			shared += other
+		} else {
+			shared += count
+		}
+	}
+	for _, count := range second {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(2*shared) / float64(total)
+}
+
+// normalizeSummary lowercases a summary and reduces it to its words
+func normalizeSummary(summary string) string {
+	summary = summaryNumber.ReplaceAllString(summary, "")
+	words := strings.FieldsFunc(strings.ToLower(summary), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// noteDuplicate comments on the existing issue that a record was linked to it
+func noteDuplicate(client *jira.Client, table, number string, match *DuplicateMatch) error {
+	return client.AddComment(match.Key, fmt.Sprintf("ServiceNow %s %s was linked to this issue instead of getting its own, as %s.", table, number, match.Reason(i18n.DefaultLocale)))
+}
@@ -0,0 +1,636 @@
+// backend/internal/integrations/servicenow/escalations.go
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// Escalation states
+const (
+	EscalationWaiting      = "waiting"
+	EscalationAcknowledged = "acknowledged"
+	// EscalationExhausted means everyone in the chain was notified without an acknowledgment
+	EscalationExhausted = "exhausted"
+	// EscalationClosed means the incident was closed in ServiceNow before anyone acknowledged it
+	EscalationClosed = "closed"
+)
+
+// ErrEscalationPolicyNotFound is returned for an unknown policy ID
+var ErrEscalationPolicyNotFound = errors.New("escalation policy not found")
+
+// jiraPriorities are Jira's default priorities, lowest first; each escalation
+// step moves the incident's epic one up
+var jiraPriorities = []string{"Lowest", "Low", "Medium", "High", "Highest"}
+
+// EscalationPolicy escalates incidents announced in a channel that nobody
+// acknowledges in time
+type EscalationPolicy struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Channel is the Slack channel name or ChannelMapping key the incident was
+	// announced in; empty matches every channel
+	Channel string `json:"channel,omitempty"`
+	// Severities the policy applies to, matched case-insensitively; critical when empty
+	Severities []string `json:"severities"`
+	// AfterMinutes is how long each person has to acknowledge before the next is notified
+	AfterMinutes int `json:"after_minutes"`
+	// Chain is the Slack user IDs notified in turn
+	Chain     []string  `json:"chain"`
+	Disabled  bool      `json:"disabled,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks that a policy can escalate anything, defaulting its severities to critical
+func (p *EscalationPolicy) Validate() error {
+	if p.AfterMinutes <= 0 {
+		return fmt.Errorf("after_minutes must be greater than zero")
+	}
+	if len(p.Chain) == 0 {
+		return fmt.Errorf("a policy needs at least one person in its chain")
+	}
+	for i, user := range p.Chain {
+		p.Chain[i] = strings.TrimPrefix(strings.TrimSuffix(strings.TrimSpace(user), ">"), "<@")
+		if p.Chain[i] == "" {
+			return fmt.Errorf("chain entries must be Slack user IDs")
+		}
+	}
+	if len(p.Severities) == 0 {
+		p.Severities = []string{"critical"}
+	}
+	p.Channel = strings.TrimPrefix(strings.TrimSpace(p.Channel), "#")
+	return nil
+}
+
+// matches reports whether the policy escalates an incident announced in channel
+func (p *EscalationPolicy) matches(channel, severity string) bool {
+	if p.Disabled {
+		return false
+	}
+	if p.Channel != "" {
+		want := p.Channel
+		if mapped, ok := slack.ChannelMapping[want]; ok {
+			want = mapped
+		}
+		if !strings.EqualFold(want, channel) {
+			return false
+		}
+	}
+	for _, s := range p.Severities {
+		if strings.EqualFold(strings.TrimSpace(s), severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// EscalationStep is one person notified about an unacknowledged incident
+type EscalationStep struct {
+	User         string    `json:"user"`
+	NotifiedAt   time.Time `json:"notified_at"`
+	JiraPriority string    `json:"jira_priority,omitempty"`
+}
+
+// Escalation follows one incident's announcement until someone acknowledges it
+type Escalation struct {
+	// ID is the incident's sys_id
+	ID       string `json:"id"`
+	Number   string `json:"number"`
+	Severity string `json:"severity"`
+	PolicyID string `json:"policy_id"`
+	// Channel and TS are the incident's announcement
+	Channel   string    `json:"channel"`
+	TS        string    `json:"ts"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"started_at"`
+	// NextAt is when the next person in the chain is notified
+	NextAt         time.Time        `json:"next_at"`
+	Steps          []EscalationStep `json:"steps"`
+	AcknowledgedBy string           `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time       `json:"acknowledged_at,omitempty"`
+}
+
+// Escalator notifies the next person in a policy's chain when an incident
+// announcement is not acknowledged in time. Each step also raises the
+// priority of the incident's Jira epic and adds a work note in ServiceNow.
+type Escalator struct {
+	ServiceNowClient    *Client
+	SlackClient         *slack.Client
+	JiraClient          *jira.Client
+	IncidentJiraMapping *jira.IncidentJiraMapping
+	JournalWriter       *JournalWriter
+	// Routes gives the language of each incident's channel; nil writes English
+	Routes *notification.Router
+	// Interval is how often overdue escalations are looked for
+	Interval time.Duration
+
+	mutex        sync.Mutex
+	policies     []*EscalationPolicy
+	sequence     int64
+	escalations  map[string]*Escalation
+	policiesPath string
+	filePath     string
+	checking     bool
+	running      bool
+	stopChan     chan struct{}
+	now          func() time.Time
+}
+
+// NewEscalator creates an escalator that checks every minute and keeps its
+// policies and escalations in storagePath
+func NewEscalator(storagePath string, serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, incidents *jira.IncidentJiraMapping) (*Escalator, error) {
+	e := &Escalator{
+		ServiceNowClient:    serviceNowClient,
+		SlackClient:         slackClient,
+		JiraClient:          jiraClient,
+		IncidentJiraMapping: incidents,
+		JournalWriter:       NewJournalWriter(serviceNowClient),
+		Interval:            time.Minute,
+		escalations:         make(map[string]*Escalation),
+		policiesPath:        filepath.Join(storagePath, "escalation_policies.json"),
+		filePath:            filepath.Join(storagePath, "escalations.json"),
+		stopChan:            make(chan struct{}),
+		now:                 time.Now,
+	}
+
+	if _, err := os.Stat(e.policiesPath); err == nil {
+		file, err := os.ReadFile(e.policiesPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading escalation policy file: %w", err)
+		}
+		if err := json.Unmarshal(file, &e.policies); err != nil {
+			return nil, fmt.Errorf("error unmarshaling escalation policies: %w", err)
+		}
+	}
+	for _, policy := range e.policies {
+		var n int64
+		if _, err := fmt.Sscanf(policy.ID, "policy-%d", &n); err == nil && n > e.sequence {
+			e.sequence = n
+		}
+	}
+	if _, err := os.Stat(e.filePath); err == nil {
+		file, err := os.ReadFile(e.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading escalation file: %w", err)
+		}
+		if err := json.Unmarshal(file, &e.escalations); err != nil {
+			return nil, fmt.Errorf("error unmarshaling escalations: %w", err)
+		}
+	}
+
+	return e, nil
+}
+
+// Watch starts escalating an incident announced in channel when a policy
+// covers it. Incidents without an announcement to acknowledge are ignored.
+func (e *Escalator) Watch(incident Incident, channel, ts string) {
+	if e == nil || incident.ID == "" || ts == "" {
+		return
+	}
+
+	e.mutex.Lock()
+	policy := e.policyFor(channel, incident.Severity)
+	if policy == nil {
+		e.mutex.Unlock()
+		return
+	}
+	now := e.now()
+	e.escalations[incident.ID] = &Escalation{
+		ID:        incident.ID,
+		Number:    incident.Number,
+		Severity:  incident.Severity,
+		PolicyID:  policy.ID,
+		Channel:   channel,
+		TS:        ts,
+		Status:    EscalationWaiting,
+		StartedAt: now,
+		NextAt:    now.Add(time.Duration(policy.AfterMinutes) * time.Minute),
+		Steps:     []EscalationStep{},
+	}
+	e.mutex.Unlock()
+	e.save()
+}
+
+// policyFor returns the policy for an incident: one naming its channel
+// before one that matches every channel. Callers hold the mutex.
+func (e *Escalator) policyFor(channel, severity string) *EscalationPolicy {
+	var fallback *EscalationPolicy
+	for _, policy := range e.policies {
+		if !policy.matches(channel, severity) {
+			continue
+		}
+		if policy.Channel != "" {
+			return policy
+		}
+		if fallback == nil {
+			fallback = policy
+		}
+	}
+	return fallback
+}
+
+// Acknowledge stops escalating an incident. It reports whether the incident
+// was still waiting.
+func (e *Escalator) Acknowledge(sysID, userID string) bool {
+	if e == nil {
+		return false
+	}
+
+	e.mutex.Lock()
+	escalation, ok := e.escalations[sysID]
+	if !ok || escalation.Status != EscalationWaiting {
+		e.mutex.Unlock()
+		return false
+	}
+	now := e.now()
+	escalation.Status = EscalationAcknowledged
+	escalation.AcknowledgedBy = userID
+	escalation.AcknowledgedAt = &now
+	e.mutex.Unlock()
+	e.save()
+	return true
+}
+
+// Chain returns the Slack users in the chain of the policy escalating an
+// incident, or nil when none is
+func (e *Escalator) Chain(sysID string) []string {
+	if e == nil {
+		return nil
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	escalation, ok := e.escalations[sysID]
+	if !ok {
+		return nil
+	}
+	if policy := e.find(escalation.PolicyID); policy != nil {
+		return append([]string(nil), policy.Chain...)
+	}
+	return nil
+}
+
+// Start looks for overdue escalations every Interval until Stop
+func (e *Escalator) Start() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.running || e.Interval <= 0 {
+		return
+	}
+	e.running = true
+
+	go func() {
+		ticker := time.NewTicker(e.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.stopChan:
+				return
+			case <-ticker.C:
+				e.Check(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop stops looking for overdue escalations
+func (e *Escalator) Stop() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.running {
+		return
+	}
+	e.running = false
+	close(e.stopChan)
+}
+
+// Check escalates every waiting incident whose time is up. A check already in
+// progress is not repeated.
+func (e *Escalator) Check(ctx context.Context) {
+	e.mutex.Lock()
+	if e.checking {
+		e.mutex.Unlock()
+		return
+	}
+	e.checking = true
+	now := e.now()
+	var due []string
+	for id, escalation := range e.escalations {
+		if escalation.Status == EscalationWaiting && !now.Before(escalation.NextAt) {
+			due = append(due, id)
+		}
+	}
+	e.mutex.Unlock()
+	defer func() {
+		e.mutex.Lock()
+		e.checking = false
+		e.mutex.Unlock()
+	}()
+
+	sort.Strings(due)
+	for _, id := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		e.escalate(ctx, id)
+	}
+	if len(due) > 0 {
+		e.save()
+	}
+}
+
+// escalate notifies the next person in an incident's chain, raises its Jira
+// priority and adds a work note. Failures of the last two are only logged.
+func (e *Escalator) escalate(ctx context.Context, id string) {
+	serviceNowClient := e.ServiceNowClient.WithContext(ctx)
+	slackClient := e.SlackClient.WithContext(ctx)
+	logger := serviceNowClient.Logger()
+
+	// Nobody needs paging about an incident that was closed in ServiceNow meanwhile
+	if record, err := serviceNowClient.GetRecord("sn_si_incident", id); err == nil && incidentClosed(referenceValue(record["state"])) {
+		e.mutex.Lock()
+		if escalation, ok := e.escalations[id]; ok && escalation.Status == EscalationWaiting {
+			escalation.Status = EscalationClosed
+		}
+		e.mutex.Unlock()
+		return
+	}
+
+	e.mutex.Lock()
+	escalation, ok := e.escalations[id]
+	if !ok || escalation.Status != EscalationWaiting {
+		e.mutex.Unlock()
+		return
+	}
+	policy := e.find(escalation.PolicyID)
+	if policy == nil || len(escalation.Steps) >= len(policy.Chain) {
+		escalation.Status = EscalationExhausted
+		e.mutex.Unlock()
+		return
+	}
+	step := len(escalation.Steps)
+	user := policy.Chain[step]
+	after := time.Duration(policy.AfterMinutes) * time.Minute
+	minutes := int(e.now().Sub(escalation.StartedAt).Round(time.Minute) / time.Minute)
+	waited := fmt.Sprintf("%d %s", minutes, plural(minutes, "minute", "minutes"))
+	copied := *escalation
+	e.mutex.Unlock()
+
+	locale := e.Routes.Locale(copied.Channel)
+	notice := i18n.T(locale, "escalation.notice", copied.Number, i18n.Plural(locale, "duration.minutes", minutes), user, step+1, len(policy.Chain))
+	if _, err := slackClient.PostReply(copied.Channel, copied.TS, slack.Message{Text: notice}); err != nil {
+		logger.Error("error posting escalation in incident thread", "sys_id", id, "error", err)
+	}
+	if _, err := slackClient.SendDirectMessage(user, escalationMessage(locale, copied, serviceNowClient.BaseURL)); err != nil {
+		logger.Error("error sending escalation to Slack user", "sys_id", id, "user", user, "error", err)
+	}
+
+	priority := ""
+	if jiraKey, linked := e.IncidentJiraMapping.GetJiraKeyFromIncidentID(id); linked {
+		var err error
+		if priority, err = raiseJiraPriority(e.JiraClient.WithContext(ctx), jiraKey); err != nil {
+			logger.Error("error raising Jira priority for escalation", "sys_id", id, "issue", jiraKey, "error", err)
+		}
+	}
+
+	note := fmt.Sprintf("Incident not acknowledged in Slack after %s; escalated to Slack user %s (%d of %d)", waited, user, step+1, len(policy.Chain))
+	if priority != "" {
+		note += fmt.Sprintf(". Jira priority raised to %s", priority)
+	}
+	if err := e.JournalWriter.Write("sn_si_incident", id, JournalWorkNotes, JournalKey("incident-escalation", id, fmt.Sprint(step)), note); err != nil {
+		logger.Error("error adding escalation work note", "sys_id", id, "error", err)
+	}
+
+	e.mutex.Lock()
+	if escalation.Status == EscalationWaiting {
+		escalation.Steps = append(escalation.Steps, EscalationStep{User: user, NotifiedAt: e.now(), JiraPriority: priority})
+		escalation.NextAt = e.now().Add(after)
+		if len(escalation.Steps) >= len(policy.Chain) {
+			escalation.Status = EscalationExhausted
+		}
+	}
+	e.mutex.Unlock()
+	logger.Info("escalated unacknowledged incident", "sys_id", id, "user", user, "step", step+1)
+}
+
+// escalationMessage is the direct message asking the next person to acknowledge,
+// written in the language of the incident's channel
+func escalationMessage(locale string, escalation Escalation, serviceNowURL string) slack.Message {
+	text := i18n.T(locale, "escalation.next_in_line", escalation.Number, i18n.Value(locale, "severity", escalation.Severity))
+	return slack.Message{
+		Text: text,
+		Blocks: []slack.Block{
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", text, false),
+			},
+			{
+				Type: "actions",
+				Elements: []interface{}{
+					map[string]interface{}{
+						"type": "button",
+						"text": map[string]interface{}{
+							"type":  "plain_text",
+							"text":  i18n.T(locale, "incident.button.acknowledge"),
+							"emoji": true,
+						},
+						"style":     "primary",
+						"value":     fmt.Sprintf("ack_incident_%s", escalation.ID),
+						"action_id": "acknowledge_incident",
+					},
+					map[string]interface{}{
+						"type": "button",
+						"text": map[string]interface{}{
+							"type":  "plain_text",
+							"text":  i18n.T(locale, "button.view_servicenow"),
+							"emoji": true,
+						},
+						"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_si_incident.do?sys_id=%s", serviceNowURL, escalation.ID),
+						"action_id": "view_incident",
+					},
+				},
+			},
+		},
+	}
+}
+
+// raiseJiraPriority moves an issue one priority up and returns the new one.
+// An issue already at the top, or with a priority outside Jira's defaults, is
+// set to Highest.
+func raiseJiraPriority(client *jira.Client, issueKey string) (string, error) {
+	issue, err := client.GetIssue(issueKey)
+	if err != nil {
+		return "", err
+	}
+	fields, _ := issue["fields"].(map[string]interface{})
+	current, _ := fields["priority"].(map[string]interface{})
+	name, _ := current["name"].(string)
+
+	next := jiraPriorities[len(jiraPriorities)-1]
+	for i, priority := range jiraPriorities[:len(jiraPriorities)-1] {
+		if strings.EqualFold(priority, name) {
+			next = jiraPriorities[i+1]
+		}
+	}
+	if strings.EqualFold(name, next) {
+		return next, nil
+	}
+	if err := client.UpdateIssue(issueKey, &jira.TicketUpdate{Priority: next}); err != nil {
+		return "", err
+	}
+	return next, nil
+}
+
+// incidentClosed reports whether an incident state, as a label or numeric code, is closed
+func incidentClosed(state string) bool {
+	switch strings.ToLower(state) {
+	case "closed", "resolved", "cancelled", "3", "4", "7":
+		return true
+	}
+	return false
+}
+
+// List returns escalations, newest first, optionally only those with a status
+func (e *Escalator) List(status string) []Escalation {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	list := []Escalation{}
+	for _, escalation := range e.escalations {
+		if status == "" || escalation.Status == status {
+			list = append(list, *escalation)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].StartedAt.After(list[j].StartedAt) })
+	return list
+}
+
+// Policies returns every policy in creation order
+func (e *Escalator) Policies() []EscalationPolicy {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	list := make([]EscalationPolicy, 0, len(e.policies))
+	for _, policy := range e.policies {
+		list = append(list, *policy)
+	}
+	return list
+}
+
+// Policy returns one policy
+func (e *Escalator) Policy(id string) (EscalationPolicy, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if policy := e.find(id); policy != nil {
+		return *policy, true
+	}
+	return EscalationPolicy{}, false
+}
+
+// CreatePolicy validates and stores a new policy, assigning its ID. It
+// applies to incidents announced from then on.
+func (e *Escalator) CreatePolicy(policy EscalationPolicy) (EscalationPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return EscalationPolicy{}, err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.sequence++
+	policy.ID = fmt.Sprintf("policy-%d", e.sequence)
+	policy.CreatedAt = e.now()
+	policy.UpdatedAt = policy.CreatedAt
+	e.policies = append(e.policies, &policy)
+
+	return policy, e.savePolicies()
+}
+
+// UpdatePolicy replaces a policy's settings, keeping its ID and creation
+// time. Escalations under way follow the new chain and timing.
+func (e *Escalator) UpdatePolicy(id string, policy EscalationPolicy) (EscalationPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return EscalationPolicy{}, err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	existing := e.find(id)
+	if existing == nil {
+		return EscalationPolicy{}, ErrEscalationPolicyNotFound
+	}
+	policy.ID = existing.ID
+	policy.CreatedAt = existing.CreatedAt
+	policy.UpdatedAt = e.now()
+	*existing = policy
+
+	return policy, e.savePolicies()
+}
+
+// DeletePolicy removes a policy; incidents it was escalating stop escalating
+func (e *Escalator) DeletePolicy(id string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for i, policy := range e.policies {
+		if policy.ID == id {
+			e.policies = append(e.policies[:i], e.policies[i+1:]...)
+			return e.savePolicies()
+		}
+	}
+	return ErrEscalationPolicyNotFound
+}
+
+// find returns the stored policy with the given ID; callers hold the mutex
+func (e *Escalator) find(id string) *EscalationPolicy {
+	for _, policy := range e.policies {
+		if policy.ID == id {
+			return policy
+		}
+	}
+	return nil
+}
+
+// savePolicies persists the policies to disk; callers hold the mutex
+func (e *Escalator) savePolicies() error {
+	data, err := json.MarshalIndent(e.policies, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling escalation policies: %w", err)
+	}
+	if err := os.WriteFile(e.policiesPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing escalation policies: %w", err)
+	}
+	return nil
+}
+
+// save writes escalations to disk; failures are logged
+func (e *Escalator) save() {
+	e.mutex.Lock()
+	data, err := json.MarshalIndent(e.escalations, "", "  ")
+	e.mutex.Unlock()
+	if err != nil {
+		slog.Error("error marshaling escalations", "error", err)
+		return
+	}
+	if err := os.WriteFile(e.filePath, data, 0644); err != nil {
+		slog.Error("error saving escalations", "error", err)
+	}
+}
@@ -0,0 +1,330 @@
+// backend/internal/integrations/servicenow/evidence.go
+package servicenow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/evidence"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// EvidenceTables are the tables evidence can be uploaded to, with the label
+// Slack messages and work notes use for their records
+var EvidenceTables = map[string]string{
+	"sn_policy_control_test": "control test",
+	"sn_compliance_task":     "compliance task",
+}
+
+// ErrEvidenceTable is returned for uploads to a table not in EvidenceTables
+var ErrEvidenceTable = errors.New("evidence cannot be attached to records of this table")
+
+// EvidenceUpload is where an uploaded evidence file ended up
+type EvidenceUpload struct {
+	Table       string `json:"table"`
+	SysID       string `json:"sys_id"`
+	Number      string `json:"number,omitempty"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	// URL is the file in evidence storage, empty when no storage is configured
+	URL                  string `json:"url,omitempty"`
+	ServiceNowAttachment string `json:"servicenow_attachment"`
+	JiraIssue            string `json:"jira_issue,omitempty"`
+	JiraAttachment       string `json:"jira_attachment,omitempty"`
+	// Warnings lists the copies that could not be made; the ServiceNow attachment always was
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// EvidenceUploader validates evidence files, keeps them in S3-compatible
+// storage and attaches them to the ServiceNow record and its Jira issue
+type EvidenceUploader struct {
+	ServiceNowClient *Client
+	SlackClient      *slack.Client
+	JiraClient       *jira.Client
+	// Store keeps a copy of every file when configured, linked from the work note
+	Store  evidence.Store
+	Policy evidence.Policy
+	// Threads finds the Slack thread a record was announced in, for the upload notice
+	Threads *ThreadTracker
+	// FetchHosts are the hosts /upload-evidence downloads files from
+	FetchHosts []string
+	HTTPClient *http.Client
+
+	now func() time.Time
+}
+
+// NewEvidenceUploader creates an uploader with the policy and fetch hosts from
+// EVIDENCE_MAX_BYTES, EVIDENCE_FILE_TYPES and EVIDENCE_FETCH_HOSTS
+func NewEvidenceUploader(serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client) *EvidenceUploader {
+	fetchHosts := []string{"files.slack.com"}
+	if value := os.Getenv("EVIDENCE_FETCH_HOSTS"); value != "" {
+		fetchHosts = nil
+		for _, host := range strings.Split(value, ",") {
+			if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+				fetchHosts = append(fetchHosts, host)
+			}
+		}
+	}
+
+	return &EvidenceUploader{
+		ServiceNowClient: serviceNowClient,
+		SlackClient:      slackClient,
+		JiraClient:       jiraClient,
+		Policy:           evidence.PolicyFromEnv(),
+		FetchHosts:       fetchHosts,
+		HTTPClient:       common.NewHTTPClient(60 * time.Second),
+		now:              time.Now,
+	}
+}
+
+// WithContext returns a copy of the uploader whose API calls run under ctx
+func (u *EvidenceUploader) WithContext(ctx context.Context) *EvidenceUploader {
+	if u == nil {
+		return nil
+	}
+	copied := *u
+	copied.ServiceNowClient = u.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = u.SlackClient.WithContext(ctx)
+	if u.JiraClient != nil {
+		copied.JiraClient = u.JiraClient.WithContext(ctx)
+	}
+	copied.Threads = u.Threads.WithContext(ctx)
+	return &copied
+}
+
+// Find looks a record up in each of the evidence tables, for commands that
+// only name its sys_id
+func (u *EvidenceUploader) Find(sysID string) (string, map[string]interface{}, error) {
+	for _, table := range []string{"sn_policy_control_test", "sn_compliance_task"} {
+		record, err := u.ServiceNowClient.GetRecord(table, sysID)
+		if errors.Is(err, ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("error looking up %s: %w", sysID, err)
+		}
+		return table, record, nil
+	}
+	return "", nil, ErrRecordNotFound
+}
+
+// Upload attaches an evidence file to a record. The file is checked against
+// the policy first; ServiceNow must accept it, while storage, Jira and Slack
+// failures are reported as warnings on the result. The notice is posted in
+// the record's thread, or in channel when the record has none. uploadedBy
+// names who uploaded the file, if known.
+func (u *EvidenceUploader) Upload(table, sysID, uploadedBy, fileName string, content []byte, channel string) (*EvidenceUpload, error) {
+	label, ok := EvidenceTables[table]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEvidenceTable, table)
+	}
+	contentType, err := u.Policy.Check(fileName, content)
+	if err != nil {
+		return nil, err
+	}
+	record, err := u.ServiceNowClient.GetRecord(table, sysID)
+	if err != nil {
+		return nil, err
+	}
+	return u.upload(table, label, sysID, record, uploadedBy, evidence.SafeName(fileName), contentType, content, channel)
+}
+
+func (u *EvidenceUploader) upload(table, label, sysID string, record map[string]interface{}, uploadedBy, fileName, contentType string, content []byte, channel string) (*EvidenceUpload, error) {
+	logger := u.ServiceNowClient.Logger()
+	result := &EvidenceUpload{
+		Table:       table,
+		SysID:       sysID,
+		Number:      referenceValue(record["number"]),
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        len(content),
+		JiraIssue:   referenceValue(record["jira_ticket"]),
+	}
+	name := result.Number
+	if name == "" {
+		name = sysID
+	}
+
+	if u.Store != nil {
+		link, err := u.Store.Put(u.ServiceNowClient.Context(), evidence.ObjectKey(table, sysID, fileName, u.now()), contentType, content)
+		if err != nil {
+			logger.Error("error storing evidence", "table", table, "sys_id", sysID, "error", err)
+			result.Warnings = append(result.Warnings, "evidence storage: "+err.Error())
+		}
+		result.URL = link
+	}
+
+	attachment, err := u.ServiceNowClient.UploadAttachment(table, sysID, fileName, contentType, content)
+	if err != nil {
+		return nil, fmt.Errorf("error attaching evidence to %s %s: %w", label, name, err)
+	}
+	result.ServiceNowAttachment = attachment.SysID
+
+	note := fmt.Sprintf("Evidence %s uploaded (%d bytes).", fileName, len(content))
+	if uploadedBy != "" {
+		note = fmt.Sprintf("Evidence %s uploaded by %s (%d bytes).", fileName, uploadedBy, len(content))
+	}
+	if result.URL != "" {
+		note += "\nStored at " + result.URL
+	}
+	key := JournalKey("evidence", sysID, attachment.SysID)
+	if err := NewJournalWriter(u.ServiceNowClient).Write(table, sysID, JournalWorkNotes, key, note); err != nil {
+		logger.Warn("error adding evidence work note", "table", table, "sys_id", sysID, "error", err)
+	}
+
+	if result.JiraIssue != "" && u.JiraClient != nil {
+		if issueAttachment, err := u.JiraClient.AddAttachment(result.JiraIssue, fileName, contentType, content); err != nil {
+			logger.Error("error attaching evidence to Jira issue", "issue", result.JiraIssue, "sys_id", sysID, "error", err)
+			result.Warnings = append(result.Warnings, "jira attachment: "+err.Error())
+		} else {
+			result.JiraAttachment = issueAttachment.ID
+		}
+		comment := fmt.Sprintf("Evidence %s was attached to %s %s in ServiceNow.", fileName, label, name)
+		if result.URL != "" {
+			comment += "\nStored at " + result.URL
+		}
+		if err := u.JiraClient.AddComment(result.JiraIssue, comment); err != nil {
+			logger.Warn("error commenting evidence upload on Jira issue", "issue", result.JiraIssue, "error", err)
+		}
+	}
+
+	if err := u.notify(result, label, name, uploadedBy, channel); err != nil {
+		logger.Warn("error posting evidence upload to Slack", "sys_id", sysID, "error", err)
+		result.Warnings = append(result.Warnings, "slack: "+err.Error())
+	}
+	return result, nil
+}
+
+// notify posts the upload in the record's Slack thread, or in channel
+func (u *EvidenceUploader) notify(result *EvidenceUpload, label, name, uploadedBy, channel string) error {
+	threadTS := ""
+	if u.Threads != nil {
+		if thread, ok := u.Threads.Threads.Get(result.SysID); ok {
+			channel, threadTS = thread.Channel, thread.TS
+		}
+	}
+	if channel == "" {
+		return nil
+	}
+
+	text := fmt.Sprintf("📎 Evidence `%s` was attached to %s %s", result.FileName, label, name)
+	if uploadedBy != "" {
+		text = fmt.Sprintf("📎 %s attached evidence `%s` to %s %s", uploadedBy, result.FileName, label, name)
+	}
+	if result.JiraAttachment != "" {
+		text += fmt.Sprintf(" and Jira issue %s", result.JiraIssue)
+	}
+	if result.URL != "" {
+		text += fmt.Sprintf("\n<%s|View the stored file>", result.URL)
+	}
+
+	if threadTS != "" {
+		_, err := u.SlackClient.PostReply(channel, threadTS, slack.Message{Text: text})
+		return err
+	}
+	_, err := u.SlackClient.PostMessage(channel, slack.Message{Text: text})
+	return err
+}
+
+// Fetch downloads an evidence file from one of the fetch hosts. Files on
+// Slack hosts are fetched with the bot token, as Slack requires.
+func (u *EvidenceUploader) Fetch(fileURL string) (string, []byte, error) {
+	parsed, err := url.Parse(fileURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", nil, fmt.Errorf("%q is not a file URL", fileURL)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if !u.fetchable(host) {
+		return "", nil, fmt.Errorf("files cannot be fetched from %s; allowed hosts: %s", host, strings.Join(u.FetchHosts, ", "))
+	}
+
+	req, err := http.NewRequestWithContext(u.ServiceNowClient.Context(), "GET", parsed.String(), nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if host == "slack.com" || strings.HasSuffix(host, ".slack.com") {
+		req.Header.Set("Authorization", "Bearer "+u.SlackClient.Token.Get())
+	}
+
+	resp, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("error downloading %s: %w", fileURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("error downloading %s: status %d", fileURL, resp.StatusCode)
+	}
+
+	// Read one byte past the limit so oversized files fail the policy check
+	limit := u.Policy.MaxBytes
+	if limit <= 0 {
+		limit = evidence.DefaultMaxBytes
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return "", nil, fmt.Errorf("error downloading %s: %w", fileURL, err)
+	}
+	return path.Base(parsed.Path), content, nil
+}
+
+// fetchable reports whether host is one of the fetch hosts or a subdomain of one
+func (u *EvidenceUploader) fetchable(host string) bool {
+	for _, allowed := range u.FetchHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleUploadCommand runs /upload-evidence RECORD_ID FILE_URL. The file is
+// fetched and attached in the background, as it may take longer than Slack
+// waits for a reply; the outcome is posted in the channel.
+func (u *EvidenceUploader) HandleUploadCommand(command *slack.Command, recordID, fileURL string) (string, error) {
+	parsed, err := url.Parse(fileURL)
+	if err != nil || !u.fetchable(strings.ToLower(parsed.Hostname())) {
+		return fmt.Sprintf("Evidence can only be fetched from %s.", strings.Join(u.FetchHosts, ", ")), nil
+	}
+
+	go func() {
+		if _, err := u.uploadFromURL(recordID, fileURL, command.ChannelID, "@"+command.UserName); err != nil {
+			u.ServiceNowClient.Logger().Error("error uploading evidence from Slack", "sys_id", recordID, "url", fileURL, "error", err)
+			message := slack.Message{Text: fmt.Sprintf("⚠️ <@%s> evidence for %s was not uploaded: %v", command.UserID, recordID, err)}
+			if _, err := u.SlackClient.PostMessage(command.ChannelID, message); err != nil {
+				u.SlackClient.Logger().Warn("error posting evidence upload failure", "sys_id", recordID, "error", err)
+			}
+		}
+	}()
+	return fmt.Sprintf("Uploading evidence to %s...", recordID), nil
+}
+
+// uploadFromURL attaches the file at fileURL to whichever evidence table holds recordID
+func (u *EvidenceUploader) uploadFromURL(recordID, fileURL, channelID, uploadedBy string) (*EvidenceUpload, error) {
+	table, record, err := u.Find(recordID)
+	if errors.Is(err, ErrRecordNotFound) {
+		return nil, fmt.Errorf("no control test or compliance task has the ID %s", recordID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fileName, content, err := u.Fetch(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	contentType, err := u.Policy.Check(fileName, content)
+	if err != nil {
+		return nil, err
+	}
+	return u.upload(table, EvidenceTables[table], recordID, record, uploadedBy, evidence.SafeName(fileName), contentType, content, channelID)
+}
@@ -0,0 +1,223 @@
+// backend/internal/integrations/servicenow/github_issues.go
+package servicenow
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/github"
+)
+
+// GitHubStates are the ServiceNow states a record is moved to when its GitHub
+// issue is closed or reopened
+type GitHubStates struct {
+	Closed   string
+	Reopened string
+}
+
+// GitHubIssueStates lists the tables filed as GitHub issues and their states
+var GitHubIssueStates = map[string]GitHubStates{
+	"sn_audit_finding":   {Closed: "resolved", Reopened: "open"},
+	"sn_compliance_task": {Closed: "complete", Reopened: "open"},
+}
+
+// closedRecordStates are ServiceNow states whose GitHub issue should be closed
+var closedRecordStates = map[string]bool{
+	"closed": true, "resolved": true, "complete": true, "completed": true, "cancelled": true, "canceled": true,
+}
+
+// GitHubIssues files audit findings and compliance tasks as GitHub issues and
+// applies issue webhooks back to the records. A nil *GitHubIssues does nothing,
+// so handlers can hold one whether or not GitHub is configured.
+type GitHubIssues struct {
+	ServiceNowClient *Client
+	GitHubClient     *github.Client
+	Mapping          *github.IssueMapping
+	JournalWriter    *JournalWriter
+}
+
+// NewGitHubIssues creates the GitHub issue sync
+func NewGitHubIssues(serviceNowClient *Client, gitHubClient *github.Client, mapping *github.IssueMapping) *GitHubIssues {
+	return &GitHubIssues{
+		ServiceNowClient: serviceNowClient,
+		GitHubClient:     gitHubClient,
+		Mapping:          mapping,
+		JournalWriter:    NewJournalWriter(serviceNowClient),
+	}
+}
+
+// WithContext returns a copy whose API calls run under ctx
+func (g *GitHubIssues) WithContext(ctx context.Context) *GitHubIssues {
+	if g == nil {
+		return nil
+	}
+	copied := *g
+	copied.ServiceNowClient = g.ServiceNowClient.WithContext(ctx)
+	copied.GitHubClient = g.GitHubClient.WithContext(ctx)
+	copied.JournalWriter = NewJournalWriter(copied.ServiceNowClient)
+	return &copied
+}
+
+// FileAuditFinding opens an issue for a finding, or updates the one already filed
+func (g *GitHubIssues) FileAuditFinding(finding AuditFinding) (*github.Issue, error) {
+	if g == nil {
+		return nil, nil
+	}
+	body := fmt.Sprintf("**Audit finding %s** from ServiceNow\n\n"+
+		"- **Audit:** %s\n- **Severity:** %s\n- **Due date:** %s\n- **ServiceNow:** %s\n\n%s",
+		finding.Number, finding.Audit, finding.Severity, finding.DueDate.Format("Jan 2, 2006"),
+		g.recordURL("sn_audit_finding", finding.ID), finding.Description)
+	labels := github.WithSeverityLabel([]string{"audit-finding"}, finding.Severity)
+	return g.file("sn_audit_finding", finding.ID, fmt.Sprintf("[%s] %s", finding.Number, finding.ShortDesc), body, labels, finding.Severity, finding.State)
+}
+
+// FileComplianceTask opens an issue for a compliance task, or updates the one already filed
+func (g *GitHubIssues) FileComplianceTask(task ComplianceTask) (*github.Issue, error) {
+	if g == nil {
+		return nil, nil
+	}
+	body := fmt.Sprintf("**Compliance task %s** from ServiceNow\n\n"+
+		"- **Framework:** %s\n- **Regulation:** %s\n- **Due date:** %s\n- **ServiceNow:** %s\n\n%s",
+		task.Number, task.Framework, task.Regulation, task.DueDate.Format("Jan 2, 2006"),
+		g.recordURL("sn_compliance_task", task.ID), task.Description)
+	return g.file("sn_compliance_task", task.ID, fmt.Sprintf("[%s] %s", task.Number, task.ShortDesc), body, []string{"compliance-task"}, "", task.State)
+}
+
+// SyncAuditFinding updates the issue filed for a finding; findings without one are skipped
+func (g *GitHubIssues) SyncAuditFinding(finding AuditFinding) error {
+	if g == nil {
+		return nil
+	}
+	if _, ok := g.Mapping.GetIssue("sn_audit_finding", finding.ID); !ok {
+		return nil
+	}
+	_, err := g.FileAuditFinding(finding)
+	return err
+}
+
+// SyncComplianceTask updates the issue filed for a compliance task; tasks without one are skipped
+func (g *GitHubIssues) SyncComplianceTask(task ComplianceTask) error {
+	if g == nil {
+		return nil
+	}
+	if _, ok := g.Mapping.GetIssue("sn_compliance_task", task.ID); !ok {
+		return nil
+	}
+	_, err := g.FileComplianceTask(task)
+	return err
+}
+
+// file creates or updates the issue linked to a record. Updates keep labels
+// added on GitHub and only swap the severity label.
+func (g *GitHubIssues) file(table, sysID, title, body string, labels []string, severity, state string) (*github.Issue, error) {
+	number, linked := g.Mapping.GetIssue(table, sysID)
+	if !linked {
+		issue, err := g.GitHubClient.CreateIssue(&github.IssueRequest{Title: title, Body: body, Labels: labels})
+		if err != nil {
+			return nil, err
+		}
+		if err := g.Mapping.AddMapping(table, sysID, issue.Number); err != nil {
+			return issue, fmt.Errorf("error saving GitHub issue link: %w", err)
+		}
+		g.ServiceNowClient.Logger().Info("filed GitHub issue", "table", table, "sys_id", sysID, "issue", issue.Number)
+		return issue, nil
+	}
+
+	current, err := g.GitHubClient.GetIssue(number)
+	if err != nil {
+		return nil, err
+	}
+	request := &github.IssueRequest{
+		Title:  title,
+		Body:   body,
+		State:  issueState(state),
+		Labels: github.WithSeverityLabel(current.LabelNames(), severity),
+	}
+	if severity == "" {
+		request.Labels = nil
+	}
+	return g.GitHubClient.UpdateIssue(number, request)
+}
+
+// HandleIssueEvent applies an issues or issue_comment webhook to the linked
+// record: closing and reopening change its state and comments become work
+// notes. It reports whether the issue belonged to a record.
+func (g *GitHubIssues) HandleIssueEvent(eventType string, event *github.WebhookEvent) (bool, error) {
+	if g == nil || event.Issue == nil {
+		return false, nil
+	}
+	table, sysID, ok := g.Mapping.GetRecord(event.Issue.Number)
+	if !ok {
+		return false, nil
+	}
+
+	switch {
+	case eventType == "issues" && (event.Action == "closed" || event.Action == "reopened"):
+		return true, g.applyState(table, sysID, event)
+
+	case eventType == "issue_comment" && event.Action == "created" && event.Comment != nil:
+		note := fmt.Sprintf("GitHub comment by %s on #%d:\n%s", event.Comment.User.Login, event.Issue.Number, event.Comment.Body)
+		key := JournalKey("github", strconv.Itoa(event.Issue.Number), "comment", strconv.FormatInt(event.Comment.ID, 10))
+		if err := g.JournalWriter.Write(table, sysID, JournalWorkNotes, key, note); err != nil {
+			return true, fmt.Errorf("error copying GitHub comment to %s %s: %w", table, sysID, err)
+		}
+	}
+	return true, nil
+}
+
+// applyState moves a record to the state for a closed or reopened issue. A
+// record that is already closed or open is left alone, so issues closed by our
+// own updates do not write back.
+func (g *GitHubIssues) applyState(table, sysID string, event *github.WebhookEvent) error {
+	states, ok := GitHubIssueStates[table]
+	if !ok {
+		return nil
+	}
+	state := states.Reopened
+	if event.Action == "closed" {
+		state = states.Closed
+	}
+
+	record, err := g.ServiceNowClient.GetRecord(table, sysID)
+	if err != nil {
+		return fmt.Errorf("error reading %s %s: %w", table, sysID, err)
+	}
+	current, _ := record["state"].(string)
+	if issueState(current) == issueState(state) {
+		return nil
+	}
+
+	data := map[string]interface{}{"state": state}
+	if table == "sn_audit_finding" && event.Action == "closed" {
+		data["resolution"] = fmt.Sprintf("Closed in GitHub issue #%d by %s", event.Issue.Number, event.Sender.Login)
+	}
+	if err := g.ServiceNowClient.UpdateRecord(table, sysID, data); err != nil {
+		return fmt.Errorf("error updating %s %s from GitHub: %w", table, sysID, err)
+	}
+
+	note := fmt.Sprintf("GitHub issue #%d was %s by %s: %s", event.Issue.Number, event.Action, event.Sender.Login, event.Issue.HTMLURL)
+	key := JournalKey("github", strconv.Itoa(event.Issue.Number), event.Action, event.Issue.UpdatedAt)
+	if err := g.JournalWriter.Write(table, sysID, JournalWorkNotes, key, note); err != nil {
+		g.ServiceNowClient.Logger().Warn("error writing GitHub state note", "table", table, "sys_id", sysID, "error", err)
+	}
+	return nil
+}
+
+// recordURL links to a record in the ServiceNow UI
+func (g *GitHubIssues) recordURL(table, sysID string) string {
+	return fmt.Sprintf("%s/nav_to.do?uri=%s.do?sys_id=%s", g.ServiceNowClient.BaseURL, table, sysID)
+}
+
+// issueState is the GitHub state for a ServiceNow state; an unknown state
+// leaves the issue's state alone
+func issueState(state string) string {
+	if state == "" {
+		return ""
+	}
+	if closedRecordStates[strings.ToLower(state)] {
+		return github.StateClosed
+	}
+	return github.StateOpen
+}
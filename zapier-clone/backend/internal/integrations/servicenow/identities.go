@@ -0,0 +1,32 @@
+// backend/internal/integrations/servicenow/identities.go
+package servicenow
+
+import "context"
+
+// Identities translates people between ServiceNow, Jira and Slack. ServiceNow
+// users are given as a sys_id or user_name. Each method returns "" for a person
+// without a known account, and callers then keep the value they had.
+type Identities interface {
+	JiraAccountID(ctx context.Context, serviceNowUser string) string
+	SlackUserID(ctx context.Context, serviceNowUser string) string
+	ServiceNowUserID(ctx context.Context, slackUserID string) string
+}
+
+// serviceNowAssignee returns the sys_user to assign for a Slack user, or the
+// Slack ID itself when identities are not configured or the user is unmapped
+func serviceNowAssignee(ctx context.Context, identities Identities, slackUserID string) string {
+	if identities != nil {
+		if sysID := identities.ServiceNowUserID(ctx, slackUserID); sysID != "" {
+			return sysID
+		}
+	}
+	return slackUserID
+}
+
+// jiraAccount returns the Jira account of a ServiceNow user, or "" when unknown
+func jiraAccount(ctx context.Context, identities Identities, serviceNowUser string) string {
+	if identities == nil || serviceNowUser == "" {
+		return ""
+	}
+	return identities.JiraAccountID(ctx, serviceNowUser)
+}
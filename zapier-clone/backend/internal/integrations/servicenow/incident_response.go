@@ -2,13 +2,18 @@
 package servicenow
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
 )
 
 // IncidentHandler handles security incident notifications and interactions
@@ -17,13 +22,34 @@ type IncidentHandler struct {
 	SlackClient         *slack.Client
 	JiraClient          *jira.Client
 	IncidentJiraMapping *jira.IncidentJiraMapping
+	JournalWriter       *JournalWriter
+	// FieldMapping builds the Jira epic from incident records
+	FieldMapping *mapping.Engine
+	// Threads keeps the announcement of each incident so updates reply in its thread
+	Threads *ThreadTracker
+	// Routes picks the channels new incidents are announced in
+	Routes *notification.Router
+	// Identities maps assignees to their Jira and ServiceNow accounts when configured
+	Identities Identities
+	// Approvals holds back the Jira epic of configured severities until approved in Slack
+	Approvals *ApprovalGate
+	// Projects picks the Jira project when no mapping does
+	Projects *ProjectProvisioner
+	// Escalations notifies the next person in a chain when nobody acknowledges an announcement in time
+	Escalations *Escalator
+	// Duplicates looks for an existing issue before an epic is created for an incident
+	Duplicates *DuplicateDetector
+	// WarRooms opens a Slack channel for major incidents and archives it on resolution
+	WarRooms *WarRooms
+	// Reviews drafts a post-incident review once an incident resolves
+	Reviews *PostIncidentReviews
 }
 
 // NewIncidentHandler creates a new incident handler
 func NewIncidentHandler(serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client) *IncidentHandler {
-	incidentJiraMapping, err := jira.NewIncidentJiraMapping("./data")
+	incidentJiraMapping, err := jira.NewIncidentJiraMapping(serviceNowClient.DataDir)
 	if err != nil {
-		log.Printf("Warning: Failed to initialize incident-jira mapping: %v", err)
+		slog.Error("error initializing incident-jira mapping", "error", err)
 		// Create an empty mapping as fallback
 		incidentJiraMapping = &jira.IncidentJiraMapping{
 			IncidentIDToJiraKey: make(map[string]string),
@@ -36,9 +62,21 @@ func NewIncidentHandler(serviceNowClient *Client, slackClient *slack.Client, jir
 		SlackClient:         slackClient,
 		JiraClient:          jiraClient,
 		IncidentJiraMapping: incidentJiraMapping,
+		JournalWriter:       NewJournalWriter(serviceNowClient),
+		FieldMapping:        mapping.NewEngine(mapping.Default()),
 	}
 }
 
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *IncidentHandler) WithContext(ctx context.Context) *IncidentHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	copied.JiraClient = h.JiraClient.WithContext(ctx)
+	copied.Threads = h.Threads.WithContext(ctx)
+	return &copied
+}
+
 // HandleNewIncident processes a new security incident and notifies Slack
 func (h *IncidentHandler) HandleNewIncident(incident Incident) (string, error) {
 	// Determine the emoji based on severity
@@ -54,113 +92,158 @@ func (h *IncidentHandler) HandleNewIncident(incident Incident) (string, error) {
 		severityEmoji = "🟢"
 	}
 
-	// Create Jira epic for the incident
-	epic, err := h.createJiraEpic(incident)
-	if err != nil {
-		log.Printf("Error creating Jira epic for incident %s: %v", incident.ID, err)
-		// Continue execution - we'll just post to Slack without the Jira integration
-	} else {
-		// Save the mapping between ServiceNow incident and Jira epic
-		err = h.IncidentJiraMapping.AddMapping(incident.ID, epic.Key)
-		if err != nil {
-			log.Printf("Error saving incident-jira mapping: %v", err)
+	// Create Jira epic for the incident, unless its severity needs approval in
+	// the thread first. One that looks like an existing issue is linked to it
+	// or, depending on the duplicate rule, held for the thread to decide.
+	needsApproval := h.Approvals.Required("sn_si_incident", incident.Severity)
+	duplicate := h.duplicateOf(incident)
+	if duplicate != nil && duplicate.Action == DuplicateLink {
+		needsApproval = false
+		if err := h.LinkDuplicate(incident.ID, incident.Number, duplicate); err != nil {
+			h.ServiceNowClient.Logger().Error("error linking incident to duplicate Jira issue", "sys_id", incident.ID, "error", err)
+		}
+	} else if duplicate != nil {
+		needsApproval = true
+	}
+	if !needsApproval && duplicate == nil {
+		if _, err := h.linkEpic(incident); err != nil {
+			h.ServiceNowClient.Logger().Error("error creating Jira epic for incident", "sys_id", incident.ID, "error", err)
+			// Continue execution - we'll just post to Slack without the Jira integration
 		}
-
-		// Create standard subtasks for incident response
-		h.createIncidentSubtasks(incident, epic.Key)
 	}
 
-	// Create a Slack message for the incident
-	message := slack.Message{
-		Blocks: []slack.Block{
-			{
-				Type: "header",
-				Text: slack.NewTextObject("plain_text", fmt.Sprintf("⚠️ Urgent: %s", incident.ShortDesc), true),
-			},
-			{
-				Type: "section",
-				Fields: []*slack.TextObject{
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Incident ID:*\n%s", incident.Number), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Category:*\n%s", incident.Category), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Severity:*\n%s %s", severityEmoji, incident.Severity), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Impact:*\n%s", incident.Impact), false),
+	// Create the Slack message for the incident in a channel's language
+	build := func(locale string) slack.Message {
+		return slack.Message{
+			Blocks: []slack.Block{
+				{
+					Type: "header",
+					Text: slack.NewTextObject("plain_text", i18n.T(locale, "incident.header", incident.ShortDesc), true),
 				},
-			},
-			{
-				Type: "section",
-				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Description:*\n%s", incident.Description), false),
-			},
-			{
-				Type: "actions",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "🚨 Acknowledge",
-							"emoji": true,
-						},
-						"style":     "primary",
-						"value":     fmt.Sprintf("ack_incident_%s", incident.ID),
-						"action_id": "acknowledge_incident",
+				{
+					Type: "section",
+					Fields: []*slack.TextObject{
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "incident.field.number", incident.Number), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.category", incident.Category), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.severity", severityEmoji+" "+i18n.Value(locale, "severity", incident.Severity)), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "incident.field.impact", i18n.Value(locale, "impact", incident.Impact)), false),
 					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "📝 Add Update",
-							"emoji": true,
+				},
+				{
+					Type: "section",
+					Text: slack.NewTextObject("mrkdwn", i18n.T(locale, "field.description", incident.Description), false),
+				},
+				{
+					Type: "actions",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "incident.button.acknowledge"),
+								"emoji": true,
+							},
+							"style":     "primary",
+							"value":     fmt.Sprintf("ack_incident_%s", incident.ID),
+							"action_id": "acknowledge_incident",
 						},
-						"value":     fmt.Sprintf("update_incident_%s", incident.ID),
-						"action_id": "update_incident",
-					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "✅ Resolve",
-							"emoji": true,
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "incident.button.update"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("update_incident_%s", incident.ID),
+							"action_id": "update_incident",
 						},
-						"style":     "danger",
-						"value":     fmt.Sprintf("resolve_incident_%s", incident.ID),
-						"action_id": "resolve_incident",
-					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "View in ServiceNow",
-							"emoji": true,
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "incident.button.resolve"),
+								"emoji": true,
+							},
+							"style":     "danger",
+							"value":     fmt.Sprintf("resolve_incident_%s", incident.ID),
+							"action_id": "resolve_incident",
+						},
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.view_servicenow"),
+								"emoji": true,
+							},
+							"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_si_incident.do?sys_id=%s", h.ServiceNowClient.BaseURL, incident.ID),
+							"action_id": "view_incident",
 						},
-						"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_si_incident.do?sys_id=%s", h.ServiceNowClient.BaseURL, incident.ID),
-						"action_id": "view_incident",
 					},
 				},
-			},
-			{
-				Type: "context",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "mrkdwn",
-						"text": "📍 Immediate Action Required! Security, IT, and Legal teams should coordinate response.",
+				{
+					Type: "context",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "mrkdwn",
+							"text": strings.TrimSpace(i18n.T(locale, "incident.context", h.SlackClient.MentionCategory("incident"))),
+						},
 					},
 				},
 			},
-		},
+		}
 	}
 
-	// Post the message to the incident-response channel
-	ts, err := h.SlackClient.PostMessage(slack.ChannelMapping["incident"], message)
+	// Post the message to the channels routed for the incident, incident-response by default
+	item := notification.Item{
+		Type:     "incident",
+		Severity: incident.Severity,
+		Category: incident.Category,
+		Number:   incident.Number,
+		Title:    incident.ShortDesc,
+		URL:      fmt.Sprintf("%s/nav_to.do?uri=sn_si_incident.do?sys_id=%s", h.ServiceNowClient.BaseURL, incident.ID),
+	}
+	channel, ts, err := announce(h.SlackClient, h.Routes, item, build)
 	if err != nil {
 		return "", fmt.Errorf("error posting incident message to Slack: %w", err)
 	}
+	message := build(h.Routes.Locale(channel))
+	h.Threads.Start("sn_si_incident", incident.ID, incident.Number, incident.State, incident.AssignedTo, channel, ts, message)
+	h.Escalations.Watch(incident, channel, ts)
+	if h.WarRooms.IsMajor(incident) {
+		if _, err := h.WarRooms.Open(h.ServiceNowClient.Context(), incident, h.Identities); err != nil {
+			h.ServiceNowClient.Logger().Error("error opening war room for major incident", "sys_id", incident.ID, "error", err)
+		}
+	}
+
+	if needsApproval {
+		if ts == "" {
+			// Held for a digest, so there is no thread to ask in
+			if _, err := h.linkEpic(incident); err != nil {
+				h.ServiceNowClient.Logger().Error("error creating Jira epic for incident", "sys_id", incident.ID, "error", err)
+			}
+		} else {
+			approval := Approval{
+				ID:        incident.ID,
+				Table:     "sn_si_incident",
+				Number:    incident.Number,
+				Severity:  incident.Severity,
+				Title:     incident.ShortDesc,
+				Duplicate: duplicate,
+				Channel:   channel,
+				ThreadTS:  ts,
+			}
+			if err := h.Approvals.Request(h.SlackClient, approval, incident); err != nil {
+				return ts, err
+			}
+		}
+	}
 
 	// For critical incidents, also add a reaction to draw attention
-	if strings.ToLower(incident.Severity) == "critical" {
-		err = h.SlackClient.AddReaction(slack.ChannelMapping["incident"], ts, "rotating_light")
+	if strings.ToLower(incident.Severity) == "critical" && ts != "" {
+		err = h.SlackClient.AddReaction(channel, ts, "rotating_light")
 		if err != nil {
 			// Non-fatal error, just log it
-			fmt.Printf("Error adding reaction to incident message: %v\n", err)
+			h.ServiceNowClient.Logger().Warn("error adding reaction to incident message", "sys_id", incident.ID, "error", err)
 		}
 	}
 
@@ -169,6 +252,8 @@ func (h *IncidentHandler) HandleNewIncident(incident Incident) (string, error) {
 
 // HandleIncidentAcknowledgment processes incident acknowledgment
 func (h *IncidentHandler) HandleIncidentAcknowledgment(incidentID, channelID, threadTS, userID string) error {
+	h.Escalations.Acknowledge(incidentID, userID)
+
 	// Post the acknowledgment notification to the thread
 	message := slack.Message{
 		Text: fmt.Sprintf("<@%s> has acknowledged this incident and is investigating.", userID),
@@ -182,9 +267,8 @@ func (h *IncidentHandler) HandleIncidentAcknowledgment(incidentID, channelID, th
 
 	// Update ServiceNow with the assignment and state change
 	body := map[string]string{
-		"assigned_to": userID,
+		"assigned_to": serviceNowAssignee(h.ServiceNowClient.Context(), h.Identities, userID),
 		"state":       "in_progress",
-		"work_notes":  fmt.Sprintf("Incident acknowledged by %s via Slack integration", userID),
 	}
 
 	_, err = h.ServiceNowClient.makeRequest("PATCH", fmt.Sprintf("api/now/table/sn_si_incident/%s", incidentID), body)
@@ -192,6 +276,14 @@ func (h *IncidentHandler) HandleIncidentAcknowledgment(incidentID, channelID, th
 		return fmt.Errorf("error updating incident acknowledgment in ServiceNow: %w", err)
 	}
 
+	// Record the acknowledgment in the incident's work notes
+	err = h.JournalWriter.Write("sn_si_incident", incidentID, JournalWorkNotes,
+		JournalKey("incident-ack", incidentID, userID),
+		fmt.Sprintf("Incident acknowledged by %s via Slack integration", userID))
+	if err != nil {
+		return fmt.Errorf("error adding acknowledgment work note in ServiceNow: %w", err)
+	}
+
 	return nil
 }
 
@@ -209,11 +301,9 @@ func (h *IncidentHandler) HandleIncidentUpdate(incidentID, channelID, threadTS,
 	}
 
 	// Update ServiceNow with the note
-	body := map[string]string{
-		"work_notes": fmt.Sprintf("Update from %s via Slack: %s", userID, updateText),
-	}
-
-	_, err = h.ServiceNowClient.makeRequest("PATCH", fmt.Sprintf("api/now/table/sn_si_incident/%s", incidentID), body)
+	err = h.JournalWriter.Write("sn_si_incident", incidentID, JournalWorkNotes,
+		JournalKey("incident-update", incidentID, userID, updateText),
+		fmt.Sprintf("Update from %s via Slack: %s", userID, updateText))
 	if err != nil {
 		return fmt.Errorf("error updating incident notes in ServiceNow: %w", err)
 	}
@@ -223,7 +313,7 @@ func (h *IncidentHandler) HandleIncidentUpdate(incidentID, channelID, threadTS,
 	if exists {
 		commentText := fmt.Sprintf("Update from %s:\n\n%s", userID, updateText)
 		if err := h.JiraClient.AddComment(jiraKey, commentText); err != nil {
-			log.Printf("Error adding update comment to Jira: %s\n", err)
+			h.ServiceNowClient.Logger().Error("error adding update comment to Jira", "issue", jiraKey, "error", err)
 		}
 	}
 
@@ -232,6 +322,9 @@ func (h *IncidentHandler) HandleIncidentUpdate(incidentID, channelID, threadTS,
 
 // HandleIncidentResolution processes incident resolution
 func (h *IncidentHandler) HandleIncidentResolution(incidentID, channelID, threadTS, userID, resolutionNotes string) error {
+	// Resolving an incident also acknowledges it, so nobody else is paged
+	h.Escalations.Acknowledge(incidentID, userID)
+
 	// Post the resolution to the thread
 	message := slack.Message{
 		Text: fmt.Sprintf("<@%s> has resolved this incident: %s", userID, resolutionNotes),
@@ -256,106 +349,152 @@ func (h *IncidentHandler) HandleIncidentResolution(incidentID, channelID, thread
 		return fmt.Errorf("error updating incident resolution in ServiceNow: %w", err)
 	}
 
+	if err := h.WarRooms.Archive(h.ServiceNowClient.Context(), incidentID, resolutionNotes); err != nil {
+		h.ServiceNowClient.Logger().Error("error archiving war room", "sys_id", incidentID, "error", err)
+	}
+	h.ReviewIfResolved(Incident{ID: incidentID, State: "resolved", ResolutionNotes: resolutionNotes})
+
 	return nil
 }
 
-// ProcessIncidentCommand handles slash commands for incidents
-func (h *IncidentHandler) ProcessIncidentCommand(command *slack.Command) (string, error) {
-	// Handle different incident commands
-	switch {
-	case command.Command == "/incident-update":
-		// Format: /incident-update INCIDENT_ID UPDATE_TEXT
-		parts := strings.SplitN(command.Text, " ", 2)
-		if len(parts) < 2 {
-			return "Invalid command format. Usage: /incident-update INCIDENT_ID UPDATE_TEXT", nil
-		}
+// ReviewIfResolved drafts the post-incident review of an incident that was
+// resolved or closed; failures are logged
+func (h *IncidentHandler) ReviewIfResolved(incident Incident) {
+	if _, err := h.Reviews.Resolved(h.ServiceNowClient.Context(), incident); err != nil {
+		h.ServiceNowClient.Logger().Error("error drafting post-incident review", "sys_id", incident.ID, "error", err)
+	}
+}
 
-		incidentID := parts[0]
-		updateText := parts[1]
+// SyncWarRoom opens, refreshes or archives an incident's war room after it
+// changed in ServiceNow
+func (h *IncidentHandler) SyncWarRoom(incident Incident) error {
+	return h.WarRooms.Sync(h.ServiceNowClient.Context(), incident, h.Identities)
+}
 
-		err := h.HandleIncidentUpdate(incidentID, command.ChannelID, "", command.UserID, updateText)
-		if err != nil {
-			return fmt.Sprintf("Error updating incident: %s", err), nil
-		}
+// RegisterCommands adds the incident slash commands to the router
+func (h *IncidentHandler) RegisterCommands(router *slack.CommandRouter) error {
+	if err := router.Register(slack.SlashCommand{
+		Name:        "/incident-update",
+		Usage:       "INCIDENT_ID UPDATE_TEXT",
+		Description: "Post an update on a security incident",
+		Args:        2,
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			if err := h.HandleIncidentUpdate(args[0], command.ChannelID, "", command.UserID, args[1]); err != nil {
+				return "", fmt.Errorf("error updating incident: %w", err)
+			}
+			return "Incident update posted successfully!", nil
+		},
+	}); err != nil {
+		return err
+	}
 
-		return "Incident update posted successfully!", nil
+	return router.Register(slack.SlashCommand{
+		Name:        "/resolve-incident",
+		Usage:       "INCIDENT_ID RESOLUTION_NOTES",
+		Description: "Resolve a security incident",
+		Args:        2,
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			if err := h.HandleIncidentResolution(args[0], command.ChannelID, "", command.UserID, args[1]); err != nil {
+				return "", fmt.Errorf("error resolving incident: %w", err)
+			}
+			return "Incident resolved successfully!", nil
+		},
+	})
+}
 
-	case command.Command == "/resolve-incident":
-		// Format: /resolve-incident INCIDENT_ID RESOLUTION_NOTES
-		parts := strings.SplitN(command.Text, " ", 2)
-		if len(parts) < 2 {
-			return "Invalid command format. Usage: /resolve-incident INCIDENT_ID RESOLUTION_NOTES", nil
-		}
+// CreateApprovedEpic creates and links the Jira epic of an incident whose
+// approval was granted, returning its key
+func (h *IncidentHandler) CreateApprovedEpic(approval *Approval) (string, error) {
+	var incident Incident
+	if err := json.Unmarshal(approval.Record, &incident); err != nil {
+		return "", fmt.Errorf("error reading approved incident %s: %w", approval.Number, err)
+	}
 
-		incidentID := parts[0]
-		resolutionNotes := parts[1]
+	epic, err := h.linkEpic(incident)
+	if err != nil {
+		return "", fmt.Errorf("error creating Jira epic for incident %s: %w", approval.Number, err)
+	}
+	return epic.Key, nil
+}
 
-		err := h.HandleIncidentResolution(incidentID, command.ChannelID, "", command.UserID, resolutionNotes)
-		if err != nil {
-			return fmt.Sprintf("Error resolving incident: %s", err), nil
-		}
+// LinkDuplicate links an incident to the existing issue it duplicates instead
+// of creating an epic, and notes it on the issue
+func (h *IncidentHandler) LinkDuplicate(incidentID, number string, duplicate *DuplicateMatch) error {
+	if err := h.IncidentJiraMapping.AddMapping(incidentID, duplicate.Key); err != nil {
+		return fmt.Errorf("error saving incident-jira mapping: %w", err)
+	}
+	if err := noteDuplicate(h.JiraClient, "sn_si_incident", number, duplicate); err != nil {
+		h.ServiceNowClient.Logger().Warn("error noting duplicate on Jira issue", "issue", duplicate.Key, "sys_id", incidentID, "error", err)
+	}
+	return nil
+}
 
-		return "Incident resolved successfully!", nil
+// duplicateOf returns the issue a new incident may duplicate, or nil. A check
+// that fails is logged and the epic is created as usual.
+func (h *IncidentHandler) duplicateOf(incident Incident) *DuplicateMatch {
+	if _, ok := h.Duplicates.Rule("sn_si_incident"); !ok {
+		return nil
+	}
+	ticket, err := h.epicTicket(incident)
+	if err != nil {
+		return nil
+	}
+	duplicate, err := h.Duplicates.Find(h.JiraClient, "sn_si_incident", incident.Number, ticket)
+	if err != nil {
+		h.ServiceNowClient.Logger().Warn("error checking for duplicate Jira issues", "sys_id", incident.ID, "error", err)
+		return nil
+	}
+	return duplicate
+}
 
-	default:
-		return "Unknown command", nil
+// linkEpic creates an incident's Jira epic with its response subtasks and
+// links it to the incident. A failure to store the link is only logged.
+func (h *IncidentHandler) linkEpic(incident Incident) (*jira.Ticket, error) {
+	epic, err := h.createJiraEpic(incident)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the mapping between ServiceNow incident and Jira epic
+	if err := h.IncidentJiraMapping.AddMapping(incident.ID, epic.Key); err != nil {
+		h.ServiceNowClient.Logger().Error("error saving incident-jira mapping", "sys_id", incident.ID, "error", err)
 	}
+
+	// Create standard subtasks for incident response
+	h.createIncidentSubtasks(incident, epic.Key)
+	return epic, nil
 }
 
 // createJiraEpic creates a Jira epic for an incident
 func (h *IncidentHandler) createJiraEpic(incident Incident) (*jira.Ticket, error) {
-	// Map ServiceNow incident severity to Jira priority
-	priority := "Medium" // Default priority
-	switch strings.ToLower(incident.Severity) {
-	case "critical":
-		priority = "Highest"
-	case "high":
-		priority = "High"
-	case "medium":
-		priority = "Medium"
-	case "low":
-		priority = "Low"
-	}
-
-	// Create formatted description with details from ServiceNow
-	description := fmt.Sprintf(`*Incident Details from ServiceNow*
-    
-*Incident Number:* %s
-*Category:* %s
-*Severity:* %s
-*Impact:* %s
-    
-*Description:*
-%s
-
-----
-This epic was automatically created from ServiceNow Incident %s.
-Please update both systems when changes are made.`,
-		incident.Number,
-		incident.Category,
-		incident.Severity,
-		incident.Impact,
-		incident.Description,
-		incident.Number)
-
-	// Create an Epic in the "Incident Response" project
-	ticket := &jira.Ticket{
-		Project:     h.JiraClient.ProjectKey, // Use "IR" or another project key for Incident Response
-		IssueType:   "Epic",
-		Summary:     fmt.Sprintf("[INCIDENT] %s", incident.ShortDesc),
-		Description: description,
-		Priority:    priority,
-		Labels:      []string{"security-incident", "auto-created", strings.ToLower(incident.Category)},
-		Epic: &jira.EpicDetails{
-			Name:  fmt.Sprintf("Incident: %s", incident.ShortDesc),
-			Color: "red", // Color for the epic
-		},
+	ticket, err := h.epicTicket(incident)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the Jira epic
 	return h.JiraClient.CreateIssue(ticket)
 }
 
+// epicTicket builds the Jira epic of an incident
+func (h *IncidentHandler) epicTicket(incident Incident) (*jira.Ticket, error) {
+	record, err := mapping.Record(incident)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := h.FieldMapping.Ticket("sn_si_incident", record)
+	if err != nil {
+		return nil, err
+	}
+	h.Projects.Route("sn_si_incident", ticket, h.JiraClient.ProjectKey)
+	if ticket.Epic != nil && ticket.Epic.Color == "" {
+		ticket.Epic.Color = "red"
+	}
+	ticket.AssigneeID = jiraAccount(h.ServiceNowClient.Context(), h.Identities, incident.AssignedTo)
+	return ticket, nil
+}
+
 // createIncidentSubtasks creates standard subtasks for incident response
 func (h *IncidentHandler) createIncidentSubtasks(incident Incident, epicKey string) {
 	// Define standard subtasks for incident response
@@ -413,10 +552,10 @@ Update documentation and prepare final report.`,
 		},
 	}
 
-	// Create each subtask
+	// Create each subtask in the epic's project, which the field mapping may have chosen
 	for _, task := range subtasks {
 		subtask := &jira.Ticket{
-			Project:     h.JiraClient.ProjectKey,
+			Project:     jira.ProjectOf(epicKey),
 			IssueType:   "Task",
 			Summary:     fmt.Sprintf("%s - %s", task.title, incident.ShortDesc),
 			Description: task.description,
@@ -428,9 +567,9 @@ Update documentation and prepare final report.`,
 		// Create the subtask
 		result, err := h.JiraClient.CreateIssue(subtask)
 		if err != nil {
-			log.Printf("Error creating %s subtask for incident %s: %v", task.title, incident.ID, err)
+			h.ServiceNowClient.Logger().Error("error creating incident subtask", "subtask", task.title, "sys_id", incident.ID, "error", err)
 		} else {
-			log.Printf("Created %s subtask %s for incident %s", task.title, result.Key, incident.ID)
+			h.ServiceNowClient.Logger().Info("created incident subtask", "subtask", task.title, "issue", result.Key, "sys_id", incident.ID)
 		}
 	}
 }
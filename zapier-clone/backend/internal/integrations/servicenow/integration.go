@@ -0,0 +1,111 @@
+// backend/internal/integrations/servicenow/integration.go
+package servicenow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+)
+
+// Integration exposes ServiceNow to workflows through the common integration interfaces
+type Integration struct {
+	Client *Client
+	// Webhook receives record webhooks; it is set where the webhook handler is built
+	Webhook http.Handler
+}
+
+// NewIntegration creates the ServiceNow integration for a client
+func NewIntegration(client *Client, webhook http.Handler) *Integration {
+	return &Integration{
+		Client:  client,
+		Webhook: webhook,
+	}
+}
+
+// Name implements common.Integration
+func (i *Integration) Name() string {
+	return "servicenow"
+}
+
+// Triggers implements common.Trigger: a created and an updated event per GRC table
+func (i *Integration) Triggers() []common.TriggerSpec {
+	triggers := make([]common.TriggerSpec, 0, 2*len(GRCTables))
+	for _, table := range GRCTables {
+		triggers = append(triggers,
+			common.TriggerSpec{ID: table + ".created", Description: fmt.Sprintf("A %s record was created", table)},
+			common.TriggerSpec{ID: table + ".updated", Description: fmt.Sprintf("A %s record changed", table)},
+		)
+	}
+	return triggers
+}
+
+// WebhookHandler implements common.Trigger
+func (i *Integration) WebhookHandler() http.Handler {
+	return i.Webhook
+}
+
+// Actions implements common.Action. Fields other than table and sys_id are
+// written to the record as they are.
+func (i *Integration) Actions() []common.ActionSpec {
+	return []common.ActionSpec{
+		{ID: "create_record", Description: "Create a record in a table", Fields: []string{"table"}},
+		{ID: "update_record", Description: "Update fields of a record", Fields: []string{"table", "sys_id"}},
+		{ID: "add_work_note", Description: "Add a work note to a record", Fields: []string{"table", "sys_id", "body"}},
+	}
+}
+
+// Execute implements common.Action
+func (i *Integration) Execute(ctx context.Context, action string, fields map[string]interface{}) (map[string]interface{}, error) {
+	client := i.Client.WithContext(ctx)
+
+	switch action {
+	case "create_record":
+		if err := common.RequireFields(fields, "table"); err != nil {
+			return nil, err
+		}
+		return client.CreateRecord(common.StringField(fields, "table"), recordFields(fields))
+
+	case "update_record":
+		if err := common.RequireFields(fields, "table", "sys_id"); err != nil {
+			return nil, err
+		}
+		table, sysID := common.StringField(fields, "table"), common.StringField(fields, "sys_id")
+		if err := client.UpdateRecord(table, sysID, recordFields(fields)); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"table": table, "sys_id": sysID}, nil
+
+	case "add_work_note":
+		if err := common.RequireFields(fields, "table", "sys_id", "body"); err != nil {
+			return nil, err
+		}
+		table, sysID := common.StringField(fields, "table"), common.StringField(fields, "sys_id")
+		if err := client.UpdateRecord(table, sysID, map[string]interface{}{JournalWorkNotes: common.StringField(fields, "body")}); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"table": table, "sys_id": sysID}, nil
+	}
+
+	return nil, fmt.Errorf("%w: servicenow.%s", common.ErrUnknownAction, action)
+}
+
+// ValidateConnection implements common.ConnectionValidator by reading one risk
+func (i *Integration) ValidateConnection(ctx context.Context) error {
+	if _, err := i.Client.WithContext(ctx).QueryRecords(GRCTables[0], "", 1); err != nil {
+		return fmt.Errorf("error checking ServiceNow credentials: %w", err)
+	}
+	return nil
+}
+
+// recordFields drops the addressing fields from an action's fields
+func recordFields(fields map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		if name != "table" && name != "sys_id" {
+			data[name] = value
+		}
+	}
+	return data
+}
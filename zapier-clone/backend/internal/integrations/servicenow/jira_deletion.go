@@ -0,0 +1,247 @@
+// backend/internal/integrations/servicenow/jira_deletion.go
+package servicenow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// Policies for handling a Jira issue that was deleted while still linked to ServiceNow
+const (
+	// DeletionPolicyRecreate creates a replacement Jira issue and relinks the record
+	DeletionPolicyRecreate = "recreate"
+	// DeletionPolicyClose closes the linked ServiceNow record
+	DeletionPolicyClose = "close"
+	// DeletionPolicyReview leaves both sides alone and flags the record for manual review
+	DeletionPolicyReview = "review"
+)
+
+// DeletionPolicies selects a policy per ServiceNow table, falling back to Default
+type DeletionPolicies struct {
+	Default string
+	Tables  map[string]string
+}
+
+// ParseDeletionPolicies parses a spec such as "review,sn_risk_risk=recreate,sn_audit_finding=close".
+// A bare policy sets the default; unknown policies are ignored with a warning.
+func ParseDeletionPolicies(spec string) DeletionPolicies {
+	policies := DeletionPolicies{
+		Default: DeletionPolicyReview,
+		Tables:  make(map[string]string),
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		table, policy := "", part
+		if i := strings.Index(part, "="); i >= 0 {
+			table, policy = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+
+		switch policy {
+		case DeletionPolicyRecreate, DeletionPolicyClose, DeletionPolicyReview:
+		default:
+			slog.Warn("ignoring unknown Jira deletion policy", "policy", policy)
+			continue
+		}
+
+		if table == "" || table == "default" {
+			policies.Default = policy
+		} else {
+			policies.Tables[table] = policy
+		}
+	}
+
+	return policies
+}
+
+// For returns the policy for a table
+func (p DeletionPolicies) For(table string) string {
+	if policy, ok := p.Tables[table]; ok {
+		return policy
+	}
+	if p.Default == "" {
+		return DeletionPolicyReview
+	}
+	return p.Default
+}
+
+// JiraDeletionHandler applies the configured policy when a linked Jira issue is deleted
+type JiraDeletionHandler struct {
+	ServiceNowClient    *Client
+	SlackClient         *slack.Client
+	JiraClient          *jira.Client
+	RiskJiraMapping     jira.MappingStore
+	IncidentJiraMapping *jira.IncidentJiraMapping
+	JournalWriter       *JournalWriter
+	Policies            DeletionPolicies
+}
+
+// NewJiraDeletionHandler creates a new Jira deletion handler
+func NewJiraDeletionHandler(serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, mapping jira.MappingStore, policies DeletionPolicies) *JiraDeletionHandler {
+	return &JiraDeletionHandler{
+		ServiceNowClient: serviceNowClient,
+		SlackClient:      slackClient,
+		JiraClient:       jiraClient,
+		RiskJiraMapping:  mapping,
+		JournalWriter:    NewJournalWriter(serviceNowClient),
+		Policies:         policies,
+	}
+}
+
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *JiraDeletionHandler) WithContext(ctx context.Context) *JiraDeletionHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	copied.JiraClient = h.JiraClient.WithContext(ctx)
+	return &copied
+}
+
+// HandleIssueDeleted processes a jira:issue_deleted event for an issue linked to ServiceNow
+func (h *JiraDeletionHandler) HandleIssueDeleted(event *jira.WebhookEvent) error {
+	if event.Issue == nil {
+		return fmt.Errorf("deletion event has no issue")
+	}
+
+	table, sysID := h.linkedRecord(event.Issue)
+	if sysID == "" {
+		h.ServiceNowClient.Logger().Info("deleted Jira issue is not linked to ServiceNow; nothing to do", "issue", event.Issue.Key)
+		return nil
+	}
+
+	policy := h.Policies.For(table)
+
+	var outcome string
+	var err error
+	switch policy {
+	case DeletionPolicyRecreate:
+		outcome, err = h.recreateIssue(event.Issue, table, sysID)
+	case DeletionPolicyClose:
+		outcome, err = h.closeRecord(event.Issue, table, sysID)
+	default:
+		outcome, err = h.flagForReview(event.Issue, table, sysID)
+	}
+
+	// Record the decision on the ServiceNow record's audit trail, even when the action failed
+	actor := "unknown user"
+	if event.User != nil && event.User.DisplayName != "" {
+		actor = event.User.DisplayName
+	}
+	note := fmt.Sprintf("Linked Jira issue %s was deleted by %s. Deletion policy %q applied: %s", event.Issue.Key, actor, policy, outcome)
+	if err != nil {
+		note = fmt.Sprintf("%s (failed: %v)", note, err)
+	}
+	if jerr := h.JournalWriter.Write(table, sysID, JournalWorkNotes, JournalKey("jira-deleted", event.Issue.Key), note); jerr != nil {
+		h.ServiceNowClient.Logger().Error("error recording Jira deletion", "table", table, "sys_id", sysID, "error", jerr)
+	}
+	h.ServiceNowClient.Logger().Info("Jira issue deleted", "issue", event.Issue.Key, "table", table, "sys_id", sysID, "policy", policy, "outcome", outcome)
+
+	return err
+}
+
+// linkedRecord finds the ServiceNow record a Jira issue was synced from
+func (h *JiraDeletionHandler) linkedRecord(issue *jira.WebhookIssue) (string, string) {
+	return LinkedRecord(issue, h.RiskJiraMapping, h.IncidentJiraMapping)
+}
+
+// recreateIssue creates a replacement issue from the deleted one, in the same
+// project, and relinks the record
+func (h *JiraDeletionHandler) recreateIssue(issue *jira.WebhookIssue, table, sysID string) (string, error) {
+	ticket := &jira.Ticket{
+		Project:     jira.ProjectOf(issue.Key),
+		IssueType:   "Task",
+		Summary:     issue.Fields.Summary,
+		Description: fmt.Sprintf("%s\n\n----\nRecreated after %s was deleted in Jira.", issue.Fields.Description, issue.Key),
+		Fields: map[string]interface{}{
+			h.JiraClient.ServiceNowIDField(): sysID,
+		},
+	}
+	if issue.Fields.IssueType != nil && issue.Fields.IssueType.Name != "" {
+		ticket.IssueType = issue.Fields.IssueType.Name
+	}
+	if issue.Fields.Priority != nil {
+		ticket.Priority = issue.Fields.Priority.Name
+	}
+
+	created, err := h.JiraClient.CreateIssue(ticket)
+	if err != nil {
+		return "recreate failed", fmt.Errorf("error recreating Jira issue: %w", err)
+	}
+
+	if err := h.relink(table, sysID, created.Key); err != nil {
+		return fmt.Sprintf("recreated as %s", created.Key), err
+	}
+
+	return fmt.Sprintf("recreated as %s", created.Key), nil
+}
+
+// closeRecord closes the ServiceNow record the deleted issue was tracking
+func (h *JiraDeletionHandler) closeRecord(issue *jira.WebhookIssue, table, sysID string) (string, error) {
+	if err := h.ServiceNowClient.UpdateRecord(table, sysID, map[string]interface{}{"state": "closed"}); err != nil {
+		return "close failed", fmt.Errorf("error closing ServiceNow record: %w", err)
+	}
+	if err := h.relink(table, sysID, ""); err != nil {
+		return "ServiceNow record closed", err
+	}
+
+	return "ServiceNow record closed", nil
+}
+
+// flagForReview asks a human to decide what to do with the orphaned record
+func (h *JiraDeletionHandler) flagForReview(issue *jira.WebhookIssue, table, sysID string) (string, error) {
+	message := slack.Message{
+		Text: fmt.Sprintf("⚠️ Jira issue *%s* (%s) was deleted while still linked to ServiceNow record <%s/nav_to.do?uri=%s.do?sys_id=%s|%s>. Please review whether to recreate the ticket or close the record.",
+			issue.Key, issue.Fields.Summary, h.ServiceNowClient.BaseURL, table, sysID, sysID),
+	}
+
+	if _, err := h.SlackClient.PostMessage(slack.ChannelMapping["ops"], message); err != nil {
+		return "flagged for manual review", fmt.Errorf("error posting review request to Slack: %w", err)
+	}
+	// The link points at an issue that no longer exists; the work note keeps its key
+	if err := h.relink(table, sysID, ""); err != nil {
+		return "flagged for manual review", err
+	}
+
+	return "flagged for manual review", nil
+}
+
+// relink points a record at another Jira issue, or unlinks it when jiraKey is empty
+func (h *JiraDeletionHandler) relink(table, sysID, jiraKey string) error {
+	return relinkRecord(h.ServiceNowClient, h.RiskJiraMapping, h.IncidentJiraMapping, table, sysID, jiraKey)
+}
+
+// relinkRecord updates wherever a record's Jira link is kept: the mapping
+// stores for risks and incidents, the record's jira_ticket field otherwise.
+// An empty jiraKey removes the link.
+func relinkRecord(client *Client, risks jira.MappingStore, incidents *jira.IncidentJiraMapping, table, sysID, jiraKey string) error {
+	var err error
+	switch {
+	case table == "sn_risk_risk" && risks != nil:
+		if jiraKey == "" {
+			err = risks.RemoveMapping(sysID)
+		} else {
+			err = risks.AddMapping(sysID, jiraKey)
+		}
+	case table == "sn_si_incident" && incidents != nil:
+		if jiraKey == "" {
+			err = incidents.RemoveMapping(sysID)
+		} else {
+			err = incidents.AddMapping(sysID, jiraKey)
+		}
+	default:
+		err = client.UpdateRecord(table, sysID, map[string]interface{}{"jira_ticket": jiraKey})
+	}
+	if err != nil {
+		return fmt.Errorf("error relinking %s %s: %w", table, sysID, err)
+	}
+	return nil
+}
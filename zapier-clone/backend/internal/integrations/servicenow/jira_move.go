@@ -0,0 +1,99 @@
+// backend/internal/integrations/servicenow/jira_move.go
+package servicenow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+)
+
+// JiraMoveHandler re-points ServiceNow records when their Jira issue is moved
+// to another project. A move gives the issue a new key, so links kept under
+// the old key would otherwise stop resolving.
+type JiraMoveHandler struct {
+	ServiceNowClient    *Client
+	RiskJiraMapping     jira.MappingStore
+	IncidentJiraMapping *jira.IncidentJiraMapping
+	JournalWriter       *JournalWriter
+}
+
+// NewJiraMoveHandler creates a new Jira move handler
+func NewJiraMoveHandler(serviceNowClient *Client, risks jira.MappingStore, incidents *jira.IncidentJiraMapping) *JiraMoveHandler {
+	return &JiraMoveHandler{
+		ServiceNowClient:    serviceNowClient,
+		RiskJiraMapping:     risks,
+		IncidentJiraMapping: incidents,
+		JournalWriter:       NewJournalWriter(serviceNowClient),
+	}
+}
+
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *JiraMoveHandler) WithContext(ctx context.Context) *JiraMoveHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	return &copied
+}
+
+// MovedFrom returns the key an issue had before a jira:issue_updated event
+// moved it to another project
+func MovedFrom(event *jira.WebhookEvent) (string, bool) {
+	if event.Issue == nil || event.Changelog == nil {
+		return "", false
+	}
+	for _, item := range event.Changelog.Items {
+		if strings.EqualFold(item.Field, "Key") && item.FromString != "" && item.FromString != event.Issue.Key {
+			return item.FromString, true
+		}
+	}
+	return "", false
+}
+
+// HandleIssueMoved relinks the record of a moved issue to its new key and
+// notes the move on the record. It reports false when the event is not a move.
+func (h *JiraMoveHandler) HandleIssueMoved(event *jira.WebhookEvent) (bool, error) {
+	oldKey, moved := MovedFrom(event)
+	if !moved {
+		return false, nil
+	}
+	newKey := event.Issue.Key
+
+	// Links are stored under the old key; the issue's own fields may not have
+	// survived the move to the new project
+	previous := *event.Issue
+	previous.Key = oldKey
+	table, sysID := LinkedRecord(&previous, h.RiskJiraMapping, h.IncidentJiraMapping)
+	if sysID == "" {
+		table, sysID = LinkedRecord(event.Issue, h.RiskJiraMapping, h.IncidentJiraMapping)
+	}
+	if sysID == "" {
+		h.ServiceNowClient.Logger().Info("moved Jira issue is not linked to ServiceNow; nothing to do", "issue", newKey, "previous_key", oldKey)
+		return true, nil
+	}
+
+	if err := relinkRecord(h.ServiceNowClient, h.RiskJiraMapping, h.IncidentJiraMapping, table, sysID, newKey); err != nil {
+		return true, err
+	}
+
+	note := fmt.Sprintf("Linked Jira issue %s was moved to %s", oldKey, newKey)
+	if project := changedTo(event, "project"); project != "" {
+		note = fmt.Sprintf("%s in project %s", note, project)
+	}
+	if err := h.JournalWriter.Write(table, sysID, JournalWorkNotes, JournalKey("jira-moved", oldKey, newKey), note+"."); err != nil {
+		h.ServiceNowClient.Logger().Error("error recording Jira move", "table", table, "sys_id", sysID, "error", err)
+	}
+	h.ServiceNowClient.Logger().Info("Jira issue moved", "issue", newKey, "previous_key", oldKey, "table", table, "sys_id", sysID)
+
+	return true, nil
+}
+
+// changedTo returns the new display value of a field changed by the event
+func changedTo(event *jira.WebhookEvent, field string) string {
+	for _, item := range event.Changelog.Items {
+		if strings.EqualFold(item.Field, field) {
+			return item.ToString
+		}
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+// backend/internal/integrations/servicenow/journal.go
+package servicenow
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// Journal fields on ServiceNow task-based tables
+const (
+	JournalWorkNotes = "work_notes"
+	JournalComments  = "comments"
+)
+
+// journalMarkerPrefix tags every note written by the integration so retries can be detected
+const journalMarkerPrefix = "[sync-id:"
+
+// JournalEntry is a single note destined for a record's journal field
+type JournalEntry struct {
+	Table    string
+	RecordID string
+	Field    string
+	// Key identifies the note for idempotency; the same key is never written twice
+	Key  string
+	Text string
+}
+
+// journalTarget groups entries that are written as one journal entry
+type journalTarget struct {
+	Table    string
+	RecordID string
+	Field    string
+}
+
+// JournalWriter appends notes to ServiceNow journal fields (work_notes, comments).
+// Each write carries an idempotency marker and is skipped if that marker is already
+// in the record's journal, so retries never duplicate entries. Notes queued for the
+// same record and field are bundled into a single journal entry on Flush.
+type JournalWriter struct {
+	Client     *Client
+	MaxRetries int
+	RetryDelay time.Duration
+
+	mutex   sync.Mutex
+	pending map[journalTarget][]JournalEntry
+	order   []journalTarget
+}
+
+// NewJournalWriter creates a journal writer for the given client
+func NewJournalWriter(client *Client) *JournalWriter {
+	return &JournalWriter{
+		Client:     client,
+		MaxRetries: 3,
+		RetryDelay: 2 * time.Second,
+		pending:    make(map[journalTarget][]JournalEntry),
+	}
+}
+
+// Add queues a note; it is written on the next Flush
+func (w *JournalWriter) Add(entry JournalEntry) {
+	if entry.Field == "" {
+		entry.Field = JournalWorkNotes
+	}
+
+	target := journalTarget{Table: entry.Table, RecordID: entry.RecordID, Field: entry.Field}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, exists := w.pending[target]; !exists {
+		w.order = append(w.order, target)
+	}
+	w.pending[target] = append(w.pending[target], entry)
+}
+
+// Flush writes all queued notes, one journal entry per record and field
+func (w *JournalWriter) Flush() error {
+	w.mutex.Lock()
+	pending := w.pending
+	order := w.order
+	w.pending = make(map[journalTarget][]JournalEntry)
+	w.order = nil
+	w.mutex.Unlock()
+
+	var errs []string
+	for _, target := range order {
+		if err := w.writeBundle(target, pending[target]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("error writing journal entries: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Write appends a single note immediately
+func (w *JournalWriter) Write(table, recordID, field, key, text string) error {
+	if field == "" {
+		field = JournalWorkNotes
+	}
+
+	target := journalTarget{Table: table, RecordID: recordID, Field: field}
+	return w.writeBundle(target, []JournalEntry{{
+		Table:    table,
+		RecordID: recordID,
+		Field:    field,
+		Key:      key,
+		Text:     text,
+	}})
+}
+
+// writeBundle writes entries as one journal entry with a combined idempotency marker
+func (w *JournalWriter) writeBundle(target journalTarget, entries []JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	marker := journalMarker(entries)
+
+	var texts []string
+	for _, entry := range entries {
+		texts = append(texts, strings.TrimSpace(entry.Text))
+	}
+	body := strings.Join(texts, "\n\n") + "\n\n" + marker
+
+	var lastErr error
+	for attempt := 0; attempt <= w.MaxRetries; attempt++ {
+		if attempt > 0 {
+			metrics.Retries.Inc(metrics.IntegrationServiceNow)
+			time.Sleep(w.RetryDelay * time.Duration(attempt))
+		}
+
+		// A previous attempt may have succeeded even though we saw an error
+		exists, err := w.hasMarker(target, marker)
+		if err != nil {
+			w.Client.Logger().Warn("could not check journal", "table", target.Table, "sys_id", target.RecordID, "error", err)
+		} else if exists {
+			return nil
+		}
+
+		lastErr = w.append(target, body)
+		if lastErr == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error appending %s to %s/%s: %w", target.Field, target.Table, target.RecordID, lastErr)
+}
+
+// append adds a journal entry; ServiceNow appends to journal fields rather than replacing them
+func (w *JournalWriter) append(target journalTarget, text string) error {
+	body := map[string]string{
+		target.Field: text,
+	}
+
+	resp, err := w.Client.makeRequest("PATCH", fmt.Sprintf("api/now/table/%s/%s", target.Table, target.RecordID), body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// hasMarker checks sys_journal_field for an entry carrying the marker
+func (w *JournalWriter) hasMarker(target journalTarget, marker string) (bool, error) {
+	query := url.Values{}
+	query.Set("sysparm_query", fmt.Sprintf("element_id=%s^element=%s^valueLIKE%s", target.RecordID, target.Field, marker))
+	query.Set("sysparm_fields", "sys_id")
+	query.Set("sysparm_limit", "1")
+
+	resp, err := w.Client.makeRequest("GET", "api/now/table/sys_journal_field?"+query.Encode(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result []map[string]interface{} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return false, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return len(response.Result) > 0, nil
+}
+
+// journalMarker derives a stable marker from the entry keys
+func journalMarker(entries []JournalEntry) string {
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		key := entry.Key
+		if key == "" {
+			key = entry.Text
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sum := sha1.Sum([]byte(strings.Join(keys, "\x00")))
+	return journalMarkerPrefix + hex.EncodeToString(sum[:])[:12] + "]"
+}
+
+// JournalKey builds an idempotency key from the parts that identify a note
+func JournalKey(parts ...string) string {
+	return strings.Join(parts, ":")
+}
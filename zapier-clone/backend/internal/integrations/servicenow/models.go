@@ -1,7 +1,10 @@
 // backend/internal/integrations/servicenow/models.go
 package servicenow
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // Risk represents a risk record in ServiceNow GRC
 type Risk struct {
@@ -55,6 +58,8 @@ type Incident struct {
 	CreatedOn       time.Time `json:"sys_created_on"`
 	LastUpdated     time.Time `json:"sys_updated_on"`
 	ResolutionNotes string    `json:"resolution_notes"`
+	// MajorIncidentState is "accepted" once the incident was promoted to a major incident
+	MajorIncidentState string `json:"major_incident_state,omitempty"`
 }
 
 // WebhookPayload represents the incoming webhook payload from ServiceNow
@@ -78,3 +83,38 @@ func RiskSeverity(score float64) string {
 		return "Low"
 	}
 }
+
+// MappingRecord returns a Table API record with the fields the field mapping
+// reads but ServiceNow does not store, such as a risk's severity. The record
+// itself is not modified.
+func MappingRecord(table string, record map[string]interface{}) map[string]interface{} {
+	if table != "sn_risk_risk" {
+		return record
+	}
+
+	derived := make(map[string]interface{}, len(record)+1)
+	for field, value := range record {
+		derived[field] = value
+	}
+	// The Table API returns numbers as strings
+	var score float64
+	switch v := record["risk_score"].(type) {
+	case float64:
+		score = v
+	case string:
+		score, _ = strconv.ParseFloat(v, 64)
+	}
+	derived["severity"] = RiskSeverity(score)
+	return derived
+}
+
+// GRCTables lists the ServiceNow tables the integration syncs
+var GRCTables = []string{
+	"sn_risk_risk",
+	"sn_compliance_task",
+	"sn_si_incident",
+	"sn_policy_control_test",
+	"sn_audit_finding",
+	"sn_vendor_risk",
+	"sn_regulatory_change",
+}
@@ -2,11 +2,14 @@
 package servicenow
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
 )
 
 // ControlTest represents a control test in ServiceNow GRC
@@ -31,6 +34,8 @@ type ControlTest struct {
 type PolicyControlHandler struct {
 	ServiceNowClient *Client
 	SlackClient      *slack.Client
+	// Routes picks the channels new control tests are announced in
+	Routes *notification.Router
 }
 
 // NewPolicyControlHandler creates a new policy and control handler
@@ -41,67 +46,94 @@ func NewPolicyControlHandler(serviceNowClient *Client, slackClient *slack.Client
 	}
 }
 
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *PolicyControlHandler) WithContext(ctx context.Context) *PolicyControlHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	return &copied
+}
+
 // HandleNewControlTest processes a new control test and notifies Slack
 func (h *PolicyControlHandler) HandleNewControlTest(test ControlTest) (string, error) {
-	// Create a Slack message for the control test
-	message := slack.Message{
-		Blocks: []slack.Block{
-			{
-				Type: "header",
-				Text: slack.NewTextObject("plain_text", fmt.Sprintf("✅ New Test Assigned: %s", test.Control), true),
-			},
-			{
-				Type: "section",
-				Fields: []*slack.TextObject{
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Test ID:*\n%s", test.Number), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Framework:*\n%s", test.Framework), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Due Date:*\n%s", test.DueDate.Format("Jan 2, 2006")), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Status:*\n%s", test.Status), false),
+	// Create the Slack message for the control test in a channel's language
+	build := func(locale string) slack.Message {
+		return slack.Message{
+			Blocks: []slack.Block{
+				{
+					Type: "header",
+					Text: slack.NewTextObject("plain_text", i18n.T(locale, "control_test.header", test.Control), true),
 				},
-			},
-			{
-				Type: "section",
-				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Description:*\n%s", test.Description), false),
-			},
-			{
-				Type: "actions",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Submit Test Results",
-							"emoji": true,
-						},
-						"value":     fmt.Sprintf("test_results_%s", test.ID),
-						"action_id": "submit_test_results",
+				{
+					Type: "section",
+					Fields: []*slack.TextObject{
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "control_test.field.number", test.Number), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "compliance_task.field.framework", test.Framework), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.due_date", i18n.Date(locale, test.DueDate)), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.status", i18n.Value(locale, "state", test.Status)), false),
 					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "View in ServiceNow",
-							"emoji": true,
+				},
+				{
+					Type: "section",
+					Text: slack.NewTextObject("mrkdwn", i18n.T(locale, "field.description", test.Description), false),
+				},
+				{
+					Type: "actions",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "control_test.button.results"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("test_results_%s", test.ID),
+							"action_id": "submit_test_results",
+						},
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "compliance_task.button.evidence"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("upload_evidence_%s", test.ID),
+							"action_id": "upload_evidence",
+						},
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.view_servicenow"),
+								"emoji": true,
+							},
+							"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_policy_control_test.do?sys_id=%s", h.ServiceNowClient.BaseURL, test.ID),
+							"action_id": "view_test",
 						},
-						"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_policy_control_test.do?sys_id=%s", h.ServiceNowClient.BaseURL, test.ID),
-						"action_id": "view_test",
 					},
 				},
-			},
-			{
-				Type: "context",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "mrkdwn",
-						"text": fmt.Sprintf("📅 This control test is due by *%s*.", test.DueDate.Format("Jan 2, 2006")),
+				{
+					Type: "context",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "mrkdwn",
+							"text": i18n.T(locale, "control_test.context", i18n.Date(locale, test.DueDate)),
+						},
 					},
 				},
 			},
-		},
+		}
 	}
 
-	// Post the message to the control-testing channel
-	ts, err := h.SlackClient.PostMessage(slack.ChannelMapping["control-testing"], message)
+	// Post the message to the channels routed for the control test, control-testing by default
+	item := notification.Item{
+		Type:     "control_test",
+		Category: test.Framework,
+		Number:   test.Number,
+		Title:    test.ShortDesc,
+		URL:      fmt.Sprintf("%s/nav_to.do?uri=sn_policy_control_test.do?sys_id=%s", h.ServiceNowClient.BaseURL, test.ID),
+	}
+	_, ts, err := announce(h.SlackClient, h.Routes, item, build)
 	if err != nil {
 		return "", fmt.Errorf("error posting control test message to Slack: %w", err)
 	}
@@ -147,33 +179,20 @@ func (h *PolicyControlHandler) HandleTestResultSubmission(testID, channelID, thr
 	return nil
 }
 
-// ProcessControlCommand handles slash commands for control testing
-func (h *PolicyControlHandler) ProcessControlCommand(command *slack.Command) (string, error) {
-	// Handle different control testing commands
-	switch {
-	case command.Command == "/submit-test":
-		// Format: /submit-test TEST_ID PASS|FAIL Notes about the test
-		parts := strings.SplitN(command.Text, " ", 3)
-		if len(parts) < 3 {
-			return "Invalid command format. Usage: /submit-test TEST_ID PASS|FAIL Notes about the test", nil
-		}
-
-		testID := parts[0]
-		status := strings.ToUpper(parts[1])
-		notes := parts[2]
-
-		if status != "PASS" && status != "FAIL" {
-			return "Status must be either PASS or FAIL", nil
-		}
-
-		err := h.HandleTestResultSubmission(testID, command.ChannelID, "", command.UserID, status, notes)
-		if err != nil {
-			return fmt.Sprintf("Error submitting test results: %s", err), nil
-		}
-
-		return "Test results submitted successfully!", nil
-
-	default:
-		return "Unknown command", nil
-	}
+// RegisterCommands adds the control testing slash commands to the router
+func (h *PolicyControlHandler) RegisterCommands(router *slack.CommandRouter) error {
+	return router.Register(slack.SlashCommand{
+		Name:        "/submit-test",
+		Usage:       "TEST_ID PASS|FAIL NOTES",
+		Description: "Record the result of a control test",
+		Args:        3,
+		Validate:    slack.ArgOneOf(1, "PASS", "FAIL"),
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			status := strings.ToUpper(args[1])
+			if err := h.HandleTestResultSubmission(args[0], command.ChannelID, "", command.UserID, status, args[2]); err != nil {
+				return "", fmt.Errorf("error submitting test results: %w", err)
+			}
+			return "Test results submitted successfully!", nil
+		},
+	})
 }
@@ -0,0 +1,96 @@
+// backend/internal/integrations/servicenow/poll.go
+package servicenow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/polling"
+)
+
+// serviceNowTimeLayout is the format of sys_created_on and sys_updated_on (UTC)
+const serviceNowTimeLayout = "2006-01-02 15:04:05"
+
+// TablePollSource polls a table for records updated since the watermark. It is
+// the fallback for instances whose business rules cannot reach the webhook.
+type TablePollSource struct {
+	Client *Client
+	Table  string
+	// PageSize caps the records fetched per poll; the rest follow next poll
+	PageSize int
+}
+
+// NewTablePollSource creates a poll source for one table
+func NewTablePollSource(client *Client, table string) *TablePollSource {
+	return &TablePollSource{
+		Client:   client,
+		Table:    table,
+		PageSize: 200,
+	}
+}
+
+// Poll fetches records updated at or after since.Time, oldest first. A source
+// that has never run starts from now rather than replaying the whole table.
+func (s *TablePollSource) Poll(ctx context.Context, since polling.Watermark) ([]polling.Item, string, error) {
+	from := since.Time
+	if from.IsZero() {
+		from = time.Now().Add(-time.Minute)
+	}
+
+	query := fmt.Sprintf("sys_updated_on>=%s^ORDERBYsys_updated_on", from.UTC().Format(serviceNowTimeLayout))
+	records, err := s.Client.WithContext(ctx).QueryRecords(s.Table, query, s.PageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items := make([]polling.Item, 0, len(records))
+	for _, record := range records {
+		sysID, _ := record["sys_id"].(string)
+		updatedOn, _ := record["sys_updated_on"].(string)
+		updatedAt, err := time.Parse(serviceNowTimeLayout, updatedOn)
+		if sysID == "" || err != nil {
+			logging.FromContext(ctx).Warn("skipping polled record without sys_id or sys_updated_on", "table", s.Table)
+			continue
+		}
+		items = append(items, polling.Item{ID: sysID, UpdatedAt: updatedAt, Data: record})
+	}
+	return items, "", nil
+}
+
+// PolledPayload turns a polled record into the webhook it stands in for: an
+// insert when it has not been updated since creation, otherwise an update
+func PolledPayload(table string, item polling.Item) WebhookPayload {
+	action := "updated"
+	if createdOn, _ := item.Data["sys_created_on"].(string); createdOn != "" && createdOn == item.Data["sys_updated_on"] {
+		action = "inserted"
+	}
+	return WebhookPayload{
+		ID:         item.ID,
+		TableName:  table,
+		ActionType: action,
+		Data:       item.Data,
+	}
+}
+
+// PollScheduleFromEnv reads the fallback poll interval from SERVICENOW_POLL_INTERVAL
+// (e.g. "2m"). Polling is off, and ok false, when it is unset or "0".
+func PollScheduleFromEnv() (schedule polling.Schedule, ok bool) {
+	value := os.Getenv("SERVICENOW_POLL_INTERVAL")
+	if value == "" || value == "0" {
+		return polling.Schedule{}, false
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		slog.Warn("ignoring invalid SERVICENOW_POLL_INTERVAL", "value", value)
+		return polling.Schedule{}, false
+	}
+
+	schedule = polling.DefaultSchedule
+	schedule.Interval = interval
+	return schedule, true
+}
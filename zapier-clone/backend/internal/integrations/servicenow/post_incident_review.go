@@ -0,0 +1,561 @@
+// backend/internal/integrations/servicenow/post_incident_review.go
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/workflow"
+)
+
+// Post-incident review states
+const (
+	// ReviewDrafting means the review is being put together
+	ReviewDrafting = "drafting"
+	ReviewDraft    = "draft"
+)
+
+// Timeline sources
+const (
+	TimelineServiceNow = "servicenow"
+	TimelineWorkflow   = "workflow"
+	TimelineSlack      = "slack"
+	TimelineJira       = "jira"
+)
+
+// maxReviewDraftText is how much of a review is posted in the review thread;
+// Slack cuts messages off at 40,000 characters
+const maxReviewDraftText = 38000
+
+// TimelineEntry is one event in the timeline of a post-incident review
+type TimelineEntry struct {
+	At     time.Time `json:"at"`
+	Source string    `json:"source"`
+	Actor  string    `json:"actor,omitempty"`
+	Text   string    `json:"text"`
+}
+
+// PostIncidentReview is the review drafted for one resolved incident
+type PostIncidentReview struct {
+	// IncidentID is the incident's sys_id
+	IncidentID string `json:"incident_id"`
+	Number     string `json:"number"`
+	Title      string `json:"title"`
+	// JiraKey is the review's ticket, and EpicKey the incident's epic
+	JiraKey  string          `json:"jira_key,omitempty"`
+	EpicKey  string          `json:"epic_key,omitempty"`
+	Channel  string          `json:"channel,omitempty"`
+	TS       string          `json:"ts,omitempty"`
+	Status   string          `json:"status"`
+	Timeline []TimelineEntry `json:"timeline,omitempty"`
+	Document string          `json:"document,omitempty"`
+	// ResolvedAt is when the incident resolved, as far as the review could tell
+	ResolvedAt time.Time `json:"resolved_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PostIncidentReviews drafts a review once an incident resolves: a timeline
+// assembled from the execution history, the Slack thread the incident was
+// announced in and the comments on its Jira epic, filed as a Jira ticket and
+// posted to the review channel for the team to complete
+type PostIncidentReviews struct {
+	ServiceNowClient    *Client
+	SlackClient         *slack.Client
+	JiraClient          *jira.Client
+	IncidentJiraMapping *jira.IncidentJiraMapping
+	JournalWriter       *JournalWriter
+	// Threads finds the Slack thread the incident was announced in
+	Threads *slack.ThreadStore
+	// Executions is the workflow execution history, when there is a database
+	Executions *workflow.Store
+	// WarRooms adds the incident's war room to the timeline
+	WarRooms *WarRooms
+	// Enabled turns reviews on
+	Enabled bool
+	// Priorities are the incident priorities that get a review; every
+	// incident with a war room gets one too
+	Priorities []string
+	// Channel is where drafts are posted, a channel name or ChannelMapping key
+	Channel string
+	// IssueType is the type of the review's Jira ticket
+	IssueType string
+
+	mutex    sync.Mutex
+	reviews  map[string]*PostIncidentReview
+	filePath string
+	now      func() time.Time
+}
+
+// NewPostIncidentReviews creates a review generator that keeps its reviews in
+// storagePath. It drafts nothing until Enabled is set, usually by ConfigureFromEnv.
+func NewPostIncidentReviews(storagePath string, serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, incidents *jira.IncidentJiraMapping) (*PostIncidentReviews, error) {
+	p := &PostIncidentReviews{
+		ServiceNowClient:    serviceNowClient,
+		SlackClient:         slackClient,
+		JiraClient:          jiraClient,
+		IncidentJiraMapping: incidents,
+		JournalWriter:       NewJournalWriter(serviceNowClient),
+		Priorities:          []string{"1", "2"},
+		Channel:             "incident-review",
+		IssueType:           "Task",
+		reviews:             make(map[string]*PostIncidentReview),
+		filePath:            filepath.Join(storagePath, "post_incident_reviews.json"),
+		now:                 time.Now,
+	}
+
+	if _, err := os.Stat(p.filePath); err == nil {
+		file, err := os.ReadFile(p.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading post-incident review file: %w", err)
+		}
+		if err := json.Unmarshal(file, &p.reviews); err != nil {
+			return nil, fmt.Errorf("error unmarshaling post-incident reviews: %w", err)
+		}
+	}
+	// A review left drafting by a restart is drafted again on request
+	for id, review := range p.reviews {
+		if review.Status == ReviewDrafting {
+			delete(p.reviews, id)
+		}
+	}
+
+	return p, nil
+}
+
+// ConfigureFromEnv turns reviews on when POST_INCIDENT_REVIEWS is "true" and reads:
+//
+//	POST_INCIDENT_REVIEW_PRIORITIES  priorities that get a review, "1|2|3"; "1|2" by default, "*" for all
+//	POST_INCIDENT_REVIEW_CHANNEL     channel drafts are posted in, incident-review by default
+//	POST_INCIDENT_REVIEW_ISSUE_TYPE  type of the review's Jira ticket, Task by default
+func (p *PostIncidentReviews) ConfigureFromEnv() {
+	p.Enabled = strings.EqualFold(os.Getenv("POST_INCIDENT_REVIEWS"), "true")
+	if channel := strings.TrimPrefix(strings.TrimSpace(os.Getenv("POST_INCIDENT_REVIEW_CHANNEL")), "#"); channel != "" {
+		p.Channel = channel
+	}
+	if issueType := strings.TrimSpace(os.Getenv("POST_INCIDENT_REVIEW_ISSUE_TYPE")); issueType != "" {
+		p.IssueType = issueType
+	}
+	if value := os.Getenv("POST_INCIDENT_REVIEW_PRIORITIES"); value != "" {
+		var priorities []string
+		for _, priority := range strings.Split(value, "|") {
+			if strings.TrimSpace(priority) == "*" {
+				priorities = nil
+				break
+			}
+			if priority = priorityLevel(priority); priority != "" {
+				priorities = append(priorities, priority)
+			}
+		}
+		p.Priorities = priorities
+	}
+}
+
+// covers reports whether a resolved incident gets a review
+func (p *PostIncidentReviews) covers(incident Incident) bool {
+	if _, ok := p.WarRooms.Get(incident.ID); ok {
+		return true
+	}
+	if len(p.Priorities) == 0 {
+		return true
+	}
+	level := priorityLevel(incident.Priority)
+	for _, priority := range p.Priorities {
+		if level != "" && level == priority {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolved drafts the review of an incident that was resolved or closed,
+// unless it has one or its priority gets none. Updates that do not close the
+// incident are ignored.
+func (p *PostIncidentReviews) Resolved(ctx context.Context, incident Incident) (*PostIncidentReview, error) {
+	if p == nil || !p.Enabled || incident.ID == "" || !incidentClosed(incident.State) {
+		return nil, nil
+	}
+	return p.Generate(ctx, incident, false)
+}
+
+// Generate drafts an incident's review. An incident that has one gets it
+// back; with force, any incident gets one whatever its priority. An incident
+// that cannot be found returns ErrRecordNotFound.
+func (p *PostIncidentReviews) Generate(ctx context.Context, incident Incident, force bool) (*PostIncidentReview, error) {
+	if p == nil || incident.ID == "" {
+		return nil, nil
+	}
+
+	p.mutex.Lock()
+	if existing, ok := p.reviews[incident.ID]; ok {
+		copied := *existing
+		p.mutex.Unlock()
+		return &copied, nil
+	}
+	p.mutex.Unlock()
+
+	// Webhooks for a resolution may carry only the fields that changed
+	incident = p.complete(ctx, incident)
+	if incident.Number == "" {
+		return nil, fmt.Errorf("%w: sn_si_incident %s", ErrRecordNotFound, incident.ID)
+	}
+	if !force && !p.covers(incident) {
+		return nil, nil
+	}
+
+	p.mutex.Lock()
+	if existing, ok := p.reviews[incident.ID]; ok {
+		copied := *existing
+		p.mutex.Unlock()
+		return &copied, nil
+	}
+	review := &PostIncidentReview{
+		IncidentID: incident.ID,
+		Number:     incident.Number,
+		Title:      incident.ShortDesc,
+		Status:     ReviewDrafting,
+		Timeline:   []TimelineEntry{},
+		CreatedAt:  p.now(),
+	}
+	p.reviews[incident.ID] = review
+	p.mutex.Unlock()
+
+	drafted, err := p.draft(ctx, *review, incident)
+
+	p.mutex.Lock()
+	if err != nil {
+		delete(p.reviews, incident.ID)
+	} else {
+		*review = drafted
+	}
+	p.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	p.save()
+	return &drafted, nil
+}
+
+// complete fills in what an incident update left out from its ServiceNow
+// record, or from its war room when the record cannot be read
+func (p *PostIncidentReviews) complete(ctx context.Context, incident Incident) Incident {
+	record, err := p.ServiceNowClient.WithContext(ctx).GetRecord("sn_si_incident", incident.ID)
+	if err != nil {
+		p.ServiceNowClient.Logger().Error("error fetching incident for post-incident review", "sys_id", incident.ID, "error", err)
+		if room, ok := p.WarRooms.Get(incident.ID); ok {
+			incident.Number = valueOr(incident.Number, room.Number)
+			incident.ShortDesc = valueOr(incident.ShortDesc, room.Title)
+			incident.Priority = valueOr(incident.Priority, room.Priority)
+			incident.Severity = valueOr(incident.Severity, room.Severity)
+			incident.AssignedTo = valueOr(incident.AssignedTo, room.AssignedTo)
+		}
+		return incident
+	}
+
+	fill := func(value *string, field string) {
+		if *value == "" {
+			*value = referenceValue(record[field])
+		}
+	}
+	fill(&incident.Number, "number")
+	fill(&incident.ShortDesc, "short_description")
+	fill(&incident.Description, "description")
+	fill(&incident.Category, "category")
+	fill(&incident.Priority, "priority")
+	fill(&incident.Severity, "severity")
+	fill(&incident.Impact, "impact")
+	fill(&incident.AssignedTo, "assigned_to")
+	fill(&incident.ResolutionNotes, "resolution_notes")
+	if incident.CreatedOn.IsZero() {
+		incident.CreatedOn, _ = time.Parse("2006-01-02 15:04:05", referenceValue(record["sys_created_on"]))
+	}
+	return incident
+}
+
+// draft assembles the timeline and document, files the Jira ticket and posts
+// the draft. Only filing the ticket has to succeed; the rest is logged when
+// it fails.
+func (p *PostIncidentReviews) draft(ctx context.Context, review PostIncidentReview, incident Incident) (PostIncidentReview, error) {
+	logger := p.ServiceNowClient.Logger()
+	jiraClient := p.JiraClient.WithContext(ctx)
+
+	review.Number = incident.Number
+	review.Title = incident.ShortDesc
+	review.ResolvedAt = p.now()
+	if epicKey, linked := p.IncidentJiraMapping.GetJiraKeyFromIncidentID(incident.ID); linked {
+		review.EpicKey = epicKey
+	}
+	review.Timeline = p.timeline(ctx, incident, review.EpicKey, review.ResolvedAt)
+	review.Document = p.document(review, incident)
+
+	project := jiraClient.ProjectKey
+	if review.EpicKey != "" {
+		project = jira.ProjectOf(review.EpicKey)
+	}
+	ticket, err := jiraClient.CreateIssue(&jira.Ticket{
+		Project:     project,
+		IssueType:   p.IssueType,
+		Summary:     fmt.Sprintf("Post-incident review: %s %s", incident.Number, incident.ShortDesc),
+		Description: review.Document,
+		Parent:      review.EpicKey,
+		Labels:      []string{"post-incident-review", "security-incident"},
+	})
+	if err != nil {
+		return review, fmt.Errorf("error creating post-incident review ticket: %w", err)
+	}
+	review.JiraKey = ticket.Key
+	review.Status = ReviewDraft
+
+	channel, ts, err := p.post(ctx, review)
+	if err != nil {
+		logger.Error("error posting post-incident review draft", "sys_id", incident.ID, "channel", p.Channel, "error", err)
+	}
+	review.Channel, review.TS = channel, ts
+
+	note := fmt.Sprintf("Post-incident review drafted in Jira as %s", review.JiraKey)
+	if err := p.JournalWriter.Write("sn_si_incident", incident.ID, JournalWorkNotes, JournalKey("incident-review", incident.ID, review.JiraKey), note); err != nil {
+		logger.Error("error adding post-incident review work note", "sys_id", incident.ID, "error", err)
+	}
+	if review.EpicKey != "" {
+		if err := jiraClient.AddComment(review.EpicKey, fmt.Sprintf("Post-incident review: %s", review.JiraKey)); err != nil {
+			logger.Error("error linking post-incident review on Jira epic", "issue", review.EpicKey, "error", err)
+		}
+	}
+
+	logger.Info("drafted post-incident review", "sys_id", incident.ID, "issue", review.JiraKey, "events", len(review.Timeline))
+	return review, nil
+}
+
+// timeline gathers an incident's events, oldest first. A source that cannot
+// be read is left out and logged.
+func (p *PostIncidentReviews) timeline(ctx context.Context, incident Incident, epicKey string, resolvedAt time.Time) []TimelineEntry {
+	logger := p.ServiceNowClient.Logger()
+	entries := []TimelineEntry{}
+	add := func(at time.Time, source, actor, text string) {
+		if text = strings.TrimSpace(text); text != "" && !at.IsZero() {
+			entries = append(entries, TimelineEntry{At: at.UTC(), Source: source, Actor: actor, Text: text})
+		}
+	}
+
+	add(incident.CreatedOn, TimelineServiceNow, "", fmt.Sprintf("%s opened with priority %s", incident.Number, incident.Priority))
+
+	if p.Executions != nil {
+		executions, err := p.Executions.ExecutionsForRecord(incident.ID, 0)
+		if err != nil {
+			logger.Error("error reading execution history for post-incident review", "sys_id", incident.ID, "error", err)
+		}
+		for _, execution := range executions {
+			text := fmt.Sprintf("%s ran: %s, %d %s", execution.WorkflowName, execution.Status, execution.Steps, plural(execution.Steps, "step", "steps"))
+			if execution.Error != nil {
+				text += " (" + *execution.Error + ")"
+			}
+			add(execution.StartedAt, TimelineWorkflow, "", text)
+		}
+	}
+
+	var thread slack.Thread
+	found := false
+	if p.Threads != nil {
+		thread, found = p.Threads.Get(incident.ID)
+	}
+	if found {
+		messages, err := p.SlackClient.WithContext(ctx).GetThreadReplies(thread.Channel, thread.TS)
+		if err != nil {
+			logger.Error("error reading Slack thread for post-incident review", "sys_id", incident.ID, "error", err)
+		}
+		for _, message := range messages {
+			actor := "bot"
+			if message.User != "" && message.BotID == "" {
+				actor = "<@" + message.User + ">"
+			}
+			text := message.Text
+			if message.TS == thread.TS {
+				text = strings.TrimSuffix("Announced in Slack: "+text, ": ")
+			}
+			add(message.Time(), TimelineSlack, actor, text)
+		}
+	}
+
+	if room, ok := p.WarRooms.Get(incident.ID); ok {
+		add(room.OpenedAt, TimelineSlack, "", fmt.Sprintf("War room #%s opened with %d %s", room.ChannelName, len(room.Members), plural(len(room.Members), "member", "members")))
+		if room.ArchivedAt != nil {
+			add(*room.ArchivedAt, TimelineSlack, "", fmt.Sprintf("War room #%s archived", room.ChannelName))
+		}
+	}
+
+	if epicKey != "" {
+		comments, err := p.JiraClient.WithContext(ctx).GetComments(epicKey)
+		if err != nil {
+			logger.Error("error reading Jira comments for post-incident review", "issue", epicKey, "error", err)
+		}
+		for _, comment := range comments {
+			add(comment.Created, TimelineJira, comment.Author, comment.Body)
+		}
+	}
+
+	text := incident.Number + " resolved"
+	if incident.ResolutionNotes != "" {
+		text += ": " + incident.ResolutionNotes
+	}
+	add(resolvedAt, TimelineServiceNow, "", text)
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+	return entries
+}
+
+// document writes the review in the markdown Jira and Slack both take: the
+// facts and timeline filled in, the analysis left for the review meeting
+func (p *PostIncidentReviews) document(review PostIncidentReview, incident Incident) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Post-incident review: %s %s\n\n", incident.Number, incident.ShortDesc)
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- Priority: %s\n", valueOr(incident.Priority, "not set"))
+	fmt.Fprintf(&b, "- Severity: %s\n", valueOr(incident.Severity, "not set"))
+	fmt.Fprintf(&b, "- Category: %s\n", valueOr(incident.Category, "not set"))
+	fmt.Fprintf(&b, "- Assigned to: %s\n", valueOr(incident.AssignedTo, "nobody"))
+	if !incident.CreatedOn.IsZero() {
+		fmt.Fprintf(&b, "- Opened: %s\n", incident.CreatedOn.UTC().Format("2006-01-02 15:04 MST"))
+		fmt.Fprintf(&b, "- Time to resolve: %s\n", review.ResolvedAt.Sub(incident.CreatedOn).Round(time.Minute))
+	}
+	fmt.Fprintf(&b, "- Resolved: %s\n", review.ResolvedAt.UTC().Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(&b, "- ServiceNow: %s/nav_to.do?uri=sn_si_incident.do?sys_id=%s\n", p.ServiceNowClient.BaseURL, incident.ID)
+	if review.EpicKey != "" {
+		fmt.Fprintf(&b, "- Jira epic: %s/browse/%s\n", strings.TrimRight(p.JiraClient.BaseURL, "/"), review.EpicKey)
+	}
+	if room, ok := p.WarRooms.Get(incident.ID); ok {
+		fmt.Fprintf(&b, "- War room: #%s %s\n", room.ChannelName, room.URL)
+	}
+
+	if incident.Description != "" {
+		fmt.Fprintf(&b, "\n## Description\n\n%s\n", incident.Description)
+	}
+	fmt.Fprintf(&b, "\n## Resolution\n\n%s\n", valueOr(incident.ResolutionNotes, "No resolution notes were recorded."))
+
+	b.WriteString("\n## Timeline\n\n")
+	if len(review.Timeline) == 0 {
+		b.WriteString("No events were found.\n")
+	}
+	for _, entry := range review.Timeline {
+		line := strings.Join(strings.Fields(entry.Text), " ")
+		if entry.Actor != "" {
+			line = entry.Actor + ": " + line
+		}
+		fmt.Fprintf(&b, "- %s [%s] %s\n", entry.At.Format("2006-01-02 15:04:05"), entry.Source, line)
+	}
+
+	for _, section := range []string{"Impact", "Root cause", "What went well", "What could be improved", "Action items"} {
+		fmt.Fprintf(&b, "\n## %s\n\nTo be completed at the review.\n", section)
+	}
+	return b.String()
+}
+
+// valueOr returns value, or fallback when it is empty
+func valueOr(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
+
+// post announces the draft in the review channel, with the document in the
+// thread below
+func (p *PostIncidentReviews) post(ctx context.Context, review PostIncidentReview) (string, string, error) {
+	slackClient := p.SlackClient.WithContext(ctx)
+	channel := p.Channel
+	if mapped, ok := slack.ChannelMapping[channel]; ok {
+		channel = mapped
+	}
+
+	ticketURL := fmt.Sprintf("%s/browse/%s", strings.TrimRight(p.JiraClient.BaseURL, "/"), review.JiraKey)
+	text := fmt.Sprintf("📝 Post-incident review drafted for *%s*: %s", review.Number, review.Title)
+	message := slack.Message{
+		Text: text,
+		Blocks: []slack.Block{
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", text, false),
+				Fields: []*slack.TextObject{
+					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Review ticket:*\n<%s|%s>", ticketURL, review.JiraKey), false),
+					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Timeline:*\n%d %s", len(review.Timeline), plural(len(review.Timeline), "event", "events")), false),
+				},
+			},
+			{
+				Type: "context",
+				Elements: []interface{}{
+					map[string]interface{}{
+						"type": "mrkdwn",
+						"text": "The draft is in the thread. Fill in impact, root cause and action items in the Jira ticket.",
+					},
+				},
+			},
+		},
+	}
+	ts, err := slackClient.PostMessage(channel, message)
+	if err != nil {
+		return "", "", err
+	}
+
+	draft := review.Document
+	if len(draft) > maxReviewDraftText {
+		draft = draft[:maxReviewDraftText] + "\n…the full draft is in " + review.JiraKey
+	}
+	if _, err := slackClient.PostReply(channel, ts, slack.Message{Text: draft}); err != nil {
+		return channel, ts, err
+	}
+	return channel, ts, nil
+}
+
+// Get returns the review of an incident
+func (p *PostIncidentReviews) Get(sysID string) (PostIncidentReview, bool) {
+	if p == nil {
+		return PostIncidentReview{}, false
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	review, ok := p.reviews[sysID]
+	if !ok {
+		return PostIncidentReview{}, false
+	}
+	return *review, true
+}
+
+// List returns reviews, newest first, without their documents
+func (p *PostIncidentReviews) List() []PostIncidentReview {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	list := []PostIncidentReview{}
+	for _, review := range p.reviews {
+		copied := *review
+		copied.Document = ""
+		copied.Timeline = nil
+		list = append(list, copied)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list
+}
+
+// save persists the reviews to disk
+func (p *PostIncidentReviews) save() {
+	p.mutex.Lock()
+	data, err := json.MarshalIndent(p.reviews, "", "  ")
+	p.mutex.Unlock()
+	if err != nil {
+		slog.Error("error marshaling post-incident reviews", "error", err)
+		return
+	}
+	if err := os.WriteFile(p.filePath, data, 0644); err != nil {
+		slog.Error("error saving post-incident reviews", "error", err)
+	}
+}
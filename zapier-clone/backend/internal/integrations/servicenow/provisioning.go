@@ -0,0 +1,311 @@
+// backend/internal/integrations/servicenow/provisioning.go
+package servicenow
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// Ways of giving a table without a configured Jira project its own place in Jira
+const (
+	// ProvisionProject creates a Jira project per table
+	ProvisionProject = "project"
+	// ProvisionComponent creates a component per table in the default project
+	ProvisionComponent = "component"
+)
+
+// DefaultProvisionKeyPrefix starts the keys of provisioned projects
+const DefaultProvisionKeyPrefix = "GRC"
+
+// maxProjectKey is the longest project key Jira accepts by default
+const maxProjectKey = 10
+
+// ProvisionedProject is where a table's tickets go after provisioning
+type ProvisionedProject struct {
+	Table   string `json:"table"`
+	Project string `json:"project"`
+	// Component is set in component mode
+	Component string `json:"component,omitempty"`
+	// Created is false when a project or component of that name already existed
+	Created       bool      `json:"created"`
+	ProvisionedAt time.Time `json:"provisioned_at"`
+}
+
+// ProjectProvisioner decides the Jira project of new tickets from tables that
+// no field mapping or servicenow.tables entry routes. With a Mode set, the
+// first ticket of such a table creates a project (or a component) named after
+// the table, and the table keeps it from then on; without one, tickets go to
+// the default project as before.
+type ProjectProvisioner struct {
+	JiraClient  *jira.Client
+	SlackClient *slack.Client
+	// Mode is ProvisionProject, ProvisionComponent or empty to provision nothing
+	Mode string
+	// KeyPrefix starts provisioned project keys, e.g. GRC + VR for sn_vendor_risk
+	KeyPrefix string
+	// Lead is the account ID of provisioned projects' lead; empty makes the
+	// integration account the lead
+	Lead string
+	// Routed maps tables to the project keys configured for them
+	Routed map[string]string
+	// Channel is where new projects and components are announced
+	Channel string
+	// Routes gives the language of Channel; nil writes English
+	Routes *notification.Router
+
+	mutex       sync.Mutex
+	provisioned map[string]*ProvisionedProject
+	filePath    string
+	now         func() time.Time
+}
+
+// NewProjectProvisioner creates a provisioner that keeps what it provisioned
+// in storagePath
+func NewProjectProvisioner(jiraClient *jira.Client, slackClient *slack.Client, storagePath string) (*ProjectProvisioner, error) {
+	p := &ProjectProvisioner{
+		JiraClient:  jiraClient,
+		SlackClient: slackClient,
+		KeyPrefix:   DefaultProvisionKeyPrefix,
+		Routed:      map[string]string{},
+		Channel:     slack.ChannelMapping["ops"],
+		provisioned: make(map[string]*ProvisionedProject),
+		filePath:    filepath.Join(storagePath, "provisioned_projects.json"),
+		now:         time.Now,
+	}
+
+	if _, err := os.Stat(p.filePath); err == nil {
+		file, err := os.ReadFile(p.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading provisioned projects file: %w", err)
+		}
+		if err := json.Unmarshal(file, &p.provisioned); err != nil {
+			return nil, fmt.Errorf("error unmarshaling provisioned projects: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+// Route sets the project of a new ticket from table unless a mapping already
+// did. Routed tables go to their project, then provisioned ones, then the
+// table is provisioned; fallback is used when provisioning is off or fails,
+// so a ticket is never held up by it. A nil provisioner only applies fallback.
+func (p *ProjectProvisioner) Route(table string, ticket *jira.Ticket, fallback string) {
+	if ticket.Project != "" {
+		return
+	}
+	ticket.Project = fallback
+	if p == nil {
+		return
+	}
+	if project := p.Routed[table]; project != "" {
+		ticket.Project = project
+		return
+	}
+
+	provisioned, err := p.provision(table, fallback)
+	if err != nil {
+		p.JiraClient.Logger().Error("error provisioning a Jira project; using the fallback", "mode", p.Mode, "table", table, "fallback", fallback, "error", err)
+		return
+	}
+	if provisioned == nil {
+		return
+	}
+	ticket.Project = provisioned.Project
+	if provisioned.Component != "" && !containsFold(ticket.Components, provisioned.Component) {
+		ticket.Components = append(ticket.Components, provisioned.Component)
+	}
+}
+
+// List returns what has been provisioned, by table
+func (p *ProjectProvisioner) List() []ProvisionedProject {
+	if p == nil {
+		return nil
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	list := make([]ProvisionedProject, 0, len(p.provisioned))
+	for _, provisioned := range p.provisioned {
+		list = append(list, *provisioned)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Table < list[j].Table })
+	return list
+}
+
+// provision returns the table's project, provisioning it on first use. The
+// lock is held across the Jira calls so concurrent webhooks for a new table
+// provision it once.
+func (p *ProjectProvisioner) provision(table, defaultProject string) (*ProvisionedProject, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if provisioned, ok := p.provisioned[table]; ok {
+		return provisioned, nil
+	}
+
+	var provisioned *ProvisionedProject
+	var err error
+	switch p.Mode {
+	case ProvisionProject:
+		provisioned, err = p.provisionProject(table)
+	case ProvisionComponent:
+		provisioned, err = p.provisionComponent(table, defaultProject)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provisioned.Table = table
+	provisioned.ProvisionedAt = p.now().UTC()
+	p.provisioned[table] = provisioned
+	p.save()
+	p.announce(*provisioned)
+	return provisioned, nil
+}
+
+// provisionProject creates the table's project, or adopts one that already
+// has its key. Keys taken by other tables get a digit appended.
+func (p *ProjectProvisioner) provisionProject(table string) (*ProvisionedProject, error) {
+	key := p.projectKey(table)
+	name := "ServiceNow " + tableTitle(table)
+
+	existing, err := p.JiraClient.GetProject(key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return &ProvisionedProject{Project: existing.Key}, nil
+	}
+
+	description := fmt.Sprintf("Tickets for ServiceNow %s records, created by the GRC integration", table)
+	project, err := p.JiraClient.CreateProject(key, name, description, p.Lead)
+	if err != nil {
+		return nil, err
+	}
+	return &ProvisionedProject{Project: project.Key, Created: true}, nil
+}
+
+// provisionComponent creates the table's component in the default project,
+// or adopts one with its name
+func (p *ProjectProvisioner) provisionComponent(table, project string) (*ProvisionedProject, error) {
+	if project == "" {
+		return nil, fmt.Errorf("no default Jira project to add a component to")
+	}
+	name := tableTitle(table)
+
+	components, err := p.JiraClient.GetComponents(project)
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range components {
+		if strings.EqualFold(component.Name, name) {
+			return &ProvisionedProject{Project: project, Component: component.Name}, nil
+		}
+	}
+
+	description := fmt.Sprintf("Tickets for ServiceNow %s records", table)
+	component, err := p.JiraClient.CreateComponent(project, name, description)
+	if err != nil {
+		return nil, err
+	}
+	return &ProvisionedProject{Project: project, Component: component.Name, Created: true}, nil
+}
+
+// projectKey names a table's project: the prefix and the initials of the
+// table's words, e.g. GRCVR for sn_vendor_risk. The caller holds the mutex.
+func (p *ProjectProvisioner) projectKey(table string) string {
+	base := strings.ToUpper(p.KeyPrefix)
+	for _, word := range strings.Fields(tableTitle(table)) {
+		base += strings.ToUpper(word[:1])
+	}
+	if len(base) > maxProjectKey {
+		base = base[:maxProjectKey]
+	}
+
+	taken := make(map[string]bool, len(p.provisioned))
+	for _, provisioned := range p.provisioned {
+		taken[provisioned.Project] = true
+	}
+	key := base
+	for n := 2; taken[key] && n <= 9; n++ {
+		if len(base) == maxProjectKey {
+			base = base[:maxProjectKey-1]
+		}
+		key = fmt.Sprintf("%s%d", base, n)
+	}
+	return key
+}
+
+// announce tells the ops channel where a new table's tickets go
+func (p *ProjectProvisioner) announce(provisioned ProvisionedProject) {
+	if p.SlackClient == nil || p.Channel == "" {
+		return
+	}
+
+	locale := p.Routes.Locale(p.Channel)
+	var text string
+	switch {
+	case provisioned.Component != "" && provisioned.Created:
+		text = i18n.T(locale, "provisioning.new_component", provisioned.Table, provisioned.Component, provisioned.Project)
+	case provisioned.Component != "":
+		text = i18n.T(locale, "provisioning.existing_component", provisioned.Table, provisioned.Component, provisioned.Project)
+	case provisioned.Created:
+		text = i18n.T(locale, "provisioning.new_project", provisioned.Table, provisioned.Project)
+	default:
+		text = i18n.T(locale, "provisioning.existing_project", provisioned.Table, provisioned.Project)
+	}
+
+	if _, err := p.SlackClient.PostMessage(p.Channel, slack.Message{Text: text}); err != nil {
+		p.SlackClient.Logger().Warn("error announcing the Jira project of a table", "table", provisioned.Table, "error", err)
+	}
+}
+
+// save writes the provisioned projects to disk; failures are logged. The
+// caller holds the mutex.
+func (p *ProjectProvisioner) save() {
+	data, err := json.MarshalIndent(p.provisioned, "", "  ")
+	if err != nil {
+		slog.Error("error marshaling provisioned projects", "error", err)
+		return
+	}
+	if err := os.WriteFile(p.filePath, data, 0644); err != nil {
+		slog.Error("error saving provisioned projects", "error", err)
+	}
+}
+
+// tableTitle names a table for people: sn_vendor_risk is "Vendor Risk"
+func tableTitle(table string) string {
+	name := table
+	for _, prefix := range []string{"sn_", "u_"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '_' })
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
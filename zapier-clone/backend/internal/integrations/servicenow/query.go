@@ -0,0 +1,210 @@
+// backend/internal/integrations/servicenow/query.go
+package servicenow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the number of records fetched per Table API request when
+// ListOptions does not set one
+const DefaultPageSize = 100
+
+// Query builds an encoded query (sysparm_query). Conditions are ANDed in the
+// order they are added. Values are used as given; ServiceNow has no escape for
+// "^", so a value containing it splits the condition.
+type Query struct {
+	terms []string
+}
+
+// NewQuery starts an empty query, which matches every record
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Where adds a condition with a ServiceNow operator, e.g. Where("priority", "<=", "2")
+// or Where("short_description", "LIKE", "phishing")
+func (q *Query) Where(field, operator, value string) *Query {
+	q.terms = append(q.terms, field+operator+value)
+	return q
+}
+
+// Equals adds field=value
+func (q *Query) Equals(field, value string) *Query {
+	return q.Where(field, "=", value)
+}
+
+// In adds a condition matching any of values
+func (q *Query) In(field string, values ...string) *Query {
+	return q.Where(field, "IN", strings.Join(values, ","))
+}
+
+// Encoded adds an already encoded query, such as one taken from configuration
+func (q *Query) Encoded(encoded string) *Query {
+	if encoded = strings.Trim(encoded, "^"); encoded != "" {
+		q.terms = append(q.terms, encoded)
+	}
+	return q
+}
+
+// OrderBy sorts ascending by field; later calls break ties of earlier ones
+func (q *Query) OrderBy(field string) *Query {
+	q.terms = append(q.terms, "ORDERBY"+field)
+	return q
+}
+
+// OrderByDesc sorts descending by field
+func (q *Query) OrderByDesc(field string) *Query {
+	q.terms = append(q.terms, "ORDERBYDESC"+field)
+	return q
+}
+
+// String returns the encoded query
+func (q *Query) String() string {
+	if q == nil {
+		return ""
+	}
+	return strings.Join(q.terms, "^")
+}
+
+// ListOptions select and page the records of a Table API list request
+type ListOptions struct {
+	// Query is an encoded query, e.g. NewQuery().Equals("active", "true").String()
+	Query string
+	// Fields limits the fields returned (sysparm_fields); empty returns all
+	Fields []string
+	// PageSize is the number of records per request (sysparm_limit); 0 means DefaultPageSize
+	PageSize int
+	// Offset skips records before the first one returned (sysparm_offset)
+	Offset int
+	// Limit caps the records Records iterates over; 0 means no cap
+	Limit int
+}
+
+// pageSize returns the records requested per page
+func (o ListOptions) pageSize() int {
+	if o.PageSize <= 0 {
+		return DefaultPageSize
+	}
+	return o.PageSize
+}
+
+// ListRecords fetches one page of a table: up to PageSize records starting at Offset
+func (c *Client) ListRecords(table string, opts ListOptions) ([]map[string]interface{}, error) {
+	params := url.Values{}
+	params.Set("sysparm_query", opts.Query)
+	if len(opts.Fields) > 0 {
+		params.Set("sysparm_fields", strings.Join(opts.Fields, ","))
+	}
+	params.Set("sysparm_limit", strconv.Itoa(opts.pageSize()))
+	if opts.Offset > 0 {
+		params.Set("sysparm_offset", strconv.Itoa(opts.Offset))
+	}
+
+	resp, err := c.makeRequest("GET", fmt.Sprintf("api/now/table/%s?%s", table, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result []map[string]interface{} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return response.Result, nil
+}
+
+// RecordIterator walks the records of a table one page at a time, so only the
+// current page is held in memory:
+//
+//	records := client.Records("sn_risk_risk", servicenow.ListOptions{Query: query})
+//	for records.Next() {
+//		record := records.Record()
+//		...
+//	}
+//	if err := records.Err(); err != nil {
+//		...
+//	}
+type RecordIterator struct {
+	client *Client
+	table  string
+	opts   ListOptions
+
+	page   []map[string]interface{}
+	index  int
+	seen   int
+	last   bool
+	record map[string]interface{}
+	err    error
+}
+
+// Records returns an iterator over the records matching opts. Pages are read by
+// offset, so a query without an ORDERBY is ordered by sys_id to keep pages from
+// overlapping. Records inserted or deleted mid-walk can still shift a record
+// across a page boundary; order by a field that does not change, such as
+// sys_created_on, when that matters.
+func (c *Client) Records(table string, opts ListOptions) *RecordIterator {
+	if !strings.Contains(opts.Query, "ORDERBY") {
+		opts.Query = NewQuery().Encoded(opts.Query).OrderBy("sys_id").String()
+	}
+	return &RecordIterator{client: c, table: table, opts: opts}
+}
+
+// Next advances to the next record, fetching the next page when the current
+// one is used up. It returns false at the end of the records or on an error.
+func (it *RecordIterator) Next() bool {
+	if it.err != nil || (it.opts.Limit > 0 && it.seen >= it.opts.Limit) {
+		return false
+	}
+
+	if it.index >= len(it.page) {
+		if it.last {
+			return false
+		}
+		if err := it.client.Context().Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		page, err := it.client.ListRecords(it.table, it.opts)
+		if err != nil {
+			it.err = fmt.Errorf("error reading %s at offset %d: %w", it.table, it.opts.Offset, err)
+			return false
+		}
+		// A short page is the last one. So is a page longer than requested: the
+		// instance ignored sysparm_limit and returned everything at once.
+		it.last = len(page) != it.opts.pageSize()
+		it.opts.Offset += len(page)
+		it.page, it.index = page, 0
+		if len(page) == 0 {
+			return false
+		}
+	}
+
+	it.record = it.page[it.index]
+	it.page[it.index] = nil
+	it.index++
+	it.seen++
+	return true
+}
+
+// Record returns the current record
+func (it *RecordIterator) Record() map[string]interface{} {
+	return it.record
+}
+
+// Err returns the error that ended the iteration, if any
+func (it *RecordIterator) Err() error {
+	return it.err
+}
@@ -2,11 +2,16 @@
 package servicenow
 
 import (
+	"context"
 	"fmt"
-	"strings"
+	"log/slog"
 	"time"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
 )
 
 // RegulatoryChange represents a regulatory change in ServiceNow GRC
@@ -30,6 +35,20 @@ type RegulatoryChange struct {
 type RegulatoryChangeHandler struct {
 	ServiceNowClient *Client
 	SlackClient      *slack.Client
+	// Routes picks the channels new regulatory changes are announced in
+	Routes *notification.Router
+	// JiraClient creates the epic and subtasks of new regulatory changes; without it none are created
+	JiraClient *jira.Client
+	// FieldMapping builds the Jira epic from regulatory change records
+	FieldMapping *mapping.Engine
+	// Projects picks the Jira project when no mapping does
+	Projects *ProjectProvisioner
+	// Identities maps assignees to their Jira accounts when configured
+	Identities Identities
+	// Subtasks are created under each new regulatory change's epic
+	Subtasks []RegulatorySubtask
+	// Tasks keeps each regulatory change's epic and subtasks to track their completion
+	Tasks *RegulatoryTaskStore
 }
 
 // NewRegulatoryChangeHandler creates a new regulatory change handler
@@ -37,80 +56,139 @@ func NewRegulatoryChangeHandler(serviceNowClient *Client, slackClient *slack.Cli
 	return &RegulatoryChangeHandler{
 		ServiceNowClient: serviceNowClient,
 		SlackClient:      slackClient,
+		FieldMapping:     mapping.NewEngine(mapping.Default()),
+		Subtasks:         DefaultRegulatorySubtasks,
 	}
 }
 
-// HandleNewRegulatoryChange processes a new regulatory change and notifies Slack
+// NewRegulatoryTaskHandler creates a regulatory change handler that also opens
+// a Jira epic with the subtasks configured in dataDir for each new change
+func NewRegulatoryTaskHandler(serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, dataDir string) (*RegulatoryChangeHandler, error) {
+	tasks, err := NewRegulatoryTaskStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	subtasks, err := LoadRegulatorySubtasks(dataDir)
+	if err != nil {
+		slog.Warn("error loading regulatory change subtasks; using the defaults", "error", err)
+		subtasks = DefaultRegulatorySubtasks
+	}
+
+	h := NewRegulatoryChangeHandler(serviceNowClient, slackClient)
+	h.JiraClient = jiraClient
+	h.Subtasks = subtasks
+	h.Tasks = tasks
+	return h, nil
+}
+
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *RegulatoryChangeHandler) WithContext(ctx context.Context) *RegulatoryChangeHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	if h.JiraClient != nil {
+		copied.JiraClient = h.JiraClient.WithContext(ctx)
+	}
+	return &copied
+}
+
+// HandleNewRegulatoryChange processes a new regulatory change, opening its
+// Jira epic and subtasks when Jira is configured, and notifies Slack
 func (h *RegulatoryChangeHandler) HandleNewRegulatoryChange(change RegulatoryChange) (string, error) {
-	// Create a Slack message for the regulatory change
-	message := slack.Message{
-		Blocks: []slack.Block{
-			{
-				Type: "header",
-				Text: slack.NewTextObject("plain_text", fmt.Sprintf("📢 New %s - Effective %s", change.ShortDesc, change.EffectiveDate.Format("January 2006")), true),
-			},
-			{
-				Type: "section",
-				Fields: []*slack.TextObject{
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Change ID:*\n%s", change.Number), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Regulation:*\n%s", change.Regulation), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Jurisdiction:*\n%s", change.Jurisdiction), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Effective Date:*\n%s", change.EffectiveDate.Format("Jan 2, 2006")), false),
+	var tasks *RegulatoryChangeTasks
+	if h.JiraClient != nil && h.Tasks != nil {
+		var err error
+		if tasks, err = h.createImpactTasks(change); err != nil {
+			h.ServiceNowClient.Logger().Error("error creating Jira epic for regulatory change", "sys_id", change.ID, "error", err)
+			// Continue execution - the change is still announced in Slack
+		}
+	}
+
+	// Create the Slack message for the regulatory change in a channel's language
+	build := func(locale string) slack.Message {
+		message := slack.Message{
+			Blocks: []slack.Block{
+				{
+					Type: "header",
+					Text: slack.NewTextObject("plain_text", i18n.T(locale, "regulatory_change.header", change.ShortDesc, change.EffectiveDate.Format(i18n.T(locale, "format.month"))), true),
 				},
-			},
-			{
-				Type: "section",
-				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Description:*\n%s", change.Description), false),
-			},
-			{
-				Type: "actions",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Add Impact Assessment",
-							"emoji": true,
-						},
-						"value":     fmt.Sprintf("impact_%s", change.ID),
-						"action_id": "add_impact_assessment",
+				{
+					Type: "section",
+					Fields: []*slack.TextObject{
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "regulatory_change.field.number", change.Number), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "compliance_task.field.regulation", change.Regulation), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "regulatory_change.field.jurisdiction", change.Jurisdiction), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "regulatory_change.field.effective", i18n.Date(locale, change.EffectiveDate)), false),
 					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Create Implementation Plan",
-							"emoji": true,
+				},
+				{
+					Type: "section",
+					Text: slack.NewTextObject("mrkdwn", i18n.T(locale, "field.description", change.Description), false),
+				},
+				{
+					Type: "actions",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "regulatory_change.button.impact"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("impact_%s", change.ID),
+							"action_id": "add_impact_assessment",
 						},
-						"value":     fmt.Sprintf("implement_%s", change.ID),
-						"action_id": "create_implementation_plan",
-					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "View in ServiceNow",
-							"emoji": true,
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "regulatory_change.button.plan"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("implement_%s", change.ID),
+							"action_id": "create_implementation_plan",
+						},
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.view_servicenow"),
+								"emoji": true,
+							},
+							"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_regulatory_change.do?sys_id=%s", h.ServiceNowClient.BaseURL, change.ID),
+							"action_id": "view_regulatory_change",
 						},
-						"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_regulatory_change.do?sys_id=%s", h.ServiceNowClient.BaseURL, change.ID),
-						"action_id": "view_regulatory_change",
 					},
 				},
-			},
-			{
-				Type: "context",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "mrkdwn",
-						"text": "🏛 Review Required by Legal and Compliance Teams.",
+				{
+					Type: "context",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "mrkdwn",
+							"text": i18n.T(locale, "regulatory_change.context"),
+						},
 					},
 				},
 			},
-		},
+		}
+		if tasks != nil {
+			message.Blocks = append(message.Blocks, slack.Block{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", regulatoryTaskSummary(*tasks), false),
+			})
+		}
+		return message
 	}
 
-	// Post the message to the regulatory-updates channel
-	ts, err := h.SlackClient.PostMessage(slack.ChannelMapping["regulatory"], message)
+	// Post the message to the channels routed for the regulatory change, regulatory-updates by default
+	item := notification.Item{
+		Type:     "regulatory_change",
+		Category: change.Jurisdiction,
+		Number:   change.Number,
+		Title:    change.ShortDesc,
+		URL:      fmt.Sprintf("%s/nav_to.do?uri=sn_regulatory_change.do?sys_id=%s", h.ServiceNowClient.BaseURL, change.ID),
+	}
+	_, ts, err := announce(h.SlackClient, h.Routes, item, build)
 	if err != nil {
 		return "", fmt.Errorf("error posting regulatory change message to Slack: %w", err)
 	}
@@ -172,45 +250,33 @@ func (h *RegulatoryChangeHandler) HandleImplementationPlan(changeID, channelID,
 	return nil
 }
 
-// ProcessRegulatoryCommand handles slash commands for regulatory changes
-func (h *RegulatoryChangeHandler) ProcessRegulatoryCommand(command *slack.Command) (string, error) {
-	// Handle different regulatory change commands
-	switch {
-	case command.Command == "/assess-impact":
-		// Format: /assess-impact CHANGE_ID Impact assessment details
-		parts := strings.SplitN(command.Text, " ", 2)
-		if len(parts) < 2 {
-			return "Invalid command format. Usage: /assess-impact CHANGE_ID Impact assessment details", nil
-		}
-
-		changeID := parts[0]
-		assessment := parts[1]
-
-		err := h.HandleImpactAssessment(changeID, command.ChannelID, "", command.UserID, assessment)
-		if err != nil {
-			return fmt.Sprintf("Error adding impact assessment: %s", err), nil
-		}
-
-		return "Impact assessment added successfully!", nil
-
-	case command.Command == "/plan-implementation":
-		// Format: /plan-implementation CHANGE_ID Implementation plan details
-		parts := strings.SplitN(command.Text, " ", 2)
-		if len(parts) < 2 {
-			return "Invalid command format. Usage: /plan-implementation CHANGE_ID Implementation plan details", nil
-		}
-
-		changeID := parts[0]
-		plan := parts[1]
-
-		err := h.HandleImplementationPlan(changeID, command.ChannelID, "", command.UserID, plan)
-		if err != nil {
-			return fmt.Sprintf("Error creating implementation plan: %s", err), nil
-		}
-
-		return "Implementation plan created successfully!", nil
-
-	default:
-		return "Unknown command", nil
+// RegisterCommands adds the regulatory change slash commands to the router
+func (h *RegulatoryChangeHandler) RegisterCommands(router *slack.CommandRouter) error {
+	if err := router.Register(slack.SlashCommand{
+		Name:        "/assess-impact",
+		Usage:       "CHANGE_ID ASSESSMENT",
+		Description: "Add an impact assessment to a regulatory change",
+		Args:        2,
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			if err := h.HandleImpactAssessment(args[0], command.ChannelID, "", command.UserID, args[1]); err != nil {
+				return "", fmt.Errorf("error adding impact assessment: %w", err)
+			}
+			return "Impact assessment added successfully!", nil
+		},
+	}); err != nil {
+		return err
 	}
+
+	return router.Register(slack.SlashCommand{
+		Name:        "/plan-implementation",
+		Usage:       "CHANGE_ID PLAN",
+		Description: "Add an implementation plan to a regulatory change",
+		Args:        2,
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			if err := h.HandleImplementationPlan(args[0], command.ChannelID, "", command.UserID, args[1]); err != nil {
+				return "", fmt.Errorf("error creating implementation plan: %w", err)
+			}
+			return "Implementation plan created successfully!", nil
+		},
+	})
 }
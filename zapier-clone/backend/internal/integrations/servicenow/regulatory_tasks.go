@@ -0,0 +1,368 @@
+// backend/internal/integrations/servicenow/regulatory_tasks.go
+package servicenow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+)
+
+// RegulatorySubtask is one step of the work a regulatory change needs, created
+// as a Jira issue under the change's epic
+type RegulatorySubtask struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// DaysBefore puts the due date this many days before the effective date
+	DaysBefore int `json:"days_before"`
+	// State is set on the regulatory change when the subtask is done
+	State string `json:"state,omitempty"`
+}
+
+// DefaultRegulatorySubtasks are created unless regulatory_subtasks.json in the
+// data directory replaces them
+var DefaultRegulatorySubtasks = []RegulatorySubtask{
+	{
+		Name:        "Impact Assessment",
+		Description: "Assess which policies, controls, systems and teams the regulatory change affects.",
+		DaysBefore:  60,
+		State:       "assessed",
+	},
+	{
+		Name:        "Gap Analysis",
+		Description: "Compare current controls against the new requirements and list the gaps to close.",
+		DaysBefore:  45,
+	},
+	{
+		Name:        "Implementation Plan",
+		Description: "Plan the changes that close the gaps, with owners and dates, before the effective date.",
+		DaysBefore:  30,
+		State:       "implementation_planned",
+	},
+}
+
+// LoadRegulatorySubtasks reads regulatory_subtasks.json from dataDir, or
+// returns the default subtasks when there is none
+func LoadRegulatorySubtasks(dataDir string) ([]RegulatorySubtask, error) {
+	path := filepath.Join(dataDir, "regulatory_subtasks.json")
+	file, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultRegulatorySubtasks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading regulatory subtasks: %w", err)
+	}
+
+	var subtasks []RegulatorySubtask
+	if err := json.Unmarshal(file, &subtasks); err != nil {
+		return nil, fmt.Errorf("error unmarshaling regulatory subtasks: %w", err)
+	}
+	seen := make(map[string]bool, len(subtasks))
+	for i, subtask := range subtasks {
+		if subtask.Name == "" {
+			return nil, fmt.Errorf("regulatory subtask %d: name is required", i+1)
+		}
+		if subtask.DaysBefore < 0 {
+			return nil, fmt.Errorf("regulatory subtask %q: days_before cannot be negative", subtask.Name)
+		}
+		if seen[strings.ToLower(subtask.Name)] {
+			return nil, fmt.Errorf("regulatory subtasks: duplicate name %q", subtask.Name)
+		}
+		seen[strings.ToLower(subtask.Name)] = true
+	}
+	return subtasks, nil
+}
+
+// RegulatoryTask is a Jira issue created for one subtask of a regulatory change
+type RegulatoryTask struct {
+	Name        string     `json:"name"`
+	Key         string     `json:"key"`
+	DueDate     string     `json:"due_date,omitempty"`
+	State       string     `json:"state,omitempty"`
+	Done        bool       `json:"done"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// RegulatoryChangeTasks links a regulatory change to its Jira epic and subtasks
+type RegulatoryChangeTasks struct {
+	ChangeID  string           `json:"sys_id"`
+	Number    string           `json:"number"`
+	Epic      string           `json:"epic"`
+	Tasks     []RegulatoryTask `json:"tasks"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// Completed counts the subtasks that are done
+func (t RegulatoryChangeTasks) Completed() int {
+	n := 0
+	for _, task := range t.Tasks {
+		if task.Done {
+			n++
+		}
+	}
+	return n
+}
+
+// RegulatoryTaskStore keeps the epic and subtasks of each regulatory change
+type RegulatoryTaskStore struct {
+	mutex    sync.RWMutex
+	changes  map[string]*RegulatoryChangeTasks
+	filePath string
+}
+
+// NewRegulatoryTaskStore loads the regulatory change tasks kept in storagePath
+func NewRegulatoryTaskStore(storagePath string) (*RegulatoryTaskStore, error) {
+	s := &RegulatoryTaskStore{
+		changes:  make(map[string]*RegulatoryChangeTasks),
+		filePath: filepath.Join(storagePath, "regulatory_change_tasks.json"),
+	}
+
+	if _, err := os.Stat(s.filePath); err == nil {
+		file, err := os.ReadFile(s.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading regulatory change tasks file: %w", err)
+		}
+		if err := json.Unmarshal(file, &s.changes); err != nil {
+			return nil, fmt.Errorf("error unmarshaling regulatory change tasks: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Get returns the tasks of a regulatory change. A nil store has none.
+func (s *RegulatoryTaskStore) Get(changeID string) (RegulatoryChangeTasks, bool) {
+	if s == nil {
+		return RegulatoryChangeTasks{}, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tasks, ok := s.changes[changeID]
+	if !ok {
+		return RegulatoryChangeTasks{}, false
+	}
+	return tasks.copy(), true
+}
+
+// FindIssue returns the regulatory change a subtask issue belongs to, and the
+// subtask's index
+func (s *RegulatoryTaskStore) FindIssue(issueKey string) (RegulatoryChangeTasks, int, bool) {
+	if s == nil {
+		return RegulatoryChangeTasks{}, 0, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, tasks := range s.changes {
+		for i, task := range tasks.Tasks {
+			if task.Key == issueKey {
+				return tasks.copy(), i, true
+			}
+		}
+	}
+	return RegulatoryChangeTasks{}, 0, false
+}
+
+// Save stores the tasks of a regulatory change
+func (s *RegulatoryTaskStore) Save(tasks RegulatoryChangeTasks) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	copied := tasks.copy()
+	s.changes[tasks.ChangeID] = &copied
+	return s.save()
+}
+
+// save persists the tasks to disk; callers hold the mutex
+func (s *RegulatoryTaskStore) save() error {
+	data, err := json.MarshalIndent(s.changes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling regulatory change tasks: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing regulatory change tasks: %w", err)
+	}
+	return nil
+}
+
+func (t RegulatoryChangeTasks) copy() RegulatoryChangeTasks {
+	t.Tasks = append([]RegulatoryTask(nil), t.Tasks...)
+	return t
+}
+
+// createImpactTasks creates a regulatory change's Jira epic and one issue per
+// configured subtask under it, due the subtask's DaysBefore ahead of the
+// effective date. The epic is linked back to the change with a work note
+// listing the subtasks. A change that already has an epic, e.g. from a
+// replayed webhook, keeps it.
+func (h *RegulatoryChangeHandler) createImpactTasks(change RegulatoryChange) (*RegulatoryChangeTasks, error) {
+	if existing, exists := h.Tasks.Get(change.ID); exists {
+		return &existing, nil
+	}
+
+	record, err := mapping.Record(change)
+	if err != nil {
+		return nil, err
+	}
+	epic, err := h.FieldMapping.Ticket("sn_regulatory_change", record)
+	if err != nil {
+		return nil, err
+	}
+	if epic.IssueType == "" {
+		epic.IssueType = "Epic"
+	}
+	h.Projects.Route("sn_regulatory_change", epic, h.JiraClient.ProjectKey)
+	if epic.DueDate.IsZero() && !change.EffectiveDate.IsZero() {
+		epic.DueDate = change.EffectiveDate
+	}
+	epic.AssigneeID = jiraAccount(h.ServiceNowClient.Context(), h.Identities, change.AssignedTo)
+
+	created, err := h.JiraClient.CreateIssue(epic)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Jira epic for regulatory change %s: %w", change.Number, err)
+	}
+
+	tasks := RegulatoryChangeTasks{
+		ChangeID:  change.ID,
+		Number:    change.Number,
+		Epic:      created.Key,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// Each subtask goes in the epic's project, which the field mapping may have chosen
+	for _, subtask := range h.Subtasks {
+		ticket := &jira.Ticket{
+			Project:     jira.ProjectOf(created.Key),
+			IssueType:   "Task",
+			Summary:     fmt.Sprintf("%s - %s", subtask.Name, change.ShortDesc),
+			Description: regulatorySubtaskDescription(subtask, change),
+			Parent:      created.Key,
+			AssigneeID:  epic.AssigneeID,
+			Labels:      []string{"regulatory-change", "auto-created"},
+		}
+		if !change.EffectiveDate.IsZero() {
+			ticket.DueDate = change.EffectiveDate.AddDate(0, 0, -subtask.DaysBefore)
+		}
+
+		result, err := h.JiraClient.CreateIssue(ticket)
+		if err != nil {
+			h.ServiceNowClient.Logger().Error("error creating regulatory change subtask", "subtask", subtask.Name, "sys_id", change.ID, "error", err)
+			continue
+		}
+		task := RegulatoryTask{Name: subtask.Name, Key: result.Key, State: subtask.State}
+		if !ticket.DueDate.IsZero() {
+			task.DueDate = ticket.DueDate.Format("2006-01-02")
+		}
+		tasks.Tasks = append(tasks.Tasks, task)
+	}
+
+	if err := h.Tasks.Save(tasks); err != nil {
+		h.ServiceNowClient.Logger().Error("error saving regulatory change tasks", "sys_id", change.ID, "error", err)
+	}
+
+	// ServiceNow learns the epic and the plan; failures there are only logged
+	if err := h.ServiceNowClient.UpdateRecord("sn_regulatory_change", change.ID, map[string]interface{}{"jira_ticket": created.Key}); err != nil {
+		h.ServiceNowClient.Logger().Warn("error linking regulatory change to Jira epic", "sys_id", change.ID, "error", err)
+	}
+	lines := []string{fmt.Sprintf("Jira epic %s created with %d %s:", created.Key, len(tasks.Tasks), plural(len(tasks.Tasks), "subtask", "subtasks"))}
+	for _, task := range tasks.Tasks {
+		line := fmt.Sprintf("- %s: %s", task.Key, task.Name)
+		if task.DueDate != "" {
+			line += fmt.Sprintf(" (due %s)", task.DueDate)
+		}
+		lines = append(lines, line)
+	}
+	key := JournalKey("regulatory_tasks", change.ID, created.Key)
+	if err := NewJournalWriter(h.ServiceNowClient).Write("sn_regulatory_change", change.ID, JournalWorkNotes, key, strings.Join(lines, "\n")); err != nil {
+		h.ServiceNowClient.Logger().Warn("error adding regulatory change work note", "sys_id", change.ID, "error", err)
+	}
+
+	return &tasks, nil
+}
+
+// regulatorySubtaskDescription describes a subtask's issue
+func regulatorySubtaskDescription(subtask RegulatorySubtask, change RegulatoryChange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*\n\n", subtask.Name)
+	if subtask.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", subtask.Description)
+	}
+	fmt.Fprintf(&b, "*Regulatory Change:* %s\n*Regulation:* %s\n*Jurisdiction:* %s\n", change.Number, change.Regulation, change.Jurisdiction)
+	if !change.EffectiveDate.IsZero() {
+		fmt.Fprintf(&b, "*Effective Date:* %s\n", change.EffectiveDate.Format("Jan 2, 2006"))
+	}
+	b.WriteString("\n----\nCompleting this issue is recorded on the regulatory change in ServiceNow.")
+	return b.String()
+}
+
+// regulatoryTaskSummary lists a change's epic and subtasks for its announcement
+func regulatoryTaskSummary(tasks RegulatoryChangeTasks) string {
+	lines := []string{fmt.Sprintf("*Jira Epic:* %s", tasks.Epic)}
+	for _, task := range tasks.Tasks {
+		line := fmt.Sprintf("• %s: %s", task.Key, task.Name)
+		if task.DueDate != "" {
+			line += fmt.Sprintf(", due %s", task.DueDate)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HandleJiraUpdate records the completion of a regulatory change subtask in
+// ServiceNow: a work note, the subtask's state if it has one, and a closing
+// note once every subtask is done. Reopening a subtask is noted as well. It
+// reports false when the issue is not a regulatory change subtask.
+func (h *RegulatoryChangeHandler) HandleJiraUpdate(event *jira.WebhookEvent) (bool, error) {
+	if event.Issue == nil {
+		return false, nil
+	}
+	tasks, i, ok := h.Tasks.FindIssue(event.Issue.Key)
+	if !ok {
+		return false, nil
+	}
+
+	task := &tasks.Tasks[i]
+	done := event.Issue.Fields.Status.Done()
+	if done == task.Done {
+		return true, nil
+	}
+
+	now := time.Now().UTC()
+	var note string
+	if done {
+		task.Done = true
+		task.CompletedAt = &now
+		note = fmt.Sprintf("%s completed in Jira (%s). %d of %d subtasks done.", task.Name, task.Key, tasks.Completed(), len(tasks.Tasks))
+	} else {
+		task.Done = false
+		task.CompletedAt = nil
+		note = fmt.Sprintf("%s reopened in Jira (%s). %d of %d subtasks done.", task.Name, task.Key, tasks.Completed(), len(tasks.Tasks))
+	}
+	if done && tasks.Completed() == len(tasks.Tasks) {
+		note += fmt.Sprintf("\nAll impact assessment subtasks of epic %s are complete.", tasks.Epic)
+	}
+	if err := h.Tasks.Save(tasks); err != nil {
+		return true, err
+	}
+
+	if done && task.State != "" {
+		if err := h.ServiceNowClient.UpdateRecord("sn_regulatory_change", tasks.ChangeID, map[string]interface{}{"state": task.State}); err != nil {
+			return true, fmt.Errorf("error updating regulatory change %s state: %w", tasks.Number, err)
+		}
+	}
+	key := JournalKey("regulatory_task", tasks.ChangeID, task.Key, fmt.Sprint(done), now.Format(time.RFC3339))
+	if err := NewJournalWriter(h.ServiceNowClient).Write("sn_regulatory_change", tasks.ChangeID, JournalWorkNotes, key, note); err != nil {
+		return true, fmt.Errorf("error adding regulatory change work note: %w", err)
+	}
+	return true, nil
+}
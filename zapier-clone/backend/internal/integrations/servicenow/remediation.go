@@ -0,0 +1,515 @@
+// backend/internal/integrations/servicenow/remediation.go
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+var (
+	// ErrMilestoneNotFound is returned for a milestone a finding's plan does not have
+	ErrMilestoneNotFound = errors.New("milestone not found")
+	// ErrInvalidMilestone is returned for a milestone without a title or with a malformed due date
+	ErrInvalidMilestone = errors.New("invalid milestone")
+)
+
+// Milestone is one step of an audit finding's remediation plan, mirrored as a
+// Jira sub-task of the finding's issue
+type Milestone struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// DueDate is a date, e.g. "2026-11-30"
+	DueDate     string     `json:"due_date,omitempty"`
+	Owner       string     `json:"owner,omitempty"`
+	JiraKey     string     `json:"jira_key,omitempty"`
+	Done        bool       `json:"done"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// RemediationPlan is the milestones of one audit finding
+type RemediationPlan struct {
+	FindingID string `json:"sys_id"`
+	Number    string `json:"number"`
+	Title     string `json:"title,omitempty"`
+	// JiraKey is the finding's issue, the parent of the milestone sub-tasks
+	JiraKey    string      `json:"jira_key,omitempty"`
+	Milestones []Milestone `json:"milestones"`
+	// PercentComplete is the share of milestones done, kept up to date on save
+	PercentComplete int       `json:"percent_complete"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Completed counts the milestones that are done
+func (p RemediationPlan) Completed() int {
+	n := 0
+	for _, milestone := range p.Milestones {
+		if milestone.Done {
+			n++
+		}
+	}
+	return n
+}
+
+// percent is the share of milestones done, 0 for a plan without milestones
+func (p RemediationPlan) percent() int {
+	if len(p.Milestones) == 0 {
+		return 0
+	}
+	return p.Completed() * 100 / len(p.Milestones)
+}
+
+// NextDue returns the open milestone due first, or nil
+func (p RemediationPlan) NextDue() *Milestone {
+	var next *Milestone
+	for i, milestone := range p.Milestones {
+		if milestone.Done || milestone.DueDate == "" {
+			continue
+		}
+		if next == nil || milestone.DueDate < next.DueDate {
+			next = &p.Milestones[i]
+		}
+	}
+	return next
+}
+
+// Progress describes in a locale how far the plan is, e.g. "2 of 5
+// milestones done (40%)"
+func (p RemediationPlan) Progress(locale string) string {
+	return i18n.Plural(locale, "remediation.progress", len(p.Milestones), p.Completed(), p.percent())
+}
+
+// milestone returns the index of the milestone with id
+func (p RemediationPlan) milestone(id int) (int, bool) {
+	for i, milestone := range p.Milestones {
+		if milestone.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (p RemediationPlan) copy() RemediationPlan {
+	p.Milestones = append([]Milestone(nil), p.Milestones...)
+	return p
+}
+
+// RemediationStore keeps the remediation plan of each audit finding
+type RemediationStore struct {
+	mutex    sync.RWMutex
+	plans    map[string]*RemediationPlan
+	filePath string
+}
+
+// NewRemediationStore loads the remediation plans kept in storagePath
+func NewRemediationStore(storagePath string) (*RemediationStore, error) {
+	s := &RemediationStore{
+		plans:    make(map[string]*RemediationPlan),
+		filePath: filepath.Join(storagePath, "remediation_plans.json"),
+	}
+
+	if _, err := os.Stat(s.filePath); err == nil {
+		file, err := os.ReadFile(s.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading remediation plans file: %w", err)
+		}
+		if err := json.Unmarshal(file, &s.plans); err != nil {
+			return nil, fmt.Errorf("error unmarshaling remediation plans: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Get returns the plan of a finding. A nil store has none.
+func (s *RemediationStore) Get(findingID string) (RemediationPlan, bool) {
+	if s == nil {
+		return RemediationPlan{}, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	plan, ok := s.plans[findingID]
+	if !ok {
+		return RemediationPlan{}, false
+	}
+	return plan.copy(), true
+}
+
+// List returns every plan, ordered by finding number
+func (s *RemediationStore) List() []RemediationPlan {
+	if s == nil {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	plans := make([]RemediationPlan, 0, len(s.plans))
+	for _, plan := range s.plans {
+		plans = append(plans, plan.copy())
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Number < plans[j].Number })
+	return plans
+}
+
+// FindIssue returns the plan a milestone sub-task belongs to, and the
+// milestone's index
+func (s *RemediationStore) FindIssue(issueKey string) (RemediationPlan, int, bool) {
+	if s == nil {
+		return RemediationPlan{}, 0, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, plan := range s.plans {
+		for i, milestone := range plan.Milestones {
+			if milestone.JiraKey == issueKey {
+				return plan.copy(), i, true
+			}
+		}
+	}
+	return RemediationPlan{}, 0, false
+}
+
+// Save stores a plan, updating its percent complete
+func (s *RemediationStore) Save(plan RemediationPlan) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	copied := plan.copy()
+	copied.PercentComplete = copied.percent()
+	s.plans[plan.FindingID] = &copied
+	return s.save()
+}
+
+// save persists the plans to disk; callers hold the mutex
+func (s *RemediationStore) save() error {
+	data, err := json.MarshalIndent(s.plans, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling remediation plans: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing remediation plans: %w", err)
+	}
+	return nil
+}
+
+// RemediationTracker manages the remediation milestones of audit findings.
+// Each milestone becomes a Jira sub-task of the finding's issue, and every
+// change to the plan is recorded in a work note and the finding's Slack thread.
+type RemediationTracker struct {
+	ServiceNowClient *Client
+	SlackClient      *slack.Client
+	JiraClient       *jira.Client
+	Plans            *RemediationStore
+	// Threads finds the Slack thread each finding was announced in
+	Threads *ThreadTracker
+	// Identities maps milestone owners to their Jira accounts when configured
+	Identities Identities
+	// Routes gives the language of each finding's thread and of command
+	// replies; nil writes English
+	Routes *notification.Router
+
+	now func() time.Time
+}
+
+// NewRemediationTracker creates a tracker that keeps plans in dataDir
+func NewRemediationTracker(serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, dataDir string) (*RemediationTracker, error) {
+	plans, err := NewRemediationStore(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	return &RemediationTracker{
+		ServiceNowClient: serviceNowClient,
+		SlackClient:      slackClient,
+		JiraClient:       jiraClient,
+		Plans:            plans,
+		now:              time.Now,
+	}, nil
+}
+
+// WithContext returns a copy of the tracker whose API calls run under ctx
+func (t *RemediationTracker) WithContext(ctx context.Context) *RemediationTracker {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	copied.ServiceNowClient = t.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = t.SlackClient.WithContext(ctx)
+	copied.JiraClient = t.JiraClient.WithContext(ctx)
+	copied.Threads = t.Threads.WithContext(ctx)
+	return &copied
+}
+
+// AddMilestone adds a milestone to a finding's plan, creating the plan with
+// the first one. The milestone's Jira sub-task is created first, so a
+// milestone is only kept once Jira has it; a finding without a Jira issue
+// gets milestones without sub-tasks.
+func (t *RemediationTracker) AddMilestone(findingID string, milestone Milestone) (*RemediationPlan, *Milestone, error) {
+	milestone.Title = strings.TrimSpace(milestone.Title)
+	if milestone.Title == "" {
+		return nil, nil, fmt.Errorf("%w: a title is required", ErrInvalidMilestone)
+	}
+	var due time.Time
+	if milestone.DueDate != "" {
+		parsed, err := time.Parse("2006-01-02", milestone.DueDate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: due_date must be YYYY-MM-DD", ErrInvalidMilestone)
+		}
+		due = parsed
+	}
+
+	record, err := t.ServiceNowClient.GetRecord("sn_audit_finding", findingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan, exists := t.Plans.Get(findingID)
+	if !exists {
+		plan = RemediationPlan{FindingID: findingID}
+	}
+	plan.Number = referenceValue(record["number"])
+	plan.Title = referenceValue(record["short_description"])
+	plan.JiraKey = referenceValue(record["jira_ticket"])
+
+	milestone.ID = 1
+	for _, existing := range plan.Milestones {
+		if existing.ID >= milestone.ID {
+			milestone.ID = existing.ID + 1
+		}
+	}
+	milestone.Done = false
+	milestone.CompletedAt = nil
+	milestone.CreatedAt = t.now().UTC()
+	milestone.JiraKey = ""
+
+	if plan.JiraKey != "" {
+		ticket := &jira.Ticket{
+			Project:     jira.ProjectOf(plan.JiraKey),
+			IssueType:   "Sub-task",
+			Summary:     fmt.Sprintf("[%s] %s", plan.Number, milestone.Title),
+			Description: remediationDescription(plan, milestone),
+			Parent:      plan.JiraKey,
+			DueDate:     due,
+			AssigneeID:  jiraAccount(t.ServiceNowClient.Context(), t.Identities, milestone.Owner),
+			Labels:      []string{"audit-remediation"},
+		}
+		created, err := t.JiraClient.CreateIssue(ticket)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating Jira sub-task for milestone: %w", err)
+		}
+		milestone.JiraKey = created.Key
+	}
+
+	plan.Milestones = append(plan.Milestones, milestone)
+	plan.UpdatedAt = t.now().UTC()
+	if err := t.Plans.Save(plan); err != nil {
+		return nil, nil, err
+	}
+	plan, _ = t.Plans.Get(findingID)
+
+	t.record(plan, JournalKey("remediation", findingID, fmt.Sprint(milestone.ID), "added"), func(locale string) string {
+		text := i18n.T(locale, "remediation.added", milestone.ID, milestone.Title)
+		if milestone.DueDate != "" {
+			text += i18n.T(locale, "remediation.added_due", milestone.DueDate)
+		}
+		if milestone.JiraKey != "" {
+			text += i18n.T(locale, "remediation.added_jira", milestone.JiraKey)
+		}
+		return text + "."
+	})
+	return &plan, &milestone, nil
+}
+
+// SetMilestoneDone completes or reopens a milestone, moving its Jira sub-task
+// to Done or To Do to match
+func (t *RemediationTracker) SetMilestoneDone(findingID string, milestoneID int, done bool) (*RemediationPlan, error) {
+	plan, exists := t.Plans.Get(findingID)
+	if !exists {
+		return nil, ErrMilestoneNotFound
+	}
+	i, ok := plan.milestone(milestoneID)
+	if !ok {
+		return nil, ErrMilestoneNotFound
+	}
+	milestone := plan.Milestones[i]
+	if milestone.Done == done {
+		return &plan, nil
+	}
+
+	if milestone.JiraKey != "" {
+		status := "Done"
+		if !done {
+			status = "To Do"
+		}
+		if err := t.JiraClient.UpdateIssue(milestone.JiraKey, &jira.TicketUpdate{Status: status}); err != nil {
+			return nil, fmt.Errorf("error moving Jira sub-task %s to %s: %w", milestone.JiraKey, status, err)
+		}
+	}
+	return t.setDone(plan, i, done, "")
+}
+
+// HandleJiraUpdate records a milestone sub-task that moved to or from a done
+// status in Jira. It reports false when the issue is not a milestone.
+func (t *RemediationTracker) HandleJiraUpdate(event *jira.WebhookEvent) (bool, error) {
+	if t == nil || event.Issue == nil {
+		return false, nil
+	}
+	plan, i, ok := t.Plans.FindIssue(event.Issue.Key)
+	if !ok {
+		return false, nil
+	}
+	done := event.Issue.Fields.Status.Done()
+	if done == plan.Milestones[i].Done {
+		return true, nil
+	}
+	_, err := t.setDone(plan, i, done, event.Issue.Key)
+	return true, err
+}
+
+// setDone marks milestone i and records the plan's new progress; jiraKey is
+// the sub-task when the change was made in Jira
+func (t *RemediationTracker) setDone(plan RemediationPlan, i int, done bool, jiraKey string) (*RemediationPlan, error) {
+	now := t.now().UTC()
+	milestone := &plan.Milestones[i]
+	milestone.Done = done
+	milestone.CompletedAt = nil
+	verb := "reopened"
+	if done {
+		milestone.CompletedAt = &now
+		verb = "completed"
+	}
+	plan.UpdatedAt = now
+	if err := t.Plans.Save(plan); err != nil {
+		return nil, err
+	}
+	plan, _ = t.Plans.Get(plan.FindingID)
+
+	t.record(plan, JournalKey("remediation", plan.FindingID, fmt.Sprint(milestone.ID), verb, now.Format(time.RFC3339)), func(locale string) string {
+		var text string
+		if jiraKey != "" {
+			text = i18n.T(locale, "remediation."+verb+"_in_jira", milestone.ID, jiraKey, milestone.Title)
+		} else {
+			text = i18n.T(locale, "remediation."+verb, milestone.ID, milestone.Title)
+		}
+		if done && plan.Completed() == len(plan.Milestones) {
+			text += i18n.T(locale, "remediation.all_done")
+		}
+		return text
+	})
+	return &plan, nil
+}
+
+// record adds a change to the plan, with its progress, to the finding's work
+// notes in English and to its Slack thread in the channel's language. describe
+// writes the change in a locale. Failures are only logged.
+func (t *RemediationTracker) record(plan RemediationPlan, key string, describe func(locale string) string) {
+	text := func(locale string) string {
+		return describe(locale) + "\n" + i18n.T(locale, "remediation.progress_line", plan.Progress(locale))
+	}
+	logger := t.ServiceNowClient.Logger()
+	if err := NewJournalWriter(t.ServiceNowClient).Write("sn_audit_finding", plan.FindingID, JournalWorkNotes, key, text(i18n.DefaultLocale)); err != nil {
+		logger.Warn("error adding remediation work note", "sys_id", plan.FindingID, "error", err)
+	}
+
+	if t.Threads == nil {
+		return
+	}
+	thread, ok := t.Threads.Threads.Get(plan.FindingID)
+	if !ok {
+		return
+	}
+	if _, err := t.SlackClient.PostReply(thread.Channel, thread.TS, slack.Message{Text: "🪜 " + text(t.Routes.Locale(thread.Channel))}); err != nil {
+		logger.Warn("error posting remediation progress to Slack", "sys_id", plan.FindingID, "error", err)
+	}
+}
+
+// remediationDescription describes a milestone's sub-task
+func remediationDescription(plan RemediationPlan, milestone Milestone) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Remediation milestone %d of %s*\n\n", milestone.ID, plan.Number)
+	if milestone.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", milestone.Description)
+	}
+	fmt.Fprintf(&b, "*Audit Finding:* %s %s\n", plan.Number, plan.Title)
+	if milestone.Owner != "" {
+		fmt.Fprintf(&b, "*Owner:* %s\n", milestone.Owner)
+	}
+	b.WriteString("\n----\nCompleting this sub-task is recorded on the audit finding in ServiceNow.")
+	return b.String()
+}
+
+// RegisterCommands adds /add-milestone and /complete-milestone
+func (t *RemediationTracker) RegisterCommands(router *slack.CommandRouter) error {
+	commands := []slack.SlashCommand{
+		{
+			Name:        "/add-milestone",
+			Usage:       "FINDING_ID DUE_DATE TITLE",
+			Description: "Add a remediation milestone to an audit finding",
+			Args:        3,
+			Validate: func(args []string) error {
+				if _, err := time.Parse("2006-01-02", args[1]); err != nil {
+					return fmt.Errorf("%q is not a date like 2026-11-30", args[1])
+				}
+				return nil
+			},
+			Handler: func(command *slack.Command, args []string) (string, error) {
+				locale := t.Routes.Locale(command.ChannelID)
+				plan, milestone, err := t.AddMilestone(args[0], Milestone{Title: args[2], DueDate: args[1], Owner: serviceNowAssignee(t.ServiceNowClient.Context(), t.Identities, command.UserID)})
+				if errors.Is(err, ErrRecordNotFound) {
+					return i18n.T(locale, "remediation.command.no_finding", args[0]), nil
+				}
+				if err != nil {
+					return "", err
+				}
+				if milestone.JiraKey != "" {
+					return i18n.T(locale, "remediation.command.added_jira", milestone.ID, plan.Number, milestone.JiraKey, plan.Progress(locale)), nil
+				}
+				return i18n.T(locale, "remediation.command.added", milestone.ID, plan.Number, plan.Progress(locale)), nil
+			},
+		},
+		{
+			Name:        "/complete-milestone",
+			Usage:       "FINDING_ID MILESTONE_ID",
+			Description: "Mark a remediation milestone of an audit finding done",
+			Args:        2,
+			Handler: func(command *slack.Command, args []string) (string, error) {
+				locale := t.Routes.Locale(command.ChannelID)
+				var id int
+				if _, err := fmt.Sscan(args[1], &id); err != nil {
+					return i18n.T(locale, "remediation.command.not_a_number", args[1]), nil
+				}
+				plan, err := t.SetMilestoneDone(args[0], id, true)
+				if errors.Is(err, ErrMilestoneNotFound) {
+					return i18n.T(locale, "remediation.command.no_milestone", args[0], id), nil
+				}
+				if err != nil {
+					return "", err
+				}
+				return i18n.T(locale, "remediation.command.done", id, plan.Number, plan.Progress(locale)), nil
+			},
+		},
+	}
+	for _, command := range commands {
+		if err := router.Register(command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@
 package servicenow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -42,6 +43,14 @@ func NewReportingHandler(serviceNowClient *Client, slackClient *slack.Client) *R
 	}
 }
 
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *ReportingHandler) WithContext(ctx context.Context) *ReportingHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	return &copied
+}
+
 // GetGRCSummary fetches a summary of GRC data from ServiceNow
 func (h *ReportingHandler) GetGRCSummary() (*GRCSummary, error) {
 	resp, err := h.ServiceNowClient.makeRequest("GET", "api/now/table/sn_grc_summary", nil)
@@ -239,20 +248,17 @@ func (h *ReportingHandler) HandleStatusRequest(channelID, userID string) error {
 	return nil
 }
 
-// ProcessReportingCommand handles slash commands for GRC reporting
-func (h *ReportingHandler) ProcessReportingCommand(command *slack.Command) (string, error) {
-	// Handle different reporting commands
-	switch {
-	case command.Command == "/grc-status":
-		// Handle status request in the channel where the command was issued
-		err := h.HandleStatusRequest(command.ChannelID, command.UserID)
-		if err != nil {
-			return fmt.Sprintf("Error getting GRC status: %s", err), nil
-		}
-
-		return "", nil // Empty response as we're sending a message to the channel directly
-
-	default:
-		return "Unknown command", nil
-	}
+// RegisterCommands adds the reporting slash commands to the router
+func (h *ReportingHandler) RegisterCommands(router *slack.CommandRouter) error {
+	return router.Register(slack.SlashCommand{
+		Name:        "/grc-status",
+		Description: "Post the GRC status summary to this channel",
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			// The summary is posted to the channel directly, so there is nothing to reply
+			if err := h.HandleStatusRequest(command.ChannelID, command.UserID); err != nil {
+				return "", fmt.Errorf("error getting GRC status: %w", err)
+			}
+			return "", nil
+		},
+	})
 }
@@ -2,11 +2,18 @@
 package servicenow
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+	"github.com/shivani-1505/zapier-clone/backend/internal/riskmatrix"
 )
 
 // RiskHandler handles risk notifications and interactions
@@ -14,124 +21,234 @@ type RiskHandler struct {
 	ServiceNowClient *Client
 	SlackClient      *slack.Client
 	JiraClient       *jira.Client
-	RiskJiraMapping  *jira.RiskJiraMapping
+	RiskJiraMapping  jira.MappingStore
+	// FieldMapping builds Jira issues from risk records
+	FieldMapping *mapping.Engine
+	// Threads keeps the announcement of each risk so updates reply in its thread
+	Threads *ThreadTracker
+	// Routes picks the channels new risks are announced in
+	Routes *notification.Router
+	// Identities maps assignees to their Jira and ServiceNow accounts when configured
+	Identities Identities
+	// Assignees mentions the risk owner in Slack and DMs them when configured
+	Assignees *AssigneeNotifier
+	// Approvals holds back the Jira issue of configured severities until approved in Slack
+	Approvals *ApprovalGate
+	// Projects picks the Jira project when no mapping does
+	Projects *ProjectProvisioner
+	// Matrix rates risks by likelihood and impact; the rating's priority and
+	// score go on the Jira issue. Without it, priority follows severity alone.
+	Matrix *riskmatrix.Store
+	// Duplicates looks for an existing issue before one is created for a risk
+	Duplicates *DuplicateDetector
 }
 
 // NewRiskHandler creates a new risk handler
-func NewRiskHandler(serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, mapping *jira.RiskJiraMapping) *RiskHandler {
+func NewRiskHandler(serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, mappingStore jira.MappingStore) *RiskHandler {
 	return &RiskHandler{
 		ServiceNowClient: serviceNowClient,
 		SlackClient:      slackClient,
 		JiraClient:       jiraClient,
-		RiskJiraMapping:  mapping,
+		RiskJiraMapping:  mappingStore,
+		FieldMapping:     mapping.NewEngine(mapping.Default()),
 	}
 }
 
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *RiskHandler) WithContext(ctx context.Context) *RiskHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	copied.JiraClient = h.JiraClient.WithContext(ctx)
+	copied.Threads = h.Threads.WithContext(ctx)
+	return &copied
+}
+
 // HandleNewRisk processes a new risk and notifies Slack
 func (h *RiskHandler) HandleNewRisk(risk Risk) (string, error) {
 	// Format risk severity for display
 	severity := RiskSeverity(risk.RiskScore)
 
-	// Create a Slack message for the risk
-	message := slack.Message{
-		Blocks: []slack.Block{
-			{
-				Type: "header",
-				Text: slack.NewTextObject("plain_text", fmt.Sprintf("🚨 New %s-Severity Risk: %s", severity, risk.ShortDesc), true),
-			},
-			{
-				Type: "section",
-				Fields: []*slack.TextObject{
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Risk ID:*\n%s", risk.Number), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Category:*\n%s", risk.Category), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Severity:*\n%s", severity), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Due Date:*\n%s", risk.DueDate.Format("Jan 2, 2006")), false),
+	// Create the Slack message for the risk in a channel's language
+	build := func(locale string) slack.Message {
+		message := slack.Message{
+			Blocks: []slack.Block{
+				{
+					Type: "header",
+					Text: slack.NewTextObject("plain_text", i18n.T(locale, "risk.header", i18n.Value(locale, "severity", severity), risk.ShortDesc), true),
 				},
-			},
-			{
-				Type: "section",
-				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Description:*\n%s", risk.Description), false),
-			},
-			{
-				Type: "actions",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Discuss Mitigation",
-							"emoji": true,
-						},
-						"value":     fmt.Sprintf("discuss_risk_%s", risk.ID),
-						"action_id": "discuss_risk",
+				{
+					Type: "section",
+					Fields: []*slack.TextObject{
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "risk.field.number", risk.Number), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.category", risk.Category), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.severity", i18n.Value(locale, "severity", severity)), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.due_date", i18n.Date(locale, risk.DueDate)), false),
 					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Assign Owner",
-							"emoji": true,
+				},
+				{
+					Type: "section",
+					Text: slack.NewTextObject("mrkdwn", i18n.T(locale, "field.description", risk.Description), false),
+				},
+				{
+					Type: "actions",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "risk.button.discuss"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("discuss_risk_%s", risk.ID),
+							"action_id": "discuss_risk",
 						},
-						"value":     fmt.Sprintf("assign_risk_%s", risk.ID),
-						"action_id": "assign_risk",
-					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "View in ServiceNow",
-							"emoji": true,
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.assign_owner"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("assign_risk_%s", risk.ID),
+							"action_id": "assign_risk",
+						},
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.view_servicenow"),
+								"emoji": true,
+							},
+							"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_risk_risk.do?sys_id=%s", h.ServiceNowClient.BaseURL, risk.ID),
+							"action_id": "view_risk",
 						},
-						"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_risk_risk.do?sys_id=%s", h.ServiceNowClient.BaseURL, risk.ID),
-						"action_id": "view_risk",
 					},
 				},
-			},
-			{
-				Type: "context",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "mrkdwn",
-						"text": fmt.Sprintf("👁️ This risk requires action by IT and Security teams due by *%s*.", risk.DueDate.Format("Jan 2, 2006")),
+				{
+					Type: "context",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "mrkdwn",
+							"text": strings.TrimSpace(i18n.T(locale, "risk.context", i18n.Date(locale, risk.DueDate), h.SlackClient.MentionCategory("risk-management"))),
+						},
 					},
 				},
 			},
-		},
+		}
+
+		if assessment, ok := h.assess(risk); ok {
+			message.Blocks[1].Fields = append(message.Blocks[1].Fields, slack.NewTextObject("mrkdwn", i18n.T(locale, "risk.field.matrix", matrixSummary(assessment)), false))
+		}
+		if risk.AssignedTo != "" {
+			owner := h.Assignees.Mention(h.SlackClient.Context(), risk.AssignedTo, risk.AssignedTo)
+			message.Blocks[1].Fields = append(message.Blocks[1].Fields, slack.NewTextObject("mrkdwn", i18n.T(locale, "field.assigned_to", owner), false))
+		}
+		return message
 	}
 
-	// Post the message to the risk-management channel
-	ts, err := h.SlackClient.PostMessage(slack.ChannelMapping["risk-management"], message)
+	// Post the message to the channels routed for the risk, risk-management by default
+	item := notification.Item{
+		Type:     "risk",
+		Severity: severity,
+		Category: risk.Category,
+		Number:   risk.Number,
+		Title:    risk.ShortDesc,
+		URL:      fmt.Sprintf("%s/nav_to.do?uri=sn_risk_risk.do?sys_id=%s", h.ServiceNowClient.BaseURL, risk.ID),
+	}
+	channel, ts, err := announce(h.SlackClient, h.Routes, item, build)
 	if err != nil {
 		return "", fmt.Errorf("error posting risk message to Slack: %w", err)
 	}
+	locale := h.Routes.Locale(channel)
+	message := build(locale)
+	h.Threads.Start("sn_risk_risk", risk.ID, risk.Number, risk.State, risk.AssignedTo, channel, ts, message)
+	if risk.AssignedTo != "" {
+		h.Assignees.NotifyRisk(h.SlackClient, risk.Number, risk.AssignedTo, message)
+	}
+
+	// A risk that looks like an existing issue is linked to it when the
+	// duplicate rule says so
+	duplicate := h.duplicateOf(risk, severity)
+	if duplicate != nil && duplicate.Action == DuplicateLink {
+		if err := h.LinkDuplicate(risk.ID, risk.Number, duplicate); err != nil {
+			return ts, err
+		}
+		if ts != "" {
+			reply := slack.Message{
+				Text: i18n.T(locale, "risk.duplicate_linked", h.JiraClient.BaseURL, duplicate.Key, duplicate.Key, duplicate.Reason(locale)),
+			}
+			if _, err := h.SlackClient.PostReply(channel, ts, reply); err != nil {
+				h.ServiceNowClient.Logger().Warn("error posting duplicate link to Slack", "sys_id", risk.ID, "error", err)
+			}
+		}
+		return ts, nil
+	}
+
+	// Ask in the thread instead when the risk's severity needs approval or it
+	// may be a duplicate; the issue is created by CreateApprovedIssue
+	if ts != "" && (duplicate != nil || h.Approvals.Required("sn_risk_risk", severity)) {
+		approval := Approval{
+			ID:        risk.ID,
+			Table:     "sn_risk_risk",
+			Number:    risk.Number,
+			Severity:  severity,
+			Title:     risk.ShortDesc,
+			Duplicate: duplicate,
+			Channel:   channel,
+			ThreadTS:  ts,
+		}
+		return ts, h.Approvals.Request(h.SlackClient, approval, risk)
+	}
 
 	// Create a Jira issue for the risk
 	jiraIssue, err := h.createJiraIssue(risk, severity)
 	if err != nil {
 		// Log the error but continue - we don't want to fail the whole process if just Jira fails
 		// In a real implementation, you might want more sophisticated error handling/retries
-		fmt.Printf("Error creating Jira issue: %s\n", err)
+		h.ServiceNowClient.Logger().Error("error creating Jira issue for risk", "sys_id", risk.ID, "error", err)
 	} else {
 		// Store the mapping between ServiceNow risk and Jira issue
 		if err := h.RiskJiraMapping.AddMapping(risk.ID, jiraIssue.Key); err != nil {
-			fmt.Printf("Error storing risk-jira mapping: %s\n", err)
+			h.ServiceNowClient.Logger().Error("error storing risk-jira mapping", "sys_id", risk.ID, "issue", jiraIssue.Key, "error", err)
 		}
 
-		// Add a comment to the Slack thread about the Jira issue
-		jiraMessage := slack.Message{
-			Text: fmt.Sprintf("📋 This risk has been synced with Jira as issue *<%s/browse/%s|%s>*",
-				h.JiraClient.BaseURL, jiraIssue.Key, jiraIssue.Key),
-		}
+		// Add a comment to the Slack thread about the Jira issue; a risk held for
+		// the digest has no message to reply to
+		if ts != "" {
+			jiraMessage := slack.Message{
+				Text: fmt.Sprintf("📋 This risk has been synced with Jira as issue *<%s/browse/%s|%s>*",
+					h.JiraClient.BaseURL, jiraIssue.Key, jiraIssue.Key),
+			}
 
-		_, err := h.SlackClient.PostReply(slack.ChannelMapping["risk-management"], ts, jiraMessage)
-		if err != nil {
-			fmt.Printf("Error posting Jira link to Slack: %s\n", err)
+			_, err := h.SlackClient.PostReply(channel, ts, jiraMessage)
+			if err != nil {
+				h.ServiceNowClient.Logger().Warn("error posting Jira link to Slack", "sys_id", risk.ID, "issue", jiraIssue.Key, "error", err)
+			}
 		}
 	}
 
 	return ts, nil
 }
 
+// CreateApprovedIssue creates and links the Jira issue of a risk whose
+// approval was granted, returning its key
+func (h *RiskHandler) CreateApprovedIssue(approval *Approval) (string, error) {
+	var risk Risk
+	if err := json.Unmarshal(approval.Record, &risk); err != nil {
+		return "", fmt.Errorf("error reading approved risk %s: %w", approval.Number, err)
+	}
+
+	jiraIssue, err := h.createJiraIssue(risk, approval.Severity)
+	if err != nil {
+		return "", fmt.Errorf("error creating Jira issue for risk %s: %w", approval.Number, err)
+	}
+	if err := h.RiskJiraMapping.AddMapping(risk.ID, jiraIssue.Key); err != nil {
+		return jiraIssue.Key, fmt.Errorf("error storing risk-jira mapping: %w", err)
+	}
+	return jiraIssue.Key, nil
+}
+
 // HandleRiskUpdate processes a risk update and updates the Slack message
 func (h *RiskHandler) HandleRiskUpdate(risk Risk, channelID, threadTS string) error {
 	// Format a message about the update
@@ -159,17 +276,16 @@ func (h *RiskHandler) HandleRiskUpdate(risk Risk, channelID, threadTS string) er
 	// Update the corresponding Jira issue if one exists
 	jiraKey, exists := h.RiskJiraMapping.GetJiraKeyFromRiskID(risk.ID)
 	if exists {
-		// Map the fields to update in Jira
-		fields := map[string]interface{}{}
-
-		// Update status based on ServiceNow state
+		// Update status based on ServiceNow state. Jira only changes status
+		// through a transition, which the client finds by the status name.
+		ticketUpdate := &jira.TicketUpdate{}
 		switch risk.State {
 		case "Draft":
-			fields["status"] = map[string]string{"name": "To Do"}
+			ticketUpdate.Status = "To Do"
 		case "In Progress":
-			fields["status"] = map[string]string{"name": "In Progress"}
+			ticketUpdate.Status = "In Progress"
 		case "Completed":
-			fields["status"] = map[string]string{"name": "Done"}
+			ticketUpdate.Status = "Done"
 			// Add more state mappings as needed
 		}
 
@@ -178,18 +294,14 @@ func (h *RiskHandler) HandleRiskUpdate(risk Risk, channelID, threadTS string) er
 			// Add as comment instead of field update
 			commentText := fmt.Sprintf("Mitigation Plan updated in ServiceNow:\n%s", risk.MitigationPlan)
 			if err := h.JiraClient.AddComment(jiraKey, commentText); err != nil {
-				fmt.Printf("Error adding mitigation plan comment to Jira: %s\n", err)
+				h.ServiceNowClient.Logger().Error("error adding mitigation plan comment to Jira", "issue", jiraKey, "error", err)
 			}
 		}
 
-		// Only update Jira if we have fields to update
-		if len(fields) > 0 {
-			ticketUpdate := &jira.TicketUpdate{
-				Fields: fields,
-			}
-
+		// Only update Jira if the status changes
+		if ticketUpdate.Status != "" {
 			if err := h.JiraClient.UpdateIssue(jiraKey, ticketUpdate); err != nil {
-				fmt.Printf("Error updating Jira issue: %s\n", err)
+				h.ServiceNowClient.Logger().Error("error updating Jira issue", "issue", jiraKey, "error", err)
 			}
 		}
 	}
@@ -214,7 +326,7 @@ func (h *RiskHandler) HandleRiskDiscussion(riskID, channelID, threadTS, userID,
 	if exists {
 		commentText := fmt.Sprintf("Comment from Slack by %s:\n%s", userID, text)
 		if err := h.JiraClient.AddComment(jiraKey, commentText); err != nil {
-			fmt.Printf("Error adding comment to Jira: %s\n", err)
+			h.ServiceNowClient.Logger().Error("error adding comment to Jira", "issue", jiraKey, "error", err)
 		}
 	}
 
@@ -237,57 +349,41 @@ func (h *RiskHandler) HandleRiskDiscussion(riskID, channelID, threadTS, userID,
 	return nil
 }
 
-// Add this method to your RiskHandler implementation:
+// LinkDuplicate links a risk to the existing issue it duplicates instead of
+// creating one, and notes it on the issue
+func (h *RiskHandler) LinkDuplicate(riskID, number string, duplicate *DuplicateMatch) error {
+	if err := h.RiskJiraMapping.AddMapping(riskID, duplicate.Key); err != nil {
+		return fmt.Errorf("error storing risk-jira mapping: %w", err)
+	}
+	if err := noteDuplicate(h.JiraClient, "sn_risk_risk", number, duplicate); err != nil {
+		h.ServiceNowClient.Logger().Warn("error noting duplicate on Jira issue", "issue", duplicate.Key, "sys_id", riskID, "error", err)
+	}
+	return nil
+}
+
+// duplicateOf returns the issue a new risk may duplicate, or nil. A check
+// that fails is logged and the issue is created as usual.
+func (h *RiskHandler) duplicateOf(risk Risk, severity string) *DuplicateMatch {
+	if _, ok := h.Duplicates.Rule("sn_risk_risk"); !ok {
+		return nil
+	}
+	ticket, err := h.riskTicket(risk, severity)
+	if err != nil {
+		return nil
+	}
+	duplicate, err := h.Duplicates.Find(h.JiraClient, "sn_risk_risk", risk.Number, ticket)
+	if err != nil {
+		h.ServiceNowClient.Logger().Warn("error checking for duplicate Jira issues", "sys_id", risk.ID, "error", err)
+		return nil
+	}
+	return duplicate
+}
 
 // createJiraIssue creates a Jira issue for a ServiceNow risk
 func (h *RiskHandler) createJiraIssue(risk Risk, severity string) (*jira.Ticket, error) {
-	// Map ServiceNow risk severity to Jira priority
-	priority := "Medium" // Default priority
-	switch severity {
-	case "Critical":
-		priority = "Highest"
-	case "High":
-		priority = "High"
-	case "Medium":
-		priority = "Medium"
-	case "Low":
-		priority = "Low"
-	}
-
-	// Create formatted description with details from ServiceNow
-	description := fmt.Sprintf(`*Risk Details from ServiceNow*
-    
-*Risk Number:* %s
-*Category:* %s
-*Severity:* %s
-*Risk Score:* %.1f
-*Due Date:* %s
-    
-*Description:*
-%s
-
-*Possible Impact:*
-%s
-
-----
-This issue was automatically created from ServiceNow Risk %s.
-Please update both systems when changes are made.`,
-		risk.Number,
-		risk.Category,
-		severity,
-		risk.RiskScore,
-		risk.DueDate.Format("May 2, 2025"),
-		risk.Description,
-		risk.Impact,
-		risk.Number)
-
-	// Create a Jira ticket struct
-	ticket := &jira.Ticket{
-		Summary:     fmt.Sprintf("[%s] %s", risk.Number, risk.ShortDesc),
-		Description: description,
-		IssueType:   "Risk",
-		Priority:    priority,
-		DueDate:     risk.DueDate,
+	ticket, err := h.riskTicket(risk, severity)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the Jira issue
@@ -299,6 +395,41 @@ Please update both systems when changes are made.`,
 	return issue, nil
 }
 
+// riskTicket builds the Jira issue of a risk
+func (h *RiskHandler) riskTicket(risk Risk, severity string) (*jira.Ticket, error) {
+	record, err := mapping.Record(risk)
+	if err != nil {
+		return nil, err
+	}
+	// Severity is derived from the score, so mappings can use it like a record field
+	record["severity"] = severity
+	assessment, assessed := h.assess(risk)
+	if assessed {
+		record["matrix_score"] = assessment.Score
+		record["matrix_rating"] = assessment.Rating
+	}
+
+	ticket, err := h.FieldMapping.Ticket("sn_risk_risk", record)
+	if err != nil {
+		return nil, err
+	}
+	if assessed {
+		// The matrix outranks the severity-based priority of the mapping
+		if assessment.Priority != "" {
+			ticket.Priority = assessment.Priority
+		}
+		if field := h.JiraClient.RiskScoreField(); field != "" {
+			if ticket.Fields == nil {
+				ticket.Fields = make(map[string]interface{})
+			}
+			ticket.Fields[field] = assessment.Score
+		}
+	}
+	h.Projects.Route("sn_risk_risk", ticket, h.JiraClient.ProjectKey)
+	ticket.AssigneeID = jiraAccount(h.ServiceNowClient.Context(), h.Identities, risk.AssignedTo)
+	return ticket, nil
+}
+
 // HandleRiskAssignment processes a risk assignment
 func (h *RiskHandler) HandleRiskAssignment(riskID, channelID, threadTS, assigneeID string) error {
 	// Get the assignee's name from their Slack ID (in a real implementation, you'd look this up)
@@ -315,9 +446,11 @@ func (h *RiskHandler) HandleRiskAssignment(riskID, channelID, threadTS, assignee
 		return fmt.Errorf("error posting risk assignment to Slack thread: %w", err)
 	}
 
-	// Update ServiceNow with the assignment
+	// Update ServiceNow with the assignment, as the Slack user's sys_user when mapped
+	ctx := h.ServiceNowClient.Context()
+	serviceNowUser := serviceNowAssignee(ctx, h.Identities, assigneeID)
 	body := map[string]string{
-		"assigned_to": assigneeID,
+		"assigned_to": serviceNowUser,
 	}
 
 	_, err = h.ServiceNowClient.makeRequest("PATCH", fmt.Sprintf("api/now/table/sn_risk_risk/%s", riskID), body)
@@ -328,30 +461,76 @@ func (h *RiskHandler) HandleRiskAssignment(riskID, channelID, threadTS, assignee
 	// Update the corresponding Jira issue if one exists
 	jiraKey, exists := h.RiskJiraMapping.GetJiraKeyFromRiskID(riskID)
 	if exists {
-		// Create fields for Jira update - we're just updating the assignee here
-		fields := map[string]interface{}{
-			"assignee": map[string]string{
-				"name": assigneeID, // In a real implementation, you'd map this to a Jira username
-			},
+		// Assign the mapped Jira account; unmapped users keep the Slack ID as before
+		ticketUpdate := &jira.TicketUpdate{Assignee: assigneeID}
+		if accountID := jiraAccount(ctx, h.Identities, serviceNowUser); accountID != "" {
+			ticketUpdate = &jira.TicketUpdate{AssigneeID: accountID}
 		}
-
-		// Only update Jira if we have fields to update
-		if len(fields) > 0 {
-			ticketUpdate := &jira.TicketUpdate{
-				Fields: fields,
-			}
-
-			if err := h.JiraClient.UpdateIssue(jiraKey, ticketUpdate); err != nil {
-				fmt.Printf("Error updating Jira issue: %s\n", err)
-			}
+		if err := h.JiraClient.UpdateIssue(jiraKey, ticketUpdate); err != nil {
+			logging.FromContext(ctx).Error("error updating Jira issue", "issue", jiraKey, "error", err)
 		}
 
 		// Add a comment about the assignment
 		commentText := fmt.Sprintf("Risk assigned to %s", assigneeName)
 		if err := h.JiraClient.AddComment(jiraKey, commentText); err != nil {
-			fmt.Printf("Error adding assignment comment to Jira: %s\n", err)
+			logging.FromContext(ctx).Error("error adding assignment comment to Jira", "issue", jiraKey, "error", err)
 		}
 	}
 
 	return nil
 }
+
+// assess rates a risk with the matrix; ok is false without a matrix or when
+// the risk's likelihood or impact is not one of its levels
+func (h *RiskHandler) assess(risk Risk) (riskmatrix.Assessment, bool) {
+	if h.Matrix == nil {
+		return riskmatrix.Assessment{}, false
+	}
+	return h.Matrix.Get().Assess(risk.Likelihood, risk.Impact)
+}
+
+// RerateRisk rates an updated risk again, since its likelihood or impact may
+// have changed, and brings the priority and Risk Matrix Score of its Jira
+// issue up to date. An issue that already matches is not written.
+func (h *RiskHandler) RerateRisk(risk Risk) error {
+	assessment, ok := h.assess(risk)
+	if !ok {
+		return nil
+	}
+	jiraKey, exists := h.RiskJiraMapping.GetJiraKeyFromRiskID(risk.ID)
+	if !exists {
+		return nil
+	}
+
+	issue, err := h.JiraClient.GetIssue(jiraKey)
+	if err != nil {
+		return fmt.Errorf("error reading Jira issue %s to rate risk %s: %w", jiraKey, risk.Number, err)
+	}
+	fields, _ := issue["fields"].(map[string]interface{})
+	update := &jira.TicketUpdate{}
+
+	if assessment.Priority != "" {
+		priority, _ := fields["priority"].(map[string]interface{})
+		if name, _ := priority["name"].(string); !strings.EqualFold(name, assessment.Priority) {
+			update.Priority = assessment.Priority
+		}
+	}
+	if field := h.JiraClient.RiskScoreField(); field != "" {
+		if score, ok := fields[field].(float64); !ok || int(score) != assessment.Score {
+			update.Fields = map[string]interface{}{field: assessment.Score}
+		}
+	}
+	if update.Priority == "" && update.Fields == nil {
+		return nil
+	}
+
+	if err := h.JiraClient.UpdateIssue(jiraKey, update); err != nil {
+		return fmt.Errorf("error updating the rating of Jira issue %s: %w", jiraKey, err)
+	}
+	return nil
+}
+
+// matrixSummary describes a rating for Slack, e.g. "Likely × Major = 16 (High)"
+func matrixSummary(assessment riskmatrix.Assessment) string {
+	return fmt.Sprintf("%s × %s = %d (%s)", assessment.Likelihood, assessment.Impact, assessment.Score, assessment.Rating)
+}
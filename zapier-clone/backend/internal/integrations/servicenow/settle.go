@@ -0,0 +1,165 @@
+// backend/internal/integrations/servicenow/settle.go
+package servicenow
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultSettleWindow is how long a new record is held for follow-up updates
+const DefaultSettleWindow = 5 * time.Second
+
+// pendingInsert is a new record waiting for its burst of updates to end
+type pendingInsert struct {
+	payload  WebhookPayload
+	updates  int
+	timer    *time.Timer
+	dispatch func(WebhookPayload)
+}
+
+// Settler coalesces the insert and the quick updates ServiceNow fires for a new
+// record into one insert carrying the final field state, so Jira sees a single
+// create instead of a create followed by redundant updates
+type Settler struct {
+	// Window is measured from the insert; updates after it pass through unchanged.
+	// Zero disables coalescing.
+	Window time.Duration
+
+	mutex   sync.Mutex
+	pending map[string]*pendingInsert
+}
+
+// NewSettler creates a settler with the given window
+func NewSettler(window time.Duration) *Settler {
+	return &Settler{
+		Window:  window,
+		pending: make(map[string]*pendingInsert),
+	}
+}
+
+// NewSettlerFromEnv reads the window from SERVICENOW_SETTLE_WINDOW (e.g. "5s", "0" to disable)
+func NewSettlerFromEnv() *Settler {
+	window := DefaultSettleWindow
+	if value := os.Getenv("SERVICENOW_SETTLE_WINDOW"); value != "" {
+		if value == "0" {
+			window = 0
+		} else if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+			window = parsed
+		} else {
+			slog.Warn("ignoring invalid SERVICENOW_SETTLE_WINDOW", "value", value)
+		}
+	}
+	return NewSettler(window)
+}
+
+// Hold takes a webhook into the settle window and reports whether it did. An
+// insert is held and handed to dispatch when the window closes; updates for a
+// held record are merged into it. A delete drops the held insert and is not held.
+func (s *Settler) Hold(payload WebhookPayload, dispatch func(WebhookPayload)) bool {
+	if s == nil || s.Window <= 0 || payload.ID == "" {
+		return false
+	}
+
+	key := payload.TableName + ":" + payload.ID
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	held, ok := s.pending[key]
+	switch payload.ActionType {
+	case "inserted":
+		if ok {
+			// A repeated insert keeps the original window; its fields are the newer state
+			held.merge(payload.Data)
+			return true
+		}
+		held = &pendingInsert{payload: payload, dispatch: dispatch}
+		held.payload.Data = copyFields(payload.Data)
+		held.timer = time.AfterFunc(s.Window, func() { s.release(key, dispatch) })
+		s.pending[key] = held
+		return true
+	case "updated":
+		if !ok {
+			return false
+		}
+		held.merge(payload.Data)
+		held.updates++
+		return true
+	case "deleted":
+		if ok {
+			held.timer.Stop()
+			delete(s.pending, key)
+			slog.Info("dropped held insert: record was deleted within the settle window", "key", key)
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// release dispatches a held insert once its window has closed
+func (s *Settler) release(key string, dispatch func(WebhookPayload)) {
+	s.mutex.Lock()
+	held, ok := s.pending[key]
+	delete(s.pending, key)
+	s.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+	if held.updates > 0 {
+		slog.Info("coalesced updates into a held insert", "updates", held.updates, "key", key)
+	}
+	dispatch(held.payload)
+}
+
+// Pending returns how many new records are waiting for their window to close
+func (s *Settler) Pending() int {
+	if s == nil {
+		return 0
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.pending)
+}
+
+// Flush dispatches every held insert now instead of waiting for its window,
+// so a shutting down server does not drop them
+func (s *Settler) Flush() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	held := make([]*pendingInsert, 0, len(s.pending))
+	for key, insert := range s.pending {
+		insert.timer.Stop()
+		held = append(held, insert)
+		delete(s.pending, key)
+	}
+	s.mutex.Unlock()
+
+	if len(held) > 0 {
+		slog.Info("flushing held inserts before the settle window closed", "count", len(held))
+	}
+	for _, insert := range held {
+		insert.dispatch(insert.payload)
+	}
+}
+
+// merge applies a later update's fields to the held record
+func (p *pendingInsert) merge(fields map[string]interface{}) {
+	for field, value := range fields {
+		p.payload.Data[field] = value
+	}
+}
+
+// copyFields copies record fields so merging never touches the caller's map
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		copied[field] = value
+	}
+	return copied
+}
@@ -0,0 +1,214 @@
+// backend/internal/integrations/servicenow/tables.go
+package servicenow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+)
+
+// GenericTable is ServiceNow's generic GRC item table. It is registered when
+// the registry is first created, and its built-in field map is used for
+// registered tables that bring none.
+const GenericTable = "sn_grc_item"
+
+// ErrTableNotFound is returned for a table that is not registered
+var ErrTableNotFound = errors.New("table not registered")
+
+// tableName matches ServiceNow table names such as u_vendor_contract
+var tableName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CustomTable is a ServiceNow table synced to Jira besides the built-in ones
+type CustomTable struct {
+	Name string `json:"name"`
+	// Label names the table's records in Slack, e.g. "Vendor Contract"
+	Label string `json:"label,omitempty"`
+	// Project and IssueType are used unless the field map sets them; an empty
+	// project routes like any table without one
+	Project   string `json:"project,omitempty"`
+	IssueType string `json:"issue_type,omitempty"`
+	// Mapping maps the table's records to Jira fields; without one the
+	// field mapping config's map for the table, then sn_grc_item's, is used
+	Mapping   *mapping.TableMap `json:"mapping,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Validate checks the table name and field map
+func (t *CustomTable) Validate() error {
+	if !tableName.MatchString(t.Name) {
+		return fmt.Errorf("invalid table name %q: use a ServiceNow table name such as u_vendor_contract", t.Name)
+	}
+	for _, builtIn := range GRCTables {
+		if t.Name == builtIn {
+			return fmt.Errorf("%s is synced by its own handler and cannot be registered", t.Name)
+		}
+	}
+	if t.Mapping != nil {
+		cfg := &mapping.Config{Tables: map[string]*mapping.TableMap{t.Name: t.Mapping}}
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TableRegistry keeps the tables registered through the API and the Jira
+// issues created for their records. Registrations apply to the next webhook.
+type TableRegistry struct {
+	mutex     sync.RWMutex
+	tables    map[string]*CustomTable
+	links     map[string]string
+	filePath  string
+	linksPath string
+	now       func() time.Time
+}
+
+// NewTableRegistry loads the tables registered in storagePath. A new
+// registry starts with the generic table registered.
+func NewTableRegistry(storagePath string) (*TableRegistry, error) {
+	r := &TableRegistry{
+		tables:    make(map[string]*CustomTable),
+		links:     make(map[string]string),
+		filePath:  filepath.Join(storagePath, "custom_tables.json"),
+		linksPath: filepath.Join(storagePath, "custom_table_links.json"),
+		now:       time.Now,
+	}
+
+	if _, err := os.Stat(r.filePath); err == nil {
+		file, err := os.ReadFile(r.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading custom tables file: %w", err)
+		}
+		if err := json.Unmarshal(file, &r.tables); err != nil {
+			return nil, fmt.Errorf("error unmarshaling custom tables: %w", err)
+		}
+	} else {
+		now := r.now().UTC()
+		r.tables[GenericTable] = &CustomTable{Name: GenericTable, Label: "GRC Item", CreatedAt: now, UpdatedAt: now}
+	}
+
+	if _, err := os.Stat(r.linksPath); err == nil {
+		file, err := os.ReadFile(r.linksPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading custom table links file: %w", err)
+		}
+		if err := json.Unmarshal(file, &r.links); err != nil {
+			return nil, fmt.Errorf("error unmarshaling custom table links: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// List returns the registered tables by name. A nil registry has none.
+func (r *TableRegistry) List() []CustomTable {
+	if r == nil {
+		return nil
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	list := make([]CustomTable, 0, len(r.tables))
+	for _, table := range r.tables {
+		list = append(list, *table)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Get returns a copy of a registered table
+func (r *TableRegistry) Get(name string) (CustomTable, bool) {
+	if r == nil {
+		return CustomTable{}, false
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if table, ok := r.tables[name]; ok {
+		return *table, true
+	}
+	return CustomTable{}, false
+}
+
+// Register adds a table or replaces its settings, keeping its creation time.
+// It reports whether the table was new.
+func (r *TableRegistry) Register(table CustomTable) (CustomTable, bool, error) {
+	if err := table.Validate(); err != nil {
+		return CustomTable{}, false, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	table.UpdatedAt = r.now().UTC()
+	table.CreatedAt = table.UpdatedAt
+	existing, exists := r.tables[table.Name]
+	if exists {
+		table.CreatedAt = existing.CreatedAt
+	}
+	r.tables[table.Name] = &table
+
+	return table, !exists, r.save()
+}
+
+// Unregister removes a table. Its records' links are kept, so issues are not
+// created twice if it is registered again.
+func (r *TableRegistry) Unregister(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.tables[name]; !ok {
+		return ErrTableNotFound
+	}
+	delete(r.tables, name)
+	return r.save()
+}
+
+// IssueKey returns the Jira issue created for a record
+func (r *TableRegistry) IssueKey(table, sysID string) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	key, ok := r.links[table+":"+sysID]
+	return key, ok
+}
+
+// Link records the Jira issue created for a record
+func (r *TableRegistry) Link(table, sysID, jiraKey string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.links[table+":"+sysID] = jiraKey
+	data, err := json.MarshalIndent(r.links, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling custom table links: %w", err)
+	}
+	if err := os.WriteFile(r.linksPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing custom table links: %w", err)
+	}
+	return nil
+}
+
+// save persists the tables to disk; callers hold the mutex
+func (r *TableRegistry) save() error {
+	data, err := json.MarshalIndent(r.tables, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling custom tables: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.filePath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+	if err := os.WriteFile(r.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing custom tables: %w", err)
+	}
+	return nil
+}
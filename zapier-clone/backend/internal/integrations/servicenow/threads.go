@@ -0,0 +1,190 @@
+// backend/internal/integrations/servicenow/threads.go
+package servicenow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// ThreadTracker keeps the Slack message each risk and incident was announced
+// with, so later status changes and comments are posted as replies in its
+// thread instead of as new messages in the channel
+type ThreadTracker struct {
+	SlackClient *slack.Client
+	Threads     *slack.ThreadStore
+	// Assignees mentions new assignees by their Slack user when configured
+	Assignees *AssigneeNotifier
+	// Routes gives the language of each thread's channel; nil writes English
+	Routes *notification.Router
+
+	now func() time.Time
+}
+
+// NewThreadTracker creates a tracker that keeps threads in store
+func NewThreadTracker(slackClient *slack.Client, store *slack.ThreadStore) *ThreadTracker {
+	return &ThreadTracker{
+		SlackClient: slackClient,
+		Threads:     store,
+		now:         time.Now,
+	}
+}
+
+// WithContext returns a copy of the tracker whose Slack calls run under ctx
+func (t *ThreadTracker) WithContext(ctx context.Context) *ThreadTracker {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	copied.SlackClient = t.SlackClient.WithContext(ctx)
+	return &copied
+}
+
+// Start records the message a record was announced with
+func (t *ThreadTracker) Start(table, sysID, number, state, assignedTo, channel, ts string, message slack.Message) {
+	if t == nil || sysID == "" || ts == "" {
+		return
+	}
+
+	err := t.Threads.Save(sysID, slack.Thread{
+		Channel:    channel,
+		TS:         ts,
+		Table:      table,
+		Number:     number,
+		State:      state,
+		AssignedTo: assignedTo,
+		UpdatedAt:  t.now(),
+		Message:    message,
+	})
+	if err != nil {
+		t.SlackClient.Logger().Error("error storing Slack thread", "table", table, "sys_id", sysID, "error", err)
+	}
+}
+
+// HandleRecordUpdate posts what changed in an update webhook as a reply in
+// the record's thread and refreshes the summary on the parent message. It
+// reports false when the record has no thread.
+func (t *ThreadTracker) HandleRecordUpdate(payload WebhookPayload) (bool, error) {
+	if t == nil {
+		return false, nil
+	}
+	thread, exists := t.Threads.Get(payload.ID)
+	if !exists {
+		return false, nil
+	}
+
+	locale := t.Routes.Locale(thread.Channel)
+	lines, assigned := t.changes(&thread, payload.Data, locale)
+	if len(lines) == 0 {
+		return true, nil
+	}
+
+	if _, err := t.SlackClient.PostReply(thread.Channel, thread.TS, slack.Message{Text: strings.Join(lines, "\n")}); err != nil {
+		return true, fmt.Errorf("error posting update to Slack thread of %s: %w", payload.ID, err)
+	}
+
+	thread.Replies++
+	thread.UpdatedAt = t.now()
+	if err := t.Threads.Save(payload.ID, thread); err != nil {
+		return true, err
+	}
+
+	// The reply is what matters; a stale summary on the parent is only logged
+	if err := t.SlackClient.UpdateMessage(thread.Channel, thread.TS, t.parent(thread, locale)); err != nil {
+		t.SlackClient.Logger().Warn("error refreshing Slack thread summary", "sys_id", payload.ID, "error", err)
+	}
+
+	if assigned != "" && thread.Table == "sn_risk_risk" {
+		t.Assignees.NotifyRisk(t.SlackClient, thread.Number, assigned, thread.Message)
+	}
+	return true, nil
+}
+
+// changes describes the state, assignee and journal changes in an update and
+// records the new state and assignee on the thread. assigned is the sys_id of
+// a new assignee.
+func (t *ThreadTracker) changes(thread *slack.Thread, data map[string]interface{}, locale string) (lines []string, assigned string) {
+	if state := referenceValue(data["state"]); state != "" && state != thread.State {
+		if thread.State == "" {
+			lines = append(lines, i18n.T(locale, "thread.state_set", i18n.Value(locale, "state", state)))
+		} else {
+			lines = append(lines, i18n.T(locale, "thread.state_changed", i18n.Value(locale, "state", thread.State), i18n.Value(locale, "state", state)))
+		}
+		thread.State = state
+	}
+
+	if assignee := referenceValue(data["assigned_to"]); assignee != "" && assignee != thread.AssignedTo {
+		assigned = assigneeID(data["assigned_to"])
+		lines = append(lines, i18n.T(locale, "thread.assigned", t.Assignees.Mention(t.SlackClient.Context(), assigned, assignee)))
+		thread.AssignedTo = assignee
+	}
+
+	author := referenceValue(data["sys_updated_by"])
+	if author == "" {
+		author = "ServiceNow"
+	}
+	for _, field := range []string{JournalComments, JournalWorkNotes} {
+		text := strings.TrimSpace(referenceValue(data[field]))
+		if text == "" || IsSyncedComment(text) {
+			continue
+		}
+		key := "thread.commented"
+		if field == JournalWorkNotes {
+			key = "thread.work_note"
+		}
+		lines = append(lines, i18n.T(locale, key, author, strings.ReplaceAll(text, "\n", "\n>")))
+	}
+
+	return lines, assigned
+}
+
+// parent is the original message with a summary of the thread appended
+func (t *ThreadTracker) parent(thread slack.Thread, locale string) slack.Message {
+	message := thread.Message
+	message.Blocks = append([]slack.Block(nil), thread.Message.Blocks...)
+
+	summary := i18n.Plural(locale, "thread.summary", thread.Replies, i18n.DateTime(locale, thread.UpdatedAt))
+	if thread.State != "" {
+		summary = i18n.T(locale, "thread.summary_state", i18n.Value(locale, "state", thread.State), summary)
+	}
+
+	if len(message.Blocks) == 0 {
+		message.Text = strings.TrimSpace(message.Text + "\n" + summary)
+		return message
+	}
+	message.Blocks = append(message.Blocks, slack.Block{
+		Type:     "context",
+		Elements: []interface{}{map[string]interface{}{"type": "mrkdwn", "text": summary}},
+	})
+	return message
+}
+
+// referenceValue reads a field that is either a plain value or a reference
+// with a display value
+func referenceValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case map[string]interface{}:
+		if display := referenceValue(v["display_value"]); display != "" {
+			return display
+		}
+		return referenceValue(v["value"])
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
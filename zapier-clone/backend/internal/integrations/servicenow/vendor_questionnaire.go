@@ -0,0 +1,173 @@
+// backend/internal/integrations/servicenow/vendor_questionnaire.go
+package servicenow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// VendorQuestion is one question of the vendor risk questionnaire
+type VendorQuestion struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	// Options make the question a choice; without them it takes free text
+	Options  []string `json:"options,omitempty"`
+	Optional bool     `json:"optional,omitempty"`
+}
+
+// QuestionnaireAnswer is a submitted answer to a VendorQuestion
+type QuestionnaireAnswer struct {
+	QuestionID string `json:"question_id"`
+	Question   string `json:"question"`
+	Answer     string `json:"answer"`
+}
+
+// DefaultVendorQuestionnaire is asked unless vendor_questionnaire.json in the
+// data directory replaces it
+var DefaultVendorQuestionnaire = []VendorQuestion{
+	{ID: "certifications", Text: "Which security certifications does the vendor hold (e.g. SOC 2, ISO 27001)?"},
+	{ID: "customer_data", Text: "Does the vendor store or process our customer data?", Options: []string{"Yes", "No"}},
+	{ID: "security_incidents", Text: "Has the vendor had a security incident in the last 12 months?", Options: []string{"Yes", "No", "Unknown"}},
+	{ID: "subprocessors", Text: "Does the vendor use subprocessors?", Options: []string{"Yes", "No", "Unknown"}},
+	{ID: "continuity_plan", Text: "Does the vendor have a tested business continuity plan?", Options: []string{"Yes", "No", "Unknown"}},
+	{ID: "notes", Text: "Additional notes", Optional: true},
+}
+
+// questionnaireDoneItem closes the Jira checklist of a completed questionnaire
+const questionnaireDoneItem = "Vendor questionnaire completed"
+
+// LoadVendorQuestionnaire reads vendor_questionnaire.json from dataDir, or
+// returns the default questionnaire when there is none
+func LoadVendorQuestionnaire(dataDir string) ([]VendorQuestion, error) {
+	path := filepath.Join(dataDir, "vendor_questionnaire.json")
+	file, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultVendorQuestionnaire, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading vendor questionnaire: %w", err)
+	}
+
+	var questions []VendorQuestion
+	if err := json.Unmarshal(file, &questions); err != nil {
+		return nil, fmt.Errorf("error unmarshaling vendor questionnaire: %w", err)
+	}
+	seen := make(map[string]bool, len(questions))
+	for i, question := range questions {
+		if question.ID == "" || question.Text == "" {
+			return nil, fmt.Errorf("vendor questionnaire question %d: id and text are required", i+1)
+		}
+		if seen[question.ID] {
+			return nil, fmt.Errorf("vendor questionnaire: duplicate question id %q", question.ID)
+		}
+		seen[question.ID] = true
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("vendor questionnaire %s has no questions", path)
+	}
+	return questions, nil
+}
+
+// HandleQuestionnaireResponse stores a vendor's questionnaire answers on the
+// vendor risk, as an attachment and a work note, ticks the questionnaire items
+// on the checklist of its Jira issue, and replies in the announcement's thread
+func (h *VendorRiskHandler) HandleQuestionnaireResponse(riskID, channelID, threadTS, userID string, answers []QuestionnaireAnswer) error {
+	record, err := h.ServiceNowClient.GetRecord("sn_vendor_risk", riskID)
+	if err != nil {
+		return fmt.Errorf("error getting vendor risk details: %w", err)
+	}
+	number := referenceValue(record["number"])
+	vendor := referenceValue(record["vendor_name"])
+	submitted := time.Now().UTC()
+
+	var failures []string
+
+	// The full answers go in an attachment; the work note summarizes them
+	document := questionnaireDocument(number, vendor, userID, submitted, answers)
+	fileName := fmt.Sprintf("vendor-questionnaire-%s-%s.txt", number, submitted.Format("20060102-150405"))
+	if _, err := h.ServiceNowClient.UploadAttachment("sn_vendor_risk", riskID, fileName, "text/plain", []byte(document)); err != nil {
+		failures = append(failures, err.Error())
+		fileName = ""
+	}
+
+	note := fmt.Sprintf("Vendor questionnaire completed in Slack by %s.\n\n%s", userID, questionnaireAnswers(answers))
+	key := JournalKey("vendor_questionnaire", riskID, userID, submitted.Format(time.RFC3339))
+	if err := NewJournalWriter(h.ServiceNowClient).Write("sn_vendor_risk", riskID, JournalWorkNotes, key, note); err != nil {
+		failures = append(failures, err.Error())
+	}
+
+	// The vendor risk's Jira issue, if it has one, gets the answered questions ticked
+	jiraKey := referenceValue(record["jira_ticket"])
+	if jiraKey != "" && h.JiraClient != nil {
+		items := make([]jira.ChecklistItem, 0, len(answers)+1)
+		for _, answer := range answers {
+			items = append(items, jira.ChecklistItem{Text: answer.Question, Done: answer.Answer != ""})
+		}
+		items = append(items, jira.ChecklistItem{Text: questionnaireDoneItem, Done: true})
+		if err := h.JiraClient.UpdateChecklist(jiraKey, items); err != nil {
+			failures = append(failures, err.Error())
+			jiraKey = ""
+		}
+	} else {
+		jiraKey = ""
+	}
+
+	text := fmt.Sprintf("📝 <@%s> completed the vendor questionnaire for *%s* (%d answers).", userID, vendor, countAnswered(answers))
+	if fileName != "" {
+		text += fmt.Sprintf("\n• Attached to %s in ServiceNow as `%s`", number, fileName)
+	}
+	if jiraKey != "" {
+		text += fmt.Sprintf("\n• Checklist updated on Jira issue %s", jiraKey)
+	}
+	if len(failures) > 0 {
+		text += "\n⚠️ Some answers could not be stored; the integration will log the details."
+	}
+	if _, err := h.SlackClient.PostReply(channelID, threadTS, slack.Message{Text: text}); err != nil {
+		failures = append(failures, fmt.Sprintf("error posting questionnaire reply to Slack thread: %v", err))
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("error storing vendor questionnaire: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// questionnaireDocument renders submitted answers as the attachment's text
+func questionnaireDocument(number, vendor, userID string, submitted time.Time, answers []QuestionnaireAnswer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Vendor Risk Questionnaire\n\n")
+	fmt.Fprintf(&b, "Vendor risk: %s\nVendor: %s\nSubmitted by: %s (Slack)\nSubmitted at: %s\n\n", number, vendor, userID, submitted.Format(time.RFC3339))
+	b.WriteString(questionnaireAnswers(answers))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// questionnaireAnswers lists answers as "Q: / A:" pairs
+func questionnaireAnswers(answers []QuestionnaireAnswer) string {
+	blocks := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		value := answer.Answer
+		if value == "" {
+			value = "(no answer)"
+		}
+		blocks = append(blocks, fmt.Sprintf("Q: %s\nA: %s", answer.Question, value))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func countAnswered(answers []QuestionnaireAnswer) int {
+	n := 0
+	for _, answer := range answers {
+		if answer.Answer != "" {
+			n++
+		}
+	}
+	return n
+}
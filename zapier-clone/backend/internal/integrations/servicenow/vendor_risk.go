@@ -2,12 +2,16 @@
 package servicenow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"log/slog"
 	"time"
 
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
 )
 
 // VendorRisk represents a vendor risk in ServiceNow GRC
@@ -32,87 +36,121 @@ type VendorRisk struct {
 type VendorRiskHandler struct {
 	ServiceNowClient *Client
 	SlackClient      *slack.Client
+	// Routes picks the channels new vendor risks are announced in
+	Routes *notification.Router
+	// JiraClient updates the checklist of a vendor risk's Jira issue when set
+	JiraClient *jira.Client
+	// Questionnaire is asked by the Request Report button
+	Questionnaire []VendorQuestion
 }
 
 // NewVendorRiskHandler creates a new vendor risk handler
 func NewVendorRiskHandler(serviceNowClient *Client, slackClient *slack.Client) *VendorRiskHandler {
+	questionnaire, err := LoadVendorQuestionnaire(serviceNowClient.DataDir)
+	if err != nil {
+		slog.Warn("error loading vendor questionnaire; using the default", "error", err)
+		questionnaire = DefaultVendorQuestionnaire
+	}
+
 	return &VendorRiskHandler{
 		ServiceNowClient: serviceNowClient,
 		SlackClient:      slackClient,
+		Questionnaire:    questionnaire,
 	}
 }
 
+// WithContext returns a copy of the handler whose API calls run under ctx
+func (h *VendorRiskHandler) WithContext(ctx context.Context) *VendorRiskHandler {
+	copied := *h
+	copied.ServiceNowClient = h.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = h.SlackClient.WithContext(ctx)
+	if h.JiraClient != nil {
+		copied.JiraClient = h.JiraClient.WithContext(ctx)
+	}
+	return &copied
+}
+
 // HandleNewVendorRisk processes a new vendor risk and notifies Slack
 func (h *VendorRiskHandler) HandleNewVendorRisk(risk VendorRisk) (string, error) {
-	// Create a Slack message for the vendor risk
-	message := slack.Message{
-		Blocks: []slack.Block{
-			{
-				Type: "header",
-				Text: slack.NewTextObject("plain_text", fmt.Sprintf("🚨 Vendor Issue: %s", risk.ShortDesc), true),
-			},
-			{
-				Type: "section",
-				Fields: []*slack.TextObject{
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Risk ID:*\n%s", risk.Number), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Vendor:*\n%s", risk.VendorName), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Severity:*\n%s", risk.Severity), false),
-					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Due Date:*\n%s", risk.DueDate.Format("Jan 2, 2006")), false),
+	// Create the Slack message for the vendor risk in a channel's language
+	build := func(locale string) slack.Message {
+		return slack.Message{
+			Blocks: []slack.Block{
+				{
+					Type: "header",
+					Text: slack.NewTextObject("plain_text", i18n.T(locale, "vendor_risk.header", risk.ShortDesc), true),
 				},
-			},
-			{
-				Type: "section",
-				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Description:*\n%s", risk.Description), false),
-			},
-			{
-				Type: "actions",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Request Compliance Report",
-							"emoji": true,
-						},
-						"value":     fmt.Sprintf("request_report_%s", risk.ID),
-						"action_id": "request_compliance_report",
+				{
+					Type: "section",
+					Fields: []*slack.TextObject{
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "risk.field.number", risk.Number), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "vendor_risk.field.vendor", risk.VendorName), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.severity", i18n.Value(locale, "severity", risk.Severity)), false),
+						slack.NewTextObject("mrkdwn", i18n.T(locale, "field.due_date", i18n.Date(locale, risk.DueDate)), false),
 					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "Update Status",
-							"emoji": true,
+				},
+				{
+					Type: "section",
+					Text: slack.NewTextObject("mrkdwn", i18n.T(locale, "field.description", risk.Description), false),
+				},
+				{
+					Type: "actions",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "vendor_risk.button.report"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("request_report_%s", risk.ID),
+							"action_id": "request_compliance_report",
 						},
-						"value":     fmt.Sprintf("update_vendor_%s", risk.ID),
-						"action_id": "update_vendor_status",
-					},
-					map[string]interface{}{
-						"type": "button",
-						"text": map[string]interface{}{
-							"type":  "plain_text",
-							"text":  "View in ServiceNow",
-							"emoji": true,
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "vendor_risk.button.status"),
+								"emoji": true,
+							},
+							"value":     fmt.Sprintf("update_vendor_%s", risk.ID),
+							"action_id": "update_vendor_status",
+						},
+						map[string]interface{}{
+							"type": "button",
+							"text": map[string]interface{}{
+								"type":  "plain_text",
+								"text":  i18n.T(locale, "button.view_servicenow"),
+								"emoji": true,
+							},
+							"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_vendor_risk.do?sys_id=%s", h.ServiceNowClient.BaseURL, risk.ID),
+							"action_id": "view_vendor_risk",
 						},
-						"url":       fmt.Sprintf("%s/nav_to.do?uri=sn_vendor_risk.do?sys_id=%s", h.ServiceNowClient.BaseURL, risk.ID),
-						"action_id": "view_vendor_risk",
 					},
 				},
-			},
-			{
-				Type: "context",
-				Elements: []interface{}{
-					map[string]interface{}{
-						"type": "mrkdwn",
-						"text": "📍 Action Needed: Request updated compliance report.",
+				{
+					Type: "context",
+					Elements: []interface{}{
+						map[string]interface{}{
+							"type": "mrkdwn",
+							"text": i18n.T(locale, "vendor_risk.context"),
+						},
 					},
 				},
 			},
-		},
+		}
 	}
 
-	// Post the message to the vendor-risk channel
-	ts, err := h.SlackClient.PostMessage(slack.ChannelMapping["vendor-risk"], message)
+	// Post the message to the channels routed for the vendor risk, vendor-risk by default
+	item := notification.Item{
+		Type:     "vendor_risk",
+		Severity: risk.Severity,
+		Category: risk.Category,
+		Number:   risk.Number,
+		Title:    risk.ShortDesc,
+		URL:      fmt.Sprintf("%s/nav_to.do?uri=sn_vendor_risk.do?sys_id=%s", h.ServiceNowClient.BaseURL, risk.ID),
+	}
+	_, ts, err := announce(h.SlackClient, h.Routes, item, build)
 	if err != nil {
 		return "", fmt.Errorf("error posting vendor risk message to Slack: %w", err)
 	}
@@ -188,29 +226,18 @@ func (h *VendorRiskHandler) HandleVendorStatusUpdate(riskID, channelID, threadTS
 	return nil
 }
 
-// ProcessVendorCommand handles slash commands for vendor risk management
-func (h *VendorRiskHandler) ProcessVendorCommand(command *slack.Command) (string, error) {
-	// Handle different vendor risk commands
-	switch {
-	case command.Command == "/update-vendor":
-		// Format: /update-vendor RISK_ID STATUS Notes about the update
-		parts := strings.SplitN(command.Text, " ", 3)
-		if len(parts) < 3 {
-			return "Invalid command format. Usage: /update-vendor RISK_ID STATUS Notes about the update", nil
-		}
-
-		riskID := parts[0]
-		status := parts[1]
-		notes := parts[2]
-
-		err := h.HandleVendorStatusUpdate(riskID, command.ChannelID, "", command.UserID, status, notes)
-		if err != nil {
-			return fmt.Sprintf("Error updating vendor status: %s", err), nil
-		}
-
-		return "Vendor status updated successfully!", nil
-
-	default:
-		return "Unknown command", nil
-	}
+// RegisterCommands adds the vendor risk slash commands to the router
+func (h *VendorRiskHandler) RegisterCommands(router *slack.CommandRouter) error {
+	return router.Register(slack.SlashCommand{
+		Name:        "/update-vendor",
+		Usage:       "RISK_ID STATUS NOTES",
+		Description: "Update the status of a vendor risk",
+		Args:        3,
+		Handler: func(command *slack.Command, args []string) (string, error) {
+			if err := h.HandleVendorStatusUpdate(args[0], command.ChannelID, "", command.UserID, args[1], args[2]); err != nil {
+				return "", fmt.Errorf("error updating vendor status: %w", err)
+			}
+			return "Vendor status updated successfully!", nil
+		},
+	})
 }
@@ -0,0 +1,579 @@
+// backend/internal/integrations/servicenow/war_room.go
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// War room states
+const (
+	// WarRoomOpening means the channel is being created
+	WarRoomOpening  = "opening"
+	WarRoomOpen     = "open"
+	WarRoomArchived = "archived"
+)
+
+// maxWarRoomNameTries is how many numbered names are tried when a channel name is taken
+const maxWarRoomNameTries = 5
+
+// channelNameInvalid matches what Slack does not accept in a channel name
+var channelNameInvalid = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// WarRoom is the Slack channel opened for one major incident
+type WarRoom struct {
+	// IncidentID is the incident's sys_id
+	IncidentID  string `json:"incident_id"`
+	Number      string `json:"number"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	URL         string `json:"url"`
+	// SummaryTS is the pinned status summary, edited as the incident changes
+	SummaryTS string   `json:"summary_ts,omitempty"`
+	Members   []string `json:"members"`
+	JiraKey   string   `json:"jira_key,omitempty"`
+	Status    string   `json:"status"`
+	// Title, Priority, State and AssignedTo are the incident as last seen, for the summary
+	Title      string     `json:"title"`
+	Priority   string     `json:"priority"`
+	Severity   string     `json:"severity,omitempty"`
+	State      string     `json:"state,omitempty"`
+	AssignedTo string     `json:"assigned_to,omitempty"`
+	OpenedAt   time.Time  `json:"opened_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
+
+// WarRooms opens a Slack channel for each major incident, invites the people
+// who need to be in it, pins a status summary and archives the channel once
+// the incident is resolved. The channel link is added to the incident's work
+// notes and its Jira epic.
+type WarRooms struct {
+	ServiceNowClient    *Client
+	SlackClient         *slack.Client
+	JiraClient          *jira.Client
+	IncidentJiraMapping *jira.IncidentJiraMapping
+	JournalWriter       *JournalWriter
+	// Escalations adds the chain escalating an incident to its war room
+	Escalations *Escalator
+	// Routes gives the language of war room messages; nil writes English
+	Routes *notification.Router
+	// Enabled turns war rooms on
+	Enabled bool
+	// Priorities are the incident priorities that get a war room, such as "1"
+	Priorities []string
+	// Prefix starts every channel name
+	Prefix string
+	// Private creates private channels instead of public ones
+	Private bool
+	// Stakeholders are Slack user IDs or emails invited by incident category;
+	// those under "default" are invited to every war room
+	Stakeholders map[string][]string
+
+	mutex    sync.Mutex
+	rooms    map[string]*WarRoom
+	filePath string
+	now      func() time.Time
+}
+
+// NewWarRooms creates a war room manager that keeps its rooms in storagePath.
+// It opens nothing until Enabled is set, usually by ConfigureFromEnv.
+func NewWarRooms(storagePath string, serviceNowClient *Client, slackClient *slack.Client, jiraClient *jira.Client, incidents *jira.IncidentJiraMapping) (*WarRooms, error) {
+	w := &WarRooms{
+		ServiceNowClient:    serviceNowClient,
+		SlackClient:         slackClient,
+		JiraClient:          jiraClient,
+		IncidentJiraMapping: incidents,
+		JournalWriter:       NewJournalWriter(serviceNowClient),
+		Priorities:          []string{"1"},
+		Prefix:              "inc",
+		Stakeholders:        make(map[string][]string),
+		rooms:               make(map[string]*WarRoom),
+		filePath:            filepath.Join(storagePath, "war_rooms.json"),
+		now:                 time.Now,
+	}
+
+	if _, err := os.Stat(w.filePath); err == nil {
+		file, err := os.ReadFile(w.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading war room file: %w", err)
+		}
+		if err := json.Unmarshal(file, &w.rooms); err != nil {
+			return nil, fmt.Errorf("error unmarshaling war rooms: %w", err)
+		}
+	}
+	// A room left opening by a restart never got its channel
+	for id, room := range w.rooms {
+		if room.Status == WarRoomOpening {
+			delete(w.rooms, id)
+		}
+	}
+
+	return w, nil
+}
+
+// ConfigureFromEnv turns war rooms on when INCIDENT_WAR_ROOMS is "true" and reads:
+//
+//	INCIDENT_WAR_ROOM_PRIORITIES    priorities that get a war room, "1|2"; "1" by default
+//	INCIDENT_WAR_ROOM_PREFIX        channel name prefix, "inc" by default
+//	INCIDENT_WAR_ROOM_PRIVATE       "true" creates private channels
+//	INCIDENT_WAR_ROOM_STAKEHOLDERS  "default=U123|oncall@example.com,network=U456"
+//
+// Stakeholders are keyed by incident category; Slack user IDs and emails can be mixed.
+func (w *WarRooms) ConfigureFromEnv() {
+	w.Enabled = strings.EqualFold(os.Getenv("INCIDENT_WAR_ROOMS"), "true")
+	w.Private = strings.EqualFold(os.Getenv("INCIDENT_WAR_ROOM_PRIVATE"), "true")
+	if prefix := channelSlug(os.Getenv("INCIDENT_WAR_ROOM_PREFIX")); prefix != "" {
+		w.Prefix = prefix
+	}
+
+	if value := os.Getenv("INCIDENT_WAR_ROOM_PRIORITIES"); value != "" {
+		var priorities []string
+		for _, priority := range strings.Split(value, "|") {
+			if priority = priorityLevel(priority); priority != "" {
+				priorities = append(priorities, priority)
+			}
+		}
+		w.Priorities = priorities
+	}
+
+	for _, entry := range strings.Split(os.Getenv("INCIDENT_WAR_ROOM_STAKEHOLDERS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		category, users, found := strings.Cut(entry, "=")
+		if !found {
+			category, users = "default", entry
+		}
+		category = strings.ToLower(strings.TrimSpace(category))
+		for _, user := range strings.Split(users, "|") {
+			if user = strings.TrimSpace(user); user != "" {
+				w.Stakeholders[category] = append(w.Stakeholders[category], user)
+			}
+		}
+	}
+}
+
+// IsMajor reports whether an incident gets a war room: it was accepted as a
+// major incident or has one of the configured priorities
+func (w *WarRooms) IsMajor(incident Incident) bool {
+	if w == nil || !w.Enabled {
+		return false
+	}
+	if strings.EqualFold(incident.MajorIncidentState, "accepted") {
+		return true
+	}
+	level := priorityLevel(incident.Priority)
+	for _, priority := range w.Priorities {
+		if level != "" && level == priority {
+			return true
+		}
+	}
+	return false
+}
+
+// priorityLevel reduces "1 - Critical", "P1" and "1" alike to "1"
+func priorityLevel(priority string) string {
+	priority = strings.ToLower(strings.TrimSpace(priority))
+	priority = strings.TrimPrefix(priority, "p")
+	level, _, _ := strings.Cut(priority, " ")
+	return strings.TrimSpace(level)
+}
+
+// Sync keeps an incident's war room in step with it: it opens one when the
+// incident became major, archives it once the incident is resolved or closed,
+// and refreshes the pinned summary otherwise
+func (w *WarRooms) Sync(ctx context.Context, incident Incident, identities Identities) error {
+	if w == nil || !w.Enabled || incident.ID == "" {
+		return nil
+	}
+
+	room, ok := w.Get(incident.ID)
+	switch {
+	case !ok && !incidentClosed(incident.State) && w.IsMajor(incident):
+		_, err := w.Open(ctx, incident, identities)
+		return err
+	case !ok || room.Status != WarRoomOpen:
+		return nil
+	case incidentClosed(incident.State):
+		return w.Archive(ctx, incident.ID, incident.ResolutionNotes)
+	}
+	return w.Update(ctx, incident)
+}
+
+// Open creates the war room for a major incident: a channel named after the
+// incident, its assignee, escalation chain and stakeholders invited, and a
+// pinned summary. An incident that already has a war room gets it back.
+func (w *WarRooms) Open(ctx context.Context, incident Incident, identities Identities) (*WarRoom, error) {
+	if w == nil || !w.Enabled || incident.ID == "" {
+		return nil, nil
+	}
+
+	w.mutex.Lock()
+	if existing, ok := w.rooms[incident.ID]; ok {
+		copied := *existing
+		w.mutex.Unlock()
+		return &copied, nil
+	}
+	room := &WarRoom{
+		IncidentID: incident.ID,
+		Number:     incident.Number,
+		Status:     WarRoomOpening,
+		Members:    []string{},
+		OpenedAt:   w.now(),
+	}
+	room.merge(incident)
+	w.rooms[incident.ID] = room
+	w.mutex.Unlock()
+
+	opened, err := w.open(ctx, *room, incident, identities)
+
+	w.mutex.Lock()
+	if err != nil {
+		delete(w.rooms, incident.ID)
+	} else {
+		*room = opened
+	}
+	w.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	w.save()
+	return &opened, nil
+}
+
+// open makes the Slack and tracking calls for Open. Only creating the channel
+// has to succeed; the rest is logged when it fails.
+func (w *WarRooms) open(ctx context.Context, room WarRoom, incident Incident, identities Identities) (WarRoom, error) {
+	slackClient := w.SlackClient.WithContext(ctx)
+	logger := w.ServiceNowClient.Logger()
+
+	channel, err := w.createChannel(slackClient, incident)
+	if err != nil {
+		return room, fmt.Errorf("error creating war room channel: %w", err)
+	}
+	room.ChannelID = channel.ID
+	room.ChannelName = channel.Name
+	room.URL = slack.ChannelURL(channel.ID)
+	room.Status = WarRoomOpen
+	if jiraKey, linked := w.IncidentJiraMapping.GetJiraKeyFromIncidentID(incident.ID); linked {
+		room.JiraKey = jiraKey
+	}
+
+	room.Members = w.members(ctx, slackClient, incident, identities)
+	if err := slackClient.InviteToChannel(channel.ID, room.Members); err != nil {
+		logger.Error("error inviting people to war room", "sys_id", incident.ID, "channel", channel.ID, "error", err)
+	}
+
+	topic := fmt.Sprintf("%s: %s", incident.Number, incident.ShortDesc)
+	if err := slackClient.SetChannelTopic(channel.ID, topic); err != nil {
+		logger.Error("error setting war room topic", "sys_id", incident.ID, "channel", channel.ID, "error", err)
+	}
+
+	ts, err := slackClient.PostMessage(channel.ID, w.summary(room))
+	if err != nil {
+		logger.Error("error posting war room summary", "sys_id", incident.ID, "channel", channel.ID, "error", err)
+	} else {
+		room.SummaryTS = ts
+		if err := slackClient.PinMessage(channel.ID, ts); err != nil {
+			logger.Error("error pinning war room summary", "sys_id", incident.ID, "channel", channel.ID, "error", err)
+		}
+	}
+
+	// Point everyone working the incident elsewhere at the channel
+	note := fmt.Sprintf("Major incident war room opened in Slack: #%s (%s)", room.ChannelName, room.URL)
+	if err := w.JournalWriter.Write("sn_si_incident", incident.ID, JournalWorkNotes, JournalKey("incident-war-room", incident.ID, room.ChannelID), note); err != nil {
+		logger.Error("error adding war room work note", "sys_id", incident.ID, "error", err)
+	}
+	if room.JiraKey != "" {
+		comment := fmt.Sprintf("Major incident war room: #%s\n%s", room.ChannelName, room.URL)
+		if err := w.JiraClient.WithContext(ctx).AddComment(room.JiraKey, comment); err != nil {
+			logger.Error("error adding war room link to Jira", "issue", room.JiraKey, "error", err)
+		}
+	}
+
+	logger.Info("opened war room for major incident", "sys_id", incident.ID, "channel", room.ChannelName, "members", len(room.Members))
+	return room, nil
+}
+
+// createChannel creates the incident's channel, numbering the name when it is
+// taken, as it is when an incident number is reused across instances
+func (w *WarRooms) createChannel(slackClient *slack.Client, incident Incident) (*slack.Channel, error) {
+	name := w.channelName(incident)
+	for try := 1; ; try++ {
+		candidate := name
+		if try > 1 {
+			candidate = fmt.Sprintf("%s-%d", name, try)
+		}
+		channel, err := slackClient.CreateChannel(candidate, w.Private)
+		if errors.Is(err, slack.ErrChannelNameTaken) && try < maxWarRoomNameTries {
+			continue
+		}
+		return channel, err
+	}
+}
+
+// channelName is the prefix, the incident number and as much of its short
+// description as fits in Slack's 80 characters, leaving room for a number
+func (w *WarRooms) channelName(incident Incident) string {
+	name := channelSlug(w.Prefix + "-" + incident.Number)
+	if title := channelSlug(incident.ShortDesc); title != "" {
+		name += "-" + title
+	}
+	if len(name) > 77 {
+		name = strings.TrimRight(name[:77], "-_")
+	}
+	return name
+}
+
+// channelSlug lowercases text and swaps what a channel name cannot hold for dashes
+func channelSlug(text string) string {
+	return strings.Trim(channelNameInvalid.ReplaceAllString(strings.ToLower(strings.TrimSpace(text)), "-"), "-_")
+}
+
+// members returns the Slack users to invite: the assignee, the escalation
+// chain and the stakeholders for the incident's category, each once
+func (w *WarRooms) members(ctx context.Context, slackClient *slack.Client, incident Incident, identities Identities) []string {
+	var candidates []string
+	if incident.AssignedTo != "" {
+		if identities != nil {
+			candidates = append(candidates, identities.SlackUserID(ctx, incident.AssignedTo))
+		} else if strings.Contains(incident.AssignedTo, "@") {
+			candidates = append(candidates, incident.AssignedTo)
+		}
+	}
+	candidates = append(candidates, w.Escalations.Chain(incident.ID)...)
+	candidates = append(candidates, w.Stakeholders["default"]...)
+	if category := strings.ToLower(strings.TrimSpace(incident.Category)); category != "default" {
+		candidates = append(candidates, w.Stakeholders[category]...)
+	}
+
+	members := []string{}
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		user := strings.TrimPrefix(strings.TrimSuffix(strings.TrimSpace(candidate), ">"), "<@")
+		if strings.Contains(user, "@") {
+			found, err := slackClient.LookupUserByEmail(user)
+			if err != nil || found == nil {
+				logging.FromContext(ctx).Info("no Slack user for war room stakeholder", "user", user)
+				continue
+			}
+			user = found.ID
+		}
+		if user == "" || seen[user] {
+			continue
+		}
+		seen[user] = true
+		members = append(members, user)
+	}
+	return members
+}
+
+// Update refreshes the pinned summary of an incident's open war room
+func (w *WarRooms) Update(ctx context.Context, incident Incident) error {
+	if w == nil {
+		return nil
+	}
+
+	w.mutex.Lock()
+	room, ok := w.rooms[incident.ID]
+	if !ok || room.Status != WarRoomOpen {
+		w.mutex.Unlock()
+		return nil
+	}
+	room.merge(incident)
+	if room.JiraKey == "" {
+		room.JiraKey, _ = w.IncidentJiraMapping.GetJiraKeyFromIncidentID(incident.ID)
+	}
+	copied := *room
+	w.mutex.Unlock()
+	w.save()
+
+	if copied.SummaryTS == "" {
+		return nil
+	}
+	if err := w.SlackClient.WithContext(ctx).UpdateMessage(copied.ChannelID, copied.SummaryTS, w.summary(copied)); err != nil {
+		return fmt.Errorf("error updating war room summary: %w", err)
+	}
+	return nil
+}
+
+// Archive closes an incident's war room once it is resolved: a closing
+// message with the resolution notes, a final summary and the channel archived
+func (w *WarRooms) Archive(ctx context.Context, sysID, resolutionNotes string) error {
+	if w == nil {
+		return nil
+	}
+
+	w.mutex.Lock()
+	room, ok := w.rooms[sysID]
+	if !ok || room.Status != WarRoomOpen {
+		w.mutex.Unlock()
+		return nil
+	}
+	now := w.now()
+	room.Status = WarRoomArchived
+	room.State = "resolved"
+	room.ArchivedAt = &now
+	copied := *room
+	w.mutex.Unlock()
+	w.save()
+
+	slackClient := w.SlackClient.WithContext(ctx)
+	logger := w.ServiceNowClient.Logger()
+
+	locale := w.Routes.Locale(copied.ChannelID)
+	text := i18n.T(locale, "war_room.closing", copied.Number)
+	if resolutionNotes != "" {
+		text += "\n" + i18n.T(locale, "war_room.resolution", resolutionNotes)
+	}
+	if _, err := slackClient.PostMessage(copied.ChannelID, slack.Message{Text: text}); err != nil {
+		logger.Error("error posting war room closing message", "sys_id", sysID, "channel", copied.ChannelID, "error", err)
+	}
+	if copied.SummaryTS != "" {
+		if err := slackClient.UpdateMessage(copied.ChannelID, copied.SummaryTS, w.summary(copied)); err != nil {
+			logger.Error("error updating war room summary", "sys_id", sysID, "channel", copied.ChannelID, "error", err)
+		}
+	}
+	if err := slackClient.ArchiveChannel(copied.ChannelID); err != nil {
+		return fmt.Errorf("error archiving war room channel: %w", err)
+	}
+
+	note := fmt.Sprintf("Major incident war room #%s archived after resolution", copied.ChannelName)
+	if err := w.JournalWriter.Write("sn_si_incident", sysID, JournalWorkNotes, JournalKey("incident-war-room-archived", sysID, copied.ChannelID), note); err != nil {
+		logger.Error("error adding war room work note", "sys_id", sysID, "error", err)
+	}
+	logger.Info("archived war room for resolved incident", "sys_id", sysID, "channel", copied.ChannelName)
+	return nil
+}
+
+// merge takes the fields an update carries into the room's copy of the incident
+func (room *WarRoom) merge(incident Incident) {
+	if incident.ShortDesc != "" {
+		room.Title = incident.ShortDesc
+	}
+	if incident.Priority != "" {
+		room.Priority = incident.Priority
+	}
+	if incident.Severity != "" {
+		room.Severity = incident.Severity
+	}
+	if incident.State != "" {
+		room.State = incident.State
+	}
+	if incident.AssignedTo != "" {
+		room.AssignedTo = incident.AssignedTo
+	}
+}
+
+// summary is the pinned status message of a war room, in the language of its channel
+func (w *WarRooms) summary(room WarRoom) slack.Message {
+	locale := w.Routes.Locale(room.ChannelID)
+	status := i18n.T(locale, "war_room.status.active")
+	if room.Status == WarRoomArchived {
+		status = i18n.T(locale, "war_room.status.resolved")
+	}
+	assignee := room.AssignedTo
+	if assignee == "" {
+		assignee = i18n.T(locale, "war_room.unassigned")
+	}
+	state := room.State
+	if state == "" {
+		state = "new"
+	}
+
+	links := fmt.Sprintf("<%s/nav_to.do?uri=sn_si_incident.do?sys_id=%s|%s>", w.ServiceNowClient.BaseURL, room.IncidentID, i18n.T(locale, "button.view_servicenow"))
+	if room.JiraKey != "" {
+		links += fmt.Sprintf(" • <%s/browse/%s|%s>", strings.TrimRight(w.JiraClient.BaseURL, "/"), room.JiraKey, i18n.T(locale, "war_room.jira_link", room.JiraKey))
+	}
+
+	text := i18n.T(locale, "war_room.text", status, room.Number, room.Title)
+	return slack.Message{
+		Text: text,
+		Blocks: []slack.Block{
+			{
+				Type: "header",
+				Text: slack.NewTextObject("plain_text", i18n.T(locale, "war_room.header", room.Number), true),
+			},
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*%s*", room.Title), false),
+				Fields: []*slack.TextObject{
+					slack.NewTextObject("mrkdwn", i18n.T(locale, "field.status", status), false),
+					slack.NewTextObject("mrkdwn", i18n.T(locale, "war_room.field.state", i18n.Value(locale, "state", state)), false),
+					slack.NewTextObject("mrkdwn", i18n.T(locale, "war_room.field.priority", room.Priority), false),
+					slack.NewTextObject("mrkdwn", i18n.T(locale, "field.assigned_to", assignee), false),
+				},
+			},
+			{
+				Type: "context",
+				Elements: []interface{}{
+					map[string]interface{}{
+						"type": "mrkdwn",
+						"text": i18n.T(locale, "war_room.opened", links, i18n.DateTime(locale, room.OpenedAt.UTC())),
+					},
+				},
+			},
+		},
+	}
+}
+
+// Get returns the war room of an incident
+func (w *WarRooms) Get(sysID string) (WarRoom, bool) {
+	if w == nil {
+		return WarRoom{}, false
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	room, ok := w.rooms[sysID]
+	if !ok {
+		return WarRoom{}, false
+	}
+	return *room, true
+}
+
+// List returns war rooms, newest first, optionally only those with a status
+func (w *WarRooms) List(status string) []WarRoom {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	list := []WarRoom{}
+	for _, room := range w.rooms {
+		if status == "" || room.Status == status {
+			list = append(list, *room)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].OpenedAt.After(list[j].OpenedAt) })
+	return list
+}
+
+// save persists the war rooms to disk
+func (w *WarRooms) save() {
+	w.mutex.Lock()
+	data, err := json.MarshalIndent(w.rooms, "", "  ")
+	w.mutex.Unlock()
+	if err != nil {
+		slog.Error("error marshaling war rooms", "error", err)
+		return
+	}
+	if err := os.WriteFile(w.filePath, data, 0644); err != nil {
+		slog.Error("error saving war rooms", "error", err)
+	}
+}
@@ -0,0 +1,319 @@
+// backend/internal/integrations/slack/channels.go
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Channel access problems reported by CheckChannelAccess
+const (
+	ChannelAccessNotFound        = "channel_not_found"
+	ChannelAccessArchived        = "channel_archived"
+	ChannelAccessPrivateNoInvite = "private_channel_not_member"
+	ChannelAccessJoinFailed      = "join_failed"
+)
+
+// Channel represents a Slack conversation as returned by conversations.list / conversations.info
+type Channel struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IsPrivate  bool   `json:"is_private"`
+	IsArchived bool   `json:"is_archived"`
+	IsMember   bool   `json:"is_member"`
+}
+
+// ChannelAccessError describes why the bot cannot post to a channel
+type ChannelAccessError struct {
+	Channel string
+	Reason  string
+	Detail  string
+}
+
+// Error implements the error interface for ChannelAccessError
+func (e *ChannelAccessError) Error() string {
+	switch e.Reason {
+	case ChannelAccessNotFound:
+		return fmt.Sprintf("slack channel %s does not exist or is not visible to the bot", e.Channel)
+	case ChannelAccessArchived:
+		return fmt.Sprintf("slack channel %s is archived", e.Channel)
+	case ChannelAccessPrivateNoInvite:
+		return fmt.Sprintf("slack channel %s is private and the bot has not been invited (run /invite @bot in the channel)", e.Channel)
+	case ChannelAccessJoinFailed:
+		return fmt.Sprintf("could not join slack channel %s: %s", e.Channel, e.Detail)
+	default:
+		return fmt.Sprintf("cannot post to slack channel %s: %s", e.Channel, e.Reason)
+	}
+}
+
+// ChannelStatus is one entry of the channel misconfiguration report
+type ChannelStatus struct {
+	Key       string `json:"key"`
+	Channel   string `json:"channel"`
+	ChannelID string `json:"channel_id,omitempty"`
+	Reachable bool   `json:"reachable"`
+	Reason    string `json:"reason,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ListChannels lists the public and private channels visible to the bot
+func (c *Client) ListChannels() ([]Channel, error) {
+	var channels []Channel
+	cursor := ""
+
+	for {
+		query := url.Values{}
+		query.Set("types", "public_channel,private_channel")
+		query.Set("exclude_archived", "false")
+		query.Set("limit", "200")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		resp, err := c.makeRequest("GET", "conversations.list?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			OK               bool      `json:"ok"`
+			Error            string    `json:"error,omitempty"`
+			Channels         []Channel `json:"channels"`
+			ResponseMetadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		err = decodeResponse(resp, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		if !response.OK {
+			return nil, fmt.Errorf("slack API error: %s", response.Error)
+		}
+
+		channels = append(channels, response.Channels...)
+
+		cursor = response.ResponseMetadata.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	return channels, nil
+}
+
+// GetChannelInfo fetches a channel by ID via conversations.info
+func (c *Client) GetChannelInfo(channelID string) (*Channel, error) {
+	query := url.Values{}
+	query.Set("channel", channelID)
+
+	resp, err := c.makeRequest("GET", "conversations.info?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		OK      bool    `json:"ok"`
+		Error   string  `json:"error,omitempty"`
+		Channel Channel `json:"channel"`
+	}
+	if err := decodeResponse(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if !response.OK {
+		if response.Error == "channel_not_found" {
+			return nil, &ChannelAccessError{Channel: channelID, Reason: ChannelAccessNotFound}
+		}
+		return nil, fmt.Errorf("slack API error: %s", response.Error)
+	}
+
+	return &response.Channel, nil
+}
+
+// JoinChannel joins a public channel via conversations.join
+func (c *Client) JoinChannel(channelID string) error {
+	body := map[string]string{
+		"channel": channelID,
+	}
+
+	resp, err := c.makeRequest("POST", "conversations.join", body)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error,omitempty"`
+	}
+	if err := decodeResponse(resp, &response); err != nil {
+		return err
+	}
+
+	if !response.OK {
+		return fmt.Errorf("slack API error: %s", response.Error)
+	}
+
+	return nil
+}
+
+// CheckChannelAccess verifies the bot can post to a channel, joining it if it is public.
+// The channel may be given either as an ID or as a name (with or without a leading #).
+func (c *Client) CheckChannelAccess(channel string) error {
+	ch, err := c.findChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	if ch.IsArchived {
+		return &ChannelAccessError{Channel: channel, Reason: ChannelAccessArchived}
+	}
+
+	if ch.IsMember {
+		return nil
+	}
+
+	if ch.IsPrivate {
+		return &ChannelAccessError{Channel: channel, Reason: ChannelAccessPrivateNoInvite}
+	}
+
+	// Public channel the bot is not in yet - try to join it
+	if err := c.JoinChannel(ch.ID); err != nil {
+		return &ChannelAccessError{Channel: channel, Reason: ChannelAccessJoinFailed, Detail: err.Error()}
+	}
+
+	c.Logger().Info("joined Slack channel", "channel", ch.Name, "channel_id", ch.ID)
+	return nil
+}
+
+// ensureChannelAccess runs CheckChannelAccess once per channel and caches the result.
+// Only classified access problems are returned; failures of the check itself are
+// logged and the post is attempted anyway so a missing scope does not block delivery.
+func (c *Client) ensureChannelAccess(channel string) error {
+	c.channelMu.Lock()
+	verified := c.verifiedChannels[channel]
+	c.channelMu.Unlock()
+
+	if verified {
+		return nil
+	}
+
+	err := c.CheckChannelAccess(channel)
+	if err != nil {
+		if _, ok := err.(*ChannelAccessError); ok {
+			return err
+		}
+		c.Logger().Warn("could not verify access to Slack channel", "channel", channel, "error", err)
+		return nil
+	}
+
+	c.channelMu.Lock()
+	c.verifiedChannels[channel] = true
+	c.channelMu.Unlock()
+
+	return nil
+}
+
+// ChannelReport checks every configured channel in ChannelMapping and reports which ones the bot can't reach
+func (c *Client) ChannelReport() ([]ChannelStatus, error) {
+	channels, err := c.ListChannels()
+	if err != nil {
+		return nil, fmt.Errorf("error listing Slack channels: %w", err)
+	}
+
+	var report []ChannelStatus
+	for key, name := range ChannelMapping {
+		status := ChannelStatus{Key: key, Channel: name}
+
+		ch := matchChannel(channels, name)
+		switch {
+		case ch == nil:
+			status.Reason = ChannelAccessNotFound
+		case ch.IsArchived:
+			status.ChannelID = ch.ID
+			status.Reason = ChannelAccessArchived
+		case ch.IsMember:
+			status.ChannelID = ch.ID
+			status.Reachable = true
+		case ch.IsPrivate:
+			status.ChannelID = ch.ID
+			status.Reason = ChannelAccessPrivateNoInvite
+		default:
+			// Public channel the bot can join on first post
+			status.ChannelID = ch.ID
+			status.Reachable = true
+		}
+
+		if !status.Reachable {
+			status.Error = (&ChannelAccessError{Channel: name, Reason: status.Reason}).Error()
+		}
+
+		report = append(report, status)
+	}
+
+	sort.Slice(report, func(i, j int) bool { return report[i].Key < report[j].Key })
+
+	return report, nil
+}
+
+// findChannel looks up a channel by ID or name
+func (c *Client) findChannel(channel string) (*Channel, error) {
+	if looksLikeChannelID(channel) {
+		return c.GetChannelInfo(channel)
+	}
+
+	channels, err := c.ListChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := matchChannel(channels, channel)
+	if ch == nil {
+		return nil, &ChannelAccessError{Channel: channel, Reason: ChannelAccessNotFound}
+	}
+
+	return ch, nil
+}
+
+// matchChannel finds a channel by ID or name in a list
+func matchChannel(channels []Channel, channel string) *Channel {
+	name := strings.TrimPrefix(channel, "#")
+	for i := range channels {
+		if channels[i].ID == channel || channels[i].Name == name {
+			return &channels[i]
+		}
+	}
+	return nil
+}
+
+// looksLikeChannelID reports whether a string is a Slack conversation ID rather than a name
+func looksLikeChannelID(channel string) bool {
+	if len(channel) < 9 || strings.ToUpper(channel) != channel {
+		return false
+	}
+	switch channel[0] {
+	case 'C', 'G', 'D':
+		return true
+	}
+	return false
+}
+
+// decodeResponse checks the HTTP status and decodes a Slack API response
+func decodeResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return nil
+}
@@ -3,32 +3,104 @@ package slack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/eventbus"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
 )
 
+// DefaultAPIURL is the base URL of the Slack Web API
+const DefaultAPIURL = "https://slack.com/api"
+
 // Client represents a Slack API client
 type Client struct {
-	Token      string
+	// Token is replaced in place when the secret is rotated
+	Token      *common.Secret
 	HTTPClient *http.Client
+	// APIURL is the Web API base URL, SLACK_API_URL when set; the mock Slack
+	// server serves it at http://localhost:3002/api
+	APIURL string
+	// Events receives a notification.* event for every message; nil publishes nothing
+	Events *eventbus.Bus
+
+	// ctx bounds API calls made through a WithContext copy
+	ctx context.Context
+	// caches are shared by WithContext copies
+	*caches
+}
+
+// caches holds lookups that outlive a single request
+type caches struct {
+	channelMu        sync.Mutex
+	verifiedChannels map[string]bool
+
+	groupMu        sync.Mutex
+	groupIDs       map[string]string
+	groupsLoadedAt time.Time
+
+	// platforms receive messages for "<platform>:<channel>" channels
+	platformMu sync.RWMutex
+	platforms  map[string]Notifier
 }
 
 // NewClient creates a new Slack client
 func NewClient(token string) *Client {
 	return &Client{
-		Token: token,
+		Token: common.NewSecret(token),
 		HTTPClient: &http.Client{
 			Timeout: time.Second * 30,
 		},
+		APIURL: apiURLFromEnv(),
+		caches: &caches{
+			verifiedChannels: make(map[string]bool),
+			groupIDs:         make(map[string]string),
+		},
+	}
+}
+
+// apiURLFromEnv reads SLACK_API_URL, defaulting to Slack's own API
+func apiURLFromEnv() string {
+	if value := os.Getenv("SLACK_API_URL"); value != "" {
+		return strings.TrimRight(value, "/")
 	}
+	return DefaultAPIURL
+}
+
+// WithContext returns a copy of the client whose API calls are cancelled with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// Context returns the context API calls run under
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// Logger returns the logger for work done with this client, tagged with the
+// correlation ID of its context
+func (c *Client) Logger() *slog.Logger {
+	return logging.FromContext(c.Context())
 }
 
 // makeRequest performs an HTTP request to the Slack API
 func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.Response, error) {
-	url := fmt.Sprintf("https://slack.com/api/%s", endpoint)
+	url := fmt.Sprintf("%s/%s", c.APIURL, endpoint)
 
 	var req *http.Request
 	var err error
@@ -38,9 +110,9 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.R
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %w", err)
 		}
-		req, err = http.NewRequest(method, url, bytes.NewBuffer(jsonBody))
+		req, err = http.NewRequestWithContext(c.Context(), method, url, bytes.NewBuffer(jsonBody))
 	} else {
-		req, err = http.NewRequest(method, url, nil)
+		req, err = http.NewRequestWithContext(c.Context(), method, url, nil)
 	}
 
 	if err != nil {
@@ -48,9 +120,12 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.R
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token.Get()))
+	logging.Propagate(req)
 
+	start := time.Now()
 	resp, err := c.HTTPClient.Do(req)
+	metrics.RequestDuration.ObserveSince(start, metrics.IntegrationSlack, metrics.Outcome(resp, err))
 	if err != nil {
 		return nil, fmt.Errorf("error executing request: %w", err)
 	}
@@ -59,8 +134,29 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.R
 }
 
 // PostMessage sends a message to a Slack channel
-func (c *Client) PostMessage(channel string, message Message) (string, error) {
+func (c *Client) PostMessage(channel string, message Message) (ts string, err error) {
+	if notifier, id, ok := c.platform(channel); ok {
+		return notifier.PostMessage(id, message)
+	}
+	defer c.countNotification("message", channel, &err)
+
+	if err := c.ensureChannelAccess(channel); err != nil {
+		return "", err
+	}
+	return c.postMessage(channel, message)
+}
+
+// SendDirectMessage posts a message to a user's DM with the bot. Slack opens
+// the conversation itself, so there is no channel to check or join.
+func (c *Client) SendDirectMessage(userID string, message Message) (ts string, err error) {
+	defer c.countNotification("direct_message", userID, &err)
+	return c.postMessage(userID, message)
+}
+
+// postMessage sends chat.postMessage to a channel or user
+func (c *Client) postMessage(channel string, message Message) (string, error) {
 	message.Channel = channel
+	message.Text = c.ExpandGroupMentions(message.Text)
 
 	resp, err := c.makeRequest("POST", "chat.postMessage", message)
 	if err != nil {
@@ -88,8 +184,32 @@ func (c *Client) PostMessage(channel string, message Message) (string, error) {
 	return response.Timestamp, nil
 }
 
+// countNotification records whether a message was delivered and puts a
+// notification.sent or notification.failed event on the event bus
+func (c *Client) countNotification(kind, channel string, err *error) {
+	status := "sent"
+	eventType := eventbus.TypeNotificationSent
+	data := map[string]string{"kind": kind, "channel": channel}
+	if *err != nil {
+		status = "failed"
+		eventType = eventbus.TypeNotificationFailed
+		data["error"] = (*err).Error()
+	}
+	metrics.SlackNotifications.Inc(kind, status)
+	c.Events.Publish(eventbus.Message{
+		Type:    eventType,
+		Source:  eventbus.SourceSlack,
+		Subject: channel,
+		Data:    data,
+	})
+}
+
 // AddReaction adds a reaction to a message
 func (c *Client) AddReaction(channel, timestamp, reaction string) error {
+	if notifier, id, ok := c.platform(channel); ok {
+		return notifier.AddReaction(id, timestamp, reaction)
+	}
+
 	body := map[string]string{
 		"channel":   channel,
 		"timestamp": timestamp,
@@ -122,9 +242,19 @@ func (c *Client) AddReaction(channel, timestamp, reaction string) error {
 }
 
 // PostReply sends a reply to a thread
-func (c *Client) PostReply(channel, threadTS string, message Message) (string, error) {
+func (c *Client) PostReply(channel, threadTS string, message Message) (ts string, err error) {
+	if notifier, id, ok := c.platform(channel); ok {
+		return notifier.PostReply(id, threadTS, message)
+	}
+	defer c.countNotification("reply", channel, &err)
+
+	if err := c.ensureChannelAccess(channel); err != nil {
+		return "", err
+	}
+
 	message.Channel = channel
 	message.ThreadTS = threadTS
+	message.Text = c.ExpandGroupMentions(message.Text)
 
 	resp, err := c.makeRequest("POST", "chat.postMessage", message)
 	if err != nil {
@@ -153,7 +283,12 @@ func (c *Client) PostReply(channel, threadTS string, message Message) (string, e
 }
 
 // UpdateMessage updates a previously sent message
-func (c *Client) UpdateMessage(channel, timestamp string, message Message) error {
+func (c *Client) UpdateMessage(channel, timestamp string, message Message) (err error) {
+	if notifier, id, ok := c.platform(channel); ok {
+		return notifier.UpdateMessage(id, timestamp, message)
+	}
+	defer c.countNotification("update", channel, &err)
+
 	message.Channel = channel
 	message.TS = timestamp
 
@@ -191,20 +326,21 @@ func (c *Client) UploadFile(channel, filename, content string) (string, error) {
 
 // OpenModal opens a modal in Slack
 func (c *Client) OpenModal(request ModalRequest) error {
-	url := "https://slack.com/api/views.open"
+	url := c.APIURL + "/views.open"
 
 	payload, err := json.Marshal(request)
 	if err != nil {
 		return fmt.Errorf("error marshaling modal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(c.Context(), "POST", url, bytes.NewBuffer(payload))
 	if err != nil {
 		return fmt.Errorf("error creating modal request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Authorization", "Bearer "+c.Token.Get())
+	logging.Propagate(req)
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
@@ -0,0 +1,198 @@
+// backend/internal/integrations/slack/commands.go
+package slack
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Response types for slash command replies
+const (
+	ResponseEphemeral = "ephemeral"
+	ResponseInChannel = "in_channel"
+)
+
+// CommandResponse is the immediate reply to a slash command
+type CommandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// CommandFunc runs a validated slash command. args holds exactly SlashCommand.Args
+// values; the last one carries the rest of the text. The returned text is sent back
+// to the user, and an empty string sends nothing.
+type CommandFunc func(command *Command, args []string) (string, error)
+
+// SlashCommand describes one command registered with a CommandRouter
+type SlashCommand struct {
+	Name        string // including the slash, e.g. "/submit-test"
+	Usage       string // argument synopsis, e.g. "TEST_ID PASS|FAIL NOTES"
+	Description string
+	// Args is the number of required arguments
+	Args int
+	// Validate checks the parsed arguments before Handler runs
+	Validate func(args []string) error
+	Handler  CommandFunc
+	// InChannel makes successful replies visible to the whole channel
+	InChannel bool
+}
+
+// UsageText returns the command with its argument synopsis
+func (c *SlashCommand) UsageText() string {
+	if c.Usage == "" {
+		return c.Name
+	}
+	return c.Name + " " + c.Usage
+}
+
+// CommandRouter dispatches slash commands to registered handlers
+type CommandRouter struct {
+	mutex    sync.RWMutex
+	commands map[string]*SlashCommand
+}
+
+// NewCommandRouter creates an empty command router
+func NewCommandRouter() *CommandRouter {
+	return &CommandRouter{
+		commands: make(map[string]*SlashCommand),
+	}
+}
+
+// Register adds a command; names must be unique
+func (r *CommandRouter) Register(command SlashCommand) error {
+	if !strings.HasPrefix(command.Name, "/") || len(command.Name) < 2 {
+		return fmt.Errorf("invalid command name %q", command.Name)
+	}
+	if command.Handler == nil {
+		return fmt.Errorf("command %s has no handler", command.Name)
+	}
+	if command.Args < 0 {
+		return fmt.Errorf("command %s has a negative argument count", command.Name)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.commands[command.Name]; exists {
+		return fmt.Errorf("command %s is already registered", command.Name)
+	}
+	r.commands[command.Name] = &command
+	return nil
+}
+
+// Commands returns the registered commands sorted by name
+func (r *CommandRouter) Commands() []SlashCommand {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	commands := make([]SlashCommand, 0, len(r.commands))
+	for _, command := range r.commands {
+		commands = append(commands, *command)
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands
+}
+
+// Help lists every registered command with its usage
+func (r *CommandRouter) Help() string {
+	var lines []string
+	for _, command := range r.Commands() {
+		line := "`" + command.UsageText() + "`"
+		if command.Description != "" {
+			line += " - " + command.Description
+		}
+		lines = append(lines, line)
+	}
+	return "Available commands:\n" + strings.Join(lines, "\n")
+}
+
+// Dispatch parses, validates and runs a command. Bad input and handler errors are
+// answered with an ephemeral message rather than an HTTP error, so the user sees them.
+func (r *CommandRouter) Dispatch(command *Command) CommandResponse {
+	r.mutex.RLock()
+	spec, ok := r.commands[command.Command]
+	r.mutex.RUnlock()
+
+	if !ok {
+		slog.Warn("unknown Slack command", "command", command.Command)
+		return ephemeral(fmt.Sprintf("Unknown command %s.\n%s", command.Command, r.Help()))
+	}
+
+	text := strings.TrimSpace(command.Text)
+	if text == "help" {
+		help := fmt.Sprintf("Usage: `%s`", spec.UsageText())
+		if spec.Description != "" {
+			help += "\n" + spec.Description
+		}
+		return ephemeral(help)
+	}
+
+	args, ok := splitArgs(text, spec.Args)
+	if !ok {
+		return ephemeral(fmt.Sprintf("Invalid command format. Usage: `%s`", spec.UsageText()))
+	}
+
+	if spec.Validate != nil {
+		if err := spec.Validate(args); err != nil {
+			return ephemeral(fmt.Sprintf("%s\nUsage: `%s`", capitalize(err.Error()), spec.UsageText()))
+		}
+	}
+
+	reply, err := spec.Handler(command, args)
+	if err != nil {
+		slog.Error("error running Slack command", "command", spec.Name, "user", command.UserID, "error", err)
+		return ephemeral(fmt.Sprintf("Sorry, %s failed: %v", spec.Name, err))
+	}
+
+	responseType := ResponseEphemeral
+	if spec.InChannel {
+		responseType = ResponseInChannel
+	}
+	return CommandResponse{ResponseType: responseType, Text: reply}
+}
+
+// ArgOneOf validates that the argument at index is one of the allowed values (case-insensitive)
+func ArgOneOf(index int, allowed ...string) func(args []string) error {
+	return func(args []string) error {
+		if index >= len(args) {
+			return fmt.Errorf("missing argument %d", index+1)
+		}
+		for _, value := range allowed {
+			if strings.EqualFold(args[index], value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q must be one of %s", args[index], strings.Join(allowed, ", "))
+	}
+}
+
+// splitArgs splits text into n arguments, the last taking the remaining text
+func splitArgs(text string, n int) ([]string, bool) {
+	if n == 0 {
+		return nil, true
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) < n {
+		return nil, false
+	}
+
+	args := append([]string(nil), fields[:n-1]...)
+	return append(args, strings.Join(fields[n-1:], " ")), true
+}
+
+// ephemeral builds a reply only the caller can see
+func ephemeral(text string) CommandResponse {
+	return CommandResponse{ResponseType: ResponseEphemeral, Text: text}
+}
+
+// capitalize upper-cases the first letter of an error message for display
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,175 @@
+// backend/internal/integrations/slack/conversations.go
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChannelNameTaken is returned by CreateChannel when a channel already has the name
+var ErrChannelNameTaken = errors.New("slack channel name is taken")
+
+// CreateChannel creates a public or private channel via conversations.create.
+// The bot that creates it is its first member. Needs channels:manage, or
+// groups:write for a private channel.
+func (c *Client) CreateChannel(name string, private bool) (*Channel, error) {
+	body := map[string]interface{}{
+		"name":       name,
+		"is_private": private,
+	}
+
+	resp, err := c.makeRequest("POST", "conversations.create", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		OK      bool    `json:"ok"`
+		Error   string  `json:"error,omitempty"`
+		Channel Channel `json:"channel"`
+	}
+	if err := decodeResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	if response.Error == "name_taken" {
+		return nil, fmt.Errorf("%w: %s", ErrChannelNameTaken, name)
+	}
+	if !response.OK {
+		return nil, fmt.Errorf("slack API error: %s", response.Error)
+	}
+	return &response.Channel, nil
+}
+
+// InviteToChannel adds members to a channel via conversations.invite. Members
+// who are already in the channel are not an error.
+func (c *Client) InviteToChannel(channelID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	body := map[string]string{
+		"channel": channelID,
+		"users":   strings.Join(userIDs, ","),
+	}
+	return c.conversationCall("conversations.invite", body, "already_in_channel")
+}
+
+// SetChannelTopic sets the topic shown at the top of a channel
+func (c *Client) SetChannelTopic(channelID, topic string) error {
+	body := map[string]string{
+		"channel": channelID,
+		"topic":   topic,
+	}
+	return c.conversationCall("conversations.setTopic", body)
+}
+
+// PinMessage pins a message to its channel via pins.add. A message that is
+// already pinned is not an error.
+func (c *Client) PinMessage(channelID, timestamp string) error {
+	body := map[string]string{
+		"channel":   channelID,
+		"timestamp": timestamp,
+	}
+	return c.conversationCall("pins.add", body, "already_pinned")
+}
+
+// ArchiveChannel archives a channel via conversations.archive. A channel that
+// is already archived is not an error.
+func (c *Client) ArchiveChannel(channelID string) error {
+	body := map[string]string{
+		"channel": channelID,
+	}
+	return c.conversationCall("conversations.archive", body, "already_archived")
+}
+
+// ThreadMessage is one message of a thread as conversations.replies lists it
+type ThreadMessage struct {
+	User     string `json:"user,omitempty"`
+	BotID    string `json:"bot_id,omitempty"`
+	Text     string `json:"text"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts,omitempty"`
+}
+
+// Time is when the message was posted, read from its timestamp
+func (m ThreadMessage) Time() time.Time {
+	seconds, err := strconv.ParseFloat(m.TS, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+// GetThreadReplies lists a thread via conversations.replies: the parent
+// message first, then every reply oldest first. Needs channels:history, or
+// groups:history for a private channel.
+func (c *Client) GetThreadReplies(channelID, threadTS string) ([]ThreadMessage, error) {
+	var messages []ThreadMessage
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("channel", channelID)
+		query.Set("ts", threadTS)
+		query.Set("limit", "200")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		resp, err := c.makeRequest("GET", "conversations.replies?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			OK               bool            `json:"ok"`
+			Error            string          `json:"error,omitempty"`
+			Messages         []ThreadMessage `json:"messages"`
+			ResponseMetadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		if err := decodeResponse(resp, &response); err != nil {
+			return nil, err
+		}
+		if !response.OK {
+			return nil, fmt.Errorf("slack API error: %s", response.Error)
+		}
+		messages = append(messages, response.Messages...)
+		if cursor = response.ResponseMetadata.NextCursor; cursor == "" {
+			return messages, nil
+		}
+	}
+}
+
+// ChannelURL is a link that opens a channel in Slack
+func ChannelURL(channelID string) string {
+	return "https://slack.com/app_redirect?channel=" + url.QueryEscape(channelID)
+}
+
+// conversationCall makes a call that answers with only ok and error. The
+// errors in ignore mean the call had nothing left to do.
+func (c *Client) conversationCall(method string, body interface{}, ignore ...string) error {
+	resp, err := c.makeRequest("POST", method, body)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error,omitempty"`
+	}
+	if err := decodeResponse(resp, &response); err != nil {
+		return err
+	}
+	if response.OK {
+		return nil
+	}
+	for _, expected := range ignore {
+		if response.Error == expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("slack API error: %s", response.Error)
+}
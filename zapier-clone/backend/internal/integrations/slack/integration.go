@@ -0,0 +1,132 @@
+// backend/internal/integrations/slack/integration.go
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+)
+
+// Integration exposes Slack to workflows through the common integration
+// interfaces. Slack calls back on its own interaction and command endpoints,
+// so it has no webhook trigger and serves those endpoints as routes instead.
+type Integration struct {
+	Client *Client
+	// Interactions and Commands serve Slack's callbacks, including verifying them
+	Interactions http.Handler
+	Commands     http.Handler
+}
+
+// NewIntegration creates the Slack integration for a client and the handlers
+// of its interaction and command callbacks; nil handlers are not served
+func NewIntegration(client *Client, interactions, commands http.Handler) *Integration {
+	return &Integration{
+		Client:       client,
+		Interactions: interactions,
+		Commands:     commands,
+	}
+}
+
+// Name implements common.Integration
+func (i *Integration) Name() string {
+	return "slack"
+}
+
+// Routes implements common.RouteProvider. Interactions are also accepted at
+// /api/slack/interaction, the path older Slack apps were configured with.
+func (i *Integration) Routes() []common.Route {
+	var routes []common.Route
+	if i.Interactions != nil {
+		routes = append(routes,
+			common.Route{Method: http.MethodPost, Path: "/api/slack/interactions", Handler: i.Interactions},
+			common.Route{Method: http.MethodPost, Path: "/api/slack/interaction", Handler: i.Interactions},
+		)
+	}
+	if i.Commands != nil {
+		routes = append(routes, common.Route{Method: http.MethodPost, Path: "/api/slack/commands", Handler: i.Commands})
+	}
+	return routes
+}
+
+// Actions implements common.Action
+func (i *Integration) Actions() []common.ActionSpec {
+	return []common.ActionSpec{
+		{ID: "post_message", Description: "Post a message; channel may be a ChannelMapping key, and thread_ts posts it as a reply", Fields: []string{"channel", "text"}},
+		{ID: "add_reaction", Description: "React to a message", Fields: []string{"channel", "ts", "reaction"}},
+	}
+}
+
+// Execute implements common.Action
+func (i *Integration) Execute(ctx context.Context, action string, fields map[string]interface{}) (map[string]interface{}, error) {
+	client := i.Client.WithContext(ctx)
+
+	switch action {
+	case "post_message":
+		if err := common.RequireFields(fields, "channel", "text"); err != nil {
+			return nil, err
+		}
+		channel := resolveChannel(common.StringField(fields, "channel"))
+		message := Message{Text: common.StringField(fields, "text")}
+
+		var ts string
+		var err error
+		if threadTS := common.StringField(fields, "thread_ts"); threadTS != "" {
+			ts, err = client.PostReply(channel, threadTS, message)
+		} else {
+			ts, err = client.PostMessage(channel, message)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"channel": channel, "ts": ts}, nil
+
+	case "add_reaction":
+		if err := common.RequireFields(fields, "channel", "ts", "reaction"); err != nil {
+			return nil, err
+		}
+		channel := resolveChannel(common.StringField(fields, "channel"))
+		ts := common.StringField(fields, "ts")
+		if err := client.AddReaction(channel, ts, common.StringField(fields, "reaction")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"channel": channel, "ts": ts}, nil
+	}
+
+	return nil, fmt.Errorf("%w: slack.%s", common.ErrUnknownAction, action)
+}
+
+// ValidateConnection implements common.ConnectionValidator with auth.test
+func (i *Integration) ValidateConnection(ctx context.Context) error {
+	resp, err := i.Client.WithContext(ctx).makeRequest("POST", "auth.test", nil)
+	if err != nil {
+		return fmt.Errorf("error checking Slack token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	if !response.OK {
+		return fmt.Errorf("slack API error: %s", response.Error)
+	}
+	return nil
+}
+
+// resolveChannel maps a ChannelMapping key to its channel
+func resolveChannel(channel string) string {
+	if mapped, ok := ChannelMapping[channel]; ok {
+		return mapped
+	}
+	return channel
+}
@@ -116,7 +116,7 @@ type InteractionPayload struct {
 	ActionTS    string                 `json:"action_ts"`
 	MessageTS   string                 `json:"message_ts"`
 	CallbackID  string                 `json:"callback_id"`
-	Actions     []map[string]string    `json:"actions"`
+	Actions     []InteractionAction    `json:"actions"`
 	State       string                 `json:"state"`
 	ResponseURL string                 `json:"response_url"`
 	Container   map[string]interface{} `json:"container"`
@@ -137,6 +137,10 @@ type InteractionPayload struct {
 				} `json:"selected_options,omitempty"`
 				// For date pickers
 				SelectedDate string `json:"selected_date,omitempty"`
+				// For static selects and radio buttons
+				SelectedOption *struct {
+					Value string `json:"value"`
+				} `json:"selected_option,omitempty"`
 			} `json:"values"`
 		} `json:"state"`
 		Hash string `json:"hash"`
@@ -148,6 +152,51 @@ type InteractionPayload struct {
 		Name string `json:"name"`
 		Team string `json:"team_id,omitempty"`
 	} `json:"user,omitempty"`
+
+	// Channel and message as sent by Slack for block_actions
+	Channel struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"channel,omitempty"`
+	Message struct {
+		TS       string `json:"ts"`
+		ThreadTS string `json:"thread_ts,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+// InteractionAction is one element of an interaction's actions array
+type InteractionAction struct {
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Value    string `json:"value"`
+	ActionTS string `json:"action_ts,omitempty"`
+}
+
+// Normalize fills the flat channel, message and user fields from the nested objects
+// Slack sends, so handlers work with both real payloads and the flat mock format
+func (p *InteractionPayload) Normalize() {
+	if p.ChannelID == "" {
+		p.ChannelID = p.Channel.ID
+	}
+	if p.ChannelID == "" {
+		p.ChannelID, _ = p.Container["channel_id"].(string)
+	}
+	if p.ChannelName == "" {
+		p.ChannelName = p.Channel.Name
+	}
+	if p.MessageTS == "" {
+		p.MessageTS = p.Message.TS
+	}
+	if p.MessageTS == "" {
+		p.MessageTS, _ = p.Container["message_ts"].(string)
+	}
+	if p.UserID == "" {
+		p.UserID = p.User.ID
+	}
+	if p.UserName == "" {
+		p.UserName = p.User.Name
+	}
 }
 
 // ChannelMapping maps GRC categories to Slack channels
@@ -155,11 +204,13 @@ var ChannelMapping = map[string]string{
 	"risk-management": "risk-management",
 	"compliance":      "compliance-team",
 	"incident":        "incident-response",
+	"incident-review": "incident-review",
 	"audit":           "audit-team",
 	"vendor-risk":     "vendor-risk",
 	"regulatory":      "regulatory-updates",
 	"reports":         "grc-reports",
 	"control-testing": "control-testing",
+	"ops":             "grc-ops",
 }
 
 // ModalRequest is a request to open a modal
@@ -0,0 +1,52 @@
+// backend/internal/integrations/slack/notifier.go
+package slack
+
+import (
+	"strings"
+)
+
+// Notifier posts messages to a chat platform. *Client implements it for Slack;
+// other platforms implement it to receive the same messages, converting the
+// blocks to their own format.
+type Notifier interface {
+	PostMessage(channel string, message Message) (string, error)
+	PostReply(channel, threadTS string, message Message) (string, error)
+	UpdateMessage(channel, ts string, message Message) error
+	AddReaction(channel, ts, reaction string) error
+}
+
+var _ Notifier = (*Client)(nil)
+
+// PlatformChannel names a channel on another platform, e.g. "teams:incident"
+func PlatformChannel(platform, channel string) string {
+	return platform + ":" + channel
+}
+
+// RegisterPlatform forwards messages for "<name>:<channel>" channels to
+// notifier, so handlers, threads and digests reach that platform through the
+// Slack client they already hold. It is shared by WithContext copies.
+func (c *Client) RegisterPlatform(name string, notifier Notifier) {
+	c.platformMu.Lock()
+	defer c.platformMu.Unlock()
+	if c.platforms == nil {
+		c.platforms = make(map[string]Notifier)
+	}
+	c.platforms[name] = notifier
+}
+
+// platform returns the notifier and channel for a channel on a registered
+// platform; Slack channels return false
+func (c *Client) platform(channel string) (Notifier, string, bool) {
+	name, id, ok := strings.Cut(channel, ":")
+	if !ok || c.caches == nil {
+		return nil, "", false
+	}
+
+	c.platformMu.RLock()
+	defer c.platformMu.RUnlock()
+	notifier, registered := c.platforms[name]
+	if !registered {
+		return nil, "", false
+	}
+	return notifier, id, true
+}
@@ -0,0 +1,52 @@
+// backend/internal/integrations/slack/signature.go
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Headers Slack signs requests with
+const (
+	HeaderRequestTimestamp = "X-Slack-Request-Timestamp"
+	HeaderSignature        = "X-Slack-Signature"
+)
+
+// maxRequestAge rejects replayed requests older than Slack's recommended five minutes
+const maxRequestAge = 5 * time.Minute
+
+// ErrInvalidSignature is returned when a request was not signed with the app's signing secret
+var ErrInvalidSignature = errors.New("invalid slack request signature")
+
+// VerifySignature checks a request's v0 signature: HMAC-SHA256 of "v0:timestamp:body"
+// under the signing secret, with timestamps older than five minutes rejected
+func VerifySignature(signingSecret, timestamp, signature string, body []byte, now time.Time) error {
+	return VerifySignatureWithin(signingSecret, timestamp, signature, body, now, maxRequestAge)
+}
+
+// VerifySignatureWithin is VerifySignature with a custom clock-skew window
+func VerifySignatureWithin(signingSecret, timestamp, signature string, body []byte, now time.Time, maxAge time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	age := now.Sub(time.Unix(ts, 0))
+	if age > maxAge || age < -maxAge {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// backend/internal/integrations/slack/threads.go
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Thread is the Slack message a ServiceNow record was announced with. Later
+// updates to the record are posted as replies to it.
+type Thread struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+	Table   string `json:"table"`
+	Number  string `json:"number,omitempty"`
+	// State is the record state the last reply reported
+	State string `json:"state,omitempty"`
+	// AssignedTo is the assignee the last reply reported
+	AssignedTo string `json:"assigned_to,omitempty"`
+	// Replies counts the updates posted to the thread
+	Replies   int       `json:"replies"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Message is the parent message as first posted, so its summary can be
+	// refreshed without rebuilding it from a partial update
+	Message Message `json:"message"`
+}
+
+// ThreadStore keeps the thread of each ServiceNow record, keyed by sys_id, in
+// a JSON file
+type ThreadStore struct {
+	mutex    sync.RWMutex
+	threads  map[string]*Thread
+	filePath string
+}
+
+// NewThreadStore loads the threads kept in storagePath
+func NewThreadStore(storagePath string) (*ThreadStore, error) {
+	store := &ThreadStore{
+		threads:  make(map[string]*Thread),
+		filePath: filepath.Join(storagePath, "slack_threads.json"),
+	}
+
+	if _, err := os.Stat(store.filePath); err == nil {
+		file, err := os.ReadFile(store.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading thread file: %w", err)
+		}
+
+		if err := json.Unmarshal(file, &store.threads); err != nil {
+			return nil, fmt.Errorf("error unmarshaling threads: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// Get returns a copy of a record's thread
+func (s *ThreadStore) Get(sysID string) (Thread, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	thread, exists := s.threads[sysID]
+	if !exists {
+		return Thread{}, false
+	}
+	return *thread, true
+}
+
+// Save stores a record's thread, replacing any earlier one
+func (s *ThreadStore) Save(sysID string, thread Thread) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.threads[sysID] = &thread
+	return s.save()
+}
+
+// save persists the threads to disk; callers hold the mutex
+func (s *ThreadStore) save() error {
+	data, err := json.MarshalIndent(s.threads, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling threads: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing thread file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// backend/internal/integrations/slack/usergroups.go
+package slack
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// userGroupTTL is how long resolved usergroup IDs are cached before usergroups.list is called again
+const userGroupTTL = time.Hour
+
+// UserGroup represents a Slack usergroup as returned by usergroups.list
+type UserGroup struct {
+	ID          string `json:"id"`
+	Handle      string `json:"handle"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	UserCount   int    `json:"user_count"`
+}
+
+// UserGroupStatus is one entry of the usergroup mapping report
+type UserGroupStatus struct {
+	Key      string `json:"key"`
+	Handle   string `json:"handle"`
+	GroupID  string `json:"group_id,omitempty"`
+	Resolved bool   `json:"resolved"`
+}
+
+// GroupMapping maps GRC categories to the usergroup handles mentioned in their notifications.
+// Override with SLACK_USERGROUPS, e.g. "incident=security-team,legal;audit=audit-team".
+var GroupMapping = map[string][]string{
+	"risk-management": {"risk-team"},
+	"incident":        {"security-team", "it-ops", "legal"},
+	"audit":           {"audit-team"},
+	"vendor-risk":     {"vendor-management"},
+	"ops":             {"grc-ops"},
+}
+
+// groupMentionPattern matches @handle tokens in free text
+var groupMentionPattern = regexp.MustCompile(`(^|[\s(])@([a-z0-9][a-z0-9._-]*)`)
+
+// LoadGroupMappingFromEnv replaces entries of GroupMapping with those configured in SLACK_USERGROUPS
+func LoadGroupMappingFromEnv() {
+	value := os.Getenv("SLACK_USERGROUPS")
+	if value == "" {
+		return
+	}
+
+	for _, entry := range strings.Split(value, ";") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			slog.Warn("ignoring malformed SLACK_USERGROUPS entry", "entry", entry)
+			continue
+		}
+
+		var handles []string
+		for _, handle := range strings.Split(parts[1], ",") {
+			if handle = strings.TrimPrefix(strings.TrimSpace(handle), "@"); handle != "" {
+				handles = append(handles, handle)
+			}
+		}
+		GroupMapping[strings.TrimSpace(parts[0])] = handles
+	}
+}
+
+// ListUserGroups lists the workspace's usergroups via usergroups.list
+func (c *Client) ListUserGroups() ([]UserGroup, error) {
+	query := url.Values{}
+	query.Set("include_count", "true")
+
+	resp, err := c.makeRequest("GET", "usergroups.list?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		OK         bool        `json:"ok"`
+		Error      string      `json:"error,omitempty"`
+		UserGroups []UserGroup `json:"usergroups"`
+	}
+	if err := decodeResponse(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if !response.OK {
+		return nil, fmt.Errorf("slack API error: %s", response.Error)
+	}
+
+	return response.UserGroups, nil
+}
+
+// UserGroupID resolves a usergroup handle (with or without a leading @) to its ID
+func (c *Client) UserGroupID(handle string) (string, bool) {
+	handle = strings.ToLower(strings.TrimPrefix(handle, "@"))
+
+	c.groupMu.Lock()
+	defer c.groupMu.Unlock()
+
+	if time.Since(c.groupsLoadedAt) > userGroupTTL {
+		c.refreshUserGroupsLocked()
+	}
+
+	id, ok := c.groupIDs[handle]
+	return id, ok
+}
+
+// refreshUserGroupsLocked reloads the handle→ID cache; c.groupMu must be held.
+// A failed lookup keeps the previous cache and is not retried until the TTL expires.
+func (c *Client) refreshUserGroupsLocked() {
+	c.groupsLoadedAt = time.Now()
+
+	groups, err := c.ListUserGroups()
+	if err != nil {
+		c.Logger().Warn("could not load Slack usergroups", "error", err)
+		return
+	}
+
+	ids := make(map[string]string, len(groups))
+	for _, group := range groups {
+		ids[strings.ToLower(group.Handle)] = group.ID
+	}
+	c.groupIDs = ids
+}
+
+// GroupMention returns the mention markup for a usergroup handle. Unknown handles
+// fall back to plain "@handle" text so the message still reads correctly.
+func (c *Client) GroupMention(handle string) string {
+	handle = strings.TrimPrefix(handle, "@")
+	if id, ok := c.UserGroupID(handle); ok {
+		return fmt.Sprintf("<!subteam^%s|@%s>", id, handle)
+	}
+	return "@" + handle
+}
+
+// MentionCategory returns the mentions for every usergroup mapped to a GRC category
+func (c *Client) MentionCategory(key string) string {
+	var mentions []string
+	for _, handle := range GroupMapping[key] {
+		mentions = append(mentions, c.GroupMention(handle))
+	}
+	return strings.Join(mentions, " ")
+}
+
+// ExpandGroupMentions rewrites @handle tokens that name a known usergroup into mention markup
+func (c *Client) ExpandGroupMentions(text string) string {
+	if !strings.Contains(text, "@") {
+		return text
+	}
+
+	return groupMentionPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := groupMentionPattern.FindStringSubmatch(match)
+		id, ok := c.UserGroupID(parts[2])
+		if !ok {
+			return match
+		}
+		return fmt.Sprintf("%s<!subteam^%s|@%s>", parts[1], id, parts[2])
+	})
+}
+
+// UserGroupReport resolves every handle in GroupMapping and reports which ones exist
+func (c *Client) UserGroupReport() ([]UserGroupStatus, error) {
+	groups, err := c.ListUserGroups()
+	if err != nil {
+		return nil, fmt.Errorf("error listing Slack usergroups: %w", err)
+	}
+
+	ids := make(map[string]string, len(groups))
+	for _, group := range groups {
+		ids[strings.ToLower(group.Handle)] = group.ID
+	}
+
+	c.groupMu.Lock()
+	c.groupIDs = ids
+	c.groupsLoadedAt = time.Now()
+	c.groupMu.Unlock()
+
+	var report []UserGroupStatus
+	for key, handles := range GroupMapping {
+		for _, handle := range handles {
+			id, ok := ids[strings.ToLower(handle)]
+			report = append(report, UserGroupStatus{Key: key, Handle: handle, GroupID: id, Resolved: ok})
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Key != report[j].Key {
+			return report[i].Key < report[j].Key
+		}
+		return report[i].Handle < report[j].Handle
+	})
+
+	return report, nil
+}
@@ -0,0 +1,59 @@
+// backend/internal/integrations/slack/users.go
+package slack
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// User is a member of the workspace as returned by users.info and users.lookupByEmail
+type User struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name,omitempty"`
+	Deleted  bool   `json:"deleted,omitempty"`
+	IsBot    bool   `json:"is_bot,omitempty"`
+	Profile  struct {
+		Email       string `json:"email,omitempty"`
+		DisplayName string `json:"display_name,omitempty"`
+	} `json:"profile"`
+}
+
+// LookupUserByEmail finds a member by email address via users.lookupByEmail. It
+// returns nil when nobody in the workspace has the address. Needs users:read.email.
+func (c *Client) LookupUserByEmail(email string) (*User, error) {
+	query := url.Values{}
+	query.Set("email", email)
+	return c.getUser("users.lookupByEmail?" + query.Encode())
+}
+
+// GetUserInfo fetches a member by ID via users.info, or nil when there is none
+func (c *Client) GetUserInfo(userID string) (*User, error) {
+	query := url.Values{}
+	query.Set("user", userID)
+	return c.getUser("users.info?" + query.Encode())
+}
+
+// getUser calls a users.* method that answers with one user
+func (c *Client) getUser(endpoint string) (*User, error) {
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error,omitempty"`
+		User  User   `json:"user"`
+	}
+	if err := decodeResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	if response.Error == "users_not_found" || response.Error == "user_not_found" {
+		return nil, nil
+	}
+	if !response.OK {
+		return nil, fmt.Errorf("slack API error: %s", response.Error)
+	}
+	return &response.User, nil
+}
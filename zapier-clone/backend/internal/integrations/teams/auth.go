@@ -0,0 +1,192 @@
+// backend/internal/integrations/teams/auth.go
+package teams
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bot Framework token settings
+const (
+	// DefaultKeysURL lists the keys the Bot Connector signs its requests with
+	DefaultKeysURL = "https://login.botframework.com/v1/.well-known/keys"
+	// botIssuer is the issuer of Bot Connector tokens
+	botIssuer = "https://api.botframework.com"
+	// keyRefreshInterval is how often keys are re-read; unknown key IDs are
+	// looked up at most once a minute
+	keyRefreshInterval = 24 * time.Hour
+	// tokenClockSkew is the leeway on expiry and not-before times
+	tokenClockSkew = 5 * time.Minute
+)
+
+// ErrInvalidToken is returned for requests that are not from the Bot Connector
+var ErrInvalidToken = errors.New("invalid Bot Framework token")
+
+// Verifier checks the bearer token the Bot Connector sends with every
+// activity: an RS256 JWT issued by api.botframework.com for the bot's app ID.
+// It implements middleware.Verifier.
+type Verifier struct {
+	AppID      string
+	KeysURL    string
+	HTTPClient *http.Client
+
+	mutex     sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewVerifier creates a verifier for tokens issued to appID
+func NewVerifier(appID string) *Verifier {
+	return &Verifier{
+		AppID:      appID,
+		KeysURL:    DefaultKeysURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify checks the Authorization header of an activity
+func (v *Verifier) Verify(r *http.Request, body []byte, now time.Time) error {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "RS256" {
+		return ErrInvalidToken
+	}
+
+	key, err := v.key(header.Kid, now)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrInvalidToken
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+		return ErrInvalidToken
+	}
+
+	var claims struct {
+		Issuer     string `json:"iss"`
+		Audience   string `json:"aud"`
+		Expires    int64  `json:"exp"`
+		NotBefore  int64  `json:"nbf"`
+		ServiceURL string `json:"serviceurl"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return ErrInvalidToken
+	}
+	if claims.Issuer != botIssuer || claims.Audience != v.AppID {
+		return ErrInvalidToken
+	}
+	if now.After(time.Unix(claims.Expires, 0).Add(tokenClockSkew)) || now.Before(time.Unix(claims.NotBefore, 0).Add(-tokenClockSkew)) {
+		return ErrInvalidToken
+	}
+
+	// The token names the service URL replies must go to; the activity must agree
+	if claims.ServiceURL != "" {
+		var activity struct {
+			ServiceURL string `json:"serviceUrl"`
+		}
+		if json.Unmarshal(body, &activity) == nil && activity.ServiceURL != "" &&
+			strings.TrimRight(activity.ServiceURL, "/") != strings.TrimRight(claims.ServiceURL, "/") {
+			return ErrInvalidToken
+		}
+	}
+	return nil
+}
+
+// key returns the signing key with the given ID, refreshing the key set when
+// it is stale or does not have it
+func (v *Verifier) key(kid string, now time.Time) (*rsa.PublicKey, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	key, ok := v.keys[kid]
+	stale := now.Sub(v.fetchedAt) > keyRefreshInterval
+	if ok && !stale {
+		return key, nil
+	}
+	if !stale && now.Sub(v.fetchedAt) < time.Minute {
+		return nil, ErrInvalidToken
+	}
+
+	keys, err := v.fetchKeys()
+	if err != nil {
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+	v.keys = keys
+	v.fetchedAt = now
+
+	if key, ok = v.keys[kid]; !ok {
+		return nil, ErrInvalidToken
+	}
+	return key, nil
+}
+
+// fetchKeys reads the Bot Connector's JSON Web Key Set
+func (v *Verifier) fetchKeys() (map[string]*rsa.PublicKey, error) {
+	resp, err := v.HTTPClient.Get(v.KeysURL)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching Bot Framework keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching Bot Framework keys: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("error decoding Bot Framework keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+// decodeSegment decodes one base64url JSON part of a JWT
+func decodeSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,219 @@
+// backend/internal/integrations/teams/cards.go
+package teams
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// adaptiveCardType is the attachment content type of an Adaptive Card
+const adaptiveCardType = "application/vnd.microsoft.card.adaptive"
+
+// Slack mrkdwn that Teams markdown writes differently
+var (
+	labeledLink = regexp.MustCompile(`<(https?://[^|>]+)\|([^>]+)>`)
+	bareLink    = regexp.MustCompile(`<(https?://[^>]+)>`)
+	boldText    = regexp.MustCompile(`(^|[^*\w])\*([^*\n]+)\*([^*\w]|$)`)
+)
+
+// Activity builds the Bot Framework message for a Slack message: blocks become
+// an Adaptive Card, and plain text is sent as markdown
+func Activity(message slack.Message) map[string]interface{} {
+	activity := map[string]interface{}{"type": "message"}
+	if len(message.Blocks) == 0 {
+		activity["text"] = Markdown(message.Text)
+		activity["textFormat"] = "markdown"
+		return activity
+	}
+
+	activity["attachments"] = []map[string]interface{}{{
+		"contentType": adaptiveCardType,
+		"content":     Card(message),
+	}}
+	if message.Text != "" {
+		activity["summary"] = message.Text
+	}
+	return activity
+}
+
+// Card converts Block Kit blocks to an Adaptive Card. Buttons with a URL open
+// it; other buttons submit their action_id and value back to the bot, which
+// handles them like the Slack button.
+func Card(message slack.Message) map[string]interface{} {
+	var body []map[string]interface{}
+	var actions []map[string]interface{}
+	separate := false
+
+	add := func(element map[string]interface{}) {
+		if separate {
+			element["separator"] = true
+			separate = false
+		}
+		body = append(body, element)
+	}
+
+	for _, block := range message.Blocks {
+		switch block.Type {
+		case "header":
+			if block.Text != nil {
+				add(textBlock(block.Text.Text, map[string]interface{}{"size": "Large", "weight": "Bolder"}))
+			}
+
+		case "section":
+			if block.Text != nil && block.Text.Text != "" {
+				add(textBlock(block.Text.Text, nil))
+			}
+			if len(block.Fields) > 0 {
+				add(factSet(block.Fields))
+			}
+			if block.Accessory != nil {
+				if action := cardAction(block.Accessory); action != nil {
+					actions = append(actions, action)
+				}
+			}
+
+		case "context":
+			var parts []string
+			for _, element := range block.Elements {
+				if e := decodeElement(element); e.text() != "" {
+					parts = append(parts, e.text())
+				}
+			}
+			if len(parts) > 0 {
+				add(textBlock(strings.Join(parts, " · "), map[string]interface{}{"size": "Small", "isSubtle": true}))
+			}
+
+		case "actions":
+			var set []map[string]interface{}
+			for _, element := range block.Elements {
+				if action := cardAction(element); action != nil {
+					set = append(set, action)
+				}
+			}
+			if len(set) > 0 {
+				add(map[string]interface{}{"type": "ActionSet", "actions": set})
+			}
+
+		case "divider":
+			separate = true
+		}
+	}
+
+	if len(body) == 0 && message.Text != "" {
+		body = append(body, textBlock(message.Text, nil))
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+		"msteams": map[string]interface{}{"width": "Full"},
+	}
+	if len(actions) > 0 {
+		card["actions"] = actions
+	}
+	return card
+}
+
+// Markdown rewrites Slack mrkdwn links and bold text for Teams
+func Markdown(text string) string {
+	text = labeledLink.ReplaceAllString(text, "[$2]($1)")
+	text = bareLink.ReplaceAllString(text, "$1")
+	text = boldText.ReplaceAllString(text, "$1**$2**$3")
+	// Teams needs a blank line or two trailing spaces for a line break
+	return strings.ReplaceAll(text, "\n", "\n\n")
+}
+
+// textBlock is a wrapping TextBlock with extra properties
+func textBlock(text string, properties map[string]interface{}) map[string]interface{} {
+	block := map[string]interface{}{"type": "TextBlock", "text": Markdown(text), "wrap": true}
+	for key, value := range properties {
+		block[key] = value
+	}
+	return block
+}
+
+// factSet turns "*Label:*\nvalue" section fields into facts
+func factSet(fields []*slack.TextObject) map[string]interface{} {
+	facts := make([]map[string]string, 0, len(fields))
+	for _, field := range fields {
+		if field == nil {
+			continue
+		}
+		title, value, ok := strings.Cut(field.Text, "\n")
+		if !ok {
+			title, value = "", field.Text
+		}
+		title = strings.TrimSuffix(strings.Trim(title, "* "), ":")
+		facts = append(facts, map[string]string{"title": title, "value": Markdown(value)})
+	}
+	return map[string]interface{}{"type": "FactSet", "facts": facts}
+}
+
+// element is the part of a Block Kit element the card needs
+type element struct {
+	Type     string          `json:"type"`
+	Text     json.RawMessage `json:"text"`
+	ActionID string          `json:"action_id"`
+	Value    string          `json:"value"`
+	URL      string          `json:"url"`
+	Style    string          `json:"style"`
+}
+
+// text reads the element's text, which is a string in context elements and a
+// text object in buttons
+func (e element) text() string {
+	var text string
+	if json.Unmarshal(e.Text, &text) == nil {
+		return text
+	}
+	var object slack.TextObject
+	if json.Unmarshal(e.Text, &object) == nil {
+		return object.Text
+	}
+	return ""
+}
+
+// decodeElement reads an element built in code or loaded from a stored thread
+func decodeElement(value interface{}) element {
+	var e element
+	if data, err := json.Marshal(value); err == nil {
+		json.Unmarshal(data, &e)
+	}
+	return e
+}
+
+// cardAction converts a button; other interactive elements have no card equivalent
+func cardAction(value interface{}) map[string]interface{} {
+	e := decodeElement(value)
+	if e.Type != "button" {
+		return nil
+	}
+
+	if e.URL != "" {
+		return map[string]interface{}{"type": "Action.OpenUrl", "title": e.text(), "url": e.URL}
+	}
+
+	action := map[string]interface{}{
+		"type":  "Action.Submit",
+		"title": e.text(),
+		"data":  SubmitData{ActionID: e.ActionID, Value: e.Value},
+	}
+	switch e.Style {
+	case "primary":
+		action["style"] = "positive"
+	case "danger":
+		action["style"] = "destructive"
+	}
+	return action
+}
+
+// SubmitData is what a converted button sends back to the bot
+type SubmitData struct {
+	ActionID string `json:"action_id"`
+	Value    string `json:"value"`
+}
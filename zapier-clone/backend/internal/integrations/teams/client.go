@@ -0,0 +1,260 @@
+// backend/internal/integrations/teams/client.go
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// Bot Framework defaults
+const (
+	// DefaultServiceURL is the Bot Connector endpoint for Teams channels
+	DefaultServiceURL = "https://smba.trafficmanager.net/teams/"
+	// DefaultTenantID issues tokens for multi-tenant bots
+	DefaultTenantID = "botframework.com"
+	// botScope is the scope of Bot Connector access tokens
+	botScope = "https://api.botframework.com/.default"
+)
+
+// Client posts to Microsoft Teams channels as a Bot Framework bot. It
+// implements slack.Notifier, so it receives the same messages as Slack and
+// converts their blocks to Adaptive Cards.
+type Client struct {
+	AppID       string
+	AppPassword string
+	// TenantID is the Azure AD tenant of a single-tenant bot
+	TenantID   string
+	ServiceURL string
+	// Channels maps keys such as "incident" to Teams channel IDs
+	// ("19:...@thread.tacv2"); unmapped channels are used as IDs
+	Channels   map[string]string
+	HTTPClient *http.Client
+	// TokenURL overrides the Azure AD token endpoint, for mock servers
+	TokenURL string
+
+	// ctx bounds API calls made through a WithContext copy
+	ctx context.Context
+	// token is shared by WithContext copies
+	token *tokenCache
+}
+
+// tokenCache holds the bot's access token until shortly before it expires
+type tokenCache struct {
+	mutex     sync.Mutex
+	value     string
+	expiresAt time.Time
+}
+
+// NewClient creates a new Teams client; empty tenant and service URL use the defaults
+func NewClient(appID, appPassword, tenantID, serviceURL string, channels map[string]string) *Client {
+	if tenantID == "" {
+		tenantID = DefaultTenantID
+	}
+	if serviceURL == "" {
+		serviceURL = DefaultServiceURL
+	}
+	return &Client{
+		AppID:       appID,
+		AppPassword: appPassword,
+		TenantID:    tenantID,
+		ServiceURL:  strings.TrimRight(serviceURL, "/"),
+		Channels:    channels,
+		HTTPClient:  common.NewHTTPClient(30 * time.Second),
+		token:       &tokenCache{},
+	}
+}
+
+// WithContext returns a copy of the client whose API calls are cancelled with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// Context returns the context API calls run under
+func (c *Client) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// Logger returns the logger for work done with this client
+func (c *Client) Logger() *slog.Logger {
+	return logging.FromContext(c.Context())
+}
+
+// PostMessage starts a new thread in a channel and returns its message ID
+func (c *Client) PostMessage(channel string, message slack.Message) (string, error) {
+	channelID := c.resolveChannel(channel)
+	body := map[string]interface{}{
+		"isGroup":     true,
+		"channelData": map[string]interface{}{"channel": map[string]string{"id": channelID}},
+		"activity":    Activity(message),
+	}
+
+	var response struct {
+		ID         string `json:"id"`
+		ActivityID string `json:"activityId"`
+	}
+	if err := c.makeRequest("POST", "v3/conversations", body, &response); err != nil {
+		return "", fmt.Errorf("error posting to Teams channel %s: %w", channel, err)
+	}
+
+	// The conversation ID of a new thread ends in ";messageid=<id>"
+	if response.ActivityID == "" {
+		_, response.ActivityID, _ = strings.Cut(response.ID, ";messageid=")
+	}
+	return response.ActivityID, nil
+}
+
+// PostReply replies in the thread started by threadTS
+func (c *Client) PostReply(channel, threadTS string, message slack.Message) (string, error) {
+	var response struct {
+		ID string `json:"id"`
+	}
+	endpoint := fmt.Sprintf("v3/conversations/%s/activities", c.threadID(channel, threadTS))
+	if err := c.makeRequest("POST", endpoint, Activity(message), &response); err != nil {
+		return "", fmt.Errorf("error replying in Teams thread %s: %w", threadTS, err)
+	}
+	return response.ID, nil
+}
+
+// UpdateMessage replaces the card of a message the bot posted
+func (c *Client) UpdateMessage(channel, ts string, message slack.Message) error {
+	activity := Activity(message)
+	activity["id"] = ts
+	endpoint := fmt.Sprintf("v3/conversations/%s/activities/%s", c.threadID(channel, ts), url.PathEscape(ts))
+	if err := c.makeRequest("PUT", endpoint, activity, nil); err != nil {
+		return fmt.Errorf("error updating Teams message %s: %w", ts, err)
+	}
+	return nil
+}
+
+// AddReaction does nothing: bots cannot react to Teams messages, and a
+// missing reaction should not fail the announcement it decorates
+func (c *Client) AddReaction(channel, ts, reaction string) error {
+	c.Logger().Debug("skipping reaction: not supported in Teams", "channel", channel, "reaction", reaction)
+	return nil
+}
+
+// resolveChannel maps a channel key to its Teams channel ID
+func (c *Client) resolveChannel(channel string) string {
+	if id, ok := c.Channels[channel]; ok {
+		return id
+	}
+	return channel
+}
+
+// threadID is the conversation ID of the thread under a channel's root message
+func (c *Client) threadID(channel, rootID string) string {
+	return url.PathEscape(c.resolveChannel(channel) + ";messageid=" + rootID)
+}
+
+// makeRequest calls the Bot Connector API with the bot's access token and
+// decodes a JSON response into out when it is not nil
+func (c *Client) makeRequest(method, endpoint string, body, out interface{}) error {
+	token, err := c.accessToken()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error marshaling request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(metrics.WithIntegration(c.Context(), metrics.IntegrationTeams), method, c.ServiceURL+"/"+endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	logging.Propagate(req)
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	metrics.RequestDuration.ObserveSince(start, metrics.IntegrationTeams, metrics.Outcome(resp, err))
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Teams API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("error decoding response: %w", err)
+		}
+	}
+	return nil
+}
+
+// accessToken returns a cached Bot Connector token, fetching a new one with
+// the app's client credentials when it is within a minute of expiring
+func (c *Client) accessToken() (string, error) {
+	c.token.mutex.Lock()
+	defer c.token.mutex.Unlock()
+
+	if c.token.value != "" && time.Until(c.token.expiresAt) > time.Minute {
+		return c.token.value, nil
+	}
+
+	tokenURL := c.TokenURL
+	if tokenURL == "" {
+		tokenURL = fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", url.PathEscape(c.TenantID))
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.AppID},
+		"client_secret": {c.AppPassword},
+		"scope":         {botScope},
+	}
+	req, err := http.NewRequestWithContext(c.Context(), "POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting Teams bot token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("error decoding token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || response.AccessToken == "" {
+		return "", fmt.Errorf("error requesting Teams bot token (status %d): %s", resp.StatusCode, response.ErrorDescription)
+	}
+
+	c.token.value = response.AccessToken
+	c.token.expiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	return c.token.value, nil
+}
@@ -0,0 +1,85 @@
+// backend/internal/integrations/teams/integration.go
+package teams
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// Integration exposes Microsoft Teams to workflows through the common
+// integration interfaces. Button presses arrive at the bot endpoint, so it
+// has no webhook trigger and serves that endpoint as a route instead.
+type Integration struct {
+	Client *Client
+	// Bot serves the bot's messaging endpoint, including verifying its requests
+	Bot http.Handler
+}
+
+// NewIntegration creates the Teams integration for a client and the handler
+// of its bot endpoint; a nil handler is not served
+func NewIntegration(client *Client, bot http.Handler) *Integration {
+	return &Integration{
+		Client: client,
+		Bot:    bot,
+	}
+}
+
+// Name implements common.Integration
+func (i *Integration) Name() string {
+	return "teams"
+}
+
+// Routes implements common.RouteProvider
+func (i *Integration) Routes() []common.Route {
+	if i.Bot == nil {
+		return nil
+	}
+	return []common.Route{{Method: http.MethodPost, Path: "/api/teams/messages", Handler: i.Bot}}
+}
+
+// Actions implements common.Action
+func (i *Integration) Actions() []common.ActionSpec {
+	return []common.ActionSpec{
+		{ID: "post_message", Description: "Post a message; channel may be a TEAMS_CHANNELS key, and thread_ts posts it as a reply", Fields: []string{"channel", "text"}},
+	}
+}
+
+// Execute implements common.Action
+func (i *Integration) Execute(ctx context.Context, action string, fields map[string]interface{}) (map[string]interface{}, error) {
+	client := i.Client.WithContext(ctx)
+
+	switch action {
+	case "post_message":
+		if err := common.RequireFields(fields, "channel", "text"); err != nil {
+			return nil, err
+		}
+		channel := common.StringField(fields, "channel")
+		message := slack.Message{Text: common.StringField(fields, "text")}
+
+		var ts string
+		var err error
+		if threadTS := common.StringField(fields, "thread_ts"); threadTS != "" {
+			ts, err = client.PostReply(channel, threadTS, message)
+		} else {
+			ts, err = client.PostMessage(channel, message)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"channel": channel, "ts": ts}, nil
+	}
+
+	return nil, fmt.Errorf("%w: teams.%s", common.ErrUnknownAction, action)
+}
+
+// ValidateConnection implements common.ConnectionValidator by requesting a bot token
+func (i *Integration) ValidateConnection(ctx context.Context) error {
+	if _, err := i.Client.WithContext(ctx).accessToken(); err != nil {
+		return fmt.Errorf("error checking Teams bot credentials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// backend/internal/integrations/teams/models.go
+package teams
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// IncomingActivity is the part of a Bot Framework activity the bot reads
+type IncomingActivity struct {
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	ServiceURL string `json:"serviceUrl"`
+	ReplyToID  string `json:"replyToId"`
+	From       struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		AADObjectID string `json:"aadObjectId"`
+	} `json:"from"`
+	Conversation struct {
+		ID string `json:"id"`
+	} `json:"conversation"`
+	// Value carries the data of a submitted Adaptive Card
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Submit returns the button data of a card submit
+func (a *IncomingActivity) Submit() (SubmitData, bool) {
+	var data SubmitData
+	if a.Type != "message" || len(a.Value) == 0 || json.Unmarshal(a.Value, &data) != nil || data.ActionID == "" {
+		return SubmitData{}, false
+	}
+	return data, true
+}
+
+// Thread returns the channel and root message of the thread the activity was
+// sent in; channel conversation IDs end in ";messageid=<root>"
+func (a *IncomingActivity) Thread() (channel, rootID string) {
+	channel, rootID, ok := strings.Cut(a.Conversation.ID, ";messageid=")
+	if !ok {
+		rootID = a.ReplyToID
+	}
+	return channel, rootID
+}
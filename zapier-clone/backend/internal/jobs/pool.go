@@ -0,0 +1,447 @@
+// backend/internal/jobs/pool.go
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/metrics"
+)
+
+// Defaults for NewPoolFromEnv
+const (
+	DefaultWorkers      = 8
+	DefaultQueueSize    = 1000
+	DefaultDrainTimeout = 30 * time.Second
+	// cancelGrace is how long cancelled jobs get to return once a drain times out
+	cancelGrace = 5 * time.Second
+	pendingFile = "pending_jobs.json"
+)
+
+// Errors returned by Submit; webhook handlers answer 503 so the sender retries
+var (
+	ErrDraining  = errors.New("job pool is shutting down")
+	ErrQueueFull = errors.New("job queue is full")
+)
+
+// Handler runs one job. Its context carries the correlation ID of the request
+// that queued the job and is cancelled when a drain runs out of time.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Job is a unit of background sync work, kept in a form that can be written
+// to disk and run again after a restart
+type Job struct {
+	Tenant    string          `json:"tenant"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload"`
+	RequestID string          `json:"request_id,omitempty"`
+	Queued    time.Time       `json:"queued"`
+	// Interrupted is set on jobs that were cancelled mid-run by a drain; they
+	// may have made some of their writes already
+	Interrupted bool `json:"interrupted,omitempty"`
+}
+
+// Pool runs background sync jobs on a fixed set of workers. On shutdown Drain
+// stops taking jobs, waits a bounded time for queued and running ones, and
+// writes whatever could not finish to a file that Start runs again.
+type Pool struct {
+	Workers   int
+	QueueSize int
+	// Path is the file unfinished jobs are written to
+	Path string
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	onDrain  []func()
+	running  map[*Job]struct{}
+	// unstarted holds jobs a worker took from the queue after the pool was cancelled
+	unstarted []*Job
+	started   bool
+	drained   atomic.Bool
+
+	// queueMu guards sends on queue against the drain closing it
+	queueMu  sync.RWMutex
+	queue    chan *Job
+	draining bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPool creates a pool; call Start to run it
+func NewPool(workers, queueSize int, path string) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Pool{
+		Workers:   workers,
+		QueueSize: queueSize,
+		Path:      path,
+		handlers:  make(map[string]Handler),
+		running:   make(map[*Job]struct{}),
+		queue:     make(chan *Job, queueSize),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// NewPoolFromEnv sizes the pool from JOB_WORKERS and JOB_QUEUE_SIZE and keeps
+// unfinished jobs in dataDir
+func NewPoolFromEnv(dataDir string) *Pool {
+	return NewPool(intFromEnv("JOB_WORKERS", DefaultWorkers), intFromEnv("JOB_QUEUE_SIZE", DefaultQueueSize), filepath.Join(dataDir, pendingFile))
+}
+
+// DrainTimeoutFromEnv reads SHUTDOWN_DRAIN_TIMEOUT, the time Drain waits for jobs
+func DrainTimeoutFromEnv() time.Duration {
+	if value := os.Getenv("SHUTDOWN_DRAIN_TIMEOUT"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+			return d
+		}
+		slog.Warn("ignoring invalid SHUTDOWN_DRAIN_TIMEOUT", "value", value)
+	}
+	return DefaultDrainTimeout
+}
+
+// Queue returns the view of the pool that one tenant's handlers use
+func (p *Pool) Queue(tenant string) *Queue {
+	return &Queue{pool: p, tenant: tenant}
+}
+
+// OnDrain registers fn to run when a drain starts, before the pool stops
+// taking jobs, so work held elsewhere (such as settling webhooks) can be submitted
+func (p *Pool) OnDrain(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onDrain = append(p.onDrain, fn)
+}
+
+// Start runs the workers and queues the jobs a previous drain left behind.
+// Call it after every tenant has registered its handlers.
+func (p *Pool) Start() error {
+	p.mu.Lock()
+	if p.started {
+		p.mu.Unlock()
+		return nil
+	}
+	p.started = true
+	p.mu.Unlock()
+
+	for i := 0; i < p.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+
+	pending, err := p.loadPending()
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+	if err := os.Remove(p.Path); err != nil {
+		return fmt.Errorf("error removing %s: %w", p.Path, err)
+	}
+
+	for i, job := range pending {
+		if job.Interrupted {
+			slog.Warn("running a job again that was interrupted by shutdown", "tenant", job.Tenant, "kind", job.Kind, "request_id", job.RequestID)
+		}
+		if err := p.enqueue(job); err != nil {
+			// Keep what did not fit for the next start
+			slog.Warn("could not requeue every job left from the last shutdown", "requeued", i, "count", len(pending), "error", err)
+			return p.savePending(pending[i:])
+		}
+	}
+	slog.Info("requeued jobs left from the last shutdown", "count", len(pending))
+	return nil
+}
+
+// Drain stops taking jobs and waits until every queued and running job has
+// finished or ctx is done. Jobs still queued then, and running jobs that do not
+// return within a short grace after being cancelled, are written to Path.
+func (p *Pool) Drain(ctx context.Context) error {
+	if p.drained.Swap(true) {
+		return nil
+	}
+	p.mu.Lock()
+	hooks := append([]func(){}, p.onDrain...)
+	p.mu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+
+	p.queueMu.Lock()
+	p.draining = true
+	close(p.queue)
+	p.queueMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	timedOut := false
+	select {
+	case <-done:
+	case <-ctx.Done():
+		// Out of time: cancel running jobs so their outbound calls stop
+		timedOut = true
+		p.cancel()
+		select {
+		case <-done:
+		case <-time.After(cancelGrace):
+		}
+	}
+
+	var unfinished []*Job
+	p.mu.Lock()
+	for job := range p.running {
+		job.Interrupted = true
+		unfinished = append(unfinished, job)
+	}
+	unfinished = append(unfinished, p.unstarted...)
+	p.mu.Unlock()
+	// Anything left in the queue was never started (or the pool never ran)
+	for job := range p.queue {
+		unfinished = append(unfinished, job)
+	}
+	if len(unfinished) == 0 {
+		logging.FromContext(ctx).Info("all background jobs finished")
+		return nil
+	}
+
+	for _, job := range unfinished {
+		metrics.Jobs.Inc(job.Kind, "persisted")
+	}
+	if err := p.savePending(unfinished); err != nil {
+		return err
+	}
+	if timedOut {
+		logging.FromContext(ctx).Warn("drain timed out; saved unfinished jobs", "count", len(unfinished), "path", p.Path)
+	} else {
+		logging.FromContext(ctx).Info("saved unstarted jobs", "count", len(unfinished), "path", p.Path)
+	}
+	return nil
+}
+
+// Stats is a snapshot of a pool's load
+type Stats struct {
+	Workers  int  `json:"workers"`
+	Capacity int  `json:"capacity"`
+	Queued   int  `json:"queued"`
+	Running  int  `json:"running"`
+	Draining bool `json:"draining"`
+}
+
+// Stats returns how many jobs are waiting and running, across all tenants
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	running := len(p.running)
+	p.mu.Unlock()
+	return Stats{
+		Workers:  p.Workers,
+		Capacity: p.QueueSize,
+		Queued:   len(p.queue),
+		Running:  running,
+		Draining: p.isDraining(),
+	}
+}
+
+// isDraining reports whether a drain has stopped the pool taking jobs
+func (p *Pool) isDraining() bool {
+	p.queueMu.RLock()
+	defer p.queueMu.RUnlock()
+	return p.draining
+}
+
+// submit queues a job unless the pool is draining or full
+func (p *Pool) submit(ctx context.Context, tenant, kind string, payload interface{}) error {
+	if p.isDraining() {
+		metrics.Jobs.Inc(kind, "rejected")
+		return ErrDraining
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding %s job: %w", kind, err)
+	}
+	return p.enqueue(&Job{
+		Tenant:    tenant,
+		Kind:      kind,
+		Payload:   data,
+		RequestID: logging.RequestID(ctx),
+		Queued:    time.Now(),
+	})
+}
+
+// enqueue adds a job without blocking. The read lock keeps a drain from
+// closing the queue until the send is done.
+func (p *Pool) enqueue(job *Job) error {
+	p.queueMu.RLock()
+	defer p.queueMu.RUnlock()
+	if p.draining {
+		metrics.Jobs.Inc(job.Kind, "rejected")
+		return ErrDraining
+	}
+	select {
+	case p.queue <- job:
+		return nil
+	default:
+		metrics.Jobs.Inc(job.Kind, "rejected")
+		return ErrQueueFull
+	}
+}
+
+// work runs jobs until the queue is closed and empty, or the pool is cancelled
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case job, ok := <-p.queue:
+			if !ok {
+				return
+			}
+			if p.ctx.Err() != nil {
+				p.mu.Lock()
+				p.unstarted = append(p.unstarted, job)
+				p.mu.Unlock()
+				return
+			}
+			p.run(job)
+		}
+	}
+}
+
+// run executes one job with its tenant's handler
+func (p *Pool) run(job *Job) {
+	p.mu.Lock()
+	handler, ok := p.handlers[job.Tenant+"/"+job.Kind]
+	p.running[job] = struct{}{}
+	p.mu.Unlock()
+
+	ctx := p.ctx
+	if job.RequestID != "" {
+		ctx = logging.WithRequestID(ctx, job.RequestID)
+	}
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler for %s jobs of tenant %s", job.Kind, job.Tenant)
+	} else {
+		err = runSafely(ctx, handler, job.Payload)
+	}
+
+	// A job cancelled by the drain stays in running so it is saved
+	if p.ctx.Err() != nil {
+		return
+	}
+	p.mu.Lock()
+	delete(p.running, job)
+	p.mu.Unlock()
+
+	if err != nil {
+		logging.FromContext(ctx).Error("background job failed", "tenant", job.Tenant, "kind", job.Kind, "error", err)
+		metrics.Jobs.Inc(job.Kind, "failed")
+		return
+	}
+	metrics.Jobs.Inc(job.Kind, "completed")
+}
+
+// runSafely turns a panicking job into an error so one bad payload cannot stop a worker
+func runSafely(ctx context.Context, handler Handler, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// loadPending reads the jobs a previous drain saved
+func (p *Pool) loadPending() ([]*Job, error) {
+	data, err := os.ReadFile(p.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading pending jobs: %w", err)
+	}
+	var pending []*Job
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", p.Path, err)
+	}
+	return pending, nil
+}
+
+// savePending writes unfinished jobs, keeping any the file already holds
+func (p *Pool) savePending(jobs []*Job) error {
+	existing, err := p.loadPending()
+	if err != nil {
+		return err
+	}
+	jobs = append(existing, jobs...)
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding pending jobs: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.Path), 0755); err != nil {
+		return fmt.Errorf("error creating %s: %w", filepath.Dir(p.Path), err)
+	}
+	tmp := p.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("error writing pending jobs: %w", err)
+	}
+	return os.Rename(tmp, p.Path)
+}
+
+// intFromEnv reads a positive integer variable
+func intFromEnv(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+		slog.Warn("ignoring invalid setting", "variable", key, "value", value)
+	}
+	return fallback
+}
+
+// Queue is one tenant's view of a pool
+type Queue struct {
+	pool   *Pool
+	tenant string
+}
+
+// Handle registers the handler for a kind of job
+func (q *Queue) Handle(kind string, handler Handler) {
+	q.pool.mu.Lock()
+	defer q.pool.mu.Unlock()
+	q.pool.handlers[q.tenant+"/"+kind] = handler
+}
+
+// Submit queues a job for kind. The payload is stored as JSON so the job can be
+// saved at shutdown; ctx only contributes its correlation ID.
+func (q *Queue) Submit(ctx context.Context, kind string, payload interface{}) error {
+	return q.pool.submit(ctx, q.tenant, kind, payload)
+}
+
+// OnDrain registers fn to run when the pool starts draining
+func (q *Queue) OnDrain(fn func()) {
+	q.pool.OnDrain(fn)
+}
+
+// Stats returns the depth of the pool the queue submits to
+func (q *Queue) Stats() Stats {
+	return q.pool.Stats()
+}
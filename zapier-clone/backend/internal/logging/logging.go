@@ -0,0 +1,118 @@
+// backend/internal/logging/logging.go
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// HeaderRequestID carries the correlation ID on inbound and outbound requests
+const HeaderRequestID = "X-Request-ID"
+
+// KeyRequestID is the attribute the correlation ID is logged under
+const KeyRequestID = "request_id"
+
+type requestIDKey struct{}
+
+type loggerKey struct{}
+
+// Setup installs the process-wide logger. LOG_FORMAT is "json" (default) or
+// "text" and LOG_LEVEL is debug, info (default), warn or error. Output from the
+// standard log package is routed through the same handler.
+func Setup(w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{Level: levelFromEnv()}
+
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "text") {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// levelFromEnv reads LOG_LEVEL
+func levelFromEnv() slog.Level {
+	var level slog.Level
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			slog.Warn("ignoring invalid LOG_LEVEL", "value", value)
+			return slog.LevelInfo
+		}
+	}
+	return level
+}
+
+// WithRequestID returns a context carrying a correlation ID, and a logger that
+// tags every record with it
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return WithLogger(ctx, FromContext(ctx).With(KeyRequestID, id))
+}
+
+// RequestID returns the correlation ID carried by a context, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithLogger returns a context carrying a logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the context's logger, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// Detach returns a background context with the same correlation ID and logger,
+// for work that outlives the request that started it
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if id := RequestID(ctx); id != "" {
+		detached = context.WithValue(detached, requestIDKey{}, id)
+	}
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		detached = WithLogger(detached, logger)
+	}
+	return detached
+}
+
+// NewContext starts a correlation ID for work that no request started, such as
+// a poll or a scheduled report
+func NewContext(ctx context.Context) context.Context {
+	return WithRequestID(ctx, NewRequestID())
+}
+
+// Propagate sets X-Request-ID on an outbound request from its context, so the
+// call can be traced back to the webhook or API request that caused it
+func Propagate(req *http.Request) {
+	if id := RequestID(req.Context()); id != "" && req.Header.Get(HeaderRequestID) == "" {
+		req.Header.Set(HeaderRequestID, id)
+	}
+}
+
+// NewRequestID returns a random 16-character hex ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000")
+	}
+	return hex.EncodeToString(b)
+}
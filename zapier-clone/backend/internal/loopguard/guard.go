@@ -0,0 +1,198 @@
+// backend/internal/loopguard/guard.go
+package loopguard
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Origins of sync updates
+const (
+	OriginServiceNow = "servicenow"
+	OriginJira       = "jira"
+)
+
+// update is one inbound change seen for an entity
+type update struct {
+	Origin    string
+	Signature string
+	At        time.Time
+}
+
+// Detection records a loop that was broken by the guard
+type Detection struct {
+	Entity       string    `json:"entity"`
+	Origins      []string  `json:"origins"`
+	Fields       []string  `json:"fields"`
+	Updates      int       `json:"updates"`
+	DetectedAt   time.Time `json:"detected_at"`
+	BlockedUntil time.Time `json:"blocked_until"`
+}
+
+// Stats summarises guard activity for metrics and the admin API
+type Stats struct {
+	Checked        int64       `json:"checked"`
+	Blocked        int64       `json:"blocked"`
+	LoopsDetected  int64       `json:"loops_detected"`
+	ActiveBlocks   int         `json:"active_blocks"`
+	RecentDetected []Detection `json:"recent_detections"`
+}
+
+// Guard tracks update chains per entity and breaks Jira↔ServiceNow ping-pong cycles.
+// An entity is considered looping when the same field set keeps arriving from more
+// than one origin within Window; further updates are dropped until Cooldown passes.
+type Guard struct {
+	// Window is how far back updates count towards a chain
+	Window time.Duration
+	// MaxRepeats is how many updates with the same field set trigger a loop
+	MaxRepeats int
+	// Cooldown is how long an entity stays blocked once a loop is detected
+	Cooldown time.Duration
+	// MaxRecent is how many detections are kept for reporting
+	MaxRecent int
+
+	mutex   sync.Mutex
+	chains  map[string][]update
+	blocked map[string]time.Time
+	recent  []Detection
+	stats   Stats
+	now     func() time.Time
+}
+
+// NewGuard creates a loop guard with default thresholds
+func NewGuard() *Guard {
+	return &Guard{
+		Window:     2 * time.Minute,
+		MaxRepeats: 4,
+		Cooldown:   10 * time.Minute,
+		MaxRecent:  50,
+		chains:     make(map[string][]update),
+		blocked:    make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+// Allow records an inbound update for an entity and reports whether it should be synced.
+// entity should identify the record on both sides (e.g. the ServiceNow sys_id).
+func (g *Guard) Allow(entity, origin string, fields []string) bool {
+	if g == nil || entity == "" {
+		return true
+	}
+
+	now := g.now()
+	signature := fieldSignature(fields)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.stats.Checked++
+
+	if until, ok := g.blocked[entity]; ok {
+		if now.Before(until) {
+			g.stats.Blocked++
+			return false
+		}
+		delete(g.blocked, entity)
+		delete(g.chains, entity)
+	}
+
+	// Drop updates that fell out of the window
+	chain := g.chains[entity]
+	cutoff := now.Add(-g.Window)
+	kept := chain[:0]
+	for _, u := range chain {
+		if u.At.After(cutoff) {
+			kept = append(kept, u)
+		}
+	}
+	chain = append(kept, update{Origin: origin, Signature: signature, At: now})
+	g.chains[entity] = chain
+
+	repeats := 0
+	origins := make(map[string]bool)
+	for _, u := range chain {
+		if u.Signature == signature {
+			repeats++
+			origins[u.Origin] = true
+		}
+	}
+
+	if repeats < g.MaxRepeats || len(origins) < 2 {
+		return true
+	}
+
+	// Loop detected - break the cycle
+	detection := Detection{
+		Entity:       entity,
+		Fields:       sortedFields(fields),
+		Updates:      repeats,
+		DetectedAt:   now,
+		BlockedUntil: now.Add(g.Cooldown),
+	}
+	for o := range origins {
+		detection.Origins = append(detection.Origins, o)
+	}
+	sort.Strings(detection.Origins)
+
+	g.blocked[entity] = detection.BlockedUntil
+	delete(g.chains, entity)
+	g.stats.LoopsDetected++
+	g.stats.Blocked++
+
+	g.recent = append(g.recent, detection)
+	if len(g.recent) > g.MaxRecent {
+		g.recent = g.recent[len(g.recent)-g.MaxRecent:]
+	}
+
+	slog.Warn("sync loop detected; blocking", "entity", entity, "origins", strings.Join(detection.Origins, ", "), "fields", strings.Join(detection.Fields, ", "), "blocked_until", detection.BlockedUntil.Format(time.RFC3339))
+
+	return false
+}
+
+// Stats returns a snapshot of guard activity
+func (g *Guard) Stats() Stats {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := g.now()
+	stats := g.stats
+	stats.ActiveBlocks = 0
+	for _, until := range g.blocked {
+		if now.Before(until) {
+			stats.ActiveBlocks++
+		}
+	}
+
+	stats.RecentDetected = make([]Detection, len(g.recent))
+	copy(stats.RecentDetected, g.recent)
+
+	return stats
+}
+
+// Reset clears a block for an entity, e.g. after an operator fixed the cause
+func (g *Guard) Reset(entity string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	delete(g.blocked, entity)
+	delete(g.chains, entity)
+}
+
+// fieldSignature hashes a field set independent of order
+func fieldSignature(fields []string) string {
+	sum := sha1.Sum([]byte(strings.Join(sortedFields(fields), "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// sortedFields returns a sorted copy of fields
+func sortedFields(fields []string) []string {
+	sorted := make([]string, len(fields))
+	copy(sorted, fields)
+	sort.Strings(sorted)
+	return sorted
+}
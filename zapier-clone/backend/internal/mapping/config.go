@@ -0,0 +1,164 @@
+// backend/internal/mapping/config.go
+package mapping
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultConfig []byte
+
+// Config holds the field maps for each ServiceNow table
+type Config struct {
+	Tables map[string]*TableMap `json:"tables" yaml:"tables"`
+}
+
+// TableMap maps one ServiceNow table's records to Jira fields
+type TableMap struct {
+	// Defaults are Jira field values used when no rule sets the field
+	Defaults map[string]interface{} `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	Fields   []FieldRule            `json:"fields" yaml:"fields"`
+}
+
+// FieldRule produces one Jira field. The value comes from From (a dotted record
+// path), Template ("{field}" or "{field|transform:arg}" placeholders) or Value
+// (a constant), then passes through Transforms in order. Default is used when the
+// result is empty.
+type FieldRule struct {
+	To         string      `json:"to" yaml:"to"`
+	From       string      `json:"from,omitempty" yaml:"from,omitempty"`
+	Template   string      `json:"template,omitempty" yaml:"template,omitempty"`
+	Value      interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+	Transforms []Transform `json:"transforms,omitempty" yaml:"transforms,omitempty"`
+	Default    interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// Transform names a transformer and its arguments
+type Transform struct {
+	Name string `json:"name" yaml:"name"`
+	// Arg is the transformer's argument, e.g. a date layout or number format
+	Arg string `json:"arg,omitempty" yaml:"arg,omitempty"`
+	// Table and Default configure the lookup transformer
+	Table   map[string]string `json:"table,omitempty" yaml:"table,omitempty"`
+	Default string            `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// Parse reads a YAML or JSON config
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	// JSON is valid YAML, so one decoder handles both formats
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing field mapping config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// LoadFile reads a config file
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading field mapping config: %w", err)
+	}
+	return Parse(data)
+}
+
+// Default returns the built-in config, which matches the handlers' original
+// hard-coded mappings
+func Default() *Config {
+	cfg, err := Parse(defaultConfig)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// Validate checks that every rule has a target and a source and that every
+// transformer exists
+func (c *Config) Validate() error {
+	for table, tm := range c.Tables {
+		if tm == nil {
+			return fmt.Errorf("table %s: empty field map", table)
+		}
+		for i, rule := range tm.Fields {
+			if rule.To == "" {
+				return fmt.Errorf("table %s, rule %d: 'to' is required", table, i+1)
+			}
+			if rule.From == "" && rule.Template == "" && rule.Value == nil && rule.Default == nil {
+				return fmt.Errorf("table %s, field %s: one of from, template, value or default is required", table, rule.To)
+			}
+			for _, t := range rule.Transforms {
+				if _, ok := Transformers[t.Name]; !ok {
+					return fmt.Errorf("table %s, field %s: unknown transformer %q", table, rule.To, t.Name)
+				}
+			}
+			if err := validateTemplate(rule.Template); err != nil {
+				return fmt.Errorf("table %s, field %s: %w", table, rule.To, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Merge overlays other's tables on c, replacing whole tables
+func (c *Config) Merge(other *Config) *Config {
+	merged := &Config{Tables: make(map[string]*TableMap, len(c.Tables)+len(other.Tables))}
+	for table, tm := range c.Tables {
+		merged.Tables[table] = tm
+	}
+	for table, tm := range other.Tables {
+		merged.Tables[table] = tm
+	}
+	return merged
+}
+
+// SetDefault sets a default field value for a mapped table, such as the Jira
+// project its tickets go to. Rules that set the field still win; tables without
+// a field map are ignored.
+func (c *Config) SetDefault(table, field string, value interface{}) {
+	tm, ok := c.Tables[table]
+	if !ok {
+		return
+	}
+	defaults := make(map[string]interface{}, len(tm.Defaults)+1)
+	for k, v := range tm.Defaults {
+		defaults[k] = v
+	}
+	defaults[field] = value
+	c.Tables[table] = &TableMap{Defaults: defaults, Fields: tm.Fields}
+}
+
+// LoadConfig builds the config for a data directory: the built-in maps,
+// overlaid by FIELD_MAPPING_FILE when set, then by field_mapping.yaml (or
+// .json) in dataDir when present
+func LoadConfig(dataDir string) (*Config, error) {
+	cfg := Default()
+
+	paths := []string{os.Getenv("FIELD_MAPPING_FILE")}
+	for _, name := range []string{"field_mapping.yaml", "field_mapping.json"} {
+		paths = append(paths, filepath.Join(dataDir, name))
+	}
+
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); i > 0 && os.IsNotExist(err) {
+			continue
+		}
+		override, err := LoadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		cfg = cfg.Merge(override)
+	}
+
+	return cfg, nil
+}
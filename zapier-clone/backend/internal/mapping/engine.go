@@ -0,0 +1,263 @@
+// backend/internal/mapping/engine.go
+package mapping
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+)
+
+// placeholder matches {field} and {field|transform:arg|transform} in templates
+var placeholder = regexp.MustCompile(`\{([^{}|]+)((?:\|[^{}|]+)*)\}`)
+
+// Engine maps ServiceNow records to Jira fields with a Config
+type Engine struct {
+	Config *Config
+}
+
+// NewEngine creates a mapping engine
+func NewEngine(cfg *Config) *Engine {
+	return &Engine{Config: cfg}
+}
+
+// Map produces the Jira fields for a record of the given table
+func (e *Engine) Map(table string, record map[string]interface{}) (map[string]interface{}, error) {
+	tm, ok := e.Config.Tables[table]
+	if !ok {
+		return nil, fmt.Errorf("no field mapping configured for table %s", table)
+	}
+
+	fields := make(map[string]interface{}, len(tm.Defaults)+len(tm.Fields))
+	for field, value := range tm.Defaults {
+		fields[field] = value
+	}
+
+	for _, rule := range tm.Fields {
+		value, err := e.resolve(rule, record)
+		if err != nil {
+			return nil, fmt.Errorf("error mapping %s.%s: %w", table, rule.To, err)
+		}
+		if isEmpty(value) {
+			value = rule.Default
+		}
+		if isEmpty(value) {
+			continue
+		}
+
+		// Several rules can add labels and components
+		if rule.To == "labels" || rule.To == "components" {
+			fields[rule.To] = appendValues(fields[rule.To], value)
+			continue
+		}
+		fields[rule.To] = value
+	}
+
+	return fields, nil
+}
+
+// Ticket maps a record straight to a Jira ticket
+func (e *Engine) Ticket(table string, record map[string]interface{}) (*jira.Ticket, error) {
+	fields, err := e.Map(table, record)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := &jira.Ticket{}
+	ApplyFields(ticket, fields)
+	return ticket, nil
+}
+
+// resolve computes a rule's value before defaults
+func (e *Engine) resolve(rule FieldRule, record map[string]interface{}) (interface{}, error) {
+	var value interface{}
+	switch {
+	case rule.Template != "":
+		rendered, err := renderTemplate(rule.Template, record)
+		if err != nil {
+			return nil, err
+		}
+		value = rendered
+	case rule.From != "":
+		value, _ = lookupField(record, rule.From)
+	default:
+		value = rule.Value
+	}
+
+	return ApplyTransforms(value, rule.Transforms)
+}
+
+// renderTemplate replaces placeholders with record values
+func renderTemplate(template string, record map[string]interface{}) (string, error) {
+	var renderErr error
+	rendered := placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		parts := placeholder.FindStringSubmatch(match)
+		value, _ := lookupField(record, strings.TrimSpace(parts[1]))
+
+		for _, t := range parsePipes(parts[2]) {
+			transformed, err := Transformers[t.Name](value, t)
+			if err != nil && renderErr == nil {
+				renderErr = fmt.Errorf("%s in %s: %w", t.Name, match, err)
+			}
+			value = transformed
+		}
+		return toString(value)
+	})
+	return rendered, renderErr
+}
+
+// parsePipes reads "|name:arg|name" into transforms
+func parsePipes(pipes string) []Transform {
+	var transforms []Transform
+	for _, pipe := range strings.Split(pipes, "|") {
+		pipe = strings.TrimSpace(pipe)
+		if pipe == "" {
+			continue
+		}
+		name, arg := pipe, ""
+		if i := strings.Index(pipe, ":"); i >= 0 {
+			name, arg = pipe[:i], pipe[i+1:]
+		}
+		transforms = append(transforms, Transform{Name: strings.TrimSpace(name), Arg: arg})
+	}
+	return transforms
+}
+
+// validateTemplate checks that a template only uses known transformers; a
+// lookup needs its table inline
+func validateTemplate(template string) error {
+	for _, parts := range placeholder.FindAllStringSubmatch(template, -1) {
+		for _, t := range parsePipes(parts[2]) {
+			if _, ok := Transformers[t.Name]; !ok || (t.Name == "lookup" && t.Arg == "") {
+				return fmt.Errorf("transformer %q cannot be used in templates", t.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyFields sets mapped fields on a ticket. Fields the Ticket type does not
+// model (custom fields) are passed through to the Jira API as-is.
+func ApplyFields(ticket *jira.Ticket, fields map[string]interface{}) {
+	for field, value := range fields {
+		switch field {
+		case "project":
+			ticket.Project = toString(value)
+		case "issuetype":
+			ticket.IssueType = toString(value)
+		case "summary":
+			ticket.Summary = toString(value)
+		case "description":
+			ticket.Description = toString(value)
+		case "priority":
+			ticket.Priority = toString(value)
+		case "assignee":
+			ticket.Assignee = toString(value)
+		case "parent":
+			ticket.Parent = toString(value)
+		case "duedate":
+			if due, ok := ParseTime(value); ok {
+				ticket.DueDate = due
+			}
+		case "labels":
+			ticket.Labels = toStrings(value)
+		case "components":
+			ticket.Components = toStrings(value)
+		case "epic_name":
+			if ticket.Epic == nil {
+				ticket.Epic = &jira.EpicDetails{}
+			}
+			ticket.Epic.Name = toString(value)
+		default:
+			if ticket.Fields == nil {
+				ticket.Fields = make(map[string]interface{})
+			}
+			ticket.Fields[field] = value
+		}
+	}
+}
+
+// Record converts a typed record (e.g. servicenow.Risk) to the map form rules read
+func Record(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding record: %w", err)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("error decoding record: %w", err)
+	}
+	return record, nil
+}
+
+// lookupField resolves a dotted path such as "assigned_to.display_value"
+func lookupField(data map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = data
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// appendValues adds one label or component or a list of them, skipping
+// blanks and repeats
+func appendValues(existing, value interface{}) []string {
+	values := toStrings(existing)
+	for _, v := range toStrings(value) {
+		if strings.TrimSpace(v) != "" && !containsValue(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// toStrings reads a string or list as a string slice
+func toStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []string:
+		return append([]string(nil), v...)
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			list = append(list, toString(item))
+		}
+		return list
+	default:
+		return []string{toString(v)}
+	}
+}
+
+// isEmpty reports whether a mapped value should fall back to the default
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,301 @@
+// backend/internal/mapping/transform.go
+package mapping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TransformFunc converts a value using a transform's arguments
+type TransformFunc func(value interface{}, t Transform) (interface{}, error)
+
+// Transformers are the functions field rules, templates and workflow data
+// mappings can use. Add to it with RegisterTransformer.
+var Transformers = map[string]TransformFunc{
+	"uppercase": func(value interface{}, t Transform) (interface{}, error) {
+		return strings.ToUpper(toString(value)), nil
+	},
+	"lowercase": func(value interface{}, t Transform) (interface{}, error) {
+		return strings.ToLower(toString(value)), nil
+	},
+	"trim": func(value interface{}, t Transform) (interface{}, error) {
+		return strings.TrimSpace(toString(value)), nil
+	},
+	// date re-formats a timestamp with a Go layout (default 2006-01-02)
+	"date": func(value interface{}, t Transform) (interface{}, error) {
+		parsed, ok := ParseTime(value)
+		if !ok {
+			return "", nil
+		}
+		layout := t.Arg
+		if layout == "" {
+			layout = "2006-01-02"
+		}
+		return parsed.Format(layout), nil
+	},
+	// number formats a numeric value with a printf verb (default %g)
+	"number": func(value interface{}, t Transform) (interface{}, error) {
+		n, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		format := t.Arg
+		if format == "" {
+			format = "%g"
+		}
+		return fmt.Sprintf(format, n), nil
+	},
+	// lookup maps values through a table, matching case-insensitively; the
+	// table can also be given inline as the arg ("Critical=P1,High=P2")
+	"lookup": func(value interface{}, t Transform) (interface{}, error) {
+		table := t.Table
+		if table == nil {
+			table = parseTable(t.Arg)
+		}
+		return lookup(table, value, t.Default), nil
+	},
+	// label makes text a valid Jira label: lowercase, with runs of spaces
+	// and punctuation turned into single hyphens ("Data Privacy" is data-privacy)
+	"label": func(value interface{}, t Transform) (interface{}, error) {
+		var b strings.Builder
+		hyphen := false
+		for _, r := range strings.ToLower(strings.TrimSpace(toString(value))) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == ':' || r == '.' {
+				b.WriteRune(r)
+				hyphen = false
+			} else if !hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+				hyphen = true
+			}
+		}
+		return strings.TrimRight(b.String(), "-"), nil
+	},
+	// title turns a choice value into words: information_security is
+	// "Information Security"
+	"title": func(value interface{}, t Transform) (interface{}, error) {
+		words := strings.FieldsFunc(toString(value), func(r rune) bool { return r == '_' || unicode.IsSpace(r) })
+		for i, word := range words {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			words[i] = string(runes)
+		}
+		return strings.Join(words, " "), nil
+	},
+	// prefix and suffix add the arg to non-empty values
+	"prefix": func(value interface{}, t Transform) (interface{}, error) {
+		if s := toString(value); s != "" {
+			return t.Arg + s, nil
+		}
+		return "", nil
+	},
+	"suffix": func(value interface{}, t Transform) (interface{}, error) {
+		if s := toString(value); s != "" {
+			return s + t.Arg, nil
+		}
+		return "", nil
+	},
+	// concat joins a list's non-empty values with the arg (default ", ")
+	"concat": func(value interface{}, t Transform) (interface{}, error) {
+		separator := t.Arg
+		if separator == "" {
+			separator = ", "
+		}
+		var parts []string
+		for _, item := range toStrings(value) {
+			if item != "" {
+				parts = append(parts, item)
+			}
+		}
+		return strings.Join(parts, separator), nil
+	},
+	// replace swaps every "old" for "new" given as "old=>new"
+	"replace": func(value interface{}, t Transform) (interface{}, error) {
+		old, replacement, ok := strings.Cut(t.Arg, "=>")
+		if !ok || old == "" {
+			return nil, fmt.Errorf("replace needs an arg of the form old=>new, got %q", t.Arg)
+		}
+		return strings.ReplaceAll(toString(value), old, replacement), nil
+	},
+	// truncate shortens text to the arg's number of characters
+	"truncate": func(value interface{}, t Transform) (interface{}, error) {
+		limit, err := strconv.Atoi(t.Arg)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("truncate needs a positive length, got %q", t.Arg)
+		}
+		if runes := []rune(toString(value)); len(runes) > limit {
+			return string(runes[:limit]), nil
+		}
+		return toString(value), nil
+	},
+	// severity_to_priority maps ServiceNow severities, named or numbered, to
+	// Jira priorities; a table replaces the built-in map
+	"severity_to_priority": func(value interface{}, t Transform) (interface{}, error) {
+		table := t.Table
+		if table == nil {
+			table = parseTable(t.Arg)
+		}
+		if len(table) == 0 {
+			table = SeverityPriorities
+		}
+		fallback := t.Default
+		if fallback == "" {
+			fallback = "Medium"
+		}
+		return lookup(table, value, fallback), nil
+	},
+	// risk_score_to_priority maps a 0-100 risk score to a Jira priority with
+	// the thresholds of servicenow.RiskSeverity
+	"risk_score_to_priority": func(value interface{}, t Transform) (interface{}, error) {
+		score, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case score >= 80:
+			return "Highest", nil
+		case score >= 60:
+			return "High", nil
+		case score >= 40:
+			return "Medium", nil
+		default:
+			return "Low", nil
+		}
+	},
+}
+
+// SeverityPriorities is the default severity_to_priority map
+var SeverityPriorities = map[string]string{
+	"critical": "Highest", "1": "Highest",
+	"high": "High", "2": "High",
+	"medium": "Medium", "moderate": "Medium", "3": "Medium",
+	"low": "Low", "4": "Low",
+}
+
+// RegisterTransformer adds a transformer; call it at startup, before mappings are loaded
+func RegisterTransformer(name string, fn TransformFunc) error {
+	if name == "" || strings.ContainsAny(name, "|:{}") {
+		return fmt.Errorf("invalid transformer name %q", name)
+	}
+	if _, exists := Transformers[name]; exists {
+		return fmt.Errorf("transformer %q is already registered", name)
+	}
+	Transformers[name] = fn
+	return nil
+}
+
+// ParseTransforms reads a "name:arg|name" chain, the form templates and
+// workflow data mappings use
+func ParseTransforms(chain string) []Transform {
+	return parsePipes(chain)
+}
+
+// ApplyTransforms runs a value through transforms in order
+func ApplyTransforms(value interface{}, transforms []Transform) (interface{}, error) {
+	for _, t := range transforms {
+		fn, ok := Transformers[t.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown transformer %q", t.Name)
+		}
+		transformed, err := fn(value, t)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Name, err)
+		}
+		value = transformed
+	}
+	return value, nil
+}
+
+// lookup maps a value through a table, exactly first and then ignoring case;
+// unmatched values become fallback, or stay as they are without one
+func lookup(table map[string]string, value interface{}, fallback string) interface{} {
+	key := strings.TrimSpace(toString(value))
+	if mapped, ok := table[key]; ok {
+		return mapped
+	}
+	for from, to := range table {
+		if strings.EqualFold(from, key) {
+			return to
+		}
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return value
+}
+
+// parseTable reads an inline "from=to,from=to" lookup table
+func parseTable(arg string) map[string]string {
+	if arg == "" {
+		return nil
+	}
+	table := make(map[string]string)
+	for _, pair := range strings.Split(arg, ",") {
+		if from, to, ok := strings.Cut(pair, "="); ok {
+			table[strings.TrimSpace(from)] = strings.TrimSpace(to)
+		}
+	}
+	return table
+}
+
+// timeLayouts are the timestamp formats records arrive in
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"01/02/2006 15:04:05",
+	"01/02/2006",
+}
+
+// ParseTime reads a time.Time or a timestamp string; zero times are not valid
+func ParseTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case string:
+		for _, layout := range timeLayouts {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				return parsed, !parsed.IsZero()
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// toString renders a value for text fields
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// toFloat reads numbers that may arrive as strings (ServiceNow sends most values as strings)
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", v)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("%v is not a number", value)
+	}
+}
@@ -0,0 +1,37 @@
+// backend/internal/mappingstore/locks.go
+package mappingstore
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// lockShards is the number of mutexes keys are spread over
+const lockShards = 256
+
+// RecordLocks serializes work on one record, such as the check for an existing
+// Jira link and the create that follows it, so two webhooks for the same record
+// cannot both create an issue. Keys are spread over a fixed set of mutexes, so
+// unrelated records only wait on each other when they share a shard. A nil
+// RecordLocks does no locking.
+type RecordLocks struct {
+	shards [lockShards]sync.Mutex
+}
+
+// NewRecordLocks creates an empty lock set
+func NewRecordLocks() *RecordLocks {
+	return &RecordLocks{}
+}
+
+// Lock blocks until key is free and returns the function that frees it.
+// Never take a second key while holding one.
+func (l *RecordLocks) Lock(key string) (unlock func()) {
+	if l == nil {
+		return func() {}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	shard := &l.shards[h.Sum32()%lockShards]
+	shard.Lock()
+	return shard.Unlock
+}
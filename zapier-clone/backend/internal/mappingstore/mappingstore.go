@@ -0,0 +1,67 @@
+// backend/internal/mappingstore/mappingstore.go
+package mappingstore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+)
+
+// Backends selectable with MAPPING_STORE
+const (
+	BackendFile   = "file"
+	BackendSQLite = "sqlite"
+	BackendRedis  = "redis"
+)
+
+// Config selects and configures the risk↔Jira mapping backend
+type Config struct {
+	Backend string
+	// DataDir holds the JSON file for the file backend
+	DataDir     string
+	SQLitePath  string
+	RedisURL    string
+	RedisPrefix string
+}
+
+// ConfigFromEnv reads MAPPING_STORE and the backend settings. Defaults are scoped
+// to the tenant: the SQLite file lives in its data directory and Redis keys
+// carry its ID.
+func ConfigFromEnv(dataDir, tenantID string) Config {
+	prefix := os.Getenv("MAPPING_REDIS_PREFIX")
+	if prefix == "" {
+		prefix = "grc:mappings:"
+	}
+
+	return Config{
+		Backend:     getEnv("MAPPING_STORE", BackendFile),
+		DataDir:     dataDir,
+		SQLitePath:  getEnv("MAPPING_SQLITE_PATH", filepath.Join(dataDir, "mappings.db")),
+		RedisURL:    getEnv("REDIS_URL", "redis://localhost:6379/0"),
+		RedisPrefix: prefix + tenantID + ":",
+	}
+}
+
+// Open creates the configured mapping store
+func Open(cfg Config) (jira.MappingStore, error) {
+	switch cfg.Backend {
+	case BackendFile, "":
+		return jira.NewRiskJiraMapping(cfg.DataDir)
+	case BackendSQLite:
+		return NewSQLiteStore(cfg.SQLitePath)
+	case BackendRedis:
+		return NewRedisStore(cfg.RedisURL, cfg.RedisPrefix)
+	default:
+		return nil, fmt.Errorf("unknown mapping store %q: use file, sqlite or redis", cfg.Backend)
+	}
+}
+
+// getEnv returns an environment variable or the fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,146 @@
+// backend/internal/mappingstore/redis.go
+package mappingstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// addMappingScript updates both hashes atomically and drops links that the
+// new mapping replaces, so concurrent replicas never leave the indexes out of step
+var addMappingScript = redis.NewScript(`
+local oldKey = redis.call('HGET', KEYS[1], ARGV[1])
+if oldKey and oldKey ~= ARGV[2] then
+	redis.call('HDEL', KEYS[2], oldKey)
+end
+local oldRisk = redis.call('HGET', KEYS[2], ARGV[2])
+if oldRisk and oldRisk ~= ARGV[1] then
+	redis.call('HDEL', KEYS[1], oldRisk)
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('HSET', KEYS[2], ARGV[2], ARGV[1])
+return 1
+`)
+
+// removeMappingScript drops a risk from both hashes atomically
+var removeMappingScript = redis.NewScript(`
+local oldKey = redis.call('HGET', KEYS[1], ARGV[1])
+if oldKey then
+	redis.call('HDEL', KEYS[2], oldKey)
+end
+redis.call('HDEL', KEYS[1], ARGV[1])
+return 1
+`)
+
+// RedisStore keeps risk↔Jira links in two Redis hashes shared by every replica
+type RedisStore struct {
+	Client *redis.Client
+	// Prefix namespaces the hashes, e.g. per tenant
+	Prefix string
+	// Timeout bounds each Redis call
+	Timeout time.Duration
+}
+
+// NewRedisStore connects to the Redis server at url (redis://host:port/db)
+func NewRedisStore(url, prefix string) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+
+	store := &RedisStore{
+		Client:  redis.NewClient(options),
+		Prefix:  prefix,
+		Timeout: 5 * time.Second,
+	}
+
+	ctx, cancel := store.context()
+	defer cancel()
+	if err := store.Client.Ping(ctx).Err(); err != nil {
+		store.Client.Close()
+		return nil, fmt.Errorf("error connecting to Redis: %w", err)
+	}
+
+	return store, nil
+}
+
+func (s *RedisStore) riskKey() string { return s.Prefix + "risk_jira" }
+func (s *RedisStore) jiraKey() string { return s.Prefix + "jira_risk" }
+
+func (s *RedisStore) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.Timeout)
+}
+
+// AddMapping links a risk to a Jira issue, replacing any previous link of either
+func (s *RedisStore) AddMapping(riskID, jiraKey string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	if err := addMappingScript.Run(ctx, s.Client, []string{s.riskKey(), s.jiraKey()}, riskID, jiraKey).Err(); err != nil {
+		return fmt.Errorf("error saving mapping: %w", err)
+	}
+	return nil
+}
+
+// RemoveMapping drops a risk's link, if any
+func (s *RedisStore) RemoveMapping(riskID string) error {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	if err := removeMappingScript.Run(ctx, s.Client, []string{s.riskKey(), s.jiraKey()}, riskID).Err(); err != nil {
+		return fmt.Errorf("error removing mapping: %w", err)
+	}
+	return nil
+}
+
+// GetJiraKeyFromRiskID retrieves the Jira issue key for a risk ID
+func (s *RedisStore) GetJiraKeyFromRiskID(riskID string) (string, bool) {
+	return s.lookup(s.riskKey(), riskID)
+}
+
+// GetRiskIDFromJiraKey retrieves the risk ID for a Jira issue key
+func (s *RedisStore) GetRiskIDFromJiraKey(jiraKey string) (string, bool) {
+	return s.lookup(s.jiraKey(), jiraKey)
+}
+
+// lookup reads one hash field; Redis errors are logged and treated as no mapping
+func (s *RedisStore) lookup(hash, field string) (string, bool) {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	value, err := s.Client.HGet(ctx, hash, field).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error reading risk-jira mapping", "key", field, "error", err)
+		return "", false
+	}
+	return value, true
+}
+
+// Snapshot returns both indexes
+func (s *RedisStore) Snapshot() (map[string]string, map[string]string, error) {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	riskToJira, err := s.Client.HGetAll(ctx, s.riskKey()).Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing mappings: %w", err)
+	}
+	jiraToRisk, err := s.Client.HGetAll(ctx, s.jiraKey()).Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing mappings: %w", err)
+	}
+	return riskToJira, jiraToRisk, nil
+}
+
+// Close closes the Redis connection pool
+func (s *RedisStore) Close() error {
+	return s.Client.Close()
+}
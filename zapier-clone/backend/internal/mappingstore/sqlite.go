@@ -0,0 +1,126 @@
+// backend/internal/mappingstore/sqlite.go
+package mappingstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	// Pure Go driver so static release builds keep working
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore keeps risk↔Jira links in a SQLite database. Writes are
+// transactional, so several processes on one host can share the file.
+type SQLiteStore struct {
+	DB *sql.DB
+}
+
+// NewSQLiteStore opens (and creates if needed) the database at path
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory: %w", err)
+	}
+
+	// busy_timeout makes concurrent writers wait for the lock instead of failing
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path))
+	if err != nil {
+		return nil, fmt.Errorf("error opening mapping database: %w", err)
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS risk_jira_mappings (
+		risk_id    TEXT PRIMARY KEY,
+		jira_key   TEXT NOT NULL UNIQUE,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating mapping table: %w", err)
+	}
+
+	return &SQLiteStore{DB: db}, nil
+}
+
+// AddMapping links a risk to a Jira issue, dropping any earlier link of the issue
+func (s *SQLiteStore) AddMapping(riskID, jiraKey string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM risk_jira_mappings WHERE jira_key = ? AND risk_id <> ?`, jiraKey, riskID); err != nil {
+		return fmt.Errorf("error clearing previous mapping: %w", err)
+	}
+	_, err = tx.Exec(`INSERT INTO risk_jira_mappings (risk_id, jira_key) VALUES (?, ?)
+		ON CONFLICT (risk_id) DO UPDATE SET jira_key = excluded.jira_key, updated_at = CURRENT_TIMESTAMP`, riskID, jiraKey)
+	if err != nil {
+		return fmt.Errorf("error saving mapping: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing mapping: %w", err)
+	}
+	return nil
+}
+
+// RemoveMapping drops a risk's link, if any
+func (s *SQLiteStore) RemoveMapping(riskID string) error {
+	if _, err := s.DB.Exec(`DELETE FROM risk_jira_mappings WHERE risk_id = ?`, riskID); err != nil {
+		return fmt.Errorf("error removing mapping: %w", err)
+	}
+	return nil
+}
+
+// GetJiraKeyFromRiskID retrieves the Jira issue key for a risk ID
+func (s *SQLiteStore) GetJiraKeyFromRiskID(riskID string) (string, bool) {
+	return s.lookup(`SELECT jira_key FROM risk_jira_mappings WHERE risk_id = ?`, riskID)
+}
+
+// GetRiskIDFromJiraKey retrieves the risk ID for a Jira issue key
+func (s *SQLiteStore) GetRiskIDFromJiraKey(jiraKey string) (string, bool) {
+	return s.lookup(`SELECT risk_id FROM risk_jira_mappings WHERE jira_key = ?`, jiraKey)
+}
+
+// lookup reads one column; database errors are logged and treated as no mapping
+func (s *SQLiteStore) lookup(query, arg string) (string, bool) {
+	var value string
+	err := s.DB.QueryRow(query, arg).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false
+	}
+	if err != nil {
+		slog.Error("error reading risk-jira mapping", "key", arg, "error", err)
+		return "", false
+	}
+	return value, true
+}
+
+// Snapshot returns both indexes; rows are unique on both columns so they always agree
+func (s *SQLiteStore) Snapshot() (map[string]string, map[string]string, error) {
+	rows, err := s.DB.Query(`SELECT risk_id, jira_key FROM risk_jira_mappings`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing mappings: %w", err)
+	}
+	defer rows.Close()
+
+	riskToJira := make(map[string]string)
+	jiraToRisk := make(map[string]string)
+	for rows.Next() {
+		var riskID, jiraKey string
+		if err := rows.Scan(&riskID, &jiraKey); err != nil {
+			return nil, nil, fmt.Errorf("error scanning mapping: %w", err)
+		}
+		riskToJira[riskID] = jiraKey
+		jiraToRisk[jiraKey] = riskID
+	}
+	return riskToJira, jiraToRisk, rows.Err()
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.DB.Close()
+}
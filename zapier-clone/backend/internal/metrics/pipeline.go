@@ -0,0 +1,99 @@
+// backend/internal/metrics/pipeline.go
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// Integrations that report request metrics
+const (
+	IntegrationGitHub     = "github"
+	IntegrationJira       = "jira"
+	IntegrationServiceNow = "servicenow"
+	IntegrationSlack      = "slack"
+	IntegrationTeams      = "teams"
+)
+
+// Sync pipeline metrics. Label values are kept to small fixed sets (sources,
+// operations, tables, outcomes) so series counts stay bounded.
+var (
+	// WebhooksReceived counts inbound webhooks by source
+	WebhooksReceived = Default.NewCounterVec("zapier_webhooks_received_total",
+		"Inbound webhooks accepted, by source.", "source")
+
+	// SyncFailures counts events whose sync failed and was alerted
+	SyncFailures = Default.NewCounterVec("zapier_sync_failures_total",
+		"Events that failed to sync, by source.", "source")
+
+	// JiraIssues counts successful writes to Jira issues
+	JiraIssues = Default.NewCounterVec("zapier_jira_issues_total",
+		"Jira issue writes, by operation (created, updated, transitioned, commented).", "operation")
+
+	// ServiceNowUpdates counts successful writes to ServiceNow records
+	ServiceNowUpdates = Default.NewCounterVec("zapier_servicenow_updates_total",
+		"ServiceNow record writes, by table and operation (created, updated).", "table", "operation")
+
+	// SlackNotifications counts Slack posts by kind and whether they were delivered
+	SlackNotifications = Default.NewCounterVec("zapier_slack_notifications_total",
+		"Slack messages, by kind (message, reply, update) and status (sent, failed).", "kind", "status")
+
+	// Retries counts outbound calls that were sent again
+	Retries = Default.NewCounterVec("zapier_integration_retries_total",
+		"Outbound calls retried, by integration.", "integration")
+
+	// RequestDuration is the latency of outbound API calls
+	RequestDuration = Default.NewHistogramVec("zapier_integration_request_duration_seconds",
+		"Latency of outbound API calls, by integration and outcome (2xx, 4xx, 5xx, error).",
+		DefaultBuckets, "integration", "outcome")
+
+	// Jobs counts background sync jobs by kind and outcome
+	Jobs = Default.NewCounterVec("zapier_jobs_total",
+		"Background sync jobs, by kind and outcome (completed, failed, rejected, persisted).", "kind", "outcome")
+
+	// WebhookProcessing is the time from receiving a webhook to finishing its sync
+	WebhookProcessing = Default.NewHistogramVec("zapier_webhook_processing_seconds",
+		"Time from receiving a webhook to finishing its sync, by source and result (success, failure).",
+		DefaultBuckets, "source", "result")
+
+	// JiraMetadataCache counts lookups of cached Jira metadata
+	JiraMetadataCache = Default.NewCounterVec("zapier_jira_metadata_cache_total",
+		"Cached Jira metadata lookups, by kind (transitions, fields, projects) and result (hit, miss).", "kind", "result")
+
+	// EventBusMessages counts events handed to the external event bus
+	EventBusMessages = Default.NewCounterVec("zapier_event_bus_messages_total",
+		"Events for the external event bus, by outcome (published, failed, dropped).", "outcome")
+)
+
+// Outcome classifies a response for RequestDuration
+func Outcome(resp *http.Response, err error) string {
+	if err != nil || resp == nil {
+		return "error"
+	}
+	return strconv.Itoa(resp.StatusCode/100) + "xx"
+}
+
+// Result labels a sync as a success or failure
+func Result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+type integrationKey struct{}
+
+// WithIntegration tags a context with the integration its calls go to, so the
+// shared transport can attribute retries
+func WithIntegration(ctx context.Context, integration string) context.Context {
+	return context.WithValue(ctx, integrationKey{}, integration)
+}
+
+// Integration returns the integration a context was tagged with, or fallback
+func Integration(ctx context.Context, fallback string) string {
+	if integration, ok := ctx.Value(integrationKey{}).(string); ok {
+		return integration
+	}
+	return fallback
+}
@@ -0,0 +1,277 @@
+// backend/internal/monitoring/anomaly.go
+package monitoring
+
+import (
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// Anomaly kinds reported by the volume detector
+const (
+	AnomalyDrop  = "drop"
+	AnomalySpike = "spike"
+)
+
+// VolumeAnomaly describes an unusual hourly event count for a source
+type VolumeAnomaly struct {
+	Source   string    `json:"source"`
+	Kind     string    `json:"kind"`
+	Hour     time.Time `json:"hour"`
+	Count    int       `json:"count"`
+	Baseline float64   `json:"baseline"`
+	StdDev   float64   `json:"std_dev"`
+}
+
+// VolumeDetector baselines hourly webhook volume per source and alerts on drops and spikes
+type VolumeDetector struct {
+	SlackClient *slack.Client
+	Channel     string
+	// Routes gives the language of Channel; nil writes English
+	Routes *notification.Router
+
+	// BaselineHours is how many completed hours form the baseline
+	BaselineHours int
+	// MinBaseline is the average hourly volume below which drops to zero are ignored
+	MinBaseline float64
+	// SpikeStdDevs is how many standard deviations above the mean count as a spike
+	SpikeStdDevs float64
+	// SpikeMinCount is the smallest hourly count that can be reported as a spike
+	SpikeMinCount int
+
+	mutex    sync.Mutex
+	counts   map[string]map[int64]int
+	alerted  map[string]string
+	running  bool
+	stopChan chan struct{}
+	now      func() time.Time
+}
+
+// NewVolumeDetector creates a detector that posts findings to the ops channel
+func NewVolumeDetector(slackClient *slack.Client) *VolumeDetector {
+	return &VolumeDetector{
+		SlackClient:   slackClient,
+		Channel:       slack.ChannelMapping["ops"],
+		BaselineHours: 24,
+		MinBaseline:   2,
+		SpikeStdDevs:  4,
+		SpikeMinCount: 50,
+		counts:        make(map[string]map[int64]int),
+		alerted:       make(map[string]string),
+		stopChan:      make(chan struct{}),
+		now:           time.Now,
+	}
+}
+
+// Record counts one inbound event for the given source (e.g. a ServiceNow table name)
+func (d *VolumeDetector) Record(source string) {
+	if d == nil {
+		return
+	}
+
+	hour := d.now().Truncate(time.Hour).Unix()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.counts[source] == nil {
+		d.counts[source] = make(map[int64]int)
+	}
+	d.counts[source][hour]++
+}
+
+// Start begins evaluating each completed hour
+func (d *VolumeDetector) Start() {
+	if d.running {
+		return
+	}
+
+	d.running = true
+	go d.run()
+}
+
+// Stop stops the detector
+func (d *VolumeDetector) Stop() {
+	if !d.running {
+		return
+	}
+
+	d.running = false
+	close(d.stopChan)
+}
+
+// run wakes up shortly after every hour boundary and evaluates the previous hour
+func (d *VolumeDetector) run() {
+	for {
+		now := d.now()
+		next := now.Truncate(time.Hour).Add(time.Hour + time.Minute)
+
+		select {
+		case <-d.stopChan:
+			return
+		case <-time.After(next.Sub(now)):
+			for _, anomaly := range d.Evaluate(next.Truncate(time.Hour).Add(-time.Hour)) {
+				d.alert(anomaly)
+			}
+		}
+	}
+}
+
+// Evaluate compares the given hour against the preceding baseline window for every source
+func (d *VolumeDetector) Evaluate(hour time.Time) []VolumeAnomaly {
+	hour = hour.Truncate(time.Hour)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var anomalies []VolumeAnomaly
+	for source, buckets := range d.counts {
+		// Need a full baseline window before judging the source
+		if !d.hasHistory(buckets, hour) {
+			continue
+		}
+
+		var samples []float64
+		for i := 1; i <= d.BaselineHours; i++ {
+			samples = append(samples, float64(buckets[hour.Add(-time.Duration(i)*time.Hour).Unix()]))
+		}
+
+		mean, stddev := meanStdDev(samples)
+		count := buckets[hour.Unix()]
+
+		anomaly := VolumeAnomaly{
+			Source:   source,
+			Hour:     hour,
+			Count:    count,
+			Baseline: mean,
+			StdDev:   stddev,
+		}
+
+		switch {
+		case count == 0 && mean >= d.MinBaseline:
+			anomaly.Kind = AnomalyDrop
+		case count >= d.SpikeMinCount && float64(count) > mean+d.SpikeStdDevs*math.Max(stddev, 1):
+			anomaly.Kind = AnomalySpike
+		default:
+			delete(d.alerted, source)
+			continue
+		}
+
+		// Only report a drop once until the source recovers
+		if anomaly.Kind == AnomalyDrop && d.alerted[source] == AnomalyDrop {
+			continue
+		}
+		d.alerted[source] = anomaly.Kind
+
+		anomalies = append(anomalies, anomaly)
+	}
+
+	d.prune(hour)
+
+	sort.Slice(anomalies, func(i, j int) bool { return anomalies[i].Source < anomalies[j].Source })
+	return anomalies
+}
+
+// hasHistory reports whether the source has events older than the baseline window start
+func (d *VolumeDetector) hasHistory(buckets map[int64]int, hour time.Time) bool {
+	windowStart := hour.Add(-time.Duration(d.BaselineHours) * time.Hour).Unix()
+	for bucket := range buckets {
+		if bucket <= windowStart {
+			return true
+		}
+	}
+	return false
+}
+
+// prune drops buckets that can no longer be part of a baseline window
+func (d *VolumeDetector) prune(hour time.Time) {
+	cutoff := hour.Add(-time.Duration(d.BaselineHours+1) * time.Hour).Unix()
+	for _, buckets := range d.counts {
+		// Keep one bucket before the window so hasHistory stays true
+		oldest := int64(0)
+		for bucket := range buckets {
+			if bucket < cutoff && bucket > oldest {
+				oldest = bucket
+			}
+		}
+		for bucket := range buckets {
+			if bucket < cutoff && bucket != oldest {
+				delete(buckets, bucket)
+			}
+		}
+	}
+}
+
+// alert posts an anomaly to the ops channel
+func (d *VolumeDetector) alert(anomaly VolumeAnomaly) {
+	locale := d.Routes.Locale(d.Channel)
+	var headline, hint string
+	switch anomaly.Kind {
+	case AnomalyDrop:
+		headline = i18n.T(locale, "anomaly.drop.headline", anomaly.Source)
+		hint = i18n.T(locale, "anomaly.drop.hint")
+	case AnomalySpike:
+		headline = i18n.T(locale, "anomaly.spike.headline", anomaly.Source)
+		hint = i18n.T(locale, "anomaly.spike.hint")
+	}
+
+	message := slack.Message{
+		Blocks: []slack.Block{
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", headline, false),
+			},
+			{
+				Type: "section",
+				Fields: []*slack.TextObject{
+					slack.NewTextObject("mrkdwn", i18n.T(locale, "anomaly.field.hour", i18n.DateTime(locale, anomaly.Hour)), false),
+					slack.NewTextObject("mrkdwn", i18n.T(locale, "anomaly.field.events", anomaly.Count), false),
+					slack.NewTextObject("mrkdwn", i18n.T(locale, "anomaly.field.baseline", anomaly.Baseline), false),
+					slack.NewTextObject("mrkdwn", i18n.T(locale, "anomaly.field.std_dev", anomaly.StdDev), false),
+				},
+			},
+			{
+				Type: "context",
+				Elements: []interface{}{
+					map[string]interface{}{
+						"type": "mrkdwn",
+						"text": hint,
+					},
+				},
+			},
+		},
+	}
+
+	slog.Warn("event volume anomaly", "source", anomaly.Source, "kind", anomaly.Kind, "count", anomaly.Count, "baseline", anomaly.Baseline)
+
+	if _, err := d.SlackClient.PostMessage(d.Channel, message); err != nil {
+		slog.Warn("error posting volume anomaly to Slack", "error", err)
+	}
+}
+
+// meanStdDev returns the mean and population standard deviation of samples
+func meanStdDev(samples []float64) (float64, float64) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, s := range samples {
+		sum += s
+	}
+	mean := sum / float64(len(samples))
+
+	var variance float64
+	for _, s := range samples {
+		variance += (s - mean) * (s - mean)
+	}
+	variance /= float64(len(samples))
+
+	return mean, math.Sqrt(variance)
+}
@@ -0,0 +1,201 @@
+// backend/internal/monitoring/deadletters.go
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Dead letter states
+const (
+	DeadLetterPending  = "pending"
+	DeadLetterReplayed = "replayed"
+)
+
+// DeadLetter is an inbound webhook that failed to sync, kept with its full
+// payload so it can be replayed once the cause is fixed
+type DeadLetter struct {
+	ID         string          `json:"id"`
+	Source     string          `json:"source"`
+	EventType  string          `json:"event_type"`
+	RecordID   string          `json:"record_id,omitempty"`
+	Error      string          `json:"error"`
+	Payload    json.RawMessage `json:"payload"`
+	Status     string          `json:"status"`
+	Attempts   int             `json:"attempts"`
+	FailedAt   time.Time       `json:"failed_at"`
+	ReplayedAt *time.Time      `json:"replayed_at,omitempty"`
+}
+
+// DeadLetterStore persists dead letters to a JSON file so they survive restarts
+type DeadLetterStore struct {
+	// MaxStored bounds how many dead letters are kept; the oldest replayed
+	// ones are dropped first
+	MaxStored int
+
+	mutex    sync.Mutex
+	letters  []*DeadLetter
+	sequence int64
+	filePath string
+	now      func() time.Time
+}
+
+// NewDeadLetterStore loads the dead letters kept in storagePath
+func NewDeadLetterStore(storagePath string) (*DeadLetterStore, error) {
+	store := &DeadLetterStore{
+		MaxStored: 1000,
+		filePath:  filepath.Join(storagePath, "dead_letters.json"),
+		now:       time.Now,
+	}
+
+	if _, err := os.Stat(store.filePath); err == nil {
+		file, err := os.ReadFile(store.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading dead letter file: %w", err)
+		}
+
+		if err := json.Unmarshal(file, &store.letters); err != nil {
+			return nil, fmt.Errorf("error unmarshaling dead letters: %w", err)
+		}
+		// Keep IDs unique across restarts
+		store.sequence = int64(len(store.letters))
+	}
+
+	return store, nil
+}
+
+// Add stores a failed event. payload may be raw JSON or any JSON-encodable event.
+func (s *DeadLetterStore) Add(source, eventType, recordID string, payload interface{}, syncErr error) (*DeadLetter, error) {
+	if s == nil || syncErr == nil {
+		return nil, nil
+	}
+
+	raw, ok := payload.(json.RawMessage)
+	if !ok {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling dead letter payload: %w", err)
+		}
+		raw = data
+	}
+
+	letter := &DeadLetter{
+		Source:    source,
+		EventType: eventType,
+		RecordID:  recordID,
+		Error:     syncErr.Error(),
+		Payload:   raw,
+		Status:    DeadLetterPending,
+		FailedAt:  s.now(),
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sequence++
+	letter.ID = fmt.Sprintf("dl-%s-%d", letter.FailedAt.Format("20060102150405"), s.sequence)
+	s.letters = append(s.letters, letter)
+	s.trim()
+
+	return letter, s.save()
+}
+
+// Get returns a copy of a dead letter by ID
+func (s *DeadLetterStore) Get(id string) (DeadLetter, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if letter := s.find(id); letter != nil {
+		return *letter, true
+	}
+	return DeadLetter{}, false
+}
+
+// List returns copies of the dead letters with the given status (all when
+// empty), newest first
+func (s *DeadLetterStore) List(status string) []DeadLetter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	list := make([]DeadLetter, 0, len(s.letters))
+	for i := len(s.letters) - 1; i >= 0; i-- {
+		if status == "" || s.letters[i].Status == status {
+			list = append(list, *s.letters[i])
+		}
+	}
+	return list
+}
+
+// RecordReplay records the outcome of a replay: success marks the dead letter
+// replayed, failure keeps it pending with the latest error
+func (s *DeadLetterStore) RecordReplay(id string, replayErr error) (DeadLetter, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	letter := s.find(id)
+	if letter == nil {
+		return DeadLetter{}, fmt.Errorf("dead letter %s not found", id)
+	}
+
+	letter.Attempts++
+	if replayErr != nil {
+		letter.Error = replayErr.Error()
+	} else {
+		replayedAt := s.now()
+		letter.Status = DeadLetterReplayed
+		letter.ReplayedAt = &replayedAt
+	}
+
+	return *letter, s.save()
+}
+
+// find returns the stored dead letter with the given ID; callers hold the mutex
+func (s *DeadLetterStore) find(id string) *DeadLetter {
+	for _, letter := range s.letters {
+		if letter.ID == id {
+			return letter
+		}
+	}
+	return nil
+}
+
+// trim drops replayed dead letters, oldest first, then pending ones once the
+// store is over MaxStored; callers hold the mutex
+func (s *DeadLetterStore) trim() {
+	if s.MaxStored <= 0 || len(s.letters) <= s.MaxStored {
+		return
+	}
+
+	excess := len(s.letters) - s.MaxStored
+	kept := s.letters[:0]
+	for _, letter := range s.letters {
+		if excess > 0 && letter.Status == DeadLetterReplayed {
+			excess--
+			continue
+		}
+		kept = append(kept, letter)
+	}
+	s.letters = kept[excess:]
+}
+
+// save persists the dead letters to disk; callers hold the mutex
+func (s *DeadLetterStore) save() error {
+	data, err := json.MarshalIndent(s.letters, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling dead letters: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.filePath, data, 0600); err != nil {
+		return fmt.Errorf("error writing dead letter file: %w", err)
+	}
+
+	return nil
+}
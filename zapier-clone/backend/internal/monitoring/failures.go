@@ -0,0 +1,300 @@
+// backend/internal/monitoring/failures.go
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// SyncFailure is an event that could not be synced, kept for triage
+type SyncFailure struct {
+	ID         string                 `json:"id"`
+	Source     string                 `json:"source"`
+	EventType  string                 `json:"event_type"`
+	RecordID   string                 `json:"record_id,omitempty"`
+	Error      string                 `json:"error"`
+	Payload    map[string]interface{} `json:"payload"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// redactedValue replaces secrets and personal data in previews
+const redactedValue = "[redacted]"
+
+// sensitiveKeys are field name fragments whose values never reach Slack
+var sensitiveKeys = []string{
+	"password", "passwd", "secret", "token", "api_key", "apikey", "authorization",
+	"credential", "private_key", "session", "cookie", "signature",
+	"email", "phone", "mobile", "address", "ssn", "social_security", "birth", "dob",
+	"salary", "bank", "iban", "card",
+	"first_name", "last_name", "full_name", "user_name", "caller",
+}
+
+// sensitiveValues catch personal data in fields with innocent names
+var sensitiveValues = []*regexp.Regexp{
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),       // email addresses
+	regexp.MustCompile(`\+\d[\d\s().-]{7,}\d`),                                 // international phone numbers
+	regexp.MustCompile(`\(?\b\d{3}\)?[\s.-]\d{3}[\s.-]\d{4}\b`),                // national phone numbers
+	regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),                             // card and account numbers
+	regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]{8,}`),        // auth headers
+	regexp.MustCompile(`\b(xox[abprs]-|ghp_|sk_live_|AKIA)[A-Za-z0-9-]{8,}\b`), // well-known token formats
+}
+
+// FailureAlerter posts sync failures to the ops channel with a sanitized
+// payload preview, and keeps the full events for the admin API
+type FailureAlerter struct {
+	SlackClient *slack.Client
+	Channel     string
+	// AdminBaseURL prefixes the deep link to the full event
+	AdminBaseURL string
+	// Tenant is added to deep links so they resolve to the right tenant
+	Tenant string
+	// PreviewFields is how many payload fields the alert shows
+	PreviewFields int
+	// PreviewValueLength truncates each previewed value
+	PreviewValueLength int
+	// MaxStored bounds how many failures are kept in memory
+	MaxStored int
+
+	mutex    sync.Mutex
+	failures []*SyncFailure
+	sequence int64
+	now      func() time.Time
+}
+
+// NewFailureAlerter creates an alerter that posts to the ops channel
+func NewFailureAlerter(slackClient *slack.Client) *FailureAlerter {
+	return &FailureAlerter{
+		SlackClient:        slackClient,
+		Channel:            slack.ChannelMapping["ops"],
+		AdminBaseURL:       strings.TrimSuffix(getEnv("ADMIN_BASE_URL", "http://localhost:8081"), "/"),
+		PreviewFields:      8,
+		PreviewValueLength: 80,
+		MaxStored:          200,
+		now:                time.Now,
+	}
+}
+
+// Report records a failed event and alerts the ops channel. payload may be a map
+// or any JSON-encodable event.
+func (a *FailureAlerter) Report(source, eventType, recordID string, payload interface{}, syncErr error) *SyncFailure {
+	if a == nil || syncErr == nil {
+		return nil
+	}
+
+	failure := &SyncFailure{
+		Source:     source,
+		EventType:  eventType,
+		RecordID:   recordID,
+		Error:      syncErr.Error(),
+		Payload:    payloadMap(payload),
+		OccurredAt: a.now(),
+	}
+
+	a.mutex.Lock()
+	a.sequence++
+	failure.ID = fmt.Sprintf("%s-%d", failure.OccurredAt.Format("20060102150405"), a.sequence)
+	a.failures = append(a.failures, failure)
+	if len(a.failures) > a.MaxStored {
+		a.failures = a.failures[len(a.failures)-a.MaxStored:]
+	}
+	a.mutex.Unlock()
+
+	slog.Error("recorded sync failure", "failure", failure.ID, "source", source, "event", eventType, "error", syncErr)
+	a.alert(failure)
+	return failure
+}
+
+// Get returns a stored failure by ID
+func (a *FailureAlerter) Get(id string) (*SyncFailure, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, failure := range a.failures {
+		if failure.ID == id {
+			return failure, true
+		}
+	}
+	return nil, false
+}
+
+// List returns stored failures, newest first
+func (a *FailureAlerter) List() []*SyncFailure {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	list := make([]*SyncFailure, len(a.failures))
+	for i, failure := range a.failures {
+		list[len(a.failures)-1-i] = failure
+	}
+	return list
+}
+
+// Link returns the admin API URL of a stored failure
+func (a *FailureAlerter) Link(failure *SyncFailure) string {
+	link := fmt.Sprintf("%s/api/admin/events/failures/%s", a.AdminBaseURL, url.PathEscape(failure.ID))
+	if a.Tenant != "" {
+		link += "?tenant=" + url.QueryEscape(a.Tenant)
+	}
+	return link
+}
+
+// alert posts a failure with its sanitized preview
+func (a *FailureAlerter) alert(failure *SyncFailure) {
+	headline := fmt.Sprintf("🚨 Sync failed for *%s* %s", failure.Source, failure.EventType)
+	if failure.RecordID != "" {
+		headline += fmt.Sprintf(" `%s`", failure.RecordID)
+	}
+
+	preview := Preview(recordFields(failure.Payload), a.PreviewFields, a.PreviewValueLength)
+	if preview == "" {
+		preview = "_empty payload_"
+	}
+
+	message := slack.Message{
+		Blocks: []slack.Block{
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", headline, false),
+			},
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Error:*\n%s", truncate(sanitizeValue(failure.Error), 300)), false),
+			},
+			{
+				Type: "section",
+				Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Payload preview:*\n```%s```", preview), false),
+			},
+			{
+				Type: "context",
+				Elements: []interface{}{
+					map[string]interface{}{
+						"type": "mrkdwn",
+						"text": fmt.Sprintf("Secrets and personal data are redacted. <%s|Full event %s>", a.Link(failure), failure.ID),
+					},
+				},
+			},
+		},
+	}
+
+	if _, err := a.SlackClient.PostMessage(a.Channel, message); err != nil {
+		slog.Warn("error posting sync failure to Slack", "error", err)
+	}
+}
+
+// Preview renders the first maxFields top-level fields (sorted by name) as
+// "field: value" lines with secrets and personal data redacted
+func Preview(payload map[string]interface{}, maxFields, maxValueLength int) string {
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		if len(lines) == maxFields {
+			lines = append(lines, fmt.Sprintf("… %d more fields", len(keys)-maxFields))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", key, previewValue(key, payload[key], maxValueLength)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// previewValue redacts and shortens one field
+func previewValue(key string, value interface{}, maxLength int) string {
+	if sensitiveKey(key) {
+		return redactedValue
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return fmt.Sprintf("{%d fields}", len(v))
+	case []interface{}:
+		return fmt.Sprintf("[%d items]", len(v))
+	case string:
+		return truncate(sanitizeValue(v), maxLength)
+	default:
+		return truncate(sanitizeValue(fmt.Sprint(v)), maxLength)
+	}
+}
+
+// sensitiveKey reports whether a field name suggests a secret or personal data
+func sensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, fragment := range sensitiveKeys {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeValue masks personal data and credentials embedded in free text
+func sanitizeValue(value string) string {
+	for _, pattern := range sensitiveValues {
+		value = pattern.ReplaceAllString(value, redactedValue)
+	}
+	// Backticks would close the preview's code block
+	return strings.ReplaceAll(value, "`", "'")
+}
+
+// truncate shortens a value to maxLength runes
+func truncate(value string, maxLength int) string {
+	runes := []rune(value)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return value
+	}
+	return string(runes[:maxLength]) + "…"
+}
+
+// recordFields picks the record inside a webhook envelope (ServiceNow "data",
+// Jira "issue.fields") so the preview shows record fields rather than wrappers
+func recordFields(payload map[string]interface{}) map[string]interface{} {
+	if data, ok := payload["data"].(map[string]interface{}); ok {
+		return data
+	}
+	if issue, ok := payload["issue"].(map[string]interface{}); ok {
+		if fields, ok := issue["fields"].(map[string]interface{}); ok {
+			return fields
+		}
+	}
+	return payload
+}
+
+// payloadMap converts an event to a generic map for storage and previews
+func payloadMap(payload interface{}) map[string]interface{} {
+	if m, ok := payload.(map[string]interface{}); ok {
+		return m
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return map[string]interface{}{"unencodable": err.Error()}
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return map[string]interface{}{"value": string(data)}
+	}
+	return m
+}
+
+// getEnv returns an environment variable or the fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
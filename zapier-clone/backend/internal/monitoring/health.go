@@ -0,0 +1,194 @@
+// backend/internal/monitoring/health.go
+package monitoring
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/eventbus"
+	"github.com/shivani-1505/zapier-clone/backend/internal/syncevents"
+)
+
+// healthWindow is how far back error rates look
+const healthWindow = time.Hour
+
+// IntegrationHealth is how syncs with one integration have been going
+type IntegrationHealth struct {
+	Integration string     `json:"integration"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	// Successes, Failures and ErrorRate cover the last hour
+	Successes int     `json:"successes"`
+	Failures  int     `json:"failures"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// integrationState is what SyncHealth keeps per integration
+type integrationState struct {
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
+	// minutes counts outcomes by the Unix minute they happened in
+	minutes map[int64]*outcomeCounts
+}
+
+type outcomeCounts struct {
+	successes int
+	failures  int
+}
+
+// SyncHealth follows the outcome of every sync, Jira write and Slack post on
+// a tenant's event bus. It only knows what happened since the process started.
+type SyncHealth struct {
+	mutex        sync.Mutex
+	integrations map[string]*integrationState
+	now          func() time.Time
+}
+
+// NewSyncHealth creates a tracker that lists the given integrations even
+// before anything was synced with them
+func NewSyncHealth(integrations ...string) *SyncHealth {
+	h := &SyncHealth{
+		integrations: make(map[string]*integrationState),
+		now:          time.Now,
+	}
+	for _, name := range integrations {
+		h.integrations[name] = &integrationState{minutes: make(map[int64]*outcomeCounts)}
+	}
+	return h
+}
+
+// Watch records the outcomes published on a bus until stop is called
+func (h *SyncHealth) Watch(bus *eventbus.Bus) (stop func()) {
+	events, _, cancel := bus.Subscribe(eventbus.Filter{}, eventbus.DefaultQueue)
+	go func() {
+		for message := range events {
+			h.observe(message)
+		}
+	}()
+	return cancel
+}
+
+// observe classifies a bus message. Webhooks received and skipped syncs are
+// neither a success nor a failure.
+func (h *SyncHealth) observe(message eventbus.Message) {
+	switch {
+	case message.Type == eventbus.TypeWebhookReceived, message.Type == eventbus.TypeSyncSkipped:
+	case message.Type == eventbus.TypeSyncFailed:
+		event, _ := message.Data.(syncevents.Event)
+		h.Record(message.Source, failure(event.Error), message.Time)
+	case message.Type == eventbus.TypeNotificationFailed:
+		data, _ := message.Data.(map[string]string)
+		h.Record(message.Source, failure(data["error"]), message.Time)
+	case strings.HasPrefix(message.Type, "ticket."), message.Type == eventbus.TypeNotificationSent:
+		h.Record(message.Source, "", message.Time)
+	default:
+		// A synced ServiceNow or Jira record, e.g. risk.created
+		if _, ok := message.Data.(syncevents.Event); ok {
+			h.Record(message.Source, "", message.Time)
+		}
+	}
+}
+
+// failure keeps a failure without a message from counting as a success
+func failure(message string) string {
+	if message == "" {
+		return "failed"
+	}
+	return message
+}
+
+// Record counts one outcome for an integration; a non-empty syncErr is a failure
+func (h *SyncHealth) Record(integration, syncErr string, at time.Time) {
+	if at.IsZero() {
+		at = h.now()
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state := h.integrations[integration]
+	if state == nil {
+		state = &integrationState{minutes: make(map[int64]*outcomeCounts)}
+		h.integrations[integration] = state
+	}
+	minute := at.Unix() / 60
+	counts := state.minutes[minute]
+	if counts == nil {
+		counts = &outcomeCounts{}
+		state.minutes[minute] = counts
+	}
+
+	if syncErr != "" {
+		counts.failures++
+		if at.After(state.lastFailure) {
+			state.lastFailure, state.lastError = at, syncErr
+		}
+	} else {
+		counts.successes++
+		if at.After(state.lastSuccess) {
+			state.lastSuccess = at
+		}
+	}
+	h.prune(state)
+}
+
+// prune drops minutes older than the window. Callers hold the mutex.
+func (h *SyncHealth) prune(state *integrationState) {
+	oldest := h.now().Add(-healthWindow).Unix() / 60
+	for minute := range state.minutes {
+		if minute < oldest {
+			delete(state.minutes, minute)
+		}
+	}
+}
+
+// Integrations returns every integration listed or seen so far, by name
+func (h *SyncHealth) Integrations() []IntegrationHealth {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	oldest := h.now().Add(-healthWindow).Unix() / 60
+	list := make([]IntegrationHealth, 0, len(h.integrations))
+	for name, state := range h.integrations {
+		health := IntegrationHealth{Integration: name, LastError: state.lastError}
+		if !state.lastSuccess.IsZero() {
+			at := state.lastSuccess.UTC()
+			health.LastSuccess = &at
+		}
+		if !state.lastFailure.IsZero() {
+			at := state.lastFailure.UTC()
+			health.LastFailure = &at
+		}
+		for minute, counts := range state.minutes {
+			if minute >= oldest {
+				health.Successes += counts.successes
+				health.Failures += counts.failures
+			}
+		}
+		health.ErrorRate = errorRate(health.Successes, health.Failures)
+		list = append(list, health)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Integration < list[j].Integration })
+	return list
+}
+
+// errorRate is the share of outcomes that failed, 0 when there were none
+func errorRate(successes, failures int) float64 {
+	if successes+failures == 0 {
+		return 0
+	}
+	return float64(failures) / float64(successes+failures)
+}
+
+// ErrorRate returns the share of all outcomes in the last hour that failed
+func (h *SyncHealth) ErrorRate() (successes, failures int, rate float64) {
+	for _, health := range h.Integrations() {
+		successes += health.Successes
+		failures += health.Failures
+	}
+	return successes, failures, errorRate(successes, failures)
+}
@@ -0,0 +1,264 @@
+// backend/internal/notification/digest.go
+package notification
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// digestMaxLines caps the items listed in one digest message
+const digestMaxLines = 25
+
+// DefaultOverflowInterval is how often an OverflowOnly digest is posted
+const DefaultOverflowInterval = 15 * time.Minute
+
+// DigestEntry is a notification held for the next digest
+type DigestEntry struct {
+	Item     Item      `json:"item"`
+	QueuedAt time.Time `json:"queued_at"`
+	// Reason is why the item was held: its severity, the channel's quiet
+	// hours or its rate cap
+	Reason string `json:"reason"`
+}
+
+// Digest batches notifications of the configured severities into one summary
+// message per channel, posted every Interval. Everything else, criticals
+// included, is still posted right away, unless the router holds it back for a
+// channel's quiet hours or rate cap.
+type Digest struct {
+	SlackClient *slack.Client
+	Interval    time.Duration
+	// Severities are held for the digest; matching is case-insensitive
+	Severities []string
+	// Locale gives the language of a channel's digest, normally Router.Locale;
+	// nil writes English
+	Locale func(channel string) string
+	// Quiet reports the channels in their quiet hours, normally Router.Quiet;
+	// their digests wait until the hours are over
+	Quiet func(channel string) bool
+	// OverflowOnly holds no severities, only the items the router holds back
+	// for quiet hours and rate caps
+	OverflowOnly bool
+
+	mutex    sync.Mutex
+	pending  map[string][]DigestEntry
+	running  bool
+	stopChan chan struct{}
+	now      func() time.Time
+}
+
+// NewDigest creates a digest that holds low and medium severity notifications
+// once an Interval is set
+func NewDigest(slackClient *slack.Client) *Digest {
+	return &Digest{
+		SlackClient: slackClient,
+		Severities:  []string{"low", "medium"},
+		pending:     make(map[string][]DigestEntry),
+		stopChan:    make(chan struct{}),
+		now:         time.Now,
+	}
+}
+
+// ConfigureFromEnv reads SLACK_DIGEST_INTERVAL and SLACK_DIGEST_SEVERITIES and
+// reports whether the digest is enabled
+func (d *Digest) ConfigureFromEnv() bool {
+	if value := os.Getenv("SLACK_DIGEST_SEVERITIES"); value != "" {
+		d.Severities = nil
+		for _, severity := range strings.Split(value, ",") {
+			if severity = strings.TrimSpace(severity); severity != "" {
+				d.Severities = append(d.Severities, severity)
+			}
+		}
+	}
+
+	value := os.Getenv("SLACK_DIGEST_INTERVAL")
+	if value == "" || value == "0" {
+		return false
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		slog.Warn("ignoring invalid SLACK_DIGEST_INTERVAL", "value", value)
+		return false
+	}
+	d.Interval = interval
+	return true
+}
+
+// Queue holds an item for the channel's next digest. It reports false when the
+// item must be posted now: the digest is not running or the severity is not
+// digested.
+func (d *Digest) Queue(channel string, item Item) bool {
+	if d == nil || d.OverflowOnly || !matchesAny(d.Severities, item.Severity) || item.Severity == "" {
+		return false
+	}
+	return d.Overflow(channel, item, HoldSeverity)
+}
+
+// Overflow holds an item for the channel's next digest whatever its severity,
+// giving the reason it was held. It reports false when the digest is not
+// running, so the item must be posted now.
+func (d *Digest) Overflow(channel string, item Item, reason string) bool {
+	if d == nil {
+		return false
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if !d.running {
+		return false
+	}
+
+	d.pending[channel] = append(d.pending[channel], DigestEntry{Item: item, QueuedAt: d.now(), Reason: reason})
+	return true
+}
+
+// Pending returns copies of the held entries by channel
+func (d *Digest) Pending() map[string][]DigestEntry {
+	if d == nil {
+		return nil
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	pending := make(map[string][]DigestEntry, len(d.pending))
+	for channel, entries := range d.pending {
+		pending[channel] = append([]DigestEntry(nil), entries...)
+	}
+	return pending
+}
+
+// Start posts the digests every Interval until Stop
+func (d *Digest) Start() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.running || d.Interval <= 0 {
+		return
+	}
+	d.running = true
+
+	go func() {
+		ticker := time.NewTicker(d.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.stopChan:
+				return
+			case <-ticker.C:
+				d.flush(false)
+			}
+		}
+	}()
+}
+
+// Stop stops the schedule and posts what is still held, so a shutdown loses
+// nothing
+func (d *Digest) Stop() {
+	d.mutex.Lock()
+	if !d.running {
+		d.mutex.Unlock()
+		return
+	}
+	d.running = false
+	close(d.stopChan)
+	d.mutex.Unlock()
+
+	d.Flush()
+}
+
+// Flush posts one digest message per channel with held entries, quiet
+// channels included. Entries of a channel that could not be posted to are
+// held for the next digest.
+func (d *Digest) Flush() {
+	d.flush(true)
+}
+
+// flush posts the digests; unless all is set, channels in their quiet hours
+// keep their entries for a later digest
+func (d *Digest) flush(all bool) {
+	d.mutex.Lock()
+	pending := d.pending
+	d.pending = make(map[string][]DigestEntry)
+	for channel, entries := range pending {
+		if !all && d.Quiet != nil && d.Quiet(channel) {
+			d.pending[channel] = entries
+			delete(pending, channel)
+		}
+	}
+	d.mutex.Unlock()
+
+	for channel, entries := range pending {
+		if _, err := d.SlackClient.PostMessage(channel, d.message(d.locale(channel), entries)); err != nil {
+			d.SlackClient.Logger().Error("error posting Slack digest", "channel", channel, "items", len(entries), "error", err)
+			d.mutex.Lock()
+			d.pending[channel] = append(entries, d.pending[channel]...)
+			d.mutex.Unlock()
+		}
+	}
+}
+
+// locale returns the language of a channel's digest
+func (d *Digest) locale(channel string) string {
+	if d.Locale == nil {
+		return i18n.DefaultLocale
+	}
+	return d.Locale(channel)
+}
+
+// message lists the entries grouped by item type
+func (d *Digest) message(locale string, entries []DigestEntry) slack.Message {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Item.Type < entries[j].Item.Type })
+
+	var lines []string
+	for i, entry := range entries {
+		if i == digestMaxLines {
+			lines = append(lines, i18n.T(locale, "digest.more", len(entries)-digestMaxLines))
+			break
+		}
+		item := entry.Item
+		line := fmt.Sprintf("• *%s* %s", item.Number, item.Title)
+		if item.URL != "" {
+			line = fmt.Sprintf("• *<%s|%s>* %s", item.URL, item.Number, item.Title)
+		}
+		details := []string{i18n.T(locale, "item_type."+item.Type), i18n.Value(locale, "severity", item.Severity)}
+		if item.Category != "" {
+			details = append(details, item.Category)
+		}
+		lines = append(lines, fmt.Sprintf("%s _(%s)_", line, strings.Join(details, ", ")))
+	}
+
+	header := i18n.Plural(locale, "digest.header", len(entries), d.Interval)
+	message := slack.Message{
+		Text: header,
+		Blocks: []slack.Block{
+			{Type: "header", Text: slack.NewTextObject("plain_text", header, true)},
+			{Type: "section", Text: slack.NewTextObject("mrkdwn", strings.Join(lines, "\n"), false)},
+		},
+	}
+
+	// Say why items that would have been posted at once were held
+	var notes []string
+	for _, reason := range []string{HoldQuietHours, HoldRateLimit} {
+		for _, entry := range entries {
+			if entry.Reason == reason {
+				notes = append(notes, i18n.T(locale, "digest.held."+reason))
+				break
+			}
+		}
+	}
+	if len(notes) > 0 {
+		message.Blocks = append(message.Blocks, slack.Block{
+			Type:     "context",
+			Elements: []interface{}{map[string]interface{}{"type": "mrkdwn", "text": strings.Join(notes, " ")}},
+		})
+	}
+	return message
+}
@@ -0,0 +1,480 @@
+// backend/internal/notification/rules.go
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// Item types announced in Slack, with the ChannelMapping key each is posted
+// to when no rule matches
+var ItemTypes = map[string]string{
+	"risk":              "risk-management",
+	"incident":          "incident",
+	"compliance_task":   "compliance",
+	"control_test":      "control-testing",
+	"audit_finding":     "audit",
+	"vendor_risk":       "vendor-risk",
+	"regulatory_change": "regulatory",
+	// Records of tables registered through /api/v1/tables
+	"grc_item": "compliance",
+}
+
+// Platforms a rule can send to. Teams channels are passed to the Slack client
+// as "teams:<channel>", which forwards them to the Teams notifier.
+const (
+	PlatformSlack = "slack"
+	PlatformTeams = "teams"
+)
+
+// ErrRuleNotFound is returned for an unknown rule ID
+var ErrRuleNotFound = errors.New("notification rule not found")
+
+// Item describes a record about to be announced. Number, Title and URL are
+// only used to list it in a digest.
+type Item struct {
+	Type     string `json:"type"`
+	Severity string `json:"severity,omitempty"`
+	Category string `json:"category,omitempty"`
+	Number   string `json:"number,omitempty"`
+	Title    string `json:"title,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// Rule routes matching items to one or more channels. Empty severities or
+// categories match any value; matching is case-insensitive.
+type Rule struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	ItemType   string   `json:"item_type"`
+	Severities []string `json:"severities,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	// Platform is "slack" (the default) or "teams"
+	Platform string `json:"platform,omitempty"`
+	// Channels are Slack channel names or IDs, or ChannelMapping keys such as
+	// "ops"; for Teams, conversation IDs or TEAMS_CHANNELS keys
+	Channels []string `json:"channels"`
+	// QuietHours holds back items that are not urgent from the rule's
+	// channels during a daily window; they are posted in the digest after it
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+	// MaxPerHour caps the announcements posted to each of the rule's channels
+	// in any hour; items over the cap go to the digest. 0 means no cap.
+	MaxPerHour int `json:"max_per_hour,omitempty"`
+	// UrgentSeverities are posted during quiet hours and over the cap; empty
+	// means critical
+	UrgentSeverities []string `json:"urgent_severities,omitempty"`
+	// Locale is the language of messages sent to the rule's channels, such
+	// as "es"; empty leaves the channel's or the tenant's locale
+	Locale string `json:"locale,omitempty"`
+	// Disabled rules are kept but route nothing
+	Disabled  bool      `json:"disabled,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks that a rule can route anything
+func (r *Rule) Validate() error {
+	if _, ok := ItemTypes[r.ItemType]; !ok {
+		return fmt.Errorf("unknown item_type %q: use one of %s", r.ItemType, strings.Join(itemTypeNames(), ", "))
+	}
+	if r.Platform != "" && r.Platform != PlatformSlack && r.Platform != PlatformTeams {
+		return fmt.Errorf("unknown platform %q: use %s or %s", r.Platform, PlatformSlack, PlatformTeams)
+	}
+	if len(r.Channels) == 0 {
+		return fmt.Errorf("a rule needs at least one channel")
+	}
+	for _, channel := range r.Channels {
+		if strings.TrimSpace(channel) == "" {
+			return fmt.Errorf("channels must not be empty")
+		}
+	}
+	if r.QuietHours != nil {
+		if err := r.QuietHours.validate(); err != nil {
+			return err
+		}
+	}
+	if r.MaxPerHour < 0 {
+		return fmt.Errorf("max_per_hour must not be negative")
+	}
+	if r.Locale != "" {
+		locale := i18n.Normalize(r.Locale)
+		if locale == "" {
+			return fmt.Errorf("unknown locale %q: use one of %s", r.Locale, strings.Join(i18n.Supported(), ", "))
+		}
+		r.Locale = locale
+	}
+	return nil
+}
+
+// matches reports whether the rule routes item
+func (r *Rule) matches(item Item) bool {
+	return !r.Disabled && r.ItemType == item.Type &&
+		matchesAny(r.Severities, item.Severity) &&
+		matchesAny(r.Categories, item.Category)
+}
+
+// Router picks the Slack channels for new items from rules kept in a JSON
+// file. Changes through the API apply at once, and edits to the file are
+// picked up on the next lookup, so neither needs a restart.
+type Router struct {
+	// Digest holds low-severity items for a periodic summary; nil posts everything now
+	Digest *Digest
+	// DefaultPlatform receives items no rule matches; empty means Slack
+	DefaultPlatform string
+	// DefaultLocale is the tenant's language for notifications; empty means English
+	DefaultLocale string
+	// ChannelLocales sets the language of single channels, keyed by channel
+	// name, ID or ChannelMapping key; rule locales take precedence
+	ChannelLocales map[string]string
+
+	mutex    sync.RWMutex
+	rules    []*Rule
+	sequence int64
+	filePath string
+	modTime  time.Time
+	now      func() time.Time
+
+	// sent holds the times of the last hour's announcements by channel, for
+	// rate caps
+	sentMutex sync.Mutex
+	sent      map[string][]time.Time
+}
+
+// NewRouter loads the rules kept in storagePath
+func NewRouter(storagePath string) (*Router, error) {
+	router := &Router{
+		filePath: filepath.Join(storagePath, "notification_rules.json"),
+		now:      time.Now,
+		sent:     make(map[string][]time.Time),
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+	if err := router.load(); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+// Channels returns the channels an item is announced in: those of every
+// matching rule, or the item type's default channel when none match. A nil
+// router always returns the Slack default.
+func (r *Router) Channels(item Item) []string {
+	if r != nil {
+		r.reload()
+
+		r.mutex.RLock()
+		var channels []string
+		seen := make(map[string]bool)
+		for _, rule := range r.rules {
+			if !rule.matches(item) {
+				continue
+			}
+			for _, channel := range rule.Channels {
+				channel = rule.resolveChannel(channel)
+				if !seen[channel] {
+					seen[channel] = true
+					channels = append(channels, channel)
+				}
+			}
+		}
+		r.mutex.RUnlock()
+
+		if len(channels) > 0 {
+			return channels
+		}
+
+		// TEAMS_CHANNELS uses the same keys as ChannelMapping
+		if r.DefaultPlatform == PlatformTeams {
+			return []string{slack.PlatformChannel(PlatformTeams, ItemTypes[item.Type])}
+		}
+	}
+
+	return []string{slack.ChannelMapping[ItemTypes[item.Type]]}
+}
+
+// Hold queues an item for the channel's next digest and reports whether it
+// was held; items that are not held must be posted now. Items are held for
+// their severity, or when they are not urgent and the channel is in its quiet
+// hours or over its rate cap. Everything else is counted towards the cap.
+func (r *Router) Hold(channel string, item Item) bool {
+	if r == nil {
+		return false
+	}
+	if r.Digest.Queue(channel, item) {
+		return true
+	}
+
+	if reason := r.Throttle(channel, item); reason != "" && r.Digest.Overflow(channel, item, reason) {
+		slog.Info("holding notification for the digest", "channel", channel, "item_type", item.Type, "number", item.Number, "reason", reason)
+		return true
+	}
+	r.record(channel)
+	return false
+}
+
+// Locale returns the language messages to a channel are written in: that of
+// the first enabled rule sending to it with a locale, then the channel's own
+// setting, then the tenant default. A nil router returns English.
+func (r *Router) Locale(channel string) string {
+	if r == nil {
+		return i18n.DefaultLocale
+	}
+	r.reload()
+
+	r.mutex.RLock()
+	for _, rule := range r.rules {
+		if !rule.Disabled && rule.Locale != "" && rule.sendsTo(channel) {
+			r.mutex.RUnlock()
+			return rule.Locale
+		}
+	}
+	r.mutex.RUnlock()
+
+	for key, locale := range r.ChannelLocales {
+		key = strings.TrimPrefix(strings.TrimSpace(key), "#")
+		if key == channel || slack.ChannelMapping[key] == channel {
+			if normalized := i18n.Normalize(locale); normalized != "" {
+				return normalized
+			}
+		}
+	}
+	if locale := i18n.Normalize(r.DefaultLocale); locale != "" {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
+
+// List returns copies of all rules in creation order
+func (r *Router) List() []Rule {
+	r.reload()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rules := make([]Rule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, *rule)
+	}
+	return rules
+}
+
+// Get returns a copy of a rule
+func (r *Router) Get(id string) (Rule, bool) {
+	r.reload()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if rule := r.find(id); rule != nil {
+		return *rule, true
+	}
+	return Rule{}, false
+}
+
+// Create validates and stores a new rule, assigning its ID
+func (r *Router) Create(rule Rule) (Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	r.reload()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sequence++
+	rule.ID = fmt.Sprintf("rule-%d", r.sequence)
+	rule.CreatedAt = r.now()
+	rule.UpdatedAt = rule.CreatedAt
+	r.rules = append(r.rules, &rule)
+
+	return rule, r.save()
+}
+
+// Update replaces a rule's settings, keeping its ID and creation time
+func (r *Router) Update(id string, rule Rule) (Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	r.reload()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing := r.find(id)
+	if existing == nil {
+		return Rule{}, ErrRuleNotFound
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = r.now()
+	*existing = rule
+
+	return rule, r.save()
+}
+
+// Delete removes a rule
+func (r *Router) Delete(id string) error {
+	r.reload()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, rule := range r.rules {
+		if rule.ID == id {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return r.save()
+		}
+	}
+	return ErrRuleNotFound
+}
+
+// reload re-reads the rule file when it was changed outside the API
+func (r *Router) reload() {
+	info, err := os.Stat(r.filePath)
+	if err != nil {
+		return
+	}
+
+	r.mutex.RLock()
+	changed := !info.ModTime().Equal(r.modTime)
+	r.mutex.RUnlock()
+	if !changed {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.load(); err != nil {
+		slog.Warn("error reloading notification rules; keeping the previous rules", "error", err)
+	}
+}
+
+// load reads the rule file; callers hold the mutex
+func (r *Router) load() error {
+	info, err := os.Stat(r.filePath)
+	if err != nil {
+		return nil
+	}
+
+	file, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return fmt.Errorf("error reading notification rules: %w", err)
+	}
+
+	var rules []*Rule
+	if err := json.Unmarshal(file, &rules); err != nil {
+		r.modTime = info.ModTime()
+		return fmt.Errorf("error unmarshaling notification rules: %w", err)
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			r.modTime = info.ModTime()
+			return fmt.Errorf("notification rule %s: %w", rule.ID, err)
+		}
+	}
+
+	r.rules = rules
+	r.modTime = info.ModTime()
+	// Keep IDs unique across restarts and hand edits
+	for _, rule := range rules {
+		var n int64
+		if _, err := fmt.Sscanf(rule.ID, "rule-%d", &n); err == nil && n > r.sequence {
+			r.sequence = n
+		}
+	}
+	for _, rule := range rules {
+		if rule.ID == "" {
+			r.sequence++
+			rule.ID = fmt.Sprintf("rule-%d", r.sequence)
+		}
+	}
+	return nil
+}
+
+// save persists the rules to disk; callers hold the mutex
+func (r *Router) save() error {
+	data, err := json.MarshalIndent(r.rules, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling notification rules: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.filePath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing notification rules: %w", err)
+	}
+
+	// Our own write is not an outside change
+	if info, err := os.Stat(r.filePath); err == nil {
+		r.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// find returns the stored rule with the given ID; callers hold the mutex
+func (r *Router) find(id string) *Rule {
+	for _, rule := range r.rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+// resolveChannel maps a ChannelMapping key to its channel; anything else is
+// already a channel name or ID. Teams channels are resolved by the Teams client.
+func (r *Rule) resolveChannel(channel string) string {
+	channel = strings.TrimPrefix(strings.TrimSpace(channel), "#")
+	if r.Platform == PlatformTeams {
+		return slack.PlatformChannel(PlatformTeams, channel)
+	}
+	if mapped, ok := slack.ChannelMapping[channel]; ok {
+		return mapped
+	}
+	return channel
+}
+
+// sendsTo reports whether one of the rule's channels resolves to channel
+func (r *Rule) sendsTo(channel string) bool {
+	for _, target := range r.Channels {
+		if r.resolveChannel(target) == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func itemTypeNames() []string {
+	names := make([]string, 0, len(ItemTypes))
+	for name := range ItemTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,267 @@
+// backend/internal/notification/throttle.go
+package notification
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reasons an announcement is held for the digest instead of posted
+const (
+	HoldSeverity   = "severity"
+	HoldQuietHours = "quiet_hours"
+	HoldRateLimit  = "rate_limit"
+)
+
+// DefaultUrgentSeverities are posted during quiet hours and over a rate cap
+// when a rule names no urgent severities
+var DefaultUrgentSeverities = []string{"critical"}
+
+// QuietHours is a daily window in which only urgent items are posted to a
+// rule's channels; the rest wait for the channel's digest. A window whose end
+// is before its start runs past midnight, e.g. 22:00 to 07:00.
+type QuietHours struct {
+	// Start and End are "HH:MM" in TimeZone
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// TimeZone is an IANA name such as "Europe/Berlin"; empty means the
+	// server's local time
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// validate checks the window's times and time zone
+func (q *QuietHours) validate() error {
+	if _, err := clockMinutes(q.Start); err != nil {
+		return fmt.Errorf("quiet_hours start: %w", err)
+	}
+	if _, err := clockMinutes(q.End); err != nil {
+		return fmt.Errorf("quiet_hours end: %w", err)
+	}
+	if q.Start == q.End {
+		return fmt.Errorf("quiet_hours start and end must differ")
+	}
+	if _, err := q.location(); err != nil {
+		return fmt.Errorf("quiet_hours time_zone: %w", err)
+	}
+	return nil
+}
+
+// location returns the window's time zone
+func (q *QuietHours) location() (*time.Location, error) {
+	if q.TimeZone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(q.TimeZone)
+}
+
+// active reports whether now falls in the window
+func (q *QuietHours) active(now time.Time) bool {
+	start, err := clockMinutes(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := clockMinutes(q.End)
+	if err != nil {
+		return false
+	}
+	location, err := q.location()
+	if err != nil {
+		return false
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// clockMinutes parses "HH:MM" into minutes after midnight
+func clockMinutes(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ChannelStatus describes the quiet hours and rate cap of a channel
+type ChannelStatus struct {
+	Channel    string       `json:"channel"`
+	QuietHours []QuietHours `json:"quiet_hours,omitempty"`
+	// Quiet is true while one of the windows is open
+	Quiet      bool     `json:"quiet"`
+	MaxPerHour int      `json:"max_per_hour,omitempty"`
+	Urgent     []string `json:"urgent_severities"`
+	// SentLastHour counts the announcements posted in the last hour
+	SentLastHour int `json:"sent_last_hour"`
+	// Held counts the items waiting for the channel's digest
+	Held int `json:"held"`
+}
+
+// channelPolicy merges the throttling settings of the rules sending to one
+// channel: it is quiet when any of their windows is open, its cap is the
+// lowest one set, and an item urgent for any of them is urgent
+type channelPolicy struct {
+	quietHours []QuietHours
+	maxPerHour int
+	urgent     []string
+}
+
+// policy returns the throttling settings of a channel; callers hold the
+// mutex for reading
+func (r *Router) policy(channel string) channelPolicy {
+	var policy channelPolicy
+	for _, rule := range r.rules {
+		if rule.Disabled || (rule.QuietHours == nil && rule.MaxPerHour == 0) {
+			continue
+		}
+		if !rule.sendsTo(channel) {
+			continue
+		}
+		if rule.QuietHours != nil {
+			policy.quietHours = append(policy.quietHours, *rule.QuietHours)
+		}
+		if rule.MaxPerHour > 0 && (policy.maxPerHour == 0 || rule.MaxPerHour < policy.maxPerHour) {
+			policy.maxPerHour = rule.MaxPerHour
+		}
+		urgent := rule.UrgentSeverities
+		if len(urgent) == 0 {
+			urgent = DefaultUrgentSeverities
+		}
+		for _, severity := range urgent {
+			if len(policy.urgent) == 0 || !matchesAny(policy.urgent, severity) {
+				policy.urgent = append(policy.urgent, severity)
+			}
+		}
+	}
+	return policy
+}
+
+// quiet reports whether one of the policy's windows is open
+func (p channelPolicy) quiet(now time.Time) bool {
+	for _, window := range p.quietHours {
+		if window.active(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUrgent reports whether an item is posted whatever the quiet hours and cap
+func (p channelPolicy) isUrgent(item Item) bool {
+	for _, severity := range p.urgent {
+		if strings.EqualFold(strings.TrimSpace(severity), item.Severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// Quiet reports whether a channel is in its quiet hours, so its digest waits.
+// A nil router has no quiet hours.
+func (r *Router) Quiet(channel string) bool {
+	if r == nil {
+		return false
+	}
+	r.reload()
+
+	r.mutex.RLock()
+	policy := r.policy(channel)
+	r.mutex.RUnlock()
+	return policy.quiet(r.now())
+}
+
+// Throttle returns why an item must not be posted to a channel now, quiet
+// hours or the rate cap, or "" when it may be. A nil router throttles nothing.
+func (r *Router) Throttle(channel string, item Item) string {
+	if r == nil {
+		return ""
+	}
+	r.reload()
+
+	r.mutex.RLock()
+	policy := r.policy(channel)
+	r.mutex.RUnlock()
+	if policy.isUrgent(item) {
+		return ""
+	}
+
+	now := r.now()
+	if policy.quiet(now) {
+		return HoldQuietHours
+	}
+	if policy.maxPerHour > 0 && r.sentSince(channel, now.Add(-time.Hour)) >= policy.maxPerHour {
+		return HoldRateLimit
+	}
+	return ""
+}
+
+// record counts an announcement posted to a channel
+func (r *Router) record(channel string) {
+	now := r.now()
+
+	r.sentMutex.Lock()
+	defer r.sentMutex.Unlock()
+	sent := r.sent[channel]
+	// Only the last hour matters to a cap
+	for len(sent) > 0 && !sent[0].After(now.Add(-time.Hour)) {
+		sent = sent[1:]
+	}
+	r.sent[channel] = append(sent, now)
+}
+
+// sentSince counts the announcements posted to a channel after since
+func (r *Router) sentSince(channel string, since time.Time) int {
+	r.sentMutex.Lock()
+	defer r.sentMutex.Unlock()
+
+	count := 0
+	for _, at := range r.sent[channel] {
+		if at.After(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// ChannelStatuses describes every channel with quiet hours or a rate cap,
+// sorted by channel
+func (r *Router) ChannelStatuses() []ChannelStatus {
+	r.reload()
+
+	r.mutex.RLock()
+	channels := make(map[string]channelPolicy)
+	for _, rule := range r.rules {
+		if rule.Disabled || (rule.QuietHours == nil && rule.MaxPerHour == 0) {
+			continue
+		}
+		for _, channel := range rule.Channels {
+			channel = rule.resolveChannel(channel)
+			if _, seen := channels[channel]; !seen {
+				channels[channel] = r.policy(channel)
+			}
+		}
+	}
+	r.mutex.RUnlock()
+
+	pending := r.Digest.Pending()
+	now := r.now()
+	statuses := make([]ChannelStatus, 0, len(channels))
+	for channel, policy := range channels {
+		statuses = append(statuses, ChannelStatus{
+			Channel:      channel,
+			QuietHours:   policy.quietHours,
+			Quiet:        policy.quiet(now),
+			MaxPerHour:   policy.maxPerHour,
+			Urgent:       policy.urgent,
+			SentLastHour: r.sentSince(channel, now.Add(-time.Hour)),
+			Held:         len(pending[channel]),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Channel < statuses[j].Channel })
+	return statuses
+}
@@ -0,0 +1,394 @@
+// backend/internal/polling/poller.go
+package polling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// ErrRunning is returned when a source is asked to run while a poll is in progress
+var ErrRunning = errors.New("poll already in progress")
+
+// ErrUnknownSource is returned for a source name that was never registered
+var ErrUnknownSource = errors.New("unknown polling source")
+
+// Item is one record returned by a poll
+type Item struct {
+	ID        string                 `json:"id"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Source fetches the items changed since a watermark. Time-based sources query
+// from since.Time, which already includes the overlap; cursor-based sources read
+// since.Cursor and return the next cursor.
+type Source interface {
+	Poll(ctx context.Context, since Watermark) (items []Item, cursor string, err error)
+}
+
+// SourceFunc adapts a function to a Source
+type SourceFunc func(ctx context.Context, since Watermark) ([]Item, string, error)
+
+// Poll calls f
+func (f SourceFunc) Poll(ctx context.Context, since Watermark) ([]Item, string, error) {
+	return f(ctx, since)
+}
+
+// Handler processes one polled item
+type Handler func(ctx context.Context, item Item) error
+
+// Schedule controls how often and how carefully a source is polled
+type Schedule struct {
+	// Interval between polls
+	Interval time.Duration
+	// Jitter spreads each wait by up to this fraction of Interval either way, so
+	// sources on the same interval don't hit their APIs in lockstep
+	Jitter float64
+	// Overlap re-reads this much before the watermark, catching records committed
+	// late or stamped by a skewed clock; items already handled are skipped
+	Overlap time.Duration
+	// Timeout bounds one poll including its handlers
+	Timeout time.Duration
+}
+
+// DefaultSchedule is used for fields a registration leaves unset
+var DefaultSchedule = Schedule{
+	Interval: 5 * time.Minute,
+	Jitter:   0.1,
+	Overlap:  time.Minute,
+	Timeout:  2 * time.Minute,
+}
+
+// withDefaults fills unset fields from DefaultSchedule
+func (s Schedule) withDefaults() Schedule {
+	if s.Interval <= 0 {
+		s.Interval = DefaultSchedule.Interval
+	}
+	if s.Jitter < 0 || s.Jitter > 1 {
+		s.Jitter = DefaultSchedule.Jitter
+	}
+	if s.Overlap < 0 {
+		s.Overlap = 0
+	}
+	if s.Timeout <= 0 {
+		s.Timeout = DefaultSchedule.Timeout
+	}
+	return s
+}
+
+// Health is the state of one polling source
+type Health struct {
+	Source              string     `json:"source"`
+	Interval            string     `json:"interval"`
+	Healthy             bool       `json:"healthy"`
+	Running             bool       `json:"running"`
+	LastRun             *time.Time `json:"last_run,omitempty"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	ItemsLastRun        int        `json:"items_last_run"`
+	SkippedLastRun      int        `json:"skipped_last_run"`
+	ItemsTotal          int        `json:"items_total"`
+	NextRun             *time.Time `json:"next_run,omitempty"`
+	Watermark           Watermark  `json:"watermark"`
+}
+
+// registration is a source with its schedule, handler and run state
+type registration struct {
+	name     string
+	source   Source
+	handler  Handler
+	schedule Schedule
+
+	running bool
+	health  Health
+}
+
+// Poller runs registered sources on their schedules, persisting each source's
+// watermark so a restart resumes where it left off
+type Poller struct {
+	Store *WatermarkStore
+
+	mutex   sync.Mutex
+	sources map[string]*registration
+	rng     *rand.Rand
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+	now     func() time.Time
+}
+
+// NewPoller creates a poller that keeps watermarks in store
+func NewPoller(store *WatermarkStore) *Poller {
+	return &Poller{
+		Store:   store,
+		sources: make(map[string]*registration),
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		stop:    make(chan struct{}),
+		now:     time.Now,
+	}
+}
+
+// Register adds a source. Sources must be registered before Start.
+func (p *Poller) Register(name string, source Source, handler Handler, schedule Schedule) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	schedule = schedule.withDefaults()
+	p.sources[name] = &registration{
+		name:     name,
+		source:   source,
+		handler:  handler,
+		schedule: schedule,
+		health: Health{
+			Source:    name,
+			Interval:  schedule.Interval.String(),
+			Healthy:   true,
+			Watermark: p.Store.Get(name).Watermark,
+		},
+	}
+}
+
+// Start polls every registered source on its own schedule
+func (p *Poller) Start() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.started {
+		return
+	}
+	p.started = true
+
+	for _, reg := range p.sources {
+		p.wg.Add(1)
+		go p.loop(reg)
+	}
+}
+
+// Stop ends polling and waits for in-progress polls to finish
+func (p *Poller) Stop() {
+	p.mutex.Lock()
+	if !p.started {
+		p.mutex.Unlock()
+		return
+	}
+	p.started = false
+	close(p.stop)
+	p.mutex.Unlock()
+
+	p.wg.Wait()
+}
+
+// RunNow polls a source immediately, outside its schedule
+func (p *Poller) RunNow(ctx context.Context, name string) (Health, error) {
+	p.mutex.Lock()
+	reg, ok := p.sources[name]
+	p.mutex.Unlock()
+	if !ok {
+		return Health{}, fmt.Errorf("%w: %s", ErrUnknownSource, name)
+	}
+
+	err := p.run(ctx, reg)
+	return p.healthOf(reg), err
+}
+
+// Reset forgets a source's watermark so its next poll starts over
+func (p *Poller) Reset(name string) error {
+	p.mutex.Lock()
+	reg, ok := p.sources[name]
+	p.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSource, name)
+	}
+
+	if err := p.Store.Reset(name); err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	reg.health.Watermark = Watermark{}
+	p.mutex.Unlock()
+	return nil
+}
+
+// Health returns the state of every source, sorted by name
+func (p *Poller) Health() []Health {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	list := make([]Health, 0, len(p.sources))
+	for _, reg := range p.sources {
+		list = append(list, p.snapshot(reg))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Source < list[j].Source })
+	return list
+}
+
+// loop polls one source until Stop, starting after a jittered delay so sources
+// registered together don't all fire at startup
+func (p *Poller) loop(reg *registration) {
+	defer p.wg.Done()
+
+	wait := p.initialDelay(reg.schedule)
+	for {
+		p.setNextRun(reg, p.now().Add(wait))
+
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(wait):
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-p.stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		if err := p.run(ctx, reg); err != nil && !errors.Is(err, ErrRunning) {
+			slog.Error("polling failed", "source", reg.name, "error", err)
+		}
+		cancel()
+
+		wait = p.nextDelay(reg.schedule)
+	}
+}
+
+// initialDelay spreads a source's first poll over its jitter window
+func (p *Poller) initialDelay(schedule Schedule) time.Duration {
+	return time.Duration(schedule.Jitter * float64(schedule.Interval) * p.random())
+}
+
+// nextDelay is the interval moved by up to the jitter fraction either way
+func (p *Poller) nextDelay(schedule Schedule) time.Duration {
+	spread := schedule.Jitter * float64(schedule.Interval)
+	return schedule.Interval + time.Duration(spread*(2*p.random()-1))
+}
+
+// random returns a number in [0, 1)
+func (p *Poller) random() float64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.rng.Float64()
+}
+
+// run polls a source once, hands new items to its handler in update order and
+// advances the watermark past every item handled. A handler error stops the run
+// so the failed item and everything after it are fetched again next time.
+func (p *Poller) run(ctx context.Context, reg *registration) error {
+	p.mutex.Lock()
+	if reg.running {
+		p.mutex.Unlock()
+		return ErrRunning
+	}
+	reg.running = true
+	p.mutex.Unlock()
+
+	// Manual runs keep the admin request's correlation ID; scheduled runs get their own
+	if logging.RequestID(ctx) == "" {
+		ctx = logging.NewContext(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, reg.schedule.Timeout)
+	defer cancel()
+
+	state := p.Store.Get(reg.name)
+	since := state.Watermark
+	if !since.Time.IsZero() {
+		since.Time = since.Time.Add(-reg.schedule.Overlap)
+	}
+
+	started := p.now()
+	items, cursor, err := reg.source.Poll(ctx, since)
+
+	handled, skipped := 0, 0
+	if err == nil {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].UpdatedAt.Before(items[j].UpdatedAt) })
+
+		for _, item := range items {
+			if state.handled(item) {
+				skipped++
+				continue
+			}
+			if err = reg.handler(ctx, item); err != nil {
+				err = fmt.Errorf("error handling item %s: %w", item.ID, err)
+				break
+			}
+			state.markHandled(item)
+			handled++
+		}
+
+		// A cursor only moves on once the whole page is handled
+		if err == nil && cursor != "" {
+			state.Watermark.Cursor = cursor
+		}
+		state.prune(reg.schedule.Overlap)
+
+		if saveErr := p.Store.Set(reg.name, state); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	reg.running = false
+	health := &reg.health
+	health.LastRun = &started
+	health.ItemsLastRun = handled
+	health.SkippedLastRun = skipped
+	health.ItemsTotal += handled
+	health.Watermark = state.Watermark
+	if err != nil {
+		health.LastError = err.Error()
+		health.ConsecutiveFailures++
+	} else {
+		health.LastError = ""
+		health.ConsecutiveFailures = 0
+		finished := p.now()
+		health.LastSuccess = &finished
+	}
+
+	return err
+}
+
+// setNextRun records when a source will next be polled
+func (p *Poller) setNextRun(reg *registration, next time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	reg.health.NextRun = &next
+}
+
+// healthOf returns a source's current health
+func (p *Poller) healthOf(reg *registration) Health {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.snapshot(reg)
+}
+
+// snapshot copies a source's health, judging it unhealthy after a failed run or
+// when it has not succeeded for three intervals; callers hold the mutex
+func (p *Poller) snapshot(reg *registration) Health {
+	health := reg.health
+	health.Running = reg.running
+
+	stale := 3 * reg.schedule.Interval
+	switch {
+	case health.ConsecutiveFailures > 0:
+		health.Healthy = false
+	case health.LastSuccess != nil:
+		health.Healthy = p.now().Sub(*health.LastSuccess) <= stale
+	default:
+		health.Healthy = true
+	}
+	return health
+}
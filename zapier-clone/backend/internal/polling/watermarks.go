@@ -0,0 +1,131 @@
+// backend/internal/polling/watermarks.go
+package polling
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Watermark is how far a source has been read: the newest update handled and,
+// for cursor-based sources, the cursor to continue from
+type Watermark struct {
+	Time   time.Time `json:"time"`
+	Cursor string    `json:"cursor,omitempty"`
+}
+
+// State is what is persisted for a source between polls
+type State struct {
+	Watermark Watermark `json:"watermark"`
+	// Seen holds the update time of items handled within the overlap window,
+	// so re-read items are not handled twice
+	Seen map[string]time.Time `json:"seen,omitempty"`
+}
+
+// handled reports whether an item was already handled at this version
+func (s *State) handled(item Item) bool {
+	seen, ok := s.Seen[item.ID]
+	return ok && !item.UpdatedAt.After(seen)
+}
+
+// markHandled records an item and moves the watermark up to it
+func (s *State) markHandled(item Item) {
+	if s.Seen == nil {
+		s.Seen = make(map[string]time.Time)
+	}
+	s.Seen[item.ID] = item.UpdatedAt
+	if item.UpdatedAt.After(s.Watermark.Time) {
+		s.Watermark.Time = item.UpdatedAt
+	}
+}
+
+// prune forgets items that fall before the overlap window and can't be re-read
+func (s *State) prune(overlap time.Duration) {
+	cutoff := s.Watermark.Time.Add(-overlap)
+	for id, updatedAt := range s.Seen {
+		if updatedAt.Before(cutoff) {
+			delete(s.Seen, id)
+		}
+	}
+}
+
+// WatermarkStore persists each source's state to a JSON file
+type WatermarkStore struct {
+	mutex    sync.Mutex
+	states   map[string]State
+	filePath string
+}
+
+// NewWatermarkStore loads the watermarks kept in storagePath
+func NewWatermarkStore(storagePath string) (*WatermarkStore, error) {
+	store := &WatermarkStore{
+		states:   make(map[string]State),
+		filePath: filepath.Join(storagePath, "poll_watermarks.json"),
+	}
+
+	if _, err := os.Stat(store.filePath); err == nil {
+		file, err := os.ReadFile(store.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading watermark file: %w", err)
+		}
+
+		if err := json.Unmarshal(file, &store.states); err != nil {
+			return nil, fmt.Errorf("error unmarshaling watermarks: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// Get returns a copy of a source's state; a new source starts from the zero watermark
+func (s *WatermarkStore) Get(source string) State {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state := s.states[source]
+	seen := make(map[string]time.Time, len(state.Seen))
+	for id, updatedAt := range state.Seen {
+		seen[id] = updatedAt
+	}
+	state.Seen = seen
+	return state
+}
+
+// Set replaces a source's state and saves the store
+func (s *WatermarkStore) Set(source string, state State) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.states[source] = state
+	return s.save()
+}
+
+// Reset forgets a source's state so its next poll starts over
+func (s *WatermarkStore) Reset(source string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.states, source)
+	return s.save()
+}
+
+// save persists the store to disk; callers hold the mutex
+func (s *WatermarkStore) save() error {
+	data, err := json.MarshalIndent(s.states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling watermarks: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing watermark file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+// backend/internal/relay/agent.go
+package relay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// maxBodyBytes caps the size of a webhook accepted by the agent
+const maxBodyBytes = 1 << 20
+
+// Agent receives webhooks inside a restricted network, queues them on disk and
+// forwards them over outbound HTTPS to the backend's /api/relay/ingest endpoint.
+// Messages stay queued until the backend acknowledges them, so link loss only delays delivery.
+type Agent struct {
+	Name        string
+	UpstreamURL string
+	Secret      string
+	Queue       *Queue
+	HTTPClient  *http.Client
+
+	PollInterval time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewAgent creates a relay agent
+func NewAgent(name, upstreamURL, secret string, queue *Queue) *Agent {
+	return &Agent{
+		Name:        name,
+		UpstreamURL: strings.TrimRight(upstreamURL, "/"),
+		Secret:      secret,
+		Queue:       queue,
+		HTTPClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+		PollInterval: 5 * time.Second,
+		MinBackoff:   time.Second,
+		MaxBackoff:   5 * time.Minute,
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// HandleWebhook queues a webhook for the source named by the last path segment,
+// e.g. POST /api/webhooks/servicenow
+func (a *Agent) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	source := filepath.Base(r.URL.Path)
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "Error reading payload", http.StatusBadRequest)
+		return
+	}
+	if !json.Valid(body) {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	msg := &Message{
+		Source:     source,
+		Headers:    map[string]string{"Content-Type": r.Header.Get("Content-Type")},
+		Body:       body,
+		ReceivedAt: time.Now(),
+	}
+	if err := a.Queue.Enqueue(msg); err != nil {
+		logging.FromContext(r.Context()).Error("error queueing webhook", "source", source, "error", err)
+		http.Error(w, "Relay queue unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Nudge the forwarder so delivery is immediate while the link is up
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"queued"}`))
+}
+
+// HandleHealth reports the queue depth
+func (a *Agent) HandleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "ok",
+		"queued": a.Queue.Len(),
+	})
+}
+
+// Start begins forwarding queued messages
+func (a *Agent) Start() {
+	go a.run()
+}
+
+// Stop halts forwarding; queued messages remain on disk for the next start
+func (a *Agent) Stop() {
+	close(a.stop)
+	<-a.done
+}
+
+func (a *Agent) run() {
+	defer close(a.done)
+
+	backoff := a.MinBackoff
+	for {
+		// Drain the queue while the link is up
+		retryIn, err := a.forwardNext(&backoff)
+		if err != nil {
+			slog.Error("error reading relay queue", "error", err)
+		}
+
+		if retryIn > 0 {
+			// Backing off after a failure; new messages don't cut it short
+			select {
+			case <-a.stop:
+				return
+			case <-time.After(retryIn):
+			}
+			continue
+		}
+
+		select {
+		case <-a.stop:
+			return
+		case <-a.wake:
+		case <-time.After(a.PollInterval):
+		}
+	}
+}
+
+// forwardNext forwards queued messages until the queue is empty or a delivery fails,
+// returning how long to back off after a retryable failure
+func (a *Agent) forwardNext(backoff *time.Duration) (time.Duration, error) {
+	for {
+		select {
+		case <-a.stop:
+			return 0, nil
+		default:
+		}
+
+		msg, err := a.Queue.Peek()
+		if err != nil || msg == nil {
+			return 0, err
+		}
+
+		retry, err := a.forward(msg)
+		switch {
+		case err == nil:
+			if err := a.Queue.Remove(msg.ID); err != nil {
+				return 0, err
+			}
+			*backoff = a.MinBackoff
+		case !retry:
+			slog.Warn("backend rejected message; setting it aside", "message", msg.ID, "error", err)
+			a.reject(msg)
+		default:
+			msg.Attempts++
+			if err := a.Queue.Update(msg); err != nil {
+				slog.Error("error updating message", "message", msg.ID, "error", err)
+			}
+			wait := *backoff
+			slog.Warn("forwarding message failed; retrying", "message", msg.ID, "attempt", msg.Attempts, "wait", wait.String(), "error", err)
+			*backoff *= 2
+			if *backoff > a.MaxBackoff {
+				*backoff = a.MaxBackoff
+			}
+			return wait, nil
+		}
+	}
+}
+
+// forward sends one message upstream. The bool reports whether a failure is worth retrying.
+func (a *Agent) forward(msg *Message) (bool, error) {
+	req, err := http.NewRequest("POST", a.UpstreamURL+"/api/relay/ingest", bytes.NewReader(msg.Body))
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	for key, value := range msg.Headers {
+		if value != "" {
+			req.Header.Set(key, value)
+		}
+	}
+	req.Header.Set(HeaderAgent, a.Name)
+	req.Header.Set(HeaderMessageID, msg.ID)
+	req.Header.Set(HeaderSource, msg.Source)
+	req.Header.Set(HeaderTimestamp, fmt.Sprintf("%d", timestamp))
+	req.Header.Set(HeaderSignature, Sign(a.Secret, timestamp, msg.ID, msg.Body))
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	err = fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+
+	// Client errors won't succeed on retry, except auth (secret being rotated),
+	// timeouts and throttling
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+		return true, err
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return false, err
+	}
+	return true, err
+}
+
+// reject moves an undeliverable message out of the queue for manual inspection
+func (a *Agent) reject(msg *Message) {
+	path := filepath.Join(a.Queue.Dir, msg.ID+".json")
+	if err := os.Rename(path, path+".rejected"); err != nil {
+		slog.Error("error setting aside message", "message", msg.ID, "error", err)
+	}
+}
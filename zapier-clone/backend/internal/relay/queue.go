@@ -0,0 +1,155 @@
+// backend/internal/relay/queue.go
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned when the queue has reached its size limit
+var ErrQueueFull = errors.New("relay queue is full")
+
+// Message is one queued webhook waiting to be forwarded
+type Message struct {
+	ID         string            `json:"id"`
+	Source     string            `json:"source"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       []byte            `json:"body"`
+	ReceivedAt time.Time         `json:"received_at"`
+	Attempts   int               `json:"attempts"`
+}
+
+// Queue is a durable FIFO of messages, one JSON file per message, so queued
+// webhooks survive both link loss and an agent restart
+type Queue struct {
+	Dir     string
+	MaxSize int
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewQueue opens (creating if needed) a queue directory
+func NewQueue(dir string, maxSize int) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating queue directory: %w", err)
+	}
+	return &Queue{Dir: dir, MaxSize: maxSize}, nil
+}
+
+// Enqueue stores a message and assigns its ID
+func (q *Queue) Enqueue(msg *Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	names, err := q.names()
+	if err != nil {
+		return err
+	}
+	if q.MaxSize > 0 && len(names) >= q.MaxSize {
+		return ErrQueueFull
+	}
+
+	// Names sort in arrival order: nanosecond timestamp plus a tiebreaker
+	q.seq++
+	msg.ID = fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), q.seq%1000000)
+
+	return q.write(msg)
+}
+
+// Peek returns the oldest message without removing it, or nil if the queue is empty
+func (q *Queue) Peek() (*Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	names, err := q.names()
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(q.Dir, names[0]))
+	if err != nil {
+		return nil, fmt.Errorf("error reading queued message: %w", err)
+	}
+
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		// A corrupt file would block the queue forever, so set it aside
+		os.Rename(filepath.Join(q.Dir, names[0]), filepath.Join(q.Dir, names[0]+".corrupt"))
+		return nil, fmt.Errorf("error decoding queued message %s: %w", names[0], err)
+	}
+
+	return &msg, nil
+}
+
+// Update rewrites a queued message, e.g. after a failed attempt
+func (q *Queue) Update(msg *Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.write(msg)
+}
+
+// Remove deletes a delivered message
+func (q *Queue) Remove(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := os.Remove(filepath.Join(q.Dir, id+".json")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing queued message: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of queued messages
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	names, _ := q.names()
+	return len(names)
+}
+
+// write stores a message atomically via a temp file and rename
+func (q *Queue) write(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error encoding queued message: %w", err)
+	}
+
+	path := filepath.Join(q.Dir, msg.ID+".json")
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("error writing queued message: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing queued message: %w", err)
+	}
+
+	return nil
+}
+
+// names lists queued message files, oldest first
+func (q *Queue) names() ([]string, error) {
+	entries, err := ioutil.ReadDir(q.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading queue directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
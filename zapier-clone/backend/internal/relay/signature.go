@@ -0,0 +1,54 @@
+// backend/internal/relay/signature.go
+package relay
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Headers carried on every forwarded message
+const (
+	HeaderAgent     = "X-Relay-Agent"
+	HeaderMessageID = "X-Relay-Message-ID"
+	HeaderSource    = "X-Relay-Source"
+	HeaderTimestamp = "X-Relay-Timestamp"
+	HeaderSignature = "X-Relay-Signature"
+)
+
+// MaxClockSkew is how far a signature timestamp may drift from the backend's clock
+const MaxClockSkew = 5 * time.Minute
+
+// ErrInvalidSignature is returned when a forwarded message fails verification
+var ErrInvalidSignature = errors.New("invalid relay signature")
+
+// Sign returns the hex HMAC-SHA256 of "timestamp.messageID.body" under secret
+func Sign(secret string, timestamp int64, messageID string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "." + messageID + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature and rejects timestamps outside MaxClockSkew
+func Verify(secret, timestamp, messageID, signature string, body []byte, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	skew := now.Sub(time.Unix(ts, 0))
+	if skew > MaxClockSkew || skew < -MaxClockSkew {
+		return ErrInvalidSignature
+	}
+
+	expected := Sign(secret, ts, messageID, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+// backend/internal/reporting/access_review.go
+package reporting
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+)
+
+// ConnectionAccess is one connection entry in an access review
+type ConnectionAccess struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Service    string     `json:"service"`
+	Status     string     `json:"status"`
+	AuthType   string     `json:"auth_type"`
+	Owner      string     `json:"owner"`
+	OwnerEmail string     `json:"owner_email"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// APIKeyAccess is one API key entry in an access review
+type APIKeyAccess struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Owner      string     `json:"owner"`
+	OwnerEmail string     `json:"owner_email"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// AccessReview is a point-in-time listing of all credentials the platform holds
+type AccessReview struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Period      string             `json:"period"`
+	Connections []ConnectionAccess `json:"connections"`
+	APIKeys     []APIKeyAccess     `json:"api_keys"`
+	// StaleAfter marks credentials unused for longer than this as stale
+	StaleAfter time.Duration `json:"-"`
+}
+
+// AccessReviewer generates one tenant's access review reports for SOC 2
+// evidence
+type AccessReviewer struct {
+	DB *sql.DB
+	// Tenant limits the review to the tenant's users and API keys
+	Tenant      string
+	SlackClient *slack.Client
+	Channel     string
+	StaleAfter  time.Duration
+}
+
+// NewAccessReviewer creates an access reviewer for a tenant that posts to the
+// compliance channel
+func NewAccessReviewer(db *sql.DB, tenant string, slackClient *slack.Client) *AccessReviewer {
+	return &AccessReviewer{
+		DB:          db,
+		Tenant:      tenant,
+		SlackClient: slackClient,
+		Channel:     slack.ChannelMapping["compliance"],
+		StaleAfter:  90 * 24 * time.Hour,
+	}
+}
+
+// Generate builds an access review from the connections of the tenant's users
+// and the tenant's api_keys
+func (a *AccessReviewer) Generate() (*AccessReview, error) {
+	now := time.Now()
+	review := &AccessReview{
+		GeneratedAt: now,
+		Period:      quarterLabel(now),
+		StaleAfter:  a.StaleAfter,
+	}
+
+	rows, err := a.DB.Query(`
+		SELECT c.id, c.name, c.service, c.status, c.auth_type, COALESCE(c.metadata, ''),
+		       c.created_at, c.last_used_at, COALESCE(u.full_name, u.username), u.email
+		FROM connections c
+		JOIN users u ON u.id = c.user_id
+		WHERE u.tenant_id = $1
+		ORDER BY c.service, c.name`, a.Tenant)
+	if err != nil {
+		return nil, fmt.Errorf("error querying connections: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c ConnectionAccess
+		var metadata string
+		var lastUsed sql.NullTime
+		if err := rows.Scan(&c.ID, &c.Name, &c.Service, &c.Status, &c.AuthType, &metadata,
+			&c.CreatedAt, &lastUsed, &c.Owner, &c.OwnerEmail); err != nil {
+			return nil, fmt.Errorf("error scanning connection: %w", err)
+		}
+		if lastUsed.Valid {
+			c.LastUsedAt = &lastUsed.Time
+		}
+		c.Scopes = scopesFromMetadata(metadata)
+		review.Connections = append(review.Connections, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading connections: %w", err)
+	}
+
+	keyRows, err := a.DB.Query(`
+		SELECT k.id, k.name, k.key_prefix, COALESCE(k.scopes, ''), k.created_at, k.last_used_at, k.revoked_at,
+		       COALESCE(u.full_name, u.username), u.email
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.tenant_id = $1
+		ORDER BY k.created_at`, a.Tenant)
+	if err != nil {
+		return nil, fmt.Errorf("error querying api keys: %w", err)
+	}
+	defer keyRows.Close()
+
+	for keyRows.Next() {
+		var k APIKeyAccess
+		var scopes string
+		var lastUsed, revoked sql.NullTime
+		if err := keyRows.Scan(&k.ID, &k.Name, &k.Prefix, &scopes, &k.CreatedAt, &lastUsed, &revoked,
+			&k.Owner, &k.OwnerEmail); err != nil {
+			return nil, fmt.Errorf("error scanning api key: %w", err)
+		}
+		if lastUsed.Valid {
+			k.LastUsedAt = &lastUsed.Time
+		}
+		if revoked.Valid {
+			k.RevokedAt = &revoked.Time
+		}
+		k.Scopes = splitScopes(scopes)
+		review.APIKeys = append(review.APIKeys, k)
+	}
+	if err := keyRows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading api keys: %w", err)
+	}
+
+	return review, nil
+}
+
+// StaleCount returns how many active credentials have not been used within StaleAfter
+func (r *AccessReview) StaleCount() int {
+	count := 0
+	for _, c := range r.Connections {
+		if r.isStale(c.LastUsedAt, c.CreatedAt) {
+			count++
+		}
+	}
+	for _, k := range r.APIKeys {
+		if k.RevokedAt == nil && r.isStale(k.LastUsedAt, k.CreatedAt) {
+			count++
+		}
+	}
+	return count
+}
+
+// isStale reports whether a credential has gone unused for longer than StaleAfter
+func (r *AccessReview) isStale(lastUsed *time.Time, created time.Time) bool {
+	if r.StaleAfter <= 0 {
+		return false
+	}
+	reference := created
+	if lastUsed != nil {
+		reference = *lastUsed
+	}
+	return r.GeneratedAt.Sub(reference) > r.StaleAfter
+}
+
+// CSV renders the review as a single CSV with a record type column
+func (r *AccessReview) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{"type", "id", "name", "service", "status", "owner", "owner_email", "scopes", "created_at", "last_used_at", "revoked_at", "stale"})
+
+	for _, c := range r.Connections {
+		w.Write([]string{
+			"connection",
+			fmt.Sprint(c.ID),
+			c.Name,
+			c.Service,
+			c.Status,
+			c.Owner,
+			c.OwnerEmail,
+			strings.Join(c.Scopes, " "),
+			c.CreatedAt.Format(time.RFC3339),
+			formatOptionalTime(c.LastUsedAt),
+			"",
+			fmt.Sprint(r.isStale(c.LastUsedAt, c.CreatedAt)),
+		})
+	}
+
+	for _, k := range r.APIKeys {
+		status := "active"
+		if k.RevokedAt != nil {
+			status = "revoked"
+		}
+		w.Write([]string{
+			"api_key",
+			fmt.Sprint(k.ID),
+			k.Name,
+			"api",
+			status,
+			k.Owner,
+			k.OwnerEmail,
+			strings.Join(k.Scopes, " "),
+			k.CreatedAt.Format(time.RFC3339),
+			formatOptionalTime(k.LastUsedAt),
+			formatOptionalTime(k.RevokedAt),
+			fmt.Sprint(k.RevokedAt == nil && r.isStale(k.LastUsedAt, k.CreatedAt)),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("error writing CSV: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// PDF renders the review as a text PDF
+func (r *AccessReview) PDF() []byte {
+	doc := NewTextPDF(fmt.Sprintf("Access Review %s", r.Period))
+	doc.AddLine(fmt.Sprintf("Generated: %s", r.GeneratedAt.Format("Jan 2, 2006 15:04 MST")))
+	doc.AddLine(fmt.Sprintf("Connections: %d   API keys: %d   Stale credentials: %d", len(r.Connections), len(r.APIKeys), r.StaleCount()))
+	doc.AddBlank()
+
+	doc.AddLine("CONNECTIONS")
+	doc.AddLine(fmt.Sprintf("%-24s %-12s %-10s %-24s %-12s %-12s", "Name", "Service", "Status", "Owner", "Created", "Last used"))
+	for _, c := range r.Connections {
+		line := fmt.Sprintf("%-24s %-12s %-10s %-24s %-12s %-12s", truncate(c.Name, 24), truncate(c.Service, 12), truncate(c.Status, 10),
+			truncate(c.Owner, 24), c.CreatedAt.Format("2006-01-02"), formatOptionalDate(c.LastUsedAt))
+		if r.isStale(c.LastUsedAt, c.CreatedAt) {
+			line += " STALE"
+		}
+		doc.AddLine(line)
+		if len(c.Scopes) > 0 {
+			doc.AddLine("    scopes: " + strings.Join(c.Scopes, " "))
+		}
+	}
+	doc.AddBlank()
+
+	doc.AddLine("API KEYS")
+	doc.AddLine(fmt.Sprintf("%-24s %-10s %-24s %-12s %-12s %-12s", "Name", "Prefix", "Owner", "Created", "Last used", "Revoked"))
+	for _, k := range r.APIKeys {
+		line := fmt.Sprintf("%-24s %-10s %-24s %-12s %-12s %-12s", truncate(k.Name, 24), truncate(k.Prefix, 10), truncate(k.Owner, 24),
+			k.CreatedAt.Format("2006-01-02"), formatOptionalDate(k.LastUsedAt), formatOptionalDate(k.RevokedAt))
+		if k.RevokedAt == nil && r.isStale(k.LastUsedAt, k.CreatedAt) {
+			line += " STALE"
+		}
+		doc.AddLine(line)
+		if len(k.Scopes) > 0 {
+			doc.AddLine("    scopes: " + strings.Join(k.Scopes, " "))
+		}
+	}
+
+	return doc.Bytes()
+}
+
+// SendAccessReview generates the review and posts it to the compliance channel
+func (a *AccessReviewer) SendAccessReview() error {
+	review, err := a.Generate()
+	if err != nil {
+		return fmt.Errorf("error generating access review: %w", err)
+	}
+
+	message := slack.Message{
+		Blocks: []slack.Block{
+			{
+				Type: "header",
+				Text: slack.NewTextObject("plain_text", fmt.Sprintf("🔐 Access Review %s", review.Period), true),
+			},
+			{
+				Type: "section",
+				Fields: []*slack.TextObject{
+					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Connections:*\n%d", len(review.Connections)), false),
+					slack.NewTextObject("mrkdwn", fmt.Sprintf("*API Keys:*\n%d", len(review.APIKeys)), false),
+					slack.NewTextObject("mrkdwn", fmt.Sprintf("*Stale Credentials:*\n%d", review.StaleCount()), false),
+				},
+			},
+			{
+				Type: "context",
+				Elements: []interface{}{
+					map[string]interface{}{
+						"type": "mrkdwn",
+						"text": fmt.Sprintf("Report generated on %s. Review owners and revoke anything no longer needed.", review.GeneratedAt.Format("Jan 2, 2006 15:04 MST")),
+					},
+				},
+			},
+		},
+	}
+
+	ts, err := a.SlackClient.PostMessage(a.Channel, message)
+	if err != nil {
+		return fmt.Errorf("error posting access review to Slack: %w", err)
+	}
+
+	csvData, err := review.CSV()
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.SlackClient.UploadFile(a.Channel, fmt.Sprintf("access-review-%s.csv", review.Period), string(csvData)); err != nil {
+		return fmt.Errorf("error uploading access review CSV: %w", err)
+	}
+
+	if _, err := a.SlackClient.PostReply(a.Channel, ts, slack.Message{Text: "📎 Full access review attached as CSV."}); err != nil {
+		return fmt.Errorf("error posting access review reply: %w", err)
+	}
+
+	return nil
+}
+
+// scopesFromMetadata reads the scopes list from a connection's JSON metadata
+func scopesFromMetadata(metadata string) []string {
+	if metadata == "" {
+		return nil
+	}
+
+	var parsed struct {
+		Scopes interface{} `json:"scopes"`
+	}
+	if err := json.Unmarshal([]byte(metadata), &parsed); err != nil {
+		return nil
+	}
+
+	switch scopes := parsed.Scopes.(type) {
+	case string:
+		return splitScopes(scopes)
+	case []interface{}:
+		var result []string
+		for _, s := range scopes {
+			if str, ok := s.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// splitScopes splits a comma or space separated scope string
+func splitScopes(scopes string) []string {
+	return strings.FieldsFunc(scopes, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// quarterLabel returns e.g. "2024-Q3"
+func quarterLabel(t time.Time) string {
+	return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatOptionalDate(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "~"
+}
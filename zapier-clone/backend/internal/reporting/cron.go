@@ -0,0 +1,230 @@
+// backend/internal/reporting/cron.go
+package reporting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronDescriptors are the shorthand schedules accepted in place of five fields
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// cronField describes one of the five fields of a cron expression
+type cronField struct {
+	name     string
+	min, max int
+	// names are accepted in place of numbers, starting at min
+	names []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: monthNames},
+	// 7 is accepted for Sunday and folded onto 0
+	{name: "day of week", min: 0, max: 7, names: weekdayNames},
+}
+
+// CronSchedule is a parsed five-field cron expression
+// ("minute hour day-of-month month day-of-week") evaluated in Location
+type CronSchedule struct {
+	Spec     string
+	Location *time.Location
+
+	minute, hour, dom, month, dow uint64
+	// A restricted day of month and day of week match when either does, as in cron
+	domAny, dowAny bool
+}
+
+// ParseCron parses a cron expression or a descriptor such as @daily. A
+// CRON_TZ=<zone> or TZ=<zone> prefix overrides location, which otherwise
+// defaults to the server's.
+func ParseCron(spec string, location *time.Location) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if location == nil {
+		location = time.Local
+	}
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		zone, rest, _ := strings.Cut(spec, " ")
+		loaded, err := time.LoadLocation(zone[strings.Index(zone, "=")+1:])
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone in %q", zone)
+		}
+		location, spec = loaded, strings.TrimSpace(rest)
+	}
+
+	expression := spec
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		expression = descriptor
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q needs five fields: minute hour day-of-month month day-of-week", spec)
+	}
+
+	schedule := &CronSchedule{Spec: spec, Location: location}
+	targets := []*uint64{&schedule.minute, &schedule.hour, &schedule.dom, &schedule.month, &schedule.dow}
+	for i, field := range cronFields {
+		bits, err := field.parse(fields[i])
+		if err != nil {
+			return nil, err
+		}
+		*targets[i] = bits
+	}
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.domAny = fields[2] == "*" || fields[2] == "?"
+	schedule.dowAny = fields[4] == "*" || fields[4] == "?"
+
+	return schedule, nil
+}
+
+// parse reads a comma-separated list of values, ranges and steps into a bitset
+func (f cronField) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(strings.ToLower(value), ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s", stepPart, f.name)
+			}
+			step = parsed
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = f.value(from); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(to); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s", rangePart, f.name)
+			}
+		default:
+			var err error
+			if low, err = f.value(rangePart); err != nil {
+				return 0, err
+			}
+			// "5/15" runs from 5 to the end of the field
+			if !hasStep {
+				high = low
+			}
+		}
+
+		for n := low; n <= high; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+	return bits, nil
+}
+
+// value reads one number or name of the field
+func (f cronField) value(value string) (int, error) {
+	for i, name := range f.names {
+		if value == name {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid %s %q; use %d-%d", f.name, value, f.min, f.max)
+	}
+	return n, nil
+}
+
+// Next returns the first time after t the schedule fires, or the zero time
+// when it never does (e.g. February 30th)
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := s.Location
+	from := t.In(loc)
+	t = time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), from.Minute()+1, 0, 0, loc)
+	limit := t.Year() + 5
+
+wrap:
+	for t.Year() <= limit {
+		for s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+		for !s.dayMatches(t) {
+			next := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			// Midnight can fall in a DST gap, which time.Date may resolve
+			// to the day before
+			if !next.After(t) {
+				next = next.Add(time.Hour)
+			}
+			t = next
+			if t.Day() == 1 {
+				continue wrap
+			}
+		}
+		for s.hour&(1<<uint(t.Hour())) == 0 {
+			// Step in absolute time so an hour skipped by DST isn't revisited
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			if t.Hour() == 0 {
+				continue wrap
+			}
+		}
+		for s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			if t.Minute() == 0 {
+				continue wrap
+			}
+		}
+		// A time repeated when clocks go back fires only once
+		if t.Format("2006-01-02 15:04") == from.Format("2006-01-02 15:04") {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// NextN returns the next n times after t the schedule fires
+func (s *CronSchedule) NextN(t time.Time, n int) []time.Time {
+	times := []time.Time{}
+	for len(times) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
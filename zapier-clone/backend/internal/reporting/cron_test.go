@@ -0,0 +1,164 @@
+// backend/internal/reporting/cron_test.go
+package reporting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "too few fields", spec: "0 9 * *"},
+		{name: "too many fields", spec: "0 9 * * * *"},
+		{name: "minute out of range", spec: "60 * * * *"},
+		{name: "hour out of range", spec: "0 24 * * *"},
+		{name: "day of month zero", spec: "0 0 0 * *"},
+		{name: "unknown month name", spec: "0 0 1 foo *"},
+		{name: "backwards range", spec: "0 17-9 * * *"},
+		{name: "zero step", spec: "*/0 * * * *"},
+		{name: "unknown descriptor", spec: "@fortnightly"},
+		{name: "unknown timezone", spec: "CRON_TZ=Mars/Olympus 0 9 * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCron(tt.spec, time.UTC); err == nil {
+				t.Errorf("ParseCron(%q) succeeded, want an error", tt.spec)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data is not available: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		spec     string
+		location *time.Location
+		from     time.Time
+		want     []time.Time
+	}{
+		{
+			name: "daily descriptor",
+			spec: "@daily",
+			from: time.Date(2024, 5, 14, 9, 30, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "step across the hour",
+			spec: "*/20 * * * *",
+			from: time.Date(2024, 5, 14, 9, 45, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 14, 10, 20, 0, 0, time.UTC),
+				time.Date(2024, 5, 14, 10, 40, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "start with step",
+			spec: "5/30 * * * *",
+			from: time.Date(2024, 5, 14, 9, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 5, 14, 9, 5, 0, 0, time.UTC),
+				time.Date(2024, 5, 14, 9, 35, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "weekdays by name",
+			spec: "0 9 * * mon-fri",
+			// Friday 2024-05-17
+			from: time.Date(2024, 5, 17, 9, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 5, 20, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 21, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "sunday as seven",
+			spec: "0 0 * * 7",
+			from: time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "day of month or day of week",
+			spec: "0 0 1 * mon",
+			from: time.Date(2024, 4, 28, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last day of february in a leap year",
+			spec: "0 12 29 feb *",
+			from: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "never fires",
+			spec: "0 0 30 2 *",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: nil,
+		},
+		{
+			name: "timezone prefix",
+			spec: "CRON_TZ=America/New_York 0 9 * * *",
+			from: time.Date(2024, 5, 14, 12, 0, 0, 0, time.UTC),
+			want: []time.Time{time.Date(2024, 5, 14, 9, 0, 0, 0, newYork)},
+		},
+		{
+			name:     "hour skipped when clocks go forward",
+			spec:     "30 2 * * *",
+			location: newYork,
+			from:     time.Date(2024, 3, 9, 3, 0, 0, 0, newYork),
+			want:     []time.Time{time.Date(2024, 3, 11, 2, 30, 0, 0, newYork)},
+		},
+		{
+			name:     "hour repeated when clocks go back fires once",
+			spec:     "30 1 * * *",
+			location: newYork,
+			from:     time.Date(2024, 11, 3, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, 11, 3, 1, 30, 0, 0, newYork),
+				time.Date(2024, 11, 4, 1, 30, 0, 0, newYork),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := tt.location
+			if location == nil {
+				location = time.UTC
+			}
+			schedule, err := ParseCron(tt.spec, location)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.spec, err)
+			}
+
+			got := schedule.NextN(tt.from, len(tt.want)+1)
+			if len(tt.want) == 0 {
+				if len(got) != 0 {
+					t.Fatalf("NextN = %v, want none", got)
+				}
+				return
+			}
+			for i, want := range tt.want {
+				if i >= len(got) || !got[i].Equal(want) {
+					t.Fatalf("NextN = %v, want %v first", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+// backend/internal/reporting/definitions.go
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// ErrDefinitionNotFound is returned for an unknown report definition
+var ErrDefinitionNotFound = errors.New("report definition not found")
+
+// reportSources maps the source names a definition may use to their tables;
+// any other lower-case table name is used as given
+var reportSources = map[string]string{
+	"risks":            "sn_risk_risk",
+	"vendor_risks":     "sn_vendor_risk",
+	"compliance_tasks": "sn_compliance_task",
+	"control_tests":    "sn_policy_control_test",
+	"audit_findings":   "sn_audit_finding",
+	"policies":         "sn_compliance_policy",
+	"incidents":        "sn_si_incident",
+}
+
+// tableNamePattern matches a ServiceNow table name
+var tableNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// maxReportGroups caps the groups listed in a report's Slack message
+const maxReportGroups = 20
+
+// ReportDefinition is a user-defined report: which records to count, how to
+// group them, when to run and where to post the result
+type ReportDefinition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Sources are source names (risks, vendor_risks, ...) or table names
+	Sources []string `json:"sources"`
+	// Filter is a report filter, e.g. "state != closed AND risk_level = high"
+	Filter string `json:"filter,omitempty"`
+	// GroupBy counts the matching records by one field; empty gives a total only
+	GroupBy string `json:"group_by,omitempty"`
+	// Schedule is a cron expression, e.g. "0 9 * * 1" for Mondays at 9:00
+	Schedule string `json:"schedule"`
+	// Timezone is the IANA zone the schedule runs in; empty uses the scheduler's
+	Timezone  string     `json:"timezone,omitempty"`
+	Channel   string     `json:"channel"`
+	Disabled  bool       `json:"disabled,omitempty"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Validate checks a definition and normalizes its sources, fields and channel
+func (d *ReportDefinition) Validate() error {
+	d.Name = strings.TrimSpace(d.Name)
+	if d.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(d.Sources) == 0 {
+		return fmt.Errorf("at least one source is required")
+	}
+	for i, source := range d.Sources {
+		d.Sources[i] = strings.ToLower(strings.TrimSpace(source))
+		if !tableNamePattern.MatchString(d.Sources[i]) {
+			return fmt.Errorf("invalid source %q", source)
+		}
+	}
+	if _, err := ParseFilter(d.Filter); err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+	d.GroupBy = strings.ToLower(strings.TrimSpace(d.GroupBy))
+	if d.GroupBy != "" && !filterFieldPattern.MatchString(d.GroupBy) {
+		return fmt.Errorf("invalid group_by field %q", d.GroupBy)
+	}
+	d.Timezone = strings.TrimSpace(d.Timezone)
+	if d.Timezone != "" {
+		if _, err := time.LoadLocation(d.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", d.Timezone)
+		}
+	}
+	if _, err := d.Cron(nil); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	d.Channel = strings.TrimPrefix(strings.TrimSpace(d.Channel), "#")
+	if d.Channel == "" {
+		return fmt.Errorf("channel is required")
+	}
+	return nil
+}
+
+// Cron parses the definition's schedule in its timezone, or in fallback when it
+// has none
+func (d *ReportDefinition) Cron(fallback *time.Location) (*CronSchedule, error) {
+	location := fallback
+	if d.Timezone != "" {
+		loaded, err := time.LoadLocation(d.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", d.Timezone)
+		}
+		location = loaded
+	}
+	return ParseCron(d.Schedule, location)
+}
+
+// tables resolves the definition's sources to tables
+func (d *ReportDefinition) tables() []string {
+	tables := make([]string, len(d.Sources))
+	for i, source := range d.Sources {
+		if table, ok := reportSources[source]; ok {
+			tables[i] = table
+		} else {
+			tables[i] = source
+		}
+	}
+	return tables
+}
+
+// GroupCount is the number of matching records with one group_by value
+type GroupCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// DefinitionResult is one run of a report definition
+type DefinitionResult struct {
+	DefinitionID string         `json:"definition_id"`
+	Name         string         `json:"name"`
+	GeneratedAt  time.Time      `json:"generated_at"`
+	Total        int            `json:"total"`
+	BySource     map[string]int `json:"by_source"`
+	// Groups is empty when the definition has no group_by
+	Groups []GroupCount `json:"groups,omitempty"`
+}
+
+// ReportDefinitions stores user-defined reports and runs them against
+// ServiceNow, posting the results to Slack
+type ReportDefinitions struct {
+	ServiceNowClient *servicenow.Client
+	SlackClient      *slack.Client
+
+	definitions []*ReportDefinition
+	sequence    int64
+	mutex       sync.RWMutex
+	filePath    string
+	now         func() time.Time
+}
+
+// NewReportDefinitions creates a store backed by report_definitions.json under storagePath
+func NewReportDefinitions(storagePath string, serviceNowClient *servicenow.Client, slackClient *slack.Client) (*ReportDefinitions, error) {
+	d := &ReportDefinitions{
+		ServiceNowClient: serviceNowClient,
+		SlackClient:      slackClient,
+		filePath:         filepath.Join(storagePath, "report_definitions.json"),
+		now:              time.Now,
+	}
+
+	if _, err := os.Stat(d.filePath); err == nil {
+		file, err := os.ReadFile(d.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading report definition file: %w", err)
+		}
+		if err := json.Unmarshal(file, &d.definitions); err != nil {
+			return nil, fmt.Errorf("error unmarshaling report definitions: %w", err)
+		}
+	}
+	for _, definition := range d.definitions {
+		var n int64
+		if _, err := fmt.Sscanf(definition.ID, "report-%d", &n); err == nil && n > d.sequence {
+			d.sequence = n
+		}
+	}
+
+	return d, nil
+}
+
+// List returns every definition in creation order
+func (d *ReportDefinitions) List() []ReportDefinition {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	definitions := make([]ReportDefinition, 0, len(d.definitions))
+	for _, definition := range d.definitions {
+		definitions = append(definitions, *definition)
+	}
+	return definitions
+}
+
+// Get returns one definition
+func (d *ReportDefinitions) Get(id string) (ReportDefinition, bool) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if definition := d.find(id); definition != nil {
+		return *definition, true
+	}
+	return ReportDefinition{}, false
+}
+
+// Create validates and stores a new definition
+func (d *ReportDefinitions) Create(definition ReportDefinition) (ReportDefinition, error) {
+	if err := definition.Validate(); err != nil {
+		return ReportDefinition{}, err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.sequence++
+	definition.ID = fmt.Sprintf("report-%d", d.sequence)
+	definition.LastRunAt = nil
+	definition.CreatedAt = d.now()
+	definition.UpdatedAt = definition.CreatedAt
+	d.definitions = append(d.definitions, &definition)
+
+	return definition, d.save()
+}
+
+// Update replaces a definition's settings, keeping when it last ran
+func (d *ReportDefinitions) Update(id string, definition ReportDefinition) (ReportDefinition, error) {
+	if err := definition.Validate(); err != nil {
+		return ReportDefinition{}, err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	existing := d.find(id)
+	if existing == nil {
+		return ReportDefinition{}, ErrDefinitionNotFound
+	}
+	definition.ID = existing.ID
+	definition.LastRunAt = existing.LastRunAt
+	definition.CreatedAt = existing.CreatedAt
+	definition.UpdatedAt = d.now()
+	*existing = definition
+
+	return definition, d.save()
+}
+
+// Delete removes a definition
+func (d *ReportDefinitions) Delete(id string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for i, definition := range d.definitions {
+		if definition.ID == id {
+			d.definitions = append(d.definitions[:i], d.definitions[i+1:]...)
+			return d.save()
+		}
+	}
+	return ErrDefinitionNotFound
+}
+
+// Run evaluates a definition, posts the result to its channel and records the run
+func (d *ReportDefinitions) Run(ctx context.Context, id string) (*DefinitionResult, error) {
+	definition, ok := d.Get(id)
+	if !ok {
+		return nil, ErrDefinitionNotFound
+	}
+
+	result, err := d.Evaluate(ctx, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := definition.Channel
+	if mapped, ok := slack.ChannelMapping[channel]; ok {
+		channel = mapped
+	}
+	if _, err := d.SlackClient.WithContext(ctx).PostMessage(channel, result.Message(definition)); err != nil {
+		return result, fmt.Errorf("error posting report %s to Slack: %w", definition.ID, err)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if existing := d.find(id); existing != nil {
+		ranAt := result.GeneratedAt
+		existing.LastRunAt = &ranAt
+		if err := d.save(); err != nil {
+			logging.FromContext(ctx).Error("error recording report run", "report", id, "error", err)
+		}
+	}
+	return result, nil
+}
+
+// Evaluate counts the records a definition matches without posting anything
+func (d *ReportDefinitions) Evaluate(ctx context.Context, definition ReportDefinition) (*DefinitionResult, error) {
+	filter, err := ParseFilter(definition.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	now := d.now()
+	result := &DefinitionResult{
+		DefinitionID: definition.ID,
+		Name:         definition.Name,
+		GeneratedAt:  now,
+		BySource:     map[string]int{},
+	}
+
+	fields := append([]string{"number"}, filter.Fields()...)
+	if definition.GroupBy != "" {
+		fields = append(fields, definition.GroupBy)
+	}
+
+	groups := map[string]int{}
+	client := d.ServiceNowClient.WithContext(ctx)
+	for i, table := range definition.tables() {
+		records := client.Records(table, servicenow.ListOptions{
+			Query:  filter.Query(now).String(),
+			Fields: fields,
+		})
+		// The filter is applied again here so results hold even where the
+		// instance ignores part of the encoded query
+		for records.Next() {
+			record := records.Record()
+			if !filter.Matches(record, now) {
+				continue
+			}
+			result.Total++
+			result.BySource[definition.Sources[i]]++
+			if definition.GroupBy != "" {
+				value := fieldValue(record[definition.GroupBy])
+				if value == "" {
+					value = "(none)"
+				}
+				groups[value]++
+			}
+		}
+		if err := records.Err(); err != nil {
+			return nil, fmt.Errorf("error reading %s records: %w", table, err)
+		}
+	}
+
+	for value, count := range groups {
+		result.Groups = append(result.Groups, GroupCount{Value: value, Count: count})
+	}
+	sort.Slice(result.Groups, func(i, j int) bool {
+		a, b := result.Groups[i], result.Groups[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Value < b.Value)
+	})
+
+	return result, nil
+}
+
+func (d *ReportDefinitions) find(id string) *ReportDefinition {
+	for _, definition := range d.definitions {
+		if definition.ID == id {
+			return definition
+		}
+	}
+	return nil
+}
+
+// save writes the definitions; callers hold the lock
+func (d *ReportDefinitions) save() error {
+	data, err := json.MarshalIndent(d.definitions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling report definitions: %w", err)
+	}
+	if err := os.WriteFile(d.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing report definitions: %w", err)
+	}
+	return nil
+}
+
+// Message renders a run of the definition for Slack
+func (r *DefinitionResult) Message(definition ReportDefinition) slack.Message {
+	blocks := []slack.Block{
+		{
+			Type: "header",
+			Text: slack.NewTextObject("plain_text", "📋 "+definition.Name, true),
+		},
+		{
+			Type: "section",
+			Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*Matching records:* %d", r.Total), false),
+		},
+	}
+
+	if definition.GroupBy != "" {
+		var groups strings.Builder
+		for i, group := range r.Groups {
+			if i == maxReportGroups {
+				fmt.Fprintf(&groups, "…and %d more", len(r.Groups)-i)
+				break
+			}
+			fmt.Fprintf(&groups, "• *%s*: %d\n", group.Value, group.Count)
+		}
+		if groups.Len() == 0 {
+			groups.WriteString("No matching records")
+		}
+		blocks = append(blocks, slack.Block{
+			Type: "section",
+			Text: slack.NewTextObject("mrkdwn", fmt.Sprintf("*By %s*\n%s", definition.GroupBy, groups.String()), false),
+		})
+	}
+
+	details := fmt.Sprintf("Sources: %s", strings.Join(definition.Sources, ", "))
+	if definition.Filter != "" {
+		details += fmt.Sprintf(" · Filter: `%s`", definition.Filter)
+	}
+	blocks = append(blocks, slack.Block{
+		Type: "context",
+		Elements: []interface{}{
+			map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("%s · Report %s generated on %s", details, definition.ID, r.GeneratedAt.Format("Jan 2, 2006 15:04 MST")),
+			},
+		},
+	})
+
+	return slack.Message{Text: fmt.Sprintf("%s: %d matching records", definition.Name, r.Total), Blocks: blocks}
+}
@@ -0,0 +1,301 @@
+// backend/internal/reporting/dsl.go
+package reporting
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// filterFieldPattern matches the field names a filter may reference
+var filterFieldPattern = regexp.MustCompile(`^[a-z][a-z0-9_.]*$`)
+
+// relativeDatePattern matches now, now-7d and now+30d
+var relativeDatePattern = regexp.MustCompile(`^now(?:([+-])(\d+)d)?$`)
+
+// Condition is one "field operator value" clause of a report filter
+type Condition struct {
+	Field    string
+	Operator string
+	// Values holds one value, or several for "in"
+	Values []string
+}
+
+// Filter is a parsed report filter: conditions that must all hold.
+//
+// The syntax is clauses joined by AND, e.g.
+//
+//	state != closed AND risk_level in (high, critical) AND due_date < now+7d
+//
+// Operators are =, !=, <, <=, >, >=, contains and in. Values containing
+// spaces are quoted, and now, now-Nd and now+Nd stand for dates relative to
+// when the report runs.
+type Filter []Condition
+
+// ParseFilter parses a report filter; an empty string matches every record
+func ParseFilter(input string) (Filter, error) {
+	tokens, err := tokenizeFilter(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter Filter
+	for pos := 0; pos < len(tokens); {
+		if len(filter) > 0 {
+			if !strings.EqualFold(tokens[pos], "and") {
+				return nil, fmt.Errorf("expected AND before %q", tokens[pos])
+			}
+			pos++
+		}
+		if pos >= len(tokens) {
+			return nil, fmt.Errorf("the filter ends with AND")
+		}
+
+		field := strings.ToLower(tokens[pos])
+		if !filterFieldPattern.MatchString(field) {
+			return nil, fmt.Errorf("invalid field name %q", tokens[pos])
+		}
+		if pos+2 >= len(tokens) {
+			return nil, fmt.Errorf("condition on %s is missing an operator or value", field)
+		}
+		operator := strings.ToLower(tokens[pos+1])
+		pos += 2
+
+		condition := Condition{Field: field, Operator: operator}
+		switch operator {
+		case "=", "!=", "<", "<=", ">", ">=", "contains":
+			condition.Values = []string{tokens[pos]}
+			pos++
+		case "in":
+			if tokens[pos] != "(" {
+				return nil, fmt.Errorf("in needs a parenthesised list, e.g. %s in (a, b)", field)
+			}
+			pos++
+			for {
+				if pos >= len(tokens) {
+					return nil, fmt.Errorf("unclosed list after %s in", field)
+				}
+				if tokens[pos] == ")" {
+					pos++
+					break
+				}
+				if tokens[pos] == "," {
+					pos++
+					continue
+				}
+				condition.Values = append(condition.Values, tokens[pos])
+				pos++
+			}
+			if len(condition.Values) == 0 {
+				return nil, fmt.Errorf("the list after %s in is empty", field)
+			}
+		default:
+			return nil, fmt.Errorf("unknown operator %q; use =, !=, <, <=, >, >=, contains or in", tokens[pos-1])
+		}
+
+		for _, value := range condition.Values {
+			if strings.Contains(value, "^") {
+				return nil, fmt.Errorf("values cannot contain ^")
+			}
+		}
+		filter = append(filter, condition)
+	}
+
+	return filter, nil
+}
+
+// tokenizeFilter splits a filter into words, quoted strings, operators and list punctuation
+func tokenizeFilter(input string) ([]string, error) {
+	var tokens []string
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated quoted value")
+			}
+			tokens = append(tokens, string(runes[i+1:end]))
+			i = end + 1
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, string(runes[i:i+2]))
+				i += 2
+			} else if r == '!' {
+				return nil, fmt.Errorf("expected != in the filter")
+			} else {
+				tokens = append(tokens, string(r))
+				i++
+			}
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`"'(),=!<>`, runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// Fields returns the fields the filter references
+func (f Filter) Fields() []string {
+	var fields []string
+	for _, condition := range f {
+		fields = append(fields, condition.Field)
+	}
+	return fields
+}
+
+// Query encodes the filter as a ServiceNow encoded query, resolving relative
+// dates against now
+func (f Filter) Query(now time.Time) *servicenow.Query {
+	query := servicenow.NewQuery()
+	for _, condition := range f {
+		values := make([]string, len(condition.Values))
+		for i, value := range condition.Values {
+			values[i] = resolveValue(value, now)
+		}
+		switch condition.Operator {
+		case "contains":
+			query.Where(condition.Field, "LIKE", values[0])
+		case "in":
+			query.In(condition.Field, values...)
+		default:
+			query.Where(condition.Field, condition.Operator, values[0])
+		}
+	}
+	return query
+}
+
+// Matches reports whether a record satisfies every condition. A reference or
+// choice field matches on either its value or its display value, so state = 3
+// and state = closed both work.
+func (f Filter) Matches(record map[string]interface{}, now time.Time) bool {
+	for _, condition := range f {
+		candidates := fieldValues(record[condition.Field])
+		if condition.Operator == "!=" {
+			for _, candidate := range candidates {
+				if compareValues(candidate, resolveValue(condition.Values[0], now)) == 0 {
+					return false
+				}
+			}
+			continue
+		}
+
+		matched := false
+		for _, candidate := range candidates {
+			if condition.matches(candidate, now) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matches checks one candidate value against a condition other than !=
+func (c Condition) matches(candidate string, now time.Time) bool {
+	value := resolveValue(c.Values[0], now)
+	switch c.Operator {
+	case "=":
+		return compareValues(candidate, value) == 0
+	case "contains":
+		return strings.Contains(strings.ToLower(candidate), strings.ToLower(value))
+	case "in":
+		for _, option := range c.Values {
+			if compareValues(candidate, resolveValue(option, now)) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Ordering never matches an empty field, as in ServiceNow
+	if candidate == "" {
+		return false
+	}
+	cmp := compareValues(candidate, value)
+	switch c.Operator {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// resolveValue turns now, now-Nd and now+Nd into Table API date-times
+func resolveValue(value string, now time.Time) string {
+	match := relativeDatePattern.FindStringSubmatch(strings.ToLower(value))
+	if match == nil {
+		return value
+	}
+	if match[1] != "" {
+		days, _ := strconv.Atoi(match[2])
+		if match[1] == "-" {
+			days = -days
+		}
+		now = now.AddDate(0, 0, days)
+	}
+	return now.UTC().Format("2006-01-02 15:04:05")
+}
+
+// compareValues compares two values as numbers, then as dates, then as
+// case-insensitive text
+func compareValues(a, b string) int {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if x, ok := parseDate(a); ok {
+		if y, ok := parseDate(b); ok {
+			return x.Compare(y)
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// fieldValues returns a field's value and, for reference and choice fields,
+// its display value
+func fieldValues(value interface{}) []string {
+	field, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{fieldValue(value)}
+	}
+
+	values := []string{fieldValue(field["value"])}
+	if display, ok := field["display_value"].(string); ok && display != "" && display != values[0] {
+		values = append(values, display)
+	}
+	return values
+}
@@ -0,0 +1,128 @@
+// backend/internal/reporting/dsl_test.go
+package reporting
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Filter
+		wantErr bool
+	}{
+		{name: "empty filter", input: "  ", want: nil},
+		{
+			name:  "single condition",
+			input: "state != closed",
+			want:  Filter{{Field: "state", Operator: "!=", Values: []string{"closed"}}},
+		},
+		{
+			name:  "conditions joined by AND",
+			input: `State = open and risk_level in (high, "very high") AND due_date < now+7d`,
+			want: Filter{
+				{Field: "state", Operator: "=", Values: []string{"open"}},
+				{Field: "risk_level", Operator: "in", Values: []string{"high", "very high"}},
+				{Field: "due_date", Operator: "<", Values: []string{"now+7d"}},
+			},
+		},
+		{
+			name:  "operators without spaces",
+			input: "risk_score>=80 AND category contains 'vendor risk'",
+			want: Filter{
+				{Field: "risk_score", Operator: ">=", Values: []string{"80"}},
+				{Field: "category", Operator: "contains", Values: []string{"vendor risk"}},
+			},
+		},
+		{name: "missing AND", input: "state = open priority = 1", wantErr: true},
+		{name: "trailing AND", input: "state = open AND", wantErr: true},
+		{name: "missing value", input: "state =", wantErr: true},
+		{name: "unknown operator", input: "state like open", wantErr: true},
+		{name: "bare bang", input: "state ! open", wantErr: true},
+		{name: "invalid field name", input: "1state = open", wantErr: true},
+		{name: "in without a list", input: "state in open", wantErr: true},
+		{name: "unclosed list", input: "state in (open, new", wantErr: true},
+		{name: "empty list", input: "state in ()", wantErr: true},
+		{name: "unterminated quote", input: `state = "open`, wantErr: true},
+		{name: "encoded query separator", input: "state = open^ORstate=closed", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilter(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseFilter(%q) = %v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilter(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilter(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	now := time.Date(2024, 5, 14, 12, 0, 0, 0, time.UTC)
+	record := map[string]interface{}{
+		"state":             map[string]interface{}{"value": "3", "display_value": "Closed"},
+		"risk_score":        "85",
+		"category":          "Vendor Risk",
+		"due_date":          "2024-05-18 00:00:00",
+		"short_description": "",
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{filter: "", want: true},
+		{filter: "state = closed", want: true},
+		{filter: "state = 3", want: true},
+		{filter: "state != closed", want: false},
+		{filter: "state != open", want: true},
+		{filter: "risk_score > 80", want: true},
+		{filter: "risk_score > 100", want: false},
+		{filter: "risk_score >= 85 AND risk_score <= 85", want: true},
+		{filter: "category contains vendor", want: true},
+		{filter: "category in (financial, 'vendor risk')", want: true},
+		{filter: "category in (financial, operational)", want: false},
+		{filter: "due_date < now+7d", want: true},
+		{filter: "due_date < now+3d", want: false},
+		{filter: "due_date > now", want: true},
+		{filter: "short_description < zzz", want: false},
+		{filter: "risk_score > 80 AND state = open", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := ParseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseFilter: %v", err)
+			}
+			if got := filter.Matches(record, now); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterQuery(t *testing.T) {
+	now := time.Date(2024, 5, 14, 12, 0, 0, 0, time.UTC)
+	filter, err := ParseFilter("state != closed AND risk_level in (high, critical) AND category contains vendor AND due_date < now-7d")
+	if err != nil {
+		t.Fatalf("ParseFilter: %v", err)
+	}
+
+	want := "state!=closed^risk_levelINhigh,critical^categoryLIKEvendor^due_date<2024-05-07 12:00:00"
+	if got := filter.Query(now).String(); got != want {
+		t.Errorf("Query = %q, want %q", got, want)
+	}
+}
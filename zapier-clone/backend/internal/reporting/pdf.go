@@ -0,0 +1,133 @@
+// backend/internal/reporting/pdf.go
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PDF page layout in points (US Letter)
+const (
+	pdfPageWidth    = 612
+	pdfPageHeight   = 792
+	pdfMargin       = 50
+	pdfFontSize     = 9
+	pdfTitleSize    = 14
+	pdfLineHeight   = 12
+	pdfMaxLineChars = 110
+)
+
+// TextPDF builds a simple multi-page, text-only PDF document.
+// It is intentionally minimal: a single monospace font and no images,
+// which is all the tabular GRC reports need.
+type TextPDF struct {
+	title string
+	lines []string
+}
+
+// NewTextPDF creates a PDF with a title printed at the top of the first page
+func NewTextPDF(title string) *TextPDF {
+	return &TextPDF{title: title}
+}
+
+// AddLine appends a line of text, wrapping it if it is too long
+func (p *TextPDF) AddLine(line string) {
+	for len(line) > pdfMaxLineChars {
+		p.lines = append(p.lines, line[:pdfMaxLineChars])
+		line = "  " + line[pdfMaxLineChars:]
+	}
+	p.lines = append(p.lines, line)
+}
+
+// AddBlank appends an empty line
+func (p *TextPDF) AddBlank() {
+	p.lines = append(p.lines, "")
+}
+
+// Bytes renders the document
+func (p *TextPDF) Bytes() []byte {
+	linesPerPage := (pdfPageHeight - 2*pdfMargin - 2*pdfLineHeight) / pdfLineHeight
+
+	// Split lines into pages
+	var pages [][]string
+	for start := 0; start < len(p.lines) || len(pages) == 0; start += linesPerPage {
+		end := start + linesPerPage
+		if end > len(p.lines) {
+			end = len(p.lines)
+		}
+		pages = append(pages, p.lines[start:end])
+		if end == len(p.lines) {
+			break
+		}
+	}
+
+	var objects []string
+
+	// 1: catalog, 2: pages, 3: font, then a page and content object per page
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+2*i))
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>")
+
+	for i, lines := range pages {
+		var content bytes.Buffer
+		y := pdfPageHeight - pdfMargin
+
+		content.WriteString("BT\n")
+		if i == 0 {
+			fmt.Fprintf(&content, "/F1 %d Tf\n%d %d Td\n(%s) Tj\n", pdfTitleSize, pdfMargin, y, pdfEscape(p.title))
+			fmt.Fprintf(&content, "/F1 %d Tf\n0 %d Td\n", pdfFontSize, -2*pdfLineHeight)
+		} else {
+			fmt.Fprintf(&content, "/F1 %d Tf\n%d %d Td\n", pdfFontSize, pdfMargin, y)
+		}
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) Tj\n0 %d Td\n", pdfEscape(line), -pdfLineHeight)
+		}
+		content.WriteString("ET\n")
+
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	// Write the objects and cross-reference table
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// pdfEscape escapes a string for use in a PDF literal and drops non-ASCII characters
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -2,46 +2,208 @@
 package reporting
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
 	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
 )
 
-// ReportScheduler schedules and runs periodic reports
+// Built-in report names, used in RunManualReport, the schedule API and the
+// REPORT_SCHEDULE_<NAME> variables
+const (
+	ReportWeekly       = "weekly"
+	ReportRiskCategory = "risk-category"
+	ReportAccessReview = "access-review"
+)
+
+// defaultSchedules are the built-in reports' cron expressions
+var defaultSchedules = map[string]string{
+	// Mondays at 9:00
+	ReportWeekly: "0 9 * * 1",
+	// Wednesdays at 9:00
+	ReportRiskCategory: "0 9 * * 3",
+	// The first day of each quarter at 9:00
+	ReportAccessReview: "0 9 1 1,4,7,10 *",
+}
+
+// scheduleGrace is how late a run may start and still count as on time; the
+// scheduler checks once a minute
+const scheduleGrace = 2 * time.Minute
+
+// ScheduledReport describes when a report runs
+type ScheduledReport struct {
+	Name string `json:"name"`
+	// Kind is "built-in" or "definition"
+	Kind     string `json:"kind"`
+	Schedule string `json:"schedule"`
+	Timezone string `json:"timezone"`
+	Disabled bool   `json:"disabled,omitempty"`
+	// LastRunAt is the scheduled time of the last run, not counting manual runs
+	LastRunAt *time.Time  `json:"last_run_at,omitempty"`
+	NextRuns  []time.Time `json:"next_runs"`
+}
+
+// scheduledJob is one report the scheduler runs
+type scheduledJob struct {
+	name     string
+	kind     string
+	schedule *CronSchedule
+	disabled bool
+	// since is when the job started counting when it has never run
+	since time.Time
+	run   func(ctx context.Context) error
+}
+
+// ReportScheduler runs the built-in and user-defined reports on cron schedules.
+// It records when each report last ran so a run missed while the server was
+// down is made up after a restart.
 type ReportScheduler struct {
 	ReportingHandler *servicenow.ReportingHandler
-	running          bool
-	stopChan         chan struct{}
+	// AccessReviewer is optional; the quarterly access review only runs when a database is configured
+	AccessReviewer *AccessReviewer
+	// Weekly replaces the ServiceNow summary with the compliance summary and its CSV and PDF when set
+	Weekly *WeeklyReporter
+	// Definitions runs the user-defined reports on their own schedules when set
+	Definitions *ReportDefinitions
+	// Location is the timezone of schedules that don't name one
+	Location *time.Location
+	// Jitter delays each scheduled run by a random amount up to this long, so
+	// tenants sharing a schedule don't all query ServiceNow at once
+	Jitter time.Duration
+	// CatchUpWindow is how late a missed run is still made up; older missed
+	// runs are skipped until the next scheduled time
+	CatchUpWindow time.Duration
+
+	schedules map[string]string
+	lastRuns  map[string]time.Time
+	pending   map[string]bool
+	started   time.Time
+	mutex     sync.Mutex
+	filePath  string
+	running   bool
+	stopChan  chan struct{}
+	now       func() time.Time
 }
 
-// NewReportScheduler creates a new report scheduler
-func NewReportScheduler(serviceNowClient *servicenow.Client, slackClient *slack.Client) *ReportScheduler {
-	return &ReportScheduler{
+// NewReportScheduler creates a report scheduler that records its runs in
+// report_schedule.json under storagePath
+func NewReportScheduler(storagePath string, serviceNowClient *servicenow.Client, slackClient *slack.Client) *ReportScheduler {
+	s := &ReportScheduler{
 		ReportingHandler: servicenow.NewReportingHandler(serviceNowClient, slackClient),
+		Location:         time.Local,
+		CatchUpWindow:    24 * time.Hour,
+		schedules:        make(map[string]string),
+		lastRuns:         make(map[string]time.Time),
+		pending:          make(map[string]bool),
+		filePath:         filepath.Join(storagePath, "report_schedule.json"),
 		running:          false,
 		stopChan:         make(chan struct{}),
+		now:              time.Now,
 	}
+	for name, spec := range defaultSchedules {
+		s.schedules[name] = spec
+	}
+
+	if _, err := os.Stat(s.filePath); err == nil {
+		file, err := os.ReadFile(s.filePath)
+		if err != nil {
+			slog.Error("error reading report schedule file", "error", err)
+		} else if err := json.Unmarshal(file, &s.lastRuns); err != nil {
+			slog.Error("error unmarshaling report schedule", "error", err)
+		}
+	}
+
+	return s
 }
 
-// Start begins running the scheduled reports
+// ConfigureFromEnv reads REPORT_TIMEZONE, REPORT_JITTER (e.g. "5m"),
+// REPORT_CATCHUP_WINDOW (e.g. "24h", "0" to skip missed runs) and
+// REPORT_SCHEDULE_WEEKLY, REPORT_SCHEDULE_RISK_CATEGORY and
+// REPORT_SCHEDULE_ACCESS_REVIEW, each a cron expression or "off"
+func (s *ReportScheduler) ConfigureFromEnv() {
+	if value := os.Getenv("REPORT_TIMEZONE"); value != "" {
+		if location, err := time.LoadLocation(value); err == nil {
+			s.Location = location
+		} else {
+			slog.Warn("ignoring invalid REPORT_TIMEZONE", "value", value)
+		}
+	}
+	if value := os.Getenv("REPORT_JITTER"); value != "" {
+		if jitter, err := time.ParseDuration(value); err == nil && jitter >= 0 {
+			s.Jitter = jitter
+		} else {
+			slog.Warn("ignoring invalid REPORT_JITTER", "value", value)
+		}
+	}
+	if value := os.Getenv("REPORT_CATCHUP_WINDOW"); value == "0" {
+		s.CatchUpWindow = 0
+	} else if value != "" {
+		if window, err := time.ParseDuration(value); err == nil && window >= 0 {
+			s.CatchUpWindow = window
+		} else {
+			slog.Warn("ignoring invalid REPORT_CATCHUP_WINDOW", "value", value)
+		}
+	}
+
+	for name := range defaultSchedules {
+		key := "REPORT_SCHEDULE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		value := strings.TrimSpace(os.Getenv(key))
+		if value == "" {
+			continue
+		}
+		if strings.EqualFold(value, "off") {
+			s.schedules[name] = ""
+			continue
+		}
+		if _, err := ParseCron(value, s.Location); err != nil {
+			slog.Warn("ignoring invalid setting", "variable", key, "value", value, "error", err)
+			continue
+		}
+		s.schedules[name] = value
+	}
+}
+
+// Start begins running the scheduled reports. Runs missed while the server
+// was down are checked for straight away.
 func (s *ReportScheduler) Start() {
+	s.mutex.Lock()
 	if s.running {
+		s.mutex.Unlock()
 		return
 	}
-
 	s.running = true
+	s.started = s.now()
+	s.mutex.Unlock()
 
-	// Run the weekly summary report every Monday at 9:00 AM
-	go s.scheduleWeeklySummary()
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
 
-	// Run the risk category report every Wednesday at 9:00 AM
-	go s.scheduleRiskCategorySummary()
+		s.runDue()
+		for {
+			select {
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+				s.runDue()
+			}
+		}
+	}()
 }
 
 // Stop stops the scheduler
 func (s *ReportScheduler) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	if !s.running {
 		return
 	}
@@ -50,57 +212,196 @@ func (s *ReportScheduler) Stop() {
 	close(s.stopChan)
 }
 
-// scheduleWeeklySummary schedules the weekly summary report
-func (s *ReportScheduler) scheduleWeeklySummary() {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
+// jobs returns the built-in reports that can run here followed by the user-defined ones
+func (s *ReportScheduler) jobs() []scheduledJob {
+	var jobs []scheduledJob
+	for _, name := range []string{ReportWeekly, ReportRiskCategory, ReportAccessReview} {
+		if name == ReportAccessReview && s.AccessReviewer == nil {
+			continue
+		}
+		name := name
+		job := scheduledJob{
+			name:  name,
+			kind:  "built-in",
+			since: s.started,
+			run:   func(context.Context) error { return s.RunManualReport(name) },
+		}
+		if spec := s.schedules[name]; spec == "" {
+			job.disabled = true
+			job.schedule = &CronSchedule{Spec: "off", Location: s.Location}
+		} else if schedule, err := ParseCron(spec, s.Location); err == nil {
+			job.schedule = schedule
+		} else {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
 
-	for {
-		select {
-		case <-s.stopChan:
-			return
-		case t := <-ticker.C:
-			// Check if it's Monday at 9:00 AM
-			if t.Weekday() == time.Monday && t.Hour() == 9 && t.Minute() < 5 {
-				log.Println("Running weekly GRC summary report")
-				err := s.ReportingHandler.SendWeeklySummary()
-				if err != nil {
-					log.Printf("Error sending weekly summary: %v", err)
-				}
-			}
+	if s.Definitions == nil {
+		return jobs
+	}
+	for _, definition := range s.Definitions.List() {
+		schedule, err := definition.Cron(s.Location)
+		if err != nil {
+			slog.Warn("skipping report with an invalid schedule", "report", definition.ID, "error", err)
+			continue
+		}
+		id := definition.ID
+		jobs = append(jobs, scheduledJob{
+			name:     id,
+			kind:     "definition",
+			schedule: schedule,
+			disabled: definition.Disabled,
+			since:    definition.CreatedAt,
+			run: func(ctx context.Context) error {
+				_, err := s.Definitions.Run(ctx, id)
+				return err
+			},
+		})
+	}
+	return jobs
+}
+
+// runDue starts every report whose next run has come. Of several runs missed
+// in a row only the latest is made up, and only within CatchUpWindow.
+func (s *ReportScheduler) runDue() {
+	now := s.now()
+	for _, job := range s.jobs() {
+		if job.disabled {
+			continue
+		}
+
+		s.mutex.Lock()
+		last, ok := s.lastRuns[job.name]
+		if !ok {
+			last = job.since
+		}
+		pending := s.pending[job.name]
+		s.mutex.Unlock()
+		if pending {
+			continue
+		}
+
+		var due time.Time
+		for next := job.schedule.Next(last); !next.IsZero() && !next.After(now); next = job.schedule.Next(next) {
+			due = next
 		}
+		if due.IsZero() {
+			continue
+		}
+
+		window := s.CatchUpWindow
+		if window < scheduleGrace {
+			window = scheduleGrace
+		}
+		if now.Sub(due) > window {
+			slog.Warn("skipping missed report", "report", job.name, "due", due.Format(time.RFC3339))
+			s.recordRun(job.name, due)
+			continue
+		}
+		if now.Sub(due) > scheduleGrace {
+			slog.Info("catching up missed report", "report", job.name, "due", due.Format(time.RFC3339))
+		}
+
+		s.mutex.Lock()
+		s.pending[job.name] = true
+		s.mutex.Unlock()
+		go s.dispatch(job, due)
 	}
 }
 
-// scheduleRiskCategorySummary schedules the risk category summary report
-func (s *ReportScheduler) scheduleRiskCategorySummary() {
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
+// dispatch waits out the jitter and runs a job for its scheduled time due
+func (s *ReportScheduler) dispatch(job scheduledJob, due time.Time) {
+	defer func() {
+		s.mutex.Lock()
+		delete(s.pending, job.name)
+		s.mutex.Unlock()
+	}()
 
-	for {
+	if s.Jitter > 0 {
 		select {
+		case <-time.After(time.Duration(rand.Int63n(int64(s.Jitter)))):
 		case <-s.stopChan:
 			return
-		case t := <-ticker.C:
-			// Check if it's Wednesday at 9:00 AM
-			if t.Weekday() == time.Wednesday && t.Hour() == 9 && t.Minute() < 5 {
-				log.Println("Running risk category summary report")
-				err := s.ReportingHandler.SendRiskCategorySummary()
-				if err != nil {
-					log.Printf("Error sending risk category summary: %v", err)
-				}
-			}
 		}
 	}
+
+	s.recordRun(job.name, due)
+	slog.Info("running report", "report", job.name)
+	if err := job.run(context.Background()); err != nil {
+		slog.Error("error running report", "report", job.name, "error", err)
+	}
+}
+
+// recordRun notes the scheduled time a report last ran or skipped, so that
+// time isn't run twice
+func (s *ReportScheduler) recordRun(name string, at time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lastRuns[name] = at
+	s.save()
+}
+
+// Schedules lists every report with its next count run times
+func (s *ReportScheduler) Schedules(count int) []ScheduledReport {
+	now := s.now()
+	reports := []ScheduledReport{}
+	for _, job := range s.jobs() {
+		report := ScheduledReport{
+			Name:     job.name,
+			Kind:     job.kind,
+			Schedule: job.schedule.Spec,
+			Timezone: job.schedule.Location.String(),
+			Disabled: job.disabled,
+			NextRuns: []time.Time{},
+		}
+		s.mutex.Lock()
+		if last, ok := s.lastRuns[job.name]; ok {
+			report.LastRunAt = &last
+		}
+		s.mutex.Unlock()
+		if !job.disabled {
+			report.NextRuns = job.schedule.NextN(now, count)
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// save writes the last run times; callers hold the lock
+func (s *ReportScheduler) save() {
+	data, err := json.MarshalIndent(s.lastRuns, "", "  ")
+	if err != nil {
+		slog.Error("error marshaling report schedule", "error", err)
+		return
+	}
+	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+		slog.Error("error writing report schedule", "error", err)
+	}
+}
+
+// sendWeeklySummary posts the compliance summary, or ServiceNow's GRC summary without one
+func (s *ReportScheduler) sendWeeklySummary() error {
+	if s.Weekly == nil {
+		return s.ReportingHandler.SendWeeklySummary()
+	}
+	_, err := s.Weekly.Send()
+	return err
 }
 
 // RunManualReport runs a report manually
 func (s *ReportScheduler) RunManualReport(reportType string) error {
 	switch reportType {
-	case "weekly":
-		return s.ReportingHandler.SendWeeklySummary()
-	case "risk-category":
+	case ReportWeekly:
+		return s.sendWeeklySummary()
+	case ReportRiskCategory:
 		return s.ReportingHandler.SendRiskCategorySummary()
+	case ReportAccessReview:
+		if s.AccessReviewer == nil {
+			return fmt.Errorf("access review requires a database connection")
+		}
+		return s.AccessReviewer.SendAccessReview()
 	default:
 		return nil
 	}
@@ -0,0 +1,556 @@
+// backend/internal/reporting/weekly.go
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/sla"
+)
+
+// ErrReportNotFound is returned for a report that was never generated
+var ErrReportNotFound = errors.New("report not found")
+
+// overdueTables are the GRC tables whose records have a due date
+var overdueTables = []string{
+	"sn_risk_risk",
+	"sn_compliance_task",
+	"sn_policy_control_test",
+	"sn_audit_finding",
+	"sn_vendor_risk",
+}
+
+// closedStates are the record states (labels and common numeric codes) that are no longer open
+var closedStates = map[string]bool{
+	"closed":    true,
+	"resolved":  true,
+	"cancelled": true,
+	"retired":   true,
+	"complete":  true,
+	"3":         true,
+	"4":         true,
+	"7":         true,
+}
+
+// CategoryCount is the number of open risks in one category
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// OverdueItem is an open GRC record past its due date
+type OverdueItem struct {
+	Table       string    `json:"table"`
+	Number      string    `json:"number"`
+	Title       string    `json:"title"`
+	AssignedTo  string    `json:"assigned_to,omitempty"`
+	DueDate     time.Time `json:"due_date"`
+	DaysOverdue int       `json:"days_overdue"`
+}
+
+// RemediationItem is the progress of an audit finding's remediation plan
+type RemediationItem struct {
+	Number          string `json:"number"`
+	Title           string `json:"title"`
+	Completed       int    `json:"completed"`
+	Total           int    `json:"total"`
+	PercentComplete int    `json:"percent_complete"`
+	// NextDue is the due date (YYYY-MM-DD) of the next open milestone
+	NextDue      string `json:"next_due,omitempty"`
+	NextDueTitle string `json:"next_due_title,omitempty"`
+}
+
+// WeeklySummary is the weekly compliance summary: open risks by category,
+// overdue items and SLA results for the incidents opened in the past week
+type WeeklySummary struct {
+	// ID names the report's stored files, e.g. "weekly-2026-W42"
+	ID              string          `json:"id"`
+	Period          string          `json:"period"`
+	GeneratedAt     time.Time       `json:"generated_at"`
+	OpenRisks       int             `json:"open_risks"`
+	RisksByCategory []CategoryCount `json:"risks_by_category"`
+	Overdue         []OverdueItem   `json:"overdue"`
+	// SLA is nil when SLA tracking is off
+	SLA *sla.Stats `json:"sla,omitempty"`
+	// Remediation lists the audit finding remediation plans still in progress
+	Remediation []RemediationItem `json:"remediation,omitempty"`
+}
+
+// ReportInfo lists a stored report
+type ReportInfo struct {
+	ID          string    `json:"id"`
+	Period      string    `json:"period"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// WeeklyReporter generates the weekly compliance summary, keeps each week's
+// summary so its CSV and PDF can be downloaded, and posts it to Slack
+type WeeklyReporter struct {
+	ServiceNowClient *servicenow.Client
+	SlackClient      *slack.Client
+	// SLATracker adds SLA results to the summary; nil leaves them out
+	SLATracker *sla.Tracker
+	// Remediation adds audit finding remediation progress; nil leaves it out
+	Remediation *servicenow.RemediationStore
+	Channel     string
+	// BaseURL prefixes the download links in the Slack message
+	BaseURL string
+
+	dir string
+	now func() time.Time
+}
+
+// NewWeeklyReporter creates a reporter that posts to the reports channel and
+// keeps summaries in the reports directory under storagePath
+func NewWeeklyReporter(storagePath string, serviceNowClient *servicenow.Client, slackClient *slack.Client, tracker *sla.Tracker) *WeeklyReporter {
+	return &WeeklyReporter{
+		ServiceNowClient: serviceNowClient,
+		SlackClient:      slackClient,
+		SLATracker:       tracker,
+		Channel:          slack.ChannelMapping["reports"],
+		BaseURL:          strings.TrimSuffix(getEnv("ADMIN_BASE_URL", "http://localhost:8081"), "/"),
+		dir:              filepath.Join(storagePath, "reports"),
+		now:              time.Now,
+	}
+}
+
+// WithContext returns a copy of the reporter whose API calls run under ctx
+func (r *WeeklyReporter) WithContext(ctx context.Context) *WeeklyReporter {
+	copied := *r
+	copied.ServiceNowClient = r.ServiceNowClient.WithContext(ctx)
+	copied.SlackClient = r.SlackClient.WithContext(ctx)
+	return &copied
+}
+
+// Generate builds the summary from ServiceNow and the SLA clocks
+func (r *WeeklyReporter) Generate() (*WeeklySummary, error) {
+	now := r.now()
+	year, week := now.ISOWeek()
+	period := fmt.Sprintf("%d-W%02d", year, week)
+	summary := &WeeklySummary{
+		ID:          "weekly-" + period,
+		Period:      period,
+		GeneratedAt: now,
+		Overdue:     []OverdueItem{},
+	}
+
+	// Open risks by category
+	categories := map[string]int{}
+	risks := r.ServiceNowClient.Records("sn_risk_risk", servicenow.ListOptions{
+		Query:  servicenow.NewQuery().Equals("active", "true").String(),
+		Fields: []string{"category", "state"},
+	})
+	for risks.Next() {
+		record := risks.Record()
+		if closedStates[strings.ToLower(fieldValue(record["state"]))] {
+			continue
+		}
+		category := fieldValue(record["category"])
+		if category == "" {
+			category = "Uncategorized"
+		}
+		categories[category]++
+		summary.OpenRisks++
+	}
+	if err := risks.Err(); err != nil {
+		return nil, fmt.Errorf("error reading open risks: %w", err)
+	}
+	for category, count := range categories {
+		summary.RisksByCategory = append(summary.RisksByCategory, CategoryCount{Category: category, Count: count})
+	}
+	sort.Slice(summary.RisksByCategory, func(i, j int) bool {
+		a, b := summary.RisksByCategory[i], summary.RisksByCategory[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Category < b.Category)
+	})
+
+	// Open records past their due date, most overdue first
+	for _, table := range overdueTables {
+		records := r.ServiceNowClient.Records(table, servicenow.ListOptions{
+			Query: servicenow.NewQuery().
+				Equals("active", "true").
+				Where("due_date", "<", now.UTC().Format("2006-01-02 15:04:05")).
+				String(),
+			Fields: []string{"number", "short_description", "assigned_to", "due_date", "state"},
+		})
+		for records.Next() {
+			record := records.Record()
+			due, ok := parseDate(fieldValue(record["due_date"]))
+			if !ok || !due.Before(now) || closedStates[strings.ToLower(fieldValue(record["state"]))] {
+				continue
+			}
+			summary.Overdue = append(summary.Overdue, OverdueItem{
+				Table:       table,
+				Number:      fieldValue(record["number"]),
+				Title:       fieldValue(record["short_description"]),
+				AssignedTo:  fieldValue(record["assigned_to"]),
+				DueDate:     due,
+				DaysOverdue: int(now.Sub(due).Hours() / 24),
+			})
+		}
+		if err := records.Err(); err != nil {
+			return nil, fmt.Errorf("error reading overdue %s records: %w", table, err)
+		}
+	}
+	sort.SliceStable(summary.Overdue, func(i, j int) bool { return summary.Overdue[i].DueDate.Before(summary.Overdue[j].DueDate) })
+
+	if r.SLATracker != nil {
+		stats := r.SLATracker.Stats(now.AddDate(0, 0, -7))
+		summary.SLA = &stats
+	}
+
+	// Remediation plans with milestones still open, least complete first
+	if r.Remediation != nil {
+		for _, plan := range r.Remediation.List() {
+			if len(plan.Milestones) == 0 || plan.PercentComplete == 100 {
+				continue
+			}
+			item := RemediationItem{
+				Number:          plan.Number,
+				Title:           plan.Title,
+				Completed:       plan.Completed(),
+				Total:           len(plan.Milestones),
+				PercentComplete: plan.PercentComplete,
+			}
+			if next := plan.NextDue(); next != nil {
+				item.NextDue = next.DueDate
+				item.NextDueTitle = next.Title
+			}
+			summary.Remediation = append(summary.Remediation, item)
+		}
+		sort.SliceStable(summary.Remediation, func(i, j int) bool {
+			return summary.Remediation[i].PercentComplete < summary.Remediation[j].PercentComplete
+		})
+	}
+
+	return summary, nil
+}
+
+// Send generates the summary, stores it and posts it to the reports channel
+// with links to its CSV and PDF
+func (r *WeeklyReporter) Send() (*WeeklySummary, error) {
+	summary, err := r.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("error generating weekly summary: %w", err)
+	}
+	if err := r.Save(summary); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.SlackClient.PostMessage(r.Channel, summary.Message(r.BaseURL)); err != nil {
+		return summary, fmt.Errorf("error posting weekly summary to Slack: %w", err)
+	}
+	return summary, nil
+}
+
+// Save stores a summary, replacing an earlier one for the same week
+func (r *WeeklyReporter) Save(summary *WeeklySummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling weekly summary: %w", err)
+	}
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("error creating reports directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, summary.ID+".json"), data, 0644); err != nil {
+		return fmt.Errorf("error writing weekly summary: %w", err)
+	}
+	return nil
+}
+
+// Load returns a stored summary
+func (r *WeeklyReporter) Load(id string) (*WeeklySummary, error) {
+	// IDs name files, so anything that could leave the directory is unknown
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return nil, ErrReportNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.dir, id+".json"))
+	if os.IsNotExist(err) {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading report %s: %w", id, err)
+	}
+
+	var summary WeeklySummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("error unmarshaling report %s: %w", id, err)
+	}
+	return &summary, nil
+}
+
+// List returns the stored reports, newest first
+func (r *WeeklyReporter) List() ([]ReportInfo, error) {
+	paths, err := filepath.Glob(filepath.Join(r.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []ReportInfo{}
+	for _, path := range paths {
+		summary, err := r.Load(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, ReportInfo{ID: summary.ID, Period: summary.Period, GeneratedAt: summary.GeneratedAt})
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].GeneratedAt.After(reports[j].GeneratedAt) })
+	return reports, nil
+}
+
+// Message renders the summary for Slack, linking to its CSV and PDF under baseURL
+func (s *WeeklySummary) Message(baseURL string) slack.Message {
+	var categories strings.Builder
+	for _, category := range s.RisksByCategory {
+		fmt.Fprintf(&categories, "• *%s*: %d\n", category.Category, category.Count)
+	}
+	if categories.Len() == 0 {
+		categories.WriteString("No open risks 🎉")
+	}
+
+	var overdue strings.Builder
+	for i, item := range s.Overdue {
+		if i == 10 {
+			fmt.Fprintf(&overdue, "…and %d more in the CSV", len(s.Overdue)-i)
+			break
+		}
+		fmt.Fprintf(&overdue, "• *%s* %s (%d %s overdue)\n", item.Number, truncate(item.Title, 60), item.DaysOverdue, pluralDays(item.DaysOverdue))
+	}
+	if overdue.Len() == 0 {
+		overdue.WriteString("Nothing is overdue 🎉")
+	}
+
+	blocks := []slack.Block{
+		{
+			Type: "header",
+			Text: slack.NewTextObject("plain_text", fmt.Sprintf("📊 Weekly Compliance Summary %s", s.Period), true),
+		},
+		{
+			Type: "section",
+			Fields: []*slack.TextObject{
+				slack.NewTextObject("mrkdwn", fmt.Sprintf("*Open Risks:*\n%d", s.OpenRisks), false),
+				slack.NewTextObject("mrkdwn", fmt.Sprintf("*Overdue Items:*\n%d", len(s.Overdue)), false),
+			},
+		},
+		{
+			Type: "section",
+			Text: slack.NewTextObject("mrkdwn", "*Open Risks by Category*\n"+categories.String(), false),
+		},
+		{
+			Type: "section",
+			Text: slack.NewTextObject("mrkdwn", "*Overdue Items*\n"+overdue.String(), false),
+		},
+	}
+	if s.SLA != nil {
+		blocks = append(blocks, slack.Block{
+			Type: "section",
+			Fields: []*slack.TextObject{
+				slack.NewTextObject("mrkdwn", fmt.Sprintf("*Incident SLAs Met:*\n%d%% (%d of %d)", s.SLA.MetPercent, s.SLA.Met, s.SLA.Met+s.SLA.Breached), false),
+				slack.NewTextObject("mrkdwn", fmt.Sprintf("*Open SLA Breaches:*\n%d", s.SLA.OpenBreaches), false),
+			},
+		})
+	}
+	if len(s.Remediation) > 0 {
+		var remediation strings.Builder
+		for i, item := range s.Remediation {
+			if i == 10 {
+				fmt.Fprintf(&remediation, "…and %d more in the CSV", len(s.Remediation)-i)
+				break
+			}
+			fmt.Fprintf(&remediation, "• *%s* %s: %d%% (%d of %d)", item.Number, truncate(item.Title, 50), item.PercentComplete, item.Completed, item.Total)
+			if item.NextDue != "" {
+				fmt.Fprintf(&remediation, ", next due %s", item.NextDue)
+			}
+			remediation.WriteString("\n")
+		}
+		blocks = append(blocks, slack.Block{
+			Type: "section",
+			Text: slack.NewTextObject("mrkdwn", "*Audit Finding Remediation*\n"+remediation.String(), false),
+		})
+	}
+
+	download := func(label, format string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "button",
+			"text": map[string]interface{}{
+				"type":  "plain_text",
+				"text":  label,
+				"emoji": true,
+			},
+			"url":       fmt.Sprintf("%s/api/v1/reports/%s?format=%s", baseURL, s.ID, format),
+			"action_id": "download_report_" + format,
+		}
+	}
+	blocks = append(blocks,
+		slack.Block{
+			Type:     "actions",
+			Elements: []interface{}{download("⬇️ CSV", "csv"), download("⬇️ PDF", "pdf")},
+		},
+		slack.Block{
+			Type: "context",
+			Elements: []interface{}{
+				map[string]interface{}{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("Report generated on %s", s.GeneratedAt.Format("Jan 2, 2006 15:04 MST")),
+				},
+			},
+		},
+	)
+
+	return slack.Message{Text: fmt.Sprintf("Weekly Compliance Summary %s", s.Period), Blocks: blocks}
+}
+
+// CSV renders the summary as a single CSV with a section column
+func (s *WeeklySummary) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{"section", "name", "value", "table", "number", "title", "assigned_to", "due_date", "days_overdue"})
+
+	w.Write([]string{"summary", "open_risks", fmt.Sprint(s.OpenRisks), "", "", "", "", "", ""})
+	w.Write([]string{"summary", "overdue_items", fmt.Sprint(len(s.Overdue)), "", "", "", "", "", ""})
+	for _, category := range s.RisksByCategory {
+		w.Write([]string{"risk_category", category.Category, fmt.Sprint(category.Count), "", "", "", "", "", ""})
+	}
+	if s.SLA != nil {
+		for _, metric := range []struct {
+			name  string
+			value int
+		}{
+			{"tracked", s.SLA.Tracked},
+			{"met", s.SLA.Met},
+			{"breached", s.SLA.Breached},
+			{"running", s.SLA.Running},
+			{"open_breaches", s.SLA.OpenBreaches},
+			{"met_percent", s.SLA.MetPercent},
+		} {
+			w.Write([]string{"sla", metric.name, fmt.Sprint(metric.value), "", "", "", "", "", ""})
+		}
+	}
+	for _, item := range s.Remediation {
+		w.Write([]string{
+			"remediation",
+			"percent_complete",
+			fmt.Sprint(item.PercentComplete),
+			"sn_audit_finding",
+			item.Number,
+			item.Title,
+			"",
+			item.NextDue,
+			"",
+		})
+	}
+	for _, item := range s.Overdue {
+		w.Write([]string{
+			"overdue",
+			"",
+			"",
+			item.Table,
+			item.Number,
+			item.Title,
+			item.AssignedTo,
+			item.DueDate.Format("2006-01-02"),
+			fmt.Sprint(item.DaysOverdue),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("error writing CSV: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// PDF renders the summary as a text PDF
+func (s *WeeklySummary) PDF() []byte {
+	doc := NewTextPDF(fmt.Sprintf("Weekly Compliance Summary %s", s.Period))
+	doc.AddLine(fmt.Sprintf("Generated: %s", s.GeneratedAt.Format("Jan 2, 2006 15:04 MST")))
+	doc.AddLine(fmt.Sprintf("Open risks: %d   Overdue items: %d", s.OpenRisks, len(s.Overdue)))
+	doc.AddBlank()
+
+	doc.AddLine("OPEN RISKS BY CATEGORY")
+	for _, category := range s.RisksByCategory {
+		doc.AddLine(fmt.Sprintf("%-40s %6d", truncate(category.Category, 40), category.Count))
+	}
+	doc.AddBlank()
+
+	if s.SLA != nil {
+		doc.AddLine("INCIDENT SLAS (OPENED IN THE PAST WEEK)")
+		doc.AddLine(fmt.Sprintf("Tracked: %d   Met: %d   Breached: %d   Running: %d", s.SLA.Tracked, s.SLA.Met, s.SLA.Breached, s.SLA.Running))
+		doc.AddLine(fmt.Sprintf("Met: %d%%   Open breaches: %d", s.SLA.MetPercent, s.SLA.OpenBreaches))
+		doc.AddBlank()
+	}
+
+	if len(s.Remediation) > 0 {
+		doc.AddLine("AUDIT FINDING REMEDIATION")
+		doc.AddLine(fmt.Sprintf("%-12s %-40s %-10s %-8s %s", "Number", "Title", "Milestones", "Percent", "Next due"))
+		for _, item := range s.Remediation {
+			doc.AddLine(fmt.Sprintf("%-12s %-40s %-10s %-8s %s", truncate(item.Number, 12), truncate(item.Title, 40),
+				fmt.Sprintf("%d/%d", item.Completed, item.Total), fmt.Sprintf("%d%%", item.PercentComplete), item.NextDue))
+		}
+		doc.AddBlank()
+	}
+
+	doc.AddLine("OVERDUE ITEMS")
+	doc.AddLine(fmt.Sprintf("%-12s %-40s %-24s %-12s %s", "Number", "Title", "Assigned to", "Due", "Days"))
+	for _, item := range s.Overdue {
+		doc.AddLine(fmt.Sprintf("%-12s %-40s %-24s %-12s %d", truncate(item.Number, 12), truncate(item.Title, 40),
+			truncate(item.AssignedTo, 24), item.DueDate.Format("2006-01-02"), item.DaysOverdue))
+	}
+
+	return doc.Bytes()
+}
+
+// parseDate reads a Table API date or date-time
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02", time.RFC3339} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// fieldValue reads a field returned as a string or as
+// {"display_value": ..., "value": ...}
+func fieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}:
+		if display, ok := v["display_value"].(string); ok && display != "" {
+			return display
+		}
+		return fieldValue(v["value"])
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "day"
+	}
+	return "days"
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,195 @@
+// backend/internal/riskmatrix/matrix.go
+package riskmatrix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxLevels bounds the likelihood and impact levels of a matrix
+const maxLevels = 10
+
+// Matrix scores a risk from its likelihood and impact. Levels are listed
+// from lowest to highest; ServiceNow values match a level by name, with or
+// without a leading number ("4 - Likely"), or by number alone, 1 being the
+// lowest level.
+type Matrix struct {
+	Likelihoods []string `json:"likelihoods"`
+	Impacts     []string `json:"impacts"`
+	// Scores holds a row per likelihood and a column per impact
+	Scores [][]int `json:"scores"`
+	// Bands rate scores; the band with the highest Min not above a score applies
+	Bands []Band `json:"bands"`
+}
+
+// Band rates the scores from Min up. An empty Priority leaves the Jira
+// priority to the field mapping.
+type Band struct {
+	Min      int    `json:"min"`
+	Rating   string `json:"rating"`
+	Priority string `json:"priority,omitempty"`
+}
+
+// Assessment is a risk's place in the matrix
+type Assessment struct {
+	Likelihood string `json:"likelihood"`
+	Impact     string `json:"impact"`
+	Score      int    `json:"score"`
+	Rating     string `json:"rating"`
+	Priority   string `json:"priority,omitempty"`
+}
+
+// Default is the 5x5 matrix used until one is configured: the score is
+// likelihood times impact, from 1 to 25
+func Default() *Matrix {
+	m := &Matrix{
+		Likelihoods: []string{"Rare", "Unlikely", "Possible", "Likely", "Almost Certain"},
+		Impacts:     []string{"Insignificant", "Minor", "Moderate", "Major", "Severe"},
+		Bands: []Band{
+			{Min: 1, Rating: "Low", Priority: "Low"},
+			{Min: 5, Rating: "Medium", Priority: "Medium"},
+			{Min: 10, Rating: "High", Priority: "High"},
+			{Min: 17, Rating: "Critical", Priority: "Highest"},
+		},
+	}
+	for l := range m.Likelihoods {
+		row := make([]int, len(m.Impacts))
+		for i := range m.Impacts {
+			row[i] = (l + 1) * (i + 1)
+		}
+		m.Scores = append(m.Scores, row)
+	}
+	return m
+}
+
+// Validate checks that the matrix is square with its levels and that every
+// score falls in a band
+func (m *Matrix) Validate() error {
+	for _, axis := range []struct {
+		name   string
+		levels []string
+	}{{"likelihoods", m.Likelihoods}, {"impacts", m.Impacts}} {
+		if len(axis.levels) < 2 || len(axis.levels) > maxLevels {
+			return fmt.Errorf("%s must list 2 to %d levels", axis.name, maxLevels)
+		}
+		seen := make(map[string]bool, len(axis.levels))
+		for _, level := range axis.levels {
+			key := strings.ToLower(strings.TrimSpace(level))
+			if key == "" {
+				return fmt.Errorf("%s must not have an empty level", axis.name)
+			}
+			if seen[key] {
+				return fmt.Errorf("%s lists %q twice", axis.name, level)
+			}
+			seen[key] = true
+		}
+	}
+
+	if len(m.Scores) != len(m.Likelihoods) {
+		return fmt.Errorf("scores must have a row for each of the %d likelihoods", len(m.Likelihoods))
+	}
+	for l, row := range m.Scores {
+		if len(row) != len(m.Impacts) {
+			return fmt.Errorf("scores row %d (%s) must have a score for each of the %d impacts", l+1, m.Likelihoods[l], len(m.Impacts))
+		}
+		for _, score := range row {
+			if score < 0 {
+				return fmt.Errorf("scores must not be negative")
+			}
+		}
+	}
+
+	if len(m.Bands) == 0 {
+		return fmt.Errorf("at least one band is required")
+	}
+	lowest := m.Bands[0].Min
+	for _, band := range m.Bands {
+		if band.Rating == "" {
+			return fmt.Errorf("every band needs a rating")
+		}
+		if band.Min < lowest {
+			lowest = band.Min
+		}
+	}
+	for _, row := range m.Scores {
+		for _, score := range row {
+			if score < lowest {
+				return fmt.Errorf("score %d is below every band", score)
+			}
+		}
+	}
+	return nil
+}
+
+// Assess places a likelihood and impact in the matrix; ok is false when
+// either does not match a level
+func (m *Matrix) Assess(likelihood, impact string) (assessment Assessment, ok bool) {
+	l, lok := matchLevel(m.Likelihoods, likelihood)
+	i, iok := matchLevel(m.Impacts, impact)
+	if !lok || !iok {
+		return Assessment{}, false
+	}
+
+	score := m.Scores[l][i]
+	assessment = Assessment{
+		Likelihood: m.Likelihoods[l],
+		Impact:     m.Impacts[i],
+		Score:      score,
+	}
+	best := -1
+	for n, band := range m.Bands {
+		if band.Min <= score && (best < 0 || band.Min > m.Bands[best].Min) {
+			best = n
+		}
+	}
+	if best >= 0 {
+		assessment.Rating = m.Bands[best].Rating
+		assessment.Priority = m.Bands[best].Priority
+	}
+	return assessment, true
+}
+
+// matchLevel finds a ServiceNow value's level: by name, by the name after a
+// leading number, or by the number
+func matchLevel(levels []string, value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	for n, level := range levels {
+		if strings.EqualFold(level, value) {
+			return n, true
+		}
+	}
+
+	digits := strings.IndexFunc(value, func(r rune) bool { return !unicode.IsDigit(r) })
+	if digits < 0 {
+		digits = len(value)
+	}
+	if name := strings.TrimLeft(value[digits:], " -.:"); digits > 0 && name != "" {
+		for n, level := range levels {
+			if strings.EqualFold(level, name) {
+				return n, true
+			}
+		}
+	}
+	if number, err := strconv.Atoi(value[:digits]); err == nil && number >= 1 && number <= len(levels) {
+		return number - 1, true
+	}
+	return 0, false
+}
+
+// clone returns a copy that shares nothing with m
+func (m *Matrix) clone() *Matrix {
+	copied := &Matrix{
+		Likelihoods: append([]string(nil), m.Likelihoods...),
+		Impacts:     append([]string(nil), m.Impacts...),
+		Bands:       append([]Band(nil), m.Bands...),
+	}
+	for _, row := range m.Scores {
+		copied.Scores = append(copied.Scores, append([]int(nil), row...))
+	}
+	return copied
+}
@@ -0,0 +1,93 @@
+// backend/internal/riskmatrix/store.go
+package riskmatrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store keeps a tenant's risk matrix on disk
+type Store struct {
+	mutex    sync.RWMutex
+	matrix   *Matrix
+	filePath string
+}
+
+// NewStore loads the risk matrix from storagePath; without one the default
+// matrix applies
+func NewStore(storagePath string) (*Store, error) {
+	store := &Store{filePath: filepath.Join(storagePath, "risk_matrix.json")}
+
+	if _, err := os.Stat(store.filePath); err == nil {
+		file, err := os.ReadFile(store.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading risk matrix file: %w", err)
+		}
+		var matrix Matrix
+		if err := json.Unmarshal(file, &matrix); err != nil {
+			return nil, fmt.Errorf("error unmarshaling risk matrix: %w", err)
+		}
+		if err := matrix.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid risk matrix in %s: %w", store.filePath, err)
+		}
+		store.matrix = &matrix
+	}
+
+	return store, nil
+}
+
+// Get returns the matrix in effect; a nil store has the default matrix
+func (s *Store) Get() *Matrix {
+	if s == nil {
+		return Default()
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.matrix == nil {
+		return Default()
+	}
+	return s.matrix.clone()
+}
+
+// Customized reports whether a matrix has been configured
+func (s *Store) Customized() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.matrix != nil
+}
+
+// Set replaces the matrix
+func (s *Store) Set(matrix *Matrix) error {
+	if err := matrix.Validate(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := json.MarshalIndent(matrix, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling risk matrix: %w", err)
+	}
+	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing risk matrix file: %w", err)
+	}
+	s.matrix = matrix.clone()
+	return nil
+}
+
+// Reset goes back to the default matrix
+func (s *Store) Reset() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := os.Remove(s.filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing risk matrix file: %w", err)
+	}
+	s.matrix = nil
+	return nil
+}
@@ -0,0 +1,134 @@
+// backend/internal/scoring/engine.go
+package scoring
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+)
+
+// Engine computes compliance scores from live ServiceNow data
+type Engine struct {
+	ServiceNowClient *servicenow.Client
+	Store            *Store
+}
+
+// NewEngine creates a new scoring engine
+func NewEngine(serviceNowClient *servicenow.Client, store *Store) *Engine {
+	return &Engine{
+		ServiceNowClient: serviceNowClient,
+		Store:            store,
+	}
+}
+
+// ScoreReport is a score together with its comparisons to previous periods
+type ScoreReport struct {
+	Score
+	Weights     Weights       `json:"weights"`
+	Comparisons []*Comparison `json:"comparisons"`
+}
+
+// Score computes the tenant's current score, records it and compares it with earlier periods
+func (e *Engine) Score(tenant string) (*ScoreReport, error) {
+	counts, err := e.Counts()
+	if err != nil {
+		return nil, err
+	}
+
+	weights := e.Store.GetWeights(tenant)
+	score := Compute(counts, weights)
+	score.Tenant = tenant
+
+	// Compare before recording so today's snapshot is not compared with itself
+	history := e.Store.GetHistory(tenant)
+	report := &ScoreReport{Score: score, Weights: weights}
+	for _, period := range Periods {
+		comparison, err := Compare(score, history, period)
+		if err != nil {
+			return nil, err
+		}
+		report.Comparisons = append(report.Comparisons, comparison)
+	}
+
+	snapshot := Snapshot{
+		Score:      score.Score,
+		Counts:     counts,
+		Weights:    weights,
+		ComputedAt: score.ComputedAt,
+	}
+	if err := e.Store.Record(tenant, snapshot); err != nil {
+		slog.Warn("could not record compliance score snapshot", "error", err)
+	}
+
+	return report, nil
+}
+
+// Counts gathers the outstanding items for each factor from ServiceNow
+func (e *Engine) Counts() (Counts, error) {
+	now := time.Now()
+	counts := make(Counts)
+
+	risks, err := e.ServiceNowClient.GetRisks()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching risks: %w", err)
+	}
+	for _, risk := range risks {
+		if isOpen(risk.State) && servicenow.RiskSeverity(risk.RiskScore) == "Critical" {
+			counts[FactorOpenCriticals]++
+		}
+	}
+
+	incidents, err := e.ServiceNowClient.GetIncidents()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching incidents: %w", err)
+	}
+	for _, incident := range incidents {
+		if isOpen(incident.State) && isCritical(incident.Severity) {
+			counts[FactorOpenCriticals]++
+		}
+	}
+
+	tasks, err := e.ServiceNowClient.GetComplianceTasks()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching compliance tasks: %w", err)
+	}
+	for _, task := range tasks {
+		if isOpen(task.State) && !task.DueDate.IsZero() && task.DueDate.Before(now) {
+			counts[FactorOverdueTasks]++
+		}
+	}
+
+	tests, err := e.ServiceNowClient.GetControlTests()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching control tests: %w", err)
+	}
+	for _, test := range tests {
+		status := strings.ToLower(test.Status)
+		if status == "fail" || status == "failed" {
+			counts[FactorFailedTests]++
+		}
+	}
+
+	return counts, nil
+}
+
+// isOpen reports whether a ServiceNow state counts as outstanding
+func isOpen(state string) bool {
+	switch strings.ToLower(state) {
+	case "closed", "resolved", "complete", "completed", "cancelled", "canceled":
+		return false
+	}
+	return true
+}
+
+// isCritical reports whether an incident severity is critical
+func isCritical(severity string) bool {
+	switch strings.ToLower(severity) {
+	case "critical", "1", "1 - critical":
+		return true
+	}
+	return false
+}
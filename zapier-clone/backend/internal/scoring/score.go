@@ -0,0 +1,162 @@
+// backend/internal/scoring/score.go
+package scoring
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Score factors
+const (
+	FactorOpenCriticals = "open_criticals"
+	FactorOverdueTasks  = "overdue_tasks"
+	FactorFailedTests   = "failed_tests"
+)
+
+// MaxScore is the score of a tenant with nothing outstanding
+const MaxScore = 100.0
+
+// Weights is the penalty applied per item of each factor
+type Weights map[string]float64
+
+// DefaultWeights are used until a tenant configures its own
+var DefaultWeights = Weights{
+	FactorOpenCriticals: 5,
+	FactorOverdueTasks:  2,
+	FactorFailedTests:   3,
+}
+
+// Validate checks that every weight names a known factor and is not negative
+func (w Weights) Validate() error {
+	for factor, weight := range w {
+		if _, ok := DefaultWeights[factor]; !ok {
+			return fmt.Errorf("unknown factor: %s", factor)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight for %s must not be negative", factor)
+		}
+	}
+	return nil
+}
+
+// merged returns the weights with defaults filled in for unset factors
+func (w Weights) merged() Weights {
+	result := make(Weights, len(DefaultWeights))
+	for factor, weight := range DefaultWeights {
+		result[factor] = weight
+	}
+	for factor, weight := range w {
+		result[factor] = weight
+	}
+	return result
+}
+
+// Counts is the number of outstanding items per factor
+type Counts map[string]int
+
+// FactorScore shows how one factor contributed to the score
+type FactorScore struct {
+	Factor  string  `json:"factor"`
+	Count   int     `json:"count"`
+	Weight  float64 `json:"weight"`
+	Penalty float64 `json:"penalty"`
+}
+
+// Score is a computed compliance score with its breakdown
+type Score struct {
+	Tenant     string        `json:"tenant"`
+	Score      float64       `json:"score"`
+	MaxScore   float64       `json:"max_score"`
+	Penalty    float64       `json:"penalty"`
+	Factors    []FactorScore `json:"factors"`
+	ComputedAt time.Time     `json:"computed_at"`
+}
+
+// Compute scores the counts with the given weights: MaxScore minus the weighted
+// sum of outstanding items, floored at zero
+func Compute(counts Counts, weights Weights) Score {
+	weights = weights.merged()
+
+	factors := make([]string, 0, len(weights))
+	for factor := range weights {
+		factors = append(factors, factor)
+	}
+	sort.Strings(factors)
+
+	score := Score{MaxScore: MaxScore, ComputedAt: time.Now()}
+	for _, factor := range factors {
+		fs := FactorScore{
+			Factor: factor,
+			Count:  counts[factor],
+			Weight: weights[factor],
+		}
+		fs.Penalty = float64(fs.Count) * fs.Weight
+		score.Penalty += fs.Penalty
+		score.Factors = append(score.Factors, fs)
+	}
+
+	score.Score = MaxScore - score.Penalty
+	if score.Score < 0 {
+		score.Score = 0
+	}
+
+	return score
+}
+
+// Comparison contrasts a score with the last snapshot from an earlier period
+type Comparison struct {
+	Period   string    `json:"period"`
+	Previous *Snapshot `json:"previous,omitempty"`
+	Delta    float64   `json:"delta"`
+	// FactorDeltas is the change in item count per factor
+	FactorDeltas map[string]int `json:"factor_deltas,omitempty"`
+}
+
+// Periods that scores can be compared against
+var Periods = []string{"day", "week", "month", "quarter"}
+
+// periodStart returns the cutoff for a period ending at t
+func periodStart(t time.Time, period string) (time.Time, error) {
+	switch period {
+	case "day":
+		return t.AddDate(0, 0, -1), nil
+	case "week":
+		return t.AddDate(0, 0, -7), nil
+	case "month":
+		return t.AddDate(0, -1, 0), nil
+	case "quarter":
+		return t.AddDate(0, -3, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown period: %s", period)
+	}
+}
+
+// Compare finds the latest snapshot at least one period older than the score
+func Compare(score Score, history []Snapshot, period string) (*Comparison, error) {
+	cutoff, err := periodStart(score.ComputedAt, period)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &Comparison{Period: period}
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].ComputedAt.After(cutoff) {
+			previous := history[i]
+			comparison.Previous = &previous
+			break
+		}
+	}
+
+	if comparison.Previous == nil {
+		return comparison, nil
+	}
+
+	comparison.Delta = score.Score - comparison.Previous.Score
+	comparison.FactorDeltas = make(map[string]int)
+	for _, fs := range score.Factors {
+		comparison.FactorDeltas[fs.Factor] = fs.Count - comparison.Previous.Counts[fs.Factor]
+	}
+
+	return comparison, nil
+}
@@ -0,0 +1,131 @@
+// backend/internal/scoring/store.go
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxSnapshots bounds the history kept per tenant (a little over a year of daily snapshots)
+const maxSnapshots = 400
+
+// Snapshot is a stored score used for period comparisons
+type Snapshot struct {
+	Score      float64   `json:"score"`
+	Counts     Counts    `json:"counts"`
+	Weights    Weights   `json:"weights"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// Store persists per-tenant weights and score history to disk
+type Store struct {
+	Weights map[string]Weights    `json:"weights"`
+	History map[string][]Snapshot `json:"history"`
+
+	mutex    sync.RWMutex
+	filePath string
+}
+
+// NewStore loads the score store from storagePath, creating it if needed
+func NewStore(storagePath string) (*Store, error) {
+	filePath := filepath.Join(storagePath, "compliance_scores.json")
+
+	store := &Store{
+		Weights:  make(map[string]Weights),
+		History:  make(map[string][]Snapshot),
+		filePath: filePath,
+	}
+
+	if _, err := os.Stat(filePath); err == nil {
+		file, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading score file: %w", err)
+		}
+
+		if err := json.Unmarshal(file, store); err != nil {
+			return nil, fmt.Errorf("error unmarshaling scores: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// GetWeights returns the tenant's weights with defaults for unset factors
+func (s *Store) GetWeights(tenant string) Weights {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.Weights[tenant].merged()
+}
+
+// SetWeights replaces the tenant's weights
+func (s *Store) SetWeights(tenant string, weights Weights) error {
+	if err := weights.Validate(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Weights[tenant] = weights.merged()
+	return s.save()
+}
+
+// Record adds a snapshot, replacing any earlier snapshot from the same day
+func (s *Store) Record(tenant string, snapshot Snapshot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	history := s.History[tenant]
+	if n := len(history); n > 0 && sameDay(history[n-1].ComputedAt, snapshot.ComputedAt) {
+		history[n-1] = snapshot
+	} else {
+		history = append(history, snapshot)
+	}
+	if len(history) > maxSnapshots {
+		history = history[len(history)-maxSnapshots:]
+	}
+	s.History[tenant] = history
+
+	return s.save()
+}
+
+// GetHistory returns a copy of the tenant's snapshots, oldest first
+func (s *Store) GetHistory(tenant string) []Snapshot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	history := make([]Snapshot, len(s.History[tenant]))
+	copy(history, s.History[tenant])
+	return history
+}
+
+// save persists the store to disk
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling scores: %w", err)
+	}
+
+	// Ensure directory exists
+	dir := filepath.Dir(s.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+		return fmt.Errorf("error writing score file: %w", err)
+	}
+
+	return nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
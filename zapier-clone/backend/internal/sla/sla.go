@@ -0,0 +1,73 @@
+// backend/internal/sla/sla.go
+package sla
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Targets is how long an incident of each severity may stay open, keyed by
+// lowercase severity
+type Targets map[string]time.Duration
+
+// DefaultTargets apply to severities that SLA_TARGETS does not set
+var DefaultTargets = Targets{
+	"critical": 4 * time.Hour,
+	"high":     24 * time.Hour,
+	"medium":   72 * time.Hour,
+	"low":      7 * 24 * time.Hour,
+}
+
+// severityCodes are ServiceNow's numeric severity values
+var severityCodes = map[string]string{
+	"1": "critical",
+	"2": "high",
+	"3": "medium",
+	"4": "low",
+}
+
+// ParseTargets reads "critical=2h,high=12h" over the defaults. Invalid entries
+// are logged and ignored.
+func ParseTargets(spec string) Targets {
+	targets := Targets{}
+	for severity, target := range DefaultTargets {
+		targets[severity] = target
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		severity, value, ok := strings.Cut(entry, "=")
+		target, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil || target <= 0 {
+			slog.Warn("ignoring invalid SLA target", "entry", entry)
+			continue
+		}
+		targets[Severity(severity)] = target
+	}
+	return targets
+}
+
+// Target returns the SLA of a severity; ok is false when it has none
+func (t Targets) Target(severity string) (time.Duration, bool) {
+	target, ok := t[Severity(severity)]
+	return target, ok
+}
+
+// Severity normalizes a severity label or numeric code, such as "2 - High" or "High", to "high"
+func Severity(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if code, label, ok := strings.Cut(value, " - "); ok {
+		if name, known := severityCodes[strings.TrimSpace(code)]; known {
+			return name
+		}
+		value = strings.TrimSpace(label)
+	}
+	if name, known := severityCodes[value]; known {
+		return name
+	}
+	return value
+}
@@ -0,0 +1,499 @@
+// backend/internal/sla/tracker.go
+package sla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/jira"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/servicenow"
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/slack"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"github.com/shivani-1505/zapier-clone/backend/internal/notification"
+)
+
+// WarnAt is the share of an SLA after which a warning is posted
+const WarnAt = 0.75
+
+// serviceNowTimeLayout is how the Table API formats date-times
+const serviceNowTimeLayout = "2006-01-02 15:04:05"
+
+// batchSize is the number of incidents read per ServiceNow request
+const batchSize = 100
+
+// closedStates are the incident states (labels and common numeric codes) that stop the clock
+var closedStates = map[string]bool{
+	"closed":    true,
+	"resolved":  true,
+	"cancelled": true,
+	"3":         true,
+	"4":         true,
+	"7":         true,
+}
+
+// Clock is the SLA of one incident linked to a Jira epic. It runs from when
+// the incident was opened until either side is resolved.
+type Clock struct {
+	SysID    string        `json:"sys_id"`
+	Number   string        `json:"number"`
+	JiraKey  string        `json:"jira_key"`
+	Severity string        `json:"severity"`
+	Target   time.Duration `json:"target_ns"`
+	OpenedAt time.Time     `json:"opened_at"`
+	DueBy    time.Time     `json:"due_by"`
+	// StoppedAt is when the incident or its epic was first seen resolved
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+	// StoppedBy is the side that was resolved: servicenow or jira
+	StoppedBy  string     `json:"stopped_by,omitempty"`
+	WarnedAt   *time.Time `json:"warned_at,omitempty"`
+	BreachedAt *time.Time `json:"breached_at,omitempty"`
+}
+
+// Elapsed is how much of the SLA has been used by now, or by when the clock stopped
+func (c *Clock) Elapsed(now time.Time) time.Duration {
+	if c.StoppedAt != nil {
+		now = *c.StoppedAt
+	}
+	return now.Sub(c.OpenedAt)
+}
+
+// Breach is a clock that ran past its due-by time, as served by the API
+type Breach struct {
+	Clock
+	TargetText  string `json:"target"`
+	ElapsedText string `json:"elapsed"`
+	OverdueText string `json:"overdue"`
+	Open        bool   `json:"open"`
+}
+
+// Tracker starts an SLA clock for every incident linked to a Jira epic and
+// warns in Slack when an incident has used 75% and 100% of it
+type Tracker struct {
+	ServiceNowClient *servicenow.Client
+	JiraClient       *jira.Client
+	SlackClient      *slack.Client
+	Incidents        *jira.IncidentJiraMapping
+	// Threads lets warnings reply to an incident's announcement; without one
+	// they are posted to Channel
+	Threads *slack.ThreadStore
+	Channel string
+	// Routes gives the language of each warning's channel; nil writes English
+	Routes   *notification.Router
+	Targets  Targets
+	Interval time.Duration
+
+	mutex    sync.Mutex
+	clocks   map[string]*Clock
+	checking bool
+	running  bool
+	stopChan chan struct{}
+	filePath string
+	now      func() time.Time
+}
+
+// NewTracker creates a tracker with the default targets that checks every
+// five minutes and keeps its clocks in storagePath
+func NewTracker(storagePath string, serviceNowClient *servicenow.Client, jiraClient *jira.Client, slackClient *slack.Client, incidents *jira.IncidentJiraMapping, threads *slack.ThreadStore) (*Tracker, error) {
+	t := &Tracker{
+		ServiceNowClient: serviceNowClient,
+		JiraClient:       jiraClient,
+		SlackClient:      slackClient,
+		Incidents:        incidents,
+		Threads:          threads,
+		Channel:          slack.ChannelMapping["incident"],
+		Targets:          ParseTargets(""),
+		Interval:         5 * time.Minute,
+		clocks:           make(map[string]*Clock),
+		stopChan:         make(chan struct{}),
+		filePath:         filepath.Join(storagePath, "sla_clocks.json"),
+		now:              time.Now,
+	}
+
+	if _, err := os.Stat(t.filePath); err == nil {
+		file, err := os.ReadFile(t.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading SLA file: %w", err)
+		}
+		if err := json.Unmarshal(file, &t.clocks); err != nil {
+			return nil, fmt.Errorf("error unmarshaling SLA clocks: %w", err)
+		}
+	}
+
+	return t, nil
+}
+
+// ConfigureFromEnv reads SLA_TARGETS ("critical=2h,high=12h") and
+// SLA_CHECK_INTERVAL. It returns false when the interval is "0", leaving the
+// checks off.
+func (t *Tracker) ConfigureFromEnv() bool {
+	t.Targets = ParseTargets(os.Getenv("SLA_TARGETS"))
+
+	value := os.Getenv("SLA_CHECK_INTERVAL")
+	if value == "0" {
+		return false
+	}
+	if value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			slog.Warn("ignoring invalid SLA_CHECK_INTERVAL", "value", value)
+		} else {
+			t.Interval = interval
+		}
+	}
+	return true
+}
+
+// Start checks the clocks every Interval until Stop
+func (t *Tracker) Start() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.running || t.Interval <= 0 {
+		return
+	}
+	t.running = true
+
+	go func() {
+		ticker := time.NewTicker(t.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stopChan:
+				return
+			case <-ticker.C:
+				if err := t.Check(context.Background()); err != nil {
+					slog.Error("error checking SLAs", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduled checks
+func (t *Tracker) Stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !t.running {
+		return
+	}
+	t.running = false
+	close(t.stopChan)
+}
+
+// Check starts clocks for newly linked incidents, stops those resolved on
+// either side and posts the warnings that are due. A check already in
+// progress is not repeated.
+func (t *Tracker) Check(ctx context.Context) error {
+	t.mutex.Lock()
+	if t.checking {
+		t.mutex.Unlock()
+		return nil
+	}
+	t.checking = true
+	t.mutex.Unlock()
+	defer func() {
+		t.mutex.Lock()
+		t.checking = false
+		t.mutex.Unlock()
+	}()
+
+	ctx = logging.NewContext(ctx)
+	serviceNowClient := t.ServiceNowClient.WithContext(ctx)
+	jiraClient := t.JiraClient.WithContext(ctx)
+	slackClient := t.SlackClient.WithContext(ctx)
+
+	// Only incidents whose clock is still running need reading
+	forward, _ := t.Incidents.Snapshot()
+	var sysIDs []string
+	t.mutex.Lock()
+	for sysID := range forward {
+		if clock, ok := t.clocks[sysID]; !ok || clock.StoppedAt == nil {
+			sysIDs = append(sysIDs, sysID)
+		}
+	}
+	t.mutex.Unlock()
+	sort.Strings(sysIDs)
+
+	for start := 0; start < len(sysIDs); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := start + batchSize
+		if end > len(sysIDs) {
+			end = len(sysIDs)
+		}
+
+		query := servicenow.NewQuery().In("sys_id", sysIDs[start:end]...)
+		iter := serviceNowClient.Records("sn_si_incident", servicenow.ListOptions{Query: query.String(), PageSize: batchSize})
+		for iter.Next() {
+			record := iter.Record()
+			sysID := stringValue(record["sys_id"])
+			t.update(slackClient, jiraClient, record, sysID, forward[sysID])
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("error reading incidents for SLA check: %w", err)
+		}
+	}
+
+	t.save()
+	return nil
+}
+
+// update advances one incident's clock and posts any warning now due
+func (t *Tracker) update(slackClient *slack.Client, jiraClient *jira.Client, record map[string]interface{}, sysID, jiraKey string) {
+	now := t.now()
+
+	t.mutex.Lock()
+	clock, ok := t.clocks[sysID]
+	t.mutex.Unlock()
+	if !ok {
+		severity := referenceValue(record["severity"])
+		target, hasTarget := t.Targets.Target(severity)
+		opened := openedAt(record)
+		if !hasTarget || opened.IsZero() {
+			return
+		}
+		clock = &Clock{
+			SysID:    sysID,
+			Number:   stringValue(record["number"]),
+			JiraKey:  jiraKey,
+			Severity: Severity(severity),
+			Target:   target,
+			OpenedAt: opened,
+			DueBy:    opened.Add(target),
+		}
+		t.mutex.Lock()
+		t.clocks[sysID] = clock
+		t.mutex.Unlock()
+	}
+
+	// The clock stops as soon as either side of the pair is resolved
+	stoppedBy := ""
+	if closedStates[strings.ToLower(referenceValue(record["state"]))] {
+		stoppedBy = "servicenow"
+	} else if issue, err := jiraClient.GetIssue(clock.JiraKey); err == nil && issueDone(issue) {
+		stoppedBy = "jira"
+	}
+
+	t.mutex.Lock()
+	if stoppedBy != "" {
+		clock.StoppedAt = &now
+		clock.StoppedBy = stoppedBy
+	}
+	elapsed := clock.Elapsed(now)
+	var warning string
+	switch {
+	case elapsed >= clock.Target && clock.BreachedAt == nil:
+		breached := clock.DueBy
+		clock.BreachedAt = &breached
+		warning = "sla.breached"
+	case clock.StoppedAt == nil && elapsed >= time.Duration(float64(clock.Target)*WarnAt) && clock.WarnedAt == nil:
+		clock.WarnedAt = &now
+		warning = "sla.warning"
+	}
+	// A breach also covers the 75% warning
+	if clock.BreachedAt != nil && clock.WarnedAt == nil {
+		clock.WarnedAt = &now
+	}
+	copied := *clock
+	t.mutex.Unlock()
+
+	if warning != "" {
+		t.warn(slackClient, copied, warning)
+	}
+}
+
+// warn posts the warning with catalog key in the incident's thread, or in
+// Channel when it has none, in the language of the channel
+func (t *Tracker) warn(slackClient *slack.Client, clock Clock, key string) {
+	thread, threaded := t.thread(clock.SysID)
+	channel := t.Channel
+	if threaded {
+		channel = thread.Channel
+	}
+	locale := t.Routes.Locale(channel)
+
+	severity := i18n.Value(locale, "severity", clock.Severity)
+	due := i18n.DateTime(locale, clock.DueBy)
+	var text string
+	if key == "sla.breached" {
+		text = i18n.T(locale, key, clock.Number, formatDuration(clock.Target), severity, due)
+	} else {
+		text = i18n.T(locale, key, clock.Number, int(WarnAt*100), formatDuration(clock.Target), severity, due)
+	}
+	if clock.JiraKey != "" && t.JiraClient != nil {
+		text += fmt.Sprintf(" · Jira <%s/browse/%s|%s>", t.JiraClient.BaseURL, clock.JiraKey, clock.JiraKey)
+	}
+	message := slack.Message{Text: text}
+
+	var err error
+	if threaded {
+		_, err = slackClient.PostReply(thread.Channel, thread.TS, message)
+	} else {
+		_, err = slackClient.PostMessage(t.Channel, message)
+	}
+	if err != nil {
+		slackClient.Logger().Error("error posting SLA warning", "sys_id", clock.SysID, "error", err)
+	}
+}
+
+// thread returns the Slack thread an incident was announced in
+func (t *Tracker) thread(sysID string) (slack.Thread, bool) {
+	if t.Threads == nil {
+		return slack.Thread{}, false
+	}
+	return t.Threads.Get(sysID)
+}
+
+// Breaches returns the clocks that ran past their due-by time, most overdue
+// first. With openOnly, incidents that have since been resolved are left out.
+func (t *Tracker) Breaches(openOnly bool) []Breach {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	breaches := []Breach{}
+	for _, clock := range t.clocks {
+		elapsed := clock.Elapsed(now)
+		if elapsed < clock.Target || (openOnly && clock.StoppedAt != nil) {
+			continue
+		}
+		breaches = append(breaches, Breach{
+			Clock:       *clock,
+			TargetText:  formatDuration(clock.Target),
+			ElapsedText: formatDuration(elapsed),
+			OverdueText: formatDuration(elapsed - clock.Target),
+			Open:        clock.StoppedAt == nil,
+		})
+	}
+	sort.Slice(breaches, func(i, j int) bool {
+		return breaches[i].Elapsed(now)-breaches[i].Target > breaches[j].Elapsed(now)-breaches[j].Target
+	})
+	return breaches
+}
+
+// Stats summarizes the clocks of incidents opened in a period
+type Stats struct {
+	Tracked int `json:"tracked"`
+	// Met were resolved within their SLA
+	Met      int `json:"met"`
+	Breached int `json:"breached"`
+	// Running are still open and within their SLA
+	Running int `json:"running"`
+	// OpenBreaches are breached incidents still open, whenever they were opened
+	OpenBreaches int `json:"open_breaches"`
+	// MetPercent is Met as a share of the met and breached clocks; 100 when there are none
+	MetPercent int `json:"met_percent"`
+}
+
+// Stats counts the clocks of incidents opened since since
+func (t *Tracker) Stats(since time.Time) Stats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	var stats Stats
+	for _, clock := range t.clocks {
+		breached := clock.Elapsed(now) >= clock.Target
+		if breached && clock.StoppedAt == nil {
+			stats.OpenBreaches++
+		}
+		if clock.OpenedAt.Before(since) {
+			continue
+		}
+		stats.Tracked++
+		switch {
+		case breached:
+			stats.Breached++
+		case clock.StoppedAt != nil:
+			stats.Met++
+		default:
+			stats.Running++
+		}
+	}
+
+	stats.MetPercent = 100
+	if finished := stats.Met + stats.Breached; finished > 0 {
+		stats.MetPercent = stats.Met * 100 / finished
+	}
+	return stats
+}
+
+// save writes the clocks to disk; failures are logged
+func (t *Tracker) save() {
+	t.mutex.Lock()
+	data, err := json.MarshalIndent(t.clocks, "", "  ")
+	t.mutex.Unlock()
+	if err != nil {
+		slog.Error("error marshaling SLA clocks", "error", err)
+		return
+	}
+	if err := os.WriteFile(t.filePath, data, 0644); err != nil {
+		slog.Error("error saving SLA clocks", "error", err)
+	}
+}
+
+// openedAt reads when an incident was opened, falling back to when it was created
+func openedAt(record map[string]interface{}) time.Time {
+	for _, field := range []string{"opened_at", "sys_created_on"} {
+		if opened, err := time.Parse(serviceNowTimeLayout, stringValue(record[field])); err == nil {
+			return opened
+		}
+		if opened, err := time.Parse(time.RFC3339, stringValue(record[field])); err == nil {
+			return opened
+		}
+	}
+	return time.Time{}
+}
+
+// issueDone reports whether a Jira issue is in the done status category
+func issueDone(issue map[string]interface{}) bool {
+	fields, _ := issue["fields"].(map[string]interface{})
+	status, _ := fields["status"].(map[string]interface{})
+	category, _ := status["statusCategory"].(map[string]interface{})
+	return stringValue(category["key"]) == "done"
+}
+
+// formatDuration prints a duration in whole minutes, e.g. "26h5m" or "4h"
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "0m"
+	}
+	text := strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// referenceValue reads a field returned as a string or as
+// {"display_value": ..., "value": ...}
+func referenceValue(value interface{}) string {
+	if ref, ok := value.(map[string]interface{}); ok {
+		if display := stringValue(ref["display_value"]); display != "" {
+			return display
+		}
+		return stringValue(ref["value"])
+	}
+	return stringValue(value)
+}
+
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
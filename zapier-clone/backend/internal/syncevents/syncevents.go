@@ -0,0 +1,161 @@
+// backend/internal/syncevents/syncevents.go
+package syncevents
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sources of sync events
+const (
+	SourceServiceNow = "servicenow"
+	SourceJira       = "jira"
+)
+
+// Results of a sync
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	// ResultSkipped is an event held back by the loop guard or conflict detector
+	ResultSkipped = "skipped"
+)
+
+// DefaultBuffer is how many events a subscriber may fall behind by before
+// further events are dropped for it
+const DefaultBuffer = 256
+
+// Event is one inbound change the backend finished syncing
+type Event struct {
+	// Sequence increases by one for every event published on a hub
+	Sequence uint64    `json:"sequence"`
+	Time     time.Time `json:"time"`
+	Tenant   string    `json:"tenant"`
+	Source   string    `json:"source"`
+	// Action is the ServiceNow action (inserted, updated, deleted) or the Jira
+	// webhook event (e.g. jira:issue_updated)
+	Action string `json:"action"`
+	// Table is the ServiceNow table; empty for Jira events
+	Table    string `json:"table,omitempty"`
+	SysID    string `json:"sys_id,omitempty"`
+	Number   string `json:"number,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	JiraKey  string `json:"jira_key,omitempty"`
+	Result   string `json:"result"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Filter selects events; an empty list matches every value. Values are
+// compared case-insensitively.
+type Filter struct {
+	Tables     []string
+	Severities []string
+	Sources    []string
+}
+
+// Matches reports whether an event passes the filter
+func (f Filter) Matches(event Event) bool {
+	return matchesAny(f.Tables, event.Table) && matchesAny(f.Severities, event.Severity) && matchesAny(f.Sources, event.Source)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscription is one consumer's filtered channel
+type subscription struct {
+	filter  Filter
+	events  chan Event
+	dropped uint64
+}
+
+// Hub fans sync events out to subscribers. Publishing never blocks: a
+// subscriber whose buffer is full misses events, and the misses are counted.
+type Hub struct {
+	Tenant string
+
+	mu          sync.RWMutex
+	subscribers map[int]*subscription
+	nextID      int
+	sequence    uint64
+	now         func() time.Time
+}
+
+// NewHub creates a hub for a tenant's events
+func NewHub(tenant string) *Hub {
+	return &Hub{
+		Tenant:      tenant,
+		subscribers: make(map[int]*subscription),
+		now:         time.Now,
+	}
+}
+
+// Publish stamps an event and hands it to every matching subscriber. A nil
+// hub discards events.
+func (h *Hub) Publish(event Event) {
+	if h == nil {
+		return
+	}
+	event.Sequence = atomic.AddUint64(&h.sequence, 1)
+	if event.Time.IsZero() {
+		event.Time = h.now().UTC()
+	}
+	event.Tenant = h.Tenant
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, sub := range h.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+		}
+	}
+}
+
+// Subscribe returns a channel of events matching filter and a function that
+// ends the subscription and closes the channel. dropped reports how many
+// events were missed because the channel was full.
+func (h *Hub) Subscribe(filter Filter, buffer int) (events <-chan Event, dropped func() uint64, cancel func()) {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	sub := &subscription{filter: filter, events: make(chan Event, buffer)}
+
+	h.mu.Lock()
+	id := h.nextID
+	h.nextID++
+	h.subscribers[id] = sub
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, id)
+			h.mu.Unlock()
+			close(sub.events)
+		})
+	}
+	dropped = func() uint64 { return atomic.LoadUint64(&sub.dropped) }
+	return sub.events, dropped, cancel
+}
+
+// Subscribers returns how many consumers are subscribed
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
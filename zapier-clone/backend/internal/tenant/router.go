@@ -0,0 +1,207 @@
+// backend/internal/tenant/router.go
+package tenant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/auth"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// HeaderID names the tenant on a request; the router sets it on every
+// request it dispatches so handlers can read the resolved tenant
+const HeaderID = "X-Tenant-ID"
+
+// HeaderAPIKey carries a tenant API key
+const HeaderAPIKey = "X-API-Key"
+
+// maxSlackBody bounds how much of a Slack request is read to find the team
+const maxSlackBody = 1 << 20
+
+// ErrTenantRequired is returned when a request names no tenant and there is no default
+var ErrTenantRequired = errors.New("tenant required")
+
+// ErrTenantMismatch is returned when an API key or access token and the
+// header name different tenants
+var ErrTenantMismatch = errors.New("credentials do not belong to the requested tenant")
+
+// ErrInvalidToken is returned when a request's access token cannot be verified
+var ErrInvalidToken = errors.New("invalid or expired access token")
+
+type contextKey struct{}
+
+// WithTenant returns a context carrying the tenant
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant resolved for a request
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	t, ok := ctx.Value(contextKey{}).(*Tenant)
+	return t, ok
+}
+
+// Resolve picks the tenant for a request, in order: the X-API-Key header, the
+// tenant of the bearer access token, the X-Tenant-ID header or ?tenant= query
+// (for webhooks that cannot set headers), the Slack team of a Slack request,
+// then the default tenant. A header or query naming another tenant than the
+// key or token is refused. Requests without a key or token only reach routes
+// that check a credential of the tenant they name, such as webhook secrets.
+// Bearer tokens on webhook and relay routes are webhook API keys, not access
+// tokens, so they do not select the tenant.
+func (reg *Registry) Resolve(r *http.Request) (*Tenant, error) {
+	requested := r.Header.Get(HeaderID)
+	if requested == "" {
+		requested = r.URL.Query().Get("tenant")
+	}
+
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		t, ok := reg.ByAPIKey(key)
+		if !ok {
+			return nil, ErrUnknownTenant
+		}
+		if requested != "" && requested != t.ID {
+			return nil, ErrTenantMismatch
+		}
+		return t, nil
+	}
+
+	if token := auth.BearerToken(r); token != "" && reg.TokenTenant != nil && !webhookPath(r.URL.Path) {
+		id, err := reg.TokenTenant(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		if id == "" {
+			// Tokens issued before accounts had tenants
+			id = DefaultID
+		}
+		if requested != "" && requested != id {
+			return nil, ErrTenantMismatch
+		}
+		t, ok := reg.Get(id)
+		if !ok {
+			return nil, ErrUnknownTenant
+		}
+		return t, nil
+	}
+
+	if requested != "" {
+		t, ok := reg.Get(requested)
+		if !ok {
+			return nil, ErrUnknownTenant
+		}
+		return t, nil
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/slack/") {
+		if teamID := slackTeamID(r); teamID != "" {
+			if t, ok := reg.BySlackTeam(teamID); ok {
+				return t, nil
+			}
+		}
+	}
+
+	if reg.Default != nil {
+		return reg.Default, nil
+	}
+	return nil, ErrTenantRequired
+}
+
+// webhookPath reports whether a path is served to webhook senders, whose
+// bearer tokens are API keys checked by the webhook routes
+func webhookPath(path string) bool {
+	return strings.HasPrefix(path, "/api/webhooks/") || strings.HasPrefix(path, "/api/relay/")
+}
+
+// slackTeamID reads the workspace from a slash command (team_id) or an
+// interaction payload (team.id), restoring the body for signature checks
+func slackTeamID(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackBody))
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+	if teamID := form.Get("team_id"); teamID != "" {
+		return teamID
+	}
+
+	var payload struct {
+		Team struct {
+			ID string `json:"id"`
+		} `json:"team"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		return ""
+	}
+	return payload.Team.ID
+}
+
+// Router dispatches each request to the handler built for its tenant
+type Router struct {
+	Registry *Registry
+	Handlers map[string]http.Handler
+	// Fallback serves paths outside /api/ (health checks, the web UI) when no
+	// tenant is resolved
+	Fallback http.Handler
+}
+
+// NewRouter creates a tenant router
+func NewRouter(registry *Registry, handlers map[string]http.Handler, fallback http.Handler) *Router {
+	return &Router{
+		Registry: registry,
+		Handlers: handlers,
+		Fallback: fallback,
+	}
+}
+
+// ServeHTTP resolves the tenant and hands the request to its handler
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t, err := rt.Registry.Resolve(r)
+	if err != nil {
+		if rt.Fallback != nil && !strings.HasPrefix(r.URL.Path, "/api/") {
+			rt.Fallback.ServeHTTP(w, r)
+			return
+		}
+
+		switch {
+		case errors.Is(err, ErrTenantRequired):
+			http.Error(w, "A tenant is required: send X-API-Key or X-Tenant-ID", http.StatusUnauthorized)
+		case errors.Is(err, ErrTenantMismatch):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ErrInvalidToken):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			http.Error(w, ErrInvalidToken.Error(), http.StatusUnauthorized)
+		default:
+			logging.FromContext(r.Context()).Warn("rejected request", "path", r.URL.Path, "error", err)
+			http.Error(w, "Unknown tenant", http.StatusUnauthorized)
+		}
+		return
+	}
+
+	handler, ok := rt.Handlers[t.ID]
+	if !ok {
+		http.Error(w, "Tenant is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	r.Header.Set(HeaderID, t.ID)
+	handler.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), t)))
+}
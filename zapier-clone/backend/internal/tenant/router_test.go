@@ -0,0 +1,70 @@
+// backend/internal/tenant/router_test.go
+package tenant
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testTenant returns a tenant that passes validation
+func testTenant(id string) *Tenant {
+	return &Tenant{
+		ID:         id,
+		ServiceNow: ServiceNowConfig{URL: "http://servicenow", Username: "user", Password: "pass"},
+		Jira:       JiraConfig{URL: "http://jira", Email: "jira@example.com", APIToken: "token", ProjectKey: "GRC"},
+	}
+}
+
+func TestResolveBearerToken(t *testing.T) {
+	reg, err := NewRegistry([]*Tenant{testTenant("acme")}, testTenant(DefaultID))
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	reg.TokenTenant = func(token string) (string, error) {
+		if token == "access-token" {
+			return "acme", nil
+		}
+		return "", errors.New("token is malformed")
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		token   string
+		header  string
+		want    string
+		wantErr error
+	}{
+		{name: "access token selects its tenant", path: "/api/v1/workflows", token: "access-token", want: "acme"},
+		{name: "invalid access token", path: "/api/v1/workflows", token: "grc_webhookkey", wantErr: ErrInvalidToken},
+		{name: "webhook key as bearer token", path: "/api/webhooks/pagerduty", token: "grc_webhookkey", want: DefaultID},
+		{name: "webhook key with tenant header", path: "/api/webhooks/servicenow", token: "grc_webhookkey", header: "acme", want: "acme"},
+		{name: "relay key as bearer token", path: "/api/relay/ingest", token: "grc_webhookkey", want: DefaultID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+			if tt.header != "" {
+				r.Header.Set(HeaderID, tt.header)
+			}
+
+			got, err := reg.Resolve(r)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("Resolve tenant = %q, want %q", got.ID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,427 @@
+// backend/internal/tenant/tenant.go
+package tenant
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/i18n"
+)
+
+// DefaultID is the tenant built from the process environment
+const DefaultID = "default"
+
+// ErrUnknownTenant is returned when a request names a tenant that is not configured
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// validID keeps tenant IDs safe to use as directory names and header values
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// validKeyPrefix leaves room in a Jira project key for a table's initials
+var validKeyPrefix = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,5}$`)
+
+// ServiceNowConfig is a tenant's ServiceNow instance
+type ServiceNowConfig struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Tables sends each table's tickets to a project named in jira.projects
+	// instead of jira.project_key, e.g. {"sn_si_incident": "security"}
+	Tables map[string]string `json:"tables,omitempty"`
+}
+
+// JiraConfig is a tenant's Jira site and project
+type JiraConfig struct {
+	URL        string `json:"url"`
+	Email      string `json:"email"`
+	APIToken   string `json:"api_token"`
+	ProjectKey string `json:"project_key"`
+	// APIVersion is the REST API version, "2" or "3" (Jira Cloud, ADF descriptions); empty means 2
+	APIVersion string `json:"api_version,omitempty"`
+	// Projects names the tenant's other Jira projects, e.g. {"security": "SEC"}
+	Projects map[string]string `json:"projects,omitempty"`
+	// AutoProvision is "project" or "component" to give tables without a
+	// project their own on their first ticket; empty sends them to project_key
+	AutoProvision string `json:"auto_provision,omitempty"`
+	// ProvisionKeyPrefix starts provisioned project keys; empty means GRC
+	ProvisionKeyPrefix string `json:"provision_key_prefix,omitempty"`
+	// ProvisionLead is the account ID of provisioned projects' lead
+	ProvisionLead string `json:"provision_lead,omitempty"`
+}
+
+// GitHubConfig is the repository a tenant files audit findings and compliance
+// tasks in; an empty Repo leaves GitHub off
+type GitHubConfig struct {
+	Token string `json:"token"`
+	// Repo is "owner/name"
+	Repo string `json:"repo"`
+	// APIURL is the REST API root; empty means https://api.github.com
+	APIURL string `json:"api_url,omitempty"`
+}
+
+// SlackConfig is a tenant's Slack workspace
+type SlackConfig struct {
+	Token string `json:"token"`
+	// TeamID routes Slack commands and interactions from this workspace
+	TeamID string `json:"team_id"`
+}
+
+// TeamsConfig is the Microsoft Teams bot a tenant notifies through; an empty
+// AppID leaves Teams off
+type TeamsConfig struct {
+	AppID       string `json:"app_id"`
+	AppPassword string `json:"app_password"`
+	// TenantID is the Azure AD tenant of a single-tenant bot
+	TenantID   string `json:"tenant_id,omitempty"`
+	ServiceURL string `json:"service_url,omitempty"`
+	// Channels maps keys such as "incident" to Teams channel IDs
+	Channels map[string]string `json:"channels,omitempty"`
+	// Default sends notifications that match no routing rule to Teams
+	Default bool `json:"default,omitempty"`
+}
+
+// NotificationConfig sets the language of a tenant's Slack and Teams
+// notifications. Rules from the notification rules API can set a locale for
+// their own channels, which wins over both.
+type NotificationConfig struct {
+	// Locale is the default language, such as "es"; empty means English
+	Locale string `json:"locale,omitempty"`
+	// ChannelLocales sets single channels' languages, keyed by channel name,
+	// ID or ChannelMapping key, e.g. {"compliance": "es"}
+	ChannelLocales map[string]string `json:"channel_locales,omitempty"`
+}
+
+// Tenant is an organization with its own integrations and mapping tables
+type Tenant struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	ServiceNow ServiceNowConfig `json:"servicenow"`
+	Jira       JiraConfig       `json:"jira"`
+	GitHub     GitHubConfig     `json:"github"`
+	Slack      SlackConfig      `json:"slack"`
+	Teams      TeamsConfig      `json:"teams"`
+	// Notifications sets the language messages are written in
+	Notifications NotificationConfig `json:"notifications"`
+	// DataDir holds the tenant's mapping files; defaults to ./data/tenants/<id>
+	DataDir string `json:"data_dir"`
+	// APIKeyHashes are hex SHA-256 hashes of the keys that select this tenant
+	APIKeyHashes []string `json:"api_key_hashes"`
+}
+
+// HashAPIKey returns the hash stored in api_key_hashes for a key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Registry holds the configured tenants
+type Registry struct {
+	tenants map[string]*Tenant
+	// keys maps API key hashes to tenant IDs
+	keys  map[string]string
+	teams map[string]string
+	// Default serves requests that do not name a tenant; nil requires one
+	Default *Tenant
+	// TokenTenant verifies an access token and returns the tenant it was
+	// issued for
+	TokenTenant func(token string) (string, error)
+}
+
+// NewRegistry validates tenants and indexes their API keys and Slack teams
+func NewRegistry(tenants []*Tenant, defaultTenant *Tenant) (*Registry, error) {
+	reg := &Registry{
+		tenants: make(map[string]*Tenant),
+		keys:    make(map[string]string),
+		teams:   make(map[string]string),
+		Default: defaultTenant,
+	}
+
+	all := tenants
+	if defaultTenant != nil {
+		all = append([]*Tenant{defaultTenant}, tenants...)
+	}
+	for _, t := range all {
+		if err := reg.add(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return reg, nil
+}
+
+// add validates and indexes one tenant
+func (reg *Registry) add(t *Tenant) error {
+	if !validID.MatchString(t.ID) {
+		return fmt.Errorf("invalid tenant ID %q: use lowercase letters, digits, '-' and '_'", t.ID)
+	}
+	if _, exists := reg.tenants[t.ID]; exists {
+		return fmt.Errorf("duplicate tenant ID %q", t.ID)
+	}
+	if t.DataDir == "" {
+		t.DataDir = filepath.Join("./data", "tenants", t.ID)
+	}
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("tenant %s: %w", t.ID, err)
+	}
+
+	for _, hash := range t.APIKeyHashes {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if len(hash) != sha256.Size*2 {
+			return fmt.Errorf("tenant %s: api_key_hashes must be hex SHA-256 digests", t.ID)
+		}
+		if owner, exists := reg.keys[hash]; exists {
+			return fmt.Errorf("tenant %s: API key is already assigned to tenant %s", t.ID, owner)
+		}
+		reg.keys[hash] = t.ID
+	}
+
+	if t.Slack.TeamID != "" {
+		if owner, exists := reg.teams[t.Slack.TeamID]; exists {
+			return fmt.Errorf("tenant %s: Slack team %s is already assigned to tenant %s", t.ID, t.Slack.TeamID, owner)
+		}
+		reg.teams[t.Slack.TeamID] = t.ID
+	}
+
+	reg.tenants[t.ID] = t
+	return nil
+}
+
+// Validate checks that the tenant's ServiceNow and Jira credentials are set
+// and that its optional settings are well formed
+func (t *Tenant) Validate() error {
+	var missing []string
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"servicenow url", t.ServiceNow.URL},
+		{"servicenow username", t.ServiceNow.Username},
+		{"servicenow password", t.ServiceNow.Password},
+		{"jira url", t.Jira.URL},
+		{"jira email", t.Jira.Email},
+		{"jira api_token", t.Jira.APIToken},
+		{"jira project_key", t.Jira.ProjectKey},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	if v := t.Jira.APIVersion; v != "" && v != "2" && v != "3" {
+		return fmt.Errorf("jira api_version must be 2 or 3, got %q", v)
+	}
+	if mode := t.Jira.AutoProvision; mode != "" && mode != "project" && mode != "component" {
+		return fmt.Errorf("jira auto_provision must be project or component, got %q", mode)
+	}
+	if prefix := t.Jira.ProvisionKeyPrefix; prefix != "" && !validKeyPrefix.MatchString(prefix) {
+		return fmt.Errorf("jira provision_key_prefix must be up to 6 capital letters or digits, starting with a letter, got %q", prefix)
+	}
+	for table, name := range t.ServiceNow.Tables {
+		if t.Jira.Projects[name] == "" {
+			return fmt.Errorf("servicenow table %s uses Jira project %q, which is not in jira projects", table, name)
+		}
+	}
+	if repo := t.GitHub.Repo; repo != "" {
+		owner, name, ok := strings.Cut(repo, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("github repo must be owner/name, got %q", repo)
+		}
+	}
+	if locale := t.Notifications.Locale; locale != "" && i18n.Normalize(locale) == "" {
+		return fmt.Errorf("notifications locale %q has no message catalog; use one of %s", locale, strings.Join(i18n.Supported(), ", "))
+	}
+	for channel, locale := range t.Notifications.ChannelLocales {
+		if i18n.Normalize(locale) == "" {
+			return fmt.Errorf("notifications locale %q of channel %s has no message catalog; use one of %s", locale, channel, strings.Join(i18n.Supported(), ", "))
+		}
+	}
+	return nil
+}
+
+// TableProjects maps ServiceNow tables to the Jira project keys their tickets go to
+func (t *Tenant) TableProjects() map[string]string {
+	projects := make(map[string]string, len(t.ServiceNow.Tables))
+	for table, name := range t.ServiceNow.Tables {
+		projects[table] = t.Jira.Projects[name]
+	}
+	return projects
+}
+
+// Get returns a tenant by ID
+func (reg *Registry) Get(id string) (*Tenant, bool) {
+	t, ok := reg.tenants[id]
+	return t, ok
+}
+
+// ByAPIKey returns the tenant an API key belongs to
+func (reg *Registry) ByAPIKey(key string) (*Tenant, bool) {
+	hash := HashAPIKey(key)
+	for stored, id := range reg.keys {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			return reg.tenants[id], true
+		}
+	}
+	return nil, false
+}
+
+// BySlackTeam returns the tenant linked to a Slack workspace
+func (reg *Registry) BySlackTeam(teamID string) (*Tenant, bool) {
+	id, ok := reg.teams[teamID]
+	if !ok {
+		return nil, false
+	}
+	return reg.tenants[id], true
+}
+
+// All returns every tenant sorted by ID
+func (reg *Registry) All() []*Tenant {
+	list := make([]*Tenant, 0, len(reg.tenants))
+	for _, t := range reg.tenants {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// LoadFile reads tenants from a JSON array. ${VAR} references are expanded
+// from the environment so secrets can stay out of the file.
+func LoadFile(path string) ([]*Tenant, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tenants file: %w", err)
+	}
+
+	var tenants []*Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("error parsing tenants file: %w", err)
+	}
+
+	for _, t := range tenants {
+		t.expandEnv()
+	}
+	return tenants, nil
+}
+
+// expandEnv resolves ${VAR} references in credential fields
+func (t *Tenant) expandEnv() {
+	for _, field := range []*string{
+		&t.ServiceNow.URL, &t.ServiceNow.Username, &t.ServiceNow.Password,
+		&t.Jira.URL, &t.Jira.Email, &t.Jira.APIToken, &t.Jira.ProjectKey, &t.Jira.APIVersion, &t.Jira.ProvisionLead,
+		&t.GitHub.Token, &t.GitHub.Repo, &t.GitHub.APIURL,
+		&t.Slack.Token, &t.Slack.TeamID,
+		&t.Teams.AppID, &t.Teams.AppPassword, &t.Teams.TenantID, &t.Teams.ServiceURL,
+	} {
+		*field = os.ExpandEnv(*field)
+	}
+	for key, channel := range t.Teams.Channels {
+		t.Teams.Channels[key] = os.ExpandEnv(channel)
+	}
+	for name, key := range t.Jira.Projects {
+		t.Jira.Projects[name] = os.ExpandEnv(key)
+	}
+}
+
+// DefaultFromEnv builds the tenant that single-organization deployments use,
+// from the same variables the server has always read (see the config package,
+// which can set them from a file). Its mappings stay in ./data.
+func DefaultFromEnv() *Tenant {
+	return &Tenant{
+		ID:   DefaultID,
+		Name: getEnv("DEFAULT_TENANT_NAME", "Default"),
+		ServiceNow: ServiceNowConfig{
+			URL:      os.Getenv("SERVICENOW_URL"),
+			Username: os.Getenv("SERVICENOW_USERNAME"),
+			Password: os.Getenv("SERVICENOW_PASSWORD"),
+			Tables:   parseChannels(os.Getenv("SERVICENOW_TABLES")),
+		},
+		Jira: JiraConfig{
+			URL:        os.Getenv("JIRA_URL"),
+			Email:      os.Getenv("JIRA_EMAIL"),
+			APIToken:   os.Getenv("JIRA_API_TOKEN"),
+			ProjectKey: os.Getenv("JIRA_PROJECT_KEY"),
+			APIVersion: os.Getenv("JIRA_API_VERSION"),
+			Projects:   parseChannels(os.Getenv("JIRA_PROJECTS")),
+
+			AutoProvision:      os.Getenv("JIRA_AUTO_PROVISION"),
+			ProvisionKeyPrefix: os.Getenv("JIRA_PROVISION_KEY_PREFIX"),
+			ProvisionLead:      os.Getenv("JIRA_PROVISION_LEAD"),
+		},
+		GitHub: GitHubConfig{
+			Token:  os.Getenv("GITHUB_TOKEN"),
+			Repo:   os.Getenv("GITHUB_REPO"),
+			APIURL: os.Getenv("GITHUB_API_URL"),
+		},
+		Slack: SlackConfig{
+			Token:  os.Getenv("SLACK_API_TOKEN"),
+			TeamID: os.Getenv("SLACK_TEAM_ID"),
+		},
+		Teams: TeamsConfig{
+			AppID:       os.Getenv("TEAMS_APP_ID"),
+			AppPassword: os.Getenv("TEAMS_APP_PASSWORD"),
+			TenantID:    os.Getenv("TEAMS_TENANT_ID"),
+			ServiceURL:  os.Getenv("TEAMS_SERVICE_URL"),
+			Channels:    parseChannels(os.Getenv("TEAMS_CHANNELS")),
+			Default:     os.Getenv("TEAMS_DEFAULT") == "true",
+		},
+		Notifications: NotificationConfig{
+			Locale:         os.Getenv("NOTIFICATION_LOCALE"),
+			ChannelLocales: parseChannels(os.Getenv("NOTIFICATION_CHANNEL_LOCALES")),
+		},
+		DataDir: "./data",
+	}
+}
+
+// LoadRegistryFromEnv loads TENANTS_FILE when set. The environment tenant
+// serves unnamed requests unless TENANT_REQUIRED=true.
+func LoadRegistryFromEnv() (*Registry, error) {
+	var tenants []*Tenant
+	if path := os.Getenv("TENANTS_FILE"); path != "" {
+		loaded, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		tenants = loaded
+	}
+
+	var defaultTenant *Tenant
+	if getEnv("TENANT_REQUIRED", "false") != "true" {
+		defaultTenant = DefaultFromEnv()
+	}
+	if defaultTenant == nil && len(tenants) == 0 {
+		return nil, errors.New("TENANT_REQUIRED is set but TENANTS_FILE defines no tenants")
+	}
+
+	return NewRegistry(tenants, defaultTenant)
+}
+
+// parseChannels reads "key=id,key=id" mappings such as TEAMS_CHANNELS and JIRA_PROJECTS
+func parseChannels(value string) map[string]string {
+	channels := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, id, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(key) != "" {
+			channels[strings.TrimSpace(key)] = strings.TrimSpace(id)
+		}
+	}
+	return channels
+}
+
+// getEnv returns an environment variable or the fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
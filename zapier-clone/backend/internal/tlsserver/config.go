@@ -0,0 +1,154 @@
+// backend/internal/tlsserver/config.go
+package tlsserver
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+// TLS modes
+const (
+	ModeOff  = "off"
+	ModeACME = "acme"
+)
+
+// ACME challenge types
+const (
+	ChallengeHTTP01    = "http-01"
+	ChallengeTLSALPN01 = "tls-alpn-01"
+	ChallengeDNS01     = "dns-01"
+)
+
+// LetsEncryptStagingURL is useful while testing; its certificates are not trusted
+const LetsEncryptStagingURL = "https://acme-staging-v02.api.letsencrypt.org/directory"
+
+// Config controls the optional TLS listener
+type Config struct {
+	Mode      string
+	Domains   []string
+	Email     string
+	Challenge string
+	// DirectoryURL is the ACME directory; defaults to Let's Encrypt production
+	DirectoryURL string
+	// CacheDir holds the ACME account key and issued certificates
+	CacheDir string
+
+	TLSAddr  string
+	HTTPAddr string
+
+	// DNSHook is run to publish and remove dns-01 TXT records:
+	//   <hook> present|cleanup <fqdn> <value>
+	DNSHook string
+	// DNSPropagation is how long to wait after publishing a TXT record
+	DNSPropagation time.Duration
+	// RenewBefore renews dns-01 certificates this long before they expire
+	RenewBefore time.Duration
+
+	HSTS HSTSConfig
+}
+
+// HSTSConfig is the Strict-Transport-Security policy sent over TLS
+type HSTSConfig struct {
+	MaxAge            time.Duration
+	IncludeSubdomains bool
+	Preload           bool
+}
+
+// LoadConfigFromEnv reads the TLS configuration; TLS stays off unless TLS_MODE=acme
+func LoadConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		Mode:           strings.ToLower(getEnv("TLS_MODE", ModeOff)),
+		Email:          os.Getenv("ACME_EMAIL"),
+		Challenge:      strings.ToLower(getEnv("ACME_CHALLENGE", ChallengeHTTP01)),
+		DirectoryURL:   getEnv("ACME_DIRECTORY_URL", acme.LetsEncryptURL),
+		CacheDir:       getEnv("TLS_CACHE_DIR", "./data/certs"),
+		TLSAddr:        getEnv("TLS_ADDR", ":443"),
+		HTTPAddr:       getEnv("TLS_HTTP_ADDR", ":80"),
+		DNSHook:        os.Getenv("ACME_DNS_HOOK"),
+		DNSPropagation: 30 * time.Second,
+		RenewBefore:    30 * 24 * time.Hour,
+		HSTS: HSTSConfig{
+			MaxAge:            365 * 24 * time.Hour,
+			IncludeSubdomains: getEnv("HSTS_INCLUDE_SUBDOMAINS", "false") == "true",
+			Preload:           getEnv("HSTS_PRELOAD", "false") == "true",
+		},
+	}
+
+	for _, domain := range strings.Split(os.Getenv("TLS_DOMAINS"), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			cfg.Domains = append(cfg.Domains, strings.ToLower(domain))
+		}
+	}
+
+	if value := os.Getenv("ACME_DNS_PROPAGATION_SECONDS"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ACME_DNS_PROPAGATION_SECONDS %q: %w", value, err)
+		}
+		cfg.DNSPropagation = time.Duration(seconds) * time.Second
+	}
+
+	// HSTS_MAX_AGE=0 disables the header
+	if value := os.Getenv("HSTS_MAX_AGE"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HSTS_MAX_AGE %q: %w", value, err)
+		}
+		cfg.HSTS.MaxAge = time.Duration(seconds) * time.Second
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether the TLS listener should run
+func (c *Config) Enabled() bool {
+	return c.Mode == ModeACME
+}
+
+// Validate checks the configuration for the selected mode
+func (c *Config) Validate() error {
+	switch c.Mode {
+	case ModeOff, "":
+		return nil
+	case ModeACME:
+	default:
+		return fmt.Errorf("invalid TLS_MODE %q (expected %s or %s)", c.Mode, ModeOff, ModeACME)
+	}
+
+	if len(c.Domains) == 0 {
+		return fmt.Errorf("TLS_DOMAINS is required when TLS_MODE=%s", ModeACME)
+	}
+
+	switch c.Challenge {
+	case ChallengeHTTP01, ChallengeTLSALPN01:
+		for _, domain := range c.Domains {
+			if strings.HasPrefix(domain, "*.") {
+				return fmt.Errorf("wildcard domain %s requires ACME_CHALLENGE=%s", domain, ChallengeDNS01)
+			}
+		}
+	case ChallengeDNS01:
+		if c.DNSHook == "" {
+			return fmt.Errorf("ACME_DNS_HOOK is required for ACME_CHALLENGE=%s", ChallengeDNS01)
+		}
+	default:
+		return fmt.Errorf("invalid ACME_CHALLENGE %q", c.Challenge)
+	}
+
+	return nil
+}
+
+// getEnv returns an environment variable or the fallback
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,345 @@
+// backend/internal/tlsserver/dns01.go
+package tlsserver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"golang.org/x/crypto/acme"
+)
+
+// DNSManager obtains and renews a certificate using the ACME dns-01 challenge.
+// TXT records are published by an external hook so any DNS provider can be used.
+type DNSManager struct {
+	Config *Config
+
+	mutex sync.RWMutex
+	cert  *tls.Certificate
+
+	stop chan struct{}
+}
+
+// NewDNSManager creates a dns-01 certificate manager
+func NewDNSManager(cfg *Config) *DNSManager {
+	return &DNSManager{
+		Config: cfg,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start loads the cached certificate, obtains one if it is missing or due for
+// renewal, and keeps renewing it in the background
+func (m *DNSManager) Start() error {
+	if err := os.MkdirAll(m.Config.CacheDir, 0700); err != nil {
+		return fmt.Errorf("error creating certificate cache: %w", err)
+	}
+
+	if cert, err := m.loadCertificate(); err == nil {
+		m.setCertificate(cert)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		slog.Warn("ignoring cached certificate", "error", err)
+	}
+
+	if err := m.renewIfDue(); err != nil && m.Certificate() == nil {
+		return err
+	} else if err != nil {
+		slog.Error("error renewing certificate; keeping the current one", "error", err)
+	}
+
+	go m.run()
+	return nil
+}
+
+// Stop ends the renewal loop
+func (m *DNSManager) Stop() {
+	close(m.stop)
+}
+
+// Certificate returns the current certificate, or nil before the first issuance
+func (m *DNSManager) Certificate() *tls.Certificate {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.cert
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (m *DNSManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := m.Certificate()
+	if cert == nil {
+		return nil, errors.New("no certificate issued yet")
+	}
+	return cert, nil
+}
+
+// run checks twice a day whether the certificate needs renewing
+func (m *DNSManager) run() {
+	ticker := time.NewTicker(12 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			if err := m.renewIfDue(); err != nil {
+				slog.Error("error renewing certificate", "domains", strings.Join(m.Config.Domains, ", "), "error", err)
+			}
+		}
+	}
+}
+
+// renewIfDue obtains a new certificate when there is none or it expires within RenewBefore
+func (m *DNSManager) renewIfDue() error {
+	if cert := m.Certificate(); cert != nil && cert.Leaf != nil {
+		if time.Until(cert.Leaf.NotAfter) > m.Config.RenewBefore {
+			return nil
+		}
+		slog.Info("certificate expires soon; renewing", "domains", strings.Join(m.Config.Domains, ", "), "not_after", cert.Leaf.NotAfter.Format(time.RFC3339))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	cert, err := m.obtain(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.saveCertificate(cert); err != nil {
+		logging.FromContext(ctx).Warn("could not cache certificate", "error", err)
+	}
+	m.setCertificate(cert)
+	logging.FromContext(ctx).Info("obtained certificate", "domains", strings.Join(m.Config.Domains, ", "), "not_after", cert.Leaf.NotAfter.Format(time.RFC3339))
+	return nil
+}
+
+// obtain runs one ACME order for the configured domains
+func (m *DNSManager) obtain(ctx context.Context) (*tls.Certificate, error) {
+	accountKey, err := m.accountKey()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &acme.Client{Key: accountKey, DirectoryURL: m.Config.DirectoryURL}
+
+	account := &acme.Account{}
+	if m.Config.Email != "" {
+		account.Contact = []string{"mailto:" + m.Config.Email}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, fmt.Errorf("error registering ACME account: %w", err)
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(m.Config.Domains...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating ACME order: %w", err)
+	}
+
+	for _, authzURL := range order.AuthzURLs {
+		if err := m.authorize(ctx, client, authzURL); err != nil {
+			return nil, err
+		}
+	}
+
+	order, err = client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for ACME order: %w", err)
+	}
+
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating certificate key: %w", err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: m.Config.Domains}, certKey)
+	if err != nil {
+		return nil, fmt.Errorf("error creating CSR: %w", err)
+	}
+
+	der, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, fmt.Errorf("error finalizing ACME order: %w", err)
+	}
+
+	return newCertificate(der, certKey)
+}
+
+// authorize completes the dns-01 challenge for one authorization
+func (m *DNSManager) authorize(ctx context.Context, client *acme.Client, authzURL string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("error fetching authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == ChallengeDNS01 {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("no dns-01 challenge offered for %s", authz.Identifier.Value)
+	}
+
+	value, err := client.DNS01ChallengeRecord(challenge.Token)
+	if err != nil {
+		return fmt.Errorf("error computing dns-01 record: %w", err)
+	}
+	// Wildcards are validated on the base domain
+	fqdn := "_acme-challenge." + strings.TrimPrefix(authz.Identifier.Value, "*.")
+
+	if err := m.runHook(ctx, "present", fqdn, value); err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.runHook(context.Background(), "cleanup", fqdn, value); err != nil {
+			logging.FromContext(ctx).Warn("error cleaning up DNS challenge", "record", fqdn, "error", err)
+		}
+	}()
+
+	select {
+	case <-time.After(m.Config.DNSPropagation):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("error accepting dns-01 challenge for %s: %w", authz.Identifier.Value, err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("dns-01 challenge for %s failed: %w", authz.Identifier.Value, err)
+	}
+	return nil
+}
+
+// runHook calls the DNS hook: <hook> present|cleanup <fqdn> <value>
+func (m *DNSManager) runHook(ctx context.Context, action, fqdn, value string) error {
+	cmd := exec.CommandContext(ctx, m.Config.DNSHook, action, fqdn, value)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error running DNS hook %s %s: %w: %s", action, fqdn, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// accountKey loads or creates the ACME account key
+func (m *DNSManager) accountKey() (crypto.Signer, error) {
+	path := filepath.Join(m.Config.CacheDir, "acme_account.key")
+
+	if data, err := os.ReadFile(path); err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("invalid account key in %s", path)
+		}
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing account key: %w", err)
+		}
+		return key, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading account key: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating account key: %w", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding account key: %w", err)
+	}
+	if err := writeFileAtomic(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})); err != nil {
+		return nil, fmt.Errorf("error saving account key: %w", err)
+	}
+	return key, nil
+}
+
+// certPaths returns the cache files for the configured domain set
+func (m *DNSManager) certPaths() (string, string) {
+	name := strings.ReplaceAll(strings.Join(m.Config.Domains, "+"), "*", "_wildcard")
+	return filepath.Join(m.Config.CacheDir, name+".crt"), filepath.Join(m.Config.CacheDir, name+".key")
+}
+
+// loadCertificate reads the cached certificate
+func (m *DNSManager) loadCertificate() (*tls.Certificate, error) {
+	certPath, keyPath := m.certPaths()
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cached certificate: %w", err)
+	}
+	return &cert, nil
+}
+
+// saveCertificate writes the certificate chain and key to the cache
+func (m *DNSManager) saveCertificate(cert *tls.Certificate) error {
+	certPath, keyPath := m.certPaths()
+
+	var chain []byte
+	for _, der := range cert.Certificate {
+		chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+
+	key, ok := cert.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return errors.New("unsupported certificate key type")
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})); err != nil {
+		return err
+	}
+	return writeFileAtomic(certPath, chain)
+}
+
+// setCertificate swaps in a new certificate
+func (m *DNSManager) setCertificate(cert *tls.Certificate) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.cert = cert
+}
+
+// newCertificate builds a tls.Certificate from an issued chain
+func newCertificate(der [][]byte, key crypto.Signer) (*tls.Certificate, error) {
+	if len(der) == 0 {
+		return nil, errors.New("empty certificate chain")
+	}
+	leaf, err := x509.ParseCertificate(der[0])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing issued certificate: %w", err)
+	}
+	return &tls.Certificate{Certificate: der, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// writeFileAtomic writes a private file via rename so readers never see a partial write
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
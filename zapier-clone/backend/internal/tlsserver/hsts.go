@@ -0,0 +1,39 @@
+// backend/internal/tlsserver/hsts.go
+package tlsserver
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HeaderValue renders the Strict-Transport-Security header, or "" when disabled
+func (h HSTSConfig) HeaderValue() string {
+	if h.MaxAge <= 0 {
+		return ""
+	}
+
+	value := fmt.Sprintf("max-age=%d", int64(h.MaxAge.Seconds()))
+	if h.IncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if h.Preload {
+		value += "; preload"
+	}
+	return value
+}
+
+// HSTS adds the Strict-Transport-Security header to responses served over TLS.
+// Browsers ignore the header on plain HTTP, so it is never sent there.
+func HSTS(cfg HSTSConfig, next http.Handler) http.Handler {
+	value := cfg.HeaderValue()
+	if value == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			w.Header().Set("Strict-Transport-Security", value)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,143 @@
+// backend/internal/tlsserver/server.go
+package tlsserver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Server runs the HTTPS listener plus a plain HTTP listener that answers
+// http-01 challenges and redirects everything else to HTTPS
+type Server struct {
+	Config *Config
+	HTTPS  *http.Server
+	HTTP   *http.Server
+
+	dns *DNSManager
+}
+
+// New wraps base (its handler and timeouts) in TLS listeners for cfg
+func New(cfg *Config, base *http.Server) (*Server, error) {
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("TLS is not enabled")
+	}
+
+	s := &Server{Config: cfg}
+	redirect := http.HandlerFunc(s.redirectToHTTPS)
+
+	var tlsConfig *tls.Config
+	httpHandler := http.Handler(redirect)
+
+	switch cfg.Challenge {
+	case ChallengeDNS01:
+		s.dns = NewDNSManager(cfg)
+		tlsConfig = &tls.Config{GetCertificate: s.dns.GetCertificate}
+
+	default:
+		// autocert renews certificates 30 days before they expire
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+			Cache:      autocert.DirCache(cfg.CacheDir),
+			Email:      cfg.Email,
+			Client:     &acme.Client{DirectoryURL: cfg.DirectoryURL},
+		}
+		tlsConfig = manager.TLSConfig()
+		if cfg.Challenge == ChallengeHTTP01 {
+			httpHandler = manager.HTTPHandler(redirect)
+		}
+	}
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	s.HTTPS = &http.Server{
+		Addr:         cfg.TLSAddr,
+		Handler:      HSTS(cfg.HSTS, base.Handler),
+		TLSConfig:    tlsConfig,
+		ReadTimeout:  base.ReadTimeout,
+		WriteTimeout: base.WriteTimeout,
+		IdleTimeout:  base.IdleTimeout,
+	}
+	s.HTTP = &http.Server{
+		Addr:         cfg.HTTPAddr,
+		Handler:      httpHandler,
+		ReadTimeout:  base.ReadTimeout,
+		WriteTimeout: base.WriteTimeout,
+		IdleTimeout:  base.IdleTimeout,
+	}
+
+	return s, nil
+}
+
+// ListenAndServe starts both listeners and blocks until the HTTPS listener stops
+func (s *Server) ListenAndServe() error {
+	if s.dns != nil {
+		if err := s.dns.Start(); err != nil {
+			return fmt.Errorf("error obtaining certificate: %w", err)
+		}
+	}
+
+	go func() {
+		slog.Info("starting HTTP redirect listener", "addr", s.HTTP.Addr)
+		if err := s.HTTP.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("HTTP listener error", "error", err)
+		}
+	}()
+
+	slog.Info("starting TLS server", "addr", s.HTTPS.Addr, "domains", strings.Join(s.Config.Domains, ", "), "challenge", s.Config.Challenge)
+	return s.HTTPS.ListenAndServeTLS("", "")
+}
+
+// Shutdown gracefully stops both listeners
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.dns != nil {
+		s.dns.Stop()
+	}
+	if err := s.HTTP.Shutdown(ctx); err != nil {
+		logging.FromContext(ctx).Error("error stopping HTTP listener", "error", err)
+	}
+	return s.HTTPS.Shutdown(ctx)
+}
+
+// redirectToHTTPS sends plain HTTP requests to the TLS listener. 308 keeps the
+// method and body so webhook senders that follow redirects still deliver.
+func (s *Server) redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if !s.servesHost(host) {
+		host = strings.TrimPrefix(s.Config.Domains[0], "*.")
+	}
+	if _, port, err := net.SplitHostPort(s.Config.TLSAddr); err == nil && port != "443" {
+		host = net.JoinHostPort(host, port)
+	}
+
+	status := http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		status = http.StatusMovedPermanently
+	}
+	http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), status)
+}
+
+// servesHost reports whether host is covered by a configured domain
+func (s *Server) servesHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range s.Config.Domains {
+		if domain == host {
+			return true
+		}
+		if strings.HasPrefix(domain, "*.") && strings.HasSuffix(host, domain[1:]) && !strings.Contains(strings.TrimSuffix(host, domain[1:]), ".") {
+			return true
+		}
+	}
+	return false
+}
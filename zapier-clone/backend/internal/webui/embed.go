@@ -0,0 +1,27 @@
+//go:build embedui
+
+// backend/internal/webui/embed.go
+package webui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Assets returns the embedded frontend build
+func Assets() (fs.FS, bool) {
+	assets, err := fs.Sub(dist, "dist")
+	if err != nil {
+		return nil, false
+	}
+
+	// An empty dist directory means the frontend was not built before embedding
+	if _, err := fs.Stat(assets, "index.html"); err != nil {
+		return nil, false
+	}
+
+	return assets, true
+}
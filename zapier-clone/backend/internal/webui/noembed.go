@@ -0,0 +1,11 @@
+//go:build !embedui
+
+// backend/internal/webui/noembed.go
+package webui
+
+import "io/fs"
+
+// Assets reports that no frontend is embedded; build with -tags embedui to include it
+func Assets() (fs.FS, bool) {
+	return nil, false
+}
@@ -0,0 +1,64 @@
+// backend/internal/webui/webui.go
+package webui
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Handler serves a single-page app from fsys. Existing files are served directly;
+// any other GET falls back to index.html so client-side routes survive a reload.
+// Paths under /api/ are never rewritten, so unknown API calls still 404.
+func Handler(fsys fs.FS) http.Handler {
+	files := http.FileServer(http.FS(fsys))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+		if name == "" || !exists(fsys, name) {
+			serveIndex(w, r, fsys)
+			return
+		}
+
+		setCacheHeaders(w, name)
+		files.ServeHTTP(w, r)
+	})
+}
+
+// serveIndex writes index.html without letting the browser cache it
+func serveIndex(w http.ResponseWriter, r *http.Request, fsys fs.FS) {
+	index, err := fs.ReadFile(fsys, "index.html")
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(index)
+}
+
+// setCacheHeaders caches content-hashed build output for a year and revalidates everything else
+func setCacheHeaders(w http.ResponseWriter, name string) {
+	if strings.HasPrefix(name, "static/") {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+}
+
+// exists reports whether name is a regular file in fsys
+func exists(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	return err == nil && !info.IsDir()
+}
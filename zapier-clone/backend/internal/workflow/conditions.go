@@ -0,0 +1,296 @@
+// backend/internal/workflow/conditions.go
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+)
+
+// ConditionService marks an action as a condition node. Its config is a
+// Branch; it makes no outbound call and decides which of the actions after it run.
+//
+//	{"action_service": "condition", "action_id": "if", "action_config": {
+//	  "conditions": [{"field": "servicenow.severity", "operator": "severity_at_least", "value": "High"}],
+//	  "then": 1, "else": 1}}
+const ConditionService = "condition"
+
+// WorkflowSource is the pseudo-service conditions use to read the workflow's
+// trigger: "workflow.trigger_id" and trigger config keys such as "workflow.table"
+const WorkflowSource = "workflow"
+
+// Condition operators
+const (
+	OpEquals          = "equals"
+	OpNotEquals       = "not_equals"
+	OpIn              = "in"
+	OpNotIn           = "not_in"
+	OpGreaterThan     = "gt"
+	OpAtLeast         = "gte"
+	OpLessThan        = "lt"
+	OpAtMost          = "lte"
+	OpMatches         = "matches"
+	OpExists          = "exists"
+	OpSeverityAtLeast = "severity_at_least"
+)
+
+// Branch match modes
+const (
+	MatchAll = "all"
+	MatchAny = "any"
+)
+
+// ErrInvalidCondition is returned for condition nodes that cannot be evaluated
+var ErrInvalidCondition = errors.New("invalid condition")
+
+// Condition compares one field of the trigger or of an earlier action's output
+type Condition struct {
+	// Field is "service.path", like a data mapping source
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+// Branch is the config of a condition node. When its conditions hold, the
+// Then actions that follow the node run and the Else actions after them are
+// skipped; otherwise only the Else actions run. Then 0 covers every remaining
+// action, so a failed condition ends the run.
+type Branch struct {
+	Conditions []Condition `json:"conditions"`
+	// Match is "all" (the default) or "any"
+	Match string `json:"match,omitempty"`
+	Then  int    `json:"then,omitempty"`
+	Else  int    `json:"else,omitempty"`
+}
+
+// ParseBranch reads and checks a condition node's config
+func ParseBranch(config map[string]interface{}) (*Branch, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCondition, err)
+	}
+	var branch Branch
+	if err := json.Unmarshal(data, &branch); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCondition, err)
+	}
+
+	if len(branch.Conditions) == 0 {
+		return nil, fmt.Errorf("%w: no conditions", ErrInvalidCondition)
+	}
+	if branch.Match == "" {
+		branch.Match = MatchAll
+	}
+	if branch.Match != MatchAll && branch.Match != MatchAny {
+		return nil, fmt.Errorf("%w: match must be %q or %q", ErrInvalidCondition, MatchAll, MatchAny)
+	}
+	if branch.Then < 0 || branch.Else < 0 {
+		return nil, fmt.Errorf("%w: then and else must not be negative", ErrInvalidCondition)
+	}
+	if branch.Then == 0 && branch.Else > 0 {
+		return nil, fmt.Errorf("%w: else needs a then count", ErrInvalidCondition)
+	}
+
+	for i, c := range branch.Conditions {
+		if _, _, ok := splitServiceField(c.Field); !ok {
+			return nil, fmt.Errorf("%w: condition %d: field %q must be service.field", ErrInvalidCondition, i+1, c.Field)
+		}
+		if err := c.check(); err != nil {
+			return nil, fmt.Errorf("%w: condition %d: %v", ErrInvalidCondition, i+1, err)
+		}
+	}
+	return &branch, nil
+}
+
+// blocks returns the end of the then block and of the else block for a node
+// at index i of actions
+func (b *Branch) blocks(i int, actions []Action) (int, int, error) {
+	thenEnd := len(actions)
+	if b.Then > 0 {
+		thenEnd = i + 1 + b.Then
+	}
+	elseEnd := thenEnd + b.Else
+	if elseEnd > len(actions) {
+		return 0, 0, fmt.Errorf("%w: then and else of the condition at position %d cover %d actions, only %d follow it",
+			ErrInvalidCondition, actions[i].Position, b.Then+b.Else, len(actions)-i-1)
+	}
+	return thenEnd, elseEnd, nil
+}
+
+// Evaluate reports whether the branch's conditions hold for the outputs so far
+func (b *Branch) Evaluate(outputs map[string]map[string]interface{}) bool {
+	for _, c := range b.Conditions {
+		service, path, _ := splitServiceField(c.Field)
+		value, ok := lookupField(outputs[service], path)
+		held := c.holds(value, ok)
+		if b.Match == MatchAny && held {
+			return true
+		}
+		if b.Match == MatchAll && !held {
+			return false
+		}
+	}
+	return b.Match == MatchAll
+}
+
+// check validates the operator and its value
+func (c Condition) check() error {
+	switch c.Operator {
+	case OpEquals, OpNotEquals:
+		return nil
+	case OpExists:
+		if _, ok := c.Value.(bool); c.Value != nil && !ok {
+			return fmt.Errorf("exists takes true, false or no value")
+		}
+	case OpIn, OpNotIn:
+		if _, ok := c.Value.([]interface{}); !ok {
+			return fmt.Errorf("%s needs a list value", c.Operator)
+		}
+	case OpGreaterThan, OpAtLeast, OpLessThan, OpAtMost:
+		if _, err := toFloat(c.Value); err != nil {
+			return fmt.Errorf("%s needs a number: %v", c.Operator, err)
+		}
+	case OpMatches:
+		if _, err := regexp.Compile(fmt.Sprint(c.Value)); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	case OpSeverityAtLeast:
+		if severityRank(c.Value) == 0 {
+			return fmt.Errorf("unknown severity %v", c.Value)
+		}
+	default:
+		return fmt.Errorf("unknown operator %q", c.Operator)
+	}
+	return nil
+}
+
+// holds compares a looked-up value; a missing field only satisfies not_equals,
+// not_in and "exists": false. Text comparisons ignore case, like the
+// notification rules.
+func (c Condition) holds(value interface{}, found bool) bool {
+	if c.Operator == OpExists {
+		want := true
+		if b, ok := c.Value.(bool); ok {
+			want = b
+		}
+		return found == want
+	}
+	if !found {
+		return c.Operator == OpNotEquals || c.Operator == OpNotIn
+	}
+
+	switch c.Operator {
+	case OpEquals:
+		return sameValue(value, c.Value)
+	case OpNotEquals:
+		return !sameValue(value, c.Value)
+	case OpIn, OpNotIn:
+		in := false
+		for _, option := range c.Value.([]interface{}) {
+			if sameValue(value, option) {
+				in = true
+				break
+			}
+		}
+		return in == (c.Operator == OpIn)
+	case OpGreaterThan, OpAtLeast, OpLessThan, OpAtMost:
+		number, err := toFloat(value)
+		if err != nil {
+			return false
+		}
+		limit, _ := toFloat(c.Value)
+		switch c.Operator {
+		case OpGreaterThan:
+			return number > limit
+		case OpAtLeast:
+			return number >= limit
+		case OpLessThan:
+			return number < limit
+		default:
+			return number <= limit
+		}
+	case OpMatches:
+		return regexp.MustCompile(fmt.Sprint(c.Value)).MatchString(fmt.Sprint(value))
+	case OpSeverityAtLeast:
+		rank := severityRank(value)
+		return rank > 0 && rank >= severityRank(c.Value)
+	}
+	return false
+}
+
+// sameValue compares printed values, so YAML and JSON numbers match the
+// strings ServiceNow sends
+func sameValue(a, b interface{}) bool {
+	return strings.EqualFold(strings.TrimSpace(fmt.Sprint(a)), strings.TrimSpace(fmt.Sprint(b)))
+}
+
+// severityRank orders ServiceNow severities, named or numbered; 0 is unknown
+func severityRank(value interface{}) int {
+	switch strings.ToLower(strings.TrimSpace(fmt.Sprint(value))) {
+	case "critical", "1":
+		return 4
+	case "high", "2":
+		return 3
+	case "medium", "moderate", "3":
+		return 2
+	case "low", "4":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Validate checks the workflow's condition nodes, that their then and else
+// blocks fit inside the block that contains them, the templates in its action
+// configs, the transformers its data mappings use and its environment
+func (w *Workflow) Validate() error {
+	switch w.Environment {
+	case "", EnvironmentProduction, EnvironmentSandbox:
+	default:
+		return fmt.Errorf("environment must be %s or %s, got %q", EnvironmentProduction, EnvironmentSandbox, w.Environment)
+	}
+	for _, action := range w.Actions {
+		if action.ActionService == ConditionService {
+			continue
+		}
+		if err := validateTemplates(action.ActionConfig, ""); err != nil {
+			return fmt.Errorf("%s.%s at position %d: %w", action.ActionService, action.ActionID, action.Position, err)
+		}
+	}
+	for _, m := range w.DataMappings {
+		for _, t := range mapping.ParseTransforms(m.Transformer) {
+			if _, ok := mapping.Transformers[t.Name]; !ok {
+				return fmt.Errorf("mapping %s.%s → %s.%s: unknown transformer %q", m.SourceService, m.SourceField, m.TargetService, m.TargetField, t.Name)
+			}
+		}
+	}
+	return validateBlock(sortedActions(w.Actions))
+}
+
+// validateBlock checks the condition nodes of one block and their nested blocks
+func validateBlock(actions []Action) error {
+	for i := 0; i < len(actions); i++ {
+		if actions[i].ActionService != ConditionService {
+			continue
+		}
+		branch, err := ParseBranch(actions[i].ActionConfig)
+		if err != nil {
+			return fmt.Errorf("condition at position %d: %w", actions[i].Position, err)
+		}
+		thenEnd, elseEnd, err := branch.blocks(i, actions)
+		if err != nil {
+			return err
+		}
+		if err := validateBlock(actions[i+1 : thenEnd]); err != nil {
+			return err
+		}
+		if err := validateBlock(actions[thenEnd:elseEnd]); err != nil {
+			return err
+		}
+		i = elseEnd - 1
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// backend/internal/workflow/dispatcher.go
+package workflow
+
+import (
+	"context"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/logging"
+)
+
+// TriggerStore finds the workflows a trigger starts and records their runs;
+// *Store implements it
+type TriggerStore interface {
+	ActiveForTrigger(service, triggerID string) ([]*Workflow, error)
+	RecordExecution(w *Workflow, trigger map[string]interface{}, steps []Step, runErr error) (int, error)
+}
+
+// Dispatcher runs the stored workflows started by an integration's trigger,
+// such as servicenow "sn_risk_risk.created", with the tenant's integrations as
+// connectors, and records each run in the execution history
+type Dispatcher struct {
+	Store        TriggerStore
+	Integrations *common.Registry
+}
+
+// NewDispatcher creates a dispatcher
+func NewDispatcher(store TriggerStore, integrations *common.Registry) *Dispatcher {
+	return &Dispatcher{
+		Store:        store,
+		Integrations: integrations,
+	}
+}
+
+// Dispatch runs every active workflow for a trigger with the event's data.
+// The built-in workflows are skipped, since the ServiceNow flows carry them out
+// and record their runs, and so are sandbox workflows, whose actions must not
+// reach the production integrations. A failed run is logged and recorded,
+// never returned, so a broken workflow does not fail the sync. A nil
+// dispatcher runs nothing.
+func (d *Dispatcher) Dispatch(ctx context.Context, service, triggerID string, data map[string]interface{}) {
+	if d == nil || triggerID == "" {
+		return
+	}
+	logger := logging.FromContext(ctx).With("trigger_service", service, "trigger_id", triggerID)
+
+	workflows, err := d.Store.ActiveForTrigger(service, triggerID)
+	if err != nil {
+		logger.Error("error loading workflows for trigger", "error", err)
+		return
+	}
+
+	var engine *Engine
+	for _, w := range workflows {
+		if isSeed(w.Key) {
+			continue
+		}
+		if w.Environment == EnvironmentSandbox {
+			logger.Debug("not running sandbox workflow on a live trigger", "workflow_id", w.ID)
+			continue
+		}
+		if engine == nil {
+			engine = NewEngine(ConnectorsFrom(ctx, d.Integrations))
+		}
+
+		steps, runErr := engine.RunSteps(w, data)
+		if runErr != nil {
+			logger.Warn("workflow run failed", "workflow_id", w.ID, "error", runErr)
+		}
+		if _, err := d.Store.RecordExecution(w, data, steps, runErr); err != nil {
+			logger.Error("error recording workflow execution", "workflow_id", w.ID, "error", err)
+		}
+	}
+}
+
+// isSeed reports whether a key is one of the built-in workflows
+func isSeed(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, seed := range SeedWorkflows {
+		if seed.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+// backend/internal/workflow/dispatcher_test.go
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+)
+
+// recordingStore serves fixed workflows and keeps the executions recorded
+type recordingStore struct {
+	workflows  []*Workflow
+	executions []recordedExecution
+}
+
+type recordedExecution struct {
+	workflow *Workflow
+	trigger  map[string]interface{}
+	steps    []Step
+	err      error
+}
+
+func (s *recordingStore) ActiveForTrigger(service, triggerID string) ([]*Workflow, error) {
+	var active []*Workflow
+	for _, w := range s.workflows {
+		if w.Status == StatusActive && w.TriggerService == service && w.TriggerID == triggerID {
+			active = append(active, w)
+		}
+	}
+	return active, nil
+}
+
+func (s *recordingStore) RecordExecution(w *Workflow, trigger map[string]interface{}, steps []Step, runErr error) (int, error) {
+	s.executions = append(s.executions, recordedExecution{workflow: w, trigger: trigger, steps: steps, err: runErr})
+	return len(s.executions), nil
+}
+
+// fakeJira is a registered integration whose actions are recorded
+type fakeJira struct {
+	calls []map[string]interface{}
+	err   error
+}
+
+func (j *fakeJira) Name() string { return "jira" }
+
+func (j *fakeJira) Actions() []common.ActionSpec {
+	return []common.ActionSpec{{ID: "create_issue", Fields: []string{"summary"}}}
+}
+
+func (j *fakeJira) Execute(ctx context.Context, action string, fields map[string]interface{}) (map[string]interface{}, error) {
+	j.calls = append(j.calls, fields)
+	if j.err != nil {
+		return nil, j.err
+	}
+	return map[string]interface{}{"key": "GRC-7"}, nil
+}
+
+// templatedWorkflow files a Jira issue for high severity risks, with a
+// summary and labels rendered from the trigger
+func templatedWorkflow() *Workflow {
+	return &Workflow{
+		ID:             7,
+		Name:           "High risks to Jira",
+		Status:         StatusActive,
+		Environment:    EnvironmentProduction,
+		TriggerService: "servicenow",
+		TriggerID:      "sn_risk_risk.created",
+		Actions: []Action{
+			{
+				ID:            1,
+				ActionService: ConditionService,
+				ActionID:      "if",
+				ActionConfig: map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"field": "servicenow.severity", "operator": OpSeverityAtLeast, "value": "High"},
+					},
+				},
+				Position: 1,
+			},
+			{
+				ID:            2,
+				ActionService: "jira",
+				ActionID:      "create_issue",
+				ActionConfig: map[string]interface{}{
+					"summary": "{{trigger.data.number}}: {{servicenow.short_description | uppercase}}",
+					"labels":  "{{trigger.data.table}}",
+				},
+				Position: 2,
+			},
+		},
+	}
+}
+
+func newDispatcher(t *testing.T, store *recordingStore, jira *fakeJira) *Dispatcher {
+	t.Helper()
+	registry := common.NewRegistry()
+	if err := registry.Register(jira); err != nil {
+		t.Fatal(err)
+	}
+	return NewDispatcher(store, registry)
+}
+
+func TestDispatchRunsTemplatedWorkflow(t *testing.T) {
+	store := &recordingStore{workflows: []*Workflow{templatedWorkflow()}}
+	jira := &fakeJira{}
+
+	trigger := map[string]interface{}{
+		"number":            "RISK0042",
+		"short_description": "Unpatched VPN appliance",
+		"severity":          "Critical",
+		"table":             "sn_risk_risk",
+	}
+	newDispatcher(t, store, jira).Dispatch(context.Background(), "servicenow", "sn_risk_risk.created", trigger)
+
+	if len(jira.calls) != 1 {
+		t.Fatalf("jira calls = %d, want 1", len(jira.calls))
+	}
+	if got, want := jira.calls[0]["summary"], "RISK0042: UNPATCHED VPN APPLIANCE"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+	if got := jira.calls[0]["labels"]; got != "sn_risk_risk" {
+		t.Errorf("labels = %q, want sn_risk_risk", got)
+	}
+
+	if len(store.executions) != 1 {
+		t.Fatalf("executions = %d, want 1", len(store.executions))
+	}
+	execution := store.executions[0]
+	if execution.err != nil {
+		t.Errorf("execution error = %v", execution.err)
+	}
+	if len(execution.steps) != 2 {
+		t.Fatalf("steps = %d, want the condition and the issue", len(execution.steps))
+	}
+	if got := execution.steps[1].Call.Config["summary"]; got != "RISK0042: UNPATCHED VPN APPLIANCE" {
+		t.Errorf("recorded config summary = %q, want the rendered template", got)
+	}
+	if got := execution.steps[1].Output["key"]; got != "GRC-7" {
+		t.Errorf("recorded output key = %v, want GRC-7", got)
+	}
+}
+
+func TestDispatchFollowsConditions(t *testing.T) {
+	store := &recordingStore{workflows: []*Workflow{templatedWorkflow()}}
+	jira := &fakeJira{}
+
+	trigger := map[string]interface{}{"number": "RISK0043", "short_description": "Stale account", "severity": "Low"}
+	newDispatcher(t, store, jira).Dispatch(context.Background(), "servicenow", "sn_risk_risk.created", trigger)
+
+	if len(jira.calls) != 0 {
+		t.Errorf("jira calls = %d, want none for a low risk", len(jira.calls))
+	}
+	if len(store.executions) != 1 || len(store.executions[0].steps) != 1 {
+		t.Fatalf("want one execution with only the condition step, got %+v", store.executions)
+	}
+}
+
+func TestDispatchRecordsFailedRuns(t *testing.T) {
+	store := &recordingStore{workflows: []*Workflow{templatedWorkflow()}}
+	jira := &fakeJira{err: errors.New("jira is down")}
+
+	trigger := map[string]interface{}{"number": "RISK0044", "short_description": "Open S3 bucket", "severity": "High"}
+	newDispatcher(t, store, jira).Dispatch(context.Background(), "servicenow", "sn_risk_risk.created", trigger)
+
+	if len(store.executions) != 1 {
+		t.Fatalf("executions = %d, want 1", len(store.executions))
+	}
+	if store.executions[0].err == nil {
+		t.Error("want the run's error recorded")
+	}
+}
+
+func TestDispatchSkipsBuiltInAndSandboxWorkflows(t *testing.T) {
+	seed := templatedWorkflow()
+	seed.Key = SeedWorkflows[0].Key
+	sandbox := templatedWorkflow()
+	sandbox.Environment = EnvironmentSandbox
+	other := templatedWorkflow()
+	other.TriggerID = "sn_risk_risk.updated"
+
+	store := &recordingStore{workflows: []*Workflow{seed, sandbox, other}}
+	jira := &fakeJira{}
+
+	trigger := map[string]interface{}{"number": "RISK0045", "short_description": "Weak TLS", "severity": "Critical"}
+	newDispatcher(t, store, jira).Dispatch(context.Background(), "servicenow", "sn_risk_risk.created", trigger)
+
+	if len(jira.calls) != 0 || len(store.executions) != 0 {
+		t.Errorf("want nothing run, got %d calls and %d executions", len(jira.calls), len(store.executions))
+	}
+}
+
+func TestNilDispatcherRunsNothing(t *testing.T) {
+	var d *Dispatcher
+	d.Dispatch(context.Background(), "servicenow", "sn_risk_risk.created", nil)
+}
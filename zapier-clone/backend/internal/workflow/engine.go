@@ -0,0 +1,263 @@
+// backend/internal/workflow/engine.go
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/integrations/common"
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+)
+
+// Call is one outbound request the engine makes to a connector
+type Call struct {
+	Service string                 `json:"service" yaml:"service"`
+	Action  string                 `json:"action" yaml:"action"`
+	Config  map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// Connector performs actions against one external service. The returned map is
+// the action's output and can be used as a mapping source by later actions.
+type Connector interface {
+	Execute(call Call) (map[string]interface{}, error)
+}
+
+// integrationConnector runs calls as actions of a registered integration
+type integrationConnector struct {
+	ctx    context.Context
+	action common.Action
+}
+
+// Execute implements Connector; the action's config is passed with its mapped
+// fields, which take precedence
+func (c *integrationConnector) Execute(call Call) (map[string]interface{}, error) {
+	fields := make(map[string]interface{}, len(call.Config)+len(call.Fields))
+	for name, value := range call.Config {
+		fields[name] = value
+	}
+	for name, value := range call.Fields {
+		fields[name] = value
+	}
+	return c.action.Execute(c.ctx, call.Action, fields)
+}
+
+// ConnectorsFrom returns a connector for every integration in the registry
+// that has actions, running them under ctx
+func ConnectorsFrom(ctx context.Context, registry *common.Registry) map[string]Connector {
+	connectors := make(map[string]Connector)
+	for _, integration := range registry.List() {
+		if action, ok := integration.(common.Action); ok {
+			connectors[integration.Name()] = &integrationConnector{ctx: ctx, action: action}
+		}
+	}
+	return connectors
+}
+
+// Engine runs workflow actions against a set of connectors. Data mappings and
+// config templates use the transformers in mapping.Transformers.
+type Engine struct {
+	Connectors map[string]Connector
+}
+
+// NewEngine creates an engine
+func NewEngine(connectors map[string]Connector) *Engine {
+	return &Engine{
+		Connectors: connectors,
+	}
+}
+
+// Run executes the workflow's actions in order for one trigger payload and
+// returns the outbound calls that were made; condition nodes are not calls
+func (e *Engine) Run(w *Workflow, trigger map[string]interface{}) ([]Call, error) {
+	steps, err := e.RunSteps(w, trigger)
+	calls := make([]Call, 0, len(steps))
+	for _, step := range steps {
+		if step.Call.Service != ConditionService {
+			calls = append(calls, step.Call)
+		}
+	}
+	return calls, err
+}
+
+// RunSteps executes the workflow like Run and returns each attempted step with
+// its output, for recording with Store.RecordExecution. Condition nodes are
+// steps too, with their result as output.
+func (e *Engine) RunSteps(w *Workflow, trigger map[string]interface{}) ([]Step, error) {
+	// Outputs by service; the trigger counts as the first output, and
+	// conditions can also read the trigger's definition
+	outputs := map[string]map[string]interface{}{
+		w.TriggerService: trigger,
+		WorkflowSource:   triggerDefinition(w),
+	}
+
+	var steps []Step
+	err := e.runBlock(w, trigger, sortedActions(w.Actions), outputs, &steps)
+	return steps, err
+}
+
+// runBlock executes a run of actions, descending into the then or else block
+// of each condition node
+func (e *Engine) runBlock(w *Workflow, trigger map[string]interface{}, actions []Action, outputs map[string]map[string]interface{}, steps *[]Step) error {
+	for i := 0; i < len(actions); i++ {
+		action := actions[i]
+
+		if action.ActionService == ConditionService {
+			step := Step{
+				ActionID:  action.ID,
+				Call:      Call{Service: ConditionService, Action: action.ActionID, Config: action.ActionConfig},
+				StartedAt: time.Now(),
+			}
+			branch, err := ParseBranch(action.ActionConfig)
+			var thenEnd, elseEnd int
+			if err == nil {
+				thenEnd, elseEnd, err = branch.blocks(i, actions)
+			}
+			if err != nil {
+				err = fmt.Errorf("error evaluating condition at position %d: %w", action.Position, err)
+				*steps = append(*steps, step.finish(nil, err))
+				return err
+			}
+
+			result := branch.Evaluate(outputs)
+			*steps = append(*steps, step.finish(map[string]interface{}{"result": result}, nil))
+
+			block := actions[thenEnd:elseEnd]
+			if result {
+				block = actions[i+1 : thenEnd]
+			}
+			if err := e.runBlock(w, trigger, block, outputs, steps); err != nil {
+				return err
+			}
+			i = elseEnd - 1
+			continue
+		}
+
+		fields, err := e.mapFields(w.DataMappings, action.ActionService, outputs)
+		if err != nil {
+			return fmt.Errorf("error mapping fields for %s.%s: %w", action.ActionService, action.ActionID, err)
+		}
+		config, err := e.renderConfig(action.ActionConfig, templateScope(w, trigger, outputs, fields))
+		if err != nil {
+			return fmt.Errorf("error rendering config for %s.%s: %w", action.ActionService, action.ActionID, err)
+		}
+
+		step := Step{
+			ActionID: action.ID,
+			Call: Call{
+				Service: action.ActionService,
+				Action:  action.ActionID,
+				Config:  config,
+				Fields:  fields,
+			},
+			StartedAt: time.Now(),
+		}
+
+		connector, ok := e.Connectors[action.ActionService]
+		if !ok {
+			err := fmt.Errorf("no connector for service %q", action.ActionService)
+			*steps = append(*steps, step.finish(nil, err))
+			return err
+		}
+
+		output, err := connector.Execute(step.Call)
+		if err != nil {
+			err = fmt.Errorf("error executing %s.%s: %w", action.ActionService, action.ActionID, err)
+			*steps = append(*steps, step.finish(nil, err))
+			return err
+		}
+		*steps = append(*steps, step.finish(output, nil))
+		if output != nil {
+			outputs[action.ActionService] = output
+		}
+	}
+
+	return nil
+}
+
+// sortedActions returns the actions ordered by position
+func sortedActions(actions []Action) []Action {
+	sorted := append([]Action(nil), actions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+	return sorted
+}
+
+// triggerDefinition is what conditions read from the workflow source: the
+// trigger config (such as "table") with the trigger's service and ID
+func triggerDefinition(w *Workflow) map[string]interface{} {
+	definition := make(map[string]interface{}, len(w.TriggerConfig)+2)
+	for key, value := range w.TriggerConfig {
+		definition[key] = value
+	}
+	definition["trigger_service"] = w.TriggerService
+	definition["trigger_id"] = w.TriggerID
+	return definition
+}
+
+// mapFields applies the mappings targeting a service; missing source fields are skipped
+func (e *Engine) mapFields(mappings []DataMapping, service string, outputs map[string]map[string]interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for _, m := range mappings {
+		if m.TargetService != service {
+			continue
+		}
+
+		value, ok := lookupField(outputs[m.SourceService], m.SourceField)
+		if !ok {
+			continue
+		}
+
+		if m.Transformer != "" {
+			transformed, err := mapping.ApplyTransforms(value, mapping.ParseTransforms(m.Transformer))
+			if err != nil {
+				return nil, fmt.Errorf("error transforming %s with %s: %w", m.SourceField, m.Transformer, err)
+			}
+			value = transformed
+		}
+
+		fields[m.TargetField] = value
+	}
+	return fields, nil
+}
+
+// lookupField resolves a dotted path such as "fields.priority.name"
+func lookupField(data map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = data
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// toFloat accepts numbers and numeric strings (ServiceNow sends most values as strings)
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", v)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("invalid number %v", value)
+	}
+}
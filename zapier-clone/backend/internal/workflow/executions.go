@@ -0,0 +1,466 @@
+// backend/internal/workflow/executions.go
+package workflow
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Execution statuses
+const (
+	ExecutionCompleted = "completed"
+	ExecutionFailed    = "failed"
+)
+
+// ErrExecutionNotFound is returned when an execution does not exist
+var ErrExecutionNotFound = errors.New("execution not found")
+
+// ErrUnredactedNotAllowed is returned when the redaction policy kept no
+// original data for an execution
+var ErrUnredactedNotAllowed = errors.New("redaction policy does not allow viewing unredacted data")
+
+// Sealer encrypts original execution data kept for the unredacted view;
+// connections.Cipher implements it
+type Sealer interface {
+	Encrypt(plaintext []byte) (string, error)
+	Decrypt(value string) ([]byte, error)
+}
+
+// Step is one action attempted during a run
+type Step struct {
+	ActionID    int
+	Call        Call
+	Output      map[string]interface{}
+	Err         error
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// finish records a step's result
+func (s Step) finish(output map[string]interface{}, err error) Step {
+	s.Output = output
+	s.Err = err
+	s.CompletedAt = time.Now()
+	return s
+}
+
+// ExecutionDetail is a stored execution with its steps, as served by the API.
+// Redacted fields hold RedactedValue and are listed in RedactedFields.
+type ExecutionDetail struct {
+	ID             int                    `json:"id"`
+	WorkflowID     int                    `json:"workflow_id"`
+	WorkspaceID    *int                   `json:"workspace_id,omitempty"`
+	Status         string                 `json:"status"`
+	TriggerService string                 `json:"trigger_service"`
+	TriggerData    map[string]interface{} `json:"trigger_data"`
+	RedactedFields []string               `json:"redacted_fields,omitempty"`
+	Redacted       bool                   `json:"redacted"`
+	StartedAt      time.Time              `json:"started_at"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+	DurationMS     *int64                 `json:"duration_ms,omitempty"`
+	Error          *string                `json:"error,omitempty"`
+	Steps          []StepDetail           `json:"steps"`
+
+	rawTrigger *string
+}
+
+// StepDetail is one stored action execution
+type StepDetail struct {
+	ID             int                    `json:"id"`
+	ActionID       int                    `json:"action_id"`
+	Service        string                 `json:"service"`
+	Status         string                 `json:"status"`
+	Input          map[string]interface{} `json:"input"`
+	Output         map[string]interface{} `json:"output"`
+	RedactedFields []string               `json:"redacted_fields,omitempty"`
+	StartedAt      time.Time              `json:"started_at"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+	DurationMS     *int64                 `json:"duration_ms,omitempty"`
+	Error          *string                `json:"error,omitempty"`
+
+	rawInput  *string
+	rawOutput *string
+}
+
+// ExecutionSummary is one row of a workflow's execution history
+type ExecutionSummary struct {
+	ID          int        `json:"id"`
+	WorkflowID  int        `json:"workflow_id"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DurationMS  *int64     `json:"duration_ms,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	Steps       int        `json:"steps"`
+	FailedSteps int        `json:"failed_steps"`
+}
+
+// RecordExecution stores a run with its steps. Trigger data and step inputs
+// and outputs pass through the store's redaction policy first; the originals
+// are kept, encrypted, only for connectors whose policy allows it and only
+// when the store has a Sealer.
+func (s *Store) RecordExecution(w *Workflow, trigger map[string]interface{}, steps []Step, runErr error) (int, error) {
+	status := ExecutionCompleted
+	var errText *string
+	if runErr != nil {
+		status = ExecutionFailed
+		text := runErr.Error()
+		errText = &text
+	}
+
+	triggerData, triggerPaths, rawTrigger, err := s.prepare(w.TriggerService, trigger)
+	if err != nil {
+		return 0, err
+	}
+	triggerRedacted, err := marshalPaths(triggerPaths)
+	if err != nil {
+		return 0, err
+	}
+
+	startedAt := time.Now()
+	if len(steps) > 0 {
+		startedAt = steps[0].StartedAt
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var executionID int
+	err = tx.QueryRow(
+		`INSERT INTO workflow_executions (workflow_id, workspace_id, status, trigger_data, redacted_fields, raw_trigger_data, started_at, completed_at, error)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, $8)
+		 RETURNING id`,
+		w.ID, w.WorkspaceID, status, triggerData, triggerRedacted, rawTrigger, startedAt, errText,
+	).Scan(&executionID)
+	if err != nil {
+		return 0, fmt.Errorf("error recording execution: %w", err)
+	}
+
+	for _, step := range steps {
+		input, inputPaths, rawInput, err := s.prepare(step.Call.Service, step.Call.Fields)
+		if err != nil {
+			return 0, err
+		}
+		output, outputPaths, rawOutput, err := s.prepare(step.Call.Service, step.Output)
+		if err != nil {
+			return 0, err
+		}
+
+		// Step paths say whether they were found in the input or the output
+		var paths []string
+		for _, path := range inputPaths {
+			paths = append(paths, "input."+path)
+		}
+		for _, path := range outputPaths {
+			paths = append(paths, "output."+path)
+		}
+		redacted, err := marshalPaths(paths)
+		if err != nil {
+			return 0, err
+		}
+
+		stepStatus := ExecutionCompleted
+		var stepErr *string
+		if step.Err != nil {
+			stepStatus = ExecutionFailed
+			text := step.Err.Error()
+			stepErr = &text
+		}
+
+		if _, err := tx.Exec(
+			`INSERT INTO workflow_action_executions
+			 (workflow_execution_id, workflow_action_id, status, input_data, output_data, redacted_fields, raw_input_data, raw_output_data, started_at, completed_at, error)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			executionID, step.ActionID, stepStatus, input, output, redacted, rawInput, rawOutput, step.StartedAt, step.CompletedAt, stepErr,
+		); err != nil {
+			return 0, fmt.Errorf("error recording action execution: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing execution: %w", err)
+	}
+
+	return executionID, nil
+}
+
+// prepare redacts data for storage and seals the original when policy
+// allows. It returns the redacted JSON, the redacted paths and the sealed original.
+func (s *Store) prepare(connector string, data map[string]interface{}) (string, []string, *string, error) {
+	redacted, paths := s.Redaction.Redact(connector, data)
+
+	stored, err := marshalConfig(redacted)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(paths) == 0 || s.Sealer == nil || !s.Redaction.AllowsUnredacted(connector) {
+		return stored, paths, nil, nil
+	}
+
+	original, err := json.Marshal(data)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("error marshaling execution data: %w", err)
+	}
+	sealed, err := s.Sealer.Encrypt(original)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("error encrypting execution data: %w", err)
+	}
+	return stored, paths, &sealed, nil
+}
+
+// GetExecution loads an execution with its steps. With unredacted set, the
+// sealed originals replace the redacted data; ErrUnredactedNotAllowed is
+// returned when redacted data has no original to show.
+func (s *Store) GetExecution(id int, unredacted bool) (*ExecutionDetail, error) {
+	e := &ExecutionDetail{ID: id}
+	var triggerData, redacted sql.NullString
+	err := s.DB.QueryRow(
+		`SELECT e.workflow_id, e.workspace_id, e.status, w.trigger_service, e.trigger_data, e.redacted_fields, e.raw_trigger_data,
+		        e.started_at, e.completed_at, e.error
+		 FROM workflow_executions e
+		 JOIN workflows w ON w.id = e.workflow_id
+		 WHERE e.id = $1`, id,
+	).Scan(&e.WorkflowID, &e.WorkspaceID, &e.Status, &e.TriggerService, &triggerData, &redacted, &e.rawTrigger,
+		&e.StartedAt, &e.CompletedAt, &e.Error)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting execution: %w", err)
+	}
+
+	if e.TriggerData, err = unmarshalConfig(triggerData.String); err != nil {
+		return nil, err
+	}
+	if e.RedactedFields, err = unmarshalPaths(redacted.String); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT ae.id, ae.workflow_action_id, a.action_service, ae.status, ae.input_data, ae.output_data, ae.redacted_fields,
+		        ae.raw_input_data, ae.raw_output_data, ae.started_at, ae.completed_at, ae.error
+		 FROM workflow_action_executions ae
+		 JOIN workflow_actions a ON a.id = ae.workflow_action_id
+		 WHERE ae.workflow_execution_id = $1
+		 ORDER BY ae.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting action executions: %w", err)
+	}
+	defer rows.Close()
+
+	e.Steps = []StepDetail{}
+	for rows.Next() {
+		var step StepDetail
+		var input, output, stepRedacted sql.NullString
+		if err := rows.Scan(&step.ID, &step.ActionID, &step.Service, &step.Status, &input, &output, &stepRedacted,
+			&step.rawInput, &step.rawOutput, &step.StartedAt, &step.CompletedAt, &step.Error); err != nil {
+			return nil, fmt.Errorf("error scanning action execution: %w", err)
+		}
+		if step.Input, err = unmarshalConfig(input.String); err != nil {
+			return nil, err
+		}
+		if step.Output, err = unmarshalConfig(output.String); err != nil {
+			return nil, err
+		}
+		if step.RedactedFields, err = unmarshalPaths(stepRedacted.String); err != nil {
+			return nil, err
+		}
+		step.DurationMS = duration(step.StartedAt, step.CompletedAt)
+		e.Steps = append(e.Steps, step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading action executions: %w", err)
+	}
+
+	e.DurationMS = duration(e.StartedAt, e.CompletedAt)
+	e.Redacted = len(e.RedactedFields) > 0
+	for _, step := range e.Steps {
+		if len(step.RedactedFields) > 0 {
+			e.Redacted = true
+		}
+	}
+
+	if unredacted && e.Redacted {
+		if err := s.unseal(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// unseal replaces redacted data with the stored originals
+func (s *Store) unseal(e *ExecutionDetail) error {
+	if s.Sealer == nil {
+		return ErrUnredactedNotAllowed
+	}
+
+	if len(e.RedactedFields) > 0 {
+		original, err := s.open(e.rawTrigger)
+		if err != nil {
+			return err
+		}
+		e.TriggerData = original
+	}
+
+	for i := range e.Steps {
+		step := &e.Steps[i]
+		if len(step.RedactedFields) == 0 {
+			continue
+		}
+		if step.rawInput == nil && step.rawOutput == nil {
+			return ErrUnredactedNotAllowed
+		}
+		if step.rawInput != nil {
+			original, err := s.open(step.rawInput)
+			if err != nil {
+				return err
+			}
+			step.Input = original
+		}
+		if step.rawOutput != nil {
+			original, err := s.open(step.rawOutput)
+			if err != nil {
+				return err
+			}
+			step.Output = original
+		}
+	}
+
+	e.Redacted = false
+	return nil
+}
+
+// open decrypts one sealed original
+func (s *Store) open(sealed *string) (map[string]interface{}, error) {
+	if sealed == nil {
+		return nil, ErrUnredactedNotAllowed
+	}
+
+	data, err := s.Sealer.Decrypt(*sealed)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting execution data: %w", err)
+	}
+
+	var original map[string]interface{}
+	if err := json.Unmarshal(data, &original); err != nil {
+		return nil, fmt.Errorf("error unmarshaling execution data: %w", err)
+	}
+	return original, nil
+}
+
+// ListExecutions returns a workflow's most recent executions, optionally filtered by status
+func (s *Store) ListExecutions(workflowID int, status string, limit int) ([]ExecutionSummary, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT e.id, e.workflow_id, e.status, e.started_at, e.completed_at, e.error,
+		        COUNT(ae.id), COUNT(ae.id) FILTER (WHERE ae.status = $4)
+		 FROM workflow_executions e
+		 LEFT JOIN workflow_action_executions ae ON ae.workflow_execution_id = e.id
+		 WHERE e.workflow_id = $1 AND ($2 = '' OR e.status = $2)
+		 GROUP BY e.id
+		 ORDER BY e.started_at DESC
+		 LIMIT $3`, workflowID, status, limit, ExecutionFailed)
+	if err != nil {
+		return nil, fmt.Errorf("error listing executions: %w", err)
+	}
+	defer rows.Close()
+
+	executions := []ExecutionSummary{}
+	for rows.Next() {
+		var e ExecutionSummary
+		if err := rows.Scan(&e.ID, &e.WorkflowID, &e.Status, &e.StartedAt, &e.CompletedAt, &e.Error, &e.Steps, &e.FailedSteps); err != nil {
+			return nil, fmt.Errorf("error scanning execution: %w", err)
+		}
+		e.DurationMS = duration(e.StartedAt, e.CompletedAt)
+		executions = append(executions, e)
+	}
+
+	return executions, rows.Err()
+}
+
+// RecordExecution is an execution triggered by one ServiceNow record, with
+// the workflow it ran
+type RecordExecution struct {
+	ExecutionSummary
+	WorkflowName string `json:"workflow_name"`
+}
+
+// ExecutionsForRecord returns the executions whose trigger was the record
+// with sysID, oldest first
+func (s *Store) ExecutionsForRecord(sysID string, limit int) ([]RecordExecution, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT e.id, e.workflow_id, w.name, e.status, e.started_at, e.completed_at, e.error,
+		        COUNT(ae.id), COUNT(ae.id) FILTER (WHERE ae.status = $3)
+		 FROM workflow_executions e
+		 JOIN workflows w ON w.id = e.workflow_id
+		 LEFT JOIN workflow_action_executions ae ON ae.workflow_execution_id = e.id
+		 WHERE e.trigger_data::jsonb ->> 'sys_id' = $1
+		 GROUP BY e.id, w.name
+		 ORDER BY e.started_at
+		 LIMIT $2`, sysID, limit, ExecutionFailed)
+	if err != nil {
+		return nil, fmt.Errorf("error listing executions for record: %w", err)
+	}
+	defer rows.Close()
+
+	executions := []RecordExecution{}
+	for rows.Next() {
+		var e RecordExecution
+		if err := rows.Scan(&e.ID, &e.WorkflowID, &e.WorkflowName, &e.Status, &e.StartedAt, &e.CompletedAt, &e.Error, &e.Steps, &e.FailedSteps); err != nil {
+			return nil, fmt.Errorf("error scanning execution: %w", err)
+		}
+		e.DurationMS = duration(e.StartedAt, e.CompletedAt)
+		executions = append(executions, e)
+	}
+
+	return executions, rows.Err()
+}
+
+// duration returns the milliseconds between start and completion, if completed
+func duration(startedAt time.Time, completedAt *time.Time) *int64 {
+	if completedAt == nil {
+		return nil
+	}
+	ms := completedAt.Sub(startedAt).Milliseconds()
+	return &ms
+}
+
+// marshalPaths encodes redacted paths for storage; nil when there are none
+func marshalPaths(paths []string) (*string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(paths)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling redacted fields: %w", err)
+	}
+	text := string(data)
+	return &text, nil
+}
+
+// unmarshalPaths decodes a stored list of redacted paths
+func unmarshalPaths(data string) ([]string, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var paths []string
+	if err := json.Unmarshal([]byte(data), &paths); err != nil {
+		return nil, fmt.Errorf("error unmarshaling redacted fields: %w", err)
+	}
+	return paths, nil
+}
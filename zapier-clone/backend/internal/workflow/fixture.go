@@ -0,0 +1,306 @@
+// backend/internal/workflow/fixture.go
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is a declarative regression case for workflow mapping logic:
+// given a trigger payload, the engine must make the expected outbound calls.
+//
+//	name: high risk score becomes a Highest priority issue
+//	workflow: grc-risk-notify
+//	trigger:
+//	  short_description: Unpatched VPN appliance
+//	  risk_score: "85"
+//	expect:
+//	  calls:
+//	    - service: slack
+//	      action: post_message
+//	    - service: jira
+//	      action: create_issue
+//	      fields:
+//	        priority: Highest
+type Fixture struct {
+	Name string `yaml:"name"`
+	// Workflow is the key of a built-in workflow; Actions and Mappings override its definition
+	Workflow       string `yaml:"workflow"`
+	TriggerService string `yaml:"trigger_service"`
+	// TriggerConfig overrides the workflow's trigger config, for conditions on workflow.table
+	TriggerConfig map[string]interface{} `yaml:"trigger_config"`
+	Actions       []FixtureAction        `yaml:"actions"`
+	Mappings      []FixtureMapping       `yaml:"mappings"`
+	Trigger       map[string]interface{} `yaml:"trigger"`
+	// Responses are returned by the mocked connectors, keyed by "service.action"
+	Responses map[string]map[string]interface{} `yaml:"responses"`
+	Expect    FixtureExpectation                `yaml:"expect"`
+
+	// File is the path the fixture was loaded from
+	File string `yaml:"-"`
+}
+
+// FixtureAction is an action in a fixture's inline workflow
+type FixtureAction struct {
+	Service string                 `yaml:"service"`
+	Action  string                 `yaml:"action"`
+	Config  map[string]interface{} `yaml:"config"`
+}
+
+// FixtureMapping is a data mapping in a fixture's inline workflow
+type FixtureMapping struct {
+	From        string `yaml:"from"` // "service.field"
+	To          string `yaml:"to"`   // "service.field"
+	Transformer string `yaml:"transformer"`
+}
+
+// FixtureExpectation describes the outcome a fixture requires. Calls must match in
+// order and number; only the fields listed for a call are compared.
+type FixtureExpectation struct {
+	Calls []Call `yaml:"calls"`
+	// Error, if set, must appear in the run's error message
+	Error string `yaml:"error"`
+}
+
+// FixtureResult is the outcome of running one fixture
+type FixtureResult struct {
+	Fixture  *Fixture
+	Calls    []Call
+	Failures []string
+}
+
+// Passed reports whether the fixture met all expectations
+func (r *FixtureResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// LoadFixtures reads every .yaml/.yml fixture under dir, sorted by path
+func LoadFixtures(dir string) ([]*Fixture, error) {
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixtures from %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	var fixtures []*Fixture
+	for _, path := range paths {
+		fixture, err := LoadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// LoadFixture reads a single fixture file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixture %s: %w", path, err)
+	}
+
+	var fixture Fixture
+	if err := yaml.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("error parsing fixture %s: %w", path, err)
+	}
+	fixture.File = path
+	if fixture.Name == "" {
+		fixture.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &fixture, nil
+}
+
+// Definition builds the workflow the fixture runs, starting from the built-in
+// workflow named by Workflow (if any) and applying inline overrides
+func (f *Fixture) Definition() (*Workflow, error) {
+	w := &Workflow{Name: f.Name, TriggerService: "servicenow"}
+
+	if f.Workflow != "" {
+		seed := findSeed(f.Workflow)
+		if seed == nil {
+			return nil, fmt.Errorf("unknown workflow %q", f.Workflow)
+		}
+		*w = *seed
+	}
+	if f.TriggerService != "" {
+		w.TriggerService = f.TriggerService
+	}
+	if f.TriggerConfig != nil {
+		w.TriggerConfig = f.TriggerConfig
+	}
+
+	if len(f.Actions) > 0 {
+		w.Actions = nil
+		for i, a := range f.Actions {
+			w.Actions = append(w.Actions, Action{
+				ActionService: a.Service,
+				ActionID:      a.Action,
+				ActionConfig:  a.Config,
+				Position:      i,
+			})
+		}
+	}
+
+	if len(f.Mappings) > 0 {
+		w.DataMappings = nil
+		for _, m := range f.Mappings {
+			sourceService, sourceField, ok := splitServiceField(m.From)
+			if !ok {
+				return nil, fmt.Errorf("invalid mapping source %q, expected service.field", m.From)
+			}
+			targetService, targetField, ok := splitServiceField(m.To)
+			if !ok {
+				return nil, fmt.Errorf("invalid mapping target %q, expected service.field", m.To)
+			}
+			w.DataMappings = append(w.DataMappings, DataMapping{
+				SourceService: sourceService,
+				SourceField:   sourceField,
+				TargetService: targetService,
+				TargetField:   targetField,
+				Transformer:   m.Transformer,
+			})
+		}
+	}
+
+	if len(w.Actions) == 0 {
+		return nil, fmt.Errorf("workflow has no actions")
+	}
+	return w, nil
+}
+
+// RunFixture executes a fixture against mocked connectors and checks its expectations
+func RunFixture(f *Fixture) *FixtureResult {
+	result := &FixtureResult{Fixture: f}
+
+	w, err := f.Definition()
+	if err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+
+	connectors := make(map[string]Connector)
+	for _, action := range w.Actions {
+		connectors[action.ActionService] = &mockConnector{Responses: f.Responses}
+	}
+
+	calls, err := NewEngine(connectors).Run(w, f.Trigger)
+	result.Calls = calls
+
+	switch {
+	case err != nil && f.Expect.Error == "":
+		result.Failures = append(result.Failures, fmt.Sprintf("unexpected error: %v", err))
+	case err == nil && f.Expect.Error != "":
+		result.Failures = append(result.Failures, fmt.Sprintf("expected error containing %q, got none", f.Expect.Error))
+	case err != nil && !strings.Contains(err.Error(), f.Expect.Error):
+		result.Failures = append(result.Failures, fmt.Sprintf("expected error containing %q, got %v", f.Expect.Error, err))
+	}
+
+	if f.Expect.Error == "" || len(f.Expect.Calls) > 0 {
+		result.Failures = append(result.Failures, compareCalls(f.Expect.Calls, calls)...)
+	}
+	return result
+}
+
+// compareCalls checks actual calls against the expected ones
+func compareCalls(expected, actual []Call) []string {
+	var failures []string
+	if len(expected) != len(actual) {
+		failures = append(failures, fmt.Sprintf("expected %d calls, got %d: %s", len(expected), len(actual), describeCalls(actual)))
+	}
+
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		want, got := expected[i], actual[i]
+		if want.Service != got.Service || want.Action != got.Action {
+			failures = append(failures, fmt.Sprintf("call %d: expected %s.%s, got %s.%s", i+1, want.Service, want.Action, got.Service, got.Action))
+			continue
+		}
+		for _, field := range sortedKeys(want.Fields) {
+			gotValue, ok := got.Fields[field]
+			if !ok {
+				failures = append(failures, fmt.Sprintf("call %d (%s.%s): field %q not set", i+1, got.Service, got.Action, field))
+				continue
+			}
+			// Compare printed values so YAML ints match the strings ServiceNow sends
+			if fmt.Sprint(want.Fields[field]) != fmt.Sprint(gotValue) {
+				failures = append(failures, fmt.Sprintf("call %d (%s.%s): field %q expected %v, got %v", i+1, got.Service, got.Action, field, want.Fields[field], gotValue))
+			}
+		}
+		for _, key := range sortedKeys(want.Config) {
+			if fmt.Sprint(want.Config[key]) != fmt.Sprint(got.Config[key]) {
+				failures = append(failures, fmt.Sprintf("call %d (%s.%s): config %q expected %v, got %v", i+1, got.Service, got.Action, key, want.Config[key], got.Config[key]))
+			}
+		}
+	}
+	return failures
+}
+
+// mockConnector stands in for a real service and returns the fixture's canned response for each action
+type mockConnector struct {
+	Responses map[string]map[string]interface{}
+}
+
+// Execute implements Connector
+func (c *mockConnector) Execute(call Call) (map[string]interface{}, error) {
+	response := c.Responses[call.Service+"."+call.Action]
+	if errMsg, ok := response["error"].(string); ok && errMsg != "" {
+		return nil, fmt.Errorf("%s", errMsg)
+	}
+	return response, nil
+}
+
+// findSeed returns the built-in workflow with the given key
+func findSeed(key string) *Workflow {
+	for i := range SeedWorkflows {
+		if SeedWorkflows[i].Key == key {
+			return &SeedWorkflows[i]
+		}
+	}
+	return nil
+}
+
+// splitServiceField splits "service.field.path" into service and field path
+func splitServiceField(s string) (string, string, bool) {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// describeCalls renders calls as "service.action, ..." for failure messages
+func describeCalls(calls []Call) string {
+	if len(calls) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(calls))
+	for _, call := range calls {
+		names = append(names, call.Service+"."+call.Action)
+	}
+	return strings.Join(names, ", ")
+}
+
+// sortedKeys returns map keys in a stable order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,36 @@
+// backend/internal/workflow/fixture_test.go
+package workflow
+
+import (
+	"testing"
+)
+
+// fixtureDir is the fixture directory relative to this package
+const fixtureDir = "../../fixtures/workflows"
+
+// TestFixtures runs every YAML fixture in fixtures/workflows as a subtest, so
+// go test covers the same cases as go run ./cmd/fixtures
+func TestFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures(fixtureDir)
+	if err != nil {
+		t.Fatalf("error loading fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatalf("no fixtures found in %s", fixtureDir)
+	}
+
+	for _, fixture := range fixtures {
+		fixture := fixture
+		t.Run(fixture.Name, func(t *testing.T) {
+			result := RunFixture(fixture)
+			for _, failure := range result.Failures {
+				t.Errorf("%s: %s", fixture.File, failure)
+			}
+			if !result.Passed() {
+				for _, call := range result.Calls {
+					t.Logf("made %s.%s %v", call.Service, call.Action, call.Fields)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// backend/internal/workflow/models.go
+package workflow
+
+import (
+	"errors"
+	"time"
+)
+
+// Workflow statuses
+const (
+	StatusDraft    = "draft"
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+)
+
+// Environments a workflow can target; they match the environments of
+// connections
+const (
+	EnvironmentProduction = "production"
+	EnvironmentSandbox    = "sandbox"
+)
+
+// ErrNotFound is returned when a workflow does not exist
+var ErrNotFound = errors.New("workflow not found")
+
+// Workflow connects a trigger in one service to a sequence of actions
+type Workflow struct {
+	ID          int    `json:"id"`
+	Key         string `json:"key,omitempty"`
+	UserID      int    `json:"user_id"`
+	WorkspaceID *int   `json:"workspace_id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	// Environment selects the production or sandbox connections the actions use
+	Environment string `json:"environment"`
+
+	TriggerService string                 `json:"trigger_service"`
+	TriggerID      string                 `json:"trigger_id"`
+	TriggerConfig  map[string]interface{} `json:"trigger_config"`
+
+	Actions      []Action      `json:"actions"`
+	DataMappings []DataMapping `json:"data_mappings"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Action is one step of a workflow
+type Action struct {
+	ID            int                    `json:"id"`
+	ActionService string                 `json:"action_service"`
+	ActionID      string                 `json:"action_id"`
+	ActionConfig  map[string]interface{} `json:"action_config"`
+	Position      int                    `json:"position"`
+}
+
+// DataMapping copies a field from the trigger (or an earlier action) into an action
+type DataMapping struct {
+	ID            int    `json:"id"`
+	SourceService string `json:"source_service"`
+	SourceField   string `json:"source_field"`
+	TargetService string `json:"target_service"`
+	TargetField   string `json:"target_field"`
+	// Transformer is a chain of mapping transformers, such as "trim|prefix:GRC-"
+	Transformer string `json:"transformer,omitempty"`
+}
+
+// ListOptions filters workflow listings; zero values are ignored
+type ListOptions struct {
+	UserID         int
+	WorkspaceID    int
+	Status         string
+	Environment    string
+	TriggerService string
+	TriggerID      string
+}
@@ -0,0 +1,83 @@
+// backend/internal/workflow/recorder.go
+package workflow
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Run collects the steps of one execution of a stored workflow whose actions
+// are carried out by code outside the engine, such as the built-in ServiceNow
+// flows, so they appear in the execution history. A nil Run ignores all calls.
+type Run struct {
+	store    *Store
+	workflow *Workflow
+	trigger  map[string]interface{}
+	last     time.Time
+	steps    []Step
+}
+
+// StartRun begins recording an execution of the workflow with the given key.
+// It returns nil when there is no store or the workflow does not exist.
+func (s *Store) StartRun(key string, trigger map[string]interface{}) *Run {
+	if s == nil {
+		return nil
+	}
+
+	w, err := s.GetByKey(key)
+	if err != nil {
+		slog.Warn("not recording workflow execution", "workflow", key, "error", err)
+		return nil
+	}
+
+	return &Run{store: s, workflow: w, trigger: trigger, last: time.Now()}
+}
+
+// Step records one action; it is timed from the end of the previous step.
+// Steps that do not match an action of the workflow are not recorded.
+func (r *Run) Step(service, action string, input, output map[string]interface{}, err error) {
+	if r == nil {
+		return
+	}
+
+	step := Step{
+		Call:      Call{Service: service, Action: action, Fields: input},
+		StartedAt: r.last,
+	}
+	for _, a := range r.workflow.Actions {
+		if a.ActionService == service && a.ActionID == action {
+			step.ActionID = a.ID
+			step.Call.Config = a.ActionConfig
+			break
+		}
+	}
+	step = step.finish(output, err)
+	r.last = step.CompletedAt
+
+	if step.ActionID == 0 {
+		slog.Warn("workflow has no such action; step not recorded", "workflow", r.workflow.Key, "service", service, "action", action)
+		return
+	}
+	r.steps = append(r.steps, step)
+}
+
+// Finish stores the execution; failures to record are logged, not returned,
+// so history never blocks a sync
+func (r *Run) Finish(runErr error) {
+	if r == nil {
+		return
+	}
+
+	if runErr == nil {
+		for _, step := range r.steps {
+			if step.Err != nil {
+				runErr = step.Err
+				break
+			}
+		}
+	}
+
+	if _, err := r.store.RecordExecution(r.workflow, r.trigger, r.steps, runErr); err != nil {
+		slog.Error("error recording workflow execution", "workflow", r.workflow.Key, "error", err)
+	}
+}
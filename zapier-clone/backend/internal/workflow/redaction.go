@@ -0,0 +1,196 @@
+// backend/internal/workflow/redaction.go
+package workflow
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces redacted fields in stored execution data
+const RedactedValue = "[redacted]"
+
+// AllConnectors is the connector key whose rules apply to every connector
+const AllConnectors = "*"
+
+//go:embed redaction.yaml
+var defaultRedactionYAML []byte
+
+// ConnectorRedaction lists the field paths redacted for one connector.
+// Paths are dotted, e.g. "fields.reporter.emailAddress"; "*" matches one
+// segment and "**" any number of segments. Array elements are matched by the
+// same path as the array itself.
+type ConnectorRedaction struct {
+	Paths []string `yaml:"paths" json:"paths"`
+	// AllowUnredacted keeps an encrypted copy of the original data so
+	// workspace admins can view it
+	AllowUnredacted bool `yaml:"allow_unredacted" json:"allow_unredacted"`
+}
+
+// RedactionPolicy holds the redaction rules applied before execution data is stored
+type RedactionPolicy struct {
+	Connectors map[string]*ConnectorRedaction `yaml:"connectors" json:"connectors"`
+}
+
+// DefaultRedactionPolicy returns the built-in rules
+func DefaultRedactionPolicy() *RedactionPolicy {
+	policy, err := ParseRedactionPolicy(defaultRedactionYAML)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in redaction policy: %v", err))
+	}
+	return policy
+}
+
+// ParseRedactionPolicy reads rules from YAML or JSON
+func ParseRedactionPolicy(data []byte) (*RedactionPolicy, error) {
+	var policy RedactionPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("error parsing redaction policy: %w", err)
+	}
+	for connector, rules := range policy.Connectors {
+		if rules == nil {
+			return nil, fmt.Errorf("redaction rules for %s are empty", connector)
+		}
+		for _, path := range rules.Paths {
+			if strings.TrimSpace(path) == "" || strings.Contains(path, "..") {
+				return nil, fmt.Errorf("invalid redaction path %q for %s", path, connector)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+// LoadRedactionPolicy reads the file named by EXECUTION_REDACTION_FILE, or
+// returns the built-in rules when it is not set
+func LoadRedactionPolicy() (*RedactionPolicy, error) {
+	path := os.Getenv("EXECUTION_REDACTION_FILE")
+	if path == "" {
+		return DefaultRedactionPolicy(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading redaction policy: %w", err)
+	}
+	return ParseRedactionPolicy(data)
+}
+
+// AllowsUnredacted reports whether originals of a connector's data may be kept
+func (p *RedactionPolicy) AllowsUnredacted(connector string) bool {
+	if p == nil {
+		return false
+	}
+	rules, ok := p.Connectors[connector]
+	return ok && rules.AllowUnredacted
+}
+
+// paths returns the connector's rules plus those for every connector, split into segments
+func (p *RedactionPolicy) paths(connector string) [][]string {
+	if p == nil {
+		return nil
+	}
+
+	var patterns [][]string
+	for _, key := range []string{AllConnectors, connector} {
+		rules, ok := p.Connectors[key]
+		if !ok {
+			continue
+		}
+		for _, path := range rules.Paths {
+			patterns = append(patterns, strings.Split(strings.ToLower(path), "."))
+		}
+		if connector == AllConnectors {
+			break
+		}
+	}
+	return patterns
+}
+
+// Redact returns a copy of data with matching fields replaced by RedactedValue,
+// and the sorted paths that were redacted. data itself is not modified.
+func (p *RedactionPolicy) Redact(connector string, data map[string]interface{}) (map[string]interface{}, []string) {
+	patterns := p.paths(connector)
+	if data == nil || len(patterns) == 0 {
+		return data, nil
+	}
+
+	found := make(map[string]bool)
+	redacted := redactMap(data, nil, patterns, found)
+
+	paths := make([]string, 0, len(found))
+	for path := range found {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return redacted, paths
+}
+
+// redactMap copies one level of a document, redacting fields whose path matches
+func redactMap(data map[string]interface{}, prefix []string, patterns [][]string, found map[string]bool) map[string]interface{} {
+	copied := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		path := append(append([]string(nil), prefix...), key)
+		if matchesAny(path, patterns) {
+			copied[key] = RedactedValue
+			found[strings.Join(path, ".")] = true
+			continue
+		}
+		copied[key] = redactValue(value, path, patterns, found)
+	}
+	return copied
+}
+
+// redactValue descends into nested objects and arrays
+func redactValue(value interface{}, path []string, patterns [][]string, found map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactMap(v, path, patterns, found)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = redactValue(item, path, patterns, found)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// matchesAny reports whether a field path matches one of the patterns
+func matchesAny(path []string, patterns [][]string) bool {
+	lower := make([]string, len(path))
+	for i, segment := range path {
+		lower[i] = strings.ToLower(segment)
+	}
+	for _, pattern := range patterns {
+		if matchSegments(pattern, lower) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches a pattern with "*" and "**" wildcards against a path
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 || (pattern[0] != "*" && pattern[0] != path[0]) {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
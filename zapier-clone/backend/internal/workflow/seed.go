@@ -0,0 +1,112 @@
+// backend/internal/workflow/seed.go
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// SystemUsername owns the built-in workflows (created by migration 0004)
+const SystemUsername = "system"
+
+// SeedWorkflows are the built-in risk and incident flows, expressed as workflow definitions
+var SeedWorkflows = []Workflow{
+	{
+		Key:            "grc-risk-notify",
+		Name:           "New ServiceNow risk → Slack + Jira",
+		Description:    "Posts new GRC risks to #risk-management and opens a matching Jira issue.",
+		Status:         StatusActive,
+		TriggerService: "servicenow",
+		TriggerID:      "record_inserted",
+		TriggerConfig:  map[string]interface{}{"table": "sn_risk_risk"},
+		Actions: []Action{
+			{
+				ActionService: "slack",
+				ActionID:      "post_message",
+				ActionConfig:  map[string]interface{}{"channel": "risk-management", "template": "new_risk"},
+			},
+			{
+				ActionService: "jira",
+				ActionID:      "create_issue",
+				ActionConfig:  map[string]interface{}{"issue_type": "Risk", "link_back": true},
+			},
+		},
+		DataMappings: []DataMapping{
+			{SourceService: "servicenow", SourceField: "short_description", TargetService: "jira", TargetField: "summary"},
+			{SourceService: "servicenow", SourceField: "description", TargetService: "jira", TargetField: "description"},
+			{SourceService: "servicenow", SourceField: "risk_score", TargetService: "jira", TargetField: "priority", Transformer: "risk_score_to_priority"},
+			{SourceService: "servicenow", SourceField: "due_date", TargetService: "jira", TargetField: "duedate"},
+		},
+	},
+	{
+		Key:            "grc-incident-response",
+		Name:           "New security incident → Slack + Jira epic",
+		Description:    "Posts new security incidents to #incident-response and opens a Jira epic with response subtasks.",
+		Status:         StatusActive,
+		TriggerService: "servicenow",
+		TriggerID:      "record_inserted",
+		TriggerConfig:  map[string]interface{}{"table": "sn_si_incident"},
+		Actions: []Action{
+			{
+				ActionService: "slack",
+				ActionID:      "post_message",
+				ActionConfig:  map[string]interface{}{"channel": "incident-response", "template": "new_incident"},
+			},
+			{
+				ActionService: "jira",
+				ActionID:      "create_issue",
+				ActionConfig:  map[string]interface{}{"issue_type": "Epic", "link_back": true},
+			},
+			{
+				ActionService: "jira",
+				ActionID:      "create_subtasks",
+				ActionConfig: map[string]interface{}{"subtasks": []interface{}{
+					"Investigate", "Contain", "Eradicate", "Recover", "Post-incident review",
+				}},
+			},
+		},
+		DataMappings: []DataMapping{
+			{SourceService: "servicenow", SourceField: "short_description", TargetService: "jira", TargetField: "summary"},
+			{SourceService: "servicenow", SourceField: "description", TargetService: "jira", TargetField: "description"},
+			{SourceService: "servicenow", SourceField: "severity", TargetService: "jira", TargetField: "priority", Transformer: "severity_to_priority"},
+		},
+	},
+}
+
+// EnsureSeeds creates any built-in workflow that does not exist yet. Existing
+// seeds are left alone so edits made through the API are kept.
+func (s *Store) EnsureSeeds() error {
+	var userID int
+	if err := s.DB.QueryRow(`SELECT id FROM users WHERE username = $1`, SystemUsername).Scan(&userID); err != nil {
+		return fmt.Errorf("error finding system user: %w", err)
+	}
+
+	var workspaceID *int
+	var id int
+	if err := s.DB.QueryRow(`SELECT id FROM workspaces WHERE slug = 'system'`).Scan(&id); err == nil {
+		workspaceID = &id
+	}
+
+	for _, seed := range SeedWorkflows {
+		_, err := s.GetByKey(seed.Key)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		w := seed
+		w.UserID = userID
+		w.WorkspaceID = workspaceID
+		w.Actions = append([]Action(nil), seed.Actions...)
+		w.DataMappings = append([]DataMapping(nil), seed.DataMappings...)
+		if err := s.Create(&w); err != nil {
+			return fmt.Errorf("error seeding workflow %s: %w", seed.Key, err)
+		}
+		slog.Info("seeded workflow", "name", w.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+// backend/internal/workflow/store.go
+package workflow
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Store persists workflow definitions and executions in PostgreSQL
+type Store struct {
+	DB *sql.DB
+	// Redaction is applied to execution data before it is stored
+	Redaction *RedactionPolicy
+	// Sealer encrypts originals of redacted data; without it none are kept
+	Sealer Sealer
+}
+
+// NewStore creates a workflow store with the built-in redaction rules
+func NewStore(db *sql.DB) *Store {
+	return &Store{
+		DB:        db,
+		Redaction: DefaultRedactionPolicy(),
+	}
+}
+
+// Create inserts a workflow together with its actions and data mappings
+func (s *Store) Create(w *Workflow) error {
+	if w.Status == "" {
+		w.Status = StatusDraft
+	}
+	if w.Environment == "" {
+		w.Environment = EnvironmentProduction
+	}
+
+	triggerConfig, err := marshalConfig(w.TriggerConfig)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		`INSERT INTO workflows (key, user_id, workspace_id, name, description, status, environment, trigger_service, trigger_id, trigger_config)
+		 VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		 RETURNING id, created_at, updated_at`,
+		w.Key, w.UserID, w.WorkspaceID, w.Name, w.Description, w.Status, w.Environment, w.TriggerService, w.TriggerID, triggerConfig,
+	).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating workflow: %w", err)
+	}
+
+	if err := insertChildren(tx, w); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing workflow: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a workflow's definition, including its actions and data mappings
+func (s *Store) Update(w *Workflow) error {
+	if w.Environment == "" {
+		w.Environment = EnvironmentProduction
+	}
+	triggerConfig, err := marshalConfig(w.TriggerConfig)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		`UPDATE workflows
+		 SET name = $2, description = $3, status = $4, trigger_service = $5, trigger_id = $6, trigger_config = $7,
+		     workspace_id = $8, environment = $9, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $1
+		 RETURNING updated_at`,
+		w.ID, w.Name, w.Description, w.Status, w.TriggerService, w.TriggerID, triggerConfig, w.WorkspaceID, w.Environment,
+	).Scan(&w.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error updating workflow: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM workflow_data_mappings WHERE workflow_id = $1`, w.ID); err != nil {
+		return fmt.Errorf("error clearing data mappings: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM workflow_actions WHERE workflow_id = $1`, w.ID); err != nil {
+		return fmt.Errorf("error clearing actions: %w", err)
+	}
+
+	if err := insertChildren(tx, w); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing workflow: %w", err)
+	}
+
+	return nil
+}
+
+// SetStatus changes a workflow's status
+func (s *Store) SetStatus(id int, status string) error {
+	res, err := s.DB.Exec(
+		`UPDATE workflows SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id, status)
+	if err != nil {
+		return fmt.Errorf("error setting workflow status: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetEnvironment switches the environment a workflow's actions target
+func (s *Store) SetEnvironment(id int, environment string) error {
+	res, err := s.DB.Exec(
+		`UPDATE workflows SET environment = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id, environment)
+	if err != nil {
+		return fmt.Errorf("error setting workflow environment: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a workflow; actions, mappings and executions cascade
+func (s *Store) Delete(id int) error {
+	res, err := s.DB.Exec(`DELETE FROM workflows WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting workflow: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Get loads a workflow by ID
+func (s *Store) Get(id int) (*Workflow, error) {
+	return s.getOne(`WHERE id = $1`, id)
+}
+
+// GetByKey loads a workflow by its stable key
+func (s *Store) GetByKey(key string) (*Workflow, error) {
+	return s.getOne(`WHERE key = $1`, key)
+}
+
+// List loads the workflows matching the options
+func (s *Store) List(opts ListOptions) ([]*Workflow, error) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if opts.UserID != 0 {
+		add("user_id = $%d", opts.UserID)
+	}
+	if opts.WorkspaceID != 0 {
+		add("workspace_id = $%d", opts.WorkspaceID)
+	}
+	if opts.Status != "" {
+		add("status = $%d", opts.Status)
+	}
+	if opts.Environment != "" {
+		add("environment = $%d", opts.Environment)
+	}
+	if opts.TriggerService != "" {
+		add("trigger_service = $%d", opts.TriggerService)
+	}
+	if opts.TriggerID != "" {
+		add("trigger_id = $%d", opts.TriggerID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := s.DB.Query(selectWorkflow+" "+where+" ORDER BY name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing workflows: %w", err)
+	}
+	defer rows.Close()
+
+	var workflows []*Workflow
+	for rows.Next() {
+		w, err := scanWorkflow(rows)
+		if err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading workflows: %w", err)
+	}
+
+	for _, w := range workflows {
+		if err := s.loadChildren(w); err != nil {
+			return nil, err
+		}
+	}
+
+	return workflows, nil
+}
+
+// ActiveForTrigger returns the active workflows started by a trigger
+func (s *Store) ActiveForTrigger(service, triggerID string) ([]*Workflow, error) {
+	return s.List(ListOptions{Status: StatusActive, TriggerService: service, TriggerID: triggerID})
+}
+
+const selectWorkflow = `SELECT id, COALESCE(key, ''), user_id, workspace_id, name, COALESCE(description, ''), status,
+	environment, trigger_service, trigger_id, trigger_config, created_at, updated_at FROM workflows`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWorkflow(row rowScanner) (*Workflow, error) {
+	w := &Workflow{}
+	var triggerConfig string
+	err := row.Scan(&w.ID, &w.Key, &w.UserID, &w.WorkspaceID, &w.Name, &w.Description, &w.Status,
+		&w.Environment, &w.TriggerService, &w.TriggerID, &triggerConfig, &w.CreatedAt, &w.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning workflow: %w", err)
+	}
+
+	if w.TriggerConfig, err = unmarshalConfig(triggerConfig); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (s *Store) getOne(where string, arg interface{}) (*Workflow, error) {
+	w, err := scanWorkflow(s.DB.QueryRow(selectWorkflow+" "+where, arg))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadChildren(w); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// loadChildren loads a workflow's actions and data mappings
+func (s *Store) loadChildren(w *Workflow) error {
+	rows, err := s.DB.Query(
+		`SELECT id, action_service, action_id, action_config, position
+		 FROM workflow_actions WHERE workflow_id = $1 ORDER BY position`, w.ID)
+	if err != nil {
+		return fmt.Errorf("error loading actions: %w", err)
+	}
+	defer rows.Close()
+
+	w.Actions = nil
+	for rows.Next() {
+		var a Action
+		var config string
+		if err := rows.Scan(&a.ID, &a.ActionService, &a.ActionID, &config, &a.Position); err != nil {
+			return fmt.Errorf("error scanning action: %w", err)
+		}
+		if a.ActionConfig, err = unmarshalConfig(config); err != nil {
+			return err
+		}
+		w.Actions = append(w.Actions, a)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading actions: %w", err)
+	}
+
+	mappingRows, err := s.DB.Query(
+		`SELECT id, source_service, source_field, target_service, target_field, COALESCE(transformer, '')
+		 FROM workflow_data_mappings WHERE workflow_id = $1 ORDER BY id`, w.ID)
+	if err != nil {
+		return fmt.Errorf("error loading data mappings: %w", err)
+	}
+	defer mappingRows.Close()
+
+	w.DataMappings = nil
+	for mappingRows.Next() {
+		var m DataMapping
+		if err := mappingRows.Scan(&m.ID, &m.SourceService, &m.SourceField, &m.TargetService, &m.TargetField, &m.Transformer); err != nil {
+			return fmt.Errorf("error scanning data mapping: %w", err)
+		}
+		w.DataMappings = append(w.DataMappings, m)
+	}
+
+	return mappingRows.Err()
+}
+
+// insertChildren writes a workflow's actions and data mappings inside tx
+func insertChildren(tx *sql.Tx, w *Workflow) error {
+	for i := range w.Actions {
+		a := &w.Actions[i]
+		if a.Position == 0 {
+			a.Position = i + 1
+		}
+
+		config, err := marshalConfig(a.ActionConfig)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(
+			`INSERT INTO workflow_actions (workflow_id, action_service, action_id, action_config, position)
+			 VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			w.ID, a.ActionService, a.ActionID, config, a.Position,
+		).Scan(&a.ID)
+		if err != nil {
+			return fmt.Errorf("error creating action: %w", err)
+		}
+	}
+
+	for i := range w.DataMappings {
+		m := &w.DataMappings[i]
+		err := tx.QueryRow(
+			`INSERT INTO workflow_data_mappings (workflow_id, source_service, source_field, target_service, target_field, transformer)
+			 VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')) RETURNING id`,
+			w.ID, m.SourceService, m.SourceField, m.TargetService, m.TargetField, m.Transformer,
+		).Scan(&m.ID)
+		if err != nil {
+			return fmt.Errorf("error creating data mapping: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func marshalConfig(config map[string]interface{}) (string, error) {
+	if config == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling config: %w", err)
+	}
+	return string(data), nil
+}
+
+func unmarshalConfig(data string) (map[string]interface{}, error) {
+	config := make(map[string]interface{})
+	if data == "" {
+		return config, nil
+	}
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+	return config, nil
+}
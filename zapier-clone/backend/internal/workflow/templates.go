@@ -0,0 +1,237 @@
+// backend/internal/workflow/templates.go
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shivani-1505/zapier-clone/backend/internal/mapping"
+)
+
+// Template sources besides service outputs and WorkflowSource
+const (
+	// TriggerSource holds the trigger's service, id, config and data:
+	// {{trigger.data.short_description}}
+	TriggerSource = "trigger"
+	// MappingSource holds the fields mapped into the current action:
+	// {{mapping.jira_key}}
+	MappingSource = "mapping"
+)
+
+// expression matches {{source.path}} and {{source.path | transformer:arg | default:text}}
+var expression = regexp.MustCompile(`\{\{\s*([^{}|]+?)\s*((?:\|[^{}|]*)*)\}\}`)
+
+// ErrInvalidTemplate is returned for action configs whose templates cannot be rendered
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// renderConfig evaluates the templates in an action's config. A string that is
+// a single expression takes the value's own type, so lists and numbers pass
+// through; expressions inside longer text are printed.
+func (e *Engine) renderConfig(config map[string]interface{}, scope map[string]map[string]interface{}) (map[string]interface{}, error) {
+	if config == nil {
+		return nil, nil
+	}
+	rendered, err := e.renderValue(config, scope, "")
+	if err != nil {
+		return nil, err
+	}
+	return rendered.(map[string]interface{}), nil
+}
+
+// renderValue renders strings and walks maps and lists; path names the
+// config key for errors
+func (e *Engine) renderValue(value interface{}, scope map[string]map[string]interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return e.renderString(v, scope, path)
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			r, err := e.renderValue(item, scope, joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			rendered[key] = r
+		}
+		return rendered, nil
+	case []interface{}:
+		rendered := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := e.renderValue(item, scope, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			rendered[i] = r
+		}
+		return rendered, nil
+	default:
+		return value, nil
+	}
+}
+
+// renderString evaluates the expressions in one config string
+func (e *Engine) renderString(text string, scope map[string]map[string]interface{}, path string) (interface{}, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	matches := expression.FindAllStringSubmatchIndex(text, -1)
+	if strings.Count(text, "{{") != len(matches) {
+		return nil, fmt.Errorf("%w: config %q: malformed expression in %q", ErrInvalidTemplate, path, text)
+	}
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(text) {
+		value, err := e.evaluate(text, scope)
+		if err != nil {
+			return nil, fmt.Errorf("%w: config %q: %v", ErrInvalidTemplate, path, err)
+		}
+		return value, nil
+	}
+
+	var rendered strings.Builder
+	last := 0
+	for _, m := range matches {
+		rendered.WriteString(text[last:m[0]])
+		value, err := e.evaluate(text[m[0]:m[1]], scope)
+		if err != nil {
+			return nil, fmt.Errorf("%w: config %q: %v", ErrInvalidTemplate, path, err)
+		}
+		if value != nil {
+			rendered.WriteString(fmt.Sprint(value))
+		}
+		last = m[1]
+	}
+	rendered.WriteString(text[last:])
+	return rendered.String(), nil
+}
+
+// evaluate looks up one {{...}} expression and applies its pipes. A missing
+// field is an error naming the fields that do exist, unless a default pipe
+// supplies a value.
+func (e *Engine) evaluate(match string, scope map[string]map[string]interface{}) (interface{}, error) {
+	parts := expression.FindStringSubmatch(match)
+	reference := strings.TrimSpace(parts[1])
+
+	value, lookupErr := resolveReference(scope, reference)
+	for _, t := range mapping.ParseTransforms(parts[2]) {
+		if t.Name == "default" {
+			if lookupErr != nil || value == nil || value == "" {
+				value, lookupErr = t.Arg, nil
+			}
+			continue
+		}
+		if lookupErr != nil {
+			break
+		}
+
+		transformed, err := mapping.ApplyTransforms(value, []mapping.Transform{t})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", match, err)
+		}
+		value = transformed
+	}
+
+	if lookupErr != nil {
+		return nil, fmt.Errorf("%s: %v", match, lookupErr)
+	}
+	return value, nil
+}
+
+// resolveReference follows "source.path" through the scope, reporting the
+// first missing segment and what was available there
+func resolveReference(scope map[string]map[string]interface{}, reference string) (interface{}, error) {
+	source, path, ok := splitServiceField(reference)
+	if !ok {
+		return nil, fmt.Errorf("%q must be source.field, for example trigger.data.short_description", reference)
+	}
+	data, ok := scope[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q (available: %s)", source, strings.Join(sortedScope(scope), ", "))
+	}
+
+	var current interface{} = data
+	walked := source
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not an object, so it has no field %q", walked, part)
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %q (fields: %s)", walked, part, strings.Join(sortedKeys(m), ", "))
+		}
+		walked += "." + part
+	}
+	return current, nil
+}
+
+// templateScope is what action config templates can read: every output so
+// far, the trigger and the current action's mapped fields
+func templateScope(w *Workflow, trigger map[string]interface{}, outputs map[string]map[string]interface{}, fields map[string]interface{}) map[string]map[string]interface{} {
+	scope := make(map[string]map[string]interface{}, len(outputs)+2)
+	for source, output := range outputs {
+		scope[source] = output
+	}
+	scope[TriggerSource] = map[string]interface{}{
+		"service": w.TriggerService,
+		"id":      w.TriggerID,
+		"config":  w.TriggerConfig,
+		"data":    trigger,
+	}
+	scope[MappingSource] = fields
+	return scope
+}
+
+// validateTemplates checks the expressions in an action's config for syntax
+// and for transformers that do not exist
+func validateTemplates(value interface{}, path string) error {
+	switch v := value.(type) {
+	case string:
+		if strings.Count(v, "{{") != len(expression.FindAllString(v, -1)) {
+			return fmt.Errorf("%w: config %q: malformed expression in %q", ErrInvalidTemplate, path, v)
+		}
+		for _, parts := range expression.FindAllStringSubmatch(v, -1) {
+			if _, _, ok := splitServiceField(strings.TrimSpace(parts[1])); !ok {
+				return fmt.Errorf("%w: config %q: %q must be source.field", ErrInvalidTemplate, path, strings.TrimSpace(parts[1]))
+			}
+			for _, t := range mapping.ParseTransforms(parts[2]) {
+				if _, ok := mapping.Transformers[t.Name]; !ok && t.Name != "default" {
+					return fmt.Errorf("%w: config %q: unknown transformer %q", ErrInvalidTemplate, path, t.Name)
+				}
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			if err := validateTemplates(item, joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := validateTemplates(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// joinPath names a nested config key
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedScope lists the sources a template can read
+func sortedScope(scope map[string]map[string]interface{}) []string {
+	sources := make([]string, 0, len(scope))
+	for source := range scope {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
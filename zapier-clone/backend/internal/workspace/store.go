@@ -0,0 +1,435 @@
+// backend/internal/workspace/store.go
+package workspace
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Store manages workspaces, memberships and workflow shares in PostgreSQL
+type Store struct {
+	DB *sql.DB
+}
+
+// NewStore creates a workspace store
+func NewStore(db *sql.DB) *Store {
+	return &Store{DB: db}
+}
+
+// CreateWorkspace creates a workspace with the creator as its owner
+func (s *Store) CreateWorkspace(name, slug string, createdBy int) (*Workspace, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ws := &Workspace{Name: name, Slug: slug, CreatedBy: &createdBy, Role: RoleOwner}
+	err = tx.QueryRow(
+		`INSERT INTO workspaces (name, slug, created_by) VALUES ($1, $2, $3) RETURNING id, created_at`,
+		name, slug, createdBy,
+	).Scan(&ws.ID, &ws.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error creating workspace: %w", err)
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)`,
+		ws.ID, createdBy, RoleOwner,
+	); err != nil {
+		return nil, fmt.Errorf("error adding workspace owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing workspace: %w", err)
+	}
+
+	return ws, nil
+}
+
+// GetWorkspace fetches a workspace by ID
+func (s *Store) GetWorkspace(id int) (*Workspace, error) {
+	ws := &Workspace{}
+	err := s.DB.QueryRow(
+		`SELECT id, name, slug, max_workflows, max_monthly_executions, created_by, created_at
+		 FROM workspaces WHERE id = $1`, id,
+	).Scan(&ws.ID, &ws.Name, &ws.Slug, &ws.MaxWorkflows, &ws.MaxMonthlyExecutions, &ws.CreatedBy, &ws.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting workspace: %w", err)
+	}
+
+	return ws, nil
+}
+
+// ListForUser returns the workspaces a user belongs to, with their role in each
+func (s *Store) ListForUser(userID int) ([]Workspace, error) {
+	rows, err := s.DB.Query(
+		`SELECT w.id, w.name, w.slug, w.max_workflows, w.max_monthly_executions, w.created_by, w.created_at, m.role
+		 FROM workspaces w
+		 JOIN workspace_members m ON m.workspace_id = w.id
+		 WHERE m.user_id = $1
+		 ORDER BY w.name`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	var workspaces []Workspace
+	for rows.Next() {
+		var ws Workspace
+		if err := rows.Scan(&ws.ID, &ws.Name, &ws.Slug, &ws.MaxWorkflows, &ws.MaxMonthlyExecutions, &ws.CreatedBy, &ws.CreatedAt, &ws.Role); err != nil {
+			return nil, fmt.Errorf("error scanning workspace: %w", err)
+		}
+		workspaces = append(workspaces, ws)
+	}
+
+	return workspaces, rows.Err()
+}
+
+// MemberRole returns a user's role in a workspace
+func (s *Store) MemberRole(workspaceID, userID int) (string, error) {
+	var role string
+	err := s.DB.QueryRow(
+		`SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID, userID,
+	).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotMember
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting member role: %w", err)
+	}
+
+	return role, nil
+}
+
+// Members lists the members of a workspace
+func (s *Store) Members(workspaceID int) ([]Member, error) {
+	rows, err := s.DB.Query(
+		`SELECT m.workspace_id, m.user_id, u.username, u.email, m.role, m.created_at
+		 FROM workspace_members m
+		 JOIN users u ON u.id = m.user_id
+		 WHERE m.workspace_id = $1
+		 ORDER BY u.username`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []Member
+	for rows.Next() {
+		var m Member
+		if err := rows.Scan(&m.WorkspaceID, &m.UserID, &m.Username, &m.Email, &m.Role, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning member: %w", err)
+		}
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// SetMember adds a user to a workspace or changes their role
+func (s *Store) SetMember(workspaceID, userID int, role string) error {
+	if !ValidRole(role) {
+		return ErrInvalidRole
+	}
+
+	// Demoting the last owner would leave the workspace unmanageable
+	if role != RoleOwner {
+		if err := s.ensureOtherOwner(workspaceID, userID); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.DB.Exec(
+		`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)
+		 ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role`,
+		workspaceID, userID, role)
+	if err != nil {
+		return fmt.Errorf("error setting member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes a user from a workspace
+func (s *Store) RemoveMember(workspaceID, userID int) error {
+	if err := s.ensureOtherOwner(workspaceID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.DB.Exec(
+		`DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID, userID,
+	); err != nil {
+		return fmt.Errorf("error removing member: %w", err)
+	}
+
+	return nil
+}
+
+// ensureOtherOwner fails if userID is the only owner of the workspace
+func (s *Store) ensureOtherOwner(workspaceID, userID int) error {
+	var others int
+	err := s.DB.QueryRow(
+		`SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1 AND role = $2 AND user_id <> $3`,
+		workspaceID, RoleOwner, userID,
+	).Scan(&others)
+	if err != nil {
+		return fmt.Errorf("error counting owners: %w", err)
+	}
+
+	role, err := s.MemberRole(workspaceID, userID)
+	if errors.Is(err, ErrNotMember) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if role == RoleOwner && others == 0 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+// WorkflowWorkspace returns the workspace that owns a workflow
+func (s *Store) WorkflowWorkspace(workflowID int) (int, error) {
+	var workspaceID sql.NullInt64
+	err := s.DB.QueryRow(`SELECT workspace_id FROM workflows WHERE id = $1`, workflowID).Scan(&workspaceID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !workspaceID.Valid) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error getting workflow workspace: %w", err)
+	}
+
+	return int(workspaceID.Int64), nil
+}
+
+// Can reports whether a user may perform an action on a workflow, either through
+// membership of the owning workspace or through a share with one of their workspaces
+func (s *Store) Can(userID, workflowID int, action string) (bool, error) {
+	var workspaceID sql.NullInt64
+	var creatorID int
+	err := s.DB.QueryRow(`SELECT workspace_id, user_id FROM workflows WHERE id = $1`, workflowID).Scan(&workspaceID, &creatorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting workflow workspace: %w", err)
+	}
+
+	// Workflows outside any workspace are private to their creator
+	if !workspaceID.Valid {
+		return creatorID == userID, nil
+	}
+
+	role, err := s.MemberRole(int(workspaceID.Int64), userID)
+	if err == nil && RoleAllows(role, action) {
+		return true, nil
+	}
+	if err != nil && !errors.Is(err, ErrNotMember) {
+		return false, err
+	}
+
+	// Shares never grant management of the workflow
+	if action == ActionManage {
+		return false, nil
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT m.role, sh.permission
+		 FROM workflow_shares sh
+		 JOIN workspace_members m ON m.workspace_id = sh.workspace_id
+		 WHERE sh.workflow_id = $1 AND m.user_id = $2`, workflowID, userID)
+	if err != nil {
+		return false, fmt.Errorf("error checking workflow shares: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var memberRole, permission string
+		if err := rows.Scan(&memberRole, &permission); err != nil {
+			return false, fmt.Errorf("error scanning share: %w", err)
+		}
+		// Access through a share is limited by both the share and the member's role
+		if ShareAllows(permission, action) && RoleAllows(memberRole, action) {
+			return true, nil
+		}
+	}
+
+	return false, rows.Err()
+}
+
+// ShareWorkflow grants another workspace access to a workflow
+func (s *Store) ShareWorkflow(workflowID, workspaceID int, permission string, createdBy int) error {
+	if !ValidPermission(permission) {
+		return fmt.Errorf("invalid permission: %s", permission)
+	}
+
+	ownerID, err := s.WorkflowWorkspace(workflowID)
+	if err != nil {
+		return err
+	}
+	if ownerID == workspaceID {
+		return fmt.Errorf("workflow already belongs to workspace %d", workspaceID)
+	}
+
+	_, err = s.DB.Exec(
+		`INSERT INTO workflow_shares (workflow_id, workspace_id, permission, created_by) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (workflow_id, workspace_id) DO UPDATE SET permission = EXCLUDED.permission`,
+		workflowID, workspaceID, permission, createdBy)
+	if err != nil {
+		return fmt.Errorf("error sharing workflow: %w", err)
+	}
+
+	return nil
+}
+
+// UnshareWorkflow revokes a workspace's access to a workflow
+func (s *Store) UnshareWorkflow(workflowID, workspaceID int) error {
+	if _, err := s.DB.Exec(
+		`DELETE FROM workflow_shares WHERE workflow_id = $1 AND workspace_id = $2`,
+		workflowID, workspaceID,
+	); err != nil {
+		return fmt.Errorf("error unsharing workflow: %w", err)
+	}
+
+	return nil
+}
+
+// Shares lists the workspaces a workflow is shared with
+func (s *Store) Shares(workflowID int) ([]Share, error) {
+	rows, err := s.DB.Query(
+		`SELECT workflow_id, workspace_id, permission, created_by, created_at
+		 FROM workflow_shares WHERE workflow_id = $1 ORDER BY workspace_id`, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing shares: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []Share
+	for rows.Next() {
+		var sh Share
+		if err := rows.Scan(&sh.WorkflowID, &sh.WorkspaceID, &sh.Permission, &sh.CreatedBy, &sh.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning share: %w", err)
+		}
+		shares = append(shares, sh)
+	}
+
+	return shares, rows.Err()
+}
+
+// SetQuota sets a workspace's limits; nil removes a limit
+func (s *Store) SetQuota(workspaceID int, maxWorkflows, maxMonthlyExecutions *int) error {
+	res, err := s.DB.Exec(
+		`UPDATE workspaces SET max_workflows = $2, max_monthly_executions = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		workspaceID, maxWorkflows, maxMonthlyExecutions)
+	if err != nil {
+		return fmt.Errorf("error setting quota: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Usage returns a workspace's consumption for the current month
+func (s *Store) Usage(workspaceID int) (*Usage, error) {
+	ws, err := s.GetWorkspace(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &Usage{
+		MaxWorkflows:         ws.MaxWorkflows,
+		MaxMonthlyExecutions: ws.MaxMonthlyExecutions,
+	}
+
+	if err := s.DB.QueryRow(
+		`SELECT COUNT(*) FROM workflows WHERE workspace_id = $1`, workspaceID,
+	).Scan(&usage.Workflows); err != nil {
+		return nil, fmt.Errorf("error counting workflows: %w", err)
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if err := s.DB.QueryRow(
+		`SELECT COUNT(*) FROM workflow_executions WHERE workspace_id = $1 AND started_at >= $2`, workspaceID, monthStart,
+	).Scan(&usage.MonthlyExecutions); err != nil {
+		return nil, fmt.Errorf("error counting executions: %w", err)
+	}
+
+	return usage, nil
+}
+
+// CheckWorkflowQuota fails if the workspace cannot hold another workflow
+func (s *Store) CheckWorkflowQuota(workspaceID int) error {
+	usage, err := s.Usage(workspaceID)
+	if err != nil {
+		return err
+	}
+	if usage.MaxWorkflows != nil && usage.Workflows >= *usage.MaxWorkflows {
+		return fmt.Errorf("%w: %d of %d workflows", ErrQuotaExceeded, usage.Workflows, *usage.MaxWorkflows)
+	}
+	return nil
+}
+
+// CheckExecutionQuota fails if the workspace has used its executions for the month
+func (s *Store) CheckExecutionQuota(workspaceID int) error {
+	usage, err := s.Usage(workspaceID)
+	if err != nil {
+		return err
+	}
+	if usage.MaxMonthlyExecutions != nil && usage.MonthlyExecutions >= *usage.MaxMonthlyExecutions {
+		return fmt.Errorf("%w: %d of %d executions this month", ErrQuotaExceeded, usage.MonthlyExecutions, *usage.MaxMonthlyExecutions)
+	}
+	return nil
+}
+
+// Execution is a workflow execution record with its workspace context
+type Execution struct {
+	ID          int        `json:"id"`
+	WorkflowID  int        `json:"workflow_id"`
+	WorkspaceID int        `json:"workspace_id"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+}
+
+// Executions lists a workspace's most recent executions, optionally filtered by status
+func (s *Store) Executions(workspaceID int, status string, limit int) ([]Execution, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT id, workflow_id, workspace_id, status, started_at, completed_at, error
+		 FROM workflow_executions
+		 WHERE workspace_id = $1 AND ($2 = '' OR status = $2)
+		 ORDER BY started_at DESC
+		 LIMIT $3`, workspaceID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing executions: %w", err)
+	}
+	defer rows.Close()
+
+	var executions []Execution
+	for rows.Next() {
+		var e Execution
+		if err := rows.Scan(&e.ID, &e.WorkflowID, &e.WorkspaceID, &e.Status, &e.StartedAt, &e.CompletedAt, &e.Error); err != nil {
+			return nil, fmt.Errorf("error scanning execution: %w", err)
+		}
+		executions = append(executions, e)
+	}
+
+	return executions, rows.Err()
+}
@@ -0,0 +1,119 @@
+// backend/internal/workspace/workspace.go
+package workspace
+
+import (
+	"errors"
+	"time"
+)
+
+// Member roles, from most to least privileged
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Actions that can be performed on a workflow or workspace
+const (
+	ActionView   = "view"
+	ActionRun    = "run"
+	ActionEdit   = "edit"
+	ActionManage = "manage"
+)
+
+// Errors returned by the workspace store
+var (
+	ErrNotFound      = errors.New("workspace not found")
+	ErrNotMember     = errors.New("user is not a member of the workspace")
+	ErrForbidden     = errors.New("not allowed")
+	ErrLastOwner     = errors.New("workspace must keep at least one owner")
+	ErrQuotaExceeded = errors.New("workspace quota exceeded")
+	ErrInvalidRole   = errors.New("invalid role")
+)
+
+// roleActions lists what each role may do with the workspace's own workflows
+var roleActions = map[string][]string{
+	RoleOwner:  {ActionView, ActionRun, ActionEdit, ActionManage},
+	RoleAdmin:  {ActionView, ActionRun, ActionEdit, ActionManage},
+	RoleEditor: {ActionView, ActionRun, ActionEdit},
+	RoleViewer: {ActionView},
+}
+
+// shareActions lists what a share permission grants the receiving workspace
+var shareActions = map[string][]string{
+	ActionView: {ActionView},
+	ActionRun:  {ActionView, ActionRun},
+	ActionEdit: {ActionView, ActionRun, ActionEdit},
+}
+
+// Workspace is a team that owns workflows
+type Workspace struct {
+	ID                   int       `json:"id"`
+	Name                 string    `json:"name"`
+	Slug                 string    `json:"slug"`
+	MaxWorkflows         *int      `json:"max_workflows,omitempty"`
+	MaxMonthlyExecutions *int      `json:"max_monthly_executions,omitempty"`
+	CreatedBy            *int      `json:"created_by,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+	// Role is the requesting user's role when listing a user's workspaces
+	Role string `json:"role,omitempty"`
+}
+
+// Member is a user's membership in a workspace
+type Member struct {
+	WorkspaceID int       `json:"workspace_id"`
+	UserID      int       `json:"user_id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Share grants another workspace access to a workflow
+type Share struct {
+	WorkflowID  int       `json:"workflow_id"`
+	WorkspaceID int       `json:"workspace_id"`
+	Permission  string    `json:"permission"`
+	CreatedBy   *int      `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Usage is a workspace's consumption against its quotas
+type Usage struct {
+	Workflows            int  `json:"workflows"`
+	MaxWorkflows         *int `json:"max_workflows,omitempty"`
+	MonthlyExecutions    int  `json:"monthly_executions"`
+	MaxMonthlyExecutions *int `json:"max_monthly_executions,omitempty"`
+}
+
+// ValidRole reports whether role is a known member role
+func ValidRole(role string) bool {
+	_, ok := roleActions[role]
+	return ok
+}
+
+// ValidPermission reports whether permission is a known share permission
+func ValidPermission(permission string) bool {
+	_, ok := shareActions[permission]
+	return ok
+}
+
+// RoleAllows reports whether a member role grants an action
+func RoleAllows(role, action string) bool {
+	return contains(roleActions[role], action)
+}
+
+// ShareAllows reports whether a share permission grants an action
+func ShareAllows(permission, action string) bool {
+	return contains(shareActions[permission], action)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+// Sync events and record mappings for internal services.
+//
+// Regenerate the Go code from backend/ with:
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/sync/v1/sync.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v4.25.3
+// source: proto/sync/v1/sync.proto
+
+package syncv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	SyncEvents_Subscribe_FullMethodName = "/zapierclone.sync.v1.SyncEvents/Subscribe"
+)
+
+// SyncEventsClient is the client API for SyncEvents service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SyncEvents streams the webhooks the backend finishes syncing.
+type SyncEventsClient interface {
+	// Subscribe streams events from the moment of the call until the client
+	// cancels. Events are not replayed; a client that falls too far behind
+	// misses events, and the next event it receives reports how many.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (SyncEvents_SubscribeClient, error)
+}
+
+type syncEventsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSyncEventsClient(cc grpc.ClientConnInterface) SyncEventsClient {
+	return &syncEventsClient{cc}
+}
+
+func (c *syncEventsClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (SyncEvents_SubscribeClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SyncEvents_ServiceDesc.Streams[0], SyncEvents_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &syncEventsSubscribeClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SyncEvents_SubscribeClient interface {
+	Recv() (*SyncEvent, error)
+	grpc.ClientStream
+}
+
+type syncEventsSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *syncEventsSubscribeClient) Recv() (*SyncEvent, error) {
+	m := new(SyncEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SyncEventsServer is the server API for SyncEvents service.
+// All implementations must embed UnimplementedSyncEventsServer
+// for forward compatibility
+//
+// SyncEvents streams the webhooks the backend finishes syncing.
+type SyncEventsServer interface {
+	// Subscribe streams events from the moment of the call until the client
+	// cancels. Events are not replayed; a client that falls too far behind
+	// misses events, and the next event it receives reports how many.
+	Subscribe(*SubscribeRequest, SyncEvents_SubscribeServer) error
+	mustEmbedUnimplementedSyncEventsServer()
+}
+
+// UnimplementedSyncEventsServer must be embedded to have forward compatible implementations.
+type UnimplementedSyncEventsServer struct {
+}
+
+func (UnimplementedSyncEventsServer) Subscribe(*SubscribeRequest, SyncEvents_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedSyncEventsServer) mustEmbedUnimplementedSyncEventsServer() {}
+
+// UnsafeSyncEventsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SyncEventsServer will
+// result in compilation errors.
+type UnsafeSyncEventsServer interface {
+	mustEmbedUnimplementedSyncEventsServer()
+}
+
+func RegisterSyncEventsServer(s grpc.ServiceRegistrar, srv SyncEventsServer) {
+	s.RegisterService(&SyncEvents_ServiceDesc, srv)
+}
+
+func _SyncEvents_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SyncEventsServer).Subscribe(m, &syncEventsSubscribeServer{ServerStream: stream})
+}
+
+type SyncEvents_SubscribeServer interface {
+	Send(*SyncEvent) error
+	grpc.ServerStream
+}
+
+type syncEventsSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *syncEventsSubscribeServer) Send(m *SyncEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// SyncEvents_ServiceDesc is the grpc.ServiceDesc for SyncEvents service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SyncEvents_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "zapierclone.sync.v1.SyncEvents",
+	HandlerType: (*SyncEventsServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _SyncEvents_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/sync/v1/sync.proto",
+}
+
+const (
+	Mappings_LookupMapping_FullMethodName = "/zapierclone.sync.v1.Mappings/LookupMapping"
+	Mappings_ListMappings_FullMethodName  = "/zapierclone.sync.v1.Mappings/ListMappings"
+)
+
+// MappingsClient is the client API for Mappings service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Mappings looks up the links between ServiceNow records and Jira issues.
+type MappingsClient interface {
+	// LookupMapping finds the link of a ServiceNow record or of a Jira issue.
+	// Returns NOT_FOUND when neither is linked.
+	LookupMapping(ctx context.Context, in *LookupMappingRequest, opts ...grpc.CallOption) (*Mapping, error)
+	// ListMappings returns every link, optionally of one kind.
+	ListMappings(ctx context.Context, in *ListMappingsRequest, opts ...grpc.CallOption) (*ListMappingsResponse, error)
+}
+
+type mappingsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMappingsClient(cc grpc.ClientConnInterface) MappingsClient {
+	return &mappingsClient{cc}
+}
+
+func (c *mappingsClient) LookupMapping(ctx context.Context, in *LookupMappingRequest, opts ...grpc.CallOption) (*Mapping, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Mapping)
+	err := c.cc.Invoke(ctx, Mappings_LookupMapping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mappingsClient) ListMappings(ctx context.Context, in *ListMappingsRequest, opts ...grpc.CallOption) (*ListMappingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMappingsResponse)
+	err := c.cc.Invoke(ctx, Mappings_ListMappings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MappingsServer is the server API for Mappings service.
+// All implementations must embed UnimplementedMappingsServer
+// for forward compatibility
+//
+// Mappings looks up the links between ServiceNow records and Jira issues.
+type MappingsServer interface {
+	// LookupMapping finds the link of a ServiceNow record or of a Jira issue.
+	// Returns NOT_FOUND when neither is linked.
+	LookupMapping(context.Context, *LookupMappingRequest) (*Mapping, error)
+	// ListMappings returns every link, optionally of one kind.
+	ListMappings(context.Context, *ListMappingsRequest) (*ListMappingsResponse, error)
+	mustEmbedUnimplementedMappingsServer()
+}
+
+// UnimplementedMappingsServer must be embedded to have forward compatible implementations.
+type UnimplementedMappingsServer struct {
+}
+
+func (UnimplementedMappingsServer) LookupMapping(context.Context, *LookupMappingRequest) (*Mapping, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupMapping not implemented")
+}
+func (UnimplementedMappingsServer) ListMappings(context.Context, *ListMappingsRequest) (*ListMappingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMappings not implemented")
+}
+func (UnimplementedMappingsServer) mustEmbedUnimplementedMappingsServer() {}
+
+// UnsafeMappingsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MappingsServer will
+// result in compilation errors.
+type UnsafeMappingsServer interface {
+	mustEmbedUnimplementedMappingsServer()
+}
+
+func RegisterMappingsServer(s grpc.ServiceRegistrar, srv MappingsServer) {
+	s.RegisterService(&Mappings_ServiceDesc, srv)
+}
+
+func _Mappings_LookupMapping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupMappingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MappingsServer).LookupMapping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mappings_LookupMapping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MappingsServer).LookupMapping(ctx, req.(*LookupMappingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mappings_ListMappings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMappingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MappingsServer).ListMappings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mappings_ListMappings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MappingsServer).ListMappings(ctx, req.(*ListMappingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Mappings_ServiceDesc is the grpc.ServiceDesc for Mappings service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Mappings_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "zapierclone.sync.v1.Mappings",
+	HandlerType: (*MappingsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "LookupMapping",
+			Handler:    _Mappings_LookupMapping_Handler,
+		},
+		{
+			MethodName: "ListMappings",
+			Handler:    _Mappings_ListMappings_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/sync/v1/sync.proto",
+}
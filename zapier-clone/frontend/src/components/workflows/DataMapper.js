@@ -31,15 +31,18 @@ const DataMapperPanel = ({
   const [loadingTargetFields, setLoadingTargetFields] = useState(false);
   const [submitting, setSubmitting] = useState(false);
   
-  // Available transformers
+  // Available transformers (the backend's mapping.Transformers); ones that
+  // need an arg are set through the API
   const transformers = [
     { id: '', name: 'None' },
-    { id: 'toString', name: 'Convert to String' },
-    { id: 'toNumber', name: 'Convert to Number' },
-    { id: 'toBoolean', name: 'Convert to Boolean' },
-    { id: 'toArray', name: 'Convert to Array' },
-    { id: 'toLowerCase', name: 'Convert to Lowercase' },
-    { id: 'toUpperCase', name: 'Convert to Uppercase' }
+    { id: 'trim', name: 'Trim Spaces' },
+    { id: 'lowercase', name: 'Convert to Lowercase' },
+    { id: 'uppercase', name: 'Convert to Uppercase' },
+    { id: 'date', name: 'Format Date (YYYY-MM-DD)' },
+    { id: 'number', name: 'Format Number' },
+    { id: 'concat', name: 'Join List' },
+    { id: 'severity_to_priority', name: 'Severity to Jira Priority' },
+    { id: 'risk_score_to_priority', name: 'Risk Score to Jira Priority' }
   ];
   
   // Target services - all unique action_service values from the workflow actions
@@ -1,6 +1,9 @@
 import React, { createContext, useState, useContext, useEffect } from 'react';
 import { useNavigate } from 'react-router-dom';
-import api from '../services/api';
+import apiService from '../services/api';
+
+// Use the axios client directly so responses keep their status and headers
+const api = apiService.client;
 
 // Create the auth context
 const AuthContext = createContext();
@@ -36,14 +39,12 @@ export const AuthProvider = ({ children }) => {
           setIsAuthenticated(true);
         } else {
           // Clear invalid token
-          localStorage.removeItem('token');
-          delete api.defaults.headers.common['Authorization'];
+          clearSession();
         }
       } catch (err) {
         console.error('Auth initialization error:', err);
         // Clear invalid token
-        localStorage.removeItem('token');
-        delete api.defaults.headers.common['Authorization'];
+        clearSession();
         setError('Session expired. Please login again.');
       } finally {
         setLoading(false);
@@ -53,6 +54,19 @@ export const AuthProvider = ({ children }) => {
     initAuth();
   }, []);
 
+  // Keep the access token and the refresh token it was issued with
+  const storeSession = (data) => {
+    localStorage.setItem('token', data.token);
+    localStorage.setItem('refreshToken', data.refresh_token);
+    api.defaults.headers.common['Authorization'] = `Bearer ${data.token}`;
+  };
+
+  const clearSession = () => {
+    localStorage.removeItem('token');
+    localStorage.removeItem('refreshToken');
+    delete api.defaults.headers.common['Authorization'];
+  };
+
   // Login function
   const login = async (email, password) => {
     try {
@@ -62,8 +76,7 @@ export const AuthProvider = ({ children }) => {
       const response = await api.post('/api/v1/auth/login', { email, password });
       
       if (response.data && response.data.token) {
-        localStorage.setItem('token', response.data.token);
-        api.defaults.headers.common['Authorization'] = `Bearer ${response.data.token}`;
+        storeSession(response.data);
         
         // Fetch user profile
         const userResponse = await api.get('/api/v1/user/profile');
@@ -97,8 +110,7 @@ export const AuthProvider = ({ children }) => {
       });
       
       if (response.data && response.data.token) {
-        localStorage.setItem('token', response.data.token);
-        api.defaults.headers.common['Authorization'] = `Bearer ${response.data.token}`;
+        storeSession(response.data);
         
         // Fetch user profile
         const userResponse = await api.get('/api/v1/user/profile');
@@ -120,9 +132,18 @@ export const AuthProvider = ({ children }) => {
   };
 
   // Logout function
-  const logout = () => {
-    localStorage.removeItem('token');
-    delete api.defaults.headers.common['Authorization'];
+  const logout = async () => {
+    // Revoke the session on the server; sign out locally even if that fails
+    const refreshToken = localStorage.getItem('refreshToken');
+    if (localStorage.getItem('token')) {
+      try {
+        await api.post('/api/v1/auth/logout', { refresh_token: refreshToken });
+      } catch (err) {
+        console.error('Logout error:', err);
+      }
+    }
+    
+    clearSession();
     setUser(null);
     setIsAuthenticated(false);
     navigate('/login');
@@ -181,20 +202,19 @@ export const AuthProvider = ({ children }) => {
   // Refresh token function
   const refreshToken = async () => {
     try {
-      const currentToken = localStorage.getItem('token');
+      const currentRefreshToken = localStorage.getItem('refreshToken');
       
-      if (!currentToken) {
+      if (!currentRefreshToken) {
         logout();
         return false;
       }
       
       const response = await api.post('/api/v1/auth/refresh', {
-        token: currentToken
+        refresh_token: currentRefreshToken
       });
       
       if (response.data && response.data.token) {
-        localStorage.setItem('token', response.data.token);
-        api.defaults.headers.common['Authorization'] = `Bearer ${response.data.token}`;
+        storeSession(response.data);
         return true;
       } else {
         logout();
@@ -34,11 +34,14 @@ api.interceptors.response.use(
         // Try to refresh the token
         const refreshToken = localStorage.getItem('refreshToken');
         if (refreshToken) {
-          const res = await axios.post('/api/v1/auth/refresh', { token: refreshToken });
+          // Refresh tokens are single use, so keep the rotated one
+          const res = await axios.post(`${api.defaults.baseURL}/api/v1/auth/refresh`, { refresh_token: refreshToken });
           
           if (res.data.token) {
             localStorage.setItem('token', res.data.token);
+            localStorage.setItem('refreshToken', res.data.refresh_token);
             api.defaults.headers.common['Authorization'] = `Bearer ${res.data.token}`;
+            originalRequest.headers.Authorization = `Bearer ${res.data.token}`;
             return api(originalRequest);
           }
         }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// AttachmentRecord is an uploaded file, shaped like a row of ServiceNow's sys_attachment table
+type AttachmentRecord struct {
+	SysID        string `json:"sys_id"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    string `json:"size_bytes"`
+	TableName    string `json:"table_name"`
+	TableSysID   string `json:"table_sys_id"`
+	SysCreatedOn string `json:"sys_created_on"`
+}
+
+// Attachments holds uploaded files and their content, oldest first
+var Attachments = struct {
+	sync.Mutex
+	records []AttachmentRecord
+	content map[string][]byte
+	nextID  int
+}{content: make(map[string][]byte)}
+
+// handleAttachmentUpload stores the request body as a file attached to
+// ?table_name=&table_sys_id=&file_name=, like the Attachment API
+func handleAttachmentUpload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	if query.Get("table_name") == "" || query.Get("table_sys_id") == "" || query.Get("file_name") == "" {
+		http.Error(w, "table_name, table_sys_id and file_name are required", http.StatusBadRequest)
+		return
+	}
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	Attachments.Lock()
+	defer Attachments.Unlock()
+	Attachments.nextID++
+	record := AttachmentRecord{
+		SysID:        fmt.Sprintf("attachment%d", Attachments.nextID),
+		FileName:     query.Get("file_name"),
+		ContentType:  r.Header.Get("Content-Type"),
+		SizeBytes:    fmt.Sprint(len(content)),
+		TableName:    query.Get("table_name"),
+		TableSysID:   query.Get("table_sys_id"),
+		SysCreatedOn: time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+	Attachments.records = append(Attachments.records, record)
+	Attachments.content[record.SysID] = content
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ResponseResult{Result: record})
+}
+
+// handleAttachments lists uploaded files; table_sys_id and table_name narrow the list
+func handleAttachments(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	Attachments.Lock()
+	defer Attachments.Unlock()
+
+	query := r.URL.Query()
+	results := []AttachmentRecord{}
+	for _, record := range Attachments.records {
+		if (query.Get("table_sys_id") != "" && record.TableSysID != query.Get("table_sys_id")) ||
+			(query.Get("table_name") != "" && record.TableName != query.Get("table_name")) {
+			continue
+		}
+		results = append(results, record)
+	}
+	json.NewEncoder(w).Encode(ResponseResult{Result: results})
+}
+
+// handleAttachmentFile returns an uploaded file's content
+func handleAttachmentFile(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	Attachments.Lock()
+	defer Attachments.Unlock()
+
+	content, ok := Attachments.content[id]
+	if !ok {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+	for _, record := range Attachments.records {
+		if record.SysID == id {
+			w.Header().Set("Content-Type", record.ContentType)
+		}
+	}
+	w.Write(content)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AuditRecord is one field change, shaped like a row of ServiceNow's sys_audit table
+type AuditRecord struct {
+	SysID       string `json:"sys_id"`
+	DocumentKey string `json:"documentkey"` // sys_id of the changed record
+	TableName   string `json:"tablename"`
+	FieldName   string `json:"fieldname"`
+	OldValue    string `json:"oldvalue"`
+	NewValue    string `json:"newvalue"`
+	// User is the source of the change: the basic auth user, or "guest"
+	User         string `json:"user"`
+	SysCreatedOn string `json:"sys_created_on"`
+}
+
+// AuditTrail holds the recorded changes, oldest first
+var AuditTrail = struct {
+	sync.Mutex
+	records []AuditRecord
+	nextID  int
+}{}
+
+// auditChanges records every field of update whose value differs from item.
+// It is called before the update is applied.
+func auditChanges(r *http.Request, table, id string, item, update map[string]interface{}) {
+	user, _, ok := r.BasicAuth()
+	if !ok || user == "" {
+		user = "guest"
+	}
+	now := time.Now().UTC()
+
+	fields := make([]string, 0, len(update))
+	for field := range update {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	AuditTrail.Lock()
+	defer AuditTrail.Unlock()
+	for _, field := range fields {
+		oldValue, newValue := auditValue(item[field]), auditValue(update[field])
+		if oldValue == newValue {
+			continue
+		}
+		AuditTrail.nextID++
+		AuditTrail.records = append(AuditTrail.records, AuditRecord{
+			SysID:        fmt.Sprintf("audit%d", AuditTrail.nextID),
+			DocumentKey:  id,
+			TableName:    table,
+			FieldName:    field,
+			OldValue:     oldValue,
+			NewValue:     newValue,
+			User:         user,
+			SysCreatedOn: now.Format("2006-01-02 15:04:05"),
+		})
+	}
+}
+
+// auditValue renders a field value the way sys_audit stores it, as a string
+func auditValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// handleAudit lists recorded changes, oldest first. record (the changed
+// record's sys_id), tablename and fieldname narrow the list. DELETE clears it.
+func handleAudit(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	AuditTrail.Lock()
+	defer AuditTrail.Unlock()
+
+	if r.Method == "DELETE" {
+		AuditTrail.records = nil
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	query := r.URL.Query()
+	results := []AuditRecord{}
+	for _, record := range AuditTrail.records {
+		if (query.Get("record") != "" && record.DocumentKey != query.Get("record")) ||
+			(query.Get("tablename") != "" && record.TableName != query.Get("tablename")) ||
+			(query.Get("fieldname") != "" && record.FieldName != query.Get("fieldname")) {
+			continue
+		}
+		results = append(results, record)
+	}
+	json.NewEncoder(w).Encode(ResponseResult{Result: results})
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Chaos faults
+const (
+	FaultNone      = ""
+	FaultRateLimit = "429"   // 429 Too Many Requests with Retry-After
+	FaultError     = "500"   // 500 Internal Server Error
+	FaultReset     = "reset" // the connection is reset without a response
+)
+
+// ChaosRule slows down or fails a share of the Table API requests
+type ChaosRule struct {
+	// Table limits the rule to one ServiceNow table, e.g. "sn_risk_risk"; empty matches all
+	Table string `json:"table,omitempty"`
+	// Percent of matching requests the rule hits; 0 means every request
+	Percent float64 `json:"percent,omitempty"`
+	// Latency is added before the response, e.g. "1500ms"
+	Latency string `json:"latency,omitempty"`
+	Fault   string `json:"fault,omitempty"`
+	// RetryAfter is the Retry-After of a 429, in seconds; 0 means 1
+	RetryAfter int `json:"retry_after,omitempty"`
+
+	latency time.Duration
+}
+
+// ChaosConfig is the set of rules in force. The first rule that matches a
+// request and hits decides what happens to it.
+type ChaosConfig struct {
+	Rules []ChaosRule `json:"rules"`
+}
+
+// Chaos holds the rules and how often they hit
+var Chaos = struct {
+	sync.Mutex
+	config   ChaosConfig
+	injected map[string]int
+}{config: ChaosConfig{Rules: []ChaosRule{}}, injected: map[string]int{}}
+
+// validate checks a rule and parses its latency
+func (rule *ChaosRule) validate() error {
+	if rule.Percent < 0 || rule.Percent > 100 {
+		return fmt.Errorf("percent must be between 0 and 100")
+	}
+	switch rule.Fault {
+	case FaultNone, FaultRateLimit, FaultError, FaultReset:
+	default:
+		return fmt.Errorf("unknown fault %q (use 429, 500 or reset)", rule.Fault)
+	}
+	if rule.Latency != "" {
+		latency, err := time.ParseDuration(rule.Latency)
+		if err != nil || latency < 0 {
+			return fmt.Errorf("invalid latency %q", rule.Latency)
+		}
+		rule.latency = latency
+	}
+	if rule.Fault == FaultNone && rule.latency == 0 {
+		return fmt.Errorf("a rule needs a latency or a fault")
+	}
+	return nil
+}
+
+// hits reports whether the rule applies to a request for table
+func (rule *ChaosRule) hits(table string) bool {
+	if rule.Table != "" && rule.Table != table {
+		return false
+	}
+	return rule.Percent == 0 || rand.Float64()*100 < rule.Percent
+}
+
+// handleChaosConfig shows (GET), replaces (POST) or clears (DELETE) the chaos rules
+func handleChaosConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case "POST":
+		var config ChaosConfig
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		for i := range config.Rules {
+			if err := config.Rules[i].validate(); err != nil {
+				http.Error(w, fmt.Sprintf("Rule %d: %v", i, err), http.StatusBadRequest)
+				return
+			}
+		}
+		if config.Rules == nil {
+			config.Rules = []ChaosRule{}
+		}
+		Chaos.Lock()
+		Chaos.config = config
+		Chaos.injected = map[string]int{}
+		Chaos.Unlock()
+		fmt.Printf("Chaos rules set: %d rule(s)\n", len(config.Rules))
+
+	case "DELETE":
+		Chaos.Lock()
+		Chaos.config = ChaosConfig{Rules: []ChaosRule{}}
+		Chaos.injected = map[string]int{}
+		Chaos.Unlock()
+		fmt.Println("Chaos rules cleared")
+	}
+
+	Chaos.Lock()
+	defer Chaos.Unlock()
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"rules":    Chaos.config.Rules,
+		"injected": Chaos.injected,
+	})
+}
+
+// chaosMiddleware applies the chaos rules to Table API requests
+func chaosMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/now/table/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		table := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/api/now/table/"), "/", 2)[0]
+
+		Chaos.Lock()
+		var rule *ChaosRule
+		for i := range Chaos.config.Rules {
+			if Chaos.config.Rules[i].hits(table) {
+				matched := Chaos.config.Rules[i]
+				rule = &matched
+				break
+			}
+		}
+		if rule != nil {
+			if rule.latency > 0 {
+				Chaos.injected["latency"]++
+			}
+			if rule.Fault != FaultNone {
+				Chaos.injected[rule.Fault]++
+			}
+		}
+		Chaos.Unlock()
+
+		if rule == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if rule.latency > 0 {
+			select {
+			case <-time.After(rule.latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		switch rule.Fault {
+		case FaultRateLimit:
+			retryAfter := rule.RetryAfter
+			if retryAfter <= 0 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			writeChaosError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+		case FaultError:
+			writeChaosError(w, http.StatusInternalServerError, "Injected server error")
+		case FaultReset:
+			resetConnection(w)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// writeChaosError answers with a ServiceNow style error body
+func writeChaosError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  map[string]string{"message": message, "detail": "Injected by the mock's chaos rules"},
+		"status": "failure",
+	})
+}
+
+// resetConnection closes the client connection with a TCP reset
+func resetConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeChaosError(w, http.StatusInternalServerError, "Connection reset not supported")
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		log.Printf("Error hijacking connection: %v", err)
+		return
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+}
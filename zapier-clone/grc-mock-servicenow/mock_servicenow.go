@@ -47,6 +47,17 @@ var MockDatabase = map[string]map[string]interface{}{
 	"regulatory_changes": {},
 }
 
+// ServiceNowTables maps the mock's table keys to their ServiceNow names
+var ServiceNowTables = map[string]string{
+	"risks":              "sn_risk_risk",
+	"compliance_tasks":   "sn_compliance_task",
+	"incidents":          "sn_si_incident",
+	"control_tests":      "sn_policy_control_test",
+	"audit_findings":     "sn_audit_finding",
+	"vendor_risks":       "sn_vendor_risk",
+	"regulatory_changes": "sn_regulatory_change",
+}
+
 func main() {
 	r := mux.NewRouter()
 
@@ -72,6 +83,14 @@ func main() {
 	r.HandleFunc("/api/now/table/sn_regulatory_change", handleRegulatoryChanges).Methods("GET", "POST", "PATCH")
 	r.HandleFunc("/api/now/table/sn_regulatory_change/{id}", handleRegulatoryChangeByID).Methods("GET", "PATCH", "DELETE")
 
+	// Field changes made through PATCH, like ServiceNow's sys_audit
+	r.HandleFunc("/api/now/table/sys_audit", handleAudit).Methods("GET", "DELETE")
+
+	// Files uploaded through the Attachment API
+	r.HandleFunc("/api/now/attachment/file", handleAttachmentUpload).Methods("POST")
+	r.HandleFunc("/api/now/attachment", handleAttachments).Methods("GET")
+	r.HandleFunc("/api/now/attachment/{id}/file", handleAttachmentFile).Methods("GET")
+
 	// Special endpoints for GRC dashboard data
 	r.HandleFunc("/api/now/table/sn_grc_summary", handleGRCSummary).Methods("GET")
 	r.HandleFunc("/api/now/table/sn_risk_by_category", handleRisksByCategory).Methods("GET")
@@ -84,6 +103,10 @@ func main() {
 	// Test webhook trigger endpoint (special mock endpoint to simulate ServiceNow sending webhooks)
 	r.HandleFunc("/trigger_webhook/{table_name}/{action_type}", triggerWebhook).Methods("POST")
 
+	// Latency and failure injection for resilience tests
+	r.HandleFunc("/chaos/config", handleChaosConfig).Methods("GET", "POST", "DELETE")
+	r.Use(chaosMiddleware)
+
 	// Start server
 	port := "3000"
 	fmt.Printf("Starting mock ServiceNow server on port %s...\n", port)
@@ -215,6 +238,7 @@ func handleGenericItemByID(w http.ResponseWriter, r *http.Request, tableName str
 			return
 		}
 
+		auditChanges(r, ServiceNowTables[tableName], id, itemMap, updateData)
 		for k, v := range updateData {
 			itemMap[k] = v
 		}
@@ -281,17 +305,7 @@ func triggerWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Map table names to their ServiceNow equivalents
-	tableNameMap := map[string]string{
-		"risks":              "sn_risk_risk",
-		"compliance_tasks":   "sn_compliance_task",
-		"incidents":          "sn_si_incident",
-		"control_tests":      "sn_policy_control_test",
-		"audit_findings":     "sn_audit_finding",
-		"vendor_risks":       "sn_vendor_risk",
-		"regulatory_changes": "sn_regulatory_change",
-	}
-
-	sn_table := tableNameMap[tableName]
+	sn_table := ServiceNowTables[tableName]
 	if sn_table == "" {
 		http.Error(w, "Invalid table name", http.StatusBadRequest)
 		return
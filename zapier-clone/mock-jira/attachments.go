@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// JiraAttachment is a file attached to a mock issue. Its content is kept in
+// memory and served from /rest/api/2/attachment/content/{id}.
+type JiraAttachment struct {
+	ID       string `json:"id"`
+	Self     string `json:"self"`
+	Filename string `json:"filename"`
+	Author   string `json:"author"`
+	Created  string `json:"created"`
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Content  string `json:"content"`
+}
+
+// attachmentFiles holds the content of every attachment by ID
+var attachmentFiles = struct {
+	sync.Mutex
+	content map[string][]byte
+	nextID  int
+}{content: make(map[string][]byte), nextID: 10000}
+
+// handleAddAttachments stores the "file" parts of a multipart upload on the
+// issue. Like Jira, it refuses uploads without X-Atlassian-Token: no-check.
+func handleAddAttachments(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	key := mux.Vars(r)["key"]
+
+	if r.Header.Get("X-Atlassian-Token") != "no-check" {
+		http.Error(w, "XSRF check failed", http.StatusForbidden)
+		return
+	}
+
+	tickets := MockDatabase["tickets"].(map[string]JiraTicket)
+	ticket, exists := tickets[key]
+	if !exists {
+		http.Error(w, "Issue not found", http.StatusNotFound)
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Invalid multipart body", http.StatusBadRequest)
+		return
+	}
+	files := r.MultipartForm.File["file"]
+	if len(files) == 0 {
+		http.Error(w, "Missing file part", http.StatusBadRequest)
+		return
+	}
+
+	var added []JiraAttachment
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			http.Error(w, "Invalid file part", http.StatusBadRequest)
+			return
+		}
+		content, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			http.Error(w, "Invalid file part", http.StatusBadRequest)
+			return
+		}
+
+		attachmentFiles.Lock()
+		id := fmt.Sprint(attachmentFiles.nextID)
+		attachmentFiles.nextID++
+		attachmentFiles.content[id] = content
+		attachmentFiles.Unlock()
+
+		attachment := JiraAttachment{
+			ID:       id,
+			Self:     fmt.Sprintf("http://%s/rest/api/2/attachment/%s", r.Host, id),
+			Filename: header.Filename,
+			Author:   "mock-user",
+			Created:  time.Now().Format(time.RFC3339),
+			Size:     len(content),
+			MimeType: header.Header.Get("Content-Type"),
+			Content:  fmt.Sprintf("http://%s/rest/api/2/attachment/content/%s", r.Host, id),
+		}
+		ticket.Attachments = append(ticket.Attachments, attachment)
+		added = append(added, attachment)
+		log.Printf("Attached %s (%d bytes) to %s", header.Filename, len(content), key)
+	}
+
+	tickets[key] = ticket
+	MockDatabase["tickets"] = tickets
+	json.NewEncoder(w).Encode(added)
+}
+
+// handleAttachmentContent serves an attachment's file
+func handleAttachmentContent(w http.ResponseWriter, r *http.Request) {
+	attachmentFiles.Lock()
+	content, exists := attachmentFiles.content[mux.Vars(r)["id"]]
+	attachmentFiles.Unlock()
+	if !exists {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+	w.Write(content)
+}
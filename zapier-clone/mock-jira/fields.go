@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// JiraField is a field as listed by /rest/api/2/field
+type JiraField struct {
+	ID     string                 `json:"id"`
+	Name   string                 `json:"name"`
+	Custom bool                   `json:"custom"`
+	Schema map[string]interface{} `json:"schema,omitempty"`
+}
+
+// Fields are the site's fields. Custom fields created through the API are
+// numbered from 10100, as on a site with a few of its own.
+var Fields = struct {
+	sync.Mutex
+	list []JiraField
+	next int
+}{
+	list: []JiraField{
+		{ID: "summary", Name: "Summary", Schema: map[string]interface{}{"type": "string", "system": "summary"}},
+		{ID: "description", Name: "Description", Schema: map[string]interface{}{"type": "string", "system": "description"}},
+		{ID: "status", Name: "Status", Schema: map[string]interface{}{"type": "status", "system": "status"}},
+		{ID: "priority", Name: "Priority", Schema: map[string]interface{}{"type": "priority", "system": "priority"}},
+		{ID: "labels", Name: "Labels", Schema: map[string]interface{}{"type": "array", "items": "string", "system": "labels"}},
+		{ID: "duedate", Name: "Due date", Schema: map[string]interface{}{"type": "date", "system": "duedate"}},
+		{ID: EpicNameField, Name: "Epic Name", Custom: true, Schema: map[string]interface{}{"type": "string", "custom": "com.pyxis.greenhopper.jira:gh-epic-label"}},
+		{ID: EpicLinkField, Name: "Epic Link", Custom: true, Schema: map[string]interface{}{"type": "any", "custom": "com.pyxis.greenhopper.jira:gh-epic-link"}},
+	},
+	next: 10100,
+}
+
+// serviceNowIDField is the custom field named "ServiceNow ID", or the name
+// clients used before the field existed
+func serviceNowIDField() string {
+	Fields.Lock()
+	defer Fields.Unlock()
+	for _, field := range Fields.list {
+		if field.Custom && strings.EqualFold(field.Name, "ServiceNow ID") {
+			return field.ID
+		}
+	}
+	return "customfield_servicenow_id"
+}
+
+// handleFields lists the fields or creates a custom field, like /rest/api/2/field
+func handleFields(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	Fields.Lock()
+	defer Fields.Unlock()
+
+	if r.Method == "GET" {
+		json.NewEncoder(w).Encode(Fields.list)
+		return
+	}
+
+	var request struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Type        string `json:"type"`
+		SearcherKey string `json:"searcherKey"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" || request.Type == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errorMessages": []string{"A field needs a name and a type"}})
+		return
+	}
+
+	field := JiraField{
+		ID:     fmt.Sprintf("customfield_%d", Fields.next),
+		Name:   request.Name,
+		Custom: true,
+		Schema: map[string]interface{}{"type": "string", "custom": request.Type, "customId": Fields.next},
+	}
+	Fields.next++
+	Fields.list = append(Fields.list, field)
+	log.Printf("Created custom field %s (%s)", field.Name, field.ID)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(field)
+}
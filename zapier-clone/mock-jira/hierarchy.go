@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Custom fields the mock gives the meaning they have on Jira Cloud
+const (
+	EpicNameField = "customfield_10011"
+	EpicLinkField = "customfield_10014"
+)
+
+// JiraIssueLinkType is a kind of link, named from both ends
+type JiraIssueLinkType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+// IssueLinkTypes are the link types every Jira site starts with
+var IssueLinkTypes = []JiraIssueLinkType{
+	{ID: "10000", Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+	{ID: "10001", Name: "Cloners", Inward: "is cloned by", Outward: "clones"},
+	{ID: "10002", Name: "Duplicate", Inward: "is duplicated by", Outward: "duplicates"},
+	{ID: "10003", Name: "Relates", Inward: "relates to", Outward: "relates to"},
+}
+
+// JiraIssueLink is a link as it appears on one of its issues: the other issue
+// is the inward or the outward one
+type JiraIssueLink struct {
+	ID           string            `json:"id"`
+	Type         JiraIssueLinkType `json:"type"`
+	InwardIssue  *JiraLinkedIssue  `json:"inwardIssue,omitempty"`
+	OutwardIssue *JiraLinkedIssue  `json:"outwardIssue,omitempty"`
+}
+
+// JiraLinkedIssue names the issue at the other end of a link
+type JiraLinkedIssue struct {
+	Key  string `json:"key"`
+	Self string `json:"self"`
+}
+
+// nextLinkID numbers the issue links
+var nextLinkID = 1
+
+// findLinkType looks a link type up by name, case-insensitively, or by ID
+func findLinkType(name, id string) (JiraIssueLinkType, bool) {
+	for _, linkType := range IssueLinkTypes {
+		if (name != "" && strings.EqualFold(linkType.Name, name)) || (id != "" && linkType.ID == id) {
+			return linkType, true
+		}
+	}
+	return JiraIssueLinkType{}, false
+}
+
+// linkedIssue refers to an issue from a link
+func linkedIssue(key string) *JiraLinkedIssue {
+	return &JiraLinkedIssue{Key: key, Self: fmt.Sprintf("http://localhost:3001/rest/api/2/issue/%s", key)}
+}
+
+// fieldKey reads an issue reference such as {"key": "AUDIT-1"} or a bare key
+func fieldKey(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		key, _ := v["key"].(string)
+		return key
+	}
+	return ""
+}
+
+// hierarchyError checks the issue type, parent and epic link of create or
+// update fields. ticketKey is the issue being updated, empty on create.
+func hierarchyError(fields map[string]interface{}, ticketKey string) string {
+	tickets := MockDatabase["tickets"].(map[string]JiraTicket)
+	issueType := ""
+	if issuetype, ok := fields["issuetype"].(map[string]interface{}); ok {
+		issueType, _ = issuetype["name"].(string)
+	}
+
+	if parentValue, ok := fields["parent"]; ok {
+		parentKey := fieldKey(parentValue)
+		parent, exists := tickets[parentKey]
+		switch {
+		case !exists:
+			return fmt.Sprintf("Parent issue %s does not exist", parentKey)
+		case parentKey == ticketKey:
+			return "An issue cannot be its own parent"
+		case isSubtaskType(parent.IssueType):
+			return "A sub-task cannot be the parent of another issue"
+		}
+	} else if isSubtaskType(issueType) {
+		return "A sub-task needs a parent"
+	}
+
+	if epicValue, ok := fields[EpicLinkField]; ok && epicValue != nil {
+		epicKey := fieldKey(epicValue)
+		epic, exists := tickets[epicKey]
+		if !exists || !strings.EqualFold(epic.IssueType, "Epic") {
+			return fmt.Sprintf("Epic %s does not exist", epicKey)
+		}
+	}
+	return ""
+}
+
+// isSubtaskType reports whether an issue type is a sub-task type
+func isSubtaskType(issueType string) bool {
+	return strings.EqualFold(issueType, "Sub-task") || strings.EqualFold(issueType, "Subtask")
+}
+
+// applyHierarchy sets a ticket's issue type, parent, epic name and epic link
+// from create or update fields, keeping the parent's sub-task list in step.
+// hierarchyError must have accepted the fields.
+func applyHierarchy(ticket *JiraTicket, fields map[string]interface{}) {
+	tickets := MockDatabase["tickets"].(map[string]JiraTicket)
+
+	if issuetype, ok := fields["issuetype"].(map[string]interface{}); ok {
+		if name, ok := issuetype["name"].(string); ok && name != "" {
+			ticket.IssueType = name
+		}
+	}
+	if name, ok := fields[EpicNameField].(string); ok {
+		ticket.EpicName = name
+	}
+	if epicValue, ok := fields[EpicLinkField]; ok {
+		ticket.EpicLink = fieldKey(epicValue)
+	}
+
+	if parentValue, ok := fields["parent"]; ok {
+		parentKey := fieldKey(parentValue)
+		if ticket.Parent != "" && ticket.Parent != parentKey {
+			if old, exists := tickets[ticket.Parent]; exists {
+				old.Subtasks = removeKey(old.Subtasks, ticket.Key)
+				tickets[old.Key] = old
+			}
+		}
+		ticket.Parent = parentKey
+		if parent, exists := tickets[parentKey]; exists && !containsFold(parent.Subtasks, ticket.Key) {
+			parent.Subtasks = append(parent.Subtasks, ticket.Key)
+			tickets[parentKey] = parent
+		}
+	}
+}
+
+// detachIssue removes a deleted issue from its parent, its sub-tasks, the
+// issues of its epic and every link
+func detachIssue(key string) {
+	tickets := MockDatabase["tickets"].(map[string]JiraTicket)
+	for otherKey, other := range tickets {
+		changed := false
+		if containsFold(other.Subtasks, key) {
+			other.Subtasks = removeKey(other.Subtasks, key)
+			changed = true
+		}
+		if other.Parent == key {
+			other.Parent = ""
+			changed = true
+		}
+		if other.EpicLink == key {
+			other.EpicLink = ""
+			changed = true
+		}
+		links := other.IssueLinks[:0]
+		for _, link := range other.IssueLinks {
+			if (link.InwardIssue != nil && link.InwardIssue.Key == key) || (link.OutwardIssue != nil && link.OutwardIssue.Key == key) {
+				changed = true
+				continue
+			}
+			links = append(links, link)
+		}
+		other.IssueLinks = links
+		if changed {
+			tickets[otherKey] = other
+		}
+	}
+}
+
+func removeKey(keys []string, key string) []string {
+	kept := keys[:0]
+	for _, k := range keys {
+		if k != key {
+			kept = append(kept, k)
+		}
+	}
+	return kept
+}
+
+// handleIssueLinkTypes lists the link types like GET /rest/api/2/issueLinkType
+func handleIssueLinkTypes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"issueLinkTypes": IssueLinkTypes})
+}
+
+// handleCreateIssueLink links two issues like POST /rest/api/2/issueLink
+func handleCreateIssueLink(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Type struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		} `json:"type"`
+		InwardIssue  JiraLinkedIssue `json:"inwardIssue"`
+		OutwardIssue JiraLinkedIssue `json:"outwardIssue"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	linkType, ok := findLinkType(request.Type.Name, request.Type.ID)
+	if !ok {
+		http.Error(w, fmt.Sprintf("No issue link type named %q", request.Type.Name), http.StatusNotFound)
+		return
+	}
+
+	tickets := MockDatabase["tickets"].(map[string]JiraTicket)
+	inward, inwardExists := tickets[request.InwardIssue.Key]
+	outward, outwardExists := tickets[request.OutwardIssue.Key]
+	if !inwardExists || !outwardExists {
+		http.Error(w, "Issue not found", http.StatusNotFound)
+		return
+	}
+	if inward.Key == outward.Key {
+		http.Error(w, "An issue cannot be linked to itself", http.StatusBadRequest)
+		return
+	}
+
+	id := fmt.Sprintf("%d", nextLinkID)
+	nextLinkID++
+
+	// The inward issue sees the outward one, and the other way round
+	inward.IssueLinks = append(inward.IssueLinks, JiraIssueLink{ID: id, Type: linkType, OutwardIssue: linkedIssue(outward.Key)})
+	outward.IssueLinks = append(outward.IssueLinks, JiraIssueLink{ID: id, Type: linkType, InwardIssue: linkedIssue(inward.Key)})
+	tickets[inward.Key] = inward
+	tickets[outward.Key] = outward
+
+	w.Header().Set("Location", fmt.Sprintf("http://localhost:3001/rest/api/2/issueLink/%s", id))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// findIssueLink returns a link with both of its issues
+func findIssueLink(id string) (link map[string]interface{}, found bool) {
+	for _, ticket := range MockDatabase["tickets"].(map[string]JiraTicket) {
+		for _, l := range ticket.IssueLinks {
+			if l.ID != id || l.OutwardIssue == nil {
+				continue
+			}
+			// This ticket is the inward issue
+			return map[string]interface{}{
+				"id":           l.ID,
+				"type":         l.Type,
+				"inwardIssue":  linkedIssue(ticket.Key),
+				"outwardIssue": l.OutwardIssue,
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// handleIssueLink returns or deletes one link like /rest/api/2/issueLink/{id}
+func handleIssueLink(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := mux.Vars(r)["id"]
+
+	link, found := findIssueLink(id)
+	if !found {
+		http.Error(w, "Issue link not found", http.StatusNotFound)
+		return
+	}
+
+	if r.Method == "GET" {
+		json.NewEncoder(w).Encode(link)
+		return
+	}
+
+	tickets := MockDatabase["tickets"].(map[string]JiraTicket)
+	for _, end := range []*JiraLinkedIssue{link["inwardIssue"].(*JiraLinkedIssue), link["outwardIssue"].(*JiraLinkedIssue)} {
+		ticket := tickets[end.Key]
+		links := ticket.IssueLinks[:0]
+		for _, l := range ticket.IssueLinks {
+			if l.ID != id {
+				links = append(links, l)
+			}
+		}
+		ticket.IssueLinks = links
+		tickets[end.Key] = ticket
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
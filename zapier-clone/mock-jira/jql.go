@@ -0,0 +1,668 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// The mock understands a subset of JQL:
+//
+//	clause  = field op value | field [NOT] IN (value, ...) | field IS [NOT] EMPTY
+//	op      = "=" | "!=" | ">" | ">=" | "<" | "<=" | "~" | "!~"
+//	query   = expr [ORDER BY field [ASC|DESC], ...]
+//	expr    = clauses joined with AND, OR and NOT, grouped with parentheses
+//
+// Dates (created, updated, duedate) accept "2024-01-31", "2024-01-31 14:00",
+// relative offsets such as -7d, -4h or -2w, and now(), startOfDay() and
+// endOfDay(). text searches summary, description and comments.
+
+// jqlNode is a parsed JQL expression
+type jqlNode interface {
+	match(ticket JiraTicket) bool
+}
+
+type jqlAnd struct{ left, right jqlNode }
+type jqlOr struct{ left, right jqlNode }
+type jqlNot struct{ node jqlNode }
+
+func (n jqlAnd) match(t JiraTicket) bool { return n.left.match(t) && n.right.match(t) }
+func (n jqlOr) match(t JiraTicket) bool  { return n.left.match(t) || n.right.match(t) }
+func (n jqlNot) match(t JiraTicket) bool { return !n.node.match(t) }
+
+// jqlAll matches every issue; it stands in for an empty query
+type jqlAll struct{}
+
+func (jqlAll) match(JiraTicket) bool { return true }
+
+// jqlClause compares one field against one or more values
+type jqlClause struct {
+	field    string
+	operator string // =, !=, >, >=, <, <=, ~, !~, in, not in, is, is not
+	values   []string
+}
+
+// jqlOrder is one ORDER BY key
+type jqlOrder struct {
+	field string
+	desc  bool
+}
+
+// jqlQuery is a parsed search
+type jqlQuery struct {
+	where jqlNode
+	order []jqlOrder
+}
+
+// parseJQL parses a JQL string
+func parseJQL(input string) (*jqlQuery, error) {
+	tokens, err := tokenizeJQL(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &jqlParser{tokens: tokens}
+
+	query := &jqlQuery{where: jqlAll{}}
+	if !p.done() && !p.keyword("ORDER") {
+		if query.where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.keyword("ORDER") {
+		p.next()
+		if !p.keyword("BY") {
+			return nil, fmt.Errorf("expected BY after ORDER")
+		}
+		p.next()
+		for {
+			field := p.next()
+			if field.kind != tokenWord {
+				return nil, fmt.Errorf("expected a field to order by")
+			}
+			order := jqlOrder{field: strings.ToLower(field.text)}
+			if p.keyword("ASC") {
+				p.next()
+			} else if p.keyword("DESC") {
+				p.next()
+				order.desc = true
+			}
+			query.order = append(query.order, order)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	return query, nil
+}
+
+// Token kinds
+const (
+	tokenWord = iota
+	tokenString
+	tokenOperator
+	tokenOpen
+	tokenClose
+	tokenComma
+	tokenEnd
+)
+
+type jqlToken struct {
+	kind int
+	text string
+}
+
+// tokenizeJQL splits a query into words, quoted strings, operators and punctuation
+func tokenizeJQL(input string) ([]jqlToken, error) {
+	var tokens []jqlToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, jqlToken{tokenOpen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, jqlToken{tokenClose, ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, jqlToken{tokenComma, ","})
+			i++
+		case c == '"' || c == '\'':
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != c; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				text.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, jqlToken{tokenString, text.String()})
+			i = j + 1
+		case strings.ContainsRune("=!<>~", c):
+			op := string(c)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (c == '!' && runes[i+1] == '~')) {
+				op += string(runes[i+1])
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected !")
+			}
+			tokens = append(tokens, jqlToken{tokenOperator, op})
+			i += len(op)
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune("(),=!<>~\"'", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, jqlToken{tokenWord, string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type jqlParser struct {
+	tokens []jqlToken
+	pos    int
+}
+
+func (p *jqlParser) peek() jqlToken {
+	if p.pos >= len(p.tokens) {
+		return jqlToken{kind: tokenEnd}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *jqlParser) next() jqlToken {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *jqlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// keyword reports whether the next token is the given keyword
+func (p *jqlParser) keyword(word string) bool {
+	token := p.peek()
+	return token.kind == tokenWord && strings.EqualFold(token.text, word)
+}
+
+func (p *jqlParser) parseOr() (jqlNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = jqlOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *jqlParser) parseAnd() (jqlNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = jqlAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *jqlParser) parseUnary() (jqlNode, error) {
+	if p.keyword("NOT") {
+		p.next()
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return jqlNot{node}, nil
+	}
+	if p.peek().kind == tokenOpen {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenClose {
+			return nil, fmt.Errorf("expected )")
+		}
+		return node, nil
+	}
+	return p.parseClause()
+}
+
+func (p *jqlParser) parseClause() (jqlNode, error) {
+	field := p.next()
+	if field.kind != tokenWord && field.kind != tokenString {
+		return nil, fmt.Errorf("expected a field, got %q", field.text)
+	}
+	clause := jqlClause{field: strings.ToLower(field.text)}
+
+	switch {
+	case p.peek().kind == tokenOperator:
+		clause.operator = p.next().text
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		clause.values = []string{value}
+	case p.keyword("IN"), p.keyword("NOT"):
+		clause.operator = "in"
+		if p.keyword("NOT") {
+			p.next()
+			if !p.keyword("IN") {
+				return nil, fmt.Errorf("expected IN after NOT")
+			}
+			clause.operator = "not in"
+		}
+		p.next()
+		if p.next().kind != tokenOpen {
+			return nil, fmt.Errorf("expected ( after IN")
+		}
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			clause.values = append(clause.values, value)
+			token := p.next()
+			if token.kind == tokenClose {
+				break
+			}
+			if token.kind != tokenComma {
+				return nil, fmt.Errorf("expected , or ) in IN list")
+			}
+		}
+	case p.keyword("IS"):
+		p.next()
+		clause.operator = "is"
+		if p.keyword("NOT") {
+			p.next()
+			clause.operator = "is not"
+		}
+		if !p.keyword("EMPTY") && !p.keyword("NULL") {
+			return nil, fmt.Errorf("expected EMPTY after IS")
+		}
+		p.next()
+	default:
+		return nil, fmt.Errorf("expected an operator after %s", field.text)
+	}
+	return clause, nil
+}
+
+// parseValue reads a literal, or a function call such as now()
+func (p *jqlParser) parseValue() (string, error) {
+	token := p.next()
+	switch token.kind {
+	case tokenString:
+		return token.text, nil
+	case tokenWord:
+		if p.peek().kind == tokenOpen {
+			p.next()
+			if p.next().kind != tokenClose {
+				return "", fmt.Errorf("functions take no arguments: %s()", token.text)
+			}
+			return token.text + "()", nil
+		}
+		return token.text, nil
+	}
+	return "", fmt.Errorf("expected a value, got %q", token.text)
+}
+
+// dateFields are compared as times
+var dateFields = map[string]bool{"created": true, "updated": true, "duedate": true}
+
+// relativeDate matches offsets such as -7d or 4h
+var relativeDate = regexp.MustCompile(`^([-+]?\d+)([wdhm])$`)
+
+func (c jqlClause) match(t JiraTicket) bool {
+	actual := ticketValues(t, c.field)
+
+	switch c.operator {
+	case "is":
+		return len(actual) == 0
+	case "is not":
+		return len(actual) > 0
+	case "in", "not in":
+		found := false
+		for _, value := range c.values {
+			if containsFold(actual, value) {
+				found = true
+				break
+			}
+		}
+		return found == (c.operator == "in")
+	}
+
+	value := c.values[0]
+	if strings.EqualFold(value, "EMPTY") || strings.EqualFold(value, "NULL") {
+		switch c.operator {
+		case "=":
+			return len(actual) == 0
+		case "!=":
+			return len(actual) > 0
+		}
+	}
+
+	switch c.operator {
+	case "=":
+		return containsFold(actual, value)
+	case "!=":
+		return !containsFold(actual, value)
+	case "~", "!~":
+		found := false
+		for _, a := range actual {
+			if strings.Contains(strings.ToLower(a), strings.ToLower(strings.Trim(value, "*"))) {
+				found = true
+				break
+			}
+		}
+		return found == (c.operator == "~")
+	}
+
+	// Ordering comparisons
+	if len(actual) == 0 {
+		return false
+	}
+	var cmp int
+	if dateFields[c.field] {
+		have, err1 := parseTicketTime(actual[0])
+		want, err2 := parseJQLTime(value, time.Now())
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		cmp = have.Compare(want)
+	} else {
+		cmp = compareValues(actual[0], value)
+	}
+	switch c.operator {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// ticketValues returns a field's values; an empty slice means the field is empty
+func ticketValues(t JiraTicket, field string) []string {
+	single := func(value string) []string {
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	}
+
+	switch field {
+	case "key", "issuekey", "id":
+		if field == "id" {
+			return single(t.ID)
+		}
+		return single(t.Key)
+	case "project":
+		return single(strings.SplitN(t.Key, "-", 2)[0])
+	case "summary":
+		return single(t.Summary)
+	case "description":
+		return single(t.Description)
+	case "status":
+		return single(t.Status)
+	case "resolution":
+		return single(t.Resolution)
+	case "priority":
+		return single(t.Priority)
+	case "assignee":
+		return single(t.Assignee)
+	case "created":
+		return single(t.Created)
+	case "updated":
+		return single(t.Updated)
+	case "duedate", "due":
+		return single(t.DueDate)
+	case "issuetype", "type":
+		return single(t.IssueType)
+	case "parent":
+		return single(t.Parent)
+	case "epic link", "cf[10014]", EpicLinkField:
+		return single(t.EpicLink)
+	case "labels":
+		return t.Labels
+	case "component", "components":
+		return t.Components
+	case "comment":
+		var bodies []string
+		for _, comment := range t.Comments {
+			bodies = append(bodies, comment.Body)
+		}
+		return bodies
+	case "text":
+		values := append(single(t.Summary), single(t.Description)...)
+		return append(values, ticketValues(t, "comment")...)
+	}
+
+	// Anything else is looked up in the issue's custom fields
+	for name, value := range t.Fields {
+		if strings.EqualFold(name, field) && value != nil {
+			return single(fmt.Sprint(value))
+		}
+	}
+	return nil
+}
+
+func containsFold(values []string, want string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareValues compares numbers and issue keys numerically, anything else as text
+func compareValues(a, b string) int {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if pa, na, ok := splitKey(a); ok {
+		if pb, nb, ok := splitKey(b); ok && strings.EqualFold(pa, pb) {
+			return na - nb
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// splitKey splits an issue key such as AUDIT-12 into project and number
+func splitKey(key string) (string, int, bool) {
+	project, number, found := strings.Cut(key, "-")
+	n, err := strconv.Atoi(number)
+	return project, n, found && err == nil
+}
+
+// parseTicketTime reads the timestamps the mock stores
+func parseTicketTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+// parseJQLTime reads a JQL date: an absolute date, a relative offset or a date function
+func parseJQLTime(value string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(value) {
+	case "now()":
+		return now, nil
+	case "startofday()":
+		year, month, day := now.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location()), nil
+	case "endofday()":
+		year, month, day := now.Date()
+		return time.Date(year, month, day, 23, 59, 59, 0, now.Location()), nil
+	}
+
+	if m := relativeDate.FindStringSubmatch(value); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{"w": 7 * 24 * time.Hour, "d": 24 * time.Hour, "h": time.Hour, "m": time.Minute}[m[2]]
+		return now.Add(time.Duration(n) * unit), nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04", "2006/01/02 15:04", "2006-01-02", "2006/01/02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// sortTickets orders tickets by the ORDER BY keys, then by key
+func sortTickets(tickets []JiraTicket, order []jqlOrder) {
+	sort.SliceStable(tickets, func(i, j int) bool {
+		for _, key := range order {
+			a, b := first(ticketValues(tickets[i], key.field)), first(ticketValues(tickets[j], key.field))
+			var cmp int
+			if dateFields[key.field] {
+				ta, _ := parseTicketTime(a)
+				tb, _ := parseTicketTime(b)
+				cmp = ta.Compare(tb)
+			} else {
+				cmp = compareValues(a, b)
+			}
+			if cmp != 0 {
+				return (cmp < 0) != key.desc
+			}
+		}
+		return compareValues(tickets[i].Key, tickets[j].Key) < 0
+	})
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// handleSearch answers GET and POST /rest/api/2/search with the issues
+// matching jql, paged by startAt and maxResults
+func handleSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	request := struct {
+		JQL        string `json:"jql"`
+		StartAt    int    `json:"startAt"`
+		MaxResults *int   `json:"maxResults"`
+	}{}
+	if r.Method == "POST" {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeSearchError(w, "Invalid request body")
+			return
+		}
+	} else {
+		query := r.URL.Query()
+		request.JQL = query.Get("jql")
+		if value := query.Get("startAt"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				writeSearchError(w, "Invalid startAt")
+				return
+			}
+			request.StartAt = parsed
+		}
+		if value := query.Get("maxResults"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				writeSearchError(w, "Invalid maxResults")
+				return
+			}
+			request.MaxResults = &parsed
+		}
+	}
+
+	maxResults := 50
+	if request.MaxResults != nil {
+		maxResults = *request.MaxResults
+	}
+	if maxResults < 0 || maxResults > 1000 {
+		maxResults = 1000
+	}
+	if request.StartAt < 0 {
+		request.StartAt = 0
+	}
+
+	query, err := parseJQL(request.JQL)
+	if err != nil {
+		writeSearchError(w, fmt.Sprintf("Error in the JQL Query: %v", err))
+		return
+	}
+
+	matched := []JiraTicket{}
+	for _, ticket := range MockDatabase["tickets"].(map[string]JiraTicket) {
+		if query.where.match(ticket) {
+			matched = append(matched, ticket)
+		}
+	}
+	sortTickets(matched, query.order)
+
+	page := []JiraTicket{}
+	if request.StartAt < len(matched) {
+		end := request.StartAt + maxResults
+		if end > len(matched) {
+			end = len(matched)
+		}
+		page = matched[request.StartAt:end]
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"startAt":    request.StartAt,
+		"maxResults": maxResults,
+		"total":      len(matched),
+		"issues":     page,
+	})
+}
+
+// writeSearchError answers 400 the way Jira reports a bad query
+func writeSearchError(w http.ResponseWriter, message string) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errorMessages": []string{message},
+		"errors":        map[string]string{},
+	})
+}
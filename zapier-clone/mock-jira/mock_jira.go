@@ -29,6 +29,13 @@ type JiraTicket struct {
 	Components  []string               `json:"components,omitempty"`
 	Fields      map[string]interface{} `json:"fields,omitempty"`
 	Comments    []JiraComment          `json:"comments,omitempty"`
+	IssueType   string                 `json:"issuetype,omitempty"`
+	Parent      string                 `json:"parent,omitempty"`
+	Subtasks    []string               `json:"subtasks,omitempty"`
+	EpicName    string                 `json:"epicName,omitempty"`
+	EpicLink    string                 `json:"epicLink,omitempty"`
+	IssueLinks  []JiraIssueLink        `json:"issuelinks,omitempty"`
+	Attachments []JiraAttachment       `json:"attachment,omitempty"`
 }
 
 // JiraComment represents a comment on a Jira issue
@@ -58,12 +65,6 @@ type JiraWebhookPayload struct {
 // MockDatabase holds our mock Jira data
 var MockDatabase = map[string]interface{}{
 	"tickets": map[string]JiraTicket{},
-	"projects": map[string]interface{}{
-		"AUDIT": map[string]interface{}{
-			"key":  "AUDIT",
-			"name": "Audit Management",
-		},
-	},
 }
 
 // ServiceNowJiraMapping maps ServiceNow IDs to Jira ticket keys
@@ -74,10 +75,22 @@ func main() {
 
 	// Jira REST API endpoints
 	r.HandleFunc("/rest/api/2/issue", handleIssues).Methods("GET", "POST")
+	r.HandleFunc("/rest/api/2/issue/bulk", handleBulkIssues).Methods("POST")
 	r.HandleFunc("/rest/api/2/issue/{key}", handleIssueByKey).Methods("GET", "PUT", "DELETE")
 	r.HandleFunc("/rest/api/2/issue/{key}/comment", handleComments).Methods("GET", "POST")
 	r.HandleFunc("/rest/api/2/issue/{key}/transitions", handleTransitions).Methods("GET", "POST")
-	r.HandleFunc("/rest/api/2/project", handleProjects).Methods("GET")
+	r.HandleFunc("/rest/api/2/issue/{key}/attachments", handleAddAttachments).Methods("POST")
+	r.HandleFunc("/rest/api/2/attachment/content/{id}", handleAttachmentContent).Methods("GET")
+	r.HandleFunc("/rest/api/2/project", handleProjects).Methods("GET", "POST")
+	r.HandleFunc("/rest/api/2/project/{key}", handleProject).Methods("GET")
+	r.HandleFunc("/rest/api/2/project/{key}/components", handleProjectComponents).Methods("GET")
+	r.HandleFunc("/rest/api/2/component", handleCreateComponent).Methods("POST")
+	r.HandleFunc("/rest/api/2/myself", handleMyself).Methods("GET")
+	r.HandleFunc("/rest/api/2/field", handleFields).Methods("GET", "POST")
+	r.HandleFunc("/rest/api/2/search", handleSearch).Methods("GET", "POST")
+	r.HandleFunc("/rest/api/2/issueLink", handleCreateIssueLink).Methods("POST")
+	r.HandleFunc("/rest/api/2/issueLink/{id}", handleIssueLink).Methods("GET", "DELETE")
+	r.HandleFunc("/rest/api/2/issueLinkType", handleIssueLinkTypes).Methods("GET")
 
 	// Webhook receiver (this would be an endpoint in your application)
 	r.HandleFunc("/api/webhooks/jira", handleReceiveWebhook).Methods("POST")
@@ -85,6 +98,11 @@ func main() {
 	// Webhook trigger (special endpoint to simulate sending webhooks to your app)
 	r.HandleFunc("/trigger_webhook/{event_type}", triggerWebhook).Methods("POST")
 
+	// Webhook log: every webhook sent or received, with replay
+	r.HandleFunc("/api/webhook_logs", handleWebhookLogs).Methods("GET", "DELETE")
+	r.HandleFunc("/api/webhook_logs/{id:[0-9]+}", handleWebhookLog).Methods("GET")
+	r.HandleFunc("/api/webhook_logs/{id:[0-9]+}/replay", handleReplayWebhook).Methods("POST")
+
 	// Health check and UI
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -127,54 +145,146 @@ func handleIssues(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Invalid fields format", http.StatusBadRequest)
 			return
 		}
+		if message := hierarchyError(fields, ""); message != "" {
+			http.Error(w, message, http.StatusBadRequest)
+			return
+		}
 
-		// Generate ID and key
-		id := fmt.Sprintf("10%d", len(MockDatabase["tickets"].(map[string]JiraTicket))+1)
-		key := fmt.Sprintf("AUDIT-%d", len(MockDatabase["tickets"].(map[string]JiraTicket))+1)
+		ticket := createTicket(fields)
 
-		// Extract data from fields
-		summary := ""
-		if sum, ok := fields["summary"].(string); ok {
-			summary = sum
-		}
+		// Return success with key
+		json.NewEncoder(w).Encode(map[string]string{
+			"id":   ticket.ID,
+			"key":  ticket.Key,
+			"self": ticket.Self,
+		})
+	}
+}
 
-		description := ""
-		if desc, ok := fields["description"].(string); ok {
-			description = desc
-		}
+// handleBulkIssues creates several issues like POST /rest/api/2/issue/bulk.
+// Issues without a summary fail, so partial failures can be tried out.
+func handleBulkIssues(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var requestData struct {
+		IssueUpdates []struct {
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"issueUpdates"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
 
-		// Create the ticket
-		ticket := JiraTicket{
-			ID:          id,
-			Key:         key,
-			Self:        fmt.Sprintf("http://localhost:3001/rest/api/2/issue/%s", key),
-			Summary:     summary,
-			Description: description,
-			Status:      "To Do",
-			Created:     time.Now().Format(time.RFC3339),
-			Updated:     time.Now().Format(time.RFC3339),
-			Comments:    []JiraComment{},
+	issues := []map[string]string{}
+	errors := []map[string]interface{}{}
+	for i, update := range requestData.IssueUpdates {
+		elementErrors := map[string]string{}
+		if summary, _ := update.Fields["summary"].(string); summary == "" {
+			elementErrors["summary"] = "You must specify a summary of the issue."
+		}
+		if message := hierarchyError(update.Fields, ""); message != "" {
+			elementErrors["parent"] = message
+		}
+		if len(elementErrors) > 0 {
+			errors = append(errors, map[string]interface{}{
+				"status":              400,
+				"failedElementNumber": i,
+				"elementErrors": map[string]interface{}{
+					"errorMessages": []string{},
+					"errors":        elementErrors,
+				},
+			})
+			continue
 		}
+		ticket := createTicket(update.Fields)
+		issues = append(issues, map[string]string{"id": ticket.ID, "key": ticket.Key, "self": ticket.Self})
+	}
+
+	// Jira answers 400 only when every issue failed
+	if len(issues) == 0 && len(errors) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"issues": issues, "errors": errors})
+}
+
+// createTicket stores a new ticket built from create-issue fields
+func createTicket(fields map[string]interface{}) JiraTicket {
+	// Generate ID and key
+	id := fmt.Sprintf("10%d", len(MockDatabase["tickets"].(map[string]JiraTicket))+1)
+	key := fmt.Sprintf("%s-%d", issueProject(fields), len(MockDatabase["tickets"].(map[string]JiraTicket))+1)
+
+	// Extract data from fields
+	summary := ""
+	if sum, ok := fields["summary"].(string); ok {
+		summary = sum
+	}
+
+	description := ""
+	if desc, ok := fields["description"].(string); ok {
+		description = desc
+	}
+
+	// Create the ticket
+	ticket := JiraTicket{
+		ID:          id,
+		Key:         key,
+		Self:        fmt.Sprintf("http://localhost:3001/rest/api/2/issue/%s", key),
+		Summary:     summary,
+		Description: description,
+		Status:      "To Do",
+		Created:     time.Now().Format(time.RFC3339),
+		Updated:     time.Now().Format(time.RFC3339),
+		Comments:    []JiraComment{},
+		IssueType:   "Task",
+	}
+	applyHierarchy(&ticket, fields)
 
-		// Check for custom fields for ServiceNow mapping
-		if customFields, ok := fields["customfield_servicenow_id"]; ok {
-			if snID, ok := customFields.(string); ok && snID != "" {
-				ServiceNowJiraMapping[snID] = key
+	if priority, ok := fields["priority"].(map[string]interface{}); ok {
+		ticket.Priority, _ = priority["name"].(string)
+	}
+	if labels, ok := fields["labels"].([]interface{}); ok {
+		for _, label := range labels {
+			if name, _ := label.(string); name != "" {
+				ticket.Labels = append(ticket.Labels, name)
+			}
+		}
+	}
+	if components, ok := fields["components"].([]interface{}); ok {
+		for _, component := range components {
+			if c, ok := component.(map[string]interface{}); ok {
+				if name, _ := c["name"].(string); name != "" {
+					ticket.Components = append(ticket.Components, name)
+				}
 			}
 		}
+	}
 
-		// Save to database
-		tickets := MockDatabase["tickets"].(map[string]JiraTicket)
-		tickets[key] = ticket
-		MockDatabase["tickets"] = tickets
+	// Keep custom fields so reads and webhooks return them
+	for field, value := range fields {
+		if strings.HasPrefix(field, "customfield_") {
+			if ticket.Fields == nil {
+				ticket.Fields = map[string]interface{}{}
+			}
+			ticket.Fields[field] = value
+		}
+	}
 
-		// Return success with key
-		json.NewEncoder(w).Encode(map[string]string{
-			"id":   id,
-			"key":  key,
-			"self": ticket.Self,
-		})
+	// Check for custom fields for ServiceNow mapping
+	if customFields, ok := fields[serviceNowIDField()]; ok {
+		if snID, ok := customFields.(string); ok && snID != "" {
+			ServiceNowJiraMapping[snID] = key
+		}
 	}
+
+	// Save to database
+	tickets := MockDatabase["tickets"].(map[string]JiraTicket)
+	tickets[key] = ticket
+	MockDatabase["tickets"] = tickets
+
+	return ticket
 }
 
 func handleIssueByKey(w http.ResponseWriter, r *http.Request) {
@@ -202,6 +312,11 @@ func handleIssueByKey(w http.ResponseWriter, r *http.Request) {
 
 		fields, ok := updateData["fields"].(map[string]interface{})
 		if ok {
+			if message := hierarchyError(fields, key); message != "" {
+				http.Error(w, message, http.StatusBadRequest)
+				return
+			}
+			applyHierarchy(&ticket, fields)
 			if summary, ok := fields["summary"].(string); ok {
 				ticket.Summary = summary
 			}
@@ -218,6 +333,14 @@ func handleIssueByKey(w http.ResponseWriter, r *http.Request) {
 					ticket.Assignee = name
 				}
 			}
+			for field, value := range fields {
+				if strings.HasPrefix(field, "customfield_") {
+					if ticket.Fields == nil {
+						ticket.Fields = map[string]interface{}{}
+					}
+					ticket.Fields[field] = value
+				}
+			}
 		}
 
 		ticket.Updated = time.Now().Format(time.RFC3339)
@@ -228,6 +351,7 @@ func handleIssueByKey(w http.ResponseWriter, r *http.Request) {
 
 	case "DELETE":
 		delete(tickets, key)
+		detachIssue(key)
 		MockDatabase["tickets"] = tickets
 		w.WriteHeader(http.StatusNoContent)
 	}
@@ -387,18 +511,6 @@ func handleTransitions(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handleProjects(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	projects := MockDatabase["projects"].(map[string]interface{})
-	var projectList []interface{}
-	for _, project := range projects {
-		projectList = append(projectList, project)
-	}
-
-	json.NewEncoder(w).Encode(projectList)
-}
-
 func handleReceiveWebhook(w http.ResponseWriter, r *http.Request) {
 	// This simulates your application's webhook endpoint for receiving Jira events
 	var payload map[string]interface{}
@@ -409,6 +521,8 @@ func handleReceiveWebhook(w http.ResponseWriter, r *http.Request) {
 
 	// Log the received webhook
 	fmt.Printf("Received webhook from Jira: %v\n", payload)
+	body, _ := json.Marshal(payload)
+	logWebhook(WebhookLog{Source: SourceReceived, Payload: body})
 
 	// Return success
 	w.WriteHeader(http.StatusOK)
@@ -449,27 +563,22 @@ func triggerWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the webhook URL from the query parameter or use default
-	webhookURL := r.URL.Query().Get("webhook_url")
-	if webhookURL == "" {
-		webhookURL = "http://localhost:8080/api/webhooks/jira"
-	}
+	// Get the webhook URL from the query parameter or use the configured target
+	webhookURL := webhookTarget(r.URL.Query().Get("webhook_url"))
 
 	// Send the webhook
-	jsonPayload, _ := json.Marshal(webhookPayload)
-	resp, err := http.Post(webhookURL, "application/json", strings.NewReader(string(jsonPayload)))
+	entry, err := sendWebhook(SourceTrigger, webhookURL, webhookPayload)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error sending webhook: %v", err), http.StatusInternalServerError)
 		return
 	}
-	defer resp.Body.Close()
 
 	// Return status
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
+	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":     "success",
 		"message":    fmt.Sprintf("Jira webhook sent to %s", webhookURL),
-		"webhook_id": fmt.Sprintf("mock-jira-webhook-%d", time.Now().UnixNano()),
+		"webhook_id": entry.ID,
 		"event_type": eventType,
 		"issue_key":  issueKey,
 	})
@@ -495,17 +604,11 @@ func triggerStatusChangeWebhook(issueKey string, newStatus string) {
 		},
 	}
 
-	// Get default webhook URL
-	webhookURL := "http://localhost:8080/api/webhooks/jira"
-
 	// Send the webhook
-	jsonPayload, _ := json.Marshal(payload)
-	resp, err := http.Post(webhookURL, "application/json", strings.NewReader(string(jsonPayload)))
-	if err != nil {
+	if _, err := sendWebhook(SourceTransition, webhookTarget(""), payload); err != nil {
 		fmt.Printf("Error sending status change webhook: %v\n", err)
 		return
 	}
-	defer resp.Body.Close()
 
 	fmt.Printf("Status change webhook sent for issue %s (new status: %s)\n", issueKey, newStatus)
 }
@@ -546,9 +649,19 @@ func buildIssueWebhookPayload(issueKey string, action string, data map[string]in
 		},
 	}
 
+	// Carry the issue's custom fields and resolution, as Jira does
+	if exists {
+		for field, value := range ticket.Fields {
+			issueFields[field] = value
+		}
+		if ticket.Resolution != "" {
+			issueFields["resolution"] = map[string]interface{}{"name": ticket.Resolution}
+		}
+	}
+
 	// Add ServiceNow ID if provided
 	if snID, ok := data["servicenow_id"].(string); ok && snID != "" {
-		issueFields["customfield_servicenow_id"] = snID
+		issueFields[serviceNowIDField()] = snID
 	}
 
 	return map[string]interface{}{
@@ -683,12 +796,71 @@ func handleUI(w http.ResponseWriter, r *http.Request) {
                         <li><strong>Comments API:</strong> http://localhost:3001/rest/api/2/issue/{key}/comment</li>
                         <li><strong>Transitions API:</strong> http://localhost:3001/rest/api/2/issue/{key}/transitions</li>
                         <li><strong>Projects API:</strong> http://localhost:3001/rest/api/2/project</li>
+                        <li><strong>Issue Links API:</strong> http://localhost:3001/rest/api/2/issueLink</li>
+                        <li><strong>Search API:</strong> http://localhost:3001/rest/api/2/search?jql=status%20IN%20(%22To%20Do%22)%20ORDER%20BY%20created%20DESC</li>
                         <li><strong>Webhook Trigger:</strong> http://localhost:3001/trigger_webhook/{event_type}</li>
+                        <li><strong>Webhook Log:</strong> http://localhost:3001/api/webhook_logs?source=&amp;event_type=&amp;issue_key=&amp;since=&amp;until=</li>
+                        <li><strong>Replay Webhook:</strong> POST http://localhost:3001/api/webhook_logs/{id}/replay</li>
                     </ul>
                 </div>
             </div>
+
+            <div class="section">
+                <h2>Webhook Log</h2>
+                <div class="endpoint">
+                    <form id="webhookLogForm">
+                        <label>Source:
+                            <select name="source">
+                                <option value="">Any</option>
+                                <option value="trigger">trigger</option>
+                                <option value="transition">transition</option>
+                                <option value="received">received</option>
+                                <option value="replay">replay</option>
+                            </select>
+                        </label>
+                        <label>Event: <input type="text" name="event_type" placeholder="jira:issue_updated"></label>
+                        <label>Issue Key: <input type="text" name="issue_key" placeholder="AUDIT-1"></label>
+                        <button type="submit" class="btn">Filter</button>
+                    </form>
+                    <table id="webhookLogTable" style="width: 100%; margin-top: 10px;">
+                        <thead><tr><th>ID</th><th>Time</th><th>Source</th><th>Event</th><th>Issue</th><th>Status</th><th></th></tr></thead>
+                        <tbody></tbody>
+                    </table>
+                </div>
+            </div>
             
             <script>
+                // Load the webhook log with the filter form's values
+                function loadWebhookLogs() {
+                    const form = document.getElementById('webhookLogForm');
+                    const params = new URLSearchParams();
+                    ['source', 'event_type', 'issue_key'].forEach(name => {
+                        if (form.elements[name].value) params.set(name, form.elements[name].value);
+                    });
+                    params.set('limit', '50');
+
+                    fetch('/api/webhook_logs?' + params.toString())
+                    .then(response => response.json())
+                    .then(data => {
+                        const body = document.querySelector('#webhookLogTable tbody');
+                        body.innerHTML = '';
+                        data.logs.forEach(log => {
+                            const row = body.insertRow();
+                            [log.id, log.timestamp, log.source, log.event_type, log.issue_key || '', log.error || log.status_code || '']
+                                .forEach(value => { row.insertCell().textContent = value; });
+                            const button = document.createElement('button');
+                            button.textContent = 'Replay';
+                            button.onclick = () => fetch('/api/webhook_logs/' + log.id + '/replay', {method: 'POST'}).then(loadWebhookLogs);
+                            row.insertCell().appendChild(button);
+                        });
+                    });
+                }
+                document.getElementById('webhookLogForm').addEventListener('submit', function(e) {
+                    e.preventDefault();
+                    loadWebhookLogs();
+                });
+                loadWebhookLogs();
+
                 // Handle form submissions
                 document.getElementById('issueCreateForm').addEventListener('submit', function(e) {
                     e.preventDefault();
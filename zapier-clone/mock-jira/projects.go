@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// JiraProject is a project as returned by /rest/api/2/project
+type JiraProject struct {
+	ID          string `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Lead        string `json:"leadAccountId,omitempty"`
+}
+
+// JiraComponent is a project component
+type JiraComponent struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Project     string `json:"project"`
+}
+
+// mockAccountID is the account the mock treats every caller as
+const mockAccountID = "mock-integration-user"
+
+var projectKey = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// Projects are the site's projects and their components
+var Projects = struct {
+	sync.Mutex
+	byKey      map[string]*JiraProject
+	components map[string][]JiraComponent
+	next       int
+}{
+	byKey: map[string]*JiraProject{
+		"AUDIT": {ID: "10000", Key: "AUDIT", Name: "Audit Management"},
+	},
+	components: map[string][]JiraComponent{},
+	next:       10001,
+}
+
+// issueProject is the key of the project a new issue is created in. Issues
+// for projects the mock does not know go to AUDIT.
+func issueProject(fields map[string]interface{}) string {
+	project, _ := fields["project"].(map[string]interface{})
+	key, _ := project["key"].(string)
+
+	Projects.Lock()
+	defer Projects.Unlock()
+	if _, ok := Projects.byKey[key]; !ok {
+		return "AUDIT"
+	}
+	return key
+}
+
+func handleProjects(w http.ResponseWriter, r *http.Request) {
+	Projects.Lock()
+	defer Projects.Unlock()
+
+	if r.Method == "GET" {
+		list := make([]JiraProject, 0, len(Projects.byKey))
+		for _, project := range Projects.byKey {
+			list = append(list, *project)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+		writeMockJSON(w, http.StatusOK, list)
+		return
+	}
+
+	var request struct {
+		Key           string `json:"key"`
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		LeadAccountID string `json:"leadAccountId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	errors := map[string]string{}
+	if !projectKey.MatchString(request.Key) {
+		errors["projectKey"] = "Project keys must start with an uppercase letter, followed by one or more uppercase alphanumeric characters."
+	} else if _, taken := Projects.byKey[request.Key]; taken {
+		errors["projectKey"] = fmt.Sprintf("Project '%s' uses this project key.", request.Key)
+	}
+	if request.Name == "" {
+		errors["projectName"] = "You must specify a valid project name."
+	}
+	if request.LeadAccountID == "" {
+		errors["projectLead"] = "You must specify a valid project lead."
+	}
+	if len(errors) > 0 {
+		writeMockJSON(w, http.StatusBadRequest, map[string]interface{}{"errorMessages": []string{}, "errors": errors})
+		return
+	}
+
+	project := &JiraProject{
+		ID:          fmt.Sprintf("%d", Projects.next),
+		Key:         request.Key,
+		Name:        request.Name,
+		Description: request.Description,
+		Lead:        request.LeadAccountID,
+	}
+	Projects.next++
+	Projects.byKey[project.Key] = project
+	writeMockJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":   project.ID,
+		"key":  project.Key,
+		"self": fmt.Sprintf("http://%s/rest/api/2/project/%s", r.Host, project.ID),
+	})
+}
+
+func handleProject(w http.ResponseWriter, r *http.Request) {
+	Projects.Lock()
+	defer Projects.Unlock()
+
+	project, ok := Projects.byKey[mux.Vars(r)["key"]]
+	if !ok {
+		writeMockJSON(w, http.StatusNotFound, map[string]interface{}{"errorMessages": []string{"No project could be found with key '" + mux.Vars(r)["key"] + "'."}})
+		return
+	}
+	writeMockJSON(w, http.StatusOK, project)
+}
+
+func handleProjectComponents(w http.ResponseWriter, r *http.Request) {
+	Projects.Lock()
+	defer Projects.Unlock()
+
+	key := mux.Vars(r)["key"]
+	if _, ok := Projects.byKey[key]; !ok {
+		writeMockJSON(w, http.StatusNotFound, map[string]interface{}{"errorMessages": []string{"No project could be found with key '" + key + "'."}})
+		return
+	}
+	components := Projects.components[key]
+	if components == nil {
+		components = []JiraComponent{}
+	}
+	writeMockJSON(w, http.StatusOK, components)
+}
+
+func handleCreateComponent(w http.ResponseWriter, r *http.Request) {
+	var request JiraComponent
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	Projects.Lock()
+	defer Projects.Unlock()
+
+	if _, ok := Projects.byKey[request.Project]; !ok {
+		writeMockJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": map[string]string{"project": "The project specified does not exist."}})
+		return
+	}
+	for _, component := range Projects.components[request.Project] {
+		if component.Name == request.Name {
+			writeMockJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": map[string]string{"name": "A component with the name " + request.Name + " already exists in this project."}})
+			return
+		}
+	}
+
+	request.ID = fmt.Sprintf("%d", Projects.next)
+	Projects.next++
+	Projects.components[request.Project] = append(Projects.components[request.Project], request)
+	writeMockJSON(w, http.StatusCreated, request)
+}
+
+func handleMyself(w http.ResponseWriter, r *http.Request) {
+	writeMockJSON(w, http.StatusOK, map[string]interface{}{
+		"accountId":   mockAccountID,
+		"displayName": "Mock Integration User",
+		"active":      true,
+	})
+}
+
+func writeMockJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Webhook log sources
+const (
+	SourceTrigger    = "trigger"    // sent through /trigger_webhook
+	SourceTransition = "transition" // sent when an issue was transitioned
+	SourceReceived   = "received"   // posted to /api/webhooks/jira
+	SourceReplay     = "replay"     // re-sent through /api/webhook_logs/{id}/replay
+)
+
+// maxWebhookLogs is how many webhooks are kept; the oldest are dropped first
+const maxWebhookLogs = 1000
+
+// WebhookLog is one webhook the mock sent or received
+type WebhookLog struct {
+	ID         int             `json:"id"`
+	Source     string          `json:"source"`
+	EventType  string          `json:"event_type"`
+	IssueKey   string          `json:"issue_key,omitempty"`
+	Target     string          `json:"target,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+	Timestamp  time.Time       `json:"timestamp"`
+	StatusCode int             `json:"status_code,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	// ReplayOf is the ID of the log a replay re-sent
+	ReplayOf int `json:"replay_of,omitempty"`
+}
+
+// WebhookLogs holds the logged webhooks, oldest first
+var WebhookLogs = struct {
+	sync.Mutex
+	entries []WebhookLog
+	nextID  int
+}{nextID: 1}
+
+// webhookTarget is where webhooks go when the request does not name a URL.
+// WEBHOOK_TARGET_URL overrides the default.
+func webhookTarget(override string) string {
+	if override != "" {
+		return override
+	}
+	if target := os.Getenv("WEBHOOK_TARGET_URL"); target != "" {
+		return target
+	}
+	return "http://localhost:8080/api/webhooks/jira"
+}
+
+// logWebhook stores a webhook and returns it with its ID
+func logWebhook(entry WebhookLog) WebhookLog {
+	WebhookLogs.Lock()
+	defer WebhookLogs.Unlock()
+
+	entry.ID = WebhookLogs.nextID
+	WebhookLogs.nextID++
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if entry.EventType == "" || entry.IssueKey == "" {
+		var payload struct {
+			WebhookEvent string `json:"webhookEvent"`
+			Issue        struct {
+				Key string `json:"key"`
+			} `json:"issue"`
+		}
+		json.Unmarshal(entry.Payload, &payload)
+		if entry.EventType == "" {
+			entry.EventType = payload.WebhookEvent
+		}
+		if entry.IssueKey == "" {
+			entry.IssueKey = payload.Issue.Key
+		}
+	}
+
+	WebhookLogs.entries = append(WebhookLogs.entries, entry)
+	if len(WebhookLogs.entries) > maxWebhookLogs {
+		WebhookLogs.entries = WebhookLogs.entries[len(WebhookLogs.entries)-maxWebhookLogs:]
+	}
+	return entry
+}
+
+// sendWebhook posts a payload to target and logs the attempt
+func sendWebhook(source, target string, payload interface{}) (WebhookLog, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return WebhookLog{}, err
+	}
+	return deliverWebhook(WebhookLog{Source: source, Target: target, Payload: body})
+}
+
+// deliverWebhook posts entry.Payload to entry.Target and logs the outcome
+func deliverWebhook(entry WebhookLog) (WebhookLog, error) {
+	resp, err := http.Post(entry.Target, "application/json", bytes.NewReader(entry.Payload))
+	if err != nil {
+		entry.Error = err.Error()
+		return logWebhook(entry), err
+	}
+	resp.Body.Close()
+	entry.StatusCode = resp.StatusCode
+	return logWebhook(entry), nil
+}
+
+// findWebhookLog returns a logged webhook by ID
+func findWebhookLog(id int) (WebhookLog, bool) {
+	WebhookLogs.Lock()
+	defer WebhookLogs.Unlock()
+	for _, entry := range WebhookLogs.entries {
+		if entry.ID == id {
+			return entry, true
+		}
+	}
+	return WebhookLog{}, false
+}
+
+// handleWebhookLogs lists logged webhooks, newest first. Query parameters
+// filter them: source, event_type, issue_key, since and until (RFC 3339), and
+// limit. DELETE clears the log.
+func handleWebhookLogs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "DELETE" {
+		WebhookLogs.Lock()
+		WebhookLogs.entries = nil
+		WebhookLogs.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	query := r.URL.Query()
+	var since, until time.Time
+	for name, bound := range map[string]*time.Time{"since": &since, "until": &until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid %s: use RFC 3339, e.g. 2024-01-02T15:04:05Z", name), http.StatusBadRequest)
+				return
+			}
+			*bound = parsed
+		}
+	}
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	WebhookLogs.Lock()
+	matched := []WebhookLog{}
+	for i := len(WebhookLogs.entries) - 1; i >= 0; i-- {
+		entry := WebhookLogs.entries[i]
+		if (query.Get("source") != "" && entry.Source != query.Get("source")) ||
+			(query.Get("event_type") != "" && entry.EventType != query.Get("event_type")) ||
+			(query.Get("issue_key") != "" && entry.IssueKey != query.Get("issue_key")) ||
+			(!since.IsZero() && entry.Timestamp.Before(since)) ||
+			(!until.IsZero() && entry.Timestamp.After(until)) {
+			continue
+		}
+		matched = append(matched, entry)
+		if limit > 0 && len(matched) == limit {
+			break
+		}
+	}
+	WebhookLogs.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"total": len(matched),
+		"logs":  matched,
+	})
+}
+
+// handleWebhookLog returns one logged webhook
+func handleWebhookLog(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	entry, ok := findWebhookLog(id)
+	if !ok {
+		http.Error(w, "Webhook log not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// handleReplayWebhook re-sends a logged webhook's payload to the configured
+// target, or to ?webhook_url=, and returns the new log entry
+func handleReplayWebhook(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	original, ok := findWebhookLog(id)
+	if !ok {
+		http.Error(w, "Webhook log not found", http.StatusNotFound)
+		return
+	}
+
+	replay, err := deliverWebhook(WebhookLog{
+		Source:    SourceReplay,
+		EventType: original.EventType,
+		IssueKey:  original.IssueKey,
+		Target:    webhookTarget(r.URL.Query().Get("webhook_url")),
+		Payload:   original.Payload,
+		ReplayOf:  original.ID,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	json.NewEncoder(w).Encode(replay)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ChannelState holds what the app did to channels besides posting: the
+// members it invited, the messages it pinned, topics, and archiving
+var ChannelState = struct {
+	sync.Mutex
+	members  map[string][]string
+	pins     map[string][]string
+	topics   map[string]string
+	archived map[string]bool
+	private  map[string]bool
+	nextID   int
+}{
+	members:  make(map[string][]string),
+	pins:     make(map[string][]string),
+	topics:   make(map[string]string),
+	archived: make(map[string]bool),
+	private:  make(map[string]bool),
+}
+
+// channelName is what Slack accepts as a channel name
+var channelName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,79}$`)
+
+// decodeCall reads a JSON or form-encoded API call into a map of strings
+func decodeCall(r *http.Request) map[string]string {
+	fields := map[string]string{}
+	if strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		for key, value := range body {
+			fields[key] = fmt.Sprint(value)
+		}
+		return fields
+	}
+	r.ParseForm()
+	for key := range r.Form {
+		fields[key] = r.FormValue(key)
+	}
+	return fields
+}
+
+// channelInfo is a channel in the shape conversations.* methods return
+func channelInfo(id string) map[string]interface{} {
+	ChannelState.Lock()
+	defer ChannelState.Unlock()
+	return map[string]interface{}{
+		"id":          id,
+		"name":        MockDatabase.Channels[id],
+		"is_channel":  true,
+		"is_private":  ChannelState.private[id],
+		"is_archived": ChannelState.archived[id],
+		"is_member":   true,
+		"topic":       map[string]interface{}{"value": ChannelState.topics[id]},
+		"members":     ChannelState.members[id],
+		"pins":        ChannelState.pins[id],
+	}
+}
+
+// handleCreateChannel accepts conversations.create
+func handleCreateChannel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	call := decodeCall(r)
+	name := call["name"]
+	if !channelName.MatchString(name) {
+		slackError(w, "invalid_name_specials")
+		return
+	}
+	for _, existing := range MockDatabase.Channels {
+		if existing == name {
+			slackError(w, "name_taken")
+			return
+		}
+	}
+
+	ChannelState.Lock()
+	ChannelState.nextID++
+	id := fmt.Sprintf("C9%05d", ChannelState.nextID)
+	ChannelState.private[id] = call["is_private"] == "true"
+	ChannelState.Unlock()
+	MockDatabase.Channels[id] = name
+
+	log.Printf("[MOCK SLACK] Created channel #%s (%s)\n", name, id)
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "channel": channelInfo(id)})
+}
+
+// handleChannelInfo accepts conversations.info
+func handleChannelInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := resolveChannel(decodeCall(r)["channel"])
+	if _, ok := MockDatabase.Channels[id]; !ok {
+		slackError(w, "channel_not_found")
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "channel": channelInfo(id)})
+}
+
+// handleThreadReplies accepts conversations.replies: the parent message, then
+// its replies oldest first
+func handleThreadReplies(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	call := decodeCall(r)
+	for key := range r.URL.Query() {
+		call[key] = r.URL.Query().Get(key)
+	}
+	parent, ok := MockDatabase.Messages[call["ts"]]
+	if !ok {
+		slackError(w, "thread_not_found")
+		return
+	}
+
+	messages := []map[string]interface{}{{
+		"type": "message",
+		"user": "U54321",
+		"text": parent.Text,
+		"ts":   parent.Timestamp,
+	}}
+	for _, reply := range MockDatabase.Threads[call["ts"]] {
+		messages = append(messages, map[string]interface{}{
+			"type":      "message",
+			"user":      "U54321",
+			"text":      reply.Text,
+			"ts":        reply.Timestamp,
+			"thread_ts": call["ts"],
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "messages": messages, "has_more": false})
+}
+
+// handleInviteToChannel accepts conversations.invite
+func handleInviteToChannel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	call := decodeCall(r)
+	id := resolveChannel(call["channel"])
+	if _, ok := MockDatabase.Channels[id]; !ok {
+		slackError(w, "channel_not_found")
+		return
+	}
+
+	ChannelState.Lock()
+	if ChannelState.archived[id] {
+		ChannelState.Unlock()
+		slackError(w, "is_archived")
+		return
+	}
+	added := 0
+	for _, user := range strings.Split(call["users"], ",") {
+		user = strings.TrimSpace(user)
+		if user == "" || containsString(ChannelState.members[id], user) {
+			continue
+		}
+		ChannelState.members[id] = append(ChannelState.members[id], user)
+		added++
+	}
+	ChannelState.Unlock()
+
+	if added == 0 {
+		slackError(w, "already_in_channel")
+		return
+	}
+	log.Printf("[MOCK SLACK] Invited %s to %s\n", call["users"], id)
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "channel": channelInfo(id)})
+}
+
+// handleSetTopic accepts conversations.setTopic
+func handleSetTopic(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	call := decodeCall(r)
+	id := resolveChannel(call["channel"])
+	if _, ok := MockDatabase.Channels[id]; !ok {
+		slackError(w, "channel_not_found")
+		return
+	}
+	ChannelState.Lock()
+	ChannelState.topics[id] = call["topic"]
+	ChannelState.Unlock()
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "channel": channelInfo(id)})
+}
+
+// handleArchiveChannel accepts conversations.archive
+func handleArchiveChannel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id := resolveChannel(decodeCall(r)["channel"])
+	if _, ok := MockDatabase.Channels[id]; !ok {
+		slackError(w, "channel_not_found")
+		return
+	}
+	ChannelState.Lock()
+	already := ChannelState.archived[id]
+	ChannelState.archived[id] = true
+	ChannelState.Unlock()
+	if already {
+		slackError(w, "already_archived")
+		return
+	}
+	log.Printf("[MOCK SLACK] Archived channel %s\n", id)
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true})
+}
+
+// handleAddPin accepts pins.add
+func handleAddPin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	call := decodeCall(r)
+	id := resolveChannel(call["channel"])
+	if _, ok := MockDatabase.Messages[call["timestamp"]]; !ok {
+		slackError(w, "message_not_found")
+		return
+	}
+	ChannelState.Lock()
+	defer ChannelState.Unlock()
+	if containsString(ChannelState.pins[id], call["timestamp"]) {
+		slackError(w, "already_pinned")
+		return
+	}
+	ChannelState.pins[id] = append(ChannelState.pins[id], call["timestamp"])
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true})
+}
+
+func containsString(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
@@ -20,6 +20,8 @@ type SlackMessage struct {
 	ThreadTS  string        `json:"thread_ts,omitempty"`
 	Blocks    []interface{} `json:"blocks,omitempty"`
 	Timestamp string        `json:"timestamp"`
+	// Edited is when chat.update last changed the message
+	Edited string `json:"edited,omitempty"`
 }
 
 // SlackCommand represents a slash command from Slack
@@ -62,6 +64,17 @@ var MockDatabase = struct {
 		"C12345": "general",
 		"C67890": "grc-alerts",
 		"C54321": "audit",
+		// The backend's default channels (slack.ChannelMapping)
+		"C10001": "risk-management",
+		"C10002": "compliance-team",
+		"C10003": "incident-response",
+		"C10004": "audit-team",
+		"C10005": "vendor-risk",
+		"C10006": "regulatory-updates",
+		"C10007": "grc-reports",
+		"C10008": "control-testing",
+		"C10009": "grc-ops",
+		"C10010": "incident-review",
 	},
 	Users: map[string]string{
 		"U12345": "john.doe",
@@ -81,10 +94,18 @@ func main() {
 	r.HandleFunc("/api/chat.update", handleUpdateMessage).Methods("POST")
 	r.HandleFunc("/api/chat.postEphemeral", handlePostEphemeral).Methods("POST")
 	r.HandleFunc("/api/reactions.add", handleAddReaction).Methods("POST")
+	r.HandleFunc("/api/views.open", handleOpenView).Methods("POST")
 
 	// Channel endpoints
 	r.HandleFunc("/api/conversations.list", handleListChannels).Methods("GET", "POST")
 	r.HandleFunc("/api/conversations.history", handleChannelHistory).Methods("GET", "POST")
+	r.HandleFunc("/api/conversations.info", handleChannelInfo).Methods("GET", "POST")
+	r.HandleFunc("/api/conversations.replies", handleThreadReplies).Methods("GET", "POST")
+	r.HandleFunc("/api/conversations.create", handleCreateChannel).Methods("POST")
+	r.HandleFunc("/api/conversations.invite", handleInviteToChannel).Methods("POST")
+	r.HandleFunc("/api/conversations.setTopic", handleSetTopic).Methods("POST")
+	r.HandleFunc("/api/conversations.archive", handleArchiveChannel).Methods("POST")
+	r.HandleFunc("/api/pins.add", handleAddPin).Methods("POST")
 
 	// User endpoints
 	r.HandleFunc("/api/users.list", handleListUsers).Methods("GET", "POST")
@@ -108,6 +129,9 @@ func main() {
 	}).Methods("GET")
 
 	r.HandleFunc("/", handleUI).Methods("GET")
+	r.HandleFunc("/messages", handleMessagesUI).Methods("GET")
+	r.HandleFunc("/api/mock/workspace", handleWorkspace).Methods("GET")
+	r.HandleFunc("/api/mock/views", handleListViews).Methods("GET")
 
 	// Start server
 	port := "3002" // Different port from ServiceNow and Jira mocks
@@ -167,9 +191,10 @@ func handlePostMessage(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Missing required field: channel", http.StatusBadRequest)
 		return
 	}
+	channelID = resolveChannel(channelID)
 
 	// Generate timestamp
-	messageTS := fmt.Sprintf("%d.%d", time.Now().Unix(), time.Now().Nanosecond()/1000000)
+	messageTS := newTimestamp()
 
 	// Create the message
 	message := SlackMessage{
@@ -271,11 +296,12 @@ func handleUpdateMessage(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Missing required fields: channel and ts", http.StatusBadRequest)
 		return
 	}
+	channelID = resolveChannel(channelID)
 
 	// Check if message exists
 	message, exists := MockDatabase.Messages[messageTS]
-	if !exists {
-		http.Error(w, "Message not found", http.StatusNotFound)
+	if !exists || message.ChannelID != channelID {
+		slackError(w, "message_not_found")
 		return
 	}
 
@@ -286,9 +312,10 @@ func handleUpdateMessage(w http.ResponseWriter, r *http.Request) {
 	if blocks != nil {
 		message.Blocks = blocks
 	}
+	message.Edited = newTimestamp()
 
 	// Save the updated message
-	MockDatabase.Messages[messageTS] = message
+	replaceMessage(message)
 
 	// Log the update
 	log.Printf("[MOCK SLACK] Message updated in %s (ts: %s)\n", channelID, messageTS)
@@ -352,9 +379,10 @@ func handlePostEphemeral(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Missing required fields: channel and user", http.StatusBadRequest)
 		return
 	}
+	channelID = resolveChannel(channelID)
 
 	// Generate timestamp (but don't store the message - it's ephemeral)
-	messageTS := fmt.Sprintf("%d.%d", time.Now().Unix(), time.Now().Nanosecond()/1000000)
+	messageTS := newTimestamp()
 
 	// Log the ephemeral message
 	log.Printf("[MOCK SLACK] Ephemeral message posted to %s for user %s: %s\n", channelID, userID, text)
@@ -437,6 +465,7 @@ func handleListChannels(w http.ResponseWriter, r *http.Request) {
 			"created":     time.Now().AddDate(0, 0, -30).Unix(),
 			"creator":     "U12345",
 			"is_archived": false,
+			"is_member":   true,
 			"is_general":  id == "C12345",
 			"members":     []string{"U12345", "U67890", "U54321"},
 			"topic": map[string]interface{}{
@@ -494,6 +523,7 @@ func handleChannelHistory(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Missing required field: channel", http.StatusBadRequest)
 		return
 	}
+	channelID = resolveChannel(channelID)
 
 	// Default limit
 	if limit <= 0 {
@@ -813,6 +843,9 @@ func handleMockResponseURL(w http.ResponseWriter, r *http.Request) {
 	// Log the response
 	log.Printf("[MOCK SLACK] Response URL received payload: %v\n", response)
 
+	// Apply it to the message the interaction came from, like Slack does
+	respondToMessage(r.URL.Query().Get("channel"), r.URL.Query().Get("ts"), response)
+
 	// Always return success
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"ok":true}`))
@@ -866,7 +899,7 @@ func triggerCommand(w http.ResponseWriter, r *http.Request) {
 		"channel_name": []string{channelName},
 		"team_id":      []string{"T12345"},
 		"team_domain":  []string{"mockteam"},
-		"response_url": []string{"http://localhost:3002/mock_response"},
+		"response_url": []string{"http://localhost:3002/mock_response?channel=" + url.QueryEscape(requestData.ChannelID)},
 		"trigger_id":   []string{fmt.Sprintf("trigger.%d", time.Now().Unix())},
 	}
 
@@ -935,10 +968,25 @@ func triggerInteraction(w http.ResponseWriter, r *http.Request) {
 	if requestData.ChannelID == "" {
 		requestData.ChannelID = "C12345"
 	}
+	requestData.ChannelID = resolveChannel(requestData.ChannelID)
 	if requestData.MessageTS == "" {
 		requestData.MessageTS = fmt.Sprintf("%d.%d", time.Now().Unix()-100, time.Now().Nanosecond()/1000000)
 	}
 
+	// An interaction with a posted message carries that message, as in Slack
+	messageText := "Mock message text"
+	if stored, exists := MockDatabase.Messages[requestData.MessageTS]; exists {
+		requestData.ChannelID = stored.ChannelID
+		messageText = stored.Text
+		if len(requestData.Blocks) == 0 {
+			requestData.Blocks = stored.Blocks
+		}
+	}
+	responseURL := fmt.Sprintf("http://localhost:3002/mock_response?%s", url.Values{
+		"channel": []string{requestData.ChannelID},
+		"ts":      []string{requestData.MessageTS},
+	}.Encode())
+
 	// Build the payload object based on interaction type
 	payload := map[string]interface{}{
 		"type":         requestData.Type,
@@ -948,7 +996,7 @@ func triggerInteraction(w http.ResponseWriter, r *http.Request) {
 		"api_app_id":   "A12345",
 		"token":        "mock_token",
 		"trigger_id":   fmt.Sprintf("trigger.%d", time.Now().Unix()),
-		"response_url": "http://localhost:3002/mock_response",
+		"response_url": responseURL,
 	}
 
 	// Add type-specific data
@@ -959,7 +1007,7 @@ func triggerInteraction(w http.ResponseWriter, r *http.Request) {
 			"block_id":  "mock_block",
 			"value":     requestData.Value,
 			"type":      "button",
-			"action_ts": fmt.Sprintf("%d.%d", time.Now().Unix(), time.Now().Nanosecond()/1000000),
+			"action_ts": newTimestamp(),
 		}
 
 		// Use provided blocks or create default
@@ -992,7 +1040,7 @@ func triggerInteraction(w http.ResponseWriter, r *http.Request) {
 
 		payload["message"] = map[string]interface{}{
 			"ts":     requestData.MessageTS,
-			"text":   "Mock message text",
+			"text":   messageText,
 			"blocks": blocks,
 		}
 
@@ -1099,6 +1147,7 @@ func handleUI(w http.ResponseWriter, r *http.Request) {
                 <h1>Mock Slack Server</h1>
                 <span class="status online">Online - Port 3002</span>
             </div>
+            <p><a href="/messages">View channels and messages</a></p>
             
             <div class="section">
                 <h2>Trigger Slack Commands</h2>
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// OpenedViews records the modals the app opened, newest last, so a test can
+// read a modal's blocks and post the matching view_submission
+var OpenedViews = struct {
+	sync.Mutex
+	views  []map[string]interface{}
+	nextID int
+}{}
+
+// handleOpenView accepts a views.open call and answers with the opened view
+func handleOpenView(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var request struct {
+		TriggerID string                 `json:"trigger_id"`
+		View      map[string]interface{} `json:"view"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if request.TriggerID == "" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": "invalid_trigger_id"})
+		return
+	}
+	if request.View == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": "invalid_arguments"})
+		return
+	}
+
+	OpenedViews.Lock()
+	OpenedViews.nextID++
+	view := request.View
+	view["id"] = fmt.Sprintf("V%08d", OpenedViews.nextID)
+	OpenedViews.views = append(OpenedViews.views, view)
+	OpenedViews.Unlock()
+
+	log.Printf("[MOCK SLACK] Opened view %s (callback_id %v)\n", view["id"], view["callback_id"])
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "view": view})
+}
+
+// handleListViews returns the modals opened so far
+func handleListViews(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	OpenedViews.Lock()
+	defer OpenedViews.Unlock()
+	views := OpenedViews.views
+	if views == nil {
+		views = []map[string]interface{}{}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"views": views})
+}
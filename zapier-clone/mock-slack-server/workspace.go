@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// resolveChannel turns a channel ID or name ("grc-ops" or "#grc-ops") into the
+// channel's ID. Like a workspace where the app was invited everywhere, a name
+// the mock has not seen yet becomes a new channel.
+func resolveChannel(channel string) string {
+	channel = strings.TrimPrefix(channel, "#")
+	if channel == "" {
+		return ""
+	}
+	if _, ok := MockDatabase.Channels[channel]; ok {
+		return channel
+	}
+	for id, name := range MockDatabase.Channels {
+		if name == channel {
+			return id
+		}
+	}
+
+	id := fmt.Sprintf("C%d", 70000+len(MockDatabase.Channels))
+	MockDatabase.Channels[id] = channel
+	log.Printf("[MOCK SLACK] Created channel #%s (%s)\n", channel, id)
+	return id
+}
+
+// lastTimestamp is the most recent message timestamp handed out
+var lastTimestamp = struct {
+	sync.Mutex
+	micros int64
+}{}
+
+// newTimestamp returns a message timestamp in Slack's seconds.microseconds
+// format. Messages are keyed by it, so two are never the same.
+func newTimestamp() string {
+	lastTimestamp.Lock()
+	defer lastTimestamp.Unlock()
+	micros := time.Now().UnixMicro()
+	if micros <= lastTimestamp.micros {
+		micros = lastTimestamp.micros + 1
+	}
+	lastTimestamp.micros = micros
+	return fmt.Sprintf("%d.%06d", micros/1000000, micros%1000000)
+}
+
+// slackError answers the way the Slack Web API reports failures: 200 with ok false
+func slackError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": code})
+}
+
+// tsValue orders message timestamps
+func tsValue(ts string) float64 {
+	value, _ := strconv.ParseFloat(ts, 64)
+	return value
+}
+
+// replaceMessage stores an edited message, including its copy in its thread
+func replaceMessage(message SlackMessage) {
+	MockDatabase.Messages[message.Timestamp] = message
+	if message.ThreadTS == "" {
+		return
+	}
+	replies := MockDatabase.Threads[message.ThreadTS]
+	for i := range replies {
+		if replies[i].Timestamp == message.Timestamp {
+			replies[i] = message
+		}
+	}
+}
+
+// deleteMessage removes a message and its thread
+func deleteMessage(ts string) {
+	message, ok := MockDatabase.Messages[ts]
+	if !ok {
+		return
+	}
+	delete(MockDatabase.Messages, ts)
+	delete(MockDatabase.Threads, ts)
+	if message.ThreadTS != "" {
+		replies := MockDatabase.Threads[message.ThreadTS]
+		kept := replies[:0]
+		for _, reply := range replies {
+			if reply.Timestamp != ts {
+				kept = append(kept, reply)
+			}
+		}
+		MockDatabase.Threads[message.ThreadTS] = kept
+	}
+}
+
+// respondToMessage applies a response_url payload. replace_original and
+// delete_original act on the message the interaction came from; an in_channel
+// response is posted to the channel, and an ephemeral one is only logged.
+func respondToMessage(channelID, ts string, response map[string]interface{}) {
+	text, _ := response["text"].(string)
+	blocks, _ := response["blocks"].([]interface{})
+	now := newTimestamp()
+
+	if original, exists := MockDatabase.Messages[ts]; exists {
+		if remove, _ := response["delete_original"].(bool); remove {
+			deleteMessage(ts)
+			log.Printf("[MOCK SLACK] Message deleted by response URL (ts: %s)\n", ts)
+			return
+		}
+		if replace, _ := response["replace_original"].(bool); replace {
+			original.Text = text
+			original.Blocks = blocks
+			original.Edited = now
+			replaceMessage(original)
+			log.Printf("[MOCK SLACK] Message replaced by response URL (ts: %s)\n", ts)
+			return
+		}
+	}
+
+	if responseType, _ := response["response_type"].(string); responseType != "in_channel" || channelID == "" {
+		return
+	}
+	message := SlackMessage{ChannelID: resolveChannel(channelID), Text: text, Blocks: blocks, Timestamp: now}
+	if threadTS, ok := response["thread_ts"].(string); ok && threadTS != "" {
+		message.ThreadTS = threadTS
+		MockDatabase.Threads[threadTS] = append(MockDatabase.Threads[threadTS], message)
+	}
+	MockDatabase.Messages[now] = message
+	log.Printf("[MOCK SLACK] Response posted to %s: %s (ts: %s)\n", message.ChannelID, text, now)
+}
+
+// handleWorkspace returns every channel with its messages and their replies,
+// oldest first, for the messages page
+func handleWorkspace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	type viewMessage struct {
+		SlackMessage
+		Replies []SlackMessage `json:"replies,omitempty"`
+	}
+	type viewChannel struct {
+		ID       string        `json:"id"`
+		Name     string        `json:"name"`
+		Messages []viewMessage `json:"messages"`
+	}
+
+	channels := map[string]*viewChannel{}
+	for id, name := range MockDatabase.Channels {
+		channels[id] = &viewChannel{ID: id, Name: name, Messages: []viewMessage{}}
+	}
+	for _, message := range MockDatabase.Messages {
+		if message.ThreadTS != "" && message.ThreadTS != message.Timestamp {
+			continue
+		}
+		channel, ok := channels[message.ChannelID]
+		if !ok {
+			channel = &viewChannel{ID: message.ChannelID, Name: message.ChannelID}
+			channels[message.ChannelID] = channel
+		}
+		channel.Messages = append(channel.Messages, viewMessage{SlackMessage: message, Replies: MockDatabase.Threads[message.Timestamp]})
+	}
+
+	list := make([]*viewChannel, 0, len(channels))
+	for _, channel := range channels {
+		sort.Slice(channel.Messages, func(i, j int) bool {
+			return tsValue(channel.Messages[i].Timestamp) < tsValue(channel.Messages[j].Timestamp)
+		})
+		list = append(list, channel)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"channels": list,
+		"updated":  time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// handleMessagesUI shows channels and messages the way a Slack client would,
+// refreshing every two seconds. Buttons in a message's blocks send a
+// block_actions interaction to the app, closing the loop locally.
+func handleMessagesUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head>
+    <title>Mock Slack - Messages</title>
+    <style>
+        body { font-family: Arial, sans-serif; margin: 0; display: flex; height: 100vh; }
+        #sidebar { width: 220px; background: #3f0e40; color: #cfc3cf; padding: 15px; overflow-y: auto; }
+        #sidebar h2 { color: white; font-size: 16px; }
+        .channel { padding: 4px 8px; cursor: pointer; border-radius: 4px; }
+        .channel.active { background: #1164a3; color: white; }
+        .count { float: right; font-size: 12px; }
+        #main { flex: 1; padding: 15px 25px; overflow-y: auto; }
+        .message { border-bottom: 1px solid #eee; padding: 10px 0; }
+        .ts { color: #888; font-size: 12px; }
+        .edited { color: #888; font-size: 12px; font-style: italic; }
+        .block { margin: 6px 0; white-space: pre-wrap; }
+        .replies { margin-left: 25px; border-left: 3px solid #ddd; padding-left: 10px; }
+        button { background: #007a5a; color: white; border: none; border-radius: 4px; padding: 5px 10px; margin-right: 6px; cursor: pointer; }
+        button.danger { background: #e01e5a; }
+        #settings { color: #888; font-size: 12px; margin-bottom: 10px; }
+        #settings input { width: 380px; }
+    </style>
+</head>
+<body>
+    <div id="sidebar"><h2>Channels</h2><div id="channels"></div></div>
+    <div id="main">
+        <div id="settings">Interactions go to <input id="webhookURL" value="http://localhost:8080/api/slack/interactions"></div>
+        <h2 id="title"></h2>
+        <div id="messages"></div>
+    </div>
+    <script>
+        let selected = null;
+        let workspace = {channels: []};
+
+        function text(value) {
+            if (!value) return '';
+            return typeof value === 'string' ? value : (value.text || '');
+        }
+
+        function renderBlocks(message, container) {
+            if (!message.blocks || message.blocks.length === 0) {
+                const body = document.createElement('div');
+                body.className = 'block';
+                body.textContent = message.text;
+                container.appendChild(body);
+                return;
+            }
+            message.blocks.forEach(block => {
+                const div = document.createElement('div');
+                div.className = 'block';
+                if (block.text) div.textContent = text(block.text);
+                (block.fields || []).forEach(field => { div.textContent += '\n' + text(field); });
+                (block.elements || []).forEach(element => {
+                    if (element.type === 'button') {
+                        const button = document.createElement('button');
+                        button.textContent = text(element.text);
+                        if (element.style === 'danger') button.className = 'danger';
+                        button.onclick = () => sendAction(message, block, element);
+                        div.appendChild(button);
+                    } else if (element.text || element.type === 'mrkdwn' || element.type === 'plain_text') {
+                        div.textContent += text(element);
+                    }
+                });
+                container.appendChild(div);
+            });
+        }
+
+        function renderMessage(message, container) {
+            const div = document.createElement('div');
+            div.className = 'message';
+            const ts = document.createElement('span');
+            ts.className = 'ts';
+            ts.textContent = new Date(parseFloat(message.timestamp) * 1000).toLocaleString() + ' (' + message.timestamp + ')';
+            div.appendChild(ts);
+            if (message.edited) {
+                const edited = document.createElement('span');
+                edited.className = 'edited';
+                edited.textContent = ' edited';
+                div.appendChild(edited);
+            }
+            renderBlocks(message, div);
+            if (message.replies && message.replies.length > 0) {
+                const replies = document.createElement('div');
+                replies.className = 'replies';
+                message.replies.forEach(reply => renderMessage(reply, replies));
+                div.appendChild(replies);
+            }
+            container.appendChild(div);
+        }
+
+        function render() {
+            const channels = document.getElementById('channels');
+            channels.innerHTML = '';
+            workspace.channels.forEach(channel => {
+                const div = document.createElement('div');
+                div.className = 'channel' + (channel.id === selected ? ' active' : '');
+                div.textContent = '# ' + channel.name;
+                const count = document.createElement('span');
+                count.className = 'count';
+                count.textContent = channel.messages.length || '';
+                div.appendChild(count);
+                div.onclick = () => { selected = channel.id; render(); };
+                channels.appendChild(div);
+            });
+
+            const channel = workspace.channels.find(c => c.id === selected);
+            document.getElementById('title').textContent = channel ? '# ' + channel.name : 'Select a channel';
+            const messages = document.getElementById('messages');
+            messages.innerHTML = '';
+            if (channel) channel.messages.forEach(message => renderMessage(message, messages));
+        }
+
+        function refresh() {
+            fetch('/api/mock/workspace')
+            .then(response => response.json())
+            .then(data => {
+                workspace = data;
+                if (!selected) {
+                    const busy = data.channels.find(c => c.messages.length > 0);
+                    selected = (busy || data.channels[0] || {}).id;
+                }
+                render();
+            });
+        }
+
+        function sendAction(message, block, element) {
+            fetch('/trigger_interaction', {
+                method: 'POST',
+                headers: {'Content-Type': 'application/json'},
+                body: JSON.stringify({
+                    type: 'block_actions',
+                    action_id: element.action_id,
+                    value: element.value || '',
+                    channel_id: message.channel_id,
+                    message_ts: message.timestamp,
+                    blocks: message.blocks,
+                    webhook_url: document.getElementById('webhookURL').value,
+                })
+            })
+            .then(response => response.ok ? refresh() : response.text().then(alert));
+        }
+
+        refresh();
+        setInterval(refresh, 2000);
+    </script>
+</body>
+</html>`))
+}
@@ -0,0 +1,28 @@
+#!/bin/bash
+
+# This script builds a single release binary that serves both the API and the frontend
+
+# Exit on error
+set -e
+
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+DIST="$ROOT/backend/internal/webui/dist"
+OUTPUT="${OUTPUT:-$ROOT/backend/bin/server}"
+
+# Build the frontend; API calls go to the same origin that served the UI
+echo "Building frontend..."
+cd "$ROOT/frontend"
+npm ci
+REACT_APP_API_URL=/ npm run build
+
+# Copy the production build where the embed directive picks it up
+echo "Embedding frontend build..."
+find "$DIST" -mindepth 1 ! -name .gitkeep ! -name .gitignore -exec rm -rf {} +
+cp -R "$ROOT/frontend/build/." "$DIST/"
+
+# Build a static binary with the frontend embedded
+echo "Building backend..."
+cd "$ROOT/backend"
+CGO_ENABLED=0 go build -tags embedui -trimpath -ldflags "-s -w" -o "$OUTPUT" ./cmd/server
+
+echo "Built $OUTPUT"
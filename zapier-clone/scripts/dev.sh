@@ -71,17 +71,16 @@ cd frontend
 npm install
 cd ..
 
-# Create a default config file if it doesn't exist
-if [ ! -f config/config.yaml ]; then
-    echo "Creating default config file..."
-    mkdir -p config
-    cp config.example.yaml config/config.yaml
+# Create a server config file if it doesn't exist
+if [ ! -f config/server.yaml ]; then
+    echo "Creating config/server.yaml; fill in your ServiceNow and Jira settings..."
+    cp config/server.example.yaml config/server.yaml
 fi
 
 # Run the backend in the background
 echo "Starting backend server..."
 cd backend
-go run cmd/server/main.go &
+go run ./cmd/server -config ../config/server.yaml &
 BACKEND_PID=$!
 cd ..
 